@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	alertDomain "event-metrics-service/internal/alert/core/domain"
+	alertPorts "event-metrics-service/internal/alert/core/ports"
+	authDomain "event-metrics-service/internal/auth/core/domain"
+	metricsWarmup "event-metrics-service/internal/metrics/adapters/warmup"
+)
+
+// alertMeasurer runs the queries of alert rules, written like the saved
+// queries of METRICS_WARMUP_QUERIES, through the metrics use case as the
+// tenant of the rule.
+type alertMeasurer struct {
+	uc metricsWarmup.GetMetricsUseCase
+}
+
+var _ alertPorts.MeasurePort = alertMeasurer{}
+
+func (m alertMeasurer) Window(query string) (time.Duration, error) {
+	q, err := metricsWarmup.ParseSavedQuery(query)
+	if err != nil {
+		return 0, err
+	}
+	return q.Range, nil
+}
+
+func (m alertMeasurer) Measure(ctx context.Context, tenantID, query string, to time.Time) (alertDomain.Measurement, error) {
+	q, err := metricsWarmup.ParseSavedQuery(query)
+	if err != nil {
+		return alertDomain.Measurement{}, err
+	}
+	in := q.Input
+	in.From = to.Add(-q.Range).Unix()
+	in.To = to.Unix()
+
+	res, err := m.uc.Execute(authDomain.WithPrincipal(ctx, authDomain.Principal{TenantID: tenantID}), in)
+	if err != nil {
+		return alertDomain.Measurement{}, err
+	}
+	return alertDomain.Measurement{TotalCount: res.TotalCount, UniqueUsers: res.UniqueUsers, Value: res.Value}, nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	auditUsecase "event-metrics-service/internal/audit/core/usecase"
+	authDomain "event-metrics-service/internal/auth/core/domain"
+	metricsAudit "event-metrics-service/internal/metrics/adapters/audit"
+)
+
+// queryAuditor records metrics queries in the query audit log as the
+// principal of the request.
+type queryAuditor struct {
+	uc *auditUsecase.AuditUseCase
+}
+
+var _ metricsAudit.Recorder = queryAuditor{}
+
+func (a queryAuditor) RecordQuery(ctx context.Context, q metricsAudit.Query) {
+	p := authDomain.PrincipalFromContext(ctx)
+	err := a.uc.Record(ctx, auditUsecase.RecordInput{
+		APIKey:    p.APIKey,
+		Subject:   p.Subject,
+		TenantID:  p.TenantID,
+		Operation: q.Operation,
+		EventName: q.Input.EventName,
+		GroupBy:   q.Input.GroupBy,
+		From:      q.Input.From,
+		To:        q.Input.To,
+		Filter:    q.Filter,
+		Duration:  q.Duration,
+		Rows:      q.Rows,
+		Err:       q.Err,
+	})
+	if err != nil {
+		log.Printf("query audit: failed to record a metrics query: %v", err)
+	}
+}
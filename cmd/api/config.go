@@ -0,0 +1,844 @@
+package main
+
+import (
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	auditUsecase "event-metrics-service/internal/audit/core/usecase"
+	authDomain "event-metrics-service/internal/auth/core/domain"
+	eventsPrometheus "event-metrics-service/internal/events/adapters/prometheus"
+	eventsUsecase "event-metrics-service/internal/events/core/usecase"
+	identityUsecase "event-metrics-service/internal/identity/core/usecase"
+	journalUsecase "event-metrics-service/internal/journal/core/usecase"
+	metricsCache "event-metrics-service/internal/metrics/adapters/cache"
+	metricsHttp "event-metrics-service/internal/metrics/adapters/http/fiber"
+	metricsWarmup "event-metrics-service/internal/metrics/adapters/warmup"
+	metricsUsecase "event-metrics-service/internal/metrics/core/usecase"
+	migrationUsecase "event-metrics-service/internal/migration/core/usecase"
+	quotaUsecase "event-metrics-service/internal/quota/core/usecase"
+	sessionUsecase "event-metrics-service/internal/session/core/usecase"
+	tenantUsecase "event-metrics-service/internal/tenant/core/usecase"
+	warmupUsecase "event-metrics-service/internal/warmup/core/usecase"
+	webhookSender "event-metrics-service/internal/webhook/adapters/sender"
+	webhookUsecase "event-metrics-service/internal/webhook/core/usecase"
+)
+
+type config struct {
+	PostgresDSN string
+	HTTPAddr    string
+
+	// MetricsService is also served over gRPC (h2c) on this address when set
+	GRPCAddr string
+
+	// Header carrying the client IP when running behind a proxy (e.g. X-Forwarded-For)
+	ProxyHeader string
+
+	// Decode JSON numbers in event payloads as json.Number (lossless) instead of float64.
+	PreserveMetadataNumbers bool
+
+	// Tag normalization limits (0 disables a limit)
+	MaxTagsPerEvent int
+	MaxTagLength    int
+
+	// Metadata limits: total keys, nesting depth, serialized bytes (0 disables a limit)
+	MaxMetadataKeys  int
+	MaxMetadataDepth int
+	MaxMetadataBytes int
+
+	// Events older than this are rejected (0 = no limit)
+	MaxEventAge time.Duration
+
+	// Share of events kept per event name, e.g. heartbeat=0.1; other event
+	// names are all kept
+	SamplingRates map[string]float64
+
+	// Failed event inserts are retried, then kept in DeadLetterFile (when
+	// set) for a re-drive via /admin/dead-letters
+	InsertRetries      int
+	InsertRetryBackoff time.Duration
+	DeadLetterFile     string
+
+	// Journal of rejected ingest requests, retrievable by X-Request-ID
+	JournalEnabled       bool
+	JournalTTL           time.Duration
+	JournalMaxBodyBytes  int
+	JournalPurgeInterval time.Duration
+
+	// Audit log of metrics queries, listed at /admin/metrics-queries
+	QueryAuditEnabled       bool
+	QueryAuditRetention     time.Duration
+	QueryAuditPurgeInterval time.Duration
+
+	// Identical events are rejected as duplicates only within this window (0 = forever)
+	DedupeWindow        time.Duration
+	DedupePurgeInterval time.Duration
+
+	// Retention: events older than EventsRetentionDays (by event_time) are
+	// purged in batches; 0 keeps events forever
+	EventsRetentionDays     int
+	RetentionPurgeInterval  time.Duration
+	RetentionPurgeBatchSize int
+
+	// GET /events/export reads matching events in pages of this size
+	ExportBatchSize int
+
+	// GET /events/tail: at most this many live tails at once
+	MaxTails int
+
+	// GET /metrics/stream: at most this many metrics streams at once
+	MaxMetricsStreams int
+
+	// Outbox: with brokers set, every stored event is also recorded in
+	// event_outbox and relayed to KafkaTopic
+	KafkaBrokers        []string
+	KafkaTopic          string
+	OutboxRelayInterval time.Duration
+	OutboxBatchSize     int
+
+	// Webhooks: when enabled, stored events are also relayed through the
+	// outbox to the subscriptions managed at /admin/webhooks
+	WebhooksEnabled         bool
+	WebhookDeliveryInterval time.Duration
+	WebhookMaxAttempts      int
+	WebhookRetryBackoff     time.Duration
+	WebhookTimeout          time.Duration
+	WebhookConcurrency      int
+
+	// Alerts: when enabled, the rules managed at /admin/alerts are
+	// evaluated on every tick and their changes of status notified
+	AlertsEnabled           bool
+	AlertEvaluationInterval time.Duration
+	AlertNotifyTimeout      time.Duration
+
+	// Archive: with a bucket set, purged events are first written to S3 as
+	// Parquet and can be rehydrated via /admin/archive/rehydrate
+	ArchiveS3Bucket    string
+	ArchiveS3Region    string
+	ArchiveS3Endpoint  string // S3 compatible endpoint (path-style), e.g. MinIO
+	ArchiveS3Prefix    string
+	AWSAccessKeyID     string
+	AWSSecretAccessKey string
+	AWSSessionToken    string
+
+	// Exports: with a bucket set, GET /events/export and GET /metrics/export
+	// take destination=s3, with the AWS credentials of the archive
+	ExportS3Bucket   string
+	ExportS3Region   string
+	ExportS3Endpoint string
+	ExportS3Prefix   string
+
+	// Ingest enrichers, in order: "useragent", "geoip" (requires GeoIPCSV)
+	Enrichers []string
+	GeoIPCSV  string
+
+	// Largest request body accepted on any route, and the most events per
+	// POST /events/bulk (0 = unlimited)
+	MaxBodyBytes  int
+	BulkMaxEvents int
+
+	// HTTP server: time to read a request, to write a response and to keep an
+	// idle connection open (0 = no timeout), and the buffer bounding headers
+	HTTPReadTimeout    time.Duration
+	HTTPWriteTimeout   time.Duration
+	HTTPIdleTimeout    time.Duration
+	HTTPReadBufferSize int
+
+	// Deepest nesting and most values one JSON body or /events/ws message
+	// may hold (0 = unlimited); larger documents get 413 json_too_complex
+	JSONMaxDepth  int
+	JSONMaxValues int
+
+	// Per API key cap on concurrent metrics queries (0 = unlimited)
+	MetricsMaxConcurrentPerKey  int
+	MetricsConcurrencyOverrides map[string]int
+
+	// Longest range one metrics query may span (0 = unlimited), and the most
+	// time buckets it may group into
+	MetricsMaxQueryRange  time.Duration
+	MetricsMaxTimeBuckets int
+
+	// Deadline of one metrics query, after which it is cancelled in the
+	// database (0 = none)
+	MetricsQueryTimeout time.Duration
+
+	// Planner estimates above which grouped metrics queries are refused
+	// rather than run, checked with EXPLAIN (0 = unchecked)
+	MetricsMaxQueryCost float64
+	MetricsMaxQueryRows float64
+
+	// Metrics response cache: with an address set, metrics results are cached
+	// in Redis by filter, fresh for MetricsCacheTTL and served for
+	// MetricsCacheStale more while refreshed in the background
+	MetricsCacheRedisAddr     string
+	MetricsCacheRedisPassword string
+	MetricsCacheTTL           time.Duration
+	MetricsCacheStale         time.Duration
+
+	// Hourly and daily rollups of events, recomputed every RollupInterval
+	// for the last RollupLookback and backfilled up to RollupHistory back;
+	// eligible metrics queries read them instead of the events
+	RollupEnabled  bool
+	RollupInterval time.Duration
+	RollupLookback time.Duration
+	RollupHistory  time.Duration
+
+	// Keep rollups as HyperLogLog sketches of unique users (migrations/hll)
+	// instead of one row per user; unique users become estimates
+	RollupSketches bool
+
+	// Hourly event counts per name and channel, incremented at ingest;
+	// counts-only metrics queries of whole hours read them instead of the
+	// events
+	EventCountersEnabled bool
+
+	// Counts-only metrics queries answer the last LiveCountersWindow from
+	// in-memory counts of the events this instance stored (0 = off)
+	LiveCountersWindow time.Duration
+
+	// Where eligible metrics queries read whole buckets from: "postgres"
+	// (events, or the rollups above) or "timescale" (TimescaleDB continuous
+	// aggregates, when available); or "clickhouse" to answer metrics queries
+	// from a ClickHouse copy of events
+	MetricsReader string
+
+	// ClickHouse HTTP interface read with METRICS_READER=clickhouse
+	ClickHouseURL      string
+	ClickHouseDatabase string
+	ClickHouseUser     string
+	ClickHousePassword string
+
+	// Per API key token bucket on POST /events and /events/bulk, in requests
+	// per second (0 = unlimited)
+	IngestRateLimit     float64
+	IngestRateBurst     int
+	IngestRateOverrides map[string]float64
+
+	// Soft daily ingest quotas per API key (0 = unlimited)
+	QuotaDailyLimit  int
+	QuotaOverrides   map[string]int
+	QuotaWarnPercent int
+
+	// Anonymous events this long before an identify call count as the user
+	IdentityStitchWindow time.Duration
+
+	// Inactivity that ends a session in GET /metrics/sessions, unless the
+	// query sets gap
+	SessionGap time.Duration
+
+	// How often schemas changed on other instances are picked up
+	SchemaRefreshInterval time.Duration
+
+	// Retention applied to tenants onboarded without an explicit one
+	TenantDefaultRetentionDays int
+
+	// Scope events and metrics to the tenant owning the caller's API key;
+	// keys belonging to no tenant are rejected on ingest and metrics reads
+	TenantIsolation   bool
+	TenantKeyCacheTTL time.Duration
+
+	// Top values cache behind GET /metrics/dimensions/{name}/values
+	DimensionMetadataKeys    []string
+	DimensionTopN            int
+	DimensionLookback        time.Duration
+	DimensionRefreshInterval time.Duration
+
+	// Startup warm-up gating /readyz: saved GET /metrics queries with a
+	// relative range, e.g. "event_name=purchase&group_by=channel&range=24h"
+	WarmUpQueries []string
+	WarmUpTimeout time.Duration
+
+	// Storage growth / bloat collector
+	StorageStatsInterval time.Duration
+	StorageStatsTables   []string
+
+	// Columns added by recent migrations: "table.column=off|write|on" (default on
+	// once the column exists), re-probed so a migration applied mid-deploy is picked up
+	SchemaColumnModes           map[string]string
+	SchemaCompatRefreshInterval time.Duration
+
+	// Metadata keys mirrored into real events columns (meta_<key>)
+	PromotedMetadataKeys []string
+
+	// Bearer token guarding the /prometheus scrape endpoint; unset disables it
+	PrometheusToken string
+
+	// Business KPI counters exported at /metrics/openmetrics; "*" among the
+	// event names counts up to OpenMetricsMaxEventNames others. Without
+	// OpenMetricsChannels the first OpenMetricsMaxChannels channels are
+	// labeled
+	OpenMetricsEventNames    []string
+	OpenMetricsMaxEventNames int
+	OpenMetricsChannels      []string
+	OpenMetricsMaxChannels   int
+	OpenMetricsToken         string
+
+	// Business SLOs (see /admin/slo)
+	SLOTenants               map[string]string        // API key -> tenant label
+	SLOLatencyTargets        map[string]time.Duration // operation -> latency target
+	SLOLatencyObjective      float64
+	SLOAvailabilityObjective float64
+
+	// Heartbeat monitor (dead-man's switch)
+	HeartbeatCheckInterval time.Duration
+	HeartbeatGrace         time.Duration
+
+	// Deployment environment; fault injection is refused when "production"
+	AppEnv       string
+	ChaosEnabled bool
+	AdminToken   string
+
+	// JWTs from this OpenID Connect issuer are required on the metrics read
+	// endpoints when set; tokens must grant every scope in OIDCScopes
+	OIDCIssuer   string
+	OIDCAudience string
+	OIDCScopes   []string
+
+	// Channel of GA4 Measurement Protocol hits per measurement_id or
+	// firebase_app_id (default "web" and "app")
+	GA4Channels map[string]string
+
+	// Channel of Snowplow events per app id (default: by platform)
+	SnowplowChannels map[string]string
+
+	// Scopes per API key ("anonymous" for callers without one), space
+	// separated: "key_a=events:write,key_b=metrics:read admin". Other keys get
+	// DefaultAPIKeyScopes (none unless set); callers without a key only get
+	// what "anonymous" lists. Bearer tokens add the scopes they were granted.
+	APIKeyScopes        map[string]authDomain.Scopes
+	DefaultAPIKeyScopes authDomain.Scopes
+}
+
+func loadConfig() config {
+	cfg := config{
+		PostgresDSN: os.Getenv("POSTGRES_DSN"),
+		HTTPAddr:    envString("HTTP_ADDR", ":8080"),
+		GRPCAddr:    os.Getenv("GRPC_ADDR"),
+		ProxyHeader: os.Getenv("PROXY_HEADER"),
+
+		PreserveMetadataNumbers: envBool("METADATA_PRESERVE_NUMBERS", true),
+
+		MaxTagsPerEvent: envInt("EVENT_MAX_TAGS", eventsUsecase.DefaultMaxTags),
+		MaxTagLength:    envInt("EVENT_MAX_TAG_LENGTH", eventsUsecase.DefaultMaxTagLength),
+
+		MaxMetadataKeys:  envInt("METADATA_MAX_KEYS", eventsUsecase.DefaultMaxMetadataKeys),
+		MaxMetadataDepth: envInt("METADATA_MAX_DEPTH", eventsUsecase.DefaultMaxMetadataDepth),
+		MaxMetadataBytes: envInt("METADATA_MAX_BYTES", eventsUsecase.DefaultMaxMetadataBytes),
+
+		MaxEventAge: envDuration("EVENT_MAX_AGE", 0),
+
+		SamplingRates: envFloatMap("SAMPLING_RATES"),
+
+		InsertRetries:      envInt("INSERT_RETRIES", 2),
+		InsertRetryBackoff: envDuration("INSERT_RETRY_BACKOFF", 100*time.Millisecond),
+		DeadLetterFile:     os.Getenv("DEAD_LETTER_FILE"),
+
+		JournalEnabled:       envBool("JOURNAL_ENABLED", false),
+		JournalTTL:           envDuration("JOURNAL_TTL", journalUsecase.DefaultTTL),
+		JournalMaxBodyBytes:  envInt("JOURNAL_MAX_BODY_BYTES", journalUsecase.DefaultMaxBodyBytes),
+		JournalPurgeInterval: envDuration("JOURNAL_PURGE_INTERVAL", 10*time.Minute),
+
+		QueryAuditEnabled:       envBool("QUERY_AUDIT_ENABLED", false),
+		QueryAuditRetention:     envDuration("QUERY_AUDIT_RETENTION", auditUsecase.DefaultRetention),
+		QueryAuditPurgeInterval: envDuration("QUERY_AUDIT_PURGE_INTERVAL", time.Hour),
+
+		DedupeWindow:        envDuration("DEDUPE_WINDOW", eventsUsecase.DefaultDedupeWindow),
+		DedupePurgeInterval: envDuration("DEDUPE_PURGE_INTERVAL", 10*time.Minute),
+
+		EventsRetentionDays:     envInt("EVENTS_RETENTION_DAYS", 0),
+		RetentionPurgeInterval:  envDuration("RETENTION_PURGE_INTERVAL", time.Hour),
+		RetentionPurgeBatchSize: envInt("RETENTION_PURGE_BATCH_SIZE", eventsUsecase.DefaultRetentionPurgeBatchSize),
+
+		ExportBatchSize: envInt("EXPORT_BATCH_SIZE", eventsUsecase.DefaultExportBatchSize),
+		MaxTails:        envInt("MAX_TAILS", eventsUsecase.DefaultMaxTails),
+
+		MaxMetricsStreams: envInt("MAX_METRICS_STREAMS", metricsHttp.DefaultMaxStreams),
+
+		KafkaBrokers:        envList("KAFKA_BROKERS", nil),
+		KafkaTopic:          envString("KAFKA_TOPIC", "events"),
+		OutboxRelayInterval: envDuration("OUTBOX_RELAY_INTERVAL", time.Second),
+		OutboxBatchSize:     envInt("OUTBOX_BATCH_SIZE", eventsUsecase.DefaultOutboxBatchSize),
+
+		WebhooksEnabled:         envBool("WEBHOOKS_ENABLED", false),
+		WebhookDeliveryInterval: envDuration("WEBHOOK_DELIVERY_INTERVAL", time.Second),
+		WebhookMaxAttempts:      envInt("WEBHOOK_MAX_ATTEMPTS", webhookUsecase.DefaultMaxAttempts),
+		WebhookRetryBackoff:     envDuration("WEBHOOK_RETRY_BACKOFF", webhookUsecase.DefaultRetryBackoff),
+		WebhookTimeout:          envDuration("WEBHOOK_TIMEOUT", webhookSender.DefaultTimeout),
+		WebhookConcurrency:      envInt("WEBHOOK_CONCURRENCY", webhookUsecase.DefaultDeliveryConcurrency),
+
+		AlertsEnabled:           envBool("ALERTS_ENABLED", false),
+		AlertEvaluationInterval: envDuration("ALERT_EVALUATION_INTERVAL", time.Minute),
+		AlertNotifyTimeout:      envDuration("ALERT_NOTIFY_TIMEOUT", webhookSender.DefaultTimeout),
+
+		ArchiveS3Bucket:    os.Getenv("ARCHIVE_S3_BUCKET"),
+		ArchiveS3Region:    envString("ARCHIVE_S3_REGION", "us-east-1"),
+		ArchiveS3Endpoint:  os.Getenv("ARCHIVE_S3_ENDPOINT"),
+		ArchiveS3Prefix:    os.Getenv("ARCHIVE_S3_PREFIX"),
+		AWSAccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		AWSSecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		AWSSessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+
+		ExportS3Bucket:   os.Getenv("EXPORT_S3_BUCKET"),
+		ExportS3Region:   envString("EXPORT_S3_REGION", "us-east-1"),
+		ExportS3Endpoint: os.Getenv("EXPORT_S3_ENDPOINT"),
+		ExportS3Prefix:   os.Getenv("EXPORT_S3_PREFIX"),
+
+		Enrichers: envList("ENRICHERS", nil),
+		GeoIPCSV:  os.Getenv("GEOIP_CSV"),
+
+		MaxBodyBytes:  envInt("MAX_BODY_BYTES", 4*1024*1024),
+		BulkMaxEvents: envInt("BULK_MAX_EVENTS", 1000),
+
+		HTTPReadTimeout:    envDuration("HTTP_READ_TIMEOUT", 0),
+		HTTPWriteTimeout:   envDuration("HTTP_WRITE_TIMEOUT", 0),
+		HTTPIdleTimeout:    envDuration("HTTP_IDLE_TIMEOUT", 0),
+		HTTPReadBufferSize: envInt("HTTP_READ_BUFFER_SIZE", 4096),
+
+		JSONMaxDepth:  envInt("JSON_MAX_DEPTH", 0),
+		JSONMaxValues: envInt("JSON_MAX_VALUES", 0),
+
+		MetricsMaxConcurrentPerKey:  envInt("METRICS_MAX_CONCURRENT_PER_KEY", 4),
+		MetricsConcurrencyOverrides: envIntMap("METRICS_CONCURRENCY_OVERRIDES"),
+
+		MetricsMaxQueryRange:  envDuration("METRICS_MAX_QUERY_RANGE", 0),
+		MetricsMaxTimeBuckets: envInt("METRICS_MAX_TIME_BUCKETS", metricsUsecase.MaxTimeBuckets),
+		MetricsQueryTimeout:   envDuration("METRICS_QUERY_TIMEOUT", 30*time.Second),
+		MetricsMaxQueryCost:   envFloat("METRICS_MAX_QUERY_COST", 0),
+		MetricsMaxQueryRows:   envFloat("METRICS_MAX_QUERY_ROWS", 0),
+
+		MetricsCacheRedisAddr:     os.Getenv("METRICS_CACHE_REDIS_ADDR"),
+		MetricsCacheRedisPassword: os.Getenv("METRICS_CACHE_REDIS_PASSWORD"),
+		MetricsCacheTTL:           envDuration("METRICS_CACHE_TTL", metricsCache.DefaultTTL),
+		MetricsCacheStale:         envDuration("METRICS_CACHE_STALE", metricsCache.DefaultStale),
+
+		RollupEnabled:  envBool("ROLLUP_ENABLED", false),
+		RollupInterval: envDuration("ROLLUP_INTERVAL", 5*time.Minute),
+		RollupLookback: envDuration("ROLLUP_LOOKBACK", metricsUsecase.DefaultRollupLookback),
+		RollupHistory:  envDuration("ROLLUP_HISTORY", metricsUsecase.DefaultRollupHistory),
+		RollupSketches: envBool("ROLLUP_SKETCHES", false),
+
+		EventCountersEnabled: envBool("EVENT_COUNTERS_ENABLED", false),
+		LiveCountersWindow:   envDuration("LIVE_COUNTERS_WINDOW", 0),
+
+		MetricsReader: envString("METRICS_READER", "postgres"),
+
+		ClickHouseURL:      os.Getenv("CLICKHOUSE_URL"),
+		ClickHouseDatabase: os.Getenv("CLICKHOUSE_DATABASE"),
+		ClickHouseUser:     os.Getenv("CLICKHOUSE_USER"),
+		ClickHousePassword: os.Getenv("CLICKHOUSE_PASSWORD"),
+
+		IngestRateLimit:     envFloat("INGEST_RATE_LIMIT", 0),
+		IngestRateBurst:     envInt("INGEST_RATE_BURST", 0),
+		IngestRateOverrides: envFloatMap("INGEST_RATE_OVERRIDES"),
+
+		QuotaDailyLimit:  envInt("QUOTA_DAILY_LIMIT", 0),
+		QuotaOverrides:   envIntMap("QUOTA_OVERRIDES"),
+		QuotaWarnPercent: envInt("QUOTA_WARN_PERCENT", quotaUsecase.DefaultWarnPercent),
+
+		IdentityStitchWindow: envDuration("IDENTITY_STITCH_WINDOW", identityUsecase.DefaultStitchWindow),
+
+		SessionGap: envDuration("SESSION_GAP", sessionUsecase.DefaultGap),
+
+		SchemaRefreshInterval: envDuration("SCHEMA_REFRESH_INTERVAL", 30*time.Second),
+
+		TenantDefaultRetentionDays: envInt("TENANT_DEFAULT_RETENTION_DAYS", tenantUsecase.DefaultRetentionDays),
+		TenantIsolation:            envBool("TENANT_ISOLATION", false),
+		TenantKeyCacheTTL:          envDuration("TENANT_KEY_CACHE_TTL", tenantUsecase.DefaultTenantKeyCacheTTL),
+
+		DimensionMetadataKeys:    envList("DIMENSION_METADATA_KEYS", nil),
+		DimensionTopN:            envInt("DIMENSION_TOP_N", metricsUsecase.DefaultDimensionTopN),
+		DimensionLookback:        envDuration("DIMENSION_LOOKBACK", metricsUsecase.DefaultDimensionLookback),
+		DimensionRefreshInterval: envDuration("DIMENSION_REFRESH_INTERVAL", 5*time.Minute),
+
+		WarmUpQueries: envList("WARMUP_QUERIES", nil),
+		WarmUpTimeout: envDuration("WARMUP_TIMEOUT", warmupUsecase.DefaultTimeout),
+
+		StorageStatsInterval: envDuration("STORAGE_STATS_INTERVAL", time.Minute),
+		StorageStatsTables:   envList("STORAGE_STATS_TABLES", []string{"events", "event_dedupe"}),
+
+		SchemaColumnModes:           envStringMap("SCHEMA_COLUMN_MODES"),
+		SchemaCompatRefreshInterval: envDuration("SCHEMA_COMPAT_REFRESH_INTERVAL", 30*time.Second),
+
+		PromotedMetadataKeys: envList("PROMOTED_METADATA_KEYS", nil),
+
+		OpenMetricsEventNames:    envList("OPENMETRICS_EVENT_NAMES", nil),
+		OpenMetricsMaxEventNames: envInt("OPENMETRICS_MAX_EVENT_NAMES", eventsPrometheus.DefaultMaxEventNames),
+		OpenMetricsChannels:      envList("OPENMETRICS_CHANNELS", nil),
+		OpenMetricsMaxChannels:   envInt("OPENMETRICS_MAX_CHANNELS", eventsPrometheus.DefaultMaxChannels),
+		OpenMetricsToken:         os.Getenv("OPENMETRICS_TOKEN"),
+		PrometheusToken:          os.Getenv("PROMETHEUS_TOKEN"),
+
+		SLOTenants:               envStringMap("SLO_TENANTS"),
+		SLOLatencyTargets:        envDurationMap("SLO_LATENCY_TARGETS"),
+		SLOLatencyObjective:      envFloat("SLO_LATENCY_OBJECTIVE", 0.99),
+		SLOAvailabilityObjective: envFloat("SLO_AVAILABILITY_OBJECTIVE", 0.999),
+
+		HeartbeatCheckInterval: envDuration("HEARTBEAT_CHECK_INTERVAL", 30*time.Second),
+		HeartbeatGrace:         envDuration("HEARTBEAT_GRACE", 30*time.Second),
+
+		AppEnv:       envString("APP_ENV", "production"),
+		ChaosEnabled: envBool("CHAOS_ENABLED", false),
+		AdminToken:   os.Getenv("ADMIN_TOKEN"),
+
+		OIDCIssuer:   os.Getenv("OIDC_ISSUER"),
+		OIDCAudience: os.Getenv("OIDC_AUDIENCE"),
+		OIDCScopes:   envList("OIDC_SCOPES", []string{"metrics:read"}),
+
+		GA4Channels:      envStringMap("GA4_CHANNELS"),
+		SnowplowChannels: envStringMap("SNOWPLOW_CHANNELS"),
+
+		APIKeyScopes:        envScopesMap("API_KEY_SCOPES"),
+		DefaultAPIKeyScopes: envScopes("DEFAULT_API_KEY_SCOPES", 0),
+	}
+
+	if cfg.PostgresDSN == "" {
+		log.Fatal("POSTGRES_DSN is not set")
+	}
+
+	if err := eventsUsecase.ValidatePromotedKeys(cfg.PromotedMetadataKeys); err != nil {
+		log.Fatalf("invalid PROMOTED_METADATA_KEYS: %v", err)
+	}
+
+	if err := migrationUsecase.ValidateColumnModes(cfg.SchemaColumnModes, gatedColumns()); err != nil {
+		log.Fatalf("invalid SCHEMA_COLUMN_MODES: %v", err)
+	}
+
+	if cfg.EventsRetentionDays < 0 {
+		log.Fatalf("invalid EVENTS_RETENTION_DAYS: %d must not be negative", cfg.EventsRetentionDays)
+	}
+
+	for name, rate := range cfg.SamplingRates {
+		if rate <= 0 || rate > 1 {
+			log.Fatalf("invalid SAMPLING_RATES: %s=%v must be above 0 and at most 1", name, rate)
+		}
+	}
+
+	if cfg.IngestRateBurst < 0 {
+		log.Fatalf("invalid INGEST_RATE_BURST: %d must not be negative", cfg.IngestRateBurst)
+	}
+
+	if cfg.MaxBodyBytes < 1 {
+		log.Fatalf("invalid MAX_BODY_BYTES: %d must be at least 1", cfg.MaxBodyBytes)
+	}
+	if cfg.BulkMaxEvents < 0 {
+		log.Fatalf("invalid BULK_MAX_EVENTS: %d must not be negative", cfg.BulkMaxEvents)
+	}
+	if cfg.HTTPReadTimeout < 0 || cfg.HTTPWriteTimeout < 0 || cfg.HTTPIdleTimeout < 0 {
+		log.Fatalf("invalid HTTP_*_TIMEOUT: timeouts must not be negative")
+	}
+	if cfg.HTTPReadBufferSize < 1024 {
+		log.Fatalf("invalid HTTP_READ_BUFFER_SIZE: %d must be at least 1024", cfg.HTTPReadBufferSize)
+	}
+	if cfg.JSONMaxDepth < 0 || cfg.JSONMaxValues < 0 {
+		log.Fatalf("invalid JSON_MAX_*: limits must not be negative")
+	}
+
+	if cfg.MetricsMaxQueryRange < 0 {
+		log.Fatalf("invalid METRICS_MAX_QUERY_RANGE: %s must not be negative", cfg.MetricsMaxQueryRange)
+	}
+	if cfg.MetricsMaxTimeBuckets < 1 {
+		log.Fatalf("invalid METRICS_MAX_TIME_BUCKETS: %d must be positive", cfg.MetricsMaxTimeBuckets)
+	}
+	if cfg.MetricsQueryTimeout < 0 {
+		log.Fatalf("invalid METRICS_QUERY_TIMEOUT: %s must not be negative", cfg.MetricsQueryTimeout)
+	}
+	if cfg.MetricsMaxQueryCost < 0 {
+		log.Fatalf("invalid METRICS_MAX_QUERY_COST: %g must not be negative", cfg.MetricsMaxQueryCost)
+	}
+	if cfg.MetricsMaxQueryRows < 0 {
+		log.Fatalf("invalid METRICS_MAX_QUERY_ROWS: %g must not be negative", cfg.MetricsMaxQueryRows)
+	}
+	if cfg.MetricsCacheRedisAddr != "" {
+		if cfg.MetricsCacheTTL <= 0 {
+			log.Fatalf("invalid METRICS_CACHE_TTL: %s must be positive", cfg.MetricsCacheTTL)
+		}
+		if cfg.MetricsCacheStale < 0 {
+			log.Fatalf("invalid METRICS_CACHE_STALE: %s must not be negative", cfg.MetricsCacheStale)
+		}
+	}
+
+	if cfg.RollupEnabled {
+		if cfg.RollupInterval <= 0 {
+			log.Fatalf("invalid ROLLUP_INTERVAL: %s must be positive", cfg.RollupInterval)
+		}
+		// Late events are only picked up within the lookback, which has to
+		// cover at least the last closed day.
+		if cfg.RollupLookback < 24*time.Hour {
+			log.Fatalf("invalid ROLLUP_LOOKBACK: %s must be at least 24h", cfg.RollupLookback)
+		}
+		if cfg.RollupHistory < 0 {
+			log.Fatalf("invalid ROLLUP_HISTORY: %s must not be negative", cfg.RollupHistory)
+		}
+	}
+
+	if cfg.LiveCountersWindow != 0 && (cfg.LiveCountersWindow < time.Minute || cfg.LiveCountersWindow > time.Hour) {
+		log.Fatalf("invalid LIVE_COUNTERS_WINDOW: %s must be between 1m and 1h", cfg.LiveCountersWindow)
+	}
+
+	if cfg.RollupSketches && !cfg.RollupEnabled {
+		log.Fatal("ROLLUP_SKETCHES requires ROLLUP_ENABLED")
+	}
+
+	switch cfg.MetricsReader {
+	case "postgres":
+	case "timescale":
+		if cfg.RollupEnabled {
+			log.Fatal("ROLLUP_ENABLED must not be set when METRICS_READER=timescale")
+		}
+	case "clickhouse":
+		if cfg.ClickHouseURL == "" {
+			log.Fatal("CLICKHOUSE_URL is required when METRICS_READER=clickhouse")
+		}
+	default:
+		log.Fatalf("invalid METRICS_READER: %q (want postgres, timescale or clickhouse)", cfg.MetricsReader)
+	}
+
+	if cfg.InsertRetries < 0 {
+		log.Fatalf("invalid INSERT_RETRIES: %d must not be negative", cfg.InsertRetries)
+	}
+
+	if cfg.SessionGap < sessionUsecase.MinGap || cfg.SessionGap > sessionUsecase.MaxGap {
+		log.Fatalf("invalid SESSION_GAP: %s must be between %s and %s", cfg.SessionGap, sessionUsecase.MinGap, sessionUsecase.MaxGap)
+	}
+
+	if cfg.WebhooksEnabled {
+		if cfg.WebhookMaxAttempts < 1 {
+			log.Fatalf("invalid WEBHOOK_MAX_ATTEMPTS: %d must be at least 1", cfg.WebhookMaxAttempts)
+		}
+		if cfg.WebhookConcurrency < 1 {
+			log.Fatalf("invalid WEBHOOK_CONCURRENCY: %d must be at least 1", cfg.WebhookConcurrency)
+		}
+	}
+
+	if cfg.AlertsEnabled && cfg.AlertEvaluationInterval <= 0 {
+		log.Fatalf("invalid ALERT_EVALUATION_INTERVAL: %s must be positive", cfg.AlertEvaluationInterval)
+	}
+	if cfg.QueryAuditEnabled {
+		if cfg.QueryAuditRetention <= 0 {
+			log.Fatalf("invalid QUERY_AUDIT_RETENTION: %s must be positive", cfg.QueryAuditRetention)
+		}
+		if cfg.QueryAuditPurgeInterval <= 0 {
+			log.Fatalf("invalid QUERY_AUDIT_PURGE_INTERVAL: %s must be positive", cfg.QueryAuditPurgeInterval)
+		}
+	}
+
+	if cfg.OIDCIssuer != "" {
+		if u, err := url.Parse(cfg.OIDCIssuer); err != nil || u.Scheme == "" || u.Host == "" {
+			log.Fatalf("invalid OIDC_ISSUER: %q must be an absolute URL", cfg.OIDCIssuer)
+		}
+	}
+
+	if cfg.DefaultAPIKeyScopes.Has(authDomain.ScopeAdmin) {
+		log.Fatal("invalid DEFAULT_API_KEY_SCOPES: admin must be granted per key")
+	}
+
+	if cfg.ArchiveS3Bucket != "" {
+		if cfg.AWSAccessKeyID == "" || cfg.AWSSecretAccessKey == "" {
+			log.Fatal("ARCHIVE_S3_BUCKET requires AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+		}
+		if cfg.ArchiveS3Endpoint != "" {
+			if u, err := url.Parse(cfg.ArchiveS3Endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+				log.Fatalf("invalid ARCHIVE_S3_ENDPOINT: %q must be an absolute URL", cfg.ArchiveS3Endpoint)
+			}
+		}
+		if cfg.ArchiveS3Prefix != "" && !strings.HasSuffix(cfg.ArchiveS3Prefix, "/") {
+			cfg.ArchiveS3Prefix += "/"
+		}
+	}
+
+	if cfg.ExportS3Bucket != "" {
+		if cfg.AWSAccessKeyID == "" || cfg.AWSSecretAccessKey == "" {
+			log.Fatal("EXPORT_S3_BUCKET requires AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+		}
+		if cfg.ExportS3Endpoint != "" {
+			if u, err := url.Parse(cfg.ExportS3Endpoint); err != nil || u.Scheme == "" || u.Host == "" {
+				log.Fatalf("invalid EXPORT_S3_ENDPOINT: %q must be an absolute URL", cfg.ExportS3Endpoint)
+			}
+		}
+		if cfg.ExportS3Prefix != "" && !strings.HasSuffix(cfg.ExportS3Prefix, "/") {
+			cfg.ExportS3Prefix += "/"
+		}
+	}
+
+	for _, raw := range cfg.WarmUpQueries {
+		if _, err := metricsWarmup.ParseSavedQuery(raw); err != nil {
+			log.Fatalf("invalid WARMUP_QUERIES: %v", err)
+		}
+	}
+
+	for op := range cfg.SLOLatencyTargets {
+		if _, ok := defaultSLOLatencyTargets[op]; !ok {
+			log.Fatalf("invalid SLO_LATENCY_TARGETS: unknown operation %q", op)
+		}
+	}
+	for key, v := range map[string]float64{
+		"SLO_LATENCY_OBJECTIVE":      cfg.SLOLatencyObjective,
+		"SLO_AVAILABILITY_OBJECTIVE": cfg.SLOAvailabilityObjective,
+	} {
+		if v <= 0 || v >= 1 {
+			log.Fatalf("invalid %s: %v must be between 0 and 1 (exclusive)", key, v)
+		}
+	}
+
+	if cfg.ChaosEnabled && cfg.AppEnv == "production" {
+		log.Fatal("CHAOS_ENABLED must not be set when APP_ENV=production")
+	}
+
+	return cfg
+}
+
+func envString(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func envInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Fatalf("invalid %s: %v", key, err)
+	}
+	return n
+}
+
+func envBool(key string, def bool) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Fatalf("invalid %s: %v", key, err)
+	}
+	return b
+}
+
+func envDuration(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Fatalf("invalid %s: %v", key, err)
+	}
+	return d
+}
+
+func envFloat(key string, def float64) float64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		log.Fatalf("invalid %s: %v", key, err)
+	}
+	return f
+}
+
+// envList parses a comma separated list, ignoring empty items.
+func envList(key string, def []string) []string {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// envIntMap parses "key=value" pairs separated by commas, e.g. "a=1,b=2".
+func envIntMap(key string) map[string]int {
+	out := map[string]int{}
+	for _, pair := range envList(key, nil) {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			log.Fatalf("invalid %s: %q is not key=value", key, pair)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			log.Fatalf("invalid %s: %v", key, err)
+		}
+		out[strings.TrimSpace(k)] = n
+	}
+	return out
+}
+
+// envStringMap parses "key=value" pairs separated by commas, e.g. "a=x,b=y".
+func envStringMap(key string) map[string]string {
+	out := map[string]string{}
+	for _, pair := range envList(key, nil) {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			log.Fatalf("invalid %s: %q is not key=value", key, pair)
+		}
+		out[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return out
+}
+
+// envFloatMap parses "key=number" pairs separated by commas, e.g. "a=0.1,b=0.5".
+func envFloatMap(key string) map[string]float64 {
+	out := map[string]float64{}
+	for k, v := range envStringMap(key) {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			log.Fatalf("invalid %s: %v", key, err)
+		}
+		out[k] = f
+	}
+	return out
+}
+
+// envDurationMap parses "key=duration" pairs separated by commas, e.g. "a=100ms,b=1s".
+func envDurationMap(key string) map[string]time.Duration {
+	out := map[string]time.Duration{}
+	for k, v := range envStringMap(key) {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			log.Fatalf("invalid %s: %v", key, err)
+		}
+		out[k] = d
+	}
+	return out
+}
+
+// envScopes parses a space separated scope list, e.g. "events:write metrics:read".
+func envScopes(key string, def authDomain.Scopes) authDomain.Scopes {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	scopes, err := authDomain.ParseScopes(v)
+	if err != nil {
+		log.Fatalf("invalid %s: %v", key, err)
+	}
+	return scopes
+}
+
+// envScopesMap parses "key=scopes" pairs separated by commas, e.g.
+// "a=events:write metrics:read,b=admin".
+func envScopesMap(key string) map[string]authDomain.Scopes {
+	out := map[string]authDomain.Scopes{}
+	for k, v := range envStringMap(key) {
+		scopes, err := authDomain.ParseScopes(v)
+		if err != nil {
+			log.Fatalf("invalid %s: %v", key, err)
+		}
+		out[k] = scopes
+	}
+	return out
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	authDomain "event-metrics-service/internal/auth/core/domain"
+)
+
+func TestLoadConfig_HTTPDefaults(t *testing.T) {
+	t.Setenv("POSTGRES_DSN", "postgres://localhost/events")
+
+	cfg := loadConfig()
+
+	if cfg.HTTPReadTimeout != 0 || cfg.HTTPWriteTimeout != 0 || cfg.HTTPIdleTimeout != 0 {
+		t.Fatalf("expected no HTTP timeouts by default, got read=%s write=%s idle=%s",
+			cfg.HTTPReadTimeout, cfg.HTTPWriteTimeout, cfg.HTTPIdleTimeout)
+	}
+	if cfg.HTTPReadBufferSize != 4096 {
+		t.Fatalf("expected read buffer 4096, got %d", cfg.HTTPReadBufferSize)
+	}
+	if cfg.MaxBodyBytes != 4*1024*1024 {
+		t.Fatalf("expected body limit 4 MiB, got %d", cfg.MaxBodyBytes)
+	}
+	if cfg.JSONMaxDepth != 0 || cfg.JSONMaxValues != 0 {
+		t.Fatalf("expected unlimited JSON by default, got depth=%d values=%d", cfg.JSONMaxDepth, cfg.JSONMaxValues)
+	}
+}
+
+func TestLoadConfig_HTTPOverrides(t *testing.T) {
+	t.Setenv("POSTGRES_DSN", "postgres://localhost/events")
+	t.Setenv("HTTP_READ_TIMEOUT", "15s")
+	t.Setenv("HTTP_WRITE_TIMEOUT", "1m")
+	t.Setenv("HTTP_IDLE_TIMEOUT", "90s")
+	t.Setenv("HTTP_READ_BUFFER_SIZE", "16384")
+	t.Setenv("MAX_BODY_BYTES", "1048576")
+	t.Setenv("JSON_MAX_DEPTH", "32")
+	t.Setenv("JSON_MAX_VALUES", "100000")
+
+	cfg := loadConfig()
+
+	if cfg.HTTPReadTimeout != 15*time.Second {
+		t.Fatalf("expected read timeout 15s, got %s", cfg.HTTPReadTimeout)
+	}
+	if cfg.HTTPWriteTimeout != time.Minute {
+		t.Fatalf("expected write timeout 1m, got %s", cfg.HTTPWriteTimeout)
+	}
+	if cfg.HTTPIdleTimeout != 90*time.Second {
+		t.Fatalf("expected idle timeout 90s, got %s", cfg.HTTPIdleTimeout)
+	}
+	if cfg.HTTPReadBufferSize != 16384 {
+		t.Fatalf("expected read buffer 16384, got %d", cfg.HTTPReadBufferSize)
+	}
+	if cfg.MaxBodyBytes != 1048576 {
+		t.Fatalf("expected body limit 1048576, got %d", cfg.MaxBodyBytes)
+	}
+	if cfg.JSONMaxDepth != 32 || cfg.JSONMaxValues != 100000 {
+		t.Fatalf("expected JSON limits 32/100000, got %d/%d", cfg.JSONMaxDepth, cfg.JSONMaxValues)
+	}
+}
+
+func TestLoadConfig_NoDefaultScopes(t *testing.T) {
+	t.Setenv("POSTGRES_DSN", "postgres://localhost/events")
+
+	cfg := loadConfig()
+
+	if cfg.DefaultAPIKeyScopes != 0 {
+		t.Fatalf("expected no default scopes, got %s", cfg.DefaultAPIKeyScopes)
+	}
+}
+
+func TestAPIKeyScopes_DefaultsSkipAnonymous(t *testing.T) {
+	defaults := authDomain.ScopeEventsWrite | authDomain.ScopeMetricsRead
+	scopesOf := apiKeyScopes(map[string]authDomain.Scopes{"dash-key": authDomain.ScopeMetricsRead}, defaults)
+
+	if got := scopesOf("dash-key"); got != authDomain.ScopeMetricsRead {
+		t.Fatalf("expected the listed scopes, got %s", got)
+	}
+	if got := scopesOf("other-key"); got != defaults {
+		t.Fatalf("expected the default scopes, got %s", got)
+	}
+	if got := scopesOf(authDomain.AnonymousKey); got != 0 {
+		t.Fatalf("expected no scopes for anonymous callers, got %s", got)
+	}
+
+	scopesOf = apiKeyScopes(map[string]authDomain.Scopes{authDomain.AnonymousKey: authDomain.ScopeEventsWrite}, defaults)
+	if got := scopesOf(authDomain.AnonymousKey); got != authDomain.ScopeEventsWrite {
+		t.Fatalf("expected the scopes listed for anonymous, got %s", got)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	authDomain "event-metrics-service/internal/auth/core/domain"
+	authPorts "event-metrics-service/internal/auth/core/ports"
+	metricsGrpc "event-metrics-service/internal/metrics/adapters/grpc"
+	"event-metrics-service/internal/metrics/adapters/grpc/metricsv1"
+	throttleUsecase "event-metrics-service/internal/throttle/core/usecase"
+)
+
+// grpcReadAccess authorizes gRPC calls like the metrics read routes: the
+// x-api-key and authorization metadata, the tenant of the key, scope
+// metrics:read and the concurrency limit of the caller. verifier and
+// resolveTenant are nil when OIDC and tenant isolation are off.
+type grpcReadAccess struct {
+	scopesOf      func(apiKey string) authDomain.Scopes
+	verifier      authPorts.TokenVerifierPort
+	tokenScopes   []string
+	resolveTenant func(ctx context.Context, apiKey string) (string, error)
+	limiter       *throttleUsecase.ConcurrencyLimiter
+}
+
+func (a grpcReadAccess) intercept(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	p := authDomain.Principal{APIKey: metadataValue(md, "x-api-key")}
+	if p.APIKey == "" {
+		p.APIKey = authDomain.AnonymousKey
+	}
+	p.Scopes = a.scopesOf(p.APIKey)
+
+	if a.verifier != nil {
+		token, ok := strings.CutPrefix(metadataValue(md, "authorization"), "Bearer ")
+		if !ok || token == "" {
+			return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+		}
+		claims, err := a.verifier.Verify(ctx, token)
+		switch {
+		case errors.Is(err, authDomain.ErrInvalidToken):
+			return nil, status.Error(codes.Unauthenticated, "invalid bearer token")
+		case err != nil:
+			return nil, status.Error(codes.Unavailable, "token verification is unavailable")
+		}
+		if len(claims.MissingScopes(a.tokenScopes)) > 0 {
+			return nil, status.Error(codes.PermissionDenied, "insufficient scope")
+		}
+		p.Subject = claims.Subject
+		p.Scopes |= authDomain.KnownScopes(claims.Scopes)
+	}
+
+	if a.resolveTenant != nil {
+		if p.APIKey == authDomain.AnonymousKey {
+			return nil, status.Error(codes.Unauthenticated, "missing api key")
+		}
+		tenantID, err := a.resolveTenant(ctx, p.APIKey)
+		if err != nil {
+			return nil, status.Error(codes.Unavailable, "tenant lookup is unavailable")
+		}
+		if tenantID == "" {
+			return nil, status.Error(codes.Unauthenticated, "unknown api key")
+		}
+		p.TenantID = tenantID
+	}
+
+	if !p.Scopes.Has(authDomain.ScopeMetricsRead) {
+		return nil, status.Error(codes.PermissionDenied, "missing scope "+authDomain.ScopeMetricsRead.String())
+	}
+
+	caller := p.APIKey
+	if p.APIKey == authDomain.AnonymousKey && p.Subject != "" {
+		caller = "sub:" + p.Subject
+	}
+	release, ok := a.limiter.TryAcquire(caller)
+	if !ok {
+		return nil, status.Error(codes.ResourceExhausted, "too many concurrent queries for this API key")
+	}
+	defer release()
+
+	return handler(authDomain.WithPrincipal(ctx, p), req)
+}
+
+func metadataValue(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// newGRPCServer serves MetricsService on metrics, authorizing every call
+// with access.
+func newGRPCServer(metrics metricsGrpc.GetMetricsUseCase, access grpcReadAccess) *grpc.Server {
+	s := grpc.NewServer(grpc.UnaryInterceptor(access.intercept))
+	metricsv1.RegisterMetricsServiceServer(s, metricsGrpc.NewServer(metrics))
+	return s
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"context"
+
+	eventsDomain "event-metrics-service/internal/events/core/domain"
+	eventsPorts "event-metrics-service/internal/events/core/ports"
+	metricsLive "event-metrics-service/internal/metrics/adapters/live"
+)
+
+// liveCounter counts stored events into the live counters metrics queries
+// merge in.
+type liveCounter struct {
+	counters *metricsLive.Counters
+}
+
+var _ eventsPorts.EventObserverPort = liveCounter{}
+
+func (l liveCounter) EventStored(_ context.Context, e *eventsDomain.Event) {
+	l.counters.Add(e.TenantID, e.EventName, e.Channel, e.EventTime, e.Weight())
+}
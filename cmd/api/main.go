@@ -3,36 +3,150 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"log"
+	"net"
+	"net/url"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
 	"time"
 
+	eventsChaos "event-metrics-service/internal/events/adapters/chaos"
+	eventsDeadLetter "event-metrics-service/internal/events/adapters/deadletter"
+	eventsEnrichment "event-metrics-service/internal/events/adapters/enrichment"
 	eventsHttp "event-metrics-service/internal/events/adapters/http/fiber"
+	eventsKafka "event-metrics-service/internal/events/adapters/kafka"
 	eventsRepoPg "event-metrics-service/internal/events/adapters/postgres"
+	eventsPrometheus "event-metrics-service/internal/events/adapters/prometheus"
+	eventsSLO "event-metrics-service/internal/events/adapters/slo"
+	eventsPorts "event-metrics-service/internal/events/core/ports"
 	eventsUsecase "event-metrics-service/internal/events/core/usecase"
 
+	metricsAudit "event-metrics-service/internal/metrics/adapters/audit"
+	metricsCache "event-metrics-service/internal/metrics/adapters/cache"
+	metricsChaos "event-metrics-service/internal/metrics/adapters/chaos"
+	metricsClickHouse "event-metrics-service/internal/metrics/adapters/clickhouse"
 	metricsHttp "event-metrics-service/internal/metrics/adapters/http/fiber"
+	metricsLive "event-metrics-service/internal/metrics/adapters/live"
 	metricsRepoPg "event-metrics-service/internal/metrics/adapters/postgres"
+	metricsSLO "event-metrics-service/internal/metrics/adapters/slo"
+	metricsTimescale "event-metrics-service/internal/metrics/adapters/timescale"
+	metricsWarmup "event-metrics-service/internal/metrics/adapters/warmup"
+	metricsPorts "event-metrics-service/internal/metrics/core/ports"
 	metricsUsecase "event-metrics-service/internal/metrics/core/usecase"
 
+	storageRepoPg "event-metrics-service/internal/storage/adapters/postgres"
+	storagePrometheus "event-metrics-service/internal/storage/adapters/prometheus"
+	storageUsecase "event-metrics-service/internal/storage/core/usecase"
+
+	heartbeatHttp "event-metrics-service/internal/heartbeat/adapters/http/fiber"
+	heartbeatRepoPg "event-metrics-service/internal/heartbeat/adapters/postgres"
+	heartbeatPrometheus "event-metrics-service/internal/heartbeat/adapters/prometheus"
+	heartbeatDomain "event-metrics-service/internal/heartbeat/core/domain"
+	heartbeatUsecase "event-metrics-service/internal/heartbeat/core/usecase"
+
+	"event-metrics-service/internal/jsonlimit"
+	"event-metrics-service/internal/telemetry"
+
+	authHttp "event-metrics-service/internal/auth/adapters/http/fiber"
+	authOIDC "event-metrics-service/internal/auth/adapters/oidc"
+	authDomain "event-metrics-service/internal/auth/core/domain"
+	authPorts "event-metrics-service/internal/auth/core/ports"
+
+	throttleHttp "event-metrics-service/internal/throttle/adapters/http/fiber"
+	throttleUsecase "event-metrics-service/internal/throttle/core/usecase"
+
+	quotaHttp "event-metrics-service/internal/quota/adapters/http/fiber"
+	quotaRepoPg "event-metrics-service/internal/quota/adapters/postgres"
+	quotaUsecase "event-metrics-service/internal/quota/core/usecase"
+
+	tenantHttp "event-metrics-service/internal/tenant/adapters/http/fiber"
+	tenantRepoPg "event-metrics-service/internal/tenant/adapters/postgres"
+	tenantUsecase "event-metrics-service/internal/tenant/core/usecase"
+
+	identityHttp "event-metrics-service/internal/identity/adapters/http/fiber"
+	identityRepoPg "event-metrics-service/internal/identity/adapters/postgres"
+	identityUsecase "event-metrics-service/internal/identity/core/usecase"
+
+	schemaHttp "event-metrics-service/internal/schema/adapters/http/fiber"
+	schemaRepoPg "event-metrics-service/internal/schema/adapters/postgres"
+	schemaUsecase "event-metrics-service/internal/schema/core/usecase"
+
+	auditHttp "event-metrics-service/internal/audit/adapters/http/fiber"
+	auditRepoPg "event-metrics-service/internal/audit/adapters/postgres"
+	auditUsecase "event-metrics-service/internal/audit/core/usecase"
+
+	journalHttp "event-metrics-service/internal/journal/adapters/http/fiber"
+	journalRepoPg "event-metrics-service/internal/journal/adapters/postgres"
+	journalUsecase "event-metrics-service/internal/journal/core/usecase"
+
+	migrationHttp "event-metrics-service/internal/migration/adapters/http/fiber"
+	migrationRepoPg "event-metrics-service/internal/migration/adapters/postgres"
+	migrationDomain "event-metrics-service/internal/migration/core/domain"
+	migrationUsecase "event-metrics-service/internal/migration/core/usecase"
+
+	privacyHttp "event-metrics-service/internal/privacy/adapters/http/fiber"
+	privacyRepoPg "event-metrics-service/internal/privacy/adapters/postgres"
+	privacyUsecase "event-metrics-service/internal/privacy/core/usecase"
+
+	sessionHttp "event-metrics-service/internal/session/adapters/http/fiber"
+	sessionRepoPg "event-metrics-service/internal/session/adapters/postgres"
+	sessionUsecase "event-metrics-service/internal/session/core/usecase"
+
+	sloHttp "event-metrics-service/internal/slo/adapters/http/fiber"
+	sloPrometheus "event-metrics-service/internal/slo/adapters/prometheus"
+	sloDomain "event-metrics-service/internal/slo/core/domain"
+	sloUsecase "event-metrics-service/internal/slo/core/usecase"
+
+	archiveHttp "event-metrics-service/internal/archive/adapters/http/fiber"
+	archiveNDJSON "event-metrics-service/internal/archive/adapters/ndjson"
+	archiveParquet "event-metrics-service/internal/archive/adapters/parquet"
+	archiveRepoPg "event-metrics-service/internal/archive/adapters/postgres"
+	archiveS3 "event-metrics-service/internal/archive/adapters/s3"
+	archiveDomain "event-metrics-service/internal/archive/core/domain"
+	archivePorts "event-metrics-service/internal/archive/core/ports"
+	archiveUsecase "event-metrics-service/internal/archive/core/usecase"
+
+	warmupHttp "event-metrics-service/internal/warmup/adapters/http/fiber"
+	warmupDomain "event-metrics-service/internal/warmup/core/domain"
+	warmupPorts "event-metrics-service/internal/warmup/core/ports"
+	warmupUsecase "event-metrics-service/internal/warmup/core/usecase"
+
+	chaosHttp "event-metrics-service/internal/chaos/adapters/http/fiber"
+	chaosDomain "event-metrics-service/internal/chaos/core/domain"
+	chaosUsecase "event-metrics-service/internal/chaos/core/usecase"
+
+	webhookHttp "event-metrics-service/internal/webhook/adapters/http/fiber"
+	webhookRepoPg "event-metrics-service/internal/webhook/adapters/postgres"
+	webhookSender "event-metrics-service/internal/webhook/adapters/sender"
+	webhookUsecase "event-metrics-service/internal/webhook/core/usecase"
+
+	reportHttp "event-metrics-service/internal/report/adapters/http/fiber"
+	reportRepoPg "event-metrics-service/internal/report/adapters/postgres"
+	reportUsecase "event-metrics-service/internal/report/core/usecase"
+
+	alertHttp "event-metrics-service/internal/alert/adapters/http/fiber"
+	alertRepoPg "event-metrics-service/internal/alert/adapters/postgres"
+	alertUsecase "event-metrics-service/internal/alert/core/usecase"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	_ "github.com/lib/pq"
 	fiberSwagger "github.com/swaggo/fiber-swagger"
+	"google.golang.org/grpc"
 
 	_ "event-metrics-service/docs"
 )
 
 func main() {
 	// Config
-	dsn := os.Getenv("POSTGRES_DSN")
-	if dsn == "" {
-		log.Fatal("POSTGRES_DSN is not set")
-	}
+	cfg := loadConfig()
 
 	// DB connection
-	db, err := sql.Open("postgres", dsn)
+	db, err := sql.Open("postgres", cfg.PostgresDSN)
 	if err != nil {
 		log.Fatalf("failed to open postgres: %v", err)
 	}
@@ -49,38 +163,844 @@ func main() {
 	// Adapter-level DB wrappers
 	eventsDB := eventsRepoPg.NewSQLDB(db)
 	metricsDB := metricsRepoPg.NewSQLDB(db)
+	storageDB := storageRepoPg.NewSQLDB(db)
+	heartbeatDB := heartbeatRepoPg.NewSQLDB(db)
+	quotaDB := quotaRepoPg.NewSQLDB(db)
+	tenantDB := tenantRepoPg.NewSQLDB(db)
+	identityDB := identityRepoPg.NewSQLDB(db)
+	schemaDB := schemaRepoPg.NewSQLDB(db)
+	journalDB := journalRepoPg.NewSQLDB(db)
+	auditDB := auditRepoPg.NewSQLDB(db)
+	migrationDB := migrationRepoPg.NewSQLDB(db)
+	privacyDB := privacyRepoPg.NewSQLDB(db)
+	sessionDB := sessionRepoPg.NewSQLDB(db)
+	webhookDB := webhookRepoPg.NewSQLDB(db)
+	reportDB := reportRepoPg.NewSQLDB(db)
+	alertDB := alertRepoPg.NewSQLDB(db)
+
+	// Columns added by recent migrations are only used once they exist, so
+	// old and new releases can share the database during a rolling deploy.
+	schemaCompatUC := migrationUsecase.NewCompatUseCase(
+		migrationRepoPg.NewColumnProber(migrationDB),
+		gatedColumns(),
+		cfg.SchemaColumnModes,
+	)
+	if _, err := schemaCompatUC.Refresh(context.Background()); err != nil {
+		log.Fatalf("failed to probe schema columns: %v", err)
+	}
+	for _, s := range schemaCompatUC.States() {
+		log.Printf("schema column %s: mode=%s exists=%t", s.Column, s.Mode, s.Exists)
+	}
 
 	// Repositories
-	eventRepository := eventsRepoPg.NewEventRepository(eventsDB)
-	metricsRepository := metricsRepoPg.NewMetricsRepository(metricsDB)
+	eventRepoOpts := []eventsRepoPg.RepositoryOption{
+		eventsRepoPg.WithPromotedMetadataKeys(cfg.PromotedMetadataKeys...),
+		eventsRepoPg.WithColumnGate(schemaCompatUC),
+	}
+	if len(cfg.KafkaBrokers) > 0 || cfg.WebhooksEnabled {
+		eventRepoOpts = append(eventRepoOpts, eventsRepoPg.WithOutbox())
+	}
+	if cfg.EventCountersEnabled {
+		eventRepoOpts = append(eventRepoOpts, eventsRepoPg.WithCounters())
+	}
+	eventRepository := eventsRepoPg.NewEventRepository(eventsDB, eventRepoOpts...)
+
+	// Promoted metadata columns are added by migrations and must exist before
+	// ingest writes them; the reader switches to a column once it is indexed
+	// and backfilled.
+	promoteMetadataUC := eventsUsecase.NewPromoteMetadataUseCase(
+		eventRepository,
+		cfg.PromotedMetadataKeys,
+		eventsUsecase.DefaultPromotedBackfillBatchSize,
+	)
+	if err := promoteMetadataUC.Prepare(context.Background()); err != nil {
+		log.Fatalf("failed to check promoted metadata columns: %v", err)
+	}
+
+	metricsRepoOpts := []metricsRepoPg.RepositoryOption{
+		metricsRepoPg.WithPromotedMetadata(promoteMetadataUC.Ready),
+		metricsRepoPg.WithColumnGate(schemaCompatUC),
+		metricsRepoPg.WithCostLimit(cfg.MetricsMaxQueryCost, cfg.MetricsMaxQueryRows),
+	}
+	if cfg.EventCountersEnabled {
+		metricsRepoOpts = append(metricsRepoOpts, metricsRepoPg.WithCounters())
+	}
+
+	// Rollups are written through a repository of their own; metrics only
+	// read the buckets the worker reports rolled up
+	var rollupUC *metricsUsecase.RollupUseCase
+	if cfg.RollupEnabled {
+		rollupRepoOpts := []metricsRepoPg.RepositoryOption{metricsRepoPg.WithColumnGate(schemaCompatUC)}
+		if cfg.RollupSketches {
+			available, err := metricsRepoPg.SketchesAvailable(context.Background(), metricsDB)
+			if err != nil {
+				log.Fatalf("failed to detect rollup sketches: %v", err)
+			}
+			if available {
+				rollupRepoOpts = append(rollupRepoOpts, metricsRepoPg.WithRollupSketches())
+				metricsRepoOpts = append(metricsRepoOpts, metricsRepoPg.WithRollupSketches())
+			} else {
+				log.Printf("ROLLUP_SKETCHES: hll or the tables of migrations/hll are missing, rolling up users exactly")
+			}
+		}
+		rollupUC = metricsUsecase.NewRollupUseCase(
+			metricsRepoPg.NewMetricsRepository(metricsDB, rollupRepoOpts...),
+			metricsUsecase.WithRollupLookback(cfg.RollupLookback),
+			metricsUsecase.WithRollupHistory(cfg.RollupHistory),
+		)
+		metricsRepoOpts = append(metricsRepoOpts, metricsRepoPg.WithRollups(rollupUC.Coverage))
+	}
+
+	// Continuous aggregates, when the extension and views are there
+	newMetricsRepository := metricsRepoPg.NewMetricsRepository
+	if cfg.MetricsReader == "timescale" {
+		available, err := metricsTimescale.Available(context.Background(), metricsDB)
+		if err != nil {
+			log.Fatalf("failed to detect TimescaleDB: %v", err)
+		}
+		if available {
+			newMetricsRepository = metricsTimescale.NewMetricsRepository
+		} else {
+			log.Printf("METRICS_READER=timescale: timescaledb or its continuous aggregates are missing, reading events")
+		}
+	}
+	metricsRepository := newMetricsRepository(metricsDB, metricsRepoOpts...)
+	storageStatsRepository := storageRepoPg.NewStorageStatsRepository(storageDB)
+	heartbeatRepository := heartbeatRepoPg.NewHeartbeatRepository(heartbeatDB, heartbeatRepoPg.WithColumnGate(schemaCompatUC))
+	quotaUsageRepository := quotaRepoPg.NewUsageRepository(quotaDB)
+	tenantRepository := tenantRepoPg.NewTenantRepository(tenantDB)
+	identityRepository := identityRepoPg.NewIdentityRepository(identityDB, identityRepoPg.WithColumnGate(schemaCompatUC))
+	schemaRepository := schemaRepoPg.NewSchemaRepository(schemaDB)
+	journalRepository := journalRepoPg.NewJournalRepository(journalDB)
+	auditRepository := auditRepoPg.NewAuditRepository(auditDB)
+	webhookRepository := webhookRepoPg.NewWebhookRepository(webhookDB)
+	eraserOpts := []privacyRepoPg.EraserOption{privacyRepoPg.WithColumnGate(schemaCompatUC)}
+	if cfg.EventCountersEnabled {
+		eraserOpts = append(eraserOpts, privacyRepoPg.WithCounters())
+	}
+	userEraser := privacyRepoPg.NewUserEraser(privacyDB, eraserOpts...)
+	sessionRepository := sessionRepoPg.NewSessionRepository(sessionDB, sessionRepoPg.WithColumnGate(schemaCompatUC))
+
+	// Fault injection (non-production only, see loadConfig)
+	var eventStore eventsPorts.EventRepositoryPort = eventRepository
+	var metricsReader metricsPorts.MetricsReaderPort = metricsRepository
+
+	// ClickHouse copy of events, Postgres for what it cannot answer
+	if cfg.MetricsReader == "clickhouse" {
+		clickHouseClient, err := metricsClickHouse.NewClient(
+			cfg.ClickHouseURL,
+			metricsClickHouse.WithDatabase(cfg.ClickHouseDatabase),
+			metricsClickHouse.WithCredentials(cfg.ClickHouseUser, cfg.ClickHousePassword),
+		)
+		if err != nil {
+			log.Fatalf("failed to create clickhouse client: %v", err)
+		}
+		defer clickHouseClient.Close()
+		metricsReader = metricsClickHouse.NewMetricsReader(clickHouseClient, metricsRepository)
+	}
+
+	var faultInjector *chaosUsecase.Injector
+	if cfg.ChaosEnabled {
+		faultInjector = chaosUsecase.NewInjector(chaosDomain.TargetEventsRepository, chaosDomain.TargetMetricsReader)
+		eventStore = eventsChaos.NewEventRepository(eventStore, faultInjector, chaosDomain.TargetEventsRepository)
+		metricsReader = metricsChaos.NewMetricsReader(metricsReader, faultInjector, chaosDomain.TargetMetricsReader)
+		log.Printf("fault injection enabled (APP_ENV=%s)", cfg.AppEnv)
+	}
+
+	// Response cache, outside fault injection so cached results stay served
+	if cfg.MetricsCacheRedisAddr != "" {
+		metricsCacheStore := metricsCache.NewRedisStore(
+			cfg.MetricsCacheRedisAddr,
+			metricsCache.WithRedisPassword(cfg.MetricsCacheRedisPassword),
+		)
+		defer metricsCacheStore.Close()
+		metricsReader = metricsCache.NewMetricsReader(
+			metricsReader,
+			metricsCacheStore,
+			cfg.MetricsCacheTTL,
+			cfg.MetricsCacheStale,
+			metricsCache.WithErrorHandler(func(err error) {
+				log.Printf("metrics cache: %v", err)
+			}),
+		)
+	}
+
+	// Live counts of the last minutes, over the cache so they do not lag
+	var liveCounters *metricsLive.Counters
+	if cfg.LiveCountersWindow > 0 {
+		liveCounters = metricsLive.NewCounters(cfg.LiveCountersWindow)
+		metricsReader = metricsLive.NewMetricsReader(metricsReader, liveCounters)
+	}
+
+	// Prometheus registry (operational metrics, not the business /metrics API)
+	promRegistry := telemetry.NewRegistry()
+
+	// Business KPI registry, scraped separately at /metrics/openmetrics
+	kpiRegistry := telemetry.NewRegistry()
+
+	// Business SLIs, measured around the use cases rather than HTTP routes
+	objectives := sloObjectives(cfg)
+	sloTracker := sloUsecase.NewTracker(
+		objectives,
+		sloPrometheus.NewSLIPublisher(promRegistry, objectives),
+		sloUsecase.WithTenantResolver(sloTenantResolver(cfg.SLOTenants)),
+	)
 
 	// Usecaseses
-	storeEventUC := eventsUsecase.NewStoreEventUseCase(eventRepository)
-	getMetricsUC := metricsUsecase.NewGetMetricsUseCase(metricsRepository)
+	schemaRegistryUC := schemaUsecase.NewSchemaRegistryUseCase(schemaRepository)
+	if err := schemaRegistryUC.Refresh(context.Background()); err != nil {
+		log.Printf("failed to load event schemas: %v", err)
+	}
+
+	tailEventsUC := eventsUsecase.NewTailEventsUseCase(cfg.MaxTails)
+	storeOpts := []eventsUsecase.Option{
+		eventsUsecase.WithTagLimits(cfg.MaxTagsPerEvent, cfg.MaxTagLength),
+		eventsUsecase.WithDedupeWindow(cfg.DedupeWindow),
+		eventsUsecase.WithMetadataLimits(cfg.MaxMetadataKeys, cfg.MaxMetadataDepth, cfg.MaxMetadataBytes),
+		eventsUsecase.WithMaxEventAge(cfg.MaxEventAge),
+		eventsUsecase.WithSampling(cfg.SamplingRates),
+		eventsUsecase.WithMetadataSchemas(metadataSchemas{registry: schemaRegistryUC}),
+		eventsUsecase.WithEnrichers(buildEnrichers(cfg)...),
+		eventsUsecase.WithObserver(eventsPrometheus.NewEventCounter(
+			kpiRegistry,
+			cfg.OpenMetricsEventNames,
+			cfg.OpenMetricsChannels,
+			eventsPrometheus.WithMaxEventNames(cfg.OpenMetricsMaxEventNames),
+			eventsPrometheus.WithMaxChannels(cfg.OpenMetricsMaxChannels),
+		)),
+		eventsUsecase.WithInsertRetries(cfg.InsertRetries, cfg.InsertRetryBackoff),
+		eventsUsecase.WithObserver(tailEventsUC),
+	}
+	if liveCounters != nil {
+		storeOpts = append(storeOpts, eventsUsecase.WithObserver(liveCounter{counters: liveCounters}))
+	}
+	// Dead letters live on local disk, not in the database whose failures
+	// put them there.
+	var deadLetterStore *eventsDeadLetter.FileStore
+	if cfg.DeadLetterFile != "" {
+		deadLetterStore, err = eventsDeadLetter.OpenFileStore(cfg.DeadLetterFile)
+		if err != nil {
+			log.Fatalf("failed to open DEAD_LETTER_FILE: %v", err)
+		}
+		storeOpts = append(storeOpts, eventsUsecase.WithDeadLetters(deadLetterStore))
+	}
+	// With tenant isolation, requests carry the tenant of their API key
+	// (see authHttp.RequireTenant); background work runs without one.
+	var tenantOf func(ctx context.Context) string
+	if cfg.TenantIsolation {
+		tenantOf = func(ctx context.Context) string {
+			return authDomain.PrincipalFromContext(ctx).TenantID
+		}
+		storeOpts = append(storeOpts, eventsUsecase.WithTenantResolver(tenantOf))
+	}
+	storeEventUC := eventsUsecase.NewStoreEventUseCase(eventStore, storeOpts...)
+	listEventsUC := eventsUsecase.NewListEventsUseCase(eventRepository)
+	getEventUC := eventsUsecase.NewGetEventUseCase(eventRepository)
+	exportEventsUC := eventsUsecase.NewExportEventsUseCase(eventRepository, cfg.ExportBatchSize)
+	amendEventUC := eventsUsecase.NewAmendEventUseCase(eventRepository, storeEventUC)
+	deleteEventsUC := eventsUsecase.NewDeleteEventsUseCase(eventRepository, eventsUsecase.DefaultDeleteEventsBatchSize)
+	purgeDedupeKeysUC := eventsUsecase.NewPurgeDedupeKeysUseCase(eventRepository, eventsUsecase.DefaultDedupePurgeBatchSize)
+
+	// Archive: purged events go to S3 first when a bucket is configured
+	var (
+		retentionOpts []eventsUsecase.RetentionOption
+		rehydrateUC   *archiveUsecase.RehydrateUseCase
+		replayUC      *archiveUsecase.ReplayUseCase
+	)
+	if cfg.ArchiveS3Bucket != "" {
+		objectStore := newS3Client(cfg, cfg.ArchiveS3Bucket, cfg.ArchiveS3Region, cfg.ArchiveS3Endpoint)
+		archiveRepository := archiveRepoPg.NewArchiveRepository(
+			archiveRepoPg.NewSQLDB(db),
+			archiveRepoPg.WithColumnGate(schemaCompatUC),
+		)
+
+		archiveEventsUC := archiveUsecase.NewArchiveEventsUseCase(archiveRepository, objectStore, archiveParquet.Codec{}, cfg.ArchiveS3Prefix)
+		retentionOpts = append(retentionOpts, eventsUsecase.WithArchiver(archiveEventsUC))
+		rehydrateUC = archiveUsecase.NewRehydrateUseCase(objectStore, archiveParquet.Codec{}, archiveRepository, cfg.ArchiveS3Prefix)
+		replayUC = archiveUsecase.NewReplayUseCase(objectStore, map[string]archivePorts.CodecPort{
+			archiveDomain.FileExtension: archiveParquet.Codec{},
+			archiveNDJSON.Extension:     archiveNDJSON.Codec{},
+		}, archiveReplayer{store: storeEventUC}, cfg.ArchiveS3Prefix)
+	}
+
+	// Exports: destination=s3 writes them to the export bucket
+	var (
+		eventQueryOpts  []eventsHttp.QueryHandlerOption
+		metricsHttpOpts []metricsHttp.MetricsHandlerOption
+	)
+	if cfg.ExportS3Bucket != "" {
+		exportStore := newS3Client(cfg, cfg.ExportS3Bucket, cfg.ExportS3Region, cfg.ExportS3Endpoint)
+		eventQueryOpts = append(eventQueryOpts, eventsHttp.WithExportStore(func(ctx context.Context, name string) eventsHttp.ObjectUpload {
+			return exportStore.NewUpload(ctx, cfg.ExportS3Prefix+name)
+		}))
+		metricsHttpOpts = append(metricsHttpOpts, metricsHttp.WithExportStore(func(ctx context.Context, name string) metricsHttp.ObjectUpload {
+			return exportStore.NewUpload(ctx, cfg.ExportS3Prefix+name)
+		}))
+	}
+
+	// Webhooks: fed by the outbox relay, delivered by a worker per instance
+	var webhookDispatchUC *webhookUsecase.DispatchUseCase
+	if cfg.WebhooksEnabled {
+		webhookDispatchUC = webhookUsecase.NewDispatchUseCase(
+			webhookRepository,
+			webhookRepository,
+			webhookSender.NewHTTPSender(webhookSender.WithTimeout(cfg.WebhookTimeout)),
+			webhookUsecase.WithRetries(cfg.WebhookMaxAttempts, cfg.WebhookRetryBackoff),
+			webhookUsecase.WithConcurrency(cfg.WebhookConcurrency),
+		)
+	}
+
+	purgeExpiredEventsUC := eventsUsecase.NewPurgeExpiredEventsUseCase(
+		eventRepository,
+		time.Duration(cfg.EventsRetentionDays)*24*time.Hour,
+		cfg.RetentionPurgeBatchSize,
+		retentionOpts...,
+	)
+	metricsOpts := []metricsUsecase.Option{
+		metricsUsecase.WithWatermark(metricsRepository),
+		metricsUsecase.WithStreamer(metricsRepository),
+		metricsUsecase.WithMaxRange(cfg.MetricsMaxQueryRange),
+		metricsUsecase.WithMaxTimeBuckets(cfg.MetricsMaxTimeBuckets),
+		metricsUsecase.WithQueryTimeout(cfg.MetricsQueryTimeout),
+	}
+	dimensionOpts := []metricsUsecase.DimensionOption{
+		metricsUsecase.WithTopN(cfg.DimensionTopN),
+		metricsUsecase.WithLookback(cfg.DimensionLookback),
+	}
+	if cfg.TenantIsolation {
+		metricsOpts = append(metricsOpts, metricsUsecase.WithTenantResolver(tenantOf))
+		dimensionOpts = append(dimensionOpts, metricsUsecase.WithValuesPerTenant(tenantOf))
+	}
+	getMetricsUC := metricsUsecase.NewGetMetricsUseCase(metricsReader, metricsOpts...)
+	var cohortOpts []metricsUsecase.RetentionOption
+	if cfg.TenantIsolation {
+		cohortOpts = append(cohortOpts, metricsUsecase.WithRetentionTenantResolver(tenantOf))
+	}
+	getRetentionUC := metricsUsecase.NewGetRetentionUseCase(metricsRepository, cohortOpts...)
+	var eventNamesOpts []metricsUsecase.EventNamesOption
+	if cfg.TenantIsolation {
+		eventNamesOpts = append(eventNamesOpts, metricsUsecase.WithEventNamesTenantResolver(tenantOf))
+	}
+	listEventNamesUC := metricsUsecase.NewListEventNamesUseCase(metricsRepository, eventNamesOpts...)
+	var channelsOpts []metricsUsecase.ChannelsOption
+	if cfg.TenantIsolation {
+		channelsOpts = append(channelsOpts, metricsUsecase.WithChannelsTenantResolver(tenantOf))
+	}
+	listChannelsUC := metricsUsecase.NewListChannelsUseCase(metricsRepository, channelsOpts...)
+	sessionOpts := []sessionUsecase.Option{sessionUsecase.WithDefaultGap(cfg.SessionGap)}
+	if cfg.TenantIsolation {
+		sessionOpts = append(sessionOpts, sessionUsecase.WithTenantResolver(tenantOf))
+	}
+	sessionStatsUC := sessionUsecase.NewSessionStatsUseCase(sessionRepository, sessionOpts...)
+	dimensionValuesUC := metricsUsecase.NewDimensionValuesUseCase(
+		metricsRepository,
+		cfg.DimensionMetadataKeys,
+		dimensionOpts...,
+	)
+	quotaUC := quotaUsecase.NewQuotaUseCase(
+		quotaUsageRepository,
+		cfg.QuotaDailyLimit,
+		quotaUsecase.WithOverrides(cfg.QuotaOverrides),
+		quotaUsecase.WithWarnPercent(cfg.QuotaWarnPercent),
+	)
+	onboardTenantUC := tenantUsecase.NewOnboardTenantUseCase(
+		tenantRepository,
+		tenantUsecase.WithDefaultRetentionDays(cfg.TenantDefaultRetentionDays),
+	)
+	var identifyOpts []identityUsecase.Option
+	if cfg.TenantIsolation {
+		identifyOpts = append(identifyOpts, identityUsecase.WithTenantResolver(tenantOf))
+	}
+	identifyUC := identityUsecase.NewIdentifyUseCase(identityRepository, cfg.IdentityStitchWindow, identifyOpts...)
+	eraseUserUC := privacyUsecase.NewEraseUserUseCase(userEraser)
+	journalUC := journalUsecase.NewJournalUseCase(journalRepository, cfg.JournalTTL, cfg.JournalMaxBodyBytes)
+	auditUC := auditUsecase.NewAuditUseCase(auditRepository, cfg.QueryAuditRetention)
+	collectStorageStatsUC := storageUsecase.NewCollectStorageStatsUseCase(
+		storageStatsRepository,
+		storagePrometheus.NewStorageStatsPublisher(promRegistry),
+		cfg.StorageStatsTables,
+	)
+	var heartbeatOpts []heartbeatUsecase.Option
+	if cfg.TenantIsolation {
+		heartbeatOpts = append(heartbeatOpts, heartbeatUsecase.WithTenantResolver(tenantOf))
+	}
+	heartbeatUC := heartbeatUsecase.NewHeartbeatUseCase(heartbeatRepository, cfg.HeartbeatGrace, heartbeatOpts...)
+	monitorHeartbeatsUC := heartbeatUsecase.NewMonitorHeartbeatsUseCase(
+		heartbeatUC,
+		heartbeatPrometheus.NewHeartbeatStatusPublisher(promRegistry),
+	)
+
+	// Background workers
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	go purgeDedupeKeysUC.Run(workerCtx, cfg.DedupePurgeInterval, func(err error) {
+		log.Printf("dedupe key purge failed: %v", err)
+	})
+
+	if cfg.EventsRetentionDays > 0 {
+		go purgeExpiredEventsUC.Run(workerCtx, cfg.RetentionPurgeInterval,
+			func(p eventsUsecase.RetentionProgress) {
+				if p.Deleted == 0 {
+					return
+				}
+				if p.Done {
+					log.Printf("retention purge done: %d events before %s deleted in %d batches", p.Deleted, p.Cutoff.Format(time.RFC3339), p.Batches)
+				} else {
+					log.Printf("retention purge: %d events before %s deleted so far (batch %d)", p.Deleted, p.Cutoff.Format(time.RFC3339), p.Batches)
+				}
+			},
+			func(err error) {
+				log.Printf("retention purge failed: %v", err)
+			},
+		)
+	}
+
+	go promoteMetadataUC.Run(workerCtx, time.Minute, func(err error) {
+		log.Printf("promoted metadata backfill failed: %v", err)
+	})
+
+	go schemaCompatUC.Run(workerCtx, cfg.SchemaCompatRefreshInterval,
+		func(s migrationDomain.ColumnState) {
+			log.Printf("schema column %s changed: mode=%s exists=%t", s.Column, s.Mode, s.Exists)
+		},
+		func(err error) {
+			log.Printf("schema column probe failed: %v", err)
+		},
+	)
+
+	go schemaRegistryUC.Run(workerCtx, cfg.SchemaRefreshInterval, func(err error) {
+		log.Printf("event schema refresh failed: %v", err)
+	})
+
+	go dimensionValuesUC.Run(workerCtx, cfg.DimensionRefreshInterval, func(err error) {
+		log.Printf("dimension values refresh failed: %v", err)
+	})
+
+	// Every instance runs the rollup worker, the rollup lock lets a single
+	// one roll up at a time
+	if rollupUC != nil {
+		go rollupUC.Run(workerCtx, cfg.RollupInterval, func(err error) {
+			log.Printf("rollup failed: %v", err)
+		})
+	}
+
+	// Outbox relay: every instance runs one, the outbox lock lets a single
+	// one publish at a time
+	var relayTo publishers
+	if webhookDispatchUC != nil {
+		relayTo = append(relayTo, webhookPublisher{dispatch: webhookDispatchUC})
+		go webhookDispatchUC.Run(workerCtx, cfg.WebhookDeliveryInterval, func(err error) {
+			log.Printf("webhook delivery failed: %v", err)
+		})
+	}
+
+	// Alerts: every instance evaluates every rule; a change of status is
+	// only saved, and notified, by the first
+	alertRepository := alertRepoPg.NewAlertRepository(alertDB)
+	alertMetrics := alertMeasurer{uc: getMetricsUC}
+	if cfg.AlertsEnabled {
+		evaluateUC := alertUsecase.NewEvaluateUseCase(
+			alertRepository,
+			alertMetrics,
+			webhookSender.NewHTTPSender(webhookSender.WithTimeout(cfg.AlertNotifyTimeout)),
+		)
+		go evaluateUC.Run(workerCtx, cfg.AlertEvaluationInterval, func(err error) {
+			log.Printf("alert evaluation failed: %v", err)
+		})
+	}
+	if len(cfg.KafkaBrokers) > 0 {
+		producer := eventsKafka.NewProducer(cfg.KafkaBrokers, cfg.KafkaTopic)
+		defer producer.Close()
+		relayTo = append(relayTo, producer)
+	}
+	if len(relayTo) > 0 {
+		relayOutboxUC := eventsUsecase.NewRelayOutboxUseCase(eventRepository, relayTo, cfg.OutboxBatchSize)
+		go relayOutboxUC.Run(workerCtx, cfg.OutboxRelayInterval, func(err error) {
+			log.Printf("outbox relay failed: %v", err)
+		})
+	}
+
+	if cfg.JournalEnabled {
+		go journalUC.Run(workerCtx, cfg.JournalPurgeInterval, func(err error) {
+			log.Printf("rejected request journal purge failed: %v", err)
+		})
+	}
+
+	if cfg.QueryAuditEnabled {
+		go auditUC.Run(workerCtx, cfg.QueryAuditPurgeInterval, func(err error) {
+			log.Printf("query audit purge failed: %v", err)
+		})
+	}
+
+	go collectStorageStatsUC.Run(workerCtx, cfg.StorageStatsInterval, func(err error) {
+		log.Printf("storage stats collection failed: %v", err)
+	})
+
+	go monitorHeartbeatsUC.Run(workerCtx, cfg.HeartbeatCheckInterval,
+		func(s heartbeatDomain.HeartbeatStatus) {
+			log.Printf("heartbeat missing: tenant=%s producer=%s event_name=%s overdue=%s", s.TenantID, s.Producer, s.EventName, s.Overdue)
+		},
+		func(err error) {
+			log.Printf("heartbeat check failed: %v", err)
+		},
+	)
+
+	// Warm-up: primes caches before /readyz reports ready
+	warmUpUC := warmupUsecase.NewWarmUpUseCase(
+		warmUpTasks(cfg, eventRepository, getMetricsUC, dimensionValuesUC),
+		cfg.WarmUpTimeout,
+	)
+
+	// Throttling
+	metricsConcurrencyLimiter := throttleUsecase.NewConcurrencyLimiter(
+		cfg.MetricsMaxConcurrentPerKey,
+		cfg.MetricsConcurrencyOverrides,
+	)
+	ingestRateLimiter := throttleUsecase.NewRateLimiter(
+		cfg.IngestRateLimit,
+		cfg.IngestRateBurst,
+		cfg.IngestRateOverrides,
+	)
 
 	// HTTP (Fiber) app + handlers
-	app := fiber.New()
+	app := fiber.New(fiber.Config{
+		ProxyHeader:    cfg.ProxyHeader,
+		BodyLimit:      cfg.MaxBodyBytes,
+		ReadTimeout:    cfg.HTTPReadTimeout,
+		WriteTimeout:   cfg.HTTPWriteTimeout,
+		IdleTimeout:    cfg.HTTPIdleTimeout,
+		ReadBufferSize: cfg.HTTPReadBufferSize,
+		ErrorHandler:   errorHandler(cfg.MaxBodyBytes),
+	})
+	app.Use(requestid.New())
+	jsonLimits := jsonlimit.Limits{MaxDepth: cfg.JSONMaxDepth, MaxValues: cfg.JSONMaxValues}
+	app.Use(jsonlimit.Middleware(jsonLimits))
+	keyScopes := authHttp.WithKeyScopes(apiKeyScopes(cfg.APIKeyScopes, cfg.DefaultAPIKeyScopes))
+	app.Use(authHttp.IdentifyAPIKey(keyScopes))
+
+	apiKeyOf := func(c *fiber.Ctx) string { return authHttp.Principal(c).APIKey }
+	// Token-authenticated callers without an API key are throttled per subject.
+	callerOf := func(c *fiber.Ctx) string {
+		p := authHttp.Principal(c)
+		if p.APIKey == authDomain.AnonymousKey && p.Subject != "" {
+			return "sub:" + p.Subject
+		}
+		return p.APIKey
+	}
 
 	// events endpoints
-	eventsHandler := eventsHttp.NewEventHandler(storeEventUC)
-	app.Post("/events", eventsHandler.CreateEvent)
-	app.Post("/events/bulk", eventsHandler.BulkCreateEvents)
+	eventsHandler := eventsHttp.NewEventHandler(
+		eventsSLO.NewStoreEvent(storeEventUC, sloTracker),
+		eventsHttp.WithPreserveNumbers(cfg.PreserveMetadataNumbers),
+		eventsHttp.WithGA4Channels(cfg.GA4Channels),
+		eventsHttp.WithSnowplowChannels(cfg.SnowplowChannels),
+		eventsHttp.WithMaxBulkEvents(cfg.BulkMaxEvents),
+		eventsHttp.WithWebSocketGate(webSocketGate(cfg, ingestRateLimiter, quotaUC)),
+		eventsHttp.WithWebSocketReadLimit(cfg.MaxBodyBytes),
+		eventsHttp.WithWebSocketJSONLimits(jsonLimits),
+	)
+	var tenantMiddleware []fiber.Handler
+	var resolveTenant func(ctx context.Context, apiKey string) (string, error)
+	if cfg.TenantIsolation {
+		resolveTenant = tenantUsecase.NewResolveTenantUseCase(tenantRepository, cfg.TenantKeyCacheTTL).Execute
+		tenantMiddleware = append(tenantMiddleware, authHttp.RequireTenant(resolveTenant))
+	}
+
+	var ingestMiddleware []fiber.Handler
+	ingestMiddleware = append(ingestMiddleware, tenantMiddleware...)
+	ingestMiddleware = append(ingestMiddleware, authHttp.RequireScope(authDomain.ScopeEventsWrite))
+	if cfg.IngestRateLimit > 0 || len(cfg.IngestRateOverrides) > 0 {
+		ingestMiddleware = append(ingestMiddleware, throttleHttp.RateLimit(ingestRateLimiter, apiKeyOf))
+	}
+	if cfg.QuotaDailyLimit > 0 || len(cfg.QuotaOverrides) > 0 {
+		ingestMiddleware = append(ingestMiddleware, quotaHttp.SoftQuota(quotaUC, apiKeyOf))
+	}
+	if cfg.JournalEnabled {
+		ingestMiddleware = append(ingestMiddleware, journalHttp.RecordRejected(journalUC, apiKeyOf))
+	}
+	// Clipped so each route's append copies instead of sharing spare capacity.
+	ingestMiddleware = slices.Clip(ingestMiddleware)
+	app.Post("/events", append(ingestMiddleware, eventsHandler.CreateEvent)...)
+	app.Post("/events/bulk", append(ingestMiddleware, eventsHandler.BulkCreateEvents)...)
+
+	// Collectors for third-party trackers and image beacons, which may only be
+	// able to pass the API key in the URL.
+	collectorMiddleware := func(keyParam string) []fiber.Handler {
+		return append([]fiber.Handler{authHttp.IdentifyAPIKey(keyScopes, authHttp.WithKeyQueryParam(keyParam))}, ingestMiddleware...)
+	}
+	app.Post("/mp/collect", append(collectorMiddleware("api_secret"), eventsHandler.CollectGA4)...)
+	app.Get("/i", append(collectorMiddleware("api_key"), eventsHandler.CollectSnowplowPixel)...)
+	app.Post("/com.snowplowanalytics.snowplow/tp2", append(collectorMiddleware("api_key"), eventsHandler.CollectSnowplow)...)
+	app.Get("/pixel.gif", append(collectorMiddleware("api_key"), eventsHandler.CollectPixel)...)
+	app.Get("/events/ws", append(collectorMiddleware("api_key"), eventsHandler.IngestWebSocket)...)
+
+	// Raw events carry user data, so reading them needs the admin token.
+	eventQueryHandler := eventsHttp.NewEventQueryHandler(listEventsUC, getEventUC, exportEventsUC, eventQueryOpts...)
+	app.Get("/events", authHttp.RequireAdminToken(cfg.AdminToken), eventQueryHandler.ListEvents)
+	app.Get("/events/export", authHttp.RequireAdminToken(cfg.AdminToken), eventQueryHandler.ExportEvents)
+	app.Get("/events/tail", authHttp.RequireAdminToken(cfg.AdminToken), eventsHttp.NewTailHandler(tailEventsUC).TailEvents)
+	app.Get("/events/:id", authHttp.RequireAdminToken(cfg.AdminToken), eventQueryHandler.GetEvent)
+
+	amendmentHandler := eventsHttp.NewAmendmentHandler(amendEventUC)
+	app.Patch("/events/:id", authHttp.RequireAdminToken(cfg.AdminToken), amendmentHandler.AmendEvent)
+	app.Get("/events/:id/amendments", authHttp.RequireAdminToken(cfg.AdminToken), amendmentHandler.ListAmendments)
+
+	// right-to-erasure endpoint
+	privacyHandler := privacyHttp.NewPrivacyHandler(eraseUserUC)
+	app.Delete("/users/:user_id/events", authHttp.RequireAdminToken(cfg.AdminToken), privacyHandler.EraseUser)
+
+	// readiness probe
+	warmUpHandler := warmupHttp.NewWarmUpHandler(warmUpUC)
+	app.Get("/readyz", warmUpHandler.Ready)
+
+	// identity endpoints
+	identityHandler := identityHttp.NewIdentityHandler(identifyUC)
+	app.Post("/identify", append(slices.Clip(tenantMiddleware),
+		authHttp.RequireScope(authDomain.ScopeEventsWrite),
+		identityHandler.Identify,
+	)...)
+
+	// quota endpoints
+	quotaHandler := quotaHttp.NewQuotaHandler(quotaUC, apiKeyOf)
+	app.Get("/quota", authHttp.RequireScope(authDomain.ScopeMetricsRead), quotaHandler.GetQuota)
 
 	// metrics endpoints
-	metricsHandler := metricsHttp.NewMetricsHandler(getMetricsUC)
-	app.Get("/metrics", metricsHandler.GetMetrics)
+	var readMiddleware []fiber.Handler
+	var verifier authPorts.TokenVerifierPort
+	if cfg.OIDCIssuer != "" {
+		verifier = authOIDC.NewVerifier(cfg.OIDCIssuer, cfg.OIDCAudience)
+		readMiddleware = append(readMiddleware, authHttp.RequireJWT(verifier, cfg.OIDCScopes))
+	}
+	readMiddleware = append(readMiddleware, tenantMiddleware...)
+	readMiddleware = append(readMiddleware, authHttp.RequireScope(authDomain.ScopeMetricsRead))
+	readMiddleware = slices.Clip(readMiddleware)
+
+	// Queries callers run go to the query audit log; those of alerts and
+	// warm-up do not. Recording is left out of the SLIs.
+	var servedMetrics, measuredMetrics metricsAudit.GetMetricsUseCase = getMetricsUC, metricsSLO.NewGetMetrics(getMetricsUC, sloTracker)
+	if cfg.QueryAuditEnabled {
+		auditor := queryAuditor{uc: auditUC}
+		servedMetrics = metricsAudit.NewGetMetrics(servedMetrics, auditor)
+		measuredMetrics = metricsAudit.NewGetMetrics(measuredMetrics, auditor)
+	}
+
+	metricsHandler := metricsHttp.NewMetricsHandler(measuredMetrics, metricsHttpOpts...)
+	app.Get("/metrics", append(readMiddleware,
+		throttleHttp.ConcurrencyLimit(metricsConcurrencyLimiter, callerOf),
+		metricsHandler.GetMetrics,
+	)...)
+	app.Post("/metrics/batch", append(readMiddleware,
+		throttleHttp.ConcurrencyLimit(metricsConcurrencyLimiter, callerOf),
+		metricsHandler.GetMetricsBatch,
+	)...)
+	app.Get("/metrics/top", append(readMiddleware,
+		throttleHttp.ConcurrencyLimit(metricsConcurrencyLimiter, callerOf),
+		metricsHandler.GetTopValues,
+	)...)
+	app.Get("/metrics/export", append(readMiddleware,
+		throttleHttp.ConcurrencyLimit(metricsConcurrencyLimiter, callerOf),
+		metricsHandler.ExportMetrics,
+	)...)
+	queryHandler := metricsHttp.NewQueryHandler(metricsUsecase.NewQueryMetricsUseCase(servedMetrics))
+	app.Post("/metrics/query", append(readMiddleware,
+		throttleHttp.ConcurrencyLimit(metricsConcurrencyLimiter, callerOf),
+		queryHandler.QueryMetrics,
+	)...)
+	retentionHandler := metricsHttp.NewRetentionHandler(getRetentionUC)
+	app.Get("/metrics/retention", append(readMiddleware,
+		throttleHttp.ConcurrencyLimit(metricsConcurrencyLimiter, callerOf),
+		retentionHandler.GetRetention,
+	)...)
+	eventNamesHandler := metricsHttp.NewEventNamesHandler(listEventNamesUC)
+	app.Get("/metrics/event-names", append(readMiddleware,
+		throttleHttp.ConcurrencyLimit(metricsConcurrencyLimiter, callerOf),
+		eventNamesHandler.ListEventNames,
+	)...)
+	channelsHandler := metricsHttp.NewChannelsHandler(listChannelsUC)
+	app.Get("/metrics/channels", append(readMiddleware,
+		throttleHttp.ConcurrencyLimit(metricsConcurrencyLimiter, callerOf),
+		channelsHandler.ListChannels,
+	)...)
+	anomalyHandler := metricsHttp.NewAnomalyHandler(metricsUsecase.NewDetectAnomaliesUseCase(servedMetrics))
+	app.Get("/metrics/anomalies", append(readMiddleware,
+		throttleHttp.ConcurrencyLimit(metricsConcurrencyLimiter, callerOf),
+		anomalyHandler.GetAnomalies,
+	)...)
+	// Only the first query of a stream holds a concurrency slot; the stream
+	// limit bounds the rest.
+	streamHandler := metricsHttp.NewStreamHandler(servedMetrics, metricsHttp.WithMaxStreams(cfg.MaxMetricsStreams))
+	app.Get("/metrics/stream", append(readMiddleware,
+		throttleHttp.ConcurrencyLimit(metricsConcurrencyLimiter, callerOf),
+		streamHandler.StreamMetrics,
+	)...)
+	sessionHandler := sessionHttp.NewSessionHandler(sessionStatsUC)
+	app.Get("/metrics/sessions", append(readMiddleware,
+		throttleHttp.ConcurrencyLimit(metricsConcurrencyLimiter, callerOf),
+		sessionHandler.GetSessionStats,
+	)...)
+
+	dimensionsHandler := metricsHttp.NewDimensionsHandler(dimensionValuesUC)
+	app.Get("/metrics/dimensions/:name/values", append(readMiddleware, dimensionsHandler.GetDimensionValues)...)
+
+	// Saved metrics queries; runs go through the GET /metrics handler.
+	var reportOpts []reportUsecase.ReportOption
+	if tenantOf != nil {
+		reportOpts = append(reportOpts, reportUsecase.WithTenantResolver(tenantOf))
+	}
+	reportHandler := reportHttp.NewReportHandler(
+		reportUsecase.NewReportUseCase(reportRepoPg.NewReportRepository(reportDB), reportOpts...),
+		metricsHandler.GetMetrics,
+	)
+	reportsWrite := authHttp.RequireScope(authDomain.ScopeReportsWrite)
+	app.Get("/reports", append(readMiddleware, reportHandler.ListReports)...)
+	app.Post("/reports", append(readMiddleware, reportsWrite, reportHandler.CreateReport)...)
+	app.Get("/reports/:name", append(readMiddleware, reportHandler.GetReport)...)
+	app.Put("/reports/:name", append(readMiddleware, reportsWrite, reportHandler.UpdateReport)...)
+	app.Delete("/reports/:name", append(readMiddleware, reportsWrite, reportHandler.DeleteReport)...)
+	app.Get("/reports/:name/run", append(readMiddleware,
+		throttleHttp.ConcurrencyLimit(metricsConcurrencyLimiter, callerOf),
+		reportHandler.RunReport,
+	)...)
+
+	// heartbeat endpoints
+	heartbeatHandler := heartbeatHttp.NewHeartbeatHandler(heartbeatUC)
+	heartbeatMiddleware := slices.Clip(tenantMiddleware)
+	app.Get("/heartbeats", append(heartbeatMiddleware,
+		authHttp.RequireScope(authDomain.ScopeMetricsRead),
+		heartbeatHandler.ListHeartbeats,
+	)...)
+	app.Put("/heartbeats", append(heartbeatMiddleware,
+		authHttp.RequireScope(authDomain.ScopeEventsWrite),
+		heartbeatHandler.RegisterHeartbeat,
+	)...)
+	app.Delete("/heartbeats/:producer/:event_name", append(heartbeatMiddleware,
+		authHttp.RequireScope(authDomain.ScopeEventsWrite),
+		heartbeatHandler.UnregisterHeartbeat,
+	)...)
+
+	// Prometheus scrape endpoints
+	app.Get("/prometheus", authHttp.RequireBearerToken(cfg.PrometheusToken), promRegistry.Handler)
+	app.Get("/metrics/openmetrics",
+		authHttp.RequireBearerToken(cfg.OpenMetricsToken),
+		kpiRegistry.OpenMetricsHandler,
+	)
+
+	// admin endpoints
+	admin := app.Group("/admin", authHttp.RequireAdminToken(cfg.AdminToken))
+
+	tenantHandler := tenantHttp.NewTenantHandler(onboardTenantUC)
+	admin.Post("/tenants", tenantHandler.OnboardTenant)
+
+	schemaHandler := schemaHttp.NewSchemaHandler(schemaRegistryUC)
+	admin.Get("/schemas", schemaHandler.ListSchemas)
+	admin.Put("/schemas/:event_name", schemaHandler.PutSchema)
+	admin.Delete("/schemas/:event_name", schemaHandler.DeleteSchema)
+
+	schemaCompatHandler := migrationHttp.NewCompatHandler(schemaCompatUC)
+	admin.Get("/schema-compat", schemaCompatHandler.GetSchemaCompat)
+
+	sloHandler := sloHttp.NewSLOHandler(sloTracker)
+	admin.Get("/slo", sloHandler.GetSLO)
+
+	admin.Get("/warmup", warmUpHandler.GetWarmUp)
+	admin.Post("/warmup", warmUpHandler.TriggerWarmUp)
+
+	deleteHandler := eventsHttp.NewDeleteHandler(deleteEventsUC)
+	admin.Post("/events/delete", deleteHandler.DeleteEvents)
+
+	if rehydrateUC != nil {
+		archiveHandler := archiveHttp.NewArchiveHandler(rehydrateUC, replayUC)
+		admin.Post("/archive/rehydrate", archiveHandler.Rehydrate)
+		admin.Post("/archive/replay", archiveHandler.Replay)
+	}
+
+	if deadLetterStore != nil {
+		deadLetterHandler := eventsHttp.NewDeadLetterHandler(eventsUsecase.NewDeadLetterUseCase(deadLetterStore, storeEventUC))
+		admin.Get("/dead-letters", deadLetterHandler.ListDeadLetters)
+		admin.Post("/dead-letters/redrive", deadLetterHandler.RedriveDeadLetters)
+		admin.Post("/dead-letters/:id/redrive", deadLetterHandler.RedriveDeadLetter)
+		admin.Delete("/dead-letters/:id", deadLetterHandler.DiscardDeadLetter)
+	}
+
+	if cfg.JournalEnabled {
+		journalHandler := journalHttp.NewJournalHandler(journalUC)
+		admin.Get("/rejected-requests/:request_id", journalHandler.GetRejectedRequest)
+	}
+
+	if cfg.QueryAuditEnabled {
+		auditHandler := auditHttp.NewAuditHandler(auditUC)
+		admin.Get("/metrics-queries", auditHandler.ListQueries)
+	}
+
+	if cfg.WebhooksEnabled {
+		webhookHandler := webhookHttp.NewWebhookHandler(webhookUsecase.NewSubscriptionUseCase(webhookRepository))
+		admin.Get("/webhooks", webhookHandler.ListSubscriptions)
+		admin.Post("/webhooks", webhookHandler.CreateSubscription)
+		admin.Get("/webhooks/:id", webhookHandler.GetSubscription)
+		admin.Put("/webhooks/:id", webhookHandler.UpdateSubscription)
+		admin.Delete("/webhooks/:id", webhookHandler.DeleteSubscription)
+	}
+	if cfg.AlertsEnabled {
+		alertHandler := alertHttp.NewAlertHandler(alertUsecase.NewRuleUseCase(alertRepository, alertMetrics))
+		admin.Get("/alerts", alertHandler.ListRules)
+		admin.Post("/alerts", alertHandler.CreateRule)
+		admin.Get("/alerts/:id", alertHandler.GetRule)
+		admin.Put("/alerts/:id", alertHandler.UpdateRule)
+		admin.Delete("/alerts/:id", alertHandler.DeleteRule)
+	}
+
+	if faultInjector != nil {
+		faultHandler := chaosHttp.NewFaultHandler(faultInjector)
+		admin.Get("/faults", faultHandler.ListFaults)
+		admin.Put("/faults/:target", faultHandler.SetFault)
+		admin.Delete("/faults/:target", faultHandler.ClearFault)
+		admin.Delete("/faults", faultHandler.ClearAllFaults)
+	}
 
 	// Swagger
 	app.Get("/docs/*", fiberSwagger.WrapHandler)
 
 	// Graceful shutdown
 	go func() {
-		if err := app.Listen(":8080"); err != nil {
+		if err := app.Listen(cfg.HTTPAddr); err != nil {
 			log.Printf("fiber stopped: %v", err)
 		}
 	}()
 
-	log.Println("server started on :8080")
+	log.Printf("server started on %s", cfg.HTTPAddr)
+
+	var grpcServer *grpc.Server
+	if cfg.GRPCAddr != "" {
+		access := grpcReadAccess{
+			scopesOf:      apiKeyScopes(cfg.APIKeyScopes, cfg.DefaultAPIKeyScopes),
+			verifier:      verifier,
+			tokenScopes:   cfg.OIDCScopes,
+			resolveTenant: resolveTenant,
+			limiter:       metricsConcurrencyLimiter,
+		}
+		grpcListener, err := net.Listen("tcp", cfg.GRPCAddr)
+		if err != nil {
+			log.Fatalf("failed to listen on %s: %v", cfg.GRPCAddr, err)
+		}
+		grpcServer = newGRPCServer(servedMetrics, access)
+		go func() {
+			// Serve returns nil once the server is stopped.
+			if err := grpcServer.Serve(grpcListener); err != nil {
+				log.Printf("grpc stopped: %v", err)
+			}
+		}()
+		log.Printf("grpc started on %s", cfg.GRPCAddr)
+	}
+
+	go func() {
+		status, err := warmUpUC.Execute(workerCtx)
+		if err != nil {
+			log.Printf("warm-up failed: %v", err)
+			return
+		}
+		logWarmUp(status)
+	}()
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -89,12 +1009,231 @@ func main() {
 
 	log.Println("shutting down...")
 
+	stopWorkers()
+	// Open tails and streams would otherwise hold the shutdown until its timeout.
+	tailEventsUC.Close()
+	streamHandler.Close()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err := app.ShutdownWithContext(ctx); err != nil {
 		log.Printf("fiber shutdown error: %v", err)
 	}
+	if grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			grpcServer.Stop()
+			log.Printf("grpc shutdown error: %v", ctx.Err())
+		}
+	}
 
 	log.Println("server exiting")
 }
+
+// buildEnrichers instantiates the configured ingest enrichers in order.
+func buildEnrichers(cfg config) []eventsPorts.EnricherPort {
+	var out []eventsPorts.EnricherPort
+	for _, name := range cfg.Enrichers {
+		switch name {
+		case "useragent":
+			out = append(out, eventsEnrichment.NewUserAgentEnricher())
+		case "geoip":
+			f, err := os.Open(cfg.GeoIPCSV)
+			if err != nil {
+				log.Fatalf("failed to open GEOIP_CSV: %v", err)
+			}
+			geo, err := eventsEnrichment.LoadGeoIPCSV(f)
+			f.Close()
+			if err != nil {
+				log.Fatalf("failed to load GEOIP_CSV: %v", err)
+			}
+			out = append(out, geo)
+		default:
+			log.Fatalf("unknown enricher %q in ENRICHERS", name)
+		}
+	}
+	return out
+}
+
+// gatedColumns collects the columns the repositories consult the schema
+// compatibility gate for.
+func gatedColumns() []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, columns := range [][]string{
+		eventsRepoPg.GatedColumns,
+		metricsRepoPg.GatedColumns,
+		privacyRepoPg.GatedColumns,
+		identityRepoPg.GatedColumns,
+		heartbeatRepoPg.GatedColumns,
+		sessionRepoPg.GatedColumns,
+		archiveRepoPg.GatedColumns,
+	} {
+		for _, c := range columns {
+			if !seen[c] {
+				seen[c] = true
+				out = append(out, c)
+			}
+		}
+	}
+	return out
+}
+
+// defaultSLOLatencyTargets are the latency objectives of the measured
+// operations; SLO_LATENCY_TARGETS overrides them per operation.
+var defaultSLOLatencyTargets = map[string]time.Duration{
+	eventsSLO.OperationStoreEvent:         100 * time.Millisecond,
+	eventsSLO.OperationBulkStore:          2 * time.Second,
+	metricsSLO.OperationQueryMetrics:      time.Second,
+	metricsSLO.OperationQueryMetricsBatch: 5 * time.Second,
+}
+
+// warmUpTasks lists the startup warm-up steps: dedupe claims first since
+// ingest traffic arrives first, then the top values cache and saved queries.
+func warmUpTasks(
+	cfg config,
+	dedupe eventsPorts.DedupeWarmerPort,
+	metrics metricsWarmup.GetMetricsUseCase,
+	dimensions *metricsUsecase.DimensionValuesUseCase,
+) []warmupUsecase.Task {
+	tasks := []warmupUsecase.Task{
+		{Name: "dedupe_keys", Warmer: warmupPorts.WarmerFunc(func(ctx context.Context) error {
+			_, err := dedupe.WarmDedupeKeys(ctx)
+			return err
+		})},
+		{Name: "dimension_values", Warmer: warmupPorts.WarmerFunc(dimensions.Refresh)},
+	}
+	for _, raw := range cfg.WarmUpQueries {
+		q, err := metricsWarmup.ParseSavedQuery(raw)
+		if err != nil {
+			log.Fatalf("invalid WARMUP_QUERIES: %v", err)
+		}
+		tasks = append(tasks, warmupUsecase.Task{
+			Name:   "metrics:" + q.Raw,
+			Warmer: metricsWarmup.NewQueryWarmer(metrics, q),
+		})
+	}
+	return tasks
+}
+
+func logWarmUp(status warmupDomain.Status) {
+	for _, r := range status.Results {
+		if r.Err != nil {
+			log.Printf("warm-up task %s failed after %s: %v", r.Name, r.Duration, r.Err)
+			continue
+		}
+		log.Printf("warm-up task %s done in %s", r.Name, r.Duration)
+	}
+	log.Printf("warm-up finished in %s, instance ready", status.FinishedAt.Sub(status.StartedAt))
+}
+
+func sloObjectives(cfg config) []sloDomain.Objective {
+	ops := []string{
+		eventsSLO.OperationStoreEvent,
+		eventsSLO.OperationBulkStore,
+		metricsSLO.OperationQueryMetrics,
+		metricsSLO.OperationQueryMetricsBatch,
+	}
+
+	out := make([]sloDomain.Objective, 0, len(ops))
+	for _, op := range ops {
+		target := defaultSLOLatencyTargets[op]
+		if d, ok := cfg.SLOLatencyTargets[op]; ok {
+			target = d
+		}
+		out = append(out, sloDomain.Objective{
+			Operation:             op,
+			LatencyTarget:         target,
+			LatencyObjective:      cfg.SLOLatencyObjective,
+			AvailabilityObjective: cfg.SLOAvailabilityObjective,
+		})
+	}
+	return out
+}
+
+// webSocketGate applies the ingest rate limit and quota to every message on
+// /events/ws, which the middleware only sees the upgrade request of.
+func webSocketGate(cfg config, limiter *throttleUsecase.RateLimiter, quota *quotaUsecase.QuotaUseCase) eventsHttp.WebSocketGate {
+	limited := cfg.IngestRateLimit > 0 || len(cfg.IngestRateOverrides) > 0
+	counted := cfg.QuotaDailyLimit > 0 || len(cfg.QuotaOverrides) > 0
+	return func(ctx context.Context) (time.Duration, bool) {
+		key := authDomain.PrincipalFromContext(ctx).APIKey
+		if limited {
+			if retryAfter, ok := limiter.Allow(key); !ok {
+				return retryAfter, false
+			}
+		}
+		if counted {
+			// Soft, as over HTTP: a failing usage store only skips counting.
+			quota.Consume(ctx, key, 1)
+		}
+		return 0, true
+	}
+}
+
+// newS3Client returns a client of bucket signed with the AWS credentials;
+// endpoint is validated by loadConfig.
+func newS3Client(cfg config, bucket, region, endpoint string) *archiveS3.Client {
+	var opts []archiveS3.Option
+	if endpoint != "" {
+		u, _ := url.Parse(endpoint)
+		opts = append(opts, archiveS3.WithEndpoint(u))
+	}
+	return archiveS3.NewClient(bucket, region, archiveS3.Credentials{
+		AccessKeyID:     cfg.AWSAccessKeyID,
+		SecretAccessKey: cfg.AWSSecretAccessKey,
+		SessionToken:    cfg.AWSSessionToken,
+	}, opts...)
+}
+
+// errorHandler answers bodies over MAX_BODY_BYTES with the JSON error shape
+// the handlers use; fasthttp rejects them before any handler runs.
+func errorHandler(maxBodyBytes int) fiber.ErrorHandler {
+	return func(c *fiber.Ctx, err error) error {
+		var fe *fiber.Error
+		if errors.As(err, &fe) && fe.Code == fiber.StatusRequestEntityTooLarge {
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+				"error":   "body_too_large",
+				"message": fmt.Sprintf("request body exceeds %d bytes", maxBodyBytes),
+			})
+		}
+		return fiber.DefaultErrorHandler(c, err)
+	}
+}
+
+// apiKeyScopes grants each API key its listed scopes, or the defaults.
+func apiKeyScopes(perKey map[string]authDomain.Scopes, defaults authDomain.Scopes) func(apiKey string) authDomain.Scopes {
+	return func(apiKey string) authDomain.Scopes {
+		if scopes, ok := perKey[apiKey]; ok {
+			return scopes
+		}
+		// The defaults are for keys; callers without one are opted in
+		// explicitly.
+		if apiKey == authDomain.AnonymousKey {
+			return 0
+		}
+		return defaults
+	}
+}
+
+// sloTenantResolver labels SLIs with the tenant of the calling API key.
+// Unlisted keys share one label so cardinality stays bounded.
+func sloTenantResolver(tenants map[string]string) func(ctx context.Context) string {
+	return func(ctx context.Context) string {
+		key := authDomain.PrincipalFromContext(ctx).APIKey
+		if t, ok := tenants[key]; ok {
+			return t
+		}
+		if key == authDomain.AnonymousKey {
+			return authDomain.AnonymousKey
+		}
+		return "other"
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	archiveDomain "event-metrics-service/internal/archive/core/domain"
+	archivePorts "event-metrics-service/internal/archive/core/ports"
+	eventsUsecase "event-metrics-service/internal/events/core/usecase"
+)
+
+// archiveReplayer feeds archived events to the events module's ingest path,
+// so replayed events are validated and deduplicated like new ones, in the
+// tenant and with the sample rate they were archived with.
+type archiveReplayer struct {
+	store *eventsUsecase.StoreEventUseCase
+}
+
+var _ archivePorts.ReplayPort = archiveReplayer{}
+
+func (r archiveReplayer) Replay(ctx context.Context, records []archiveDomain.Record) (archiveDomain.ReplayResult, error) {
+	var res archiveDomain.ReplayResult
+
+	inputs := make([]eventsUsecase.ReplayEventInput, 0, len(records))
+	for _, rec := range records {
+		// Numbers stay json.Number so large integers survive the round trip.
+		var metadata map[string]any
+		dec := json.NewDecoder(bytes.NewReader(rec.Metadata))
+		dec.UseNumber()
+		if err := dec.Decode(&metadata); err != nil {
+			res.Rejected++
+			continue
+		}
+
+		inputs = append(inputs, eventsUsecase.ReplayEventInput{
+			StoreEventInput: eventsUsecase.StoreEventInput{
+				EventID:     rec.EventID,
+				EventName:   rec.EventName,
+				Channel:     rec.Channel,
+				CampaignID:  rec.CampaignID,
+				UserID:      rec.UserID,
+				AnonymousID: rec.AnonymousID,
+				TimestampMs: rec.EventTime.UnixMilli(),
+				Value:       rec.Value,
+				Tags:        rec.Tags,
+				Metadata:    metadata,
+			},
+			TenantID:   rec.TenantID,
+			SampleRate: rec.Rate(),
+		})
+	}
+
+	out, err := r.store.ReplayEvents(ctx, inputs)
+	res.Created += out.Created
+	res.Duplicates += out.Duplicates
+	res.Rejected += out.Invalid
+	return res, err
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	eventsPorts "event-metrics-service/internal/events/core/ports"
+	schemaUsecase "event-metrics-service/internal/schema/core/usecase"
+)
+
+// metadataSchemas exposes the schema registry to the events module.
+type metadataSchemas struct {
+	registry *schemaUsecase.SchemaRegistryUseCase
+}
+
+var _ eventsPorts.MetadataSchemaPort = metadataSchemas{}
+
+func (m metadataSchemas) ValidateMetadata(eventName string, metadata map[string]any) []eventsPorts.SchemaViolation {
+	violations := m.registry.Validate(eventName, metadata)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	out := make([]eventsPorts.SchemaViolation, 0, len(violations))
+	for _, v := range violations {
+		out = append(out, eventsPorts.SchemaViolation{Field: v.Path, Message: v.Message})
+	}
+	return out
+}
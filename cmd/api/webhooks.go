@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+
+	eventsDomain "event-metrics-service/internal/events/core/domain"
+	eventsPorts "event-metrics-service/internal/events/core/ports"
+	webhookDomain "event-metrics-service/internal/webhook/core/domain"
+	webhookUsecase "event-metrics-service/internal/webhook/core/usecase"
+)
+
+// webhookPublisher queues relayed events for the webhook subscriptions they
+// match.
+type webhookPublisher struct {
+	dispatch *webhookUsecase.DispatchUseCase
+}
+
+var _ eventsPorts.EventPublisherPort = webhookPublisher{}
+
+func (p webhookPublisher) Publish(ctx context.Context, events []eventsDomain.StoredEvent) error {
+	out := make([]webhookDomain.Event, 0, len(events))
+	for _, e := range events {
+		out = append(out, webhookDomain.Event{
+			ID:          e.ID,
+			EventID:     e.EventID,
+			EventName:   e.EventName,
+			Channel:     e.Channel,
+			CampaignID:  e.CampaignID,
+			UserID:      e.UserID,
+			AnonymousID: e.AnonymousID,
+			EventTime:   e.EventTime,
+			ReceivedAt:  e.ReceivedAt,
+			Value:       e.Value,
+			Tags:        e.Tags,
+			Metadata:    e.Metadata,
+			DedupeKey:   e.DedupeKey,
+			TenantID:    e.TenantID,
+		})
+	}
+	return p.dispatch.Enqueue(ctx, out)
+}
+
+// publishers relays each batch to every publisher in order and stops at the
+// first error, so the whole batch is relayed again. Idempotent publishers
+// (webhooks) should come first to keep duplicates off the others.
+type publishers []eventsPorts.EventPublisherPort
+
+func (ps publishers) Publish(ctx context.Context, events []eventsDomain.StoredEvent) error {
+	for _, p := range ps {
+		if err := p.Publish(ctx, events); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	eventsHttp "event-metrics-service/internal/events/adapters/http/fiber"
+)
+
+// defaultBulkBatch stays under the service's default BULK_MAX_EVENTS.
+const defaultBulkBatch = 500
+
+// bulkSummary totals the batches of one file. Failures are located by line.
+type bulkSummary struct {
+	Lines      int           `json:"lines"`
+	Created    int           `json:"created"`
+	Duplicates int           `json:"duplicates"`
+	Invalid    int           `json:"invalid"`
+	Accepted   int           `json:"accepted"`
+	Sampled    int           `json:"sampled"`
+	Failed     int           `json:"failed"`
+	Failures   []bulkFailure `json:"failures,omitempty"`
+}
+
+type bulkFailure struct {
+	Line    int    `json:"line"`
+	EventID string `json:"event_id,omitempty"`
+	Reason  string `json:"reason"`
+}
+
+func runBulk(ctx context.Context, e env, args []string) error {
+	fs := commandFlags(e, "bulk", "<file.ndjson | ->")
+	batch := fs.Int("batch", defaultBulkBatch, "events per request, at most the service's BULK_MAX_EVENTS")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() != 1 || *batch < 1 {
+		fs.Usage()
+		return errUsage
+	}
+
+	in := e.stdin
+	if path := fs.Arg(0); path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	summary, err := streamBulk(ctx, e.client, in, *batch)
+	if err != nil {
+		// Report what was stored before the failure, so a rerun can skip it.
+		_ = writeBulkSummary(e, summary)
+		return err
+	}
+	if err := writeBulkSummary(e, summary); err != nil {
+		return err
+	}
+	if len(summary.Failures) > 0 {
+		return fmt.Errorf("%d of %d events were not stored", len(summary.Failures), summary.Lines)
+	}
+	return nil
+}
+
+// streamBulk sends the events of r, one JSON object per line, in batches of
+// size. Blank lines are skipped; lines that are not JSON objects are
+// reported as failures without being sent.
+func streamBulk(ctx context.Context, c *client, r io.Reader, size int) (bulkSummary, error) {
+	var (
+		summary bulkSummary
+		events  []json.RawMessage
+		lines   []int // line of each event in events
+	)
+	flush := func() error {
+		if len(events) == 0 {
+			return nil
+		}
+		err := sendBulk(ctx, c, events, lines, &summary)
+		if err != nil {
+			err = fmt.Errorf("lines %d-%d: %w", lines[0], lines[len(lines)-1], err)
+		}
+		events, lines = events[:0], lines[:0]
+		return err
+	}
+
+	br := bufio.NewReader(r)
+	for n := 1; ; n++ {
+		line, readErr := br.ReadBytes('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return summary, readErr
+		}
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			summary.Lines++
+			if line[0] == '{' && json.Valid(line) {
+				events = append(events, json.RawMessage(line))
+				lines = append(lines, n)
+			} else {
+				summary.Invalid++
+				summary.Failures = append(summary.Failures, bulkFailure{Line: n, Reason: "not a JSON object"})
+			}
+		}
+		if len(events) == size || (readErr != nil && len(events) > 0) {
+			if err := flush(); err != nil {
+				return summary, err
+			}
+		}
+		if readErr != nil {
+			return summary, nil
+		}
+	}
+}
+
+func sendBulk(ctx context.Context, c *client, events []json.RawMessage, lines []int, summary *bulkSummary) error {
+	body, err := json.Marshal(struct {
+		Events []json.RawMessage `json:"events"`
+	}{events})
+	if err != nil {
+		return err
+	}
+	var resp eventsHttp.BulkCreateEventsResponse
+	if err := c.do(ctx, http.MethodPost, "/events/bulk", nil, body, &resp); err != nil {
+		return err
+	}
+
+	summary.Created += resp.Created
+	summary.Duplicates += resp.Duplicates
+	summary.Invalid += resp.Invalid
+	summary.Accepted += resp.Accepted
+	summary.Sampled += resp.Sampled
+	summary.Failed += resp.Failed
+	for _, item := range resp.Items {
+		if (item.Status != "invalid" && item.Status != "failed") || item.Index < 0 || item.Index >= len(lines) {
+			continue
+		}
+		summary.Failures = append(summary.Failures, bulkFailure{
+			Line:    lines[item.Index],
+			EventID: item.EventID,
+			Reason:  item.Reason,
+		})
+	}
+	return nil
+}
+
+func writeBulkSummary(e env, s bulkSummary) error {
+	if e.output == "json" {
+		return writeJSON(e.stdout, s)
+	}
+	t := newTable(e.stdout, "LINES", "CREATED", "DUPLICATES", "INVALID", "ACCEPTED", "SAMPLED", "FAILED")
+	t.row(s.Lines, s.Created, s.Duplicates, s.Invalid, s.Accepted, s.Sampled, s.Failed)
+	if err := t.flush(); err != nil {
+		return err
+	}
+	if len(s.Failures) == 0 {
+		return nil
+	}
+	fmt.Fprintln(e.stdout)
+	t = newTable(e.stdout, "LINE", "EVENT ID", "REASON")
+	for _, f := range s.Failures {
+		t.row(f.Line, f.EventID, f.Reason)
+	}
+	return t.flush()
+}
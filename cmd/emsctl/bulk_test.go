@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	eventsHttp "event-metrics-service/internal/events/adapters/http/fiber"
+)
+
+func TestStreamBulk_BatchesAndLocatesFailures(t *testing.T) {
+	var batches []int
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			// the first attempt is rate limited and must be retried
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if r.URL.Path != "/events/bulk" || r.Header.Get("X-API-Key") != "key-a" {
+			t.Errorf("unexpected request %s with key %q", r.URL.Path, r.Header.Get("X-API-Key"))
+		}
+		var req struct {
+			Events []map[string]any `json:"events"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		batches = append(batches, len(req.Events))
+
+		resp := eventsHttp.BulkCreateEventsResponse{}
+		for i, e := range req.Events {
+			if e["event_name"] == "" {
+				resp.Invalid++
+				resp.Items = append(resp.Items, eventsHttp.BulkItemResponse{Index: i, Status: "invalid", Reason: "event_name is required"})
+				continue
+			}
+			resp.Created++
+			resp.Items = append(resp.Items, eventsHttp.BulkItemResponse{Index: i, Status: "created"})
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	ndjson := strings.Join([]string{
+		`{"event_name":"a"}`,
+		``,
+		`{"event_name":"b"}`,
+		`not json`,
+		`{"event_name":""}`,
+	}, "\n")
+	c := &client{baseURL: srv.URL, apiKey: "key-a", http: srv.Client()}
+
+	summary, err := streamBulk(context.Background(), c, strings.NewReader(ndjson), 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(batches) != 2 || batches[0] != 2 || batches[1] != 1 {
+		t.Fatalf("expected batches of 2 and 1, got %v", batches)
+	}
+	if summary.Lines != 4 || summary.Created != 2 || summary.Invalid != 2 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if len(summary.Failures) != 2 || summary.Failures[0].Line != 4 || summary.Failures[1].Line != 5 {
+		t.Fatalf("expected failures on lines 4 and 5, got %+v", summary.Failures)
+	}
+}
+
+func TestStreamBulk_StopsOnServiceError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		w.Write([]byte(`{"error":"batch_too_large","message":"at most 1 events per request"}`))
+	}))
+	defer srv.Close()
+
+	c := &client{baseURL: srv.URL, http: srv.Client()}
+	_, err := streamBulk(context.Background(), c, strings.NewReader("{}\n{}\n"), 2)
+	if err == nil || !strings.Contains(err.Error(), "lines 1-2") || !strings.Contains(err.Error(), "batch_too_large") {
+		t.Fatalf("expected the failed lines and error code, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	metricsHttp "event-metrics-service/internal/metrics/adapters/http/fiber"
+)
+
+func runMetrics(ctx context.Context, e env, args []string) error {
+	fs := commandFlags(e, "metrics", "")
+	event := fs.String("event", "", "event name (required)")
+	from := fs.String("from", "", "start, RFC3339 or unix seconds (default 24h ago)")
+	to := fs.String("to", "", "end, RFC3339 or unix seconds (default now)")
+	channel := fs.String("channel", "", "only this channel")
+	groupBy := fs.String("group-by", "", "channel, time or metadata.<key>, or several joined by commas")
+	interval := fs.String("interval", "", "time buckets with -group-by time: minute, hour, day, week or month")
+	aggregate := fs.String("aggregate", "", "sum, avg, min, max, p50, p90, p95 or p99 of -field")
+	field := fs.String("field", "", "numeric metadata.<key> to aggregate")
+	mode := fs.String("mode", "", "histogram or lag")
+	bucketMin := fs.String("bucket-min", "", "histogram lower bound")
+	bucketMax := fs.String("bucket-max", "", "histogram upper bound")
+	bucketCount := fs.String("bucket-count", "", "number of histogram buckets")
+	asOf := fs.String("as-of", "", "count events received up to this RFC3339 time, or latest")
+	filters := keyValues{}
+	fs.Var(filters, "filter", "metadata filter key=value (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if *event == "" || fs.NArg() > 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	now := time.Now()
+	fromTime, toTime := now.Add(-24*time.Hour), now
+	var err error
+	if *from != "" {
+		if fromTime, err = parseTime(*from); err != nil {
+			return fmt.Errorf("invalid -from: %w", err)
+		}
+	}
+	if *to != "" {
+		if toTime, err = parseTime(*to); err != nil {
+			return fmt.Errorf("invalid -to: %w", err)
+		}
+	}
+
+	q := url.Values{
+		"event_name": {*event},
+		"from":       {strconv.FormatInt(fromTime.Unix(), 10)},
+		"to":         {strconv.FormatInt(toTime.Unix(), 10)},
+	}
+	for name, v := range map[string]string{
+		"channel":      *channel,
+		"group_by":     *groupBy,
+		"interval":     *interval,
+		"aggregate":    *aggregate,
+		"field":        *field,
+		"mode":         *mode,
+		"bucket_min":   *bucketMin,
+		"bucket_max":   *bucketMax,
+		"bucket_count": *bucketCount,
+		"as_of":        *asOf,
+	} {
+		if v != "" {
+			q.Set(name, v)
+		}
+	}
+	for k, v := range filters {
+		q.Set("metadata."+k, v)
+	}
+
+	var resp metricsHttp.MetricsResponse
+	if err := e.client.do(ctx, http.MethodGet, "/metrics", q, nil, &resp); err != nil {
+		return err
+	}
+	if e.output == "json" {
+		return writeJSON(e.stdout, resp)
+	}
+	return writeMetricsTable(e, resp)
+}
+
+func writeMetricsTable(e env, m metricsHttp.MetricsResponse) error {
+	var t *table
+	switch {
+	case len(m.Histogram) > 0:
+		t = newTable(e.stdout, "BUCKET", "LOWER", "UPPER", "COUNT")
+		for _, b := range m.Histogram {
+			t.row(b.Bucket, optional(b.Lower), optional(b.Upper), b.Count)
+		}
+	case len(m.Groups) > 0:
+		switch {
+		case m.Mode == "lag":
+			t = newTable(e.stdout, "KEY", "TOTAL", "AVG", "P50", "P95", "P99", "MAX")
+		case m.Aggregate != "":
+			t = newTable(e.stdout, "KEY", "TOTAL", "UNIQUE USERS", strings.ToUpper(m.Aggregate))
+		default:
+			t = newTable(e.stdout, "KEY", "TOTAL", "UNIQUE USERS")
+		}
+		for _, g := range m.Groups {
+			switch {
+			case m.Mode == "lag" && g.Lag != nil:
+				l := g.Lag
+				t.row(g.Key, g.TotalCount, l.AvgSeconds, l.P50Seconds, l.P95Seconds, l.P99Seconds, l.MaxSeconds)
+			case m.Aggregate != "":
+				t.row(g.Key, g.TotalCount, g.UniqueUsers, optional(g.Value))
+			default:
+				t.row(g.Key, g.TotalCount, g.UniqueUsers)
+			}
+		}
+	case m.Lag != nil:
+		t = newTable(e.stdout, "TOTAL", "AVG", "P50", "P95", "P99", "MAX")
+		l := m.Lag
+		t.row(m.TotalCount, l.AvgSeconds, l.P50Seconds, l.P95Seconds, l.P99Seconds, l.MaxSeconds)
+	case m.Aggregate != "":
+		t = newTable(e.stdout, "EVENT", "TOTAL", "UNIQUE USERS", strings.ToUpper(m.Aggregate))
+		t.row(m.EventName, m.TotalCount, m.UniqueUsers, optional(m.Value))
+	default:
+		t = newTable(e.stdout, "EVENT", "TOTAL", "UNIQUE USERS")
+		t.row(m.EventName, m.TotalCount, m.UniqueUsers)
+	}
+	return t.flush()
+}
+
+// optional prints an open histogram bound or a missing aggregate as "-".
+func optional(v *float64) any {
+	if v == nil {
+		return "-"
+	}
+	return *v
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	eventsHttp "event-metrics-service/internal/events/adapters/http/fiber"
+)
+
+func runSend(ctx context.Context, e env, args []string) error {
+	fs := commandFlags(e, "send", "")
+	name := fs.String("name", "", "event name (required)")
+	channel := fs.String("channel", "web", "channel")
+	user := fs.String("user", "", "user id")
+	anonymous := fs.String("anonymous", "", "anonymous id, when there is no user id")
+	campaign := fs.String("campaign", "", "campaign id")
+	id := fs.String("id", "", "event id (UUID); resending the same id is a duplicate")
+	value := fs.String("value", "", "event value")
+	at := fs.String("time", "", "event time, RFC3339 or unix seconds (default now)")
+	var tags stringList
+	fs.Var(&tags, "tag", "tag (repeatable)")
+	metadata := keyValues{}
+	fs.Var(metadata, "meta", "metadata key=value (repeatable); JSON values such as 3 or true keep their type")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if *name == "" || fs.NArg() > 0 {
+		fs.Usage()
+		return errUsage
+	}
+
+	req := eventsHttp.CreateEventRequest{
+		EventID:     *id,
+		EventName:   *name,
+		Channel:     *channel,
+		CampaignID:  *campaign,
+		UserID:      *user,
+		AnonymousID: *anonymous,
+		TimestampMs: time.Now().UnixMilli(),
+		Tags:        tags,
+		Metadata:    metadataValues(metadata),
+	}
+	if *at != "" {
+		t, err := parseTime(*at)
+		if err != nil {
+			return fmt.Errorf("invalid -time: %w", err)
+		}
+		req.TimestampMs = t.UnixMilli()
+	}
+	if *value != "" {
+		v, err := strconv.ParseFloat(*value, 64)
+		if err != nil {
+			return fmt.Errorf("invalid -value %q", *value)
+		}
+		req.Value = &v
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	var resp eventsHttp.CreateEventResponse
+	if err := e.client.do(ctx, http.MethodPost, "/events", nil, body, &resp); err != nil {
+		return err
+	}
+
+	if e.output == "json" {
+		return writeJSON(e.stdout, resp)
+	}
+	t := newTable(e.stdout, "STATUS", "EVENT ID", "MESSAGE")
+	t.row(resp.Status, resp.EventID, resp.Message)
+	return t.flush()
+}
+
+// metadataValues keeps JSON literals (numbers, booleans, objects) typed and
+// sends everything else as a string.
+func metadataValues(kv keyValues) map[string]any {
+	if len(kv) == 0 {
+		return nil
+	}
+	out := make(map[string]any, len(kv))
+	for k, v := range kv {
+		dec := json.NewDecoder(bytes.NewReader([]byte(v)))
+		dec.UseNumber()
+		var typed any
+		if err := dec.Decode(&typed); err == nil && !dec.More() {
+			if _, isString := typed.(string); !isString {
+				out[k] = typed
+				continue
+			}
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// parseTime accepts RFC3339 or unix seconds.
+func parseTime(s string) (time.Time, error) {
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(secs, 0), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	eventsUsecase "event-metrics-service/internal/events/core/usecase"
+
+	"github.com/google/uuid"
+)
+
+// profile describes the traffic to generate.
+type profile struct {
+	Events    weights            // event name -> share
+	Channels  weights            // channel -> share
+	Values    map[string]float64 // event name -> mean value; other events have none
+	Users     int                // distinct user ids
+	Anonymous float64            // share of events sent by anonymous visitors
+	Campaigns int                // distinct campaign ids; 0 = none
+	SKUs      int                // distinct metadata.sku values; 0 = none
+	From, To  time.Time          // event times; equal = all at From
+	Diurnal   bool               // more traffic in the afternoon than at night (UTC)
+}
+
+var (
+	countries = weights{{"TR", 35}, {"DE", 20}, {"US", 20}, {"GB", 10}, {"NL", 8}, {"FR", 7}}
+	plans     = weights{{"free", 80}, {"pro", 17}, {"enterprise", 3}}
+)
+
+// generator draws events from a profile. The same seed produces the same
+// events, event ids included, so runs can be repeated. Not safe for
+// concurrent use.
+type generator struct {
+	p     profile
+	rng   *rand.Rand
+	ids   *rand.ChaCha8
+	users *rand.Zipf
+	skus  *rand.Zipf
+	camps *rand.Zipf
+}
+
+func newGenerator(p profile, seed uint64) *generator {
+	rng := rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+	var key [32]byte
+	for i := range key {
+		key[i] = byte(rng.Uint32())
+	}
+
+	g := &generator{p: p, rng: rng, ids: rand.NewChaCha8(key)}
+	// A few users, SKUs and campaigns account for most traffic, as in
+	// production.
+	g.users = rand.NewZipf(rng, 1.1, 1, uint64(max(p.Users, 1)-1))
+	if p.SKUs > 0 {
+		g.skus = rand.NewZipf(rng, 1.2, 1, uint64(p.SKUs-1))
+	}
+	if p.Campaigns > 0 {
+		g.camps = rand.NewZipf(rng, 1.3, 1, uint64(p.Campaigns-1))
+	}
+	return g
+}
+
+func (g *generator) next() eventsUsecase.StoreEventInput {
+	name := g.p.Events.pick(g.rng)
+	user := g.users.Uint64()
+
+	in := eventsUsecase.StoreEventInput{
+		EventID:     g.eventID(),
+		EventName:   name,
+		Channel:     g.p.Channels.pick(g.rng),
+		TimestampMs: g.eventTime().UnixMilli(),
+		Metadata: map[string]any{
+			// Country and plan belong to the user, so they stay stable across
+			// a user's events.
+			"country": countries.at(user),
+			"plan":    plans.at(user * 7919),
+		},
+	}
+	if g.rng.Float64() < g.p.Anonymous {
+		in.AnonymousID = "anon-" + strconv.FormatUint(g.rng.Uint64N(uint64(max(g.p.Users, 1))*2), 10)
+	} else {
+		in.UserID = "user-" + strconv.FormatUint(user, 10)
+	}
+	if g.camps != nil && g.rng.Float64() < 0.4 {
+		in.CampaignID = "campaign-" + strconv.FormatUint(g.camps.Uint64(), 10)
+	}
+	if g.skus != nil {
+		in.Metadata["sku"] = "sku-" + strconv.FormatUint(g.skus.Uint64(), 10)
+	}
+	if mean, ok := g.p.Values[name]; ok {
+		// Log-normal: mostly near the mean with a long tail of large values.
+		v := math.Round(mean*math.Exp(g.rng.NormFloat64()*0.6-0.18)*100) / 100
+		in.Value = &v
+	}
+	return in
+}
+
+func (g *generator) eventID() string {
+	id, err := uuid.NewRandomFromReader(g.ids)
+	if err != nil {
+		// ChaCha8 reads never fail.
+		panic(err)
+	}
+	return id.String()
+}
+
+func (g *generator) eventTime() time.Time {
+	span := g.p.To.Sub(g.p.From)
+	if span <= 0 {
+		return g.p.From
+	}
+	for {
+		t := g.p.From.Add(time.Duration(g.rng.Int64N(int64(span))))
+		if !g.p.Diurnal || g.rng.Float64() < hourWeight(t.UTC().Hour()) {
+			return t
+		}
+	}
+}
+
+// hourWeight is the relative traffic of an hour of the day: 0.2 at 04:00,
+// rising to 1 at 16:00.
+func hourWeight(hour int) float64 {
+	return 0.6 - 0.4*math.Cos(2*math.Pi*float64(hour-4)/24)
+}
+
+// weights is a weighted choice between names.
+type weights []weighted
+
+type weighted struct {
+	name   string
+	weight float64
+}
+
+// parseWeights reads "a=70,b=30"; a name without a weight has weight 1.
+func parseWeights(s string) (weights, error) {
+	var w weights
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, hasValue := strings.Cut(part, "=")
+		weight := 1.0
+		if hasValue {
+			var err error
+			if weight, err = strconv.ParseFloat(value, 64); err != nil || weight <= 0 {
+				return nil, fmt.Errorf("%q: weight must be a positive number", part)
+			}
+		}
+		w = append(w, weighted{strings.TrimSpace(name), weight})
+	}
+	if len(w) == 0 {
+		return nil, fmt.Errorf("no names in %q", s)
+	}
+	sort.SliceStable(w, func(i, j int) bool { return w[i].weight > w[j].weight })
+	return w, nil
+}
+
+func (w weights) total() float64 {
+	var sum float64
+	for _, x := range w {
+		sum += x.weight
+	}
+	return sum
+}
+
+func (w weights) pick(rng *rand.Rand) string {
+	return w.choose(rng.Float64() * w.total())
+}
+
+// at picks deterministically from a number, e.g. a user index.
+func (w weights) at(n uint64) string {
+	return w.choose(float64(n%1000) / 1000 * w.total())
+}
+
+func (w weights) choose(r float64) string {
+	for _, x := range w {
+		if r < x.weight {
+			return x.name
+		}
+		r -= x.weight
+	}
+	return w[len(w)-1].name
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testProfile(t *testing.T) profile {
+	t.Helper()
+	p, err := buildProfile("page_view=90,purchase=10", "web=1", "purchase=50")
+	if err != nil {
+		t.Fatalf("buildProfile: %v", err)
+	}
+	p.Users, p.Campaigns, p.SKUs = 100, 5, 20
+	p.From = time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	p.To = p.From.Add(7 * 24 * time.Hour)
+	p.Diurnal = true
+	return p
+}
+
+func TestGenerator_SameSeedSameEvents(t *testing.T) {
+	a, b := newGenerator(testProfile(t), 42), newGenerator(testProfile(t), 42)
+	for i := 0; i < 100; i++ {
+		if ea, eb := a.next(), b.next(); !reflect.DeepEqual(ea, eb) {
+			t.Fatalf("event %d differs:\n%+v\n%+v", i, ea, eb)
+		}
+	}
+	if newGenerator(testProfile(t), 43).next().EventID == newGenerator(testProfile(t), 42).next().EventID {
+		t.Fatalf("expected another seed to generate other event ids")
+	}
+}
+
+func TestGenerator_FollowsProfile(t *testing.T) {
+	p := testProfile(t)
+	g := newGenerator(p, 1)
+
+	const n = 20000
+	names := map[string]int{}
+	hours := make([]int, 24)
+	for i := 0; i < n; i++ {
+		e := g.next()
+		names[e.EventName]++
+		ts := time.UnixMilli(e.TimestampMs)
+		if ts.Before(p.From) || !ts.Before(p.To) {
+			t.Fatalf("event time %s outside the window", ts)
+		}
+		hours[ts.UTC().Hour()]++
+		if (e.Value != nil) != (e.EventName == "purchase") {
+			t.Fatalf("expected values only on purchases: %+v", e)
+		}
+		if (e.UserID == "") == (e.AnonymousID == "") {
+			t.Fatalf("expected either a user or an anonymous id: %+v", e)
+		}
+	}
+
+	if share := float64(names["purchase"]) / n; share < 0.08 || share > 0.12 {
+		t.Fatalf("expected ~10%% purchases, got %.3f", share)
+	}
+	if hours[16] < 3*hours[4] {
+		t.Fatalf("expected afternoon traffic well above night traffic, got %d at 16h and %d at 4h", hours[16], hours[4])
+	}
+}
+
+func TestParseWeights(t *testing.T) {
+	w, err := parseWeights("a=1, b=3,c")
+	if err != nil {
+		t.Fatalf("parseWeights: %v", err)
+	}
+	if len(w) != 3 || w[0].name != "b" || w.total() != 5 {
+		t.Fatalf("unexpected weights: %+v", w)
+	}
+	for _, bad := range []string{"", "a=0", "a=x"} {
+		if _, err := parseWeights(bad); err == nil {
+			t.Fatalf("expected %q to be rejected", bad)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+// Command seed generates realistic synthetic events, for load tests and local
+// demos: weighted event names and channels, a long tail of users, SKUs and
+// campaigns, and day/night traffic spread over a time window.
+//
+//	seed -count 100000 -span 168h                          # POST /events/bulk of a running service
+//	seed -target db -count 1000000 -rate 0                 # straight into Postgres via the use case
+//	seed -count 0 -rate 2000 -span 0 -workers 8            # steady load at 2000 events/s until interrupted
+//
+// The same -seed generates the same events, so a rerun only adds duplicates.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	eventsRepoPg "event-metrics-service/internal/events/adapters/postgres"
+	eventsUsecase "event-metrics-service/internal/events/core/usecase"
+
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	target := flag.String("target", "http", "http: POST /events/bulk of -url; db: the events use case on -dsn (no auth, outbox or enrichers)")
+	baseURL := flag.String("url", envOr("EMS_URL", "http://localhost:8080"), "service URL (target http)")
+	apiKey := flag.String("api-key", os.Getenv("EMS_API_KEY"), "API key (target http)")
+	dsn := flag.String("dsn", os.Getenv("POSTGRES_DSN"), "Postgres DSN (target db)")
+
+	count := flag.Int("count", 10000, "events to send; 0 = until interrupted")
+	batch := flag.Int("batch", 500, "events per batch, at most the service's BULK_MAX_EVENTS")
+	workers := flag.Int("workers", 4, "concurrent batches")
+	rate := flag.Float64("rate", 0, "events per second; 0 = as fast as possible")
+	seed := flag.Uint64("seed", 1, "random seed; 0 = different every run")
+
+	events := flag.String("events", "page_view=70,add_to_cart=20,purchase=10", "event names and their weights")
+	channels := flag.String("channels", "web=60,app=30,email=10", "channels and their weights")
+	values := flag.String("values", "purchase=50", "mean value per event name; other events have none")
+	users := flag.Int("users", 5000, "distinct users")
+	anonymous := flag.Float64("anonymous", 0.2, "share of events from anonymous visitors")
+	campaigns := flag.Int("campaigns", 20, "distinct campaign ids")
+	skus := flag.Int("skus", 500, "distinct metadata.sku values")
+	span := flag.Duration("span", 7*24*time.Hour, "events are spread over this long before now; 0 = all now")
+	diurnal := flag.Bool("diurnal", true, "more traffic in the afternoon than at night (UTC)")
+	flag.Parse()
+
+	p, err := buildProfile(*events, *channels, *values)
+	if err != nil {
+		log.Fatalf("invalid profile: %v", err)
+	}
+	p.Users, p.Anonymous, p.Campaigns, p.SKUs, p.Diurnal = *users, *anonymous, *campaigns, *skus, *diurnal
+	if *batch < 1 || *workers < 1 || *count < 0 || *rate < 0 || *users < 1 {
+		log.Fatalf("-batch, -workers and -users must be at least 1; -count and -rate must not be negative")
+	}
+	if *seed == 0 {
+		*seed = uint64(time.Now().UnixNano())
+	}
+
+	var s sink
+	switch *target {
+	case "http":
+		s = httpSink{url: strings.TrimSuffix(*baseURL, "/"), apiKey: *apiKey, client: &http.Client{Timeout: time.Minute}}
+	case "db":
+		db, err := sql.Open("postgres", *dsn)
+		if err != nil {
+			log.Fatalf("failed to open postgres: %v", err)
+		}
+		defer db.Close()
+		db.SetMaxOpenConns(*workers * 2)
+		if err := db.Ping(); err != nil {
+			log.Fatalf("failed to ping postgres: %v", err)
+		}
+		repo := eventsRepoPg.NewEventRepository(eventsRepoPg.NewSQLDB(db))
+		s = usecaseSink{uc: eventsUsecase.NewStoreEventUseCase(repo)}
+	default:
+		log.Fatalf("invalid -target %q: must be http or db", *target)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("seeding %s with seed %d", describeCount(*count), *seed)
+	now := time.Now()
+	p.From, p.To = now.Add(-*span), now
+	st := runLoad(ctx, s, newGenerator(p, *seed), *count, *batch, *workers, *rate, *span == 0)
+	st.report()
+	if st.failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func buildProfile(events, channels, values string) (profile, error) {
+	var p profile
+	var err error
+	if p.Events, err = parseWeights(events); err != nil {
+		return p, fmt.Errorf("-events: %w", err)
+	}
+	if p.Channels, err = parseWeights(channels); err != nil {
+		return p, fmt.Errorf("-channels: %w", err)
+	}
+	p.Values = map[string]float64{}
+	if values != "" {
+		means, err := parseWeights(values)
+		if err != nil {
+			return p, fmt.Errorf("-values: %w", err)
+		}
+		for _, m := range means {
+			p.Values[m.name] = m.weight
+		}
+	}
+	return p, nil
+}
+
+// stats totals a run. Safe for concurrent use.
+type stats struct {
+	mu        sync.Mutex
+	started   time.Time
+	result    eventsUsecase.BulkCreateEventsResult
+	failed    int // events in failed batches
+	firstErr  error
+	latencies []time.Duration
+}
+
+func (st *stats) add(n int, res eventsUsecase.BulkCreateEventsResult, err error, took time.Duration) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.latencies = append(st.latencies, took)
+	if err != nil {
+		st.failed += n
+		if st.firstErr == nil {
+			st.firstErr = err
+		}
+		return
+	}
+	st.result.Created += res.Created
+	st.result.Duplicates += res.Duplicates
+	st.result.Invalid += res.Invalid
+	st.result.Accepted += res.Accepted
+	st.result.Sampled += res.Sampled
+	st.failed += res.Failed
+}
+
+func (st *stats) report() {
+	elapsed := time.Since(st.started)
+	r := st.result
+	sent := r.Created + r.Duplicates + r.Invalid + r.Accepted + r.Sampled + st.failed
+	log.Printf("sent %d events in %s (%.0f/s): created=%d duplicates=%d invalid=%d accepted=%d sampled=%d failed=%d",
+		sent, elapsed.Round(time.Millisecond), float64(sent)/elapsed.Seconds(),
+		r.Created, r.Duplicates, r.Invalid, r.Accepted, r.Sampled, st.failed)
+	if len(st.latencies) > 0 {
+		slices.Sort(st.latencies)
+		log.Printf("batch latency: p50=%s p95=%s p99=%s max=%s",
+			percentile(st.latencies, 0.50), percentile(st.latencies, 0.95),
+			percentile(st.latencies, 0.99), st.latencies[len(st.latencies)-1])
+	}
+	if st.firstErr != nil {
+		log.Printf("first error: %v", st.firstErr)
+	}
+}
+
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	return sorted[int(q*float64(len(sorted)-1))].Round(time.Microsecond)
+}
+
+// runLoad generates count events (0 = until ctx ends) in batches sent by
+// workers, paced to rate events per second when rate > 0. With live, every
+// event is stamped with the time it is generated.
+func runLoad(ctx context.Context, s sink, g *generator, count, batch, workers int, rate float64, live bool) *stats {
+	st := &stats{started: time.Now()}
+	batches := make(chan []eventsUsecase.StoreEventInput, workers)
+
+	// Batches already generated are sent even after an interrupt.
+	sendCtx := context.WithoutCancel(ctx)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for events := range batches {
+				start := time.Now()
+				res, err := s.send(sendCtx, events)
+				st.add(len(events), res, err, time.Since(start))
+			}
+		}()
+	}
+
+	generated := 0
+	for count == 0 || generated < count {
+		n := batch
+		if count > 0 {
+			n = min(n, count-generated)
+		}
+		if rate > 0 {
+			// Paced against the start, so slow batches are caught up on.
+			due := st.started.Add(time.Duration(float64(generated) / rate * float64(time.Second)))
+			if err := sleepUntil(ctx, due); err != nil {
+				break
+			}
+		}
+		if live {
+			now := time.Now()
+			g.p.From, g.p.To = now, now
+		}
+		events := make([]eventsUsecase.StoreEventInput, n)
+		for i := range events {
+			events[i] = g.next()
+		}
+		select {
+		case batches <- events:
+			generated += n
+			continue
+		case <-ctx.Done():
+		}
+		break
+	}
+	close(batches)
+	wg.Wait()
+	return st
+}
+
+func sleepUntil(ctx context.Context, t time.Time) error {
+	d := time.Until(t)
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func describeCount(count int) string {
+	if count == 0 {
+		return "events until interrupted"
+	}
+	return strconv.Itoa(count) + " events"
+}
+
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
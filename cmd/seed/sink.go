@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	eventsHttp "event-metrics-service/internal/events/adapters/http/fiber"
+	eventsUsecase "event-metrics-service/internal/events/core/usecase"
+)
+
+// sink stores one batch of generated events.
+type sink interface {
+	send(ctx context.Context, events []eventsUsecase.StoreEventInput) (eventsUsecase.BulkCreateEventsResult, error)
+}
+
+// usecaseSink writes through the events use case, skipping HTTP, auth and
+// rate limits.
+type usecaseSink struct {
+	uc *eventsUsecase.StoreEventUseCase
+}
+
+func (s usecaseSink) send(ctx context.Context, events []eventsUsecase.StoreEventInput) (eventsUsecase.BulkCreateEventsResult, error) {
+	return s.uc.BulkCreateEvents(ctx, eventsUsecase.BulkCreateEventsInput{Events: events})
+}
+
+// maxRateLimitWaits bounds how often one batch waits out a 429.
+const maxRateLimitWaits = 10
+
+// httpSink posts batches to POST /events/bulk of a running service.
+type httpSink struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+func (s httpSink) send(ctx context.Context, events []eventsUsecase.StoreEventInput) (eventsUsecase.BulkCreateEventsResult, error) {
+	req := struct {
+		Events []eventsHttp.CreateEventRequest `json:"events"`
+	}{Events: make([]eventsHttp.CreateEventRequest, len(events))}
+	for i, e := range events {
+		req.Events[i] = eventsHttp.CreateEventRequest{
+			EventID:     e.EventID,
+			EventName:   e.EventName,
+			Channel:     e.Channel,
+			CampaignID:  e.CampaignID,
+			UserID:      e.UserID,
+			AnonymousID: e.AnonymousID,
+			TimestampMs: e.TimestampMs,
+			Value:       e.Value,
+			Tags:        e.Tags,
+			Metadata:    e.Metadata,
+		}
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return eventsUsecase.BulkCreateEventsResult{}, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, raw, err := s.post(ctx, body)
+		if err != nil {
+			return eventsUsecase.BulkCreateEventsResult{}, err
+		}
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < maxRateLimitWaits {
+			wait := time.Second
+			if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+				wait = time.Duration(secs) * time.Second
+			}
+			select {
+			case <-ctx.Done():
+				return eventsUsecase.BulkCreateEventsResult{}, ctx.Err()
+			case <-time.After(wait):
+			}
+			continue
+		}
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return eventsUsecase.BulkCreateEventsResult{}, fmt.Errorf("POST /events/bulk: %s: %s", resp.Status, strings.TrimSpace(string(raw)))
+		}
+
+		var out eventsHttp.BulkCreateEventsResponse
+		if err := json.Unmarshal(raw, &out); err != nil {
+			return eventsUsecase.BulkCreateEventsResult{}, fmt.Errorf("POST /events/bulk: %w", err)
+		}
+		return eventsUsecase.BulkCreateEventsResult{
+			Created:    out.Created,
+			Duplicates: out.Duplicates,
+			Invalid:    out.Invalid,
+			Accepted:   out.Accepted,
+			Sampled:    out.Sampled,
+			Failed:     out.Failed,
+		}, nil
+	}
+}
+
+func (s httpSink) post(ctx context.Context, body []byte) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url+"/events/bulk", bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("X-API-Key", s.apiKey)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	return resp, raw, err
+}
@@ -15,9 +15,41 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/events": {
+        "/admin/alerts": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List alert rules and their state",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.RuleListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_alert_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Stores a single event with idempotency handling",
+                "description": "The rule fires when measure operator threshold holds for its query, a saved metrics query whose range= (default 24h) is the window evaluated up to now, e.g. total_count \u003c 100 for event_name=signup\u0026range=1h.\nEvery change between ok and firing is POSTed as JSON to notify_url; its text field suits Slack incoming webhooks.",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,51 +57,202 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Events"
+                    "Admin"
                 ],
-                "summary": "Create a new event",
+                "summary": "Create an alert rule",
                 "parameters": [
                     {
-                        "description": "Event payload",
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Rule",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/fiber.CreateEventRequest"
+                            "$ref": "#/definitions/fiber.RuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.RuleResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_alert_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_alert_adapters_http_fiber.ErrorResponse"
                         }
                     }
+                }
+            }
+        },
+        "/admin/alerts/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get an alert rule and its state",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Duplicate event",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/fiber.CreateEventResponse"
+                            "$ref": "#/definitions/fiber.RuleResponse"
                         }
                     },
-                    "201": {
-                        "description": "Created",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/fiber.CreateEventResponse"
+                            "$ref": "#/definitions/internal_alert_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_alert_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "The status is kept: a firing rule resolves, and notifies so, once the new condition no longer holds.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Replace an alert rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fiber.RuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.RuleResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                            "$ref": "#/definitions/internal_alert_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_alert_adapters_http_fiber.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                            "$ref": "#/definitions/internal_alert_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete an alert rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_alert_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_alert_adapters_http_fiber.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/events/bulk": {
+        "/admin/archive/rehydrate": {
             "post": {
-                "description": "Accepts a list of events and stores them individually",
+                "description": "Restores events purged by retention whose event_time is within [from, to] (unix seconds, at most\n31 days) from the S3 archive into the events_rehydrated table. Already restored events are skipped.",
                 "consumes": [
                     "application/json"
                 ],
@@ -77,48 +260,52 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Events"
+                    "Admin"
                 ],
-                "summary": "Bulk create events",
+                "summary": "Load archived events back into Postgres",
                 "parameters": [
                     {
-                        "description": "Bulk event payload",
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Event time range",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/fiber.BulkCreateEventsRequest"
+                            "$ref": "#/definitions/fiber.RehydrateRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "integer"
-                            }
+                            "$ref": "#/definitions/fiber.RehydrateResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                            "$ref": "#/definitions/internal_archive_adapters_http_fiber.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                            "$ref": "#/definitions/internal_archive_adapters_http_fiber.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/metrics": {
-            "get": {
-                "description": "Returns metrics grouped by channel or time bucket",
+        "/admin/archive/replay": {
+            "post": {
+                "description": "Reads the archive files (Parquet, or NDJSON in the GET /events/export format) of events whose\nevent_time is within [from, to] (unix seconds, at most 31 days) and stores them again through the\nregular ingest path, so dedupe and validation apply. Events still stored or replayed before are\nreported as duplicates.",
                 "consumes": [
                     "application/json"
                 ],
@@ -126,41 +313,118 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Metrics"
+                    "Admin"
                 ],
-                "summary": "Query aggregated metrics",
+                "summary": "Re-ingest archived events",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Event name",
-                        "name": "event_name",
-                        "in": "query",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "From timestamp",
-                        "name": "from",
-                        "in": "query",
-                        "required": true
+                        "description": "Event time range",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fiber.ReplayRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.ReplayResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_archive_adapters_http_fiber.ErrorResponse"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_archive_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/dead-letters": {
+            "get": {
+                "description": "Returns events whose insert failed after the retries, oldest first, with the last error.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List dead-lettered events",
+                "parameters": [
                     {
-                        "type": "integer",
-                        "description": "To timestamp",
-                        "name": "to",
-                        "in": "query",
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Group by: channel | time",
-                        "name": "group_by",
+                        "type": "integer",
+                        "description": "Maximum entries (default 100, max 1000)",
+                        "name": "limit",
                         "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.DeadLetterListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/dead-letters/redrive": {
+            "post": {
+                "description": "Inserts up to limit dead-lettered events again, oldest first. Stored and duplicate events leave the\nqueue; the first failure stops the run and stays queued with its new error.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Re-drive dead-lettered events",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "Interval: minute | hour | day",
-                        "name": "interval",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum entries (default 100, max 1000)",
+                        "name": "limit",
                         "in": "query"
                     }
                 ],
@@ -168,131 +432,6300 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/fiber.MetricsResponse"
+                            "$ref": "#/definitions/fiber.RedriveResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "fiber.BulkCreateEventsRequest": {
-            "type": "object",
-            "properties": {
-                "events": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/fiber.bulkEventItem"
+        },
+        "/admin/dead-letters/{id}": {
+            "delete": {
+                "description": "Drops an entry that should not be stored, e.g. one the database keeps refusing.",
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Discard a dead-lettered event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Dead letter ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/dead-letters/{id}/redrive": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Re-drive one dead-lettered event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Dead letter ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.RedriveResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/events/delete": {
+            "post": {
+                "description": "Deletes every event with event_name whose event_time is within [from, to] (to including its whole\nsecond, so events stored with milliseconds are not left behind), optionally only on one\nchannel, in batches. The dedupe claims of deleted events are released. Set dry_run to only count the\nmatching events. A delete that fails midway keeps what it deleted; sending it again finishes it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete events by filter",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Filter",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fiber.DeleteEventsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.DeleteEventsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/faults": {
+            "get": {
+                "description": "Non-production only. Requires the X-Admin-Token header.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List active injected faults",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.FaultListResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Remove all injected faults",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/admin/faults/{target}": {
+            "put": {
+                "description": "Non-production only. Replaces any fault already set on the target.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Inject latency or errors into a repository port",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target: events.repository | metrics.reader",
+                        "name": "target",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fault",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fiber.FaultRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.FaultResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_chaos_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_chaos_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Remove the injected fault from a target",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target",
+                        "name": "target",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/admin/metrics-queries": {
+            "get": {
+                "description": "Returns the metrics queries callers ran, with their filter, duration and the rows they returned, newest\nfirst or slowest first. Only available when QUERY_AUDIT_ENABLED is set; queries are kept for\nQUERY_AUDIT_RETENTION.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List audited metrics queries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "SHA-256 of the caller's API key (hex)",
+                        "name": "api_key_hash",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "tenant_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event name, as queried",
+                        "name": "event_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Started at or after (unix seconds)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Started before (unix seconds)",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only queries that took at least this long",
+                        "name": "min_duration_ms",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only queries that returned an error",
+                        "name": "failed",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "recent (default) or duration",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum entries (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.QueryListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_audit_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_audit_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/rejected-requests/{request_id}": {
+            "get": {
+                "description": "Returns the payload of a rejected POST /events or /events/bulk request by the X-Request-ID it was\nanswered with. Only available when JOURNAL_ENABLED is set; entries expire after JOURNAL_TTL.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Show a rejected ingest request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Request ID (X-Request-ID response header)",
+                        "name": "request_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.RejectedRequestResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_journal_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_journal_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/schema-compat": {
+            "get": {
+                "description": "Lists columns added by recent migrations with their SCHEMA_COLUMN_MODES flag, whether this\ninstance found them in the database and whether it currently writes and reads them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Show gated schema columns",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.SchemaCompatResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/schemas": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List registered metadata schemas",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.EventSchemaListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_schema_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/schemas/{event_name}": {
+            "put": {
+                "description": "Events with this event_name whose metadata does not match are rejected with 400 schema_violation.\nSupported keywords: type, enum, const, properties, required, additionalProperties, items,\nminItems, maxItems, minLength, maxLength, pattern, minimum, maximum, exclusiveMinimum, exclusiveMaximum.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Register the metadata JSON Schema for an event name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event name",
+                        "name": "event_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Schema",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fiber.PutSchemaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.EventSchemaResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_schema_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_schema_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Stop validating metadata for an event name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event name",
+                        "name": "event_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_schema_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_schema_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/slo": {
+            "get": {
+                "description": "Error and latency burn rates of business operations (store_event, bulk_store_events, query_metrics,\nquery_metrics_batch) per tenant over the last 5m, 1h and 6h, as seen by this instance. A burn rate\nof 1 spends the error budget exactly over the SLO period; rejected (invalid) requests do not count.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Summarize SLO burn rates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.SLOResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tenants": {
+            "post": {
+                "description": "Creates the tenant with its API keys, retention policy, channel registry entries and optional\nmetadata schemas in one atomic step. API key secrets are only returned in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Provision a new tenant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Tenant",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fiber.OnboardTenantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.OnboardTenantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_tenant_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_tenant_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_tenant_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/warmup": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Show the last warm-up run",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.WarmUpStatusResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Re-primes caches, e.g. after a database failover. Waits for the run to finish. The instance stays\nready while it runs.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Run the warm-up again",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.WarmUpStatusResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_warmup_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List webhook subscriptions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.SubscriptionListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_webhook_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Matching events are POSTed to url, signed with the secret in X-Webhook-Signature.\nEmpty event_names or channels match every value. The secret is only returned here and when it is replaced.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Subscribe a URL to stored events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Subscription",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fiber.SubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.SubscriptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_webhook_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_webhook_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.SubscriptionResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_webhook_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_webhook_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Omitting secret keeps the current one. Deliveries already queued are sent to the new url.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Replace a webhook subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Subscription",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fiber.SubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.SubscriptionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_webhook_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_webhook_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_webhook_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete a webhook subscription and its pending deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_webhook_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_webhook_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/com.snowplowanalytics.snowplow/tp2": {
+            "post": {
+                "description": "Snowplow tracker protocol POST request (tp2), a batch of events. Structured (se), page view (pv)\nand self-describing (ue) events are stored; other types and invalid events are dropped, so\ntrackers do not retry them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Collect Snowplow POST events",
+                "parameters": [
+                    {
+                        "description": "payload_data",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fiber.SnowplowPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "missing or unknown API key (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing scope events:write",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "batch_too_large: more events than BULK_MAX_EVENTS; body_too_large: body over MAX_BODY_BYTES; json_too_complex: body over JSON_MAX_DEPTH or JSON_MAX_VALUES",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate_limited: request rate limit exceeded for this API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "description": "Returns raw events, newest first, for debugging without database access.\nPass next_cursor from the previous response as cursor to fetch the next page.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "List stored events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event name",
+                        "name": "event_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Channel",
+                        "name": "channel",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campaign ID",
+                        "name": "campaign_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "From timestamp (unix seconds, inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "To timestamp (unix seconds, inclusive of the whole second)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated tags; events must carry all of them",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.ListEventsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Stores a single event with idempotency handling.\nWhen event_id (UUID) is supplied it is used as the idempotency key and echoed back; otherwise a UUIDv7 is generated and returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Create a new event",
+                "parameters": [
+                    {
+                        "description": "Event payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fiber.CreateEventRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Duplicate event",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.CreateEventResponse"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.CreateEventResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Insert failed; kept in the dead-letter queue and stored on re-drive (status accepted), or dropped by sampling (status sampled)",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.CreateEventResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "missing or unknown API key (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing scope events:write",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "metadata_too_large; body_too_large: body over MAX_BODY_BYTES; json_too_complex: body over JSON_MAX_DEPTH or JSON_MAX_VALUES",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate_limited: request rate limit exceeded for this API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/bulk": {
+            "post": {
+                "description": "Accepts a list of events and stores them individually.\nInvalid events do not abort the batch; every event gets an indexed status in \"items\".",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Bulk create events",
+                "parameters": [
+                    {
+                        "description": "Bulk event payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fiber.BulkCreateEventsRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "All-or-nothing: reject the batch on any invalid event and insert in a single transaction",
+                        "name": "atomic",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.BulkCreateEventsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "missing or unknown API key (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing scope events:write",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "batch_too_large: more events than BULK_MAX_EVENTS; body_too_large: body over MAX_BODY_BYTES; json_too_complex: body over JSON_MAX_DEPTH or JSON_MAX_VALUES; metadata_too_large (atomic batches)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate_limited: request rate limit exceeded for this API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/export": {
+            "get": {
+                "description": "Streams every event matching the filters, newest first, with chunked transfer encoding. Filters are\nthe same as GET /events. NDJSON writes one StoredEventResponse per line; CSV writes a header row\nand one row per event with tags and metadata as JSON; Parquet writes the same columns, one row group\nper batch. format wins over the Accept header. If the export fails midway, an NDJSON stream ends with\nan ErrorResponse line, a CSV stream is cut short and a Parquet file lacks its footer.\ndestination=s3 writes the file to the export bucket instead and answers with its location once it is complete.",
+                "produces": [
+                    "application/x-ndjson",
+                    "text/csv",
+                    "application/vnd.apache.parquet",
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Export stored events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "ndjson",
+                            "csv",
+                            "parquet"
+                        ],
+                        "type": "string",
+                        "description": "Output format (default ndjson, or csv or parquet when Accept asks for text/csv or application/vnd.apache.parquet)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "response",
+                            "s3"
+                        ],
+                        "type": "string",
+                        "description": "Where the file goes: response (default) | s3 (needs EXPORT_S3_BUCKET)",
+                        "name": "destination",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event name",
+                        "name": "event_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Channel",
+                        "name": "channel",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campaign ID",
+                        "name": "campaign_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "From timestamp (unix seconds, inclusive)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "To timestamp (unix seconds, inclusive of the whole second)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated tags; events must carry all of them",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of events (default: all)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "NDJSON, CSV or Parquet stream, or an ExportObjectResponse with destination=s3",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "export_failed: the upload to S3 failed",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/tail": {
+            "get": {
+                "description": "Streams events as this instance stores them, as Server-Sent Events. Each \"event\" event carries a\nStoredEventResponse without the id; duplicates and sampled-out events are not sent. A tail that falls behind\nloses events and is sent a \"dropped\" event with their count. Filters must all match; user_id also\nmatches anonymous_id. Only events accepted by the instance serving the tail are seen.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Live tail of accepted events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event name",
+                        "name": "event_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Channel",
+                        "name": "channel",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID or anonymous ID",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campaign ID",
+                        "name": "campaign_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "tenant_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "event stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/ws": {
+            "get": {
+                "description": "Upgrades to a WebSocket on which the client sends events as JSON text messages, one event object\nor an array of up to BULK_MAX_EVENTS events per message, each with an optional ref. Every event is\nanswered with one ack message in order: created, duplicate, invalid (with message), accepted,\nsampled, rejected (rate_limited, with retry_after_ms; resend it) or error (resend it). Browsers\ncannot set headers on a WebSocket, so the API key may also be passed as api_key.",
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Stream events over a WebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key, when X-API-Key cannot be sent",
+                        "name": "api_key",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Message format (sent over the socket)",
+                        "name": "message",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.WebSocketEvent"
+                        }
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols; acks are sent over the socket",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.WebSocketAck"
+                        }
+                    },
+                    "401": {
+                        "description": "missing or unknown API key (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing scope events:write",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "426": {
+                        "description": "websocket_upgrade_required: not a WebSocket handshake",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate_limited: request rate limit exceeded for this API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}": {
+            "get": {
+                "description": "Returns the full stored record, including tags, metadata, dedupe key and received_at, so support\ncan confirm an event landed. id is the client-supplied event_id (UUID) or the numeric id from GET /events.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Show a stored event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "event_id (UUID) or numeric id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.StoredEventResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Fixes channel, campaign_id, tags and metadata of a stored event and records the change, with its reason,\nin the event's audit trail. tags replace the stored tags; metadata keys are merged, null removing a key.\nThe corrected event must pass the tag, metadata and schema checks of ingest. Event name, user, time and\ndedupe key cannot be changed. id is the event_id (UUID) or the numeric id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Correct a stored event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "event_id (UUID) or numeric id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Correction",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fiber.AmendEventRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.AmendEventResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "metadata_too_large",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}/amendments": {
+            "get": {
+                "description": "Returns the corrections made to a stored event with PATCH /events/{id}, oldest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Show the audit trail of an event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "event_id (UUID) or numeric id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.AmendmentListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/heartbeats": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Heartbeats"
+                ],
+                "summary": "List heartbeats with their current health",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.HeartbeatListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "missing or unknown API key (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_heartbeat_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing scope metrics:read",
+                        "schema": {
+                            "$ref": "#/definitions/internal_heartbeat_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_heartbeat_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Heartbeats are ordinary events sent to POST /events with event_name and user_id = producer.\nRe-registering updates the interval.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Heartbeats"
+                ],
+                "summary": "Register an expected heartbeat",
+                "parameters": [
+                    {
+                        "description": "Heartbeat",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fiber.RegisterHeartbeatRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.HeartbeatResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_heartbeat_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "missing or unknown API key (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_heartbeat_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing scope events:write",
+                        "schema": {
+                            "$ref": "#/definitions/internal_heartbeat_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_heartbeat_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/heartbeats/{producer}/{event_name}": {
+            "delete": {
+                "tags": [
+                    "Heartbeats"
+                ],
+                "summary": "Stop monitoring a heartbeat",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Producer",
+                        "name": "producer",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event name",
+                        "name": "event_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "missing or unknown API key (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_heartbeat_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing scope events:write",
+                        "schema": {
+                            "$ref": "#/definitions/internal_heartbeat_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_heartbeat_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_heartbeat_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/i": {
+            "get": {
+                "description": "Snowplow tracker protocol GET request: the event is in the query parameters. Structured (se),\npage view (pv) and self-describing (ue) events are stored; other types and invalid events are\ndropped. Answers a transparent 1x1 GIF like a Snowplow collector.",
+                "produces": [
+                    "image/gif"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Collect a Snowplow GET event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event type: se, pv or ue",
+                        "name": "e",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "401": {
+                        "description": "missing or unknown API key (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing scope events:write",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate_limited: request rate limit exceeded for this API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/identify": {
+            "post": {
+                "description": "Anonymous events (anonymous_id without user_id) from the stitching window before this call onwards\ncount as user_id in unique-user metrics. Repeating the call is idempotent.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Attribute an anonymous visitor to a user",
+                "parameters": [
+                    {
+                        "description": "Identity",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fiber.IdentifyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.IdentityLinkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_identity_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "missing or unknown API key (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_identity_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing scope events:write",
+                        "schema": {
+                            "$ref": "#/definitions/internal_identity_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_identity_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_identity_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics": {
+            "get": {
+                "description": "Returns metrics grouped by channel, time bucket or a metadata key, or by up to three of them combined (group_by=channel,time).\nFilter on metadata with repeated metadata.\u003ckey\u003e=\u003cvalue\u003e query parameters (exact match).\nformat=ndjson streams every group instead of a page, with chunked transfer encoding: the first line is\nthe MetricsResponse without groups, then one MetricsGroupResponse per line. If the stream fails midway,\nit ends with an ErrorResponse line. format=csv or parquet streams the same groups as a file, as GET /metrics/export does.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv",
+                    "application/vnd.apache.parquet"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Query aggregated metrics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event name; several comma-separated or repeated names are counted together",
+                        "name": "event_name",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "From timestamp",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "To timestamp",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Group by: channel | campaign_id | user_id | time | hour_of_day | day_of_week | metadata.\u003ckey\u003e, or a comma-separated combination",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interval: minute | hour | day | week (starting Monday) | month; at most 10000 buckets",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Aggregate of field per group: sum | avg | min | max | p50 | p90 | p95 | p99",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Numeric metadata field to aggregate, or to bucket in histogram mode: metadata.\u003ckey\u003e",
+                        "name": "field",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Mode: histogram (value buckets over the event value, or over field) | lag (ingestion lag percentiles, received_at - event_time) | conversion (event_name counts divided by denominator counts, per group)",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated denominator event names (mode=conversion), e.g. product_view for event_name=purchase",
+                        "name": "denominator",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated ascending histogram bucket bounds, e.g. 0,10,50,100,500, instead of bucket_min, bucket_max and bucket_count; implies mode=histogram",
+                        "name": "buckets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Histogram lower bound (mode=histogram)",
+                        "name": "bucket_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Histogram upper bound (mode=histogram)",
+                        "name": "bucket_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of equal-width histogram buckets (mode=histogram)",
+                        "name": "bucket_count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events of this campaign; empty (campaign_id=) for events without a campaign",
+                        "name": "campaign_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the events of this user, including anonymous events stitched to them",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated tags; only events carrying all of them (or any, with tag_match=any)",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "How tags match: all (default) | any",
+                        "name": "tag_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Also query the comparison window and return changes: previous_period | previous_year",
+                        "name": "compare",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order groups by: key (default) | total_count | unique_users | value (with aggregate)",
+                        "name": "order_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order: asc | desc (default asc by key, desc otherwise)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Groups per page in key order, 1 to 10000 (default 10000); next_offset is set when more follow",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Groups to skip, e.g. the next_offset of the previous page",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv",
+                            "parquet"
+                        ],
+                        "type": "string",
+                        "description": "Response format (default json, or the format Accept asks for: application/x-ndjson, text/csv or application/vnd.apache.parquet); ndjson, csv and parquet need a group_by and no limit, offset, top, compare or mode",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "response",
+                            "s3"
+                        ],
+                        "type": "string",
+                        "description": "Where a csv or parquet file goes: response (default) | s3 (needs EXPORT_S3_BUCKET)",
+                        "name": "destination",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only count events received at or before this RFC3339 time, or 'latest' for the current watermark",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip unique users (reported as 0); whole-hour counts by channel or time are then answered from the event counters when enabled",
+                        "name": "counts_only",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer JWT from OIDC_ISSUER, required when it is set",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.MetricsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid query, query_range_too_large: the range or its time buckets exceed the configured maximum, or query_too_expensive: the planner estimates a grouped query above METRICS_MAX_QUERY_COST or METRICS_MAX_QUERY_ROWS",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "query_concurrency_limited: too many concurrent queries for this API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "query_timeout: the query ran longer than METRICS_QUERY_TIMEOUT",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics/anomalies": {
+            "get": {
+                "description": "Scores the count of every hour or day bucket against the same bucket in the previous weeks: z = (count - mean) / stddev, with stddev at least √mean.\nBuckets with |z| \u003e= threshold are flagged as spike or drop. The last bucket is scored on its count up to to, so end to on a bucket boundary to avoid flagging an hour in progress.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Unusual spikes and drops of an event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event name (comma-separated for several)",
+                        "name": "event_name",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "From timestamp",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "To timestamp",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bucket: hour | day (default hour)",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Previous weeks in the baseline, 1 to 12 (default 4)",
+                        "name": "seasons",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Flagged |z-score| (default 3)",
+                        "name": "threshold",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only return flagged buckets",
+                        "name": "anomalies_only",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Channel filter",
+                        "name": "channel",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer JWT from OIDC_ISSUER, required when it is set",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.AnomalyResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "query_concurrency_limited: too many concurrent queries for this API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics/batch": {
+            "post": {
+                "description": "Runs up to 20 metrics queries pinned to the same as_of watermark, so dashboard panels never disagree because events arrived between queries.\nWhen as_of is omitted the current watermark (newest received_at) is used and returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Query several metrics at one snapshot",
+                "parameters": [
+                    {
+                        "description": "Queries",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fiber.BatchMetricsRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer JWT from OIDC_ISSUER, required when it is set",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.BatchMetricsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid query, query_range_too_large: the range or its time buckets exceed the configured maximum, or query_too_expensive: the planner estimates a grouped query above METRICS_MAX_QUERY_COST or METRICS_MAX_QUERY_ROWS",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "query_concurrency_limited: too many concurrent queries for this API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "query_timeout: the query ran longer than METRICS_QUERY_TIMEOUT",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics/channels": {
+            "get": {
+                "description": "Lists the channels with events in the range, most frequent first, with their event count. At most 10000 channels are returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Channel catalog",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "From timestamp",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "To timestamp",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer JWT from OIDC_ISSUER, required when it is set",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.ChannelsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "query_concurrency_limited: too many concurrent queries for this API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics/dimensions/{name}/values": {
+            "get": {
+                "description": "Served from a periodically refreshed cache, intended for filter dropdowns.\nDimensions: channel, campaign_id and the configured metadata.\u003ckey\u003e dimensions.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Top values of a groupable dimension",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dimension: channel | campaign_id | metadata.\u003ckey\u003e",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only values starting with this prefix (case-insensitive)",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer JWT from OIDC_ISSUER, required when it is set",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.DimensionValuesResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics/event-names": {
+            "get": {
+                "description": "Lists the event names with events in the range, in name order, with their event count and first and last event time in the range. At most 10000 names are returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Event name catalog",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "From timestamp",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "To timestamp",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer JWT from OIDC_ISSUER, required when it is set",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.EventNamesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "query_concurrency_limited: too many concurrent queries for this API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics/export": {
+            "get": {
+                "description": "Streams every group of a grouped query as CSV or Parquet, with chunked transfer encoding, for spreadsheets\nand data tools. Takes the parameters of GET /metrics; group_by is required and limit, offset, top, compare\nand mode are not allowed. There is one column per group_by dimension (a timestamp for time in Parquet),\nthen total_count and unique_users, and value when aggregate is set. If the export fails midway, the\nCSV is cut short and the Parquet file lacks its footer. destination=s3 writes the file to the export\nbucket instead and answers with its location once it is complete.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.apache.parquet",
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Export grouped metrics as CSV or Parquet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event name; several comma-separated or repeated names are counted together",
+                        "name": "event_name",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "From timestamp",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "To timestamp",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Group by: channel | campaign_id | user_id | time | hour_of_day | day_of_week | metadata.\u003ckey\u003e, or a comma-separated combination",
+                        "name": "group_by",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interval: minute | hour | day | week (starting Monday) | month; at most 10000 buckets",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Aggregate of field per group: sum | avg | min | max | p50 | p90 | p95 | p99",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Numeric metadata field to aggregate: metadata.\u003ckey\u003e",
+                        "name": "field",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events of this campaign; empty (campaign_id=) for events without a campaign",
+                        "name": "campaign_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the events of this user, including anonymous events stitched to them",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated tags; only events carrying all of them (or any, with tag_match=any)",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "How tags match: all (default) | any",
+                        "name": "tag_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order groups by: key (default) | total_count | unique_users | value (with aggregate)",
+                        "name": "order_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order: asc | desc (default asc by key, desc otherwise)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only count events received at or before this RFC3339 time, or 'latest' for the current watermark",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip unique users (reported as 0)",
+                        "name": "counts_only",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "parquet"
+                        ],
+                        "type": "string",
+                        "description": "File format (default csv)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "response",
+                            "s3"
+                        ],
+                        "type": "string",
+                        "description": "Where the file goes: response (default) | s3 (needs EXPORT_S3_BUCKET)",
+                        "name": "destination",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer JWT from OIDC_ISSUER, required when it is set",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV or Parquet stream, or an ExportObjectResponse with destination=s3",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid query, or query_range_too_large: the range or its time buckets exceed the configured maximum",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "query_concurrency_limited: too many concurrent queries for this API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics/query": {
+            "post": {
+                "description": "Runs a metrics query described by a JSON body instead of query parameters: filters, a list of group_by dimensions\n(interval on the time one) and up to 10 aggregations. The response is that of GET /metrics; aggregations holds\nevery aggregation and each group their values in the same order, the first is also aggregate, field and value.\nWith several aggregations order_by value is refused, and comparisons cover the first aggregation only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Structured metrics query",
+                "parameters": [
+                    {
+                        "description": "Query",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fiber.StructuredQueryRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer JWT from OIDC_ISSUER, required when it is set",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.MetricsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid query, query_range_too_large: the range or its time buckets exceed the configured maximum, or query_too_expensive: the planner estimates a grouped query above METRICS_MAX_QUERY_COST or METRICS_MAX_QUERY_ROWS",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "query_concurrency_limited: too many concurrent queries for this API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "query_timeout: the query ran longer than METRICS_QUERY_TIMEOUT",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics/retention": {
+            "get": {
+                "description": "Cohorts users by the day or week of their first start_event in the range and reports, for each later bucket, how many of them did return_event in it.\nBuckets starting after to are left out, so later cohorts have fewer periods (a retention triangle).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Cohort retention",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event that puts a user in a cohort",
+                        "name": "start_event",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event that counts as coming back",
+                        "name": "return_event",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "From timestamp",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "To timestamp",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bucket: day | week (default week, starting Monday)",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Buckets to report after the cohort bucket, 1 to 90 (default 8)",
+                        "name": "periods",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer JWT from OIDC_ISSUER, required when it is set",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.RetentionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "query_concurrency_limited: too many concurrent queries for this API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics/sessions": {
+            "get": {
+                "description": "Groups every user's events in the range into sessions, split where the user was inactive for longer than gap,\nand returns the session count, session duration and events per session. Sessions are computed at query time.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Session metrics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "From timestamp",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "To timestamp (at most 31 days after from)",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Inactivity gap as a Go duration, 1m to 24h (default SESSION_GAP)",
+                        "name": "gap",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer JWT from OIDC_ISSUER, required when it is set",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.SessionStatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_session_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_session_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read",
+                        "schema": {
+                            "$ref": "#/definitions/internal_session_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "query_concurrency_limited: too many concurrent queries for this API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_session_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_session_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics/stream": {
+            "get": {
+                "description": "Streams the result of a metrics query over the last range as Server-Sent Events, for wallboards that\nwould otherwise poll GET /metrics. A \"metrics\" event carrying a MetricsResponse is sent at once and then\nevery ` + "`" + `every` + "`" + ` seconds, with from and to moved to the last range; the range starts on a whole minute. A\nquery that fails midway sends an \"error\" event with an ErrorResponse and is retried at the next tick.\nFilters and grouping are those of GET /metrics; paging, format, mode and as_of are not supported.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Live metrics stream",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event name; several comma-separated or repeated names are counted together",
+                        "name": "event_name",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rolling window ending now, as a Go duration (default 1h)",
+                        "name": "range",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Seconds between pushes, 1 to 60 (default 10)",
+                        "name": "every",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Group by: channel | campaign_id | user_id | time | hour_of_day | day_of_week | metadata.\u003ckey\u003e, or a comma-separated combination",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interval: minute | hour | day | week (starting Monday) | month",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Aggregate of field per group: sum | avg | min | max | p50 | p90 | p95 | p99",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Numeric metadata field to aggregate: metadata.\u003ckey\u003e",
+                        "name": "field",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events of this channel",
+                        "name": "channel",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events of this campaign; empty (campaign_id=) for events without a campaign",
+                        "name": "campaign_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the events of this user, including anonymous events stitched to them",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated tags; only events carrying all of them (or any, with tag_match=any)",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "How tags match: all (default) | any",
+                        "name": "tag_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Also query the comparison window and return changes: previous_period | previous_year",
+                        "name": "compare",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order groups by: key (default) | total_count | unique_users | value (with aggregate)",
+                        "name": "order_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order: asc | desc (default asc by key, desc otherwise)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Groups sent, 1 to 10000 (default 10000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip unique users (reported as 0)",
+                        "name": "counts_only",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer JWT from OIDC_ISSUER, required when it is set",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "event stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid query, or query_range_too_large: the range or its time buckets exceed the configured maximum",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "query_concurrency_limited: too many concurrent queries for this API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "too_many_streams: the maximum number of metrics streams is open",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics/top": {
+            "get": {
+                "description": "Returns the n values of a dimension with the most events in the time range, or with the highest aggregate when aggregate and field are set, highest first.\nEmpty values are skipped; total_count and unique_users cover every value.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Metrics"
+                ],
+                "summary": "Top values of a dimension",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event name; several comma-separated or repeated names are counted together",
+                        "name": "event_name",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "From timestamp",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "To timestamp",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Dimension: user_id | campaign_id | channel | metadata.\u003ckey\u003e",
+                        "name": "dimension",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of values, 1 to 1000 (default 10)",
+                        "name": "n",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rank by this aggregate of field: sum | avg | min | max | p50 | p90 | p95 | p99",
+                        "name": "aggregate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Numeric metadata field to aggregate, or to bucket in histogram mode: metadata.\u003ckey\u003e",
+                        "name": "field",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events of this campaign; empty (campaign_id=) for events without a campaign",
+                        "name": "campaign_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the events of this user, including anonymous events stitched to them",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated tags; only events carrying all of them (or any, with tag_match=any)",
+                        "name": "tags",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "How tags match: all (default) | any",
+                        "name": "tag_match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Also query the comparison window and return changes: previous_period | previous_year",
+                        "name": "compare",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only count events received at or before this RFC3339 time, or 'latest' for the current watermark",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Skip unique users (reported as 0)",
+                        "name": "counts_only",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer JWT from OIDC_ISSUER, required when it is set",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.MetricsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "invalid query, query_range_too_large: the range or its time buckets exceed the configured maximum, or query_too_expensive: the planner estimates a grouped query above METRICS_MAX_QUERY_COST or METRICS_MAX_QUERY_ROWS",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "query_concurrency_limited: too many concurrent queries for this API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "504": {
+                        "description": "query_timeout: the query ran longer than METRICS_QUERY_TIMEOUT",
+                        "schema": {
+                            "$ref": "#/definitions/internal_metrics_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/mp/collect": {
+            "post": {
+                "description": "Drop-in replacement for https://www.google-analytics.com/mp/collect. Each hit becomes an event:\nname is the event name, user_id the user, client_id (or app_instance_id) the anonymous id and\nparams the metadata; params.value and params.campaign_id fill value and campaign_id. Like\nGA4, invalid hits are dropped without failing the request. The API key may be passed as api_secret.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Collect GA4 Measurement Protocol hits",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Web stream id; one of measurement_id and firebase_app_id is required",
+                        "name": "measurement_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "App stream id",
+                        "name": "firebase_app_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "API key, when X-API-Key cannot be sent",
+                        "name": "api_secret",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Measurement Protocol payload",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fiber.GA4CollectRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "missing or unknown API key (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing scope events:write",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate_limited: request rate limit exceeded for this API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pixel.gif": {
+            "get": {
+                "description": "Records one event from the query parameters and answers a transparent 1x1 GIF that is not\ncached, e.g. for email open tracking. Parameters prefixed metadata. are stored as metadata\n(metadata.template=welcome). Invalid events are dropped; the GIF is answered anyway.",
+                "produces": [
+                    "image/gif"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Record an event from an image beacon",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Event name",
+                        "name": "event_name",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User id",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Anonymous id",
+                        "name": "anonymous_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event id (UUID), deduplicates repeated loads",
+                        "name": "event_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Channel (default email)",
+                        "name": "channel",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campaign id",
+                        "name": "campaign_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Value",
+                        "name": "value",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Event time in unix milliseconds (default: time of receipt)",
+                        "name": "timestamp_ms",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "401": {
+                        "description": "missing or unknown API key (when TENANT_ISOLATION is on)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "missing scope events:write",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "rate_limited: request rate limit exceeded for this API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_events_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/quota": {
+            "get": {
+                "description": "Quotas are soft: ingest keeps working past the limit, but responses carry X-Quota-Warning.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Quota"
+                ],
+                "summary": "Daily ingest quota of the calling API key",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.QuotaStatusResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_quota_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "200 once the startup warm-up (saved queries, dimension values, dedupe claims) has finished,\n503 before that. Point load balancer and orchestrator readiness checks here.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.WarmUpStatusResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.WarmUpStatusResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "List reports by name",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer JWT from OIDC_ISSUER, required when it is set",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.ReportListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_report_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "query is a GET /metrics query string; a pasted URL is cut at \"?\". range= (e.g. 168h) stands in for from/to and ends at the time of each run; without either a report covers the last 24h.\nThe query is only checked for event_name and its time range here; GET /metrics validates the rest on each run.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Save a metrics query as a named report",
+                "parameters": [
+                    {
+                        "description": "Report",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fiber.ReportRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer JWT from OIDC_ISSUER, required when it is set",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.ReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_report_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/internal_report_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_report_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/{name}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Get a report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Report name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer JWT from OIDC_ISSUER, required when it is set",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.ReportResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_report_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_report_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Replace the description and query of a report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Report name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Report",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/fiber.ReportRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer JWT from OIDC_ISSUER, required when it is set",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.ReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_report_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_report_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_report_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Delete a report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Report name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer JWT from OIDC_ISSUER, required when it is set",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_report_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_report_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/{name}/run": {
+            "get": {
+                "description": "Answers like GET /metrics with the saved query. Query parameters override the saved ones, e.g. format=ndjson or offset for the next page; range=, or from and to, replace the saved time range.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reports"
+                ],
+                "summary": "Run a report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Report name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Relative time range ending now, e.g. 168h",
+                        "name": "range",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Start (unix seconds), with to",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "End (unix seconds), with from",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer JWT from OIDC_ISSUER, required when it is set",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "the GET /metrics response",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_report_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_report_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "query_concurrency_limited: too many concurrent queries for this API key",
+                        "schema": {
+                            "$ref": "#/definitions/internal_report_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_report_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/events": {
+            "delete": {
+                "description": "Deletes every event of the user, anonymous events stitched to it via /identify, its identity links\nand the dedupe claims of the deleted events, in one atomic statement. Repeating the call is safe.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Erase a user's events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID (URL-escaped)",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/fiber.DeletionReceiptResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_privacy_adapters_http_fiber.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_privacy_adapters_http_fiber.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "fiber.APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string",
+                    "example": "key_3f2a9c1b7d4e5f60"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "web"
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "ems_5c1e..."
+                }
+            }
+        },
+        "fiber.AggregationResponse": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "metadata.amount"
+                },
+                "function": {
+                    "type": "string",
+                    "example": "sum"
+                },
+                "value": {
+                    "type": "number",
+                    "example": 1234.5
+                }
+            }
+        },
+        "fiber.AmendEventRequest": {
+            "type": "object",
+            "properties": {
+                "amended_by": {
+                    "type": "string",
+                    "example": "jane@example.com"
+                },
+                "campaign_id": {
+                    "type": "string",
+                    "example": "black-friday"
+                },
+                "channel": {
+                    "type": "string",
+                    "example": "ios"
+                },
+                "metadata": {
+                    "description": "Merged into the stored metadata; null removes a key.",
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "campaign was mis-tagged by the 4.2.0 iOS release"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "fiber.AmendEventResponse": {
+            "type": "object",
+            "properties": {
+                "amendment": {
+                    "description": "null when the event already matched and nothing was changed",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/fiber.AmendmentResponse"
+                        }
+                    ]
+                },
+                "event": {
+                    "$ref": "#/definitions/fiber.StoredEventResponse"
+                }
+            }
+        },
+        "fiber.AmendmentListResponse": {
+            "type": "object",
+            "properties": {
+                "amendments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.AmendmentResponse"
+                    }
+                }
+            }
+        },
+        "fiber.AmendmentResponse": {
+            "type": "object",
+            "properties": {
+                "amended_at": {
+                    "type": "string"
+                },
+                "amended_by": {
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.FieldChangeResponse"
+                    }
+                },
+                "event_row_id": {
+                    "type": "integer",
+                    "example": 1042
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "fiber.AnomalyPointResponse": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "string"
+                },
+                "count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "direction": {
+                    "description": "spike or drop when flagged",
+                    "type": "string",
+                    "example": "drop"
+                },
+                "expected": {
+                    "description": "mean count of the bucket in the previous weeks",
+                    "type": "number",
+                    "example": 140.5
+                },
+                "stddev": {
+                    "type": "number",
+                    "example": 11.9
+                },
+                "z_score": {
+                    "type": "number",
+                    "example": -10.8
+                }
+            }
+        },
+        "fiber.AnomalyResponse": {
+            "type": "object",
+            "properties": {
+                "event_name": {
+                    "type": "string",
+                    "example": "signup"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "interval": {
+                    "type": "string",
+                    "example": "hour"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.AnomalyPointResponse"
+                    }
+                },
+                "seasons": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "threshold": {
+                    "type": "number",
+                    "example": 3
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "fiber.BatchMetricsRequest": {
+            "type": "object",
+            "properties": {
+                "as_of": {
+                    "type": "string",
+                    "example": "2025-12-07T10:00:00.123Z"
+                },
+                "queries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.MetricsQueryRequest"
+                    }
+                }
+            }
+        },
+        "fiber.BatchMetricsResponse": {
+            "type": "object",
+            "properties": {
+                "as_of": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.MetricsResponse"
+                    }
+                }
+            }
+        },
+        "fiber.BulkCreateEventsRequest": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.bulkEventItem"
+                    }
+                }
+            }
+        },
+        "fiber.BulkCreateEventsResponse": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "description": "dead-lettered, stored on re-drive",
+                    "type": "integer"
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "duplicates": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "description": "valid, not stored; safe to retry",
+                    "type": "integer"
+                },
+                "invalid": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.BulkItemResponse"
+                    }
+                },
+                "sampled": {
+                    "description": "valid, dropped by sampling",
+                    "type": "integer"
+                }
+            }
+        },
+        "fiber.BulkItemResponse": {
+            "type": "object",
+            "properties": {
+                "event_id": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "invalid event"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "created",
+                        "duplicate",
+                        "invalid",
+                        "accepted",
+                        "sampled",
+                        "failed"
+                    ],
+                    "example": "created"
+                }
+            }
+        },
+        "fiber.ChangeResponse": {
+            "type": "object",
+            "properties": {
+                "delta": {
+                    "type": "number",
+                    "example": 120
+                },
+                "percent": {
+                    "type": "number",
+                    "example": 12.5
+                }
+            }
+        },
+        "fiber.ChangesResponse": {
+            "type": "object",
+            "properties": {
+                "total_count": {
+                    "$ref": "#/definitions/fiber.ChangeResponse"
+                },
+                "unique_users": {
+                    "$ref": "#/definitions/fiber.ChangeResponse"
+                },
+                "value": {
+                    "$ref": "#/definitions/fiber.ChangeResponse"
+                }
+            }
+        },
+        "fiber.ChannelRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Marketing website"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "web"
+                }
+            }
+        },
+        "fiber.ChannelResponse": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Marketing website"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "web"
+                }
+            }
+        },
+        "fiber.ChannelsResponse": {
+            "type": "object",
+            "properties": {
+                "channels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.DimensionValueResponse"
+                    }
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "fiber.ColumnStateResponse": {
+            "type": "object",
+            "properties": {
+                "column": {
+                    "type": "string",
+                    "example": "events.anonymous_id"
+                },
+                "exists": {
+                    "type": "boolean"
+                },
+                "mode": {
+                    "type": "string",
+                    "enum": [
+                        "off",
+                        "write",
+                        "on"
+                    ],
+                    "example": "write"
+                },
+                "readable": {
+                    "type": "boolean"
+                },
+                "writable": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "fiber.ComparisonResponse": {
+            "type": "object",
+            "properties": {
+                "change": {
+                    "$ref": "#/definitions/fiber.ChangesResponse"
+                },
+                "compare": {
+                    "type": "string",
+                    "example": "previous_period"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.MetricsGroupResponse"
+                    }
+                },
+                "to": {
+                    "type": "integer"
+                },
+                "total_count": {
+                    "type": "integer"
+                },
+                "unique_users": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "fiber.ConversionResponse": {
+            "type": "object",
+            "properties": {
+                "denominator": {
+                    "type": "string",
+                    "example": "product_view"
+                },
+                "rate": {
+                    "type": "number",
+                    "example": 0.05
+                },
+                "total_count": {
+                    "type": "integer",
+                    "example": 2400
+                },
+                "unique_users": {
+                    "type": "integer",
+                    "example": 910
+                },
+                "user_rate": {
+                    "type": "number",
+                    "example": 0.11
+                }
+            }
+        },
+        "fiber.CreateEventRequest": {
+            "description": "Event creation DTO",
+            "type": "object",
+            "properties": {
+                "anonymous_id": {
+                    "type": "string",
+                    "example": "3f9c2a7e-anon"
+                },
+                "campaign_id": {
+                    "type": "string"
+                },
+                "channel": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string",
+                    "example": "0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42"
+                },
+                "event_name": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timestamp": {
+                    "type": "integer",
+                    "example": 1733572800
+                },
+                "timestamp_ms": {
+                    "type": "integer",
+                    "example": 1733572800123
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number",
+                    "example": 42.5
+                }
+            }
+        },
+        "fiber.CreateEventResponse": {
+            "type": "object",
+            "properties": {
+                "event_id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "fiber.DeadLetterListResponse": {
+            "type": "object",
+            "properties": {
+                "dead_letters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.DeadLetterResponse"
+                    }
+                }
+            }
+        },
+        "fiber.DeadLetterResponse": {
+            "type": "object",
+            "properties": {
+                "anonymous_id": {
+                    "type": "string"
+                },
+                "attempts": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "campaign_id": {
+                    "type": "string"
+                },
+                "channel": {
+                    "type": "string",
+                    "example": "web"
+                },
+                "dedupe_key": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "dial tcp 10.0.0.5:5432: connect: connection refused"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_name": {
+                    "type": "string",
+                    "example": "purchase"
+                },
+                "event_time": {
+                    "type": "string"
+                },
+                "failed_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "5f1c2d3e-0a9b-4c8d-b7e6-1f2a3b4c5d6e"
+                },
+                "last_attempt_at": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "fiber.DeleteEventsRequest": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string",
+                    "example": "web"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "event_name": {
+                    "type": "string",
+                    "example": "test_event"
+                },
+                "from": {
+                    "description": "unix seconds, inclusive",
+                    "type": "integer",
+                    "example": 1733529600
+                },
+                "to": {
+                    "description": "unix seconds, inclusive of the whole second",
+                    "type": "integer",
+                    "example": 1733616000
+                }
+            }
+        },
+        "fiber.DeleteEventsResponse": {
+            "type": "object",
+            "properties": {
+                "batches": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "deleted": {
+                    "type": "integer",
+                    "example": 1000000
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "matched": {
+                    "type": "integer",
+                    "example": 1000000
+                }
+            }
+        },
+        "fiber.DeletionReceiptResponse": {
+            "type": "object",
+            "properties": {
+                "dedupe_keys_deleted": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "erased_at": {
+                    "type": "string"
+                },
+                "events_deleted": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "identity_links_deleted": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "receipt_id": {
+                    "type": "string",
+                    "example": "5d0c1c2e-8f0a-4a57-b7c4-0f9f3b0d6a11"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "u1"
+                }
+            }
+        },
+        "fiber.DimensionValueResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 1520
+                },
+                "value": {
+                    "type": "string",
+                    "example": "web"
+                }
+            }
+        },
+        "fiber.DimensionValuesResponse": {
+            "type": "object",
+            "properties": {
+                "dimension": {
+                    "type": "string",
+                    "example": "channel"
+                },
+                "refreshed_at": {
+                    "type": "string"
+                },
+                "values": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.DimensionValueResponse"
+                    }
+                }
+            }
+        },
+        "fiber.ErrorDetail": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "tags[0]"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "exceeds max length 64"
+                }
+            }
+        },
+        "fiber.EventNameResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 1520
+                },
+                "first_seen": {
+                    "type": "string"
+                },
+                "last_seen": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "signup"
+                }
+            }
+        },
+        "fiber.EventNamesResponse": {
+            "type": "object",
+            "properties": {
+                "event_names": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.EventNameResponse"
+                    }
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "fiber.EventSchemaListResponse": {
+            "type": "object",
+            "properties": {
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.EventSchemaResponse"
+                    }
+                }
+            }
+        },
+        "fiber.EventSchemaResponse": {
+            "type": "object",
+            "properties": {
+                "event_name": {
+                    "type": "string",
+                    "example": "purchase"
+                },
+                "schema": {
+                    "type": "object"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "fiber.FaultListResponse": {
+            "type": "object",
+            "properties": {
+                "faults": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.FaultResponse"
+                    }
+                },
+                "targets": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "fiber.FaultRequest": {
+            "type": "object",
+            "properties": {
+                "error_rate": {
+                    "type": "number",
+                    "example": 0.1
+                },
+                "latency_ms": {
+                    "type": "integer",
+                    "example": 250
+                },
+                "message": {
+                    "type": "string",
+                    "example": "simulated connection reset"
+                }
+            }
+        },
+        "fiber.FaultResponse": {
+            "type": "object",
+            "properties": {
+                "error_rate": {
+                    "type": "number",
+                    "example": 0.1
+                },
+                "latency_ms": {
+                    "type": "integer",
+                    "example": 250
+                },
+                "message": {
+                    "type": "string",
+                    "example": "simulated connection reset"
+                },
+                "target": {
+                    "type": "string",
+                    "example": "events.repository"
+                }
+            }
+        },
+        "fiber.FieldChangeResponse": {
+            "type": "object",
+            "properties": {
+                "after": {},
+                "before": {},
+                "field": {
+                    "type": "string",
+                    "example": "campaign_id"
+                }
+            }
+        },
+        "fiber.GA4CollectRequest": {
+            "description": "GA4 Measurement Protocol payload",
+            "type": "object",
+            "properties": {
+                "app_instance_id": {
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "string",
+                    "example": "123456.7654321"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.GA4Event"
+                    }
+                },
+                "timestamp_micros": {
+                    "type": "integer",
+                    "example": 1733572800123456
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "user_properties": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/fiber.ga4UserProperty"
+                    }
+                }
+            }
+        },
+        "fiber.GA4Event": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "purchase"
+                },
+                "params": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "timestamp_micros": {
+                    "type": "integer"
+                }
+            }
+        },
+        "fiber.HeartbeatListResponse": {
+            "type": "object",
+            "properties": {
+                "heartbeats": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.HeartbeatResponse"
+                    }
+                }
+            }
+        },
+        "fiber.HeartbeatResponse": {
+            "type": "object",
+            "properties": {
+                "event_name": {
+                    "type": "string",
+                    "example": "billing_heartbeat"
+                },
+                "interval_seconds": {
+                    "type": "integer",
+                    "example": 60
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "overdue_seconds": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "producer": {
+                    "type": "string",
+                    "example": "billing-cron"
+                },
+                "registered_at": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "healthy",
+                        "missing",
+                        "pending"
+                    ],
+                    "example": "healthy"
+                }
+            }
+        },
+        "fiber.HistogramBucketResponse": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "integer"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "lower": {
+                    "type": "number"
+                },
+                "upper": {
+                    "type": "number"
+                }
+            }
+        },
+        "fiber.IdentifyRequest": {
+            "type": "object",
+            "properties": {
+                "anonymous_id": {
+                    "type": "string",
+                    "example": "3f9c2a7e-anon"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "user_123"
+                }
+            }
+        },
+        "fiber.IdentityLinkResponse": {
+            "type": "object",
+            "properties": {
+                "anonymous_id": {
+                    "type": "string",
+                    "example": "3f9c2a7e-anon"
+                },
+                "identified_at": {
+                    "type": "string"
+                },
+                "stitch_from": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "user_123"
+                }
+            }
+        },
+        "fiber.LagResponse": {
+            "type": "object",
+            "properties": {
+                "avg_seconds": {
+                    "type": "number",
+                    "example": 2.4
+                },
+                "max_seconds": {
+                    "type": "number",
+                    "example": 312.7
+                },
+                "p50_seconds": {
+                    "type": "number",
+                    "example": 0.8
+                },
+                "p95_seconds": {
+                    "type": "number",
+                    "example": 9.5
+                },
+                "p99_seconds": {
+                    "type": "number",
+                    "example": 41
+                }
+            }
+        },
+        "fiber.ListEventsResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.StoredEventResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "MTczMzU3MjgwMDAwMDAwMDAwMDoxMDQy"
+                }
+            }
+        },
+        "fiber.MetricsGroupResponse": {
+            "type": "object",
+            "properties": {
+                "change": {
+                    "$ref": "#/definitions/fiber.ChangesResponse"
+                },
+                "conversion": {
+                    "$ref": "#/definitions/fiber.ConversionResponse"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "web|2025-12-07T10:00:00Z"
+                },
+                "keys": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "lag": {
+                    "$ref": "#/definitions/fiber.LagResponse"
+                },
+                "total_count": {
+                    "type": "integer"
+                },
+                "unique_users": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "number"
+                },
+                "values": {
+                    "description": "POST /metrics/query: one per aggregation, null without numeric values",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "fiber.MetricsQueryRequest": {
+            "type": "object",
+            "properties": {
+                "aggregate": {
+                    "type": "string"
+                },
+                "bucket_count": {
+                    "type": "integer"
+                },
+                "bucket_max": {
+                    "type": "number"
+                },
+                "bucket_min": {
+                    "type": "number"
+                },
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "campaign_id": {
+                    "description": "\"\" = events without a campaign",
+                    "type": "string"
+                },
+                "channel": {
+                    "type": "string"
+                },
+                "compare": {
+                    "type": "string"
+                },
+                "counts_only": {
+                    "type": "boolean"
+                },
+                "denominator": {
+                    "type": "string"
+                },
+                "event_name": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "group_by": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "order": {
+                    "type": "string"
+                },
+                "order_by": {
+                    "type": "string"
+                },
+                "tag_match": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "to": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "fiber.MetricsResponse": {
+            "type": "object",
+            "properties": {
+                "aggregate": {
+                    "description": "Aggregate of a numeric metadata field; value is omitted when no\nmatching event carries a number in it.",
+                    "type": "string",
+                    "example": "sum"
+                },
+                "aggregations": {
+                    "description": "Every aggregation of POST /metrics/query; the first is also\naggregate, field and value.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.AggregationResponse"
+                    }
+                },
+                "as_of": {
+                    "type": "string"
+                },
+                "comparison": {
+                    "$ref": "#/definitions/fiber.ComparisonResponse"
+                },
+                "conversion": {
+                    "$ref": "#/definitions/fiber.ConversionResponse"
+                },
+                "event_name": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string",
+                    "example": "metadata.revenue"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "group_by": {
+                    "type": "string"
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.MetricsGroupResponse"
+                    }
+                },
+                "histogram": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.HistogramBucketResponse"
+                    }
+                },
+                "lag": {
+                    "$ref": "#/definitions/fiber.LagResponse"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "next_offset": {
+                    "description": "offset of the next page of groups, when one follows",
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "integer"
+                },
+                "total_count": {
+                    "type": "integer"
+                },
+                "unique_users": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "number",
+                    "example": 1234.5
+                }
+            }
+        },
+        "fiber.OnboardTenantRequest": {
+            "type": "object",
+            "properties": {
+                "api_keys": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "web",
+                        "backend"
+                    ]
+                },
+                "channels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.ChannelRequest"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "example": "acme"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Acme Corp"
+                },
+                "retention_days": {
+                    "type": "integer",
+                    "example": 90
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.SchemaRequest"
+                    }
+                }
+            }
+        },
+        "fiber.OnboardTenantResponse": {
+            "type": "object",
+            "properties": {
+                "api_keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.APIKeyResponse"
+                    }
+                },
+                "channels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.ChannelResponse"
+                    }
+                },
+                "schemas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tenant": {
+                    "$ref": "#/definitions/fiber.TenantResponse"
+                }
+            }
+        },
+        "fiber.PutSchemaRequest": {
+            "type": "object",
+            "properties": {
+                "schema": {
+                    "type": "object"
+                }
+            }
+        },
+        "fiber.QueryAggregateRequest": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "metadata.amount"
+                },
+                "function": {
+                    "type": "string",
+                    "example": "sum"
+                }
+            }
+        },
+        "fiber.QueryFiltersRequest": {
+            "type": "object",
+            "properties": {
+                "campaign_id": {
+                    "description": "\"\" = events without a campaign",
+                    "type": "string"
+                },
+                "channel": {
+                    "type": "string",
+                    "example": "web"
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "tag_match": {
+                    "type": "string",
+                    "example": "any"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "fiber.QueryGroupByRequest": {
+            "type": "object",
+            "properties": {
+                "dimension": {
+                    "type": "string",
+                    "example": "time"
+                },
+                "interval": {
+                    "type": "string",
+                    "example": "hour"
+                }
+            }
+        },
+        "fiber.QueryListResponse": {
+            "type": "object",
+            "properties": {
+                "queries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.QueryResponse"
+                    }
+                }
+            }
+        },
+        "fiber.QueryOptionsRequest": {
+            "type": "object",
+            "properties": {
+                "as_of": {
+                    "description": "RFC3339 time or \"latest\"",
+                    "type": "string",
+                    "example": "latest"
+                },
+                "compare": {
+                    "type": "string",
+                    "example": "previous_period"
+                },
+                "counts_only": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "fiber.QueryResponse": {
+            "type": "object",
+            "properties": {
+                "api_key_hash": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "type": "number",
+                    "example": 412.5
+                },
+                "error": {
+                    "type": "string"
+                },
+                "event_name": {
+                    "type": "string",
+                    "example": "purchase"
+                },
+                "filter": {
+                    "type": "object"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "group_by": {
+                    "type": "string",
+                    "example": "channel,time"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 81234
+                },
+                "operation": {
+                    "type": "string",
+                    "example": "query"
+                },
+                "rows": {
+                    "type": "integer",
+                    "example": 168
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "fiber.QuotaStatusResponse": {
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "type": "string",
+                    "example": "key-a"
+                },
+                "exceeded": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 100000
+                },
+                "remaining": {
+                    "type": "integer",
+                    "example": 15000
+                },
+                "reset_at": {
+                    "type": "string"
+                },
+                "unlimited": {
+                    "type": "boolean"
+                },
+                "used": {
+                    "type": "integer",
+                    "example": 85000
+                },
+                "warning": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "fiber.RedriveResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer",
+                    "example": 40
+                },
+                "duplicates": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                }
+            }
+        },
+        "fiber.RegisterHeartbeatRequest": {
+            "type": "object",
+            "properties": {
+                "event_name": {
+                    "type": "string",
+                    "example": "billing_heartbeat"
+                },
+                "interval_seconds": {
+                    "type": "integer",
+                    "example": 60
+                },
+                "producer": {
+                    "type": "string",
+                    "example": "billing-cron"
+                }
+            }
+        },
+        "fiber.RehydrateRequest": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "integer",
+                    "example": 1725148800
+                },
+                "to": {
+                    "type": "integer",
+                    "example": 1725235199
+                }
+            }
+        },
+        "fiber.RehydrateResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "integer",
+                    "example": 14210
+                },
+                "files": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "from": {
+                    "type": "string"
+                },
+                "restored": {
+                    "type": "integer",
+                    "example": 14210
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "fiber.RejectedRequestResponse": {
+            "type": "object",
+            "properties": {
+                "api_key_hash": {
+                    "type": "string"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "body_base64": {
+                    "type": "string"
+                },
+                "body_sha256": {
+                    "type": "string"
+                },
+                "body_size": {
+                    "type": "integer",
+                    "example": 1834
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string",
+                    "example": "POST"
+                },
+                "path": {
+                    "type": "string",
+                    "example": "/events/bulk?atomic=true"
+                },
+                "received_at": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "3f1c9a2e-5b7d-4e8f-a1b2-c3d4e5f60718"
+                },
+                "response": {
+                    "type": "string",
+                    "example": "{\"error\":\"invalid_event\"}"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "truncated": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "fiber.ReplayRequest": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "integer",
+                    "example": 1725148800
+                },
+                "to": {
+                    "type": "integer",
+                    "example": 1725235199
+                }
+            }
+        },
+        "fiber.ReplayResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer",
+                    "example": 14190
+                },
+                "duplicates": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "events": {
+                    "type": "integer",
+                    "example": 14210
+                },
+                "files": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "from": {
+                    "type": "string"
+                },
+                "rejected": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "fiber.ReportListResponse": {
+            "type": "object",
+            "properties": {
+                "reports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.ReportResponse"
+                    }
+                }
+            }
+        },
+        "fiber.ReportRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Purchases per channel over the last week"
+                },
+                "name": {
+                    "description": "create only; PUT takes it from the path",
+                    "type": "string",
+                    "example": "weekly-purchases"
+                },
+                "query": {
+                    "type": "string",
+                    "example": "event_name=purchase\u0026group_by=channel\u0026range=168h"
+                }
+            }
+        },
+        "fiber.ReportResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "weekly-purchases"
+                },
+                "query": {
+                    "type": "string",
+                    "example": "event_name=purchase\u0026group_by=channel\u0026range=168h"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "fiber.RetentionCohortResponse": {
+            "type": "object",
+            "properties": {
+                "rates": {
+                    "description": "returned as fractions of users",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "returned": {
+                    "description": "returned[k-1]: users back k buckets after start",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "start": {
+                    "type": "string"
+                },
+                "users": {
+                    "type": "integer"
+                }
+            }
+        },
+        "fiber.RetentionResponse": {
+            "type": "object",
+            "properties": {
+                "cohorts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.RetentionCohortResponse"
+                    }
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "interval": {
+                    "type": "string"
+                },
+                "periods": {
+                    "type": "integer"
+                },
+                "return_event": {
+                    "type": "string"
+                },
+                "start_event": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "fiber.RuleListResponse": {
+            "type": "object",
+            "properties": {
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.RuleResponse"
+                    }
+                }
+            }
+        },
+        "fiber.RuleRequest": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "defaults to true",
+                    "type": "boolean",
+                    "example": true
+                },
+                "measure": {
+                    "description": "total_count, unique_users or value",
+                    "type": "string",
+                    "example": "total_count"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "signups stalled"
+                },
+                "notify_url": {
+                    "type": "string",
+                    "example": "https://hooks.slack.com/services/T000/B000/XXXX"
+                },
+                "operator": {
+                    "type": "string",
+                    "example": "\u003c"
+                },
+                "query": {
+                    "type": "string",
+                    "example": "event_name=signup\u0026range=1h"
+                },
+                "tenant_id": {
+                    "description": "the tenant the query runs as (TENANT_ISOLATION)",
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "number",
+                    "example": 100
+                }
+            }
+        },
+        "fiber.RuleResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "evaluated_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "5f1c2d3e-0a9b-4c8d-b7e6-1f2a3b4c5d6e"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "measure": {
+                    "type": "string",
+                    "example": "total_count"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "signups stalled"
+                },
+                "notify_url": {
+                    "type": "string"
+                },
+                "operator": {
+                    "type": "string",
+                    "example": "\u003c"
+                },
+                "query": {
+                    "type": "string",
+                    "example": "event_name=signup\u0026range=1h"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "firing"
+                },
+                "status_since": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "number",
+                    "example": 100
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number",
+                    "example": 12
+                }
+            }
+        },
+        "fiber.SLOResponse": {
+            "type": "object",
+            "properties": {
+                "summaries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.SLOSummaryResponse"
+                    }
+                }
+            }
+        },
+        "fiber.SLOSummaryResponse": {
+            "type": "object",
+            "properties": {
+                "availability_objective": {
+                    "type": "number",
+                    "example": 0.999
+                },
+                "latency_objective": {
+                    "type": "number",
+                    "example": 0.99
+                },
+                "latency_target_ms": {
+                    "type": "number",
+                    "example": 100
+                },
+                "operation": {
+                    "type": "string",
+                    "example": "store_event"
+                },
+                "tenant": {
+                    "type": "string",
+                    "example": "acme"
+                },
+                "windows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.SLOWindowResponse"
+                    }
+                }
+            }
+        },
+        "fiber.SLOWindowResponse": {
+            "type": "object",
+            "properties": {
+                "error_burn_rate": {
+                    "type": "number",
+                    "example": 0.25
+                },
+                "errors": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "latency_burn_rate": {
+                    "type": "number",
+                    "example": 0.75
+                },
+                "p99_ms": {
+                    "type": "number",
+                    "example": 100
+                },
+                "rejected": {
+                    "type": "integer",
+                    "example": 41
+                },
+                "requests": {
+                    "type": "integer",
+                    "example": 12000
+                },
+                "slow": {
+                    "type": "integer",
+                    "example": 90
+                },
+                "window": {
+                    "type": "string",
+                    "example": "1h0m0s"
+                }
+            }
+        },
+        "fiber.SchemaCompatResponse": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.ColumnStateResponse"
+                    }
+                }
+            }
+        },
+        "fiber.SchemaRequest": {
+            "type": "object",
+            "properties": {
+                "event_name": {
+                    "type": "string",
+                    "example": "purchase"
+                },
+                "schema": {
+                    "type": "object"
+                }
+            }
+        },
+        "fiber.SessionStatsResponse": {
+            "type": "object",
+            "properties": {
+                "avg_duration_seconds": {
+                    "type": "number",
+                    "example": 312.5
+                },
+                "avg_events_per_session": {
+                    "type": "number",
+                    "example": 6.4
+                },
+                "from": {
+                    "type": "integer",
+                    "example": 1765065600
+                },
+                "gap_seconds": {
+                    "type": "integer",
+                    "example": 1800
+                },
+                "median_duration_seconds": {
+                    "type": "number",
+                    "example": 95
+                },
+                "sessions": {
+                    "type": "integer",
+                    "example": 5120
+                },
+                "to": {
+                    "type": "integer",
+                    "example": 1765152000
+                },
+                "users": {
+                    "type": "integer",
+                    "example": 2048
+                }
+            }
+        },
+        "fiber.SnowplowPayload": {
+            "description": "Snowplow payload_data",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "additionalProperties": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "schema": {
+                    "type": "string",
+                    "example": "iglu:com.snowplowanalytics.snowplow/payload_data/jsonschema/1-0-4"
+                }
+            }
+        },
+        "fiber.StoredEventResponse": {
+            "type": "object",
+            "properties": {
+                "anonymous_id": {
+                    "type": "string"
+                },
+                "campaign_id": {
+                    "type": "string"
+                },
+                "channel": {
+                    "type": "string",
+                    "example": "web"
+                },
+                "dedupe_key": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string",
+                    "example": "0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42"
+                },
+                "event_name": {
+                    "type": "string",
+                    "example": "purchase"
+                },
+                "event_time": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1042
+                },
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "received_at": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tenant_id": {
+                    "type": "string",
+                    "example": "team-a"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "fiber.StructuredQueryRequest": {
+            "type": "object",
+            "properties": {
+                "aggregations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.QueryAggregateRequest"
+                    }
+                },
+                "event_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "purchase",
+                        "refund"
+                    ]
+                },
+                "filters": {
+                    "$ref": "#/definitions/fiber.QueryFiltersRequest"
+                },
+                "from": {
+                    "type": "integer",
+                    "example": 1765065600
+                },
+                "group_by": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.QueryGroupByRequest"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "options": {
+                    "$ref": "#/definitions/fiber.QueryOptionsRequest"
+                },
+                "order": {
+                    "type": "string",
+                    "example": "desc"
+                },
+                "order_by": {
+                    "type": "string",
+                    "example": "total_count"
+                },
+                "to": {
+                    "type": "integer",
+                    "example": 1765151999
+                }
+            }
+        },
+        "fiber.SubscriptionListResponse": {
+            "type": "object",
+            "properties": {
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.SubscriptionResponse"
+                    }
+                }
+            }
+        },
+        "fiber.SubscriptionRequest": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "defaults to true",
+                    "type": "boolean",
+                    "example": true
+                },
+                "channels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "web"
+                    ]
+                },
+                "event_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "purchase"
+                    ]
+                },
+                "secret": {
+                    "description": "generated on create, kept on update when omitted",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://hooks.example.com/events"
+                }
+            }
+        },
+        "fiber.SubscriptionResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "channels": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "event_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
+                },
+                "id": {
+                    "type": "string",
+                    "example": "5f1c2d3e-0a9b-4c8d-b7e6-1f2a3b4c5d6e"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://hooks.example.com/events"
                 }
             }
         },
-        "fiber.CreateEventRequest": {
-            "description": "Event creation DTO",
+        "fiber.TenantResponse": {
             "type": "object",
             "properties": {
-                "campaign_id": {
+                "created_at": {
                     "type": "string"
                 },
-                "channel": {
+                "id": {
+                    "type": "string",
+                    "example": "acme"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Acme Corp"
+                },
+                "retention_days": {
+                    "type": "integer",
+                    "example": 90
+                }
+            }
+        },
+        "fiber.WarmUpStatusResponse": {
+            "type": "object",
+            "properties": {
+                "finished_at": {
                     "type": "string"
                 },
-                "event_name": {
+                "started_at": {
                     "type": "string"
                 },
-                "metadata": {
-                    "type": "object",
-                    "additionalProperties": {}
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "warming",
+                        "ready"
+                    ],
+                    "example": "ready"
                 },
-                "tags": {
+                "tasks": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/fiber.WarmUpTaskResponse"
                     }
-                },
-                "timestamp": {
-                    "type": "integer"
-                },
-                "user_id": {
-                    "type": "string"
                 }
             }
         },
-        "fiber.CreateEventResponse": {
+        "fiber.WarmUpTaskResponse": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string"
+                "duration_ms": {
+                    "type": "number",
+                    "example": 182.4
                 },
-                "status": {
+                "error": {
                     "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "metrics:purchase"
                 }
             }
         },
-        "fiber.MetricsGroupResponse": {
+        "fiber.WebSocketAck": {
+            "description": "WebSocket event ack",
             "type": "object",
             "properties": {
-                "key": {
+                "error": {
+                    "type": "string",
+                    "example": "rate_limited"
+                },
+                "event_id": {
                     "type": "string"
                 },
-                "total_count": {
-                    "type": "integer"
+                "message": {
+                    "type": "string"
                 },
-                "unique_users": {
+                "ref": {
+                    "type": "string",
+                    "example": "42"
+                },
+                "retry_after_ms": {
                     "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "created",
+                        "duplicate",
+                        "invalid",
+                        "accepted",
+                        "sampled",
+                        "rejected",
+                        "error"
+                    ],
+                    "example": "created"
                 }
             }
         },
-        "fiber.MetricsResponse": {
+        "fiber.WebSocketEvent": {
+            "description": "WebSocket event message",
             "type": "object",
             "properties": {
-                "event_name": {
+                "anonymous_id": {
+                    "type": "string",
+                    "example": "3f9c2a7e-anon"
+                },
+                "campaign_id": {
                     "type": "string"
                 },
-                "from": {
-                    "type": "integer"
+                "channel": {
+                    "type": "string"
                 },
-                "group_by": {
+                "event_id": {
+                    "type": "string",
+                    "example": "0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42"
+                },
+                "event_name": {
                     "type": "string"
                 },
-                "groups": {
+                "metadata": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "ref": {
+                    "type": "string",
+                    "example": "42"
+                },
+                "tags": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/fiber.MetricsGroupResponse"
+                        "type": "string"
                     }
                 },
-                "to": {
-                    "type": "integer"
+                "timestamp": {
+                    "type": "integer",
+                    "example": 1733572800
                 },
-                "total_count": {
-                    "type": "integer"
+                "timestamp_ms": {
+                    "type": "integer",
+                    "example": 1733572800123
                 },
-                "unique_users": {
-                    "type": "integer"
+                "user_id": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number",
+                    "example": 42.5
                 }
             }
         },
         "fiber.bulkEventItem": {
             "type": "object",
             "properties": {
+                "anonymous_id": {
+                    "type": "string",
+                    "example": "3f9c2a7e-anon"
+                },
                 "campaign_id": {
                     "type": "string"
                 },
                 "channel": {
                     "type": "string"
                 },
+                "event_id": {
+                    "type": "string"
+                },
                 "event_name": {
                     "type": "string"
                 },
@@ -307,16 +6740,89 @@ const docTemplate = `{
                     }
                 },
                 "timestamp": {
-                    "type": "integer"
+                    "type": "integer",
+                    "example": 1733572800
+                },
+                "timestamp_ms": {
+                    "type": "integer",
+                    "example": 1733572800123
                 },
                 "user_id": {
                     "type": "string"
+                },
+                "value": {
+                    "type": "number",
+                    "example": 42.5
+                }
+            }
+        },
+        "fiber.ga4UserProperty": {
+            "type": "object",
+            "properties": {
+                "value": {}
+            }
+        },
+        "internal_alert_adapters_http_fiber.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "invalid_alert_rule"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "invalid alert rule: operator must be \u003c, \u003c=, \u003e or \u003e="
+                }
+            }
+        },
+        "internal_archive_adapters_http_fiber.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "invalid_range"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "invalid archive range: from must not be after to"
+                }
+            }
+        },
+        "internal_audit_adapters_http_fiber.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "invalid_query"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "invalid audit query: limit must be between 1 and 1000"
+                }
+            }
+        },
+        "internal_chaos_adapters_http_fiber.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "invalid_fault"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "error_rate must be between 0 and 1"
                 }
             }
         },
         "internal_events_adapters_http_fiber.ErrorResponse": {
             "type": "object",
             "properties": {
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/fiber.ErrorDetail"
+                    }
+                },
                 "error": {
                     "type": "string",
                     "example": "invalid_event"
@@ -327,6 +6833,45 @@ const docTemplate = `{
                 }
             }
         },
+        "internal_heartbeat_adapters_http_fiber.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "invalid_heartbeat"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "producer, event_name and interval_seconds \u003e= 10 are required"
+                }
+            }
+        },
+        "internal_identity_adapters_http_fiber.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "invalid_identity"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "anonymous_id and user_id are required"
+                }
+            }
+        },
+        "internal_journal_adapters_http_fiber.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "journal_entry_not_found"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "no journal entry for this request id"
+                }
+            }
+        },
         "internal_metrics_adapters_http_fiber.ErrorResponse": {
             "type": "object",
             "properties": {
@@ -339,6 +6884,109 @@ const docTemplate = `{
                     "example": "Event payload is invalid"
                 }
             }
+        },
+        "internal_privacy_adapters_http_fiber.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "invalid_user_id"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "user_id is required"
+                }
+            }
+        },
+        "internal_quota_adapters_http_fiber.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "internal_server_error"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_report_adapters_http_fiber.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "invalid_report"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "invalid report: query must have an event_name"
+                }
+            }
+        },
+        "internal_schema_adapters_http_fiber.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "invalid_schema"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "invalid schema: jsonschema validation failed with 'https://json-schema.org/draft/2020-12/schema#'"
+                }
+            }
+        },
+        "internal_session_adapters_http_fiber.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "invalid_session_query"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "invalid session query: gap must be between 1m0s and 24h0m0s"
+                }
+            }
+        },
+        "internal_tenant_adapters_http_fiber.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "invalid_tenant"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "invalid tenant: duplicate channel \"web\""
+                }
+            }
+        },
+        "internal_warmup_adapters_http_fiber.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "warmup_running"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "warm-up already running"
+                }
+            }
+        },
+        "internal_webhook_adapters_http_fiber.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "invalid_subscription"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "invalid subscription: url must be an absolute http or https URL"
+                }
+            }
         }
     }
 }`
@@ -0,0 +1,44 @@
+package fiber
+
+import "time"
+
+// RuleRequest creates or replaces an alert rule.
+type RuleRequest struct {
+	Name      string  `json:"name" example:"signups stalled"`
+	TenantID  string  `json:"tenant_id,omitempty"` // the tenant the query runs as (TENANT_ISOLATION)
+	Query     string  `json:"query" example:"event_name=signup&range=1h"`
+	Measure   string  `json:"measure" example:"total_count"` // total_count, unique_users or value
+	Operator  string  `json:"operator" example:"<"`
+	Threshold float64 `json:"threshold" example:"100"`
+	NotifyURL string  `json:"notify_url" example:"https://hooks.slack.com/services/T000/B000/XXXX"`
+	Active    *bool   `json:"active,omitempty" example:"true"` // defaults to true
+}
+
+// RuleResponse is a rule and the outcome of its last evaluation.
+type RuleResponse struct {
+	ID          string     `json:"id" example:"5f1c2d3e-0a9b-4c8d-b7e6-1f2a3b4c5d6e"`
+	Name        string     `json:"name" example:"signups stalled"`
+	TenantID    string     `json:"tenant_id,omitempty"`
+	Query       string     `json:"query" example:"event_name=signup&range=1h"`
+	Measure     string     `json:"measure" example:"total_count"`
+	Operator    string     `json:"operator" example:"<"`
+	Threshold   float64    `json:"threshold" example:"100"`
+	NotifyURL   string     `json:"notify_url"`
+	Active      bool       `json:"active"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Status      string     `json:"status" example:"firing"`
+	StatusSince *time.Time `json:"status_since,omitempty"`
+	Value       *float64   `json:"value,omitempty" example:"12"`
+	EvaluatedAt *time.Time `json:"evaluated_at,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+}
+
+type RuleListResponse struct {
+	Rules []RuleResponse `json:"rules"`
+}
+
+type ErrorResponse struct {
+	Error   string `json:"error" example:"invalid_alert_rule"`
+	Message string `json:"message" example:"invalid alert rule: operator must be <, <=, > or >="`
+}
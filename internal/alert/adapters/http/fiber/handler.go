@@ -0,0 +1,209 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"event-metrics-service/internal/alert/core/domain"
+	"event-metrics-service/internal/alert/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RuleUseCase interface {
+	Create(ctx context.Context, in usecase.RuleInput) (domain.Rule, error)
+	Update(ctx context.Context, id string, in usecase.RuleInput) (domain.Rule, error)
+	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id string) (domain.Rule, error)
+	List(ctx context.Context) ([]domain.Rule, error)
+}
+
+type AlertHandler struct {
+	uc RuleUseCase
+}
+
+func NewAlertHandler(uc RuleUseCase) *AlertHandler {
+	return &AlertHandler{uc: uc}
+}
+
+// CreateRule godoc
+// @Summary Create an alert rule
+// @Description The rule fires when measure operator threshold holds for its query, a saved metrics query whose range= (default 24h) is the window evaluated up to now, e.g. total_count < 100 for event_name=signup&range=1h.
+// @Description Every change between ok and firing is POSTed as JSON to notify_url; its text field suits Slack incoming webhooks.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param request body RuleRequest true "Rule"
+// @Success 201 {object} RuleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/alerts [post]
+func (h *AlertHandler) CreateRule(c *fiber.Ctx) error {
+	var req RuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid_json",
+		})
+	}
+
+	r, err := h.uc.Create(c.UserContext(), toRuleInput(req))
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(toRuleResponse(r))
+}
+
+// UpdateRule godoc
+// @Summary Replace an alert rule
+// @Description The status is kept: a firing rule resolves, and notifies so, once the new condition no longer holds.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param id path string true "Rule ID"
+// @Param request body RuleRequest true "Rule"
+// @Success 200 {object} RuleResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/alerts/{id} [put]
+func (h *AlertHandler) UpdateRule(c *fiber.Ctx) error {
+	var req RuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid_json",
+		})
+	}
+
+	r, err := h.uc.Update(c.UserContext(), c.Params("id"), toRuleInput(req))
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.JSON(toRuleResponse(r))
+}
+
+// GetRule godoc
+// @Summary Get an alert rule and its state
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param id path string true "Rule ID"
+// @Success 200 {object} RuleResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/alerts/{id} [get]
+func (h *AlertHandler) GetRule(c *fiber.Ctx) error {
+	r, err := h.uc.Get(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.JSON(toRuleResponse(r))
+}
+
+// ListRules godoc
+// @Summary List alert rules and their state
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} RuleListResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/alerts [get]
+func (h *AlertHandler) ListRules(c *fiber.Ctx) error {
+	rules, err := h.uc.List(c.UserContext())
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	resp := RuleListResponse{Rules: make([]RuleResponse, 0, len(rules))}
+	for _, r := range rules {
+		resp.Rules = append(resp.Rules, toRuleResponse(r))
+	}
+
+	return c.JSON(resp)
+}
+
+// DeleteRule godoc
+// @Summary Delete an alert rule
+// @Tags Admin
+// @Param X-Admin-Token header string true "Admin token"
+// @Param id path string true "Rule ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/alerts/{id} [delete]
+func (h *AlertHandler) DeleteRule(c *fiber.Ctx) error {
+	if err := h.uc.Delete(c.UserContext(), c.Params("id")); err != nil {
+		return writeError(c, err)
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+func writeError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidRule):
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error:   "invalid_alert_rule",
+			Message: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrRuleNotFound):
+		return c.Status(http.StatusNotFound).JSON(ErrorResponse{
+			Error:   "alert_rule_not_found",
+			Message: err.Error(),
+		})
+	}
+	return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+		Error: "internal_server_error",
+	})
+}
+
+func toRuleInput(req RuleRequest) usecase.RuleInput {
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+	return usecase.RuleInput{
+		Name:      req.Name,
+		TenantID:  req.TenantID,
+		Query:     req.Query,
+		Measure:   req.Measure,
+		Operator:  req.Operator,
+		Threshold: req.Threshold,
+		NotifyURL: req.NotifyURL,
+		Active:    active,
+	}
+}
+
+func toRuleResponse(r domain.Rule) RuleResponse {
+	return RuleResponse{
+		ID:          r.ID,
+		Name:        r.Name,
+		TenantID:    r.TenantID,
+		Query:       r.Query,
+		Measure:     r.Measure,
+		Operator:    r.Operator,
+		Threshold:   r.Threshold,
+		NotifyURL:   r.NotifyURL,
+		Active:      r.Active,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+		Status:      string(r.State.Status),
+		StatusSince: timeOrNil(r.State.Since),
+		Value:       r.State.Value,
+		EvaluatedAt: timeOrNil(r.State.EvaluatedAt),
+		LastError:   r.State.Error,
+	}
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,132 @@
+package fiber_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpadapter "event-metrics-service/internal/alert/adapters/http/fiber"
+	"event-metrics-service/internal/alert/core/domain"
+	"event-metrics-service/internal/alert/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeRuleUseCase struct {
+	CreateFn func(ctx context.Context, in usecase.RuleInput) (domain.Rule, error)
+	UpdateFn func(ctx context.Context, id string, in usecase.RuleInput) (domain.Rule, error)
+	DeleteFn func(ctx context.Context, id string) error
+	GetFn    func(ctx context.Context, id string) (domain.Rule, error)
+	ListFn   func(ctx context.Context) ([]domain.Rule, error)
+}
+
+func (f *fakeRuleUseCase) Create(ctx context.Context, in usecase.RuleInput) (domain.Rule, error) {
+	return f.CreateFn(ctx, in)
+}
+
+func (f *fakeRuleUseCase) Update(ctx context.Context, id string, in usecase.RuleInput) (domain.Rule, error) {
+	return f.UpdateFn(ctx, id, in)
+}
+
+func (f *fakeRuleUseCase) Delete(ctx context.Context, id string) error {
+	return f.DeleteFn(ctx, id)
+}
+
+func (f *fakeRuleUseCase) Get(ctx context.Context, id string) (domain.Rule, error) {
+	return f.GetFn(ctx, id)
+}
+
+func (f *fakeRuleUseCase) List(ctx context.Context) ([]domain.Rule, error) {
+	return f.ListFn(ctx)
+}
+
+func setupApp(uc httpadapter.RuleUseCase) *fiber.App {
+	app := fiber.New()
+	h := httpadapter.NewAlertHandler(uc)
+	app.Get("/admin/alerts", h.ListRules)
+	app.Post("/admin/alerts", h.CreateRule)
+	app.Get("/admin/alerts/:id", h.GetRule)
+	app.Put("/admin/alerts/:id", h.UpdateRule)
+	app.Delete("/admin/alerts/:id", h.DeleteRule)
+	return app
+}
+
+func doRequest(t *testing.T, app *fiber.App, method, path, body string) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	return resp
+}
+
+func TestCreateRule_DefaultsToActive(t *testing.T) {
+	var got usecase.RuleInput
+	uc := &fakeRuleUseCase{
+		CreateFn: func(ctx context.Context, in usecase.RuleInput) (domain.Rule, error) {
+			got = in
+			return domain.Rule{ID: "r1", State: domain.State{Status: domain.StatusOK}}, nil
+		},
+	}
+
+	resp := doRequest(t, setupApp(uc), http.MethodPost, "/admin/alerts",
+		`{"name":"signups stalled","query":"event_name=signup&range=1h","measure":"total_count","operator":"<","threshold":100,"notify_url":"https://hooks.example.com/alerts"}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	if !got.Active || got.Operator != "<" || got.Threshold != 100 {
+		t.Fatalf("unexpected input: %+v", got)
+	}
+
+	var body map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if _, ok := body["evaluated_at"]; ok || body["status"] != "ok" {
+		t.Fatalf("expected an unevaluated rule, got %v", body)
+	}
+}
+
+func TestGetRule_State(t *testing.T) {
+	value := 12.0
+	since := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	uc := &fakeRuleUseCase{
+		GetFn: func(ctx context.Context, id string) (domain.Rule, error) {
+			return domain.Rule{ID: id, State: domain.State{Status: domain.StatusFiring, Since: since, Value: &value, EvaluatedAt: since}}, nil
+		},
+	}
+
+	resp := doRequest(t, setupApp(uc), http.MethodGet, "/admin/alerts/r1", "")
+	var body httpadapter.RuleResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if body.Status != "firing" || *body.Value != 12 || !body.StatusSince.Equal(since) {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+}
+
+func TestRule_Errors(t *testing.T) {
+	uc := &fakeRuleUseCase{
+		UpdateFn: func(ctx context.Context, id string, in usecase.RuleInput) (domain.Rule, error) {
+			return domain.Rule{}, fmt.Errorf("%w: operator must be <, <=, > or >=", usecase.ErrInvalidRule)
+		},
+		DeleteFn: func(ctx context.Context, id string) error {
+			return usecase.ErrRuleNotFound
+		},
+	}
+
+	if resp := doRequest(t, setupApp(uc), http.MethodPut, "/admin/alerts/r1", `{"operator":"=="}`); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+	if resp := doRequest(t, setupApp(uc), http.MethodDelete, "/admin/alerts/r1", ""); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"event-metrics-service/internal/alert/core/domain"
+	"event-metrics-service/internal/alert/core/ports"
+)
+
+type RowScanner interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+type DB interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+type AlertRepository struct {
+	db DB
+}
+
+func NewAlertRepository(db DB) *AlertRepository {
+	return &AlertRepository{db: db}
+}
+
+var _ ports.RuleRepositoryPort = (*AlertRepository)(nil)
+
+const insertRuleSQL = `
+INSERT INTO alert_rules (id, name, tenant_id, query, measure, operator, threshold, notify_url, active, created_at, updated_at, status)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+
+const updateRuleSQL = `
+UPDATE alert_rules
+SET name = $2, tenant_id = $3, query = $4, measure = $5, operator = $6, threshold = $7, notify_url = $8, active = $9, updated_at = $10
+WHERE id = $1`
+
+const deleteRuleSQL = `
+DELETE FROM alert_rules WHERE id = $1`
+
+const selectRulesSQL = `
+SELECT id, name, tenant_id, query, measure, operator, threshold, notify_url, active, created_at, updated_at,
+       status, status_since, value, evaluated_at, last_error
+FROM alert_rules`
+
+const saveStateSQL = `
+UPDATE alert_rules
+SET status = $3, status_since = $4, value = $5, evaluated_at = $6, last_error = $7
+WHERE id = $1 AND status = $2`
+
+func (r *AlertRepository) CreateRule(ctx context.Context, rule domain.Rule) error {
+	_, err := r.db.ExecContext(ctx, insertRuleSQL,
+		rule.ID, rule.Name, rule.TenantID, rule.Query, rule.Measure, rule.Operator, rule.Threshold, rule.NotifyURL,
+		rule.Active, rule.CreatedAt, rule.UpdatedAt, string(rule.State.Status))
+	return err
+}
+
+func (r *AlertRepository) UpdateRule(ctx context.Context, rule domain.Rule) (bool, error) {
+	res, err := r.db.ExecContext(ctx, updateRuleSQL,
+		rule.ID, rule.Name, rule.TenantID, rule.Query, rule.Measure, rule.Operator, rule.Threshold, rule.NotifyURL,
+		rule.Active, rule.UpdatedAt)
+	return affected(res, err)
+}
+
+func (r *AlertRepository) DeleteRule(ctx context.Context, id string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, deleteRuleSQL, id)
+	return affected(res, err)
+}
+
+func (r *AlertRepository) GetRule(ctx context.Context, id string) (domain.Rule, bool, error) {
+	rules, err := r.queryRules(ctx, selectRulesSQL+"\nWHERE id = $1", id)
+	if err != nil || len(rules) == 0 {
+		return domain.Rule{}, false, err
+	}
+	return rules[0], true, nil
+}
+
+func (r *AlertRepository) ListRules(ctx context.Context) ([]domain.Rule, error) {
+	return r.queryRules(ctx, selectRulesSQL+"\nORDER BY created_at, id")
+}
+
+func (r *AlertRepository) SaveState(ctx context.Context, id string, from domain.Status, s domain.State) (bool, error) {
+	res, err := r.db.ExecContext(ctx, saveStateSQL,
+		id, string(from), string(s.Status), nullTime(s.Since), s.Value, nullTime(s.EvaluatedAt), s.Error)
+	return affected(res, err)
+}
+
+func (r *AlertRepository) queryRules(ctx context.Context, query string, args ...any) ([]domain.Rule, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Rule
+	for rows.Next() {
+		var (
+			rule               domain.Rule
+			status             string
+			since, evaluatedAt sql.NullTime
+			value              sql.NullFloat64
+		)
+		if err := rows.Scan(&rule.ID, &rule.Name, &rule.TenantID, &rule.Query, &rule.Measure, &rule.Operator,
+			&rule.Threshold, &rule.NotifyURL, &rule.Active, &rule.CreatedAt, &rule.UpdatedAt,
+			&status, &since, &value, &evaluatedAt, &rule.State.Error); err != nil {
+			return nil, err
+		}
+		rule.CreatedAt, rule.UpdatedAt = rule.CreatedAt.UTC(), rule.UpdatedAt.UTC()
+		rule.State.Status = domain.Status(status)
+		if since.Valid {
+			rule.State.Since = since.Time.UTC()
+		}
+		if evaluatedAt.Valid {
+			rule.State.EvaluatedAt = evaluatedAt.Time.UTC()
+		}
+		if value.Valid {
+			v := value.Float64
+			rule.State.Value = &v
+		}
+		out = append(out, rule)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// nullTime stores the zero time as NULL.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// affected reports whether a statement changed a row.
+func affected(res sql.Result, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/alert/core/domain"
+)
+
+type fakeRowScanner struct {
+	rows [][]any
+	i    int
+}
+
+func (f *fakeRowScanner) Next() bool {
+	return f.i < len(f.rows)
+}
+
+func (f *fakeRowScanner) Scan(dest ...any) error {
+	row := f.rows[f.i]
+	if len(dest) != len(row) {
+		return errors.New("dest length mismatch")
+	}
+	for i := range dest {
+		switch d := dest[i].(type) {
+		case *string:
+			*d = row[i].(string)
+		case *bool:
+			*d = row[i].(bool)
+		case *float64:
+			*d = row[i].(float64)
+		case *time.Time:
+			*d = row[i].(time.Time)
+		case *sql.NullTime:
+			*d = row[i].(sql.NullTime)
+		case *sql.NullFloat64:
+			*d = row[i].(sql.NullFloat64)
+		default:
+			return errors.New("unsupported dest type")
+		}
+	}
+	f.i++
+	return nil
+}
+
+func (f *fakeRowScanner) Err() error   { return nil }
+func (f *fakeRowScanner) Close() error { return nil }
+
+type fakeResult struct{ n int64 }
+
+func (r fakeResult) LastInsertId() (int64, error) { return 0, nil }
+func (r fakeResult) RowsAffected() (int64, error) { return r.n, nil }
+
+type fakeDB struct {
+	ExecFn  func(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryFn func(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+func (f *fakeDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return f.ExecFn(ctx, query, args...)
+}
+
+func (f *fakeDB) QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error) {
+	return f.QueryFn(ctx, query, args...)
+}
+
+func TestAlertRepository_SaveStateComparesStatus(t *testing.T) {
+	var gotArgs []any
+	db := &fakeDB{
+		ExecFn: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+			if !strings.Contains(query, "WHERE id = $1 AND status = $2") {
+				t.Fatalf("unexpected query: %s", query)
+			}
+			gotArgs = args
+			return fakeResult{n: 0}, nil
+		},
+	}
+
+	saved, err := NewAlertRepository(db).SaveState(context.Background(), "r1", domain.StatusOK, domain.State{Status: domain.StatusFiring})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if saved {
+		t.Fatalf("expected saved=false")
+	}
+	if gotArgs[1] != "ok" || gotArgs[2] != "firing" || gotArgs[3].(sql.NullTime).Valid {
+		t.Fatalf("unexpected args: %v", gotArgs)
+	}
+}
+
+func TestAlertRepository_Get(t *testing.T) {
+	created := time.Date(2025, 12, 7, 10, 0, 0, 0, time.FixedZone("TRT", 3*3600))
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return &fakeRowScanner{rows: [][]any{{
+				"r1", "signups stalled", "", "event_name=signup&range=1h", "total_count", "<", 100.0,
+				"https://hooks.example.com/alerts", true, created, created,
+				"firing", sql.NullTime{Time: created, Valid: true}, sql.NullFloat64{Float64: 12, Valid: true}, sql.NullTime{}, "",
+			}}}, nil
+		},
+	}
+
+	r, found, err := NewAlertRepository(db).GetRule(context.Background(), "r1")
+	if err != nil || !found {
+		t.Fatalf("expected rule, got found=%v err=%v", found, err)
+	}
+	if r.State.Status != domain.StatusFiring || *r.State.Value != 12 || r.State.Since.Location() != time.UTC || !r.State.EvaluatedAt.IsZero() {
+		t.Fatalf("unexpected state: %+v", r.State)
+	}
+}
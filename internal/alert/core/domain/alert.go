@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Measures a rule compares with its threshold, named like the GET /metrics
+// response fields.
+const (
+	MeasureTotalCount  = "total_count"
+	MeasureUniqueUsers = "unique_users"
+	MeasureValue       = "value" // needs aggregate= in the query
+)
+
+const (
+	OperatorLess         = "<"
+	OperatorLessEqual    = "<="
+	OperatorGreater      = ">"
+	OperatorGreaterEqual = ">="
+)
+
+type Status string
+
+const (
+	StatusOK     Status = "ok"
+	StatusFiring Status = "firing"
+)
+
+// Rule fires when a measure of its metrics query crosses the threshold,
+// e.g. total_count < 100 over the last hour.
+type Rule struct {
+	ID       string
+	Name     string
+	TenantID string // the tenant the query runs as; "" without tenant isolation
+	// Query is a saved metrics query (event_name=signup&range=1h); its
+	// range is the window evaluated up to now.
+	Query     string
+	Measure   string
+	Operator  string
+	Threshold float64
+	NotifyURL string // receives a POST on every change of status
+	Active    bool   // inactive rules are not evaluated
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	State State
+}
+
+// Holds reports whether value meets the condition. A missing value, e.g. an
+// aggregate over no events, never does.
+func (r Rule) Holds(value *float64) bool {
+	if value == nil {
+		return false
+	}
+	switch r.Operator {
+	case OperatorLess:
+		return *value < r.Threshold
+	case OperatorLessEqual:
+		return *value <= r.Threshold
+	case OperatorGreater:
+		return *value > r.Threshold
+	case OperatorGreaterEqual:
+		return *value >= r.Threshold
+	}
+	return false
+}
+
+// Condition is the rule as text, e.g. "total_count < 100".
+func (r Rule) Condition() string {
+	return fmt.Sprintf("%s %s %s", r.Measure, r.Operator, strconv.FormatFloat(r.Threshold, 'f', -1, 64))
+}
+
+// State is the outcome of the last evaluation of a rule.
+type State struct {
+	Status      Status
+	Since       time.Time // when Status was entered; zero before the first change
+	Value       *float64  // the measure at the last evaluation
+	EvaluatedAt time.Time // zero before the first evaluation
+	Error       string    // why the last evaluation or notification failed
+}
+
+// Measurement is what a metrics query of a rule returned.
+type Measurement struct {
+	TotalCount  int64
+	UniqueUsers int64
+	Value       *float64
+}
+
+// Of returns the named measure.
+func (m Measurement) Of(measure string) *float64 {
+	var v float64
+	switch measure {
+	case MeasureTotalCount:
+		v = float64(m.TotalCount)
+	case MeasureUniqueUsers:
+		v = float64(m.UniqueUsers)
+	case MeasureValue:
+		return m.Value
+	default:
+		return nil
+	}
+	return &v
+}
@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"event-metrics-service/internal/alert/core/domain"
+)
+
+type RuleRepositoryPort interface {
+	CreateRule(ctx context.Context, r domain.Rule) error
+	// UpdateRule replaces the definition of the rule with r.ID, keeping its
+	// state; found is false when there is none.
+	UpdateRule(ctx context.Context, r domain.Rule) (found bool, err error)
+	DeleteRule(ctx context.Context, id string) (found bool, err error)
+	GetRule(ctx context.Context, id string) (r domain.Rule, found bool, err error)
+	ListRules(ctx context.Context) ([]domain.Rule, error)
+	// SaveState replaces the state of a rule if its status is still from,
+	// so of several instances evaluating the rule only one sees a change of
+	// status and notifies it. saved is false when another got there first.
+	SaveState(ctx context.Context, id string, from domain.Status, s domain.State) (saved bool, err error)
+}
+
+// MeasurePort runs the metrics queries of rules.
+type MeasurePort interface {
+	// Window checks a rule query and returns the time range it covers.
+	Window(query string) (time.Duration, error)
+	// Measure runs query over the window ending at to, as the tenant.
+	Measure(ctx context.Context, tenantID, query string, to time.Time) (domain.Measurement, error)
+}
+
+// SenderPort POSTs a payload. A non-2xx response is an error.
+type SenderPort interface {
+	Send(ctx context.Context, url string, headers map[string]string, payload []byte) error
+}
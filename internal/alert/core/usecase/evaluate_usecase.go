@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"event-metrics-service/internal/alert/core/domain"
+	"event-metrics-service/internal/alert/core/ports"
+)
+
+// Notification statuses; a rule going back to ok is "resolved".
+const (
+	NotifyFiring   = "firing"
+	NotifyResolved = "resolved"
+)
+
+// EvaluateUseCase evaluates the active rules one after another and notifies
+// every change of status. A failed notification puts the rule back in its
+// previous status, so the next evaluation notifies again.
+type EvaluateUseCase struct {
+	rules   ports.RuleRepositoryPort
+	measure ports.MeasurePort
+	sender  ports.SenderPort
+	now     func() time.Time
+}
+
+func NewEvaluateUseCase(rules ports.RuleRepositoryPort, measure ports.MeasurePort, sender ports.SenderPort) *EvaluateUseCase {
+	return &EvaluateUseCase{rules: rules, measure: measure, sender: sender, now: time.Now}
+}
+
+type EvaluationReport struct {
+	Evaluated int
+	Fired     int
+	Resolved  int
+}
+
+// notification is the body POSTed to the notify URL of a rule.
+type notification struct {
+	// Text is a one-line summary, which Slack and compatible incoming
+	// webhooks show as the message.
+	Text      string    `json:"text"`
+	RuleID    string    `json:"rule_id"`
+	Name      string    `json:"name"`
+	TenantID  string    `json:"tenant_id,omitempty"`
+	Status    string    `json:"status"`
+	Condition string    `json:"condition"`
+	Value     *float64  `json:"value"`
+	Query     string    `json:"query"`
+	At        time.Time `json:"at"`
+}
+
+func (uc *EvaluateUseCase) Evaluate(ctx context.Context) (EvaluationReport, error) {
+	var report EvaluationReport
+	rules, err := uc.rules.ListRules(ctx)
+	if err != nil {
+		return report, fmt.Errorf("list alert rules: %w", err)
+	}
+
+	var errs []error
+	for _, r := range rules {
+		if !r.Active {
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		status, err := uc.evaluate(ctx, r)
+		report.Evaluated++
+		switch status {
+		case domain.StatusFiring:
+			report.Fired++
+		case domain.StatusOK:
+			report.Resolved++
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return report, errors.Join(errs...)
+}
+
+// evaluate returns the status the rule changed to and notified, or "".
+func (uc *EvaluateUseCase) evaluate(ctx context.Context, r domain.Rule) (domain.Status, error) {
+	now := uc.now().UTC()
+	next := r.State
+	next.EvaluatedAt = now
+
+	m, err := uc.measure.Measure(ctx, r.TenantID, r.Query, now)
+	if err != nil {
+		next.Error = err.Error()
+		_, saveErr := uc.rules.SaveState(ctx, r.ID, r.State.Status, next)
+		return "", errors.Join(fmt.Errorf("alert rule %s: %w", r.ID, err), saveErr)
+	}
+
+	next.Value, next.Error = m.Of(r.Measure), ""
+	status := domain.StatusOK
+	if r.Holds(next.Value) {
+		status = domain.StatusFiring
+	}
+	if status != r.State.Status {
+		next.Status, next.Since = status, now
+	}
+
+	saved, err := uc.rules.SaveState(ctx, r.ID, r.State.Status, next)
+	if err != nil || !saved || status == r.State.Status {
+		return "", err
+	}
+
+	if err := uc.notify(ctx, r, next); err != nil {
+		prev := r.State
+		prev.EvaluatedAt, prev.Value, prev.Error = now, next.Value, "notify: "+err.Error()
+		_, saveErr := uc.rules.SaveState(ctx, r.ID, status, prev)
+		return "", errors.Join(fmt.Errorf("alert rule %s: notify: %w", r.ID, err), saveErr)
+	}
+	return status, nil
+}
+
+func (uc *EvaluateUseCase) notify(ctx context.Context, r domain.Rule, s domain.State) error {
+	status := NotifyResolved
+	if s.Status == domain.StatusFiring {
+		status = NotifyFiring
+	}
+	value := "no value"
+	if s.Value != nil {
+		value = strconv.FormatFloat(*s.Value, 'f', -1, 64)
+	}
+
+	payload, err := json.Marshal(notification{
+		Text:      fmt.Sprintf("[%s] %s: %s (now %s)", status, r.Name, r.Condition(), value),
+		RuleID:    r.ID,
+		Name:      r.Name,
+		TenantID:  r.TenantID,
+		Status:    status,
+		Condition: r.Condition(),
+		Value:     s.Value,
+		Query:     r.Query,
+		At:        s.EvaluatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	return uc.sender.Send(ctx, r.NotifyURL, nil, payload)
+}
+
+// Run evaluates on every tick until ctx is cancelled.
+func (uc *EvaluateUseCase) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := uc.Evaluate(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/alert/core/domain"
+	"event-metrics-service/internal/alert/core/usecase"
+)
+
+// memRules is an in-memory RuleRepositoryPort.
+type memRules struct {
+	rules []domain.Rule
+	// lose makes SaveState fail the compare-and-set, as if another instance
+	// had changed the status first.
+	lose bool
+}
+
+func (m *memRules) CreateRule(ctx context.Context, r domain.Rule) error {
+	m.rules = append(m.rules, r)
+	return nil
+}
+
+func (m *memRules) UpdateRule(ctx context.Context, r domain.Rule) (bool, error) {
+	for i := range m.rules {
+		if m.rules[i].ID == r.ID {
+			m.rules[i] = r
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memRules) DeleteRule(ctx context.Context, id string) (bool, error) {
+	for i := range m.rules {
+		if m.rules[i].ID == id {
+			m.rules = append(m.rules[:i], m.rules[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memRules) GetRule(ctx context.Context, id string) (domain.Rule, bool, error) {
+	for _, r := range m.rules {
+		if r.ID == id {
+			return r, true, nil
+		}
+	}
+	return domain.Rule{}, false, nil
+}
+
+func (m *memRules) ListRules(ctx context.Context) ([]domain.Rule, error) {
+	return append([]domain.Rule(nil), m.rules...), nil
+}
+
+func (m *memRules) SaveState(ctx context.Context, id string, from domain.Status, s domain.State) (bool, error) {
+	for i := range m.rules {
+		if m.rules[i].ID == id && m.rules[i].State.Status == from && !m.lose {
+			m.rules[i].State = s
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fakeMeasure answers every query with a fixed measurement.
+type fakeMeasure struct {
+	m       domain.Measurement
+	err     error
+	tenants []string
+}
+
+func (f *fakeMeasure) Window(query string) (time.Duration, error) {
+	if !strings.Contains(query, "event_name=") {
+		return 0, errors.New("event_name is required")
+	}
+	return time.Hour, nil
+}
+
+func (f *fakeMeasure) Measure(ctx context.Context, tenantID, query string, to time.Time) (domain.Measurement, error) {
+	f.tenants = append(f.tenants, tenantID)
+	return f.m, f.err
+}
+
+type fakeSender struct {
+	payloads []map[string]any
+	err      error
+}
+
+func (f *fakeSender) Send(ctx context.Context, url string, headers map[string]string, payload []byte) error {
+	var body map[string]any
+	if err := json.Unmarshal(payload, &body); err != nil {
+		return err
+	}
+	f.payloads = append(f.payloads, body)
+	return f.err
+}
+
+func signupRule(status domain.Status) domain.Rule {
+	return domain.Rule{
+		ID:        "r1",
+		Name:      "signups stalled",
+		TenantID:  "acme",
+		Query:     "event_name=signup&range=1h",
+		Measure:   domain.MeasureTotalCount,
+		Operator:  domain.OperatorLess,
+		Threshold: 100,
+		NotifyURL: "https://hooks.example.com/alerts",
+		Active:    true,
+		State:     domain.State{Status: status},
+	}
+}
+
+func TestEvaluate_FiresOnceAndResolves(t *testing.T) {
+	repo := &memRules{rules: []domain.Rule{signupRule(domain.StatusOK)}}
+	measure := &fakeMeasure{m: domain.Measurement{TotalCount: 12}}
+	sender := &fakeSender{}
+	uc := usecase.NewEvaluateUseCase(repo, measure, sender)
+
+	for range 2 {
+		if _, err := uc.Evaluate(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(sender.payloads) != 1 || sender.payloads[0]["status"] != usecase.NotifyFiring {
+		t.Fatalf("expected one firing notification, got %v", sender.payloads)
+	}
+	if text := sender.payloads[0]["text"]; text != "[firing] signups stalled: total_count < 100 (now 12)" {
+		t.Fatalf("unexpected text: %v", text)
+	}
+	if s := repo.rules[0].State; s.Status != domain.StatusFiring || *s.Value != 12 || s.Since.IsZero() {
+		t.Fatalf("unexpected state: %+v", s)
+	}
+	if measure.tenants[0] != "acme" {
+		t.Fatalf("expected the query to run as the rule's tenant, got %v", measure.tenants)
+	}
+
+	measure.m.TotalCount = 250
+	report, err := uc.Evaluate(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Resolved != 1 || len(sender.payloads) != 2 || sender.payloads[1]["status"] != usecase.NotifyResolved {
+		t.Fatalf("expected a resolved notification, got %+v %v", report, sender.payloads)
+	}
+}
+
+func TestEvaluate_LostRaceDoesNotNotify(t *testing.T) {
+	repo := &memRules{rules: []domain.Rule{signupRule(domain.StatusOK)}, lose: true}
+	sender := &fakeSender{}
+	uc := usecase.NewEvaluateUseCase(repo, &fakeMeasure{m: domain.Measurement{TotalCount: 0}}, sender)
+
+	if _, err := uc.Evaluate(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sender.payloads) != 0 {
+		t.Fatalf("expected no notification, got %v", sender.payloads)
+	}
+}
+
+func TestEvaluate_FailedNotificationIsRetried(t *testing.T) {
+	repo := &memRules{rules: []domain.Rule{signupRule(domain.StatusOK)}}
+	sender := &fakeSender{err: errors.New("webhook: 503 Service Unavailable")}
+	uc := usecase.NewEvaluateUseCase(repo, &fakeMeasure{m: domain.Measurement{TotalCount: 0}}, sender)
+
+	if _, err := uc.Evaluate(context.Background()); err == nil {
+		t.Fatalf("expected the notification error")
+	}
+	if s := repo.rules[0].State; s.Status != domain.StatusOK || !strings.Contains(s.Error, "503") {
+		t.Fatalf("expected the rule back in ok with the error, got %+v", s)
+	}
+
+	sender.err = nil
+	if _, err := uc.Evaluate(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sender.payloads) != 2 || repo.rules[0].State.Status != domain.StatusFiring {
+		t.Fatalf("expected the notification to be sent again, got %v", sender.payloads)
+	}
+}
+
+func TestEvaluate_QueryErrorKeepsStatus(t *testing.T) {
+	rule := signupRule(domain.StatusFiring)
+	rule.Measure, rule.Operator = domain.MeasureValue, domain.OperatorGreater
+	inactive := signupRule(domain.StatusOK)
+	inactive.ID, inactive.Active = "r2", false
+	repo := &memRules{rules: []domain.Rule{rule, inactive}}
+	measure := &fakeMeasure{err: errors.New("statement timeout")}
+	uc := usecase.NewEvaluateUseCase(repo, measure, &fakeSender{})
+
+	report, err := uc.Evaluate(context.Background())
+	if err == nil || report.Evaluated != 1 {
+		t.Fatalf("expected one failed evaluation, got %+v %v", report, err)
+	}
+	if s := repo.rules[0].State; s.Status != domain.StatusFiring || s.Error != "statement timeout" || s.EvaluatedAt.IsZero() {
+		t.Fatalf("unexpected state: %+v", s)
+	}
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"event-metrics-service/internal/alert/core/domain"
+	"event-metrics-service/internal/alert/core/ports"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidRule  = errors.New("invalid alert rule")
+	ErrRuleNotFound = errors.New("alert rule not found")
+)
+
+const (
+	MaxNameLength = 100
+	MaxWindow     = 31 * 24 * time.Hour
+)
+
+type RuleUseCase struct {
+	repo    ports.RuleRepositoryPort
+	measure ports.MeasurePort
+	now     func() time.Time
+}
+
+func NewRuleUseCase(repo ports.RuleRepositoryPort, measure ports.MeasurePort) *RuleUseCase {
+	return &RuleUseCase{repo: repo, measure: measure, now: time.Now}
+}
+
+type RuleInput struct {
+	Name      string
+	TenantID  string
+	Query     string
+	Measure   string
+	Operator  string
+	Threshold float64
+	NotifyURL string
+	Active    bool
+}
+
+// Create stores a rule in the ok state; it fires from its first evaluation
+// on.
+func (uc *RuleUseCase) Create(ctx context.Context, in RuleInput) (domain.Rule, error) {
+	r, err := uc.buildRule(in)
+	if err != nil {
+		return domain.Rule{}, err
+	}
+
+	now := uc.now().UTC()
+	r.ID = uuid.NewString()
+	r.CreatedAt, r.UpdatedAt = now, now
+	r.State = domain.State{Status: domain.StatusOK}
+	if err := uc.repo.CreateRule(ctx, r); err != nil {
+		return domain.Rule{}, err
+	}
+	return r, nil
+}
+
+// Update replaces the definition of a rule. Its state is kept, so a firing
+// rule resolves, and notifies so, once the new condition no longer holds.
+func (uc *RuleUseCase) Update(ctx context.Context, id string, in RuleInput) (domain.Rule, error) {
+	r, err := uc.buildRule(in)
+	if err != nil {
+		return domain.Rule{}, err
+	}
+
+	current, err := uc.Get(ctx, id)
+	if err != nil {
+		return domain.Rule{}, err
+	}
+	r.ID = current.ID
+	r.CreatedAt = current.CreatedAt
+	r.UpdatedAt = uc.now().UTC()
+	r.State = current.State
+
+	found, err := uc.repo.UpdateRule(ctx, r)
+	if err != nil {
+		return domain.Rule{}, err
+	}
+	if !found {
+		return domain.Rule{}, ErrRuleNotFound
+	}
+	return r, nil
+}
+
+func (uc *RuleUseCase) Delete(ctx context.Context, id string) error {
+	if uuid.Validate(id) != nil {
+		return ErrRuleNotFound
+	}
+	found, err := uc.repo.DeleteRule(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrRuleNotFound
+	}
+	return nil
+}
+
+func (uc *RuleUseCase) Get(ctx context.Context, id string) (domain.Rule, error) {
+	if uuid.Validate(id) != nil {
+		return domain.Rule{}, ErrRuleNotFound
+	}
+	r, found, err := uc.repo.GetRule(ctx, id)
+	if err != nil {
+		return domain.Rule{}, err
+	}
+	if !found {
+		return domain.Rule{}, ErrRuleNotFound
+	}
+	return r, nil
+}
+
+func (uc *RuleUseCase) List(ctx context.Context) ([]domain.Rule, error) {
+	return uc.repo.ListRules(ctx)
+}
+
+func (uc *RuleUseCase) buildRule(in RuleInput) (domain.Rule, error) {
+	name := strings.TrimSpace(in.Name)
+	if name == "" || len(name) > MaxNameLength {
+		return domain.Rule{}, fmt.Errorf("%w: name must be 1-%d characters", ErrInvalidRule, MaxNameLength)
+	}
+
+	query := strings.TrimSpace(in.Query)
+	window, err := uc.measure.Window(query)
+	if err != nil {
+		return domain.Rule{}, fmt.Errorf("%w: %s", ErrInvalidRule, err)
+	}
+	if window > MaxWindow {
+		return domain.Rule{}, fmt.Errorf("%w: range must be at most %s", ErrInvalidRule, MaxWindow)
+	}
+
+	switch in.Measure {
+	case domain.MeasureTotalCount, domain.MeasureUniqueUsers:
+	case domain.MeasureValue:
+		if q, _ := url.ParseQuery(query); q.Get("aggregate") == "" {
+			return domain.Rule{}, fmt.Errorf("%w: measure value needs aggregate= in the query", ErrInvalidRule)
+		}
+	default:
+		return domain.Rule{}, fmt.Errorf("%w: measure must be %s, %s or %s",
+			ErrInvalidRule, domain.MeasureTotalCount, domain.MeasureUniqueUsers, domain.MeasureValue)
+	}
+	switch in.Operator {
+	case domain.OperatorLess, domain.OperatorLessEqual, domain.OperatorGreater, domain.OperatorGreaterEqual:
+	default:
+		return domain.Rule{}, fmt.Errorf("%w: operator must be <, <=, > or >=", ErrInvalidRule)
+	}
+
+	u, err := url.Parse(strings.TrimSpace(in.NotifyURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return domain.Rule{}, fmt.Errorf("%w: notify_url must be an absolute http or https URL", ErrInvalidRule)
+	}
+
+	return domain.Rule{
+		Name:      name,
+		TenantID:  strings.TrimSpace(in.TenantID),
+		Query:     query,
+		Measure:   in.Measure,
+		Operator:  in.Operator,
+		Threshold: in.Threshold,
+		NotifyURL: u.String(),
+		Active:    in.Active,
+	}, nil
+}
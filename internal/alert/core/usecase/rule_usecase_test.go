@@ -0,0 +1,73 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"event-metrics-service/internal/alert/core/domain"
+	"event-metrics-service/internal/alert/core/usecase"
+)
+
+func validRuleInput() usecase.RuleInput {
+	return usecase.RuleInput{
+		Name:      " signups stalled ",
+		Query:     "event_name=signup&range=1h",
+		Measure:   domain.MeasureTotalCount,
+		Operator:  domain.OperatorLess,
+		Threshold: 100,
+		NotifyURL: "https://hooks.example.com/alerts",
+		Active:    true,
+	}
+}
+
+func TestRule_CreateStartsOK(t *testing.T) {
+	repo := &memRules{}
+	r, err := usecase.NewRuleUseCase(repo, &fakeMeasure{}).Create(context.Background(), validRuleInput())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.ID == "" || r.Name != "signups stalled" || r.State.Status != domain.StatusOK || len(repo.rules) != 1 {
+		t.Fatalf("unexpected rule: %+v", r)
+	}
+}
+
+func TestRule_CreateInvalid(t *testing.T) {
+	tests := []func(*usecase.RuleInput){
+		func(in *usecase.RuleInput) { in.Name = "" },
+		func(in *usecase.RuleInput) { in.Query = "range=1h" },
+		func(in *usecase.RuleInput) { in.Measure = "count" },
+		func(in *usecase.RuleInput) { in.Measure = domain.MeasureValue },
+		func(in *usecase.RuleInput) { in.Operator = "==" },
+		func(in *usecase.RuleInput) { in.NotifyURL = "hooks.example.com" },
+	}
+	for i, mutate := range tests {
+		in := validRuleInput()
+		mutate(&in)
+		_, err := usecase.NewRuleUseCase(&memRules{}, &fakeMeasure{}).Create(context.Background(), in)
+		if !errors.Is(err, usecase.ErrInvalidRule) {
+			t.Fatalf("case %d: expected ErrInvalidRule, got %v", i, err)
+		}
+	}
+}
+
+func TestRule_UpdateKeepsState(t *testing.T) {
+	current := signupRule(domain.StatusFiring)
+	current.ID = "5f1c2d3e-0a9b-4c8d-b7e6-1f2a3b4c5d6e"
+	repo := &memRules{rules: []domain.Rule{current}}
+
+	in := validRuleInput()
+	in.Threshold = 50
+	r, err := usecase.NewRuleUseCase(repo, &fakeMeasure{}).Update(context.Background(), current.ID, in)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Threshold != 50 || r.State.Status != domain.StatusFiring {
+		t.Fatalf("unexpected rule: %+v", r)
+	}
+
+	_, err = usecase.NewRuleUseCase(repo, &fakeMeasure{}).Get(context.Background(), "r1")
+	if !errors.Is(err, usecase.ErrRuleNotFound) {
+		t.Fatalf("expected ErrRuleNotFound for a malformed id, got %v", err)
+	}
+}
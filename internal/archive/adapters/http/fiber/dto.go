@@ -0,0 +1,36 @@
+package fiber
+
+import "time"
+
+type RehydrateRequest struct {
+	From int64 `json:"from" example:"1725148800"`
+	To   int64 `json:"to" example:"1725235199"`
+}
+
+type RehydrateResponse struct {
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Files    int       `json:"files" example:"3"`
+	Events   int       `json:"events" example:"14210"`
+	Restored int64     `json:"restored" example:"14210"`
+}
+
+type ReplayRequest struct {
+	From int64 `json:"from" example:"1725148800"`
+	To   int64 `json:"to" example:"1725235199"`
+}
+
+type ReplayResponse struct {
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	Files      int       `json:"files" example:"3"`
+	Events     int       `json:"events" example:"14210"`
+	Created    int       `json:"created" example:"14190"`
+	Duplicates int       `json:"duplicates" example:"12"`
+	Rejected   int       `json:"rejected" example:"8"`
+}
+
+type ErrorResponse struct {
+	Error   string `json:"error" example:"invalid_range"`
+	Message string `json:"message" example:"invalid archive range: from must not be after to"`
+}
@@ -0,0 +1,132 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"event-metrics-service/internal/archive/core/domain"
+	"event-metrics-service/internal/archive/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RehydrateUseCase interface {
+	Execute(ctx context.Context, from, to time.Time) (domain.RehydrationReport, error)
+}
+
+type ReplayUseCase interface {
+	Execute(ctx context.Context, from, to time.Time) (domain.ReplayReport, error)
+}
+
+type ArchiveHandler struct {
+	rehydrateUC RehydrateUseCase
+	replayUC    ReplayUseCase
+}
+
+func NewArchiveHandler(rehydrateUC RehydrateUseCase, replayUC ReplayUseCase) *ArchiveHandler {
+	return &ArchiveHandler{rehydrateUC: rehydrateUC, replayUC: replayUC}
+}
+
+// Rehydrate godoc
+// @Summary Load archived events back into Postgres
+// @Description Restores events purged by retention whose event_time is within [from, to] (unix seconds, at most
+// @Description 31 days) from the S3 archive into the events_rehydrated table. Already restored events are skipped.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param request body RehydrateRequest true "Event time range"
+// @Success 200 {object} RehydrateResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/archive/rehydrate [post]
+func (h *ArchiveHandler) Rehydrate(c *fiber.Ctx) error {
+	var req RehydrateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c)
+	}
+	if req.From <= 0 || req.To <= 0 {
+		return missingRange(c)
+	}
+
+	report, err := h.rehydrateUC.Execute(c.UserContext(), time.Unix(req.From, 0), time.Unix(req.To, 0))
+	if err != nil {
+		return rangeError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(RehydrateResponse{
+		From:     report.From,
+		To:       report.To,
+		Files:    report.Files,
+		Events:   report.Events,
+		Restored: report.Restored,
+	})
+}
+
+// Replay godoc
+// @Summary Re-ingest archived events
+// @Description Reads the archive files (Parquet, or NDJSON in the GET /events/export format) of events whose
+// @Description event_time is within [from, to] (unix seconds, at most 31 days) and stores them again through the
+// @Description regular ingest path, so dedupe and validation apply. Events still stored or replayed before are
+// @Description reported as duplicates.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param request body ReplayRequest true "Event time range"
+// @Success 200 {object} ReplayResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/archive/replay [post]
+func (h *ArchiveHandler) Replay(c *fiber.Ctx) error {
+	var req ReplayRequest
+	if err := c.BodyParser(&req); err != nil {
+		return invalidBody(c)
+	}
+	if req.From <= 0 || req.To <= 0 {
+		return missingRange(c)
+	}
+
+	report, err := h.replayUC.Execute(c.UserContext(), time.Unix(req.From, 0), time.Unix(req.To, 0))
+	if err != nil {
+		return rangeError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(ReplayResponse{
+		From:       report.From,
+		To:         report.To,
+		Files:      report.Files,
+		Events:     report.Events,
+		Created:    report.Created,
+		Duplicates: report.Duplicates,
+		Rejected:   report.Rejected,
+	})
+}
+
+func invalidBody(c *fiber.Ctx) error {
+	return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+		Error:   "invalid_body",
+		Message: "Request body could not be parsed",
+	})
+}
+
+func missingRange(c *fiber.Ctx) error {
+	return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+		Error:   "invalid_range",
+		Message: "from and to are required",
+	})
+}
+
+func rangeError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, usecase.ErrInvalidRange) {
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error:   "invalid_range",
+			Message: err.Error(),
+		})
+	}
+	return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+		Error: "internal_server_error",
+	})
+}
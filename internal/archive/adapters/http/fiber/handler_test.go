@@ -0,0 +1,167 @@
+package fiber_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpadapter "event-metrics-service/internal/archive/adapters/http/fiber"
+	"event-metrics-service/internal/archive/core/domain"
+	"event-metrics-service/internal/archive/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeRehydrateUseCase struct {
+	ExecuteFn func(ctx context.Context, from, to time.Time) (domain.RehydrationReport, error)
+}
+
+func (f *fakeRehydrateUseCase) Execute(ctx context.Context, from, to time.Time) (domain.RehydrationReport, error) {
+	return f.ExecuteFn(ctx, from, to)
+}
+
+type fakeReplayUseCase struct {
+	ExecuteFn func(ctx context.Context, from, to time.Time) (domain.ReplayReport, error)
+}
+
+func (f *fakeReplayUseCase) Execute(ctx context.Context, from, to time.Time) (domain.ReplayReport, error) {
+	return f.ExecuteFn(ctx, from, to)
+}
+
+// requestKey marks the request context, to check handlers pass it on.
+type requestKey struct{}
+
+func doRequest(t *testing.T, h *httpadapter.ArchiveHandler, path, body string) *http.Response {
+	t.Helper()
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(context.WithValue(c.UserContext(), requestKey{}, path))
+		return c.Next()
+	})
+	app.Post("/admin/archive/rehydrate", h.Rehydrate)
+	app.Post("/admin/archive/replay", h.Replay)
+
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	return resp
+}
+
+func doRehydrate(t *testing.T, uc httpadapter.RehydrateUseCase, body string) *http.Response {
+	t.Helper()
+	return doRequest(t, httpadapter.NewArchiveHandler(uc, &fakeReplayUseCase{}), "/admin/archive/rehydrate", body)
+}
+
+func TestRehydrate_Success(t *testing.T) {
+	uc := &fakeRehydrateUseCase{
+		ExecuteFn: func(ctx context.Context, from, to time.Time) (domain.RehydrationReport, error) {
+			if from.Unix() != 1725148800 || to.Unix() != 1725235199 {
+				t.Fatalf("unexpected range %s - %s", from, to)
+			}
+			if ctx.Value(requestKey{}) == nil {
+				t.Fatalf("expected the request context")
+			}
+			return domain.RehydrationReport{From: from, To: to, Files: 2, Events: 10, Restored: 7}, nil
+		},
+	}
+
+	resp := doRehydrate(t, uc, `{"from": 1725148800, "to": 1725235199}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body httpadapter.RehydrateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if body.Files != 2 || body.Events != 10 || body.Restored != 7 {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+}
+
+func TestRehydrate_Errors(t *testing.T) {
+	tests := []struct {
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{`not json`, nil, http.StatusBadRequest},
+		{`{"from": 1725148800}`, nil, http.StatusBadRequest},
+		{`{"from": 2, "to": 1}`, fmt.Errorf("%w: from must not be after to", usecase.ErrInvalidRange), http.StatusBadRequest},
+		{`{"from": 1, "to": 2}`, errors.New("s3 unavailable"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		uc := &fakeRehydrateUseCase{
+			ExecuteFn: func(ctx context.Context, from, to time.Time) (domain.RehydrationReport, error) {
+				return domain.RehydrationReport{}, tt.err
+			},
+		}
+		if resp := doRehydrate(t, uc, tt.body); resp.StatusCode != tt.wantStatus {
+			t.Fatalf("%s: expected %d, got %d", tt.body, tt.wantStatus, resp.StatusCode)
+		}
+	}
+}
+
+// ------------------------------------------------------------
+
+func TestReplay_Success(t *testing.T) {
+	uc := &fakeReplayUseCase{
+		ExecuteFn: func(ctx context.Context, from, to time.Time) (domain.ReplayReport, error) {
+			if from.Unix() != 1725148800 || to.Unix() != 1725235199 {
+				t.Fatalf("unexpected range %s - %s", from, to)
+			}
+			if ctx.Value(requestKey{}) == nil {
+				t.Fatalf("expected the request context")
+			}
+			return domain.ReplayReport{From: from, To: to, Files: 2, Events: 10, Created: 6, Duplicates: 3, Rejected: 1}, nil
+		},
+	}
+
+	h := httpadapter.NewArchiveHandler(&fakeRehydrateUseCase{}, uc)
+	resp := doRequest(t, h, "/admin/archive/replay", `{"from": 1725148800, "to": 1725235199}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body httpadapter.ReplayResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if body.Files != 2 || body.Events != 10 || body.Created != 6 || body.Duplicates != 3 || body.Rejected != 1 {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+}
+
+func TestReplay_Errors(t *testing.T) {
+	tests := []struct {
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{`{"to": 1725148800}`, nil, http.StatusBadRequest},
+		{`{"from": 1, "to": 9999999999}`, fmt.Errorf("%w: at most 31 days per request", usecase.ErrInvalidRange), http.StatusBadRequest},
+		{`{"from": 1, "to": 2}`, errors.New("db down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		uc := &fakeReplayUseCase{
+			ExecuteFn: func(ctx context.Context, from, to time.Time) (domain.ReplayReport, error) {
+				return domain.ReplayReport{}, tt.err
+			},
+		}
+		h := httpadapter.NewArchiveHandler(&fakeRehydrateUseCase{}, uc)
+		if resp := doRequest(t, h, "/admin/archive/replay", tt.body); resp.StatusCode != tt.wantStatus {
+			t.Fatalf("%s: expected %d, got %d", tt.body, tt.wantStatus, resp.StatusCode)
+		}
+	}
+}
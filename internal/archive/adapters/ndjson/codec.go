@@ -0,0 +1,121 @@
+package ndjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"event-metrics-service/internal/archive/core/domain"
+	"event-metrics-service/internal/archive/core/ports"
+)
+
+// Extension of NDJSON archive files.
+const Extension = ".ndjson"
+
+// Codec reads and writes archive records as newline delimited JSON, one
+// event per line in the shape GET /events/export writes, so exports can be
+// dropped into the archive and replayed.
+type Codec struct{}
+
+var _ ports.CodecPort = Codec{}
+
+var ErrInvalidRecord = errors.New("invalid ndjson record")
+
+type line struct {
+	ID          int64           `json:"id"`
+	EventID     string          `json:"event_id,omitempty"`
+	EventName   string          `json:"event_name"`
+	Channel     string          `json:"channel"`
+	CampaignID  string          `json:"campaign_id,omitempty"`
+	UserID      string          `json:"user_id"`
+	AnonymousID string          `json:"anonymous_id,omitempty"`
+	EventTime   time.Time       `json:"event_time"`
+	ReceivedAt  time.Time       `json:"received_at"`
+	Value       *float64        `json:"value,omitempty"`
+	Tags        []string        `json:"tags"`
+	Metadata    json.RawMessage `json:"metadata"`
+	DedupeKey   string          `json:"dedupe_key"`
+	TenantID    string          `json:"tenant_id,omitempty"`
+	SampleRate  float64         `json:"sample_rate,omitempty"`
+}
+
+func (Codec) Encode(records []domain.Record) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range records {
+		tags := r.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		metadata := r.Metadata
+		if len(metadata) == 0 {
+			metadata = json.RawMessage(`{}`)
+		}
+		if err := enc.Encode(line{
+			ID:          r.ID,
+			EventID:     r.EventID,
+			EventName:   r.EventName,
+			Channel:     r.Channel,
+			CampaignID:  r.CampaignID,
+			UserID:      r.UserID,
+			AnonymousID: r.AnonymousID,
+			EventTime:   r.EventTime.UTC(),
+			ReceivedAt:  r.ReceivedAt.UTC(),
+			Value:       r.Value,
+			Tags:        tags,
+			Metadata:    metadata,
+			DedupeKey:   r.DedupeKey,
+			TenantID:    r.TenantID,
+			SampleRate:  r.Rate(),
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode reads every line of data; blank lines are skipped. Lines without
+// event_name or event_time are refused, as is an error line ending a failed
+// export.
+func (Codec) Decode(data []byte) ([]domain.Record, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var out []domain.Record
+	for n := 1; ; n++ {
+		var l line
+		err := dec.Decode(&l)
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: record %d: %v", ErrInvalidRecord, n, err)
+		}
+		if l.EventName == "" || l.EventTime.IsZero() {
+			return nil, fmt.Errorf("%w: record %d: event_name and event_time are required", ErrInvalidRecord, n)
+		}
+
+		metadata := l.Metadata
+		if len(metadata) == 0 || string(metadata) == "null" {
+			metadata = json.RawMessage(`{}`)
+		}
+		out = append(out, domain.Record{
+			ID:          l.ID,
+			EventID:     l.EventID,
+			EventName:   l.EventName,
+			Channel:     l.Channel,
+			CampaignID:  l.CampaignID,
+			UserID:      l.UserID,
+			AnonymousID: l.AnonymousID,
+			EventTime:   l.EventTime.UTC(),
+			ReceivedAt:  l.ReceivedAt.UTC(),
+			Value:       l.Value,
+			Tags:        l.Tags,
+			Metadata:    metadata,
+			DedupeKey:   l.DedupeKey,
+			TenantID:    l.TenantID,
+			SampleRate:  l.SampleRate,
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package ndjson
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/archive/core/domain"
+)
+
+func TestCodec_RoundTrip(t *testing.T) {
+	value := 42.5
+	t0 := time.Date(2025, 9, 1, 10, 0, 0, 123456000, time.UTC)
+	records := []domain.Record{
+		{
+			ID:          1,
+			EventID:     "0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42",
+			EventName:   "purchase",
+			Channel:     "web",
+			CampaignID:  "cmp_1",
+			UserID:      "user_1",
+			AnonymousID: "anon_1",
+			EventTime:   t0,
+			ReceivedAt:  t0.Add(time.Second),
+			Value:       &value,
+			Tags:        []string{"vip"},
+			Metadata:    json.RawMessage(`{"order_id":"o-1"}`),
+			DedupeKey:   "tenant|acme|event_id|0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42",
+			TenantID:    "acme",
+			SampleRate:  0.5,
+		},
+		{ID: 2, EventName: "signup", Channel: "ios", UserID: "user_2", EventTime: t0, ReceivedAt: t0, Tags: []string{}, Metadata: json.RawMessage(`{}`), SampleRate: 1},
+	}
+
+	data, err := Codec{}.Encode(records)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	got, err := Codec{}.Decode(data)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !reflect.DeepEqual(got, records) {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", got, records)
+	}
+}
+
+func TestCodec_DecodeExportLines(t *testing.T) {
+	data := []byte(`{"id":7,"event_name":"purchase","channel":"web","user_id":"u1","event_time":"2025-12-07T10:00:00Z","received_at":"2025-12-07T10:00:02.417Z","tags":["promo"],"metadata":null,"dedupe_key":"dk"}
+
+{"id":8,"event_name":"purchase","channel":"web","user_id":"u1","event_time":"2025-12-07T11:00:00+01:00","received_at":"2025-12-07T10:00:03Z","tags":[],"metadata":{"n":1},"dedupe_key":"dk2"}
+`)
+
+	got, err := Codec{}.Decode(data)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(got) != 2 || string(got[0].Metadata) != `{}` || got[1].EventTime.Location() != time.UTC || got[1].EventTime.Hour() != 10 {
+		t.Fatalf("unexpected records: %+v", got)
+	}
+}
+
+func TestCodec_DecodeRejectsInvalidLines(t *testing.T) {
+	for _, data := range []string{
+		`{"event_name":"purchase"`,
+		`{"event_name":"purchase","channel":"web"}`,
+		`{"error":"export_failed","message":"export stopped before the last event; retry the request"}`,
+	} {
+		if _, err := (Codec{}).Decode([]byte(data)); !errors.Is(err, ErrInvalidRecord) {
+			t.Fatalf("%s: expected ErrInvalidRecord, got %v", data, err)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"event-metrics-service/internal/archive/core/domain"
+	"event-metrics-service/internal/archive/core/ports"
+	"event-metrics-service/internal/parquet"
+)
+
+// Codec writes archive records as Parquet files, in one row group. Tags and
+// metadata are JSON strings, empty optional strings are nulls like the NULL
+// columns of the events table.
+type Codec struct{}
+
+var _ ports.CodecPort = Codec{}
+
+var ErrUnsupportedFile = parquet.ErrUnsupportedFile
+
+type column struct {
+	parquet.Column
+
+	// value returns the value of r in the column.
+	value func(r *domain.Record) any
+	// set sets the value of r from a non-null value.
+	set func(r *domain.Record, v any) error
+}
+
+var columns = []column{
+	{parquet.Column{Name: "id", Type: parquet.Int64},
+		func(r *domain.Record) any { return r.ID },
+		func(r *domain.Record, v any) error { r.ID = v.(int64); return nil }},
+	{parquet.Column{Name: "event_id", Type: parquet.String, Optional: true},
+		func(r *domain.Record) any { return optionalString(r.EventID) },
+		func(r *domain.Record, v any) error { r.EventID = v.(string); return nil }},
+	{parquet.Column{Name: "event_name", Type: parquet.String},
+		func(r *domain.Record) any { return r.EventName },
+		func(r *domain.Record, v any) error { r.EventName = v.(string); return nil }},
+	{parquet.Column{Name: "channel", Type: parquet.String},
+		func(r *domain.Record) any { return r.Channel },
+		func(r *domain.Record, v any) error { r.Channel = v.(string); return nil }},
+	{parquet.Column{Name: "campaign_id", Type: parquet.String, Optional: true},
+		func(r *domain.Record) any { return optionalString(r.CampaignID) },
+		func(r *domain.Record, v any) error { r.CampaignID = v.(string); return nil }},
+	{parquet.Column{Name: "user_id", Type: parquet.String},
+		func(r *domain.Record) any { return r.UserID },
+		func(r *domain.Record, v any) error { r.UserID = v.(string); return nil }},
+	{parquet.Column{Name: "anonymous_id", Type: parquet.String, Optional: true},
+		func(r *domain.Record) any { return optionalString(r.AnonymousID) },
+		func(r *domain.Record, v any) error { r.AnonymousID = v.(string); return nil }},
+	{parquet.Column{Name: "event_time", Type: parquet.Timestamp},
+		func(r *domain.Record) any { return r.EventTime },
+		func(r *domain.Record, v any) error { r.EventTime = v.(time.Time); return nil }},
+	{parquet.Column{Name: "received_at", Type: parquet.Timestamp},
+		func(r *domain.Record) any { return r.ReceivedAt },
+		func(r *domain.Record, v any) error { r.ReceivedAt = v.(time.Time); return nil }},
+	{parquet.Column{Name: "value", Type: parquet.Double, Optional: true},
+		func(r *domain.Record) any {
+			if r.Value == nil {
+				return nil
+			}
+			return *r.Value
+		},
+		func(r *domain.Record, v any) error { f := v.(float64); r.Value = &f; return nil }},
+	{parquet.Column{Name: "tags", Type: parquet.JSON},
+		func(r *domain.Record) any {
+			tags := r.Tags
+			if tags == nil {
+				tags = []string{}
+			}
+			raw, _ := json.Marshal(tags) // []string always marshals
+			return raw
+		},
+		func(r *domain.Record, v any) error { return json.Unmarshal(v.([]byte), &r.Tags) }},
+	{parquet.Column{Name: "metadata", Type: parquet.JSON},
+		func(r *domain.Record) any {
+			if len(r.Metadata) == 0 {
+				return []byte("{}")
+			}
+			return []byte(r.Metadata)
+		},
+		func(r *domain.Record, v any) error { r.Metadata = json.RawMessage(v.([]byte)); return nil }},
+	{parquet.Column{Name: "dedupe_key", Type: parquet.String},
+		func(r *domain.Record) any { return r.DedupeKey },
+		func(r *domain.Record, v any) error { r.DedupeKey = v.(string); return nil }},
+	{parquet.Column{Name: "tenant_id", Type: parquet.String},
+		func(r *domain.Record) any { return r.TenantID },
+		func(r *domain.Record, v any) error { r.TenantID = v.(string); return nil }},
+	{parquet.Column{Name: "sample_rate", Type: parquet.Double},
+		func(r *domain.Record) any { return r.Rate() },
+		func(r *domain.Record, v any) error { r.SampleRate = v.(float64); return nil }},
+}
+
+// optionalString stores empty strings as null.
+func optionalString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func (Codec) Encode(records []domain.Record) ([]byte, error) {
+	if len(records) == 0 {
+		return nil, errors.New("no records to encode")
+	}
+
+	schema := make([]parquet.Column, len(columns))
+	for i, col := range columns {
+		schema[i] = col.Column
+	}
+
+	var file bytes.Buffer
+	w := parquet.NewWriter(&file, schema)
+	row := make([]any, len(columns))
+	for j := range records {
+		for i, col := range columns {
+			row[i] = col.value(&records[j])
+		}
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return file.Bytes(), nil
+}
+
+// Decode reads files written by Encode. Columns it does not know are
+// skipped; columns the file lacks, as in files archived before tenant_id and
+// sample_rate were, are left zero.
+func (Codec) Decode(data []byte) ([]domain.Record, error) {
+	schema, rows, err := parquet.Read(data)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]column, len(columns))
+	for _, col := range columns {
+		known[col.Name] = col
+	}
+	type field struct {
+		index int
+		col   column
+	}
+	var fields []field
+	for i, c := range schema {
+		col, ok := known[c.Name]
+		if !ok {
+			continue
+		}
+		if c.Type != col.Type {
+			return nil, fmt.Errorf("%w: column %s has the wrong type", ErrUnsupportedFile, c.Name)
+		}
+		fields = append(fields, field{index: i, col: col})
+	}
+
+	records := make([]domain.Record, len(rows))
+	for j, row := range rows {
+		for _, f := range fields {
+			if row[f.index] == nil {
+				continue
+			}
+			if err := f.col.set(&records[j], row[f.index]); err != nil {
+				return nil, fmt.Errorf("column %s: %w", f.col.Name, err)
+			}
+		}
+	}
+	return records, nil
+}
@@ -0,0 +1,138 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/archive/core/domain"
+	"event-metrics-service/internal/parquet"
+)
+
+func sampleRecords() []domain.Record {
+	value := 42.5
+	t0 := time.Date(2025, 9, 1, 10, 0, 0, 123456000, time.UTC)
+	return []domain.Record{
+		{
+			ID:          1,
+			EventID:     "0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42",
+			EventName:   "purchase",
+			Channel:     "web",
+			CampaignID:  "cmp_1",
+			UserID:      "user_1",
+			AnonymousID: "anon_1",
+			EventTime:   t0,
+			ReceivedAt:  t0.Add(time.Second),
+			Value:       &value,
+			Tags:        []string{"vip", "promo"},
+			Metadata:    json.RawMessage(`{"plan":"pro","amount":12.5}`),
+			DedupeKey:   "tenant|acme|k1",
+			TenantID:    "acme",
+			SampleRate:  0.25,
+		},
+		{
+			ID:         2,
+			EventName:  "page_view",
+			Channel:    "ios",
+			UserID:     "",
+			EventTime:  t0.Add(time.Minute),
+			ReceivedAt: t0.Add(2 * time.Minute),
+			Tags:       []string{},
+			Metadata:   json.RawMessage(`{}`),
+			DedupeKey:  "k2",
+			SampleRate: 1,
+		},
+	}
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	records := sampleRecords()
+	// Enough rows for multi-byte RLE run headers.
+	for i := int64(3); i <= 300; i++ {
+		r := records[1]
+		r.ID = i
+		records = append(records, r)
+	}
+
+	data, err := Codec{}.Encode(records)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("PAR1")) || !bytes.HasSuffix(data, []byte("PAR1")) {
+		t.Fatalf("missing magic bytes")
+	}
+
+	got, err := Codec{}.Decode(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, records) {
+		t.Fatalf("round trip mismatch:\nwant %+v\ngot  %+v", records[:2], got[:2])
+	}
+}
+
+func TestCodec_Schema(t *testing.T) {
+	data, err := Codec{}.Encode(sampleRecords())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	schema, rows, err := parquet.Read(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	if len(schema) != len(columns) {
+		t.Fatalf("expected %d columns, got %d", len(columns), len(schema))
+	}
+	if eventTime := schema[7]; eventTime.Name != "event_time" || eventTime.Type != parquet.Timestamp {
+		t.Fatalf("unexpected column %+v", eventTime)
+	}
+	if eventID := schema[1]; !eventID.Optional || rows[1][1] != nil {
+		t.Fatalf("expected an empty event_id to be null, got %+v %v", eventID, rows[1][1])
+	}
+}
+
+func TestCodec_DecodeFilesWithoutTenantAndSampleRate(t *testing.T) {
+	// Archived before tenant_id and sample_rate were.
+	var buf bytes.Buffer
+	w := parquet.NewWriter(&buf, []parquet.Column{
+		{Name: "id", Type: parquet.Int64},
+		{Name: "event_name", Type: parquet.String},
+	})
+	if err := w.Write([]any{int64(7), "purchase"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := Codec{}.Decode(buf.Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != 7 || got[0].TenantID != "" || got[0].Rate() != 1 {
+		t.Fatalf("unexpected records: %+v", got)
+	}
+}
+
+func TestCodec_DecodeRejectsInvalidFiles(t *testing.T) {
+	for _, data := range [][]byte{
+		nil,
+		[]byte("PAR1PAR1"),
+		[]byte("not a parquet file at all"),
+		append([]byte("PAR1\xff\xff\xff\x7f"), []byte("\x10\x00\x00\x00PAR1")...),
+	} {
+		if _, err := (Codec{}).Decode(data); err == nil {
+			t.Fatalf("%q: expected an error", data)
+		}
+	}
+
+	if _, err := (Codec{}).Encode(nil); err == nil {
+		t.Fatalf("expected an error for no records")
+	}
+}
@@ -0,0 +1,271 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"event-metrics-service/internal/archive/core/domain"
+	"event-metrics-service/internal/archive/core/ports"
+
+	"github.com/lib/pq"
+)
+
+type RowScanner interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+type DB interface {
+	QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+// Gated columns (see the migration module) the archive reads and restores.
+// Migration 028 adds tenant_id and sample_rate to events_rehydrated together,
+// so the one gate covers both.
+const (
+	ColumnAnonymousID        = "events.anonymous_id"
+	ColumnTenantID           = "events.tenant_id"
+	ColumnSampleRate         = "events.sample_rate"
+	ColumnRehydratedTenantID = "events_rehydrated.tenant_id"
+)
+
+var GatedColumns = []string{ColumnAnonymousID, ColumnTenantID, ColumnSampleRate, ColumnRehydratedTenantID}
+
+// ColumnGate reports whether a gated column may be read or written.
+type ColumnGate interface {
+	Reads(column string) bool
+	Writes(column string) bool
+}
+
+type ArchiveRepository struct {
+	db   DB
+	gate ColumnGate
+}
+
+type RepositoryOption func(*ArchiveRepository)
+
+// WithColumnGate archives anonymous_id and tenant_id as empty, and
+// sample_rate as 1, while those columns are gated off or not migrated yet,
+// and restores without tenant_id and sample_rate until migration 028.
+func WithColumnGate(g ColumnGate) RepositoryOption {
+	return func(r *ArchiveRepository) {
+		r.gate = g
+	}
+}
+
+func NewArchiveRepository(db DB, opts ...RepositoryOption) *ArchiveRepository {
+	r := &ArchiveRepository{db: db}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+var (
+	_ ports.EventSourcePort = (*ArchiveRepository)(nil)
+	_ ports.RestorePort     = (*ArchiveRepository)(nil)
+)
+
+// Same order as the retention purge, so the archived batch is exactly the
+// batch it deletes next.
+const oldestEventsBeforeSQL = `
+SELECT
+    id,
+    COALESCE(event_id::text, ''),
+    event_name,
+    channel,
+    COALESCE(campaign_id, ''),
+    user_id,
+    %s,
+    event_time,
+    received_at,
+    value,
+    tags,
+    metadata,
+    dedupe_key,
+    %s,
+    %s
+FROM events
+WHERE event_time < $1
+ORDER BY event_time, id
+LIMIT $2`
+
+func (r *ArchiveRepository) OldestEventsBefore(ctx context.Context, cutoff time.Time, limit int) ([]domain.Record, error) {
+	anonymousID := "COALESCE(anonymous_id, '')"
+	if !r.reads(ColumnAnonymousID) {
+		anonymousID = "''"
+	}
+	tenantID := "tenant_id"
+	if !r.reads(ColumnTenantID) {
+		tenantID = "''"
+	}
+	sampleRate := "sample_rate"
+	if !r.reads(ColumnSampleRate) {
+		sampleRate = "1::double precision"
+	}
+
+	query := fmt.Sprintf(oldestEventsBeforeSQL, anonymousID, tenantID, sampleRate)
+	rows, err := r.db.QueryContext(ctx, query, cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Record
+	for rows.Next() {
+		var (
+			rec      domain.Record
+			value    sql.NullFloat64
+			metadata []byte
+		)
+		if err := rows.Scan(
+			&rec.ID,
+			&rec.EventID,
+			&rec.EventName,
+			&rec.Channel,
+			&rec.CampaignID,
+			&rec.UserID,
+			&rec.AnonymousID,
+			&rec.EventTime,
+			&rec.ReceivedAt,
+			&value,
+			pq.Array(&rec.Tags),
+			&metadata,
+			&rec.DedupeKey,
+			&rec.TenantID,
+			&rec.SampleRate,
+		); err != nil {
+			return nil, err
+		}
+		if value.Valid {
+			v := value.Float64
+			rec.Value = &v
+		}
+		rec.Metadata = json.RawMessage(metadata)
+		out = append(out, rec)
+	}
+	return out, rows.Err()
+}
+
+// restoreChunkSize keeps a statement well below the 65535 parameter limit.
+const restoreChunkSize = 500
+
+const (
+	restoreColumns       = 16
+	legacyRestoreColumns = 14
+)
+
+const restoreEventsSQL = `
+WITH restored AS (
+    INSERT INTO events_rehydrated (
+        id, event_id, event_name, channel, campaign_id, user_id, anonymous_id,
+        event_time, received_at, value, tags, metadata, dedupe_key, archive_key,
+        tenant_id, sample_rate
+    )
+    VALUES %s
+    ON CONFLICT (id) DO NOTHING
+    RETURNING 1
+)
+SELECT count(*) FROM restored`
+
+// legacyRestoreEventsSQL restores into events_rehydrated before migration 028.
+const legacyRestoreEventsSQL = `
+WITH restored AS (
+    INSERT INTO events_rehydrated (
+        id, event_id, event_name, channel, campaign_id, user_id, anonymous_id,
+        event_time, received_at, value, tags, metadata, dedupe_key, archive_key
+    )
+    VALUES %s
+    ON CONFLICT (id) DO NOTHING
+    RETURNING 1
+)
+SELECT count(*) FROM restored`
+
+func (r *ArchiveRepository) RestoreEvents(ctx context.Context, key string, records []domain.Record) (int64, error) {
+	query, columns := restoreEventsSQL, restoreColumns
+	if !r.writes(ColumnRehydratedTenantID) {
+		query, columns = legacyRestoreEventsSQL, legacyRestoreColumns
+	}
+
+	var total int64
+	for start := 0; start < len(records); start += restoreChunkSize {
+		chunk := records[start:min(start+restoreChunkSize, len(records))]
+
+		values := make([]string, 0, len(chunk))
+		args := make([]any, 0, len(chunk)*columns)
+		for _, rec := range chunk {
+			n := len(args)
+			tuple := fmt.Sprintf(
+				"$%d, $%d::uuid, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d::text[], $%d::jsonb, $%d, $%d",
+				n+1, n+2, n+3, n+4, n+5, n+6, n+7, n+8, n+9, n+10, n+11, n+12, n+13, n+14,
+			)
+			if columns == restoreColumns {
+				tuple += fmt.Sprintf(", $%d, $%d", n+15, n+16)
+			}
+			values = append(values, "("+tuple+")")
+			args = append(args,
+				rec.ID,
+				nullIfEmpty(rec.EventID),
+				rec.EventName,
+				rec.Channel,
+				nullIfEmpty(rec.CampaignID),
+				rec.UserID,
+				nullIfEmpty(rec.AnonymousID),
+				rec.EventTime,
+				rec.ReceivedAt,
+				rec.Value,
+				pq.Array(rec.Tags),
+				[]byte(rec.Metadata),
+				rec.DedupeKey,
+				key,
+			)
+			if columns == restoreColumns {
+				args = append(args, rec.TenantID, rec.Rate())
+			}
+		}
+
+		n, err := r.count(ctx, fmt.Sprintf(query, strings.Join(values, ",\n           ")), args...)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func (r *ArchiveRepository) count(ctx context.Context, query string, args ...any) (int64, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var n int64
+	if rows.Next() {
+		if err := rows.Scan(&n); err != nil {
+			return 0, err
+		}
+	}
+	return n, rows.Err()
+}
+
+func (r *ArchiveRepository) reads(column string) bool {
+	return r.gate == nil || r.gate.Reads(column)
+}
+
+func (r *ArchiveRepository) writes(column string) bool {
+	return r.gate == nil || r.gate.Writes(column)
+}
+
+func nullIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/archive/core/domain"
+)
+
+type fakeDB struct {
+	QueryFn func(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+func (f *fakeDB) QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error) {
+	return f.QueryFn(ctx, query, args...)
+}
+
+// fakeRowScanner assigns row values to destinations of the same type.
+type fakeRowScanner struct {
+	rows [][]any
+	i    int
+}
+
+func (f *fakeRowScanner) Next() bool {
+	return f.i < len(f.rows)
+}
+
+func (f *fakeRowScanner) Scan(dest ...any) error {
+	row := f.rows[f.i]
+	if len(dest) != len(row) {
+		return errors.New("dest length mismatch")
+	}
+	for i := range dest {
+		switch d := dest[i].(type) {
+		case *int64:
+			*d = row[i].(int64)
+		case *string:
+			*d = row[i].(string)
+		case *float64:
+			*d = row[i].(float64)
+		case *time.Time:
+			*d = row[i].(time.Time)
+		case *[]byte:
+			*d = row[i].([]byte)
+		case sql.Scanner:
+			if err := d.Scan(row[i]); err != nil {
+				return err
+			}
+		default:
+			return errors.New("unsupported dest type")
+		}
+	}
+	f.i++
+	return nil
+}
+
+func (f *fakeRowScanner) Err() error   { return nil }
+func (f *fakeRowScanner) Close() error { return nil }
+
+type fakeColumnGate map[string]bool
+
+func (g fakeColumnGate) Reads(column string) bool  { return g[column] }
+func (g fakeColumnGate) Writes(column string) bool { return g[column] }
+
+func TestArchiveRepository_OldestEventsBefore(t *testing.T) {
+	cutoff := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	t0 := cutoff.Add(-48 * time.Hour)
+
+	var query string
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, q string, args ...any) (RowScanner, error) {
+			query = q
+			if args[0] != cutoff || args[1] != 500 {
+				t.Fatalf("unexpected args: %v", args)
+			}
+			return &fakeRowScanner{rows: [][]any{{
+				int64(7), "", "purchase", "web", "", "user_1", "anon_1", t0, t0,
+				float64(12.5), []byte(`{vip,promo}`), []byte(`{"plan":"pro"}`), "tenant|acme|k7", "acme", float64(0.25),
+			}}}, nil
+		},
+	}
+
+	records, err := NewArchiveRepository(db).OldestEventsBefore(context.Background(), cutoff, 500)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(query, "ORDER BY event_time, id") || !strings.Contains(query, "COALESCE(anonymous_id, '')") {
+		t.Fatalf("unexpected query: %s", query)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	r := records[0]
+	if r.ID != 7 || r.AnonymousID != "anon_1" || r.Value == nil || *r.Value != 12.5 || r.TenantID != "acme" || r.SampleRate != 0.25 {
+		t.Fatalf("unexpected record: %+v", r)
+	}
+	if len(r.Tags) != 2 || r.Tags[1] != "promo" || string(r.Metadata) != `{"plan":"pro"}` {
+		t.Fatalf("unexpected tags/metadata: %v %s", r.Tags, r.Metadata)
+	}
+
+	// Before migrations 009, 016 and 017 the gated columns must not be
+	// referenced.
+	_, _ = NewArchiveRepository(db, WithColumnGate(fakeColumnGate{})).OldestEventsBefore(context.Background(), cutoff, 500)
+	for _, column := range []string{"anonymous_id", "tenant_id", "sample_rate"} {
+		if strings.Contains(query, column) {
+			t.Fatalf("expected %s to be left out: %s", column, query)
+		}
+	}
+}
+
+func TestArchiveRepository_RestoreEventsInChunks(t *testing.T) {
+	records := make([]domain.Record, restoreChunkSize+1)
+	for i := range records {
+		records[i] = domain.Record{ID: int64(i + 1), EventName: "purchase", Metadata: json.RawMessage(`{}`), TenantID: "acme", SampleRate: 0.5}
+	}
+
+	var calls []int
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, q string, args ...any) (RowScanner, error) {
+			if !strings.Contains(q, "INSERT INTO events_rehydrated") || !strings.Contains(q, "ON CONFLICT (id) DO NOTHING") {
+				t.Fatalf("unexpected query: %s", q)
+			}
+			if args[13] != "events/dt=2025-09-01/1-501.parquet" {
+				t.Fatalf("expected the archive key, got %v", args[13])
+			}
+			if args[14] != "acme" || args[15] != 0.5 {
+				t.Fatalf("expected the tenant and sample rate, got %v %v", args[14], args[15])
+			}
+			calls = append(calls, len(args)/restoreColumns)
+			return &fakeRowScanner{rows: [][]any{{int64(len(args) / restoreColumns)}}}, nil
+		},
+	}
+
+	n, err := NewArchiveRepository(db).RestoreEvents(context.Background(), "events/dt=2025-09-01/1-501.parquet", records)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != int64(len(records)) || len(calls) != 2 || calls[0] != restoreChunkSize || calls[1] != 1 {
+		t.Fatalf("expected two chunks restoring %d events, got %d via %v", len(records), n, calls)
+	}
+}
+
+func TestArchiveRepository_RestoreEventsBeforeMigration028(t *testing.T) {
+	records := []domain.Record{{ID: 1, EventName: "purchase", Metadata: json.RawMessage(`{}`), TenantID: "acme", SampleRate: 0.5}}
+
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, q string, args ...any) (RowScanner, error) {
+			if strings.Contains(q, "tenant_id") || strings.Contains(q, "sample_rate") {
+				t.Fatalf("expected the legacy insert: %s", q)
+			}
+			if len(args) != legacyRestoreColumns {
+				t.Fatalf("expected %d args, got %d", legacyRestoreColumns, len(args))
+			}
+			return &fakeRowScanner{rows: [][]any{{int64(1)}}}, nil
+		},
+	}
+
+	n, err := NewArchiveRepository(db, WithColumnGate(fakeColumnGate{})).RestoreEvents(context.Background(), "events/dt=2025-09-01/1-1.parquet", records)
+	if err != nil || n != 1 {
+		t.Fatalf("expected one restored event, got %d, %v", n, err)
+	}
+}
@@ -0,0 +1,268 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"event-metrics-service/internal/archive/core/ports"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// Credentials sign requests with AWS Signature Version 4.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // temporary credentials only
+}
+
+// Client reads and writes the objects of one bucket with the AWS SDK:
+// PutObject, GetObject, ListObjectsV2 and multipart uploads.
+type Client struct {
+	api      *s3.Client
+	bucket   string
+	partSize int
+}
+
+// DefaultPartSize is the part size of multipart uploads; S3 takes parts of
+// at least 5 MiB, except the last.
+const DefaultPartSize = 8 << 20
+
+type clientConfig struct {
+	endpoint *url.URL
+	http     *http.Client
+	partSize int
+}
+
+type Option func(*clientConfig)
+
+// WithEndpoint sends path-style requests to an S3 compatible endpoint (e.g.
+// MinIO) instead of the bucket's AWS virtual host.
+func WithEndpoint(u *url.URL) Option {
+	return func(c *clientConfig) {
+		c.endpoint = u
+	}
+}
+
+// WithPartSize sets the part size of multipart uploads.
+func WithPartSize(n int) Option {
+	return func(c *clientConfig) {
+		c.partSize = n
+	}
+}
+
+func WithHTTPClient(h *http.Client) Option {
+	return func(c *clientConfig) {
+		c.http = h
+	}
+}
+
+func NewClient(bucket, region string, creds Credentials, opts ...Option) *Client {
+	cfg := clientConfig{
+		http:     &http.Client{Timeout: time.Minute},
+		partSize: DefaultPartSize,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	o := s3.Options{
+		Region:      region,
+		Credentials: credentials.NewStaticCredentialsProvider(creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken),
+		HTTPClient:  cfg.http,
+	}
+	if cfg.endpoint != nil {
+		o.BaseEndpoint = aws.String(cfg.endpoint.String())
+		o.UsePathStyle = true
+		// S3 compatible stores do not all accept the flexible checksums the
+		// SDK sends to AWS by default.
+		o.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+		o.ResponseChecksumValidation = aws.ResponseChecksumValidationWhenRequired
+	}
+
+	return &Client{api: s3.New(o), bucket: bucket, partSize: cfg.partSize}
+}
+
+var _ ports.ObjectStorePort = (*Client)(nil)
+
+func (c *Client) Put(ctx context.Context, key string, body []byte) error {
+	_, err := c.api.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(c.bucket),
+		Key:           aws.String(key),
+		Body:          bytes.NewReader(body),
+		ContentLength: aws.Int64(int64(len(body))),
+		ContentType:   aws.String("application/octet-stream"),
+	})
+	if err != nil {
+		return fmt.Errorf("s3 put %s: %w", key, err)
+	}
+	return nil
+}
+
+func (c *Client) Get(ctx context.Context, key string) ([]byte, error) {
+	out, err := c.api.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(c.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("s3 get %s: %w", key, err)
+	}
+	defer out.Body.Close()
+	return io.ReadAll(out.Body)
+}
+
+func (c *Client) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	pages := s3.NewListObjectsV2Paginator(c.api, &s3.ListObjectsV2Input{
+		Bucket: aws.String(c.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("s3 list %s: %w", prefix, err)
+		}
+		for _, obj := range page.Contents {
+			keys = append(keys, aws.ToString(obj.Key))
+		}
+	}
+	return keys, nil
+}
+
+// ------------------------------------------------------------
+// MULTIPART UPLOADS
+// ------------------------------------------------------------
+
+// Upload writes one object of unknown size without holding it in memory:
+// every full part is sent as part of a multipart upload, and Close sends
+// the rest and completes the object. An object smaller than a part is sent
+// with one PutObject.
+type Upload struct {
+	c        *Client
+	ctx      context.Context
+	key      string
+	buf      []byte
+	uploadID string
+	parts    []types.CompletedPart
+	err      error
+}
+
+// NewUpload starts writing the object key. Close or Abort must be called.
+func (c *Client) NewUpload(ctx context.Context, key string) *Upload {
+	return &Upload{c: c, ctx: ctx, key: key}
+}
+
+// URI names the object as s3://bucket/key.
+func (u *Upload) URI() string {
+	return "s3://" + u.c.bucket + "/" + u.key
+}
+
+func (u *Upload) Write(p []byte) (int, error) {
+	if u.err != nil {
+		return 0, u.err
+	}
+	n := len(p)
+	for len(p) > 0 {
+		free := u.c.partSize - len(u.buf)
+		if len(p) < free {
+			u.buf = append(u.buf, p...)
+			break
+		}
+		u.buf = append(u.buf, p[:free]...)
+		p = p[free:]
+		if u.err = u.sendPart(); u.err != nil {
+			return 0, u.err
+		}
+	}
+	return n, nil
+}
+
+// Close sends what is left and completes the object.
+func (u *Upload) Close() error {
+	if u.err != nil {
+		return u.err
+	}
+	if u.uploadID == "" {
+		u.err = u.c.Put(u.ctx, u.key, u.buf)
+		return u.err
+	}
+	if len(u.buf) > 0 {
+		if u.err = u.sendPart(); u.err != nil {
+			return u.err
+		}
+	}
+
+	_, err := u.c.api.CompleteMultipartUpload(u.ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(u.c.bucket),
+		Key:             aws.String(u.key),
+		UploadId:        aws.String(u.uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: u.parts},
+	})
+	if err != nil {
+		u.err = fmt.Errorf("s3 complete %s: %w", u.key, err)
+		return u.err
+	}
+	u.err = errors.New("s3: upload is closed")
+	return nil
+}
+
+// Abort drops the parts sent so far; nothing is written to key.
+func (u *Upload) Abort() error {
+	u.err = errors.New("s3: upload is aborted")
+	if u.uploadID == "" {
+		return nil
+	}
+	_, err := u.c.api.AbortMultipartUpload(context.WithoutCancel(u.ctx), &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(u.c.bucket),
+		Key:      aws.String(u.key),
+		UploadId: aws.String(u.uploadID),
+	})
+	if err != nil {
+		return fmt.Errorf("s3 abort %s: %w", u.key, err)
+	}
+	return nil
+}
+
+// sendPart sends the buffer as the next part, starting the multipart upload
+// with the first one.
+func (u *Upload) sendPart() error {
+	if u.uploadID == "" {
+		out, err := u.c.api.CreateMultipartUpload(u.ctx, &s3.CreateMultipartUploadInput{
+			Bucket:      aws.String(u.c.bucket),
+			Key:         aws.String(u.key),
+			ContentType: aws.String("application/octet-stream"),
+		})
+		if err != nil {
+			return fmt.Errorf("s3 create multipart upload %s: %w", u.key, err)
+		}
+		if aws.ToString(out.UploadId) == "" {
+			return fmt.Errorf("s3 create multipart upload %s: missing upload id", u.key)
+		}
+		u.uploadID = aws.ToString(out.UploadId)
+	}
+
+	number := int32(len(u.parts) + 1)
+	out, err := u.c.api.UploadPart(u.ctx, &s3.UploadPartInput{
+		Bucket:        aws.String(u.c.bucket),
+		Key:           aws.String(u.key),
+		UploadId:      aws.String(u.uploadID),
+		PartNumber:    aws.Int32(number),
+		Body:          bytes.NewReader(u.buf),
+		ContentLength: aws.Int64(int64(len(u.buf))),
+	})
+	if err != nil {
+		return fmt.Errorf("s3 upload part %d of %s: %w", number, u.key, err)
+	}
+	u.parts = append(u.parts, types.CompletedPart{PartNumber: aws.Int32(number), ETag: out.ETag})
+	u.buf = u.buf[:0]
+	return nil
+}
@@ -0,0 +1,236 @@
+package s3
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type fakePart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// fakeS3 serves path-style requests for bucket "archive" from memory.
+type fakeS3 struct {
+	mu       sync.Mutex
+	objects  map[string][]byte
+	pageSize int
+	auth     []string
+	tokens   []string
+	uploads  map[string][][]byte // parts by upload id
+	aborted  int
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.auth = append(f.auth, r.Header.Get("Authorization"))
+	f.tokens = append(f.tokens, r.Header.Get("X-Amz-Security-Token"))
+
+	key := strings.TrimPrefix(r.URL.Path, "/archive")
+	key = strings.TrimPrefix(key, "/")
+	q := r.URL.Query()
+	switch {
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		id := fmt.Sprintf("upload-%d", len(f.uploads)+1)
+		if f.uploads == nil {
+			f.uploads = map[string][][]byte{}
+		}
+		f.uploads[id] = nil
+		fmt.Fprintf(w, "<InitiateMultipartUploadResult><UploadId>%s</UploadId></InitiateMultipartUploadResult>", id)
+	case r.Method == http.MethodPut && q.Has("uploadId"):
+		body, _ := io.ReadAll(r.Body)
+		f.uploads[q.Get("uploadId")] = append(f.uploads[q.Get("uploadId")], body)
+		w.Header().Set("ETag", fmt.Sprintf(`"etag-%s"`, q.Get("partNumber")))
+	case r.Method == http.MethodPost && q.Has("uploadId"):
+		var complete struct {
+			Parts []fakePart `xml:"Part"`
+		}
+		_ = xml.NewDecoder(r.Body).Decode(&complete)
+		var parts []byte
+		for i, part := range f.uploads[q.Get("uploadId")] {
+			if i >= len(complete.Parts) || complete.Parts[i] != (fakePart{PartNumber: i + 1, ETag: fmt.Sprintf(`"etag-%d"`, i+1)}) {
+				fmt.Fprint(w, `<Error><Code>InvalidPart</Code><Message>One or more of the specified parts could not be found.</Message></Error>`)
+				return
+			}
+			parts = append(parts, part...)
+		}
+		f.objects[key] = parts
+		delete(f.uploads, q.Get("uploadId"))
+		fmt.Fprint(w, "<CompleteMultipartUploadResult></CompleteMultipartUploadResult>")
+	case r.Method == http.MethodDelete:
+		delete(f.uploads, q.Get("uploadId"))
+		f.aborted++
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[key] = body
+	case r.Method == http.MethodGet && key != "":
+		body, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`)
+			return
+		}
+		_, _ = w.Write(body)
+	default:
+		prefix := r.URL.Query().Get("prefix")
+		var keys []string
+		for k := range f.objects {
+			if strings.HasPrefix(k, prefix) && k > r.URL.Query().Get("continuation-token") {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		truncated := len(keys) > f.pageSize
+		if truncated {
+			keys = keys[:f.pageSize]
+		}
+		fmt.Fprint(w, "<ListBucketResult>")
+		for _, k := range keys {
+			fmt.Fprintf(w, "<Contents><Key>%s</Key></Contents>", k)
+		}
+		if truncated {
+			fmt.Fprintf(w, "<IsTruncated>true</IsTruncated><NextContinuationToken>%s</NextContinuationToken>", keys[len(keys)-1])
+		}
+		fmt.Fprint(w, "</ListBucketResult>")
+	}
+}
+
+func newTestClient(t *testing.T, srv *fakeS3, creds Credentials) *Client {
+	t.Helper()
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	u, _ := url.Parse(ts.URL)
+	return NewClient("archive", "eu-central-1", creds, WithEndpoint(u))
+}
+
+var testCreds = Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}
+
+func TestClient_PutGetList(t *testing.T) {
+	srv := &fakeS3{objects: map[string][]byte{}, pageSize: 2}
+	c := newTestClient(t, srv, testCreds)
+	ctx := context.Background()
+
+	keys := []string{
+		"events/dt=2025-09-01/1-10.parquet",
+		"events/dt=2025-09-01/11-20.parquet",
+		"events/dt=2025-09-01/21-30.parquet",
+		"events/dt=2025-09-02/31-40.parquet",
+	}
+	for _, k := range keys {
+		if err := c.Put(ctx, k, []byte("data "+k)); err != nil {
+			t.Fatalf("put %s: %v", k, err)
+		}
+	}
+
+	got, err := c.List(ctx, "events/dt=2025-09-01/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 3 || got[2] != keys[2] {
+		t.Fatalf("expected 3 keys across pages, got %v", got)
+	}
+
+	body, err := c.Get(ctx, keys[3])
+	if err != nil || string(body) != "data "+keys[3] {
+		t.Fatalf("unexpected object %q (%v)", body, err)
+	}
+
+	if _, err := c.Get(ctx, "missing"); err == nil || !strings.Contains(err.Error(), "NoSuchKey") {
+		t.Fatalf("expected NoSuchKey, got %v", err)
+	}
+
+	for _, auth := range srv.auth {
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") ||
+			!strings.Contains(auth, "/eu-central-1/s3/aws4_request, SignedHeaders=") {
+			t.Fatalf("unexpected authorization header: %s", auth)
+		}
+	}
+}
+
+func TestUpload_Multipart(t *testing.T) {
+	srv := &fakeS3{objects: map[string][]byte{}}
+	c := newTestClient(t, srv, testCreds)
+	c.partSize = 4
+	ctx := context.Background()
+
+	u := c.NewUpload(ctx, "exports/events.csv")
+	for _, chunk := range []string{"a,b\n", "1,2\n3", ",4\n"} {
+		if _, err := u.Write([]byte(chunk)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := u.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := string(srv.objects["exports/events.csv"]); got != "a,b\n1,2\n3,4\n" {
+		t.Fatalf("unexpected object %q", got)
+	}
+	if len(srv.uploads) != 0 {
+		t.Fatalf("expected the upload to be completed, got %v", srv.uploads)
+	}
+	if got := u.URI(); got != "s3://archive/exports/events.csv" {
+		t.Fatalf("unexpected uri %s", got)
+	}
+}
+
+func TestUpload_SmallObjectIsOnePut(t *testing.T) {
+	srv := &fakeS3{objects: map[string][]byte{}}
+	c := newTestClient(t, srv, testCreds)
+
+	u := c.NewUpload(context.Background(), "exports/small.csv")
+	if _, err := u.Write([]byte("a,b\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := u.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(srv.objects["exports/small.csv"]) != "a,b\n" || srv.uploads != nil {
+		t.Fatalf("expected one PutObject, got objects %v uploads %v", srv.objects, srv.uploads)
+	}
+}
+
+func TestUpload_Abort(t *testing.T) {
+	srv := &fakeS3{objects: map[string][]byte{}}
+	c := newTestClient(t, srv, testCreds)
+	c.partSize = 4
+
+	u := c.NewUpload(context.Background(), "exports/events.csv")
+	if _, err := u.Write([]byte("a,b\n1,2\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := u.Abort(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := srv.objects["exports/events.csv"]; ok || len(srv.uploads) != 0 || srv.aborted != 1 {
+		t.Fatalf("expected the upload to be dropped, got objects %v uploads %v", srv.objects, srv.uploads)
+	}
+	if err := u.Close(); err == nil {
+		t.Fatalf("expected an error closing an aborted upload")
+	}
+}
+
+func TestClient_SessionToken(t *testing.T) {
+	srv := &fakeS3{objects: map[string][]byte{}}
+	c := newTestClient(t, srv, Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "token"})
+
+	if err := c.Put(context.Background(), "events/a.parquet", []byte("data")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(srv.tokens) != 1 || srv.tokens[0] != "token" {
+		t.Fatalf("expected the session token to be sent, got %v", srv.tokens)
+	}
+	if !strings.Contains(srv.auth[0], "x-amz-security-token") {
+		t.Fatalf("expected the session token to be signed: %s", srv.auth[0])
+	}
+}
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Record is one archived event with the columns of the events table.
+type Record struct {
+	ID          int64
+	EventID     string
+	EventName   string
+	Channel     string
+	CampaignID  string
+	UserID      string
+	AnonymousID string
+	EventTime   time.Time
+	ReceivedAt  time.Time
+	Value       *float64
+	Tags        []string
+	Metadata    json.RawMessage
+	DedupeKey   string
+	TenantID    string
+	// SampleRate is the share of events with this name ingest sampling kept
+	// (1 for unsampled events); 0 in archives written before it was kept.
+	SampleRate float64
+}
+
+// Rate is the sample rate of r, 1 when it was not sampled or not recorded.
+func (r Record) Rate() float64 {
+	if r.SampleRate > 0 && r.SampleRate < 1 {
+		return r.SampleRate
+	}
+	return 1
+}
+
+// FileExtension of archive objects; they are Parquet files.
+const FileExtension = ".parquet"
+
+// DayPrefix is the object key prefix of the archive files holding events of
+// day (UTC), laid out Hive style so query engines can prune by date:
+//
+//	<prefix>events/dt=2025-09-01/
+func DayPrefix(prefix string, day time.Time) string {
+	return fmt.Sprintf("%sevents/dt=%s/", prefix, day.UTC().Format(time.DateOnly))
+}
+
+// FileKey names the file holding events firstID..lastID of one day. The
+// ids make keys unique across runs.
+func FileKey(prefix string, day time.Time, firstID, lastID int64) string {
+	return fmt.Sprintf("%s%d-%d%s", DayPrefix(prefix, day), firstID, lastID, FileExtension)
+}
+
+// RehydrationReport summarizes loading archived events back into Postgres.
+type RehydrationReport struct {
+	From     time.Time
+	To       time.Time
+	Files    int   // archive files read
+	Events   int   // archived events within [From, To]
+	Restored int64 // events not rehydrated before
+}
+
+// ReplayResult counts the outcome of re-ingesting a batch of records.
+type ReplayResult struct {
+	Created    int
+	Duplicates int // rejected by dedupe, e.g. still stored or replayed before
+	Rejected   int // refused by ingest validation, e.g. tags or metadata over the limits
+}
+
+// ReplayReport summarizes re-ingesting archived events.
+type ReplayReport struct {
+	From       time.Time
+	To         time.Time
+	Files      int // archive files read
+	Events     int // archived events within [From, To]
+	Created    int
+	Duplicates int
+	Rejected   int
+}
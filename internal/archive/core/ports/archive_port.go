@@ -0,0 +1,42 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"event-metrics-service/internal/archive/core/domain"
+)
+
+type EventSourcePort interface {
+	// OldestEventsBefore returns up to limit events whose event_time is
+	// before cutoff, oldest first, the same rows the retention purge would
+	// delete next.
+	OldestEventsBefore(ctx context.Context, cutoff time.Time, limit int) ([]domain.Record, error)
+}
+
+type RestorePort interface {
+	// RestoreEvents stores rehydrated events, skipping ids restored before,
+	// and returns how many were added.
+	RestoreEvents(ctx context.Context, key string, records []domain.Record) (int64, error)
+}
+
+// ReplayPort re-ingests archived events through the regular ingest path, so
+// validation and dedupe apply as they do to new events. An error aborts the
+// replay; invalid events are only counted as rejected.
+type ReplayPort interface {
+	Replay(ctx context.Context, records []domain.Record) (domain.ReplayResult, error)
+}
+
+// ObjectStorePort is the long-term storage the archive files are kept in.
+type ObjectStorePort interface {
+	Put(ctx context.Context, key string, body []byte) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	// List returns every key starting with prefix.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// CodecPort encodes archive files.
+type CodecPort interface {
+	Encode(records []domain.Record) ([]byte, error)
+	Decode(data []byte) ([]domain.Record, error)
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"event-metrics-service/internal/archive/core/domain"
+	"event-metrics-service/internal/archive/core/ports"
+)
+
+// ArchiveEventsUseCase copies events about to be purged by retention to
+// object storage, one file per event day.
+type ArchiveEventsUseCase struct {
+	source ports.EventSourcePort
+	store  ports.ObjectStorePort
+	codec  ports.CodecPort
+	prefix string
+}
+
+func NewArchiveEventsUseCase(source ports.EventSourcePort, store ports.ObjectStorePort, codec ports.CodecPort, prefix string) *ArchiveEventsUseCase {
+	return &ArchiveEventsUseCase{source: source, store: store, codec: codec, prefix: prefix}
+}
+
+// ArchiveEventsBefore archives up to limit of the oldest events before
+// cutoff and returns their ids. Only the returned ids are safe to delete: an
+// id is returned once its file has been uploaded. A run interrupted between
+// upload and delete archives the same events again into a new file, so
+// readers must tolerate duplicate ids.
+func (uc *ArchiveEventsUseCase) ArchiveEventsBefore(ctx context.Context, cutoff time.Time, limit int) ([]int64, error) {
+	records, err := uc.source.OldestEventsBefore(ctx, cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, len(records))
+	for _, day := range splitByDay(records) {
+		first, last := day[0].ID, day[0].ID
+		for _, r := range day {
+			first, last = min(first, r.ID), max(last, r.ID)
+		}
+
+		body, err := uc.codec.Encode(day)
+		if err != nil {
+			return ids, fmt.Errorf("encode archive: %w", err)
+		}
+		key := domain.FileKey(uc.prefix, day[0].EventTime, first, last)
+		if err := uc.store.Put(ctx, key, body); err != nil {
+			return ids, fmt.Errorf("upload %s: %w", key, err)
+		}
+
+		for _, r := range day {
+			ids = append(ids, r.ID)
+		}
+	}
+	return ids, nil
+}
+
+// splitByDay groups records, which are ordered by event_time, by UTC day.
+func splitByDay(records []domain.Record) [][]domain.Record {
+	var out [][]domain.Record
+	start := 0
+	for i := 1; i <= len(records); i++ {
+		if i == len(records) || !sameDay(records[i].EventTime, records[start].EventTime) {
+			out = append(out, records[start:i])
+			start = i
+		}
+	}
+	return out
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.UTC().Date()
+	by, bm, bd := b.UTC().Date()
+	return ay == by && am == bm && ad == bd
+}
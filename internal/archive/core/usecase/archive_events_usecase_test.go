@@ -0,0 +1,271 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/archive/core/domain"
+	"event-metrics-service/internal/archive/core/ports"
+	"event-metrics-service/internal/archive/core/usecase"
+)
+
+type fakeSource struct {
+	records []domain.Record
+}
+
+func (f *fakeSource) OldestEventsBefore(ctx context.Context, cutoff time.Time, limit int) ([]domain.Record, error) {
+	var out []domain.Record
+	for _, r := range f.records {
+		if r.EventTime.Before(cutoff) && len(out) < limit {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+type fakeObjectStore struct {
+	objects map[string][]byte
+	putErr  error
+}
+
+func (f *fakeObjectStore) Put(ctx context.Context, key string, body []byte) error {
+	if f.putErr != nil {
+		return f.putErr
+	}
+	f.objects[key] = body
+	return nil
+}
+
+func (f *fakeObjectStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return f.objects[key], nil
+}
+
+func (f *fakeObjectStore) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	for k := range f.objects {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// jsonCodec stands in for the Parquet codec.
+type jsonCodec struct{}
+
+func (jsonCodec) Encode(records []domain.Record) ([]byte, error) { return json.Marshal(records) }
+
+func (jsonCodec) Decode(data []byte) ([]domain.Record, error) {
+	var out []domain.Record
+	err := json.Unmarshal(data, &out)
+	return out, err
+}
+
+func record(id int64, at time.Time) domain.Record {
+	return domain.Record{ID: id, EventName: "purchase", EventTime: at, Metadata: json.RawMessage(`{}`)}
+}
+
+// ------------------------------------------------------------
+// ARCHIVE
+// ------------------------------------------------------------
+
+func TestArchiveEvents_OneFilePerDay(t *testing.T) {
+	day1 := time.Date(2025, 9, 1, 23, 0, 0, 0, time.UTC)
+	day2 := time.Date(2025, 9, 2, 1, 0, 0, 0, time.UTC)
+	source := &fakeSource{records: []domain.Record{record(3, day1), record(1, day1), record(2, day2), record(9, day2.Add(time.Hour))}}
+	store := &fakeObjectStore{objects: map[string][]byte{}}
+	uc := usecase.NewArchiveEventsUseCase(source, store, jsonCodec{}, "ems/")
+
+	ids, err := uc.ArchiveEventsBefore(context.Background(), day2.Add(time.Hour), 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ids) != 3 || ids[0] != 3 || ids[2] != 2 {
+		t.Fatalf("unexpected ids: %v", ids)
+	}
+
+	keys, _ := store.List(context.Background(), "")
+	want := []string{"ems/events/dt=2025-09-01/1-3.parquet", "ems/events/dt=2025-09-02/2-2.parquet"}
+	if strings.Join(keys, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected keys %v, got %v", want, keys)
+	}
+}
+
+func TestArchiveEvents_UploadFailureReturnsNoIDs(t *testing.T) {
+	at := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	store := &fakeObjectStore{objects: map[string][]byte{}, putErr: errors.New("access denied")}
+	uc := usecase.NewArchiveEventsUseCase(&fakeSource{records: []domain.Record{record(1, at)}}, store, jsonCodec{}, "")
+
+	ids, err := uc.ArchiveEventsBefore(context.Background(), at.Add(time.Hour), 100)
+	if err == nil || len(ids) != 0 {
+		t.Fatalf("expected an error and no ids, got %v / %v", ids, err)
+	}
+}
+
+// ------------------------------------------------------------
+// REHYDRATE
+// ------------------------------------------------------------
+
+type fakeRestorer struct {
+	restored map[int64]bool
+	keys     []string
+}
+
+func (f *fakeRestorer) RestoreEvents(ctx context.Context, key string, records []domain.Record) (int64, error) {
+	f.keys = append(f.keys, key)
+	var n int64
+	for _, r := range records {
+		if !f.restored[r.ID] {
+			f.restored[r.ID] = true
+			n++
+		}
+	}
+	return n, nil
+}
+
+func TestRehydrate_RestoresRangeOnce(t *testing.T) {
+	day1 := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+	day2 := time.Date(2025, 9, 2, 10, 0, 0, 0, time.UTC)
+	store := &fakeObjectStore{objects: map[string][]byte{}}
+	archive := usecase.NewArchiveEventsUseCase(
+		&fakeSource{records: []domain.Record{record(1, day1), record(2, day1.Add(time.Hour)), record(3, day2)}},
+		store, jsonCodec{}, "",
+	)
+	if _, err := archive.ArchiveEventsBefore(context.Background(), day2.Add(time.Hour), 100); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	restorer := &fakeRestorer{restored: map[int64]bool{}}
+	uc := usecase.NewRehydrateUseCase(store, jsonCodec{}, restorer, "")
+
+	report, err := uc.Execute(context.Background(), day1.Add(30*time.Minute), day2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Files != 2 || report.Events != 2 || report.Restored != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if !restorer.restored[2] || !restorer.restored[3] || restorer.restored[1] {
+		t.Fatalf("expected only events within the range, got %v", restorer.restored)
+	}
+
+	report, err = uc.Execute(context.Background(), day1.Add(30*time.Minute), day2)
+	if err != nil || report.Restored != 0 || report.Events != 2 {
+		t.Fatalf("expected nothing new on a second run, got %+v (%v)", report, err)
+	}
+}
+
+func TestRehydrate_InvalidRange(t *testing.T) {
+	uc := usecase.NewRehydrateUseCase(&fakeObjectStore{}, jsonCodec{}, &fakeRestorer{}, "")
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, to := range []time.Time{from.Add(-time.Second), from.AddDate(0, 0, usecase.MaxRehydrationDays+1)} {
+		if _, err := uc.Execute(context.Background(), from, to); !errors.Is(err, usecase.ErrInvalidRange) {
+			t.Fatalf("%s: expected ErrInvalidRange, got %v", to, err)
+		}
+	}
+}
+
+// ------------------------------------------------------------
+// REPLAY
+// ------------------------------------------------------------
+
+// fakeReplayer dedupes by id and rejects events without a name.
+type fakeReplayer struct {
+	stored  map[int64]bool
+	batches []int
+	err     error
+}
+
+func (f *fakeReplayer) Replay(ctx context.Context, records []domain.Record) (domain.ReplayResult, error) {
+	f.batches = append(f.batches, len(records))
+	var res domain.ReplayResult
+	for _, r := range records {
+		switch {
+		case r.EventName == "":
+			res.Rejected++
+		case f.stored[r.ID]:
+			res.Duplicates++
+		default:
+			f.stored[r.ID] = true
+			res.Created++
+		}
+	}
+	return res, f.err
+}
+
+func TestReplay_ReingestsRangeByExtension(t *testing.T) {
+	day := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+	invalid := record(3, day)
+	invalid.EventName = ""
+	parquetFile, _ := jsonCodec{}.Encode([]domain.Record{record(1, day), record(2, day.Add(-time.Hour))})
+	ndjsonFile, _ := jsonCodec{}.Encode([]domain.Record{invalid, record(4, day)})
+	store := &fakeObjectStore{objects: map[string][]byte{
+		domain.FileKey("", day, 1, 2):                        parquetFile,
+		domain.DayPrefix("", day) + "export.ndjson":          ndjsonFile,
+		domain.DayPrefix("", day) + "_SUCCESS":               nil,
+		domain.DayPrefix("", day.AddDate(0, 0, 1)) + "x.csv": []byte("not decoded"),
+	}}
+
+	replayer := &fakeReplayer{stored: map[int64]bool{}}
+	uc := usecase.NewReplayUseCase(store, map[string]ports.CodecPort{
+		domain.FileExtension: jsonCodec{},
+		".ndjson":            jsonCodec{},
+	}, replayer, "")
+
+	from, to := day.Add(-30*time.Minute), day.AddDate(0, 0, 1)
+	report, err := uc.Execute(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Files != 2 || report.Events != 3 || report.Created != 2 || report.Rejected != 1 || report.Duplicates != 0 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if replayer.stored[2] {
+		t.Fatal("event outside the range must not be replayed")
+	}
+
+	report, err = uc.Execute(context.Background(), from, to)
+	if err != nil || report.Created != 0 || report.Duplicates != 2 {
+		t.Fatalf("expected duplicates on a second run, got %+v (%v)", report, err)
+	}
+}
+
+func TestReplay_BatchesAndStopsOnError(t *testing.T) {
+	day := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+	records := make([]domain.Record, usecase.ReplayBatchSize+1)
+	for i := range records {
+		records[i] = record(int64(i+1), day)
+	}
+	file, _ := jsonCodec{}.Encode(records)
+	store := &fakeObjectStore{objects: map[string][]byte{domain.FileKey("", day, 1, int64(len(records))): file}}
+	codecs := map[string]ports.CodecPort{domain.FileExtension: jsonCodec{}}
+
+	replayer := &fakeReplayer{stored: map[int64]bool{}}
+	report, err := usecase.NewReplayUseCase(store, codecs, replayer, "").Execute(context.Background(), day, day)
+	if err != nil || report.Created != len(records) {
+		t.Fatalf("unexpected result: %+v (%v)", report, err)
+	}
+	if len(replayer.batches) != 2 || replayer.batches[0] != usecase.ReplayBatchSize || replayer.batches[1] != 1 {
+		t.Fatalf("unexpected batches: %v", replayer.batches)
+	}
+
+	failing := &fakeReplayer{stored: map[int64]bool{}, err: errors.New("db down")}
+	if _, err := usecase.NewReplayUseCase(store, codecs, failing, "").Execute(context.Background(), day, day); err == nil || len(failing.batches) != 1 {
+		t.Fatalf("expected the replay to stop at the first error, got %v after %d batches", err, len(failing.batches))
+	}
+}
+
+func TestReplay_InvalidRange(t *testing.T) {
+	uc := usecase.NewReplayUseCase(&fakeObjectStore{}, nil, &fakeReplayer{}, "")
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := uc.Execute(context.Background(), from, from.Add(-time.Second)); !errors.Is(err, usecase.ErrInvalidRange) {
+		t.Fatalf("expected ErrInvalidRange, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"time"
+
+	"event-metrics-service/internal/archive/core/domain"
+	"event-metrics-service/internal/archive/core/ports"
+)
+
+var ErrInvalidRange = errors.New("invalid archive range")
+
+// MaxRehydrationDays bounds one rehydration or replay request; larger ranges
+// are handled in several calls.
+const MaxRehydrationDays = 31
+
+func validateRange(from, to time.Time) error {
+	if from.IsZero() || to.Before(from) {
+		return fmt.Errorf("%w: from must not be after to", ErrInvalidRange)
+	}
+	if to.Sub(from) > MaxRehydrationDays*24*time.Hour {
+		return fmt.Errorf("%w: at most %d days per request", ErrInvalidRange, MaxRehydrationDays)
+	}
+	return nil
+}
+
+// archiveRange reads the archive files of the days [from, to] touches.
+type archiveRange struct {
+	store  ports.ObjectStorePort
+	codecs map[string]ports.CodecPort // by file extension
+	prefix string
+}
+
+// scan hands fn the records of each file whose event_time is within
+// [from, to], skipping files left with none, and returns the number of files
+// read. Files with an extension no codec handles are ignored.
+func (a archiveRange) scan(ctx context.Context, from, to time.Time, fn func(key string, records []domain.Record) error) (int, error) {
+	files := 0
+	for day := from.Truncate(24 * time.Hour); !day.After(to); day = day.Add(24 * time.Hour) {
+		keys, err := a.store.List(ctx, domain.DayPrefix(a.prefix, day))
+		if err != nil {
+			return files, err
+		}
+
+		for _, key := range keys {
+			codec, ok := a.codecs[path.Ext(key)]
+			if !ok {
+				continue
+			}
+			body, err := a.store.Get(ctx, key)
+			if err != nil {
+				return files, err
+			}
+			records, err := codec.Decode(body)
+			if err != nil {
+				return files, fmt.Errorf("decode %s: %w", key, err)
+			}
+			files++
+
+			var inRange []domain.Record
+			for _, r := range records {
+				if !r.EventTime.Before(from) && !r.EventTime.After(to) {
+					inRange = append(inRange, r)
+				}
+			}
+			if len(inRange) == 0 {
+				continue
+			}
+			if err := fn(key, inRange); err != nil {
+				return files, err
+			}
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"event-metrics-service/internal/archive/core/domain"
+	"event-metrics-service/internal/archive/core/ports"
+)
+
+// RehydrateUseCase loads archived events back into Postgres.
+type RehydrateUseCase struct {
+	archive  archiveRange
+	restorer ports.RestorePort
+}
+
+func NewRehydrateUseCase(store ports.ObjectStorePort, codec ports.CodecPort, restorer ports.RestorePort, prefix string) *RehydrateUseCase {
+	return &RehydrateUseCase{
+		archive: archiveRange{
+			store:  store,
+			codecs: map[string]ports.CodecPort{domain.FileExtension: codec},
+			prefix: prefix,
+		},
+		restorer: restorer,
+	}
+}
+
+// Execute restores the archived events whose event_time is within
+// [from, to]. Running it again for the same range restores nothing new.
+func (uc *RehydrateUseCase) Execute(ctx context.Context, from, to time.Time) (domain.RehydrationReport, error) {
+	from, to = from.UTC(), to.UTC()
+	if err := validateRange(from, to); err != nil {
+		return domain.RehydrationReport{}, err
+	}
+
+	report := domain.RehydrationReport{From: from, To: to}
+	files, err := uc.archive.scan(ctx, from, to, func(key string, records []domain.Record) error {
+		report.Events += len(records)
+		n, err := uc.restorer.RestoreEvents(ctx, key, records)
+		report.Restored += n
+		return err
+	})
+	report.Files = files
+	return report, err
+}
@@ -0,0 +1,34 @@
+package fiber
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type QueryListResponse struct {
+	Queries []QueryResponse `json:"queries"`
+}
+
+// QueryResponse is one audited metrics query. Filter holds the query with
+// the parameter names of GET /metrics.
+type QueryResponse struct {
+	ID         int64           `json:"id" example:"81234"`
+	APIKeyHash string          `json:"api_key_hash"`
+	Subject    string          `json:"subject,omitempty"`
+	TenantID   string          `json:"tenant_id,omitempty"`
+	Operation  string          `json:"operation" example:"query"`
+	EventName  string          `json:"event_name" example:"purchase"`
+	GroupBy    string          `json:"group_by,omitempty" example:"channel,time"`
+	From       time.Time       `json:"from"`
+	To         time.Time       `json:"to"`
+	Filter     json.RawMessage `json:"filter" swaggertype:"object"`
+	DurationMS float64         `json:"duration_ms" example:"412.5"`
+	Rows       int             `json:"rows" example:"168"`
+	Error      string          `json:"error,omitempty"`
+	StartedAt  time.Time       `json:"started_at"`
+}
+
+type ErrorResponse struct {
+	Error   string `json:"error" example:"invalid_query"`
+	Message string `json:"message" example:"invalid audit query: limit must be between 1 and 1000"`
+}
@@ -0,0 +1,135 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"event-metrics-service/internal/audit/core/domain"
+	"event-metrics-service/internal/audit/core/ports"
+	"event-metrics-service/internal/audit/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditUseCase interface {
+	List(ctx context.Context, f ports.QueryFilter) ([]domain.Query, error)
+}
+
+type AuditHandler struct {
+	uc AuditUseCase
+}
+
+func NewAuditHandler(uc AuditUseCase) *AuditHandler {
+	return &AuditHandler{uc: uc}
+}
+
+// ListQueries godoc
+// @Summary List audited metrics queries
+// @Description Returns the metrics queries callers ran, with their filter, duration and the rows they returned, newest
+// @Description first or slowest first. Only available when QUERY_AUDIT_ENABLED is set; queries are kept for
+// @Description QUERY_AUDIT_RETENTION.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param api_key_hash query string false "SHA-256 of the caller's API key (hex)"
+// @Param tenant_id query string false "Tenant ID"
+// @Param event_name query string false "Event name, as queried"
+// @Param since query int false "Started at or after (unix seconds)"
+// @Param until query int false "Started before (unix seconds)"
+// @Param min_duration_ms query int false "Only queries that took at least this long"
+// @Param failed query bool false "Only queries that returned an error"
+// @Param order query string false "recent (default) or duration"
+// @Param limit query int false "Maximum entries (default 100, max 1000)"
+// @Success 200 {object} QueryListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/metrics-queries [get]
+func (h *AuditHandler) ListQueries(c *fiber.Ctx) error {
+	f := ports.QueryFilter{
+		APIKeyHash: c.Query("api_key_hash"),
+		TenantID:   c.Query("tenant_id"),
+		EventName:  c.Query("event_name"),
+		OrderBy:    c.Query("order"),
+	}
+
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"since", &f.Since}, {"until", &f.Until}} {
+		raw := c.Query(p.name)
+		if raw == "" {
+			continue
+		}
+		sec, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return invalidQuery(c, "invalid '"+p.name+"' parameter")
+		}
+		*p.dst = time.Unix(sec, 0).UTC()
+	}
+	if raw := c.Query("min_duration_ms"); raw != "" {
+		ms, err := strconv.Atoi(raw)
+		if err != nil || ms < 0 {
+			return invalidQuery(c, "invalid 'min_duration_ms' parameter")
+		}
+		f.MinDuration = time.Duration(ms) * time.Millisecond
+	}
+	if raw := c.Query("failed"); raw != "" {
+		failed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return invalidQuery(c, "invalid 'failed' parameter")
+		}
+		f.FailedOnly = failed
+	}
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			return invalidQuery(c, "invalid 'limit' parameter")
+		}
+		f.Limit = n
+	}
+
+	queries, err := h.uc.List(c.UserContext(), f)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidAuditQuery) {
+			return invalidQuery(c, err.Error())
+		}
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error: "internal_server_error",
+		})
+	}
+
+	resp := QueryListResponse{Queries: make([]QueryResponse, 0, len(queries))}
+	for _, q := range queries {
+		resp.Queries = append(resp.Queries, toQueryResponse(q))
+	}
+	return c.JSON(resp)
+}
+
+func toQueryResponse(q domain.Query) QueryResponse {
+	return QueryResponse{
+		ID:         q.ID,
+		APIKeyHash: q.APIKeyHash,
+		Subject:    q.Subject,
+		TenantID:   q.TenantID,
+		Operation:  q.Operation,
+		EventName:  q.EventName,
+		GroupBy:    q.GroupBy,
+		From:       q.From,
+		To:         q.To,
+		Filter:     q.Filter,
+		DurationMS: float64(q.Duration) / float64(time.Millisecond),
+		Rows:       q.Rows,
+		Error:      q.Error,
+		StartedAt:  q.StartedAt,
+	}
+}
+
+func invalidQuery(c *fiber.Ctx, msg string) error {
+	return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+		Error:   "invalid_query",
+		Message: msg,
+	})
+}
@@ -0,0 +1,104 @@
+package fiber
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/audit/core/domain"
+	"event-metrics-service/internal/audit/core/ports"
+	"event-metrics-service/internal/audit/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeAuditUseCase struct {
+	ListFn func(ctx context.Context, f ports.QueryFilter) ([]domain.Query, error)
+}
+
+func (f *fakeAuditUseCase) List(ctx context.Context, filter ports.QueryFilter) ([]domain.Query, error) {
+	return f.ListFn(ctx, filter)
+}
+
+func setupAuditApp(uc AuditUseCase) *fiber.App {
+	app := fiber.New()
+	app.Get("/admin/metrics-queries", NewAuditHandler(uc).ListQueries)
+	return app
+}
+
+func TestListQueries(t *testing.T) {
+	started := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	var got ports.QueryFilter
+	uc := &fakeAuditUseCase{
+		ListFn: func(ctx context.Context, f ports.QueryFilter) ([]domain.Query, error) {
+			got = f
+			return []domain.Query{{
+				ID:         7,
+				APIKeyHash: "hash",
+				Operation:  domain.OperationQuery,
+				EventName:  "purchase",
+				Filter:     []byte(`{"event_name":"purchase"}`),
+				Duration:   412500 * time.Microsecond,
+				Rows:       3,
+				StartedAt:  started,
+			}}, nil
+		},
+	}
+
+	path := "/admin/metrics-queries?api_key_hash=hash&event_name=purchase&since=1764547200&min_duration_ms=250&failed=true&order=duration&limit=10"
+	resp, err := setupAuditApp(uc).Test(httptest.NewRequest(http.MethodGet, path, nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	want := ports.QueryFilter{
+		APIKeyHash:  "hash",
+		EventName:   "purchase",
+		Since:       time.Unix(1764547200, 0).UTC(),
+		MinDuration: 250 * time.Millisecond,
+		FailedOnly:  true,
+		OrderBy:     ports.OrderDuration,
+		Limit:       10,
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("expected filter %+v, got %+v", want, got)
+	}
+
+	var body QueryListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if len(body.Queries) != 1 || body.Queries[0].DurationMS != 412.5 || string(body.Queries[0].Filter) != `{"event_name":"purchase"}` {
+		t.Fatalf("unexpected response: %+v", body)
+	}
+}
+
+func TestListQueries_InvalidParams(t *testing.T) {
+	uc := &fakeAuditUseCase{
+		ListFn: func(ctx context.Context, f ports.QueryFilter) ([]domain.Query, error) {
+			if f.OrderBy == "rows" {
+				return nil, fmt.Errorf("%w: order must be \"recent\" or \"duration\"", usecase.ErrInvalidAuditQuery)
+			}
+			t.Fatalf("unexpected list")
+			return nil, nil
+		},
+	}
+	app := setupAuditApp(uc)
+
+	for _, query := range []string{"since=yesterday", "min_duration_ms=-1", "failed=maybe", "limit=0", "order=rows"} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/admin/metrics-queries?"+query, nil))
+		if err != nil {
+			t.Fatalf("app.Test error: %v", err)
+		}
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d", query, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"event-metrics-service/internal/audit/core/domain"
+	"event-metrics-service/internal/audit/core/ports"
+)
+
+type RowScanner interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+type DB interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+type AuditRepository struct {
+	db DB
+}
+
+func NewAuditRepository(db DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+var _ ports.AuditRepositoryPort = (*AuditRepository)(nil)
+
+const recordQuerySQL = `
+INSERT INTO metrics_query_audit (
+    api_key_hash, subject, tenant_id, operation, event_name, group_by,
+    from_time, to_time, filter, duration_ms, rows_returned, error, started_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+
+const listQueriesSQL = `
+SELECT id, api_key_hash, subject, tenant_id, operation, event_name, group_by,
+       from_time, to_time, filter, duration_ms, rows_returned, error, started_at
+FROM metrics_query_audit`
+
+const purgeQueriesSQL = `
+DELETE FROM metrics_query_audit
+WHERE id IN (
+    SELECT id FROM metrics_query_audit
+    WHERE started_at < $1
+    LIMIT $2
+);
+`
+
+func (r *AuditRepository) RecordQuery(ctx context.Context, q domain.Query) error {
+	_, err := r.db.ExecContext(ctx, recordQuerySQL,
+		q.APIKeyHash,
+		q.Subject,
+		q.TenantID,
+		q.Operation,
+		q.EventName,
+		q.GroupBy,
+		q.From,
+		q.To,
+		[]byte(q.Filter),
+		float64(q.Duration)/float64(time.Millisecond),
+		q.Rows,
+		q.Error,
+		q.StartedAt,
+	)
+	return err
+}
+
+func (r *AuditRepository) ListQueries(ctx context.Context, f ports.QueryFilter) ([]domain.Query, error) {
+	var conds []string
+	var args []any
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if f.APIKeyHash != "" {
+		add("api_key_hash = $%d", f.APIKeyHash)
+	}
+	if f.TenantID != "" {
+		add("tenant_id = $%d", f.TenantID)
+	}
+	if f.EventName != "" {
+		add("event_name = $%d", f.EventName)
+	}
+	if !f.Since.IsZero() {
+		add("started_at >= $%d", f.Since)
+	}
+	if !f.Until.IsZero() {
+		add("started_at < $%d", f.Until)
+	}
+	if f.MinDuration > 0 {
+		add("duration_ms >= $%d", float64(f.MinDuration)/float64(time.Millisecond))
+	}
+	if f.FailedOnly {
+		conds = append(conds, "error <> ''")
+	}
+
+	q := listQueriesSQL
+	if len(conds) > 0 {
+		q += "\nWHERE " + strings.Join(conds, " AND ")
+	}
+	if f.OrderBy == ports.OrderDuration {
+		q += "\nORDER BY duration_ms DESC, id DESC"
+	} else {
+		q += "\nORDER BY started_at DESC, id DESC"
+	}
+	args = append(args, f.Limit)
+	q += fmt.Sprintf("\nLIMIT $%d", len(args))
+
+	rows, err := r.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Query
+	for rows.Next() {
+		var (
+			e          domain.Query
+			filter     []byte
+			durationMS float64
+		)
+		if err := rows.Scan(
+			&e.ID,
+			&e.APIKeyHash,
+			&e.Subject,
+			&e.TenantID,
+			&e.Operation,
+			&e.EventName,
+			&e.GroupBy,
+			&e.From,
+			&e.To,
+			&filter,
+			&durationMS,
+			&e.Rows,
+			&e.Error,
+			&e.StartedAt,
+		); err != nil {
+			return nil, err
+		}
+		e.Filter = filter
+		e.Duration = time.Duration(durationMS * float64(time.Millisecond))
+		e.From = e.From.UTC()
+		e.To = e.To.UTC()
+		e.StartedAt = e.StartedAt.UTC()
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (r *AuditRepository) PurgeQueries(ctx context.Context, before time.Time, limit int) (int64, error) {
+	res, err := r.db.ExecContext(ctx, purgeQueriesSQL, before, limit)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
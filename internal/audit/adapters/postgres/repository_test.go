@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/audit/core/domain"
+	"event-metrics-service/internal/audit/core/ports"
+)
+
+type fakeRowScanner struct {
+	rows [][]any
+	i    int
+}
+
+func (f *fakeRowScanner) Next() bool {
+	return f.i < len(f.rows)
+}
+
+func (f *fakeRowScanner) Scan(dest ...any) error {
+	row := f.rows[f.i]
+	if len(dest) != len(row) {
+		return errors.New("dest length mismatch")
+	}
+	for i := range dest {
+		switch d := dest[i].(type) {
+		case *[]byte:
+			*d = row[i].([]byte)
+		case *int:
+			*d = row[i].(int)
+		case *int64:
+			*d = row[i].(int64)
+		case *float64:
+			*d = row[i].(float64)
+		case *string:
+			*d = row[i].(string)
+		case *time.Time:
+			*d = row[i].(time.Time)
+		default:
+			return errors.New("unsupported dest type")
+		}
+	}
+	f.i++
+	return nil
+}
+
+func (f *fakeRowScanner) Err() error   { return nil }
+func (f *fakeRowScanner) Close() error { return nil }
+
+type fakeResult struct{ n int64 }
+
+func (r fakeResult) LastInsertId() (int64, error) { return 0, nil }
+func (r fakeResult) RowsAffected() (int64, error) { return r.n, nil }
+
+type fakeDB struct {
+	ExecFn  func(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryFn func(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+func (f *fakeDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return f.ExecFn(ctx, query, args...)
+}
+
+func (f *fakeDB) QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error) {
+	return f.QueryFn(ctx, query, args...)
+}
+
+func TestAuditRepository_Record(t *testing.T) {
+	var gotArgs []any
+	db := &fakeDB{
+		ExecFn: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+			if !strings.Contains(query, "INSERT INTO metrics_query_audit") {
+				t.Fatalf("expected insert query, got %s", query)
+			}
+			gotArgs = args
+			return fakeResult{n: 1}, nil
+		},
+	}
+
+	err := NewAuditRepository(db).RecordQuery(context.Background(), domain.Query{
+		APIKeyHash: "hash",
+		Operation:  domain.OperationQuery,
+		EventName:  "purchase",
+		Filter:     []byte(`{"event_name":"purchase"}`),
+		Duration:   1500 * time.Microsecond,
+		Rows:       4,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(gotArgs) != 13 || gotArgs[0] != "hash" || gotArgs[9] != 1.5 || gotArgs[10] != 4 {
+		t.Fatalf("unexpected args: %v", gotArgs)
+	}
+}
+
+func TestAuditRepository_ListFilters(t *testing.T) {
+	started := time.Date(2025, 12, 7, 10, 0, 0, 0, time.FixedZone("TRT", 3*3600))
+	since := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			for _, want := range []string{
+				"WHERE api_key_hash = $1 AND event_name = $2 AND started_at >= $3 AND duration_ms >= $4 AND error <> ''",
+				"ORDER BY duration_ms DESC, id DESC",
+				"LIMIT $5",
+			} {
+				if !strings.Contains(query, want) {
+					t.Fatalf("expected %q in query, got: %s", want, query)
+				}
+			}
+			if args[0] != "hash" || args[1] != "purchase" || args[3] != 250.0 || args[4] != 10 {
+				t.Fatalf("unexpected args: %v", args)
+			}
+			return &fakeRowScanner{rows: [][]any{{
+				int64(7), "hash", "", "", "query", "purchase", "channel",
+				started, started.Add(time.Hour), []byte(`{"event_name":"purchase"}`), 412.5, 3, "boom", started,
+			}}}, nil
+		},
+	}
+
+	got, err := NewAuditRepository(db).ListQueries(context.Background(), ports.QueryFilter{
+		APIKeyHash:  "hash",
+		EventName:   "purchase",
+		Since:       since,
+		MinDuration: 250 * time.Millisecond,
+		FailedOnly:  true,
+		OrderBy:     ports.OrderDuration,
+		Limit:       10,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 query, got %d", len(got))
+	}
+	q := got[0]
+	if q.ID != 7 || q.Duration != 412500*time.Microsecond || q.Rows != 3 || q.StartedAt.Location() != time.UTC {
+		t.Fatalf("unexpected query: %+v", q)
+	}
+}
+
+func TestAuditRepository_ListWithoutFilters(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if strings.Contains(query, "WHERE") || !strings.Contains(query, "ORDER BY started_at DESC, id DESC") {
+				t.Fatalf("unexpected query: %s", query)
+			}
+			if len(args) != 1 || args[0] != 100 {
+				t.Fatalf("unexpected args: %v", args)
+			}
+			return &fakeRowScanner{}, nil
+		},
+	}
+
+	got, err := NewAuditRepository(db).ListQueries(context.Background(), ports.QueryFilter{Limit: 100})
+	if err != nil || len(got) != 0 {
+		t.Fatalf("expected no queries, got %v %v", got, err)
+	}
+}
+
+func TestAuditRepository_PurgeError(t *testing.T) {
+	db := &fakeDB{
+		ExecFn: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+			return nil, errors.New("db failure")
+		},
+	}
+
+	if _, err := NewAuditRepository(db).PurgeQueries(context.Background(), time.Now(), 10); err == nil {
+		t.Fatalf("expected error")
+	}
+}
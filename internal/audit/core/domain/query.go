@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Operations a metrics query is audited under.
+const (
+	OperationQuery  = "query"  // one query, e.g. GET /metrics
+	OperationBatch  = "batch"  // one query of a batch, timed as the whole batch
+	OperationStream = "stream" // groups streamed as read, timed until the last one is sent
+)
+
+// Query is one metrics query run on behalf of a caller, kept so admins can
+// see who issues which queries and what they cost.
+type Query struct {
+	ID         int64
+	APIKeyHash string // SHA-256 of the caller's API key; the key itself is never stored
+	Subject    string // "sub" of the caller's bearer token, if any
+	TenantID   string
+	Operation  string
+
+	EventName string
+	GroupBy   string
+	From      time.Time
+	To        time.Time
+	Filter    json.RawMessage // the complete query, with the parameter names of GET /metrics
+
+	Duration time.Duration
+	Rows     int    // groups, histogram buckets, or 1 for an ungrouped result
+	Error    string // "" when the query succeeded
+
+	StartedAt time.Time
+}
@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"event-metrics-service/internal/audit/core/domain"
+)
+
+// Orderings of ListQueries.
+const (
+	OrderRecent   = "recent"   // newest first
+	OrderDuration = "duration" // slowest first
+)
+
+// QueryFilter selects audited queries. Zero fields do not filter.
+type QueryFilter struct {
+	APIKeyHash  string
+	TenantID    string
+	EventName   string
+	Since       time.Time // started at or after
+	Until       time.Time // started before
+	MinDuration time.Duration
+	FailedOnly  bool
+	OrderBy     string // OrderRecent or OrderDuration
+	Limit       int
+}
+
+type AuditRepositoryPort interface {
+	RecordQuery(ctx context.Context, q domain.Query) error
+	ListQueries(ctx context.Context, f QueryFilter) ([]domain.Query, error)
+	// PurgeQueries deletes up to limit queries started before before and
+	// returns how many were removed.
+	PurgeQueries(ctx context.Context, before time.Time, limit int) (int64, error)
+}
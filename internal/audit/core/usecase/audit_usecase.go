@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"event-metrics-service/internal/audit/core/domain"
+	"event-metrics-service/internal/audit/core/ports"
+)
+
+var ErrInvalidAuditQuery = errors.New("invalid audit query")
+
+const (
+	DefaultRetention = 30 * 24 * time.Hour
+
+	DefaultListLimit = 100
+	MaxListLimit     = 1000
+
+	// MaxErrorLength caps the stored error message.
+	MaxErrorLength = 1024
+
+	DefaultPurgeBatchSize = 5000
+)
+
+// AuditUseCase records the metrics queries callers run and lists them back
+// to admins until they are older than the retention.
+type AuditUseCase struct {
+	repo      ports.AuditRepositoryPort
+	retention time.Duration
+	now       func() time.Time
+}
+
+func NewAuditUseCase(repo ports.AuditRepositoryPort, retention time.Duration) *AuditUseCase {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &AuditUseCase{repo: repo, retention: retention, now: time.Now}
+}
+
+type RecordInput struct {
+	APIKey    string
+	Subject   string
+	TenantID  string
+	Operation string
+
+	EventName string
+	GroupBy   string
+	From      int64 // unix second
+	To        int64 // unix second
+	Filter    []byte
+
+	Duration time.Duration
+	Rows     int
+	Err      error
+}
+
+// Record stores a query that has just finished, so it started Duration ago.
+func (uc *AuditUseCase) Record(ctx context.Context, in RecordInput) error {
+	var msg string
+	if in.Err != nil {
+		msg = in.Err.Error()
+		if len(msg) > MaxErrorLength {
+			msg = msg[:MaxErrorLength]
+		}
+	}
+	filter := append([]byte(nil), in.Filter...)
+	if len(filter) == 0 {
+		filter = []byte("{}")
+	}
+
+	return uc.repo.RecordQuery(ctx, domain.Query{
+		APIKeyHash: hashAPIKey(in.APIKey),
+		Subject:    in.Subject,
+		TenantID:   in.TenantID,
+		Operation:  in.Operation,
+		EventName:  in.EventName,
+		GroupBy:    in.GroupBy,
+		From:       time.Unix(in.From, 0).UTC(),
+		To:         time.Unix(in.To, 0).UTC(),
+		Filter:     filter,
+		Duration:   in.Duration,
+		Rows:       in.Rows,
+		Error:      msg,
+		StartedAt:  uc.now().Add(-in.Duration).UTC(),
+	})
+}
+
+// List returns the queries matching f, newest first unless ordered by
+// duration. A zero limit is DefaultListLimit.
+func (uc *AuditUseCase) List(ctx context.Context, f ports.QueryFilter) ([]domain.Query, error) {
+	switch f.OrderBy {
+	case "":
+		f.OrderBy = ports.OrderRecent
+	case ports.OrderRecent, ports.OrderDuration:
+	default:
+		return nil, fmt.Errorf("%w: order must be %q or %q", ErrInvalidAuditQuery, ports.OrderRecent, ports.OrderDuration)
+	}
+	if f.Limit == 0 {
+		f.Limit = DefaultListLimit
+	}
+	if f.Limit < 0 || f.Limit > MaxListLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidAuditQuery, MaxListLimit)
+	}
+	if f.MinDuration < 0 {
+		return nil, fmt.Errorf("%w: min_duration must not be negative", ErrInvalidAuditQuery)
+	}
+	if !f.Since.IsZero() && !f.Until.IsZero() && !f.Since.Before(f.Until) {
+		return nil, fmt.Errorf("%w: since must be before until", ErrInvalidAuditQuery)
+	}
+	return uc.repo.ListQueries(ctx, f)
+}
+
+// Purge deletes queries older than the retention in batches until a short
+// batch signals nothing is left, and returns the total number removed.
+func (uc *AuditUseCase) Purge(ctx context.Context) (int64, error) {
+	before := uc.now().Add(-uc.retention).UTC()
+	var total int64
+
+	for {
+		n, err := uc.repo.PurgeQueries(ctx, before, DefaultPurgeBatchSize)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if n < DefaultPurgeBatchSize {
+			return total, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+	}
+}
+
+// Run purges on every tick until ctx is cancelled.
+func (uc *AuditUseCase) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := uc.Purge(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+func hashAPIKey(key string) string {
+	if key == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
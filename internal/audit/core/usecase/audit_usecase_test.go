@@ -0,0 +1,167 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/audit/core/domain"
+	"event-metrics-service/internal/audit/core/ports"
+	"event-metrics-service/internal/audit/core/usecase"
+)
+
+type fakeAuditRepo struct {
+	RecordFn func(ctx context.Context, q domain.Query) error
+	ListFn   func(ctx context.Context, f ports.QueryFilter) ([]domain.Query, error)
+	PurgeFn  func(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+func (f *fakeAuditRepo) RecordQuery(ctx context.Context, q domain.Query) error {
+	return f.RecordFn(ctx, q)
+}
+
+func (f *fakeAuditRepo) ListQueries(ctx context.Context, filter ports.QueryFilter) ([]domain.Query, error) {
+	return f.ListFn(ctx, filter)
+}
+
+func (f *fakeAuditRepo) PurgeQueries(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return f.PurgeFn(ctx, before, limit)
+}
+
+// ------------------------------------------------------------
+// RECORD
+// ------------------------------------------------------------
+
+func TestAudit_RecordHashesKeyAndCapsError(t *testing.T) {
+	var stored domain.Query
+	repo := &fakeAuditRepo{
+		RecordFn: func(ctx context.Context, q domain.Query) error {
+			stored = q
+			return nil
+		},
+	}
+	uc := usecase.NewAuditUseCase(repo, 0)
+
+	before := time.Now()
+	err := uc.Record(context.Background(), usecase.RecordInput{
+		APIKey:    "secret-key",
+		Operation: domain.OperationQuery,
+		EventName: "purchase",
+		GroupBy:   "channel",
+		From:      1764979200,
+		To:        1765065600,
+		Filter:    []byte(`{"event_name":"purchase","group_by":"channel"}`),
+		Duration:  2 * time.Second,
+		Rows:      3,
+		Err:       errors.New(strings.Repeat("x", 2*usecase.MaxErrorLength)),
+	})
+	after := time.Now()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stored.APIKeyHash == "" || stored.APIKeyHash == "secret-key" {
+		t.Fatalf("expected hashed api key, got %q", stored.APIKeyHash)
+	}
+	if len(stored.Error) != usecase.MaxErrorLength {
+		t.Fatalf("expected error capped at %d bytes, got %d", usecase.MaxErrorLength, len(stored.Error))
+	}
+	if !stored.From.Equal(time.Unix(1764979200, 0)) || stored.From.Location() != time.UTC {
+		t.Fatalf("unexpected from: %s", stored.From)
+	}
+	if started := stored.StartedAt; started.Before(before.Add(-2*time.Second)) || started.After(after.Add(-2*time.Second)) {
+		t.Fatalf("expected the query to start 2s before recording, got %s", started)
+	}
+	if stored.Rows != 3 || stored.Duration != 2*time.Second {
+		t.Fatalf("unexpected entry: %+v", stored)
+	}
+}
+
+func TestAudit_RecordWithoutFilterStoresEmptyObject(t *testing.T) {
+	var stored domain.Query
+	repo := &fakeAuditRepo{
+		RecordFn: func(ctx context.Context, q domain.Query) error {
+			stored = q
+			return nil
+		},
+	}
+
+	if err := usecase.NewAuditUseCase(repo, 0).Record(context.Background(), usecase.RecordInput{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(stored.Filter) != "{}" || stored.APIKeyHash != "" || stored.Error != "" {
+		t.Fatalf("unexpected entry: %+v", stored)
+	}
+}
+
+// ------------------------------------------------------------
+// LIST / PURGE
+// ------------------------------------------------------------
+
+func TestAudit_ListDefaults(t *testing.T) {
+	var got ports.QueryFilter
+	repo := &fakeAuditRepo{
+		ListFn: func(ctx context.Context, f ports.QueryFilter) ([]domain.Query, error) {
+			got = f
+			return nil, nil
+		},
+	}
+
+	if _, err := usecase.NewAuditUseCase(repo, 0).List(context.Background(), ports.QueryFilter{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Limit != usecase.DefaultListLimit || got.OrderBy != ports.OrderRecent {
+		t.Fatalf("unexpected filter: %+v", got)
+	}
+}
+
+func TestAudit_ListRejectsInvalidFilters(t *testing.T) {
+	repo := &fakeAuditRepo{
+		ListFn: func(ctx context.Context, f ports.QueryFilter) ([]domain.Query, error) {
+			t.Fatalf("unexpected list")
+			return nil, nil
+		},
+	}
+	uc := usecase.NewAuditUseCase(repo, 0)
+
+	now := time.Now()
+	for _, f := range []ports.QueryFilter{
+		{OrderBy: "rows"},
+		{Limit: usecase.MaxListLimit + 1},
+		{MinDuration: -time.Second},
+		{Since: now, Until: now.Add(-time.Hour)},
+	} {
+		if _, err := uc.List(context.Background(), f); !errors.Is(err, usecase.ErrInvalidAuditQuery) {
+			t.Fatalf("%+v: expected ErrInvalidAuditQuery, got %v", f, err)
+		}
+	}
+}
+
+func TestAudit_PurgeBeforeRetention(t *testing.T) {
+	batches := []int64{usecase.DefaultPurgeBatchSize, 7}
+	var cutoffs []time.Time
+	repo := &fakeAuditRepo{
+		PurgeFn: func(ctx context.Context, before time.Time, limit int) (int64, error) {
+			cutoffs = append(cutoffs, before)
+			n := batches[0]
+			batches = batches[1:]
+			return n, nil
+		},
+	}
+
+	total, err := usecase.NewAuditUseCase(repo, 24*time.Hour).Purge(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != usecase.DefaultPurgeBatchSize+7 {
+		t.Fatalf("unexpected total: %d", total)
+	}
+	if len(cutoffs) != 2 || !cutoffs[0].Equal(cutoffs[1]) {
+		t.Fatalf("expected one cutoff for every batch, got %v", cutoffs)
+	}
+	if age := time.Since(cutoffs[0]); age < 24*time.Hour || age > 24*time.Hour+time.Minute {
+		t.Fatalf("expected a cutoff 24h ago, got %s", age)
+	}
+}
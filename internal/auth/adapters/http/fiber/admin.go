@@ -0,0 +1,37 @@
+package fiber
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"event-metrics-service/internal/auth/core/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const HeaderAdminToken = "X-Admin-Token"
+
+// RequireAdminToken guards operator-only endpoints with a shared secret sent
+// in the X-Admin-Token header. An empty token disables the endpoints for
+// everyone but callers granted the admin scope, who need no token.
+func RequireAdminToken(token string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if Principal(c).Scopes.Has(domain.ScopeAdmin) {
+			return c.Next()
+		}
+		if token == "" {
+			return c.Status(http.StatusForbidden).JSON(fiber.Map{
+				"error": "admin endpoints are disabled",
+			})
+		}
+
+		got := c.Get(HeaderAdminToken)
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
+				"error": "invalid admin token",
+			})
+		}
+
+		return c.Next()
+	}
+}
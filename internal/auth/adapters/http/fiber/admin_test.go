@@ -0,0 +1,50 @@
+package fiber
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func setupAdminApp(token string) *fiber.App {
+	app := fiber.New()
+	app.Get("/admin", RequireAdminToken(token), func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusNoContent)
+	})
+	return app
+}
+
+func TestRequireAdminToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		want   int
+	}{
+		{"valid", "s3cret", "s3cret", http.StatusNoContent},
+		{"wrong", "s3cret", "nope", http.StatusUnauthorized},
+		{"missing", "s3cret", "", http.StatusUnauthorized},
+		{"disabled", "", "", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := setupAdminApp(tt.token)
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.header != "" {
+				req.Header.Set(HeaderAdminToken, tt.header)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test error: %v", err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package fiber
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireBearerToken guards scrape-style endpoints with a static
+// "Authorization: Bearer <token>" credential, which Prometheus supports
+// natively. An empty token disables the endpoint.
+func RequireBearerToken(token string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if token == "" {
+			return c.Status(http.StatusForbidden).JSON(fiber.Map{
+				"error": "endpoint is disabled",
+			})
+		}
+
+		got, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+			return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
+				"error": "invalid bearer token",
+			})
+		}
+
+		return c.Next()
+	}
+}
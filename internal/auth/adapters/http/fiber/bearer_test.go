@@ -0,0 +1,43 @@
+package fiber
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRequireBearerToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		want   int
+	}{
+		{"valid", "s3cret", "Bearer s3cret", http.StatusNoContent},
+		{"wrong", "s3cret", "Bearer nope", http.StatusUnauthorized},
+		{"wrong scheme", "s3cret", "Basic s3cret", http.StatusUnauthorized},
+		{"disabled", "", "Bearer ", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/scrape", RequireBearerToken(tt.token), func(c *fiber.Ctx) error {
+				return c.SendStatus(http.StatusNoContent)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/scrape", nil)
+			req.Header.Set(fiber.HeaderAuthorization, tt.header)
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test error: %v", err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, resp.StatusCode)
+			}
+		})
+	}
+}
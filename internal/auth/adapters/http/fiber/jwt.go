@@ -0,0 +1,57 @@
+package fiber
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"event-metrics-service/internal/auth/core/domain"
+	"event-metrics-service/internal/auth/core/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireJWT guards read endpoints with an "Authorization: Bearer <jwt>"
+// token checked by verifier, which must grant every scope in scopes. The
+// token's subject and scopes are added to the principal; the API key is
+// kept, so quotas and throttling still apply to it.
+func RequireJWT(verifier ports.TokenVerifierPort, scopes []string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if !ok || token == "" {
+			c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+			return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
+				"error": "missing bearer token",
+			})
+		}
+
+		claims, err := verifier.Verify(c.UserContext(), token)
+		switch {
+		case errors.Is(err, domain.ErrInvalidToken):
+			c.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+			return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
+				"error": "invalid bearer token",
+			})
+		case err != nil:
+			return c.Status(http.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": "token verification is unavailable",
+			})
+		}
+
+		if missing := claims.MissingScopes(scopes); len(missing) > 0 {
+			c.Set(fiber.HeaderWWWAuthenticate, fmt.Sprintf(`Bearer error="insufficient_scope", scope=%q`, strings.Join(scopes, " ")))
+			return c.Status(http.StatusForbidden).JSON(fiber.Map{
+				"error": "insufficient scope",
+			})
+		}
+
+		p := Principal(c)
+		p.Subject = claims.Subject
+		p.Scopes |= domain.KnownScopes(claims.Scopes)
+		c.Locals(localsPrincipal, p)
+		c.SetUserContext(domain.WithPrincipal(c.UserContext(), p))
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,87 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"event-metrics-service/internal/auth/core/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeVerifier struct {
+	VerifyFn func(ctx context.Context, token string) (domain.Claims, error)
+}
+
+func (f fakeVerifier) Verify(ctx context.Context, token string) (domain.Claims, error) {
+	return f.VerifyFn(ctx, token)
+}
+
+func TestRequireJWT(t *testing.T) {
+	verifier := fakeVerifier{VerifyFn: func(_ context.Context, token string) (domain.Claims, error) {
+		switch token {
+		case "reader":
+			return domain.Claims{Subject: "alice", Scopes: []string{"openid", "metrics:read"}}, nil
+		case "no-scope":
+			return domain.Claims{Subject: "bob", Scopes: []string{"openid"}}, nil
+		case "down":
+			return domain.Claims{}, errors.New("oidc jwks: connection refused")
+		}
+		return domain.Claims{}, domain.ErrInvalidToken
+	}}
+
+	tests := []struct {
+		name          string
+		header        string
+		want          int
+		wantChallenge string
+	}{
+		{"valid", "Bearer reader", http.StatusOK, ""},
+		{"missing", "", http.StatusUnauthorized, "Bearer"},
+		{"wrong scheme", "Basic reader", http.StatusUnauthorized, "Bearer"},
+		{"invalid", "Bearer forged", http.StatusUnauthorized, `Bearer error="invalid_token"`},
+		{"insufficient scope", "Bearer no-scope", http.StatusForbidden, `Bearer error="insufficient_scope", scope="metrics:read"`},
+		{"issuer down", "Bearer down", http.StatusServiceUnavailable, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(IdentifyAPIKey())
+			app.Get("/metrics", RequireJWT(verifier, []string{"metrics:read"}), func(c *fiber.Ctx) error {
+				p := domain.PrincipalFromContext(c.UserContext())
+				if p != Principal(c) {
+					return c.SendStatus(http.StatusInternalServerError)
+				}
+				return c.SendString(p.APIKey + "/" + p.Subject + "/" + p.Scopes.String())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			req.Header.Set(HeaderAPIKey, "key-a")
+			if tt.header != "" {
+				req.Header.Set(fiber.HeaderAuthorization, tt.header)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test error: %v", err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, resp.StatusCode)
+			}
+			if got := resp.Header.Get(fiber.HeaderWWWAuthenticate); got != tt.wantChallenge {
+				t.Fatalf("expected challenge %q, got %q", tt.wantChallenge, got)
+			}
+			if tt.want == http.StatusOK {
+				body, _ := io.ReadAll(resp.Body)
+				if got := string(body); got != "key-a/alice/metrics:read" {
+					t.Fatalf("expected principal key-a/alice/metrics:read, got %q", got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package fiber
+
+import (
+	"event-metrics-service/internal/auth/core/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	HeaderAPIKey = "X-API-Key"
+
+	localsPrincipal = "principal"
+)
+
+type IdentifyOption func(*identifyConfig)
+
+type identifyConfig struct {
+	scopesOf   func(apiKey string) domain.Scopes
+	queryParam string
+}
+
+// WithKeyScopes grants each principal the scopes of its API key
+// (domain.AnonymousKey for callers without one).
+func WithKeyScopes(scopesOf func(apiKey string) domain.Scopes) IdentifyOption {
+	return func(cfg *identifyConfig) {
+		cfg.scopesOf = scopesOf
+	}
+}
+
+// WithKeyQueryParam also reads the API key from the query parameter name,
+// for clients that cannot set headers. The header takes precedence.
+func WithKeyQueryParam(name string) IdentifyOption {
+	return func(cfg *identifyConfig) {
+		cfg.queryParam = name
+	}
+}
+
+// IdentifyAPIKey resolves the caller's principal from the X-API-Key header
+// and makes it available via Principal(c) and the request user context.
+// It does not reject requests; authorization is up to later middleware.
+func IdentifyAPIKey(opts ...IdentifyOption) fiber.Handler {
+	var cfg identifyConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func(c *fiber.Ctx) error {
+		key := c.Get(HeaderAPIKey)
+		if key == "" && cfg.queryParam != "" {
+			key = c.Query(cfg.queryParam)
+		}
+		if key == "" {
+			key = domain.AnonymousKey
+		}
+
+		p := domain.Principal{APIKey: key}
+		if cfg.scopesOf != nil {
+			p.Scopes = cfg.scopesOf(key)
+		}
+		c.Locals(localsPrincipal, p)
+		c.SetUserContext(domain.WithPrincipal(c.UserContext(), p))
+
+		return c.Next()
+	}
+}
+
+// Principal returns the principal resolved by IdentifyAPIKey.
+func Principal(c *fiber.Ctx) domain.Principal {
+	if p, ok := c.Locals(localsPrincipal).(domain.Principal); ok {
+		return p
+	}
+	return domain.Principal{APIKey: domain.AnonymousKey}
+}
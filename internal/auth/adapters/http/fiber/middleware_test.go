@@ -0,0 +1,91 @@
+package fiber
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"event-metrics-service/internal/auth/core/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func setupApp() *fiber.App {
+	app := fiber.New()
+	app.Use(IdentifyAPIKey())
+	app.Get("/whoami", func(c *fiber.Ctx) error {
+		fromCtx := domain.PrincipalFromContext(c.UserContext())
+		if fromCtx != Principal(c) {
+			return c.SendStatus(http.StatusInternalServerError)
+		}
+		return c.SendString(Principal(c).APIKey)
+	})
+	return app
+}
+
+func TestIdentifyAPIKey_FromHeader(t *testing.T) {
+	app := setupApp()
+
+	req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+	req.Header.Set(HeaderAPIKey, "key_123")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK || string(body) != "key_123" {
+		t.Fatalf("expected key_123, got %d %s", resp.StatusCode, body)
+	}
+}
+
+func TestIdentifyAPIKey_Anonymous(t *testing.T) {
+	app := setupApp()
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/whoami", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+
+	if string(body) != domain.AnonymousKey {
+		t.Fatalf("expected %s, got %s", domain.AnonymousKey, body)
+	}
+}
+
+func TestIdentifyAPIKey_FromQueryParam(t *testing.T) {
+	app := fiber.New()
+	app.Use(IdentifyAPIKey(WithKeyQueryParam("api_secret")))
+	app.Get("/whoami", func(c *fiber.Ctx) error {
+		return c.SendString(Principal(c).APIKey)
+	})
+
+	tests := []struct {
+		name   string
+		path   string
+		header string
+		want   string
+	}{
+		{"query", "/whoami?api_secret=key_q", "", "key_q"},
+		{"header wins", "/whoami?api_secret=key_q", "key_h", "key_h"},
+		{"neither", "/whoami", "", domain.AnonymousKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(HeaderAPIKey, tt.header)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test error: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package fiber
+
+import (
+	"net/http"
+
+	"event-metrics-service/internal/auth/core/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireScope rejects principals that were not granted scope.
+func RequireScope(scope domain.Scopes) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !Principal(c).Scopes.Has(scope) {
+			return c.Status(http.StatusForbidden).JSON(fiber.Map{
+				"error": "missing scope " + scope.String(),
+			})
+		}
+		return c.Next()
+	}
+}
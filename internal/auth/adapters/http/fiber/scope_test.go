@@ -0,0 +1,66 @@
+package fiber
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"event-metrics-service/internal/auth/core/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRequireScope(t *testing.T) {
+	scopes := map[string]domain.Scopes{
+		"writer": domain.ScopeEventsWrite,
+		"reader": domain.ScopeMetricsRead,
+		"ops":    domain.ScopeAdmin,
+	}
+
+	app := fiber.New()
+	app.Use(IdentifyAPIKey(WithKeyScopes(func(apiKey string) domain.Scopes {
+		return scopes[apiKey]
+	})))
+	app.Post("/events", RequireScope(domain.ScopeEventsWrite), func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusCreated)
+	})
+	app.Get("/metrics", RequireScope(domain.ScopeMetricsRead), func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusOK)
+	})
+	app.Get("/admin", RequireAdminToken("s3cret"), func(c *fiber.Ctx) error {
+		return c.SendStatus(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		key    string
+		want   int
+	}{
+		{"writer writes", http.MethodPost, "/events", "writer", http.StatusCreated},
+		{"reader cannot write", http.MethodPost, "/events", "reader", http.StatusForbidden},
+		{"reader reads", http.MethodGet, "/metrics", "reader", http.StatusOK},
+		{"writer cannot read", http.MethodGet, "/metrics", "writer", http.StatusForbidden},
+		{"admin scope needs no token", http.MethodGet, "/admin", "ops", http.StatusNoContent},
+		{"other scopes need the token", http.MethodGet, "/admin", "writer", http.StatusUnauthorized},
+		{"anonymous", http.MethodPost, "/events", "", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set(HeaderAPIKey, tt.key)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test error: %v", err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, resp.StatusCode)
+			}
+		})
+	}
+}
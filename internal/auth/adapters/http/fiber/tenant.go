@@ -0,0 +1,41 @@
+package fiber
+
+import (
+	"context"
+	"net/http"
+
+	"event-metrics-service/internal/auth/core/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireTenant rejects requests whose API key belongs to no tenant and adds
+// the tenant to the principal. resolve returns "" for unknown keys.
+func RequireTenant(resolve func(ctx context.Context, apiKey string) (string, error)) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		p := Principal(c)
+		if p.APIKey == domain.AnonymousKey {
+			return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
+				"error": "missing api key",
+			})
+		}
+
+		tenantID, err := resolve(c.UserContext(), p.APIKey)
+		if err != nil {
+			return c.Status(http.StatusServiceUnavailable).JSON(fiber.Map{
+				"error": "tenant lookup is unavailable",
+			})
+		}
+		if tenantID == "" {
+			return c.Status(http.StatusUnauthorized).JSON(fiber.Map{
+				"error": "unknown api key",
+			})
+		}
+
+		p.TenantID = tenantID
+		c.Locals(localsPrincipal, p)
+		c.SetUserContext(domain.WithPrincipal(c.UserContext(), p))
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,70 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"event-metrics-service/internal/auth/core/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRequireTenant(t *testing.T) {
+	resolve := func(_ context.Context, apiKey string) (string, error) {
+		switch apiKey {
+		case "key-a":
+			return "team-a", nil
+		case "key-down":
+			return "", errors.New("db failure")
+		}
+		return "", nil
+	}
+
+	tests := []struct {
+		name string
+		key  string
+		want int
+	}{
+		{"known key", "key-a", http.StatusOK},
+		{"no key", "", http.StatusUnauthorized},
+		{"unknown key", "key-x", http.StatusUnauthorized},
+		{"lookup failure", "key-down", http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(IdentifyAPIKey())
+			app.Post("/events", RequireTenant(resolve), func(c *fiber.Ctx) error {
+				p := domain.PrincipalFromContext(c.UserContext())
+				if p != Principal(c) {
+					return c.SendStatus(http.StatusInternalServerError)
+				}
+				return c.SendString(p.APIKey + "/" + p.TenantID)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/events", nil)
+			if tt.key != "" {
+				req.Header.Set(HeaderAPIKey, tt.key)
+			}
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test error: %v", err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, resp.StatusCode)
+			}
+			if tt.want == http.StatusOK {
+				body, _ := io.ReadAll(resp.Body)
+				if got := string(body); got != "key-a/team-a" {
+					t.Fatalf("expected principal key-a/team-a, got %q", got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+
+	"event-metrics-service/internal/auth/core/domain"
+)
+
+const maxDocumentBytes = 1 << 20
+
+// keySet caches the issuer's signing keys. Keys are fetched on first use,
+// again once they are older than maxAge, and early when a token names a key
+// id that is not cached (the issuer rotated its keys), but no more than once
+// per minRefresh.
+type keySet struct {
+	issuer     string
+	client     *http.Client
+	maxAge     time.Duration
+	minRefresh time.Duration
+	now        func() time.Time
+
+	mu          sync.Mutex
+	jwksURI     string
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	lastAttempt time.Time
+	lastErr     error
+}
+
+func (s *keySet) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	_, known := s.lookup(kid)
+	stale := s.keys == nil || now.Sub(s.fetchedAt) >= s.maxAge
+	if (stale || !known) && now.Sub(s.lastAttempt) >= s.minRefresh {
+		s.lastAttempt = now
+		// Detached from the request: one caller giving up must not fail the
+		// refresh for everyone until the next attempt. The client has a timeout.
+		s.lastErr = s.refresh(context.WithoutCancel(ctx))
+	}
+	if s.keys == nil {
+		// On a failed refresh with keys already cached, those keep being used.
+		return nil, s.lastErr
+	}
+
+	k, ok := s.lookup(kid)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key id %q", domain.ErrInvalidToken, kid)
+	}
+	return k, nil
+}
+
+// lookup finds a key by id. A token without a key id matches the only key
+// of a single-key set.
+func (s *keySet) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, k := range s.keys {
+			return k, true
+		}
+	}
+	k, ok := s.keys[kid]
+	return k, ok
+}
+
+func (s *keySet) refresh(ctx context.Context) error {
+	if s.jwksURI == "" {
+		var discovery struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		url := strings.TrimSuffix(s.issuer, "/") + "/.well-known/openid-configuration"
+		if err := s.getJSON(ctx, url, &discovery); err != nil {
+			return fmt.Errorf("oidc discovery: %w", err)
+		}
+		if discovery.Issuer != s.issuer {
+			return fmt.Errorf("oidc discovery: issuer %q does not match %q", discovery.Issuer, s.issuer)
+		}
+		if discovery.JWKSURI == "" {
+			return errors.New("oidc discovery: no jwks_uri")
+		}
+		s.jwksURI = discovery.JWKSURI
+	}
+
+	var set struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := s.getJSON(ctx, s.jwksURI, &set); err != nil {
+		return fmt.Errorf("oidc jwks: %w", err)
+	}
+	keys := map[string]crypto.PublicKey{}
+	for _, raw := range set.Keys {
+		// Keys of unsupported types are skipped rather than failing the set.
+		var k jose.JSONWebKey
+		if err := k.UnmarshalJSON(raw); err != nil || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		switch pub := k.Key.(type) {
+		case *rsa.PublicKey, *ecdsa.PublicKey:
+			keys[k.KeyID] = pub
+		}
+	}
+	s.keys, s.fetchedAt = keys, s.now()
+	return nil
+}
+
+func (s *keySet) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxDocumentBytes)).Decode(v)
+}
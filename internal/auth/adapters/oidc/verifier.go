@@ -0,0 +1,170 @@
+package oidc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"event-metrics-service/internal/auth/core/domain"
+	"event-metrics-service/internal/auth/core/ports"
+)
+
+const (
+	DefaultLeeway     = time.Minute
+	DefaultKeysMaxAge = time.Hour
+	DefaultTimeout    = 10 * time.Second
+
+	// Unknown key ids trigger a refresh at most this often, so garbage tokens
+	// cannot make the service hammer the issuer.
+	minKeyRefresh = 10 * time.Second
+)
+
+// Verifier checks JWTs issued by an OpenID Connect provider: the signature
+// against the keys the issuer publishes (RS256/384/512, ES256/384/512), the
+// issuer and audience, and the expiry. Keys are discovered from the issuer's
+// /.well-known/openid-configuration on first use.
+type Verifier struct {
+	issuer   string
+	audience string
+	leeway   time.Duration
+	now      func() time.Time
+	keys     *keySet
+}
+
+type Option func(*Verifier)
+
+func WithHTTPClient(c *http.Client) Option {
+	return func(v *Verifier) {
+		v.keys.client = c
+	}
+}
+
+// WithLeeway tolerates clock skew between the issuer and this service when
+// checking exp and nbf.
+func WithLeeway(d time.Duration) Option {
+	return func(v *Verifier) {
+		v.leeway = d
+	}
+}
+
+// WithKeysMaxAge sets how long fetched keys are used before they are fetched
+// again.
+func WithKeysMaxAge(d time.Duration) Option {
+	return func(v *Verifier) {
+		v.keys.maxAge = d
+	}
+}
+
+func WithClock(now func() time.Time) Option {
+	return func(v *Verifier) {
+		v.now = now
+		v.keys.now = now
+	}
+}
+
+// NewVerifier creates a verifier for tokens from issuer. When audience is
+// not empty, tokens must list it in their aud claim.
+func NewVerifier(issuer, audience string, opts ...Option) *Verifier {
+	v := &Verifier{
+		issuer:   issuer,
+		audience: audience,
+		leeway:   DefaultLeeway,
+		now:      time.Now,
+		keys: &keySet{
+			issuer:     issuer,
+			client:     &http.Client{Timeout: DefaultTimeout},
+			maxAge:     DefaultKeysMaxAge,
+			minRefresh: minKeyRefresh,
+			now:        time.Now,
+		},
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+var _ ports.TokenVerifierPort = (*Verifier)(nil)
+
+// tokenClaims are the registered JWT claims plus the two ways providers encode
+// granted scopes.
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	Scope string     `json:"scope"`
+	Scp   stringList `json:"scp"`
+}
+
+// stringList accepts a single string or an array of strings, as JWT
+// providers differ in how they encode scp.
+type stringList []string
+
+func (l *stringList) UnmarshalJSON(b []byte) error {
+	if bytes.HasPrefix(b, []byte(`"`)) {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		*l = strings.Fields(s)
+		return nil
+	}
+	return json.Unmarshal(b, (*[]string)(l))
+}
+
+// validMethods lists the supported algorithms. "none" and the HMAC
+// algorithms are deliberately absent: the issuer's keys are public.
+var validMethods = []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}
+
+func (v *Verifier) Verify(ctx context.Context, token string) (domain.Claims, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(validMethods),
+		jwt.WithIssuer(v.issuer),
+		jwt.WithLeeway(v.leeway),
+		jwt.WithTimeFunc(v.now),
+		jwt.WithExpirationRequired(),
+	}
+	if v.audience != "" {
+		opts = append(opts, jwt.WithAudience(v.audience))
+	}
+
+	var c tokenClaims
+	_, err := jwt.NewParser(opts...).ParseWithClaims(token, &c, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.keys.key(ctx, kid)
+	})
+	switch {
+	case err == nil:
+	case errors.Is(err, domain.ErrInvalidToken):
+		return domain.Claims{}, err
+	case errors.Is(err, jwt.ErrTokenUnverifiable):
+		// The keys could not be fetched: the token may well be valid.
+		return domain.Claims{}, err
+	default:
+		return domain.Claims{}, invalid(err.Error())
+	}
+
+	scopes := strings.Fields(c.Scope)
+	for _, s := range c.Scp {
+		if !slices.Contains(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+	return domain.Claims{
+		Issuer:    c.Issuer,
+		Subject:   c.Subject,
+		Audience:  c.Audience,
+		Scopes:    scopes,
+		ExpiresAt: c.ExpiresAt.Time,
+	}, nil
+}
+
+func invalid(reason string) error {
+	return fmt.Errorf("%w: %s", domain.ErrInvalidToken, reason)
+}
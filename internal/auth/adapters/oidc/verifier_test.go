@@ -0,0 +1,251 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/auth/core/domain"
+)
+
+// ------------------------------------------------------------
+// FAKE ISSUER
+// ------------------------------------------------------------
+
+type fakeIssuer struct {
+	srv *httptest.Server
+
+	mu        sync.Mutex
+	keys      []map[string]string
+	jwksCalls int
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	f := &fakeIssuer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   f.srv.URL,
+			"jwks_uri": f.srv.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.jwksCalls++
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": f.keys})
+	})
+	f.srv = httptest.NewServer(mux)
+	t.Cleanup(f.srv.Close)
+	return f
+}
+
+func (f *fakeIssuer) publish(keys ...map[string]string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys = keys
+}
+
+func (f *fakeIssuer) fetches() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.jwksCalls
+}
+
+func b64(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+func rsaJWK(kid string, k *rsa.PrivateKey) map[string]string {
+	return map[string]string{
+		"kty": "RSA", "kid": kid, "use": "sig",
+		"n": b64(k.N.Bytes()), "e": b64(big.NewInt(int64(k.E)).Bytes()),
+	}
+}
+
+func ecJWK(kid string, k *ecdsa.PrivateKey) map[string]string {
+	pub, _ := k.PublicKey.Bytes() // 0x04 || x || y
+	size := (len(pub) - 1) / 2
+	return map[string]string{
+		"kty": "EC", "kid": kid, "crv": "P-256",
+		"x": b64(pub[1 : 1+size]), "y": b64(pub[1+size:]),
+	}
+}
+
+func sign(t *testing.T, alg, kid string, key crypto.Signer, claims map[string]any) string {
+	t.Helper()
+	h, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	c, _ := json.Marshal(claims)
+	input := b64(h) + "." + b64(c)
+
+	var hash crypto.Hash = crypto.SHA256
+	digest := hash.New()
+	digest.Write([]byte(input))
+	sum := digest.Sum(nil)
+
+	var sig []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		var err error
+		if sig, err = rsa.SignPKCS1v15(rand.Reader, k, hash, sum); err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, sum)
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return input + "." + b64(sig)
+}
+
+var now = time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+
+func claims(issuer string) map[string]any {
+	return map[string]any{
+		"iss":   issuer,
+		"sub":   "alice",
+		"aud":   "event-metrics",
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"scope": "openid metrics:read",
+	}
+}
+
+// ------------------------------------------------------------
+// VERIFY
+// ------------------------------------------------------------
+
+func TestVerifier_ValidTokens(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	issuer.publish(rsaJWK("r1", rsaKey), ecJWK("e1", ecKey))
+
+	v := NewVerifier(issuer.srv.URL, "event-metrics", WithClock(func() time.Time { return now }))
+
+	got, err := v.Verify(context.Background(), sign(t, "RS256", "r1", rsaKey, claims(issuer.srv.URL)))
+	if err != nil {
+		t.Fatalf("RS256: unexpected error: %v", err)
+	}
+	if got.Subject != "alice" || len(got.Scopes) != 2 || got.Scopes[1] != "metrics:read" {
+		t.Fatalf("unexpected claims %+v", got)
+	}
+
+	c := claims(issuer.srv.URL)
+	delete(c, "scope")
+	c["scp"] = []string{"metrics:read"}
+	c["aud"] = []string{"other", "event-metrics"}
+	got, err = v.Verify(context.Background(), sign(t, "ES256", "e1", ecKey, c))
+	if err != nil {
+		t.Fatalf("ES256: unexpected error: %v", err)
+	}
+	if len(got.Scopes) != 1 || got.Scopes[0] != "metrics:read" {
+		t.Fatalf("expected scopes from scp, got %v", got.Scopes)
+	}
+
+	if n := issuer.fetches(); n != 1 {
+		t.Fatalf("expected keys to be fetched once, got %d", n)
+	}
+}
+
+func TestVerifier_RejectsInvalidTokens(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	issuer.publish(rsaJWK("r1", key))
+
+	v := NewVerifier(issuer.srv.URL, "event-metrics", WithClock(func() time.Time { return now }))
+
+	with := func(k string, val any) map[string]any {
+		c := claims(issuer.srv.URL)
+		if val == nil {
+			delete(c, k)
+		} else {
+			c[k] = val
+		}
+		return c
+	}
+	unsigned := b64([]byte(`{"alg":"none","kid":"r1"}`)) + "." + b64([]byte(`{"sub":"alice"}`)) + "."
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"garbage", "not-a-token"},
+		{"alg none", unsigned},
+		{"wrong key", sign(t, "RS256", "r1", other, claims(issuer.srv.URL))},
+		{"unknown kid", sign(t, "RS256", "r2", key, claims(issuer.srv.URL))},
+		{"wrong issuer", sign(t, "RS256", "r1", key, with("iss", "https://evil.example"))},
+		{"wrong audience", sign(t, "RS256", "r1", key, with("aud", "someone-else"))},
+		{"expired", sign(t, "RS256", "r1", key, with("exp", now.Add(-2*time.Minute).Unix()))},
+		{"no exp", sign(t, "RS256", "r1", key, with("exp", nil))},
+		{"not yet valid", sign(t, "RS256", "r1", key, with("nbf", now.Add(2*time.Minute).Unix()))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := v.Verify(context.Background(), tt.token); !errors.Is(err, domain.ErrInvalidToken) {
+				t.Fatalf("expected ErrInvalidToken, got %v", err)
+			}
+		})
+	}
+
+	// Within the leeway, a just-expired token still passes.
+	if _, err := v.Verify(context.Background(), sign(t, "RS256", "r1", key, with("exp", now.Add(-30*time.Second).Unix()))); err != nil {
+		t.Fatalf("expected token within leeway to pass, got %v", err)
+	}
+}
+
+func TestVerifier_RefetchesKeysOnRotation(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	oldKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	newKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	issuer.publish(rsaJWK("old", oldKey))
+
+	clock := now
+	v := NewVerifier(issuer.srv.URL, "", WithClock(func() time.Time { return clock }))
+
+	if _, err := v.Verify(context.Background(), sign(t, "RS256", "old", oldKey, claims(issuer.srv.URL))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	issuer.publish(rsaJWK("old", oldKey), rsaJWK("new", newKey))
+	rotated := sign(t, "RS256", "new", newKey, claims(issuer.srv.URL))
+
+	// Refreshes are spaced out, so right away the new key is still unknown.
+	if _, err := v.Verify(context.Background(), rotated); !errors.Is(err, domain.ErrInvalidToken) {
+		t.Fatalf("expected unknown key before the refresh interval, got %v", err)
+	}
+
+	clock = clock.Add(minKeyRefresh)
+	if _, err := v.Verify(context.Background(), rotated); err != nil {
+		t.Fatalf("expected rotated key to be fetched, got %v", err)
+	}
+	if n := issuer.fetches(); n != 2 {
+		t.Fatalf("expected 2 key fetches, got %d", n)
+	}
+}
+
+func TestVerifier_IssuerUnreachable(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	token := sign(t, "RS256", "r1", key, claims(issuer.srv.URL))
+	url := issuer.srv.URL
+	issuer.srv.Close()
+
+	v := NewVerifier(url, "", WithClock(func() time.Time { return now }))
+	_, err := v.Verify(context.Background(), token)
+	if err == nil || errors.Is(err, domain.ErrInvalidToken) {
+		t.Fatalf("expected an unavailable error, got %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "context"
+
+// AnonymousKey identifies callers that did not send an API key.
+const AnonymousKey = "anonymous"
+
+// Principal is the identity a request is made on behalf of.
+type Principal struct {
+	APIKey string
+	// Subject is the "sub" claim of a verified bearer token, if any.
+	Subject string
+	// TenantID is the tenant owning APIKey, when tenants are isolated.
+	TenantID string
+	// Scopes are granted by the API key and a verified bearer token.
+	Scopes Scopes
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the principal stored in ctx, or an anonymous
+// principal when none is present.
+func PrincipalFromContext(ctx context.Context) Principal {
+	if p, ok := ctx.Value(principalKey{}).(Principal); ok {
+		return p
+	}
+	return Principal{APIKey: AnonymousKey}
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Scopes is a set of the permissions routes are guarded with.
+type Scopes uint8
+
+const (
+	ScopeEventsWrite Scopes = 1 << iota
+	ScopeMetricsRead
+	ScopeAdmin
+	ScopeReportsWrite
+)
+
+// scopeNames is ordered as the scopes are declared, so String is stable.
+var scopeNames = []struct {
+	name  string
+	scope Scopes
+}{
+	{"events:write", ScopeEventsWrite},
+	{"metrics:read", ScopeMetricsRead},
+	{"admin", ScopeAdmin},
+	{"reports:write", ScopeReportsWrite},
+}
+
+// ParseScopes parses a space separated scope list, e.g.
+// "events:write metrics:read". "none" is the empty set.
+func ParseScopes(s string) (Scopes, error) {
+	var out Scopes
+	for _, name := range strings.Fields(s) {
+		if name == "none" {
+			continue
+		}
+		scope, ok := lookupScope(name)
+		if !ok {
+			return 0, fmt.Errorf("unknown scope %q", name)
+		}
+		out |= scope
+	}
+	return out, nil
+}
+
+// KnownScopes returns the scopes among names, ignoring the others, as
+// tokens carry scopes meant for other services too.
+func KnownScopes(names []string) Scopes {
+	var out Scopes
+	for _, name := range names {
+		if scope, ok := lookupScope(name); ok {
+			out |= scope
+		}
+	}
+	return out
+}
+
+// Has reports whether every scope in want is in s.
+func (s Scopes) Has(want Scopes) bool {
+	return s&want == want
+}
+
+func (s Scopes) String() string {
+	var names []string
+	for _, n := range scopeNames {
+		if s.Has(n.scope) {
+			names = append(names, n.name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, " ")
+}
+
+func lookupScope(name string) (Scopes, bool) {
+	for _, n := range scopeNames {
+		if n.name == name {
+			return n.scope, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "testing"
+
+func TestParseScopes(t *testing.T) {
+	got, err := ParseScopes(" events:write  metrics:read ")
+	if err != nil || got != ScopeEventsWrite|ScopeMetricsRead {
+		t.Fatalf("unexpected scopes: %v, %v", got, err)
+	}
+	if got.String() != "events:write metrics:read" {
+		t.Fatalf("unexpected string: %q", got)
+	}
+	if got.Has(ScopeAdmin) || !got.Has(ScopeMetricsRead) {
+		t.Fatalf("unexpected Has for %s", got)
+	}
+
+	if got, err := ParseScopes("none"); err != nil || got != 0 || got.String() != "none" {
+		t.Fatalf("expected the empty set, got %v, %v", got, err)
+	}
+	if got, err := ParseScopes("reports:write metrics:read"); err != nil || got.String() != "metrics:read reports:write" {
+		t.Fatalf("unexpected scopes: %v, %v", got, err)
+	}
+	if _, err := ParseScopes("events:read"); err == nil {
+		t.Fatalf("expected error for unknown scope")
+	}
+}
+
+func TestKnownScopes(t *testing.T) {
+	if got := KnownScopes([]string{"openid", "admin", "profile"}); got != ScopeAdmin {
+		t.Fatalf("expected only admin, got %s", got)
+	}
+}
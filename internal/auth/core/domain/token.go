@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// ErrInvalidToken is returned for bearer tokens that are malformed, badly
+// signed, expired or meant for another issuer or audience.
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims are the verified claims of a bearer token.
+type Claims struct {
+	Issuer    string
+	Subject   string
+	Audience  []string
+	Scopes    []string
+	ExpiresAt time.Time
+}
+
+// MissingScopes returns the required scopes the token was not granted.
+func (c Claims) MissingScopes(required []string) []string {
+	var missing []string
+	for _, s := range required {
+		if !slices.Contains(c.Scopes, s) {
+			missing = append(missing, s)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"event-metrics-service/internal/auth/core/domain"
+)
+
+// TokenVerifierPort checks a raw bearer token and returns its claims. Tokens
+// that fail verification yield an error wrapping domain.ErrInvalidToken; any
+// other error means the token could not be checked (e.g. the issuer's keys
+// are unreachable).
+type TokenVerifierPort interface {
+	Verify(ctx context.Context, token string) (domain.Claims, error)
+}
@@ -0,0 +1,24 @@
+package fiber
+
+type FaultRequest struct {
+	LatencyMs int64   `json:"latency_ms" example:"250"`
+	ErrorRate float64 `json:"error_rate" example:"0.1"`
+	Message   string  `json:"message,omitempty" example:"simulated connection reset"`
+}
+
+type FaultResponse struct {
+	Target    string  `json:"target" example:"events.repository"`
+	LatencyMs int64   `json:"latency_ms" example:"250"`
+	ErrorRate float64 `json:"error_rate" example:"0.1"`
+	Message   string  `json:"message,omitempty" example:"simulated connection reset"`
+}
+
+type FaultListResponse struct {
+	Targets []string        `json:"targets"`
+	Faults  []FaultResponse `json:"faults"`
+}
+
+type ErrorResponse struct {
+	Error   string `json:"error" example:"invalid_fault"`
+	Message string `json:"message" example:"error_rate must be between 0 and 1"`
+}
@@ -0,0 +1,126 @@
+package fiber
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"event-metrics-service/internal/chaos/core/domain"
+	"event-metrics-service/internal/chaos/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type FaultHandler struct {
+	injector *usecase.Injector
+}
+
+func NewFaultHandler(injector *usecase.Injector) *FaultHandler {
+	return &FaultHandler{injector: injector}
+}
+
+// ListFaults godoc
+// @Summary List active injected faults
+// @Description Non-production only. Requires the X-Admin-Token header.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} FaultListResponse
+// @Router /admin/faults [get]
+func (h *FaultHandler) ListFaults(c *fiber.Ctx) error {
+	faults := h.injector.List()
+
+	resp := FaultListResponse{
+		Targets: h.injector.Targets(),
+		Faults:  make([]FaultResponse, 0, len(faults)),
+	}
+	for _, f := range faults {
+		resp.Faults = append(resp.Faults, toFaultResponse(f))
+	}
+
+	return c.JSON(resp)
+}
+
+// SetFault godoc
+// @Summary Inject latency or errors into a repository port
+// @Description Non-production only. Replaces any fault already set on the target.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param target path string true "Target: events.repository | metrics.reader"
+// @Param request body FaultRequest true "Fault"
+// @Success 200 {object} FaultResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/faults/{target} [put]
+func (h *FaultHandler) SetFault(c *fiber.Ctx) error {
+	var req FaultRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error:   "invalid_body",
+			Message: "request body could not be parsed",
+		})
+	}
+
+	f := domain.Fault{
+		Target:    c.Params("target"),
+		Latency:   time.Duration(req.LatencyMs) * time.Millisecond,
+		ErrorRate: req.ErrorRate,
+		Message:   req.Message,
+	}
+
+	if err := h.injector.Set(f); err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnknownTarget):
+			return c.Status(http.StatusNotFound).JSON(ErrorResponse{
+				Error:   "unknown_target",
+				Message: err.Error(),
+			})
+		case errors.Is(err, usecase.ErrInvalidFault):
+			return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+				Error:   "invalid_fault",
+				Message: "latency_ms must be >= 0 and error_rate between 0 and 1",
+			})
+		default:
+			return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+				Error:   "internal_error",
+				Message: err.Error(),
+			})
+		}
+	}
+
+	return c.JSON(toFaultResponse(f))
+}
+
+// ClearFault godoc
+// @Summary Remove the injected fault from a target
+// @Tags Admin
+// @Param X-Admin-Token header string true "Admin token"
+// @Param target path string true "Target"
+// @Success 204
+// @Router /admin/faults/{target} [delete]
+func (h *FaultHandler) ClearFault(c *fiber.Ctx) error {
+	h.injector.Clear(c.Params("target"))
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// ClearAllFaults godoc
+// @Summary Remove all injected faults
+// @Tags Admin
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 204
+// @Router /admin/faults [delete]
+func (h *FaultHandler) ClearAllFaults(c *fiber.Ctx) error {
+	h.injector.ClearAll()
+	return c.SendStatus(http.StatusNoContent)
+}
+
+func toFaultResponse(f domain.Fault) FaultResponse {
+	return FaultResponse{
+		Target:    f.Target,
+		LatencyMs: f.Latency.Milliseconds(),
+		ErrorRate: f.ErrorRate,
+		Message:   f.Message,
+	}
+}
@@ -0,0 +1,84 @@
+package fiber_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpadapter "event-metrics-service/internal/chaos/adapters/http/fiber"
+	"event-metrics-service/internal/chaos/core/domain"
+	"event-metrics-service/internal/chaos/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func setupApp(inj *usecase.Injector) *fiber.App {
+	app := fiber.New()
+	h := httpadapter.NewFaultHandler(inj)
+	app.Get("/admin/faults", h.ListFaults)
+	app.Put("/admin/faults/:target", h.SetFault)
+	app.Delete("/admin/faults/:target", h.ClearFault)
+	app.Delete("/admin/faults", h.ClearAllFaults)
+	return app
+}
+
+func doRequest(t *testing.T, app *fiber.App, method, path, body string) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	return resp
+}
+
+func TestSetFault_ThenList(t *testing.T) {
+	inj := usecase.NewInjector(domain.TargetEventsRepository, domain.TargetMetricsReader)
+	app := setupApp(inj)
+
+	resp := doRequest(t, app, http.MethodPut, "/admin/faults/events.repository",
+		`{"latency_ms":250,"error_rate":0.5,"message":"boom"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	resp = doRequest(t, app, http.MethodGet, "/admin/faults", "")
+	var body httpadapter.FaultListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if len(body.Faults) != 1 || body.Faults[0].LatencyMs != 250 || body.Faults[0].ErrorRate != 0.5 {
+		t.Fatalf("unexpected faults: %+v", body.Faults)
+	}
+	if len(body.Targets) != 2 {
+		t.Fatalf("expected 2 targets, got %v", body.Targets)
+	}
+
+	resp = doRequest(t, app, http.MethodDelete, "/admin/faults/events.repository", "")
+	if resp.StatusCode != http.StatusNoContent || len(inj.List()) != 0 {
+		t.Fatalf("expected fault to be cleared, got %d %+v", resp.StatusCode, inj.List())
+	}
+}
+
+func TestSetFault_Errors(t *testing.T) {
+	inj := usecase.NewInjector(domain.TargetEventsRepository)
+	app := setupApp(inj)
+
+	resp := doRequest(t, app, http.MethodPut, "/admin/faults/unknown", `{"error_rate":1}`)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", resp.StatusCode)
+	}
+
+	resp = doRequest(t, app, http.MethodPut, "/admin/faults/events.repository", `{"error_rate":2}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+
+	resp = doRequest(t, app, http.MethodPut, "/admin/faults/events.repository", `{`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// Well-known injection targets (one per wrapped port).
+const (
+	TargetEventsRepository = "events.repository"
+	TargetMetricsReader    = "metrics.reader"
+)
+
+// Fault describes what to inject into calls made through a target.
+type Fault struct {
+	Target    string
+	Latency   time.Duration // added before the call
+	ErrorRate float64       // 0..1 probability of failing the call
+	Message   string        // error message for injected failures
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"event-metrics-service/internal/chaos/core/domain"
+)
+
+var (
+	ErrInjectedFault = errors.New("injected fault")
+	ErrUnknownTarget = errors.New("unknown fault target")
+	ErrInvalidFault  = errors.New("invalid fault")
+)
+
+// Injector holds the active faults and applies them to calls made through
+// the chaos adapters. It is safe for concurrent use.
+type Injector struct {
+	mu      sync.RWMutex
+	targets map[string]struct{}
+	faults  map[string]domain.Fault
+	random  func() float64
+	sleep   func(ctx context.Context, d time.Duration) error
+}
+
+// NewInjector creates an injector that only accepts the given targets.
+func NewInjector(targets ...string) *Injector {
+	known := make(map[string]struct{}, len(targets))
+	for _, t := range targets {
+		known[t] = struct{}{}
+	}
+	return &Injector{
+		targets: known,
+		faults:  map[string]domain.Fault{},
+		random:  rand.Float64,
+		sleep:   sleepContext,
+	}
+}
+
+func (i *Injector) Set(f domain.Fault) error {
+	if _, ok := i.targets[f.Target]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownTarget, f.Target)
+	}
+	if f.Latency < 0 || f.ErrorRate < 0 || f.ErrorRate > 1 {
+		return ErrInvalidFault
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.faults[f.Target] = f
+	return nil
+}
+
+func (i *Injector) Clear(target string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.faults, target)
+}
+
+func (i *Injector) ClearAll() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.faults = map[string]domain.Fault{}
+}
+
+// List returns the active faults ordered by target.
+func (i *Injector) List() []domain.Fault {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	out := make([]domain.Fault, 0, len(i.faults))
+	for _, f := range i.faults {
+		out = append(out, f)
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].Target < out[b].Target })
+	return out
+}
+
+// Targets returns the targets faults can be injected into.
+func (i *Injector) Targets() []string {
+	out := make([]string, 0, len(i.targets))
+	for t := range i.targets {
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Inject applies the active fault for target, if any: it waits for the
+// configured latency and then fails with the configured probability.
+func (i *Injector) Inject(ctx context.Context, target string) error {
+	i.mu.RLock()
+	f, ok := i.faults[target]
+	i.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	if f.Latency > 0 {
+		if err := i.sleep(ctx, f.Latency); err != nil {
+			return err
+		}
+	}
+
+	if f.ErrorRate > 0 && i.random() < f.ErrorRate {
+		msg := f.Message
+		if msg == "" {
+			msg = target
+		}
+		return fmt.Errorf("%w: %s", ErrInjectedFault, msg)
+	}
+
+	return nil
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/chaos/core/domain"
+)
+
+func TestInjector_NoFault(t *testing.T) {
+	i := NewInjector(domain.TargetEventsRepository)
+
+	if err := i.Inject(context.Background(), domain.TargetEventsRepository); err != nil {
+		t.Fatalf("expected nil without active fault, got %v", err)
+	}
+}
+
+func TestInjector_ErrorAndLatency(t *testing.T) {
+	i := NewInjector(domain.TargetEventsRepository)
+
+	var slept time.Duration
+	i.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = d
+		return nil
+	}
+	i.random = func() float64 { return 0.3 }
+
+	err := i.Set(domain.Fault{
+		Target:    domain.TargetEventsRepository,
+		Latency:   200 * time.Millisecond,
+		ErrorRate: 0.5,
+		Message:   "connection reset",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err = i.Inject(context.Background(), domain.TargetEventsRepository)
+	if !errors.Is(err, ErrInjectedFault) {
+		t.Fatalf("expected ErrInjectedFault, got %v", err)
+	}
+	if slept != 200*time.Millisecond {
+		t.Fatalf("expected 200ms latency, got %v", slept)
+	}
+
+	// roll above the error rate -> success
+	i.random = func() float64 { return 0.9 }
+	if err := i.Inject(context.Background(), domain.TargetEventsRepository); err != nil {
+		t.Fatalf("expected success above error rate, got %v", err)
+	}
+
+	i.Clear(domain.TargetEventsRepository)
+	if len(i.List()) != 0 {
+		t.Fatalf("expected no faults after Clear")
+	}
+}
+
+func TestInjector_LatencyRespectsContext(t *testing.T) {
+	i := NewInjector(domain.TargetMetricsReader)
+	_ = i.Set(domain.Fault{Target: domain.TargetMetricsReader, Latency: time.Hour})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := i.Inject(ctx, domain.TargetMetricsReader); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestInjector_Validation(t *testing.T) {
+	i := NewInjector(domain.TargetEventsRepository)
+
+	if err := i.Set(domain.Fault{Target: "nope"}); !errors.Is(err, ErrUnknownTarget) {
+		t.Fatalf("expected ErrUnknownTarget, got %v", err)
+	}
+	if err := i.Set(domain.Fault{Target: domain.TargetEventsRepository, ErrorRate: 1.5}); !errors.Is(err, ErrInvalidFault) {
+		t.Fatalf("expected ErrInvalidFault, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package chaos
+
+import (
+	"context"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+)
+
+// FaultInjector is satisfied by the chaos injector.
+type FaultInjector interface {
+	Inject(ctx context.Context, target string) error
+}
+
+// EventRepository decorates an event repository with fault injection.
+type EventRepository struct {
+	next     ports.EventRepositoryPort
+	injector FaultInjector
+	target   string
+}
+
+func NewEventRepository(next ports.EventRepositoryPort, injector FaultInjector, target string) *EventRepository {
+	return &EventRepository{next: next, injector: injector, target: target}
+}
+
+var _ ports.EventRepositoryPort = (*EventRepository)(nil)
+
+func (r *EventRepository) InsertEvent(ctx context.Context, e *domain.Event) (bool, error) {
+	if err := r.injector.Inject(ctx, r.target); err != nil {
+		return false, err
+	}
+	return r.next.InsertEvent(ctx, e)
+}
+
+func (r *EventRepository) WithinTransaction(ctx context.Context, fn func(repo ports.EventRepositoryPort) error) error {
+	if err := r.injector.Inject(ctx, r.target); err != nil {
+		return err
+	}
+	return r.next.WithinTransaction(ctx, func(repo ports.EventRepositoryPort) error {
+		// Keep injecting into statements issued inside the transaction.
+		return fn(&EventRepository{next: repo, injector: r.injector, target: r.target})
+	})
+}
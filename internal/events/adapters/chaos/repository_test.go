@@ -0,0 +1,77 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+)
+
+type fakeInjector struct {
+	err   error
+	calls int
+}
+
+func (f *fakeInjector) Inject(ctx context.Context, target string) error {
+	f.calls++
+	return f.err
+}
+
+type fakeRepo struct {
+	inserts int
+}
+
+func (f *fakeRepo) InsertEvent(ctx context.Context, e *domain.Event) (bool, error) {
+	f.inserts++
+	return true, nil
+}
+
+func (f *fakeRepo) WithinTransaction(ctx context.Context, fn func(repo ports.EventRepositoryPort) error) error {
+	return fn(f)
+}
+
+func TestEventRepository_PassThrough(t *testing.T) {
+	inj := &fakeInjector{}
+	next := &fakeRepo{}
+	repo := NewEventRepository(next, inj, "events.repository")
+
+	created, err := repo.InsertEvent(context.Background(), &domain.Event{})
+	if err != nil || !created {
+		t.Fatalf("expected pass-through, got created=%v err=%v", created, err)
+	}
+	if next.inserts != 1 {
+		t.Fatalf("expected delegate to be called")
+	}
+}
+
+func TestEventRepository_InjectedError(t *testing.T) {
+	inj := &fakeInjector{err: errors.New("injected fault")}
+	next := &fakeRepo{}
+	repo := NewEventRepository(next, inj, "events.repository")
+
+	if _, err := repo.InsertEvent(context.Background(), &domain.Event{}); err == nil {
+		t.Fatalf("expected injected error")
+	}
+	if next.inserts != 0 {
+		t.Fatalf("delegate should not be called on injected error")
+	}
+}
+
+func TestEventRepository_InjectsInsideTransaction(t *testing.T) {
+	inj := &fakeInjector{}
+	next := &fakeRepo{}
+	repo := NewEventRepository(next, inj, "events.repository")
+
+	err := repo.WithinTransaction(context.Background(), func(tx ports.EventRepositoryPort) error {
+		_, err := tx.InsertEvent(context.Background(), &domain.Event{})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inj.calls != 2 {
+		t.Fatalf("expected injection for tx and insert, got %d", inj.calls)
+	}
+}
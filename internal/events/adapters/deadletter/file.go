@@ -0,0 +1,272 @@
+package deadletter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+)
+
+// FileStore keeps dead letters in a JSON lines file, so they survive the
+// database outage that produced them. Entries are held in memory: adding
+// one appends a line and syncs it, updating or removing rewrites the file.
+type FileStore struct {
+	mu      sync.Mutex
+	path    string
+	entries []domain.DeadLetter // oldest first
+}
+
+var _ ports.DeadLetterPort = (*FileStore)(nil)
+
+// OpenFileStore loads the entries of the file at path, which is created on
+// the first dead letter. A last line cut short by a crash is dropped.
+func OpenFileStore(path string) (*FileStore, error) {
+	s := &FileStore{path: path}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for n := 1; ; n++ {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			// Only a complete line ends with a newline. Drop a torn one
+			// from the file too, or the next append would extend it.
+			if len(line) > 0 {
+				if err := s.rewrite(s.entries); err != nil {
+					return nil, err
+				}
+			}
+			return s, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		d, err := decode(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", path, n, err)
+		}
+		s.entries = append(s.entries, d)
+	}
+}
+
+func (s *FileStore) AddDeadLetter(ctx context.Context, d domain.DeadLetter) error {
+	line, err := encode(d)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	s.entries = append(s.entries, d)
+	return nil
+}
+
+func (s *FileStore) ListDeadLetters(ctx context.Context, limit int) ([]domain.DeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := min(limit, len(s.entries))
+	out := make([]domain.DeadLetter, n)
+	copy(out, s.entries[:n])
+	return out, nil
+}
+
+func (s *FileStore) GetDeadLetter(ctx context.Context, id string) (domain.DeadLetter, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, d := range s.entries {
+		if d.ID == id {
+			return d, true, nil
+		}
+	}
+	return domain.DeadLetter{}, false, nil
+}
+
+func (s *FileStore) UpdateDeadLetter(ctx context.Context, d domain.DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]domain.DeadLetter, len(s.entries))
+	copy(entries, s.entries)
+	for i := range entries {
+		if entries[i].ID == d.ID {
+			entries[i] = d
+		}
+	}
+	return s.rewrite(entries)
+}
+
+func (s *FileStore) RemoveDeadLetter(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]domain.DeadLetter, 0, len(s.entries))
+	for _, d := range s.entries {
+		if d.ID != id {
+			entries = append(entries, d)
+		}
+	}
+	return s.rewrite(entries)
+}
+
+// rewrite replaces the file with entries through a rename, so a crash
+// leaves either the old or the new file. Callers hold s.mu.
+func (s *FileStore) rewrite(entries []domain.DeadLetter) error {
+	var buf bytes.Buffer
+	for _, d := range entries {
+		line, err := encode(d)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+	}
+
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+
+	s.entries = entries
+	return nil
+}
+
+// ------------------------------------------------------------
+// LINE FORMAT
+// ------------------------------------------------------------
+
+type line struct {
+	ID              string         `json:"id"`
+	EventID         string         `json:"event_id,omitempty"`
+	EventName       string         `json:"event_name"`
+	Channel         string         `json:"channel"`
+	CampaignID      string         `json:"campaign_id,omitempty"`
+	UserID          string         `json:"user_id,omitempty"`
+	AnonymousID     string         `json:"anonymous_id,omitempty"`
+	EventTime       time.Time      `json:"event_time"`
+	Value           *float64       `json:"value,omitempty"`
+	Tags            []string       `json:"tags,omitempty"`
+	Metadata        map[string]any `json:"metadata,omitempty"`
+	DedupeKey       string         `json:"dedupe_key"`
+	DedupeExpiresAt time.Time      `json:"dedupe_expires_at"`
+	SampleRate      float64        `json:"sample_rate,omitempty"`
+	TenantID        string         `json:"tenant_id,omitempty"`
+	Error           string         `json:"error"`
+	Attempts        int            `json:"attempts"`
+	FailedAt        time.Time      `json:"failed_at"`
+	LastAttemptAt   time.Time      `json:"last_attempt_at"`
+}
+
+func encode(d domain.DeadLetter) ([]byte, error) {
+	e := d.Event
+	b, err := json.Marshal(line{
+		ID:              d.ID,
+		EventID:         e.EventID,
+		EventName:       e.EventName,
+		Channel:         e.Channel,
+		CampaignID:      e.CampaignID,
+		UserID:          e.UserID,
+		AnonymousID:     e.AnonymousID,
+		EventTime:       e.EventTime,
+		Value:           e.Value,
+		Tags:            e.Tags,
+		Metadata:        e.Metadata,
+		DedupeKey:       e.DedupeKey,
+		DedupeExpiresAt: e.DedupeExpiresAt,
+		SampleRate:      e.SampleRate,
+		TenantID:        e.TenantID,
+		Error:           d.Error,
+		Attempts:        d.Attempts,
+		FailedAt:        d.FailedAt,
+		LastAttemptAt:   d.LastAttemptAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+func decode(b []byte) (domain.DeadLetter, error) {
+	// Numbers stay json.Number so large integers are stored exactly.
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var l line
+	if err := dec.Decode(&l); err != nil {
+		return domain.DeadLetter{}, err
+	}
+
+	return domain.DeadLetter{
+		ID: l.ID,
+		Event: domain.Event{
+			EventID:         l.EventID,
+			EventName:       l.EventName,
+			Channel:         l.Channel,
+			CampaignID:      l.CampaignID,
+			UserID:          l.UserID,
+			AnonymousID:     l.AnonymousID,
+			EventTime:       l.EventTime,
+			Value:           l.Value,
+			Tags:            l.Tags,
+			Metadata:        l.Metadata,
+			DedupeKey:       l.DedupeKey,
+			DedupeExpiresAt: l.DedupeExpiresAt,
+			SampleRate:      l.SampleRate,
+			TenantID:        l.TenantID,
+		},
+		Error:         l.Error,
+		Attempts:      l.Attempts,
+		FailedAt:      l.FailedAt,
+		LastAttemptAt: l.LastAttemptAt,
+	}, nil
+}
@@ -0,0 +1,115 @@
+package deadletter
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/events/core/domain"
+)
+
+func deadLetter(id string) domain.DeadLetter {
+	value := 42.5
+	at := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	return domain.DeadLetter{
+		ID: id,
+		Event: domain.Event{
+			EventName: "purchase",
+			Channel:   "web",
+			UserID:    "u1",
+			EventTime: at,
+			Value:     &value,
+			Tags:      []string{"promo"},
+			Metadata:  map[string]any{"order_id": json.Number("9007199254740993")},
+			DedupeKey: "purchase|u1|web||1765101600",
+		},
+		Error:         "connection refused",
+		Attempts:      3,
+		FailedAt:      at.Add(time.Second),
+		LastAttemptAt: at.Add(time.Second),
+	}
+}
+
+func TestFileStore_SurvivesReopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "dead_letters.jsonl")
+
+	s, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		if err := s.AddDeadLetter(ctx, deadLetter(id)); err != nil {
+			t.Fatalf("add %s: %v", id, err)
+		}
+	}
+	updated := deadLetter("b")
+	updated.Attempts = 4
+	updated.Error = "timeout"
+	if err := s.UpdateDeadLetter(ctx, updated); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if err := s.RemoveDeadLetter(ctx, "a"); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+
+	reopened, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	got, _ := reopened.ListDeadLetters(ctx, 10)
+	if len(got) != 2 || got[0].ID != "b" || got[1].ID != "c" {
+		t.Fatalf("unexpected entries: %+v", got)
+	}
+	if !reflect.DeepEqual(got[0], updated) {
+		t.Fatalf("entry did not round-trip:\n got %+v\nwant %+v", got[0], updated)
+	}
+
+	if _, found, _ := reopened.GetDeadLetter(ctx, "a"); found {
+		t.Fatal("removed entry must be gone")
+	}
+	if got, _ := reopened.ListDeadLetters(ctx, 1); len(got) != 1 || got[0].ID != "b" {
+		t.Fatalf("expected the oldest entry only, got %+v", got)
+	}
+}
+
+func TestFileStore_DropsTornLastLine(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "dead_letters.jsonl")
+
+	line, _ := encode(deadLetter("a"))
+	if err := os.WriteFile(path, append(line, `{"id":"b","event_na`...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if err := s.AddDeadLetter(ctx, deadLetter("c")); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	reopened, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	got, _ := reopened.ListDeadLetters(ctx, 10)
+	if len(got) != 2 || got[0].ID != "a" || got[1].ID != "c" {
+		t.Fatalf("unexpected entries: %+v", got)
+	}
+}
+
+func TestFileStore_RejectsCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead_letters.jsonl")
+	if err := os.WriteFile(path, []byte("not json\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenFileStore(path); err == nil {
+		t.Fatal("expected an error for a corrupt line")
+	}
+}
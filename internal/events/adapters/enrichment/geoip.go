@@ -0,0 +1,114 @@
+package enrichment
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/netip"
+	"strings"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+)
+
+// GeoIPEnricher adds a "geo" object ({"country", "region"}) for client IPs
+// found in a static range table. The most specific matching range wins.
+type GeoIPEnricher struct {
+	// Ranges keyed by prefix length, so a lookup is one map access per
+	// possible length instead of a scan over the whole table.
+	byBits map[int]map[netip.Prefix]GeoLocation
+	bits   []int // prefix lengths present, longest first
+}
+
+type GeoLocation struct {
+	Country string
+	Region  string
+}
+
+var _ ports.EnricherPort = (*GeoIPEnricher)(nil)
+
+// LoadGeoIPCSV reads "cidr,country[,region]" lines. Empty lines and lines
+// starting with '#' are ignored.
+func LoadGeoIPCSV(r io.Reader) (*GeoIPEnricher, error) {
+	g := &GeoIPEnricher{byBits: map[int]map[netip.Prefix]GeoLocation{}}
+
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Split(text, ",")
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("geoip line %d: expected cidr,country[,region]", line)
+		}
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(fields[0]))
+		if err != nil {
+			return nil, fmt.Errorf("geoip line %d: %w", line, err)
+		}
+
+		loc := GeoLocation{Country: strings.ToUpper(strings.TrimSpace(fields[1]))}
+		if len(fields) == 3 {
+			loc.Region = strings.TrimSpace(fields[2])
+		}
+		g.add(prefix, loc)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func (g *GeoIPEnricher) add(p netip.Prefix, loc GeoLocation) {
+	p = p.Masked()
+	if g.byBits[p.Bits()] == nil {
+		g.byBits[p.Bits()] = map[netip.Prefix]GeoLocation{}
+
+		// Keep bits sorted longest first.
+		i := 0
+		for i < len(g.bits) && g.bits[i] > p.Bits() {
+			i++
+		}
+		g.bits = append(g.bits[:i], append([]int{p.Bits()}, g.bits[i:]...)...)
+	}
+	g.byBits[p.Bits()][p] = loc
+}
+
+// Lookup returns the location of the most specific range containing ip.
+func (g *GeoIPEnricher) Lookup(ip netip.Addr) (GeoLocation, bool) {
+	ip = ip.Unmap()
+	for _, bits := range g.bits {
+		if bits > ip.BitLen() {
+			continue
+		}
+		p, err := ip.Prefix(bits)
+		if err != nil {
+			continue
+		}
+		if loc, ok := g.byBits[bits][p]; ok {
+			return loc, true
+		}
+	}
+	return GeoLocation{}, false
+}
+
+func (g *GeoIPEnricher) Enrich(ctx context.Context, e *domain.Event, client domain.ClientInfo) map[string]any {
+	ip, err := netip.ParseAddr(client.IP)
+	if err != nil {
+		return nil
+	}
+
+	loc, ok := g.Lookup(ip)
+	if !ok {
+		return nil
+	}
+
+	geo := map[string]any{"country": loc.Country}
+	if loc.Region != "" {
+		geo["region"] = loc.Region
+	}
+	return map[string]any{"geo": geo}
+}
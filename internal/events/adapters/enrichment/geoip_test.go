@@ -0,0 +1,51 @@
+package enrichment
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"event-metrics-service/internal/events/core/domain"
+)
+
+const geoTable = `
+# cidr,country[,region]
+85.96.0.0/12,tr
+85.100.0.0/16,TR,Istanbul
+2a02:e0::/29,TR
+`
+
+func TestGeoIPEnricher(t *testing.T) {
+	g, err := LoadGeoIPCSV(strings.NewReader(geoTable))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		ip   string
+		want map[string]any
+	}{
+		{"85.97.1.1", map[string]any{"geo": map[string]any{"country": "TR"}}},
+		{"85.100.3.4", map[string]any{"geo": map[string]any{"country": "TR", "region": "Istanbul"}}},
+		{"::ffff:85.100.3.4", map[string]any{"geo": map[string]any{"country": "TR", "region": "Istanbul"}}},
+		{"2a02:e0:1::1", map[string]any{"geo": map[string]any{"country": "TR"}}},
+		{"10.0.0.1", nil},
+		{"not-an-ip", nil},
+	}
+
+	for _, tt := range tests {
+		got := g.Enrich(context.Background(), &domain.Event{}, domain.ClientInfo{IP: tt.ip})
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.ip, tt.want, got)
+		}
+	}
+}
+
+func TestLoadGeoIPCSV_Invalid(t *testing.T) {
+	for _, table := range []string{"85.96.0.0/12", "85.96.0.0/33,TR", "a,b,c,d"} {
+		if _, err := LoadGeoIPCSV(strings.NewReader(table)); err == nil {
+			t.Fatalf("%q: expected error", table)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package enrichment
+
+import (
+	"context"
+	"strings"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+)
+
+// UserAgentEnricher adds a "ua" object parsed from the client's User-Agent:
+// browser, browser_version (major only, to keep cardinality low), os and
+// device (desktop | mobile | tablet | bot).
+type UserAgentEnricher struct{}
+
+func NewUserAgentEnricher() *UserAgentEnricher {
+	return &UserAgentEnricher{}
+}
+
+var _ ports.EnricherPort = (*UserAgentEnricher)(nil)
+
+func (u *UserAgentEnricher) Enrich(ctx context.Context, e *domain.Event, client domain.ClientInfo) map[string]any {
+	if client.UserAgent == "" {
+		return nil
+	}
+
+	ua := ParseUserAgent(client.UserAgent)
+	return map[string]any{
+		"ua": map[string]any{
+			"browser":         ua.Browser,
+			"browser_version": ua.BrowserVersion,
+			"os":              ua.OS,
+			"device":          ua.Device,
+		},
+	}
+}
+
+type UserAgent struct {
+	Browser        string
+	BrowserVersion string
+	OS             string
+	Device         string
+}
+
+const unknown = "Other"
+
+// Checked in order: most browsers also claim to be Chrome and/or Safari.
+var browserTokens = []struct{ token, name string }{
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"SamsungBrowser/", "Samsung Internet"},
+	{"FxiOS/", "Firefox"},
+	{"Firefox/", "Firefox"},
+	{"CriOS/", "Chrome"},
+	{"Chrome/", "Chrome"},
+}
+
+var botMarkers = []string{"bot", "crawler", "spider", "curl/", "wget/", "python-requests"}
+
+// ParseUserAgent recognizes the common browsers and platforms. It is a
+// heuristic, not a full UA database; anything unrecognized is "Other".
+func ParseUserAgent(s string) UserAgent {
+	ua := UserAgent{Browser: unknown, OS: parseOS(s), Device: "desktop"}
+
+	for _, b := range browserTokens {
+		if v, ok := tokenVersion(s, b.token); ok {
+			ua.Browser, ua.BrowserVersion = b.name, v
+			break
+		}
+	}
+	if ua.Browser == unknown && strings.Contains(s, "Safari/") {
+		if v, ok := tokenVersion(s, "Version/"); ok {
+			ua.Browser, ua.BrowserVersion = "Safari", v
+		}
+	}
+
+	lower := strings.ToLower(s)
+	for _, m := range botMarkers {
+		if strings.Contains(lower, m) {
+			ua.Device = "bot"
+			return ua
+		}
+	}
+
+	switch {
+	case strings.Contains(s, "iPad") || strings.Contains(s, "Tablet") ||
+		(strings.Contains(s, "Android") && !strings.Contains(s, "Mobile")):
+		ua.Device = "tablet"
+	case strings.Contains(s, "Mobi") || strings.Contains(s, "iPhone"):
+		ua.Device = "mobile"
+	}
+
+	return ua
+}
+
+func parseOS(s string) string {
+	switch {
+	case strings.Contains(s, "Windows"):
+		return "Windows"
+	case strings.Contains(s, "Android"):
+		return "Android"
+	case strings.Contains(s, "iPhone") || strings.Contains(s, "iPad") || strings.Contains(s, "iPod"):
+		return "iOS"
+	case strings.Contains(s, "Mac OS X") || strings.Contains(s, "Macintosh"):
+		return "macOS"
+	case strings.Contains(s, "CrOS"):
+		return "ChromeOS"
+	case strings.Contains(s, "Linux"):
+		return "Linux"
+	}
+	return unknown
+}
+
+// tokenVersion returns the major version following token, e.g. "120" for
+// "Chrome/120.0.6099.71".
+func tokenVersion(s, token string) (string, bool) {
+	i := strings.Index(s, token)
+	if i < 0 {
+		return "", false
+	}
+
+	v := s[i+len(token):]
+	if end := strings.IndexAny(v, " ;)"); end >= 0 {
+		v = v[:end]
+	}
+	major, _, _ := strings.Cut(v, ".")
+	return major, true
+}
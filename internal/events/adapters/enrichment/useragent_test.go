@@ -0,0 +1,53 @@
+package enrichment
+
+import (
+	"context"
+	"testing"
+
+	"event-metrics-service/internal/events/core/domain"
+)
+
+func TestParseUserAgent(t *testing.T) {
+	tests := []struct {
+		ua   string
+		want UserAgent
+	}{
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.71 Safari/537.36",
+			UserAgent{Browser: "Chrome", BrowserVersion: "120", OS: "Windows", Device: "desktop"},
+		},
+		{
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.61",
+			UserAgent{Browser: "Edge", BrowserVersion: "120", OS: "Windows", Device: "desktop"},
+		},
+		{
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1",
+			UserAgent{Browser: "Safari", BrowserVersion: "17", OS: "iOS", Device: "mobile"},
+		},
+		{
+			"Mozilla/5.0 (Linux; Android 13; SM-X200) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/119.0.0.0 Safari/537.36",
+			UserAgent{Browser: "Chrome", BrowserVersion: "119", OS: "Android", Device: "tablet"},
+		},
+		{
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 14.1; rv:120.0) Gecko/20100101 Firefox/120.0",
+			UserAgent{Browser: "Firefox", BrowserVersion: "120", OS: "macOS", Device: "desktop"},
+		},
+		{
+			"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			UserAgent{Browser: "Other", OS: "Other", Device: "bot"},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := ParseUserAgent(tt.ua); got != tt.want {
+			t.Errorf("%s:\nexpected %+v\ngot      %+v", tt.ua, tt.want, got)
+		}
+	}
+}
+
+func TestUserAgentEnricher_NoHeader(t *testing.T) {
+	got := NewUserAgentEnricher().Enrich(context.Background(), &domain.Event{}, domain.ClientInfo{IP: "10.0.0.1"})
+	if got != nil {
+		t.Fatalf("expected no fields without a user agent, got %v", got)
+	}
+}
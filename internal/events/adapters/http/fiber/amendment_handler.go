@@ -0,0 +1,162 @@
+package fiber
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AmendEventUseCase interface {
+	Execute(ctx context.Context, ref string, in usecase.AmendEventInput) (usecase.AmendEventResult, error)
+	History(ctx context.Context, ref string) ([]domain.EventAmendment, error)
+}
+
+// AmendmentHandler corrects stored events and serves their audit trail.
+type AmendmentHandler struct {
+	uc AmendEventUseCase
+}
+
+func NewAmendmentHandler(uc AmendEventUseCase) *AmendmentHandler {
+	return &AmendmentHandler{uc: uc}
+}
+
+// AmendEvent godoc
+// @Summary Correct a stored event
+// @Description Fixes channel, campaign_id, tags and metadata of a stored event and records the change, with its reason,
+// @Description in the event's audit trail. tags replace the stored tags; metadata keys are merged, null removing a key.
+// @Description The corrected event must pass the tag, metadata and schema checks of ingest. Event name, user, time and
+// @Description dedupe key cannot be changed. id is the event_id (UUID) or the numeric id.
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param id path string true "event_id (UUID) or numeric id"
+// @Param request body AmendEventRequest true "Correction"
+// @Success 200 {object} AmendEventResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse "metadata_too_large"
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id} [patch]
+func (h *AmendmentHandler) AmendEvent(c *fiber.Ctx) error {
+	var req AmendEventRequest
+	// UseNumber keeps large integers in metadata exact.
+	dec := json.NewDecoder(bytes.NewReader(c.Body()))
+	dec.UseNumber()
+	if err := dec.Decode(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{Error: "invalid_json"})
+	}
+
+	res, err := h.uc.Execute(c.UserContext(), c.Params("id"), usecase.AmendEventInput{
+		Channel:    req.Channel,
+		CampaignID: req.CampaignID,
+		Tags:       req.Tags,
+		Metadata:   req.Metadata,
+		Reason:     req.Reason,
+		AmendedBy:  req.AmendedBy,
+	})
+	if err != nil {
+		return amendmentError(c, err)
+	}
+
+	resp := AmendEventResponse{Event: toStoredEventResponse(res.Event)}
+	if res.Amendment != nil {
+		a := toAmendmentResponse(*res.Amendment)
+		resp.Amendment = &a
+	}
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+// ListAmendments godoc
+// @Summary Show the audit trail of an event
+// @Description Returns the corrections made to a stored event with PATCH /events/{id}, oldest first.
+// @Tags Events
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param id path string true "event_id (UUID) or numeric id"
+// @Success 200 {object} AmendmentListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id}/amendments [get]
+func (h *AmendmentHandler) ListAmendments(c *fiber.Ctx) error {
+	amendments, err := h.uc.History(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return amendmentError(c, err)
+	}
+
+	resp := AmendmentListResponse{Amendments: make([]AmendmentResponse, 0, len(amendments))}
+	for _, a := range amendments {
+		resp.Amendments = append(resp.Amendments, toAmendmentResponse(a))
+	}
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func amendmentError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidEventRef):
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error:   "invalid_event_id",
+			Message: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrEventNotFound):
+		return c.Status(http.StatusNotFound).JSON(ErrorResponse{
+			Error:   "event_not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrEmptyAmendment):
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error:   "empty_amendment",
+			Message: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrInvalidTags):
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error:   "invalid_tags",
+			Message: err.Error(),
+			Details: errorDetails(err),
+		})
+	case errors.Is(err, usecase.ErrSchemaViolation):
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error:   "schema_violation",
+			Message: err.Error(),
+			Details: errorDetails(err),
+		})
+	case errors.Is(err, usecase.ErrInvalidEvent):
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error:   "invalid_amendment",
+			Message: err.Error(),
+			Details: errorDetails(err),
+		})
+	case errors.Is(err, usecase.ErrMetadataTooLarge):
+		return c.Status(http.StatusRequestEntityTooLarge).JSON(ErrorResponse{
+			Error:   "metadata_too_large",
+			Message: err.Error(),
+		})
+	default:
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error: "internal_server_error",
+		})
+	}
+}
+
+func toAmendmentResponse(a domain.EventAmendment) AmendmentResponse {
+	changes := make([]FieldChangeResponse, 0, len(a.Changes))
+	for _, ch := range a.Changes {
+		changes = append(changes, FieldChangeResponse(ch))
+	}
+	return AmendmentResponse{
+		ID:         a.ID,
+		EventRowID: a.EventRowID,
+		Changes:    changes,
+		Reason:     a.Reason,
+		AmendedBy:  a.AmendedBy,
+		AmendedAt:  a.AmendedAt,
+	}
+}
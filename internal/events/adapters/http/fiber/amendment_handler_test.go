@@ -0,0 +1,125 @@
+package fiber
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeAmendEventUseCase struct {
+	ExecuteFn func(ctx context.Context, ref string, in usecase.AmendEventInput) (usecase.AmendEventResult, error)
+	HistoryFn func(ctx context.Context, ref string) ([]domain.EventAmendment, error)
+}
+
+func (f *fakeAmendEventUseCase) Execute(ctx context.Context, ref string, in usecase.AmendEventInput) (usecase.AmendEventResult, error) {
+	return f.ExecuteFn(ctx, ref, in)
+}
+
+func (f *fakeAmendEventUseCase) History(ctx context.Context, ref string) ([]domain.EventAmendment, error) {
+	return f.HistoryFn(ctx, ref)
+}
+
+func setupAmendmentApp(uc AmendEventUseCase) *fiber.App {
+	app := fiber.New()
+	h := NewAmendmentHandler(uc)
+	app.Patch("/events/:id", h.AmendEvent)
+	app.Get("/events/:id/amendments", h.ListAmendments)
+	return app
+}
+
+func TestAmendEvent_Success(t *testing.T) {
+	amendedAt := time.Date(2025, 12, 8, 9, 0, 0, 0, time.UTC)
+	var got usecase.AmendEventInput
+	app := setupAmendmentApp(&fakeAmendEventUseCase{
+		ExecuteFn: func(ctx context.Context, ref string, in usecase.AmendEventInput) (usecase.AmendEventResult, error) {
+			if ref != "1042" {
+				t.Fatalf("unexpected ref %q", ref)
+			}
+			got = in
+			return usecase.AmendEventResult{
+				Event: domain.StoredEvent{ID: 1042, Event: domain.Event{EventName: "purchase", CampaignID: "bf-2025"}},
+				Amendment: &domain.EventAmendment{
+					ID:         7,
+					EventRowID: 1042,
+					Changes:    []domain.FieldChange{{Field: "campaign_id", Before: "bf-2024", After: "bf-2025"}},
+					Reason:     "wrong campaign",
+					AmendedAt:  amendedAt,
+				},
+			}, nil
+		},
+	})
+
+	resp, body := doRequest(t, app, http.MethodPatch, "/events/1042", map[string]any{
+		"campaign_id": "bf-2025",
+		"metadata":    map[string]any{"order_id": json.Number("12345678901234567890"), "coupon": nil},
+		"reason":      "wrong campaign",
+	})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, body)
+	}
+	if got.CampaignID == nil || *got.CampaignID != "bf-2025" || got.Channel != nil || got.Tags != nil {
+		t.Fatalf("unexpected input: %+v", got)
+	}
+	if v, ok := got.Metadata["coupon"]; !ok || v != nil || got.Metadata["order_id"] != json.Number("12345678901234567890") {
+		t.Fatalf("unexpected metadata: %#v", got.Metadata)
+	}
+
+	var out AmendEventResponse
+	if err := json.Unmarshal(body, &out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if out.Event.CampaignID != "bf-2025" || out.Amendment == nil || out.Amendment.ID != 7 || out.Amendment.Changes[0].Field != "campaign_id" {
+		t.Fatalf("unexpected response: %s", body)
+	}
+}
+
+func TestAmendEvent_Errors(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{usecase.ErrEventNotFound, http.StatusNotFound, "event_not_found"},
+		{usecase.ErrInvalidEventRef, http.StatusBadRequest, "invalid_event_id"},
+		{usecase.ErrEmptyAmendment, http.StatusBadRequest, "empty_amendment"},
+		{&usecase.ValidationError{Violations: []usecase.FieldViolation{{Field: "reason", Message: "is required"}}}, http.StatusBadRequest, "invalid_amendment"},
+		{usecase.ErrMetadataTooLarge, http.StatusRequestEntityTooLarge, "metadata_too_large"},
+	}
+
+	for _, tt := range tests {
+		app := setupAmendmentApp(&fakeAmendEventUseCase{
+			ExecuteFn: func(ctx context.Context, ref string, in usecase.AmendEventInput) (usecase.AmendEventResult, error) {
+				return usecase.AmendEventResult{}, tt.err
+			},
+		})
+		resp, body := doRequest(t, app, http.MethodPatch, "/events/1042", map[string]any{"channel": "ios"})
+		if resp.StatusCode != tt.wantStatus || !strings.Contains(string(body), tt.wantCode) {
+			t.Fatalf("%v: expected %d %s, got %d: %s", tt.err, tt.wantStatus, tt.wantCode, resp.StatusCode, body)
+		}
+	}
+}
+
+func TestListAmendments(t *testing.T) {
+	app := setupAmendmentApp(&fakeAmendEventUseCase{
+		HistoryFn: func(ctx context.Context, ref string) ([]domain.EventAmendment, error) {
+			return []domain.EventAmendment{{ID: 7, EventRowID: 1042, Reason: "wrong campaign"}}, nil
+		},
+	})
+
+	resp, body := doRequest(t, app, http.MethodGet, "/events/1042/amendments", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, body)
+	}
+	var out AmendmentListResponse
+	if err := json.Unmarshal(body, &out); err != nil || len(out.Amendments) != 1 || out.Amendments[0].Changes == nil {
+		t.Fatalf("unexpected response %s: %v", body, err)
+	}
+}
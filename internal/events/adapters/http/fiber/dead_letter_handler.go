@@ -0,0 +1,143 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type DeadLetterUseCase interface {
+	List(ctx context.Context, limit int) ([]domain.DeadLetter, error)
+	Redrive(ctx context.Context, id string) (usecase.RedriveResult, error)
+	RedriveOldest(ctx context.Context, limit int) (usecase.RedriveResult, error)
+	Discard(ctx context.Context, id string) error
+}
+
+// DeadLetterHandler serves the dead-letter queue of failed inserts.
+type DeadLetterHandler struct {
+	uc DeadLetterUseCase
+}
+
+func NewDeadLetterHandler(uc DeadLetterUseCase) *DeadLetterHandler {
+	return &DeadLetterHandler{uc: uc}
+}
+
+// ListDeadLetters godoc
+// @Summary List dead-lettered events
+// @Description Returns events whose insert failed after the retries, oldest first, with the last error.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param limit query int false "Maximum entries (default 100, max 1000)"
+// @Success 200 {object} DeadLetterListResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/dead-letters [get]
+func (h *DeadLetterHandler) ListDeadLetters(c *fiber.Ctx) error {
+	limit, ok := queryLimit(c)
+	if !ok {
+		return invalidQuery(c, "invalid 'limit' parameter")
+	}
+
+	entries, err := h.uc.List(c.UserContext(), limit)
+	if err != nil {
+		return deadLetterError(c, err)
+	}
+
+	resp := DeadLetterListResponse{DeadLetters: make([]DeadLetterResponse, 0, len(entries))}
+	for _, d := range entries {
+		resp.DeadLetters = append(resp.DeadLetters, toDeadLetterResponse(d))
+	}
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+// RedriveDeadLetters godoc
+// @Summary Re-drive dead-lettered events
+// @Description Inserts up to limit dead-lettered events again, oldest first. Stored and duplicate events leave the
+// @Description queue; the first failure stops the run and stays queued with its new error.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param limit query int false "Maximum entries (default 100, max 1000)"
+// @Success 200 {object} RedriveResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/dead-letters/redrive [post]
+func (h *DeadLetterHandler) RedriveDeadLetters(c *fiber.Ctx) error {
+	limit, ok := queryLimit(c)
+	if !ok {
+		return invalidQuery(c, "invalid 'limit' parameter")
+	}
+
+	res, err := h.uc.RedriveOldest(c.UserContext(), limit)
+	if err != nil {
+		return deadLetterError(c, err)
+	}
+	return c.Status(http.StatusOK).JSON(toRedriveResponse(res))
+}
+
+// RedriveDeadLetter godoc
+// @Summary Re-drive one dead-lettered event
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param id path string true "Dead letter ID"
+// @Success 200 {object} RedriveResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/dead-letters/{id}/redrive [post]
+func (h *DeadLetterHandler) RedriveDeadLetter(c *fiber.Ctx) error {
+	res, err := h.uc.Redrive(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return deadLetterError(c, err)
+	}
+	return c.Status(http.StatusOK).JSON(toRedriveResponse(res))
+}
+
+// DiscardDeadLetter godoc
+// @Summary Discard a dead-lettered event
+// @Description Drops an entry that should not be stored, e.g. one the database keeps refusing.
+// @Tags Admin
+// @Param X-Admin-Token header string true "Admin token"
+// @Param id path string true "Dead letter ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/dead-letters/{id} [delete]
+func (h *DeadLetterHandler) DiscardDeadLetter(c *fiber.Ctx) error {
+	if err := h.uc.Discard(c.UserContext(), c.Params("id")); err != nil {
+		return deadLetterError(c, err)
+	}
+	return c.SendStatus(http.StatusNoContent)
+}
+
+func queryLimit(c *fiber.Ctx) (int, bool) {
+	raw := c.Query("limit")
+	if raw == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(raw)
+	return n, err == nil && n >= 1
+}
+
+func deadLetterError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrDeadLetterNotFound):
+		return c.Status(http.StatusNotFound).JSON(ErrorResponse{
+			Error:   "dead_letter_not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrInvalidDeadLetters):
+		return invalidQuery(c, err.Error())
+	default:
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error: "internal_server_error",
+		})
+	}
+}
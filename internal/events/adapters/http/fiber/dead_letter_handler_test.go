@@ -0,0 +1,149 @@
+package fiber
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeDeadLetterUseCase struct {
+	ListFn          func(ctx context.Context, limit int) ([]domain.DeadLetter, error)
+	RedriveFn       func(ctx context.Context, id string) (usecase.RedriveResult, error)
+	RedriveOldestFn func(ctx context.Context, limit int) (usecase.RedriveResult, error)
+	DiscardFn       func(ctx context.Context, id string) error
+}
+
+func (f *fakeDeadLetterUseCase) List(ctx context.Context, limit int) ([]domain.DeadLetter, error) {
+	return f.ListFn(ctx, limit)
+}
+
+func (f *fakeDeadLetterUseCase) Redrive(ctx context.Context, id string) (usecase.RedriveResult, error) {
+	return f.RedriveFn(ctx, id)
+}
+
+func (f *fakeDeadLetterUseCase) RedriveOldest(ctx context.Context, limit int) (usecase.RedriveResult, error) {
+	return f.RedriveOldestFn(ctx, limit)
+}
+
+func (f *fakeDeadLetterUseCase) Discard(ctx context.Context, id string) error {
+	return f.DiscardFn(ctx, id)
+}
+
+func setupDeadLetterApp(uc DeadLetterUseCase) *fiber.App {
+	app := fiber.New()
+	h := NewDeadLetterHandler(uc)
+	app.Get("/admin/dead-letters", h.ListDeadLetters)
+	app.Post("/admin/dead-letters/redrive", h.RedriveDeadLetters)
+	app.Post("/admin/dead-letters/:id/redrive", h.RedriveDeadLetter)
+	app.Delete("/admin/dead-letters/:id", h.DiscardDeadLetter)
+	return app
+}
+
+func testRequest(t *testing.T, app *fiber.App, method, path string) *http.Response {
+	t.Helper()
+	resp, err := app.Test(httptest.NewRequest(method, path, nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	return resp
+}
+
+func TestListDeadLetters(t *testing.T) {
+	uc := &fakeDeadLetterUseCase{
+		ListFn: func(ctx context.Context, limit int) ([]domain.DeadLetter, error) {
+			if limit != 5 {
+				t.Fatalf("expected limit 5, got %d", limit)
+			}
+			return []domain.DeadLetter{{
+				ID:       "dl-1",
+				Event:    domain.Event{EventName: "purchase", UserID: "u1", EventTime: time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)},
+				Error:    "connection refused",
+				Attempts: 3,
+			}}, nil
+		},
+	}
+
+	resp := testRequest(t, setupDeadLetterApp(uc), http.MethodGet, "/admin/dead-letters?limit=5")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var body DeadLetterListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if len(body.DeadLetters) != 1 || body.DeadLetters[0].ID != "dl-1" || body.DeadLetters[0].Attempts != 3 || body.DeadLetters[0].Tags == nil {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+}
+
+func TestRedriveDeadLetters(t *testing.T) {
+	uc := &fakeDeadLetterUseCase{
+		RedriveOldestFn: func(ctx context.Context, limit int) (usecase.RedriveResult, error) {
+			return usecase.RedriveResult{Created: 4, Duplicates: 1, Failed: 1}, nil
+		},
+		RedriveFn: func(ctx context.Context, id string) (usecase.RedriveResult, error) {
+			if id != "dl-1" {
+				return usecase.RedriveResult{}, usecase.ErrDeadLetterNotFound
+			}
+			return usecase.RedriveResult{Created: 1}, nil
+		},
+	}
+	app := setupDeadLetterApp(uc)
+
+	resp := testRequest(t, app, http.MethodPost, "/admin/dead-letters/redrive")
+	var body RedriveResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Created != 4 || body.Failed != 1 {
+		t.Fatalf("unexpected response %d %+v (%v)", resp.StatusCode, body, err)
+	}
+
+	if resp := testRequest(t, app, http.MethodPost, "/admin/dead-letters/dl-1/redrive"); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if resp := testRequest(t, app, http.MethodPost, "/admin/dead-letters/missing/redrive"); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestDiscardDeadLetter(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantStatus int
+	}{
+		{nil, http.StatusNoContent},
+		{usecase.ErrDeadLetterNotFound, http.StatusNotFound},
+		{errors.New("disk full"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		uc := &fakeDeadLetterUseCase{
+			DiscardFn: func(ctx context.Context, id string) error { return tt.err },
+		}
+		if resp := testRequest(t, setupDeadLetterApp(uc), http.MethodDelete, "/admin/dead-letters/dl-1"); resp.StatusCode != tt.wantStatus {
+			t.Fatalf("%v: expected %d, got %d", tt.err, tt.wantStatus, resp.StatusCode)
+		}
+	}
+}
+
+func TestListDeadLetters_BadLimit(t *testing.T) {
+	uc := &fakeDeadLetterUseCase{
+		ListFn: func(ctx context.Context, limit int) ([]domain.DeadLetter, error) {
+			return nil, usecase.ErrInvalidDeadLetters
+		},
+	}
+	app := setupDeadLetterApp(uc)
+
+	for _, path := range []string{"/admin/dead-letters?limit=abc", "/admin/dead-letters?limit=5000"} {
+		if resp := testRequest(t, app, http.MethodGet, path); resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", path, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"event-metrics-service/internal/events/core/ports"
+	"event-metrics-service/internal/events/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type DeleteEventsUseCase interface {
+	Execute(ctx context.Context, f ports.EventDeleteFilter, dryRun bool) (usecase.DeleteEventsResult, error)
+}
+
+// DeleteHandler deletes events by filter.
+type DeleteHandler struct {
+	uc DeleteEventsUseCase
+}
+
+func NewDeleteHandler(uc DeleteEventsUseCase) *DeleteHandler {
+	return &DeleteHandler{uc: uc}
+}
+
+// DeleteEvents godoc
+// @Summary Delete events by filter
+// @Description Deletes every event with event_name whose event_time is within [from, to] (to including its whole
+// @Description second, so events stored with milliseconds are not left behind), optionally only on one
+// @Description channel, in batches. The dedupe claims of deleted events are released. Set dry_run to only count the
+// @Description matching events. A delete that fails midway keeps what it deleted; sending it again finishes it.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param request body DeleteEventsRequest true "Filter"
+// @Success 200 {object} DeleteEventsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/events/delete [post]
+func (h *DeleteHandler) DeleteEvents(c *fiber.Ctx) error {
+	var req DeleteEventsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{Error: "invalid_json"})
+	}
+
+	f := ports.EventDeleteFilter{EventName: req.EventName, Channel: req.Channel}
+	if req.From > 0 {
+		f.From = time.Unix(req.From, 0).UTC()
+	}
+	// The filter's end is exclusive; the whole to second is included, as
+	// event_time has milliseconds.
+	if req.To > 0 {
+		f.To = time.Unix(req.To, 0).Add(time.Second).UTC()
+	}
+
+	res, err := h.uc.Execute(c.UserContext(), f, req.DryRun)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidDeleteFilter) {
+			return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+				Error:   "invalid_filter",
+				Message: err.Error(),
+			})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error:   "delete_failed",
+			Message: fmt.Sprintf("%d events were deleted before the delete failed; send it again to finish", res.Deleted),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(DeleteEventsResponse{
+		DryRun:  res.DryRun,
+		Matched: res.Matched,
+		Deleted: res.Deleted,
+		Batches: res.Batches,
+	})
+}
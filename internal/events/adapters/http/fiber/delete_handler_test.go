@@ -0,0 +1,79 @@
+package fiber
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/events/core/ports"
+	"event-metrics-service/internal/events/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeDeleteEventsUseCase struct {
+	ExecuteFn func(ctx context.Context, f ports.EventDeleteFilter, dryRun bool) (usecase.DeleteEventsResult, error)
+}
+
+func (f *fakeDeleteEventsUseCase) Execute(ctx context.Context, filter ports.EventDeleteFilter, dryRun bool) (usecase.DeleteEventsResult, error) {
+	return f.ExecuteFn(ctx, filter, dryRun)
+}
+
+func setupDeleteApp(uc DeleteEventsUseCase) *fiber.App {
+	app := fiber.New()
+	app.Post("/admin/events/delete", NewDeleteHandler(uc).DeleteEvents)
+	return app
+}
+
+func TestDeleteEvents_DryRun(t *testing.T) {
+	app := setupDeleteApp(&fakeDeleteEventsUseCase{
+		ExecuteFn: func(ctx context.Context, f ports.EventDeleteFilter, dryRun bool) (usecase.DeleteEventsResult, error) {
+			want := ports.EventDeleteFilter{
+				EventName: "test_event",
+				From:      time.Unix(1733529600, 0).UTC(),
+				To:        time.Unix(1733616001, 0).UTC(),
+			}
+			if f != want || !dryRun {
+				t.Fatalf("unexpected call: %+v dry_run=%v", f, dryRun)
+			}
+			return usecase.DeleteEventsResult{DryRun: true, Matched: 1000000}, nil
+		},
+	})
+
+	resp, body := doRequest(t, app, http.MethodPost, "/admin/events/delete", map[string]any{
+		"event_name": "test_event", "from": 1733529600, "to": 1733616000, "dry_run": true,
+	})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, body)
+	}
+	var out DeleteEventsResponse
+	if err := json.Unmarshal(body, &out); err != nil || !out.DryRun || out.Matched != 1000000 {
+		t.Fatalf("unexpected response %s: %v", body, err)
+	}
+}
+
+func TestDeleteEvents_Errors(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{usecase.ErrInvalidDeleteFilter, http.StatusBadRequest, "invalid_filter"},
+		{errors.New("db down"), http.StatusInternalServerError, "5000 events were deleted"},
+	}
+	for _, tt := range tests {
+		app := setupDeleteApp(&fakeDeleteEventsUseCase{
+			ExecuteFn: func(ctx context.Context, f ports.EventDeleteFilter, dryRun bool) (usecase.DeleteEventsResult, error) {
+				return usecase.DeleteEventsResult{Deleted: 5000}, tt.err
+			},
+		})
+		resp, body := doRequest(t, app, http.MethodPost, "/admin/events/delete", map[string]any{"event_name": "test_event"})
+		if resp.StatusCode != tt.wantStatus || !strings.Contains(string(body), tt.wantBody) {
+			t.Fatalf("%v: expected %d %q, got %d: %s", tt.err, tt.wantStatus, tt.wantBody, resp.StatusCode, body)
+		}
+	}
+}
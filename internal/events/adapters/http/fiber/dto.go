@@ -1,19 +1,31 @@
 package fiber
 
+import (
+	"time"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/usecase"
+)
+
 // CreateEventRequest represents event creation payload
 // @Description Event creation DTO
 type CreateEventRequest struct {
-	EventName  string         `json:"event_name"`
-	Channel    string         `json:"channel"`
-	CampaignID string         `json:"campaign_id"`
-	UserID     string         `json:"user_id"`
-	Timestamp  int64          `json:"timestamp"`
-	Tags       []string       `json:"tags"`
-	Metadata   map[string]any `json:"metadata"`
+	EventID     string         `json:"event_id" example:"0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42"`
+	EventName   string         `json:"event_name"`
+	Channel     string         `json:"channel"`
+	CampaignID  string         `json:"campaign_id"`
+	UserID      string         `json:"user_id"`
+	AnonymousID string         `json:"anonymous_id,omitempty" example:"3f9c2a7e-anon"`
+	Timestamp   int64          `json:"timestamp" example:"1733572800"`
+	TimestampMs int64          `json:"timestamp_ms,omitempty" example:"1733572800123"`
+	Value       *float64       `json:"value,omitempty" example:"42.5"`
+	Tags        []string       `json:"tags"`
+	Metadata    map[string]any `json:"metadata"`
 }
 
 type CreateEventResponse struct {
 	Status  string `json:"status"`
+	EventID string `json:"event_id,omitempty"`
 	Message string `json:"message,omitempty"`
 }
 
@@ -22,21 +34,233 @@ type BulkCreateEventsRequest struct {
 }
 
 type bulkEventItem struct {
-	EventName  string         `json:"event_name"`
-	Channel    string         `json:"channel"`
-	CampaignID string         `json:"campaign_id"`
-	UserID     string         `json:"user_id"`
-	Timestamp  int64          `json:"timestamp"`
-	Tags       []string       `json:"tags"`
-	Metadata   map[string]any `json:"metadata"`
+	EventID     string         `json:"event_id"`
+	EventName   string         `json:"event_name"`
+	Channel     string         `json:"channel"`
+	CampaignID  string         `json:"campaign_id"`
+	UserID      string         `json:"user_id"`
+	AnonymousID string         `json:"anonymous_id,omitempty" example:"3f9c2a7e-anon"`
+	Timestamp   int64          `json:"timestamp" example:"1733572800"`
+	TimestampMs int64          `json:"timestamp_ms,omitempty" example:"1733572800123"`
+	Value       *float64       `json:"value,omitempty" example:"42.5"`
+	Tags        []string       `json:"tags"`
+	Metadata    map[string]any `json:"metadata"`
 }
 
 type BulkCreateEventsResponse struct {
-	Created    int `json:"created"`
-	Duplicates int `json:"duplicates"`
+	Created    int                `json:"created"`
+	Duplicates int                `json:"duplicates"`
+	Invalid    int                `json:"invalid"`
+	Accepted   int                `json:"accepted"` // dead-lettered, stored on re-drive
+	Sampled    int                `json:"sampled"`  // valid, dropped by sampling
+	Failed     int                `json:"failed"`   // valid, not stored; safe to retry
+	Items      []BulkItemResponse `json:"items"`
+}
+
+// BulkItemResponse reports the outcome of the event at Index in the request.
+type BulkItemResponse struct {
+	Index   int    `json:"index" example:"0"`
+	EventID string `json:"event_id,omitempty"`
+	Status  string `json:"status" example:"created" enums:"created,duplicate,invalid,accepted,sampled,failed"`
+	Reason  string `json:"reason,omitempty" example:"invalid event"`
+}
+
+// GA4CollectRequest is a Google Analytics 4 Measurement Protocol payload.
+// @Description GA4 Measurement Protocol payload
+type GA4CollectRequest struct {
+	ClientID       string                     `json:"client_id" example:"123456.7654321"`
+	AppInstanceID  string                     `json:"app_instance_id,omitempty"`
+	UserID         string                     `json:"user_id,omitempty"`
+	TimestampMicro ga4Micros                  `json:"timestamp_micros,omitempty" swaggertype:"integer" example:"1733572800123456"`
+	UserProperties map[string]ga4UserProperty `json:"user_properties,omitempty"`
+	Events         []GA4Event                 `json:"events"`
+}
+
+type GA4Event struct {
+	Name           string         `json:"name" example:"purchase"`
+	TimestampMicro ga4Micros      `json:"timestamp_micros,omitempty" swaggertype:"integer"`
+	Params         map[string]any `json:"params,omitempty"`
+}
+
+type ga4UserProperty struct {
+	Value any `json:"value"`
 }
 
 type ErrorResponse struct {
-	Error   string `json:"error" example:"invalid_event"`
-	Message string `json:"message" example:"Event payload is invalid"`
+	Error   string        `json:"error" example:"invalid_event"`
+	Message string        `json:"message" example:"Event payload is invalid"`
+	Details []ErrorDetail `json:"details,omitempty"`
+}
+
+// ErrorDetail points at a single invalid field of the request.
+type ErrorDetail struct {
+	Field   string `json:"field" example:"tags[0]"`
+	Message string `json:"message" example:"exceeds max length 64"`
+}
+
+// StoredEventResponse is an event as stored, including server-side fields.
+type StoredEventResponse struct {
+	ID          int64          `json:"id,omitempty" example:"1042"`
+	EventID     string         `json:"event_id,omitempty" example:"0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42"`
+	EventName   string         `json:"event_name" example:"purchase"`
+	Channel     string         `json:"channel" example:"web"`
+	CampaignID  string         `json:"campaign_id,omitempty"`
+	UserID      string         `json:"user_id"`
+	AnonymousID string         `json:"anonymous_id,omitempty"`
+	EventTime   time.Time      `json:"event_time"`
+	ReceivedAt  time.Time      `json:"received_at"`
+	Value       *float64       `json:"value,omitempty"`
+	Tags        []string       `json:"tags"`
+	Metadata    map[string]any `json:"metadata"`
+	DedupeKey   string         `json:"dedupe_key"`
+	TenantID    string         `json:"tenant_id,omitempty" example:"team-a"`
+}
+
+// ExportObjectResponse locates an export written to object storage.
+type ExportObjectResponse struct {
+	URI    string `json:"uri" example:"s3://exports/ems/events-20251015T101500Z-3f9a1c2e.parquet"`
+	Format string `json:"format" example:"parquet"`
+	Events int64  `json:"events" example:"981234"`
+}
+
+type ListEventsResponse struct {
+	Events     []StoredEventResponse `json:"events"`
+	NextCursor string                `json:"next_cursor,omitempty" example:"MTczMzU3MjgwMDAwMDAwMDAwMDoxMDQy"`
+}
+
+// AmendEventRequest corrects a stored event; omitted fields are unchanged.
+type AmendEventRequest struct {
+	Channel    *string  `json:"channel,omitempty" example:"ios"`
+	CampaignID *string  `json:"campaign_id,omitempty" example:"black-friday"`
+	Tags       []string `json:"tags,omitempty"`
+	// Merged into the stored metadata; null removes a key.
+	Metadata  map[string]any `json:"metadata,omitempty"`
+	Reason    string         `json:"reason" example:"campaign was mis-tagged by the 4.2.0 iOS release"`
+	AmendedBy string         `json:"amended_by,omitempty" example:"jane@example.com"`
+}
+
+type FieldChangeResponse struct {
+	Field  string `json:"field" example:"campaign_id"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+type AmendmentResponse struct {
+	ID         int64                 `json:"id" example:"7"`
+	EventRowID int64                 `json:"event_row_id" example:"1042"`
+	Changes    []FieldChangeResponse `json:"changes"`
+	Reason     string                `json:"reason"`
+	AmendedBy  string                `json:"amended_by,omitempty"`
+	AmendedAt  time.Time             `json:"amended_at"`
+}
+
+type AmendEventResponse struct {
+	Event StoredEventResponse `json:"event"`
+	// null when the event already matched and nothing was changed
+	Amendment *AmendmentResponse `json:"amendment"`
+}
+
+type AmendmentListResponse struct {
+	Amendments []AmendmentResponse `json:"amendments"`
+}
+
+// DeleteEventsRequest selects the events to delete. event_name, from and
+// to are required.
+type DeleteEventsRequest struct {
+	EventName string `json:"event_name" example:"test_event"`
+	Channel   string `json:"channel,omitempty" example:"web"`
+	From      int64  `json:"from" example:"1733529600"` // unix seconds, inclusive
+	To        int64  `json:"to" example:"1733616000"`   // unix seconds, inclusive of the whole second
+	DryRun    bool   `json:"dry_run"`
+}
+
+type DeleteEventsResponse struct {
+	DryRun  bool  `json:"dry_run"`
+	Matched int64 `json:"matched" example:"1000000"`
+	Deleted int64 `json:"deleted" example:"1000000"`
+	Batches int   `json:"batches" example:"200"`
+}
+
+// DeadLetterResponse is an event whose insert failed, as it will be stored on
+// re-drive.
+type DeadLetterResponse struct {
+	ID            string         `json:"id" example:"5f1c2d3e-0a9b-4c8d-b7e6-1f2a3b4c5d6e"`
+	EventID       string         `json:"event_id,omitempty"`
+	EventName     string         `json:"event_name" example:"purchase"`
+	Channel       string         `json:"channel" example:"web"`
+	CampaignID    string         `json:"campaign_id,omitempty"`
+	UserID        string         `json:"user_id,omitempty"`
+	AnonymousID   string         `json:"anonymous_id,omitempty"`
+	EventTime     time.Time      `json:"event_time"`
+	Value         *float64       `json:"value,omitempty"`
+	Tags          []string       `json:"tags"`
+	Metadata      map[string]any `json:"metadata"`
+	DedupeKey     string         `json:"dedupe_key"`
+	Error         string         `json:"error" example:"dial tcp 10.0.0.5:5432: connect: connection refused"`
+	Attempts      int            `json:"attempts" example:"3"`
+	FailedAt      time.Time      `json:"failed_at"`
+	LastAttemptAt time.Time      `json:"last_attempt_at"`
+}
+
+type DeadLetterListResponse struct {
+	DeadLetters []DeadLetterResponse `json:"dead_letters"`
+}
+
+type RedriveResponse struct {
+	Created    int `json:"created" example:"40"`
+	Duplicates int `json:"duplicates" example:"2"`
+	Failed     int `json:"failed" example:"0"`
+}
+
+func toDeadLetterResponse(d domain.DeadLetter) DeadLetterResponse {
+	e := d.Event
+	tags := e.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return DeadLetterResponse{
+		ID:            d.ID,
+		EventID:       e.EventID,
+		EventName:     e.EventName,
+		Channel:       e.Channel,
+		CampaignID:    e.CampaignID,
+		UserID:        e.UserID,
+		AnonymousID:   e.AnonymousID,
+		EventTime:     e.EventTime,
+		Value:         e.Value,
+		Tags:          tags,
+		Metadata:      e.Metadata,
+		DedupeKey:     e.DedupeKey,
+		Error:         d.Error,
+		Attempts:      d.Attempts,
+		FailedAt:      d.FailedAt,
+		LastAttemptAt: d.LastAttemptAt,
+	}
+}
+
+func toRedriveResponse(r usecase.RedriveResult) RedriveResponse {
+	return RedriveResponse{Created: r.Created, Duplicates: r.Duplicates, Failed: r.Failed}
+}
+
+func toStoredEventResponse(e domain.StoredEvent) StoredEventResponse {
+	tags := e.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return StoredEventResponse{
+		ID:          e.ID,
+		EventID:     e.EventID,
+		EventName:   e.EventName,
+		Channel:     e.Channel,
+		CampaignID:  e.CampaignID,
+		UserID:      e.UserID,
+		AnonymousID: e.AnonymousID,
+		EventTime:   e.EventTime,
+		ReceivedAt:  e.ReceivedAt,
+		Value:       e.Value,
+		Tags:        tags,
+		Metadata:    e.Metadata,
+		DedupeKey:   e.DedupeKey,
+		TenantID:    e.TenantID,
+	}
 }
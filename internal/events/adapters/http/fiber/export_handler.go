@@ -0,0 +1,404 @@
+package fiber
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+	"event-metrics-service/internal/parquet"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	ExportFormatNDJSON  = "ndjson"
+	ExportFormatCSV     = "csv"
+	ExportFormatParquet = "parquet"
+)
+
+// Destinations of an export.
+const (
+	ExportToResponse = "response"
+	ExportToS3       = "s3"
+)
+
+// parquetMediaType is the media type of Parquet files.
+const parquetMediaType = "application/vnd.apache.parquet"
+
+// ObjectUpload is an export being written to object storage: Close
+// completes the object and Abort drops it.
+type ObjectUpload interface {
+	io.Writer
+	Close() error
+	Abort() error
+	URI() string
+}
+
+// ObjectStore starts the upload of an export to object storage under name.
+type ObjectStore func(ctx context.Context, name string) ObjectUpload
+
+type QueryHandlerOption func(*EventQueryHandler)
+
+// WithExportStore lets exports be written to object storage with
+// destination=s3 instead of the response.
+func WithExportStore(store ObjectStore) QueryHandlerOption {
+	return func(h *EventQueryHandler) {
+		h.exportStore = store
+	}
+}
+
+// ExportEvents godoc
+// @Summary Export stored events
+// @Description Streams every event matching the filters, newest first, with chunked transfer encoding. Filters are
+// @Description the same as GET /events. NDJSON writes one StoredEventResponse per line; CSV writes a header row
+// @Description and one row per event with tags and metadata as JSON; Parquet writes the same columns, one row group
+// @Description per batch. format wins over the Accept header. If the export fails midway, an NDJSON stream ends with
+// @Description an ErrorResponse line, a CSV stream is cut short and a Parquet file lacks its footer.
+// @Description destination=s3 writes the file to the export bucket instead and answers with its location once it is complete.
+// @Tags Events
+// @Produce application/x-ndjson
+// @Produce text/csv
+// @Produce application/vnd.apache.parquet
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param format query string false "Output format (default ndjson, or csv or parquet when Accept asks for text/csv or application/vnd.apache.parquet)" Enums(ndjson, csv, parquet)
+// @Param destination query string false "Where the file goes: response (default) | s3 (needs EXPORT_S3_BUCKET)" Enums(response, s3)
+// @Param event_name query string false "Event name"
+// @Param channel query string false "Channel"
+// @Param user_id query string false "User ID"
+// @Param campaign_id query string false "Campaign ID"
+// @Param from query int false "From timestamp (unix seconds, inclusive)"
+// @Param to query int false "To timestamp (unix seconds, inclusive of the whole second)"
+// @Param tags query string false "Comma separated tags; events must carry all of them"
+// @Param limit query int false "Maximum number of events (default: all)"
+// @Success 200 {string} string "NDJSON, CSV or Parquet stream, or an ExportObjectResponse with destination=s3"
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse "export_failed: the upload to S3 failed"
+// @Router /events/export [get]
+func (h *EventQueryHandler) ExportEvents(c *fiber.Ctx) error {
+	q, msg := parseEventFilters(c)
+	if msg != "" {
+		return invalidQuery(c, msg)
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			return invalidQuery(c, "invalid 'limit' parameter")
+		}
+		q.Limit = n
+	}
+
+	format := c.Query("format")
+	if format == "" {
+		format = ExportFormatNDJSON
+		accept := c.Get(fiber.HeaderAccept)
+		switch {
+		case strings.Contains(accept, "text/csv"):
+			format = ExportFormatCSV
+		case strings.Contains(accept, parquetMediaType):
+			format = ExportFormatParquet
+		}
+	}
+	if format != ExportFormatNDJSON && format != ExportFormatCSV && format != ExportFormatParquet {
+		return invalidQuery(c, "format must be ndjson, csv or parquet")
+	}
+
+	destination := c.Query("destination", ExportToResponse)
+	switch {
+	case destination != ExportToResponse && destination != ExportToS3:
+		return invalidQuery(c, "destination must be response or s3")
+	case destination == ExportToS3 && h.exportStore == nil:
+		return invalidQuery(c, "exports to s3 are not enabled (EXPORT_S3_BUCKET)")
+	}
+
+	// The status line is sent before the first page is read, so refuse bad
+	// queries now rather than midway through the stream.
+	if err := h.exportUC.Validate(q); err != nil {
+		return invalidQuery(c, err.Error())
+	}
+
+	if destination == ExportToS3 {
+		return h.exportToStore(c, q, format)
+	}
+
+	c.Set(fiber.HeaderContentType, exportContentTypes[format])
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="events.`+format+`"`)
+	c.Status(http.StatusOK)
+
+	ctx := c.UserContext()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		_, _ = h.writeExport(ctx, q, format, w)
+	})
+	return nil
+}
+
+var exportContentTypes = map[string]string{
+	ExportFormatNDJSON:  "application/x-ndjson",
+	ExportFormatCSV:     "text/csv; charset=utf-8",
+	ExportFormatParquet: parquetMediaType,
+}
+
+// exportToStore writes the export to object storage and answers with its
+// location once the object is complete. A failed export leaves no object.
+func (h *EventQueryHandler) exportToStore(c *fiber.Ctx, q ports.EventQuery, format string) error {
+	ctx := c.UserContext()
+	upload := h.exportStore(ctx, exportObjectName("events", format))
+	w := bufio.NewWriter(upload)
+	n, err := h.writeExport(ctx, q, format, w)
+	if err == nil {
+		err = upload.Close()
+	}
+	if err != nil {
+		_ = upload.Abort()
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error:   "export_failed",
+			Message: "export to s3 failed; retry the request",
+		})
+	}
+	return c.Status(http.StatusOK).JSON(ExportObjectResponse{URI: upload.URI(), Format: format, Events: n})
+}
+
+// exportObjectName names an export object by kind and UTC time, with a
+// random suffix so concurrent exports do not overwrite each other.
+func exportObjectName(kind, format string) string {
+	var suffix [4]byte
+	_, _ = rand.Read(suffix[:])
+	return kind + "-" + time.Now().UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(suffix[:]) + "." + format
+}
+
+// writeExport writes every event matching q to w, one chunk per page, and
+// returns how many were written.
+func (h *EventQueryHandler) writeExport(ctx context.Context, q ports.EventQuery, format string, w *bufio.Writer) (int64, error) {
+	enc := newExportEncoder(format, w)
+	n, err := h.exportUC.Execute(ctx, q, func(events []domain.StoredEvent) error {
+		for _, e := range events {
+			if err := enc.write(e); err != nil {
+				return err
+			}
+		}
+		// One chunk per page; a failed flush means the client is gone
+		// and stops the export before the next query.
+		return enc.flush()
+	})
+	if err != nil {
+		enc.fail()
+		_ = enc.flush()
+		return n, err
+	}
+	return n, enc.close()
+}
+
+type exportEncoder interface {
+	write(e domain.StoredEvent) error
+	flush() error
+	// fail marks the stream as incomplete where the format allows it.
+	fail()
+	// close ends a complete stream.
+	close() error
+}
+
+func newExportEncoder(format string, w *bufio.Writer) exportEncoder {
+	switch format {
+	case ExportFormatCSV:
+		return &csvExportEncoder{w: csv.NewWriter(w), buf: w}
+	case ExportFormatParquet:
+		return &parquetExportEncoder{w: parquet.NewWriter(w, exportParquetColumns), buf: w}
+	}
+	return &ndjsonExportEncoder{enc: json.NewEncoder(w), buf: w}
+}
+
+type ndjsonExportEncoder struct {
+	enc *json.Encoder
+	buf *bufio.Writer
+}
+
+func (e *ndjsonExportEncoder) write(ev domain.StoredEvent) error {
+	return e.enc.Encode(toStoredEventResponse(ev))
+}
+
+func (e *ndjsonExportEncoder) flush() error {
+	return e.buf.Flush()
+}
+
+func (e *ndjsonExportEncoder) close() error {
+	return e.flush()
+}
+
+func (e *ndjsonExportEncoder) fail() {
+	_ = e.enc.Encode(ErrorResponse{
+		Error:   "export_failed",
+		Message: "export stopped before the last event; retry the request",
+	})
+}
+
+// exportCSVHeader lists the columns of a CSV export, in order.
+var exportCSVHeader = []string{
+	"id", "event_id", "event_name", "channel", "campaign_id", "user_id", "anonymous_id",
+	"event_time", "received_at", "value", "tags", "metadata", "dedupe_key",
+}
+
+type csvExportEncoder struct {
+	w          *csv.Writer
+	buf        *bufio.Writer
+	headerDone bool
+}
+
+// writeHeader writes the header row once, so an empty export still has one.
+func (e *csvExportEncoder) writeHeader() error {
+	if e.headerDone {
+		return nil
+	}
+	e.headerDone = true
+	return e.w.Write(exportCSVHeader)
+}
+
+func (e *csvExportEncoder) write(ev domain.StoredEvent) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	r := toStoredEventResponse(ev)
+	value := ""
+	if r.Value != nil {
+		value = strconv.FormatFloat(*r.Value, 'f', -1, 64)
+	}
+	tags, err := json.Marshal(r.Tags)
+	if err != nil {
+		return err
+	}
+	metadata := []byte("{}")
+	if r.Metadata != nil {
+		if metadata, err = json.Marshal(r.Metadata); err != nil {
+			return err
+		}
+	}
+
+	return e.w.Write([]string{
+		strconv.FormatInt(r.ID, 10),
+		r.EventID,
+		r.EventName,
+		r.Channel,
+		r.CampaignID,
+		r.UserID,
+		r.AnonymousID,
+		r.EventTime.UTC().Format(time.RFC3339Nano),
+		r.ReceivedAt.UTC().Format(time.RFC3339Nano),
+		value,
+		string(tags),
+		string(metadata),
+		r.DedupeKey,
+	})
+}
+
+func (e *csvExportEncoder) flush() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	if err := e.w.Error(); err != nil {
+		return err
+	}
+	return e.buf.Flush()
+}
+
+func (e *csvExportEncoder) close() error {
+	return e.flush()
+}
+
+// fail is a no-op: CSV has no way to carry an error, so a failed export is
+// only recognizable by ending early.
+func (e *csvExportEncoder) fail() {}
+
+// exportParquetColumns are the CSV columns, typed; empty optional strings
+// are nulls.
+var exportParquetColumns = []parquet.Column{
+	{Name: "id", Type: parquet.Int64},
+	{Name: "event_id", Type: parquet.String, Optional: true},
+	{Name: "event_name", Type: parquet.String},
+	{Name: "channel", Type: parquet.String},
+	{Name: "campaign_id", Type: parquet.String, Optional: true},
+	{Name: "user_id", Type: parquet.String},
+	{Name: "anonymous_id", Type: parquet.String, Optional: true},
+	{Name: "event_time", Type: parquet.Timestamp},
+	{Name: "received_at", Type: parquet.Timestamp},
+	{Name: "value", Type: parquet.Double, Optional: true},
+	{Name: "tags", Type: parquet.JSON},
+	{Name: "metadata", Type: parquet.JSON},
+	{Name: "dedupe_key", Type: parquet.String},
+}
+
+// parquetExportEncoder writes a row group per page.
+type parquetExportEncoder struct {
+	w   *parquet.Writer
+	buf *bufio.Writer
+}
+
+func (e *parquetExportEncoder) write(ev domain.StoredEvent) error {
+	r := toStoredEventResponse(ev)
+	var value any
+	if r.Value != nil {
+		value = *r.Value
+	}
+	tags, err := json.Marshal(r.Tags)
+	if err != nil {
+		return err
+	}
+	metadata := []byte("{}")
+	if r.Metadata != nil {
+		if metadata, err = json.Marshal(r.Metadata); err != nil {
+			return err
+		}
+	}
+
+	return e.w.Write([]any{
+		r.ID,
+		optionalString(r.EventID),
+		r.EventName,
+		r.Channel,
+		optionalString(r.CampaignID),
+		r.UserID,
+		optionalString(r.AnonymousID),
+		r.EventTime,
+		r.ReceivedAt,
+		value,
+		tags,
+		metadata,
+		r.DedupeKey,
+	})
+}
+
+func (e *parquetExportEncoder) flush() error {
+	if err := e.w.Flush(); err != nil {
+		return err
+	}
+	return e.buf.Flush()
+}
+
+func (e *parquetExportEncoder) close() error {
+	if err := e.w.Close(); err != nil {
+		return err
+	}
+	return e.buf.Flush()
+}
+
+// fail is a no-op: the file of a failed export has no footer, which makes
+// readers reject it.
+func (e *parquetExportEncoder) fail() {}
+
+func optionalString(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
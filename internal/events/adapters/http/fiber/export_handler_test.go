@@ -0,0 +1,343 @@
+package fiber
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+	"event-metrics-service/internal/events/core/usecase"
+	"event-metrics-service/internal/parquet"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeExportEventsUseCase struct {
+	ValidateFn func(q ports.EventQuery) error
+	ExecuteFn  func(ctx context.Context, q ports.EventQuery, emit func([]domain.StoredEvent) error) (int64, error)
+}
+
+func (f *fakeExportEventsUseCase) Validate(q ports.EventQuery) error {
+	if f.ValidateFn == nil {
+		return nil
+	}
+	return f.ValidateFn(q)
+}
+
+func (f *fakeExportEventsUseCase) Execute(ctx context.Context, q ports.EventQuery, emit func([]domain.StoredEvent) error) (int64, error) {
+	return f.ExecuteFn(ctx, q, emit)
+}
+
+func setupExportApp(uc ExportEventsUseCase, opts ...QueryHandlerOption) *fiber.App {
+	app := fiber.New()
+	h := NewEventQueryHandler(&fakeListEventsUseCase{}, &fakeGetEventUseCase{}, uc, opts...)
+	app.Get("/events/export", h.ExportEvents)
+	return app
+}
+
+// fakeUpload keeps an object in memory.
+type fakeUpload struct {
+	name      string
+	body      bytes.Buffer
+	closeErr  error
+	completed bool
+	aborted   bool
+}
+
+func (u *fakeUpload) Write(p []byte) (int, error) { return u.body.Write(p) }
+func (u *fakeUpload) Close() error                { u.completed = u.closeErr == nil; return u.closeErr }
+func (u *fakeUpload) Abort() error                { u.aborted = true; return nil }
+func (u *fakeUpload) URI() string                 { return "s3://exports/" + u.name }
+
+func (u *fakeUpload) store(ctx context.Context, name string) ObjectUpload {
+	u.name = name
+	return u
+}
+
+// pagedExport emits pages in order, then returns err.
+func pagedExport(pages [][]domain.StoredEvent, err error) *fakeExportEventsUseCase {
+	return &fakeExportEventsUseCase{
+		ExecuteFn: func(ctx context.Context, q ports.EventQuery, emit func([]domain.StoredEvent) error) (int64, error) {
+			var n int64
+			for _, page := range pages {
+				if err := emit(page); err != nil {
+					return n, err
+				}
+				n += int64(len(page))
+			}
+			return n, err
+		},
+	}
+}
+
+func exportEvent(id int64) domain.StoredEvent {
+	value := 42.5
+	return domain.StoredEvent{
+		Event: domain.Event{
+			EventName: "purchase",
+			Channel:   "web",
+			UserID:    "u1",
+			EventTime: time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC),
+			Value:     &value,
+			Tags:      []string{"promo"},
+			Metadata:  map[string]any{"order_id": "o-1"},
+			DedupeKey: "dk",
+		},
+		ID: id,
+	}
+}
+
+// ------------------------------------------------------------
+
+func TestExportEvents_NDJSON(t *testing.T) {
+	uc := pagedExport([][]domain.StoredEvent{{exportEvent(3), exportEvent(2)}, {exportEvent(1)}}, nil)
+	var got ports.EventQuery
+	uc.ValidateFn = func(q ports.EventQuery) error {
+		got = q
+		return nil
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/events/export?event_name=purchase&tags=promo&from=1733529600&limit=10", nil)
+	resp, err := setupExportApp(uc).Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	if got.EventName != "purchase" || len(got.Tags) != 1 || got.From.Unix() != 1733529600 || got.Limit != 10 {
+		t.Fatalf("filters not passed through: %+v", got)
+	}
+
+	var ids []int64
+	sc := bufio.NewScanner(resp.Body)
+	for sc.Scan() {
+		var e StoredEventResponse
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			t.Fatalf("invalid ndjson line %q: %v", sc.Text(), err)
+		}
+		ids = append(ids, e.ID)
+	}
+	if len(ids) != 3 || ids[0] != 3 || ids[2] != 1 {
+		t.Fatalf("unexpected events: %v", ids)
+	}
+}
+
+func TestExportEvents_CSV(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		path   string
+		accept string
+	}{
+		{"format parameter", "/events/export?format=csv", ""},
+		{"accept header", "/events/export", "text/csv"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			resp, err := setupExportApp(pagedExport([][]domain.StoredEvent{{exportEvent(7)}}, nil)).Test(req)
+			if err != nil {
+				t.Fatalf("app.Test error: %v", err)
+			}
+			if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/csv") {
+				t.Fatalf("unexpected response: %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+			}
+
+			rows, err := csv.NewReader(resp.Body).ReadAll()
+			if err != nil {
+				t.Fatalf("invalid csv: %v", err)
+			}
+			if len(rows) != 2 || strings.Join(rows[0], ",") != strings.Join(exportCSVHeader, ",") {
+				t.Fatalf("unexpected rows: %v", rows)
+			}
+			row := rows[1]
+			if row[0] != "7" || row[2] != "purchase" || row[7] != "2025-12-07T10:00:00Z" || row[9] != "42.5" ||
+				row[10] != `["promo"]` || row[11] != `{"order_id":"o-1"}` {
+				t.Fatalf("unexpected row: %v", row)
+			}
+		})
+	}
+}
+
+func TestExportEvents_EmptyCSVHasHeader(t *testing.T) {
+	resp, err := setupExportApp(pagedExport(nil, nil)).Test(httptest.NewRequest(http.MethodGet, "/events/export?format=csv", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if strings.TrimSpace(string(body)) != strings.Join(exportCSVHeader, ",") {
+		t.Fatalf("expected only the header, got %q", body)
+	}
+}
+
+func TestExportEvents_Parquet(t *testing.T) {
+	uc := pagedExport([][]domain.StoredEvent{{exportEvent(3), exportEvent(2)}, {exportEvent(1)}}, nil)
+	req := httptest.NewRequest(http.MethodGet, "/events/export", nil)
+	req.Header.Set("Accept", "application/vnd.apache.parquet")
+	resp, err := setupExportApp(uc).Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/vnd.apache.parquet" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	if cd := resp.Header.Get("Content-Disposition"); cd != `attachment; filename="events.parquet"` {
+		t.Fatalf("unexpected content disposition %q", cd)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	columns, rows, err := parquet.Read(body)
+	if err != nil {
+		t.Fatalf("invalid parquet file: %v", err)
+	}
+	if len(columns) != len(exportCSVHeader) || columns[7].Name != "event_time" || columns[7].Type != parquet.Timestamp {
+		t.Fatalf("unexpected columns: %+v", columns)
+	}
+	if len(rows) != 3 || rows[0][0] != int64(3) || rows[2][0] != int64(1) {
+		t.Fatalf("unexpected rows: %v", rows)
+	}
+	if rows[0][1] != nil || rows[0][9] != 42.5 || string(rows[0][11].([]byte)) != `{"order_id":"o-1"}` {
+		t.Fatalf("unexpected row: %v", rows[0])
+	}
+}
+
+func TestExportEvents_FailedParquetHasNoFooter(t *testing.T) {
+	uc := pagedExport([][]domain.StoredEvent{{exportEvent(1)}}, errors.New("db failure"))
+	resp, err := setupExportApp(uc).Test(httptest.NewRequest(http.MethodGet, "/events/export?format=parquet", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if _, _, err := parquet.Read(body); err == nil {
+		t.Fatalf("expected a failed export to be unreadable")
+	}
+}
+
+func TestExportEvents_ToS3(t *testing.T) {
+	upload := &fakeUpload{}
+	uc := pagedExport([][]domain.StoredEvent{{exportEvent(2), exportEvent(1)}}, nil)
+	resp, err := setupExportApp(uc, WithExportStore(upload.store)).
+		Test(httptest.NewRequest(http.MethodGet, "/events/export?format=csv&destination=s3", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var out ExportObjectResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("invalid body: %v", err)
+	}
+	if out.URI != "s3://exports/"+upload.name || out.Format != "csv" || out.Events != 2 {
+		t.Fatalf("unexpected response: %+v", out)
+	}
+	if !strings.HasPrefix(upload.name, "events-") || !strings.HasSuffix(upload.name, ".csv") {
+		t.Fatalf("unexpected object name %q", upload.name)
+	}
+	rows, err := csv.NewReader(&upload.body).ReadAll()
+	if err != nil || len(rows) != 3 || !upload.completed {
+		t.Fatalf("expected a complete CSV with 2 events, got %v (%v)", rows, err)
+	}
+}
+
+func TestExportEvents_ToS3FailureAborts(t *testing.T) {
+	for name, tt := range map[string]struct {
+		exportErr error
+		closeErr  error
+	}{
+		"export fails": {exportErr: errors.New("db failure")},
+		"upload fails": {closeErr: errors.New("s3 unavailable")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			upload := &fakeUpload{closeErr: tt.closeErr}
+			uc := pagedExport([][]domain.StoredEvent{{exportEvent(1)}}, tt.exportErr)
+			resp, err := setupExportApp(uc, WithExportStore(upload.store)).
+				Test(httptest.NewRequest(http.MethodGet, "/events/export?destination=s3", nil))
+			if err != nil {
+				t.Fatalf("app.Test error: %v", err)
+			}
+			if resp.StatusCode != http.StatusInternalServerError {
+				t.Fatalf("expected 500, got %d", resp.StatusCode)
+			}
+			var body ErrorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Error != "export_failed" {
+				t.Fatalf("unexpected body: %+v (%v)", body, err)
+			}
+			if !upload.aborted || upload.completed {
+				t.Fatalf("expected the upload to be aborted")
+			}
+		})
+	}
+}
+
+func TestExportEvents_FailureEndsNDJSONWithError(t *testing.T) {
+	uc := pagedExport([][]domain.StoredEvent{{exportEvent(1)}}, errors.New("db failure"))
+
+	resp, err := setupExportApp(uc).Test(httptest.NewRequest(http.MethodGet, "/events/export", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected an event and an error line, got %q", body)
+	}
+	var last ErrorResponse
+	if err := json.Unmarshal([]byte(lines[1]), &last); err != nil || last.Error != "export_failed" {
+		t.Fatalf("unexpected last line %q", lines[1])
+	}
+}
+
+func TestExportEvents_BadRequests(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		err   error
+	}{
+		{"bad format", "format=xml", nil},
+		{"bad destination", "destination=gcs", nil},
+		{"s3 not enabled", "destination=s3", nil},
+		{"bad limit", "limit=0", nil},
+		{"bad from", "from=yesterday", nil},
+		{"rejected by use case", "", usecase.ErrInvalidEventQuery},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &fakeExportEventsUseCase{
+				ValidateFn: func(q ports.EventQuery) error { return tt.err },
+				ExecuteFn: func(ctx context.Context, q ports.EventQuery, emit func([]domain.StoredEvent) error) (int64, error) {
+					t.Fatal("export must not start for a bad request")
+					return 0, nil
+				},
+			}
+			resp, err := setupExportApp(uc).Test(httptest.NewRequest(http.MethodGet, "/events/export?"+tt.query, nil))
+			if err != nil {
+				t.Fatalf("app.Test error: %v", err)
+			}
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d", resp.StatusCode)
+			}
+			var body ErrorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Error != "invalid_query" {
+				t.Fatalf("unexpected body: %+v (%v)", body, err)
+			}
+		})
+	}
+}
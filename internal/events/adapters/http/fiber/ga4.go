@@ -0,0 +1,207 @@
+package fiber
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"event-metrics-service/internal/events/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MaxGA4EventsPerRequest is the Measurement Protocol's own limit.
+const MaxGA4EventsPerRequest = 25
+
+const (
+	defaultGA4WebChannel = "web"
+	defaultGA4AppChannel = "app"
+)
+
+// WithGA4Channels sets the channel of GA4 hits per measurement_id or
+// firebase_app_id. Other web streams are stored as "web", app streams as
+// "app".
+func WithGA4Channels(channels map[string]string) HandlerOption {
+	return func(h *EventHandler) {
+		h.ga4Channels = channels
+	}
+}
+
+// CollectGA4 godoc
+// @Summary Collect GA4 Measurement Protocol hits
+// @Description Drop-in replacement for https://www.google-analytics.com/mp/collect. Each hit becomes an event:
+// @Description name is the event name, user_id the user, client_id (or app_instance_id) the anonymous id and
+// @Description params the metadata; params.value and params.campaign_id fill value and campaign_id. Like
+// @Description GA4, invalid hits are dropped without failing the request. The API key may be passed as api_secret.
+// @Tags Events
+// @Accept json
+// @Param measurement_id query string false "Web stream id; one of measurement_id and firebase_app_id is required"
+// @Param firebase_app_id query string false "App stream id"
+// @Param api_secret query string false "API key, when X-API-Key cannot be sent"
+// @Param request body GA4CollectRequest true "Measurement Protocol payload"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse "missing or unknown API key (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "missing scope events:write"
+// @Failure 429 {object} ErrorResponse "rate_limited: request rate limit exceeded for this API key"
+// @Failure 500 {object} ErrorResponse
+// @Router /mp/collect [post]
+func (h *EventHandler) CollectGA4(c *fiber.Ctx) error {
+	channel, ok := h.ga4Channel(c.Query("measurement_id"), c.Query("firebase_app_id"))
+	if !ok {
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error:   "invalid_request",
+			Message: "measurement_id or firebase_app_id is required",
+		})
+	}
+
+	var req GA4CollectRequest
+	if err := h.parseBody(c, &req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid_json",
+		})
+	}
+	if len(req.Events) == 0 {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "events_list_required",
+		})
+	}
+	if len(req.Events) > MaxGA4EventsPerRequest {
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error:   "too_many_events",
+			Message: "at most " + strconv.Itoa(MaxGA4EventsPerRequest) + " events per request",
+		})
+	}
+
+	received := time.Now()
+	client := clientInfo(c)
+	inputs := make([]usecase.StoreEventInput, len(req.Events))
+	for i, e := range req.Events {
+		in := ga4Input(req, e, received)
+		in.Channel = channel
+		in.Client = client
+		inputs[i] = in
+	}
+
+	// Invalid hits are reported per item and dropped, as GA4 does.
+	res, err := h.storeUC.BulkCreateEvents(c.UserContext(), usecase.BulkCreateEventsInput{Events: inputs})
+	if err != nil || res.Failed > 0 {
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error: "internal_server_error",
+		})
+	}
+	return c.SendStatus(http.StatusNoContent)
+}
+
+func (h *EventHandler) ga4Channel(measurementID, firebaseAppID string) (string, bool) {
+	switch {
+	case measurementID != "":
+		if ch, ok := h.ga4Channels[measurementID]; ok {
+			return ch, true
+		}
+		return defaultGA4WebChannel, true
+	case firebaseAppID != "":
+		if ch, ok := h.ga4Channels[firebaseAppID]; ok {
+			return ch, true
+		}
+		return defaultGA4AppChannel, true
+	}
+	return "", false
+}
+
+// ga4Input translates one hit. The event's own timestamp wins over the
+// request's; without either the hit happened when it was received.
+func ga4Input(req GA4CollectRequest, e GA4Event, received time.Time) usecase.StoreEventInput {
+	ts := received.UnixMilli()
+	switch {
+	case e.TimestampMicro > 0:
+		ts = int64(e.TimestampMicro) / 1000
+	case req.TimestampMicro > 0:
+		ts = int64(req.TimestampMicro) / 1000
+	}
+
+	anonymousID := req.ClientID
+	if anonymousID == "" {
+		anonymousID = req.AppInstanceID
+	}
+
+	in := usecase.StoreEventInput{
+		EventName:   e.Name,
+		UserID:      req.UserID,
+		AnonymousID: anonymousID,
+		TimestampMs: ts,
+	}
+
+	metadata := make(map[string]any, len(e.Params)+1)
+	for k, v := range e.Params {
+		switch k {
+		case "value":
+			if f, ok := ga4Number(v); ok {
+				in.Value = &f
+				continue
+			}
+		case "campaign_id":
+			if s, ok := ga4String(v); ok {
+				in.CampaignID = s
+				continue
+			}
+		}
+		metadata[k] = v
+	}
+	if len(req.UserProperties) > 0 {
+		props := make(map[string]any, len(req.UserProperties))
+		for k, p := range req.UserProperties {
+			props[k] = p.Value
+		}
+		metadata["user_properties"] = props
+	}
+	if len(metadata) > 0 {
+		in.Metadata = metadata
+	}
+	return in
+}
+
+func ga4Number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func ga4String(v any) (string, bool) {
+	switch s := v.(type) {
+	case string:
+		return s, true
+	case json.Number:
+		return s.String(), true
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64), true
+	}
+	return "", false
+}
+
+// ga4Micros is a unix timestamp in microseconds. Clients send it as a
+// number or as a string.
+type ga4Micros int64
+
+func (m *ga4Micros) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" {
+		return nil
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*m = ga4Micros(n)
+	return nil
+}
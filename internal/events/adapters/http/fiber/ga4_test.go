@@ -0,0 +1,151 @@
+package fiber
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"event-metrics-service/internal/events/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func setupGA4App(uc StoreEventUseCase, opts ...HandlerOption) *fiber.App {
+	app := fiber.New()
+	h := NewEventHandler(uc, opts...)
+	app.Post("/mp/collect", h.CollectGA4)
+	return app
+}
+
+func TestCollectGA4_TranslatesHits(t *testing.T) {
+	uc := &fakeStoreEventUseCase{}
+	app := setupGA4App(uc, WithGA4Channels(map[string]string{"G-SHOP": "shop"}))
+
+	resp, body := doRequest(t, app, http.MethodPost, "/mp/collect?measurement_id=G-SHOP&api_secret=key", map[string]any{
+		"client_id":        "123.456",
+		"user_id":          "u1",
+		"timestamp_micros": "1733572800123456",
+		"user_properties":  map[string]any{"plan": map[string]any{"value": "pro"}},
+		"events": []map[string]any{
+			{"name": "purchase", "params": map[string]any{"value": 12.5, "currency": "EUR", "campaign_id": 42}},
+			{"name": "page_view", "timestamp_micros": 1733572801000000},
+		},
+	})
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", resp.StatusCode, body)
+	}
+
+	events := uc.LastBulkCreateInput.Events
+	if len(events) != 2 || uc.LastBulkCreateInput.Atomic {
+		t.Fatalf("unexpected bulk input: %+v", uc.LastBulkCreateInput)
+	}
+	purchase := events[0]
+	if purchase.EventName != "purchase" || purchase.Channel != "shop" || purchase.UserID != "u1" || purchase.AnonymousID != "123.456" {
+		t.Fatalf("unexpected purchase: %+v", purchase)
+	}
+	if purchase.TimestampMs != 1733572800123 {
+		t.Fatalf("expected request timestamp, got %d", purchase.TimestampMs)
+	}
+	if purchase.Value == nil || *purchase.Value != 12.5 || purchase.CampaignID != "42" {
+		t.Fatalf("expected value and campaign_id from params, got %+v", purchase)
+	}
+	if purchase.Metadata["currency"] != "EUR" {
+		t.Fatalf("expected other params as metadata, got %v", purchase.Metadata)
+	}
+	if _, ok := purchase.Metadata["value"]; ok {
+		t.Fatalf("expected value not to be kept as metadata")
+	}
+	props, _ := purchase.Metadata["user_properties"].(map[string]any)
+	if props["plan"] != "pro" {
+		t.Fatalf("expected user properties in metadata, got %v", purchase.Metadata)
+	}
+
+	if events[1].TimestampMs != 1733572801000 {
+		t.Fatalf("expected the event's own timestamp, got %d", events[1].TimestampMs)
+	}
+}
+
+func TestCollectGA4_AppStream(t *testing.T) {
+	uc := &fakeStoreEventUseCase{}
+	app := setupGA4App(uc)
+
+	resp, _ := doRequest(t, app, http.MethodPost, "/mp/collect?firebase_app_id=1:123:android:abc", map[string]any{
+		"app_instance_id": "inst-1",
+		"events":          []map[string]any{{"name": "screen_view"}},
+	})
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", resp.StatusCode)
+	}
+	e := uc.LastBulkCreateInput.Events[0]
+	if e.Channel != "app" || e.AnonymousID != "inst-1" || e.TimestampMs == 0 || e.Metadata != nil {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+}
+
+func TestCollectGA4_DropsInvalidHits(t *testing.T) {
+	uc := &fakeStoreEventUseCase{
+		BulkCreateFunc: func(ctx context.Context, in usecase.BulkCreateEventsInput) (usecase.BulkCreateEventsResult, error) {
+			return usecase.BulkCreateEventsResult{Invalid: 1, Items: []usecase.BulkItemResult{{Status: usecase.ItemStatusInvalid}}}, nil
+		},
+	}
+	app := setupGA4App(uc)
+
+	resp, _ := doRequest(t, app, http.MethodPost, "/mp/collect?measurement_id=G-1", map[string]any{
+		"events": []map[string]any{{"name": "purchase"}},
+	})
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", resp.StatusCode)
+	}
+}
+
+func TestCollectGA4_BadRequests(t *testing.T) {
+	tooMany := make([]map[string]any, MaxGA4EventsPerRequest+1)
+	for i := range tooMany {
+		tooMany[i] = map[string]any{"name": "page_view"}
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		body      any
+		wantError string
+	}{
+		{"no stream", "/mp/collect", map[string]any{"events": []map[string]any{{"name": "x"}}}, "invalid_request"},
+		{"no events", "/mp/collect?measurement_id=G-1", map[string]any{"client_id": "1"}, "events_list_required"},
+		{"too many events", "/mp/collect?measurement_id=G-1", map[string]any{"events": tooMany}, "too_many_events"},
+		{"bad timestamp", "/mp/collect?measurement_id=G-1", map[string]any{"timestamp_micros": "soon", "events": []map[string]any{{"name": "x"}}}, "invalid_json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := setupGA4App(&fakeStoreEventUseCase{})
+			resp, body := doRequest(t, app, http.MethodPost, tt.path, tt.body)
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d", resp.StatusCode)
+			}
+			var got ErrorResponse
+			if err := json.Unmarshal(body, &got); err != nil || got.Error != tt.wantError {
+				t.Fatalf("expected %s, got %s", tt.wantError, body)
+			}
+		})
+	}
+}
+
+func TestCollectGA4_StoreFailure(t *testing.T) {
+	uc := &fakeStoreEventUseCase{
+		BulkCreateFunc: func(ctx context.Context, in usecase.BulkCreateEventsInput) (usecase.BulkCreateEventsResult, error) {
+			return usecase.BulkCreateEventsResult{}, errors.New("db down")
+		},
+	}
+	app := setupGA4App(uc)
+
+	resp, _ := doRequest(t, app, http.MethodPost, "/mp/collect?measurement_id=G-1", map[string]any{
+		"client_id": "1",
+		"events":    []map[string]any{{"name": "purchase"}},
+	})
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", resp.StatusCode)
+	}
+}
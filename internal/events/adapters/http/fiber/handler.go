@@ -1,67 +1,155 @@
 package fiber
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 
+	"event-metrics-service/internal/events/core/domain"
 	"event-metrics-service/internal/events/core/usecase"
+	"event-metrics-service/internal/jsonlimit"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type StoreEventUseCase interface {
-	Execute(ctx context.Context, in usecase.StoreEventInput) (bool, error)
+	Execute(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error)
 	BulkCreateEvents(ctx context.Context, in usecase.BulkCreateEventsInput) (usecase.BulkCreateEventsResult, error)
 }
 
 type EventHandler struct {
-	storeUC StoreEventUseCase
+	storeUC          StoreEventUseCase
+	preserveNumbers  bool
+	maxBulkEvents    int
+	ga4Channels      map[string]string
+	snowplowChannels map[string]string
+	wsGate           WebSocketGate
+	wsReadLimit      int
+	wsJSONLimits     jsonlimit.Limits
 }
 
-func NewEventHandler(storeUC StoreEventUseCase) *EventHandler {
-	return &EventHandler{storeUC: storeUC}
+type HandlerOption func(*EventHandler)
+
+// WithPreserveNumbers controls how numbers inside request bodies (notably
+// metadata values) are decoded. When enabled (the default) they are kept as
+// json.Number so large integer IDs round-trip to storage without float64
+// rounding; when disabled they are decoded as float64.
+func WithPreserveNumbers(preserve bool) HandlerOption {
+	return func(h *EventHandler) {
+		h.preserveNumbers = preserve
+	}
+}
+
+// WithMaxBulkEvents caps the events of one bulk request (0 = unlimited);
+// larger batches are rejected with 413 before anything is stored.
+func WithMaxBulkEvents(n int) HandlerOption {
+	return func(h *EventHandler) {
+		h.maxBulkEvents = n
+	}
+}
+
+func NewEventHandler(storeUC StoreEventUseCase, opts ...HandlerOption) *EventHandler {
+	h := &EventHandler{storeUC: storeUC, preserveNumbers: true}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *EventHandler) parseBody(c *fiber.Ctx, out any) error {
+	if !h.preserveNumbers {
+		return c.BodyParser(out)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(c.Body()))
+	dec.UseNumber()
+	return dec.Decode(out)
 }
 
 // CreateEvent godoc
 // @Summary Create a new event
-// @Description Stores a single event with idempotency handling
+// @Description Stores a single event with idempotency handling.
+// @Description When event_id (UUID) is supplied it is used as the idempotency key and echoed back; otherwise a UUIDv7 is generated and returned.
 // @Tags Events
 // @Accept json
 // @Produce json
 // @Param request body CreateEventRequest true "Event payload"
 // @Success 201 {object} CreateEventResponse
 // @Success 200 {object} CreateEventResponse "Duplicate event"
+// @Success 202 {object} CreateEventResponse "Insert failed; kept in the dead-letter queue and stored on re-drive (status accepted), or dropped by sampling (status sampled)"
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse "missing or unknown API key (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "missing scope events:write"
+// @Failure 413 {object} ErrorResponse "metadata_too_large; body_too_large: body over MAX_BODY_BYTES; json_too_complex: body over JSON_MAX_DEPTH or JSON_MAX_VALUES"
+// @Failure 429 {object} ErrorResponse "rate_limited: request rate limit exceeded for this API key"
 // @Failure 500 {object} ErrorResponse
 // @Router /events [post]
 func (h *EventHandler) CreateEvent(c *fiber.Ctx) error {
 	var req CreateEventRequest
 
-	if err := c.BodyParser(&req); err != nil {
+	if err := h.parseBody(c, &req); err != nil {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
 			"error": "invalid_json",
 		})
 	}
 
 	input := usecase.StoreEventInput{
-		EventName:  req.EventName,
-		Channel:    req.Channel,
-		CampaignID: req.CampaignID,
-		UserID:     req.UserID,
-		Timestamp:  req.Timestamp,
-		Tags:       req.Tags,
-		Metadata:   req.Metadata,
+		EventID:     req.EventID,
+		EventName:   req.EventName,
+		Channel:     req.Channel,
+		CampaignID:  req.CampaignID,
+		UserID:      req.UserID,
+		AnonymousID: req.AnonymousID,
+		Timestamp:   req.Timestamp,
+		TimestampMs: req.TimestampMs,
+		Value:       req.Value,
+		Tags:        req.Tags,
+		Metadata:    req.Metadata,
+		Client:      clientInfo(c),
 	}
 
-	created, err := h.storeUC.Execute(c.UserContext(), input)
+	res, err := h.storeUC.Execute(c.UserContext(), input)
 	if err != nil {
 		switch {
+		case errors.Is(err, usecase.ErrEventDeadLettered):
+			return c.Status(http.StatusAccepted).JSON(CreateEventResponse{
+				Status:  "accepted",
+				EventID: res.EventID,
+				Message: "event could not be stored yet and will be retried",
+			})
+		case errors.Is(err, usecase.ErrEventSampledOut):
+			return c.Status(http.StatusAccepted).JSON(CreateEventResponse{
+				Status:  "sampled",
+				EventID: req.EventID,
+				Message: "event dropped by sampling",
+			})
+		case errors.Is(err, usecase.ErrInvalidTags):
+			return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+				Error:   "invalid_tags",
+				Message: err.Error(),
+				Details: errorDetails(err),
+			})
+		case errors.Is(err, usecase.ErrSchemaViolation):
+			return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+				Error:   "schema_violation",
+				Message: err.Error(),
+				Details: errorDetails(err),
+			})
 		case errors.Is(err, usecase.ErrInvalidEvent),
-			errors.Is(err, usecase.ErrFutureTime):
+			errors.Is(err, usecase.ErrFutureTime),
+			errors.Is(err, usecase.ErrEventTooOld):
 			return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
 				Error:   "invalid_event",
 				Message: err.Error(),
+				Details: errorDetails(err),
+			})
+		case errors.Is(err, usecase.ErrMetadataTooLarge):
+			return c.Status(http.StatusRequestEntityTooLarge).JSON(ErrorResponse{
+				Error:   "metadata_too_large",
+				Message: err.Error(),
 			})
 		default:
 			return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
@@ -70,33 +158,41 @@ func (h *EventHandler) CreateEvent(c *fiber.Ctx) error {
 		}
 	}
 
-	if !created {
+	if !res.Created {
 		resp := CreateEventResponse{
-			Status: "duplicate",
+			Status:  "duplicate",
+			EventID: res.EventID,
 		}
 		return c.Status(http.StatusOK).JSON(resp)
 	}
 
 	resp := CreateEventResponse{
-		Status: "created",
+		Status:  "created",
+		EventID: res.EventID,
 	}
 	return c.Status(http.StatusCreated).JSON(resp)
 }
 
 // BulkCreateEvents godoc
 // @Summary Bulk create events
-// @Description Accepts a list of events and stores them individually
+// @Description Accepts a list of events and stores them individually.
+// @Description Invalid events do not abort the batch; every event gets an indexed status in "items".
 // @Tags Events
 // @Accept json
 // @Produce json
 // @Param request body BulkCreateEventsRequest true "Bulk event payload"
-// @Success 201 {object} map[string]int
+// @Param atomic query bool false "All-or-nothing: reject the batch on any invalid event and insert in a single transaction"
+// @Success 201 {object} BulkCreateEventsResponse
 // @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse "missing or unknown API key (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "missing scope events:write"
+// @Failure 413 {object} ErrorResponse "batch_too_large: more events than BULK_MAX_EVENTS; body_too_large: body over MAX_BODY_BYTES; json_too_complex: body over JSON_MAX_DEPTH or JSON_MAX_VALUES; metadata_too_large (atomic batches)"
+// @Failure 429 {object} ErrorResponse "rate_limited: request rate limit exceeded for this API key"
 // @Failure 500 {object} ErrorResponse
 // @Router /events/bulk [post]
 func (h *EventHandler) BulkCreateEvents(c *fiber.Ctx) error {
 	var req BulkCreateEventsRequest
-	if err := c.BodyParser(&req); err != nil {
+	if err := h.parseBody(c, &req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "invalid_json",
 		})
@@ -107,31 +203,62 @@ func (h *EventHandler) BulkCreateEvents(c *fiber.Ctx) error {
 			"error": "events_list_required",
 		})
 	}
+	if h.tooManyEvents(len(req.Events)) {
+		return h.batchTooLarge(c)
+	}
 
+	client := clientInfo(c)
 	inputs := make([]usecase.StoreEventInput, len(req.Events))
 	for i, e := range req.Events {
 		inputs[i] = usecase.StoreEventInput{
-			EventName:  e.EventName,
-			Channel:    e.Channel,
-			CampaignID: e.CampaignID,
-			UserID:     e.UserID,
-			Timestamp:  e.Timestamp,
-			Tags:       e.Tags,
-			Metadata:   e.Metadata,
+			EventID:     e.EventID,
+			EventName:   e.EventName,
+			Channel:     e.Channel,
+			CampaignID:  e.CampaignID,
+			UserID:      e.UserID,
+			AnonymousID: e.AnonymousID,
+			Timestamp:   e.Timestamp,
+			TimestampMs: e.TimestampMs,
+			Value:       e.Value,
+			Tags:        e.Tags,
+			Metadata:    e.Metadata,
+			Client:      client,
 		}
 	}
 
 	result, err := h.storeUC.BulkCreateEvents(
 		c.UserContext(),
-		usecase.BulkCreateEventsInput{Events: inputs},
+		usecase.BulkCreateEventsInput{
+			Events: inputs,
+			Atomic: c.QueryBool("atomic", false),
+		},
 	)
 	if err != nil {
 		switch {
+		case errors.Is(err, usecase.ErrInvalidTags):
+			return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+				Error:   "invalid_tags",
+				Message: err.Error(),
+				Details: errorDetails(err),
+			})
+		case errors.Is(err, usecase.ErrSchemaViolation):
+			return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+				Error:   "schema_violation",
+				Message: err.Error(),
+				Details: errorDetails(err),
+			})
 		case errors.Is(err, usecase.ErrInvalidEvent),
-			errors.Is(err, usecase.ErrFutureTime):
+			errors.Is(err, usecase.ErrFutureTime),
+			errors.Is(err, usecase.ErrEventTooOld):
 			return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
 				Error:   "invalid_event",
 				Message: err.Error(),
+				Details: errorDetails(err),
+			})
+		case errors.Is(err, usecase.ErrMetadataTooLarge):
+			return c.Status(http.StatusRequestEntityTooLarge).JSON(ErrorResponse{
+				Error:   "metadata_too_large",
+				Message: err.Error(),
 			})
 		default:
 			return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
@@ -140,8 +267,57 @@ func (h *EventHandler) BulkCreateEvents(c *fiber.Ctx) error {
 		}
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"created":    result.Created,
-		"duplicates": result.Duplicates,
+	resp := BulkCreateEventsResponse{
+		Created:    result.Created,
+		Duplicates: result.Duplicates,
+		Invalid:    result.Invalid,
+		Accepted:   result.Accepted,
+		Sampled:    result.Sampled,
+		Failed:     result.Failed,
+		Items:      make([]BulkItemResponse, 0, len(result.Items)),
+	}
+	for _, item := range result.Items {
+		resp.Items = append(resp.Items, BulkItemResponse{
+			Index:   item.Index,
+			EventID: item.EventID,
+			Status:  item.Status,
+			Reason:  item.Reason,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+func (h *EventHandler) tooManyEvents(n int) bool {
+	return h.maxBulkEvents > 0 && n > h.maxBulkEvents
+}
+
+func (h *EventHandler) batchTooLarge(c *fiber.Ctx) error {
+	return c.Status(http.StatusRequestEntityTooLarge).JSON(ErrorResponse{
+		Error:   "batch_too_large",
+		Message: fmt.Sprintf("at most %d events per request", h.maxBulkEvents),
 	})
 }
+
+// clientInfo describes the caller for enrichers. c.IP honours the app's
+// ProxyHeader setting when the service runs behind a load balancer.
+func clientInfo(c *fiber.Ctx) domain.ClientInfo {
+	return domain.ClientInfo{
+		IP:        c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	}
+}
+
+// errorDetails extracts field level violations from validation errors.
+func errorDetails(err error) []ErrorDetail {
+	var verr *usecase.ValidationError
+	if !errors.As(err, &verr) {
+		return nil
+	}
+
+	details := make([]ErrorDetail, 0, len(verr.Violations))
+	for _, v := range verr.Violations {
+		details = append(details, ErrorDetail{Field: v.Field, Message: v.Message})
+	}
+	return details
+}
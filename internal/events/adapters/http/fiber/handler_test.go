@@ -5,9 +5,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,18 +19,18 @@ import (
 )
 
 type fakeStoreEventUseCase struct {
-	ExecuteFunc         func(ctx context.Context, in usecase.StoreEventInput) (bool, error)
+	ExecuteFunc         func(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error)
 	BulkCreateFunc      func(ctx context.Context, in usecase.BulkCreateEventsInput) (usecase.BulkCreateEventsResult, error)
 	LastExecuteInput    usecase.StoreEventInput
 	LastBulkCreateInput usecase.BulkCreateEventsInput
 }
 
-func (f *fakeStoreEventUseCase) Execute(ctx context.Context, in usecase.StoreEventInput) (bool, error) {
+func (f *fakeStoreEventUseCase) Execute(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error) {
 	f.LastExecuteInput = in
 	if f.ExecuteFunc != nil {
 		return f.ExecuteFunc(ctx, in)
 	}
-	return false, nil
+	return usecase.StoreEventResult{}, nil
 }
 
 func (f *fakeStoreEventUseCase) BulkCreateEvents(ctx context.Context, in usecase.BulkCreateEventsInput) (usecase.BulkCreateEventsResult, error) {
@@ -84,9 +86,9 @@ func TestCreateEvent_Success_Created(t *testing.T) {
 	now := time.Now().Add(-time.Minute).Unix()
 
 	fakeUC := &fakeStoreEventUseCase{
-		ExecuteFunc: func(ctx context.Context, in usecase.StoreEventInput) (bool, error) {
+		ExecuteFunc: func(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error) {
 			// created = true
-			return true, nil
+			return usecase.StoreEventResult{Created: true}, nil
 		},
 	}
 
@@ -118,13 +120,41 @@ func TestCreateEvent_Success_Created(t *testing.T) {
 	}
 }
 
+func TestCreateEvent_PassesClientInfo(t *testing.T) {
+	var got usecase.StoreEventInput
+	fakeUC := &fakeStoreEventUseCase{
+		ExecuteFunc: func(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error) {
+			got = in
+			return usecase.StoreEventResult{Created: true}, nil
+		},
+	}
+
+	app := setupTestApp(fakeUC)
+
+	body := `{"event_name":"product_view","channel":"web","user_id":"user_123","timestamp":1700000000}`
+	req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) Firefox/120.0")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, resp.StatusCode)
+	}
+	if got.Client.UserAgent != "Mozilla/5.0 (X11; Linux x86_64) Firefox/120.0" || got.Client.IP == "" {
+		t.Fatalf("unexpected client info: %+v", got.Client)
+	}
+}
+
 func TestCreateEvent_Success_Duplicate(t *testing.T) {
 	now := time.Now().Add(-time.Minute).Unix()
 
 	fakeUC := &fakeStoreEventUseCase{
-		ExecuteFunc: func(ctx context.Context, in usecase.StoreEventInput) (bool, error) {
+		ExecuteFunc: func(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error) {
 			// created = false → duplicate
-			return false, nil
+			return usecase.StoreEventResult{}, nil
 		},
 	}
 
@@ -153,6 +183,155 @@ func TestCreateEvent_Success_Duplicate(t *testing.T) {
 	}
 }
 
+func TestCreateEvent_PreservesLargeMetadataNumbers(t *testing.T) {
+	fakeUC := &fakeStoreEventUseCase{
+		ExecuteFunc: func(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error) {
+			return usecase.StoreEventResult{Created: true}, nil
+		},
+	}
+	app := setupTestApp(fakeUC)
+
+	// 2^53 + 1 cannot be represented exactly as float64.
+	raw := `{"event_name":"order","channel":"web","user_id":"u1","timestamp":1700000000,` +
+		`"metadata":{"order_id":9007199254740993,"amount":12.50}}`
+
+	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewBufferString(raw))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, resp.StatusCode)
+	}
+
+	got, ok := fakeUC.LastExecuteInput.Metadata["order_id"].(json.Number)
+	if !ok {
+		t.Fatalf("expected json.Number, got %T", fakeUC.LastExecuteInput.Metadata["order_id"])
+	}
+	if got.String() != "9007199254740993" {
+		t.Errorf("expected order_id=9007199254740993, got %s", got)
+	}
+	if amount := fakeUC.LastExecuteInput.Metadata["amount"].(json.Number); amount.String() != "12.50" {
+		t.Errorf("expected amount=12.50, got %s", amount)
+	}
+}
+
+func TestCreateEvent_FloatNumbersWhenPreserveDisabled(t *testing.T) {
+	fakeUC := &fakeStoreEventUseCase{}
+	app := fiber.New()
+	h := NewEventHandler(fakeUC, WithPreserveNumbers(false))
+	app.Post("/events", h.CreateEvent)
+
+	raw := `{"event_name":"order","channel":"web","user_id":"u1","timestamp":1700000000,"metadata":{"n":1}}`
+
+	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewBufferString(raw))
+	req.Header.Set("Content-Type", "application/json")
+
+	if _, err := app.Test(req, -1); err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+
+	if _, ok := fakeUC.LastExecuteInput.Metadata["n"].(float64); !ok {
+		t.Fatalf("expected float64, got %T", fakeUC.LastExecuteInput.Metadata["n"])
+	}
+}
+
+func TestCreateEvent_EchoesEventID(t *testing.T) {
+	fakeUC := &fakeStoreEventUseCase{
+		ExecuteFunc: func(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error) {
+			return usecase.StoreEventResult{Created: true, EventID: in.EventID}, nil
+		},
+	}
+	app := setupTestApp(fakeUC)
+
+	const id = "0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42"
+	reqBody := CreateEventRequest{
+		EventID:   id,
+		EventName: "product_view",
+		Channel:   "web",
+		UserID:    "user_123",
+		Timestamp: time.Now().Add(-time.Minute).Unix(),
+	}
+
+	resp, body := doRequest(t, app, http.MethodPost, "/events", reqBody)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d (body: %s)", http.StatusCreated, resp.StatusCode, string(body))
+	}
+
+	if fakeUC.LastExecuteInput.EventID != id {
+		t.Errorf("expected event_id to be passed to usecase, got %q", fakeUC.LastExecuteInput.EventID)
+	}
+
+	var respJSON CreateEventResponse
+	if err := json.Unmarshal(body, &respJSON); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if respJSON.EventID != id {
+		t.Errorf("expected event_id=%s in response, got %s", id, respJSON.EventID)
+	}
+}
+
+func TestCreateEvent_ReturnsStoredEventID(t *testing.T) {
+	const generated = "019a0b4c-5d6e-7f80-9a1b-2c3d4e5f6a7b"
+	tests := []struct {
+		name       string
+		result     usecase.StoreEventResult
+		wantStatus int
+	}{
+		{"created", usecase.StoreEventResult{Created: true, EventID: generated}, http.StatusCreated},
+		{"duplicate of the original", usecase.StoreEventResult{EventID: generated}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		app := setupTestApp(&fakeStoreEventUseCase{
+			ExecuteFunc: func(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error) {
+				return tt.result, nil
+			},
+		})
+
+		resp, body := doRequest(t, app, http.MethodPost, "/events", CreateEventRequest{
+			EventName: "product_view",
+			Channel:   "web",
+			UserID:    "user_123",
+			Timestamp: time.Now().Add(-time.Minute).Unix(),
+		})
+		if resp.StatusCode != tt.wantStatus {
+			t.Fatalf("%s: expected status %d, got %d (body: %s)", tt.name, tt.wantStatus, resp.StatusCode, body)
+		}
+		var respJSON CreateEventResponse
+		if err := json.Unmarshal(body, &respJSON); err != nil || respJSON.EventID != generated {
+			t.Fatalf("%s: expected event_id=%s, got %s", tt.name, generated, body)
+		}
+	}
+}
+
+func TestCreateEvent_PassesTimestampMs(t *testing.T) {
+	fakeUC := &fakeStoreEventUseCase{
+		ExecuteFunc: func(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error) {
+			return usecase.StoreEventResult{Created: true}, nil
+		},
+	}
+	app := setupTestApp(fakeUC)
+
+	ms := time.Now().Add(-time.Minute).UnixMilli()
+	reqBody := CreateEventRequest{
+		EventName:   "product_view",
+		Channel:     "web",
+		UserID:      "user_123",
+		TimestampMs: ms,
+	}
+
+	resp, body := doRequest(t, app, http.MethodPost, "/events", reqBody)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d (body: %s)", http.StatusCreated, resp.StatusCode, string(body))
+	}
+	if fakeUC.LastExecuteInput.TimestampMs != ms {
+		t.Errorf("expected timestamp_ms=%d to be passed to usecase, got %d", ms, fakeUC.LastExecuteInput.TimestampMs)
+	}
+}
+
 func TestCreateEvent_InvalidJSON(t *testing.T) {
 	fakeUC := &fakeStoreEventUseCase{}
 	app := setupTestApp(fakeUC)
@@ -177,8 +356,8 @@ func TestCreateEvent_ValidationError(t *testing.T) {
 	now := time.Now().Add(-time.Minute).Unix()
 
 	fakeUC := &fakeStoreEventUseCase{
-		ExecuteFunc: func(ctx context.Context, in usecase.StoreEventInput) (bool, error) {
-			return false, usecase.ErrInvalidEvent
+		ExecuteFunc: func(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error) {
+			return usecase.StoreEventResult{}, usecase.ErrInvalidEvent
 		},
 	}
 
@@ -209,10 +388,128 @@ func TestCreateEvent_ValidationError(t *testing.T) {
 	}
 }
 
+func TestCreateEvent_ValidationDetails(t *testing.T) {
+	fakeUC := &fakeStoreEventUseCase{
+		ExecuteFunc: func(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error) {
+			return usecase.StoreEventResult{}, &usecase.ValidationError{Violations: []usecase.FieldViolation{
+				{Field: "tags", Message: "at most 20 tags allowed, got 25"},
+			}}
+		},
+	}
+
+	app := setupTestApp(fakeUC)
+
+	reqBody := CreateEventRequest{
+		EventName: "product_view",
+		Channel:   "web",
+		UserID:    "user_123",
+		Timestamp: time.Now().Add(-time.Minute).Unix(),
+	}
+
+	resp, body := doRequest(t, app, http.MethodPost, "/events", reqBody)
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d (body: %s)", http.StatusBadRequest, resp.StatusCode, string(body))
+	}
+
+	var respJSON ErrorResponse
+	if err := json.Unmarshal(body, &respJSON); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+
+	if respJSON.Error != "invalid_event" {
+		t.Errorf("expected error=invalid_event, got %s", respJSON.Error)
+	}
+	if len(respJSON.Details) != 1 || respJSON.Details[0].Field != "tags" {
+		t.Errorf("expected details for field 'tags', got %+v", respJSON.Details)
+	}
+}
+
+func TestCreateEvent_InvalidTags(t *testing.T) {
+	fakeUC := &fakeStoreEventUseCase{
+		ExecuteFunc: func(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error) {
+			return usecase.StoreEventResult{}, &usecase.ValidationError{
+				Cause: usecase.ErrInvalidTags,
+				Violations: []usecase.FieldViolation{
+					{Field: "tags[1]", Message: "exceeds max length 64"},
+				},
+			}
+		},
+	}
+
+	app := setupTestApp(fakeUC)
+
+	reqBody := CreateEventRequest{
+		EventName: "product_view",
+		Channel:   "web",
+		UserID:    "user_123",
+		Timestamp: time.Now().Add(-time.Minute).Unix(),
+	}
+
+	resp, body := doRequest(t, app, http.MethodPost, "/events", reqBody)
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d (body: %s)", http.StatusBadRequest, resp.StatusCode, string(body))
+	}
+
+	var respJSON ErrorResponse
+	if err := json.Unmarshal(body, &respJSON); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+
+	if respJSON.Error != "invalid_tags" {
+		t.Errorf("expected error=invalid_tags, got %s", respJSON.Error)
+	}
+	if len(respJSON.Details) != 1 || respJSON.Details[0].Field != "tags[1]" {
+		t.Errorf("expected details for field 'tags[1]', got %+v", respJSON.Details)
+	}
+}
+
+func TestCreateEvent_SchemaViolation(t *testing.T) {
+	fakeUC := &fakeStoreEventUseCase{
+		ExecuteFunc: func(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error) {
+			return usecase.StoreEventResult{}, &usecase.ValidationError{
+				Cause: usecase.ErrSchemaViolation,
+				Violations: []usecase.FieldViolation{
+					{Field: "metadata.order_id", Message: "is required"},
+					{Field: "metadata.amount", Message: "must be of type number"},
+				},
+			}
+		},
+	}
+
+	app := setupTestApp(fakeUC)
+
+	reqBody := CreateEventRequest{
+		EventName: "purchase",
+		Channel:   "web",
+		UserID:    "user_123",
+		Timestamp: time.Now().Add(-time.Minute).Unix(),
+	}
+
+	resp, body := doRequest(t, app, http.MethodPost, "/events", reqBody)
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d (body: %s)", http.StatusBadRequest, resp.StatusCode, string(body))
+	}
+
+	var respJSON ErrorResponse
+	if err := json.Unmarshal(body, &respJSON); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+
+	if respJSON.Error != "schema_violation" {
+		t.Errorf("expected error=schema_violation, got %s", respJSON.Error)
+	}
+	if len(respJSON.Details) != 2 || respJSON.Details[0].Field != "metadata.order_id" {
+		t.Errorf("expected details for metadata fields, got %+v", respJSON.Details)
+	}
+}
+
 func TestCreateEvent_FutureTimeError(t *testing.T) {
 	fakeUC := &fakeStoreEventUseCase{
-		ExecuteFunc: func(ctx context.Context, in usecase.StoreEventInput) (bool, error) {
-			return false, usecase.ErrFutureTime
+		ExecuteFunc: func(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error) {
+			return usecase.StoreEventResult{}, usecase.ErrFutureTime
 		},
 	}
 
@@ -242,12 +539,67 @@ func TestCreateEvent_FutureTimeError(t *testing.T) {
 	}
 }
 
+func TestCreateEvent_EventTooOldError(t *testing.T) {
+	fakeUC := &fakeStoreEventUseCase{
+		ExecuteFunc: func(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error) {
+			return usecase.StoreEventResult{}, usecase.ErrEventTooOld
+		},
+	}
+
+	app := setupTestApp(fakeUC)
+
+	reqBody := CreateEventRequest{
+		EventName: "product_view",
+		Channel:   "web",
+		UserID:    "user_123",
+		Timestamp: time.Now().AddDate(0, -6, 0).Unix(),
+	}
+
+	resp, body := doRequest(t, app, http.MethodPost, "/events", reqBody)
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d (body: %s)", http.StatusBadRequest, resp.StatusCode, string(body))
+	}
+}
+
+func TestCreateEvent_MetadataTooLarge(t *testing.T) {
+	fakeUC := &fakeStoreEventUseCase{
+		ExecuteFunc: func(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error) {
+			return usecase.StoreEventResult{}, usecase.ErrMetadataTooLarge
+		},
+	}
+
+	app := setupTestApp(fakeUC)
+
+	reqBody := CreateEventRequest{
+		EventName: "product_view",
+		Channel:   "web",
+		UserID:    "user_123",
+		Timestamp: time.Now().Add(-time.Minute).Unix(),
+		Metadata:  map[string]any{"blob": "..."},
+	}
+
+	resp, body := doRequest(t, app, http.MethodPost, "/events", reqBody)
+
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected status %d, got %d (body: %s)", http.StatusRequestEntityTooLarge, resp.StatusCode, string(body))
+	}
+
+	var respJSON ErrorResponse
+	if err := json.Unmarshal(body, &respJSON); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if respJSON.Error != "metadata_too_large" {
+		t.Errorf("expected error=metadata_too_large, got %s", respJSON.Error)
+	}
+}
+
 func TestCreateEvent_InternalError(t *testing.T) {
 	now := time.Now().Add(-time.Minute).Unix()
 
 	fakeUC := &fakeStoreEventUseCase{
-		ExecuteFunc: func(ctx context.Context, in usecase.StoreEventInput) (bool, error) {
-			return false, errors.New("db error")
+		ExecuteFunc: func(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error) {
+			return usecase.StoreEventResult{}, errors.New("db error")
 		},
 	}
 
@@ -276,6 +628,62 @@ func TestCreateEvent_InternalError(t *testing.T) {
 	}
 }
 
+func TestCreateEvent_DeadLettered(t *testing.T) {
+	fakeUC := &fakeStoreEventUseCase{
+		ExecuteFunc: func(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error) {
+			return usecase.StoreEventResult{}, fmt.Errorf("%w: connection refused", usecase.ErrEventDeadLettered)
+		},
+	}
+
+	reqBody := CreateEventRequest{
+		EventName: "product_view",
+		Channel:   "web",
+		UserID:    "user_123",
+		Timestamp: time.Now().Add(-time.Minute).Unix(),
+	}
+
+	resp, body := doRequest(t, setupTestApp(fakeUC), http.MethodPost, "/events", reqBody)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d (body: %s)", http.StatusAccepted, resp.StatusCode, string(body))
+	}
+
+	var respJSON CreateEventResponse
+	if err := json.Unmarshal(body, &respJSON); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if respJSON.Status != "accepted" {
+		t.Errorf("expected status=accepted, got %q", respJSON.Status)
+	}
+}
+
+func TestCreateEvent_SampledOut(t *testing.T) {
+	fakeUC := &fakeStoreEventUseCase{
+		ExecuteFunc: func(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error) {
+			return usecase.StoreEventResult{}, usecase.ErrEventSampledOut
+		},
+	}
+
+	reqBody := CreateEventRequest{
+		EventName: "heartbeat",
+		Channel:   "web",
+		UserID:    "user_123",
+		Timestamp: time.Now().Add(-time.Minute).Unix(),
+	}
+
+	resp, body := doRequest(t, setupTestApp(fakeUC), http.MethodPost, "/events", reqBody)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d (body: %s)", http.StatusAccepted, resp.StatusCode, string(body))
+	}
+
+	var respJSON CreateEventResponse
+	if err := json.Unmarshal(body, &respJSON); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if respJSON.Status != "sampled" {
+		t.Errorf("expected status=sampled, got %q", respJSON.Status)
+	}
+}
+
 // ---- Bulk tests ----
 
 func TestBulkCreateEvents_Success_AllCreated(t *testing.T) {
@@ -384,6 +792,79 @@ func TestBulkCreateEvents_MixedCreatedAndDuplicate(t *testing.T) {
 	}
 }
 
+func TestBulkCreateEvents_PerItemResults(t *testing.T) {
+	now := time.Now().Add(-time.Minute).Unix()
+
+	fakeUC := &fakeStoreEventUseCase{
+		BulkCreateFunc: func(ctx context.Context, in usecase.BulkCreateEventsInput) (usecase.BulkCreateEventsResult, error) {
+			return usecase.BulkCreateEventsResult{
+				Created: 1,
+				Invalid: 1,
+				Items: []usecase.BulkItemResult{
+					{Index: 0, Status: usecase.ItemStatusCreated},
+					{Index: 1, Status: usecase.ItemStatusInvalid, Reason: usecase.ErrInvalidEvent.Error()},
+				},
+			}, nil
+		},
+	}
+
+	app := setupTestApp(fakeUC)
+
+	reqBody := BulkCreateEventsRequest{
+		Events: []bulkEventItem{
+			{EventName: "product_view", Channel: "web", UserID: "u1", Timestamp: now},
+			{EventName: "", Channel: "web", UserID: "u2", Timestamp: now},
+		},
+	}
+
+	resp, body := doRequest(t, app, http.MethodPost, "/events/bulk", reqBody)
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d (body: %s)", http.StatusCreated, resp.StatusCode, string(body))
+	}
+
+	var respJSON BulkCreateEventsResponse
+	if err := json.Unmarshal(body, &respJSON); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+
+	if respJSON.Invalid != 1 {
+		t.Errorf("expected invalid=1, got %d", respJSON.Invalid)
+	}
+	if len(respJSON.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(respJSON.Items))
+	}
+	if respJSON.Items[1].Index != 1 || respJSON.Items[1].Status != "invalid" || respJSON.Items[1].Reason == "" {
+		t.Errorf("unexpected item result: %+v", respJSON.Items[1])
+	}
+}
+
+func TestBulkCreateEvents_AtomicQueryParam(t *testing.T) {
+	now := time.Now().Add(-time.Minute).Unix()
+
+	fakeUC := &fakeStoreEventUseCase{}
+	app := setupTestApp(fakeUC)
+
+	reqBody := BulkCreateEventsRequest{
+		Events: []bulkEventItem{
+			{EventName: "product_view", Channel: "web", UserID: "u1", Timestamp: now},
+		},
+	}
+
+	resp, body := doRequest(t, app, http.MethodPost, "/events/bulk?atomic=true", reqBody)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d (body: %s)", http.StatusCreated, resp.StatusCode, string(body))
+	}
+	if !fakeUC.LastBulkCreateInput.Atomic {
+		t.Errorf("expected Atomic=true to be passed to usecase")
+	}
+
+	doRequest(t, app, http.MethodPost, "/events/bulk", reqBody)
+	if fakeUC.LastBulkCreateInput.Atomic {
+		t.Errorf("expected Atomic=false by default")
+	}
+}
+
 func TestBulkCreateEvents_InvalidJSON(t *testing.T) {
 	fakeUC := &fakeStoreEventUseCase{}
 	app := setupTestApp(fakeUC)
@@ -427,6 +908,37 @@ func TestBulkCreateEvents_EmptyEvents(t *testing.T) {
 	}
 }
 
+func TestBulkCreateEvents_TooManyEvents(t *testing.T) {
+	called := false
+	fakeUC := &fakeStoreEventUseCase{
+		BulkCreateFunc: func(ctx context.Context, in usecase.BulkCreateEventsInput) (usecase.BulkCreateEventsResult, error) {
+			called = true
+			return usecase.BulkCreateEventsResult{}, nil
+		},
+	}
+	app := fiber.New()
+	app.Post("/events/bulk", NewEventHandler(fakeUC, WithMaxBulkEvents(2)).BulkCreateEvents)
+
+	reqBody := BulkCreateEventsRequest{Events: make([]bulkEventItem, 3)}
+	resp, body := doRequest(t, app, http.MethodPost, "/events/bulk", reqBody)
+
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected status %d, got %d (body: %s)", http.StatusRequestEntityTooLarge, resp.StatusCode, string(body))
+	}
+	var respJSON ErrorResponse
+	if err := json.Unmarshal(body, &respJSON); err != nil || respJSON.Error != "batch_too_large" {
+		t.Fatalf("expected batch_too_large, got %s", body)
+	}
+	if called {
+		t.Fatalf("expected nothing to be stored")
+	}
+
+	reqBody.Events = reqBody.Events[:2]
+	if resp, _ := doRequest(t, app, http.MethodPost, "/events/bulk", reqBody); resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected a batch at the limit to be accepted, got %d", resp.StatusCode)
+	}
+}
+
 func TestBulkCreateEvents_ValidationError(t *testing.T) {
 	fakeUC := &fakeStoreEventUseCase{
 		BulkCreateFunc: func(ctx context.Context, in usecase.BulkCreateEventsInput) (usecase.BulkCreateEventsResult, error) {
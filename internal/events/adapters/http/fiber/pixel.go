@@ -0,0 +1,103 @@
+package fiber
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"event-metrics-service/internal/events/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// pixelGIF is the 1x1 transparent GIF answered by the beacon endpoints.
+var pixelGIF, _ = base64.StdEncoding.DecodeString("R0lGODlhAQABAIAAAAAAAP///yH5BAEAAAAALAAAAAABAAEAAAIBRAA7")
+
+// pixelMetadataPrefix marks the query parameters stored as metadata, so API
+// keys and cache busters in the URL are not.
+const pixelMetadataPrefix = "metadata."
+
+// DefaultPixelChannel is the channel of pixel events without a channel
+// parameter; image beacons are mostly email opens.
+const DefaultPixelChannel = "email"
+
+// CollectPixel godoc
+// @Summary Record an event from an image beacon
+// @Description Records one event from the query parameters and answers a transparent 1x1 GIF that is not
+// @Description cached, e.g. for email open tracking. Parameters prefixed metadata. are stored as metadata
+// @Description (metadata.template=welcome). Invalid events are dropped; the GIF is answered anyway.
+// @Tags Events
+// @Produce image/gif
+// @Param event_name query string true "Event name"
+// @Param user_id query string false "User id"
+// @Param anonymous_id query string false "Anonymous id"
+// @Param event_id query string false "Event id (UUID), deduplicates repeated loads"
+// @Param channel query string false "Channel (default email)"
+// @Param campaign_id query string false "Campaign id"
+// @Param value query number false "Value"
+// @Param timestamp_ms query int false "Event time in unix milliseconds (default: time of receipt)"
+// @Success 200
+// @Failure 401 {object} ErrorResponse "missing or unknown API key (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "missing scope events:write"
+// @Failure 429 {object} ErrorResponse "rate_limited: request rate limit exceeded for this API key"
+// @Failure 500 {object} ErrorResponse
+// @Router /pixel.gif [get]
+func (h *EventHandler) CollectPixel(c *fiber.Ctx) error {
+	in := pixelInput(c.Queries(), time.Now())
+	in.Client = clientInfo(c)
+
+	// The bulk path reports an invalid event instead of failing with it.
+	res, err := h.storeUC.BulkCreateEvents(c.UserContext(), usecase.BulkCreateEventsInput{
+		Events: []usecase.StoreEventInput{in},
+	})
+	if err != nil || res.Failed > 0 {
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error: "internal_server_error",
+		})
+	}
+	return sendPixel(c)
+}
+
+func pixelInput(params map[string]string, received time.Time) usecase.StoreEventInput {
+	in := usecase.StoreEventInput{
+		EventID:     params["event_id"],
+		EventName:   params["event_name"],
+		Channel:     params["channel"],
+		CampaignID:  params["campaign_id"],
+		UserID:      params["user_id"],
+		AnonymousID: params["anonymous_id"],
+		TimestampMs: received.UnixMilli(),
+	}
+	if in.Channel == "" {
+		in.Channel = DefaultPixelChannel
+	}
+	if ms, err := strconv.ParseInt(params["timestamp_ms"], 10, 64); err == nil && ms > 0 {
+		in.TimestampMs = ms
+	}
+	if v, err := strconv.ParseFloat(params["value"], 64); err == nil {
+		in.Value = &v
+	}
+
+	metadata := map[string]any{}
+	for k, v := range params {
+		if key, ok := strings.CutPrefix(k, pixelMetadataPrefix); ok && key != "" {
+			metadata[key] = v
+		}
+	}
+	if len(metadata) > 0 {
+		in.Metadata = metadata
+	}
+	return in
+}
+
+// sendPixel answers the transparent GIF, with headers that keep browsers,
+// mail clients and proxies from caching it so every open is requested.
+func sendPixel(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store, no-cache, must-revalidate, max-age=0")
+	c.Set(fiber.HeaderPragma, "no-cache")
+	c.Set(fiber.HeaderExpires, "0")
+	c.Set(fiber.HeaderContentType, "image/gif")
+	return c.Status(http.StatusOK).Send(pixelGIF)
+}
@@ -0,0 +1,77 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"event-metrics-service/internal/events/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func setupPixelApp(uc StoreEventUseCase) *fiber.App {
+	app := fiber.New()
+	app.Get("/pixel.gif", NewEventHandler(uc).CollectPixel)
+	return app
+}
+
+func TestCollectPixel_RecordsEvent(t *testing.T) {
+	uc := &fakeStoreEventUseCase{}
+	app := setupPixelApp(uc)
+
+	q := url.Values{
+		"event_name":        {"email_open"},
+		"user_id":           {"u1"},
+		"campaign_id":       {"spring"},
+		"timestamp_ms":      {"1733572800123"},
+		"metadata.template": {"welcome"},
+		"api_key":           {"key-a"},
+		"cb":                {"123"},
+	}
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/pixel.gif?"+q.Encode(), nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "image/gif" || len(body) == 0 {
+		t.Fatalf("expected a gif pixel, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if cc := resp.Header.Get("Cache-Control"); cc == "" || resp.Header.Get("Pragma") != "no-cache" {
+		t.Fatalf("expected no-cache headers, got Cache-Control %q", cc)
+	}
+
+	events := uc.LastBulkCreateInput.Events
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	e := events[0]
+	if e.EventName != "email_open" || e.UserID != "u1" || e.CampaignID != "spring" || e.Channel != DefaultPixelChannel {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+	if e.TimestampMs != 1733572800123 {
+		t.Fatalf("expected the given time, got %d", e.TimestampMs)
+	}
+	if len(e.Metadata) != 1 || e.Metadata["template"] != "welcome" {
+		t.Fatalf("expected only prefixed params as metadata, got %v", e.Metadata)
+	}
+}
+
+func TestCollectPixel_StoreFailure(t *testing.T) {
+	uc := &fakeStoreEventUseCase{
+		BulkCreateFunc: func(ctx context.Context, in usecase.BulkCreateEventsInput) (usecase.BulkCreateEventsResult, error) {
+			return usecase.BulkCreateEventsResult{}, errors.New("db down")
+		},
+	}
+	resp, err := setupPixelApp(uc).Test(httptest.NewRequest(http.MethodGet, "/pixel.gif?event_name=email_open", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,221 @@
+package fiber
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+	"event-metrics-service/internal/events/core/usecase"
+	"event-metrics-service/internal/tag"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ListEventsUseCase interface {
+	Execute(ctx context.Context, q ports.EventQuery) (*usecase.ListEventsResult, error)
+}
+
+type GetEventUseCase interface {
+	Execute(ctx context.Context, ref string) (domain.StoredEvent, error)
+}
+
+type ExportEventsUseCase interface {
+	Validate(q ports.EventQuery) error
+	Execute(ctx context.Context, q ports.EventQuery, emit func([]domain.StoredEvent) error) (int64, error)
+}
+
+// EventQueryHandler serves the read side of stored events for debugging.
+type EventQueryHandler struct {
+	listUC      ListEventsUseCase
+	getUC       GetEventUseCase
+	exportUC    ExportEventsUseCase
+	exportStore ObjectStore // nil without EXPORT_S3_BUCKET
+}
+
+func NewEventQueryHandler(listUC ListEventsUseCase, getUC GetEventUseCase, exportUC ExportEventsUseCase, opts ...QueryHandlerOption) *EventQueryHandler {
+	h := &EventQueryHandler{listUC: listUC, getUC: getUC, exportUC: exportUC}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// ListEvents godoc
+// @Summary List stored events
+// @Description Returns raw events, newest first, for debugging without database access.
+// @Description Pass next_cursor from the previous response as cursor to fetch the next page.
+// @Tags Events
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param event_name query string false "Event name"
+// @Param channel query string false "Channel"
+// @Param user_id query string false "User ID"
+// @Param campaign_id query string false "Campaign ID"
+// @Param from query int false "From timestamp (unix seconds, inclusive)"
+// @Param to query int false "To timestamp (unix seconds, inclusive of the whole second)"
+// @Param tags query string false "Comma separated tags; events must carry all of them"
+// @Param limit query int false "Page size (default 50, max 500)"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} ListEventsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events [get]
+func (h *EventQueryHandler) ListEvents(c *fiber.Ctx) error {
+	q, msg := parseEventFilters(c)
+	if msg != "" {
+		return invalidQuery(c, msg)
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			return invalidQuery(c, "invalid 'limit' parameter")
+		}
+		q.Limit = n
+	}
+
+	if raw := c.Query("cursor"); raw != "" {
+		cur, ok := decodeCursor(raw)
+		if !ok {
+			return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+				Error:   "invalid_cursor",
+				Message: "cursor is malformed; pass next_cursor from a previous response",
+			})
+		}
+		q.After = &cur
+	}
+
+	res, err := h.listUC.Execute(c.UserContext(), q)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidEventQuery) {
+			return invalidQuery(c, err.Error())
+		}
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error: "internal_server_error",
+		})
+	}
+
+	resp := ListEventsResponse{Events: make([]StoredEventResponse, 0, len(res.Events))}
+	for _, e := range res.Events {
+		resp.Events = append(resp.Events, toStoredEventResponse(e))
+	}
+	if res.Next != nil {
+		resp.NextCursor = encodeCursor(*res.Next)
+	}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+// GetEvent godoc
+// @Summary Show a stored event
+// @Description Returns the full stored record, including tags, metadata, dedupe key and received_at, so support
+// @Description can confirm an event landed. id is the client-supplied event_id (UUID) or the numeric id from GET /events.
+// @Tags Events
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param id path string true "event_id (UUID) or numeric id"
+// @Success 200 {object} StoredEventResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /events/{id} [get]
+func (h *EventQueryHandler) GetEvent(c *fiber.Ctx) error {
+	e, err := h.getUC.Execute(c.UserContext(), c.Params("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidEventRef):
+			return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+				Error:   "invalid_event_id",
+				Message: err.Error(),
+			})
+		case errors.Is(err, usecase.ErrEventNotFound):
+			return c.Status(http.StatusNotFound).JSON(ErrorResponse{
+				Error:   "event_not_found",
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+				Error: "internal_server_error",
+			})
+		}
+	}
+
+	return c.Status(http.StatusOK).JSON(toStoredEventResponse(e))
+}
+
+// parseEventFilters reads the filters shared by GET /events and
+// GET /events/export; msg is non-empty when a parameter is invalid.
+func parseEventFilters(c *fiber.Ctx) (q ports.EventQuery, msg string) {
+	q = ports.EventQuery{
+		EventName:  c.Query("event_name"),
+		Channel:    c.Query("channel"),
+		UserID:     c.Query("user_id"),
+		CampaignID: c.Query("campaign_id"),
+	}
+
+	for _, p := range []struct {
+		name string
+		dest *time.Time
+	}{{"from", &q.From}, {"to", &q.To}} {
+		raw := c.Query(p.name)
+		if raw == "" {
+			continue
+		}
+		sec, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return q, "invalid '" + p.name + "' parameter"
+		}
+		*p.dest = time.Unix(sec, 0).UTC()
+	}
+	// The query's end is exclusive; the whole to second is included, as
+	// event_time has milliseconds.
+	if !q.To.IsZero() {
+		q.To = q.To.Add(time.Second)
+	}
+
+	// Stored tags were normalized on ingest.
+	if raw := c.Query("tags"); raw != "" {
+		q.Tags = tag.Normalize(strings.Split(raw, ","))
+	}
+	return q, ""
+}
+
+func invalidQuery(c *fiber.Ctx, msg string) error {
+	return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+		Error:   "invalid_query",
+		Message: msg,
+	})
+}
+
+// Cursors are opaque to clients: base64url("<event_time unix nanos>:<id>").
+
+func encodeCursor(cur ports.EventCursor) string {
+	raw := strconv.FormatInt(cur.EventTime.UnixNano(), 10) + ":" + strconv.FormatInt(cur.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(s string) (ports.EventCursor, bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ports.EventCursor{}, false
+	}
+	ts, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return ports.EventCursor{}, false
+	}
+	nanos, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ports.EventCursor{}, false
+	}
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ports.EventCursor{}, false
+	}
+	return ports.EventCursor{EventTime: time.Unix(0, nanos).UTC(), ID: n}, true
+}
@@ -0,0 +1,240 @@
+package fiber
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+	"event-metrics-service/internal/events/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeListEventsUseCase struct {
+	ExecuteFn func(ctx context.Context, q ports.EventQuery) (*usecase.ListEventsResult, error)
+	called    bool
+}
+
+func (f *fakeListEventsUseCase) Execute(ctx context.Context, q ports.EventQuery) (*usecase.ListEventsResult, error) {
+	f.called = true
+	return f.ExecuteFn(ctx, q)
+}
+
+type fakeGetEventUseCase struct {
+	ExecuteFn func(ctx context.Context, ref string) (domain.StoredEvent, error)
+}
+
+func (f *fakeGetEventUseCase) Execute(ctx context.Context, ref string) (domain.StoredEvent, error) {
+	return f.ExecuteFn(ctx, ref)
+}
+
+func setupQueryApp(uc ListEventsUseCase) *fiber.App {
+	return setupQueryAppWith(uc, &fakeGetEventUseCase{})
+}
+
+func setupQueryAppWith(listUC ListEventsUseCase, getUC GetEventUseCase) *fiber.App {
+	app := fiber.New()
+	h := NewEventQueryHandler(listUC, getUC, &fakeExportEventsUseCase{})
+	app.Get("/events", h.ListEvents)
+	app.Get("/events/:id", h.GetEvent)
+	return app
+}
+
+func TestListEvents_FiltersAndCursor(t *testing.T) {
+	next := ports.EventCursor{EventTime: time.Date(2025, 12, 7, 10, 0, 0, 123, time.UTC), ID: 1042}
+	after := ports.EventCursor{EventTime: time.Date(2025, 12, 7, 11, 0, 0, 0, time.UTC), ID: 2000}
+
+	uc := &fakeListEventsUseCase{
+		ExecuteFn: func(ctx context.Context, q ports.EventQuery) (*usecase.ListEventsResult, error) {
+			if q.EventName != "purchase" || q.UserID != "u1" || q.Limit != 2 {
+				t.Fatalf("unexpected query: %+v", q)
+			}
+			if q.From.Unix() != 1733529600 || q.To.Unix() != 1733616001 {
+				t.Fatalf("unexpected range: %v - %v", q.From, q.To)
+			}
+			if len(q.Tags) != 2 || q.Tags[0] != "promo" || q.Tags[1] != "vip" {
+				t.Fatalf("unexpected tags: %v", q.Tags)
+			}
+			if q.After == nil || *q.After != after {
+				t.Fatalf("expected cursor to round-trip, got %+v", q.After)
+			}
+			return &usecase.ListEventsResult{
+				Events: []domain.StoredEvent{{
+					Event: domain.Event{EventName: "purchase", UserID: "u1", DedupeKey: "dk-1"},
+					ID:    1042,
+				}},
+				Next: &next,
+			}, nil
+		},
+	}
+
+	params := url.Values{}
+	params.Set("event_name", "purchase")
+	params.Set("user_id", "u1")
+	params.Set("from", "1733529600")
+	params.Set("to", "1733616000")
+	params.Set("tags", "Promo, vip,,promo")
+	params.Set("limit", "2")
+	params.Set("cursor", encodeCursor(after))
+
+	resp, err := setupQueryApp(uc).Test(httptest.NewRequest(http.MethodGet, "/events?"+params.Encode(), nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body ListEventsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if len(body.Events) != 1 || body.Events[0].ID != 1042 || body.Events[0].DedupeKey != "dk-1" || body.Events[0].Tags == nil {
+		t.Fatalf("unexpected events: %+v", body.Events)
+	}
+	if cur, ok := decodeCursor(body.NextCursor); !ok || cur != next {
+		t.Fatalf("expected next cursor %+v, got %q", next, body.NextCursor)
+	}
+}
+
+func TestListEvents_BadRequests(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		ucErr     error
+		wantError string
+	}{
+		{"bad from", "from=yesterday", nil, "invalid_query"},
+		{"bad limit", "limit=0", nil, "invalid_query"},
+		{"bad cursor", "cursor=bm9wZQ", nil, "invalid_cursor"},
+		{"usecase validation", "limit=5000", usecase.ErrInvalidEventQuery, "invalid_query"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &fakeListEventsUseCase{
+				ExecuteFn: func(ctx context.Context, q ports.EventQuery) (*usecase.ListEventsResult, error) {
+					return nil, tt.ucErr
+				},
+			}
+
+			resp, err := setupQueryApp(uc).Test(httptest.NewRequest(http.MethodGet, "/events?"+tt.query, nil))
+			if err != nil {
+				t.Fatalf("app.Test error: %v", err)
+			}
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d", resp.StatusCode)
+			}
+
+			var body ErrorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("invalid json response: %v", err)
+			}
+			if body.Error != tt.wantError {
+				t.Fatalf("expected %s, got %+v", tt.wantError, body)
+			}
+		})
+	}
+}
+
+func TestListEvents_InternalError(t *testing.T) {
+	uc := &fakeListEventsUseCase{
+		ExecuteFn: func(ctx context.Context, q ports.EventQuery) (*usecase.ListEventsResult, error) {
+			return nil, errors.New("db down")
+		},
+	}
+
+	resp, err := setupQueryApp(uc).Test(httptest.NewRequest(http.MethodGet, "/events", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", resp.StatusCode)
+	}
+}
+
+// ------------------------------------------------------------
+// GET /events/:id
+// ------------------------------------------------------------
+
+func TestGetEvent_Success(t *testing.T) {
+	receivedAt := time.Date(2025, 12, 7, 10, 0, 2, 0, time.UTC)
+	uc := &fakeGetEventUseCase{
+		ExecuteFn: func(ctx context.Context, ref string) (domain.StoredEvent, error) {
+			if ref != "0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42" {
+				t.Fatalf("unexpected ref %q", ref)
+			}
+			return domain.StoredEvent{
+				Event: domain.Event{
+					EventID:   ref,
+					EventName: "purchase",
+					Tags:      []string{"promo"},
+					Metadata:  map[string]any{"order_id": "ord_1"},
+					DedupeKey: ref,
+				},
+				ID:         1042,
+				ReceivedAt: receivedAt,
+			}, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/events/0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42", nil)
+	resp, err := setupQueryAppWith(&fakeListEventsUseCase{}, uc).Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body StoredEventResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if body.ID != 1042 || body.DedupeKey != body.EventID || !body.ReceivedAt.Equal(receivedAt) || body.Metadata["order_id"] != "ord_1" {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+}
+
+func TestGetEvent_Errors(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantStatus int
+		wantError  string
+	}{
+		{usecase.ErrInvalidEventRef, http.StatusBadRequest, "invalid_event_id"},
+		{usecase.ErrEventNotFound, http.StatusNotFound, "event_not_found"},
+		{errors.New("db down"), http.StatusInternalServerError, "internal_server_error"},
+	}
+
+	for _, tt := range tests {
+		uc := &fakeGetEventUseCase{
+			ExecuteFn: func(ctx context.Context, ref string) (domain.StoredEvent, error) {
+				return domain.StoredEvent{}, tt.err
+			},
+		}
+
+		resp, err := setupQueryAppWith(&fakeListEventsUseCase{}, uc).Test(httptest.NewRequest(http.MethodGet, "/events/42", nil))
+		if err != nil {
+			t.Fatalf("app.Test error: %v", err)
+		}
+		if resp.StatusCode != tt.wantStatus {
+			t.Fatalf("%v: expected %d, got %d", tt.err, tt.wantStatus, resp.StatusCode)
+		}
+
+		var body ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("invalid json response: %v", err)
+		}
+		if body.Error != tt.wantError {
+			t.Fatalf("%v: expected %s, got %+v", tt.err, tt.wantError, body)
+		}
+	}
+}
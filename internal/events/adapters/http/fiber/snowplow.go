@@ -0,0 +1,243 @@
+package fiber
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"event-metrics-service/internal/events/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// snowplowPlatformChannels maps the tracker protocol's platform codes (p)
+// to channels.
+var snowplowPlatformChannels = map[string]string{
+	"web":  "web",
+	"mob":  "mobile",
+	"app":  "app",
+	"pc":   "desktop",
+	"srv":  "server",
+	"tv":   "tv",
+	"cnsl": "console",
+	"iot":  "iot",
+}
+
+// WithSnowplowChannels sets the channel of Snowplow events per app id (aid).
+// Other events get the channel of their platform (p), e.g. "web" or "mobile".
+func WithSnowplowChannels(channels map[string]string) HandlerOption {
+	return func(h *EventHandler) {
+		h.snowplowChannels = channels
+	}
+}
+
+// SnowplowPayload is a tracker protocol POST body: a self-describing
+// payload_data wrapping one parameter map per event.
+// @Description Snowplow payload_data
+type SnowplowPayload struct {
+	Schema string              `json:"schema" example:"iglu:com.snowplowanalytics.snowplow/payload_data/jsonschema/1-0-4"`
+	Data   []map[string]string `json:"data"`
+}
+
+// CollectSnowplowPixel godoc
+// @Summary Collect a Snowplow GET event
+// @Description Snowplow tracker protocol GET request: the event is in the query parameters. Structured (se),
+// @Description page view (pv) and self-describing (ue) events are stored; other types and invalid events are
+// @Description dropped. Answers a transparent 1x1 GIF like a Snowplow collector.
+// @Tags Events
+// @Produce image/gif
+// @Param e query string true "Event type: se, pv or ue"
+// @Success 200
+// @Failure 401 {object} ErrorResponse "missing or unknown API key (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "missing scope events:write"
+// @Failure 429 {object} ErrorResponse "rate_limited: request rate limit exceeded for this API key"
+// @Failure 500 {object} ErrorResponse
+// @Router /i [get]
+func (h *EventHandler) CollectSnowplowPixel(c *fiber.Ctx) error {
+	if err := h.storeSnowplow(c, []map[string]string{c.Queries()}); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error: "internal_server_error",
+		})
+	}
+	return sendPixel(c)
+}
+
+// CollectSnowplow godoc
+// @Summary Collect Snowplow POST events
+// @Description Snowplow tracker protocol POST request (tp2), a batch of events. Structured (se), page view (pv)
+// @Description and self-describing (ue) events are stored; other types and invalid events are dropped, so
+// @Description trackers do not retry them.
+// @Tags Events
+// @Accept json
+// @Produce plain
+// @Param request body SnowplowPayload true "payload_data"
+// @Success 200 {string} string "ok"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse "missing or unknown API key (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "missing scope events:write"
+// @Failure 413 {object} ErrorResponse "batch_too_large: more events than BULK_MAX_EVENTS; body_too_large: body over MAX_BODY_BYTES; json_too_complex: body over JSON_MAX_DEPTH or JSON_MAX_VALUES"
+// @Failure 429 {object} ErrorResponse "rate_limited: request rate limit exceeded for this API key"
+// @Failure 500 {object} ErrorResponse
+// @Router /com.snowplowanalytics.snowplow/tp2 [post]
+func (h *EventHandler) CollectSnowplow(c *fiber.Ctx) error {
+	var payload SnowplowPayload
+	if err := json.Unmarshal(c.Body(), &payload); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid_json",
+		})
+	}
+	if len(payload.Data) == 0 {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "events_list_required",
+		})
+	}
+	if h.tooManyEvents(len(payload.Data)) {
+		return h.batchTooLarge(c)
+	}
+
+	if err := h.storeSnowplow(c, payload.Data); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error: "internal_server_error",
+		})
+	}
+	return c.Status(http.StatusOK).SendString("ok")
+}
+
+// storeSnowplow stores the supported events; unsupported and invalid ones
+// are dropped.
+func (h *EventHandler) storeSnowplow(c *fiber.Ctx, events []map[string]string) error {
+	received := time.Now()
+	client := clientInfo(c)
+
+	var inputs []usecase.StoreEventInput
+	for _, params := range events {
+		in, ok := h.snowplowInput(params, received)
+		if !ok {
+			continue
+		}
+		in.Channel = h.snowplowChannel(params)
+		in.Client = client
+		inputs = append(inputs, in)
+	}
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	// Failed events are retried by the tracker along with the others, which
+	// dedupe catches.
+	res, err := h.storeUC.BulkCreateEvents(c.UserContext(), usecase.BulkCreateEventsInput{Events: inputs})
+	if err == nil && res.Failed > 0 {
+		err = fmt.Errorf("%d events could not be stored", res.Failed)
+	}
+	return err
+}
+
+func (h *EventHandler) snowplowChannel(params map[string]string) string {
+	if ch, ok := h.snowplowChannels[params["aid"]]; ok && params["aid"] != "" {
+		return ch
+	}
+	if ch, ok := snowplowPlatformChannels[params["p"]]; ok {
+		return ch
+	}
+	return "web"
+}
+
+// snowplowInput translates the tracker protocol parameters of one event.
+// ok is false for event types that are not stored.
+func (h *EventHandler) snowplowInput(params map[string]string, received time.Time) (in usecase.StoreEventInput, ok bool) {
+	metadata := map[string]any{}
+
+	switch params["e"] {
+	case "se":
+		in.EventName = params["se_ac"]
+		setNonEmpty(metadata, "category", params["se_ca"])
+		setNonEmpty(metadata, "label", params["se_la"])
+		setNonEmpty(metadata, "property", params["se_pr"])
+		if v, err := strconv.ParseFloat(params["se_va"], 64); err == nil {
+			in.Value = &v
+		}
+	case "pv":
+		in.EventName = "page_view"
+		setNonEmpty(metadata, "url", params["url"])
+		setNonEmpty(metadata, "page_title", params["page"])
+		setNonEmpty(metadata, "referrer", params["refr"])
+	case "ue":
+		name, data, ok := h.snowplowSelfDescribing(params)
+		if !ok {
+			return in, false
+		}
+		in.EventName = name
+		for k, v := range data {
+			metadata[k] = v
+		}
+	default:
+		return in, false
+	}
+	setNonEmpty(metadata, "app_id", params["aid"])
+
+	in.EventID = params["eid"]
+	in.UserID = params["uid"]
+	in.AnonymousID = params["duid"]
+	if in.AnonymousID == "" {
+		in.AnonymousID = params["nuid"]
+	}
+
+	// The true timestamp is set by the application, the device timestamp by
+	// the tracker.
+	in.TimestampMs = received.UnixMilli()
+	for _, p := range []string{"ttm", "dtm"} {
+		if ms, err := strconv.ParseInt(params[p], 10, 64); err == nil && ms > 0 {
+			in.TimestampMs = ms
+			break
+		}
+	}
+
+	if len(metadata) > 0 {
+		in.Metadata = metadata
+	}
+	return in, true
+}
+
+// snowplowSelfDescribing decodes a self-describing event from ue_pr (JSON) or
+// ue_px (base64). The event name is the name of its schema, e.g.
+// "button_click" for iglu:com.acme/button_click/jsonschema/1-0-0.
+func (h *EventHandler) snowplowSelfDescribing(params map[string]string) (string, map[string]any, bool) {
+	raw := []byte(params["ue_pr"])
+	if px := params["ue_px"]; len(raw) == 0 && px != "" {
+		decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(px, "="))
+		if err != nil {
+			return "", nil, false
+		}
+		raw = decoded
+	}
+
+	var envelope struct {
+		Data struct {
+			Schema string         `json:"schema"`
+			Data   map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if h.preserveNumbers {
+		dec.UseNumber()
+	}
+	if err := dec.Decode(&envelope); err != nil {
+		return "", nil, false
+	}
+	parts := strings.Split(strings.TrimPrefix(envelope.Data.Schema, "iglu:"), "/")
+	if len(parts) != 4 || parts[1] == "" {
+		return "", nil, false
+	}
+	return parts[1], envelope.Data.Data, true
+}
+
+func setNonEmpty(m map[string]any, key, value string) {
+	if value != "" {
+		m[key] = value
+	}
+}
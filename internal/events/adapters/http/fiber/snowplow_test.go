@@ -0,0 +1,144 @@
+package fiber
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"event-metrics-service/internal/events/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func setupSnowplowApp(uc StoreEventUseCase, opts ...HandlerOption) *fiber.App {
+	app := fiber.New()
+	h := NewEventHandler(uc, opts...)
+	app.Get("/i", h.CollectSnowplowPixel)
+	app.Post("/com.snowplowanalytics.snowplow/tp2", h.CollectSnowplow)
+	return app
+}
+
+func TestCollectSnowplowPixel_StructuredEvent(t *testing.T) {
+	uc := &fakeStoreEventUseCase{}
+	app := setupSnowplowApp(uc)
+
+	q := url.Values{
+		"e":     {"se"},
+		"eid":   {"0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42"},
+		"p":     {"mob"},
+		"aid":   {"shop-app"},
+		"uid":   {"u1"},
+		"duid":  {"d-1"},
+		"dtm":   {"1733572800123"},
+		"se_ca": {"basket"},
+		"se_ac": {"add_to_basket"},
+		"se_la": {"sku-1"},
+		"se_va": {"19.99"},
+	}
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/i?"+q.Encode(), nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "image/gif" || len(body) == 0 {
+		t.Fatalf("expected a gif pixel, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	events := uc.LastBulkCreateInput.Events
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	e := events[0]
+	if e.EventName != "add_to_basket" || e.Channel != "mobile" || e.EventID != q.Get("eid") || e.UserID != "u1" || e.AnonymousID != "d-1" {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+	if e.TimestampMs != 1733572800123 || e.Value == nil || *e.Value != 19.99 {
+		t.Fatalf("unexpected time or value: %+v", e)
+	}
+	if e.Metadata["category"] != "basket" || e.Metadata["label"] != "sku-1" || e.Metadata["app_id"] != "shop-app" {
+		t.Fatalf("unexpected metadata: %v", e.Metadata)
+	}
+}
+
+func TestCollectSnowplow_Batch(t *testing.T) {
+	uc := &fakeStoreEventUseCase{}
+	app := setupSnowplowApp(uc, WithSnowplowChannels(map[string]string{"shop-web": "shop"}))
+
+	ue, _ := json.Marshal(map[string]any{
+		"schema": "iglu:com.snowplowanalytics.snowplow/unstruct_event/jsonschema/1-0-0",
+		"data": map[string]any{
+			"schema": "iglu:com.acme/button_click/jsonschema/1-0-0",
+			"data":   map[string]any{"button": "buy", "position": 3},
+		},
+	})
+	resp, body := doRequest(t, app, http.MethodPost, "/com.snowplowanalytics.snowplow/tp2", SnowplowPayload{
+		Schema: "iglu:com.snowplowanalytics.snowplow/payload_data/jsonschema/1-0-4",
+		Data: []map[string]string{
+			{"e": "pv", "p": "web", "aid": "shop-web", "duid": "d-1", "url": "https://shop.example/", "page": "Home"},
+			{"e": "ue", "p": "web", "duid": "d-1", "ue_px": base64.RawURLEncoding.EncodeToString(ue)},
+			{"e": "pp", "p": "web", "duid": "d-1"},
+			{"e": "ue", "p": "web", "duid": "d-1", "ue_pr": "not json"},
+		},
+	})
+	if resp.StatusCode != http.StatusOK || string(body) != "ok" {
+		t.Fatalf("expected 200 ok, got %d %s", resp.StatusCode, body)
+	}
+
+	events := uc.LastBulkCreateInput.Events
+	if len(events) != 2 {
+		t.Fatalf("expected page ping and bad self-describing event to be dropped, got %d events", len(events))
+	}
+	pv := events[0]
+	if pv.EventName != "page_view" || pv.Channel != "shop" || pv.Metadata["url"] != "https://shop.example/" || pv.Metadata["page_title"] != "Home" {
+		t.Fatalf("unexpected page view: %+v", pv)
+	}
+	click := events[1]
+	if click.EventName != "button_click" || click.Channel != "web" || click.Metadata["button"] != "buy" {
+		t.Fatalf("unexpected self-describing event: %+v", click)
+	}
+	if n, ok := click.Metadata["position"].(json.Number); !ok || n.String() != "3" {
+		t.Fatalf("expected numbers to be preserved, got %T", click.Metadata["position"])
+	}
+}
+
+func TestCollectSnowplow_BadRequests(t *testing.T) {
+	app := setupSnowplowApp(&fakeStoreEventUseCase{})
+
+	for _, body := range []any{"nope", SnowplowPayload{Schema: "iglu:x"}} {
+		resp, _ := doRequest(t, app, http.MethodPost, "/com.snowplowanalytics.snowplow/tp2", body)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %v, got %d", body, resp.StatusCode)
+		}
+	}
+
+	app = setupSnowplowApp(&fakeStoreEventUseCase{}, WithMaxBulkEvents(1))
+	resp, _ := doRequest(t, app, http.MethodPost, "/com.snowplowanalytics.snowplow/tp2", SnowplowPayload{
+		Data: []map[string]string{{"e": "pv"}, {"e": "pv"}},
+	})
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413 over the batch limit, got %d", resp.StatusCode)
+	}
+}
+
+func TestCollectSnowplow_StoreFailure(t *testing.T) {
+	uc := &fakeStoreEventUseCase{
+		BulkCreateFunc: func(ctx context.Context, in usecase.BulkCreateEventsInput) (usecase.BulkCreateEventsResult, error) {
+			return usecase.BulkCreateEventsResult{}, errors.New("db down")
+		},
+	}
+	app := setupSnowplowApp(uc)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/i?e=pv&duid=d-1", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected 500 so the tracker retries, got %d", resp.StatusCode)
+	}
+}
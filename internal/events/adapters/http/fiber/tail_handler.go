@@ -0,0 +1,114 @@
+package fiber
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"event-metrics-service/internal/events/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DefaultTailHeartbeat is how often an idle tail sends a comment line, so
+// proxies keep the connection open and a gone client is noticed.
+const DefaultTailHeartbeat = 15 * time.Second
+
+type TailEventsUseCase interface {
+	Subscribe(f usecase.TailFilter) (*usecase.Tail, error)
+}
+
+// TailHandler streams newly accepted events as Server-Sent Events.
+type TailHandler struct {
+	uc        TailEventsUseCase
+	heartbeat time.Duration
+}
+
+func NewTailHandler(uc TailEventsUseCase) *TailHandler {
+	return &TailHandler{uc: uc, heartbeat: DefaultTailHeartbeat}
+}
+
+// TailDroppedResponse is the data of a "dropped" event.
+type TailDroppedResponse struct {
+	Dropped int64 `json:"dropped" example:"12"`
+}
+
+// TailEvents godoc
+// @Summary Live tail of accepted events
+// @Description Streams events as this instance stores them, as Server-Sent Events. Each "event" event carries a
+// @Description StoredEventResponse without the id; duplicates and sampled-out events are not sent. A tail that falls behind
+// @Description loses events and is sent a "dropped" event with their count. Filters must all match; user_id also
+// @Description matches anonymous_id. Only events accepted by the instance serving the tail are seen.
+// @Tags Events
+// @Produce text/event-stream
+// @Param X-Admin-Token header string true "Admin token"
+// @Param event_name query string false "Event name"
+// @Param channel query string false "Channel"
+// @Param user_id query string false "User ID or anonymous ID"
+// @Param campaign_id query string false "Campaign ID"
+// @Param tenant_id query string false "Tenant ID"
+// @Success 200 {string} string "event stream"
+// @Failure 503 {object} ErrorResponse
+// @Router /events/tail [get]
+func (h *TailHandler) TailEvents(c *fiber.Ctx) error {
+	tail, err := h.uc.Subscribe(usecase.TailFilter{
+		EventName:  c.Query("event_name"),
+		Channel:    c.Query("channel"),
+		UserID:     c.Query("user_id"),
+		CampaignID: c.Query("campaign_id"),
+		TenantID:   c.Query("tenant_id"),
+	})
+	if errors.Is(err, usecase.ErrTooManyTails) {
+		return c.Status(http.StatusServiceUnavailable).JSON(ErrorResponse{
+			Error:   "too_many_tails",
+			Message: "the maximum number of live tails is open; retry later",
+		})
+	}
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error:   "internal_error",
+			Message: err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	// Stops nginx from buffering the stream.
+	c.Set("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer tail.Close()
+		ticker := time.NewTicker(h.heartbeat)
+		defer ticker.Stop()
+
+		// The comment gets the headers out before the first event.
+		fmt.Fprint(w, ": tail started\n\n")
+		for {
+			if err := w.Flush(); err != nil {
+				return
+			}
+			select {
+			case e, ok := <-tail.Events:
+				if !ok {
+					return
+				}
+				writeSSE(w, "event", toStoredEventResponse(e))
+			case <-ticker.C:
+				fmt.Fprint(w, ": ping\n\n")
+			}
+			if n := tail.Dropped(); n > 0 {
+				writeSSE(w, "dropped", TailDroppedResponse{Dropped: n})
+			}
+		}
+	})
+	return nil
+}
+
+func writeSSE(w *bufio.Writer, event string, v any) {
+	data, _ := json.Marshal(v)
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
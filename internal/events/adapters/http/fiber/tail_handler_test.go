@@ -0,0 +1,85 @@
+package fiber
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// signallingTails reports each subscription, so a test publishes only once
+// the tail is open.
+type signallingTails struct {
+	*usecase.TailEventsUseCase
+	subscribed chan usecase.TailFilter
+}
+
+func (s *signallingTails) Subscribe(f usecase.TailFilter) (*usecase.Tail, error) {
+	t, err := s.TailEventsUseCase.Subscribe(f)
+	if err == nil {
+		s.subscribed <- f
+	}
+	return t, err
+}
+
+func TestTailEvents_StreamsMatchingEvents(t *testing.T) {
+	tails := &signallingTails{usecase.NewTailEventsUseCase(1), make(chan usecase.TailFilter, 1)}
+	app := fiber.New()
+	app.Get("/events/tail", NewTailHandler(tails).TailEvents)
+
+	go func() {
+		f := <-tails.subscribed
+		if f.EventName != "purchase" || f.TenantID != "team-a" {
+			t.Errorf("unexpected filter: %+v", f)
+		}
+		ctx := context.Background()
+		tails.EventStored(ctx, &domain.Event{EventName: "page_view", TenantID: "team-a"})
+		tails.EventStored(ctx, &domain.Event{EventName: "purchase", UserID: "u1", TenantID: "team-a"})
+		tails.Close()
+	}()
+
+	req := httptest.NewRequest(http.MethodGet, "/events/tail?event_name=purchase&tenant_id=team-a", nil)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if n := strings.Count(string(body), "event: event\n"); n != 1 {
+		t.Fatalf("expected exactly one event, got %d in %q", n, body)
+	}
+	if !strings.Contains(string(body), `"event_name":"purchase"`) || strings.Contains(string(body), `"id":`) {
+		t.Fatalf("unexpected event data: %q", body)
+	}
+}
+
+func TestTailEvents_TooManyTails(t *testing.T) {
+	uc := usecase.NewTailEventsUseCase(1)
+	open, _ := uc.Subscribe(usecase.TailFilter{})
+	defer open.Close()
+
+	app := fiber.New()
+	app.Get("/events/tail", NewTailHandler(uc).TailEvents)
+
+	resp, body := doRequest(t, app, http.MethodGet, "/events/tail", nil)
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", resp.StatusCode)
+	}
+	if !strings.Contains(string(body), "too_many_tails") {
+		t.Fatalf("unexpected body: %s", body)
+	}
+}
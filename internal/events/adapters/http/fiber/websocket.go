@@ -0,0 +1,256 @@
+package fiber
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/usecase"
+	"event-metrics-service/internal/jsonlimit"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// WebSocketIdleTimeout closes connections that send nothing, not even a
+	// pong, for this long. The server pings at half of it.
+	WebSocketIdleTimeout = time.Minute
+
+	defaultWebSocketReadLimit = 1 << 20
+	webSocketWriteTimeout     = 10 * time.Second
+)
+
+// WebSocketGate is consulted for every message before its events are
+// stored, as the ingest middleware is for every HTTP request. ok false
+// rejects the message; retryAfter tells the client when to resend.
+type WebSocketGate func(ctx context.Context) (retryAfter time.Duration, ok bool)
+
+// WithWebSocketGate applies rate limits (and quota accounting) per message
+// on /events/ws, where the HTTP middleware only sees the upgrade request.
+func WithWebSocketGate(gate WebSocketGate) HandlerOption {
+	return func(h *EventHandler) {
+		h.wsGate = gate
+	}
+}
+
+// WithWebSocketReadLimit caps the bytes of one /events/ws message; larger
+// messages close the connection with 1009.
+func WithWebSocketReadLimit(n int) HandlerOption {
+	return func(h *EventHandler) {
+		h.wsReadLimit = n
+	}
+}
+
+// WithWebSocketJSONLimits bounds the JSON of one /events/ws message; the
+// HTTP middleware enforcing them on request bodies never sees messages.
+// Messages over them are acked invalid with json_too_complex.
+func WithWebSocketJSONLimits(l jsonlimit.Limits) HandlerOption {
+	return func(h *EventHandler) {
+		h.wsJSONLimits = l
+	}
+}
+
+// WebSocketEvent is one event sent over /events/ws: an event as for
+// POST /events plus a client chosen ref echoed in its ack.
+// @Description WebSocket event message
+type WebSocketEvent struct {
+	Ref string `json:"ref" example:"42"`
+	CreateEventRequest
+}
+
+// WebSocketAck answers one WebSocketEvent.
+// @Description WebSocket event ack
+type WebSocketAck struct {
+	Ref          string `json:"ref,omitempty" example:"42"`
+	EventID      string `json:"event_id,omitempty"`
+	Status       string `json:"status" example:"created" enums:"created,duplicate,invalid,accepted,sampled,rejected,error"`
+	Error        string `json:"error,omitempty" example:"rate_limited"`
+	Message      string `json:"message,omitempty"`
+	RetryAfterMs int64  `json:"retry_after_ms,omitempty"`
+}
+
+// IngestWebSocket godoc
+// @Summary Stream events over a WebSocket
+// @Description Upgrades to a WebSocket on which the client sends events as JSON text messages, one event object
+// @Description or an array of up to BULK_MAX_EVENTS events per message, each with an optional ref. Every event is
+// @Description answered with one ack message in order: created, duplicate, invalid (with message), accepted,
+// @Description sampled, rejected (rate_limited, with retry_after_ms; resend it) or error (resend it). Browsers
+// @Description cannot set headers on a WebSocket, so the API key may also be passed as api_key.
+// @Tags Events
+// @Param api_key query string false "API key, when X-API-Key cannot be sent"
+// @Param message body WebSocketEvent false "Message format (sent over the socket)"
+// @Success 101 {object} WebSocketAck "Switching Protocols; acks are sent over the socket"
+// @Failure 401 {object} ErrorResponse "missing or unknown API key (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "missing scope events:write"
+// @Failure 426 {object} ErrorResponse "websocket_upgrade_required: not a WebSocket handshake"
+// @Failure 429 {object} ErrorResponse "rate_limited: request rate limit exceeded for this API key"
+// @Router /events/ws [get]
+func (h *EventHandler) IngestWebSocket(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return c.Status(http.StatusUpgradeRequired).JSON(ErrorResponse{
+			Error:   "websocket_upgrade_required",
+			Message: "connect with a WebSocket client",
+		})
+	}
+
+	// c is recycled once the upgrade is answered.
+	ctx := c.UserContext()
+	client := clientInfo(c)
+	return websocket.New(func(conn *websocket.Conn) {
+		h.serveWebSocket(ctx, conn, client)
+	})(c)
+}
+
+func (h *EventHandler) serveWebSocket(ctx context.Context, conn *websocket.Conn, client domain.ClientInfo) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	readLimit := int64(defaultWebSocketReadLimit)
+	if h.wsReadLimit > 0 {
+		readLimit = int64(h.wsReadLimit)
+	}
+	conn.SetReadLimit(readLimit)
+	extendDeadline := func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(WebSocketIdleTimeout))
+	}
+	conn.SetPongHandler(extendDeadline)
+	go func() {
+		ticker := time.NewTicker(WebSocketIdleTimeout / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// WriteControl may run concurrently with the acks written below.
+				if conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(webSocketWriteTimeout)) != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		extendDeadline("")
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			// Messages over the read limit were already answered with 1009.
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+				time.Now().Add(webSocketWriteTimeout))
+			return
+		}
+		for _, ack := range h.ingestWebSocketMessage(ctx, data, client) {
+			conn.SetWriteDeadline(time.Now().Add(webSocketWriteTimeout))
+			if err := conn.WriteJSON(ack); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// ingestWebSocketMessage stores the events of one message and returns an
+// ack per event, in order.
+func (h *EventHandler) ingestWebSocketMessage(ctx context.Context, data []byte, client domain.ClientInfo) []WebSocketAck {
+	data = bytes.TrimSpace(data)
+	if err := h.wsJSONLimits.Check(data); err != nil {
+		return []WebSocketAck{{Status: "invalid", Error: "json_too_complex", Message: err.Error()}}
+	}
+
+	var events []WebSocketEvent
+	var err error
+	if len(data) > 0 && data[0] == '[' {
+		err = h.decodeJSON(data, &events)
+	} else {
+		var e WebSocketEvent
+		err = h.decodeJSON(data, &e)
+		events = []WebSocketEvent{e}
+	}
+	if err != nil {
+		return []WebSocketAck{{Status: "invalid", Error: "invalid_json", Message: err.Error()}}
+	}
+	if len(events) == 0 {
+		return []WebSocketAck{{Status: "invalid", Error: "events_list_required"}}
+	}
+	if h.tooManyEvents(len(events)) {
+		return []WebSocketAck{{
+			Status:  "invalid",
+			Error:   "batch_too_large",
+			Message: fmt.Sprintf("at most %d events per message", h.maxBulkEvents),
+		}}
+	}
+
+	acks := make([]WebSocketAck, len(events))
+	for i, e := range events {
+		acks[i] = WebSocketAck{Ref: e.Ref, EventID: e.EventID}
+	}
+
+	if h.wsGate != nil {
+		if retryAfter, ok := h.wsGate(ctx); !ok {
+			for i := range acks {
+				acks[i].Status = "rejected"
+				acks[i].Error = "rate_limited"
+				acks[i].RetryAfterMs = max(retryAfter.Milliseconds(), 1)
+			}
+			return acks
+		}
+	}
+
+	inputs := make([]usecase.StoreEventInput, len(events))
+	for i, e := range events {
+		inputs[i] = usecase.StoreEventInput{
+			EventID:     e.EventID,
+			EventName:   e.EventName,
+			Channel:     e.Channel,
+			CampaignID:  e.CampaignID,
+			UserID:      e.UserID,
+			AnonymousID: e.AnonymousID,
+			Timestamp:   e.Timestamp,
+			TimestampMs: e.TimestampMs,
+			Value:       e.Value,
+			Tags:        e.Tags,
+			Metadata:    e.Metadata,
+			Client:      client,
+		}
+	}
+	result, err := h.storeUC.BulkCreateEvents(ctx, usecase.BulkCreateEventsInput{Events: inputs})
+	if err != nil {
+		for i := range acks {
+			acks[i].Status = "error"
+			acks[i].Error = "internal_server_error"
+		}
+		return acks
+	}
+	for _, item := range result.Items {
+		if item.Index < 0 || item.Index >= len(acks) {
+			continue
+		}
+		ack := &acks[item.Index]
+		ack.Status = item.Status
+		ack.Message = item.Reason
+		switch item.Status {
+		case usecase.ItemStatusInvalid:
+			ack.Error = "invalid_event"
+		case usecase.ItemStatusFailed:
+			ack.Status = "error"
+			ack.Error = "internal_server_error"
+		}
+		if item.EventID != "" {
+			ack.EventID = item.EventID
+		}
+	}
+	return acks
+}
+
+func (h *EventHandler) decodeJSON(data []byte, out any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if h.preserveNumbers {
+		dec.UseNumber()
+	}
+	return dec.Decode(out)
+}
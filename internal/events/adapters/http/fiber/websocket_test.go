@@ -0,0 +1,158 @@
+package fiber
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/events/core/usecase"
+	"event-metrics-service/internal/jsonlimit"
+
+	"github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+type wsCtxKey struct{}
+
+// startWebSocketApp serves /events/ws behind a middleware tagging the
+// request context, and returns a connected client.
+func startWebSocketApp(t *testing.T, uc StoreEventUseCase, opts ...HandlerOption) *websocket.Conn {
+	t.Helper()
+	app := fiber.New()
+	app.Get("/events/ws", func(c *fiber.Ctx) error {
+		c.SetUserContext(context.WithValue(c.UserContext(), wsCtxKey{}, "key-a"))
+		return c.Next()
+	}, NewEventHandler(uc, opts...).IngestWebSocket)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	go app.Listener(ln)
+	t.Cleanup(func() { app.Shutdown() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, "ws://"+ln.Addr().String()+"/events/ws", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func readAcks(t *testing.T, conn *websocket.Conn, n int) []WebSocketAck {
+	t.Helper()
+	acks := make([]WebSocketAck, n)
+	for i := range acks {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("read ack %d: %v", i, err)
+		}
+		if err := json.Unmarshal(data, &acks[i]); err != nil {
+			t.Fatalf("decode ack %q: %v", data, err)
+		}
+	}
+	return acks
+}
+
+func TestIngestWebSocket_AcksEveryEvent(t *testing.T) {
+	var tagged []any
+	uc := &fakeStoreEventUseCase{
+		BulkCreateFunc: func(ctx context.Context, in usecase.BulkCreateEventsInput) (usecase.BulkCreateEventsResult, error) {
+			tagged = append(tagged, ctx.Value(wsCtxKey{}))
+			res := usecase.BulkCreateEventsResult{}
+			for i, e := range in.Events {
+				status := "created"
+				if e.EventName == "" {
+					status = "invalid"
+				}
+				res.Items = append(res.Items, usecase.BulkItemResult{Index: i, EventID: e.EventID, Status: status})
+			}
+			return res, nil
+		},
+	}
+	conn := startWebSocketApp(t, uc)
+
+	conn.WriteMessage(websocket.TextMessage, []byte(`{"ref":"1","event_name":"jump","channel":"game","user_id":"u1"}`))
+	conn.WriteMessage(websocket.TextMessage, []byte(`[{"ref":"2","event_name":"land"},{"ref":"3"}]`))
+	conn.WriteMessage(websocket.TextMessage, []byte(`not json`))
+
+	acks := readAcks(t, conn, 4)
+	if acks[0].Ref != "1" || acks[0].Status != "created" {
+		t.Fatalf("unexpected ack for the single event: %+v", acks[0])
+	}
+	if acks[1].Ref != "2" || acks[1].Status != "created" || acks[2].Ref != "3" || acks[2].Error != "invalid_event" {
+		t.Fatalf("unexpected acks for the array: %+v", acks[1:3])
+	}
+	if acks[3].Error != "invalid_json" {
+		t.Fatalf("expected invalid_json, got %+v", acks[3])
+	}
+	if len(tagged) != 2 || tagged[0] != "key-a" || tagged[1] != "key-a" {
+		t.Fatalf("expected the request context to reach the use case, got %v", tagged)
+	}
+}
+
+func TestIngestWebSocket_GateRejects(t *testing.T) {
+	called := false
+	uc := &fakeStoreEventUseCase{
+		BulkCreateFunc: func(ctx context.Context, in usecase.BulkCreateEventsInput) (usecase.BulkCreateEventsResult, error) {
+			called = true
+			return usecase.BulkCreateEventsResult{}, nil
+		},
+	}
+	gate := func(ctx context.Context) (time.Duration, bool) { return 1500 * time.Millisecond, false }
+	conn := startWebSocketApp(t, uc, WithWebSocketGate(gate))
+
+	conn.WriteMessage(websocket.TextMessage, []byte(`{"ref":"1","event_name":"jump"}`))
+	ack := readAcks(t, conn, 1)[0]
+	if ack.Ref != "1" || ack.Status != "rejected" || ack.Error != "rate_limited" || ack.RetryAfterMs != 1500 {
+		t.Fatalf("unexpected ack: %+v", ack)
+	}
+	if called {
+		t.Fatalf("expected a rejected message not to be stored")
+	}
+}
+
+func TestIngestWebSocket_JSONLimits(t *testing.T) {
+	called := false
+	uc := &fakeStoreEventUseCase{
+		BulkCreateFunc: func(ctx context.Context, in usecase.BulkCreateEventsInput) (usecase.BulkCreateEventsResult, error) {
+			called = true
+			return usecase.BulkCreateEventsResult{}, nil
+		},
+	}
+	conn := startWebSocketApp(t, uc, WithWebSocketJSONLimits(jsonlimit.Limits{MaxDepth: 2}))
+
+	conn.WriteMessage(websocket.TextMessage, []byte(`{"ref":"1","event_name":"jump","metadata":{"a":{"b":1}}}`))
+	ack := readAcks(t, conn, 1)[0]
+	if ack.Status != "invalid" || ack.Error != "json_too_complex" {
+		t.Fatalf("unexpected ack: %+v", ack)
+	}
+	if called {
+		t.Fatalf("expected a message over the JSON limits not to be stored")
+	}
+}
+
+func TestIngestWebSocket_ReadLimit(t *testing.T) {
+	conn := startWebSocketApp(t, &fakeStoreEventUseCase{}, WithWebSocketReadLimit(16))
+
+	conn.WriteMessage(websocket.TextMessage, []byte(`{"ref":"1","event_name":"jump"}`))
+	_, _, err := conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseMessageTooBig) {
+		t.Fatalf("expected close 1009, got %v", err)
+	}
+}
+
+func TestIngestWebSocket_RequiresUpgrade(t *testing.T) {
+	app := fiber.New()
+	app.Get("/events/ws", NewEventHandler(&fakeStoreEventUseCase{}).IngestWebSocket)
+
+	resp, body := doRequest(t, app, http.MethodGet, "/events/ws", nil)
+	if resp.StatusCode != http.StatusUpgradeRequired {
+		t.Fatalf("expected 426, got %d (%s)", resp.StatusCode, body)
+	}
+}
@@ -0,0 +1,152 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	DefaultClientID = "event-metrics-service"
+	DefaultTimeout  = 30 * time.Second
+)
+
+// Producer publishes stored events as JSON to a single topic. Records are
+// keyed by user (anonymous id for visitors) and partitioned like the Java
+// client does, so a user's events land on one partition in the order they
+// were published. Connections are plaintext; TLS and SASL are not supported.
+type Producer struct {
+	w messageWriter
+}
+
+// messageWriter is the part of kafka.Writer the producer uses.
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+type config struct {
+	clientID string
+	timeout  time.Duration
+}
+
+type Option func(*config)
+
+func WithClientID(id string) Option {
+	return func(c *config) {
+		c.clientID = id
+	}
+}
+
+// WithTimeout bounds each request, including the wait for replica acks.
+func WithTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.timeout = d
+	}
+}
+
+func NewProducer(brokers []string, topic string, opts ...Option) *Producer {
+	cfg := config{clientID: DefaultClientID, timeout: DefaultTimeout}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &Producer{w: &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Murmur2Balancer{},
+		RequiredAcks: kafka.RequireAll,
+		// Publish blocks until its batch is written; waiting the default
+		// second for more messages would only delay it.
+		BatchTimeout: 10 * time.Millisecond,
+		ReadTimeout:  cfg.timeout,
+		WriteTimeout: cfg.timeout,
+		Transport:    &kafka.Transport{ClientID: cfg.clientID},
+	}}
+}
+
+var _ ports.EventPublisherPort = (*Producer)(nil)
+
+// message is the record value, in the shape of GET /events.
+type message struct {
+	ID          int64          `json:"id"`
+	EventID     string         `json:"event_id,omitempty"`
+	EventName   string         `json:"event_name"`
+	Channel     string         `json:"channel"`
+	CampaignID  string         `json:"campaign_id,omitempty"`
+	UserID      string         `json:"user_id"`
+	AnonymousID string         `json:"anonymous_id,omitempty"`
+	EventTime   time.Time      `json:"event_time"`
+	ReceivedAt  time.Time      `json:"received_at"`
+	Value       *float64       `json:"value,omitempty"`
+	Tags        []string       `json:"tags"`
+	Metadata    map[string]any `json:"metadata"`
+	DedupeKey   string         `json:"dedupe_key"`
+	TenantID    string         `json:"tenant_id,omitempty"`
+}
+
+func toMessage(e domain.StoredEvent) (kafka.Message, error) {
+	tags := e.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	value, err := json.Marshal(message{
+		ID:          e.ID,
+		EventID:     e.EventID,
+		EventName:   e.EventName,
+		Channel:     e.Channel,
+		CampaignID:  e.CampaignID,
+		UserID:      e.UserID,
+		AnonymousID: e.AnonymousID,
+		EventTime:   e.EventTime,
+		ReceivedAt:  e.ReceivedAt,
+		Value:       e.Value,
+		Tags:        tags,
+		Metadata:    e.Metadata,
+		DedupeKey:   e.DedupeKey,
+		TenantID:    e.TenantID,
+	})
+	if err != nil {
+		return kafka.Message{}, fmt.Errorf("encode event %d: %w", e.ID, err)
+	}
+
+	key := e.UserID
+	if key == "" {
+		key = e.AnonymousID
+	}
+	return kafka.Message{Key: []byte(key), Value: value, Time: e.ReceivedAt}, nil
+}
+
+// Publish sends events and returns once every partition leader has them
+// acknowledged by all in-sync replicas. On error some events may have been
+// written; publishing them again duplicates them.
+func (p *Producer) Publish(ctx context.Context, events []domain.StoredEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	msgs := make([]kafka.Message, 0, len(events))
+	for _, e := range events {
+		m, err := toMessage(e)
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, m)
+	}
+
+	if err := p.w.WriteMessages(ctx, msgs...); err != nil {
+		return fmt.Errorf("kafka produce: %w", err)
+	}
+	return nil
+}
+
+// Close flushes and closes the broker connections.
+func (p *Producer) Close() error {
+	return p.w.Close()
+}
@@ -0,0 +1,136 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/events/core/domain"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// fakeWriter records the messages of every WriteMessages call.
+type fakeWriter struct {
+	batches [][]kafka.Message
+	err     error
+}
+
+func (f *fakeWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.batches = append(f.batches, msgs)
+	return nil
+}
+
+func (f *fakeWriter) Close() error { return nil }
+
+func stored(id int64, userID string) domain.StoredEvent {
+	return domain.StoredEvent{
+		ID: id,
+		Event: domain.Event{
+			EventName: "purchase",
+			Channel:   "web",
+			UserID:    userID,
+			EventTime: time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC),
+			Tags:      []string{"vip"},
+			Metadata:  map[string]any{"order_id": json.Number("12345678901234567890")},
+			DedupeKey: "dk-" + strconv.FormatInt(id, 10),
+		},
+		ReceivedAt: time.Date(2025, 12, 7, 10, 0, 1, 0, time.UTC),
+	}
+}
+
+func TestProducer_PartitionsLikeJavaClient(t *testing.T) {
+	// murmur2 values from the Java client's Utils.murmur2 tests; the Java
+	// partitioner picks (murmur2 & 0x7fffffff) % partitions.
+	cases := map[string]int32{
+		"21":                         -973932308,
+		"foobar":                     -790332482,
+		"a-little-bit-long-string":   -985981536,
+		"a-little-bit-longer-string": -1486304829,
+		"lkjh234lh9fiuh90y23oiuhsafujhadof229phr9h19h89h8": -58897971,
+		"abc": 479470107,
+	}
+	partitions := []int{0, 1, 2, 3, 4, 5, 6}
+
+	w := NewProducer([]string{"127.0.0.1:9092"}, "events").w.(*kafka.Writer)
+	for key, hash := range cases {
+		want := int(hash&0x7fffffff) % len(partitions)
+		if got := w.Balancer.Balance(kafka.Message{Key: []byte(key)}, partitions...); got != want {
+			t.Errorf("key %q went to partition %d, want %d", key, got, want)
+		}
+	}
+	if w.RequiredAcks != kafka.RequireAll {
+		t.Fatalf("expected acks=all, got %v", w.RequiredAcks)
+	}
+}
+
+func TestProducer_PublishKeysByUser(t *testing.T) {
+	fw := &fakeWriter{}
+	p := &Producer{w: fw}
+
+	anonymous := stored(3, "")
+	anonymous.AnonymousID = "anon-1"
+	events := []domain.StoredEvent{stored(1, "u1"), stored(2, "u2"), anonymous}
+	if err := p.Publish(context.Background(), events); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(fw.batches) != 1 || len(fw.batches[0]) != 3 {
+		t.Fatalf("expected one write of 3 messages, got %v", fw.batches)
+	}
+	for i, want := range []string{"u1", "u2", "anon-1"} {
+		m := fw.batches[0][i]
+		if string(m.Key) != want {
+			t.Fatalf("message %d keyed %q, want %q", i, m.Key, want)
+		}
+		if !m.Time.Equal(events[i].ReceivedAt) {
+			t.Fatalf("message %d timestamped %s, want %s", i, m.Time, events[i].ReceivedAt)
+		}
+		var v message
+		if err := json.Unmarshal(m.Value, &v); err != nil {
+			t.Fatalf("invalid record value: %v", err)
+		}
+		if v.ID != events[i].ID || v.DedupeKey == "" || v.EventName != "purchase" {
+			t.Fatalf("unexpected record %s: %s", m.Key, m.Value)
+		}
+	}
+}
+
+func TestProducer_PublishKeepsLargeIntegers(t *testing.T) {
+	fw := &fakeWriter{}
+	p := &Producer{w: fw}
+
+	if err := p.Publish(context.Background(), []domain.StoredEvent{stored(1, "u1")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var m map[string]json.RawMessage
+	_ = json.Unmarshal(fw.batches[0][0].Value, &m)
+	if got := string(m["metadata"]); got != `{"order_id":12345678901234567890}` {
+		t.Fatalf("unexpected metadata %s", got)
+	}
+}
+
+func TestProducer_PublishError(t *testing.T) {
+	fw := &fakeWriter{err: kafka.UnknownTopicOrPartition}
+	p := &Producer{w: fw}
+
+	err := p.Publish(context.Background(), []domain.StoredEvent{stored(1, "u1")})
+	if !errors.Is(err, kafka.UnknownTopicOrPartition) {
+		t.Fatalf("expected unknown topic error, got %v", err)
+	}
+}
+
+func TestProducer_PublishNothing(t *testing.T) {
+	fw := &fakeWriter{err: errors.New("must not be called")}
+	p := &Producer{w: fw}
+
+	if err := p.Publish(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
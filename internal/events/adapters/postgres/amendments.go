@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+)
+
+var _ ports.EventAmendmentPort = (*EventRepository)(nil)
+
+const insertAmendmentSQL = `
+INSERT INTO event_amendments (event_row_id, changes, reason, amended_by, amended_at)
+VALUES ($1, $2::jsonb, $3, $4, $5)
+RETURNING id`
+
+const listAmendmentsSQL = `
+SELECT id, event_row_id, changes, reason, amended_by, amended_at
+FROM event_amendments
+WHERE event_row_id = $1
+ORDER BY id`
+
+// fieldChange is the JSON form of domain.FieldChange in
+// event_amendments.changes.
+type fieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+func (r *EventRepository) AmendEvent(ctx context.Context, ref ports.EventRef, amend func(e *domain.StoredEvent) (*domain.EventAmendment, error)) (domain.StoredEvent, bool, error) {
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return domain.StoredEvent{}, false, err
+	}
+	txRepo := *r
+	txRepo.db = tx
+
+	e, found, err := txRepo.amendEvent(ctx, ref, amend)
+	if err != nil || !found {
+		_ = tx.Rollback()
+		return domain.StoredEvent{}, found, err
+	}
+	if err := tx.Commit(); err != nil {
+		return domain.StoredEvent{}, false, err
+	}
+	return e, true, nil
+}
+
+func (r *EventRepository) amendEvent(ctx context.Context, ref ports.EventRef, amend func(e *domain.StoredEvent) (*domain.EventAmendment, error)) (domain.StoredEvent, bool, error) {
+	e, found, err := r.getEvent(ctx, ref, "\nFOR UPDATE")
+	if err != nil || !found {
+		return domain.StoredEvent{}, found, err
+	}
+	channel := e.Channel
+	a, err := amend(&e)
+	if err != nil || a == nil {
+		return e, true, err
+	}
+
+	// Counters are per channel; the other corrected fields are not counted.
+	if e.Channel != channel && r.countsEvents() {
+		if _, err := r.db.ExecContext(ctx, r.moveCounterQuery(), e.ID, e.Channel); err != nil {
+			return domain.StoredEvent{}, false, err
+		}
+	}
+
+	var campaignID any
+	if e.CampaignID != "" {
+		campaignID = e.CampaignID
+	}
+	metadata, err := json.Marshal(e.Metadata)
+	if err != nil {
+		return domain.StoredEvent{}, false, err
+	}
+	if _, err := r.db.ExecContext(ctx, r.updateAmendedEventSQL(), e.ID, e.Channel, campaignID, pqStringArray(e.Tags), metadata); err != nil {
+		return domain.StoredEvent{}, false, err
+	}
+
+	changes := make([]fieldChange, len(a.Changes))
+	for i, c := range a.Changes {
+		changes[i] = fieldChange(c)
+	}
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return domain.StoredEvent{}, false, err
+	}
+	rows, err := r.db.QueryContext(ctx, insertAmendmentSQL, e.ID, changesJSON, a.Reason, a.AmendedBy, a.AmendedAt)
+	if err != nil {
+		return domain.StoredEvent{}, false, err
+	}
+	defer rows.Close()
+	if rows.Next() {
+		if err := rows.Scan(&a.ID); err != nil {
+			return domain.StoredEvent{}, false, err
+		}
+	}
+	return e, true, rows.Err()
+}
+
+// updateAmendedEventSQL also rewrites the promoted columns, so they keep
+// matching metadata->>'key'.
+func (r *EventRepository) updateAmendedEventSQL() string {
+	set := "channel = $2, campaign_id = $3, tags = $4::text[], metadata = $5::jsonb"
+	for _, k := range r.promotedKeys {
+		set += ",\n    " + PromotedColumn(k) + " = ($5::jsonb)->>'" + k + "'"
+	}
+	return "\nUPDATE events\nSET " + set + "\nWHERE id = $1"
+}
+
+func (r *EventRepository) ListAmendments(ctx context.Context, ref ports.EventRef) ([]domain.EventAmendment, bool, error) {
+	id := ref.ID
+	if ref.EventID != "" {
+		e, found, err := r.GetEvent(ctx, ref)
+		if err != nil || !found {
+			return nil, found, err
+		}
+		id = e.ID
+	}
+
+	rows, err := r.db.QueryContext(ctx, listAmendmentsSQL, id)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	out := []domain.EventAmendment{}
+	for rows.Next() {
+		var (
+			a       domain.EventAmendment
+			changes []byte
+		)
+		if err := rows.Scan(&a.ID, &a.EventRowID, &changes, &a.Reason, &a.AmendedBy, &a.AmendedAt); err != nil {
+			return nil, false, err
+		}
+		var fcs []fieldChange
+		dec := json.NewDecoder(bytes.NewReader(changes))
+		dec.UseNumber()
+		if err := dec.Decode(&fcs); err != nil {
+			return nil, false, err
+		}
+		for _, c := range fcs {
+			a.Changes = append(a.Changes, domain.FieldChange(c))
+		}
+		a.AmendedAt = a.AmendedAt.UTC()
+		out = append(out, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+	if len(out) > 0 || ref.EventID != "" {
+		return out, true, nil
+	}
+
+	// Without amendments, an id still has to be told apart from a missing
+	// event.
+	_, found, err := r.GetEvent(ctx, ref)
+	return out, found, err
+}
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+)
+
+func TestEventRepository_AmendEvent(t *testing.T) {
+	eventTime := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	var updateQuery string
+	var updateArgs, insertArgs []any
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if strings.Contains(query, "INSERT INTO event_amendments") {
+				insertArgs = args
+				return &fakeRowScanner{rows: [][]any{{int64(7)}}}, nil
+			}
+			if !strings.HasSuffix(query, "WHERE id = $1\nFOR UPDATE") {
+				t.Fatalf("expected the event to be locked:\n%s", query)
+			}
+			return &fakeRowScanner{rows: [][]any{storedEventRow(41, eventTime)}}, nil
+		},
+		ExecFn: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+			updateQuery, updateArgs = query, args
+			return &fakeResult{rowsAffected: 1}, nil
+		},
+	}
+	repo := NewEventRepository(db, WithPromotedMetadataKeys("plan"))
+
+	var amendment *domain.EventAmendment
+	e, found, err := repo.AmendEvent(context.Background(), ports.EventRef{ID: 41}, func(e *domain.StoredEvent) (*domain.EventAmendment, error) {
+		e.CampaignID = "bf-2025"
+		amendment = &domain.EventAmendment{
+			EventRowID: e.ID,
+			Changes:    []domain.FieldChange{{Field: "campaign_id", Before: "", After: "bf-2025"}},
+			Reason:     "wrong campaign",
+			AmendedAt:  eventTime,
+		}
+		return amendment, nil
+	})
+	if err != nil || !found || e.CampaignID != "bf-2025" {
+		t.Fatalf("unexpected result: %+v found=%v err=%v", e, found, err)
+	}
+	if !db.tx.committed {
+		t.Fatalf("expected the transaction to be committed")
+	}
+	if !strings.Contains(updateQuery, "meta_plan = ($5::jsonb)->>'plan'") || updateArgs[0] != int64(41) || updateArgs[2] != "bf-2025" {
+		t.Fatalf("unexpected update %v:\n%s", updateArgs, updateQuery)
+	}
+	var changes []map[string]any
+	if err := json.Unmarshal(insertArgs[1].([]byte), &changes); err != nil || changes[0]["field"] != "campaign_id" {
+		t.Fatalf("unexpected changes %s: %v", insertArgs[1], err)
+	}
+	if amendment.ID != 7 {
+		t.Fatalf("expected the amendment id to be set, got %d", amendment.ID)
+	}
+}
+
+func TestEventRepository_AmendEvent_MovesCounter(t *testing.T) {
+	eventTime := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		name    string
+		channel string
+		opts    []RepositoryOption
+		moved   bool
+	}{
+		{"channel changed", "ios", []RepositoryOption{WithCounters()}, true},
+		{"channel kept", "web", []RepositoryOption{WithCounters()}, false},
+		{"counters off", "ios", nil, false},
+	} {
+		var execs []string
+		db := &fakeDB{
+			QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+				if strings.Contains(query, "INSERT INTO event_amendments") {
+					return &fakeRowScanner{rows: [][]any{{int64(7)}}}, nil
+				}
+				return &fakeRowScanner{rows: [][]any{storedEventRow(41, eventTime)}}, nil
+			},
+			ExecFn: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+				execs = append(execs, query)
+				if strings.Contains(query, "event_counters") && (args[0] != int64(41) || args[1] != tt.channel) {
+					t.Fatalf("%s: unexpected counter args %v", tt.name, args)
+				}
+				return &fakeResult{rowsAffected: 1}, nil
+			},
+		}
+		_, _, err := NewEventRepository(db, tt.opts...).AmendEvent(context.Background(), ports.EventRef{ID: 41}, func(e *domain.StoredEvent) (*domain.EventAmendment, error) {
+			e.Channel = tt.channel
+			return &domain.EventAmendment{EventRowID: e.ID, Reason: "wrong channel", AmendedAt: eventTime}, nil
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		// The counter is moved before the event's channel is updated.
+		moved := len(execs) == 2 && strings.Contains(execs[0], "INSERT INTO event_counters") && strings.Contains(execs[0], "SET events = c.events - d.events")
+		if moved != tt.moved || (!tt.moved && len(execs) != 1) {
+			t.Fatalf("%s: expected the counter moved: %v, got %q", tt.name, tt.moved, execs)
+		}
+	}
+}
+
+func TestEventRepository_AmendEvent_NothingWritten(t *testing.T) {
+	eventTime := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	boom := errors.New("boom")
+
+	for _, amendErr := range []error{nil, boom} {
+		db := &fakeDB{
+			QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+				return &fakeRowScanner{rows: [][]any{storedEventRow(41, eventTime)}}, nil
+			},
+		}
+		_, found, err := NewEventRepository(db).AmendEvent(context.Background(), ports.EventRef{ID: 41}, func(e *domain.StoredEvent) (*domain.EventAmendment, error) {
+			return nil, amendErr
+		})
+		if !errors.Is(err, amendErr) || !found {
+			t.Fatalf("unexpected result: found=%v err=%v", found, err)
+		}
+		if db.execCalled {
+			t.Fatalf("expected nothing written (amend error %v)", amendErr)
+		}
+		if amendErr != nil && !db.tx.rolledBack {
+			t.Fatalf("expected a failed amend to roll back")
+		}
+	}
+}
+
+func TestEventRepository_ListAmendments(t *testing.T) {
+	amendedAt := time.Date(2025, 12, 8, 9, 0, 0, 0, time.UTC)
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if !strings.Contains(query, "FROM event_amendments") || args[0] != int64(41) {
+				t.Fatalf("unexpected query %v:\n%s", args, query)
+			}
+			return &fakeRowScanner{rows: [][]any{{
+				int64(7), int64(41),
+				[]byte(`[{"field":"metadata.order_id","before":12345678901234567890,"after":null}]`),
+				"wrong order", "ops", amendedAt,
+			}}}, nil
+		},
+	}
+
+	amendments, found, err := NewEventRepository(db).ListAmendments(context.Background(), ports.EventRef{ID: 41})
+	if err != nil || !found || len(amendments) != 1 {
+		t.Fatalf("unexpected result: %+v found=%v err=%v", amendments, found, err)
+	}
+	c := amendments[0].Changes[0]
+	if c.Before != json.Number("12345678901234567890") || c.After != nil || amendments[0].Reason != "wrong order" {
+		t.Fatalf("unexpected amendment: %+v", amendments[0])
+	}
+}
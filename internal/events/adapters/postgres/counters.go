@@ -0,0 +1,54 @@
+package postgres
+
+import "fmt"
+
+// uncountSQL takes the events of a CTE off their counters (migration 021).
+// The CTE has the columns of counterColumns, one row per event.
+const uncountSQL = `
+    UPDATE event_counters c
+    SET events = c.events - d.events
+    FROM (
+        SELECT tenant_id, event_name, channel, date_trunc('hour', event_time) AS hour, SUM(weight) AS events
+        FROM %s
+        GROUP BY 1, 2, 3, 4
+    ) d
+    WHERE c.tenant_id = d.tenant_id
+      AND c.event_name = d.event_name
+      AND c.hour = d.hour
+      AND c.channel = d.channel`
+
+// moveCounterSQL moves event $1 from the counter of its channel to that of
+// channel $2. It runs before the event's channel is updated.
+const moveCounterSQL = `
+WITH moved AS (
+    SELECT %s FROM events WHERE id = $1
+), uncounted AS (%s
+)
+INSERT INTO event_counters (tenant_id, event_name, channel, hour, events)
+SELECT tenant_id, event_name, $2, date_trunc('hour', event_time), weight FROM moved
+ON CONFLICT (tenant_id, event_name, hour, channel) DO UPDATE
+SET events = event_counters.events + EXCLUDED.events`
+
+// countsEvents reports whether inserts increment event_counters, so
+// deletes and corrections must keep them in step.
+func (r *EventRepository) countsEvents() bool {
+	return r.counters && r.writes(ColumnCounters)
+}
+
+// counterColumns selects what the counters of an events row are keyed and
+// weighted by, the same way the insert filled them in.
+func (r *EventRepository) counterColumns() string {
+	tenant, weight := "''::text AS tenant_id", "1::double precision"
+	if r.writes(ColumnTenantID) {
+		tenant = "tenant_id"
+	}
+	if r.writes(ColumnSampleRate) {
+		weight = "1 / sample_rate"
+	}
+	return tenant + ", event_name, channel, event_time, " + weight + " AS weight"
+}
+
+// moveCounterQuery is moveCounterSQL for the columns of this repository.
+func (r *EventRepository) moveCounterQuery() string {
+	return fmt.Sprintf(moveCounterSQL, r.counterColumns(), fmt.Sprintf(uncountSQL, "moved"))
+}
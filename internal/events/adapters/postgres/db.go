@@ -5,6 +5,21 @@ import (
 	"database/sql"
 )
 
+type RowScanner interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
 type DB interface {
 	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error)
+	BeginTx(ctx context.Context) (Tx, error)
+}
+
+type Tx interface {
+	DB
+	Commit() error
+	Rollback() error
 }
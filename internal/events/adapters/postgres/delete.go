@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"event-metrics-service/internal/events/core/ports"
+)
+
+var _ ports.EventDeleterPort = (*EventRepository)(nil)
+
+// $2 is the channel, "" matching every channel.
+const deleteFilterWhere = `
+WHERE event_name = $1
+  AND ($2::text = '' OR channel = $2)
+  AND event_time >= $3
+  AND event_time < $4`
+
+const countFilteredEventsSQL = `
+SELECT count(*) FROM events` + deleteFilterWhere
+
+// The claims are released with their events, as on erasure, so corrected
+// events can be sent again at once. With counters, the first %s returns
+// what they are keyed by and the second takes the events off them.
+const deleteFilteredEventsSQL = `
+WITH deleted AS (
+    DELETE FROM events
+    WHERE id IN (
+        SELECT id FROM events` + deleteFilterWhere + `
+        LIMIT $5
+    )
+    RETURNING dedupe_key%s
+), claims AS (
+    DELETE FROM event_dedupe WHERE dedupe_key IN (SELECT dedupe_key FROM deleted)
+)%s
+SELECT count(*) FROM deleted`
+
+func (r *EventRepository) CountEvents(ctx context.Context, f ports.EventDeleteFilter) (int64, error) {
+	return r.queryCount(ctx, countFilteredEventsSQL, f.EventName, f.Channel, f.From, f.To)
+}
+
+func (r *EventRepository) DeleteEvents(ctx context.Context, f ports.EventDeleteFilter, limit int) (int64, error) {
+	return r.queryCount(ctx, r.deleteFilteredEventsQuery(), f.EventName, f.Channel, f.From, f.To, limit)
+}
+
+func (r *EventRepository) deleteFilteredEventsQuery() string {
+	if !r.countsEvents() {
+		return fmt.Sprintf(deleteFilteredEventsSQL, "", "")
+	}
+	return fmt.Sprintf(deleteFilteredEventsSQL,
+		", "+r.counterColumns(),
+		", uncounted AS ("+fmt.Sprintf(uncountSQL, "deleted")+"\n)")
+}
+
+func (r *EventRepository) queryCount(ctx context.Context, query string, args ...any) (int64, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var n int64
+	if rows.Next() {
+		if err := rows.Scan(&n); err != nil {
+			return 0, err
+		}
+	}
+	return n, rows.Err()
+}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/events/core/ports"
+)
+
+func TestEventRepository_DeleteEvents(t *testing.T) {
+	from := time.Date(2025, 12, 7, 0, 0, 0, 0, time.UTC)
+	f := ports.EventDeleteFilter{EventName: "test_event", Channel: "web", From: from, To: from.Add(time.Hour)}
+
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return &fakeRowScanner{rows: [][]any{{int64(500)}}}, nil
+		},
+	}
+	repo := NewEventRepository(db)
+
+	n, err := repo.DeleteEvents(context.Background(), f, 500)
+	if err != nil || n != 500 {
+		t.Fatalf("unexpected result: %d, %v", n, err)
+	}
+	for _, want := range []string{"DELETE FROM events", "LIMIT $5", "DELETE FROM event_dedupe", "($2::text = '' OR channel = $2)", "event_time >= $3", "event_time < $4"} {
+		if !strings.Contains(db.lastQuery, want) {
+			t.Fatalf("expected %q in query:\n%s", want, db.lastQuery)
+		}
+	}
+	if len(db.lastArgs) != 5 || db.lastArgs[0] != "test_event" || db.lastArgs[1] != "web" || db.lastArgs[4] != 500 {
+		t.Fatalf("unexpected args: %v", db.lastArgs)
+	}
+
+	if _, err := repo.CountEvents(context.Background(), f); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(strings.TrimSpace(db.lastQuery), "SELECT count(*) FROM events") || len(db.lastArgs) != 4 {
+		t.Fatalf("unexpected count query %v:\n%s", db.lastArgs, db.lastQuery)
+	}
+}
+
+func TestEventRepository_DeleteEventsDecrementsCounters(t *testing.T) {
+	from := time.Date(2025, 12, 7, 0, 0, 0, 0, time.UTC)
+	f := ports.EventDeleteFilter{EventName: "test_event", From: from, To: from.Add(time.Hour)}
+
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return &fakeRowScanner{rows: [][]any{{int64(2)}}}, nil
+		},
+	}
+
+	if _, err := NewEventRepository(db, WithCounters()).DeleteEvents(context.Background(), f, 500); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"RETURNING dedupe_key, tenant_id, event_name, channel, event_time, 1 / sample_rate AS weight",
+		"uncounted AS (\n    UPDATE event_counters c\n    SET events = c.events - d.events",
+		"FROM deleted\n",
+	} {
+		if !strings.Contains(db.lastQuery, want) {
+			t.Fatalf("expected %q in query:\n%s", want, db.lastQuery)
+		}
+	}
+
+	// While event_counters may not be written, neither do deletes.
+	gate := fakeColumnGate{ColumnSampleRate: true, ColumnTenantID: true}
+	if _, err := NewEventRepository(db, WithCounters(), WithColumnGate(gate)).DeleteEvents(context.Background(), f, 500); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(db.lastQuery, "event_counters") {
+		t.Fatalf("expected counters to be left alone:\n%s", db.lastQuery)
+	}
+}
@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+
+	"github.com/lib/pq"
+)
+
+var _ ports.OutboxPort = (*EventRepository)(nil)
+
+// Only one relay runs at a time, so events are published in outbox order.
+// The lock belongs to the relay transaction and is released with it.
+const lockOutboxSQL = `
+SELECT pg_try_advisory_xact_lock(hashtext('event_outbox'));
+`
+
+const selectOutboxSQL = `
+SELECT id, event_row_id FROM event_outbox
+ORDER BY id
+LIMIT $1;
+`
+
+const deleteOutboxSQL = `
+DELETE FROM event_outbox WHERE id = ANY($1::bigint[]);
+`
+
+// RelayOutbox reads and deletes a batch in one transaction: if publish or
+// the delete fails, the entries stay and are relayed again.
+func (r *EventRepository) RelayOutbox(ctx context.Context, limit int, publish func([]domain.StoredEvent) error) (int, error) {
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	txRepo := *r
+	txRepo.db = tx
+	n, err := txRepo.relayOutbox(ctx, limit, publish)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (r *EventRepository) relayOutbox(ctx context.Context, limit int, publish func([]domain.StoredEvent) error) (int, error) {
+	locked, err := r.tryLockOutbox(ctx)
+	if err != nil || !locked {
+		return 0, err
+	}
+
+	ids, rowIDs, err := r.pendingOutbox(ctx, limit)
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	events, err := r.eventsByID(ctx, rowIDs)
+	if err != nil {
+		return 0, err
+	}
+	if len(events) > 0 {
+		if err := publish(events); err != nil {
+			return 0, err
+		}
+	}
+
+	if _, err := r.db.ExecContext(ctx, deleteOutboxSQL, pq.Array(ids)); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+func (r *EventRepository) tryLockOutbox(ctx context.Context) (bool, error) {
+	rows, err := r.db.QueryContext(ctx, lockOutboxSQL)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	var locked bool
+	if rows.Next() {
+		if err := rows.Scan(&locked); err != nil {
+			return false, err
+		}
+	}
+	return locked, rows.Err()
+}
+
+// pendingOutbox returns the ids of the oldest entries and of their events.
+func (r *EventRepository) pendingOutbox(ctx context.Context, limit int) (ids, rowIDs []int64, err error) {
+	rows, err := r.db.QueryContext(ctx, selectOutboxSQL, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, rowID int64
+		if err := rows.Scan(&id, &rowID); err != nil {
+			return nil, nil, err
+		}
+		ids = append(ids, id)
+		rowIDs = append(rowIDs, rowID)
+	}
+	return ids, rowIDs, rows.Err()
+}
+
+// eventsByID returns the events that still exist, in the order of ids.
+func (r *EventRepository) eventsByID(ctx context.Context, ids []int64) ([]domain.StoredEvent, error) {
+	rows, err := r.db.QueryContext(ctx, r.selectEventsSQL()+"\nWHERE id = ANY($1::bigint[])", pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byID := make(map[int64]domain.StoredEvent, len(ids))
+	for rows.Next() {
+		e, err := scanStoredEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		byID[e.ID] = e
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.StoredEvent, 0, len(byID))
+	for _, id := range ids {
+		if e, ok := byID[id]; ok {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
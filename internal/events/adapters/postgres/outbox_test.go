@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/events/core/domain"
+
+	"github.com/lib/pq"
+)
+
+func TestEventRepository_InsertEvent_WritesOutbox(t *testing.T) {
+	gate := fakeColumnGate{}
+	db := &fakeDB{}
+	repo := NewEventRepository(db, WithOutbox(), WithColumnGate(gate))
+
+	for _, writes := range []bool{false, true} {
+		gate[ColumnAnonymousID] = writes
+		if _, err := repo.InsertEvent(context.Background(), &domain.Event{EventName: "purchase"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, want := range []string{
+			"),\nstored AS (\nINSERT INTO events (",
+			"WHERE EXISTS (SELECT 1 FROM claim)\nRETURNING id\n)\nINSERT INTO event_outbox (event_row_id)\nSELECT id FROM stored;",
+		} {
+			if !strings.Contains(db.lastQuery, want) {
+				t.Fatalf("expected query to contain %q, got:\n%s", want, db.lastQuery)
+			}
+		}
+	}
+
+	// With counters too, the outbox insert stays the final statement.
+	counted := NewEventRepository(db, WithOutbox(), WithCounters())
+	if _, err := counted.InsertEvent(context.Background(), &domain.Event{EventName: "purchase"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(db.lastQuery, "RETURNING id\n),\ncounted AS (\nINSERT INTO event_counters") ||
+		!strings.HasSuffix(db.lastQuery, "\n)\nINSERT INTO event_outbox (event_row_id)\nSELECT id FROM stored;\n") {
+		t.Fatalf("unexpected query:\n%s", db.lastQuery)
+	}
+
+	plain := NewEventRepository(db)
+	if _, err := plain.InsertEvent(context.Background(), &domain.Event{EventName: "purchase"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(db.lastQuery, "event_outbox") {
+		t.Fatalf("expected no outbox write without WithOutbox, got:\n%s", db.lastQuery)
+	}
+}
+
+// outboxDB answers the relay queries: the lock, the pending entries and the
+// events they point at.
+func outboxDB(locked bool, entries [][]any, events [][]any) *fakeDB {
+	return &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			switch {
+			case strings.Contains(query, "pg_try_advisory_xact_lock"):
+				return &fakeRowScanner{rows: [][]any{{locked}}}, nil
+			case strings.Contains(query, "FROM event_outbox"):
+				return &fakeRowScanner{rows: entries}, nil
+			default:
+				return &fakeRowScanner{rows: events}, nil
+			}
+		},
+	}
+}
+
+func TestEventRepository_RelayOutbox(t *testing.T) {
+	eventTime := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	// Entry 3 points at an event that was purged before it was relayed.
+	db := outboxDB(true,
+		[][]any{{int64(1), int64(42)}, {int64(2), int64(41)}, {int64(3), int64(40)}},
+		[][]any{storedEventRow(41, eventTime), storedEventRow(42, eventTime)},
+	)
+	repo := NewEventRepository(db)
+
+	var published []int64
+	n, err := repo.RelayOutbox(context.Background(), 10, func(events []domain.StoredEvent) error {
+		for _, e := range events {
+			published = append(published, e.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("expected 3 entries relayed, got %d", n)
+	}
+	if len(published) != 2 || published[0] != 42 || published[1] != 41 {
+		t.Fatalf("expected events 42, 41 in outbox order, got %v", published)
+	}
+	if !strings.Contains(db.lastQuery, "DELETE FROM event_outbox") {
+		t.Fatalf("expected relayed entries to be deleted, got:\n%s", db.lastQuery)
+	}
+	if ids := db.lastArgs[0].(*pq.Int64Array); len(*ids) != 3 {
+		t.Fatalf("expected entries 1, 2, 3 to be deleted, got %v", *ids)
+	}
+	if !db.tx.committed {
+		t.Fatalf("expected the relay transaction to be committed")
+	}
+}
+
+func TestEventRepository_RelayOutbox_PublishFailureKeepsEntries(t *testing.T) {
+	db := outboxDB(true, [][]any{{int64(1), int64(42)}}, [][]any{storedEventRow(42, time.Now())})
+	repo := NewEventRepository(db)
+
+	n, err := repo.RelayOutbox(context.Background(), 10, func([]domain.StoredEvent) error {
+		return errors.New("broker unavailable")
+	})
+	if err == nil || n != 0 {
+		t.Fatalf("expected the publish error, got n=%d err=%v", n, err)
+	}
+	if db.execCalled {
+		t.Fatalf("expected no delete after a failed publish")
+	}
+	if !db.tx.rolledBack {
+		t.Fatalf("expected the relay transaction to be rolled back")
+	}
+}
+
+func TestEventRepository_RelayOutbox_LockedElsewhere(t *testing.T) {
+	db := outboxDB(false, [][]any{{int64(1), int64(42)}}, nil)
+	db.ExecFn = func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+		t.Fatalf("unexpected statement:\n%s", query)
+		return nil, nil
+	}
+	repo := NewEventRepository(db)
+
+	n, err := repo.RelayOutbox(context.Background(), 10, func([]domain.StoredEvent) error {
+		t.Fatalf("publish must not be called while another relay holds the lock")
+		return nil
+	})
+	if err != nil || n != 0 {
+		t.Fatalf("expected nothing relayed, got n=%d err=%v", n, err)
+	}
+}
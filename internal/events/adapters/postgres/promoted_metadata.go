@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"event-metrics-service/internal/events/core/ports"
+)
+
+// PromotedColumn is the events column mirroring metadata[key]. The metrics
+// reader uses the same naming.
+func PromotedColumn(key string) string {
+	return "meta_" + key
+}
+
+var _ ports.PromotedMetadataPort = (*EventRepository)(nil)
+
+// Keys are validated identifiers (see usecase.ValidatePromotedKeys), so they
+// are safe to interpolate into DDL.
+
+const promotedColumnExistsSQL = `
+SELECT count(*)
+FROM information_schema.columns
+WHERE table_schema = current_schema()
+  AND table_name = 'events'
+  AND column_name = $1`
+
+func (r *EventRepository) PromotedColumnExists(ctx context.Context, key string) (bool, error) {
+	n, err := r.queryCount(ctx, promotedColumnExistsSQL, PromotedColumn(key))
+	return n > 0, err
+}
+
+// IndexPromotedColumn builds the index concurrently so ingest is not blocked.
+func (r *EventRepository) IndexPromotedColumn(ctx context.Context, key string) error {
+	col := PromotedColumn(key)
+	_, err := r.db.ExecContext(ctx, fmt.Sprintf(
+		"CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_events_%s ON events (event_name, %s, event_time)", col, col))
+	return err
+}
+
+const promotedBackfillStateSQL = `
+SELECT last_id, completed_at IS NOT NULL
+FROM promoted_metadata_backfills
+WHERE key = $1`
+
+func (r *EventRepository) PromotedBackfillState(ctx context.Context, key string) (int64, bool, error) {
+	rows, err := r.db.QueryContext(ctx, promotedBackfillStateSQL, key)
+	if err != nil {
+		return 0, false, err
+	}
+	defer rows.Close()
+
+	var lastID int64
+	var done bool
+	if rows.Next() {
+		if err := rows.Scan(&lastID, &done); err != nil {
+			return 0, false, err
+		}
+	}
+	return lastID, done, rows.Err()
+}
+
+// The batch walks the primary key, so each statement touches a bounded id
+// range instead of rescanning for NULL columns, and the cursor is recorded
+// in the same statement. GREATEST keeps it monotonic when instances race.
+const backfillPromotedColumnSQL = `
+WITH batch AS (
+    SELECT id FROM events
+    WHERE id > $2
+    ORDER BY id
+    LIMIT $3
+), filled AS (
+    UPDATE events e SET %[1]s = e.metadata->>$1
+    FROM batch b
+    WHERE e.id = b.id AND e.%[1]s IS NULL AND e.metadata->>$1 IS NOT NULL
+), progress AS (
+    INSERT INTO promoted_metadata_backfills (key, last_id, updated_at)
+    SELECT $1, max(id), now() FROM batch HAVING count(*) > 0
+    ON CONFLICT (key) DO UPDATE SET
+        last_id    = GREATEST(promoted_metadata_backfills.last_id, EXCLUDED.last_id),
+        updated_at = EXCLUDED.updated_at
+)
+SELECT COALESCE(max(id), $2), count(*) FROM batch`
+
+func (r *EventRepository) BackfillPromotedColumn(ctx context.Context, key string, afterID int64, limit int) (int64, int, error) {
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(backfillPromotedColumnSQL, PromotedColumn(key)), key, afterID, limit)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer rows.Close()
+
+	lastID, n := afterID, 0
+	if rows.Next() {
+		if err := rows.Scan(&lastID, &n); err != nil {
+			return 0, 0, err
+		}
+	}
+	return lastID, n, rows.Err()
+}
+
+const completePromotedBackfillSQL = `
+INSERT INTO promoted_metadata_backfills (key, completed_at, updated_at)
+VALUES ($1, now(), now())
+ON CONFLICT (key) DO UPDATE SET
+    completed_at = COALESCE(promoted_metadata_backfills.completed_at, EXCLUDED.completed_at),
+    updated_at   = EXCLUDED.updated_at`
+
+func (r *EventRepository) CompletePromotedBackfill(ctx context.Context, key string) error {
+	_, err := r.db.ExecContext(ctx, completePromotedBackfillSQL, key)
+	return err
+}
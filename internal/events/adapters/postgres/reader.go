@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+
+	"github.com/lib/pq"
+)
+
+var _ ports.EventReaderPort = (*EventRepository)(nil)
+
+const selectEventColumns = `
+SELECT
+    id,
+    COALESCE(event_id::text, ''),
+    event_name,
+    channel,
+    COALESCE(campaign_id, ''),
+    user_id,
+    %s,
+    event_time,
+    received_at,
+    value,
+    tags,
+    metadata,
+    dedupe_key,
+    %s
+FROM events`
+
+// ListEvents pages with a (event_time, id) keyset, so deep pages cost the
+// same as the first one.
+func (r *EventRepository) ListEvents(ctx context.Context, q ports.EventQuery) ([]domain.StoredEvent, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if q.EventName != "" {
+		add("event_name = $%d", q.EventName)
+	}
+	if q.Channel != "" {
+		add("channel = $%d", q.Channel)
+	}
+	if q.UserID != "" {
+		add("user_id = $%d", q.UserID)
+	}
+	if q.CampaignID != "" {
+		add("campaign_id = $%d", q.CampaignID)
+	}
+	if !q.From.IsZero() {
+		add("event_time >= $%d", q.From)
+	}
+	if !q.To.IsZero() {
+		add("event_time < $%d", q.To)
+	}
+	if len(q.Tags) > 0 {
+		add("tags @> $%d::text[]", pq.Array(q.Tags))
+	}
+	if q.After != nil {
+		args = append(args, q.After.EventTime, q.After.ID)
+		conds = append(conds, fmt.Sprintf("(event_time, id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+
+	query := r.selectEventsSQL()
+	if len(conds) > 0 {
+		query += "\nWHERE " + strings.Join(conds, " AND ")
+	}
+	args = append(args, q.Limit)
+	query += fmt.Sprintf("\nORDER BY event_time DESC, id DESC\nLIMIT $%d", len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.StoredEvent
+	for rows.Next() {
+		e, err := scanStoredEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (r *EventRepository) GetEvent(ctx context.Context, ref ports.EventRef) (domain.StoredEvent, bool, error) {
+	return r.getEvent(ctx, ref, "")
+}
+
+// getEvent reads the event matching ref; suffix is appended to the query,
+// e.g. to lock the row.
+func (r *EventRepository) getEvent(ctx context.Context, ref ports.EventRef, suffix string) (domain.StoredEvent, bool, error) {
+	query, arg := r.selectEventsSQL()+eventRefWhere(ref), eventRefArg(ref)
+	rows, err := r.db.QueryContext(ctx, query+suffix, arg)
+	if err != nil {
+		return domain.StoredEvent{}, false, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return domain.StoredEvent{}, false, rows.Err()
+	}
+
+	e, err := scanStoredEvent(rows)
+	if err != nil {
+		return domain.StoredEvent{}, false, err
+	}
+	return e, true, rows.Err()
+}
+
+// eventRefWhere selects the event of ref by $1. Several events share an
+// event_id only when the dedupe window expired in between; it picks the
+// newest.
+func eventRefWhere(ref ports.EventRef) string {
+	if ref.EventID != "" {
+		return "\nWHERE event_id = $1::uuid\nORDER BY id DESC\nLIMIT 1"
+	}
+	return "\nWHERE id = $1"
+}
+
+func eventRefArg(ref ports.EventRef) any {
+	if ref.EventID != "" {
+		return ref.EventID
+	}
+	return ref.ID
+}
+
+func (r *EventRepository) selectEventsSQL() string {
+	anonymousID := "COALESCE(anonymous_id, '')"
+	if r.gate != nil && !r.gate.Reads(ColumnAnonymousID) {
+		anonymousID = "''"
+	}
+	tenantID := "tenant_id"
+	if r.gate != nil && !r.gate.Reads(ColumnTenantID) {
+		tenantID = "''"
+	}
+	return fmt.Sprintf(selectEventColumns, anonymousID, tenantID)
+}
+
+func scanStoredEvent(rows RowScanner) (domain.StoredEvent, error) {
+	var (
+		e        domain.StoredEvent
+		value    sql.NullFloat64
+		tags     pq.StringArray
+		metadata []byte
+	)
+	if err := rows.Scan(
+		&e.ID,
+		&e.EventID,
+		&e.EventName,
+		&e.Channel,
+		&e.CampaignID,
+		&e.UserID,
+		&e.AnonymousID,
+		&e.EventTime,
+		&e.ReceivedAt,
+		&value,
+		&tags,
+		&metadata,
+		&e.DedupeKey,
+		&e.TenantID,
+	); err != nil {
+		return domain.StoredEvent{}, err
+	}
+
+	if value.Valid {
+		v := value.Float64
+		e.Value = &v
+	}
+	e.Tags = []string(tags)
+	e.EventTime = e.EventTime.UTC()
+	e.ReceivedAt = e.ReceivedAt.UTC()
+
+	// UseNumber keeps large integers exact, as on ingest.
+	dec := json.NewDecoder(bytes.NewReader(metadata))
+	dec.UseNumber()
+	if err := dec.Decode(&e.Metadata); err != nil {
+		return domain.StoredEvent{}, fmt.Errorf("decode metadata of event %d: %w", e.ID, err)
+	}
+
+	return e, nil
+}
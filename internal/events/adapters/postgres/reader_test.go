@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/events/core/ports"
+)
+
+func storedEventRow(id int64, eventTime time.Time) []any {
+	return []any{
+		id,
+		"0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42",
+		"purchase",
+		"web",
+		"",
+		"u1",
+		"",
+		eventTime,
+		eventTime.Add(2 * time.Second),
+		12.5,
+		[]byte("{promo,vip}"),
+		[]byte(`{"order_id": 12345678901234567890}`),
+		"dk-1",
+		"team-a",
+	}
+}
+
+func TestEventRepository_ListEvents_FiltersAndCursor(t *testing.T) {
+	from := time.Date(2025, 12, 7, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	after := from.Add(time.Hour)
+	eventTime := from.Add(30 * time.Minute)
+
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return &fakeRowScanner{rows: [][]any{storedEventRow(41, eventTime)}}, nil
+		},
+	}
+	repo := NewEventRepository(db)
+
+	events, err := repo.ListEvents(context.Background(), ports.EventQuery{
+		EventName: "purchase",
+		Channel:   "web",
+		From:      from,
+		To:        to,
+		Tags:      []string{"vip"},
+		After:     &ports.EventCursor{EventTime: after, ID: 42},
+		Limit:     11,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"event_name = $1 AND channel = $2 AND event_time >= $3 AND event_time < $4",
+		"tags @> $5::text[]",
+		"(event_time, id) < ($6, $7)",
+		"ORDER BY event_time DESC, id DESC\nLIMIT $8",
+	} {
+		if !strings.Contains(db.lastQuery, want) {
+			t.Fatalf("expected query to contain %q, got:\n%s", want, db.lastQuery)
+		}
+	}
+	if len(db.lastArgs) != 8 || db.lastArgs[5] != after || db.lastArgs[6] != int64(42) || db.lastArgs[7] != 11 {
+		t.Fatalf("unexpected args: %v", db.lastArgs)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	e := events[0]
+	if e.ID != 41 || e.EventName != "purchase" || e.DedupeKey != "dk-1" || e.TenantID != "team-a" || !e.ReceivedAt.Equal(eventTime.Add(2*time.Second)) {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+	if e.Value == nil || *e.Value != 12.5 {
+		t.Fatalf("unexpected value: %v", e.Value)
+	}
+	if len(e.Tags) != 2 || e.Tags[1] != "vip" {
+		t.Fatalf("unexpected tags: %v", e.Tags)
+	}
+	if got := e.Metadata["order_id"]; got != json.Number("12345678901234567890") {
+		t.Fatalf("expected exact metadata number, got %#v", got)
+	}
+}
+
+func TestEventRepository_ListEvents_GatedAnonymousID(t *testing.T) {
+	db := &fakeDB{}
+	repo := NewEventRepository(db, WithColumnGate(fakeColumnGate{}))
+
+	if _, err := repo.ListEvents(context.Background(), ports.EventQuery{Limit: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(db.lastQuery, "anonymous_id") || strings.Contains(db.lastQuery, "WHERE") {
+		t.Fatalf("expected unfiltered query without anonymous_id, got:\n%s", db.lastQuery)
+	}
+}
+
+func TestEventRepository_GetEvent(t *testing.T) {
+	eventTime := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		ref       ports.EventRef
+		wantWhere string
+		wantArg   any
+	}{
+		{"by event_id", ports.EventRef{EventID: "0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42"}, "WHERE event_id = $1::uuid\nORDER BY id DESC\nLIMIT 1", "0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42"},
+		{"by id", ports.EventRef{ID: 41}, "WHERE id = $1", int64(41)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDB{
+				QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+					return &fakeRowScanner{rows: [][]any{storedEventRow(41, eventTime)}}, nil
+				},
+			}
+
+			e, found, err := NewEventRepository(db).GetEvent(context.Background(), tt.ref)
+			if err != nil || !found {
+				t.Fatalf("unexpected result: found=%v err=%v", found, err)
+			}
+			if e.ID != 41 || e.DedupeKey != "dk-1" {
+				t.Fatalf("unexpected event: %+v", e)
+			}
+			if !strings.Contains(db.lastQuery, tt.wantWhere) || db.lastArgs[0] != tt.wantArg {
+				t.Fatalf("unexpected query %v:\n%s", db.lastArgs, db.lastQuery)
+			}
+		})
+	}
+}
+
+func TestEventRepository_GetEvent_NotFound(t *testing.T) {
+	db := &fakeDB{}
+
+	_, found, err := NewEventRepository(db).GetEvent(context.Background(), ports.EventRef{ID: 7})
+	if err != nil || found {
+		t.Fatalf("expected not found, got found=%v err=%v", found, err)
+	}
+}
@@ -3,6 +3,10 @@ package postgres
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
 	"event-metrics-service/internal/events/core/domain"
 	"event-metrics-service/internal/events/core/ports"
 
@@ -10,31 +14,263 @@ import (
 )
 
 type EventRepository struct {
-	db DB
+	db           DB
+	promotedKeys []string
+	gate         ColumnGate
+	outbox       bool
+	counters     bool
+
+	insertSQL map[insertShape]string
+}
+
+// insertShape selects the insert statement: gated columns are left out
+// while the gate holds them back, sample_rate and tenant_id also when they
+// are the default, and counters while event_counters may not be written.
+type insertShape struct {
+	anonymousID bool
+	sampleRate  bool
+	tenantID    bool
+	counters    bool
+}
+
+// Gated columns, named "table.column". They were added by migrations that
+// may not have reached the database yet during a rolling deploy.
+const (
+	ColumnAnonymousID = "events.anonymous_id"
+	ColumnSampleRate  = "events.sample_rate"
+	ColumnTenantID    = "events.tenant_id"
+	ColumnCounters    = "event_counters.events"
+)
+
+// GatedColumns lists every column this adapter consults the gate for.
+var GatedColumns = []string{ColumnAnonymousID, ColumnSampleRate, ColumnTenantID, ColumnCounters}
+
+// ColumnGate reports whether a gated column may be written or read.
+type ColumnGate interface {
+	Writes(column string) bool
+	Reads(column string) bool
 }
 
-func NewEventRepository(db DB) *EventRepository {
-	return &EventRepository{db: db}
+type RepositoryOption func(*EventRepository)
+
+// WithPromotedMetadataKeys also writes metadata[key] into its promoted
+// column (see PromotedColumn) on every insert. Keys must already be
+// validated and their columns must exist.
+func WithPromotedMetadataKeys(keys ...string) RepositoryOption {
+	return func(r *EventRepository) {
+		r.promotedKeys = keys
+	}
+}
+
+// WithColumnGate only writes gated columns the gate allows, so the same
+// release can run against the schema before and after their migration.
+func WithColumnGate(g ColumnGate) RepositoryOption {
+	return func(r *EventRepository) {
+		r.gate = g
+	}
+}
+
+// WithOutbox also records every created event in event_outbox (migration
+// 014), in the same statement, for RelayOutbox to publish.
+func WithOutbox() RepositoryOption {
+	return func(r *EventRepository) {
+		r.outbox = true
+	}
+}
+
+// WithCounters also increments the hourly event count of every created
+// event in event_counters (migration 021), in the same statement.
+func WithCounters() RepositoryOption {
+	return func(r *EventRepository) {
+		r.counters = true
+	}
+}
+
+func NewEventRepository(db DB, opts ...RepositoryOption) *EventRepository {
+	r := &EventRepository{db: db}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.insertSQL = map[insertShape]string{}
+	for _, anonymousID := range []bool{true, false} {
+		for _, sampleRate := range []bool{true, false} {
+			for _, tenantID := range []bool{true, false} {
+				for _, counters := range []bool{true, false} {
+					shape := insertShape{anonymousID, sampleRate, tenantID, counters}
+					r.insertSQL[shape] = buildInsertEventSQL(r.promotedKeys, shape, r.outbox)
+				}
+			}
+		}
+	}
+	return r
 }
 
 var _ ports.EventRepositoryPort = (*EventRepository)(nil)
 
-// SQL template
+// SQL templates
+//
+// The dedupe key is claimed in event_dedupe first; an existing claim is only
+// taken over once it has expired. The event row is written only when the
+// claim succeeded, so RowsAffected reports created (1) vs duplicate (0).
+const claimDedupeKeySQL = `
+WITH claim AS (
+    INSERT INTO event_dedupe (dedupe_key, expires_at)
+    VALUES ($10, COALESCE($11::timestamptz, 'infinity'))
+    ON CONFLICT (dedupe_key) DO UPDATE
+        SET expires_at = EXCLUDED.expires_at
+        WHERE event_dedupe.expires_at <= now()
+    RETURNING dedupe_key
+)`
+
+// insertEventSQL is filled in with the columns and values of the shape.
 const insertEventSQL = `
 INSERT INTO events (
-    event_name,
-    channel,
-    campaign_id,
-    user_id,
-    event_time,
-    tags,
-    metadata,
-    dedupe_key
-) VALUES (
-    $1, $2, $3, $4,
-    $5, $6, $7, $8
+    %s
 )
-ON CONFLICT (dedupe_key) DO NOTHING;
+SELECT
+    %s
+WHERE EXISTS (SELECT 1 FROM claim)`
+
+// insertOutboxSQL records the stored row in event_outbox.
+const insertOutboxSQL = `
+INSERT INTO event_outbox (event_row_id)
+SELECT id FROM stored`
+
+// countersUpsertSQL increments the counter of the stored event, if any. The
+// tenant and weight are filled in from the parameters of the shape.
+const countersUpsertSQL = `
+INSERT INTO event_counters (tenant_id, event_name, channel, hour, events)
+SELECT %s, $2, $3, date_trunc('hour', $6::timestamptz), %s FROM stored
+ON CONFLICT (tenant_id, event_name, hour, channel) DO UPDATE
+SET events = event_counters.events + EXCLUDED.events`
+
+var (
+	insertBaseColumns = []string{
+		"event_id", "event_name", "channel", "campaign_id", "user_id",
+		"event_time", "value", "tags", "metadata", "dedupe_key",
+	}
+	insertBaseValues = []string{
+		"$1::uuid", "$2", "$3", "$4", "$5",
+		"$6::timestamptz", "$7::double precision", "$8::text[]", "$9::jsonb", "$10",
+	}
+)
+
+// insertParts are the pieces of the insert statement of one shape.
+type insertParts struct {
+	columns []string
+	values  []string
+	tenant  string // event_counters.tenant_id of the stored event
+	weight  string // event_counters.events increment of the stored event
+}
+
+// buildInsertParts numbers the optional parameters of shape after $11, the
+// dedupe expiry: anonymous_id, sample_rate and tenant_id, in that order and
+// only when in the shape. Promoted keys take one column each, extracted with
+// ->> from the metadata parameter so the column always matches
+// metadata->>'key'.
+func buildInsertParts(keys []string, shape insertShape) insertParts {
+	p := insertParts{
+		columns: append([]string(nil), insertBaseColumns...),
+		values:  append([]string(nil), insertBaseValues...),
+		tenant:  "''",
+		weight:  "1",
+	}
+	next := 12
+	add := func(column, value string) {
+		p.columns = append(p.columns, column)
+		p.values = append(p.values, value)
+	}
+	if shape.anonymousID {
+		add("anonymous_id", fmt.Sprintf("$%d", next))
+		next++
+	}
+	if shape.sampleRate {
+		p.weight = fmt.Sprintf("1 / $%d::double precision", next)
+		add("sample_rate", fmt.Sprintf("$%d::double precision", next))
+		next++
+	}
+	if shape.tenantID {
+		p.tenant = fmt.Sprintf("$%d", next)
+		add("tenant_id", p.tenant)
+	}
+	for _, k := range keys {
+		add(PromotedColumn(k), "($9::jsonb)->>'"+k+"'")
+	}
+	return p
+}
+
+// buildInsertEventSQL assembles the insert statement of shape. The events
+// insert becomes the stored CTE when its row feeds event_outbox or
+// event_counters; one statement needs no transaction to keep them in step,
+// and RowsAffected of the final insert still reports created (1) vs
+// duplicate (0). Concurrent inserts of the same counter wait for each
+// other's row lock, held until their transaction ends.
+func buildInsertEventSQL(keys []string, shape insertShape, outbox bool) string {
+	p := buildInsertParts(keys, shape)
+	events := fmt.Sprintf(insertEventSQL,
+		strings.Join(p.columns, ",\n    "),
+		strings.Join(p.values[:5], ", ")+",\n    "+strings.Join(p.values[5:], ", "))
+
+	var b strings.Builder
+	b.WriteString(claimDedupeKeySQL)
+	if !outbox && !shape.counters {
+		b.WriteString(events + ";\n")
+		return b.String()
+	}
+
+	b.WriteString(",\nstored AS (" + events + "\nRETURNING id\n)")
+	upsert := fmt.Sprintf(countersUpsertSQL, p.tenant, p.weight)
+	switch {
+	case outbox && shape.counters:
+		b.WriteString(",\ncounted AS (" + upsert + "\n)" + insertOutboxSQL)
+	case outbox:
+		b.WriteString(insertOutboxSQL)
+	default:
+		b.WriteString(upsert)
+	}
+	b.WriteString(";\n")
+	return b.String()
+}
+
+// Oldest first, so an interrupted run still trims the tail of the table.
+const purgeEventsBeforeSQL = `
+DELETE FROM events
+WHERE id IN (
+    SELECT id FROM events
+    WHERE event_time < $1
+    ORDER BY event_time
+    LIMIT $2
+);
+`
+
+const deleteEventsByIDSQL = `
+DELETE FROM events WHERE id = ANY($1::bigint[]);
+`
+
+const purgeExpiredDedupeSQL = `
+DELETE FROM event_dedupe
+WHERE dedupe_key IN (
+    SELECT dedupe_key FROM event_dedupe
+    WHERE expires_at <= now()
+    LIMIT $1
+);
+`
+
+// warmDedupeSQL walks the primary key index of the live claims, which is what
+// the ON CONFLICT check of insertEventSQL probes.
+const warmDedupeSQL = `
+SELECT count(dedupe_key) FROM event_dedupe
+WHERE expires_at > now();
+`
+
+// originalEventIDSQL finds the event a duplicate was deduplicated against,
+// through idx_events_dedupe_key. Events stored before ids were generated
+// have none.
+const originalEventIDSQL = `
+SELECT COALESCE(event_id::text, '') FROM events
+WHERE dedupe_key = $1
+ORDER BY id DESC
+LIMIT 1;
 `
 
 func (r *EventRepository) InsertEvent(ctx context.Context, e *domain.Event) (bool, error) {
@@ -46,21 +282,58 @@ func (r *EventRepository) InsertEvent(ctx context.Context, e *domain.Event) (boo
 		campaignID = e.CampaignID
 	}
 
+	var eventID any
+	if e.EventID != "" {
+		eventID = e.EventID
+	}
+
+	var anonymousID any
+	if e.AnonymousID != "" {
+		anonymousID = e.AnonymousID
+	}
+
+	var dedupeExpiresAt any
+	if !e.DedupeExpiresAt.IsZero() {
+		dedupeExpiresAt = e.DedupeExpiresAt
+	}
+
 	metadataJSON, err := json.Marshal(e.Metadata)
 	if err != nil {
 		return false, err
 	}
 
-	res, err := r.db.ExecContext(ctx, insertEventSQL,
+	shape := insertShape{
+		anonymousID: r.writes(ColumnAnonymousID),
+		// Without the column a sampled event counts once; see migration 016.
+		sampleRate: e.SampleRate > 0 && e.SampleRate < 1 && r.writes(ColumnSampleRate),
+		tenantID:   e.TenantID != "" && r.writes(ColumnTenantID),
+		counters:   r.counters && r.writes(ColumnCounters),
+	}
+	args := []any{
+		eventID,
 		e.EventName,
 		e.Channel,
 		campaignID,
 		e.UserID,
 		e.EventTime,
+		e.Value,
 		pqStringArray(e.Tags),
 		metadataJSON,
 		e.DedupeKey,
-	)
+		dedupeExpiresAt,
+	}
+	// Postgres rejects parameters the statement does not reference.
+	if shape.anonymousID {
+		args = append(args, anonymousID)
+	}
+	if shape.sampleRate {
+		args = append(args, e.SampleRate)
+	}
+	if shape.tenantID {
+		args = append(args, e.TenantID)
+	}
+
+	res, err := r.db.ExecContext(ctx, r.insertSQL[shape], args...)
 	if err != nil {
 		return false, err
 	}
@@ -71,8 +344,94 @@ func (r *EventRepository) InsertEvent(ctx context.Context, e *domain.Event) (boo
 	}
 
 	// rows == 1  -> new record
-	// rows == 0  -> duplicate (ON CONFLICT DO NOTHING)
-	return rows > 0, nil
+	// rows == 0  -> duplicate (dedupe key claimed and not expired)
+	if rows > 0 {
+		return true, nil
+	}
+	return false, r.originalEventID(ctx, e)
+}
+
+// originalEventID sets e.EventID to that of the stored event e duplicates.
+func (r *EventRepository) originalEventID(ctx context.Context, e *domain.Event) error {
+	rows, err := r.db.QueryContext(ctx, originalEventIDSQL, e.DedupeKey)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	e.EventID = ""
+	if rows.Next() {
+		if err := rows.Scan(&e.EventID); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+var _ ports.DedupeJanitorPort = (*EventRepository)(nil)
+
+func (r *EventRepository) PurgeExpiredDedupeKeys(ctx context.Context, limit int) (int64, error) {
+	res, err := r.db.ExecContext(ctx, purgeExpiredDedupeSQL, limit)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+var _ ports.DedupeWarmerPort = (*EventRepository)(nil)
+
+func (r *EventRepository) WarmDedupeKeys(ctx context.Context) (int64, error) {
+	rows, err := r.db.QueryContext(ctx, warmDedupeSQL)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var n int64
+	if rows.Next() {
+		if err := rows.Scan(&n); err != nil {
+			return 0, err
+		}
+	}
+	return n, rows.Err()
+}
+
+var _ ports.RetentionPort = (*EventRepository)(nil)
+
+func (r *EventRepository) PurgeEventsBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	res, err := r.db.ExecContext(ctx, purgeEventsBeforeSQL, cutoff, limit)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *EventRepository) DeleteEventsByID(ctx context.Context, ids []int64) (int64, error) {
+	res, err := r.db.ExecContext(ctx, deleteEventsByIDSQL, pq.Array(ids))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *EventRepository) WithinTransaction(ctx context.Context, fn func(repo ports.EventRepositoryPort) error) error {
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+
+	txRepo := *r
+	txRepo.db = tx
+	if err := fn(&txRepo); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *EventRepository) writes(column string) bool {
+	return r.gate == nil || r.gate.Writes(column)
 }
 
 func pqStringArray(tags []string) any {
@@ -3,12 +3,15 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
 
 	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
 )
 
 // fakeResult implements sql.Result for tests.
@@ -27,9 +30,37 @@ func (f *fakeResult) RowsAffected() (int64, error) {
 // fakeDB implements DB interface for tests.
 type fakeDB struct {
 	ExecFn     func(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryFn    func(ctx context.Context, query string, args ...any) (RowScanner, error)
+	BeginErr   error
 	lastQuery  string
 	lastArgs   []any
 	execCalled bool
+	tx         *fakeTx
+}
+
+func (f *fakeDB) BeginTx(ctx context.Context) (Tx, error) {
+	if f.BeginErr != nil {
+		return nil, f.BeginErr
+	}
+	f.tx = &fakeTx{fakeDB: f}
+	return f.tx, nil
+}
+
+// fakeTx records commit/rollback and delegates statements to the parent fakeDB.
+type fakeTx struct {
+	*fakeDB
+	committed  bool
+	rolledBack bool
+}
+
+func (t *fakeTx) Commit() error {
+	t.committed = true
+	return nil
+}
+
+func (t *fakeTx) Rollback() error {
+	t.rolledBack = true
+	return nil
 }
 
 func (f *fakeDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
@@ -42,6 +73,66 @@ func (f *fakeDB) ExecContext(ctx context.Context, query string, args ...any) (sq
 	return &fakeResult{rowsAffected: 1}, nil
 }
 
+func (f *fakeDB) QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error) {
+	f.lastQuery = query
+	f.lastArgs = args
+	if f.QueryFn != nil {
+		return f.QueryFn(ctx, query, args...)
+	}
+	return &fakeRowScanner{}, nil
+}
+
+// fakeRowScanner returns rows by assigning values to pointer destinations
+// of the same type.
+type fakeRowScanner struct {
+	rows [][]any
+	i    int
+}
+
+func (f *fakeRowScanner) Next() bool {
+	return f.i < len(f.rows)
+}
+
+func (f *fakeRowScanner) Scan(dest ...any) error {
+	row := f.rows[f.i]
+	if len(dest) != len(row) {
+		return errors.New("dest length mismatch")
+	}
+	for i := range dest {
+		if err := assign(dest[i], row[i]); err != nil {
+			return err
+		}
+	}
+	f.i++
+	return nil
+}
+
+func (f *fakeRowScanner) Err() error   { return nil }
+func (f *fakeRowScanner) Close() error { return nil }
+
+func assign(dest, v any) error {
+	if s, ok := dest.(sql.Scanner); ok {
+		return s.Scan(v)
+	}
+	switch d := dest.(type) {
+	case *int64:
+		*d = v.(int64)
+	case *int:
+		*d = v.(int)
+	case *bool:
+		*d = v.(bool)
+	case *string:
+		*d = v.(string)
+	case *time.Time:
+		*d = v.(time.Time)
+	case *[]byte:
+		*d = v.([]byte)
+	default:
+		return errors.New("unsupported dest type")
+	}
+	return nil
+}
+
 // ------------------------------------------------------------
 // SUCCESS (created)
 // ------------------------------------------------------------
@@ -80,8 +171,44 @@ func TestEventRepository_InsertEvent_Created(t *testing.T) {
 	if !db.execCalled {
 		t.Fatalf("expected ExecContext to be called")
 	}
-	if len(db.lastArgs) != 8 {
-		t.Fatalf("expected 8 args, got %d", len(db.lastArgs))
+	if len(db.lastArgs) != 12 {
+		t.Fatalf("expected 12 args, got %d", len(db.lastArgs))
+	}
+	if db.lastArgs[10] != nil {
+		t.Fatalf("expected NULL dedupe expiry (never expires), got %v", db.lastArgs[10])
+	}
+	if db.lastArgs[0] != nil {
+		t.Fatalf("expected NULL event_id when not supplied, got %v", db.lastArgs[0])
+	}
+}
+
+// ------------------------------------------------------------
+// METADATA NUMBERS ARE STORED VERBATIM
+// ------------------------------------------------------------
+
+func TestEventRepository_InsertEvent_PreservesJSONNumbers(t *testing.T) {
+	db := &fakeDB{}
+	repo := NewEventRepository(db)
+
+	e := &domain.Event{
+		EventName: "order",
+		Channel:   "web",
+		UserID:    "user_1",
+		EventTime: time.Now().UTC(),
+		Metadata:  map[string]any{"order_id": json.Number("9007199254740993")},
+		DedupeKey: "dk",
+	}
+
+	if _, err := repo.InsertEvent(context.Background(), e); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	metadataJSON, ok := db.lastArgs[8].([]byte)
+	if !ok {
+		t.Fatalf("expected metadata arg as []byte, got %T", db.lastArgs[8])
+	}
+	if string(metadataJSON) != `{"order_id":9007199254740993}` {
+		t.Errorf("unexpected metadata json: %s", metadataJSON)
 	}
 }
 
@@ -90,10 +217,17 @@ func TestEventRepository_InsertEvent_Created(t *testing.T) {
 // ------------------------------------------------------------
 
 func TestEventRepository_InsertEvent_Duplicate(t *testing.T) {
+	const original = "019a0b4c-5d6e-7f80-9a1b-2c3d4e5f6a7b"
 	db := &fakeDB{
 		ExecFn: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
 			return &fakeResult{rowsAffected: 0}, nil
 		},
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if !strings.Contains(query, "WHERE dedupe_key = $1") || args[0] != "dk" {
+				t.Fatalf("unexpected lookup %v:\n%s", args, query)
+			}
+			return &fakeRowScanner{rows: [][]any{{original}}}, nil
+		},
 	}
 
 	repo := NewEventRepository(db)
@@ -115,6 +249,9 @@ func TestEventRepository_InsertEvent_Duplicate(t *testing.T) {
 	if created {
 		t.Fatalf("expected created=false for duplicate")
 	}
+	if e.EventID != original {
+		t.Fatalf("expected the original's event_id, got %q", e.EventID)
+	}
 }
 
 // ------------------------------------------------------------
@@ -148,3 +285,504 @@ func TestEventRepository_InsertEvent_Error(t *testing.T) {
 		t.Fatalf("expected created=false on error")
 	}
 }
+
+// ------------------------------------------------------------
+// DEDUPE WINDOW
+// ------------------------------------------------------------
+
+func TestEventRepository_InsertEvent_ClaimsDedupeKeyWithExpiry(t *testing.T) {
+	db := &fakeDB{}
+	repo := NewEventRepository(db)
+
+	expiresAt := time.Now().Add(24 * time.Hour).UTC()
+	e := &domain.Event{
+		EventName:       "product_view",
+		Channel:         "web",
+		UserID:          "user_1",
+		EventTime:       time.Now().UTC(),
+		DedupeKey:       "dk",
+		DedupeExpiresAt: expiresAt,
+	}
+
+	if _, err := repo.InsertEvent(context.Background(), e); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(db.lastQuery, "INSERT INTO event_dedupe") {
+		t.Fatalf("expected dedupe claim in query, got: %s", db.lastQuery)
+	}
+	if !strings.Contains(db.lastQuery, "event_dedupe.expires_at <= now()") {
+		t.Fatalf("expected expired claims to be taken over, got: %s", db.lastQuery)
+	}
+	if got, ok := db.lastArgs[10].(time.Time); !ok || !got.Equal(expiresAt) {
+		t.Fatalf("expected expiry arg %v, got %v", expiresAt, db.lastArgs[10])
+	}
+}
+
+func TestEventRepository_PurgeExpiredDedupeKeys(t *testing.T) {
+	db := &fakeDB{
+		ExecFn: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+			if !strings.Contains(query, "DELETE FROM event_dedupe") {
+				t.Fatalf("unexpected query: %s", query)
+			}
+			if args[0] != 500 {
+				t.Fatalf("expected limit 500, got %v", args[0])
+			}
+			return &fakeResult{rowsAffected: 42}, nil
+		},
+	}
+
+	repo := NewEventRepository(db)
+
+	n, err := repo.PurgeExpiredDedupeKeys(context.Background(), 500)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 42 {
+		t.Fatalf("expected 42 purged, got %d", n)
+	}
+}
+
+func TestEventRepository_WarmDedupeKeys(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if !strings.Contains(query, "FROM event_dedupe") || !strings.Contains(query, "expires_at > now()") {
+				t.Fatalf("unexpected query: %s", query)
+			}
+			return &fakeRowScanner{rows: [][]any{{int64(1200)}}}, nil
+		},
+	}
+
+	n, err := NewEventRepository(db).WarmDedupeKeys(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1200 {
+		t.Fatalf("expected 1200 claims, got %d", n)
+	}
+}
+
+func TestEventRepository_PurgeEventsBefore(t *testing.T) {
+	cutoff := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	db := &fakeDB{
+		ExecFn: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+			if !strings.Contains(query, "DELETE FROM events") || !strings.Contains(query, "event_time < $1") {
+				t.Fatalf("unexpected query: %s", query)
+			}
+			if args[0] != cutoff || args[1] != 1000 {
+				t.Fatalf("unexpected args: %v", args)
+			}
+			return &fakeResult{rowsAffected: 1000}, nil
+		},
+	}
+
+	n, err := NewEventRepository(db).PurgeEventsBefore(context.Background(), cutoff, 1000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1000 {
+		t.Fatalf("expected 1000 purged, got %d", n)
+	}
+}
+
+func TestEventRepository_DeleteEventsByID(t *testing.T) {
+	db := &fakeDB{
+		ExecFn: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+			if !strings.Contains(query, "DELETE FROM events WHERE id = ANY($1::bigint[])") {
+				t.Fatalf("unexpected query: %s", query)
+			}
+			return &fakeResult{rowsAffected: 3}, nil
+		},
+	}
+
+	n, err := NewEventRepository(db).DeleteEventsByID(context.Background(), []int64{1, 2, 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("expected 3 deleted, got %d", n)
+	}
+}
+
+// ------------------------------------------------------------
+// TRANSACTION
+// ------------------------------------------------------------
+
+func TestEventRepository_WithinTransaction_Commit(t *testing.T) {
+	db := &fakeDB{}
+	repo := NewEventRepository(db)
+
+	err := repo.WithinTransaction(context.Background(), func(txRepo ports.EventRepositoryPort) error {
+		_, err := txRepo.InsertEvent(context.Background(), &domain.Event{
+			EventName: "product_view",
+			Channel:   "web",
+			UserID:    "user_1",
+			EventTime: time.Now().UTC(),
+			DedupeKey: "dk",
+		})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if db.tx == nil || !db.tx.committed {
+		t.Fatalf("expected transaction to be committed")
+	}
+	if db.tx.rolledBack {
+		t.Fatalf("expected no rollback")
+	}
+}
+
+func TestEventRepository_WithinTransaction_Rollback(t *testing.T) {
+	db := &fakeDB{}
+	repo := NewEventRepository(db)
+
+	err := repo.WithinTransaction(context.Background(), func(txRepo ports.EventRepositoryPort) error {
+		return errors.New("db error")
+	})
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+	if db.tx == nil || !db.tx.rolledBack {
+		t.Fatalf("expected transaction to be rolled back")
+	}
+	if db.tx.committed {
+		t.Fatalf("expected no commit")
+	}
+}
+
+// ------------------------------------------------------------
+// PROMOTED METADATA COLUMNS
+// ------------------------------------------------------------
+
+func TestEventRepository_InsertEvent_PromotedColumns(t *testing.T) {
+	db := &fakeDB{}
+	repo := NewEventRepository(db, WithPromotedMetadataKeys("product_id", "sku"))
+
+	_, err := repo.InsertEvent(context.Background(), &domain.Event{
+		EventName: "product_view",
+		Metadata:  map[string]any{"product_id": "p1"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"anonymous_id,\n    meta_product_id,\n    meta_sku\n)",
+		"$9::jsonb, $10, $12, ($9::jsonb)->>'product_id', ($9::jsonb)->>'sku'\n",
+	} {
+		if !strings.Contains(db.lastQuery, want) {
+			t.Fatalf("expected query to contain %q, got:\n%s", want, db.lastQuery)
+		}
+	}
+	if len(db.lastArgs) != 12 {
+		t.Fatalf("promoted columns must not add args, got %d", len(db.lastArgs))
+	}
+
+	// Transactions keep the promoted columns.
+	_ = repo.WithinTransaction(context.Background(), func(tx ports.EventRepositoryPort) error {
+		_, err := tx.InsertEvent(context.Background(), &domain.Event{Metadata: map[string]any{}})
+		return err
+	})
+	if !strings.Contains(db.lastQuery, "meta_sku") {
+		t.Fatalf("expected promoted columns inside transaction")
+	}
+}
+
+// ------------------------------------------------------------
+// GATED COLUMNS
+// ------------------------------------------------------------
+
+type fakeColumnGate map[string]bool
+
+func (g fakeColumnGate) Writes(column string) bool {
+	return g[column]
+}
+
+func (g fakeColumnGate) Reads(column string) bool {
+	return g[column]
+}
+
+func TestEventRepository_InsertEvent_GatedAnonymousID(t *testing.T) {
+	gate := fakeColumnGate{}
+	db := &fakeDB{}
+	repo := NewEventRepository(db, WithPromotedMetadataKeys("sku"), WithColumnGate(gate))
+
+	insert := func() {
+		t.Helper()
+		_, err := repo.InsertEvent(context.Background(), &domain.Event{
+			EventName:   "page_view",
+			AnonymousID: "anon-1",
+			Metadata:    map[string]any{},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Schema before the anonymous_id migration.
+	insert()
+	if strings.Contains(db.lastQuery, "anonymous_id") || strings.Contains(db.lastQuery, "$12") {
+		t.Fatalf("expected anonymous_id to be left out, got:\n%s", db.lastQuery)
+	}
+	if !strings.Contains(db.lastQuery, "$10, ($9::jsonb)->>'sku'\n") || len(db.lastArgs) != 11 {
+		t.Fatalf("unexpected legacy insert (%d args):\n%s", len(db.lastArgs), db.lastQuery)
+	}
+
+	// The migration lands; the same repository starts writing the column.
+	gate[ColumnAnonymousID] = true
+	insert()
+	if !strings.Contains(db.lastQuery, "anonymous_id") || len(db.lastArgs) != 12 || db.lastArgs[11] != "anon-1" {
+		t.Fatalf("expected anonymous_id to be written, got %v:\n%s", db.lastArgs, db.lastQuery)
+	}
+}
+
+func TestEventRepository_InsertEvent_SampleRate(t *testing.T) {
+	gate := fakeColumnGate{}
+	db := &fakeDB{}
+	repo := NewEventRepository(db, WithPromotedMetadataKeys("sku"), WithColumnGate(gate))
+
+	insert := func(rate float64) {
+		t.Helper()
+		_, err := repo.InsertEvent(context.Background(), &domain.Event{
+			EventName:  "heartbeat",
+			Metadata:   map[string]any{},
+			SampleRate: rate,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Before migration 016 the rate is dropped.
+	insert(0.1)
+	if strings.Contains(db.lastQuery, "sample_rate") || len(db.lastArgs) != 11 {
+		t.Fatalf("expected sample_rate to be left out, got %v:\n%s", db.lastArgs, db.lastQuery)
+	}
+
+	// Without anonymous_id the rate takes its parameter number.
+	gate[ColumnSampleRate] = true
+	insert(0.1)
+	if !strings.Contains(db.lastQuery, "$10, $12::double precision, ($9::jsonb)->>'sku'\n") || db.lastArgs[11] != 0.1 {
+		t.Fatalf("unexpected insert %v:\n%s", db.lastArgs, db.lastQuery)
+	}
+
+	gate[ColumnAnonymousID] = true
+	insert(0.1)
+	if !strings.Contains(db.lastQuery, "anonymous_id,\n    sample_rate,\n    meta_sku\n)") ||
+		!strings.Contains(db.lastQuery, "$10, $12, $13::double precision, ") || len(db.lastArgs) != 13 {
+		t.Fatalf("unexpected insert %v:\n%s", db.lastArgs, db.lastQuery)
+	}
+
+	// Unsampled events keep the column default.
+	insert(0)
+	if strings.Contains(db.lastQuery, "sample_rate") || len(db.lastArgs) != 12 {
+		t.Fatalf("expected the default sample rate, got %v:\n%s", db.lastArgs, db.lastQuery)
+	}
+}
+
+func TestEventRepository_InsertEvent_TenantID(t *testing.T) {
+	gate := fakeColumnGate{ColumnAnonymousID: true, ColumnSampleRate: true}
+	db := &fakeDB{}
+	repo := NewEventRepository(db, WithColumnGate(gate))
+
+	e := &domain.Event{EventName: "purchase", Metadata: map[string]any{}, SampleRate: 0.5, TenantID: "team-a"}
+
+	// Before migration 017 the tenant is dropped.
+	if _, err := repo.InsertEvent(context.Background(), e); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(db.lastQuery, "tenant_id") || len(db.lastArgs) != 13 {
+		t.Fatalf("expected tenant_id to be left out, got %v:\n%s", db.lastArgs, db.lastQuery)
+	}
+
+	gate[ColumnTenantID] = true
+	if _, err := repo.InsertEvent(context.Background(), e); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(db.lastQuery, "sample_rate,\n    tenant_id\n)") ||
+		!strings.Contains(db.lastQuery, "$13::double precision, $14\n") || db.lastArgs[13] != "team-a" {
+		t.Fatalf("unexpected insert %v:\n%s", db.lastArgs, db.lastQuery)
+	}
+}
+
+func TestEventRepository_BackfillPromotedColumn(t *testing.T) {
+	var query string
+	var args []any
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, q string, a ...any) (RowScanner, error) {
+			query, args = q, a
+			return &fakeRowScanner{rows: [][]any{{int64(1500), 500}}}, nil
+		},
+	}
+	repo := NewEventRepository(db)
+
+	lastID, n, err := repo.BackfillPromotedColumn(context.Background(), "product_id", 1000, 500)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lastID != 1500 || n != 500 {
+		t.Fatalf("expected 500 events up to id 1500, got %d up to %d", n, lastID)
+	}
+	for _, want := range []string{
+		"WHERE id > $2\n    ORDER BY id\n    LIMIT $3",
+		"e.meta_product_id IS NULL AND e.metadata->>$1 IS NOT NULL",
+		"INSERT INTO promoted_metadata_backfills",
+	} {
+		if !strings.Contains(query, want) {
+			t.Fatalf("expected %q in backfill query: %s", want, query)
+		}
+	}
+	if strings.Contains(query, "IN (") {
+		t.Fatalf("expected a keyset batch, not a NULL rescan: %s", query)
+	}
+	if args[0] != "product_id" || args[1] != int64(1000) || args[2] != 500 {
+		t.Fatalf("unexpected args: %v", args)
+	}
+}
+
+func TestEventRepository_PromotedBackfillState(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, q string, a ...any) (RowScanner, error) {
+			if !strings.Contains(q, "FROM promoted_metadata_backfills") || a[0] != "sku" {
+				t.Fatalf("unexpected query %v: %s", a, q)
+			}
+			return &fakeRowScanner{rows: [][]any{{int64(42), true}}}, nil
+		},
+	}
+
+	lastID, done, err := NewEventRepository(db).PromotedBackfillState(context.Background(), "sku")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lastID != 42 || !done {
+		t.Fatalf("expected a completed backfill at id 42, got %d %t", lastID, done)
+	}
+}
+
+func TestEventRepository_PromotedColumnExists(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, q string, a ...any) (RowScanner, error) {
+			if !strings.Contains(q, "information_schema.columns") || a[0] != "meta_sku" {
+				t.Fatalf("unexpected query %v: %s", a, q)
+			}
+			return &fakeRowScanner{rows: [][]any{{int64(0)}}}, nil
+		},
+	}
+
+	ok, err := NewEventRepository(db).PromotedColumnExists(context.Background(), "sku")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Fatalf("expected the column to be missing")
+	}
+}
+
+func TestBuildInsertEventSQL_Shapes(t *testing.T) {
+	const claim = "WITH claim AS (\n    INSERT INTO event_dedupe"
+	tests := []struct {
+		shape   insertShape
+		columns string // after dedupe_key
+		values  string // after $10
+		params  int
+		counter string // tenant and weight of the counters upsert
+	}{
+		{insertShape{true, true, true, false}, ",\n    anonymous_id,\n    sample_rate,\n    tenant_id", ", $12, $13::double precision, $14", 14, ""},
+		{insertShape{true, true, false, false}, ",\n    anonymous_id,\n    sample_rate", ", $12, $13::double precision", 13, ""},
+		{insertShape{true, false, true, false}, ",\n    anonymous_id,\n    tenant_id", ", $12, $13", 13, ""},
+		{insertShape{true, false, false, false}, ",\n    anonymous_id", ", $12", 12, ""},
+		{insertShape{false, true, true, false}, ",\n    sample_rate,\n    tenant_id", ", $12::double precision, $13", 13, ""},
+		{insertShape{false, true, false, false}, ",\n    sample_rate", ", $12::double precision", 12, ""},
+		{insertShape{false, false, true, false}, ",\n    tenant_id", ", $12", 12, ""},
+		{insertShape{false, false, false, false}, "", "", 11, ""},
+		{insertShape{true, true, true, true}, ",\n    anonymous_id,\n    sample_rate,\n    tenant_id", ", $12, $13::double precision, $14", 14, "$14, $2, $3, date_trunc('hour', $6::timestamptz), 1 / $13::double precision"},
+		{insertShape{true, true, false, true}, ",\n    anonymous_id,\n    sample_rate", ", $12, $13::double precision", 13, "'', $2, $3, date_trunc('hour', $6::timestamptz), 1 / $13::double precision"},
+		{insertShape{true, false, true, true}, ",\n    anonymous_id,\n    tenant_id", ", $12, $13", 13, "$13, $2, $3, date_trunc('hour', $6::timestamptz), 1"},
+		{insertShape{true, false, false, true}, ",\n    anonymous_id", ", $12", 12, "'', $2, $3, date_trunc('hour', $6::timestamptz), 1"},
+		{insertShape{false, true, true, true}, ",\n    sample_rate,\n    tenant_id", ", $12::double precision, $13", 13, "$13, $2, $3, date_trunc('hour', $6::timestamptz), 1 / $12::double precision"},
+		{insertShape{false, true, false, true}, ",\n    sample_rate", ", $12::double precision", 12, "'', $2, $3, date_trunc('hour', $6::timestamptz), 1 / $12::double precision"},
+		{insertShape{false, false, true, true}, ",\n    tenant_id", ", $12", 12, "$12, $2, $3, date_trunc('hour', $6::timestamptz), 1"},
+		{insertShape{false, false, false, true}, "", "", 11, "'', $2, $3, date_trunc('hour', $6::timestamptz), 1"},
+	}
+
+	for _, tt := range tests {
+		for _, outbox := range []bool{false, true} {
+			q := buildInsertEventSQL([]string{"sku"}, tt.shape, outbox)
+			name := fmt.Sprintf("%+v outbox=%v", tt.shape, outbox)
+
+			if !strings.HasPrefix(q, "\n"+claim) {
+				t.Fatalf("%s: expected the dedupe claim first, got:\n%s", name, q)
+			}
+			for _, want := range []string{
+				"dedupe_key" + tt.columns + ",\n    meta_sku\n)\nSELECT",
+				"$9::jsonb, $10" + tt.values + ", ($9::jsonb)->>'sku'\nWHERE EXISTS (SELECT 1 FROM claim)",
+			} {
+				if !strings.Contains(q, want) {
+					t.Fatalf("%s: expected query to contain %q, got:\n%s", name, want, q)
+				}
+			}
+			if strings.Contains(q, fmt.Sprintf("$%d", tt.params+1)) || !strings.Contains(q, fmt.Sprintf("$%d", tt.params)) {
+				t.Fatalf("%s: expected parameters up to $%d, got:\n%s", name, tt.params, q)
+			}
+
+			var tail string
+			switch {
+			case outbox && tt.shape.counters:
+				tail = "WHERE EXISTS (SELECT 1 FROM claim)\nRETURNING id\n),\ncounted AS (\nINSERT INTO event_counters (tenant_id, event_name, channel, hour, events)\nSELECT " +
+					tt.counter + " FROM stored\nON CONFLICT (tenant_id, event_name, hour, channel) DO UPDATE\nSET events = event_counters.events + EXCLUDED.events\n)" +
+					"\nINSERT INTO event_outbox (event_row_id)\nSELECT id FROM stored;\n"
+			case outbox:
+				tail = "WHERE EXISTS (SELECT 1 FROM claim)\nRETURNING id\n)\nINSERT INTO event_outbox (event_row_id)\nSELECT id FROM stored;\n"
+			case tt.shape.counters:
+				tail = "WHERE EXISTS (SELECT 1 FROM claim)\nRETURNING id\n)\nINSERT INTO event_counters (tenant_id, event_name, channel, hour, events)\nSELECT " +
+					tt.counter + " FROM stored\nON CONFLICT (tenant_id, event_name, hour, channel) DO UPDATE\nSET events = event_counters.events + EXCLUDED.events;\n"
+			default:
+				tail = "WHERE EXISTS (SELECT 1 FROM claim);\n"
+			}
+			if !strings.HasSuffix(q, tail) {
+				t.Fatalf("%s: expected query to end with %q, got:\n%s", name, tail, q)
+			}
+			if stored := strings.Contains(q, "),\nstored AS (\nINSERT INTO events ("); stored != (outbox || tt.shape.counters) {
+				t.Fatalf("%s: unexpected stored CTE, got:\n%s", name, q)
+			}
+		}
+	}
+}
+
+func TestEventRepository_InsertEvent_IncrementsCounters(t *testing.T) {
+	gate := fakeColumnGate{ColumnAnonymousID: true, ColumnSampleRate: true, ColumnTenantID: true}
+	db := &fakeDB{}
+	repo := NewEventRepository(db, WithCounters(), WithColumnGate(gate))
+
+	e := &domain.Event{EventName: "purchase", Metadata: map[string]any{}, SampleRate: 0.5, TenantID: "team-a"}
+
+	// Before migration 021 nothing is counted.
+	if _, err := repo.InsertEvent(context.Background(), e); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(db.lastQuery, "event_counters") {
+		t.Fatalf("expected no counters while gated, got:\n%s", db.lastQuery)
+	}
+
+	gate[ColumnCounters] = true
+	if _, err := repo.InsertEvent(context.Background(), e); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"RETURNING id\n)\nINSERT INTO event_counters",
+		"SELECT $14, $2, $3, date_trunc('hour', $6::timestamptz), 1 / $13::double precision FROM stored",
+	} {
+		if !strings.Contains(db.lastQuery, want) {
+			t.Fatalf("expected query to contain %q, got:\n%s", want, db.lastQuery)
+		}
+	}
+
+	// Unsampled events of no tenant count once towards ''.
+	if _, err := repo.InsertEvent(context.Background(), &domain.Event{EventName: "purchase", Metadata: map[string]any{}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(db.lastQuery, "SELECT '', $2, $3, date_trunc('hour', $6::timestamptz), 1 FROM stored") {
+		t.Fatalf("unexpected counters upsert:\n%s", db.lastQuery)
+	}
+}
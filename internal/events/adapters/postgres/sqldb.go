@@ -3,8 +3,11 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 )
 
+var ErrNestedTransaction = errors.New("nested transactions are not supported")
+
 type sqlDB struct {
 	db *sql.DB
 }
@@ -16,3 +19,47 @@ func NewSQLDB(db *sql.DB) DB {
 func (s *sqlDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
 	return s.db.ExecContext(ctx, query, args...)
 }
+
+func (s *sqlDB) QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (s *sqlDB) BeginTx(ctx context.Context) (Tx, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlTx{tx: tx}, nil
+}
+
+type sqlTx struct {
+	tx *sql.Tx
+}
+
+func (t *sqlTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return t.tx.ExecContext(ctx, query, args...)
+}
+
+func (t *sqlTx) QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error) {
+	rows, err := t.tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (t *sqlTx) BeginTx(ctx context.Context) (Tx, error) {
+	return nil, ErrNestedTransaction
+}
+
+func (t *sqlTx) Commit() error {
+	return t.tx.Commit()
+}
+
+func (t *sqlTx) Rollback() error {
+	return t.tx.Rollback()
+}
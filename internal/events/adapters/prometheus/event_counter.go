@@ -0,0 +1,164 @@
+package prometheus
+
+import (
+	"context"
+	"sync"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+	"event-metrics-service/internal/telemetry"
+)
+
+const metricEvents = "ems_events_total"
+
+// other labels channels outside the configured list or past the maximum, and
+// event names past the maximum, so label cardinality stays bounded. It is
+// reserved: no channel or event name is expected to be called that.
+const other = "__other__"
+
+// AllEventNames among the event names counts every event name: the first
+// ones seen up to the maximum get their own label, later ones are counted
+// as "__other__".
+const AllEventNames = "*"
+
+// DefaultMaxEventNames is the maximum without WithMaxEventNames.
+const DefaultMaxEventNames = 100
+
+// DefaultMaxChannels is the maximum without WithMaxChannels.
+const DefaultMaxChannels = 20
+
+// EventCounter counts stored events for a configured set of event names,
+// broken down by channel, as business KPI counters. A sampled event counts
+// as the events it stands for.
+type EventCounter struct {
+	reg        *telemetry.Registry
+	eventNames map[string]struct{}
+	channels   map[string]struct{} // empty = the first maxChannels seen get their own label
+
+	all         bool
+	maxNames    int
+	maxChannels int
+
+	mu           sync.Mutex
+	seen         map[string]struct{} // event names labeled under AllEventNames
+	seenChannels map[string]struct{} // channels labeled without a configured list
+}
+
+type EventCounterOption func(*EventCounter)
+
+// WithMaxEventNames labels at most n event names under AllEventNames, on
+// top of the configured ones; n <= 0 means DefaultMaxEventNames.
+func WithMaxEventNames(n int) EventCounterOption {
+	return func(c *EventCounter) {
+		if n > 0 {
+			c.maxNames = n
+		}
+	}
+}
+
+// WithMaxChannels labels at most n channels when no channel list is
+// configured; later ones are counted as "__other__". n <= 0 means
+// DefaultMaxChannels.
+func WithMaxChannels(n int) EventCounterOption {
+	return func(c *EventCounter) {
+		if n > 0 {
+			c.maxChannels = n
+		}
+	}
+}
+
+func NewEventCounter(reg *telemetry.Registry, eventNames, channels []string, opts ...EventCounterOption) *EventCounter {
+	c := &EventCounter{
+		reg:        reg,
+		eventNames: toSet(eventNames),
+		channels:   toSet(channels),
+		maxNames:   DefaultMaxEventNames,
+		seen:       map[string]struct{}{},
+
+		maxChannels:  DefaultMaxChannels,
+		seenChannels: map[string]struct{}{},
+	}
+	if _, ok := c.eventNames[AllEventNames]; ok {
+		c.all = true
+		delete(c.eventNames, AllEventNames)
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	// Start known series at zero so rate() works from the first scrape.
+	for n := range c.eventNames {
+		for _, ch := range channels {
+			c.add(n, ch, 0)
+		}
+	}
+
+	return c
+}
+
+var _ ports.EventObserverPort = (*EventCounter)(nil)
+
+func (c *EventCounter) EventStored(ctx context.Context, e *domain.Event) {
+	name, ok := c.eventName(e.EventName)
+	if !ok {
+		return
+	}
+
+	c.add(name, c.channel(e.Channel), e.Weight())
+}
+
+// channel returns the label of a channel.
+func (c *EventCounter) channel(ch string) string {
+	if len(c.channels) > 0 {
+		if _, ok := c.channels[ch]; !ok {
+			return other
+		}
+		return ch
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.seenChannels[ch]; ok {
+		return ch
+	}
+	if len(c.seenChannels) >= c.maxChannels {
+		return other
+	}
+	c.seenChannels[ch] = struct{}{}
+	return ch
+}
+
+// eventName returns the label of an event name, and false for names not
+// counted.
+func (c *EventCounter) eventName(name string) (string, bool) {
+	if _, ok := c.eventNames[name]; ok {
+		return name, true
+	}
+	if !c.all {
+		return "", false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.seen[name]; ok {
+		return name, true
+	}
+	if len(c.seen) >= c.maxNames {
+		return other, true
+	}
+	c.seen[name] = struct{}{}
+	return name, true
+}
+
+func (c *EventCounter) add(eventName, channel string, delta float64) {
+	c.reg.AddCounter(metricEvents, "Events stored, by event_name and channel.",
+		telemetry.Labels{"event_name": eventName, "channel": channel}, delta)
+}
+
+func toSet(items []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(items))
+	for _, it := range items {
+		set[it] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,101 @@
+package prometheus
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/telemetry"
+)
+
+func TestEventCounter_CountsConfiguredEvents(t *testing.T) {
+	reg := telemetry.NewRegistry()
+	c := NewEventCounter(reg, []string{"order_placed"}, []string{"web", "mobile"})
+
+	ctx := context.Background()
+	c.EventStored(ctx, &domain.Event{EventName: "order_placed", Channel: "web"})
+	c.EventStored(ctx, &domain.Event{EventName: "order_placed", Channel: "web"})
+	c.EventStored(ctx, &domain.Event{EventName: "order_placed", Channel: "kiosk"})
+	c.EventStored(ctx, &domain.Event{EventName: "product_view", Channel: "web"})
+
+	var buf bytes.Buffer
+	if err := reg.WriteOpenMetrics(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		`ems_events_total{channel="web",event_name="order_placed"} 2`,
+		`ems_events_total{channel="mobile",event_name="order_placed"} 0`,
+		`ems_events_total{channel="__other__",event_name="order_placed"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "product_view") {
+		t.Fatalf("unconfigured event names must not be exported, got:\n%s", out)
+	}
+}
+
+func TestEventCounter_CountsEveryEventName(t *testing.T) {
+	reg := telemetry.NewRegistry()
+	c := NewEventCounter(reg, []string{"order_placed", AllEventNames}, nil, WithMaxEventNames(2))
+
+	ctx := context.Background()
+	for _, name := range []string{"order_placed", "signup", "login", "logout", "signup", "order_placed"} {
+		c.EventStored(ctx, &domain.Event{EventName: name, Channel: "web"})
+	}
+
+	var buf bytes.Buffer
+	if err := reg.WriteOpenMetrics(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+
+	// Configured names do not count towards the maximum.
+	for _, want := range []string{
+		`ems_events_total{channel="web",event_name="order_placed"} 2`,
+		`ems_events_total{channel="web",event_name="signup"} 2`,
+		`ems_events_total{channel="web",event_name="login"} 1`,
+		`ems_events_total{channel="web",event_name="__other__"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "logout") {
+		t.Fatalf("event names past the maximum must be counted as other, got:\n%s", out)
+	}
+}
+
+func TestEventCounter_CapsChannelsWithoutList(t *testing.T) {
+	reg := telemetry.NewRegistry()
+	c := NewEventCounter(reg, []string{"order_placed"}, nil, WithMaxChannels(2))
+
+	ctx := context.Background()
+	for _, ch := range []string{"web", "ios", "android", "web", "kiosk"} {
+		c.EventStored(ctx, &domain.Event{EventName: "order_placed", Channel: ch})
+	}
+
+	var buf bytes.Buffer
+	if err := reg.WriteOpenMetrics(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		`ems_events_total{channel="web",event_name="order_placed"} 2`,
+		`ems_events_total{channel="ios",event_name="order_placed"} 1`,
+		`ems_events_total{channel="__other__",event_name="order_placed"} 2`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "android") || strings.Contains(out, "kiosk") {
+		t.Fatalf("channels past the maximum must be counted as __other__, got:\n%s", out)
+	}
+}
@@ -0,0 +1,80 @@
+package slo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"event-metrics-service/internal/events/core/usecase"
+)
+
+// Operation names reported to the SLO tracker.
+const (
+	OperationStoreEvent = "store_event"
+	OperationBulkStore  = "bulk_store_events"
+)
+
+// Outcome labels understood by the SLO tracker.
+const (
+	outcomeOK       = "ok"
+	outcomeRejected = "rejected"
+	outcomeError    = "error"
+)
+
+// Recorder is satisfied by the SLO tracker.
+type Recorder interface {
+	Observe(ctx context.Context, operation, outcome string, d time.Duration)
+}
+
+type StoreEventUseCase interface {
+	Execute(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error)
+	BulkCreateEvents(ctx context.Context, in usecase.BulkCreateEventsInput) (usecase.BulkCreateEventsResult, error)
+}
+
+// StoreEvent decorates the store event use case with SLI measurement.
+type StoreEvent struct {
+	next     StoreEventUseCase
+	recorder Recorder
+	now      func() time.Time
+}
+
+func NewStoreEvent(next StoreEventUseCase, recorder Recorder) *StoreEvent {
+	return &StoreEvent{next: next, recorder: recorder, now: time.Now}
+}
+
+func (s *StoreEvent) Execute(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error) {
+	start := s.now()
+	res, err := s.next.Execute(ctx, in)
+	s.recorder.Observe(ctx, OperationStoreEvent, outcome(err), s.now().Sub(start))
+	return res, err
+}
+
+// BulkCreateEvents measures the whole batch; invalid items inside a
+// successful batch are reported per item, not as a failed operation, but
+// items the repository failed to store fail it.
+func (s *StoreEvent) BulkCreateEvents(ctx context.Context, in usecase.BulkCreateEventsInput) (usecase.BulkCreateEventsResult, error) {
+	start := s.now()
+	res, err := s.next.BulkCreateEvents(ctx, in)
+	o := outcome(err)
+	if err == nil && res.Failed > 0 {
+		o = outcomeError
+	}
+	s.recorder.Observe(ctx, OperationBulkStore, o, s.now().Sub(start))
+	return res, err
+}
+
+func outcome(err error) string {
+	switch {
+	case err == nil, errors.Is(err, usecase.ErrEventSampledOut):
+		return outcomeOK
+	case errors.Is(err, usecase.ErrInvalidEvent),
+		errors.Is(err, usecase.ErrFutureTime),
+		errors.Is(err, usecase.ErrEventTooOld),
+		errors.Is(err, usecase.ErrInvalidTags),
+		errors.Is(err, usecase.ErrSchemaViolation),
+		errors.Is(err, usecase.ErrMetadataTooLarge):
+		return outcomeRejected
+	default:
+		return outcomeError
+	}
+}
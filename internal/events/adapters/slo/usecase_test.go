@@ -0,0 +1,79 @@
+package slo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/events/core/usecase"
+)
+
+type observation struct {
+	operation string
+	outcome   string
+	d         time.Duration
+}
+
+type fakeRecorder struct {
+	got []observation
+}
+
+func (f *fakeRecorder) Observe(ctx context.Context, operation, outcome string, d time.Duration) {
+	f.got = append(f.got, observation{operation, outcome, d})
+}
+
+type fakeStoreEventUseCase struct {
+	err error
+}
+
+func (f *fakeStoreEventUseCase) Execute(ctx context.Context, in usecase.StoreEventInput) (usecase.StoreEventResult, error) {
+	return usecase.StoreEventResult{Created: f.err == nil}, f.err
+}
+
+func (f *fakeStoreEventUseCase) BulkCreateEvents(ctx context.Context, in usecase.BulkCreateEventsInput) (usecase.BulkCreateEventsResult, error) {
+	return usecase.BulkCreateEventsResult{}, f.err
+}
+
+func TestStoreEvent_RecordsOutcomeAndDuration(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, outcomeOK},
+		{fmt.Errorf("%w: tags[0] too long", usecase.ErrInvalidTags), outcomeRejected},
+		{usecase.ErrFutureTime, outcomeRejected},
+		{errors.New("db failure"), outcomeError},
+	}
+
+	for _, tt := range tests {
+		rec := &fakeRecorder{}
+		s := NewStoreEvent(&fakeStoreEventUseCase{err: tt.err}, rec)
+		start := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+		calls := 0
+		s.now = func() time.Time {
+			calls++
+			return start.Add(time.Duration(calls) * 40 * time.Millisecond)
+		}
+
+		if _, err := s.Execute(context.Background(), usecase.StoreEventInput{}); !errors.Is(err, tt.err) {
+			t.Fatalf("expected error to pass through, got %v", err)
+		}
+		if len(rec.got) != 1 || rec.got[0] != (observation{OperationStoreEvent, tt.want, 40 * time.Millisecond}) {
+			t.Fatalf("%v: unexpected observations: %+v", tt.err, rec.got)
+		}
+	}
+}
+
+func TestStoreEvent_BulkIsSeparateOperation(t *testing.T) {
+	rec := &fakeRecorder{}
+	s := NewStoreEvent(&fakeStoreEventUseCase{}, rec)
+
+	if _, err := s.BulkCreateEvents(context.Background(), usecase.BulkCreateEventsInput{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rec.got) != 1 || rec.got[0].operation != OperationBulkStore || rec.got[0].outcome != outcomeOK {
+		t.Fatalf("unexpected observations: %+v", rec.got)
+	}
+}
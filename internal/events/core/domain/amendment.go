@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// EventAmendment is the audit record of one correction to a stored event.
+type EventAmendment struct {
+	ID         int64
+	EventRowID int64         // events.id
+	Changes    []FieldChange // only the fields that changed
+	Reason     string
+	AmendedBy  string
+	AmendedAt  time.Time
+}
+
+// FieldChange is a field's value before and after an amendment. Metadata is
+// recorded per key, as "metadata.<key>", with nil for an absent key.
+type FieldChange struct {
+	Field  string
+	Before any
+	After  any
+}
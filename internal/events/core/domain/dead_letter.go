@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// DeadLetter is an event whose insert kept failing, kept with the last error
+// so it can be re-driven once the database recovers.
+type DeadLetter struct {
+	ID            string
+	Event         Event // as it was about to be inserted, after enrichment
+	Error         string
+	Attempts      int       // inserts tried, re-drives included
+	FailedAt      time.Time // first failure
+	LastAttemptAt time.Time
+}
@@ -3,12 +3,50 @@ package domain
 import "time"
 
 type Event struct {
-	EventName  string
-	Channel    string
-	CampaignID string
-	UserID     string
-	EventTime  time.Time
-	Tags       []string
-	Metadata   map[string]any
-	DedupeKey  string
+	EventID     string // optional client-supplied UUID
+	EventName   string
+	Channel     string
+	CampaignID  string
+	UserID      string
+	AnonymousID string // not yet identified visitor; UserID may be empty when set
+	EventTime   time.Time
+	Value       *float64 // optional numeric measurement (histograms)
+	Tags        []string
+	Metadata    map[string]any // numbers may be json.Number to keep large integers exact
+	DedupeKey   string
+
+	// DedupeExpiresAt bounds how long DedupeKey rejects identical events.
+	// Zero means the key never expires.
+	DedupeExpiresAt time.Time
+
+	// SampleRate is the share of events with this name that were kept when
+	// the event was sampled; 0 means it was not sampled.
+	SampleRate float64
+
+	// TenantID is the tenant of the API key that sent the event, when tenant
+	// isolation is on; empty otherwise.
+	TenantID string
+}
+
+// Weight is the number of events e stands for: 1/SampleRate for a sampled
+// event, 1 otherwise.
+func (e Event) Weight() float64 {
+	if e.SampleRate > 0 && e.SampleRate < 1 {
+		return 1 / e.SampleRate
+	}
+	return 1
+}
+
+// ClientInfo describes the HTTP client that sent an event. It is only used
+// for enrichment and is never stored as is.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// StoredEvent is an event as persisted, including server-assigned fields.
+type StoredEvent struct {
+	Event
+	ID         int64     // events.id
+	ReceivedAt time.Time // server time the event was stored
 }
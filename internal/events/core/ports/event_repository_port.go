@@ -2,6 +2,8 @@ package ports
 
 import (
 	"context"
+	"time"
+
 	"event-metrics-service/internal/events/core/domain"
 )
 
@@ -10,5 +12,187 @@ type EventRepositoryPort interface {
 	//   created = true,  err = nil  -> new record
 	//   created = false, err = nil  -> duplicate (idempotent)
 	//   created = false, err != nil -> DB error
+	// On a duplicate, e.EventID is set to the event_id of the stored event
+	// ("" when it has none).
 	InsertEvent(ctx context.Context, e *domain.Event) (created bool, err error)
+
+	// WithinTransaction runs fn against a repository bound to a single
+	// transaction. The transaction is committed when fn returns nil and
+	// rolled back otherwise.
+	WithinTransaction(ctx context.Context, fn func(repo EventRepositoryPort) error) error
+}
+
+type DedupeJanitorPort interface {
+	// PurgeExpiredDedupeKeys deletes up to limit expired dedupe keys and
+	// returns how many were removed.
+	PurgeExpiredDedupeKeys(ctx context.Context, limit int) (int64, error)
+}
+
+type DedupeWarmerPort interface {
+	// WarmDedupeKeys reads the live dedupe claims so the first inserts after
+	// a restart do not pay for cold index pages. It returns how many claims
+	// were read.
+	WarmDedupeKeys(ctx context.Context) (int64, error)
+}
+
+type RetentionPort interface {
+	// PurgeEventsBefore deletes up to limit events whose event_time is before
+	// cutoff and returns how many were removed.
+	PurgeEventsBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error)
+	// DeleteEventsByID deletes the given events and returns how many were
+	// removed.
+	DeleteEventsByID(ctx context.Context, ids []int64) (int64, error)
+}
+
+// EventDeleteFilter selects events to delete. Channel may be empty; the
+// time range is on event_time, From inclusive and To exclusive.
+type EventDeleteFilter struct {
+	EventName string
+	Channel   string
+	From      time.Time
+	To        time.Time
+}
+
+// EventDeleterPort removes events matching a filter, e.g. junk from a bad
+// release.
+type EventDeleterPort interface {
+	CountEvents(ctx context.Context, f EventDeleteFilter) (int64, error)
+	// DeleteEvents deletes up to limit matching events, with their dedupe
+	// claims, and returns how many events were removed.
+	DeleteEvents(ctx context.Context, f EventDeleteFilter, limit int) (int64, error)
+}
+
+// ArchiverPort copies events to long-term storage before retention deletes
+// them.
+type ArchiverPort interface {
+	// ArchiveEventsBefore archives up to limit of the oldest events whose
+	// event_time is before cutoff and returns the ids archived. Only those
+	// may be deleted.
+	ArchiveEventsBefore(ctx context.Context, cutoff time.Time, limit int) ([]int64, error)
+}
+
+// PromotedMetadataPort maintains real columns mirroring hot metadata keys.
+// The columns are added by migrations; the backfill progress is stored so
+// every instance sees the same state.
+type PromotedMetadataPort interface {
+	// PromotedColumnExists reports whether the column for key exists; events
+	// are only written with the key promoted once it does.
+	PromotedColumnExists(ctx context.Context, key string) (bool, error)
+	// IndexPromotedColumn creates the column's query index if it is missing.
+	IndexPromotedColumn(ctx context.Context, key string) error
+	// PromotedBackfillState returns the event id the backfill of key has
+	// reached and whether it is complete.
+	PromotedBackfillState(ctx context.Context, key string) (lastID int64, done bool, err error)
+	// BackfillPromotedColumn fills the column for up to limit events with an
+	// id above afterID, records the progress and returns the last id and the
+	// number of events it scanned.
+	BackfillPromotedColumn(ctx context.Context, key string, afterID int64, limit int) (lastID int64, n int, err error)
+	// CompletePromotedBackfill marks the backfill of key as done.
+	CompletePromotedBackfill(ctx context.Context, key string) error
+}
+
+// EventObserverPort is notified about events after they have been stored.
+// Implementations must be fast and must not fail the ingest path.
+type EventObserverPort interface {
+	EventStored(ctx context.Context, e *domain.Event)
+}
+
+// EnricherPort derives metadata fields at ingest time. The returned fields
+// are merged into the event metadata without overwriting keys sent by the
+// producer; returning nil adds nothing. Enrichers must not fail the ingest
+// path.
+type EnricherPort interface {
+	Enrich(ctx context.Context, e *domain.Event, client domain.ClientInfo) map[string]any
+}
+
+// MetadataSchemaPort checks metadata against the contract registered for an
+// event name. Events without a registered schema always pass.
+type MetadataSchemaPort interface {
+	ValidateMetadata(eventName string, metadata map[string]any) []SchemaViolation
+}
+
+// SchemaViolation points at a metadata field that breaks the schema.
+type SchemaViolation struct {
+	Field   string // e.g. "metadata.items[0].sku"
+	Message string
+}
+
+// EventQuery selects stored events, newest first. Empty fields do not
+// filter.
+type EventQuery struct {
+	EventName  string
+	Channel    string
+	UserID     string
+	CampaignID string
+	From       time.Time // inclusive, event_time
+	To         time.Time // exclusive, event_time
+	Tags       []string  // events must carry every tag
+
+	After *EventCursor // continue after this event
+	Limit int
+}
+
+// EventCursor is the position of the last event of a page.
+type EventCursor struct {
+	EventTime time.Time
+	ID        int64
+}
+
+// EventRef identifies one stored event, either by its client-supplied
+// event_id or by events.id. Exactly one field is set.
+type EventRef struct {
+	EventID string
+	ID      int64
+}
+
+type EventReaderPort interface {
+	ListEvents(ctx context.Context, q EventQuery) ([]domain.StoredEvent, error)
+	// GetEvent returns found = false when no event matches. Several events
+	// share an event_id only when the dedupe window expired in between; the
+	// newest one is returned.
+	GetEvent(ctx context.Context, ref EventRef) (e domain.StoredEvent, found bool, err error)
+}
+
+// EventAmendmentPort corrects stored events and keeps an audit trail of the
+// corrections.
+type EventAmendmentPort interface {
+	// AmendEvent locks the event matching ref and passes it to amend, which
+	// changes it in place. When amend returns an amendment, the event's
+	// channel, campaign_id, tags and metadata are written and the amendment
+	// is recorded in the same transaction; nil writes nothing, an error rolls
+	// back. found = false when no event matches.
+	AmendEvent(ctx context.Context, ref EventRef, amend func(e *domain.StoredEvent) (*domain.EventAmendment, error)) (e domain.StoredEvent, found bool, err error)
+	// ListAmendments returns the amendments of the event matching ref,
+	// oldest first. found = false when no event matches.
+	ListAmendments(ctx context.Context, ref EventRef) (a []domain.EventAmendment, found bool, err error)
+}
+
+// DeadLetterPort keeps events that could not be inserted. It should not
+// depend on the events database, which is what failed.
+type DeadLetterPort interface {
+	AddDeadLetter(ctx context.Context, d domain.DeadLetter) error
+	// ListDeadLetters returns up to limit entries, oldest first.
+	ListDeadLetters(ctx context.Context, limit int) ([]domain.DeadLetter, error)
+	// GetDeadLetter returns found = false for unknown ids.
+	GetDeadLetter(ctx context.Context, id string) (d domain.DeadLetter, found bool, err error)
+	// UpdateDeadLetter replaces the entry with d.ID, e.g. after another
+	// failed attempt.
+	UpdateDeadLetter(ctx context.Context, d domain.DeadLetter) error
+	RemoveDeadLetter(ctx context.Context, id string) error
+}
+
+// OutboxPort reads the events recorded for publishing by the statement that
+// stored them.
+type OutboxPort interface {
+	// RelayOutbox passes up to limit of the oldest pending events to publish,
+	// in insertion order, and removes them once publish returns nil. Entries
+	// whose event has been deleted since are removed without publishing. It
+	// returns how many entries were removed; while another relay holds the
+	// outbox it returns 0 without calling publish.
+	RelayOutbox(ctx context.Context, limit int, publish func([]domain.StoredEvent) error) (int, error)
+}
+
+// EventPublisherPort sends stored events to downstream consumers.
+type EventPublisherPort interface {
+	Publish(ctx context.Context, events []domain.StoredEvent) error
 }
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+	"event-metrics-service/internal/tag"
+)
+
+var ErrEmptyAmendment = errors.New("amendment must set channel, campaign_id, tags or metadata")
+
+// AmendEventInput corrects a stored event. Fields left nil are not changed.
+type AmendEventInput struct {
+	Channel    *string
+	CampaignID *string // "" clears the campaign
+	Tags       []string
+	// Metadata is merged into the stored metadata; a nil value removes the
+	// key.
+	Metadata  map[string]any
+	Reason    string
+	AmendedBy string
+}
+
+// AmendEventResult is the event after the amendment. Amendment is nil when
+// the input matched the event already and nothing was written.
+type AmendEventResult struct {
+	Event     domain.StoredEvent
+	Amendment *domain.EventAmendment
+}
+
+// AmendEventUseCase fixes mis-tagged events and keeps an audit trail of the
+// fixes. Corrected events are checked against the same tag and metadata
+// rules as on ingest. The dedupe key is left as it was stored.
+type AmendEventUseCase struct {
+	events ports.EventAmendmentPort
+	store  *StoreEventUseCase
+	now    func() time.Time
+}
+
+func NewAmendEventUseCase(events ports.EventAmendmentPort, store *StoreEventUseCase) *AmendEventUseCase {
+	return &AmendEventUseCase{events: events, store: store, now: time.Now}
+}
+
+// Execute amends the event ref points at, as in GetEventUseCase.
+func (uc *AmendEventUseCase) Execute(ctx context.Context, ref string, in AmendEventInput) (AmendEventResult, error) {
+	r, err := parseEventRef(ref)
+	if err != nil {
+		return AmendEventResult{}, err
+	}
+	if in.Channel == nil && in.CampaignID == nil && in.Tags == nil && len(in.Metadata) == 0 {
+		return AmendEventResult{}, ErrEmptyAmendment
+	}
+	in.Reason = strings.TrimSpace(in.Reason)
+	if in.Reason == "" {
+		return AmendEventResult{}, &ValidationError{Violations: []FieldViolation{
+			{Field: "reason", Message: "is required"},
+		}}
+	}
+	if in.Channel != nil && *in.Channel == "" {
+		return AmendEventResult{}, &ValidationError{Violations: []FieldViolation{
+			{Field: "channel", Message: "must not be empty"},
+		}}
+	}
+
+	var amendment *domain.EventAmendment
+	e, found, err := uc.events.AmendEvent(ctx, r, func(e *domain.StoredEvent) (*domain.EventAmendment, error) {
+		a, err := uc.amend(e, in)
+		amendment = a
+		return a, err
+	})
+	if err != nil {
+		return AmendEventResult{}, err
+	}
+	if !found {
+		return AmendEventResult{}, ErrEventNotFound
+	}
+	return AmendEventResult{Event: e, Amendment: amendment}, nil
+}
+
+// History returns the amendments of the event ref points at, oldest first.
+func (uc *AmendEventUseCase) History(ctx context.Context, ref string) ([]domain.EventAmendment, error) {
+	r, err := parseEventRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	amendments, found, err := uc.events.ListAmendments(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrEventNotFound
+	}
+	return amendments, nil
+}
+
+// amend applies in to e and returns the audit record, or nil when nothing
+// changed.
+func (uc *AmendEventUseCase) amend(e *domain.StoredEvent, in AmendEventInput) (*domain.EventAmendment, error) {
+	var changes []domain.FieldChange
+
+	if in.Channel != nil && *in.Channel != e.Channel {
+		changes = append(changes, domain.FieldChange{Field: "channel", Before: e.Channel, After: *in.Channel})
+		e.Channel = *in.Channel
+	}
+	if in.CampaignID != nil && *in.CampaignID != e.CampaignID {
+		changes = append(changes, domain.FieldChange{Field: "campaign_id", Before: e.CampaignID, After: *in.CampaignID})
+		e.CampaignID = *in.CampaignID
+	}
+
+	if in.Tags != nil {
+		tags := tag.Normalize(in.Tags)
+		if violations := uc.store.tagViolations(tags); len(violations) > 0 {
+			return nil, &ValidationError{Cause: ErrInvalidTags, Violations: violations}
+		}
+		if !slices.Equal(tags, e.Tags) {
+			changes = append(changes, domain.FieldChange{Field: "tags", Before: e.Tags, After: tags})
+			e.Tags = tags
+		}
+	}
+
+	if len(in.Metadata) > 0 {
+		metadata := maps.Clone(e.Metadata)
+		if metadata == nil {
+			metadata = map[string]any{}
+		}
+		var metadataChanges []domain.FieldChange
+		for _, k := range slices.Sorted(maps.Keys(in.Metadata)) {
+			before, after := metadata[k], in.Metadata[k]
+			if after == nil {
+				delete(metadata, k)
+			} else {
+				metadata[k] = after
+			}
+			if !sameJSON(before, after) {
+				metadataChanges = append(metadataChanges, domain.FieldChange{Field: "metadata." + k, Before: before, After: after})
+			}
+		}
+		if len(metadataChanges) > 0 {
+			if err := uc.store.checkMetadataLimits(metadata); err != nil {
+				return nil, err
+			}
+			if err := uc.store.checkMetadataSchema(e.EventName, metadata); err != nil {
+				return nil, err
+			}
+			changes = append(changes, metadataChanges...)
+			e.Metadata = metadata
+		}
+	}
+
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return &domain.EventAmendment{
+		EventRowID: e.ID,
+		Changes:    changes,
+		Reason:     in.Reason,
+		AmendedBy:  in.AmendedBy,
+		AmendedAt:  uc.now().UTC(),
+	}, nil
+}
+
+// sameJSON compares metadata values by their JSON encoding, as stored values
+// are decoded with json.Number and request values are not.
+func sameJSON(a, b any) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
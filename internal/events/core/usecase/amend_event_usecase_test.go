@@ -0,0 +1,142 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+	"event-metrics-service/internal/events/core/usecase"
+)
+
+// fakeAmendments amends a copy of Event the way the repository does.
+type fakeAmendments struct {
+	Event   *domain.StoredEvent // nil = not found
+	Written *domain.EventAmendment
+	History []domain.EventAmendment
+}
+
+func (f *fakeAmendments) AmendEvent(ctx context.Context, ref ports.EventRef, amend func(e *domain.StoredEvent) (*domain.EventAmendment, error)) (domain.StoredEvent, bool, error) {
+	if f.Event == nil {
+		return domain.StoredEvent{}, false, nil
+	}
+	e := *f.Event
+	a, err := amend(&e)
+	if err != nil {
+		return domain.StoredEvent{}, false, err
+	}
+	if a != nil {
+		f.Written = a
+		*f.Event = e
+	}
+	return e, true, nil
+}
+
+func (f *fakeAmendments) ListAmendments(ctx context.Context, ref ports.EventRef) ([]domain.EventAmendment, bool, error) {
+	return f.History, f.Event != nil, nil
+}
+
+func amendableEvent() *domain.StoredEvent {
+	return &domain.StoredEvent{
+		ID: 1042,
+		Event: domain.Event{
+			EventName:  "purchase",
+			Channel:    "web",
+			CampaignID: "bf-2024",
+			Tags:       []string{"promo"},
+			Metadata:   map[string]any{"order_id": json.Number("12345678901234567890"), "coupon": "X"},
+		},
+	}
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestAmendEvent_RecordsChanges(t *testing.T) {
+	repo := &fakeAmendments{Event: amendableEvent()}
+	uc := usecase.NewAmendEventUseCase(repo, usecase.NewStoreEventUseCase(&fakeEventRepo{}))
+
+	res, err := uc.Execute(context.Background(), "1042", usecase.AmendEventInput{
+		Channel:    strPtr("web"), // unchanged, not recorded
+		CampaignID: strPtr("bf-2025"),
+		Tags:       []string{" Promo ", "retarget"},
+		Metadata: map[string]any{
+			"order_id": json.Number("12345678901234567890"), // same value
+			"coupon":   nil,
+			"source":   "email",
+		},
+		Reason:    "  wrong campaign  ",
+		AmendedBy: "ops",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []domain.FieldChange{
+		{Field: "campaign_id", Before: "bf-2024", After: "bf-2025"},
+		{Field: "tags", Before: []string{"promo"}, After: []string{"promo", "retarget"}},
+		{Field: "metadata.coupon", Before: "X", After: nil},
+		{Field: "metadata.source", Before: nil, After: "email"},
+	}
+	if res.Amendment == nil || !reflect.DeepEqual(res.Amendment.Changes, want) {
+		t.Fatalf("unexpected changes: %+v", res.Amendment)
+	}
+	if res.Amendment.Reason != "wrong campaign" || res.Amendment.EventRowID != 1042 || res.Amendment.AmendedAt.IsZero() {
+		t.Fatalf("unexpected amendment: %+v", res.Amendment)
+	}
+	md := res.Event.Metadata
+	if _, ok := md["coupon"]; ok || md["source"] != "email" || res.Event.CampaignID != "bf-2025" {
+		t.Fatalf("unexpected event: %+v", res.Event)
+	}
+}
+
+func TestAmendEvent_NoChangeWritesNothing(t *testing.T) {
+	repo := &fakeAmendments{Event: amendableEvent()}
+	uc := usecase.NewAmendEventUseCase(repo, usecase.NewStoreEventUseCase(&fakeEventRepo{}))
+
+	res, err := uc.Execute(context.Background(), "1042", usecase.AmendEventInput{
+		Tags:   []string{"PROMO"},
+		Reason: "check",
+	})
+	if err != nil || res.Amendment != nil || repo.Written != nil {
+		t.Fatalf("expected no amendment, got %+v, %v", res.Amendment, err)
+	}
+}
+
+func TestAmendEvent_Errors(t *testing.T) {
+	store := usecase.NewStoreEventUseCase(&fakeEventRepo{}, usecase.WithTagLimits(1, 10))
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		ref  string
+		in   usecase.AmendEventInput
+		want error
+	}{
+		{"bad ref", "abc", usecase.AmendEventInput{Channel: strPtr("ios"), Reason: "r"}, usecase.ErrInvalidEventRef},
+		{"empty", "1042", usecase.AmendEventInput{Reason: "r"}, usecase.ErrEmptyAmendment},
+		{"no reason", "1042", usecase.AmendEventInput{Channel: strPtr("ios")}, usecase.ErrInvalidEvent},
+		{"empty channel", "1042", usecase.AmendEventInput{Channel: strPtr(""), Reason: "r"}, usecase.ErrInvalidEvent},
+		{"too many tags", "1042", usecase.AmendEventInput{Tags: []string{"a", "b"}, Reason: "r"}, usecase.ErrInvalidTags},
+	}
+	for _, tt := range tests {
+		repo := &fakeAmendments{Event: amendableEvent()}
+		_, err := usecase.NewAmendEventUseCase(repo, store).Execute(ctx, tt.ref, tt.in)
+		if !errors.Is(err, tt.want) {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+		if repo.Written != nil {
+			t.Fatalf("%s: nothing must be written", tt.name)
+		}
+	}
+
+	missing := usecase.NewAmendEventUseCase(&fakeAmendments{}, store)
+	if _, err := missing.Execute(ctx, "7", usecase.AmendEventInput{Channel: strPtr("ios"), Reason: "r"}); !errors.Is(err, usecase.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+	if _, err := missing.History(ctx, "7"); !errors.Is(err, usecase.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound from History, got %v", err)
+	}
+}
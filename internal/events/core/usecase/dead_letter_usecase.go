@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+)
+
+var (
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+	ErrInvalidDeadLetters = errors.New("invalid dead letter query")
+)
+
+const (
+	DefaultDeadLetterLimit = 100
+	MaxDeadLetterLimit     = 1000
+)
+
+// RedriveResult counts the outcome of re-driving dead letters. Created and
+// duplicate entries leave the queue; failed ones stay with the new error.
+type RedriveResult struct {
+	Created    int
+	Duplicates int
+	Failed     int
+}
+
+// DeadLetterUseCase lists and re-drives events whose insert failed.
+type DeadLetterUseCase struct {
+	deadLetters ports.DeadLetterPort
+	store       *StoreEventUseCase
+}
+
+func NewDeadLetterUseCase(deadLetters ports.DeadLetterPort, store *StoreEventUseCase) *DeadLetterUseCase {
+	return &DeadLetterUseCase{deadLetters: deadLetters, store: store}
+}
+
+func (uc *DeadLetterUseCase) List(ctx context.Context, limit int) ([]domain.DeadLetter, error) {
+	limit, err := deadLetterLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+	return uc.deadLetters.ListDeadLetters(ctx, limit)
+}
+
+// Redrive inserts the dead letter with id again.
+func (uc *DeadLetterUseCase) Redrive(ctx context.Context, id string) (RedriveResult, error) {
+	d, found, err := uc.deadLetters.GetDeadLetter(ctx, id)
+	if err != nil {
+		return RedriveResult{}, err
+	}
+	if !found {
+		return RedriveResult{}, ErrDeadLetterNotFound
+	}
+
+	var res RedriveResult
+	return res, uc.redrive(ctx, d, &res)
+}
+
+// RedriveOldest re-drives up to limit dead letters, oldest first. It stops at
+// the first failed insert: the database is most likely still unavailable.
+func (uc *DeadLetterUseCase) RedriveOldest(ctx context.Context, limit int) (RedriveResult, error) {
+	limit, err := deadLetterLimit(limit)
+	if err != nil {
+		return RedriveResult{}, err
+	}
+	entries, err := uc.deadLetters.ListDeadLetters(ctx, limit)
+	if err != nil {
+		return RedriveResult{}, err
+	}
+
+	var res RedriveResult
+	for _, d := range entries {
+		if err := uc.redrive(ctx, d, &res); err != nil {
+			return res, err
+		}
+		if res.Failed > 0 {
+			break
+		}
+	}
+	return res, nil
+}
+
+// Discard drops a dead letter that should not be stored, e.g. one the
+// database keeps refusing.
+func (uc *DeadLetterUseCase) Discard(ctx context.Context, id string) error {
+	if _, found, err := uc.deadLetters.GetDeadLetter(ctx, id); err != nil {
+		return err
+	} else if !found {
+		return ErrDeadLetterNotFound
+	}
+	return uc.deadLetters.RemoveDeadLetter(ctx, id)
+}
+
+// redrive counts a failed insert in res; only dead-letter store errors are
+// returned.
+func (uc *DeadLetterUseCase) redrive(ctx context.Context, d domain.DeadLetter, res *RedriveResult) error {
+	created, err := uc.store.redrive(ctx, d.Event)
+	if err != nil {
+		d.Attempts++
+		d.Error = err.Error()
+		d.LastAttemptAt = uc.store.now().UTC()
+		res.Failed++
+		return uc.deadLetters.UpdateDeadLetter(ctx, d)
+	}
+
+	if created {
+		res.Created++
+	} else {
+		res.Duplicates++
+	}
+	return uc.deadLetters.RemoveDeadLetter(ctx, d.ID)
+}
+
+func deadLetterLimit(limit int) (int, error) {
+	if limit == 0 {
+		return DefaultDeadLetterLimit, nil
+	}
+	if limit < 0 || limit > MaxDeadLetterLimit {
+		return 0, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidDeadLetters, MaxDeadLetterLimit)
+	}
+	return limit, nil
+}
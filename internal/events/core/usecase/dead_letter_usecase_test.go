@@ -0,0 +1,220 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/usecase"
+)
+
+// memDeadLetters is an in-memory DeadLetterPort.
+type memDeadLetters struct {
+	entries []domain.DeadLetter
+	addErr  error
+}
+
+func (m *memDeadLetters) AddDeadLetter(ctx context.Context, d domain.DeadLetter) error {
+	if m.addErr != nil {
+		return m.addErr
+	}
+	m.entries = append(m.entries, d)
+	return nil
+}
+
+func (m *memDeadLetters) ListDeadLetters(ctx context.Context, limit int) ([]domain.DeadLetter, error) {
+	return append([]domain.DeadLetter(nil), m.entries[:min(limit, len(m.entries))]...), nil
+}
+
+func (m *memDeadLetters) GetDeadLetter(ctx context.Context, id string) (domain.DeadLetter, bool, error) {
+	for _, d := range m.entries {
+		if d.ID == id {
+			return d, true, nil
+		}
+	}
+	return domain.DeadLetter{}, false, nil
+}
+
+func (m *memDeadLetters) UpdateDeadLetter(ctx context.Context, d domain.DeadLetter) error {
+	for i := range m.entries {
+		if m.entries[i].ID == d.ID {
+			m.entries[i] = d
+		}
+	}
+	return nil
+}
+
+func (m *memDeadLetters) RemoveDeadLetter(ctx context.Context, id string) error {
+	var kept []domain.DeadLetter
+	for _, d := range m.entries {
+		if d.ID != id {
+			kept = append(kept, d)
+		}
+	}
+	m.entries = kept
+	return nil
+}
+
+func validInput(user string) usecase.StoreEventInput {
+	return usecase.StoreEventInput{EventName: "purchase", Channel: "web", UserID: user, Timestamp: time.Now().Unix()}
+}
+
+// ------------------------------------------------------------
+// RETRIES AND DEAD-LETTERING
+// ------------------------------------------------------------
+
+func TestStoreEvent_RetriesTransientInsertFailure(t *testing.T) {
+	attempts := 0
+	repo := &fakeEventRepo{
+		InsertFn: func(ctx context.Context, e *domain.Event) (bool, error) {
+			attempts++
+			if attempts < 3 {
+				return false, errors.New("connection reset")
+			}
+			return true, nil
+		},
+	}
+	dlq := &memDeadLetters{}
+	uc := usecase.NewStoreEventUseCase(repo, usecase.WithInsertRetries(2, 0), usecase.WithDeadLetters(dlq))
+
+	stored, err := uc.Execute(context.Background(), validInput("u1"))
+	if err != nil || !stored.Created {
+		t.Fatalf("expected the third attempt to succeed, got %v %v", stored.Created, err)
+	}
+	if attempts != 3 || len(dlq.entries) != 0 {
+		t.Fatalf("unexpected attempts %d / dead letters %d", attempts, len(dlq.entries))
+	}
+}
+
+func TestStoreEvent_DeadLettersAfterRetries(t *testing.T) {
+	attempts := 0
+	repo := &fakeEventRepo{
+		InsertFn: func(ctx context.Context, e *domain.Event) (bool, error) {
+			attempts++
+			return false, errors.New("connection refused")
+		},
+	}
+	dlq := &memDeadLetters{}
+	uc := usecase.NewStoreEventUseCase(repo, usecase.WithInsertRetries(2, 0), usecase.WithDeadLetters(dlq))
+
+	_, err := uc.Execute(context.Background(), validInput("u1"))
+	if !errors.Is(err, usecase.ErrEventDeadLettered) {
+		t.Fatalf("expected ErrEventDeadLettered, got %v", err)
+	}
+	if attempts != 3 || len(dlq.entries) != 1 {
+		t.Fatalf("unexpected attempts %d / dead letters %d", attempts, len(dlq.entries))
+	}
+	d := dlq.entries[0]
+	if d.ID == "" || d.Attempts != 3 || d.Error != "connection refused" || d.Event.UserID != "u1" || d.Event.DedupeKey == "" {
+		t.Fatalf("unexpected dead letter: %+v", d)
+	}
+}
+
+func TestStoreEvent_DeadLetterFailureReturnsInsertError(t *testing.T) {
+	insertErr := errors.New("connection refused")
+	repo := &fakeEventRepo{
+		InsertFn: func(ctx context.Context, e *domain.Event) (bool, error) { return false, insertErr },
+	}
+	uc := usecase.NewStoreEventUseCase(repo, usecase.WithDeadLetters(&memDeadLetters{addErr: errors.New("disk full")}))
+
+	_, err := uc.Execute(context.Background(), validInput("u1"))
+	if !errors.Is(err, insertErr) || errors.Is(err, usecase.ErrEventDeadLettered) {
+		t.Fatalf("expected the insert error, got %v", err)
+	}
+}
+
+func TestBulkCreateEvents_DeadLettersAndStopsRetrying(t *testing.T) {
+	attempts := map[string]int{}
+	repo := &fakeEventRepo{
+		InsertFn: func(ctx context.Context, e *domain.Event) (bool, error) {
+			attempts[e.UserID]++
+			return false, errors.New("connection refused")
+		},
+	}
+	dlq := &memDeadLetters{}
+	uc := usecase.NewStoreEventUseCase(repo, usecase.WithInsertRetries(2, 0), usecase.WithDeadLetters(dlq))
+
+	res, err := uc.BulkCreateEvents(context.Background(), usecase.BulkCreateEventsInput{
+		Events: []usecase.StoreEventInput{validInput("u1"), validInput("u2")},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Accepted != 2 || res.Items[0].Status != usecase.ItemStatusAccepted || len(dlq.entries) != 2 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if attempts["u1"] != 3 || attempts["u2"] != 1 {
+		t.Fatalf("expected retries only for the first failure, got %v", attempts)
+	}
+}
+
+// ------------------------------------------------------------
+// RE-DRIVE
+// ------------------------------------------------------------
+
+func TestDeadLetters_RedriveOldestStopsAtFailure(t *testing.T) {
+	down := map[string]bool{"u2": true}
+	var inserted []string
+	repo := &fakeEventRepo{
+		InsertFn: func(ctx context.Context, e *domain.Event) (bool, error) {
+			if down[e.UserID] {
+				return false, errors.New("still failing")
+			}
+			inserted = append(inserted, e.UserID)
+			return e.UserID != "u0", nil
+		},
+	}
+	dlq := &memDeadLetters{}
+	for i, user := range []string{"u0", "u1", "u2", "u3"} {
+		dlq.entries = append(dlq.entries, domain.DeadLetter{ID: string(rune('a' + i)), Event: domain.Event{UserID: user}, Attempts: 3})
+	}
+	uc := usecase.NewDeadLetterUseCase(dlq, usecase.NewStoreEventUseCase(repo))
+
+	res, err := uc.RedriveOldest(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Created != 1 || res.Duplicates != 1 || res.Failed != 1 || len(inserted) != 2 {
+		t.Fatalf("unexpected result %+v, inserted %v", res, inserted)
+	}
+	if len(dlq.entries) != 2 || dlq.entries[0].Attempts != 4 || dlq.entries[0].Error != "still failing" || dlq.entries[1].ID != "d" {
+		t.Fatalf("unexpected queue: %+v", dlq.entries)
+	}
+}
+
+func TestDeadLetters_RedriveRenewsDedupeClaim(t *testing.T) {
+	var got domain.Event
+	repo := &fakeEventRepo{
+		InsertFn: func(ctx context.Context, e *domain.Event) (bool, error) {
+			got = *e
+			return true, nil
+		},
+	}
+	expired := time.Now().Add(-time.Hour)
+	dlq := &memDeadLetters{entries: []domain.DeadLetter{{ID: "a", Event: domain.Event{UserID: "u1", DedupeExpiresAt: expired}}}}
+	uc := usecase.NewDeadLetterUseCase(dlq, usecase.NewStoreEventUseCase(repo))
+
+	res, err := uc.Redrive(context.Background(), "a")
+	if err != nil || res.Created != 1 || len(dlq.entries) != 0 {
+		t.Fatalf("unexpected result %+v (%v)", res, err)
+	}
+	if !got.DedupeExpiresAt.After(time.Now()) {
+		t.Fatalf("expected a renewed dedupe claim, got %s", got.DedupeExpiresAt)
+	}
+}
+
+func TestDeadLetters_NotFoundAndLimits(t *testing.T) {
+	uc := usecase.NewDeadLetterUseCase(&memDeadLetters{}, usecase.NewStoreEventUseCase(&fakeEventRepo{}))
+
+	if _, err := uc.Redrive(context.Background(), "missing"); !errors.Is(err, usecase.ErrDeadLetterNotFound) {
+		t.Fatalf("expected ErrDeadLetterNotFound, got %v", err)
+	}
+	if err := uc.Discard(context.Background(), "missing"); !errors.Is(err, usecase.ErrDeadLetterNotFound) {
+		t.Fatalf("expected ErrDeadLetterNotFound, got %v", err)
+	}
+	if _, err := uc.List(context.Background(), usecase.MaxDeadLetterLimit+1); !errors.Is(err, usecase.ErrInvalidDeadLetters) {
+		t.Fatalf("expected ErrInvalidDeadLetters, got %v", err)
+	}
+}
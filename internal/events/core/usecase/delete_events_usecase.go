@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"event-metrics-service/internal/events/core/ports"
+)
+
+const DefaultDeleteEventsBatchSize = 5000
+
+var ErrInvalidDeleteFilter = errors.New("invalid delete filter")
+
+// DeleteEventsResult reports a delete by filter. A dry run only sets
+// Matched.
+type DeleteEventsResult struct {
+	DryRun  bool
+	Matched int64
+	Deleted int64
+	Batches int
+}
+
+// DeleteEventsUseCase cleans up events by name, channel and time range. The
+// name and both ends of the range are required, so a mistyped request cannot
+// empty the table. Events are deleted in batches, like retention, so no
+// statement holds locks on a large part of the table.
+type DeleteEventsUseCase struct {
+	deleter   ports.EventDeleterPort
+	batchSize int
+}
+
+func NewDeleteEventsUseCase(deleter ports.EventDeleterPort, batchSize int) *DeleteEventsUseCase {
+	if batchSize <= 0 {
+		batchSize = DefaultDeleteEventsBatchSize
+	}
+	return &DeleteEventsUseCase{deleter: deleter, batchSize: batchSize}
+}
+
+// Execute deletes batches until a short batch signals nothing is left. When
+// ctx ends between batches, the events deleted so far are reported with the
+// error; running the same delete again finishes it.
+func (uc *DeleteEventsUseCase) Execute(ctx context.Context, f ports.EventDeleteFilter, dryRun bool) (DeleteEventsResult, error) {
+	if err := validateDeleteFilter(f); err != nil {
+		return DeleteEventsResult{}, err
+	}
+
+	if dryRun {
+		n, err := uc.deleter.CountEvents(ctx, f)
+		return DeleteEventsResult{DryRun: true, Matched: n}, err
+	}
+
+	var res DeleteEventsResult
+	for {
+		n, err := uc.deleter.DeleteEvents(ctx, f, uc.batchSize)
+		res.Deleted += n
+		if err != nil {
+			return res, err
+		}
+		res.Batches++
+		if n < int64(uc.batchSize) {
+			res.Matched = res.Deleted
+			return res, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+	}
+}
+
+func validateDeleteFilter(f ports.EventDeleteFilter) error {
+	switch {
+	case f.EventName == "":
+		return fmt.Errorf("%w: event_name is required", ErrInvalidDeleteFilter)
+	case f.From.IsZero() || f.To.IsZero():
+		return fmt.Errorf("%w: from and to are required", ErrInvalidDeleteFilter)
+	case !f.To.After(f.From):
+		return fmt.Errorf("%w: to must be after from", ErrInvalidDeleteFilter)
+	}
+	return nil
+}
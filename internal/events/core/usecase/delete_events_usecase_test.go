@@ -0,0 +1,94 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/events/core/ports"
+	"event-metrics-service/internal/events/core/usecase"
+)
+
+// fakeEventDeleter deletes from a pool of Remaining events.
+type fakeEventDeleter struct {
+	Remaining int64
+	FailAfter int // fail the batch after this many; 0 = never
+	batches   int
+	counted   bool
+}
+
+func (f *fakeEventDeleter) CountEvents(ctx context.Context, filter ports.EventDeleteFilter) (int64, error) {
+	f.counted = true
+	return f.Remaining, nil
+}
+
+func (f *fakeEventDeleter) DeleteEvents(ctx context.Context, filter ports.EventDeleteFilter, limit int) (int64, error) {
+	f.batches++
+	if f.FailAfter > 0 && f.batches > f.FailAfter {
+		return 0, errors.New("db down")
+	}
+	n := min(f.Remaining, int64(limit))
+	f.Remaining -= n
+	return n, nil
+}
+
+func deleteFilter() ports.EventDeleteFilter {
+	return ports.EventDeleteFilter{
+		EventName: "test_event",
+		From:      time.Date(2025, 12, 7, 0, 0, 0, 0, time.UTC),
+		To:        time.Date(2025, 12, 8, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestDeleteEvents_Batches(t *testing.T) {
+	deleter := &fakeEventDeleter{Remaining: 25}
+	res, err := usecase.NewDeleteEventsUseCase(deleter, 10).Execute(context.Background(), deleteFilter(), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Deleted != 25 || res.Matched != 25 || res.Batches != 3 || deleter.Remaining != 0 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestDeleteEvents_DryRunOnlyCounts(t *testing.T) {
+	deleter := &fakeEventDeleter{Remaining: 25}
+	res, err := usecase.NewDeleteEventsUseCase(deleter, 10).Execute(context.Background(), deleteFilter(), true)
+	if err != nil || !res.DryRun || res.Matched != 25 || res.Deleted != 0 {
+		t.Fatalf("unexpected result: %+v, %v", res, err)
+	}
+	if deleter.batches != 0 || deleter.Remaining != 25 {
+		t.Fatalf("a dry run must not delete")
+	}
+}
+
+func TestDeleteEvents_FailureReportsProgress(t *testing.T) {
+	deleter := &fakeEventDeleter{Remaining: 25, FailAfter: 1}
+	res, err := usecase.NewDeleteEventsUseCase(deleter, 10).Execute(context.Background(), deleteFilter(), false)
+	if err == nil || res.Deleted != 10 {
+		t.Fatalf("expected the error with 10 deleted, got %+v, %v", res, err)
+	}
+}
+
+func TestDeleteEvents_RequiresNameAndRange(t *testing.T) {
+	tests := map[string]func(f *ports.EventDeleteFilter){
+		"no event_name": func(f *ports.EventDeleteFilter) { f.EventName = "" },
+		"no from":       func(f *ports.EventDeleteFilter) { f.From = time.Time{} },
+		"no to":         func(f *ports.EventDeleteFilter) { f.To = time.Time{} },
+		"to before":     func(f *ports.EventDeleteFilter) { f.To = f.From.Add(-time.Second) },
+		"empty range":   func(f *ports.EventDeleteFilter) { f.To = f.From },
+	}
+	for name, mutate := range tests {
+		f := deleteFilter()
+		mutate(&f)
+		deleter := &fakeEventDeleter{Remaining: 1}
+		_, err := usecase.NewDeleteEventsUseCase(deleter, 10).Execute(context.Background(), f, true)
+		if !errors.Is(err, usecase.ErrInvalidDeleteFilter) {
+			t.Fatalf("%s: expected ErrInvalidDeleteFilter, got %v", name, err)
+		}
+		if deleter.counted {
+			t.Fatalf("%s: the store must not be called", name)
+		}
+	}
+}
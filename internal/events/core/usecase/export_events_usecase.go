@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+)
+
+const DefaultExportBatchSize = 1000
+
+// ExportEventsUseCase walks every event matching a query, newest first, in
+// keyset pages, so an export of any size holds one page in memory and each
+// query stays as cheap as the first.
+type ExportEventsUseCase struct {
+	reader    ports.EventReaderPort
+	batchSize int
+}
+
+func NewExportEventsUseCase(reader ports.EventReaderPort, batchSize int) *ExportEventsUseCase {
+	if batchSize <= 0 {
+		batchSize = DefaultExportBatchSize
+	}
+	return &ExportEventsUseCase{reader: reader, batchSize: batchSize}
+}
+
+// Validate reports ErrInvalidEventQuery for a query Execute would refuse, so
+// callers can reject it before they start writing a response. q.Limit caps
+// the number of exported events; 0 exports everything.
+func (uc *ExportEventsUseCase) Validate(q ports.EventQuery) error {
+	if q.Limit < 0 {
+		return fmt.Errorf("%w: limit must not be negative", ErrInvalidEventQuery)
+	}
+	return validateEventFilters(q)
+}
+
+// Execute hands each page to emit and returns the number of exported events.
+// It stops at the first error from the reader or emit.
+func (uc *ExportEventsUseCase) Execute(ctx context.Context, q ports.EventQuery, emit func([]domain.StoredEvent) error) (int64, error) {
+	if err := uc.Validate(q); err != nil {
+		return 0, err
+	}
+
+	remaining := q.Limit
+	var total int64
+	for {
+		q.Limit = uc.batchSize
+		if remaining > 0 && remaining < q.Limit {
+			q.Limit = remaining
+		}
+
+		events, err := uc.reader.ListEvents(ctx, q)
+		if err != nil {
+			return total, err
+		}
+		if len(events) > 0 {
+			if err := emit(events); err != nil {
+				return total, err
+			}
+		}
+		total += int64(len(events))
+
+		if len(events) < q.Limit {
+			return total, nil
+		}
+		if remaining > 0 {
+			if remaining -= len(events); remaining == 0 {
+				return total, nil
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		last := events[len(events)-1]
+		q.After = &ports.EventCursor{EventTime: last.EventTime, ID: last.ID}
+	}
+}
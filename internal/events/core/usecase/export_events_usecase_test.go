@@ -0,0 +1,116 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+	"event-metrics-service/internal/events/core/usecase"
+)
+
+func TestExportEvents_PagesWithKeysetCursor(t *testing.T) {
+	all := storedEvents(5)
+	var calls []ports.EventQuery
+	reader := &fakeEventReader{
+		ListFn: func(ctx context.Context, q ports.EventQuery) ([]domain.StoredEvent, error) {
+			calls = append(calls, q)
+			start := 0
+			if q.After != nil {
+				for i, e := range all {
+					if e.ID == q.After.ID {
+						start = i + 1
+					}
+				}
+			}
+			end := min(start+q.Limit, len(all))
+			return all[start:end], nil
+		},
+	}
+
+	var got []int64
+	n, err := usecase.NewExportEventsUseCase(reader, 2).Execute(context.Background(), ports.EventQuery{EventName: "purchase"},
+		func(events []domain.StoredEvent) error {
+			for _, e := range events {
+				got = append(got, e.ID)
+			}
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 5 || len(got) != 5 || got[0] != 100 || got[4] != 96 {
+		t.Fatalf("expected all 5 events in order, got %d %v", n, got)
+	}
+	if len(calls) != 3 {
+		t.Fatalf("expected 3 pages, got %d", len(calls))
+	}
+	if calls[0].After != nil || calls[1].After == nil || calls[1].After.ID != 99 || calls[2].After.ID != 97 {
+		t.Fatalf("unexpected cursors: %+v %+v %+v", calls[0].After, calls[1].After, calls[2].After)
+	}
+	for _, q := range calls {
+		if q.EventName != "purchase" || q.Limit != 2 {
+			t.Fatalf("filters or batch size lost: %+v", q)
+		}
+	}
+}
+
+func TestExportEvents_LimitCapsTotal(t *testing.T) {
+	var limits []int
+	reader := &fakeEventReader{
+		ListFn: func(ctx context.Context, q ports.EventQuery) ([]domain.StoredEvent, error) {
+			limits = append(limits, q.Limit)
+			return storedEvents(q.Limit), nil
+		},
+	}
+
+	n, err := usecase.NewExportEventsUseCase(reader, 2).Execute(context.Background(), ports.EventQuery{Limit: 3},
+		func([]domain.StoredEvent) error { return nil })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 3 || len(limits) != 2 || limits[0] != 2 || limits[1] != 1 {
+		t.Fatalf("expected 3 events over pages of 2 and 1, got %d %v", n, limits)
+	}
+}
+
+func TestExportEvents_InvalidQuery(t *testing.T) {
+	reader := &fakeEventReader{}
+	uc := usecase.NewExportEventsUseCase(reader, 0)
+	base := time.Date(2025, 12, 7, 0, 0, 0, 0, time.UTC)
+
+	for _, q := range []ports.EventQuery{
+		{Limit: -1},
+		{From: base, To: base.Add(-time.Hour)},
+		{Tags: make([]string, usecase.MaxListEventsTags+1)},
+	} {
+		if err := uc.Validate(q); !errors.Is(err, usecase.ErrInvalidEventQuery) {
+			t.Fatalf("expected ErrInvalidEventQuery for %+v, got %v", q, err)
+		}
+		if _, err := uc.Execute(context.Background(), q, nil); !errors.Is(err, usecase.ErrInvalidEventQuery) {
+			t.Fatalf("expected ErrInvalidEventQuery for %+v, got %v", q, err)
+		}
+	}
+	if reader.called {
+		t.Fatal("reader must not be called for an invalid query")
+	}
+}
+
+func TestExportEvents_EmitErrorStops(t *testing.T) {
+	calls := 0
+	reader := &fakeEventReader{
+		ListFn: func(ctx context.Context, q ports.EventQuery) ([]domain.StoredEvent, error) {
+			calls++
+			return storedEvents(q.Limit), nil
+		},
+	}
+	emitErr := errors.New("client went away")
+
+	n, err := usecase.NewExportEventsUseCase(reader, 2).Execute(context.Background(), ports.EventQuery{},
+		func([]domain.StoredEvent) error { return emitErr })
+	if !errors.Is(err, emitErr) || n != 0 || calls != 1 {
+		t.Fatalf("expected to stop after the first page, got n=%d calls=%d err=%v", n, calls, err)
+	}
+}
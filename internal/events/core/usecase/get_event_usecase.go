@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidEventRef = errors.New("event reference must be an event_id (UUID) or a numeric id")
+	ErrEventNotFound   = errors.New("event not found")
+)
+
+// GetEventUseCase answers "did event X land?" for support.
+type GetEventUseCase struct {
+	reader ports.EventReaderPort
+}
+
+func NewGetEventUseCase(reader ports.EventReaderPort) *GetEventUseCase {
+	return &GetEventUseCase{reader: reader}
+}
+
+// Execute looks ref up as an event_id when it is a UUID and as events.id
+// when it is a positive integer.
+func (uc *GetEventUseCase) Execute(ctx context.Context, ref string) (domain.StoredEvent, error) {
+	r, err := parseEventRef(ref)
+	if err != nil {
+		return domain.StoredEvent{}, err
+	}
+
+	e, found, err := uc.reader.GetEvent(ctx, r)
+	if err != nil {
+		return domain.StoredEvent{}, err
+	}
+	if !found {
+		return domain.StoredEvent{}, ErrEventNotFound
+	}
+	return e, nil
+}
+
+func parseEventRef(ref string) (ports.EventRef, error) {
+	if id, err := uuid.Parse(ref); err == nil {
+		return ports.EventRef{EventID: id.String()}, nil
+	}
+	if n, err := strconv.ParseInt(ref, 10, 64); err == nil && n > 0 {
+		return ports.EventRef{ID: n}, nil
+	}
+	return ports.EventRef{}, ErrInvalidEventRef
+}
@@ -0,0 +1,58 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+	"event-metrics-service/internal/events/core/usecase"
+)
+
+func TestGetEvent_ResolvesReference(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want ports.EventRef
+	}{
+		{"0B5F3C1E-7D4A-4C8E-9A51-2F0D6F1B7E42", ports.EventRef{EventID: "0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42"}},
+		{"1042", ports.EventRef{ID: 1042}},
+	}
+
+	for _, tt := range tests {
+		reader := &fakeEventReader{
+			GetFn: func(ctx context.Context, ref ports.EventRef) (domain.StoredEvent, bool, error) {
+				if ref != tt.want {
+					t.Fatalf("%s: expected %+v, got %+v", tt.ref, tt.want, ref)
+				}
+				return domain.StoredEvent{ID: 1042}, true, nil
+			},
+		}
+
+		e, err := usecase.NewGetEventUseCase(reader).Execute(context.Background(), tt.ref)
+		if err != nil || e.ID != 1042 {
+			t.Fatalf("%s: unexpected result %+v, %v", tt.ref, e, err)
+		}
+	}
+}
+
+func TestGetEvent_Errors(t *testing.T) {
+	notFound := &fakeEventReader{
+		GetFn: func(ctx context.Context, ref ports.EventRef) (domain.StoredEvent, bool, error) {
+			return domain.StoredEvent{}, false, nil
+		},
+	}
+	if _, err := usecase.NewGetEventUseCase(notFound).Execute(context.Background(), "7"); !errors.Is(err, usecase.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+
+	for _, ref := range []string{"abc", "0", "-3"} {
+		reader := &fakeEventReader{}
+		if _, err := usecase.NewGetEventUseCase(reader).Execute(context.Background(), ref); !errors.Is(err, usecase.ErrInvalidEventRef) {
+			t.Fatalf("%s: expected ErrInvalidEventRef, got %v", ref, err)
+		}
+		if reader.called {
+			t.Fatalf("%s: reader must not be called", ref)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+)
+
+var ErrInvalidEventQuery = errors.New("invalid event query")
+
+const (
+	DefaultListEventsLimit = 50
+	MaxListEventsLimit     = 500
+	MaxListEventsTags      = 10
+)
+
+type ListEventsResult struct {
+	Events []domain.StoredEvent
+	Next   *ports.EventCursor // nil on the last page
+}
+
+// ListEventsUseCase pages through stored events for debugging, newest first.
+type ListEventsUseCase struct {
+	reader ports.EventReaderPort
+}
+
+func NewListEventsUseCase(reader ports.EventReaderPort) *ListEventsUseCase {
+	return &ListEventsUseCase{reader: reader}
+}
+
+func (uc *ListEventsUseCase) Execute(ctx context.Context, q ports.EventQuery) (*ListEventsResult, error) {
+	if q.Limit == 0 {
+		q.Limit = DefaultListEventsLimit
+	}
+	if q.Limit < 0 || q.Limit > MaxListEventsLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidEventQuery, MaxListEventsLimit)
+	}
+	if err := validateEventFilters(q); err != nil {
+		return nil, err
+	}
+
+	// One extra row tells whether another page exists.
+	limit := q.Limit
+	q.Limit++
+
+	events, err := uc.reader.ListEvents(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ListEventsResult{Events: events}
+	if len(events) > limit {
+		res.Events = events[:limit]
+		last := res.Events[limit-1]
+		res.Next = &ports.EventCursor{EventTime: last.EventTime, ID: last.ID}
+	}
+	return res, nil
+}
+
+// validateEventFilters checks the filters shared by listing and export.
+func validateEventFilters(q ports.EventQuery) error {
+	if !q.From.IsZero() && !q.To.IsZero() && !q.To.After(q.From) {
+		return fmt.Errorf("%w: from must not be after to", ErrInvalidEventQuery)
+	}
+	if len(q.Tags) > MaxListEventsTags {
+		return fmt.Errorf("%w: at most %d tags are allowed", ErrInvalidEventQuery, MaxListEventsTags)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+	"event-metrics-service/internal/events/core/usecase"
+)
+
+type fakeEventReader struct {
+	ListFn func(ctx context.Context, q ports.EventQuery) ([]domain.StoredEvent, error)
+	GetFn  func(ctx context.Context, ref ports.EventRef) (domain.StoredEvent, bool, error)
+	called bool
+}
+
+func (f *fakeEventReader) ListEvents(ctx context.Context, q ports.EventQuery) ([]domain.StoredEvent, error) {
+	f.called = true
+	return f.ListFn(ctx, q)
+}
+
+func (f *fakeEventReader) GetEvent(ctx context.Context, ref ports.EventRef) (domain.StoredEvent, bool, error) {
+	f.called = true
+	return f.GetFn(ctx, ref)
+}
+
+func storedEvents(n int) []domain.StoredEvent {
+	base := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	out := make([]domain.StoredEvent, n)
+	for i := range out {
+		out[i] = domain.StoredEvent{
+			Event: domain.Event{EventName: "purchase", EventTime: base.Add(-time.Duration(i) * time.Minute)},
+			ID:    int64(100 - i),
+		}
+	}
+	return out
+}
+
+func TestListEvents_NextCursorWhenMoreRows(t *testing.T) {
+	reader := &fakeEventReader{
+		ListFn: func(ctx context.Context, q ports.EventQuery) ([]domain.StoredEvent, error) {
+			if q.Limit != 3 {
+				t.Fatalf("expected limit+1 to be requested, got %d", q.Limit)
+			}
+			return storedEvents(3), nil
+		},
+	}
+
+	res, err := usecase.NewListEventsUseCase(reader).Execute(context.Background(), ports.EventQuery{Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(res.Events))
+	}
+	if res.Next == nil || res.Next.ID != 99 || !res.Next.EventTime.Equal(res.Events[1].EventTime) {
+		t.Fatalf("expected cursor at the last returned event, got %+v", res.Next)
+	}
+}
+
+func TestListEvents_LastPage(t *testing.T) {
+	reader := &fakeEventReader{
+		ListFn: func(ctx context.Context, q ports.EventQuery) ([]domain.StoredEvent, error) {
+			if q.Limit != usecase.DefaultListEventsLimit+1 {
+				t.Fatalf("expected default limit, got %d", q.Limit)
+			}
+			return storedEvents(2), nil
+		},
+	}
+
+	res, err := usecase.NewListEventsUseCase(reader).Execute(context.Background(), ports.EventQuery{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Events) != 2 || res.Next != nil {
+		t.Fatalf("expected a final page, got %+v", res)
+	}
+}
+
+func TestListEvents_InvalidQuery(t *testing.T) {
+	from := time.Date(2025, 12, 7, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		q    ports.EventQuery
+	}{
+		{"limit too large", ports.EventQuery{Limit: usecase.MaxListEventsLimit + 1}},
+		{"negative limit", ports.EventQuery{Limit: -1}},
+		{"reversed range", ports.EventQuery{From: from, To: from.Add(-time.Second)}},
+		{"too many tags", ports.EventQuery{Tags: make([]string, usecase.MaxListEventsTags+1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &fakeEventReader{}
+			_, err := usecase.NewListEventsUseCase(reader).Execute(context.Background(), tt.q)
+			if !errors.Is(err, usecase.ErrInvalidEventQuery) {
+				t.Fatalf("expected ErrInvalidEventQuery, got %v", err)
+			}
+			if reader.called {
+				t.Fatalf("reader must not be called for invalid queries")
+			}
+		})
+	}
+}
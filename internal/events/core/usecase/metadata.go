@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrMetadataTooLarge = errors.New("metadata too large")
+
+const (
+	DefaultMaxMetadataKeys  = 100
+	DefaultMaxMetadataDepth = 5
+	DefaultMaxMetadataBytes = 16 * 1024
+)
+
+// checkMetadataLimits enforces the configured key count (counted across all
+// nesting levels), nesting depth (a flat object has depth 1) and serialized
+// JSON size. Zero disables a limit.
+func (uc *StoreEventUseCase) checkMetadataLimits(md map[string]any) error {
+	if len(md) == 0 {
+		return nil
+	}
+
+	keys, depth := metadataShape(md, 1)
+
+	if uc.maxMetadataKeys > 0 && keys > uc.maxMetadataKeys {
+		return fmt.Errorf("%w: %d keys exceeds limit of %d", ErrMetadataTooLarge, keys, uc.maxMetadataKeys)
+	}
+	if uc.maxMetadataDepth > 0 && depth > uc.maxMetadataDepth {
+		return fmt.Errorf("%w: nesting depth %d exceeds limit of %d", ErrMetadataTooLarge, depth, uc.maxMetadataDepth)
+	}
+
+	if uc.maxMetadataBytes > 0 {
+		b, err := json.Marshal(md)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+		}
+		if len(b) > uc.maxMetadataBytes {
+			return fmt.Errorf("%w: %d bytes exceeds limit of %d", ErrMetadataTooLarge, len(b), uc.maxMetadataBytes)
+		}
+	}
+
+	return nil
+}
+
+// metadataShape returns the total number of object keys below v and the
+// deepest nesting level of objects/arrays, v itself being at level depth.
+func metadataShape(v any, depth int) (keys, maxDepth int) {
+	maxDepth = depth
+
+	visit := func(child any) {
+		k, d := metadataShape(child, depth+1)
+		keys += k
+		maxDepth = max(maxDepth, d)
+	}
+
+	switch t := v.(type) {
+	case map[string]any:
+		keys += len(t)
+		for _, child := range t {
+			if isContainer(child) {
+				visit(child)
+			}
+		}
+	case []any:
+		for _, child := range t {
+			if isContainer(child) {
+				visit(child)
+			}
+		}
+	}
+
+	return keys, maxDepth
+}
+
+func isContainer(v any) bool {
+	switch v.(type) {
+	case map[string]any, []any:
+		return true
+	}
+	return false
+}
+
+// checkMetadataSchema runs the per event name schema check, after the size
+// limits so oversized payloads are never walked.
+func (uc *StoreEventUseCase) checkMetadataSchema(eventName string, md map[string]any) error {
+	if uc.schemas == nil {
+		return nil
+	}
+
+	violations := uc.schemas.ValidateMetadata(eventName, md)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	out := make([]FieldViolation, 0, len(violations))
+	for _, v := range violations {
+		out = append(out, FieldViolation{Field: v.Field, Message: v.Message})
+	}
+	return &ValidationError{Cause: ErrSchemaViolation, Violations: out}
+}
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/events/core/ports"
+)
+
+func TestMetadataShape(t *testing.T) {
+	md := map[string]any{
+		"a": 1,
+		"b": map[string]any{
+			"c": []any{map[string]any{"d": true}},
+		},
+	}
+
+	keys, depth := metadataShape(md, 1)
+	if keys != 4 {
+		t.Fatalf("expected 4 keys, got %d", keys)
+	}
+	if depth != 4 {
+		t.Fatalf("expected depth 4, got %d", depth)
+	}
+}
+
+func TestStoreEvent_MetadataLimits(t *testing.T) {
+	now := time.Now().Add(-time.Minute).Unix()
+
+	tests := []struct {
+		name     string
+		metadata map[string]any
+	}{
+		{"too many keys", map[string]any{"a": 1, "b": 2, "c": 3, "d": 4}},
+		{"too deep", map[string]any{"a": map[string]any{"b": map[string]any{"c": 1}}}},
+		{"too large", map[string]any{"blob": strings.Repeat("x", 200)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeBulkRepo{}
+			uc := NewStoreEventUseCase(repo, WithMetadataLimits(3, 2, 128))
+
+			_, err := uc.Execute(context.Background(), StoreEventInput{
+				EventName: "product_view",
+				Channel:   "web",
+				UserID:    "user_1",
+				Timestamp: now,
+				Metadata:  tt.metadata,
+			})
+			if !errors.Is(err, ErrMetadataTooLarge) {
+				t.Fatalf("expected ErrMetadataTooLarge, got %v", err)
+			}
+			if errors.Is(err, ErrInvalidEvent) {
+				t.Fatalf("ErrMetadataTooLarge must be distinct from ErrInvalidEvent")
+			}
+			if len(repo.InsertCalls) != 0 {
+				t.Fatalf("event must not be inserted")
+			}
+		})
+	}
+}
+
+func TestStoreEvent_MetadataWithinLimits(t *testing.T) {
+	repo := &fakeBulkRepo{}
+	uc := NewStoreEventUseCase(repo, WithMetadataLimits(3, 2, 128))
+
+	_, err := uc.Execute(context.Background(), StoreEventInput{
+		EventName: "product_view",
+		Channel:   "web",
+		UserID:    "user_1",
+		Timestamp: time.Now().Add(-time.Minute).Unix(),
+		Metadata:  map[string]any{"product_id": "p1", "attrs": map[string]any{"size": 42}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+type fakeMetadataSchemas struct {
+	ValidateFn func(eventName string, metadata map[string]any) []ports.SchemaViolation
+}
+
+func (f *fakeMetadataSchemas) ValidateMetadata(eventName string, metadata map[string]any) []ports.SchemaViolation {
+	return f.ValidateFn(eventName, metadata)
+}
+
+func TestStoreEvent_SchemaViolation(t *testing.T) {
+	repo := &fakeBulkRepo{}
+	schemas := &fakeMetadataSchemas{
+		ValidateFn: func(eventName string, metadata map[string]any) []ports.SchemaViolation {
+			if eventName != "purchase" {
+				return nil
+			}
+			return []ports.SchemaViolation{{Field: "metadata.order_id", Message: "is required"}}
+		},
+	}
+	uc := NewStoreEventUseCase(repo, WithMetadataSchemas(schemas))
+
+	in := StoreEventInput{
+		EventName: "purchase",
+		Channel:   "web",
+		UserID:    "user_1",
+		Timestamp: time.Now().Add(-time.Minute).Unix(),
+	}
+
+	_, err := uc.Execute(context.Background(), in)
+	if !errors.Is(err, ErrSchemaViolation) || !errors.Is(err, ErrInvalidEvent) {
+		t.Fatalf("expected ErrSchemaViolation wrapping ErrInvalidEvent, got %v", err)
+	}
+	var verr *ValidationError
+	if !errors.As(err, &verr) || len(verr.Violations) != 1 || verr.Violations[0].Field != "metadata.order_id" {
+		t.Fatalf("expected field level violation, got %v", err)
+	}
+	if len(repo.InsertCalls) != 0 {
+		t.Fatalf("event must not be inserted")
+	}
+
+	// Event names without a schema are unaffected.
+	in.EventName = "product_view"
+	if _, err := uc.Execute(context.Background(), in); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"event-metrics-service/internal/events/core/ports"
+)
+
+var (
+	ErrInvalidPromotedKey    = errors.New("invalid promoted metadata key")
+	ErrPromotedColumnMissing = errors.New("promoted metadata column missing")
+)
+
+// Keys become part of column and index names, so they are restricted to
+// lowercase identifiers short enough for Postgres' 63 byte name limit.
+var promotedKeyPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,46}$`)
+
+const DefaultPromotedBackfillBatchSize = 5000
+
+func ValidatePromotedKeys(keys []string) error {
+	for _, k := range keys {
+		if !promotedKeyPattern.MatchString(k) {
+			return fmt.Errorf("%w: %q", ErrInvalidPromotedKey, k)
+		}
+	}
+	return nil
+}
+
+// PromoteMetadataUseCase keeps real columns for hot metadata keys. The
+// columns come from migrations; new events fill them at ingest and existing
+// events are backfilled in batches along the primary key. A key is Ready
+// once its column is indexed and fully backfilled, and only then may readers
+// use the column instead of the JSONB metadata. Progress and completion are
+// stored, so restarts resume and every instance agrees on readiness.
+type PromoteMetadataUseCase struct {
+	store     ports.PromotedMetadataPort
+	keys      []string
+	batchSize int
+
+	mu    sync.RWMutex
+	ready map[string]bool
+}
+
+func NewPromoteMetadataUseCase(store ports.PromotedMetadataPort, keys []string, batchSize int) *PromoteMetadataUseCase {
+	if batchSize <= 0 {
+		batchSize = DefaultPromotedBackfillBatchSize
+	}
+	return &PromoteMetadataUseCase{
+		store:     store,
+		keys:      keys,
+		batchSize: batchSize,
+		ready:     map[string]bool{},
+	}
+}
+
+// Prepare checks that every key's column exists. Call it before serving
+// ingest traffic.
+func (uc *PromoteMetadataUseCase) Prepare(ctx context.Context) error {
+	if err := ValidatePromotedKeys(uc.keys); err != nil {
+		return err
+	}
+	for _, k := range uc.keys {
+		ok, err := uc.store.PromotedColumnExists(ctx, k)
+		if err != nil {
+			return fmt.Errorf("promote metadata key %s: %w", k, err)
+		}
+		if !ok {
+			return fmt.Errorf("%w: add events.meta_%s with a migration to promote %s", ErrPromotedColumnMissing, k, k)
+		}
+	}
+	return nil
+}
+
+// Execute indexes and backfills every key that is not ready yet, resuming
+// where the stored progress left off.
+func (uc *PromoteMetadataUseCase) Execute(ctx context.Context) error {
+	for _, k := range uc.keys {
+		if uc.Ready(k) {
+			continue
+		}
+
+		lastID, done, err := uc.store.PromotedBackfillState(ctx, k)
+		if err != nil {
+			return fmt.Errorf("load promoted metadata key %s: %w", k, err)
+		}
+		if !done {
+			if err := uc.backfill(ctx, k, lastID); err != nil {
+				return err
+			}
+		}
+
+		uc.mu.Lock()
+		uc.ready[k] = true
+		uc.mu.Unlock()
+	}
+
+	return nil
+}
+
+func (uc *PromoteMetadataUseCase) backfill(ctx context.Context, key string, lastID int64) error {
+	if err := uc.store.IndexPromotedColumn(ctx, key); err != nil {
+		return fmt.Errorf("index promoted metadata key %s: %w", key, err)
+	}
+
+	for {
+		next, n, err := uc.store.BackfillPromotedColumn(ctx, key, lastID, uc.batchSize)
+		if err != nil {
+			return fmt.Errorf("backfill promoted metadata key %s: %w", key, err)
+		}
+		lastID = next
+		if n < uc.batchSize {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+
+	if err := uc.store.CompletePromotedBackfill(ctx, key); err != nil {
+		return fmt.Errorf("complete promoted metadata key %s: %w", key, err)
+	}
+	return nil
+}
+
+// Ready reports whether the column for key can be used by readers.
+func (uc *PromoteMetadataUseCase) Ready(key string) bool {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	return uc.ready[key]
+}
+
+// Run retries Execute on every tick until all keys are ready or ctx is
+// cancelled.
+func (uc *PromoteMetadataUseCase) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := uc.Execute(ctx)
+		if err == nil {
+			return
+		}
+		if onError != nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"event-metrics-service/internal/events/core/usecase"
+)
+
+// fakePromotedStore holds events with ids 1..maxID and keeps the backfill
+// progress the way promoted_metadata_backfills does.
+type fakePromotedStore struct {
+	columns  map[string]bool
+	maxID    int64
+	lastID   map[string]int64
+	done     map[string]bool
+	indexed  []string
+	afterIDs []int64
+	IndexErr error
+}
+
+func newFakePromotedStore(maxID int64, keys ...string) *fakePromotedStore {
+	f := &fakePromotedStore{columns: map[string]bool{}, maxID: maxID, lastID: map[string]int64{}, done: map[string]bool{}}
+	for _, k := range keys {
+		f.columns[k] = true
+	}
+	return f
+}
+
+func (f *fakePromotedStore) PromotedColumnExists(ctx context.Context, key string) (bool, error) {
+	return f.columns[key], nil
+}
+
+func (f *fakePromotedStore) IndexPromotedColumn(ctx context.Context, key string) error {
+	if f.IndexErr != nil {
+		return f.IndexErr
+	}
+	f.indexed = append(f.indexed, key)
+	return nil
+}
+
+func (f *fakePromotedStore) PromotedBackfillState(ctx context.Context, key string) (int64, bool, error) {
+	return f.lastID[key], f.done[key], nil
+}
+
+func (f *fakePromotedStore) BackfillPromotedColumn(ctx context.Context, key string, afterID int64, limit int) (int64, int, error) {
+	f.afterIDs = append(f.afterIDs, afterID)
+	last := min(afterID+int64(limit), f.maxID)
+	if last <= afterID {
+		return afterID, 0, nil
+	}
+	f.lastID[key] = last
+	return last, int(last - afterID), nil
+}
+
+func (f *fakePromotedStore) CompletePromotedBackfill(ctx context.Context, key string) error {
+	f.done[key] = true
+	return nil
+}
+
+func TestPromoteMetadata_PrepareValidatesKeys(t *testing.T) {
+	store := newFakePromotedStore(0, "product_id")
+	uc := usecase.NewPromoteMetadataUseCase(store, []string{"product_id", "Bad-Key"}, 10)
+
+	if err := uc.Prepare(context.Background()); !errors.Is(err, usecase.ErrInvalidPromotedKey) {
+		t.Fatalf("expected ErrInvalidPromotedKey, got %v", err)
+	}
+}
+
+func TestPromoteMetadata_PrepareRequiresMigratedColumn(t *testing.T) {
+	store := newFakePromotedStore(0, "product_id")
+	uc := usecase.NewPromoteMetadataUseCase(store, []string{"product_id", "sku"}, 10)
+
+	if err := uc.Prepare(context.Background()); !errors.Is(err, usecase.ErrPromotedColumnMissing) {
+		t.Fatalf("expected ErrPromotedColumnMissing, got %v", err)
+	}
+}
+
+func TestPromoteMetadata_BackfillsUntilReady(t *testing.T) {
+	store := newFakePromotedStore(25, "product_id")
+	uc := usecase.NewPromoteMetadataUseCase(store, []string{"product_id"}, 10)
+
+	if err := uc.Prepare(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if uc.Ready("product_id") {
+		t.Fatalf("key must not be ready before backfill")
+	}
+
+	if err := uc.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []int64{0, 10, 20}
+	if len(store.afterIDs) != len(want) {
+		t.Fatalf("expected keyset batches after %v, got %v", want, store.afterIDs)
+	}
+	for i := range want {
+		if store.afterIDs[i] != want[i] {
+			t.Fatalf("expected keyset batches after %v, got %v", want, store.afterIDs)
+		}
+	}
+	if !store.done["product_id"] {
+		t.Fatalf("expected the completed backfill to be stored")
+	}
+	if !uc.Ready("product_id") {
+		t.Fatalf("expected key to be ready after backfill")
+	}
+}
+
+func TestPromoteMetadata_ResumesFromStoredProgress(t *testing.T) {
+	store := newFakePromotedStore(25, "product_id")
+	store.lastID["product_id"] = 20
+	uc := usecase.NewPromoteMetadataUseCase(store, []string{"product_id"}, 10)
+
+	if err := uc.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.afterIDs) != 1 || store.afterIDs[0] != 20 {
+		t.Fatalf("expected one batch after id 20, got %v", store.afterIDs)
+	}
+	if !uc.Ready("product_id") {
+		t.Fatalf("expected key to be ready after backfill")
+	}
+}
+
+func TestPromoteMetadata_ReadyFromStoredCompletion(t *testing.T) {
+	// Another instance finished the backfill.
+	store := newFakePromotedStore(25, "product_id")
+	store.done["product_id"] = true
+	uc := usecase.NewPromoteMetadataUseCase(store, []string{"product_id"}, 10)
+
+	if err := uc.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.indexed) != 0 || len(store.afterIDs) != 0 {
+		t.Fatalf("expected no work for a completed key, indexed %v, batches %v", store.indexed, store.afterIDs)
+	}
+	if !uc.Ready("product_id") {
+		t.Fatalf("expected key to be ready")
+	}
+}
+
+func TestPromoteMetadata_NotReadyOnError(t *testing.T) {
+	store := newFakePromotedStore(25, "product_id")
+	store.IndexErr = errors.New("lock timeout")
+	uc := usecase.NewPromoteMetadataUseCase(store, []string{"product_id"}, 10)
+
+	if err := uc.Execute(context.Background()); err == nil {
+		t.Fatalf("expected error")
+	}
+	if uc.Ready("product_id") || store.done["product_id"] {
+		t.Fatalf("key must not be ready after a failed index build")
+	}
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"event-metrics-service/internal/events/core/ports"
+)
+
+const DefaultDedupePurgeBatchSize = 5000
+
+// PurgeDedupeKeysUseCase removes expired dedupe claims in batches so the
+// dedupe table stays proportional to the dedupe window, not to history.
+type PurgeDedupeKeysUseCase struct {
+	janitor   ports.DedupeJanitorPort
+	batchSize int
+}
+
+func NewPurgeDedupeKeysUseCase(janitor ports.DedupeJanitorPort, batchSize int) *PurgeDedupeKeysUseCase {
+	if batchSize <= 0 {
+		batchSize = DefaultDedupePurgeBatchSize
+	}
+	return &PurgeDedupeKeysUseCase{janitor: janitor, batchSize: batchSize}
+}
+
+// Execute purges batches until a short batch signals nothing is left, and
+// returns the total number of purged keys.
+func (uc *PurgeDedupeKeysUseCase) Execute(ctx context.Context) (int64, error) {
+	var total int64
+
+	for {
+		n, err := uc.janitor.PurgeExpiredDedupeKeys(ctx, uc.batchSize)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if n < int64(uc.batchSize) {
+			return total, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+	}
+}
+
+// Run purges on every tick until ctx is cancelled.
+func (uc *PurgeDedupeKeysUseCase) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := uc.Execute(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"event-metrics-service/internal/events/core/usecase"
+)
+
+type fakeDedupeJanitor struct {
+	batches []int64
+	err     error
+	calls   int
+	limit   int
+}
+
+func (f *fakeDedupeJanitor) PurgeExpiredDedupeKeys(ctx context.Context, limit int) (int64, error) {
+	f.limit = limit
+	if f.calls >= len(f.batches) {
+		return 0, f.err
+	}
+	n := f.batches[f.calls]
+	f.calls++
+	return n, nil
+}
+
+func TestPurgeDedupeKeys_LoopsUntilShortBatch(t *testing.T) {
+	janitor := &fakeDedupeJanitor{batches: []int64{100, 100, 7}}
+	uc := usecase.NewPurgeDedupeKeysUseCase(janitor, 100)
+
+	total, err := uc.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 207 {
+		t.Fatalf("expected 207 purged, got %d", total)
+	}
+	if janitor.calls != 3 {
+		t.Fatalf("expected 3 batches, got %d", janitor.calls)
+	}
+	if janitor.limit != 100 {
+		t.Fatalf("expected batch size 100, got %d", janitor.limit)
+	}
+}
+
+func TestPurgeDedupeKeys_Error(t *testing.T) {
+	janitor := &fakeDedupeJanitor{err: errors.New("db failure")}
+	uc := usecase.NewPurgeDedupeKeysUseCase(janitor, 100)
+
+	_, err := uc.Execute(context.Background())
+	if err == nil || err.Error() != "db failure" {
+		t.Fatalf("expected db failure, got %v", err)
+	}
+}
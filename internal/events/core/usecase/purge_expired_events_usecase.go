@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"event-metrics-service/internal/events/core/ports"
+)
+
+const DefaultRetentionPurgeBatchSize = 5000
+
+// RetentionProgress reports a purge run after each batch.
+type RetentionProgress struct {
+	Cutoff  time.Time
+	Batches int
+	Deleted int64 // total so far in this run
+	Done    bool  // set on the last report of a run that finished
+}
+
+// PurgeExpiredEventsUseCase enforces the retention policy: events whose
+// event_time is older than the retention period are deleted in batches, so
+// no single statement holds locks on a large part of the table.
+type PurgeExpiredEventsUseCase struct {
+	store     ports.RetentionPort
+	archiver  ports.ArchiverPort
+	retention time.Duration
+	batchSize int
+	now       func() time.Time
+}
+
+type RetentionOption func(*PurgeExpiredEventsUseCase)
+
+// WithArchiver archives every batch before it is deleted. A batch that
+// fails to archive is kept and the run stops.
+func WithArchiver(a ports.ArchiverPort) RetentionOption {
+	return func(uc *PurgeExpiredEventsUseCase) {
+		uc.archiver = a
+	}
+}
+
+func NewPurgeExpiredEventsUseCase(store ports.RetentionPort, retention time.Duration, batchSize int, opts ...RetentionOption) *PurgeExpiredEventsUseCase {
+	if batchSize <= 0 {
+		batchSize = DefaultRetentionPurgeBatchSize
+	}
+	uc := &PurgeExpiredEventsUseCase{store: store, retention: retention, batchSize: batchSize, now: time.Now}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// Execute purges batches until a short batch signals nothing is left. The
+// cutoff is fixed at the start of the run; onProgress (optional) is called
+// after every batch.
+func (uc *PurgeExpiredEventsUseCase) Execute(ctx context.Context, onProgress func(RetentionProgress)) (int64, error) {
+	p := RetentionProgress{Cutoff: uc.now().Add(-uc.retention).UTC()}
+
+	for {
+		n, full, err := uc.purgeBatch(ctx, p.Cutoff)
+		p.Deleted += n
+		if err != nil {
+			return p.Deleted, err
+		}
+		p.Batches++
+		p.Done = !full
+
+		if onProgress != nil {
+			onProgress(p)
+		}
+		if p.Done {
+			return p.Deleted, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return p.Deleted, err
+		}
+	}
+}
+
+// purgeBatch deletes one batch and reports whether it was full, i.e. more
+// events may be left.
+func (uc *PurgeExpiredEventsUseCase) purgeBatch(ctx context.Context, cutoff time.Time) (int64, bool, error) {
+	if uc.archiver == nil {
+		n, err := uc.store.PurgeEventsBefore(ctx, cutoff, uc.batchSize)
+		return n, n >= int64(uc.batchSize), err
+	}
+
+	ids, err := uc.archiver.ArchiveEventsBefore(ctx, cutoff, uc.batchSize)
+	if err != nil {
+		return 0, false, fmt.Errorf("archive events: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, false, nil
+	}
+	n, err := uc.store.DeleteEventsByID(ctx, ids)
+	return n, len(ids) >= uc.batchSize, err
+}
+
+// Run purges on every tick until ctx is cancelled.
+func (uc *PurgeExpiredEventsUseCase) Run(ctx context.Context, interval time.Duration, onProgress func(RetentionProgress), onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := uc.Execute(ctx, onProgress); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
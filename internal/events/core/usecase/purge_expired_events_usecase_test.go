@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeRetentionStore struct {
+	batches []int64
+	err     error
+	cutoffs []time.Time
+	limit   int
+	deleted []int64
+}
+
+func (f *fakeRetentionStore) PurgeEventsBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	f.limit = limit
+	if len(f.cutoffs) >= len(f.batches) {
+		return 0, f.err
+	}
+	f.cutoffs = append(f.cutoffs, cutoff)
+	return f.batches[len(f.cutoffs)-1], nil
+}
+
+func (f *fakeRetentionStore) DeleteEventsByID(ctx context.Context, ids []int64) (int64, error) {
+	f.deleted = append(f.deleted, ids...)
+	return int64(len(ids)), f.err
+}
+
+type fakeArchiver struct {
+	batches [][]int64
+	err     error
+	calls   int
+}
+
+func (f *fakeArchiver) ArchiveEventsBefore(ctx context.Context, cutoff time.Time, limit int) ([]int64, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.calls++
+	if f.calls > len(f.batches) {
+		return nil, nil
+	}
+	return f.batches[f.calls-1], nil
+}
+
+func TestPurgeExpiredEvents_BatchesWithFixedCutoff(t *testing.T) {
+	now := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	store := &fakeRetentionStore{batches: []int64{100, 100, 3}}
+	uc := NewPurgeExpiredEventsUseCase(store, 90*24*time.Hour, 100)
+	uc.now = func() time.Time { return now }
+
+	var progress []RetentionProgress
+	total, err := uc.Execute(context.Background(), func(p RetentionProgress) {
+		progress = append(progress, p)
+		now = now.Add(time.Minute) // the cutoff must not move mid-run
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 203 || store.limit != 100 {
+		t.Fatalf("unexpected total %d / limit %d", total, store.limit)
+	}
+
+	wantCutoff := time.Date(2025, 9, 8, 10, 0, 0, 0, time.UTC)
+	for _, c := range store.cutoffs {
+		if !c.Equal(wantCutoff) {
+			t.Fatalf("expected cutoff %s, got %s", wantCutoff, c)
+		}
+	}
+
+	if len(progress) != 3 || progress[1].Deleted != 200 || progress[1].Done || !progress[2].Done || progress[2].Batches != 3 {
+		t.Fatalf("unexpected progress: %+v", progress)
+	}
+}
+
+func TestPurgeExpiredEvents_Error(t *testing.T) {
+	store := &fakeRetentionStore{batches: []int64{100}, err: errors.New("db failure")}
+	uc := NewPurgeExpiredEventsUseCase(store, time.Hour, 100)
+
+	total, err := uc.Execute(context.Background(), nil)
+	if err == nil || total != 100 {
+		t.Fatalf("expected error after one batch, got %d %v", total, err)
+	}
+}
+
+func TestPurgeExpiredEvents_DeletesOnlyArchivedEvents(t *testing.T) {
+	store := &fakeRetentionStore{}
+	archiver := &fakeArchiver{batches: [][]int64{{1, 2}, {3}}}
+	uc := NewPurgeExpiredEventsUseCase(store, time.Hour, 2, WithArchiver(archiver))
+
+	total, err := uc.Execute(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != 3 || len(store.deleted) != 3 || store.deleted[2] != 3 {
+		t.Fatalf("expected the archived ids to be deleted, got %d %v", total, store.deleted)
+	}
+	if len(store.cutoffs) != 0 {
+		t.Fatalf("expected no unarchived purge")
+	}
+}
+
+func TestPurgeExpiredEvents_ArchiveFailureKeepsEvents(t *testing.T) {
+	store := &fakeRetentionStore{}
+	uc := NewPurgeExpiredEventsUseCase(store, time.Hour, 2, WithArchiver(&fakeArchiver{err: errors.New("s3 unavailable")}))
+
+	if _, err := uc.Execute(context.Background(), nil); err == nil {
+		t.Fatalf("expected an error")
+	}
+	if len(store.deleted) != 0 {
+		t.Fatalf("expected nothing deleted, got %v", store.deleted)
+	}
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+)
+
+const DefaultOutboxBatchSize = 500
+
+// RelayOutboxUseCase publishes stored events from the outbox. An entry is
+// only removed after the publisher accepted its event, so delivery is at
+// least once: a relay that fails between the two publishes the batch again.
+type RelayOutboxUseCase struct {
+	outbox    ports.OutboxPort
+	publisher ports.EventPublisherPort
+	batchSize int
+}
+
+func NewRelayOutboxUseCase(outbox ports.OutboxPort, publisher ports.EventPublisherPort, batchSize int) *RelayOutboxUseCase {
+	if batchSize <= 0 {
+		batchSize = DefaultOutboxBatchSize
+	}
+	return &RelayOutboxUseCase{outbox: outbox, publisher: publisher, batchSize: batchSize}
+}
+
+// Execute relays batches until a short batch signals the outbox is drained,
+// and returns the number of outbox entries removed.
+func (uc *RelayOutboxUseCase) Execute(ctx context.Context) (int64, error) {
+	var total int64
+
+	for {
+		n, err := uc.outbox.RelayOutbox(ctx, uc.batchSize, func(events []domain.StoredEvent) error {
+			if err := uc.publisher.Publish(ctx, events); err != nil {
+				return fmt.Errorf("publish events: %w", err)
+			}
+			return nil
+		})
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+		if n < uc.batchSize {
+			return total, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+	}
+}
+
+// Run relays on every tick until ctx is cancelled.
+func (uc *RelayOutboxUseCase) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := uc.Execute(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/usecase"
+)
+
+// fakeOutbox hands out pending events in batches and removes a batch only
+// when publish succeeds.
+type fakeOutbox struct {
+	pending []domain.StoredEvent
+	limit   int
+}
+
+func (f *fakeOutbox) RelayOutbox(ctx context.Context, limit int, publish func([]domain.StoredEvent) error) (int, error) {
+	f.limit = limit
+	batch := f.pending[:min(limit, len(f.pending))]
+	if len(batch) == 0 {
+		return 0, nil
+	}
+	if err := publish(batch); err != nil {
+		return 0, err
+	}
+	f.pending = f.pending[len(batch):]
+	return len(batch), nil
+}
+
+type fakePublisher struct {
+	PublishFn func(ctx context.Context, events []domain.StoredEvent) error
+	published []int64
+}
+
+func (f *fakePublisher) Publish(ctx context.Context, events []domain.StoredEvent) error {
+	if f.PublishFn != nil {
+		if err := f.PublishFn(ctx, events); err != nil {
+			return err
+		}
+	}
+	for _, e := range events {
+		f.published = append(f.published, e.ID)
+	}
+	return nil
+}
+
+func TestRelayOutbox_DrainsInOrder(t *testing.T) {
+	outbox := &fakeOutbox{pending: storedEvents(5)}
+	pub := &fakePublisher{}
+	uc := usecase.NewRelayOutboxUseCase(outbox, pub, 2)
+
+	n, err := uc.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 5 {
+		t.Fatalf("expected 5 relayed, got %d", n)
+	}
+	if outbox.limit != 2 {
+		t.Fatalf("expected batch size 2, got %d", outbox.limit)
+	}
+	for i, id := range pub.published {
+		if id != int64(100-i) {
+			t.Fatalf("expected events in outbox order, got %v", pub.published)
+		}
+	}
+	if len(outbox.pending) != 0 {
+		t.Fatalf("expected an empty outbox, %d left", len(outbox.pending))
+	}
+}
+
+func TestRelayOutbox_PublishFailureKeepsBatch(t *testing.T) {
+	outbox := &fakeOutbox{pending: storedEvents(3)}
+	calls := 0
+	pub := &fakePublisher{PublishFn: func(ctx context.Context, events []domain.StoredEvent) error {
+		calls++
+		if calls == 2 {
+			return errors.New("broker unavailable")
+		}
+		return nil
+	}}
+	uc := usecase.NewRelayOutboxUseCase(outbox, pub, 2)
+
+	n, err := uc.Execute(context.Background())
+	if err == nil || err.Error() != "publish events: broker unavailable" {
+		t.Fatalf("expected publish error, got %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 relayed before the failure, got %d", n)
+	}
+	if len(outbox.pending) != 1 || outbox.pending[0].ID != 98 {
+		t.Fatalf("expected event 98 to stay in the outbox, got %+v", outbox.pending)
+	}
+
+	// The next run picks up where the failed one stopped.
+	if _, err := uc.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(outbox.pending) != 0 {
+		t.Fatalf("expected an empty outbox, %d left", len(outbox.pending))
+	}
+}
+
+func TestRelayOutbox_DefaultBatchSize(t *testing.T) {
+	outbox := &fakeOutbox{}
+	uc := usecase.NewRelayOutboxUseCase(outbox, &fakePublisher{}, 0)
+
+	if _, err := uc.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if outbox.limit != usecase.DefaultOutboxBatchSize {
+		t.Fatalf("expected batch size %d, got %d", usecase.DefaultOutboxBatchSize, outbox.limit)
+	}
+}
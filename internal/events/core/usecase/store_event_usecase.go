@@ -4,126 +4,702 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"hash/fnv"
+	"strconv"
 	"time"
 
 	"event-metrics-service/internal/events/core/domain"
 	"event-metrics-service/internal/events/core/ports"
+	"event-metrics-service/internal/tag"
+
+	"github.com/google/uuid"
 )
 
 var (
 	ErrInvalidEvent = errors.New("invalid event")
 	ErrFutureTime   = errors.New("timestamp cannot be in the future")
+	ErrEventTooOld  = errors.New("timestamp is older than the maximum event age")
+	ErrInvalidTags  = errors.New("invalid tags")
+
+	ErrSchemaViolation = errors.New("metadata does not match the event schema")
+
+	// ErrEventDeadLettered means the insert failed but the event was kept in
+	// the dead-letter store for a later re-drive.
+	ErrEventDeadLettered = errors.New("event stored in the dead-letter queue")
+
+	// ErrEventSampledOut means the event was valid but dropped by the
+	// sampling rate of its event name.
+	ErrEventSampledOut = errors.New("event dropped by sampling")
 )
 
+// DefaultDedupeWindow is how long an identical event is rejected as a duplicate.
+const DefaultDedupeWindow = 24 * time.Hour
+
 type StoreEventUseCase struct {
-	repo ports.EventRepositoryPort
+	repo      ports.EventRepositoryPort
+	observers []ports.EventObserverPort
+	schemas   ports.MetadataSchemaPort
+
+	enrichers []ports.EnricherPort
+
+	sampleRates map[string]float64
+	tenantOf    func(ctx context.Context) string
+
+	deadLetters   ports.DeadLetterPort
+	insertRetries int
+	retryBackoff  time.Duration
+
+	maxTags      int
+	maxTagLength int
+	dedupeWindow time.Duration
+	maxEventAge  time.Duration
+	now          func() time.Time
+
+	maxMetadataKeys  int
+	maxMetadataDepth int
+	maxMetadataBytes int
+}
+
+type Option func(*StoreEventUseCase)
+
+// WithTagLimits overrides the per-event tag cap and the max tag length
+// (measured in characters after normalization). Zero disables a limit.
+func WithTagLimits(maxTags, maxTagLength int) Option {
+	return func(uc *StoreEventUseCase) {
+		uc.maxTags = maxTags
+		uc.maxTagLength = maxTagLength
+	}
+}
+
+// WithDedupeWindow sets how long a dedupe key keeps rejecting identical
+// events. Zero keeps keys forever.
+func WithDedupeWindow(d time.Duration) Option {
+	return func(uc *StoreEventUseCase) {
+		uc.dedupeWindow = d
+	}
+}
+
+// WithMetadataLimits overrides the metadata key count, nesting depth and
+// serialized size limits. Zero disables a limit.
+func WithMetadataLimits(maxKeys, maxDepth, maxBytes int) Option {
+	return func(uc *StoreEventUseCase) {
+		uc.maxMetadataKeys = maxKeys
+		uc.maxMetadataDepth = maxDepth
+		uc.maxMetadataBytes = maxBytes
+	}
+}
+
+// WithMaxEventAge rejects events whose timestamp is further in the past than
+// d (e.g. stale retries from client queues). Zero accepts any age. Replayed
+// events are exempt; archives are older than any sensible limit.
+func WithMaxEventAge(d time.Duration) Option {
+	return func(uc *StoreEventUseCase) {
+		uc.maxEventAge = d
+	}
+}
+
+// WithObserver notifies o about every newly created (committed) event.
+// Observers given in several options are notified in order.
+func WithObserver(o ports.EventObserverPort) Option {
+	return func(uc *StoreEventUseCase) {
+		uc.observers = append(uc.observers, o)
+	}
+}
+
+// WithMetadataSchemas validates metadata against the schema registered for
+// each event name.
+func WithMetadataSchemas(s ports.MetadataSchemaPort) Option {
+	return func(uc *StoreEventUseCase) {
+		uc.schemas = s
+	}
+}
+
+// WithInsertRetries retries a failed insert up to retries times, waiting
+// backoff, 2*backoff, ... in between. Transactional (atomic bulk) inserts
+// are not retried.
+func WithInsertRetries(retries int, backoff time.Duration) Option {
+	return func(uc *StoreEventUseCase) {
+		uc.insertRetries = retries
+		uc.retryBackoff = backoff
+	}
+}
+
+// WithDeadLetters keeps events whose insert still fails after the retries in
+// d instead of failing the request.
+func WithDeadLetters(d ports.DeadLetterPort) Option {
+	return func(uc *StoreEventUseCase) {
+		uc.deadLetters = d
+	}
+}
+
+// WithEnrichers runs the enrichers, in order, on every event before it is
+// stored. Enrichment happens after validation, so derived fields are not
+// subject to metadata limits or schemas.
+func WithEnrichers(enrichers ...ports.EnricherPort) Option {
+	return func(uc *StoreEventUseCase) {
+		uc.enrichers = append(uc.enrichers, enrichers...)
+	}
+}
+
+// WithSampling keeps only the given share (0 < rate < 1) of the events of
+// each listed event name; the others are dropped with ErrEventSampledOut.
+// Kept events record their rate so counts can be scaled back up. The choice
+// follows the dedupe key, so a retried event is kept or dropped again.
+func WithSampling(rates map[string]float64) Option {
+	return func(uc *StoreEventUseCase) {
+		uc.sampleRates = map[string]float64{}
+		for name, rate := range rates {
+			if rate > 0 && rate < 1 {
+				uc.sampleRates[name] = rate
+			}
+		}
+	}
+}
+
+// WithTenantResolver stores every event under the tenant of the request
+// context. Dedupe keys are scoped to the tenant, so tenants never dedupe
+// against each other's events.
+func WithTenantResolver(fn func(ctx context.Context) string) Option {
+	return func(uc *StoreEventUseCase) {
+		uc.tenantOf = fn
+	}
+}
+
+// WithStoreClock replaces time.Now, which event timestamps are checked
+// against and dedupe claims expire from.
+func WithStoreClock(now func() time.Time) Option {
+	return func(uc *StoreEventUseCase) {
+		uc.now = now
+	}
 }
 
-func NewStoreEventUseCase(repo ports.EventRepositoryPort) *StoreEventUseCase {
-	return &StoreEventUseCase{repo: repo}
+func NewStoreEventUseCase(repo ports.EventRepositoryPort, opts ...Option) *StoreEventUseCase {
+	uc := &StoreEventUseCase{
+		repo:         repo,
+		maxTags:      DefaultMaxTags,
+		maxTagLength: DefaultMaxTagLength,
+		dedupeWindow: DefaultDedupeWindow,
+		now:          time.Now,
+
+		maxMetadataKeys:  DefaultMaxMetadataKeys,
+		maxMetadataDepth: DefaultMaxMetadataDepth,
+		maxMetadataBytes: DefaultMaxMetadataBytes,
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
 }
 
 type StoreEventInput struct {
-	EventName  string
-	Channel    string
-	CampaignID string
-	UserID     string
-	Timestamp  int64
-	Tags       []string
-	Metadata   map[string]any
+	// EventID is an optional UUID, used as the idempotency key when set.
+	// Without one the stored event gets a generated UUIDv7.
+	EventID     string
+	EventName   string
+	Channel     string
+	CampaignID  string
+	UserID      string
+	AnonymousID string // may replace UserID until the visitor is identified
+	Timestamp   int64  // unix seconds
+	// TimestampMs is the event time in unix milliseconds; when set it takes
+	// precedence over Timestamp (which, if also set, must be the same second).
+	TimestampMs int64
+	Value       *float64
+	Tags        []string
+	Metadata    map[string]any
+
+	Client domain.ClientInfo // sender of the request, used by enrichers
+
+	replay *replayOrigin // set for events replayed from the archive
 }
 
-func (uc *StoreEventUseCase) Execute(ctx context.Context, in StoreEventInput) (bool, error) {
+// replayOrigin is the tenant and sample rate an archived event was stored
+// with, which its replay keeps instead of resolving them again.
+type replayOrigin struct {
+	tenantID   string
+	sampleRate float64
+}
 
-	if err := uc.validateInput(in); err != nil {
-		return false, err
+// StoreEventResult is the outcome of storing one event.
+type StoreEventResult struct {
+	Created bool // false for a duplicate
+	// EventID identifies the stored event; for a duplicate, the original
+	// (empty when it predates generated ids). It is also set when the error
+	// is ErrEventDeadLettered, as the event keeps it on re-drive.
+	EventID string
+}
+
+func (uc *StoreEventUseCase) Execute(ctx context.Context, in StoreEventInput) (StoreEventResult, error) {
+	return uc.execute(ctx, in, uc.insertRetries)
+}
+
+// execute stores in, retrying a failed insert up to retries times before the
+// event is dead-lettered.
+func (uc *StoreEventUseCase) execute(ctx context.Context, in StoreEventInput, retries int) (StoreEventResult, error) {
+	in, err := uc.prepareInput(in)
+	if err != nil {
+		return StoreEventResult{}, err
 	}
+	return uc.store(ctx, in, retries)
+}
 
-	eventTime := time.Unix(in.Timestamp, 0).UTC()
+// store is execute for an input prepareInput has already validated.
+func (uc *StoreEventUseCase) store(ctx context.Context, in StoreEventInput, retries int) (StoreEventResult, error) {
+	e, err := uc.build(ctx, in)
+	if err != nil {
+		return StoreEventResult{}, err
+	}
 
-	if in.Tags == nil {
-		in.Tags = []string{}
+	created, err := uc.insert(ctx, e, retries)
+	if err != nil {
+		err = uc.deadLetter(ctx, e, retries+1, err)
+		if errors.Is(err, ErrEventDeadLettered) {
+			return StoreEventResult{EventID: e.EventID}, err
+		}
+		return StoreEventResult{}, err
 	}
+
+	if created {
+		uc.notify(ctx, e)
+	}
+
+	return StoreEventResult{Created: created, EventID: e.EventID}, nil
+}
+
+// build turns an input prepared by prepareInput into the event to insert.
+func (uc *StoreEventUseCase) build(ctx context.Context, in StoreEventInput) (*domain.Event, error) {
+	eventTime := in.eventTime()
+
 	if in.Metadata == nil {
 		in.Metadata = map[string]any{}
 	}
 
+	var tenantID string
+	switch {
+	case in.replay != nil:
+		tenantID = in.replay.tenantID
+	case uc.tenantOf != nil:
+		tenantID = uc.tenantOf(ctx)
+	}
 	dedupeKey := buildDedupeKey(in, eventTime)
+	if tenantID != "" {
+		dedupeKey = "tenant|" + tenantID + "|" + dedupeKey
+	}
+	// Generated after the dedupe key, which must not depend on it.
+	eventID := in.EventID
+	if eventID == "" {
+		eventID = uuid.Must(uuid.NewV7()).String()
+	}
+
+	// Sampled out after validation, so producers still learn about bad
+	// events, and before enrichment, which would be wasted on them. Replayed
+	// events were sampled when first stored and keep that rate.
+	rate, sampled := uc.sampleRates[in.EventName]
+	if in.replay != nil {
+		rate = in.replay.sampleRate
+		sampled = rate > 0 && rate < 1
+	} else if sampled && !keepSample(dedupeKey, rate) {
+		return nil, ErrEventSampledOut
+	}
 
 	e := &domain.Event{
-		EventName:  in.EventName,
-		Channel:    in.Channel,
-		CampaignID: in.CampaignID,
-		UserID:     in.UserID,
-		EventTime:  eventTime,
-		Tags:       in.Tags,
-		Metadata:   in.Metadata,
-		DedupeKey:  dedupeKey,
+		EventID:     eventID,
+		EventName:   in.EventName,
+		Channel:     in.Channel,
+		CampaignID:  in.CampaignID,
+		UserID:      in.UserID,
+		AnonymousID: in.AnonymousID,
+		EventTime:   eventTime,
+		Value:       in.Value,
+		Tags:        in.Tags,
+		Metadata:    in.Metadata,
+		DedupeKey:   dedupeKey,
+		TenantID:    tenantID,
+	}
+	if sampled {
+		e.SampleRate = rate
+	}
+
+	uc.enrich(ctx, e, in.Client)
+
+	if uc.dedupeWindow > 0 {
+		e.DedupeExpiresAt = uc.now().UTC().Add(uc.dedupeWindow)
 	}
 
-	created, err := uc.repo.InsertEvent(ctx, e)
+	return e, nil
+}
+
+func (uc *StoreEventUseCase) insert(ctx context.Context, e *domain.Event, retries int) (bool, error) {
+	for attempt := 1; ; attempt++ {
+		created, err := uc.repo.InsertEvent(ctx, e)
+		if err == nil || attempt > retries {
+			return created, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, err
+		case <-time.After(time.Duration(attempt) * uc.retryBackoff):
+		}
+	}
+}
+
+// deadLetter keeps e for a later re-drive and returns ErrEventDeadLettered.
+// Without a dead-letter store, or when it fails as well, the insert error is
+// returned.
+func (uc *StoreEventUseCase) deadLetter(ctx context.Context, e *domain.Event, attempts int, insertErr error) error {
+	if uc.deadLetters == nil {
+		return insertErr
+	}
+
+	now := uc.now().UTC()
+	err := uc.deadLetters.AddDeadLetter(context.WithoutCancel(ctx), domain.DeadLetter{
+		ID:            uuid.NewString(),
+		Event:         *e,
+		Error:         insertErr.Error(),
+		Attempts:      attempts,
+		FailedAt:      now,
+		LastAttemptAt: now,
+	})
 	if err != nil {
-		return false, err
+		return errors.Join(insertErr, fmt.Errorf("dead-letter: %w", err))
+	}
+	return fmt.Errorf("%w: %v", ErrEventDeadLettered, insertErr)
+}
+
+// redrive inserts a dead-lettered event once more. Its dedupe claim is
+// renewed from now, since the original one may have expired in the queue.
+func (uc *StoreEventUseCase) redrive(ctx context.Context, e domain.Event) (bool, error) {
+	if uc.dedupeWindow > 0 {
+		e.DedupeExpiresAt = uc.now().UTC().Add(uc.dedupeWindow)
 	}
 
+	created, err := uc.repo.InsertEvent(ctx, &e)
+	if err != nil {
+		return false, err
+	}
+	if created {
+		uc.notify(ctx, &e)
+	}
 	return created, nil
 }
 
+// enrich merges the fields derived by every enricher into e.Metadata. Keys
+// already present, from the producer or an earlier enricher, are kept.
+func (uc *StoreEventUseCase) enrich(ctx context.Context, e *domain.Event, client domain.ClientInfo) {
+	if len(uc.enrichers) == 0 {
+		return
+	}
+
+	// Never write into the caller's map.
+	md := make(map[string]any, len(e.Metadata))
+	for k, v := range e.Metadata {
+		md[k] = v
+	}
+	e.Metadata = md
+
+	for _, en := range uc.enrichers {
+		for k, v := range en.Enrich(ctx, e, client) {
+			if _, exists := md[k]; !exists {
+				md[k] = v
+			}
+		}
+	}
+}
+
+func (uc *StoreEventUseCase) notify(ctx context.Context, events ...*domain.Event) {
+	for _, o := range uc.observers {
+		for _, e := range events {
+			o.EventStored(ctx, e)
+		}
+	}
+}
+
 func buildDedupeKey(in StoreEventInput, t time.Time) string {
-	// event_name + user_id + channel + campaign_id + unix_timestamp
-	return fmt.Sprintf("%s|%s|%s|%s|%d",
+	// A client-supplied event_id is the idempotency key on its own.
+	if in.EventID != "" {
+		return "event_id|" + in.EventID
+	}
+
+	// event_name + user_id + channel + campaign_id + unix_timestamp[.millis]
+	// Whole-second times keep the original key format so keys issued before
+	// millisecond support still match.
+	ts := strconv.FormatInt(t.Unix(), 10)
+	if ms := t.UnixMilli() % 1000; ms != 0 {
+		ts += fmt.Sprintf(".%03d", ms)
+	}
+
+	// Anonymous events are keyed by their anonymous_id instead.
+	user := in.UserID
+	if user == "" {
+		user = "anon:" + in.AnonymousID
+	}
+
+	return fmt.Sprintf("%s|%s|%s|%s|%s",
 		in.EventName,
-		in.UserID,
+		user,
 		in.Channel,
 		in.CampaignID,
-		t.Unix(),
+		ts,
 	)
 }
 
+// keepSample maps the dedupe key to a uniform number in [0, 1) and keeps the
+// event when it falls below rate.
+func keepSample(dedupeKey string, rate float64) bool {
+	h := fnv.New64a()
+	h.Write([]byte(dedupeKey))
+	return float64(h.Sum64()>>11)/(1<<53) < rate
+}
+
+func (in StoreEventInput) eventTime() time.Time {
+	if in.TimestampMs != 0 {
+		return time.UnixMilli(in.TimestampMs).UTC()
+	}
+	return time.Unix(in.Timestamp, 0).UTC()
+}
+
+// ReplayEventInput is an archived event to store again, with the tenant and
+// sample rate it was archived with.
+type ReplayEventInput struct {
+	StoreEventInput
+	TenantID   string
+	SampleRate float64
+}
+
+// ReplayEvents stores archived events like BulkCreateEvents, in the tenant
+// and with the sample rate each was archived with rather than the caller's.
+// The maximum event age does not apply; dedupe does.
+func (uc *StoreEventUseCase) ReplayEvents(ctx context.Context, events []ReplayEventInput) (BulkCreateEventsResult, error) {
+	in := BulkCreateEventsInput{Events: make([]StoreEventInput, len(events))}
+	for i, ev := range events {
+		in.Events[i] = ev.StoreEventInput
+		in.Events[i].replay = &replayOrigin{tenantID: ev.TenantID, sampleRate: ev.SampleRate}
+	}
+	return uc.BulkCreateEvents(ctx, in)
+}
+
 type BulkCreateEventsInput struct {
 	Events []StoreEventInput
+
+	// Atomic makes the batch all-or-nothing: any invalid event rejects the
+	// whole batch and all inserts share a single transaction.
+	Atomic bool
+}
+
+const (
+	ItemStatusCreated   = "created"
+	ItemStatusDuplicate = "duplicate"
+	ItemStatusInvalid   = "invalid"
+	ItemStatusAccepted  = "accepted" // dead-lettered, stored on re-drive
+	ItemStatusSampled   = "sampled"  // valid, dropped by sampling
+	ItemStatusFailed    = "failed"   // valid, not stored; safe to retry
+)
+
+// ItemFailedReason is the reason of failed items, whose repository error is
+// not reported to producers.
+const ItemFailedReason = "the event could not be stored"
+
+// BulkItemResult is the outcome of a single event in a bulk request.
+// Index refers to the position of the event in BulkCreateEventsInput.Events.
+type BulkItemResult struct {
+	Index   int
+	EventID string // as in StoreEventResult; empty for invalid and sampled items
+	Status  string // created | duplicate | invalid | accepted | sampled | failed
+	Reason  string // set for invalid items
 }
 
 type BulkCreateEventsResult struct {
 	Created    int
 	Duplicates int
+	Invalid    int
+	Accepted   int
+	Sampled    int
+	Failed     int
+	Items      []BulkItemResult
 }
 
+// BulkCreateEvents stores every valid event of the batch. Invalid events and
+// events the repository failed to store (and that could not be
+// dead-lettered) are reported per item instead of failing the whole batch.
 func (uc *StoreEventUseCase) BulkCreateEvents(ctx context.Context, in BulkCreateEventsInput) (BulkCreateEventsResult, error) {
-	var res BulkCreateEventsResult
+	if in.Atomic {
+		return uc.bulkCreateAtomic(ctx, in)
+	}
 
-	for _, ev := range in.Events {
-		if err := uc.validateInput(ev); err != nil {
-			return res, err
-		}
+	res := BulkCreateEventsResult{
+		Items: make([]BulkItemResult, 0, len(in.Events)),
 	}
 
-	for _, ev := range in.Events {
-		ok, err := uc.Execute(ctx, ev)
+	retries := uc.insertRetries
+	for i, ev := range in.Events {
+		item := BulkItemResult{Index: i}
+
+		prepared, err := uc.prepareInput(ev)
 		if err != nil {
-			return res, err
+			item.Status = ItemStatusInvalid
+			item.Reason = err.Error()
+			res.Invalid++
+			res.Items = append(res.Items, item)
+			continue
 		}
 
-		if ok {
+		item.EventID = prepared.EventID
+
+		stored, err := uc.store(ctx, prepared, retries)
+		if stored.EventID != "" {
+			item.EventID = stored.EventID
+		}
+		if errors.Is(err, ErrEventSampledOut) {
+			item.Status = ItemStatusSampled
+			res.Sampled++
+			res.Items = append(res.Items, item)
+			continue
+		}
+		if errors.Is(err, ErrEventDeadLettered) {
+			item.Status = ItemStatusAccepted
+			res.Accepted++
+			res.Items = append(res.Items, item)
+			// The database is failing; try the rest of the batch once each
+			// rather than waiting out the retries for every event.
+			retries = 0
+			continue
+		}
+		if err != nil {
+			item.Status = ItemStatusFailed
+			item.Reason = ItemFailedReason
+			res.Failed++
+			res.Items = append(res.Items, item)
+			retries = 0 // as for a dead-lettered event
+			continue
+		}
+
+		if stored.Created {
+			item.Status = ItemStatusCreated
 			res.Created++
 		} else {
+			item.Status = ItemStatusDuplicate
 			res.Duplicates++
 		}
+		res.Items = append(res.Items, item)
 	}
 
 	return res, nil
 }
 
+func (uc *StoreEventUseCase) bulkCreateAtomic(ctx context.Context, in BulkCreateEventsInput) (BulkCreateEventsResult, error) {
+	prepared := make([]StoreEventInput, len(in.Events))
+	for i, ev := range in.Events {
+		p, err := uc.prepareInput(ev)
+		if err != nil {
+			return BulkCreateEventsResult{}, fmt.Errorf("event at index %d: %w", i, err)
+		}
+		prepared[i] = p
+	}
+
+	var res BulkCreateEventsResult
+	var created []*domain.Event
+
+	err := uc.repo.WithinTransaction(ctx, func(repo ports.EventRepositoryPort) error {
+		// Reset on every attempt so a rolled back run never leaks counts.
+		res = BulkCreateEventsResult{
+			Items: make([]BulkItemResult, 0, len(in.Events)),
+		}
+		created = created[:0]
+
+		for i, ev := range prepared {
+			e, err := uc.build(ctx, ev)
+			if errors.Is(err, ErrEventSampledOut) {
+				res.Items = append(res.Items, BulkItemResult{Index: i, EventID: ev.EventID, Status: ItemStatusSampled})
+				res.Sampled++
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			ok, err := repo.InsertEvent(ctx, e)
+			if err != nil {
+				return err
+			}
+
+			item := BulkItemResult{Index: i, EventID: e.EventID, Status: ItemStatusDuplicate}
+			if ok {
+				item.Status = ItemStatusCreated
+				res.Created++
+				created = append(created, e)
+			} else {
+				res.Duplicates++
+			}
+			res.Items = append(res.Items, item)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return BulkCreateEventsResult{}, err
+	}
+
+	// Only observed once the transaction has committed.
+	uc.notify(ctx, created...)
+
+	return res, nil
+}
+
+// prepareInput normalizes the input (tags, event_id) and validates the result.
+func (uc *StoreEventUseCase) prepareInput(in StoreEventInput) (StoreEventInput, error) {
+	in.Tags = tag.Normalize(in.Tags)
+
+	if in.EventID != "" {
+		id, err := uuid.Parse(in.EventID)
+		if err != nil {
+			return in, &ValidationError{Violations: []FieldViolation{
+				{Field: "event_id", Message: "must be a valid UUID"},
+			}}
+		}
+		in.EventID = id.String()
+	}
+
+	if err := uc.validateInput(in); err != nil {
+		return in, err
+	}
+
+	return in, nil
+}
+
 func (uc *StoreEventUseCase) validateInput(in StoreEventInput) error {
 
-	if in.EventName == "" || in.Channel == "" || in.UserID == "" {
+	if in.EventName == "" || in.Channel == "" || (in.UserID == "" && in.AnonymousID == "") {
 		return ErrInvalidEvent
 	}
 
-	now := time.Now().Unix()
-	if in.Timestamp > now {
+	if in.TimestampMs != 0 && in.Timestamp != 0 && in.Timestamp != time.UnixMilli(in.TimestampMs).Unix() {
+		return &ValidationError{Violations: []FieldViolation{
+			{Field: "timestamp_ms", Message: "does not match timestamp"},
+		}}
+	}
+
+	now := uc.now()
+
+	// Second granularity, same tolerance as before millisecond support.
+	if in.eventTime().Unix() > now.Unix() {
 		return ErrFutureTime
 	}
 
+	if uc.maxEventAge > 0 && in.replay == nil && in.eventTime().Before(now.Add(-uc.maxEventAge)) {
+		return ErrEventTooOld
+	}
+
+	if violations := uc.tagViolations(in.Tags); len(violations) > 0 {
+		return &ValidationError{Cause: ErrInvalidTags, Violations: violations}
+	}
+
+	if err := uc.checkMetadataLimits(in.Metadata); err != nil {
+		return err
+	}
+
+	if err := uc.checkMetadataSchema(in.EventName, in.Metadata); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
 )
 
 // Fake repo
@@ -14,12 +15,31 @@ type fakeBulkRepo struct {
 	InsertCalls []*domain.Event
 	Results     []bool
 	Err         error
+
+	// ErrAfter makes InsertEvent fail once this many inserts succeeded (0 = never).
+	ErrAfter   int
+	TxCalls    int
+	Committed  bool
+	RolledBack bool
+}
+
+func (f *fakeBulkRepo) WithinTransaction(ctx context.Context, fn func(repo ports.EventRepositoryPort) error) error {
+	f.TxCalls++
+	if err := fn(f); err != nil {
+		f.RolledBack = true
+		return err
+	}
+	f.Committed = true
+	return nil
 }
 
 func (f *fakeBulkRepo) InsertEvent(ctx context.Context, e *domain.Event) (bool, error) {
 	if f.Err != nil {
 		return false, f.Err
 	}
+	if f.ErrAfter > 0 && len(f.InsertCalls) >= f.ErrAfter {
+		return false, errors.New("db failure")
+	}
 	f.InsertCalls = append(f.InsertCalls, e)
 
 	if len(f.Results) == 0 {
@@ -144,7 +164,7 @@ func TestBulkCreateEvents_MixedCreatedAndDuplicate(t *testing.T) {
 	}
 }
 
-func TestBulkCreateEvents_ValidationErrorInOneEvent(t *testing.T) {
+func TestBulkCreateEvents_InvalidEventReportedPerItem(t *testing.T) {
 	ctx := context.Background()
 
 	repo := &fakeBulkRepo{}
@@ -176,16 +196,279 @@ func TestBulkCreateEvents_ValidationErrorInOneEvent(t *testing.T) {
 		},
 	}
 
+	res, err := uc.BulkCreateEvents(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Created != 2 {
+		t.Errorf("expected Created=2, got %d", res.Created)
+	}
+	if res.Invalid != 1 {
+		t.Errorf("expected Invalid=1, got %d", res.Invalid)
+	}
+
+	if len(repo.InsertCalls) != 2 {
+		t.Errorf("expected 2 InsertEvent calls, got %d", len(repo.InsertCalls))
+	}
+
+	if len(res.Items) != 3 {
+		t.Fatalf("expected 3 item results, got %d", len(res.Items))
+	}
+	wantStatuses := []string{ItemStatusCreated, ItemStatusInvalid, ItemStatusCreated}
+	for i, item := range res.Items {
+		if item.Index != i {
+			t.Errorf("item %d: expected index %d, got %d", i, i, item.Index)
+		}
+		if item.Status != wantStatuses[i] {
+			t.Errorf("item %d: expected status %s, got %s", i, wantStatuses[i], item.Status)
+		}
+	}
+	if res.Items[1].Reason != ErrInvalidEvent.Error() {
+		t.Errorf("expected reason %q, got %q", ErrInvalidEvent.Error(), res.Items[1].Reason)
+	}
+}
+
+func TestBulkCreateEvents_RepositoryErrorIsPerItem(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &fakeBulkRepo{ErrAfter: 1}
+	uc := NewStoreEventUseCase(repo)
+
+	now := time.Now().Add(-time.Minute).Unix()
+
+	input := BulkCreateEventsInput{
+		Events: []StoreEventInput{
+			{EventName: "product_view", Channel: "web", UserID: "user_1", Timestamp: now},
+			{EventName: "", Channel: "web", UserID: "user_2", Timestamp: now},
+			{EventName: "purchase", Channel: "web", UserID: "user_3", Timestamp: now},
+		},
+	}
+
+	res, err := uc.BulkCreateEvents(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Created != 1 || res.Invalid != 1 || res.Failed != 1 {
+		t.Fatalf("unexpected counts: %+v", res)
+	}
+	want := []string{ItemStatusCreated, ItemStatusInvalid, ItemStatusFailed}
+	for i, item := range res.Items {
+		if item.Index != i || item.Status != want[i] {
+			t.Fatalf("item %d: expected %s, got %+v", i, want[i], item)
+		}
+	}
+	if res.Items[2].Reason != ItemFailedReason {
+		t.Fatalf("unexpected failed item: %+v", res.Items[2])
+	}
+}
+
+func TestBulkCreateEvents_ValidatesOnce(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &fakeBulkRepo{}
+	uc := NewStoreEventUseCase(repo, WithMaxEventAge(time.Hour))
+
+	// The event is just young enough at the first reading of the clock and
+	// too old at any later one.
+	base := time.Now()
+	calls := 0
+	uc.now = func() time.Time {
+		calls++
+		if calls == 1 {
+			return base
+		}
+		return base.Add(time.Minute)
+	}
+
+	res, err := uc.BulkCreateEvents(ctx, BulkCreateEventsInput{
+		Events: []StoreEventInput{
+			{EventName: "product_view", Channel: "web", UserID: "user_1", Timestamp: base.Add(-time.Hour + 30*time.Second).Unix()},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Created != 1 || res.Items[0].Status != ItemStatusCreated {
+		t.Fatalf("expected the event to be created, got %+v", res.Items)
+	}
+}
+
+func TestBulkCreateEvents_Atomic_AllCommitted(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &fakeBulkRepo{Results: []bool{true, false}}
+	uc := NewStoreEventUseCase(repo)
+
+	now := time.Now().Add(-time.Minute).Unix()
+
+	input := BulkCreateEventsInput{
+		Atomic: true,
+		Events: []StoreEventInput{
+			{EventName: "product_view", Channel: "web", UserID: "user_1", Timestamp: now},
+			{EventName: "product_view", Channel: "web", UserID: "user_1", Timestamp: now},
+		},
+	}
+
+	res, err := uc.BulkCreateEvents(ctx, input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.TxCalls != 1 || !repo.Committed {
+		t.Fatalf("expected a single committed transaction, got calls=%d committed=%v", repo.TxCalls, repo.Committed)
+	}
+	if res.Created != 1 || res.Duplicates != 1 {
+		t.Errorf("expected Created=1 Duplicates=1, got %+v", res)
+	}
+	if len(res.Items) != 2 {
+		t.Errorf("expected 2 item results, got %d", len(res.Items))
+	}
+}
+
+func TestBulkCreateEvents_Atomic_InvalidEventRejectsBatch(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &fakeBulkRepo{}
+	uc := NewStoreEventUseCase(repo)
+
+	now := time.Now().Add(-time.Minute).Unix()
+
+	input := BulkCreateEventsInput{
+		Atomic: true,
+		Events: []StoreEventInput{
+			{EventName: "product_view", Channel: "web", UserID: "user_1", Timestamp: now},
+			{EventName: "", Channel: "web", UserID: "user_2", Timestamp: now},
+		},
+	}
+
 	_, err := uc.BulkCreateEvents(ctx, input)
+	if !errors.Is(err, ErrInvalidEvent) {
+		t.Fatalf("expected ErrInvalidEvent, got %v", err)
+	}
+	if repo.TxCalls != 0 {
+		t.Errorf("expected no transaction for invalid batch, got %d", repo.TxCalls)
+	}
+	if len(repo.InsertCalls) != 0 {
+		t.Errorf("expected 0 InsertEvent calls, got %d", len(repo.InsertCalls))
+	}
+}
+
+func TestBulkCreateEvents_Atomic_DBErrorRollsBack(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &fakeBulkRepo{ErrAfter: 1}
+	uc := NewStoreEventUseCase(repo)
+
+	now := time.Now().Add(-time.Minute).Unix()
+
+	input := BulkCreateEventsInput{
+		Atomic: true,
+		Events: []StoreEventInput{
+			{EventName: "product_view", Channel: "web", UserID: "user_1", Timestamp: now},
+			{EventName: "add_to_cart", Channel: "web", UserID: "user_1", Timestamp: now},
+		},
+	}
+
+	res, err := uc.BulkCreateEvents(ctx, input)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
+	if !repo.RolledBack || repo.Committed {
+		t.Fatalf("expected rollback without commit")
+	}
+	if res.Created != 0 || len(res.Items) != 0 {
+		t.Errorf("expected empty result on rollback, got %+v", res)
+	}
+}
 
-	if !errors.Is(err, ErrInvalidEvent) {
-		t.Errorf("expected ErrInvalidEvent, got %v", err)
+type recordingObserver struct {
+	stored []*domain.Event
+}
+
+func (o *recordingObserver) EventStored(ctx context.Context, e *domain.Event) {
+	o.stored = append(o.stored, e)
+}
+
+func TestBulkCreateEvents_ObserverSeesOnlyCreatedEvents(t *testing.T) {
+	now := time.Now().Add(-time.Minute).Unix()
+	events := []StoreEventInput{
+		{EventName: "order_placed", Channel: "web", UserID: "user_1", Timestamp: now},
+		{EventName: "order_placed", Channel: "web", UserID: "user_2", Timestamp: now},
 	}
 
-	if len(repo.InsertCalls) != 0 {
-		t.Errorf("expected 0 InsertEvent calls, got %d", len(repo.InsertCalls))
+	for _, atomic := range []bool{false, true} {
+		repo := &fakeBulkRepo{Results: []bool{true, false}}
+		obs := &recordingObserver{}
+		uc := NewStoreEventUseCase(repo, WithObserver(obs))
+
+		_, err := uc.BulkCreateEvents(context.Background(), BulkCreateEventsInput{Events: events, Atomic: atomic})
+		if err != nil {
+			t.Fatalf("atomic=%v: unexpected error: %v", atomic, err)
+		}
+		if len(obs.stored) != 1 || obs.stored[0].UserID != "user_1" {
+			t.Fatalf("atomic=%v: expected only the created event to be observed, got %+v", atomic, obs.stored)
+		}
+	}
+}
+
+func TestBulkCreateEvents_NotifiesEveryObserver(t *testing.T) {
+	repo := &fakeBulkRepo{Results: []bool{true}}
+	first, second := &recordingObserver{}, &recordingObserver{}
+	uc := NewStoreEventUseCase(repo, WithObserver(first), WithObserver(second))
+
+	_, err := uc.BulkCreateEvents(context.Background(), BulkCreateEventsInput{Events: []StoreEventInput{
+		{EventName: "order_placed", Channel: "web", UserID: "user_1", Timestamp: time.Now().Add(-time.Minute).Unix()},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(first.stored) != 1 || len(second.stored) != 1 {
+		t.Fatalf("expected both observers to see the event, got %d and %d", len(first.stored), len(second.stored))
+	}
+}
+
+func TestBulkCreateEvents_Atomic_RollbackNotObserved(t *testing.T) {
+	now := time.Now().Add(-time.Minute).Unix()
+	repo := &fakeBulkRepo{ErrAfter: 1}
+	obs := &recordingObserver{}
+	uc := NewStoreEventUseCase(repo, WithObserver(obs))
+
+	_, err := uc.BulkCreateEvents(context.Background(), BulkCreateEventsInput{
+		Events: []StoreEventInput{
+			{EventName: "order_placed", Channel: "web", UserID: "user_1", Timestamp: now},
+			{EventName: "order_placed", Channel: "web", UserID: "user_2", Timestamp: now},
+		},
+		Atomic: true,
+	})
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	if len(obs.stored) != 0 {
+		t.Fatalf("rolled back events must not be observed, got %d", len(obs.stored))
+	}
+}
+
+func TestBulkCreateEvents_SampledItems(t *testing.T) {
+	for _, atomic := range []bool{false, true} {
+		repo := &fakeBulkRepo{}
+		uc := NewStoreEventUseCase(repo, WithSampling(map[string]float64{"heartbeat": 0.000001}))
+
+		now := time.Now().Add(-time.Minute).Unix()
+		res, err := uc.BulkCreateEvents(context.Background(), BulkCreateEventsInput{
+			Atomic: atomic,
+			Events: []StoreEventInput{
+				{EventName: "heartbeat", Channel: "web", UserID: "user_1", Timestamp: now},
+				{EventName: "product_view", Channel: "web", UserID: "user_1", Timestamp: now},
+			},
+		})
+		if err != nil {
+			t.Fatalf("atomic=%v: unexpected error: %v", atomic, err)
+		}
+		if res.Sampled != 1 || res.Created != 1 || len(repo.InsertCalls) != 1 {
+			t.Fatalf("atomic=%v: expected 1 sampled and 1 created, got %+v", atomic, res)
+		}
+		if res.Items[0].Status != ItemStatusSampled || res.Items[1].Status != ItemStatusCreated {
+			t.Fatalf("atomic=%v: unexpected items %+v", atomic, res.Items)
+		}
 	}
 }
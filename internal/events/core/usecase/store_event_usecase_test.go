@@ -3,11 +3,15 @@ package usecase_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
 	"event-metrics-service/internal/events/core/usecase"
+
+	"github.com/google/uuid"
 )
 
 // Fake repository implementing EventRepositoryPort
@@ -19,6 +23,10 @@ func (f *fakeEventRepo) InsertEvent(ctx context.Context, e *domain.Event) (bool,
 	return f.InsertFn(ctx, e)
 }
 
+func (f *fakeEventRepo) WithinTransaction(ctx context.Context, fn func(repo ports.EventRepositoryPort) error) error {
+	return fn(f)
+}
+
 // ------------------------------------------------------------
 // SUCCESS TEST
 // ------------------------------------------------------------
@@ -55,12 +63,12 @@ func TestStoreEvent_Success(t *testing.T) {
 		Timestamp: time.Now().Unix(),
 	}
 
-	created, err := uc.Execute(context.Background(), input)
+	stored, err := uc.Execute(context.Background(), input)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if !created {
+	if !stored.Created {
 		t.Fatalf("expected created=true, got false")
 	}
 	if !called {
@@ -83,12 +91,12 @@ func TestStoreEvent_InvalidEventName(t *testing.T) {
 		Timestamp: time.Now().Unix(),
 	}
 
-	created, err := uc.Execute(context.Background(), input)
+	stored, err := uc.Execute(context.Background(), input)
 
 	if err == nil {
 		t.Fatalf("expected error, got nil")
 	}
-	if created {
+	if stored.Created {
 		t.Fatalf("expected created=false for invalid event")
 	}
 	if !errors.Is(err, usecase.ErrInvalidEvent) {
@@ -109,12 +117,12 @@ func TestStoreEvent_InvalidUserOrChannel(t *testing.T) {
 	}
 
 	for _, in := range tests {
-		created, err := uc.Execute(context.Background(), in)
+		stored, err := uc.Execute(context.Background(), in)
 
 		if err == nil {
 			t.Fatalf("expected error for invalid input, got nil")
 		}
-		if created {
+		if stored.Created {
 			t.Fatalf("expected created=false")
 		}
 		if !errors.Is(err, usecase.ErrInvalidEvent) {
@@ -137,12 +145,12 @@ func TestStoreEvent_FutureTimestamp(t *testing.T) {
 		Timestamp: time.Now().Add(5 * time.Minute).Unix(), // future
 	}
 
-	created, err := uc.Execute(context.Background(), input)
+	stored, err := uc.Execute(context.Background(), input)
 
 	if err == nil {
 		t.Fatalf("expected error for future timestamp, got nil")
 	}
-	if created {
+	if stored.Created {
 		t.Fatalf("expected created=false")
 	}
 	if !errors.Is(err, usecase.ErrFutureTime) {
@@ -150,6 +158,93 @@ func TestStoreEvent_FutureTimestamp(t *testing.T) {
 	}
 }
 
+func TestStoreEvent_TimestampChecksUseTheClock(t *testing.T) {
+	repo := &fakeEventRepo{
+		InsertFn: func(ctx context.Context, e *domain.Event) (bool, error) {
+			return true, nil
+		},
+	}
+	now := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	uc := usecase.NewStoreEventUseCase(repo,
+		usecase.WithMaxEventAge(time.Hour),
+		usecase.WithStoreClock(func() time.Time { return now }),
+	)
+
+	in := usecase.StoreEventInput{EventName: "product_view", Channel: "web", UserID: "user_123"}
+	for _, tt := range []struct {
+		at   time.Time
+		want error
+	}{
+		{now.Add(time.Second), usecase.ErrFutureTime},
+		{now, nil},
+		{now.Add(-time.Hour), nil},
+		{now.Add(-time.Hour - time.Second), usecase.ErrEventTooOld},
+	} {
+		in.Timestamp = tt.at.Unix()
+		if _, err := uc.Execute(context.Background(), in); !errors.Is(err, tt.want) {
+			t.Fatalf("%s: expected %v, got %v", tt.at, tt.want, err)
+		}
+	}
+}
+
+// ------------------------------------------------------------
+// MAX EVENT AGE
+// ------------------------------------------------------------
+func TestStoreEvent_MaxEventAge(t *testing.T) {
+	repo := &fakeEventRepo{
+		InsertFn: func(ctx context.Context, e *domain.Event) (bool, error) {
+			return true, nil
+		},
+	}
+	uc := usecase.NewStoreEventUseCase(repo, usecase.WithMaxEventAge(90*24*time.Hour))
+
+	old := usecase.StoreEventInput{
+		EventName: "product_view",
+		Channel:   "web",
+		UserID:    "user_123",
+		Timestamp: time.Now().Add(-91 * 24 * time.Hour).Unix(),
+	}
+	if _, err := uc.Execute(context.Background(), old); !errors.Is(err, usecase.ErrEventTooOld) {
+		t.Fatalf("expected ErrEventTooOld, got %v", err)
+	}
+
+	recent := old
+	recent.Timestamp = time.Now().Add(-89 * 24 * time.Hour).Unix()
+	if _, err := uc.Execute(context.Background(), recent); err != nil {
+		t.Fatalf("unexpected error within max age: %v", err)
+	}
+}
+
+func TestStoreEvent_ReplaySkipsMaxEventAgeButNotDedupe(t *testing.T) {
+	claimed := map[string]bool{}
+	repo := &fakeEventRepo{
+		InsertFn: func(ctx context.Context, e *domain.Event) (bool, error) {
+			if claimed[e.DedupeKey] {
+				return false, nil
+			}
+			claimed[e.DedupeKey] = true
+			return true, nil
+		},
+	}
+	uc := usecase.NewStoreEventUseCase(repo, usecase.WithMaxEventAge(90*24*time.Hour))
+
+	old := usecase.ReplayEventInput{StoreEventInput: usecase.StoreEventInput{
+		EventName: "product_view",
+		Channel:   "web",
+		UserID:    "user_123",
+		Timestamp: time.Now().Add(-400 * 24 * time.Hour).Unix(),
+	}}
+	for i, want := range []usecase.BulkCreateEventsResult{{Created: 1}, {Duplicates: 1}} {
+		res, err := uc.ReplayEvents(context.Background(), []usecase.ReplayEventInput{old})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.Created != want.Created || res.Duplicates != want.Duplicates || res.Invalid != 0 {
+			t.Fatalf("replay %d: expected %+v, got %+v", i, want, res)
+		}
+	}
+}
+
 // ------------------------------------------------------------
 // DUPLICATE
 // ------------------------------------------------------------
@@ -169,12 +264,12 @@ func TestStoreEvent_Duplicate(t *testing.T) {
 		Timestamp: time.Now().Unix(),
 	}
 
-	created, err := uc.Execute(context.Background(), input)
+	stored, err := uc.Execute(context.Background(), input)
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if created {
+	if stored.Created {
 		t.Fatalf("expected created=false for duplicate")
 	}
 }
@@ -198,15 +293,505 @@ func TestStoreEvent_RepositoryError(t *testing.T) {
 		Timestamp: time.Now().Unix(),
 	}
 
-	created, err := uc.Execute(context.Background(), input)
+	stored, err := uc.Execute(context.Background(), input)
 
 	if err == nil {
 		t.Fatalf("expected db error, got nil")
 	}
-	if created {
+	if stored.Created {
 		t.Fatalf("expected created=false")
 	}
 	if err.Error() != "db failure" {
 		t.Fatalf("expected 'db failure', got %v", err)
 	}
 }
+
+// ------------------------------------------------------------
+// CLIENT-SUPPLIED EVENT ID
+// ------------------------------------------------------------
+func TestStoreEvent_EventIDUsedAsDedupeKey(t *testing.T) {
+	var stored *domain.Event
+
+	repo := &fakeEventRepo{
+		InsertFn: func(ctx context.Context, e *domain.Event) (bool, error) {
+			stored = e
+			return true, nil
+		},
+	}
+
+	uc := usecase.NewStoreEventUseCase(repo)
+
+	input := usecase.StoreEventInput{
+		EventID:   "0B5F3C1E-7D4A-4C8E-9A51-2F0D6F1B7E42",
+		EventName: "product_view",
+		Channel:   "web",
+		UserID:    "user_123",
+		Timestamp: time.Now().Unix(),
+	}
+
+	if _, err := uc.Execute(context.Background(), input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	const canonical = "0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42"
+	if stored.EventID != canonical {
+		t.Fatalf("expected canonical event_id %s, got %s", canonical, stored.EventID)
+	}
+	if stored.DedupeKey != "event_id|"+canonical {
+		t.Fatalf("expected dedupe key based on event_id, got %s", stored.DedupeKey)
+	}
+}
+
+func TestStoreEvent_GeneratesEventID(t *testing.T) {
+	var stored []domain.Event
+	repo := &fakeEventRepo{
+		InsertFn: func(ctx context.Context, e *domain.Event) (bool, error) {
+			stored = append(stored, *e)
+			if len(stored) == 1 {
+				return true, nil
+			}
+			e.EventID = stored[0].EventID // duplicate: the repository reports the original
+			return false, nil
+		},
+	}
+	uc := usecase.NewStoreEventUseCase(repo)
+
+	input := usecase.StoreEventInput{
+		EventName: "product_view",
+		Channel:   "web",
+		UserID:    "user_123",
+		Timestamp: time.Now().Unix(),
+	}
+	first, err := uc.Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	id, err := uuid.Parse(first.EventID)
+	if err != nil || id.Version() != 7 || stored[0].EventID != first.EventID {
+		t.Fatalf("expected a stored UUIDv7, got %q (stored %q)", first.EventID, stored[0].EventID)
+	}
+
+	dup, err := uc.Execute(context.Background(), input)
+	if err != nil || dup.Created || dup.EventID != first.EventID {
+		t.Fatalf("expected the duplicate to report %s, got %+v, %v", first.EventID, dup, err)
+	}
+	if stored[1].EventID == first.EventID || stored[1].DedupeKey != stored[0].DedupeKey {
+		t.Fatalf("expected a fresh id and the same content-based dedupe key, got %+v", stored)
+	}
+}
+
+func TestStoreEvent_InvalidEventID(t *testing.T) {
+	repo := &fakeEventRepo{}
+	uc := usecase.NewStoreEventUseCase(repo)
+
+	input := usecase.StoreEventInput{
+		EventID:   "not-a-uuid",
+		EventName: "product_view",
+		Channel:   "web",
+		UserID:    "user_123",
+		Timestamp: time.Now().Unix(),
+	}
+
+	stored, err := uc.Execute(context.Background(), input)
+	if !errors.Is(err, usecase.ErrInvalidEvent) {
+		t.Fatalf("expected ErrInvalidEvent, got %v", err)
+	}
+	if stored.Created {
+		t.Fatalf("expected created=false")
+	}
+}
+
+// ------------------------------------------------------------
+// DEDUPE WINDOW
+// ------------------------------------------------------------
+func TestStoreEvent_DedupeWindowSetsExpiry(t *testing.T) {
+	var stored *domain.Event
+
+	repo := &fakeEventRepo{
+		InsertFn: func(ctx context.Context, e *domain.Event) (bool, error) {
+			stored = e
+			return true, nil
+		},
+	}
+
+	uc := usecase.NewStoreEventUseCase(repo, usecase.WithDedupeWindow(time.Hour))
+
+	before := time.Now().UTC()
+	input := usecase.StoreEventInput{
+		EventName: "product_view",
+		Channel:   "web",
+		UserID:    "user_123",
+		Timestamp: time.Now().Unix(),
+	}
+
+	if _, err := uc.Execute(context.Background(), input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stored.DedupeExpiresAt.Before(before.Add(time.Hour)) || stored.DedupeExpiresAt.After(time.Now().UTC().Add(time.Hour)) {
+		t.Fatalf("expected expiry ~1h from now, got %v", stored.DedupeExpiresAt)
+	}
+}
+
+func TestStoreEvent_DedupeWindowZeroNeverExpires(t *testing.T) {
+	var stored *domain.Event
+
+	repo := &fakeEventRepo{
+		InsertFn: func(ctx context.Context, e *domain.Event) (bool, error) {
+			stored = e
+			return true, nil
+		},
+	}
+
+	uc := usecase.NewStoreEventUseCase(repo, usecase.WithDedupeWindow(0))
+
+	input := usecase.StoreEventInput{
+		EventName: "product_view",
+		Channel:   "web",
+		UserID:    "user_123",
+		Timestamp: time.Now().Unix(),
+	}
+
+	if _, err := uc.Execute(context.Background(), input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !stored.DedupeExpiresAt.IsZero() {
+		t.Fatalf("expected no expiry, got %v", stored.DedupeExpiresAt)
+	}
+}
+
+// ------------------------------------------------------------
+// MILLISECOND TIMESTAMPS
+// ------------------------------------------------------------
+func TestStoreEvent_MillisecondPrecision(t *testing.T) {
+	var stored []*domain.Event
+
+	repo := &fakeEventRepo{
+		InsertFn: func(ctx context.Context, e *domain.Event) (bool, error) {
+			stored = append(stored, e)
+			return true, nil
+		},
+	}
+
+	uc := usecase.NewStoreEventUseCase(repo)
+
+	base := time.Now().Add(-time.Minute).Truncate(time.Second).UnixMilli()
+	for _, ms := range []int64{base + 120, base + 450} {
+		input := usecase.StoreEventInput{
+			EventName:   "product_view",
+			Channel:     "web",
+			UserID:      "user_123",
+			TimestampMs: ms,
+		}
+		if _, err := uc.Execute(context.Background(), input); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if got := stored[0].EventTime.UnixMilli(); got != base+120 {
+		t.Fatalf("expected event_time with ms precision %d, got %d", base+120, got)
+	}
+	if stored[0].DedupeKey == stored[1].DedupeKey {
+		t.Fatalf("events in the same second must have distinct dedupe keys, got %s", stored[0].DedupeKey)
+	}
+}
+
+func TestStoreEvent_WholeSecondDedupeKeyUnchanged(t *testing.T) {
+	var keys []string
+
+	repo := &fakeEventRepo{
+		InsertFn: func(ctx context.Context, e *domain.Event) (bool, error) {
+			keys = append(keys, e.DedupeKey)
+			return true, nil
+		},
+	}
+
+	uc := usecase.NewStoreEventUseCase(repo)
+
+	sec := time.Now().Add(-time.Minute).Unix()
+	inputs := []usecase.StoreEventInput{
+		{EventName: "product_view", Channel: "web", UserID: "user_123", Timestamp: sec},
+		{EventName: "product_view", Channel: "web", UserID: "user_123", TimestampMs: sec * 1000},
+	}
+	for _, in := range inputs {
+		if _, err := uc.Execute(context.Background(), in); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if keys[0] != keys[1] {
+		t.Fatalf("expected same dedupe key for whole-second timestamps, got %s and %s", keys[0], keys[1])
+	}
+}
+
+func TestStoreEvent_TimestampMismatch(t *testing.T) {
+	repo := &fakeEventRepo{}
+	uc := usecase.NewStoreEventUseCase(repo)
+
+	sec := time.Now().Add(-time.Minute).Unix()
+	input := usecase.StoreEventInput{
+		EventName:   "product_view",
+		Channel:     "web",
+		UserID:      "user_123",
+		Timestamp:   sec,
+		TimestampMs: (sec + 5) * 1000,
+	}
+
+	_, err := uc.Execute(context.Background(), input)
+	if !errors.Is(err, usecase.ErrInvalidEvent) {
+		t.Fatalf("expected ErrInvalidEvent, got %v", err)
+	}
+}
+
+// ------------------------------------------------------------
+// ANONYMOUS EVENTS
+// ------------------------------------------------------------
+func TestStoreEvent_AnonymousOnly(t *testing.T) {
+	var stored *domain.Event
+
+	repo := &fakeEventRepo{
+		InsertFn: func(ctx context.Context, e *domain.Event) (bool, error) {
+			stored = e
+			return true, nil
+		},
+	}
+
+	uc := usecase.NewStoreEventUseCase(repo)
+
+	input := usecase.StoreEventInput{
+		EventName:   "product_view",
+		Channel:     "web",
+		AnonymousID: "anon-1",
+		Timestamp:   time.Now().Unix(),
+	}
+
+	if _, err := uc.Execute(context.Background(), input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.UserID != "" || stored.AnonymousID != "anon-1" {
+		t.Fatalf("unexpected stored identity: user=%q anonymous=%q", stored.UserID, stored.AnonymousID)
+	}
+
+	anonKey := stored.DedupeKey
+
+	// A user event with the same id must not collide with the anonymous one.
+	input.UserID = "anon-1"
+	input.AnonymousID = ""
+	if _, err := uc.Execute(context.Background(), input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.DedupeKey == anonKey {
+		t.Fatalf("expected distinct dedupe keys, got %s for both", anonKey)
+	}
+}
+
+// ------------------------------------------------------------
+// ENRICHMENT
+// ------------------------------------------------------------
+type fakeEnricher struct {
+	EnrichFn func(ctx context.Context, e *domain.Event, client domain.ClientInfo) map[string]any
+}
+
+func (f *fakeEnricher) Enrich(ctx context.Context, e *domain.Event, client domain.ClientInfo) map[string]any {
+	return f.EnrichFn(ctx, e, client)
+}
+
+func TestStoreEvent_EnrichersAddFieldsWithoutOverwriting(t *testing.T) {
+	var stored *domain.Event
+
+	repo := &fakeEventRepo{
+		InsertFn: func(ctx context.Context, e *domain.Event) (bool, error) {
+			stored = e
+			return true, nil
+		},
+	}
+
+	geo := &fakeEnricher{
+		EnrichFn: func(ctx context.Context, e *domain.Event, client domain.ClientInfo) map[string]any {
+			if client.IP != "85.100.3.4" {
+				t.Fatalf("unexpected client: %+v", client)
+			}
+			return map[string]any{"geo": "TR", "plan": "derived"}
+		},
+	}
+	ua := &fakeEnricher{
+		EnrichFn: func(ctx context.Context, e *domain.Event, client domain.ClientInfo) map[string]any {
+			return map[string]any{"geo": "overwritten", "ua": "Chrome"}
+		},
+	}
+
+	uc := usecase.NewStoreEventUseCase(repo, usecase.WithEnrichers(geo, ua))
+
+	metadata := map[string]any{"plan": "pro"}
+	input := usecase.StoreEventInput{
+		EventName: "product_view",
+		Channel:   "web",
+		UserID:    "user_123",
+		Timestamp: time.Now().Unix(),
+		Metadata:  metadata,
+		Client:    domain.ClientInfo{IP: "85.100.3.4", UserAgent: "Mozilla/5.0"},
+	}
+
+	if _, err := uc.Execute(context.Background(), input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]any{"plan": "pro", "geo": "TR", "ua": "Chrome"}
+	if len(stored.Metadata) != len(want) {
+		t.Fatalf("expected %v, got %v", want, stored.Metadata)
+	}
+	for k, v := range want {
+		if stored.Metadata[k] != v {
+			t.Fatalf("expected %v, got %v", want, stored.Metadata)
+		}
+	}
+	if len(metadata) != 1 {
+		t.Fatalf("caller's metadata must not be modified, got %v", metadata)
+	}
+}
+
+// ------------------------------------------------------------
+// SAMPLING
+// ------------------------------------------------------------
+func TestStoreEvent_SamplingKeepsShareAndRecordsRate(t *testing.T) {
+	var stored []*domain.Event
+	repo := &fakeEventRepo{
+		InsertFn: func(ctx context.Context, e *domain.Event) (bool, error) {
+			stored = append(stored, e)
+			return true, nil
+		},
+	}
+
+	uc := usecase.NewStoreEventUseCase(repo, usecase.WithSampling(map[string]float64{"heartbeat": 0.1}))
+
+	ts := time.Now().Unix()
+	kept := map[string]bool{}
+	for i := range 2000 {
+		user := fmt.Sprintf("user_%d", i)
+		_, err := uc.Execute(context.Background(), usecase.StoreEventInput{
+			EventName: "heartbeat", Channel: "web", UserID: user, Timestamp: ts,
+		})
+		switch {
+		case err == nil:
+			kept[user] = true
+		case !errors.Is(err, usecase.ErrEventSampledOut):
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(kept) < 150 || len(kept) > 250 {
+		t.Fatalf("expected about 200 of 2000 events kept, got %d", len(kept))
+	}
+	for _, e := range stored {
+		if e.SampleRate != 0.1 || e.Weight() != 10 {
+			t.Fatalf("expected sample rate 0.1, got %v", e.SampleRate)
+		}
+	}
+
+	// A retried event gets the same decision.
+	for _, user := range []string{"user_0", "user_1", "user_2", "user_3"} {
+		_, err := uc.Execute(context.Background(), usecase.StoreEventInput{
+			EventName: "heartbeat", Channel: "web", UserID: user, Timestamp: ts,
+		})
+		if (err == nil) != kept[user] {
+			t.Fatalf("%s: retry decided differently (err=%v)", user, err)
+		}
+	}
+
+	// Other event names are not sampled.
+	stored = nil
+	if _, err := uc.Execute(context.Background(), usecase.StoreEventInput{
+		EventName: "purchase", Channel: "web", UserID: "user_1", Timestamp: ts,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored[0].SampleRate != 0 || stored[0].Weight() != 1 {
+		t.Fatalf("expected unsampled event, got rate %v", stored[0].SampleRate)
+	}
+}
+
+func TestStoreEvent_SamplingValidatesFirst(t *testing.T) {
+	uc := usecase.NewStoreEventUseCase(&fakeEventRepo{}, usecase.WithSampling(map[string]float64{"heartbeat": 0.000001}))
+
+	_, err := uc.Execute(context.Background(), usecase.StoreEventInput{
+		EventName: "heartbeat", UserID: "user_1", Timestamp: time.Now().Unix(),
+	})
+	if !errors.Is(err, usecase.ErrInvalidEvent) {
+		t.Fatalf("expected ErrInvalidEvent, got %v", err)
+	}
+}
+
+type tenantKey struct{}
+
+func TestStoreEvent_TenantScopesEventAndDedupeKey(t *testing.T) {
+	var stored []*domain.Event
+	repo := &fakeEventRepo{
+		InsertFn: func(ctx context.Context, e *domain.Event) (bool, error) {
+			stored = append(stored, e)
+			return true, nil
+		},
+	}
+	uc := usecase.NewStoreEventUseCase(repo, usecase.WithTenantResolver(func(ctx context.Context) string {
+		tenant, _ := ctx.Value(tenantKey{}).(string)
+		return tenant
+	}))
+
+	in := usecase.StoreEventInput{
+		EventID: "0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42", EventName: "purchase", Channel: "web", UserID: "user_1", Timestamp: time.Now().Unix(),
+	}
+	for _, tenant := range []string{"team-a", "team-b", ""} {
+		if _, err := uc.Execute(context.WithValue(context.Background(), tenantKey{}, tenant), in); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	want := []struct{ tenant, dedupeKey string }{
+		{"team-a", "tenant|team-a|event_id|0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42"},
+		{"team-b", "tenant|team-b|event_id|0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42"},
+		{"", "event_id|0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42"},
+	}
+	for i, w := range want {
+		if stored[i].TenantID != w.tenant || stored[i].DedupeKey != w.dedupeKey {
+			t.Fatalf("event %d: expected tenant %q and key %q, got %q and %q", i, w.tenant, w.dedupeKey, stored[i].TenantID, stored[i].DedupeKey)
+		}
+	}
+}
+
+func TestStoreEvent_ReplayKeepsArchivedTenantAndSampleRate(t *testing.T) {
+	var stored []*domain.Event
+	repo := &fakeEventRepo{
+		InsertFn: func(ctx context.Context, e *domain.Event) (bool, error) {
+			stored = append(stored, e)
+			return true, nil
+		},
+	}
+	uc := usecase.NewStoreEventUseCase(repo,
+		usecase.WithTenantResolver(func(ctx context.Context) string { return "admin" }),
+		// Would drop nearly every event if replays were sampled again.
+		usecase.WithSampling(map[string]float64{"heartbeat": 0.000001}),
+	)
+
+	ts := time.Now().Unix()
+	res, err := uc.ReplayEvents(context.Background(), []usecase.ReplayEventInput{
+		{
+			StoreEventInput: usecase.StoreEventInput{EventID: "0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42", EventName: "heartbeat", Channel: "web", UserID: "user_1", Timestamp: ts},
+			TenantID:        "team-a",
+			SampleRate:      0.25,
+		},
+		{
+			StoreEventInput: usecase.StoreEventInput{EventName: "purchase", Channel: "web", UserID: "user_2", Timestamp: ts},
+			SampleRate:      1,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Created != 2 || res.Sampled != 0 || len(stored) != 2 {
+		t.Fatalf("expected both events stored, got %+v", res)
+	}
+	if stored[0].TenantID != "team-a" || stored[0].DedupeKey != "tenant|team-a|event_id|0b5f3c1e-7d4a-4c8e-9a51-2f0d6f1b7e42" || stored[0].SampleRate != 0.25 {
+		t.Fatalf("unexpected first event: %+v", stored[0])
+	}
+	if stored[1].TenantID != "" || stored[1].SampleRate != 0 || stored[1].Weight() != 1 {
+		t.Fatalf("unexpected second event: %+v", stored[1])
+	}
+}
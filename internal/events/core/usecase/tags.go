@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+const (
+	DefaultMaxTags      = 20
+	DefaultMaxTagLength = 64
+)
+
+// tagViolations checks already normalized tags against the configured limits.
+func (uc *StoreEventUseCase) tagViolations(tags []string) []FieldViolation {
+	var violations []FieldViolation
+
+	if uc.maxTags > 0 && len(tags) > uc.maxTags {
+		violations = append(violations, FieldViolation{
+			Field:   "tags",
+			Message: fmt.Sprintf("at most %d tags allowed, got %d", uc.maxTags, len(tags)),
+		})
+	}
+
+	if uc.maxTagLength > 0 {
+		for i, t := range tags {
+			if utf8.RuneCountInString(t) > uc.maxTagLength {
+				violations = append(violations, FieldViolation{
+					Field:   fmt.Sprintf("tags[%d]", i),
+					Message: fmt.Sprintf("exceeds max length %d", uc.maxTagLength),
+				})
+			}
+		}
+	}
+
+	return violations
+}
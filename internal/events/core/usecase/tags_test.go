@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStoreEvent_TagsNormalizedBeforeInsert(t *testing.T) {
+	repo := &fakeBulkRepo{}
+	uc := NewStoreEventUseCase(repo)
+
+	_, err := uc.Execute(context.Background(), StoreEventInput{
+		EventName: "product_view",
+		Channel:   "web",
+		UserID:    "user_1",
+		Timestamp: time.Now().Add(-time.Minute).Unix(),
+		Tags:      []string{"Promo", "promo ", "Summer"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(repo.InsertCalls) != 1 {
+		t.Fatalf("expected 1 insert, got %d", len(repo.InsertCalls))
+	}
+	want := []string{"promo", "summer"}
+	if !reflect.DeepEqual(repo.InsertCalls[0].Tags, want) {
+		t.Fatalf("expected tags %v, got %v", want, repo.InsertCalls[0].Tags)
+	}
+}
+
+func TestStoreEvent_TooManyTags(t *testing.T) {
+	repo := &fakeBulkRepo{}
+	uc := NewStoreEventUseCase(repo, WithTagLimits(2, 0))
+
+	_, err := uc.Execute(context.Background(), StoreEventInput{
+		EventName: "product_view",
+		Channel:   "web",
+		UserID:    "user_1",
+		Timestamp: time.Now().Add(-time.Minute).Unix(),
+		Tags:      []string{"a", "b", "c", "A"}, // 3 after dedupe
+	})
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if !errors.Is(err, ErrInvalidEvent) {
+		t.Fatalf("expected error to wrap ErrInvalidEvent")
+	}
+	if !errors.Is(err, ErrInvalidTags) {
+		t.Fatalf("expected error to wrap ErrInvalidTags")
+	}
+	if len(verr.Violations) != 1 || verr.Violations[0].Field != "tags" {
+		t.Fatalf("unexpected violations: %+v", verr.Violations)
+	}
+	if len(repo.InsertCalls) != 0 {
+		t.Fatalf("expected no insert, got %d", len(repo.InsertCalls))
+	}
+}
+
+func TestStoreEvent_TagTooLong(t *testing.T) {
+	repo := &fakeBulkRepo{}
+	uc := NewStoreEventUseCase(repo, WithTagLimits(0, 5))
+
+	_, err := uc.Execute(context.Background(), StoreEventInput{
+		EventName: "product_view",
+		Channel:   "web",
+		UserID:    "user_1",
+		Timestamp: time.Now().Add(-time.Minute).Unix(),
+		Tags:      []string{"ok", strings.Repeat("x", 6)},
+	})
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if verr.Violations[0].Field != "tags[1]" {
+		t.Fatalf("expected violation on tags[1], got %+v", verr.Violations)
+	}
+	if !errors.Is(err, ErrInvalidTags) {
+		t.Fatalf("expected error to wrap ErrInvalidTags")
+	}
+}
+
+func TestValidationError_Message(t *testing.T) {
+	err := &ValidationError{
+		Cause:      ErrInvalidTags,
+		Violations: []FieldViolation{{Field: "tags[0]", Message: "exceeds max length 5"}},
+	}
+	if got, want := err.Error(), "invalid tags: tags[0]: exceeds max length 5"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	err.Cause = nil
+	if errors.Is(err, ErrInvalidTags) {
+		t.Fatalf("ValidationError without cause must not match ErrInvalidTags")
+	}
+	if got, want := err.Error(), "invalid event: tags[0]: exceeds max length 5"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"event-metrics-service/internal/events/core/domain"
+	"event-metrics-service/internal/events/core/ports"
+)
+
+const (
+	DefaultMaxTails = 10
+
+	// tailBuffer is how many events a tail may fall behind before events
+	// are dropped for it.
+	tailBuffer = 256
+)
+
+var ErrTooManyTails = errors.New("too many live tails")
+
+// TailFilter selects the events of a tail; empty fields match everything.
+type TailFilter struct {
+	EventName  string
+	Channel    string
+	UserID     string
+	CampaignID string
+	TenantID   string
+}
+
+func (f TailFilter) matches(e *domain.Event) bool {
+	return (f.EventName == "" || f.EventName == e.EventName) &&
+		(f.Channel == "" || f.Channel == e.Channel) &&
+		(f.UserID == "" || f.UserID == e.UserID || f.UserID == e.AnonymousID) &&
+		(f.CampaignID == "" || f.CampaignID == e.CampaignID) &&
+		(f.TenantID == "" || f.TenantID == e.TenantID)
+}
+
+// TailEventsUseCase fans newly stored events out to live tails. It observes
+// the store use case, so a tail only sees events stored by this instance.
+// A slow tail loses events rather than slowing down ingest.
+type TailEventsUseCase struct {
+	maxTails int
+	now      func() time.Time
+
+	mu     sync.Mutex
+	tails  map[*Tail]struct{}
+	closed bool
+}
+
+// Tail is one subscription. Events is closed when the tail is closed.
+type Tail struct {
+	Events  <-chan domain.StoredEvent
+	events  chan domain.StoredEvent
+	filter  TailFilter
+	dropped atomic.Int64
+	uc      *TailEventsUseCase
+}
+
+// NewTailEventsUseCase allows up to maxTails concurrent tails; maxTails <= 0
+// means DefaultMaxTails.
+func NewTailEventsUseCase(maxTails int) *TailEventsUseCase {
+	if maxTails <= 0 {
+		maxTails = DefaultMaxTails
+	}
+	return &TailEventsUseCase{
+		maxTails: maxTails,
+		now:      time.Now,
+		tails:    map[*Tail]struct{}{},
+	}
+}
+
+var _ ports.EventObserverPort = (*TailEventsUseCase)(nil)
+
+// Subscribe starts a tail. Callers must Close it.
+func (uc *TailEventsUseCase) Subscribe(f TailFilter) (*Tail, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if uc.closed || len(uc.tails) >= uc.maxTails {
+		return nil, ErrTooManyTails
+	}
+	events := make(chan domain.StoredEvent, tailBuffer)
+	t := &Tail{Events: events, events: events, filter: f, uc: uc}
+	uc.tails[t] = struct{}{}
+	return t, nil
+}
+
+func (uc *TailEventsUseCase) EventStored(_ context.Context, e *domain.Event) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if len(uc.tails) == 0 {
+		return
+	}
+	stored := domain.StoredEvent{Event: *e, ReceivedAt: uc.now().UTC()}
+	for t := range uc.tails {
+		if !t.filter.matches(e) {
+			continue
+		}
+		select {
+		case t.events <- stored:
+		default:
+			t.dropped.Add(1)
+		}
+	}
+}
+
+// Close ends every tail and refuses new ones, e.g. on shutdown.
+func (uc *TailEventsUseCase) Close() {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.closed = true
+	for t := range uc.tails {
+		delete(uc.tails, t)
+		close(t.events)
+	}
+}
+
+// Dropped returns how many matching events were dropped because the tail
+// fell behind, and resets the count.
+func (t *Tail) Dropped() int64 {
+	return t.dropped.Swap(0)
+}
+
+func (t *Tail) Close() {
+	t.uc.mu.Lock()
+	defer t.uc.mu.Unlock()
+	if _, ok := t.uc.tails[t]; ok {
+		delete(t.uc.tails, t)
+		close(t.events)
+	}
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"event-metrics-service/internal/events/core/domain"
+)
+
+func TestTailEvents_FiltersAndFansOut(t *testing.T) {
+	uc := NewTailEventsUseCase(2)
+	all, err := uc.Subscribe(TailFilter{})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	defer all.Close()
+	signups, _ := uc.Subscribe(TailFilter{EventName: "signup", UserID: "anon-1"})
+	defer signups.Close()
+
+	if _, err := uc.Subscribe(TailFilter{}); !errors.Is(err, ErrTooManyTails) {
+		t.Fatalf("expected ErrTooManyTails, got %v", err)
+	}
+
+	ctx := context.Background()
+	uc.EventStored(ctx, &domain.Event{EventName: "page_view", UserID: "u1"})
+	uc.EventStored(ctx, &domain.Event{EventName: "signup", AnonymousID: "anon-1"})
+
+	if n := len(all.Events); n != 2 {
+		t.Fatalf("expected the unfiltered tail to get 2 events, got %d", n)
+	}
+	if n := len(signups.Events); n != 1 {
+		t.Fatalf("expected the filtered tail to get 1 event, got %d", n)
+	}
+	if e := <-signups.Events; e.EventName != "signup" || e.ReceivedAt.IsZero() {
+		t.Fatalf("unexpected event: %+v", e)
+	}
+}
+
+func TestTailEvents_DropsForSlowTails(t *testing.T) {
+	uc := NewTailEventsUseCase(1)
+	tail, _ := uc.Subscribe(TailFilter{})
+
+	for i := 0; i < tailBuffer+3; i++ {
+		uc.EventStored(context.Background(), &domain.Event{EventName: "e"})
+	}
+	if n := tail.Dropped(); n != 3 {
+		t.Fatalf("expected 3 dropped events, got %d", n)
+	}
+	if n := tail.Dropped(); n != 0 {
+		t.Fatalf("expected the count to reset, got %d", n)
+	}
+
+	uc.Close()
+	for range tail.Events {
+	}
+	tail.Close() // after Close of the use case, a no-op
+	if _, err := uc.Subscribe(TailFilter{}); !errors.Is(err, ErrTooManyTails) {
+		t.Fatalf("expected a closed use case to refuse tails, got %v", err)
+	}
+}
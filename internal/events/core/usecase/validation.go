@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldViolation describes a single validation failure on an input field.
+type FieldViolation struct {
+	Field   string // e.g. "tags[2]"
+	Message string
+}
+
+// ValidationError carries field level details. It unwraps to ErrInvalidEvent
+// so callers that only care about the category keep working, and to Cause
+// (e.g. ErrInvalidTags) when a more specific error applies.
+type ValidationError struct {
+	Cause      error
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, fmt.Sprintf("%s: %s", v.Field, v.Message))
+	}
+	prefix := ErrInvalidEvent
+	if e.Cause != nil {
+		prefix = e.Cause
+	}
+	return fmt.Sprintf("%s: %s", prefix.Error(), strings.Join(parts, "; "))
+}
+
+func (e *ValidationError) Unwrap() []error {
+	if e.Cause != nil {
+		return []error{ErrInvalidEvent, e.Cause}
+	}
+	return []error{ErrInvalidEvent}
+}
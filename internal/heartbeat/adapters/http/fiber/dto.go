@@ -0,0 +1,28 @@
+package fiber
+
+import "time"
+
+type RegisterHeartbeatRequest struct {
+	Producer        string `json:"producer" example:"billing-cron"`
+	EventName       string `json:"event_name" example:"billing_heartbeat"`
+	IntervalSeconds int64  `json:"interval_seconds" example:"60"`
+}
+
+type HeartbeatResponse struct {
+	Producer        string     `json:"producer" example:"billing-cron"`
+	EventName       string     `json:"event_name" example:"billing_heartbeat"`
+	IntervalSeconds int64      `json:"interval_seconds" example:"60"`
+	RegisteredAt    time.Time  `json:"registered_at"`
+	LastSeenAt      *time.Time `json:"last_seen_at,omitempty"`
+	State           string     `json:"state,omitempty" example:"healthy" enums:"healthy,missing,pending"`
+	OverdueSeconds  int64      `json:"overdue_seconds,omitempty" example:"0"`
+}
+
+type HeartbeatListResponse struct {
+	Heartbeats []HeartbeatResponse `json:"heartbeats"`
+}
+
+type ErrorResponse struct {
+	Error   string `json:"error" example:"invalid_heartbeat"`
+	Message string `json:"message" example:"producer, event_name and interval_seconds >= 10 are required"`
+}
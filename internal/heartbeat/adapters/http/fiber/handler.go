@@ -0,0 +1,134 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"event-metrics-service/internal/heartbeat/core/domain"
+	"event-metrics-service/internal/heartbeat/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HeartbeatUseCase interface {
+	Register(ctx context.Context, in usecase.RegisterHeartbeatInput) (domain.Heartbeat, error)
+	Unregister(ctx context.Context, producer, eventName string) error
+	Statuses(ctx context.Context) ([]domain.HeartbeatStatus, error)
+}
+
+type HeartbeatHandler struct {
+	uc HeartbeatUseCase
+}
+
+func NewHeartbeatHandler(uc HeartbeatUseCase) *HeartbeatHandler {
+	return &HeartbeatHandler{uc: uc}
+}
+
+// RegisterHeartbeat godoc
+// @Summary Register an expected heartbeat
+// @Description Heartbeats are ordinary events sent to POST /events with event_name and user_id = producer.
+// @Description Re-registering updates the interval.
+// @Tags Heartbeats
+// @Accept json
+// @Produce json
+// @Param request body RegisterHeartbeatRequest true "Heartbeat"
+// @Success 200 {object} HeartbeatResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse "missing or unknown API key (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "missing scope events:write"
+// @Failure 500 {object} ErrorResponse
+// @Router /heartbeats [put]
+func (h *HeartbeatHandler) RegisterHeartbeat(c *fiber.Ctx) error {
+	var req RegisterHeartbeatRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid_json",
+		})
+	}
+
+	hb, err := h.uc.Register(c.UserContext(), usecase.RegisterHeartbeatInput{
+		Producer:  req.Producer,
+		EventName: req.EventName,
+		Interval:  time.Duration(req.IntervalSeconds) * time.Second,
+	})
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidHeartbeat) {
+			return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+				Error:   "invalid_heartbeat",
+				Message: "producer, event_name and interval_seconds >= 10 are required",
+			})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error: "internal_server_error",
+		})
+	}
+
+	return c.JSON(toHeartbeatResponse(domain.HeartbeatStatus{Heartbeat: hb}))
+}
+
+// ListHeartbeats godoc
+// @Summary List heartbeats with their current health
+// @Tags Heartbeats
+// @Produce json
+// @Success 200 {object} HeartbeatListResponse
+// @Failure 401 {object} ErrorResponse "missing or unknown API key (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "missing scope metrics:read"
+// @Failure 500 {object} ErrorResponse
+// @Router /heartbeats [get]
+func (h *HeartbeatHandler) ListHeartbeats(c *fiber.Ctx) error {
+	statuses, err := h.uc.Statuses(c.UserContext())
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error: "internal_server_error",
+		})
+	}
+
+	resp := HeartbeatListResponse{Heartbeats: make([]HeartbeatResponse, 0, len(statuses))}
+	for _, s := range statuses {
+		resp.Heartbeats = append(resp.Heartbeats, toHeartbeatResponse(s))
+	}
+
+	return c.JSON(resp)
+}
+
+// UnregisterHeartbeat godoc
+// @Summary Stop monitoring a heartbeat
+// @Tags Heartbeats
+// @Param producer path string true "Producer"
+// @Param event_name path string true "Event name"
+// @Success 204
+// @Failure 401 {object} ErrorResponse "missing or unknown API key (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "missing scope events:write"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /heartbeats/{producer}/{event_name} [delete]
+func (h *HeartbeatHandler) UnregisterHeartbeat(c *fiber.Ctx) error {
+	err := h.uc.Unregister(c.UserContext(), c.Params("producer"), c.Params("event_name"))
+	if err != nil {
+		if errors.Is(err, usecase.ErrHeartbeatNotFound) {
+			return c.Status(http.StatusNotFound).JSON(ErrorResponse{
+				Error:   "heartbeat_not_found",
+				Message: err.Error(),
+			})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error: "internal_server_error",
+		})
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+func toHeartbeatResponse(s domain.HeartbeatStatus) HeartbeatResponse {
+	return HeartbeatResponse{
+		Producer:        s.Producer,
+		EventName:       s.EventName,
+		IntervalSeconds: int64(s.Interval / time.Second),
+		RegisteredAt:    s.RegisteredAt,
+		LastSeenAt:      s.LastSeenAt,
+		State:           string(s.State),
+		OverdueSeconds:  int64(s.Overdue / time.Second),
+	}
+}
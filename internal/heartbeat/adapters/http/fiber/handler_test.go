@@ -0,0 +1,131 @@
+package fiber_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpadapter "event-metrics-service/internal/heartbeat/adapters/http/fiber"
+	"event-metrics-service/internal/heartbeat/core/domain"
+	"event-metrics-service/internal/heartbeat/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeHeartbeatUseCase struct {
+	RegisterFn   func(ctx context.Context, in usecase.RegisterHeartbeatInput) (domain.Heartbeat, error)
+	UnregisterFn func(ctx context.Context, producer, eventName string) error
+	StatusesFn   func(ctx context.Context) ([]domain.HeartbeatStatus, error)
+}
+
+func (f *fakeHeartbeatUseCase) Register(ctx context.Context, in usecase.RegisterHeartbeatInput) (domain.Heartbeat, error) {
+	return f.RegisterFn(ctx, in)
+}
+
+func (f *fakeHeartbeatUseCase) Unregister(ctx context.Context, producer, eventName string) error {
+	return f.UnregisterFn(ctx, producer, eventName)
+}
+
+func (f *fakeHeartbeatUseCase) Statuses(ctx context.Context) ([]domain.HeartbeatStatus, error) {
+	return f.StatusesFn(ctx)
+}
+
+func setupApp(uc httpadapter.HeartbeatUseCase) *fiber.App {
+	app := fiber.New()
+	h := httpadapter.NewHeartbeatHandler(uc)
+	app.Get("/heartbeats", h.ListHeartbeats)
+	app.Put("/heartbeats", h.RegisterHeartbeat)
+	app.Delete("/heartbeats/:producer/:event_name", h.UnregisterHeartbeat)
+	return app
+}
+
+func doRequest(t *testing.T, app *fiber.App, method, path, body string) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	return resp
+}
+
+func TestRegisterHeartbeat_Success(t *testing.T) {
+	uc := &fakeHeartbeatUseCase{
+		RegisterFn: func(ctx context.Context, in usecase.RegisterHeartbeatInput) (domain.Heartbeat, error) {
+			if in.Producer != "billing-cron" || in.Interval != time.Minute {
+				t.Fatalf("unexpected input: %+v", in)
+			}
+			return domain.Heartbeat{Producer: in.Producer, EventName: in.EventName, Interval: in.Interval}, nil
+		},
+	}
+	app := setupApp(uc)
+
+	resp := doRequest(t, app, http.MethodPut, "/heartbeats",
+		`{"producer":"billing-cron","event_name":"heartbeat","interval_seconds":60}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestRegisterHeartbeat_Invalid(t *testing.T) {
+	uc := &fakeHeartbeatUseCase{
+		RegisterFn: func(ctx context.Context, in usecase.RegisterHeartbeatInput) (domain.Heartbeat, error) {
+			return domain.Heartbeat{}, usecase.ErrInvalidHeartbeat
+		},
+	}
+	app := setupApp(uc)
+
+	resp := doRequest(t, app, http.MethodPut, "/heartbeats", `{"producer":"billing-cron"}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestListHeartbeats(t *testing.T) {
+	seen := time.Now().Add(-time.Hour)
+	uc := &fakeHeartbeatUseCase{
+		StatusesFn: func(ctx context.Context) ([]domain.HeartbeatStatus, error) {
+			return []domain.HeartbeatStatus{{
+				Heartbeat: domain.Heartbeat{Producer: "billing-cron", EventName: "heartbeat", Interval: time.Minute, LastSeenAt: &seen},
+				State:     domain.StateMissing,
+				Overdue:   59 * time.Minute,
+			}}, nil
+		},
+	}
+	app := setupApp(uc)
+
+	resp := doRequest(t, app, http.MethodGet, "/heartbeats", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body httpadapter.HeartbeatListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if len(body.Heartbeats) != 1 || body.Heartbeats[0].State != "missing" || body.Heartbeats[0].OverdueSeconds != 3540 {
+		t.Fatalf("unexpected heartbeats: %+v", body.Heartbeats)
+	}
+}
+
+func TestUnregisterHeartbeat_NotFound(t *testing.T) {
+	uc := &fakeHeartbeatUseCase{
+		UnregisterFn: func(ctx context.Context, producer, eventName string) error {
+			if producer != "billing-cron" || eventName != "heartbeat" {
+				t.Fatalf("unexpected params %s %s", producer, eventName)
+			}
+			return usecase.ErrHeartbeatNotFound
+		},
+	}
+	app := setupApp(uc)
+
+	resp := doRequest(t, app, http.MethodDelete, "/heartbeats/billing-cron/heartbeat", "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", resp.StatusCode)
+	}
+}
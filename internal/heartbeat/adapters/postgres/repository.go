@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"event-metrics-service/internal/heartbeat/core/domain"
+	"event-metrics-service/internal/heartbeat/core/ports"
+)
+
+type RowScanner interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+type DB interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+// ColumnTenantID is gated (see the migration module): migration 027 adds it
+// and moves the key of heartbeats to (tenant_id, producer, event_name).
+const ColumnTenantID = "heartbeats.tenant_id"
+
+var GatedColumns = []string{ColumnTenantID}
+
+// ColumnGate reports whether queries may rely on a gated column.
+type ColumnGate interface {
+	Reads(column string) bool
+	Writes(column string) bool
+}
+
+// errTenantColumnGated fails tenant-scoped requests while heartbeats has no
+// tenant_id, rather than serving every tenant's heartbeats.
+var errTenantColumnGated = errors.New("tenant-scoped heartbeats while " + ColumnTenantID + " is not usable")
+
+type HeartbeatRepository struct {
+	db   DB
+	gate ColumnGate
+}
+
+type RepositoryOption func(*HeartbeatRepository)
+
+// WithColumnGate keeps to the heartbeats of no tenant, keyed by producer and
+// event name alone, while heartbeats.tenant_id is gated off or not migrated
+// yet.
+func WithColumnGate(g ColumnGate) RepositoryOption {
+	return func(r *HeartbeatRepository) {
+		r.gate = g
+	}
+}
+
+func NewHeartbeatRepository(db DB, opts ...RepositoryOption) *HeartbeatRepository {
+	r := &HeartbeatRepository{db: db}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+var _ ports.HeartbeatRepositoryPort = (*HeartbeatRepository)(nil)
+
+// Re-registering only changes the interval; registered_at is kept so a
+// producer that never reported stays missing.
+const upsertHeartbeatSQL = `
+INSERT INTO heartbeats (tenant_id, producer, event_name, interval_seconds, registered_at)
+VALUES ($5, $1, $2, $3, $4)
+ON CONFLICT (tenant_id, producer, event_name) DO UPDATE
+SET interval_seconds = EXCLUDED.interval_seconds`
+
+const deleteHeartbeatSQL = `
+DELETE FROM heartbeats WHERE producer = $1 AND event_name = $2 AND tenant_id = $3`
+
+// Latest matching event of the heartbeat's tenant; served by
+// idx_events_tenant_name_time. %s filters the heartbeats.
+const listHeartbeatsSQL = `
+SELECT
+    h.tenant_id,
+    h.producer,
+    h.event_name,
+    h.interval_seconds,
+    h.registered_at,
+    (SELECT MAX(e.event_time) FROM events e
+      WHERE e.tenant_id = h.tenant_id AND e.event_name = h.event_name AND e.user_id = h.producer) AS last_seen_at
+FROM heartbeats h%s
+ORDER BY h.tenant_id, h.producer, h.event_name`
+
+// The legacy statements are used before migration 027.
+const (
+	legacyUpsertHeartbeatSQL = `
+INSERT INTO heartbeats (producer, event_name, interval_seconds, registered_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (producer, event_name) DO UPDATE
+SET interval_seconds = EXCLUDED.interval_seconds`
+
+	legacyDeleteHeartbeatSQL = `
+DELETE FROM heartbeats WHERE producer = $1 AND event_name = $2`
+
+	legacyListHeartbeatsSQL = `
+SELECT
+    '',
+    h.producer,
+    h.event_name,
+    h.interval_seconds,
+    h.registered_at,
+    (SELECT MAX(e.event_time) FROM events e
+      WHERE e.event_name = h.event_name AND e.user_id = h.producer) AS last_seen_at
+FROM heartbeats h
+ORDER BY h.producer, h.event_name`
+)
+
+func (r *HeartbeatRepository) UpsertHeartbeat(ctx context.Context, h domain.Heartbeat) error {
+	query, args := upsertHeartbeatSQL, []any{
+		h.Producer,
+		h.EventName,
+		int64(h.Interval / time.Second),
+		h.RegisteredAt,
+		h.TenantID,
+	}
+	if r.gate != nil && !r.gate.Writes(ColumnTenantID) {
+		if h.TenantID != "" {
+			return errTenantColumnGated
+		}
+		query, args = legacyUpsertHeartbeatSQL, args[:4]
+	}
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (r *HeartbeatRepository) DeleteHeartbeat(ctx context.Context, tenantID, producer, eventName string) (bool, error) {
+	query, args := deleteHeartbeatSQL, []any{producer, eventName, tenantID}
+	if r.gate != nil && !r.gate.Writes(ColumnTenantID) {
+		if tenantID != "" {
+			return false, errTenantColumnGated
+		}
+		query, args = legacyDeleteHeartbeatSQL, args[:2]
+	}
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *HeartbeatRepository) ListHeartbeats(ctx context.Context, tenantID *string) ([]domain.Heartbeat, error) {
+	var (
+		query string
+		args  []any
+	)
+	switch {
+	case r.gate != nil && !r.gate.Reads(ColumnTenantID):
+		if tenantID != nil && *tenantID != "" {
+			return nil, errTenantColumnGated
+		}
+		query = legacyListHeartbeatsSQL
+	case tenantID != nil:
+		query, args = fmt.Sprintf(listHeartbeatsSQL, "\nWHERE h.tenant_id = $1"), []any{*tenantID}
+	default:
+		query = fmt.Sprintf(listHeartbeatsSQL, "")
+	}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Heartbeat
+	for rows.Next() {
+		var (
+			h        domain.Heartbeat
+			seconds  int64
+			lastSeen sql.NullTime
+		)
+		if err := rows.Scan(&h.TenantID, &h.Producer, &h.EventName, &seconds, &h.RegisteredAt, &lastSeen); err != nil {
+			return nil, err
+		}
+		h.Interval = time.Duration(seconds) * time.Second
+		h.RegisteredAt = h.RegisteredAt.UTC()
+		if lastSeen.Valid {
+			t := lastSeen.Time.UTC()
+			h.LastSeenAt = &t
+		}
+		out = append(out, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
@@ -0,0 +1,193 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/heartbeat/core/domain"
+)
+
+type fakeRowScanner struct {
+	rows [][]any
+	i    int
+}
+
+func (f *fakeRowScanner) Next() bool {
+	return f.i < len(f.rows)
+}
+
+func (f *fakeRowScanner) Scan(dest ...any) error {
+	row := f.rows[f.i]
+	if len(dest) != len(row) {
+		return errors.New("dest length mismatch")
+	}
+	for i := range dest {
+		switch d := dest[i].(type) {
+		case *int64:
+			*d = row[i].(int64)
+		case *string:
+			*d = row[i].(string)
+		case *time.Time:
+			*d = row[i].(time.Time)
+		case *sql.NullTime:
+			if row[i] != nil {
+				*d = sql.NullTime{Time: row[i].(time.Time), Valid: true}
+			}
+		default:
+			return errors.New("unsupported dest type")
+		}
+	}
+	f.i++
+	return nil
+}
+
+func (f *fakeRowScanner) Err() error   { return nil }
+func (f *fakeRowScanner) Close() error { return nil }
+
+type fakeResult struct{ n int64 }
+
+func (r fakeResult) LastInsertId() (int64, error) { return 0, nil }
+func (r fakeResult) RowsAffected() (int64, error) { return r.n, nil }
+
+type fakeDB struct {
+	ExecFn  func(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryFn func(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+func (f *fakeDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return f.ExecFn(ctx, query, args...)
+}
+
+func (f *fakeDB) QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error) {
+	return f.QueryFn(ctx, query, args...)
+}
+
+func TestHeartbeatRepository_Upsert(t *testing.T) {
+	var gotArgs []any
+	db := &fakeDB{
+		ExecFn: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+			if !strings.Contains(query, "ON CONFLICT (tenant_id, producer, event_name)") {
+				t.Fatalf("expected upsert query, got %s", query)
+			}
+			gotArgs = args
+			return fakeResult{n: 1}, nil
+		},
+	}
+	repo := NewHeartbeatRepository(db)
+
+	err := repo.UpsertHeartbeat(context.Background(), domain.Heartbeat{
+		TenantID:  "acme",
+		Producer:  "billing-cron",
+		EventName: "heartbeat",
+		Interval:  90 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotArgs[2] != int64(90) || gotArgs[4] != "acme" {
+		t.Fatalf("expected interval_seconds=90 in tenant acme, got %v", gotArgs)
+	}
+}
+
+func TestHeartbeatRepository_Delete(t *testing.T) {
+	db := &fakeDB{
+		ExecFn: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+			return fakeResult{n: 0}, nil
+		},
+	}
+	repo := NewHeartbeatRepository(db)
+
+	deleted, err := repo.DeleteHeartbeat(context.Background(), "acme", "billing-cron", "heartbeat")
+	if err != nil || deleted {
+		t.Fatalf("expected deleted=false, got %v err=%v", deleted, err)
+	}
+}
+
+func TestHeartbeatRepository_List(t *testing.T) {
+	registered := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	seen := registered.Add(time.Hour)
+
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if !strings.Contains(query, "WHERE h.tenant_id = $1") || !strings.Contains(query, "e.tenant_id = h.tenant_id") || args[0] != "acme" {
+				t.Fatalf("expected the heartbeats and events of acme, got %s %v", query, args)
+			}
+			return &fakeRowScanner{rows: [][]any{
+				{"acme", "billing-cron", "heartbeat", int64(60), registered, seen},
+				{"acme", "sync-job", "heartbeat", int64(300), registered, nil},
+			}}, nil
+		},
+	}
+	repo := NewHeartbeatRepository(db)
+
+	tenant := "acme"
+	hbs, err := repo.ListHeartbeats(context.Background(), &tenant)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hbs) != 2 {
+		t.Fatalf("expected 2 heartbeats, got %d", len(hbs))
+	}
+	if hbs[0].Interval != time.Minute || hbs[0].LastSeenAt == nil || !hbs[0].LastSeenAt.Equal(seen) {
+		t.Fatalf("unexpected first heartbeat: %+v", hbs[0])
+	}
+	if hbs[1].LastSeenAt != nil {
+		t.Fatalf("expected nil LastSeenAt for never-seen heartbeat")
+	}
+}
+
+func TestHeartbeatRepository_ListEveryTenant(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if strings.Contains(query, "WHERE h.tenant_id") || len(args) != 0 {
+				t.Fatalf("expected no tenant filter, got %s %v", query, args)
+			}
+			return &fakeRowScanner{}, nil
+		},
+	}
+	if _, err := NewHeartbeatRepository(db).ListHeartbeats(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+type fakeColumnGate map[string]bool
+
+func (g fakeColumnGate) Reads(column string) bool  { return g[column] }
+func (g fakeColumnGate) Writes(column string) bool { return g[column] }
+
+func TestHeartbeatRepository_FollowsColumnGate(t *testing.T) {
+	db := &fakeDB{
+		ExecFn: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+			if strings.Contains(query, "tenant_id") {
+				t.Fatalf("expected the legacy statement, got %s", query)
+			}
+			return fakeResult{n: 1}, nil
+		},
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if strings.Contains(query, "tenant_id") {
+				t.Fatalf("expected the legacy statement, got %s", query)
+			}
+			return &fakeRowScanner{}, nil
+		},
+	}
+	repo := NewHeartbeatRepository(db, WithColumnGate(fakeColumnGate{}))
+
+	if err := repo.UpsertHeartbeat(context.Background(), domain.Heartbeat{Producer: "billing-cron", EventName: "heartbeat"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.ListHeartbeats(context.Background(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A tenant's requests are refused rather than served every tenant's rows.
+	tenant := "acme"
+	if _, err := repo.ListHeartbeats(context.Background(), &tenant); !errors.Is(err, errTenantColumnGated) {
+		t.Fatalf("expected errTenantColumnGated, got %v", err)
+	}
+	if _, err := repo.DeleteHeartbeat(context.Background(), "acme", "billing-cron", "heartbeat"); !errors.Is(err, errTenantColumnGated) {
+		t.Fatalf("expected errTenantColumnGated, got %v", err)
+	}
+}
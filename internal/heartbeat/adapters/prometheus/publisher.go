@@ -0,0 +1,65 @@
+package prometheus
+
+import (
+	"event-metrics-service/internal/heartbeat/core/domain"
+	"event-metrics-service/internal/heartbeat/core/ports"
+	"event-metrics-service/internal/telemetry"
+)
+
+const (
+	metricHeartbeatUp       = "ems_heartbeat_up"
+	metricHeartbeatLastSeen = "ems_heartbeat_last_seen_seconds"
+	metricHeartbeatChecks   = "ems_heartbeat_checks_total"
+	metricProducerUp        = "ems_heartbeat_producer_up"
+)
+
+type HeartbeatStatusPublisher struct {
+	reg *telemetry.Registry
+}
+
+func NewHeartbeatStatusPublisher(reg *telemetry.Registry) *HeartbeatStatusPublisher {
+	return &HeartbeatStatusPublisher{reg: reg}
+}
+
+var _ ports.HeartbeatStatusPublisherPort = (*HeartbeatStatusPublisher)(nil)
+
+// PublishHeartbeatStatuses exposes per-heartbeat and per-producer health.
+// Uptime over a window is rate(ems_heartbeat_checks_total{state="up"}) divided
+// by rate(ems_heartbeat_checks_total).
+func (p *HeartbeatStatusPublisher) PublishHeartbeatStatuses(statuses []domain.HeartbeatStatus) {
+	for _, name := range []string{metricHeartbeatUp, metricHeartbeatLastSeen, metricProducerUp} {
+		p.reg.Reset(name)
+	}
+
+	// Producers are per tenant; tenant is empty without tenant isolation.
+	type producer struct{ tenant, name string }
+	producerUp := map[producer]bool{}
+	for _, s := range statuses {
+		l := telemetry.Labels{"tenant": s.TenantID, "producer": s.Producer, "event_name": s.EventName}
+
+		up, state := 0.0, "down"
+		if s.Up() {
+			up, state = 1, "up"
+		}
+		p.reg.SetGauge(metricHeartbeatUp, "1 if the heartbeat arrived within its expected interval (plus grace).", l, up)
+		p.reg.AddCounter(metricHeartbeatChecks, "Heartbeat checks by outcome.",
+			telemetry.Labels{"tenant": s.TenantID, "producer": s.Producer, "event_name": s.EventName, "state": state}, 1)
+
+		if s.LastSeenAt != nil {
+			p.reg.SetGauge(metricHeartbeatLastSeen, "Unix time of the latest heartbeat event.", l, float64(s.LastSeenAt.Unix()))
+		}
+
+		key := producer{tenant: s.TenantID, name: s.Producer}
+		if prev, ok := producerUp[key]; !ok || prev {
+			producerUp[key] = s.Up()
+		}
+	}
+
+	for key, up := range producerUp {
+		v := 0.0
+		if up {
+			v = 1
+		}
+		p.reg.SetGauge(metricProducerUp, "1 if all of the producer's heartbeats are healthy.", telemetry.Labels{"tenant": key.tenant, "producer": key.name}, v)
+	}
+}
@@ -0,0 +1,61 @@
+package domain
+
+import "time"
+
+// Heartbeat is an event a producer promises to send at least once per
+// Interval. Heartbeat events are ordinary events whose event_name is
+// EventName and whose user_id is Producer.
+type Heartbeat struct {
+	TenantID     string // '' without tenant isolation
+	Producer     string
+	EventName    string
+	Interval     time.Duration
+	RegisteredAt time.Time
+	LastSeenAt   *time.Time // nil = never received
+}
+
+type State string
+
+const (
+	StateHealthy State = "healthy"
+	StateMissing State = "missing"
+	// StatePending: registered but not yet seen, still within the first interval.
+	StatePending State = "pending"
+)
+
+type HeartbeatStatus struct {
+	Heartbeat
+	State     State
+	CheckedAt time.Time
+	// Overdue is how long past the deadline (interval + grace) the heartbeat is.
+	Overdue time.Duration
+}
+
+// Up reports whether the producer is considered alive.
+func (s HeartbeatStatus) Up() bool {
+	return s.State != StateMissing
+}
+
+// Evaluate decides the heartbeat's state at now, allowing grace on top of
+// the expected interval.
+func (h Heartbeat) Evaluate(now time.Time, grace time.Duration) HeartbeatStatus {
+	st := HeartbeatStatus{Heartbeat: h, CheckedAt: now}
+
+	since := h.RegisteredAt
+	if h.LastSeenAt != nil {
+		since = *h.LastSeenAt
+	}
+
+	deadline := since.Add(h.Interval + grace)
+	switch {
+	case !now.After(deadline) && h.LastSeenAt == nil:
+		st.State = StatePending
+	case !now.After(deadline):
+		st.State = StateHealthy
+	default:
+		st.State = StateMissing
+		st.Overdue = now.Sub(deadline)
+	}
+
+	return st
+}
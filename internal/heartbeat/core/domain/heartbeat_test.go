@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHeartbeat_Evaluate(t *testing.T) {
+	now := time.Date(2025, 12, 7, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) *time.Time {
+		t := now.Add(-d)
+		return &t
+	}
+
+	tests := []struct {
+		name    string
+		hb      Heartbeat
+		want    State
+		overdue time.Duration
+	}{
+		{
+			name: "seen within interval",
+			hb:   Heartbeat{Interval: time.Minute, RegisteredAt: now.Add(-time.Hour), LastSeenAt: ago(30 * time.Second)},
+			want: StateHealthy,
+		},
+		{
+			name: "late but within grace",
+			hb:   Heartbeat{Interval: time.Minute, RegisteredAt: now.Add(-time.Hour), LastSeenAt: ago(80 * time.Second)},
+			want: StateHealthy,
+		},
+		{
+			name:    "missing",
+			hb:      Heartbeat{Interval: time.Minute, RegisteredAt: now.Add(-time.Hour), LastSeenAt: ago(5 * time.Minute)},
+			want:    StateMissing,
+			overdue: 3*time.Minute + 30*time.Second,
+		},
+		{
+			name: "never seen, just registered",
+			hb:   Heartbeat{Interval: time.Minute, RegisteredAt: now.Add(-10 * time.Second)},
+			want: StatePending,
+		},
+		{
+			name:    "never seen, past first interval",
+			hb:      Heartbeat{Interval: time.Minute, RegisteredAt: now.Add(-2 * time.Minute)},
+			want:    StateMissing,
+			overdue: 30 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.hb.Evaluate(now, 30*time.Second)
+			if got.State != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got.State)
+			}
+			if got.Overdue != tt.overdue {
+				t.Fatalf("expected overdue %v, got %v", tt.overdue, got.Overdue)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+
+	"event-metrics-service/internal/heartbeat/core/domain"
+)
+
+type HeartbeatRepositoryPort interface {
+	// UpsertHeartbeat registers a heartbeat or updates its interval.
+	UpsertHeartbeat(ctx context.Context, h domain.Heartbeat) error
+	// DeleteHeartbeat returns false when nothing was registered.
+	DeleteHeartbeat(ctx context.Context, tenantID, producer, eventName string) (bool, error)
+	// ListHeartbeats returns the registrations of tenantID, or of every
+	// tenant when nil, with LastSeenAt filled in from the latest matching
+	// event of the same tenant.
+	ListHeartbeats(ctx context.Context, tenantID *string) ([]domain.Heartbeat, error)
+}
+
+type HeartbeatStatusPublisherPort interface {
+	PublishHeartbeatStatuses(statuses []domain.HeartbeatStatus)
+}
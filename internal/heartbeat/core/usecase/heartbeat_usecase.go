@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"event-metrics-service/internal/heartbeat/core/domain"
+	"event-metrics-service/internal/heartbeat/core/ports"
+)
+
+var (
+	ErrInvalidHeartbeat  = errors.New("invalid heartbeat")
+	ErrHeartbeatNotFound = errors.New("heartbeat not found")
+)
+
+// MinHeartbeatInterval keeps registrations coarser than the monitor can check.
+const MinHeartbeatInterval = 10 * time.Second
+
+type HeartbeatUseCase struct {
+	repo     ports.HeartbeatRepositoryPort
+	grace    time.Duration
+	now      func() time.Time
+	tenantOf func(ctx context.Context) string
+}
+
+type Option func(*HeartbeatUseCase)
+
+// WithTenantResolver keeps the heartbeats of each tenant apart: callers
+// register, remove and list their own tenant's heartbeats, whose events
+// are looked up in that tenant. The monitor still checks every tenant.
+func WithTenantResolver(fn func(ctx context.Context) string) Option {
+	return func(uc *HeartbeatUseCase) {
+		uc.tenantOf = fn
+	}
+}
+
+func NewHeartbeatUseCase(repo ports.HeartbeatRepositoryPort, grace time.Duration, opts ...Option) *HeartbeatUseCase {
+	uc := &HeartbeatUseCase{repo: repo, grace: grace, now: time.Now}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+func (uc *HeartbeatUseCase) tenant(ctx context.Context) string {
+	if uc.tenantOf == nil {
+		return ""
+	}
+	return uc.tenantOf(ctx)
+}
+
+type RegisterHeartbeatInput struct {
+	Producer  string
+	EventName string
+	Interval  time.Duration
+}
+
+func (uc *HeartbeatUseCase) Register(ctx context.Context, in RegisterHeartbeatInput) (domain.Heartbeat, error) {
+	if in.Producer == "" || in.EventName == "" || in.Interval < MinHeartbeatInterval {
+		return domain.Heartbeat{}, ErrInvalidHeartbeat
+	}
+
+	h := domain.Heartbeat{
+		TenantID:     uc.tenant(ctx),
+		Producer:     in.Producer,
+		EventName:    in.EventName,
+		Interval:     in.Interval,
+		RegisteredAt: uc.now().UTC(),
+	}
+	if err := uc.repo.UpsertHeartbeat(ctx, h); err != nil {
+		return domain.Heartbeat{}, err
+	}
+
+	return h, nil
+}
+
+func (uc *HeartbeatUseCase) Unregister(ctx context.Context, producer, eventName string) error {
+	deleted, err := uc.repo.DeleteHeartbeat(ctx, uc.tenant(ctx), producer, eventName)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrHeartbeatNotFound
+	}
+	return nil
+}
+
+// Statuses evaluates the heartbeats of the caller's tenant at the current
+// time.
+func (uc *HeartbeatUseCase) Statuses(ctx context.Context) ([]domain.HeartbeatStatus, error) {
+	tenant := uc.tenant(ctx)
+	return uc.statuses(ctx, &tenant)
+}
+
+// statuses evaluates the heartbeats of tenant, or of every tenant when nil.
+func (uc *HeartbeatUseCase) statuses(ctx context.Context, tenant *string) ([]domain.HeartbeatStatus, error) {
+	hbs, err := uc.repo.ListHeartbeats(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	now := uc.now().UTC()
+	out := make([]domain.HeartbeatStatus, 0, len(hbs))
+	for _, h := range hbs {
+		out = append(out, h.Evaluate(now, uc.grace))
+	}
+	return out, nil
+}
@@ -0,0 +1,199 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/heartbeat/core/domain"
+	"event-metrics-service/internal/heartbeat/core/usecase"
+)
+
+type fakeHeartbeatRepo struct {
+	UpsertFn func(ctx context.Context, h domain.Heartbeat) error
+	DeleteFn func(ctx context.Context, tenantID, producer, eventName string) (bool, error)
+	ListFn   func(ctx context.Context, tenantID *string) ([]domain.Heartbeat, error)
+}
+
+func (f *fakeHeartbeatRepo) UpsertHeartbeat(ctx context.Context, h domain.Heartbeat) error {
+	if f.UpsertFn != nil {
+		return f.UpsertFn(ctx, h)
+	}
+	return nil
+}
+
+func (f *fakeHeartbeatRepo) DeleteHeartbeat(ctx context.Context, tenantID, producer, eventName string) (bool, error) {
+	if f.DeleteFn != nil {
+		return f.DeleteFn(ctx, tenantID, producer, eventName)
+	}
+	return true, nil
+}
+
+func (f *fakeHeartbeatRepo) ListHeartbeats(ctx context.Context, tenantID *string) ([]domain.Heartbeat, error) {
+	if f.ListFn != nil {
+		return f.ListFn(ctx, tenantID)
+	}
+	return nil, nil
+}
+
+type fakeStatusPublisher struct {
+	published [][]domain.HeartbeatStatus
+}
+
+func (f *fakeStatusPublisher) PublishHeartbeatStatuses(s []domain.HeartbeatStatus) {
+	f.published = append(f.published, s)
+}
+
+// ------------------------------------------------------------
+// REGISTER / UNREGISTER
+// ------------------------------------------------------------
+
+func TestRegisterHeartbeat_Success(t *testing.T) {
+	var stored domain.Heartbeat
+	repo := &fakeHeartbeatRepo{
+		UpsertFn: func(ctx context.Context, h domain.Heartbeat) error {
+			stored = h
+			return nil
+		},
+	}
+	uc := usecase.NewHeartbeatUseCase(repo, 0)
+
+	_, err := uc.Register(context.Background(), usecase.RegisterHeartbeatInput{
+		Producer:  "billing-cron",
+		EventName: "heartbeat",
+		Interval:  time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.Producer != "billing-cron" || stored.Interval != time.Minute || stored.RegisteredAt.IsZero() {
+		t.Fatalf("unexpected stored heartbeat: %+v", stored)
+	}
+}
+
+func TestHeartbeats_PerTenant(t *testing.T) {
+	type tenantKey struct{}
+	var (
+		stored        domain.Heartbeat
+		deletedTenant string
+		listedTenant  *string
+	)
+	repo := &fakeHeartbeatRepo{
+		UpsertFn: func(ctx context.Context, h domain.Heartbeat) error {
+			stored = h
+			return nil
+		},
+		DeleteFn: func(ctx context.Context, tenantID, producer, eventName string) (bool, error) {
+			deletedTenant = tenantID
+			return true, nil
+		},
+		ListFn: func(ctx context.Context, tenantID *string) ([]domain.Heartbeat, error) {
+			listedTenant = tenantID
+			return nil, nil
+		},
+	}
+	uc := usecase.NewHeartbeatUseCase(repo, 0, usecase.WithTenantResolver(func(ctx context.Context) string {
+		tenant, _ := ctx.Value(tenantKey{}).(string)
+		return tenant
+	}))
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+
+	if _, err := uc.Register(ctx, usecase.RegisterHeartbeatInput{Producer: "billing-cron", EventName: "heartbeat", Interval: time.Minute}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := uc.Unregister(ctx, "billing-cron", "heartbeat"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := uc.Statuses(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.TenantID != "acme" || deletedTenant != "acme" || listedTenant == nil || *listedTenant != "acme" {
+		t.Fatalf("expected every call scoped to acme, got %q %q %v", stored.TenantID, deletedTenant, listedTenant)
+	}
+}
+
+func TestRegisterHeartbeat_Invalid(t *testing.T) {
+	uc := usecase.NewHeartbeatUseCase(&fakeHeartbeatRepo{}, 0)
+
+	inputs := []usecase.RegisterHeartbeatInput{
+		{EventName: "heartbeat", Interval: time.Minute},
+		{Producer: "billing-cron", Interval: time.Minute},
+		{Producer: "billing-cron", EventName: "heartbeat", Interval: time.Second},
+	}
+	for _, in := range inputs {
+		if _, err := uc.Register(context.Background(), in); !errors.Is(err, usecase.ErrInvalidHeartbeat) {
+			t.Fatalf("expected ErrInvalidHeartbeat for %+v, got %v", in, err)
+		}
+	}
+}
+
+func TestUnregisterHeartbeat_NotFound(t *testing.T) {
+	repo := &fakeHeartbeatRepo{
+		DeleteFn: func(ctx context.Context, tenantID, producer, eventName string) (bool, error) {
+			return false, nil
+		},
+	}
+	uc := usecase.NewHeartbeatUseCase(repo, 0)
+
+	if err := uc.Unregister(context.Background(), "x", "y"); !errors.Is(err, usecase.ErrHeartbeatNotFound) {
+		t.Fatalf("expected ErrHeartbeatNotFound, got %v", err)
+	}
+}
+
+// ------------------------------------------------------------
+// MONITOR
+// ------------------------------------------------------------
+
+func TestMonitorHeartbeats_ReportsNewlyMissingOnce(t *testing.T) {
+	stale := time.Now().Add(-time.Hour)
+	fresh := time.Now()
+
+	repo := &fakeHeartbeatRepo{
+		ListFn: func(ctx context.Context, tenantID *string) ([]domain.Heartbeat, error) {
+			if tenantID != nil {
+				t.Fatalf("expected the monitor to check every tenant, got %q", *tenantID)
+			}
+			return []domain.Heartbeat{
+				{Producer: "billing-cron", EventName: "heartbeat", Interval: time.Minute, RegisteredAt: stale, LastSeenAt: &stale},
+				{Producer: "sync-job", EventName: "heartbeat", Interval: time.Minute, RegisteredAt: stale, LastSeenAt: &fresh},
+			}, nil
+		},
+	}
+	pub := &fakeStatusPublisher{}
+	monitor := usecase.NewMonitorHeartbeatsUseCase(usecase.NewHeartbeatUseCase(repo, 0), pub)
+
+	missing, err := monitor.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(missing) != 1 || missing[0].Producer != "billing-cron" {
+		t.Fatalf("expected billing-cron to be reported missing, got %+v", missing)
+	}
+
+	missing, _ = monitor.Execute(context.Background())
+	if len(missing) != 0 {
+		t.Fatalf("expected no new missing heartbeats on second check, got %+v", missing)
+	}
+
+	if len(pub.published) != 2 || len(pub.published[0]) != 2 {
+		t.Fatalf("expected statuses to be published on every check, got %+v", pub.published)
+	}
+}
+
+func TestMonitorHeartbeats_RepositoryError(t *testing.T) {
+	repo := &fakeHeartbeatRepo{
+		ListFn: func(ctx context.Context, tenantID *string) ([]domain.Heartbeat, error) {
+			return nil, errors.New("db down")
+		},
+	}
+	pub := &fakeStatusPublisher{}
+	monitor := usecase.NewMonitorHeartbeatsUseCase(usecase.NewHeartbeatUseCase(repo, 0), pub)
+
+	if _, err := monitor.Execute(context.Background()); err == nil {
+		t.Fatalf("expected error")
+	}
+	if len(pub.published) != 0 {
+		t.Fatalf("nothing should be published on error")
+	}
+}
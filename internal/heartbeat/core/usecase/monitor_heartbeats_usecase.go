@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"event-metrics-service/internal/heartbeat/core/domain"
+	"event-metrics-service/internal/heartbeat/core/ports"
+)
+
+// MonitorHeartbeatsUseCase periodically evaluates all heartbeats, publishes
+// their status and reports heartbeats that just went missing.
+type MonitorHeartbeatsUseCase struct {
+	heartbeats *HeartbeatUseCase
+	publisher  ports.HeartbeatStatusPublisherPort
+	missing    map[string]bool
+}
+
+func NewMonitorHeartbeatsUseCase(heartbeats *HeartbeatUseCase, publisher ports.HeartbeatStatusPublisherPort) *MonitorHeartbeatsUseCase {
+	return &MonitorHeartbeatsUseCase{
+		heartbeats: heartbeats,
+		publisher:  publisher,
+		missing:    map[string]bool{},
+	}
+}
+
+// Execute runs a single check and returns the heartbeats that transitioned
+// to missing since the previous check.
+func (uc *MonitorHeartbeatsUseCase) Execute(ctx context.Context) ([]domain.HeartbeatStatus, error) {
+	statuses, err := uc.heartbeats.statuses(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.publisher.PublishHeartbeatStatuses(statuses)
+
+	var newlyMissing []domain.HeartbeatStatus
+	missing := make(map[string]bool, len(statuses))
+	for _, s := range statuses {
+		key := s.TenantID + "|" + s.Producer + "|" + s.EventName
+		if s.State == domain.StateMissing {
+			missing[key] = true
+			if !uc.missing[key] {
+				newlyMissing = append(newlyMissing, s)
+			}
+		}
+	}
+	uc.missing = missing
+
+	return newlyMissing, nil
+}
+
+// Run checks on every tick until ctx is cancelled. Newly missing heartbeats
+// go to onMissing, errors to onError; neither stops the loop.
+func (uc *MonitorHeartbeatsUseCase) Run(ctx context.Context, interval time.Duration, onMissing func(domain.HeartbeatStatus), onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		newlyMissing, err := uc.Execute(ctx)
+		if err != nil && onError != nil {
+			onError(err)
+		}
+		if onMissing != nil {
+			for _, s := range newlyMissing {
+				onMissing(s)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package fiber
+
+import "time"
+
+type IdentifyRequest struct {
+	AnonymousID string `json:"anonymous_id" example:"3f9c2a7e-anon"`
+	UserID      string `json:"user_id" example:"user_123"`
+}
+
+type IdentityLinkResponse struct {
+	AnonymousID  string    `json:"anonymous_id" example:"3f9c2a7e-anon"`
+	UserID       string    `json:"user_id" example:"user_123"`
+	IdentifiedAt time.Time `json:"identified_at"`
+	StitchFrom   time.Time `json:"stitch_from"`
+}
+
+type ErrorResponse struct {
+	Error   string `json:"error" example:"invalid_identity"`
+	Message string `json:"message" example:"anonymous_id and user_id are required"`
+}
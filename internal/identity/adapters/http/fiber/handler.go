@@ -0,0 +1,78 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"event-metrics-service/internal/identity/core/domain"
+	"event-metrics-service/internal/identity/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type IdentifyUseCase interface {
+	Identify(ctx context.Context, in usecase.IdentifyInput) (domain.IdentityLink, error)
+}
+
+type IdentityHandler struct {
+	uc IdentifyUseCase
+}
+
+func NewIdentityHandler(uc IdentifyUseCase) *IdentityHandler {
+	return &IdentityHandler{uc: uc}
+}
+
+// Identify godoc
+// @Summary Attribute an anonymous visitor to a user
+// @Description Anonymous events (anonymous_id without user_id) from the stitching window before this call onwards
+// @Description count as user_id in unique-user metrics. Repeating the call is idempotent.
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Param request body IdentifyRequest true "Identity"
+// @Success 200 {object} IdentityLinkResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse "missing or unknown API key (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "missing scope events:write"
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /identify [post]
+func (h *IdentityHandler) Identify(c *fiber.Ctx) error {
+	var req IdentifyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid_json",
+		})
+	}
+
+	link, err := h.uc.Identify(c.UserContext(), usecase.IdentifyInput{
+		AnonymousID: req.AnonymousID,
+		UserID:      req.UserID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidIdentity):
+			return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+				Error:   "invalid_identity",
+				Message: "anonymous_id and user_id are required",
+			})
+		case errors.Is(err, usecase.ErrIdentityConflict):
+			return c.Status(http.StatusConflict).JSON(ErrorResponse{
+				Error:   "identity_conflict",
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+				Error: "internal_server_error",
+			})
+		}
+	}
+
+	return c.JSON(IdentityLinkResponse{
+		AnonymousID:  link.AnonymousID,
+		UserID:       link.UserID,
+		IdentifiedAt: link.IdentifiedAt,
+		StitchFrom:   link.StitchFrom,
+	})
+}
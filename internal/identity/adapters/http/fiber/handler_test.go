@@ -0,0 +1,86 @@
+package fiber_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpadapter "event-metrics-service/internal/identity/adapters/http/fiber"
+	"event-metrics-service/internal/identity/core/domain"
+	"event-metrics-service/internal/identity/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeIdentifyUseCase struct {
+	IdentifyFn func(ctx context.Context, in usecase.IdentifyInput) (domain.IdentityLink, error)
+}
+
+func (f *fakeIdentifyUseCase) Identify(ctx context.Context, in usecase.IdentifyInput) (domain.IdentityLink, error) {
+	return f.IdentifyFn(ctx, in)
+}
+
+func doIdentify(t *testing.T, uc httpadapter.IdentifyUseCase, body string) *http.Response {
+	t.Helper()
+	app := fiber.New()
+	app.Post("/identify", httpadapter.NewIdentityHandler(uc).Identify)
+
+	req := httptest.NewRequest(http.MethodPost, "/identify", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	return resp
+}
+
+func TestIdentify_Success(t *testing.T) {
+	now := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	uc := &fakeIdentifyUseCase{
+		IdentifyFn: func(ctx context.Context, in usecase.IdentifyInput) (domain.IdentityLink, error) {
+			if in.AnonymousID != "anon-1" || in.UserID != "user-1" {
+				t.Fatalf("unexpected input: %+v", in)
+			}
+			return domain.IdentityLink{AnonymousID: in.AnonymousID, UserID: in.UserID, IdentifiedAt: now, StitchFrom: now.Add(-time.Hour)}, nil
+		},
+	}
+
+	resp := doIdentify(t, uc, `{"anonymous_id":"anon-1","user_id":"user-1"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body httpadapter.IdentityLinkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if body.UserID != "user-1" || !body.StitchFrom.Equal(now.Add(-time.Hour)) {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+}
+
+func TestIdentify_Errors(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantStatus int
+	}{
+		{usecase.ErrInvalidIdentity, http.StatusBadRequest},
+		{usecase.ErrIdentityConflict, http.StatusConflict},
+		{context.DeadlineExceeded, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		uc := &fakeIdentifyUseCase{
+			IdentifyFn: func(ctx context.Context, in usecase.IdentifyInput) (domain.IdentityLink, error) {
+				return domain.IdentityLink{}, tt.err
+			},
+		}
+		if resp := doIdentify(t, uc, `{"anonymous_id":"a","user_id":"u"}`); resp.StatusCode != tt.wantStatus {
+			t.Fatalf("%v: expected %d, got %d", tt.err, tt.wantStatus, resp.StatusCode)
+		}
+	}
+}
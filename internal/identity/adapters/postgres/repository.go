@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"event-metrics-service/internal/identity/core/domain"
+	"event-metrics-service/internal/identity/core/ports"
+)
+
+type RowScanner interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+type DB interface {
+	QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+// ColumnTenantID is gated (see the migration module): migration 026 adds it
+// and moves the key of identity_links to (tenant_id, anonymous_id).
+const ColumnTenantID = "identity_links.tenant_id"
+
+var GatedColumns = []string{ColumnTenantID}
+
+// ColumnGate reports whether a gated column may be written.
+type ColumnGate interface {
+	Writes(column string) bool
+}
+
+// errTenantColumnGated fails tenant-scoped links while identity_links has no
+// tenant_id, rather than linking the anonymous id for every tenant.
+var errTenantColumnGated = errors.New("tenant-scoped identity link while " + ColumnTenantID + " is not writable")
+
+type IdentityRepository struct {
+	db   DB
+	gate ColumnGate
+}
+
+type RepositoryOption func(*IdentityRepository)
+
+// WithColumnGate links without a tenant, keyed by anonymous_id alone, while
+// identity_links.tenant_id is gated off or not migrated yet.
+func WithColumnGate(g ColumnGate) RepositoryOption {
+	return func(r *IdentityRepository) {
+		r.gate = g
+	}
+}
+
+func NewIdentityRepository(db DB, opts ...RepositoryOption) *IdentityRepository {
+	r := &IdentityRepository{db: db}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+var _ ports.IdentityRepositoryPort = (*IdentityRepository)(nil)
+
+// The no-op update makes RETURNING yield the existing row on conflict.
+const linkIdentitySQL = `
+INSERT INTO identity_links (tenant_id, anonymous_id, user_id, identified_at, stitch_from)
+VALUES ($5, $1, $2, $3, $4)
+ON CONFLICT (tenant_id, anonymous_id) DO UPDATE
+SET anonymous_id = identity_links.anonymous_id
+RETURNING anonymous_id, user_id, identified_at, stitch_from`
+
+// legacyLinkIdentitySQL is used before migration 026.
+const legacyLinkIdentitySQL = `
+INSERT INTO identity_links (anonymous_id, user_id, identified_at, stitch_from)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (anonymous_id) DO UPDATE
+SET anonymous_id = identity_links.anonymous_id
+RETURNING anonymous_id, user_id, identified_at, stitch_from`
+
+func (r *IdentityRepository) LinkIdentity(ctx context.Context, l domain.IdentityLink) (domain.IdentityLink, error) {
+	query, args := linkIdentitySQL, []any{l.AnonymousID, l.UserID, l.IdentifiedAt, l.StitchFrom, l.TenantID}
+	if r.gate != nil && !r.gate.Writes(ColumnTenantID) {
+		if l.TenantID != "" {
+			return domain.IdentityLink{}, errTenantColumnGated
+		}
+		query, args = legacyLinkIdentitySQL, args[:4]
+	}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return domain.IdentityLink{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return domain.IdentityLink{}, err
+		}
+		return domain.IdentityLink{}, errors.New("link identity: no row returned")
+	}
+
+	out := domain.IdentityLink{TenantID: l.TenantID}
+	if err := rows.Scan(&out.AnonymousID, &out.UserID, &out.IdentifiedAt, &out.StitchFrom); err != nil {
+		return domain.IdentityLink{}, err
+	}
+	out.IdentifiedAt = out.IdentifiedAt.UTC()
+	out.StitchFrom = out.StitchFrom.UTC()
+
+	return out, rows.Err()
+}
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/identity/core/domain"
+)
+
+type fakeRowScanner struct {
+	rows [][]any
+	i    int
+}
+
+func (f *fakeRowScanner) Next() bool {
+	return f.i < len(f.rows)
+}
+
+func (f *fakeRowScanner) Scan(dest ...any) error {
+	row := f.rows[f.i]
+	if len(dest) != len(row) {
+		return errors.New("dest length mismatch")
+	}
+	for i := range dest {
+		switch d := dest[i].(type) {
+		case *string:
+			*d = row[i].(string)
+		case *time.Time:
+			*d = row[i].(time.Time)
+		default:
+			return errors.New("unsupported dest type")
+		}
+	}
+	f.i++
+	return nil
+}
+
+func (f *fakeRowScanner) Err() error   { return nil }
+func (f *fakeRowScanner) Close() error { return nil }
+
+type fakeDB struct {
+	QueryFn func(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+func (f *fakeDB) QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error) {
+	return f.QueryFn(ctx, query, args...)
+}
+
+func TestIdentityRepository_LinkReturnsExisting(t *testing.T) {
+	existingAt := time.Date(2025, 12, 1, 10, 0, 0, 0, time.UTC)
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if !strings.Contains(query, "ON CONFLICT (tenant_id, anonymous_id)") || !strings.Contains(query, "RETURNING") {
+				t.Fatalf("unexpected query: %s", query)
+			}
+			if args[4] != "acme" {
+				t.Fatalf("expected the link in tenant acme, got %v", args)
+			}
+			// The row already exists for another user.
+			return &fakeRowScanner{rows: [][]any{
+				{"anon-1", "user-1", existingAt, existingAt.Add(-time.Hour)},
+			}}, nil
+		},
+	}
+
+	now := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	got, err := NewIdentityRepository(db).LinkIdentity(context.Background(), domain.IdentityLink{
+		TenantID: "acme", AnonymousID: "anon-1", UserID: "user-2", IdentifiedAt: now, StitchFrom: now,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.TenantID != "acme" || got.UserID != "user-1" || !got.IdentifiedAt.Equal(existingAt) {
+		t.Fatalf("expected the stored link, got %+v", got)
+	}
+}
+
+type fakeColumnGate map[string]bool
+
+func (g fakeColumnGate) Writes(column string) bool { return g[column] }
+
+func TestIdentityRepository_FollowsColumnGate(t *testing.T) {
+	now := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if strings.Contains(query, "tenant_id") || len(args) != 4 {
+				t.Fatalf("expected the legacy statement, got %s %v", query, args)
+			}
+			return &fakeRowScanner{rows: [][]any{{"anon-1", "user-1", now, now}}}, nil
+		},
+	}
+	repo := NewIdentityRepository(db, WithColumnGate(fakeColumnGate{}))
+
+	if _, err := repo.LinkIdentity(context.Background(), domain.IdentityLink{AnonymousID: "anon-1", UserID: "user-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A tenant's link is refused rather than stored for every tenant.
+	_, err := repo.LinkIdentity(context.Background(), domain.IdentityLink{TenantID: "acme", AnonymousID: "anon-1", UserID: "user-1"})
+	if !errors.Is(err, errTenantColumnGated) {
+		t.Fatalf("expected errTenantColumnGated, got %v", err)
+	}
+}
+
+func TestIdentityRepository_DBError(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return nil, errors.New("db failure")
+		},
+	}
+
+	if _, err := NewIdentityRepository(db).LinkIdentity(context.Background(), domain.IdentityLink{}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
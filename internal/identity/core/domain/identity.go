@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// IdentityLink attributes an anonymous visitor to a known user. Anonymous
+// events with an event time at or after StitchFrom count as UserID in
+// unique-user metrics; older ones stay anonymous.
+type IdentityLink struct {
+	TenantID     string // '' without tenant isolation
+	AnonymousID  string
+	UserID       string
+	IdentifiedAt time.Time
+	StitchFrom   time.Time // IdentifiedAt minus the stitching window
+}
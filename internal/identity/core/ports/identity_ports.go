@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"event-metrics-service/internal/identity/core/domain"
+)
+
+type IdentityRepositoryPort interface {
+	// LinkIdentity stores l unless the anonymous id is already linked in
+	// l's tenant, and returns the link that is in effect afterwards (the
+	// existing one on conflict).
+	LinkIdentity(ctx context.Context, l domain.IdentityLink) (domain.IdentityLink, error)
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"event-metrics-service/internal/identity/core/domain"
+	"event-metrics-service/internal/identity/core/ports"
+)
+
+var (
+	ErrInvalidIdentity  = errors.New("invalid identity")
+	ErrIdentityConflict = errors.New("anonymous_id is already linked to another user")
+)
+
+// DefaultStitchWindow is how far before an identify call anonymous events
+// are attributed to the identified user.
+const DefaultStitchWindow = 24 * time.Hour
+
+// MaxIDLength matches the events.user_id / anonymous_id columns.
+const MaxIDLength = 100
+
+type IdentifyUseCase struct {
+	repo     ports.IdentityRepositoryPort
+	window   time.Duration
+	now      func() time.Time
+	tenantOf func(ctx context.Context) string
+}
+
+type Option func(*IdentifyUseCase)
+
+// WithTenantResolver links anonymous ids within the tenant of the request
+// context, so one tenant cannot stitch the visitors of another.
+func WithTenantResolver(fn func(ctx context.Context) string) Option {
+	return func(uc *IdentifyUseCase) {
+		uc.tenantOf = fn
+	}
+}
+
+func NewIdentifyUseCase(repo ports.IdentityRepositoryPort, window time.Duration, opts ...Option) *IdentifyUseCase {
+	uc := &IdentifyUseCase{repo: repo, window: window, now: time.Now}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+type IdentifyInput struct {
+	AnonymousID string
+	UserID      string
+}
+
+// Identify links the anonymous id to the user. Identifying the same pair
+// again is a no-op that returns the original link; an anonymous id can only
+// ever belong to one user of a tenant.
+func (uc *IdentifyUseCase) Identify(ctx context.Context, in IdentifyInput) (domain.IdentityLink, error) {
+	if in.AnonymousID == "" || in.UserID == "" ||
+		len(in.AnonymousID) > MaxIDLength || len(in.UserID) > MaxIDLength {
+		return domain.IdentityLink{}, ErrInvalidIdentity
+	}
+
+	now := uc.now().UTC()
+	var tenantID string
+	if uc.tenantOf != nil {
+		tenantID = uc.tenantOf(ctx)
+	}
+	link, err := uc.repo.LinkIdentity(ctx, domain.IdentityLink{
+		TenantID:     tenantID,
+		AnonymousID:  in.AnonymousID,
+		UserID:       in.UserID,
+		IdentifiedAt: now,
+		StitchFrom:   now.Add(-uc.window),
+	})
+	if err != nil {
+		return domain.IdentityLink{}, err
+	}
+
+	if link.UserID != in.UserID {
+		return link, ErrIdentityConflict
+	}
+	return link, nil
+}
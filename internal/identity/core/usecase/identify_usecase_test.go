@@ -0,0 +1,114 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/identity/core/domain"
+	"event-metrics-service/internal/identity/core/usecase"
+)
+
+// fakeIdentityRepo keeps the first link per tenant and anonymous id, like
+// the table.
+type fakeIdentityRepo struct {
+	links map[[2]string]domain.IdentityLink
+	err   error
+}
+
+func (f *fakeIdentityRepo) LinkIdentity(ctx context.Context, l domain.IdentityLink) (domain.IdentityLink, error) {
+	if f.err != nil {
+		return domain.IdentityLink{}, f.err
+	}
+	if f.links == nil {
+		f.links = map[[2]string]domain.IdentityLink{}
+	}
+	key := [2]string{l.TenantID, l.AnonymousID}
+	if existing, ok := f.links[key]; ok {
+		return existing, nil
+	}
+	f.links[key] = l
+	return l, nil
+}
+
+func TestIdentify_LinksWithStitchWindow(t *testing.T) {
+	repo := &fakeIdentityRepo{}
+	uc := usecase.NewIdentifyUseCase(repo, 30*time.Minute)
+
+	link, err := uc.Identify(context.Background(), usecase.IdentifyInput{AnonymousID: "anon-1", UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if link.UserID != "user-1" || link.IdentifiedAt.Sub(link.StitchFrom) != 30*time.Minute {
+		t.Fatalf("unexpected link: %+v", link)
+	}
+
+	// Identifying again is idempotent and keeps the original window.
+	again, err := uc.Identify(context.Background(), usecase.IdentifyInput{AnonymousID: "anon-1", UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !again.IdentifiedAt.Equal(link.IdentifiedAt) {
+		t.Fatalf("expected original link, got %+v", again)
+	}
+}
+
+func TestIdentify_Conflict(t *testing.T) {
+	repo := &fakeIdentityRepo{}
+	uc := usecase.NewIdentifyUseCase(repo, time.Hour)
+
+	_, _ = uc.Identify(context.Background(), usecase.IdentifyInput{AnonymousID: "anon-1", UserID: "user-1"})
+
+	link, err := uc.Identify(context.Background(), usecase.IdentifyInput{AnonymousID: "anon-1", UserID: "user-2"})
+	if !errors.Is(err, usecase.ErrIdentityConflict) {
+		t.Fatalf("expected ErrIdentityConflict, got %v", err)
+	}
+	if link.UserID != "user-1" {
+		t.Fatalf("expected existing link to be returned, got %+v", link)
+	}
+}
+
+func TestIdentify_PerTenant(t *testing.T) {
+	type tenantKey struct{}
+	repo := &fakeIdentityRepo{}
+	uc := usecase.NewIdentifyUseCase(repo, time.Hour, usecase.WithTenantResolver(func(ctx context.Context) string {
+		tenant, _ := ctx.Value(tenantKey{}).(string)
+		return tenant
+	}))
+	acme := context.WithValue(context.Background(), tenantKey{}, "acme")
+	globex := context.WithValue(context.Background(), tenantKey{}, "globex")
+
+	if _, err := uc.Identify(acme, usecase.IdentifyInput{AnonymousID: "anon-1", UserID: "user-1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Another tenant's visitor with the same anonymous id is not a conflict.
+	link, err := uc.Identify(globex, usecase.IdentifyInput{AnonymousID: "anon-1", UserID: "user-2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if link.TenantID != "globex" || link.UserID != "user-2" {
+		t.Fatalf("unexpected link: %+v", link)
+	}
+}
+
+func TestIdentify_Invalid(t *testing.T) {
+	uc := usecase.NewIdentifyUseCase(&fakeIdentityRepo{}, time.Hour)
+
+	for _, in := range []usecase.IdentifyInput{
+		{UserID: "user-1"},
+		{AnonymousID: "anon-1"},
+	} {
+		if _, err := uc.Identify(context.Background(), in); !errors.Is(err, usecase.ErrInvalidIdentity) {
+			t.Fatalf("expected ErrInvalidIdentity for %+v, got %v", in, err)
+		}
+	}
+}
+
+func TestIdentify_RepositoryError(t *testing.T) {
+	uc := usecase.NewIdentifyUseCase(&fakeIdentityRepo{err: errors.New("db down")}, time.Hour)
+
+	if _, err := uc.Identify(context.Background(), usecase.IdentifyInput{AnonymousID: "a", UserID: "u"}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
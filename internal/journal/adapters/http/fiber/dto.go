@@ -0,0 +1,26 @@
+package fiber
+
+import "time"
+
+// RejectedRequestResponse returns the stored request. Body holds the payload
+// when it is valid UTF-8, BodyBase64 otherwise.
+type RejectedRequestResponse struct {
+	RequestID  string    `json:"request_id" example:"3f1c9a2e-5b7d-4e8f-a1b2-c3d4e5f60718"`
+	APIKeyHash string    `json:"api_key_hash,omitempty"`
+	Method     string    `json:"method" example:"POST"`
+	Path       string    `json:"path" example:"/events/bulk?atomic=true"`
+	Status     int       `json:"status" example:"400"`
+	Body       string    `json:"body,omitempty"`
+	BodyBase64 string    `json:"body_base64,omitempty"`
+	BodySHA256 string    `json:"body_sha256"`
+	BodySize   int       `json:"body_size" example:"1834"`
+	Truncated  bool      `json:"truncated"`
+	Response   string    `json:"response,omitempty" example:"{\"error\":\"invalid_event\"}"`
+	ReceivedAt time.Time `json:"received_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type ErrorResponse struct {
+	Error   string `json:"error" example:"journal_entry_not_found"`
+	Message string `json:"message" example:"no journal entry for this request id"`
+}
@@ -0,0 +1,77 @@
+package fiber
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"unicode/utf8"
+
+	"event-metrics-service/internal/journal/core/domain"
+	"event-metrics-service/internal/journal/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type JournalUseCase interface {
+	Get(ctx context.Context, requestID string) (domain.RejectedRequest, error)
+}
+
+type JournalHandler struct {
+	uc JournalUseCase
+}
+
+func NewJournalHandler(uc JournalUseCase) *JournalHandler {
+	return &JournalHandler{uc: uc}
+}
+
+// GetRejectedRequest godoc
+// @Summary Show a rejected ingest request
+// @Description Returns the payload of a rejected POST /events or /events/bulk request by the X-Request-ID it was
+// @Description answered with. Only available when JOURNAL_ENABLED is set; entries expire after JOURNAL_TTL.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param request_id path string true "Request ID (X-Request-ID response header)"
+// @Success 200 {object} RejectedRequestResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/rejected-requests/{request_id} [get]
+func (h *JournalHandler) GetRejectedRequest(c *fiber.Ctx) error {
+	r, err := h.uc.Get(c.UserContext(), c.Params("request_id"))
+	if err != nil {
+		if errors.Is(err, usecase.ErrEntryNotFound) {
+			return c.Status(http.StatusNotFound).JSON(ErrorResponse{
+				Error:   "journal_entry_not_found",
+				Message: err.Error(),
+			})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error: "internal_server_error",
+		})
+	}
+
+	// Payloads are shown to support staff; never let a browser cache them.
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	resp := RejectedRequestResponse{
+		RequestID:  r.RequestID,
+		APIKeyHash: r.APIKeyHash,
+		Method:     r.Method,
+		Path:       r.Path,
+		Status:     r.Status,
+		BodySHA256: r.BodySHA256,
+		BodySize:   r.BodySize,
+		Truncated:  r.Truncated(),
+		Response:   string(r.Response),
+		ReceivedAt: r.ReceivedAt,
+		ExpiresAt:  r.ExpiresAt,
+	}
+	if utf8.Valid(r.Body) {
+		resp.Body = string(r.Body)
+	} else {
+		resp.BodyBase64 = base64.StdEncoding.EncodeToString(r.Body)
+	}
+
+	return c.JSON(resp)
+}
@@ -0,0 +1,41 @@
+package fiber
+
+import (
+	"context"
+	"net/http"
+
+	"event-metrics-service/internal/journal/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type RejectedRecorder interface {
+	Record(ctx context.Context, in usecase.RecordInput) error
+}
+
+// RecordRejected journals requests answered with a 4xx status, keyed by the
+// X-Request-ID response header (see the requestid middleware). A failing
+// journal never affects the response.
+func RecordRejected(uc RejectedRecorder, keyFn func(c *fiber.Ctx) string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		status := c.Response().StatusCode()
+		if status < http.StatusBadRequest || status >= http.StatusInternalServerError {
+			return nil
+		}
+
+		_ = uc.Record(c.UserContext(), usecase.RecordInput{
+			RequestID: c.GetRespHeader(fiber.HeaderXRequestID),
+			APIKey:    keyFn(c),
+			Method:    c.Method(),
+			Path:      c.OriginalURL(),
+			Status:    status,
+			Body:      c.Body(),
+			Response:  c.Response().Body(),
+		})
+		return nil
+	}
+}
@@ -0,0 +1,132 @@
+package fiber
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"event-metrics-service/internal/journal/core/domain"
+	"event-metrics-service/internal/journal/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+)
+
+type fakeJournal struct {
+	recorded []usecase.RecordInput
+	entry    domain.RejectedRequest
+	err      error
+}
+
+func (f *fakeJournal) Record(ctx context.Context, in usecase.RecordInput) error {
+	f.recorded = append(f.recorded, in)
+	return f.err
+}
+
+func (f *fakeJournal) Get(ctx context.Context, requestID string) (domain.RejectedRequest, error) {
+	return f.entry, f.err
+}
+
+func keyFromHeader(c *fiber.Ctx) string { return c.Get("X-API-Key") }
+
+func newJournalApp(j *fakeJournal, status int) *fiber.App {
+	app := fiber.New()
+	app.Use(requestid.New())
+	app.Post("/events", RecordRejected(j, keyFromHeader), func(c *fiber.Ctx) error {
+		return c.Status(status).JSON(fiber.Map{"error": "invalid_event"})
+	})
+	app.Get("/admin/rejected-requests/:request_id", NewJournalHandler(j).GetRejectedRequest)
+	return app
+}
+
+func postEvent(t *testing.T, app *fiber.App, body string) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/events?atomic=true", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", "key-1")
+	req.Header.Set(fiber.HeaderXRequestID, "req-1")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	return resp
+}
+
+// ------------------------------------------------------------
+// MIDDLEWARE
+// ------------------------------------------------------------
+
+func TestRecordRejected_RecordsClientErrors(t *testing.T) {
+	j := &fakeJournal{err: errors.New("journal down")}
+
+	resp := postEvent(t, newJournalApp(j, http.StatusBadRequest), `{"event_name":""}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("journal errors must not change the response, got %d", resp.StatusCode)
+	}
+
+	if len(j.recorded) != 1 {
+		t.Fatalf("expected one recorded request, got %d", len(j.recorded))
+	}
+	got := j.recorded[0]
+	if got.RequestID != "req-1" || got.APIKey != "key-1" || got.Path != "/events?atomic=true" || got.Status != http.StatusBadRequest {
+		t.Fatalf("unexpected record: %+v", got)
+	}
+	if string(got.Body) != `{"event_name":""}` || !strings.Contains(string(got.Response), "invalid_event") {
+		t.Fatalf("unexpected body/response: %q %q", got.Body, got.Response)
+	}
+}
+
+func TestRecordRejected_IgnoresOtherStatuses(t *testing.T) {
+	for _, status := range []int{http.StatusCreated, http.StatusInternalServerError} {
+		j := &fakeJournal{}
+		postEvent(t, newJournalApp(j, status), `{}`)
+		if len(j.recorded) != 0 {
+			t.Fatalf("status %d must not be journaled", status)
+		}
+	}
+}
+
+// ------------------------------------------------------------
+// HANDLER
+// ------------------------------------------------------------
+
+func TestGetRejectedRequest(t *testing.T) {
+	j := &fakeJournal{entry: domain.RejectedRequest{
+		RequestID: "req-1",
+		Status:    400,
+		Body:      []byte{0xff, 0xfe},
+		BodySize:  4,
+	}}
+
+	resp, err := newJournalApp(j, 0).Test(httptest.NewRequest(http.MethodGet, "/admin/rejected-requests/req-1", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Cache-Control") != "no-store" {
+		t.Fatalf("unexpected response: %d %v", resp.StatusCode, resp.Header)
+	}
+
+	var body RejectedRequestResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if body.Body != "" || body.BodyBase64 != "//4=" || !body.Truncated {
+		t.Fatalf("expected base64 body for binary payloads, got %+v", body)
+	}
+}
+
+func TestGetRejectedRequest_NotFound(t *testing.T) {
+	j := &fakeJournal{err: usecase.ErrEntryNotFound}
+
+	resp, err := newJournalApp(j, 0).Test(httptest.NewRequest(http.MethodGet, "/admin/rejected-requests/nope", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", resp.StatusCode)
+	}
+}
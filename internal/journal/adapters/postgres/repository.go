@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"event-metrics-service/internal/journal/core/domain"
+	"event-metrics-service/internal/journal/core/ports"
+)
+
+type RowScanner interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+type DB interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+type JournalRepository struct {
+	db DB
+}
+
+func NewJournalRepository(db DB) *JournalRepository {
+	return &JournalRepository{db: db}
+}
+
+var _ ports.JournalRepositoryPort = (*JournalRepository)(nil)
+
+const recordRejectedSQL = `
+INSERT INTO rejected_requests (
+    request_id, api_key_hash, method, path, status,
+    body, body_sha256, body_size, response, received_at, expires_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+ON CONFLICT (request_id) DO UPDATE
+SET api_key_hash = EXCLUDED.api_key_hash,
+    method       = EXCLUDED.method,
+    path         = EXCLUDED.path,
+    status       = EXCLUDED.status,
+    body         = EXCLUDED.body,
+    body_sha256  = EXCLUDED.body_sha256,
+    body_size    = EXCLUDED.body_size,
+    response     = EXCLUDED.response,
+    received_at  = EXCLUDED.received_at,
+    expires_at   = EXCLUDED.expires_at`
+
+const getRejectedSQL = `
+SELECT request_id, api_key_hash, method, path, status,
+       body, body_sha256, body_size, response, received_at, expires_at
+FROM rejected_requests
+WHERE request_id = $1 AND expires_at > now()`
+
+const purgeExpiredRejectedSQL = `
+DELETE FROM rejected_requests
+WHERE request_id IN (
+    SELECT request_id FROM rejected_requests
+    WHERE expires_at <= now()
+    LIMIT $1
+);
+`
+
+func (r *JournalRepository) RecordRejected(ctx context.Context, e domain.RejectedRequest) error {
+	_, err := r.db.ExecContext(ctx, recordRejectedSQL,
+		e.RequestID,
+		e.APIKeyHash,
+		e.Method,
+		e.Path,
+		e.Status,
+		e.Body,
+		e.BodySHA256,
+		e.BodySize,
+		e.Response,
+		e.ReceivedAt,
+		e.ExpiresAt,
+	)
+	return err
+}
+
+func (r *JournalRepository) GetRejected(ctx context.Context, requestID string) (domain.RejectedRequest, bool, error) {
+	rows, err := r.db.QueryContext(ctx, getRejectedSQL, requestID)
+	if err != nil {
+		return domain.RejectedRequest{}, false, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return domain.RejectedRequest{}, false, rows.Err()
+	}
+
+	var e domain.RejectedRequest
+	if err := rows.Scan(
+		&e.RequestID,
+		&e.APIKeyHash,
+		&e.Method,
+		&e.Path,
+		&e.Status,
+		&e.Body,
+		&e.BodySHA256,
+		&e.BodySize,
+		&e.Response,
+		&e.ReceivedAt,
+		&e.ExpiresAt,
+	); err != nil {
+		return domain.RejectedRequest{}, false, err
+	}
+	e.ReceivedAt = e.ReceivedAt.UTC()
+	e.ExpiresAt = e.ExpiresAt.UTC()
+
+	return e, true, rows.Err()
+}
+
+func (r *JournalRepository) PurgeExpiredRejected(ctx context.Context, limit int) (int64, error) {
+	res, err := r.db.ExecContext(ctx, purgeExpiredRejectedSQL, limit)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
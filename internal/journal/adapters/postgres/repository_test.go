@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/journal/core/domain"
+)
+
+type fakeRowScanner struct {
+	rows [][]any
+	i    int
+}
+
+func (f *fakeRowScanner) Next() bool {
+	return f.i < len(f.rows)
+}
+
+func (f *fakeRowScanner) Scan(dest ...any) error {
+	row := f.rows[f.i]
+	if len(dest) != len(row) {
+		return errors.New("dest length mismatch")
+	}
+	for i := range dest {
+		switch d := dest[i].(type) {
+		case *[]byte:
+			*d = row[i].([]byte)
+		case *int:
+			*d = row[i].(int)
+		case *string:
+			*d = row[i].(string)
+		case *time.Time:
+			*d = row[i].(time.Time)
+		default:
+			return errors.New("unsupported dest type")
+		}
+	}
+	f.i++
+	return nil
+}
+
+func (f *fakeRowScanner) Err() error   { return nil }
+func (f *fakeRowScanner) Close() error { return nil }
+
+type fakeResult struct{ n int64 }
+
+func (r fakeResult) LastInsertId() (int64, error) { return 0, nil }
+func (r fakeResult) RowsAffected() (int64, error) { return r.n, nil }
+
+type fakeDB struct {
+	ExecFn  func(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryFn func(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+func (f *fakeDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return f.ExecFn(ctx, query, args...)
+}
+
+func (f *fakeDB) QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error) {
+	return f.QueryFn(ctx, query, args...)
+}
+
+func TestJournalRepository_Record(t *testing.T) {
+	var gotArgs []any
+	db := &fakeDB{
+		ExecFn: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+			if !strings.Contains(query, "ON CONFLICT (request_id)") {
+				t.Fatalf("expected upsert query, got %s", query)
+			}
+			gotArgs = args
+			return fakeResult{n: 1}, nil
+		},
+	}
+
+	err := NewJournalRepository(db).RecordRejected(context.Background(), domain.RejectedRequest{
+		RequestID: "req-1",
+		Status:    400,
+		Body:      []byte(`{}`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(gotArgs) != 11 || gotArgs[0] != "req-1" || gotArgs[4] != 400 {
+		t.Fatalf("unexpected args: %v", gotArgs)
+	}
+}
+
+func TestJournalRepository_Get(t *testing.T) {
+	received := time.Date(2025, 12, 7, 10, 0, 0, 0, time.FixedZone("TRT", 3*3600))
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if !strings.Contains(query, "expires_at > now()") {
+				t.Fatalf("expired entries must not be returned: %s", query)
+			}
+			return &fakeRowScanner{rows: [][]any{{
+				"req-1", "hash", "POST", "/events", 400,
+				[]byte(`{"a`), "sum", 10, []byte(`{"error":"invalid_json"}`), received, received.Add(time.Hour),
+			}}}, nil
+		},
+	}
+
+	got, found, err := NewJournalRepository(db).GetRejected(context.Background(), "req-1")
+	if err != nil || !found {
+		t.Fatalf("expected entry, got found=%v err=%v", found, err)
+	}
+	if got.Status != 400 || got.BodySize != 10 || !got.Truncated() || got.ReceivedAt.Location() != time.UTC {
+		t.Fatalf("unexpected entry: %+v", got)
+	}
+}
+
+func TestJournalRepository_GetMissing(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return &fakeRowScanner{}, nil
+		},
+	}
+
+	if _, found, err := NewJournalRepository(db).GetRejected(context.Background(), "req-1"); err != nil || found {
+		t.Fatalf("expected found=false, got found=%v err=%v", found, err)
+	}
+}
+
+func TestJournalRepository_PurgeError(t *testing.T) {
+	db := &fakeDB{
+		ExecFn: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+			return nil, errors.New("db failure")
+		},
+	}
+
+	if _, err := NewJournalRepository(db).PurgeExpiredRejected(context.Background(), 10); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// RejectedRequest is what a client sent in an ingest request the service
+// rejected, kept for a short time so support can inspect it.
+type RejectedRequest struct {
+	RequestID  string
+	APIKeyHash string // SHA-256 of the caller's API key; the key itself is never stored
+	Method     string
+	Path       string // including the query string
+	Status     int
+
+	Body       []byte // capped at the journal's body limit
+	BodySHA256 string // of the complete body as received
+	BodySize   int    // size of the complete body
+	Response   []byte // capped response body, usually the error JSON
+
+	ReceivedAt time.Time
+	ExpiresAt  time.Time
+}
+
+// Truncated reports whether Body is shorter than what the client sent.
+func (r RejectedRequest) Truncated() bool {
+	return len(r.Body) < r.BodySize
+}
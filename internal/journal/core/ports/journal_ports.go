@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+
+	"event-metrics-service/internal/journal/core/domain"
+)
+
+type JournalRepositoryPort interface {
+	// RecordRejected stores r. Recording the same request ID again replaces
+	// the previous entry.
+	RecordRejected(ctx context.Context, r domain.RejectedRequest) error
+	// GetRejected returns found = false for unknown or expired request IDs.
+	GetRejected(ctx context.Context, requestID string) (r domain.RejectedRequest, found bool, err error)
+	// PurgeExpiredRejected deletes up to limit expired entries and returns
+	// how many were removed.
+	PurgeExpiredRejected(ctx context.Context, limit int) (int64, error)
+}
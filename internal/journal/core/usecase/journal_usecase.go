@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"event-metrics-service/internal/journal/core/domain"
+	"event-metrics-service/internal/journal/core/ports"
+)
+
+var ErrEntryNotFound = errors.New("no journal entry for this request id")
+
+const (
+	DefaultTTL          = 24 * time.Hour
+	DefaultMaxBodyBytes = 64 * 1024
+
+	// MaxResponseBytes caps the stored response; error bodies are small.
+	MaxResponseBytes = 4 * 1024
+
+	DefaultPurgeBatchSize = 5000
+)
+
+// JournalUseCase records rejected ingest requests and serves them back to
+// admins by request ID until they expire.
+type JournalUseCase struct {
+	repo         ports.JournalRepositoryPort
+	ttl          time.Duration
+	maxBodyBytes int
+	now          func() time.Time
+}
+
+func NewJournalUseCase(repo ports.JournalRepositoryPort, ttl time.Duration, maxBodyBytes int) *JournalUseCase {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = DefaultMaxBodyBytes
+	}
+	return &JournalUseCase{repo: repo, ttl: ttl, maxBodyBytes: maxBodyBytes, now: time.Now}
+}
+
+type RecordInput struct {
+	RequestID string
+	APIKey    string
+	Method    string
+	Path      string
+	Status    int
+	Body      []byte
+	Response  []byte
+}
+
+func (uc *JournalUseCase) Record(ctx context.Context, in RecordInput) error {
+	if in.RequestID == "" {
+		return nil
+	}
+
+	sum := sha256.Sum256(in.Body)
+	now := uc.now().UTC()
+
+	return uc.repo.RecordRejected(ctx, domain.RejectedRequest{
+		RequestID:  in.RequestID,
+		APIKeyHash: hashAPIKey(in.APIKey),
+		Method:     in.Method,
+		Path:       in.Path,
+		Status:     in.Status,
+		Body:       capped(in.Body, uc.maxBodyBytes),
+		BodySHA256: hex.EncodeToString(sum[:]),
+		BodySize:   len(in.Body),
+		Response:   capped(in.Response, MaxResponseBytes),
+		ReceivedAt: now,
+		ExpiresAt:  now.Add(uc.ttl),
+	})
+}
+
+func (uc *JournalUseCase) Get(ctx context.Context, requestID string) (domain.RejectedRequest, error) {
+	r, found, err := uc.repo.GetRejected(ctx, requestID)
+	if err != nil {
+		return domain.RejectedRequest{}, err
+	}
+	if !found {
+		return domain.RejectedRequest{}, ErrEntryNotFound
+	}
+	return r, nil
+}
+
+// Purge deletes expired entries in batches until a short batch signals
+// nothing is left, and returns the total number removed.
+func (uc *JournalUseCase) Purge(ctx context.Context) (int64, error) {
+	var total int64
+
+	for {
+		n, err := uc.repo.PurgeExpiredRejected(ctx, DefaultPurgeBatchSize)
+		total += n
+		if err != nil {
+			return total, err
+		}
+		if n < DefaultPurgeBatchSize {
+			return total, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+	}
+}
+
+// Run purges on every tick until ctx is cancelled.
+func (uc *JournalUseCase) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := uc.Purge(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+func hashAPIKey(key string) string {
+	if key == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// capped copies b, because request and response buffers are reused by the
+// HTTP server after the handler returns.
+func capped(b []byte, limit int) []byte {
+	if len(b) > limit {
+		b = b[:limit]
+	}
+	return append([]byte(nil), b...)
+}
@@ -0,0 +1,129 @@
+package usecase_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/journal/core/domain"
+	"event-metrics-service/internal/journal/core/usecase"
+)
+
+type fakeJournalRepo struct {
+	RecordFn func(ctx context.Context, r domain.RejectedRequest) error
+	GetFn    func(ctx context.Context, requestID string) (domain.RejectedRequest, bool, error)
+	PurgeFn  func(ctx context.Context, limit int) (int64, error)
+}
+
+func (f *fakeJournalRepo) RecordRejected(ctx context.Context, r domain.RejectedRequest) error {
+	return f.RecordFn(ctx, r)
+}
+
+func (f *fakeJournalRepo) GetRejected(ctx context.Context, requestID string) (domain.RejectedRequest, bool, error) {
+	return f.GetFn(ctx, requestID)
+}
+
+func (f *fakeJournalRepo) PurgeExpiredRejected(ctx context.Context, limit int) (int64, error) {
+	return f.PurgeFn(ctx, limit)
+}
+
+// ------------------------------------------------------------
+// RECORD
+// ------------------------------------------------------------
+
+func TestJournal_RecordCapsAndHashes(t *testing.T) {
+	var stored domain.RejectedRequest
+	repo := &fakeJournalRepo{
+		RecordFn: func(ctx context.Context, r domain.RejectedRequest) error {
+			stored = r
+			return nil
+		},
+	}
+	uc := usecase.NewJournalUseCase(repo, time.Hour, 8)
+
+	body := []byte(`{"event_name":"purchase"}`)
+	err := uc.Record(context.Background(), usecase.RecordInput{
+		RequestID: "req-1",
+		APIKey:    "secret-key",
+		Method:    "POST",
+		Path:      "/events",
+		Status:    400,
+		Body:      body,
+		Response:  []byte(`{"error":"invalid_event"}`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sum := sha256.Sum256(body)
+	if stored.BodySHA256 != hex.EncodeToString(sum[:]) || stored.BodySize != len(body) {
+		t.Fatalf("expected checksum and size of the full body, got %+v", stored)
+	}
+	if !bytes.Equal(stored.Body, body[:8]) || !stored.Truncated() {
+		t.Fatalf("expected body capped at 8 bytes, got %q", stored.Body)
+	}
+	if stored.APIKeyHash == "" || stored.APIKeyHash == "secret-key" {
+		t.Fatalf("expected hashed api key, got %q", stored.APIKeyHash)
+	}
+	if got := stored.ExpiresAt.Sub(stored.ReceivedAt); got != time.Hour {
+		t.Fatalf("expected 1h ttl, got %s", got)
+	}
+
+	// The stored body must not alias the caller's buffer.
+	body[0] = 'X'
+	if stored.Body[0] != '{' {
+		t.Fatalf("stored body aliases the request buffer")
+	}
+}
+
+func TestJournal_RecordWithoutRequestIDIsSkipped(t *testing.T) {
+	repo := &fakeJournalRepo{
+		RecordFn: func(ctx context.Context, r domain.RejectedRequest) error {
+			t.Fatalf("unexpected record")
+			return nil
+		},
+	}
+
+	if err := usecase.NewJournalUseCase(repo, 0, 0).Record(context.Background(), usecase.RecordInput{Status: 400}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// ------------------------------------------------------------
+// GET / PURGE
+// ------------------------------------------------------------
+
+func TestJournal_GetNotFound(t *testing.T) {
+	repo := &fakeJournalRepo{
+		GetFn: func(ctx context.Context, requestID string) (domain.RejectedRequest, bool, error) {
+			return domain.RejectedRequest{}, false, nil
+		},
+	}
+
+	if _, err := usecase.NewJournalUseCase(repo, 0, 0).Get(context.Background(), "req-1"); !errors.Is(err, usecase.ErrEntryNotFound) {
+		t.Fatalf("expected ErrEntryNotFound, got %v", err)
+	}
+}
+
+func TestJournal_PurgeUntilShortBatch(t *testing.T) {
+	batches := []int64{usecase.DefaultPurgeBatchSize, 12}
+	repo := &fakeJournalRepo{
+		PurgeFn: func(ctx context.Context, limit int) (int64, error) {
+			n := batches[0]
+			batches = batches[1:]
+			return n, nil
+		},
+	}
+
+	total, err := usecase.NewJournalUseCase(repo, 0, 0).Purge(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if total != usecase.DefaultPurgeBatchSize+12 {
+		t.Fatalf("unexpected total: %d", total)
+	}
+}
@@ -0,0 +1,105 @@
+// Package jsonlimit bounds the shape of JSON request bodies before they are
+// decoded, so that a body within MAX_BODY_BYTES cannot still cost the
+// decoder unbounded nesting or millions of tiny values.
+package jsonlimit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ErrTooComplex is wrapped by every limit violation Check reports.
+var ErrTooComplex = errors.New("json document too complex")
+
+// Limits caps one JSON document; a zero field leaves that dimension
+// unlimited.
+type Limits struct {
+	// MaxDepth is the deepest nesting of objects and arrays.
+	MaxDepth int
+	// MaxValues is the most values (objects, arrays and scalars, object
+	// keys excluded) in the whole document.
+	MaxValues int
+}
+
+// Enabled reports whether any limit is set.
+func (l Limits) Enabled() bool {
+	return l.MaxDepth > 0 || l.MaxValues > 0
+}
+
+// Check scans data and returns an error wrapping ErrTooComplex when it
+// exceeds a limit. Malformed JSON is not reported; the decoder that runs
+// afterwards answers it.
+func (l Limits) Check(data []byte) error {
+	if !l.Enabled() {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	// inObject[i] tells whether container i is an object; expectKey whether
+	// the next token of the innermost object is a key.
+	var inObject []bool
+	expectKey := false
+	values := 0
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			// io.EOF ends a well-formed body; syntax errors are left to the
+			// decoder.
+			return nil
+		}
+
+		if d, ok := tok.(json.Delim); ok && (d == '}' || d == ']') {
+			inObject = inObject[:len(inObject)-1]
+			expectKey = len(inObject) > 0 && inObject[len(inObject)-1]
+			continue
+		}
+		if expectKey {
+			expectKey = false
+			continue
+		}
+
+		values++
+		if l.MaxValues > 0 && values > l.MaxValues {
+			return fmt.Errorf("%w: more than %d values", ErrTooComplex, l.MaxValues)
+		}
+
+		if d, ok := tok.(json.Delim); ok {
+			inObject = append(inObject, d == '{')
+			if l.MaxDepth > 0 && len(inObject) > l.MaxDepth {
+				return fmt.Errorf("%w: nested deeper than %d", ErrTooComplex, l.MaxDepth)
+			}
+			expectKey = d == '{'
+			continue
+		}
+		expectKey = len(inObject) > 0 && inObject[len(inObject)-1]
+	}
+}
+
+// Middleware rejects request bodies over the limits with 413
+// json_too_complex before any handler decodes them. Bodies that do not
+// start with an object or array are passed through untouched.
+func Middleware(l Limits) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !l.Enabled() {
+			return c.Next()
+		}
+		body := bytes.TrimLeft(c.Body(), " \t\r\n")
+		if len(body) == 0 || (body[0] != '{' && body[0] != '[') {
+			return c.Next()
+		}
+		if err := l.Check(body); err != nil {
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+				"error":   "json_too_complex",
+				"message": err.Error(),
+			})
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,65 @@
+package jsonlimit
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCheck(t *testing.T) {
+	cases := []struct {
+		name    string
+		limits  Limits
+		body    string
+		wantErr bool
+	}{
+		{"unlimited", Limits{}, `[[[[[[1]]]]]]`, false},
+		{"depth at limit", Limits{MaxDepth: 3}, `{"a":{"b":[1]}}`, false},
+		{"depth over limit", Limits{MaxDepth: 2}, `{"a":{"b":[1]}}`, true},
+		{"keys are not values", Limits{MaxValues: 3}, `{"a":1,"b":2}`, false},
+		{"values over limit", Limits{MaxValues: 3}, `[1,2,3]`, true},
+		{"values after nested object", Limits{MaxValues: 4}, `{"a":{"b":1},"c":2}`, false},
+		{"malformed is left to the decoder", Limits{MaxDepth: 1}, `{"a":`, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.limits.Check([]byte(tc.body))
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tc.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrTooComplex) {
+				t.Fatalf("expected ErrTooComplex, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	app := fiber.New()
+	app.Use(Middleware(Limits{MaxDepth: 2}))
+	app.Post("/", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusNoContent) })
+
+	cases := []struct {
+		body string
+		want int
+	}{
+		{`{"a":[1]}`, fiber.StatusNoContent},
+		{` {"a":{"b":[1]}}`, fiber.StatusRequestEntityTooLarge},
+		{`e=page_view`, fiber.StatusNoContent},
+	}
+	for _, tc := range cases {
+		resp, err := app.Test(httptest.NewRequest("POST", "/", strings.NewReader(tc.body)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != tc.want {
+			body, _ := io.ReadAll(resp.Body)
+			t.Fatalf("%s: expected %d, got %d: %s", tc.body, tc.want, resp.StatusCode, body)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+)
+
+// Operations queries are recorded under.
+const (
+	OperationQuery  = "query"
+	OperationBatch  = "batch"
+	OperationStream = "stream"
+)
+
+// Query is one metrics query that ran, with how long it took and what it
+// returned.
+type Query struct {
+	Operation string
+	Input     usecase.GetMetricsInput
+	Filter    json.RawMessage // Input as JSON, see filter
+	Duration  time.Duration
+	Rows      int
+	Err       error
+}
+
+// Recorder is satisfied by the query audit log. Recording must not fail
+// the query, so it reports nothing back.
+type Recorder interface {
+	RecordQuery(ctx context.Context, q Query)
+}
+
+type GetMetricsUseCase interface {
+	Execute(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error)
+	ExecuteBatch(ctx context.Context, in usecase.BatchGetMetricsInput) (*usecase.BatchMetricsResult, error)
+	ValidateStream(in usecase.GetMetricsInput) error
+	ExecuteStream(
+		ctx context.Context,
+		in usecase.GetMetricsInput,
+		head func(*domain.AggregatedMetrics) error,
+		emit func([]domain.MetricsGroup) error,
+	) error
+}
+
+// GetMetrics decorates the metrics use case with the query audit log.
+type GetMetrics struct {
+	next     GetMetricsUseCase
+	recorder Recorder
+	now      func() time.Time
+}
+
+func NewGetMetrics(next GetMetricsUseCase, recorder Recorder) *GetMetrics {
+	return &GetMetrics{next: next, recorder: recorder, now: time.Now}
+}
+
+func (g *GetMetrics) Execute(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+	start := g.now()
+	res, err := g.next.Execute(ctx, in)
+	g.record(ctx, OperationQuery, in, g.now().Sub(start), rowsOf(res), err)
+	return res, err
+}
+
+// ExecuteBatch records every query of the batch with the duration of the
+// whole batch, which is what the caller waited for.
+func (g *GetMetrics) ExecuteBatch(ctx context.Context, in usecase.BatchGetMetricsInput) (*usecase.BatchMetricsResult, error) {
+	start := g.now()
+	res, err := g.next.ExecuteBatch(ctx, in)
+	d := g.now().Sub(start)
+	for i, q := range in.Queries {
+		var rows int
+		if res != nil && i < len(res.Results) {
+			rows = rowsOf(res.Results[i])
+		}
+		g.record(ctx, OperationBatch, q, d, rows, err)
+	}
+	return res, err
+}
+
+func (g *GetMetrics) ValidateStream(in usecase.GetMetricsInput) error {
+	return g.next.ValidateStream(in)
+}
+
+// ExecuteStream records the groups sent and the time until the last one
+// was, which includes the time the client took to read them.
+func (g *GetMetrics) ExecuteStream(
+	ctx context.Context,
+	in usecase.GetMetricsInput,
+	head func(*domain.AggregatedMetrics) error,
+	emit func([]domain.MetricsGroup) error,
+) error {
+	start := g.now()
+	var rows int
+	err := g.next.ExecuteStream(ctx, in, head, func(groups []domain.MetricsGroup) error {
+		rows += len(groups)
+		return emit(groups)
+	})
+	g.record(ctx, OperationStream, in, g.now().Sub(start), rows, err)
+	return err
+}
+
+// record outlives a cancelled request, so queries the caller gave up on
+// are recorded too.
+func (g *GetMetrics) record(ctx context.Context, op string, in usecase.GetMetricsInput, d time.Duration, rows int, err error) {
+	g.recorder.RecordQuery(context.WithoutCancel(ctx), Query{
+		Operation: op,
+		Input:     in,
+		Filter:    filterOf(in),
+		Duration:  d,
+		Rows:      rows,
+		Err:       err,
+	})
+}
+
+// rowsOf counts the rows of a result: its groups or histogram buckets, or
+// one for an ungrouped result.
+func rowsOf(res *domain.AggregatedMetrics) int {
+	switch {
+	case res == nil:
+		return 0
+	case len(res.Groups) > 0:
+		return len(res.Groups)
+	case len(res.Histogram) > 0:
+		return len(res.Histogram)
+	case res.GroupBy != "":
+		return 0
+	}
+	return 1
+}
+
+// filter is GetMetricsInput with the parameter names of GET /metrics; top
+// is the n of /metrics/top.
+type filter struct {
+	EventName   string            `json:"event_name"`
+	From        int64             `json:"from"`
+	To          int64             `json:"to"`
+	Channel     *string           `json:"channel,omitempty"`
+	CampaignID  *string           `json:"campaign_id,omitempty"`
+	UserID      string            `json:"user_id,omitempty"`
+	GroupBy     string            `json:"group_by,omitempty"`
+	Interval    string            `json:"interval,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Tags        string            `json:"tags,omitempty"`
+	TagMatch    string            `json:"tag_match,omitempty"`
+	Aggregate   string            `json:"aggregate,omitempty"`
+	Field       string            `json:"field,omitempty"`
+	Top         int               `json:"top,omitempty"`
+	OrderBy     string            `json:"order_by,omitempty"`
+	Order       string            `json:"order,omitempty"`
+	Limit       int               `json:"limit,omitempty"`
+	Offset      int               `json:"offset,omitempty"`
+	Compare     string            `json:"compare,omitempty"`
+	Mode        string            `json:"mode,omitempty"`
+	BucketMin   *float64          `json:"bucket_min,omitempty"`
+	BucketMax   *float64          `json:"bucket_max,omitempty"`
+	BucketCount int               `json:"bucket_count,omitempty"`
+	Buckets     []float64         `json:"buckets,omitempty"`
+	Denominator string            `json:"denominator,omitempty"`
+	AsOf        string            `json:"as_of,omitempty"`
+	CountsOnly  bool              `json:"counts_only,omitempty"`
+}
+
+func filterOf(in usecase.GetMetricsInput) json.RawMessage {
+	f := filter{
+		EventName:   in.EventName,
+		From:        in.From,
+		To:          in.To,
+		Channel:     in.Channel,
+		CampaignID:  in.CampaignID,
+		UserID:      in.UserID,
+		GroupBy:     in.GroupBy,
+		Interval:    in.Interval,
+		Metadata:    in.Metadata,
+		Tags:        strings.Join(in.Tags, ","),
+		TagMatch:    in.TagMatch,
+		Aggregate:   in.Aggregate,
+		Top:         in.Top,
+		OrderBy:     in.OrderBy,
+		Order:       in.Order,
+		Limit:       in.Limit,
+		Offset:      in.Offset,
+		Compare:     in.Compare,
+		Mode:        in.Mode,
+		Denominator: in.Denominator,
+		CountsOnly:  in.CountsOnly,
+	}
+	// The histogram field is passed as field, like the aggregate one.
+	f.Field = in.Field
+	if h := in.Histogram; h != nil {
+		if h.Field != "" {
+			f.Field = h.Field
+		}
+		if len(h.Bounds) > 0 {
+			f.Buckets = h.Bounds
+		} else {
+			f.BucketMin, f.BucketMax, f.BucketCount = &h.Min, &h.Max, h.Count
+		}
+	}
+	switch {
+	case in.AsOfLatest:
+		f.AsOf = "latest"
+	case in.AsOf != nil:
+		f.AsOf = in.AsOf.UTC().Format(time.RFC3339Nano)
+	}
+
+	raw, _ := json.Marshal(f) // only fails on non-finite bucket bounds, recorded as {}
+	return raw
+}
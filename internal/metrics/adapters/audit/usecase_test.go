@@ -0,0 +1,140 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+)
+
+type fakeRecorder struct {
+	queries []Query
+}
+
+func (f *fakeRecorder) RecordQuery(ctx context.Context, q Query) {
+	if ctx.Err() != nil {
+		panic("recorded with a cancelled context")
+	}
+	f.queries = append(f.queries, q)
+}
+
+type fakeGetMetricsUseCase struct {
+	res *domain.AggregatedMetrics
+	err error
+}
+
+func (f *fakeGetMetricsUseCase) Execute(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+	return f.res, f.err
+}
+
+func (f *fakeGetMetricsUseCase) ExecuteBatch(ctx context.Context, in usecase.BatchGetMetricsInput) (*usecase.BatchMetricsResult, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	res := &usecase.BatchMetricsResult{}
+	for range in.Queries {
+		res.Results = append(res.Results, f.res)
+	}
+	return res, nil
+}
+
+func (f *fakeGetMetricsUseCase) ValidateStream(in usecase.GetMetricsInput) error {
+	return f.err
+}
+
+func (f *fakeGetMetricsUseCase) ExecuteStream(
+	ctx context.Context,
+	in usecase.GetMetricsInput,
+	head func(*domain.AggregatedMetrics) error,
+	emit func([]domain.MetricsGroup) error,
+) error {
+	if err := emit(f.res.Groups); err != nil {
+		return err
+	}
+	if err := emit(f.res.Groups[:1]); err != nil {
+		return err
+	}
+	return f.err
+}
+
+func grouped() *domain.AggregatedMetrics {
+	return &domain.AggregatedMetrics{GroupBy: "channel", Groups: []domain.MetricsGroup{{Key: "web"}, {Key: "mobile"}}}
+}
+
+func TestGetMetrics_RecordsQueries(t *testing.T) {
+	rec := &fakeRecorder{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	channel := "web"
+	in := usecase.GetMetricsInput{EventName: "purchase", From: 100, To: 200, Channel: &channel, GroupBy: "channel"}
+	_, _ = NewGetMetrics(&fakeGetMetricsUseCase{res: grouped()}, rec).Execute(ctx, in)
+	_, _ = NewGetMetrics(&fakeGetMetricsUseCase{res: &domain.AggregatedMetrics{}}, rec).Execute(ctx, usecase.GetMetricsInput{EventName: "signup"})
+	_, _ = NewGetMetrics(&fakeGetMetricsUseCase{err: usecase.ErrInvalidGroupBy}, rec).Execute(ctx, in)
+	_ = NewGetMetrics(&fakeGetMetricsUseCase{res: grouped()}, rec).ExecuteStream(ctx, in,
+		func(*domain.AggregatedMetrics) error { return nil },
+		func([]domain.MetricsGroup) error { return nil },
+	)
+
+	want := []struct {
+		op   string
+		rows int
+		err  error
+	}{
+		{OperationQuery, 2, nil},
+		{OperationQuery, 1, nil},
+		{OperationQuery, 0, usecase.ErrInvalidGroupBy},
+		{OperationStream, 3, nil},
+	}
+	if len(rec.queries) != len(want) {
+		t.Fatalf("expected %d queries, got %d", len(want), len(rec.queries))
+	}
+	for i, w := range want {
+		q := rec.queries[i]
+		if q.Operation != w.op || q.Rows != w.rows || !errors.Is(q.Err, w.err) {
+			t.Fatalf("query %d: got %s rows=%d err=%v", i, q.Operation, q.Rows, q.Err)
+		}
+	}
+
+	var filter map[string]any
+	if err := json.Unmarshal(rec.queries[0].Filter, &filter); err != nil {
+		t.Fatalf("invalid filter: %v", err)
+	}
+	if filter["event_name"] != "purchase" || filter["channel"] != "web" || filter["group_by"] != "channel" || len(filter) != 5 {
+		t.Fatalf("unexpected filter: %v", filter)
+	}
+}
+
+func TestGetMetrics_RecordsEveryBatchQuery(t *testing.T) {
+	rec := &fakeRecorder{}
+	batch := usecase.BatchGetMetricsInput{Queries: []usecase.GetMetricsInput{
+		{EventName: "purchase", GroupBy: "channel"},
+		{EventName: "signup", GroupBy: "channel"},
+	}}
+
+	if _, err := NewGetMetrics(&fakeGetMetricsUseCase{res: grouped()}, rec).ExecuteBatch(context.Background(), batch); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rec.queries) != 2 || rec.queries[1].Input.EventName != "signup" || rec.queries[1].Rows != 2 {
+		t.Fatalf("unexpected queries: %+v", rec.queries)
+	}
+	if rec.queries[0].Operation != OperationBatch || rec.queries[0].Duration != rec.queries[1].Duration {
+		t.Fatalf("expected batch queries timed as the batch, got %+v", rec.queries)
+	}
+}
+
+func TestFilterOf_Histogram(t *testing.T) {
+	raw := filterOf(usecase.GetMetricsInput{
+		EventName:  "purchase",
+		Mode:       domain.ModeHistogram,
+		Histogram:  &domain.HistogramSpec{Bounds: []float64{0, 10, 100}, Field: "metadata.amount"},
+		AsOfLatest: true,
+	})
+	want := `{"event_name":"purchase","from":0,"to":0,"field":"metadata.amount","mode":"histogram","buckets":[0,10,100],"as_of":"latest"}`
+	if string(raw) != want {
+		t.Fatalf("expected %s, got %s", want, raw)
+	}
+}
@@ -0,0 +1,188 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/ports"
+)
+
+const (
+	DefaultTTL            = 30 * time.Second
+	DefaultStale          = 5 * time.Minute
+	DefaultRefreshTimeout = 30 * time.Second
+)
+
+// keyPrefix namespaces the cache keys; bump its version when the cached
+// shape changes.
+const keyPrefix = "metrics:v1:"
+
+// Store keeps cached responses until their TTL runs out.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// MetricsReader caches the results of a metrics reader by filter. It wraps
+// the reader rather than the use case, so the key is the filter after
+// validation, defaults, tenant and as_of resolution, and identical dashboard
+// panels of different callers share an entry.
+//
+// Results are fresh for ttl. For stale after that they are still returned
+// while one background query per key refreshes them, so a dashboard polling
+// a popular query never waits for Postgres once it is cached. Store errors
+// never fail a query; they go to the error handler and the reader is asked.
+type MetricsReader struct {
+	next           ports.MetricsReaderPort
+	store          Store
+	ttl            time.Duration
+	stale          time.Duration
+	refreshTimeout time.Duration
+	onError        func(error)
+	now            func() time.Time
+
+	mu         sync.Mutex
+	refreshing map[string]bool
+	wg         sync.WaitGroup
+}
+
+type Option func(*MetricsReader)
+
+// WithErrorHandler receives store errors and failed background refreshes.
+func WithErrorHandler(fn func(error)) Option {
+	return func(r *MetricsReader) {
+		r.onError = fn
+	}
+}
+
+// WithRefreshTimeout bounds background refreshes, which outlive the request
+// that found the stale entry.
+func WithRefreshTimeout(d time.Duration) Option {
+	return func(r *MetricsReader) {
+		r.refreshTimeout = d
+	}
+}
+
+func NewMetricsReader(next ports.MetricsReaderPort, store Store, ttl, stale time.Duration, opts ...Option) *MetricsReader {
+	r := &MetricsReader{
+		next:           next,
+		store:          store,
+		ttl:            ttl,
+		stale:          stale,
+		refreshTimeout: DefaultRefreshTimeout,
+		now:            time.Now,
+		refreshing:     map[string]bool{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+var _ ports.MetricsReaderPort = (*MetricsReader)(nil)
+
+// entry is the cached value of a key.
+type entry struct {
+	FreshUntil time.Time                 `json:"fresh_until"`
+	Metrics    *domain.AggregatedMetrics `json:"metrics"`
+}
+
+func (r *MetricsReader) QueryMetrics(ctx context.Context, f ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+	key, err := cacheKey(f)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, ok, err := r.store.Get(ctx, key)
+	if err != nil {
+		r.fail(err)
+	}
+	if ok {
+		var e entry
+		if err := json.Unmarshal(raw, &e); err == nil && e.Metrics != nil {
+			if !r.now().Before(e.FreshUntil) {
+				r.refresh(ctx, key, f)
+			}
+			return e.Metrics, nil
+		}
+	}
+	return r.load(ctx, key, f)
+}
+
+// load queries the reader and caches the result. Callers get their own copy
+// of it, decoded from the cached bytes like a hit, as the use case adds
+// comparisons to it and the reader may keep the one it returned.
+func (r *MetricsReader) load(ctx context.Context, key string, f ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+	res, err := r.next.QueryMetrics(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(entry{FreshUntil: r.now().Add(r.ttl), Metrics: res})
+	if err != nil {
+		r.fail(err)
+		return res, nil
+	}
+	if err := r.store.Set(ctx, key, raw, r.ttl+r.stale); err != nil {
+		r.fail(err)
+	}
+
+	var e entry
+	if err := json.Unmarshal(raw, &e); err != nil {
+		r.fail(err)
+		return res, nil
+	}
+	return e.Metrics, nil
+}
+
+// refresh reloads key in the background, unless it is already being
+// reloaded.
+func (r *MetricsReader) refresh(ctx context.Context, key string, f ports.MetricsFilter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.refreshing[key] {
+		return
+	}
+	r.refreshing[key] = true
+
+	// The request may end before the refresh; keep its values, not its
+	// cancellation.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.refreshTimeout)
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer cancel()
+		if _, err := r.load(ctx, key, f); err != nil {
+			r.fail(err)
+		}
+		r.mu.Lock()
+		delete(r.refreshing, key)
+		r.mu.Unlock()
+	}()
+}
+
+// Wait blocks until running background refreshes are done.
+func (r *MetricsReader) Wait() {
+	r.wg.Wait()
+}
+
+func (r *MetricsReader) fail(err error) {
+	if r.onError != nil {
+		r.onError(err)
+	}
+}
+
+// cacheKey hashes the filter, whose JSON form has map keys sorted.
+func cacheKey(f ports.MetricsFilter) (string, error) {
+	raw, err := json.Marshal(f)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return keyPrefix + hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,200 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/ports"
+)
+
+type fakeStore struct {
+	mu     sync.Mutex
+	values map[string][]byte
+	ttls   map[string]time.Duration
+	err    error
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{values: map[string][]byte{}, ttls: map[string]time.Duration{}}
+}
+
+func (s *fakeStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, false, s.err
+	}
+	v, ok := s.values[key]
+	return v, ok, nil
+}
+
+func (s *fakeStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.values[key] = value
+	s.ttls[key] = ttl
+	return nil
+}
+
+type fakeReader struct {
+	mu    sync.Mutex
+	calls int
+	total int64
+	err   error
+}
+
+func (f *fakeReader) QueryMetrics(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &domain.AggregatedMetrics{EventName: flt.EventName, TotalCount: f.total}, nil
+}
+
+func (f *fakeReader) queries() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+var filter = ports.MetricsFilter{EventName: "purchase", From: 100, To: 200, GroupBy: "channel"}
+
+func TestMetricsReader_CachesByFilter(t *testing.T) {
+	next := &fakeReader{total: 5}
+	store := newFakeStore()
+	r := NewMetricsReader(next, store, 30*time.Second, time.Minute)
+
+	for range 3 {
+		res, err := r.QueryMetrics(context.Background(), filter)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.TotalCount != 5 {
+			t.Fatalf("unexpected result: %+v", res)
+		}
+	}
+	if next.queries() != 1 {
+		t.Fatalf("expected one query, got %d", next.queries())
+	}
+	for _, ttl := range store.ttls {
+		if ttl != 90*time.Second {
+			t.Fatalf("expected entries to live for ttl plus stale, got %s", ttl)
+		}
+	}
+
+	tenant := "acme"
+	other := filter
+	other.Tenant = &tenant
+	if _, err := r.QueryMetrics(context.Background(), other); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next.queries() != 2 || len(store.values) != 2 {
+		t.Fatalf("expected tenants not to share entries, got %d queries", next.queries())
+	}
+}
+
+// sharedReader returns the same result to every query.
+type sharedReader struct {
+	res *domain.AggregatedMetrics
+}
+
+func (s *sharedReader) QueryMetrics(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+	return s.res, nil
+}
+
+func TestMetricsReader_ReturnsACopy(t *testing.T) {
+	next := &sharedReader{res: &domain.AggregatedMetrics{EventName: "purchase", TotalCount: 5}}
+	r := NewMetricsReader(next, newFakeStore(), 30*time.Second, time.Minute)
+
+	res, err := r.QueryMetrics(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res == next.res {
+		t.Fatalf("expected a copy of the reader's result on a miss")
+	}
+
+	res.TotalCount = 99
+	if next.res.TotalCount != 5 {
+		t.Fatalf("expected the reader's result untouched, got %d", next.res.TotalCount)
+	}
+	hit, err := r.QueryMetrics(context.Background(), filter)
+	if err != nil || hit.TotalCount != 5 {
+		t.Fatalf("expected the cached result untouched, got %+v, %v", hit, err)
+	}
+}
+
+func TestMetricsReader_ServesStaleWhileRevalidating(t *testing.T) {
+	next := &fakeReader{total: 5}
+	store := newFakeStore()
+	now := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	r := NewMetricsReader(next, store, 30*time.Second, time.Minute)
+	r.now = func() time.Time { return now }
+
+	if _, err := r.QueryMetrics(context.Background(), filter); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now = now.Add(45 * time.Second)
+	next.mu.Lock()
+	next.total = 8
+	next.mu.Unlock()
+
+	res, err := r.QueryMetrics(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.TotalCount != 5 {
+		t.Fatalf("expected the stale result, got %d", res.TotalCount)
+	}
+	r.Wait()
+	if next.queries() != 2 {
+		t.Fatalf("expected a background refresh, got %d queries", next.queries())
+	}
+
+	res, err = r.QueryMetrics(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.TotalCount != 8 || next.queries() != 2 {
+		t.Fatalf("expected the refreshed result from the cache, got %d after %d queries", res.TotalCount, next.queries())
+	}
+}
+
+func TestMetricsReader_StoreErrorsFallThrough(t *testing.T) {
+	next := &fakeReader{total: 5}
+	store := newFakeStore()
+	store.err = errors.New("connection refused")
+	var failures int
+	r := NewMetricsReader(next, store, 30*time.Second, time.Minute, WithErrorHandler(func(error) { failures++ }))
+
+	res, err := r.QueryMetrics(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.TotalCount != 5 || failures != 2 {
+		t.Fatalf("expected the reader's result and two reported failures, got %+v and %d", res, failures)
+	}
+}
+
+func TestMetricsReader_ReaderErrorsAreNotCached(t *testing.T) {
+	next := &fakeReader{err: errors.New("timeout")}
+	store := newFakeStore()
+	r := NewMetricsReader(next, store, 30*time.Second, time.Minute)
+
+	if _, err := r.QueryMetrics(context.Background(), filter); err == nil {
+		t.Fatalf("expected the reader error")
+	}
+	if len(store.values) != 0 {
+		t.Fatalf("expected nothing to be cached")
+	}
+}
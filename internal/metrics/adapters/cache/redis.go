@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	DefaultRedisTimeout = time.Second
+	DefaultRedisConns   = 8
+)
+
+// RedisStore keeps cached metrics in a single Redis server. Values are
+// written with SET PX, so Redis expires them.
+type RedisStore struct {
+	client  *redis.Client
+	timeout time.Duration
+}
+
+type redisConfig struct {
+	password string
+	timeout  time.Duration
+	conns    int
+}
+
+type RedisOption func(*redisConfig)
+
+// WithRedisPassword authenticates new connections with AUTH.
+func WithRedisPassword(password string) RedisOption {
+	return func(c *redisConfig) {
+		c.password = password
+	}
+}
+
+// WithRedisTimeout bounds each command, including dialing.
+func WithRedisTimeout(d time.Duration) RedisOption {
+	return func(c *redisConfig) {
+		c.timeout = d
+	}
+}
+
+// WithRedisConns sets the number of idle connections kept open.
+func WithRedisConns(n int) RedisOption {
+	return func(c *redisConfig) {
+		if n > 0 {
+			c.conns = n
+		}
+	}
+}
+
+func NewRedisStore(addr string, opts ...RedisOption) *RedisStore {
+	cfg := redisConfig{timeout: DefaultRedisTimeout, conns: DefaultRedisConns}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &RedisStore{
+		client: redis.NewClient(&redis.Options{
+			Addr:                  addr,
+			Password:              cfg.password,
+			Protocol:              2,
+			DisableIdentity:       true,
+			DialTimeout:           cfg.timeout,
+			ReadTimeout:           cfg.timeout,
+			WriteTimeout:          cfg.timeout,
+			ContextTimeoutEnabled: true,
+			MaxIdleConns:          cfg.conns,
+		}),
+		timeout: cfg.timeout,
+	}
+}
+
+var _ Store = (*RedisStore)(nil)
+
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	value, err := s.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	// Redis rejects a zero expiry; PX has millisecond resolution.
+	return s.client.Set(ctx, key, value, max(ttl, time.Millisecond)).Err()
+}
+
+// Close closes the store's connections.
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
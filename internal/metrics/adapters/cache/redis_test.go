@@ -0,0 +1,181 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeRedis serves AUTH, GET and SET (with PX or EX) from a map, and answers
+// anything else, like HELLO, as an unknown command as Redis 5 does.
+type fakeRedis struct {
+	ln       net.Listener
+	password string
+
+	mu     sync.Mutex
+	values map[string]string
+	ttls   map[string]string
+	conns  int
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeRedis{ln: ln, password: password, values: map[string]string{}, ttls: map[string]string{}}
+	t.Cleanup(func() { ln.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *fakeRedis) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns++
+		s.mu.Unlock()
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	authed := s.password == ""
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		// Commands are case-insensitive; the client sends them in lower case.
+		args[0] = strings.ToUpper(args[0])
+		var reply string
+		s.mu.Lock()
+		switch {
+		case args[0] == "AUTH":
+			authed = len(args) == 2 && args[1] == s.password
+			reply = "+OK\r\n"
+			if !authed {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case !authed:
+			reply = "-NOAUTH Authentication required.\r\n"
+		case args[0] == "GET":
+			v, ok := s.values[args[1]]
+			reply = "$-1\r\n"
+			if ok {
+				reply = "$" + strconv.Itoa(len(v)) + "\r\n" + v + "\r\n"
+			}
+		case args[0] == "SET" && len(args) == 5 && strings.EqualFold(args[3], "PX"):
+			s.values[args[1]] = args[2]
+			s.ttls[args[1]] = args[4]
+			reply = "+OK\r\n"
+		case args[0] == "SET" && len(args) == 5 && strings.EqualFold(args[3], "EX"):
+			// Recorded in milliseconds, as with PX.
+			s.values[args[1]] = args[2]
+			s.ttls[args[1]] = args[4] + "000"
+			reply = "+OK\r\n"
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		s.mu.Unlock()
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(line[1 : len(line)-2])
+	if err != nil || line[0] != '*' {
+		return nil, errors.New("not an array")
+	}
+	args := make([]string, n)
+	for i := range args {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(line[1 : len(line)-2])
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func TestRedisStore_SetAndGet(t *testing.T) {
+	srv := newFakeRedis(t, "secret")
+	s := NewRedisStore(srv.ln.Addr().String(), WithRedisPassword("secret"))
+	defer s.Close()
+	ctx := context.Background()
+
+	if _, ok, err := s.Get(ctx, "metrics:v1:a"); err != nil || ok {
+		t.Fatalf("expected a miss, got ok=%v err=%v", ok, err)
+	}
+	value := []byte("{\"total_count\":5}\r\n with a line break")
+	if err := s.Set(ctx, "metrics:v1:a", value, 90*time.Second); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	got, ok, err := s.Get(ctx, "metrics:v1:a")
+	if err != nil || !ok || string(got) != string(value) {
+		t.Fatalf("unexpected get: %q ok=%v err=%v", got, ok, err)
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.ttls["metrics:v1:a"] != "90000" {
+		t.Fatalf("expected PX 90000, got %q", srv.ttls["metrics:v1:a"])
+	}
+	if srv.conns != 1 {
+		t.Fatalf("expected the connection to be reused, got %d", srv.conns)
+	}
+}
+
+func TestRedisStore_ErrorReply(t *testing.T) {
+	srv := newFakeRedis(t, "secret")
+	s := NewRedisStore(srv.ln.Addr().String(), WithRedisPassword("wrong"))
+	defer s.Close()
+
+	_, _, err := s.Get(context.Background(), "metrics:v1:a")
+	var rerr redis.Error
+	if !errors.As(err, &rerr) {
+		t.Fatalf("expected an error reply, got %v", err)
+	}
+}
+
+func TestRedisStore_DialFailure(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	s := NewRedisStore(addr, WithRedisTimeout(100*time.Millisecond))
+	if _, _, err := s.Get(context.Background(), "metrics:v1:a"); err == nil {
+		t.Fatalf("expected a dial error")
+	}
+}
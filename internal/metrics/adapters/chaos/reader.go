@@ -0,0 +1,33 @@
+package chaos
+
+import (
+	"context"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/ports"
+)
+
+// FaultInjector is satisfied by the chaos injector.
+type FaultInjector interface {
+	Inject(ctx context.Context, target string) error
+}
+
+// MetricsReader decorates a metrics reader with fault injection.
+type MetricsReader struct {
+	next     ports.MetricsReaderPort
+	injector FaultInjector
+	target   string
+}
+
+func NewMetricsReader(next ports.MetricsReaderPort, injector FaultInjector, target string) *MetricsReader {
+	return &MetricsReader{next: next, injector: injector, target: target}
+}
+
+var _ ports.MetricsReaderPort = (*MetricsReader)(nil)
+
+func (r *MetricsReader) QueryMetrics(ctx context.Context, f ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+	if err := r.injector.Inject(ctx, r.target); err != nil {
+		return nil, err
+	}
+	return r.next.QueryMetrics(ctx, f)
+}
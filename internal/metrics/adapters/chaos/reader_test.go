@@ -0,0 +1,50 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/ports"
+)
+
+type fakeInjector struct {
+	err error
+}
+
+func (f *fakeInjector) Inject(ctx context.Context, target string) error {
+	return f.err
+}
+
+type fakeReader struct {
+	called bool
+}
+
+func (f *fakeReader) QueryMetrics(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+	f.called = true
+	return &domain.AggregatedMetrics{EventName: flt.EventName}, nil
+}
+
+func TestMetricsReader_PassThrough(t *testing.T) {
+	next := &fakeReader{}
+	r := NewMetricsReader(next, &fakeInjector{}, "metrics.reader")
+
+	res, err := r.QueryMetrics(context.Background(), ports.MetricsFilter{EventName: "x"})
+	if err != nil || res.EventName != "x" || !next.called {
+		t.Fatalf("expected pass-through, got res=%+v err=%v", res, err)
+	}
+}
+
+func TestMetricsReader_InjectedError(t *testing.T) {
+	next := &fakeReader{}
+	r := NewMetricsReader(next, &fakeInjector{err: errors.New("injected fault")}, "metrics.reader")
+
+	res, err := r.QueryMetrics(context.Background(), ports.MetricsFilter{EventName: "x"})
+	if err == nil || res != nil {
+		t.Fatalf("expected injected error, got res=%+v err=%v", res, err)
+	}
+	if next.called {
+		t.Fatalf("delegate should not be called on injected error")
+	}
+}
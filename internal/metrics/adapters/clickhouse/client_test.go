@@ -0,0 +1,110 @@
+package clickhouse
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ClickHouse/ch-go/proto"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/column"
+	chproto "github.com/ClickHouse/clickhouse-go/v2/lib/proto"
+)
+
+// nativeBlock encodes rows in the Native format ClickHouse answers with, at
+// the protocol revision the fake server announces; columns are "name Type"
+// pairs.
+func nativeBlock(t *testing.T, columns []string, rows ...[]any) []byte {
+	t.Helper()
+	block := chproto.NewBlock()
+	for _, c := range columns {
+		name, typ, _ := strings.Cut(c, " ")
+		if err := block.AddColumn(name, column.Type(typ)); err != nil {
+			t.Fatalf("add column: %v", err)
+		}
+	}
+	for _, row := range rows {
+		if err := block.Append(row...); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	var buf proto.Buffer
+	if err := block.Encode(&buf, chproto.DBMS_TCP_PROTOCOL_VERSION); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	return buf.Buf
+}
+
+func TestClient_Query(t *testing.T) {
+	var got *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		// The client asks for the server's version before its first query.
+		if strings.Contains(string(body), "version()") {
+			w.Write(nativeBlock(t, []string{"displayName() String", "version() String", "revision() UInt32", "timezone() String"},
+				[]any{"clickhouse", "25.8.1.1", uint32(chproto.DBMS_TCP_PROTOCOL_VERSION), "UTC"}))
+			return
+		}
+		got = req
+		w.Write(nativeBlock(t, []string{"k1 String", "total_count Int64"},
+			[]any{"web", int64(3)}, []any{"mobile", int64(1)}))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/", WithDatabase("analytics"), WithCredentials("reader", "secret"))
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	rows, err := c.query(context.Background(), "SELECT channel AS k1, count() AS total_count FROM events WHERE event_name = {p0:String} GROUP BY k1", map[string]string{"p0": "signup"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var keys []string
+	var total int64
+	err = eachRow(rows, func(row resultSet) error {
+		var key string
+		var n int64
+		if err := row.Scan(&key, &n); err != nil {
+			return err
+		}
+		keys, total = append(keys, key), total+n
+		return nil
+	})
+	if err != nil || strings.Join(keys, ",") != "web,mobile" || total != 4 {
+		t.Fatalf("unexpected rows: %v %d (%v)", keys, total, err)
+	}
+
+	q := got.URL.Query()
+	if q.Get("database") != "analytics" || q.Get("param_p0") != "signup" {
+		t.Fatalf("unexpected params: %s", got.URL.RawQuery)
+	}
+	if user, password, ok := got.BasicAuth(); !ok || user != "reader" || password != "secret" {
+		t.Fatalf("expected the credentials to be sent")
+	}
+}
+
+func TestClient_QueryError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "Code: 60. DB::Exception: Table default.events does not exist.", http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	_, err = c.query(context.Background(), "SELECT 1", nil)
+	if err == nil || !strings.Contains(err.Error(), "Table default.events does not exist") {
+		t.Fatalf("expected the server error, got %v", err)
+	}
+}
+
+func TestNewClient_InvalidURL(t *testing.T) {
+	for _, u := range []string{"clickhouse:8123", "tcp://clickhouse:9000", "http://"} {
+		if _, err := NewClient(u); err == nil {
+			t.Fatalf("expected %q to be rejected", u)
+		}
+	}
+}
@@ -0,0 +1,352 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/ports"
+)
+
+// querier runs a query; *Client in production.
+type querier interface {
+	query(ctx context.Context, sql string, params map[string]string) (resultSet, error)
+}
+
+// MetricsReader answers metrics queries from a copy of the events in
+// ClickHouse (see migrations/clickhouse), falling back to another reader for
+// what the copy cannot answer alike: histograms, lag and per-user queries,
+// which need identity links. Unique users count anonymous events as their
+// own visitor, without stitching them to the user they were linked to.
+type MetricsReader struct {
+	db       querier
+	fallback ports.MetricsReaderPort
+	table    string
+}
+
+type ReaderOption func(*MetricsReader)
+
+// WithTable reads events from another table (default "events").
+func WithTable(name string) ReaderOption {
+	return func(r *MetricsReader) {
+		r.table = name
+	}
+}
+
+func NewMetricsReader(client *Client, fallback ports.MetricsReaderPort, opts ...ReaderOption) *MetricsReader {
+	return newMetricsReader(client, fallback, opts...)
+}
+
+func newMetricsReader(db querier, fallback ports.MetricsReaderPort, opts ...ReaderOption) *MetricsReader {
+	r := &MetricsReader{db: db, fallback: fallback, table: "events"}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+var _ ports.MetricsReaderPort = (*MetricsReader)(nil)
+
+// Expressions mirroring the Postgres repository. Rows replaced by a later
+// insert of the same event are merged away by FINAL.
+const (
+	eventCountExpr  = `toInt64(round(sum(1 / sample_rate)))`
+	uniqueUserExpr  = `toInt64(uniqExact(if(user_id != '', user_id, concat('anon:', anonymous_id))))`
+	timeKeyFormat   = `'%Y-%m-%dT%H:%i:%SZ'`
+	numberJSONTypes = `('Int64', 'UInt64', 'Double', 'Bool')`
+)
+
+var timeBuckets = map[string]string{
+	"minute": "toStartOfMinute(event_time)",
+	"hour":   "toStartOfHour(event_time)",
+	"day":    "toStartOfDay(event_time)",
+	"week":   "toStartOfWeek(event_time, 1)",
+	"month":  "toStartOfMonth(event_time)",
+}
+
+func (r *MetricsReader) QueryMetrics(ctx context.Context, f ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+	if f.Mode != domain.ModeCount || f.UserID != "" {
+		return r.fallback.QueryMetrics(ctx, f)
+	}
+
+	q := &query{params: map[string]string{}}
+	where := q.where(f)
+	value := q.aggregate(f)
+	res := &domain.AggregatedMetrics{
+		EventName: f.EventName,
+		From:      f.From,
+		To:        f.To,
+		GroupBy:   f.GroupBy,
+		Aggregate: f.Aggregate,
+		Field:     f.Field,
+		Mode:      f.Mode,
+		AsOf:      f.AsOf,
+	}
+
+	// Like in Postgres, the overall figures cannot be derived from the
+	// groups: users active in several of them count once.
+	rows, err := r.db.query(ctx, `
+SELECT `+eventCountExpr+` AS total_count, `+countedUsers(f)+` AS unique_users`+valueColumn(value)+`
+FROM `+r.table+` FINAL
+WHERE `+where, q.params)
+	if err != nil {
+		return nil, err
+	}
+	err = eachRow(rows, func(row resultSet) error {
+		return row.Scan(withValue([]any{&res.TotalCount, &res.UniqueUsers}, value, &res.Value)...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if f.GroupBy == "" {
+		return res, nil
+	}
+
+	total := res.TotalCount
+	dims := domain.GroupByDimensions(f.GroupBy)
+	rows, err = r.db.query(ctx, r.groupSQL(q, where, dims, f, value), q.params)
+	if err != nil {
+		return nil, err
+	}
+	res.TotalCount = 0
+	err = eachRow(rows, func(row resultSet) error {
+		g := domain.MetricsGroup{Keys: make([]string, len(dims))}
+		dest := make([]any, 0, len(dims)+3)
+		for i := range g.Keys {
+			dest = append(dest, &g.Keys[i])
+		}
+		if err := row.Scan(withValue(append(dest, &g.TotalCount, &g.UniqueUsers), value, &g.Value)...); err != nil {
+			return err
+		}
+		g.Key = strings.Join(g.Keys, domain.CompositeKeySeparator)
+		if len(dims) == 1 {
+			g.Keys = nil
+		}
+		res.Groups = append(res.Groups, g)
+		res.TotalCount += g.TotalCount
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if f.Limit > 0 && len(res.Groups) > f.Limit {
+		res.Groups, res.NextOffset = res.Groups[:f.Limit], f.Offset+f.Limit
+	}
+	// Top-N groups and pages leave events out, so their sum is not the
+	// total.
+	if f.Top > 0 || f.Offset > 0 || res.NextOffset > 0 {
+		res.TotalCount = total
+	}
+	return res, nil
+}
+
+// groupSQL groups the events of where by dims, ordered and paged like the
+// Postgres repository. Top keeps the top groups of a single dimension other
+// than time, skipping the empty key.
+func (r *MetricsReader) groupSQL(q *query, where string, dims []string, f ports.MetricsFilter, value string) string {
+	exprs := make([]string, len(dims))
+	keys := make([]string, len(dims))
+	for i, dim := range dims {
+		exprs[i] = q.dimension(dim, f.Interval)
+		keys[i] = "k" + strconv.Itoa(i+1)
+	}
+
+	var order string
+	if f.Top > 0 && len(dims) == 1 && dims[0] != "time" {
+		rank := "total_count"
+		if value != "" {
+			rank = "agg_value"
+		}
+		where += " AND " + exprs[0] + " != ''"
+		order = rank + " DESC NULLS LAST, k1\nLIMIT " + strconv.Itoa(f.Top)
+	} else {
+		order = orderBy(f, keys)
+		if f.Limit > 0 {
+			order += fmt.Sprintf("\nLIMIT %d OFFSET %d", f.Limit+1, f.Offset)
+		}
+	}
+
+	columns := make([]string, len(dims))
+	for i := range dims {
+		columns[i] = exprs[i] + " AS " + keys[i]
+	}
+
+	return `
+SELECT
+    ` + strings.Join(columns, ",\n    ") + `,
+    ` + eventCountExpr + ` AS total_count,
+    ` + countedUsers(f) + ` AS unique_users` + valueColumn(value) + `
+FROM ` + r.table + ` FINAL
+WHERE ` + where + `
+GROUP BY ` + strings.Join(keys, ", ") + `
+ORDER BY ` + order
+}
+
+// countedUsers counts unique users, or none for counts-only queries.
+func countedUsers(f ports.MetricsFilter) string {
+	if f.CountsOnly {
+		return "toInt64(0)"
+	}
+	return uniqueUserExpr
+}
+
+// orderBy orders groups like the Postgres repository, ties broken by key.
+func orderBy(f ports.MetricsFilter, keys []string) string {
+	dir := " ASC"
+	if f.Order == domain.OrderDesc {
+		dir = " DESC"
+	}
+	switch f.OrderBy {
+	case domain.OrderByTotalCount, domain.OrderByUniqueUsers:
+		return f.OrderBy + dir + ", " + strings.Join(keys, ", ")
+	case domain.OrderByValue:
+		return "agg_value" + dir + " NULLS LAST, " + strings.Join(keys, ", ")
+	}
+	return strings.Join(keys, dir+", ") + dir
+}
+
+// query collects the parameters of a query as it is built.
+type query struct {
+	params map[string]string
+}
+
+// bind adds a parameter of type typ and returns its placeholder.
+func (q *query) bind(typ, value string) string {
+	name := "p" + strconv.Itoa(len(q.params))
+	q.params[name] = value
+	return "{" + name + ":" + typ + "}"
+}
+
+// where returns the condition selecting the events of f.
+func (q *query) where(f ports.MetricsFilter) string {
+	conds := []string{}
+	if names := domain.EventNames(f.EventName); len(names) > 1 {
+		conds = append(conds, "has("+q.bind("Array(String)", arrayLiteral(names))+", event_name)")
+	} else {
+		conds = append(conds, "event_name = "+q.bind("String", f.EventName))
+	}
+	// Up to the second after to, as events carry milliseconds.
+	conds = append(conds, "event_time >= toDateTime("+q.bind("Int64", strconv.FormatInt(f.From, 10))+", 'UTC')"+
+		" AND event_time < toDateTime("+q.bind("Int64", strconv.FormatInt(f.To+1, 10))+", 'UTC')")
+
+	if f.Channel != nil {
+		conds = append(conds, "channel = "+q.bind("String", *f.Channel))
+	}
+	if f.CampaignID != nil {
+		conds = append(conds, "campaign_id = "+q.bind("String", *f.CampaignID))
+	}
+
+	keys := make([]string, 0, len(f.Metadata))
+	for k := range f.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		conds = append(conds, q.metadata(k)+" = "+q.bind("String", f.Metadata[k]))
+	}
+
+	if len(f.Tags) > 0 {
+		fn := "hasAll"
+		if f.TagMatch == domain.TagMatchAny {
+			fn = "hasAny"
+		}
+		conds = append(conds, fn+"(tags, "+q.bind("Array(String)", arrayLiteral(f.Tags))+")")
+	}
+	if f.AsOf != nil {
+		conds = append(conds, "received_at <= fromUnixTimestamp64Milli("+q.bind("Int64", strconv.FormatInt(f.AsOf.UnixMilli(), 10))+", 'UTC')")
+	}
+	if f.Tenant != nil {
+		conds = append(conds, "tenant_id = "+q.bind("String", *f.Tenant))
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// metadata returns metadata[key] as text, like metadata->>key in Postgres:
+// strings unquoted, numbers and booleans as written, "" when missing or
+// null.
+func (q *query) metadata(key string) string {
+	k := q.bind("String", key)
+	return fmt.Sprintf(`multiIf(JSONType(metadata, %[1]s) = 'String', JSONExtractString(metadata, %[1]s), JSONType(metadata, %[1]s) IN %[2]s, JSONExtractRaw(metadata, %[1]s), '')`, k, numberJSONTypes)
+}
+
+// dimension returns the text expression of a group_by dimension.
+func (q *query) dimension(dim, interval string) string {
+	switch dim {
+	case domain.DimensionChannel:
+		return "channel"
+	case domain.DimensionCampaign:
+		return "campaign_id"
+	case domain.DimensionUser:
+		return "user_id"
+	case "time":
+		return "formatDateTime(toDateTime(" + timeBuckets[interval] + ", 'UTC'), " + timeKeyFormat + ", 'UTC')"
+	case domain.DimensionHourOfDay:
+		return "formatDateTime(event_time, '%H', 'UTC')"
+	case domain.DimensionDayOfWeek:
+		return "formatDateTime(event_time, '%u', 'UTC')"
+	}
+	key, _ := domain.MetadataGroupKey(dim)
+	return q.metadata(key)
+}
+
+// aggregate returns the aggregate of f.Field, or "" without one. Values that
+// are not numbers are skipped; sums are scaled by sample rate, and
+// percentiles interpolate like percentile_cont.
+func (q *query) aggregate(f ports.MetricsFilter) string {
+	key, ok := domain.MetadataGroupKey(f.Field)
+	if f.Aggregate == "" || !ok {
+		return ""
+	}
+	v := "toFloat64OrNull(" + q.metadata(key) + ")"
+
+	if p, ok := domain.AggregatePercentiles[f.Aggregate]; ok {
+		return fmt.Sprintf("quantileExactInclusiveOrNull(%g)(%s)", p, v)
+	}
+	switch f.Aggregate {
+	case domain.AggregateSum:
+		return "sumOrNull(" + v + " / sample_rate)"
+	case domain.AggregateAvg:
+		return "avgOrNull(" + v + ")"
+	case domain.AggregateMin:
+		return "minOrNull(" + v + ")"
+	default:
+		return "maxOrNull(" + v + ")"
+	}
+}
+
+// arrayLiteral formats values as an Array(String) parameter.
+func arrayLiteral(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+	}
+	return "[" + strings.Join(quoted, ",") + "]"
+}
+
+func valueColumn(value string) string {
+	if value == "" {
+		return ""
+	}
+	return ",\n    " + value + " AS agg_value"
+}
+
+func withValue(dest []any, value string, v **float64) []any {
+	if value == "" {
+		return dest
+	}
+	return append(dest, v)
+}
+
+// eachRow calls scan for each row, then closes rows.
+func eachRow(rows resultSet, scan func(resultSet) error) error {
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return fmt.Errorf("clickhouse: scan: %w", err)
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,214 @@
+package clickhouse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/ports"
+)
+
+type fakeQuery struct {
+	sql    string
+	params map[string]string
+}
+
+// fakeClickHouse answers queries in order with canned rows of JSON columns.
+type fakeClickHouse struct {
+	results [][]string
+	queries []fakeQuery
+}
+
+func (f *fakeClickHouse) query(ctx context.Context, sql string, params map[string]string) (resultSet, error) {
+	copied := make(map[string]string, len(params))
+	for k, v := range params {
+		copied[k] = v
+	}
+	f.queries = append(f.queries, fakeQuery{sql, copied})
+
+	rows := &fakeRows{}
+	if n := len(f.queries) - 1; n < len(f.results) {
+		rows.lines = f.results[n]
+	}
+	return rows, nil
+}
+
+// fakeRows decodes each JSON column into the destination of its position.
+type fakeRows struct {
+	lines []string
+	next  int
+}
+
+func (r *fakeRows) Next() bool {
+	r.next++
+	return r.next <= len(r.lines)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	var row []json.RawMessage
+	if err := json.Unmarshal([]byte(r.lines[r.next-1]), &row); err != nil {
+		return err
+	}
+	if len(row) != len(dest) {
+		return fmt.Errorf("expected %d columns, got %d", len(dest), len(row))
+	}
+	for i, col := range row {
+		if err := json.Unmarshal(col, dest[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *fakeRows) Err() error   { return nil }
+func (r *fakeRows) Close() error { return nil }
+
+// hasParam reports whether one of the params has the value.
+func (q fakeQuery) hasParam(value string) bool {
+	for _, v := range q.params {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+type fakeReader struct {
+	calls int
+}
+
+func (f *fakeReader) QueryMetrics(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+	f.calls++
+	return &domain.AggregatedMetrics{EventName: flt.EventName, Mode: flt.Mode}, nil
+}
+
+func TestMetricsReader_FallsBack(t *testing.T) {
+	spec := &domain.HistogramSpec{Min: 0, Max: 10, Count: 5}
+	for _, f := range []ports.MetricsFilter{
+		{EventName: "purchase", Mode: domain.ModeHistogram, Histogram: spec},
+		{EventName: "purchase", Mode: domain.ModeLag},
+		{EventName: "purchase", UserID: "u1"},
+	} {
+		ch, fallback := &fakeClickHouse{}, &fakeReader{}
+		if _, err := newMetricsReader(ch, fallback).QueryMetrics(context.Background(), f); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fallback.calls != 1 || len(ch.queries) != 0 {
+			t.Fatalf("expected %+v to be answered by the fallback", f)
+		}
+	}
+}
+
+func TestMetricsReader_NoGroup(t *testing.T) {
+	ch := &fakeClickHouse{results: [][]string{{`[12, 4, 37.5]`}}}
+	tenant := "acme"
+	res, err := newMetricsReader(ch, &fakeReader{}).QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "purchase,refund",
+		From:      1700000000,
+		To:        1700086400,
+		Metadata:  map[string]string{"plan": "pro"},
+		Tags:      []string{"promo", "it's"},
+		TagMatch:  domain.TagMatchAny,
+		Aggregate: domain.AggregateSum,
+		Field:     "metadata.amount",
+		Tenant:    &tenant,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.TotalCount != 12 || res.UniqueUsers != 4 || res.Value == nil || *res.Value != 37.5 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	q := ch.queries[0]
+	for _, want := range []string{"FROM events FINAL", "has({p0:Array(String)}, event_name)", "hasAny(tags, ", "tenant_id = ", "sumOrNull(toFloat64OrNull("} {
+		if !strings.Contains(q.sql, want) {
+			t.Fatalf("expected %q in query:\n%s", want, q.sql)
+		}
+	}
+	for _, want := range []string{"['purchase','refund']", "1700000000", "plan", "pro", `['promo','it\'s']`, "acme", "amount"} {
+		if !q.hasParam(want) {
+			t.Fatalf("expected a %q param, got %v", want, q.params)
+		}
+	}
+}
+
+func TestMetricsReader_GroupsPage(t *testing.T) {
+	ch := &fakeClickHouse{results: [][]string{
+		{`[10, 6]`},
+		{`["android", 4, 2]`, `["ios", 3, 2]`, `["web", 3, 3]`},
+	}}
+	res, err := newMetricsReader(ch, &fakeReader{}).QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "signup",
+		GroupBy:   domain.DimensionChannel,
+		OrderBy:   domain.OrderByTotalCount,
+		Order:     domain.OrderDesc,
+		Limit:     2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Groups) != 2 || res.Groups[0].Key != "android" || res.Groups[0].Keys != nil || res.NextOffset != 2 {
+		t.Fatalf("expected the first page of groups, got %+v", res)
+	}
+	if res.TotalCount != 10 || res.UniqueUsers != 6 {
+		t.Fatalf("expected the overall figures of a page, got %+v", res)
+	}
+	sql := ch.queries[1].sql
+	if !strings.Contains(sql, "ORDER BY total_count DESC, k1\nLIMIT 3 OFFSET 0") {
+		t.Fatalf("unexpected group query:\n%s", sql)
+	}
+}
+
+func TestMetricsReader_GroupsByDimensions(t *testing.T) {
+	ch := &fakeClickHouse{results: [][]string{
+		{`[5, 3]`},
+		{`["web", "2025-12-07T10:00:00Z", 3, 2]`, `["web", "2025-12-07T11:00:00Z", 2, 1]`},
+	}}
+	res, err := newMetricsReader(ch, &fakeReader{}).QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "signup",
+		GroupBy:   "channel,time",
+		Interval:  "hour",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	g := res.Groups[1]
+	if g.Key != "web|2025-12-07T11:00:00Z" || len(g.Keys) != 2 || g.Keys[1] != "2025-12-07T11:00:00Z" {
+		t.Fatalf("unexpected group: %+v", g)
+	}
+	if res.TotalCount != 5 {
+		t.Fatalf("expected the sum of the groups, got %d", res.TotalCount)
+	}
+	sql := ch.queries[1].sql
+	if !strings.Contains(sql, "toStartOfHour(event_time)") || !strings.Contains(sql, "GROUP BY k1, k2") {
+		t.Fatalf("unexpected group query:\n%s", sql)
+	}
+}
+
+func TestMetricsReader_Top(t *testing.T) {
+	ch := &fakeClickHouse{results: [][]string{
+		{`[20, 8, 99.5]`},
+		{`["sku-1", 5, 3, 80]`, `["sku-2", 9, 4, null]`},
+	}}
+	res, err := newMetricsReader(ch, &fakeReader{}).QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "purchase",
+		GroupBy:   "metadata.product_id",
+		Aggregate: domain.AggregateMax,
+		Field:     "metadata.amount",
+		Top:       2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.TotalCount != 20 || res.Groups[1].Value != nil || *res.Groups[0].Value != 80 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	sql := ch.queries[1].sql
+	if !strings.Contains(sql, "!= ''") || !strings.Contains(sql, "ORDER BY agg_value DESC NULLS LAST, k1\nLIMIT 2") {
+		t.Fatalf("unexpected top query:\n%s", sql)
+	}
+}
@@ -0,0 +1,124 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"event-metrics-service/internal/metrics/adapters/grpc/metricsv1"
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+)
+
+// asOfLatest asks for the current watermark instead of an explicit time.
+const asOfLatest = "latest"
+
+func (s *Server) GetMetrics(ctx context.Context, req *metricsv1.GetMetricsRequest) (*metricsv1.GetMetricsResponse, error) {
+	in, err := toInput(req)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.metrics.Execute(ctx, in)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return toGetMetricsResponse(res), nil
+}
+
+func toInput(m *metricsv1.GetMetricsRequest) (usecase.GetMetricsInput, error) {
+	in := usecase.GetMetricsInput{
+		EventName:  m.GetEventName(),
+		From:       m.GetFrom(),
+		To:         m.GetTo(),
+		CampaignID: m.CampaignId,
+		UserID:     m.GetUserId(),
+		Metadata:   m.GetMetadata(),
+		Tags:       m.GetTags(),
+		TagMatch:   m.GetTagMatch(),
+		GroupBy:    m.GetGroupBy(),
+		Interval:   m.GetInterval(),
+		Aggregate:  m.GetAggregate(),
+		Field:      m.GetField(),
+		OrderBy:    m.GetOrderBy(),
+		Order:      m.GetOrder(),
+		Limit:      int(m.GetLimit()),
+		Offset:     int(m.GetOffset()),
+		CountsOnly: m.GetCountsOnly(),
+	}
+	// An empty channel selects every channel, as on GET /metrics.
+	if m.GetChannel() != "" {
+		in.Channel = m.Channel
+	}
+
+	if asOf := m.GetAsOf(); asOf == asOfLatest {
+		in.AsOfLatest = true
+	} else if asOf != "" {
+		t, err := time.Parse(time.RFC3339Nano, asOf)
+		if err != nil {
+			return usecase.GetMetricsInput{}, status.Error(codes.InvalidArgument, "invalid as_of")
+		}
+		in.AsOf = &t
+	}
+	return in, nil
+}
+
+func toGetMetricsResponse(res *domain.AggregatedMetrics) *metricsv1.GetMetricsResponse {
+	resp := &metricsv1.GetMetricsResponse{
+		EventName:   res.EventName,
+		From:        res.From,
+		To:          res.To,
+		TotalCount:  res.TotalCount,
+		UniqueUsers: res.UniqueUsers,
+		GroupBy:     res.GroupBy,
+		NextOffset:  int32(res.NextOffset),
+		Aggregate:   res.Aggregate,
+		Field:       res.Field,
+		Value:       res.Value,
+	}
+	if res.AsOf != nil {
+		resp.AsOf = res.AsOf.UTC().Format(time.RFC3339Nano)
+	}
+	for _, g := range res.Groups {
+		resp.Groups = append(resp.Groups, &metricsv1.MetricsGroup{
+			Key:         g.Key,
+			Keys:        g.Keys,
+			TotalCount:  g.TotalCount,
+			UniqueUsers: g.UniqueUsers,
+			Value:       g.Value,
+		})
+	}
+	return resp
+}
+
+// statusOf maps the errors of the metrics use cases to statuses, as
+// writeError does for HTTP.
+func statusOf(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidMetricsQuery),
+		errors.Is(err, usecase.ErrInvalidTimeRange),
+		errors.Is(err, usecase.ErrInvalidGroupBy),
+		errors.Is(err, usecase.ErrInvalidInterval),
+		errors.Is(err, usecase.ErrInvalidMode),
+		errors.Is(err, usecase.ErrInvalidHistogram),
+		errors.Is(err, usecase.ErrInvalidMetadata),
+		errors.Is(err, usecase.ErrInvalidTags),
+		errors.Is(err, usecase.ErrInvalidAggregate),
+		errors.Is(err, usecase.ErrInvalidTop),
+		errors.Is(err, usecase.ErrInvalidCompare),
+		errors.Is(err, usecase.ErrInvalidPage),
+		errors.Is(err, usecase.ErrInvalidOrder),
+		errors.Is(err, usecase.ErrQueryRangeTooLarge),
+		errors.Is(err, domain.ErrQueryTooExpensive):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, usecase.ErrWatermarkDisabled):
+		return status.Error(codes.Unimplemented, err.Error())
+	case errors.Is(err, usecase.ErrQueryTimeout):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,561 @@
+// MetricsService is served on GRPC_ADDR (h2c, plaintext HTTP/2). After
+// editing, regenerate metricsv1 with go generate.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: metrics.proto
+
+package metricsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetMetricsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One event name, or several joined by "," counted together.
+	EventName string `protobuf:"bytes,1,opt,name=event_name,json=eventName,proto3" json:"event_name,omitempty"`
+	// Unix seconds.
+	From int64 `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`
+	To   int64 `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`
+	// Unset or empty: every channel.
+	Channel *string `protobuf:"bytes,4,opt,name=channel,proto3,oneof" json:"channel,omitempty"`
+	// Empty: only events without a campaign.
+	CampaignId *string           `protobuf:"bytes,5,opt,name=campaign_id,json=campaignId,proto3,oneof" json:"campaign_id,omitempty"`
+	UserId     string            `protobuf:"bytes,6,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Metadata   map[string]string `protobuf:"bytes,7,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Tags       []string          `protobuf:"bytes,8,rep,name=tags,proto3" json:"tags,omitempty"`
+	// "all" (default) or "any".
+	TagMatch string `protobuf:"bytes,9,opt,name=tag_match,json=tagMatch,proto3" json:"tag_match,omitempty"`
+	// "channel", "campaign_id", "user_id", "time", "hour_of_day",
+	// "day_of_week", "metadata.<key>", or several joined by ",".
+	GroupBy string `protobuf:"bytes,10,opt,name=group_by,json=groupBy,proto3" json:"group_by,omitempty"`
+	// minute | hour | day | week | month, for group_by time.
+	Interval string `protobuf:"bytes,11,opt,name=interval,proto3" json:"interval,omitempty"`
+	// sum | avg | min | max | p50 | p90 | p95 | p99 of field.
+	Aggregate string `protobuf:"bytes,12,opt,name=aggregate,proto3" json:"aggregate,omitempty"`
+	// "metadata.<key>".
+	Field string `protobuf:"bytes,13,opt,name=field,proto3" json:"field,omitempty"`
+	// key (default) | total_count | unique_users | value.
+	OrderBy string `protobuf:"bytes,14,opt,name=order_by,json=orderBy,proto3" json:"order_by,omitempty"`
+	// asc | desc.
+	Order      string `protobuf:"bytes,15,opt,name=order,proto3" json:"order,omitempty"`
+	Limit      int32  `protobuf:"varint,16,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset     int32  `protobuf:"varint,17,opt,name=offset,proto3" json:"offset,omitempty"`
+	CountsOnly bool   `protobuf:"varint,18,opt,name=counts_only,json=countsOnly,proto3" json:"counts_only,omitempty"`
+	// RFC 3339 time, or "latest" for the current watermark.
+	AsOf          string `protobuf:"bytes,19,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMetricsRequest) Reset() {
+	*x = GetMetricsRequest{}
+	mi := &file_metrics_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetricsRequest) ProtoMessage() {}
+
+func (x *GetMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_metrics_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetricsRequest.ProtoReflect.Descriptor instead.
+func (*GetMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_metrics_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetMetricsRequest) GetEventName() string {
+	if x != nil {
+		return x.EventName
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *GetMetricsRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *GetMetricsRequest) GetChannel() string {
+	if x != nil && x.Channel != nil {
+		return *x.Channel
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetCampaignId() string {
+	if x != nil && x.CampaignId != nil {
+		return *x.CampaignId
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *GetMetricsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *GetMetricsRequest) GetTagMatch() string {
+	if x != nil {
+		return x.TagMatch
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetGroupBy() string {
+	if x != nil {
+		return x.GroupBy
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetAggregate() string {
+	if x != nil {
+		return x.Aggregate
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetOrderBy() string {
+	if x != nil {
+		return x.OrderBy
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetOrder() string {
+	if x != nil {
+		return x.Order
+	}
+	return ""
+}
+
+func (x *GetMetricsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetMetricsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GetMetricsRequest) GetCountsOnly() bool {
+	if x != nil {
+		return x.CountsOnly
+	}
+	return false
+}
+
+func (x *GetMetricsRequest) GetAsOf() string {
+	if x != nil {
+		return x.AsOf
+	}
+	return ""
+}
+
+type MetricsGroup struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// One value per group_by dimension, when there are several.
+	Keys        []string `protobuf:"bytes,2,rep,name=keys,proto3" json:"keys,omitempty"`
+	TotalCount  int64    `protobuf:"varint,3,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	UniqueUsers int64    `protobuf:"varint,4,opt,name=unique_users,json=uniqueUsers,proto3" json:"unique_users,omitempty"`
+	// Unset when no event of the group has a number in field.
+	Value         *float64 `protobuf:"fixed64,5,opt,name=value,proto3,oneof" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetricsGroup) Reset() {
+	*x = MetricsGroup{}
+	mi := &file_metrics_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetricsGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricsGroup) ProtoMessage() {}
+
+func (x *MetricsGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_metrics_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricsGroup.ProtoReflect.Descriptor instead.
+func (*MetricsGroup) Descriptor() ([]byte, []int) {
+	return file_metrics_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *MetricsGroup) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *MetricsGroup) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *MetricsGroup) GetTotalCount() int64 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *MetricsGroup) GetUniqueUsers() int64 {
+	if x != nil {
+		return x.UniqueUsers
+	}
+	return 0
+}
+
+func (x *MetricsGroup) GetValue() float64 {
+	if x != nil && x.Value != nil {
+		return *x.Value
+	}
+	return 0
+}
+
+type GetMetricsResponse struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	EventName   string                 `protobuf:"bytes,1,opt,name=event_name,json=eventName,proto3" json:"event_name,omitempty"`
+	From        int64                  `protobuf:"varint,2,opt,name=from,proto3" json:"from,omitempty"`
+	To          int64                  `protobuf:"varint,3,opt,name=to,proto3" json:"to,omitempty"`
+	TotalCount  int64                  `protobuf:"varint,4,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	UniqueUsers int64                  `protobuf:"varint,5,opt,name=unique_users,json=uniqueUsers,proto3" json:"unique_users,omitempty"`
+	GroupBy     string                 `protobuf:"bytes,6,opt,name=group_by,json=groupBy,proto3" json:"group_by,omitempty"`
+	Groups      []*MetricsGroup        `protobuf:"bytes,7,rep,name=groups,proto3" json:"groups,omitempty"`
+	// Offset of the next page of groups; 0 on the last page.
+	NextOffset int32    `protobuf:"varint,8,opt,name=next_offset,json=nextOffset,proto3" json:"next_offset,omitempty"`
+	Aggregate  string   `protobuf:"bytes,9,opt,name=aggregate,proto3" json:"aggregate,omitempty"`
+	Field      string   `protobuf:"bytes,10,opt,name=field,proto3" json:"field,omitempty"`
+	Value      *float64 `protobuf:"fixed64,11,opt,name=value,proto3,oneof" json:"value,omitempty"`
+	// RFC 3339 watermark the query was pinned to, if any.
+	AsOf          string `protobuf:"bytes,12,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMetricsResponse) Reset() {
+	*x = GetMetricsResponse{}
+	mi := &file_metrics_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMetricsResponse) ProtoMessage() {}
+
+func (x *GetMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_metrics_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMetricsResponse.ProtoReflect.Descriptor instead.
+func (*GetMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_metrics_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetMetricsResponse) GetEventName() string {
+	if x != nil {
+		return x.EventName
+	}
+	return ""
+}
+
+func (x *GetMetricsResponse) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *GetMetricsResponse) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *GetMetricsResponse) GetTotalCount() int64 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+func (x *GetMetricsResponse) GetUniqueUsers() int64 {
+	if x != nil {
+		return x.UniqueUsers
+	}
+	return 0
+}
+
+func (x *GetMetricsResponse) GetGroupBy() string {
+	if x != nil {
+		return x.GroupBy
+	}
+	return ""
+}
+
+func (x *GetMetricsResponse) GetGroups() []*MetricsGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+func (x *GetMetricsResponse) GetNextOffset() int32 {
+	if x != nil {
+		return x.NextOffset
+	}
+	return 0
+}
+
+func (x *GetMetricsResponse) GetAggregate() string {
+	if x != nil {
+		return x.Aggregate
+	}
+	return ""
+}
+
+func (x *GetMetricsResponse) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *GetMetricsResponse) GetValue() float64 {
+	if x != nil && x.Value != nil {
+		return *x.Value
+	}
+	return 0
+}
+
+func (x *GetMetricsResponse) GetAsOf() string {
+	if x != nil {
+		return x.AsOf
+	}
+	return ""
+}
+
+var File_metrics_proto protoreflect.FileDescriptor
+
+const file_metrics_proto_rawDesc = "" +
+	"\n" +
+	"\rmetrics.proto\x12\x17eventmetrics.metrics.v1\"\x94\x05\n" +
+	"\x11GetMetricsRequest\x12\x1d\n" +
+	"\n" +
+	"event_name\x18\x01 \x01(\tR\teventName\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x03R\x02to\x12\x1d\n" +
+	"\achannel\x18\x04 \x01(\tH\x00R\achannel\x88\x01\x01\x12$\n" +
+	"\vcampaign_id\x18\x05 \x01(\tH\x01R\n" +
+	"campaignId\x88\x01\x01\x12\x17\n" +
+	"\auser_id\x18\x06 \x01(\tR\x06userId\x12T\n" +
+	"\bmetadata\x18\a \x03(\v28.eventmetrics.metrics.v1.GetMetricsRequest.MetadataEntryR\bmetadata\x12\x12\n" +
+	"\x04tags\x18\b \x03(\tR\x04tags\x12\x1b\n" +
+	"\ttag_match\x18\t \x01(\tR\btagMatch\x12\x19\n" +
+	"\bgroup_by\x18\n" +
+	" \x01(\tR\agroupBy\x12\x1a\n" +
+	"\binterval\x18\v \x01(\tR\binterval\x12\x1c\n" +
+	"\taggregate\x18\f \x01(\tR\taggregate\x12\x14\n" +
+	"\x05field\x18\r \x01(\tR\x05field\x12\x19\n" +
+	"\border_by\x18\x0e \x01(\tR\aorderBy\x12\x14\n" +
+	"\x05order\x18\x0f \x01(\tR\x05order\x12\x14\n" +
+	"\x05limit\x18\x10 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x11 \x01(\x05R\x06offset\x12\x1f\n" +
+	"\vcounts_only\x18\x12 \x01(\bR\n" +
+	"countsOnly\x12\x13\n" +
+	"\x05as_of\x18\x13 \x01(\tR\x04asOf\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\n" +
+	"\n" +
+	"\b_channelB\x0e\n" +
+	"\f_campaign_id\"\x9d\x01\n" +
+	"\fMetricsGroup\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x12\n" +
+	"\x04keys\x18\x02 \x03(\tR\x04keys\x12\x1f\n" +
+	"\vtotal_count\x18\x03 \x01(\x03R\n" +
+	"totalCount\x12!\n" +
+	"\funique_users\x18\x04 \x01(\x03R\vuniqueUsers\x12\x19\n" +
+	"\x05value\x18\x05 \x01(\x01H\x00R\x05value\x88\x01\x01B\b\n" +
+	"\x06_value\"\x84\x03\n" +
+	"\x12GetMetricsResponse\x12\x1d\n" +
+	"\n" +
+	"event_name\x18\x01 \x01(\tR\teventName\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\x03R\x02to\x12\x1f\n" +
+	"\vtotal_count\x18\x04 \x01(\x03R\n" +
+	"totalCount\x12!\n" +
+	"\funique_users\x18\x05 \x01(\x03R\vuniqueUsers\x12\x19\n" +
+	"\bgroup_by\x18\x06 \x01(\tR\agroupBy\x12=\n" +
+	"\x06groups\x18\a \x03(\v2%.eventmetrics.metrics.v1.MetricsGroupR\x06groups\x12\x1f\n" +
+	"\vnext_offset\x18\b \x01(\x05R\n" +
+	"nextOffset\x12\x1c\n" +
+	"\taggregate\x18\t \x01(\tR\taggregate\x12\x14\n" +
+	"\x05field\x18\n" +
+	" \x01(\tR\x05field\x12\x19\n" +
+	"\x05value\x18\v \x01(\x01H\x00R\x05value\x88\x01\x01\x12\x13\n" +
+	"\x05as_of\x18\f \x01(\tR\x04asOfB\b\n" +
+	"\x06_value2w\n" +
+	"\x0eMetricsService\x12e\n" +
+	"\n" +
+	"GetMetrics\x12*.eventmetrics.metrics.v1.GetMetricsRequest\x1a+.eventmetrics.metrics.v1.GetMetricsResponseB@Z>event-metrics-service/internal/metrics/adapters/grpc/metricsv1b\x06proto3"
+
+var (
+	file_metrics_proto_rawDescOnce sync.Once
+	file_metrics_proto_rawDescData []byte
+)
+
+func file_metrics_proto_rawDescGZIP() []byte {
+	file_metrics_proto_rawDescOnce.Do(func() {
+		file_metrics_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_metrics_proto_rawDesc), len(file_metrics_proto_rawDesc)))
+	})
+	return file_metrics_proto_rawDescData
+}
+
+var file_metrics_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_metrics_proto_goTypes = []any{
+	(*GetMetricsRequest)(nil),  // 0: eventmetrics.metrics.v1.GetMetricsRequest
+	(*MetricsGroup)(nil),       // 1: eventmetrics.metrics.v1.MetricsGroup
+	(*GetMetricsResponse)(nil), // 2: eventmetrics.metrics.v1.GetMetricsResponse
+	nil,                        // 3: eventmetrics.metrics.v1.GetMetricsRequest.MetadataEntry
+}
+var file_metrics_proto_depIdxs = []int32{
+	3, // 0: eventmetrics.metrics.v1.GetMetricsRequest.metadata:type_name -> eventmetrics.metrics.v1.GetMetricsRequest.MetadataEntry
+	1, // 1: eventmetrics.metrics.v1.GetMetricsResponse.groups:type_name -> eventmetrics.metrics.v1.MetricsGroup
+	0, // 2: eventmetrics.metrics.v1.MetricsService.GetMetrics:input_type -> eventmetrics.metrics.v1.GetMetricsRequest
+	2, // 3: eventmetrics.metrics.v1.MetricsService.GetMetrics:output_type -> eventmetrics.metrics.v1.GetMetricsResponse
+	3, // [3:4] is the sub-list for method output_type
+	2, // [2:3] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_metrics_proto_init() }
+func file_metrics_proto_init() {
+	if File_metrics_proto != nil {
+		return
+	}
+	file_metrics_proto_msgTypes[0].OneofWrappers = []any{}
+	file_metrics_proto_msgTypes[1].OneofWrappers = []any{}
+	file_metrics_proto_msgTypes[2].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_metrics_proto_rawDesc), len(file_metrics_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_metrics_proto_goTypes,
+		DependencyIndexes: file_metrics_proto_depIdxs,
+		MessageInfos:      file_metrics_proto_msgTypes,
+	}.Build()
+	File_metrics_proto = out.File
+	file_metrics_proto_goTypes = nil
+	file_metrics_proto_depIdxs = nil
+}
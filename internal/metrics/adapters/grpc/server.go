@@ -0,0 +1,29 @@
+package grpc
+
+import (
+	"context"
+
+	"event-metrics-service/internal/metrics/adapters/grpc/metricsv1"
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+)
+
+//go:generate protoc --go_out=. --go_opt=module=event-metrics-service/internal/metrics/adapters/grpc --go-grpc_out=. --go-grpc_opt=module=event-metrics-service/internal/metrics/adapters/grpc metrics.proto
+
+type GetMetricsUseCase interface {
+	Execute(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error)
+}
+
+// Server implements MetricsService (metrics.proto) on the metrics use case;
+// register it on a *grpc.Server with metricsv1.RegisterMetricsServiceServer.
+type Server struct {
+	metricsv1.UnimplementedMetricsServiceServer
+
+	metrics GetMetricsUseCase
+}
+
+func NewServer(metrics GetMetricsUseCase) *Server {
+	return &Server{metrics: metrics}
+}
+
+var _ metricsv1.MetricsServiceServer = (*Server)(nil)
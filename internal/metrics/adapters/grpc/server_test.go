@@ -0,0 +1,145 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+
+	"event-metrics-service/internal/metrics/adapters/grpc/metricsv1"
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+)
+
+type fakeGetMetricsUseCase struct {
+	ExecuteFn func(in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error)
+	lastInput usecase.GetMetricsInput
+}
+
+func (f *fakeGetMetricsUseCase) Execute(_ context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+	f.lastInput = in
+	return f.ExecuteFn(in)
+}
+
+// dial serves uc in memory and returns a client connected to it.
+func dial(t *testing.T, uc GetMetricsUseCase, opts ...grpc.ServerOption) metricsv1.MetricsServiceClient {
+	t.Helper()
+	ln := bufconn.Listen(1 << 20)
+	s := grpc.NewServer(opts...)
+	metricsv1.RegisterMetricsServiceServer(s, NewServer(uc))
+	go s.Serve(ln)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return metricsv1.NewMetricsServiceClient(conn)
+}
+
+func TestGetMetrics_Call(t *testing.T) {
+	value := 12.5
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{
+				EventName: in.EventName, From: in.From, To: in.To, TotalCount: 3, GroupBy: in.GroupBy,
+				Aggregate: "sum", Field: "metadata.amount", Value: &value,
+				Groups: []domain.MetricsGroup{
+					{Key: "web|", Keys: []string{"web", ""}, TotalCount: 3, Value: &value},
+					{Key: "ios|x", Keys: []string{"ios", "x"}},
+				},
+			}, nil
+		},
+	}
+
+	resp, err := dial(t, uc).GetMetrics(context.Background(), &metricsv1.GetMetricsRequest{
+		EventName:  "purchase,refund",
+		From:       100,
+		To:         200,
+		Channel:    proto.String(""), // set but empty: every channel
+		CampaignId: proto.String(""), // set but empty: no campaign
+		Metadata:   map[string]string{"plan": "pro"},
+		Tags:       []string{"vip", "beta"},
+		GroupBy:    "channel,campaign_id",
+		Limit:      50,
+		CountsOnly: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	in := uc.lastInput
+	if in.EventName != "purchase,refund" || in.From != 100 || in.To != 200 || in.Channel != nil || in.CampaignID == nil || *in.CampaignID != "" {
+		t.Fatalf("unexpected input: %+v", in)
+	}
+	if in.Metadata["plan"] != "pro" || len(in.Tags) != 2 || in.GroupBy != "channel,campaign_id" || in.Limit != 50 || !in.CountsOnly {
+		t.Fatalf("unexpected input: %+v", in)
+	}
+
+	if resp.GetEventName() != "purchase,refund" || resp.GetTotalCount() != 3 || resp.Value == nil || resp.GetValue() != 12.5 {
+		t.Fatalf("unexpected response: %v", resp)
+	}
+	if len(resp.GetGroups()) != 2 {
+		t.Fatalf("expected 2 groups, got %v", resp.GetGroups())
+	}
+	first, second := resp.GetGroups()[0], resp.GetGroups()[1]
+	if first.GetKey() != "web|" || len(first.GetKeys()) != 2 || first.GetKeys()[1] != "" || first.GetValue() != 12.5 {
+		t.Fatalf("unexpected first group: %v", first)
+	}
+	if second.Value != nil {
+		t.Fatalf("expected no value on the second group, got %v", second.GetValue())
+	}
+}
+
+func TestGetMetrics_Errors(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			return nil, usecase.ErrInvalidGroupBy
+		},
+	}
+	refuse := grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if md, _ := metadata.FromIncomingContext(ctx); len(md.Get("x-api-key")) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "missing api key")
+		}
+		return handler(ctx, req)
+	})
+	client := dial(t, uc, refuse)
+	withKey := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "k")
+
+	cases := []struct {
+		name    string
+		ctx     context.Context
+		req     *metricsv1.GetMetricsRequest
+		code    codes.Code
+		message string
+	}{
+		{"invalid query", withKey, &metricsv1.GetMetricsRequest{EventName: "purchase"}, codes.InvalidArgument, "invalid group_by value"},
+		{"refused", context.Background(), &metricsv1.GetMetricsRequest{EventName: "purchase"}, codes.Unauthenticated, "missing api key"},
+		{"bad as_of", withKey, &metricsv1.GetMetricsRequest{EventName: "purchase", AsOf: "now"}, codes.InvalidArgument, "invalid as_of"},
+	}
+	for _, tc := range cases {
+		_, err := client.GetMetrics(tc.ctx, tc.req)
+		if st := status.Convert(err); st.Code() != tc.code || st.Message() != tc.message {
+			t.Fatalf("%s: expected %v %q, got %v", tc.name, tc.code, tc.message, err)
+		}
+	}
+}
+
+func TestStatusOf_ContextErrors(t *testing.T) {
+	if code := status.Code(statusOf(context.DeadlineExceeded)); code != codes.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", code)
+	}
+	if code := status.Code(statusOf(context.Canceled)); code != codes.Canceled {
+		t.Fatalf("expected Canceled, got %v", code)
+	}
+}
@@ -0,0 +1,121 @@
+package fiber
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type DetectAnomaliesUseCase interface {
+	Execute(ctx context.Context, in usecase.DetectAnomaliesInput) (*domain.AnomalyReport, error)
+}
+
+type AnomalyHandler struct {
+	uc DetectAnomaliesUseCase
+}
+
+func NewAnomalyHandler(uc DetectAnomaliesUseCase) *AnomalyHandler {
+	return &AnomalyHandler{uc: uc}
+}
+
+// GetAnomalies godoc
+// @Summary Unusual spikes and drops of an event
+// @Description Scores the count of every hour or day bucket against the same bucket in the previous weeks: z = (count - mean) / stddev, with stddev at least √mean.
+// @Description Buckets with |z| >= threshold are flagged as spike or drop. The last bucket is scored on its count up to to, so end to on a bucket boundary to avoid flagging an hour in progress.
+// @Tags Metrics
+// @Produce json
+// @Param event_name query string true "Event name (comma-separated for several)"
+// @Param from query int true "From timestamp"
+// @Param to query int true "To timestamp"
+// @Param interval query string false "Bucket: hour | day (default hour)"
+// @Param seasons query int false "Previous weeks in the baseline, 1 to 12 (default 4)"
+// @Param threshold query number false "Flagged |z-score| (default 3)"
+// @Param anomalies_only query bool false "Only return flagged buckets"
+// @Param channel query string false "Channel filter"
+// @Param Authorization header string false "Bearer JWT from OIDC_ISSUER, required when it is set"
+// @Success 200 {object} AnomalyResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read"
+// @Failure 429 {object} ErrorResponse "query_concurrency_limited: too many concurrent queries for this API key"
+// @Failure 500 {object} ErrorResponse
+// @Router /metrics/anomalies [get]
+func (h *AnomalyHandler) GetAnomalies(c *fiber.Ctx) error {
+	from, err := strconv.ParseInt(c.Query("from", ""), 10, 64)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid 'from' parameter",
+		})
+	}
+	to, err := strconv.ParseInt(c.Query("to", ""), 10, 64)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid 'to' parameter",
+		})
+	}
+
+	in := usecase.DetectAnomaliesInput{
+		EventName: parseEventNames(c),
+		From:      from,
+		To:        to,
+		Metadata:  parseMetadataFilters(c),
+		Interval:  c.Query("interval", "hour"),
+	}
+	if channel := c.Query("channel", ""); channel != "" {
+		in.Channel = &channel
+	}
+	if seasons := c.Query("seasons", ""); seasons != "" {
+		n, err := strconv.Atoi(seasons)
+		if err != nil || n < 1 {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid 'seasons' parameter",
+			})
+		}
+		in.Seasons = n
+	}
+	if threshold := c.Query("threshold", ""); threshold != "" {
+		v, err := strconv.ParseFloat(threshold, 64)
+		if err != nil || v <= 0 {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid 'threshold' parameter",
+			})
+		}
+		in.Threshold = v
+	}
+
+	res, err := h.uc.Execute(c.UserContext(), in)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	points := res.Points
+	if c.QueryBool("anomalies_only", false) {
+		points = res.Anomalies()
+	}
+	resp := AnomalyResponse{
+		EventName: res.EventName,
+		From:      res.From,
+		To:        res.To,
+		Interval:  res.Interval,
+		Seasons:   res.Seasons,
+		Threshold: res.Threshold,
+		Points:    make([]AnomalyPointResponse, 0, len(points)),
+	}
+	for _, p := range points {
+		resp.Points = append(resp.Points, AnomalyPointResponse{
+			Bucket:    p.Bucket,
+			Count:     p.Count,
+			Expected:  p.Expected,
+			StdDev:    p.StdDev,
+			ZScore:    p.ZScore,
+			Direction: p.Direction,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
@@ -0,0 +1,93 @@
+package fiber_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpadapter "event-metrics-service/internal/metrics/adapters/http/fiber"
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeDetectAnomaliesUseCase struct {
+	ExecuteFn func(in usecase.DetectAnomaliesInput) (*domain.AnomalyReport, error)
+	lastInput usecase.DetectAnomaliesInput
+}
+
+func (f *fakeDetectAnomaliesUseCase) Execute(_ context.Context, in usecase.DetectAnomaliesInput) (*domain.AnomalyReport, error) {
+	f.lastInput = in
+	return f.ExecuteFn(in)
+}
+
+func getAnomalies(t *testing.T, uc httpadapter.DetectAnomaliesUseCase, path string) *http.Response {
+	t.Helper()
+	app := fiber.New()
+	app.Get("/metrics/anomalies", httpadapter.NewAnomalyHandler(uc).GetAnomalies)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	return resp
+}
+
+func TestGetAnomalies_OnlyFlagged(t *testing.T) {
+	bucket := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	uc := &fakeDetectAnomaliesUseCase{
+		ExecuteFn: func(in usecase.DetectAnomaliesInput) (*domain.AnomalyReport, error) {
+			return &domain.AnomalyReport{
+				EventName: in.EventName,
+				Interval:  in.Interval,
+				Points: []domain.AnomalyPoint{
+					{Bucket: bucket, Count: 130, Expected: 140},
+					{Bucket: bucket.Add(time.Hour), Count: 12, Expected: 140, ZScore: -10.8, Direction: domain.AnomalyDrop},
+				},
+			}, nil
+		},
+	}
+
+	resp := getAnomalies(t, uc, "/metrics/anomalies?event_name=signup&from=100&to=200&anomalies_only=true&threshold=2.5&channel=web")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	// Hourly buckets unless asked otherwise.
+	if in := uc.lastInput; in.Interval != "hour" || in.Threshold != 2.5 || in.Seasons != 0 || *in.Channel != "web" {
+		t.Fatalf("unexpected input: %+v", in)
+	}
+
+	var body httpadapter.AnomalyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(body.Points) != 1 || body.Points[0].Direction != "drop" || body.Points[0].Count != 12 {
+		t.Fatalf("unexpected response: %+v", body)
+	}
+}
+
+func TestGetAnomalies_BadRequest(t *testing.T) {
+	for name, path := range map[string]string{
+		"missing from":   "/metrics/anomalies?event_name=signup&to=200",
+		"zero seasons":   "/metrics/anomalies?event_name=signup&from=100&to=200&seasons=0",
+		"bad threshold":  "/metrics/anomalies?event_name=signup&from=100&to=200&threshold=-1",
+		"usecase error":  "/metrics/anomalies?event_name=signup&from=100&to=200&interval=week",
+		"missing events": "/metrics/anomalies?from=100&to=200",
+	} {
+		uc := &fakeDetectAnomaliesUseCase{
+			ExecuteFn: func(in usecase.DetectAnomaliesInput) (*domain.AnomalyReport, error) {
+				if in.EventName == "" {
+					return nil, usecase.ErrInvalidMetricsQuery
+				}
+				return nil, usecase.ErrInvalidAnomaly
+			},
+		}
+		if resp := getAnomalies(t, uc, path); resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", name, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package fiber
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ListChannelsUseCase interface {
+	Execute(ctx context.Context, in usecase.ListChannelsInput) ([]domain.DimensionValue, error)
+}
+
+type ChannelsHandler struct {
+	uc ListChannelsUseCase
+}
+
+func NewChannelsHandler(uc ListChannelsUseCase) *ChannelsHandler {
+	return &ChannelsHandler{uc: uc}
+}
+
+// ListChannels godoc
+// @Summary Channel catalog
+// @Description Lists the channels with events in the range, most frequent first, with their event count. At most 10000 channels are returned.
+// @Tags Metrics
+// @Produce json
+// @Param from query int true "From timestamp"
+// @Param to query int true "To timestamp"
+// @Param Authorization header string false "Bearer JWT from OIDC_ISSUER, required when it is set"
+// @Success 200 {object} ChannelsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read"
+// @Failure 429 {object} ErrorResponse "query_concurrency_limited: too many concurrent queries for this API key"
+// @Failure 500 {object} ErrorResponse
+// @Router /metrics/channels [get]
+func (h *ChannelsHandler) ListChannels(c *fiber.Ctx) error {
+	from, err := strconv.ParseInt(c.Query("from", ""), 10, 64)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid 'from' parameter",
+		})
+	}
+	to, err := strconv.ParseInt(c.Query("to", ""), 10, 64)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid 'to' parameter",
+		})
+	}
+
+	channels, err := h.uc.Execute(c.UserContext(), usecase.ListChannelsInput{From: from, To: to})
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	resp := ChannelsResponse{
+		From:     from,
+		To:       to,
+		Channels: make([]DimensionValueResponse, 0, len(channels)),
+	}
+	for _, v := range channels {
+		resp.Channels = append(resp.Channels, DimensionValueResponse{Value: v.Value, Count: v.Count})
+	}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
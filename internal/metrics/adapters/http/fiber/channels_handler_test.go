@@ -0,0 +1,75 @@
+package fiber_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpadapter "event-metrics-service/internal/metrics/adapters/http/fiber"
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeListChannelsUseCase struct {
+	ExecuteFn func(in usecase.ListChannelsInput) ([]domain.DimensionValue, error)
+}
+
+func (f *fakeListChannelsUseCase) Execute(_ context.Context, in usecase.ListChannelsInput) ([]domain.DimensionValue, error) {
+	return f.ExecuteFn(in)
+}
+
+func getChannels(t *testing.T, uc httpadapter.ListChannelsUseCase, path string) *http.Response {
+	t.Helper()
+	app := fiber.New()
+	app.Get("/metrics/channels", httpadapter.NewChannelsHandler(uc).ListChannels)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	return resp
+}
+
+func TestListChannels_Success(t *testing.T) {
+	uc := &fakeListChannelsUseCase{
+		ExecuteFn: func(in usecase.ListChannelsInput) ([]domain.DimensionValue, error) {
+			if in.From != 100 || in.To != 200 {
+				t.Fatalf("unexpected input: %+v", in)
+			}
+			return []domain.DimensionValue{{Value: "web", Count: 30}, {Value: "ios", Count: 12}}, nil
+		},
+	}
+
+	resp := getChannels(t, uc, "/metrics/channels?from=100&to=200")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body httpadapter.ChannelsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.From != 100 || len(body.Channels) != 2 || body.Channels[0].Value != "web" || body.Channels[0].Count != 30 {
+		t.Fatalf("unexpected response: %+v", body)
+	}
+}
+
+func TestListChannels_BadRequest(t *testing.T) {
+	uc := &fakeListChannelsUseCase{
+		ExecuteFn: func(in usecase.ListChannelsInput) ([]domain.DimensionValue, error) {
+			return nil, usecase.ErrInvalidTimeRange
+		},
+	}
+	for _, path := range []string{
+		"/metrics/channels?from=abc&to=200",
+		"/metrics/channels?from=300&to=200",
+	} {
+		if resp := getChannels(t, uc, path); resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", path, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type DimensionValuesUseCase interface {
+	Values(ctx context.Context, dimension, prefix string) (domain.DimensionValues, error)
+}
+
+type DimensionsHandler struct {
+	uc DimensionValuesUseCase
+}
+
+func NewDimensionsHandler(uc DimensionValuesUseCase) *DimensionsHandler {
+	return &DimensionsHandler{uc: uc}
+}
+
+// GetDimensionValues godoc
+// @Summary Top values of a groupable dimension
+// @Description Served from a periodically refreshed cache, intended for filter dropdowns.
+// @Description Dimensions: channel, campaign_id and the configured metadata.<key> dimensions.
+// @Tags Metrics
+// @Produce json
+// @Param name path string true "Dimension: channel | campaign_id | metadata.<key>"
+// @Param prefix query string false "Only values starting with this prefix (case-insensitive)"
+// @Param Authorization header string false "Bearer JWT from OIDC_ISSUER, required when it is set"
+// @Success 200 {object} DimensionValuesResponse
+// @Failure 401 {object} ErrorResponse "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read"
+// @Failure 404 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /metrics/dimensions/{name}/values [get]
+func (h *DimensionsHandler) GetDimensionValues(c *fiber.Ctx) error {
+	res, err := h.uc.Values(c.UserContext(), c.Params("name"), c.Query("prefix"))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrUnknownDimension):
+			return c.Status(http.StatusNotFound).JSON(ErrorResponse{
+				Error:   "unknown_dimension",
+				Message: err.Error(),
+			})
+		case errors.Is(err, usecase.ErrDimensionNotReady):
+			c.Set(fiber.HeaderRetryAfter, "5")
+			return c.Status(http.StatusServiceUnavailable).JSON(ErrorResponse{
+				Error:   "dimension_not_ready",
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+				Error: "internal_server_error",
+			})
+		}
+	}
+
+	resp := DimensionValuesResponse{
+		Dimension:   res.Dimension,
+		Values:      make([]DimensionValueResponse, 0, len(res.Values)),
+		RefreshedAt: res.RefreshedAt,
+	}
+	for _, v := range res.Values {
+		resp.Values = append(resp.Values, DimensionValueResponse{Value: v.Value, Count: v.Count})
+	}
+
+	return c.JSON(resp)
+}
@@ -0,0 +1,87 @@
+package fiber_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpadapter "event-metrics-service/internal/metrics/adapters/http/fiber"
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeDimensionValuesUseCase struct {
+	ValuesFn func(dimension, prefix string) (domain.DimensionValues, error)
+}
+
+func (f *fakeDimensionValuesUseCase) Values(_ context.Context, dimension, prefix string) (domain.DimensionValues, error) {
+	return f.ValuesFn(dimension, prefix)
+}
+
+func getDimensionValues(t *testing.T, uc httpadapter.DimensionValuesUseCase, path string) *http.Response {
+	t.Helper()
+	app := fiber.New()
+	app.Get("/metrics/dimensions/:name/values", httpadapter.NewDimensionsHandler(uc).GetDimensionValues)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	return resp
+}
+
+func TestGetDimensionValues_Success(t *testing.T) {
+	refreshed := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	uc := &fakeDimensionValuesUseCase{
+		ValuesFn: func(dimension, prefix string) (domain.DimensionValues, error) {
+			if dimension != "metadata.plan" || prefix != "p" {
+				t.Fatalf("unexpected input: %s %s", dimension, prefix)
+			}
+			return domain.DimensionValues{
+				Dimension:   dimension,
+				Values:      []domain.DimensionValue{{Value: "pro", Count: 30}},
+				RefreshedAt: refreshed,
+			}, nil
+		},
+	}
+
+	resp := getDimensionValues(t, uc, "/metrics/dimensions/metadata.plan/values?prefix=p")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body httpadapter.DimensionValuesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if body.Dimension != "metadata.plan" || len(body.Values) != 1 || body.Values[0].Count != 30 || !body.RefreshedAt.Equal(refreshed) {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+}
+
+func TestGetDimensionValues_Errors(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantStatus int
+	}{
+		{fmt.Errorf("%w: user_id", usecase.ErrUnknownDimension), http.StatusNotFound},
+		{usecase.ErrDimensionNotReady, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		uc := &fakeDimensionValuesUseCase{
+			ValuesFn: func(dimension, prefix string) (domain.DimensionValues, error) {
+				return domain.DimensionValues{}, tt.err
+			},
+		}
+		if resp := getDimensionValues(t, uc, "/metrics/dimensions/user_id/values"); resp.StatusCode != tt.wantStatus {
+			t.Fatalf("%v: expected %d, got %d", tt.err, tt.wantStatus, resp.StatusCode)
+		}
+	}
+}
@@ -1,9 +1,32 @@
 package fiber
 
+import (
+	"strings"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+)
+
+// MetricsGroupResponse is one group. With several group_by dimensions, key
+// joins their values with "|" and keys holds each value by dimension.
 type MetricsGroupResponse struct {
-	Key         string `json:"key"`
-	TotalCount  int64  `json:"total_count"`
-	UniqueUsers int64  `json:"unique_users"`
+	Key         string              `json:"key" example:"web|2025-12-07T10:00:00Z"`
+	Keys        map[string]string   `json:"keys,omitempty"`
+	TotalCount  int64               `json:"total_count"`
+	UniqueUsers int64               `json:"unique_users"`
+	Value       *float64            `json:"value,omitempty"`
+	Values      []*float64          `json:"values,omitempty"` // POST /metrics/query: one per aggregation, null without numeric values
+	Lag         *LagResponse        `json:"lag,omitempty"`
+	Conversion  *ConversionResponse `json:"conversion,omitempty"`
+	Change      *ChangesResponse    `json:"change,omitempty"`
+}
+
+// ExportObjectResponse locates an export written to object storage.
+type ExportObjectResponse struct {
+	URI    string `json:"uri" example:"s3://exports/ems/metrics-20251015T101500Z-3f9a1c2e.parquet"`
+	Format string `json:"format" example:"parquet"`
+	Groups int64  `json:"groups" example:"1440"`
 }
 
 type MetricsResponse struct {
@@ -14,9 +37,369 @@ type MetricsResponse struct {
 	UniqueUsers int64                  `json:"unique_users"`
 	GroupBy     string                 `json:"group_by,omitempty"`
 	Groups      []MetricsGroupResponse `json:"groups,omitempty"`
+	NextOffset  int                    `json:"next_offset,omitempty"` // offset of the next page of groups, when one follows
+
+	// Aggregate of a numeric metadata field; value is omitted when no
+	// matching event carries a number in it.
+	Aggregate string   `json:"aggregate,omitempty" example:"sum"`
+	Field     string   `json:"field,omitempty" example:"metadata.revenue"`
+	Value     *float64 `json:"value,omitempty" example:"1234.5"`
+
+	// Every aggregation of POST /metrics/query; the first is also
+	// aggregate, field and value.
+	Aggregations []AggregationResponse `json:"aggregations,omitempty"`
+
+	Mode       string                    `json:"mode,omitempty"`
+	Histogram  []HistogramBucketResponse `json:"histogram,omitempty"`
+	Lag        *LagResponse              `json:"lag,omitempty"`
+	Conversion *ConversionResponse       `json:"conversion,omitempty"`
+
+	Comparison *ComparisonResponse `json:"comparison,omitempty"`
+
+	AsOf *time.Time `json:"as_of,omitempty"`
+}
+
+type AggregationResponse struct {
+	Function string   `json:"function" example:"sum"`
+	Field    string   `json:"field" example:"metadata.amount"`
+	Value    *float64 `json:"value,omitempty" example:"1234.5"`
+}
+
+// ComparisonResponse is the same query over the comparison window, and the
+// change from it to the requested window.
+type ComparisonResponse struct {
+	Compare     string                 `json:"compare" example:"previous_period"`
+	From        int64                  `json:"from"`
+	To          int64                  `json:"to"`
+	TotalCount  int64                  `json:"total_count"`
+	UniqueUsers int64                  `json:"unique_users"`
+	Value       *float64               `json:"value,omitempty"`
+	Groups      []MetricsGroupResponse `json:"groups,omitempty"`
+	Change      ChangesResponse        `json:"change"`
+}
+
+type ChangesResponse struct {
+	TotalCount  ChangeResponse  `json:"total_count"`
+	UniqueUsers ChangeResponse  `json:"unique_users"`
+	Value       *ChangeResponse `json:"value,omitempty"`
+}
+
+// ChangeResponse is current - previous; percent is omitted when previous
+// is zero.
+type ChangeResponse struct {
+	Delta   float64  `json:"delta" example:"120"`
+	Percent *float64 `json:"percent,omitempty" example:"12.5"`
+}
+
+func toChangesResponse(c domain.Changes) ChangesResponse {
+	resp := ChangesResponse{
+		TotalCount:  ChangeResponse(c.TotalCount),
+		UniqueUsers: ChangeResponse(c.UniqueUsers),
+	}
+	if c.Value != nil {
+		v := ChangeResponse(*c.Value)
+		resp.Value = &v
+	}
+	return resp
+}
+
+// HistogramBucketResponse is one value bucket. A missing lower/upper bound
+// means the bucket is open on that side (underflow/overflow).
+type HistogramBucketResponse struct {
+	Bucket int      `json:"bucket"`
+	Lower  *float64 `json:"lower,omitempty"`
+	Upper  *float64 `json:"upper,omitempty"`
+	Count  int64    `json:"count"`
+}
+
+// LagResponse is the ingestion lag (received_at - event_time) distribution
+// in seconds.
+type LagResponse struct {
+	AvgSeconds float64 `json:"avg_seconds" example:"2.4"`
+	P50Seconds float64 `json:"p50_seconds" example:"0.8"`
+	P95Seconds float64 `json:"p95_seconds" example:"9.5"`
+	P99Seconds float64 `json:"p99_seconds" example:"41"`
+	MaxSeconds float64 `json:"max_seconds" example:"312.7"`
+}
+
+func toLagResponse(l *domain.LagStats) *LagResponse {
+	if l == nil {
+		return nil
+	}
+	return &LagResponse{
+		AvgSeconds: l.AvgSeconds,
+		P50Seconds: l.P50Seconds,
+		P95Seconds: l.P95Seconds,
+		P99Seconds: l.P99Seconds,
+		MaxSeconds: l.MaxSeconds,
+	}
+}
+
+// ConversionResponse is the denominator of mode=conversion and the rates of
+// the counted events to it; a rate is omitted when its denominator is zero.
+type ConversionResponse struct {
+	Denominator string   `json:"denominator,omitempty" example:"product_view"`
+	TotalCount  int64    `json:"total_count" example:"2400"`
+	UniqueUsers int64    `json:"unique_users" example:"910"`
+	Rate        *float64 `json:"rate,omitempty" example:"0.05"`
+	UserRate    *float64 `json:"user_rate,omitempty" example:"0.11"`
+}
+
+func toConversionResponse(c *domain.Conversion) *ConversionResponse {
+	if c == nil {
+		return nil
+	}
+	return &ConversionResponse{
+		Denominator: c.Denominator,
+		TotalCount:  c.TotalCount,
+		UniqueUsers: c.UniqueUsers,
+		Rate:        c.Rate,
+		UserRate:    c.UserRate,
+	}
+}
+
+// BatchMetricsRequest runs several queries at one as_of watermark.
+type BatchMetricsRequest struct {
+	AsOf    *time.Time            `json:"as_of,omitempty" example:"2025-12-07T10:00:00.123Z"`
+	Queries []MetricsQueryRequest `json:"queries"`
+}
+
+// MetricsQueryRequest mirrors the GET /metrics query parameters.
+type MetricsQueryRequest struct {
+	EventName   string            `json:"event_name"`
+	From        int64             `json:"from"`
+	To          int64             `json:"to"`
+	Channel     string            `json:"channel,omitempty"`
+	CampaignID  *string           `json:"campaign_id,omitempty"` // "" = events without a campaign
+	UserID      string            `json:"user_id,omitempty"`
+	GroupBy     string            `json:"group_by,omitempty"`
+	Interval    string            `json:"interval,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	TagMatch    string            `json:"tag_match,omitempty"`
+	Aggregate   string            `json:"aggregate,omitempty"`
+	Field       string            `json:"field,omitempty"`
+	Mode        string            `json:"mode,omitempty"`
+	BucketMin   float64           `json:"bucket_min,omitempty"`
+	BucketMax   float64           `json:"bucket_max,omitempty"`
+	BucketCount int               `json:"bucket_count,omitempty"`
+	Buckets     []float64         `json:"buckets,omitempty"`
+	Denominator string            `json:"denominator,omitempty"`
+	Compare     string            `json:"compare,omitempty"`
+	OrderBy     string            `json:"order_by,omitempty"`
+	Order       string            `json:"order,omitempty"`
+	Limit       int               `json:"limit,omitempty"`
+	Offset      int               `json:"offset,omitempty"`
+	CountsOnly  bool              `json:"counts_only,omitempty"`
+}
+
+func (q MetricsQueryRequest) toInput() usecase.GetMetricsInput {
+	in := usecase.GetMetricsInput{
+		EventName:   q.EventName,
+		From:        q.From,
+		To:          q.To,
+		GroupBy:     q.GroupBy,
+		Interval:    q.Interval,
+		CampaignID:  q.CampaignID,
+		UserID:      q.UserID,
+		Metadata:    q.Metadata,
+		Tags:        q.Tags,
+		TagMatch:    q.TagMatch,
+		Aggregate:   q.Aggregate,
+		Field:       q.Field,
+		Mode:        q.Mode,
+		Denominator: q.Denominator,
+		Compare:     q.Compare,
+		OrderBy:     q.OrderBy,
+		Order:       q.Order,
+		Limit:       q.Limit,
+		Offset:      q.Offset,
+		CountsOnly:  q.CountsOnly,
+	}
+	if q.Channel != "" {
+		channel := q.Channel
+		in.Channel = &channel
+	}
+	if q.Mode == domain.ModeCount && len(q.Buckets) > 0 {
+		in.Mode = domain.ModeHistogram
+	}
+	if in.Mode == domain.ModeHistogram {
+		in.Histogram = &domain.HistogramSpec{Min: q.BucketMin, Max: q.BucketMax, Count: q.BucketCount, Bounds: q.Buckets, Field: q.Field}
+		in.Field = ""
+	}
+	return in
+}
+
+// StructuredQueryRequest is the body of POST /metrics/query.
+type StructuredQueryRequest struct {
+	EventNames   []string                `json:"event_names" example:"purchase,refund"`
+	From         int64                   `json:"from" example:"1765065600"`
+	To           int64                   `json:"to" example:"1765151999"`
+	Filters      QueryFiltersRequest     `json:"filters"`
+	GroupBy      []QueryGroupByRequest   `json:"group_by,omitempty"`
+	Aggregations []QueryAggregateRequest `json:"aggregations,omitempty"`
+	OrderBy      string                  `json:"order_by,omitempty" example:"total_count"`
+	Order        string                  `json:"order,omitempty" example:"desc"`
+	Limit        int                     `json:"limit,omitempty"`
+	Offset       int                     `json:"offset,omitempty"`
+	Options      QueryOptionsRequest     `json:"options"`
+}
+
+// QueryFiltersRequest must all match.
+type QueryFiltersRequest struct {
+	Channel    string            `json:"channel,omitempty" example:"web"`
+	CampaignID *string           `json:"campaign_id,omitempty"` // "" = events without a campaign
+	UserID     string            `json:"user_id,omitempty"`
+	Metadata   map[string]string `json:"metadata,omitempty"`
+	Tags       []string          `json:"tags,omitempty"`
+	TagMatch   string            `json:"tag_match,omitempty" example:"any"`
+}
+
+// QueryGroupByRequest is one dimension; interval is required for time only.
+type QueryGroupByRequest struct {
+	Dimension string `json:"dimension" example:"time"`
+	Interval  string `json:"interval,omitempty" example:"hour"`
+}
+
+type QueryAggregateRequest struct {
+	Function string `json:"function" example:"sum"`
+	Field    string `json:"field" example:"metadata.amount"`
+}
+
+type QueryOptionsRequest struct {
+	CountsOnly bool   `json:"counts_only,omitempty"`
+	Compare    string `json:"compare,omitempty" example:"previous_period"`
+	AsOf       string `json:"as_of,omitempty" example:"latest"` // RFC3339 time or "latest"
+}
+
+// toInput returns the query of r, or a message for its first invalid field.
+func (r StructuredQueryRequest) toInput() (usecase.QueryMetricsInput, string) {
+	if len(r.EventNames) == 0 {
+		return usecase.QueryMetricsInput{}, "event_names is required"
+	}
+	in := usecase.QueryMetricsInput{
+		GetMetricsInput: usecase.GetMetricsInput{
+			EventName:  strings.Join(r.EventNames, ","),
+			From:       r.From,
+			To:         r.To,
+			CampaignID: r.Filters.CampaignID,
+			UserID:     r.Filters.UserID,
+			Metadata:   r.Filters.Metadata,
+			Tags:       r.Filters.Tags,
+			TagMatch:   r.Filters.TagMatch,
+			OrderBy:    r.OrderBy,
+			Order:      r.Order,
+			Limit:      r.Limit,
+			Offset:     r.Offset,
+			Compare:    r.Options.Compare,
+			CountsOnly: r.Options.CountsOnly,
+		},
+	}
+	if r.Filters.Channel != "" {
+		channel := r.Filters.Channel
+		in.Channel = &channel
+	}
+
+	dims := make([]string, 0, len(r.GroupBy))
+	for _, g := range r.GroupBy {
+		if g.Interval != "" {
+			if g.Dimension != "time" {
+				return usecase.QueryMetricsInput{}, "interval is only for the time dimension"
+			}
+			in.Interval = g.Interval
+		}
+		dims = append(dims, g.Dimension)
+	}
+	in.GroupBy = strings.Join(dims, ",")
+
+	for _, a := range r.Aggregations {
+		in.Aggregations = append(in.Aggregations, usecase.AggregationSpec{Aggregate: a.Function, Field: a.Field})
+	}
+
+	if r.Options.AsOf == asOfLatest {
+		in.AsOfLatest = true
+	} else if r.Options.AsOf != "" {
+		t, err := time.Parse(time.RFC3339Nano, r.Options.AsOf)
+		if err != nil {
+			return usecase.QueryMetricsInput{}, "invalid 'as_of' option"
+		}
+		in.AsOf = &t
+	}
+	return in, ""
+}
+
+type BatchMetricsResponse struct {
+	AsOf    time.Time         `json:"as_of"`
+	Results []MetricsResponse `json:"results"`
 }
 
 type ErrorResponse struct {
 	Error   string `json:"error" example:"invalid_event"`
 	Message string `json:"message" example:"Event payload is invalid"`
 }
+
+type DimensionValueResponse struct {
+	Value string `json:"value" example:"web"`
+	Count int64  `json:"count" example:"1520"`
+}
+
+type DimensionValuesResponse struct {
+	Dimension   string                   `json:"dimension" example:"channel"`
+	Values      []DimensionValueResponse `json:"values"`
+	RefreshedAt time.Time                `json:"refreshed_at"`
+}
+
+type RetentionCohortResponse struct {
+	Start    time.Time `json:"start"`
+	Users    int64     `json:"users"`
+	Returned []int64   `json:"returned"` // returned[k-1]: users back k buckets after start
+	Rates    []float64 `json:"rates"`    // returned as fractions of users
+}
+
+type RetentionResponse struct {
+	StartEvent  string                    `json:"start_event"`
+	ReturnEvent string                    `json:"return_event"`
+	From        int64                     `json:"from"`
+	To          int64                     `json:"to"`
+	Interval    string                    `json:"interval"`
+	Periods     int                       `json:"periods"`
+	Cohorts     []RetentionCohortResponse `json:"cohorts"`
+}
+
+type AnomalyPointResponse struct {
+	Bucket    time.Time `json:"bucket"`
+	Count     int64     `json:"count" example:"12"`
+	Expected  float64   `json:"expected" example:"140.5"` // mean count of the bucket in the previous weeks
+	StdDev    float64   `json:"stddev" example:"11.9"`
+	ZScore    float64   `json:"z_score" example:"-10.8"`
+	Direction string    `json:"direction,omitempty" example:"drop"` // spike or drop when flagged
+}
+
+type AnomalyResponse struct {
+	EventName string                 `json:"event_name" example:"signup"`
+	From      int64                  `json:"from"`
+	To        int64                  `json:"to"`
+	Interval  string                 `json:"interval" example:"hour"`
+	Seasons   int                    `json:"seasons" example:"4"`
+	Threshold float64                `json:"threshold" example:"3"`
+	Points    []AnomalyPointResponse `json:"points"`
+}
+
+type EventNameResponse struct {
+	Name      string    `json:"name" example:"signup"`
+	Count     int64     `json:"count" example:"1520"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+type EventNamesResponse struct {
+	From       int64               `json:"from"`
+	To         int64               `json:"to"`
+	EventNames []EventNameResponse `json:"event_names"`
+}
+
+type ChannelsResponse struct {
+	From     int64                    `json:"from"`
+	To       int64                    `json:"to"`
+	Channels []DimensionValueResponse `json:"channels"`
+}
@@ -0,0 +1,75 @@
+package fiber
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ListEventNamesUseCase interface {
+	Execute(ctx context.Context, in usecase.ListEventNamesInput) ([]domain.EventName, error)
+}
+
+type EventNamesHandler struct {
+	uc ListEventNamesUseCase
+}
+
+func NewEventNamesHandler(uc ListEventNamesUseCase) *EventNamesHandler {
+	return &EventNamesHandler{uc: uc}
+}
+
+// ListEventNames godoc
+// @Summary Event name catalog
+// @Description Lists the event names with events in the range, in name order, with their event count and first and last event time in the range. At most 10000 names are returned.
+// @Tags Metrics
+// @Produce json
+// @Param from query int true "From timestamp"
+// @Param to query int true "To timestamp"
+// @Param Authorization header string false "Bearer JWT from OIDC_ISSUER, required when it is set"
+// @Success 200 {object} EventNamesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read"
+// @Failure 429 {object} ErrorResponse "query_concurrency_limited: too many concurrent queries for this API key"
+// @Failure 500 {object} ErrorResponse
+// @Router /metrics/event-names [get]
+func (h *EventNamesHandler) ListEventNames(c *fiber.Ctx) error {
+	from, err := strconv.ParseInt(c.Query("from", ""), 10, 64)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid 'from' parameter",
+		})
+	}
+	to, err := strconv.ParseInt(c.Query("to", ""), 10, 64)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid 'to' parameter",
+		})
+	}
+
+	names, err := h.uc.Execute(c.UserContext(), usecase.ListEventNamesInput{From: from, To: to})
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	resp := EventNamesResponse{
+		From:       from,
+		To:         to,
+		EventNames: make([]EventNameResponse, 0, len(names)),
+	}
+	for _, n := range names {
+		resp.EventNames = append(resp.EventNames, EventNameResponse{
+			Name:      n.Name,
+			Count:     n.Count,
+			FirstSeen: n.FirstSeen,
+			LastSeen:  n.LastSeen,
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
@@ -0,0 +1,81 @@
+package fiber_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpadapter "event-metrics-service/internal/metrics/adapters/http/fiber"
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeListEventNamesUseCase struct {
+	ExecuteFn func(in usecase.ListEventNamesInput) ([]domain.EventName, error)
+}
+
+func (f *fakeListEventNamesUseCase) Execute(_ context.Context, in usecase.ListEventNamesInput) ([]domain.EventName, error) {
+	return f.ExecuteFn(in)
+}
+
+func getEventNames(t *testing.T, uc httpadapter.ListEventNamesUseCase, path string) *http.Response {
+	t.Helper()
+	app := fiber.New()
+	app.Get("/metrics/event-names", httpadapter.NewEventNamesHandler(uc).ListEventNames)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	return resp
+}
+
+func TestListEventNames_Success(t *testing.T) {
+	first := time.Date(2025, 12, 1, 8, 0, 0, 0, time.UTC)
+	uc := &fakeListEventNamesUseCase{
+		ExecuteFn: func(in usecase.ListEventNamesInput) ([]domain.EventName, error) {
+			if in.From != 100 || in.To != 200 {
+				t.Fatalf("unexpected input: %+v", in)
+			}
+			return []domain.EventName{{Name: "signup", Count: 42, FirstSeen: first, LastSeen: first.Add(time.Hour)}}, nil
+		},
+	}
+
+	resp := getEventNames(t, uc, "/metrics/event-names?from=100&to=200")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body httpadapter.EventNamesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(body.EventNames) != 1 {
+		t.Fatalf("unexpected response: %+v", body)
+	}
+	got := body.EventNames[0]
+	if got.Name != "signup" || got.Count != 42 || !got.FirstSeen.Equal(first) || !got.LastSeen.Equal(first.Add(time.Hour)) {
+		t.Fatalf("unexpected event name: %+v", got)
+	}
+}
+
+func TestListEventNames_BadRequest(t *testing.T) {
+	uc := &fakeListEventNamesUseCase{
+		ExecuteFn: func(in usecase.ListEventNamesInput) ([]domain.EventName, error) {
+			return nil, usecase.ErrInvalidTimeRange
+		},
+	}
+	for _, path := range []string{
+		"/metrics/event-names?to=200",
+		"/metrics/event-names?from=300&to=200",
+	} {
+		if resp := getEventNames(t, uc, path); resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", path, resp.StatusCode)
+		}
+	}
+}
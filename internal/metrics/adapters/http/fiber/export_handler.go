@@ -0,0 +1,343 @@
+package fiber
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+	"event-metrics-service/internal/parquet"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Destinations of an export.
+const (
+	ExportToResponse = "response"
+	ExportToS3       = "s3"
+)
+
+// parquetMediaType is the media type of Parquet files.
+const parquetMediaType = "application/vnd.apache.parquet"
+
+// ObjectUpload is an export being written to object storage: Close
+// completes the object and Abort drops it.
+type ObjectUpload interface {
+	io.Writer
+	Close() error
+	Abort() error
+	URI() string
+}
+
+// ObjectStore starts the upload of an export to object storage under name.
+type ObjectStore func(ctx context.Context, name string) ObjectUpload
+
+type MetricsHandlerOption func(*MetricsHandler)
+
+// WithExportStore lets exports be written to object storage with
+// destination=s3 instead of the response.
+func WithExportStore(store ObjectStore) MetricsHandlerOption {
+	return func(h *MetricsHandler) {
+		h.exportStore = store
+	}
+}
+
+// ExportMetrics godoc
+// @Summary Export grouped metrics as CSV or Parquet
+// @Description Streams every group of a grouped query as CSV or Parquet, with chunked transfer encoding, for spreadsheets
+// @Description and data tools. Takes the parameters of GET /metrics; group_by is required and limit, offset, top, compare
+// @Description and mode are not allowed. There is one column per group_by dimension (a timestamp for time in Parquet),
+// @Description then total_count and unique_users, and value when aggregate is set. If the export fails midway, the
+// @Description CSV is cut short and the Parquet file lacks its footer. destination=s3 writes the file to the export
+// @Description bucket instead and answers with its location once it is complete.
+// @Tags Metrics
+// @Produce text/csv
+// @Produce application/vnd.apache.parquet
+// @Produce json
+// @Param event_name query string true "Event name; several comma-separated or repeated names are counted together"
+// @Param from query int true "From timestamp"
+// @Param to query int true "To timestamp"
+// @Param group_by query string true "Group by: channel | campaign_id | user_id | time | hour_of_day | day_of_week | metadata.<key>, or a comma-separated combination"
+// @Param interval query string false "Interval: minute | hour | day | week (starting Monday) | month; at most 10000 buckets"
+// @Param aggregate query string false "Aggregate of field per group: sum | avg | min | max | p50 | p90 | p95 | p99"
+// @Param field query string false "Numeric metadata field to aggregate: metadata.<key>"
+// @Param campaign_id query string false "Only events of this campaign; empty (campaign_id=) for events without a campaign"
+// @Param user_id query string false "Only the events of this user, including anonymous events stitched to them"
+// @Param tags query string false "Comma separated tags; only events carrying all of them (or any, with tag_match=any)"
+// @Param tag_match query string false "How tags match: all (default) | any"
+// @Param order_by query string false "Order groups by: key (default) | total_count | unique_users | value (with aggregate)"
+// @Param order query string false "Order: asc | desc (default asc by key, desc otherwise)"
+// @Param as_of query string false "Only count events received at or before this RFC3339 time, or 'latest' for the current watermark"
+// @Param counts_only query bool false "Skip unique users (reported as 0)"
+// @Param format query string false "File format (default csv)" Enums(csv, parquet)
+// @Param destination query string false "Where the file goes: response (default) | s3 (needs EXPORT_S3_BUCKET)" Enums(response, s3)
+// @Param Authorization header string false "Bearer JWT from OIDC_ISSUER, required when it is set"
+// @Success 200 {string} string "CSV or Parquet stream, or an ExportObjectResponse with destination=s3"
+// @Failure 400 {object} ErrorResponse "invalid query, or query_range_too_large: the range or its time buckets exceed the configured maximum"
+// @Failure 401 {object} ErrorResponse "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read"
+// @Failure 429 {object} ErrorResponse "query_concurrency_limited: too many concurrent queries for this API key"
+// @Failure 500 {object} ErrorResponse
+// @Router /metrics/export [get]
+func (h *MetricsHandler) ExportMetrics(c *fiber.Ctx) error {
+	format := c.Query("format", MetricsFormatCSV)
+	if format != MetricsFormatCSV && format != MetricsFormatParquet {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "format must be csv or parquet",
+		})
+	}
+	in, errMsg := parseGetMetricsQuery(c)
+	if errMsg != "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": errMsg,
+		})
+	}
+	return h.exportMetrics(c, in, format)
+}
+
+// exportMetrics writes every group of in as a CSV or Parquet file, one
+// chunk per batch of groups, like streamMetrics does as NDJSON.
+func (h *MetricsHandler) exportMetrics(c *fiber.Ctx, in usecase.GetMetricsInput, format string) error {
+	destination := c.Query("destination", ExportToResponse)
+	switch {
+	case destination != ExportToResponse && destination != ExportToS3:
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "destination must be response or s3",
+		})
+	case destination == ExportToS3 && h.exportStore == nil:
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "exports to s3 are not enabled (EXPORT_S3_BUCKET)",
+		})
+	}
+
+	// The status line is sent before the first group is read, so refuse bad
+	// queries now rather than midway through the export.
+	if err := h.uc.ValidateStream(in); err != nil {
+		return writeError(c, err)
+	}
+
+	if destination == ExportToS3 {
+		return h.exportToStore(c, in, format)
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == MetricsFormatParquet {
+		contentType = parquetMediaType
+	}
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="metrics.`+format+`"`)
+	c.Status(http.StatusOK)
+
+	ctx := c.UserContext()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		_, _ = h.writeExport(ctx, in, format, w)
+	})
+	return nil
+}
+
+// exportToStore writes the export to object storage and answers with its
+// location once the object is complete. A failed export leaves no object.
+func (h *MetricsHandler) exportToStore(c *fiber.Ctx, in usecase.GetMetricsInput, format string) error {
+	ctx := c.UserContext()
+	upload := h.exportStore(ctx, exportObjectName(format))
+	w := bufio.NewWriter(upload)
+	n, err := h.writeExport(ctx, in, format, w)
+	if err == nil {
+		err = upload.Close()
+	}
+	if err != nil {
+		_ = upload.Abort()
+		if errors.Is(err, usecase.ErrQueryTimeout) {
+			return writeError(c, err)
+		}
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error:   "export_failed",
+			Message: "export to s3 failed; retry the request",
+		})
+	}
+	return c.Status(http.StatusOK).JSON(ExportObjectResponse{URI: upload.URI(), Format: format, Groups: n})
+}
+
+// exportObjectName names an export object by UTC time, with a random suffix
+// so concurrent exports do not overwrite each other.
+func exportObjectName(format string) string {
+	var suffix [4]byte
+	_, _ = rand.Read(suffix[:])
+	return "metrics-" + time.Now().UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(suffix[:]) + "." + format
+}
+
+// writeExport writes every group of in to w and returns how many were
+// written. CSV has no way to carry an error and a Parquet file is only
+// readable with the footer written on success, so a failed export is
+// recognizable by ending early.
+func (h *MetricsHandler) writeExport(ctx context.Context, in usecase.GetMetricsInput, format string, w *bufio.Writer) (int64, error) {
+	var enc metricsExportEncoder = &metricsCSVEncoder{w: csv.NewWriter(w), buf: w}
+	if format == MetricsFormatParquet {
+		enc = &metricsParquetEncoder{buf: w}
+	}
+	dims := domain.GroupByDimensions(in.GroupBy)
+
+	var n int64
+	err := h.uc.ExecuteStream(ctx, in,
+		func(res *domain.AggregatedMetrics) error {
+			if err := enc.header(dims, res.Aggregate != ""); err != nil {
+				return err
+			}
+			return enc.flush()
+		},
+		func(groups []domain.MetricsGroup) error {
+			for _, g := range groups {
+				keys := g.Keys
+				if len(keys) != len(dims) {
+					keys = []string{g.Key}
+				}
+				if err := enc.write(keys, g); err != nil {
+					return err
+				}
+			}
+			n += int64(len(groups))
+			// A failed flush means the client is gone and stops the
+			// export before the next fetch.
+			return enc.flush()
+		},
+	)
+	if err != nil {
+		_ = enc.flush()
+		return n, err
+	}
+	return n, enc.close()
+}
+
+// metricsExportEncoder writes one row per group: its value of every
+// group_by dimension, then the figures.
+type metricsExportEncoder interface {
+	// header starts the file, with a value column when the query has an
+	// aggregate.
+	header(dims []string, withValue bool) error
+	write(keys []string, g domain.MetricsGroup) error
+	flush() error
+	close() error
+}
+
+type metricsCSVEncoder struct {
+	w         *csv.Writer
+	buf       *bufio.Writer
+	withValue bool
+}
+
+func (e *metricsCSVEncoder) header(dims []string, withValue bool) error {
+	e.withValue = withValue
+	header := append(append([]string(nil), dims...), "total_count", "unique_users")
+	if withValue {
+		header = append(header, "value")
+	}
+	return e.w.Write(header)
+}
+
+func (e *metricsCSVEncoder) write(keys []string, g domain.MetricsGroup) error {
+	row := append(append([]string(nil), keys...),
+		strconv.FormatInt(g.TotalCount, 10),
+		strconv.FormatInt(g.UniqueUsers, 10),
+	)
+	if e.withValue {
+		value := ""
+		if g.Value != nil {
+			value = strconv.FormatFloat(*g.Value, 'f', -1, 64)
+		}
+		row = append(row, value)
+	}
+	return e.w.Write(row)
+}
+
+func (e *metricsCSVEncoder) flush() error {
+	e.w.Flush()
+	if err := e.w.Error(); err != nil {
+		return err
+	}
+	return e.buf.Flush()
+}
+
+func (e *metricsCSVEncoder) close() error {
+	return e.flush()
+}
+
+// metricsParquetEncoder writes a row group per batch of groups. Time
+// buckets are timestamps, other dimensions strings.
+type metricsParquetEncoder struct {
+	w         *parquet.Writer
+	buf       *bufio.Writer
+	columns   []parquet.Column
+	withValue bool
+}
+
+func (e *metricsParquetEncoder) header(dims []string, withValue bool) error {
+	e.withValue = withValue
+	for _, dim := range dims {
+		typ := parquet.String
+		if dim == "time" {
+			typ = parquet.Timestamp
+		}
+		e.columns = append(e.columns, parquet.Column{Name: dim, Type: typ})
+	}
+	e.columns = append(e.columns,
+		parquet.Column{Name: "total_count", Type: parquet.Int64},
+		parquet.Column{Name: "unique_users", Type: parquet.Int64},
+	)
+	if withValue {
+		e.columns = append(e.columns, parquet.Column{Name: "value", Type: parquet.Double, Optional: true})
+	}
+	e.w = parquet.NewWriter(e.buf, e.columns)
+	return nil
+}
+
+func (e *metricsParquetEncoder) write(keys []string, g domain.MetricsGroup) error {
+	row := make([]any, 0, len(e.columns))
+	for i, key := range keys {
+		if e.columns[i].Type != parquet.Timestamp {
+			row = append(row, key)
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, key)
+		if err != nil {
+			return fmt.Errorf("time bucket %q: %w", key, err)
+		}
+		row = append(row, t)
+	}
+	row = append(row, g.TotalCount, g.UniqueUsers)
+	if e.withValue {
+		var value any
+		if g.Value != nil {
+			value = *g.Value
+		}
+		row = append(row, value)
+	}
+	return e.w.Write(row)
+}
+
+func (e *metricsParquetEncoder) flush() error {
+	if e.w != nil {
+		if err := e.w.Flush(); err != nil {
+			return err
+		}
+	}
+	return e.buf.Flush()
+}
+
+func (e *metricsParquetEncoder) close() error {
+	if e.w == nil {
+		return errors.New("export ended before its header")
+	}
+	if err := e.w.Close(); err != nil {
+		return err
+	}
+	return e.buf.Flush()
+}
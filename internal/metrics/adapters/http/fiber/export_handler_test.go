@@ -0,0 +1,255 @@
+package fiber_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	httpadapter "event-metrics-service/internal/metrics/adapters/http/fiber"
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+	"event-metrics-service/internal/parquet"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// fakeUpload keeps an object in memory.
+type fakeUpload struct {
+	name      string
+	body      bytes.Buffer
+	completed bool
+	aborted   bool
+}
+
+func (u *fakeUpload) Write(p []byte) (int, error) { return u.body.Write(p) }
+func (u *fakeUpload) Close() error                { u.completed = true; return nil }
+func (u *fakeUpload) Abort() error                { u.aborted = true; return nil }
+func (u *fakeUpload) URI() string                 { return "s3://exports/" + u.name }
+
+func (u *fakeUpload) store(ctx context.Context, name string) httpadapter.ObjectUpload {
+	u.name = name
+	return u
+}
+
+// channelHours streams two channel and hour groups with a sum.
+func channelHours() *fakeGetMetricsUseCase {
+	value := 12.5
+	return &fakeGetMetricsUseCase{
+		StreamFn: func(ctx context.Context, in usecase.GetMetricsInput, head func(*domain.AggregatedMetrics) error, emit func([]domain.MetricsGroup) error) error {
+			if err := head(&domain.AggregatedMetrics{EventName: in.EventName, GroupBy: in.GroupBy, Aggregate: "sum", Field: "metadata.revenue"}); err != nil {
+				return err
+			}
+			return emit([]domain.MetricsGroup{
+				{Key: "ios|2025-12-07T10:00:00Z", Keys: []string{"ios", "2025-12-07T10:00:00Z"}, TotalCount: 10, UniqueUsers: 3, Value: &value},
+				{Key: "web|2025-12-07T11:00:00Z", Keys: []string{"web", "2025-12-07T11:00:00Z"}, TotalCount: 2, UniqueUsers: 1},
+			})
+		},
+	}
+}
+
+func setupExportApp(uc httpadapter.GetMetricsUseCase, opts ...httpadapter.MetricsHandlerOption) *fiber.App {
+	app := fiber.New()
+	app.Get("/metrics/export", httpadapter.NewMetricsHandler(uc, opts...).ExportMetrics)
+	return app
+}
+
+func TestExportMetrics_WritesCSV(t *testing.T) {
+	value := 12.5
+	uc := &fakeGetMetricsUseCase{
+		StreamFn: func(ctx context.Context, in usecase.GetMetricsInput, head func(*domain.AggregatedMetrics) error, emit func([]domain.MetricsGroup) error) error {
+			if err := head(&domain.AggregatedMetrics{EventName: in.EventName, GroupBy: in.GroupBy, Aggregate: "sum", Field: "metadata.revenue"}); err != nil {
+				return err
+			}
+			if err := emit([]domain.MetricsGroup{
+				{Key: "ios|2025-12-07T10:00:00Z", Keys: []string{"ios", "2025-12-07T10:00:00Z"}, TotalCount: 10, UniqueUsers: 3, Value: &value},
+			}); err != nil {
+				return err
+			}
+			return emit([]domain.MetricsGroup{
+				{Key: "web, desktop|2025-12-07T10:00:00Z", Keys: []string{"web, desktop", "2025-12-07T10:00:00Z"}, TotalCount: 2, UniqueUsers: 1},
+			})
+		},
+	}
+	app := setupApp(t, uc)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet,
+		"/metrics/export?event_name=purchase&from=100&to=200&group_by=channel,time&interval=hour&aggregate=sum&field=metadata.revenue", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	if cd := resp.Header.Get("Content-Disposition"); cd != `attachment; filename="metrics.csv"` {
+		t.Fatalf("unexpected content disposition %q", cd)
+	}
+
+	rows, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	want := [][]string{
+		{"channel", "time", "total_count", "unique_users", "value"},
+		{"ios", "2025-12-07T10:00:00Z", "10", "3", "12.5"},
+		{"web, desktop", "2025-12-07T10:00:00Z", "2", "1", ""},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("unexpected rows: %v", rows)
+	}
+	if uc.lastInput.GroupBy != "channel,time" || uc.lastInput.Interval != "hour" {
+		t.Fatalf("unexpected input: %+v", uc.lastInput)
+	}
+}
+
+func TestGetMetrics_AcceptCSV(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{
+		StreamFn: func(ctx context.Context, in usecase.GetMetricsInput, head func(*domain.AggregatedMetrics) error, emit func([]domain.MetricsGroup) error) error {
+			if err := head(&domain.AggregatedMetrics{EventName: in.EventName, GroupBy: in.GroupBy}); err != nil {
+				return err
+			}
+			return emit([]domain.MetricsGroup{{Key: "web", TotalCount: 15, UniqueUsers: 4}})
+		},
+	}
+	app := setupApp(t, uc)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics?event_name=purchase&from=100&to=200&group_by=channel", nil)
+	req.Header.Set("Accept", "text/csv")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	rows, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	want := [][]string{{"channel", "total_count", "unique_users"}, {"web", "15", "4"}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("unexpected rows: %v", rows)
+	}
+}
+
+func TestExportMetrics_Parquet(t *testing.T) {
+	resp, err := setupApp(t, channelHours()).Test(httptest.NewRequest(http.MethodGet,
+		"/metrics/export?event_name=purchase&from=100&to=200&group_by=channel,time&interval=hour&aggregate=sum&field=metadata.revenue&format=parquet", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/vnd.apache.parquet" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	columns, rows, err := parquet.Read(body)
+	if err != nil {
+		t.Fatalf("invalid parquet file: %v", err)
+	}
+	wantColumns := []parquet.Column{
+		{Name: "channel", Type: parquet.String},
+		{Name: "time", Type: parquet.Timestamp},
+		{Name: "total_count", Type: parquet.Int64},
+		{Name: "unique_users", Type: parquet.Int64},
+		{Name: "value", Type: parquet.Double, Optional: true},
+	}
+	if !reflect.DeepEqual(columns, wantColumns) {
+		t.Fatalf("unexpected columns: %+v", columns)
+	}
+	wantRows := [][]any{
+		{"ios", time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC), int64(10), int64(3), 12.5},
+		{"web", time.Date(2025, 12, 7, 11, 0, 0, 0, time.UTC), int64(2), int64(1), nil},
+	}
+	if !reflect.DeepEqual(rows, wantRows) {
+		t.Fatalf("unexpected rows: %v", rows)
+	}
+}
+
+func TestExportMetrics_ToS3(t *testing.T) {
+	upload := &fakeUpload{}
+	app := setupExportApp(channelHours(), httpadapter.WithExportStore(upload.store))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet,
+		"/metrics/export?event_name=purchase&from=100&to=200&group_by=channel,time&interval=hour&destination=s3", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	var out httpadapter.ExportObjectResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("invalid body: %v", err)
+	}
+	if out.URI != "s3://exports/"+upload.name || out.Format != "csv" || out.Groups != 2 {
+		t.Fatalf("unexpected response: %+v", out)
+	}
+	if !strings.HasPrefix(upload.name, "metrics-") || !strings.HasSuffix(upload.name, ".csv") || !upload.completed {
+		t.Fatalf("unexpected upload %q (completed %v)", upload.name, upload.completed)
+	}
+	if rows, err := csv.NewReader(&upload.body).ReadAll(); err != nil || len(rows) != 3 {
+		t.Fatalf("expected a header and 2 rows, got %v (%v)", rows, err)
+	}
+}
+
+func TestExportMetrics_ToS3FailureAborts(t *testing.T) {
+	for streamErr, want := range map[error]int{
+		errors.New("connection reset"):                      http.StatusInternalServerError,
+		fmt.Errorf("%w after 30s", usecase.ErrQueryTimeout): http.StatusGatewayTimeout,
+	} {
+		upload := &fakeUpload{}
+		uc := &fakeGetMetricsUseCase{
+			StreamFn: func(ctx context.Context, in usecase.GetMetricsInput, head func(*domain.AggregatedMetrics) error, emit func([]domain.MetricsGroup) error) error {
+				return streamErr
+			},
+		}
+		resp, err := setupExportApp(uc, httpadapter.WithExportStore(upload.store)).Test(httptest.NewRequest(http.MethodGet,
+			"/metrics/export?event_name=purchase&from=100&to=200&group_by=channel&format=parquet&destination=s3", nil))
+		if err != nil {
+			t.Fatalf("app.Test error: %v", err)
+		}
+		if resp.StatusCode != want {
+			t.Fatalf("%v: expected status %d, got %d", streamErr, want, resp.StatusCode)
+		}
+		if !upload.aborted || upload.completed {
+			t.Fatalf("%v: expected the upload to be aborted", streamErr)
+		}
+	}
+}
+
+func TestExportMetrics_BadRequests(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{ValidateErr: usecase.ErrInvalidStream}
+	app := setupApp(t, uc)
+
+	for _, target := range []string{
+		"/metrics/export?event_name=purchase&from=100&to=200",
+		"/metrics/export?event_name=purchase&from=100&to=200&group_by=channel&format=json",
+		"/metrics/export?event_name=purchase&from=100&to=200&group_by=channel&destination=s3",
+		"/metrics/export?from=100&to=200&group_by=channel",
+	} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, target, nil))
+		if err != nil {
+			t.Fatalf("app.Test error: %v", err)
+		}
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d", target, resp.StatusCode)
+		}
+	}
+	if uc.called {
+		t.Fatalf("usecase should not run for rejected exports")
+	}
+}
@@ -1,10 +1,14 @@
 package fiber
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"event-metrics-service/internal/metrics/core/domain"
 	"event-metrics-service/internal/metrics/core/usecase"
@@ -14,96 +18,459 @@ import (
 
 type GetMetricsUseCase interface {
 	Execute(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error)
+	ExecuteBatch(ctx context.Context, in usecase.BatchGetMetricsInput) (*usecase.BatchMetricsResult, error)
+	ValidateStream(in usecase.GetMetricsInput) error
+	ExecuteStream(
+		ctx context.Context,
+		in usecase.GetMetricsInput,
+		head func(*domain.AggregatedMetrics) error,
+		emit func([]domain.MetricsGroup) error,
+	) error
 }
 
+// Response formats of GetMetrics.
+const (
+	MetricsFormatJSON    = "json"
+	MetricsFormatNDJSON  = "ndjson"
+	MetricsFormatCSV     = "csv"
+	MetricsFormatParquet = "parquet"
+)
+
 type MetricsHandler struct {
-	uc GetMetricsUseCase
+	uc          GetMetricsUseCase
+	exportStore ObjectStore // nil without EXPORT_S3_BUCKET
 }
 
-func NewMetricsHandler(uc GetMetricsUseCase) *MetricsHandler {
-	return &MetricsHandler{uc: uc}
+func NewMetricsHandler(uc GetMetricsUseCase, opts ...MetricsHandlerOption) *MetricsHandler {
+	h := &MetricsHandler{uc: uc}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // GetMetrics godoc
 // @Summary Query aggregated metrics
-// @Description Returns metrics grouped by channel or time bucket
+// @Description Returns metrics grouped by channel, time bucket or a metadata key, or by up to three of them combined (group_by=channel,time).
+// @Description Filter on metadata with repeated metadata.<key>=<value> query parameters (exact match).
+// @Description format=ndjson streams every group instead of a page, with chunked transfer encoding: the first line is
+// @Description the MetricsResponse without groups, then one MetricsGroupResponse per line. If the stream fails midway,
+// @Description it ends with an ErrorResponse line. format=csv or parquet streams the same groups as a file, as GET /metrics/export does.
 // @Tags Metrics
 // @Accept json
 // @Produce json
-// @Param event_name query string true "Event name"
+// @Produce application/x-ndjson
+// @Produce text/csv
+// @Produce application/vnd.apache.parquet
+// @Param event_name query string true "Event name; several comma-separated or repeated names are counted together"
 // @Param from query int true "From timestamp"
 // @Param to query int true "To timestamp"
-// @Param group_by query string false "Group by: channel | time"
-// @Param interval query string false "Interval: minute | hour | day"
+// @Param group_by query string false "Group by: channel | campaign_id | user_id | time | hour_of_day | day_of_week | metadata.<key>, or a comma-separated combination"
+// @Param interval query string false "Interval: minute | hour | day | week (starting Monday) | month; at most 10000 buckets"
+// @Param aggregate query string false "Aggregate of field per group: sum | avg | min | max | p50 | p90 | p95 | p99"
+// @Param field query string false "Numeric metadata field to aggregate, or to bucket in histogram mode: metadata.<key>"
+// @Param mode query string false "Mode: histogram (value buckets over the event value, or over field) | lag (ingestion lag percentiles, received_at - event_time) | conversion (event_name counts divided by denominator counts, per group)"
+// @Param denominator query string false "Comma-separated denominator event names (mode=conversion), e.g. product_view for event_name=purchase"
+// @Param buckets query string false "Comma-separated ascending histogram bucket bounds, e.g. 0,10,50,100,500, instead of bucket_min, bucket_max and bucket_count; implies mode=histogram"
+// @Param bucket_min query number false "Histogram lower bound (mode=histogram)"
+// @Param bucket_max query number false "Histogram upper bound (mode=histogram)"
+// @Param bucket_count query int false "Number of equal-width histogram buckets (mode=histogram)"
+// @Param campaign_id query string false "Only events of this campaign; empty (campaign_id=) for events without a campaign"
+// @Param user_id query string false "Only the events of this user, including anonymous events stitched to them"
+// @Param tags query string false "Comma separated tags; only events carrying all of them (or any, with tag_match=any)"
+// @Param tag_match query string false "How tags match: all (default) | any"
+// @Param compare query string false "Also query the comparison window and return changes: previous_period | previous_year"
+// @Param order_by query string false "Order groups by: key (default) | total_count | unique_users | value (with aggregate)"
+// @Param order query string false "Order: asc | desc (default asc by key, desc otherwise)"
+// @Param limit query int false "Groups per page in key order, 1 to 10000 (default 10000); next_offset is set when more follow"
+// @Param offset query int false "Groups to skip, e.g. the next_offset of the previous page"
+// @Param format query string false "Response format (default json, or the format Accept asks for: application/x-ndjson, text/csv or application/vnd.apache.parquet); ndjson, csv and parquet need a group_by and no limit, offset, top, compare or mode" Enums(json, ndjson, csv, parquet)
+// @Param destination query string false "Where a csv or parquet file goes: response (default) | s3 (needs EXPORT_S3_BUCKET)" Enums(response, s3)
+// @Param as_of query string false "Only count events received at or before this RFC3339 time, or 'latest' for the current watermark"
+// @Param counts_only query bool false "Skip unique users (reported as 0); whole-hour counts by channel or time are then answered from the event counters when enabled"
+// @Param Authorization header string false "Bearer JWT from OIDC_ISSUER, required when it is set"
 // @Success 200 {object} MetricsResponse
-// @Failure 400 {object} ErrorResponse
+// @Failure 400 {object} ErrorResponse "invalid query, query_range_too_large: the range or its time buckets exceed the configured maximum, or query_too_expensive: the planner estimates a grouped query above METRICS_MAX_QUERY_COST or METRICS_MAX_QUERY_ROWS"
+// @Failure 401 {object} ErrorResponse "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read"
+// @Failure 429 {object} ErrorResponse "query_concurrency_limited: too many concurrent queries for this API key"
 // @Failure 500 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse "query_timeout: the query ran longer than METRICS_QUERY_TIMEOUT"
 // @Router /metrics [get]
 func (h *MetricsHandler) GetMetrics(c *fiber.Ctx) error {
-	eventName := c.Query("event_name", "")
-	if eventName == "" {
+	in, errMsg := parseGetMetricsQuery(c)
+	if errMsg != "" {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": "event_name is required",
+			"error": errMsg,
 		})
 	}
 
-	fromStr := c.Query("from", "")
-	toStr := c.Query("to", "")
-	if fromStr == "" || toStr == "" {
+	format := c.Query("format")
+	if format == "" {
+		format = MetricsFormatJSON
+		accept := c.Get(fiber.HeaderAccept)
+		switch {
+		case strings.Contains(accept, "application/x-ndjson"):
+			format = MetricsFormatNDJSON
+		case strings.Contains(accept, "text/csv"):
+			format = MetricsFormatCSV
+		case strings.Contains(accept, parquetMediaType):
+			format = MetricsFormatParquet
+		}
+	}
+	switch format {
+	case MetricsFormatJSON:
+	case MetricsFormatNDJSON:
+		return h.streamMetrics(c, in)
+	case MetricsFormatCSV, MetricsFormatParquet:
+		return h.exportMetrics(c, in, format)
+	default:
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": "from and to are required",
+			"error": "format must be json, ndjson, csv or parquet",
 		})
 	}
 
-	from, err := strconv.ParseInt(fromStr, 10, 64)
+	res, err := h.uc.Execute(c.UserContext(), in)
 	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(toMetricsResponse(res))
+}
+
+// parseGetMetricsQuery reads the query parameters of GET /metrics and
+// GET /metrics/export, except format.
+func parseGetMetricsQuery(c *fiber.Ctx) (usecase.GetMetricsInput, string) {
+	in, errMsg := parseMetricsInput(c)
+	if errMsg != "" {
+		return usecase.GetMetricsInput{}, errMsg
+	}
+
+	in.GroupBy = c.Query("group_by", "")
+	in.Interval = c.Query("interval", "")
+	in.Mode = c.Query("mode", "")
+	in.Denominator = c.Query("denominator", "")
+
+	in.OrderBy = c.Query("order_by", "")
+	in.Order = c.Query("order", "")
+
+	page := []struct {
+		name string
+		dst  *int
+	}{{"limit", &in.Limit}, {"offset", &in.Offset}}
+	for _, p := range page {
+		v := c.Query(p.name, "")
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return usecase.GetMetricsInput{}, "invalid '" + p.name + "' parameter"
+		}
+		*p.dst = n
+	}
+
+	if in.Mode == domain.ModeCount && c.Query("buckets") != "" {
+		in.Mode = domain.ModeHistogram
+	}
+	if in.Mode == domain.ModeHistogram {
+		spec, errMsg := parseHistogramSpec(c)
+		if errMsg != "" {
+			return usecase.GetMetricsInput{}, errMsg
+		}
+		// field names what to bucket, not an aggregate.
+		spec.Field, in.Field = in.Field, ""
+		in.Histogram = spec
+	}
+	return in, ""
+}
+
+// streamMetrics writes every group of in as NDJSON, one chunk per batch of
+// groups, so neither the service nor the client holds all of them.
+func (h *MetricsHandler) streamMetrics(c *fiber.Ctx, in usecase.GetMetricsInput) error {
+	// The status line is sent before the first group is read, so refuse bad
+	// queries now rather than midway through the stream.
+	if err := h.uc.ValidateStream(in); err != nil {
+		return writeError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/x-ndjson")
+	c.Status(http.StatusOK)
+
+	ctx := c.UserContext()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		enc := json.NewEncoder(w)
+		err := h.uc.ExecuteStream(ctx, in,
+			func(res *domain.AggregatedMetrics) error {
+				if err := enc.Encode(toMetricsResponse(res)); err != nil {
+					return err
+				}
+				return w.Flush()
+			},
+			func(groups []domain.MetricsGroup) error {
+				for _, g := range toGroupResponses(groups, in.GroupBy) {
+					if err := enc.Encode(g); err != nil {
+						return err
+					}
+				}
+				// A failed flush means the client is gone and stops the
+				// stream before the next fetch.
+				return w.Flush()
+			},
+		)
+		switch {
+		case errors.Is(err, usecase.ErrQueryTimeout):
+			_ = enc.Encode(ErrorResponse{Error: "query_timeout", Message: err.Error()})
+		case err != nil:
+			_ = enc.Encode(ErrorResponse{
+				Error:   "stream_failed",
+				Message: "stream stopped before the last group; retry the request",
+			})
+		}
+		_ = w.Flush()
+	})
+	return nil
+}
+
+// defaultTopN is the number of values GetTopValues returns without n.
+const defaultTopN = 10
+
+// GetTopValues godoc
+// @Summary Top values of a dimension
+// @Description Returns the n values of a dimension with the most events in the time range, or with the highest aggregate when aggregate and field are set, highest first.
+// @Description Empty values are skipped; total_count and unique_users cover every value.
+// @Tags Metrics
+// @Accept json
+// @Produce json
+// @Param event_name query string true "Event name; several comma-separated or repeated names are counted together"
+// @Param from query int true "From timestamp"
+// @Param to query int true "To timestamp"
+// @Param dimension query string true "Dimension: user_id | campaign_id | channel | metadata.<key>"
+// @Param n query int false "Number of values, 1 to 1000 (default 10)"
+// @Param aggregate query string false "Rank by this aggregate of field: sum | avg | min | max | p50 | p90 | p95 | p99"
+// @Param field query string false "Numeric metadata field to aggregate, or to bucket in histogram mode: metadata.<key>"
+// @Param campaign_id query string false "Only events of this campaign; empty (campaign_id=) for events without a campaign"
+// @Param user_id query string false "Only the events of this user, including anonymous events stitched to them"
+// @Param tags query string false "Comma separated tags; only events carrying all of them (or any, with tag_match=any)"
+// @Param tag_match query string false "How tags match: all (default) | any"
+// @Param compare query string false "Also query the comparison window and return changes: previous_period | previous_year"
+// @Param as_of query string false "Only count events received at or before this RFC3339 time, or 'latest' for the current watermark"
+// @Param counts_only query bool false "Skip unique users (reported as 0)"
+// @Param Authorization header string false "Bearer JWT from OIDC_ISSUER, required when it is set"
+// @Success 200 {object} MetricsResponse
+// @Failure 400 {object} ErrorResponse "invalid query, query_range_too_large: the range or its time buckets exceed the configured maximum, or query_too_expensive: the planner estimates a grouped query above METRICS_MAX_QUERY_COST or METRICS_MAX_QUERY_ROWS"
+// @Failure 401 {object} ErrorResponse "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read"
+// @Failure 429 {object} ErrorResponse "query_concurrency_limited: too many concurrent queries for this API key"
+// @Failure 500 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse "query_timeout: the query ran longer than METRICS_QUERY_TIMEOUT"
+// @Router /metrics/top [get]
+func (h *MetricsHandler) GetTopValues(c *fiber.Ctx) error {
+	in, errMsg := parseMetricsInput(c)
+	if errMsg != "" {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": "invalid 'from' parameter",
+			"error": errMsg,
 		})
 	}
-	to, err := strconv.ParseInt(toStr, 10, 64)
-	if err != nil {
+
+	in.GroupBy = c.Query("dimension", "")
+	if in.GroupBy == "" {
 		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-			"error": "invalid 'to' parameter",
+			"error": "dimension is required",
 		})
 	}
 
+	in.Top = defaultTopN
+	if nStr := c.Query("n", ""); nStr != "" {
+		n, err := strconv.Atoi(nStr)
+		if err != nil || n < 1 {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid 'n' parameter",
+			})
+		}
+		in.Top = n
+	}
+
+	res, err := h.uc.Execute(c.UserContext(), in)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(toMetricsResponse(res))
+}
+
+// parseMetricsInput parses the query parameters shared by GetMetrics and
+// GetTopValues, returning a message for the first invalid one.
+func parseMetricsInput(c *fiber.Ctx) (usecase.GetMetricsInput, string) {
+	eventName := parseEventNames(c)
+	if eventName == "" {
+		return usecase.GetMetricsInput{}, "event_name is required"
+	}
+
+	fromStr := c.Query("from", "")
+	toStr := c.Query("to", "")
+	if fromStr == "" || toStr == "" {
+		return usecase.GetMetricsInput{}, "from and to are required"
+	}
+
+	from, err := strconv.ParseInt(fromStr, 10, 64)
+	if err != nil {
+		return usecase.GetMetricsInput{}, "invalid 'from' parameter"
+	}
+	to, err := strconv.ParseInt(toStr, 10, 64)
+	if err != nil {
+		return usecase.GetMetricsInput{}, "invalid 'to' parameter"
+	}
+
+	in, errMsg := parseMetricsFilters(c, eventName)
+	in.From, in.To = from, to
+	return in, errMsg
+}
+
+// parseMetricsFilters parses the filters of a metrics query, everything but
+// its time range.
+func parseMetricsFilters(c *fiber.Ctx, eventName string) (usecase.GetMetricsInput, string) {
 	var channelPtr *string
 	channel := c.Query("channel", "")
 	if channel != "" {
 		channelPtr = &channel
 	}
 
-	groupBy := c.Query("group_by", "")
-	interval := c.Query("interval", "")
-
 	in := usecase.GetMetricsInput{
 		EventName: eventName,
-		From:      from,
-		To:        to,
 		Channel:   channelPtr,
-		GroupBy:   groupBy,
-		Interval:  interval,
+		Metadata:  parseMetadataFilters(c),
+		Aggregate: c.Query("aggregate", ""),
+		Field:     c.Query("field", ""),
+		UserID:    c.Query("user_id", ""),
+		TagMatch:  c.Query("tag_match", ""),
+		Compare:   c.Query("compare", ""),
+
+		CountsOnly: c.QueryBool("counts_only", false),
+	}
+	// An empty campaign_id= selects events without a campaign.
+	if c.Context().QueryArgs().Has("campaign_id") {
+		campaign := c.Query("campaign_id", "")
+		in.CampaignID = &campaign
+	}
+	if tags := c.Query("tags", ""); tags != "" {
+		in.Tags = strings.Split(tags, ",")
 	}
 
-	res, err := h.uc.Execute(c.Context(), in)
-	if err != nil {
-		switch {
-		case errors.Is(err, usecase.ErrInvalidMetricsQuery),
-			errors.Is(err, usecase.ErrInvalidTimeRange),
-			errors.Is(err, usecase.ErrInvalidGroupBy),
-			errors.Is(err, usecase.ErrInvalidInterval):
-			return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
-				Error:   "invalid_event",
-				Message: err.Error(),
-			})
-		default:
-			return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
-				Error: "internal_server_error",
-			})
+	if asOf := c.Query("as_of", ""); asOf == asOfLatest {
+		in.AsOfLatest = true
+	} else if asOf != "" {
+		t, err := time.Parse(time.RFC3339Nano, asOf)
+		if err != nil {
+			return usecase.GetMetricsInput{}, "invalid 'as_of' parameter"
 		}
+		in.AsOf = &t
+	}
+
+	return in, ""
+}
+
+// GetMetricsBatch godoc
+// @Summary Query several metrics at one snapshot
+// @Description Runs up to 20 metrics queries pinned to the same as_of watermark, so dashboard panels never disagree because events arrived between queries.
+// @Description When as_of is omitted the current watermark (newest received_at) is used and returned.
+// @Tags Metrics
+// @Accept json
+// @Produce json
+// @Param request body BatchMetricsRequest true "Queries"
+// @Param Authorization header string false "Bearer JWT from OIDC_ISSUER, required when it is set"
+// @Success 200 {object} BatchMetricsResponse
+// @Failure 400 {object} ErrorResponse "invalid query, query_range_too_large: the range or its time buckets exceed the configured maximum, or query_too_expensive: the planner estimates a grouped query above METRICS_MAX_QUERY_COST or METRICS_MAX_QUERY_ROWS"
+// @Failure 401 {object} ErrorResponse "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read"
+// @Failure 429 {object} ErrorResponse "query_concurrency_limited: too many concurrent queries for this API key"
+// @Failure 500 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse "query_timeout: the query ran longer than METRICS_QUERY_TIMEOUT"
+// @Router /metrics/batch [post]
+func (h *MetricsHandler) GetMetricsBatch(c *fiber.Ctx) error {
+	var req BatchMetricsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error:   "invalid_body",
+			Message: "Request body could not be parsed",
+		})
+	}
+
+	in := usecase.BatchGetMetricsInput{
+		AsOf:    req.AsOf,
+		Queries: make([]usecase.GetMetricsInput, 0, len(req.Queries)),
+	}
+	for _, q := range req.Queries {
+		in.Queries = append(in.Queries, q.toInput())
+	}
+
+	res, err := h.uc.ExecuteBatch(c.UserContext(), in)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	resp := BatchMetricsResponse{
+		AsOf:    res.AsOf,
+		Results: make([]MetricsResponse, 0, len(res.Results)),
+	}
+	for _, r := range res.Results {
+		resp.Results = append(resp.Results, toMetricsResponse(r))
 	}
 
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+// asOfLatest asks for the current watermark instead of an explicit time.
+const asOfLatest = "latest"
+
+func writeError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrQueryTimeout):
+		return c.Status(http.StatusGatewayTimeout).JSON(ErrorResponse{
+			Error:   "query_timeout",
+			Message: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrQueryRangeTooLarge):
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error:   "query_range_too_large",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrQueryTooExpensive):
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error:   "query_too_expensive",
+			Message: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrInvalidMetricsQuery),
+		errors.Is(err, usecase.ErrInvalidTimeRange),
+		errors.Is(err, usecase.ErrInvalidGroupBy),
+		errors.Is(err, usecase.ErrInvalidInterval),
+		errors.Is(err, usecase.ErrInvalidMode),
+		errors.Is(err, usecase.ErrInvalidHistogram),
+		errors.Is(err, usecase.ErrInvalidMetadata),
+		errors.Is(err, usecase.ErrInvalidTags),
+		errors.Is(err, usecase.ErrInvalidAggregate),
+		errors.Is(err, usecase.ErrInvalidTop),
+		errors.Is(err, usecase.ErrInvalidCompare),
+		errors.Is(err, usecase.ErrInvalidPage),
+		errors.Is(err, usecase.ErrInvalidOrder),
+		errors.Is(err, usecase.ErrInvalidRetention),
+		errors.Is(err, usecase.ErrInvalidAnomaly),
+		errors.Is(err, usecase.ErrInvalidBatch),
+		errors.Is(err, usecase.ErrInvalidStream),
+		errors.Is(err, usecase.ErrWatermarkDisabled),
+		errors.Is(err, usecase.ErrStreamDisabled):
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error:   "invalid_event",
+			Message: err.Error(),
+		})
+	default:
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error: "internal_server_error",
+		})
+	}
+}
+
+func toMetricsResponse(res *domain.AggregatedMetrics) MetricsResponse {
 	resp := MetricsResponse{
 		EventName:   res.EventName,
 		From:        res.From,
@@ -111,16 +478,147 @@ func (h *MetricsHandler) GetMetrics(c *fiber.Ctx) error {
 		TotalCount:  res.TotalCount,
 		UniqueUsers: res.UniqueUsers,
 		GroupBy:     res.GroupBy,
-		Groups:      make([]MetricsGroupResponse, 0, len(res.Groups)),
+		Groups:      toGroupResponses(res.Groups, res.GroupBy),
+		NextOffset:  res.NextOffset,
+		Aggregate:   res.Aggregate,
+		Field:       res.Field,
+		Value:       res.Value,
+		Mode:        res.Mode,
+		Lag:         toLagResponse(res.Lag),
+		Conversion:  toConversionResponse(res.Conversion),
+		AsOf:        res.AsOf,
 	}
 
-	for _, g := range res.Groups {
-		resp.Groups = append(resp.Groups, MetricsGroupResponse{
+	for _, a := range res.Aggregations {
+		resp.Aggregations = append(resp.Aggregations, AggregationResponse{
+			Function: a.Aggregate,
+			Field:    a.Field,
+			Value:    a.Value,
+		})
+	}
+
+	if cmp := res.Comparison; cmp != nil {
+		resp.Comparison = &ComparisonResponse{
+			Compare:     cmp.Compare,
+			From:        cmp.Previous.From,
+			To:          cmp.Previous.To,
+			TotalCount:  cmp.Previous.TotalCount,
+			UniqueUsers: cmp.Previous.UniqueUsers,
+			Value:       cmp.Previous.Value,
+			Groups:      toGroupResponses(cmp.Previous.Groups, res.GroupBy),
+			Change:      toChangesResponse(cmp.Change),
+		}
+	}
+
+	for _, b := range res.Histogram {
+		resp.Histogram = append(resp.Histogram, HistogramBucketResponse{
+			Bucket: b.Bucket,
+			Lower:  b.Lower,
+			Upper:  b.Upper,
+			Count:  b.Count,
+		})
+	}
+
+	return resp
+}
+
+func toGroupResponses(groups []domain.MetricsGroup, groupBy string) []MetricsGroupResponse {
+	resp := make([]MetricsGroupResponse, 0, len(groups))
+	dims := domain.GroupByDimensions(groupBy)
+	for _, g := range groups {
+		var keys map[string]string
+		if len(g.Keys) > 0 && len(g.Keys) == len(dims) {
+			keys = make(map[string]string, len(dims))
+			for i, dim := range dims {
+				keys[dim] = g.Keys[i]
+			}
+		}
+		gr := MetricsGroupResponse{
 			Key:         g.Key,
+			Keys:        keys,
 			TotalCount:  g.TotalCount,
 			UniqueUsers: g.UniqueUsers,
-		})
+			Value:       g.Value,
+			Values:      g.Values,
+			Lag:         toLagResponse(g.Lag),
+			Conversion:  toConversionResponse(g.Conversion),
+		}
+		if g.Change != nil {
+			c := toChangesResponse(*g.Change)
+			gr.Change = &c
+		}
+		resp = append(resp, gr)
 	}
+	return resp
+}
 
-	return c.Status(http.StatusOK).JSON(resp)
+// parseEventNames joins repeated event_name parameters, each of which may
+// itself list several names, into one comma-separated list.
+func parseEventNames(c *fiber.Ctx) string {
+	var names []string
+	for _, v := range c.Context().QueryArgs().PeekMulti("event_name") {
+		if len(v) > 0 {
+			names = append(names, string(v))
+		}
+	}
+	return strings.Join(names, domain.EventNameSeparator)
+}
+
+// parseMetadataFilters collects metadata.<key>=<value> query parameters.
+func parseMetadataFilters(c *fiber.Ctx) map[string]string {
+	var filters map[string]string
+	for k, v := range c.Queries() {
+		key, ok := strings.CutPrefix(k, domain.MetadataGroupPrefix)
+		if !ok {
+			continue
+		}
+		if filters == nil {
+			filters = map[string]string{}
+		}
+		filters[key] = v
+	}
+	return filters
+}
+
+// parseHistogramSpec reads buckets, or bucket_min/bucket_max/bucket_count.
+// The returned message is non-empty when a parameter is missing or
+// malformed.
+func parseHistogramSpec(c *fiber.Ctx) (*domain.HistogramSpec, string) {
+	if buckets := c.Query("buckets", ""); buckets != "" {
+		parts := strings.Split(buckets, ",")
+		bounds := make([]float64, len(parts))
+		for i, p := range parts {
+			b, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+			if err != nil {
+				return nil, "invalid 'buckets' parameter"
+			}
+			bounds[i] = b
+		}
+		if c.Query("bucket_min") != "" || c.Query("bucket_max") != "" || c.Query("bucket_count") != "" {
+			return nil, "buckets cannot be combined with bucket_min, bucket_max or bucket_count"
+		}
+		return &domain.HistogramSpec{Bounds: bounds}, ""
+	}
+
+	minStr := c.Query("bucket_min", "")
+	maxStr := c.Query("bucket_max", "")
+	countStr := c.Query("bucket_count", "")
+	if minStr == "" || maxStr == "" || countStr == "" {
+		return nil, "buckets, or bucket_min, bucket_max and bucket_count, are required for mode=histogram"
+	}
+
+	minV, err := strconv.ParseFloat(minStr, 64)
+	if err != nil {
+		return nil, "invalid 'bucket_min' parameter"
+	}
+	maxV, err := strconv.ParseFloat(maxStr, 64)
+	if err != nil {
+		return nil, "invalid 'bucket_max' parameter"
+	}
+	count, err := strconv.Atoi(countStr)
+	if err != nil {
+		return nil, "invalid 'bucket_count' parameter"
+	}
+
+	return &domain.HistogramSpec{Min: minV, Max: maxV, Count: count}, ""
 }
@@ -1,14 +1,21 @@
 package fiber_test
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	httpadapter "event-metrics-service/internal/metrics/adapters/http/fiber"
 	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/ports"
 	"event-metrics-service/internal/metrics/core/usecase"
 
 	"github.com/gofiber/fiber/v2"
@@ -16,9 +23,12 @@ import (
 
 // Fake usecase implementing the interface that handler depends on.
 type fakeGetMetricsUseCase struct {
-	ExecuteFn func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error)
-	lastInput usecase.GetMetricsInput
-	called    bool
+	ExecuteFn      func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error)
+	ExecuteBatchFn func(ctx context.Context, in usecase.BatchGetMetricsInput) (*usecase.BatchMetricsResult, error)
+	StreamFn       func(ctx context.Context, in usecase.GetMetricsInput, head func(*domain.AggregatedMetrics) error, emit func([]domain.MetricsGroup) error) error
+	ValidateErr    error
+	lastInput      usecase.GetMetricsInput
+	called         bool
 }
 
 func (f *fakeGetMetricsUseCase) Execute(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
@@ -30,11 +40,41 @@ func (f *fakeGetMetricsUseCase) Execute(ctx context.Context, in usecase.GetMetri
 	return nil, nil
 }
 
+func (f *fakeGetMetricsUseCase) ExecuteBatch(ctx context.Context, in usecase.BatchGetMetricsInput) (*usecase.BatchMetricsResult, error) {
+	f.called = true
+	if f.ExecuteBatchFn != nil {
+		return f.ExecuteBatchFn(ctx, in)
+	}
+	return nil, nil
+}
+
+func (f *fakeGetMetricsUseCase) ValidateStream(in usecase.GetMetricsInput) error {
+	f.lastInput = in
+	return f.ValidateErr
+}
+
+func (f *fakeGetMetricsUseCase) ExecuteStream(
+	ctx context.Context,
+	in usecase.GetMetricsInput,
+	head func(*domain.AggregatedMetrics) error,
+	emit func([]domain.MetricsGroup) error,
+) error {
+	f.called = true
+	f.lastInput = in
+	if f.StreamFn != nil {
+		return f.StreamFn(ctx, in, head, emit)
+	}
+	return nil
+}
+
 func setupApp(t *testing.T, uc httpadapter.GetMetricsUseCase) *fiber.App {
 	t.Helper()
 	app := fiber.New()
 	h := httpadapter.NewMetricsHandler(uc)
 	app.Get("/metrics", h.GetMetrics)
+	app.Get("/metrics/top", h.GetTopValues)
+	app.Get("/metrics/export", h.ExportMetrics)
+	app.Post("/metrics/batch", h.GetMetricsBatch)
 	return app
 }
 
@@ -176,15 +216,108 @@ func TestGetMetrics_Success_GroupByTime(t *testing.T) {
 	}
 }
 
+func TestGetMetrics_Success_GroupByChannelAndTime(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{
+				EventName:   in.EventName,
+				TotalCount:  3,
+				UniqueUsers: 2,
+				GroupBy:     in.GroupBy,
+				Groups: []domain.MetricsGroup{
+					{Key: "web|2025-12-07T10:00:00Z", Keys: []string{"web", "2025-12-07T10:00:00Z"}, TotalCount: 3, UniqueUsers: 2},
+				},
+			}, nil
+		},
+	}
+
+	app := setupApp(t, uc)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics?event_name=product_view&from=100&to=200&group_by=channel,time&interval=hour", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if uc.lastInput.GroupBy != "channel,time" {
+		t.Fatalf("expected group_by=channel,time, got %q", uc.lastInput.GroupBy)
+	}
+
+	var body httpadapter.MetricsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	g := body.Groups[0]
+	if g.Key != "web|2025-12-07T10:00:00Z" || g.Keys["channel"] != "web" || g.Keys["time"] != "2025-12-07T10:00:00Z" {
+		t.Fatalf("unexpected group: %+v", g)
+	}
+}
+
+func TestGetMetrics_Success_Aggregate(t *testing.T) {
+	sum, webSum := 150.5, 100.0
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{
+				EventName: in.EventName,
+				GroupBy:   in.GroupBy,
+				Aggregate: in.Aggregate,
+				Field:     in.Field,
+				Value:     &sum,
+				Groups: []domain.MetricsGroup{
+					{Key: "ios", TotalCount: 1},
+					{Key: "web", TotalCount: 2, Value: &webSum},
+				},
+			}, nil
+		},
+	}
+
+	app := setupApp(t, uc)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics?event_name=purchase&from=100&to=200&group_by=channel&aggregate=sum&field=metadata.revenue", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if uc.lastInput.Aggregate != "sum" || uc.lastInput.Field != "metadata.revenue" {
+		t.Fatalf("unexpected input: %+v", uc.lastInput)
+	}
+	// metadata.revenue must not be taken for a metadata filter.
+	if len(uc.lastInput.Metadata) != 0 {
+		t.Fatalf("unexpected metadata filters: %v", uc.lastInput.Metadata)
+	}
+
+	var body map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	groups := body["groups"].([]any)
+	if body["value"] != 150.5 || body["aggregate"] != "sum" || body["field"] != "metadata.revenue" {
+		t.Fatalf("unexpected body: %v", body)
+	}
+	if _, ok := groups[0].(map[string]any)["value"]; ok || groups[1].(map[string]any)["value"] != 100.0 {
+		t.Fatalf("unexpected groups: %v", groups)
+	}
+}
+
 // ------------------------------------------------------------
-// INVALID QUERY PARAM (bad int)
+// SUCCESS: metadata filter + group_by=metadata.<key>
 // ------------------------------------------------------------
 
-func TestGetMetrics_InvalidQueryParam(t *testing.T) {
+func TestGetMetrics_Success_MetadataFilterAndGroup(t *testing.T) {
 	uc := &fakeGetMetricsUseCase{
 		ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
-			t.Fatalf("usecase should not be called on invalid query params")
-			return nil, nil
+			if in.GroupBy != "metadata.product_id" {
+				t.Fatalf("expected group_by=metadata.product_id, got %s", in.GroupBy)
+			}
+			if len(in.Metadata) != 1 || in.Metadata["category"] != "shoes" {
+				t.Fatalf("unexpected metadata filters: %v", in.Metadata)
+			}
+			return &domain.AggregatedMetrics{EventName: in.EventName, GroupBy: in.GroupBy}, nil
 		},
 	}
 
@@ -192,8 +325,10 @@ func TestGetMetrics_InvalidQueryParam(t *testing.T) {
 
 	params := url.Values{}
 	params.Set("event_name", "product_view")
-	params.Set("from", "abc") // invalid
+	params.Set("from", "100")
 	params.Set("to", "200")
+	params.Set("group_by", "metadata.product_id")
+	params.Set("metadata.category", "shoes")
 
 	req := httptest.NewRequest(http.MethodGet, "/metrics?"+params.Encode(), nil)
 
@@ -201,71 +336,219 @@ func TestGetMetrics_InvalidQueryParam(t *testing.T) {
 	if err != nil {
 		t.Fatalf("app.Test error: %v", err)
 	}
-	if resp.StatusCode != http.StatusBadRequest {
-		t.Fatalf("expected status 400, got %d", resp.StatusCode)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
 	}
 }
 
 // ------------------------------------------------------------
-// USECASE-LEVEL VALIDATION ERRORS -> 400
+// SUCCESS: mode=histogram
 // ------------------------------------------------------------
 
-func TestGetMetrics_UsecaseValidationErrors(t *testing.T) {
-	tests := []struct {
-		name    string
-		ucError error
-	}{
-		{"invalid_query", usecase.ErrInvalidMetricsQuery},
-		{"invalid_time_range", usecase.ErrInvalidTimeRange},
-		{"invalid_group_by", usecase.ErrInvalidGroupBy},
-		{"invalid_interval", usecase.ErrInvalidInterval},
+func TestGetMetrics_Success_Histogram(t *testing.T) {
+	lower, upper := 0.0, 50.0
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			if in.Mode != "histogram" {
+				t.Fatalf("expected mode=histogram, got %s", in.Mode)
+			}
+			if in.Histogram == nil || in.Histogram.Min != 0 || in.Histogram.Max != 100 || in.Histogram.Count != 2 {
+				t.Fatalf("unexpected histogram spec: %+v", in.Histogram)
+			}
+			return &domain.AggregatedMetrics{
+				EventName:  in.EventName,
+				TotalCount: 5,
+				Mode:       "histogram",
+				Histogram: []domain.HistogramBucket{
+					{Bucket: 1, Lower: &lower, Upper: &upper, Count: 5},
+				},
+			}, nil
+		},
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			uc := &fakeGetMetricsUseCase{
-				ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
-					return nil, tt.ucError
-				},
-			}
+	app := setupApp(t, uc)
 
-			app := setupApp(t, uc)
+	params := url.Values{}
+	params.Set("event_name", "order_placed")
+	params.Set("from", "100")
+	params.Set("to", "200")
+	params.Set("mode", "histogram")
+	params.Set("bucket_min", "0")
+	params.Set("bucket_max", "100")
+	params.Set("bucket_count", "2")
 
-			params := url.Values{}
-			params.Set("event_name", "product_view")
-			params.Set("from", "100")
-			params.Set("to", "200")
+	req := httptest.NewRequest(http.MethodGet, "/metrics?"+params.Encode(), nil)
 
-			req := httptest.NewRequest(http.MethodGet, "/metrics?"+params.Encode(), nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
 
-			resp, err := app.Test(req)
-			if err != nil {
-				t.Fatalf("app.Test error: %v", err)
+	var body httpadapter.MetricsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if len(body.Histogram) != 1 || body.Histogram[0].Count != 5 || *body.Histogram[0].Upper != 50 {
+		t.Fatalf("unexpected histogram: %+v", body.Histogram)
+	}
+}
+
+func TestGetMetrics_HistogramBucketsOnField(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			h := in.Histogram
+			if in.Mode != "histogram" || in.Field != "" || h == nil || h.Field != "metadata.amount" ||
+				len(h.Bounds) != 5 || h.Bounds[4] != 500 {
+				t.Fatalf("unexpected input: %+v %+v", in, h)
 			}
-			if resp.StatusCode != http.StatusBadRequest {
-				t.Fatalf("expected status 400, got %d", resp.StatusCode)
+			return &domain.AggregatedMetrics{EventName: in.EventName, Mode: "histogram"}, nil
+		},
+	}
+
+	app := setupApp(t, uc)
+
+	params := url.Values{}
+	params.Set("event_name", "purchase")
+	params.Set("from", "100")
+	params.Set("to", "200")
+	params.Set("field", "metadata.amount")
+	params.Set("buckets", "0,10,50,100,500")
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics?"+params.Encode(), nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	for _, bad := range []string{"buckets=0,ten", "buckets=0,10&bucket_count=2"} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics?event_name=purchase&from=100&to=200&"+bad, nil))
+		if err != nil {
+			t.Fatalf("app.Test error: %v", err)
+		}
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d", bad, resp.StatusCode)
+		}
+	}
+}
+
+func TestGetMetrics_HistogramMissingParams(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{}
+	app := setupApp(t, uc)
+
+	params := url.Values{}
+	params.Set("event_name", "order_placed")
+	params.Set("from", "100")
+	params.Set("to", "200")
+	params.Set("mode", "histogram")
+	params.Set("bucket_min", "0")
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics?"+params.Encode(), nil)
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", resp.StatusCode)
+	}
+	if uc.called {
+		t.Fatalf("usecase should not be called on missing histogram params")
+	}
+}
+
+// ------------------------------------------------------------
+// SUCCESS: mode=lag
+// ------------------------------------------------------------
+
+func TestGetMetrics_Success_Lag(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			if in.Mode != "lag" || in.GroupBy != "channel" {
+				t.Fatalf("unexpected input: %+v", in)
 			}
-		})
+			return &domain.AggregatedMetrics{
+				EventName:  in.EventName,
+				TotalCount: 10,
+				GroupBy:    "channel",
+				Mode:       "lag",
+				Lag:        &domain.LagStats{AvgSeconds: 7.2, P50Seconds: 1, P95Seconds: 50, P99Seconds: 58, MaxSeconds: 60},
+				Groups: []domain.MetricsGroup{
+					{Key: "ios", TotalCount: 2, Lag: &domain.LagStats{P95Seconds: 55}},
+				},
+			}, nil
+		},
+	}
+
+	app := setupApp(t, uc)
+
+	params := url.Values{}
+	params.Set("event_name", "order_placed")
+	params.Set("from", "100")
+	params.Set("to", "200")
+	params.Set("group_by", "channel")
+	params.Set("mode", "lag")
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics?"+params.Encode(), nil)
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	var body httpadapter.MetricsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if body.Lag == nil || body.Lag.P95Seconds != 50 || body.Lag.MaxSeconds != 60 {
+		t.Fatalf("unexpected lag: %+v", body.Lag)
+	}
+	if len(body.Groups) != 1 || body.Groups[0].Lag == nil || body.Groups[0].Lag.P95Seconds != 55 {
+		t.Fatalf("unexpected groups: %+v", body.Groups)
 	}
 }
 
 // ------------------------------------------------------------
-// USECASE OTHER ERROR -> 500
+// SUCCESS: mode=conversion
 // ------------------------------------------------------------
 
-func TestGetMetrics_InternalError(t *testing.T) {
+func TestGetMetrics_Success_Conversion(t *testing.T) {
+	rate, hourRate := 0.05, 0.1
 	uc := &fakeGetMetricsUseCase{
 		ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
-			return nil, context.DeadlineExceeded // herhangi bir 5xx kabul edilebilir hata
+			if in.Mode != "conversion" || in.Denominator != "product_view" || in.EventName != "purchase" {
+				t.Fatalf("unexpected input: %+v", in)
+			}
+			return &domain.AggregatedMetrics{
+				EventName:  in.EventName,
+				TotalCount: 3,
+				GroupBy:    "time",
+				Mode:       "conversion",
+				Conversion: &domain.Conversion{Denominator: "product_view", TotalCount: 60, Rate: &rate},
+				Groups: []domain.MetricsGroup{
+					{Key: "2025-12-07T10:00:00Z", TotalCount: 2, Conversion: &domain.Conversion{TotalCount: 20, Rate: &hourRate}},
+				},
+			}, nil
 		},
 	}
 
 	app := setupApp(t, uc)
 
 	params := url.Values{}
-	params.Set("event_name", "product_view")
+	params.Set("event_name", "purchase")
+	params.Set("denominator", "product_view")
 	params.Set("from", "100")
 	params.Set("to", "200")
+	params.Set("group_by", "time")
+	params.Set("interval", "hour")
+	params.Set("mode", "conversion")
 
 	req := httptest.NewRequest(http.MethodGet, "/metrics?"+params.Encode(), nil)
 
@@ -273,7 +556,855 @@ func TestGetMetrics_InternalError(t *testing.T) {
 	if err != nil {
 		t.Fatalf("app.Test error: %v", err)
 	}
-	if resp.StatusCode != http.StatusInternalServerError {
-		t.Fatalf("expected status 500, got %d", resp.StatusCode)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	var body httpadapter.MetricsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if c := body.Conversion; c == nil || c.Denominator != "product_view" || c.TotalCount != 60 || *c.Rate != 0.05 || c.UserRate != nil {
+		t.Fatalf("unexpected conversion: %+v", body.Conversion)
+	}
+	if len(body.Groups) != 1 || body.Groups[0].Conversion == nil || *body.Groups[0].Conversion.Rate != 0.1 {
+		t.Fatalf("unexpected groups: %+v", body.Groups)
+	}
+}
+
+func TestGetMetrics_QueryRangeTooLarge(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			return nil, fmt.Errorf("%w: 8760h0m0s exceeds the maximum of 2160h0m0s", usecase.ErrQueryRangeTooLarge)
+		},
+	}
+	app := setupApp(t, uc)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics?event_name=product_view&from=100&to=200", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", resp.StatusCode)
+	}
+	var body httpadapter.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if body.Error != "query_range_too_large" || !strings.Contains(body.Message, "2160h0m0s") {
+		t.Fatalf("unexpected error: %+v", body)
+	}
+}
+
+func TestGetMetrics_QueryTooExpensive(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			return nil, fmt.Errorf("%w: estimated cost 1843022 exceeds 500000", domain.ErrQueryTooExpensive)
+		},
+	}
+	app := setupApp(t, uc)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics?event_name=product_view&from=100&to=200&group_by=channel", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", resp.StatusCode)
+	}
+	var body httpadapter.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if body.Error != "query_too_expensive" || !strings.Contains(body.Message, "narrow your range") {
+		t.Fatalf("unexpected error: %+v", body)
+	}
+}
+
+func TestGetMetrics_QueryTimeout(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			return nil, fmt.Errorf("%w after 30s", usecase.ErrQueryTimeout)
+		},
+	}
+	app := setupApp(t, uc)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics?event_name=product_view&from=100&to=200", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusGatewayTimeout {
+		t.Fatalf("expected status 504, got %d", resp.StatusCode)
+	}
+	var body httpadapter.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if body.Error != "query_timeout" {
+		t.Fatalf("unexpected error: %+v", body)
+	}
+}
+
+// ------------------------------------------------------------
+// INVALID QUERY PARAM (bad int)
+// ------------------------------------------------------------
+
+func TestGetMetrics_InvalidQueryParam(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			t.Fatalf("usecase should not be called on invalid query params")
+			return nil, nil
+		},
+	}
+
+	app := setupApp(t, uc)
+
+	params := url.Values{}
+	params.Set("event_name", "product_view")
+	params.Set("from", "abc") // invalid
+	params.Set("to", "200")
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics?"+params.Encode(), nil)
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestGetMetrics_RepeatedEventName(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{EventName: in.EventName}, nil
+		},
+	}
+
+	app := setupApp(t, uc)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics?event_name=signup_web,signup_ios&event_name=signup_android&from=100&to=200", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if uc.lastInput.EventName != "signup_web,signup_ios,signup_android" {
+		t.Fatalf("unexpected event_name: %q", uc.lastInput.EventName)
+	}
+}
+
+func strPtr(s string) *string { return &s }
+
+func TestGetMetrics_CampaignFilter(t *testing.T) {
+	tests := []struct {
+		query string
+		want  *string
+	}{
+		{"", nil},
+		{"&campaign_id=spring", strPtr("spring")},
+		{"&campaign_id=", strPtr("")},
+	}
+
+	for _, tt := range tests {
+		uc := &fakeGetMetricsUseCase{
+			ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+				return &domain.AggregatedMetrics{EventName: in.EventName}, nil
+			},
+		}
+		app := setupApp(t, uc)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics?event_name=purchase&from=100&to=200"+tt.query, nil))
+		if err != nil {
+			t.Fatalf("app.Test error: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%q: expected status 200, got %d", tt.query, resp.StatusCode)
+		}
+		got := uc.lastInput.CampaignID
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Fatalf("%q: unexpected campaign_id %v", tt.query, got)
+		}
+	}
+}
+
+func TestGetMetrics_UserFilter(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{EventName: in.EventName}, nil
+		},
+	}
+	app := setupApp(t, uc)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics?event_name=purchase&from=100&to=200&user_id=u1&group_by=time&interval=day", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if uc.lastInput.UserID != "u1" || uc.lastInput.GroupBy != "time" {
+		t.Fatalf("unexpected input: %+v", uc.lastInput)
+	}
+}
+
+func TestGetMetrics_CountsOnly(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{EventName: in.EventName}, nil
+		},
+	}
+	app := setupApp(t, uc)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics?event_name=purchase&from=0&to=3599&counts_only=true", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if !uc.lastInput.CountsOnly {
+		t.Fatalf("unexpected input: %+v", uc.lastInput)
+	}
+}
+
+func TestGetMetrics_GroupPage(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{
+				EventName:  in.EventName,
+				GroupBy:    in.GroupBy,
+				Groups:     []domain.MetricsGroup{{Key: "p3"}, {Key: "p4"}},
+				NextOffset: in.Offset + in.Limit,
+			}, nil
+		},
+	}
+	app := setupApp(t, uc)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics?event_name=product_view&from=100&to=200&group_by=metadata.product_id&limit=2&offset=2", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if uc.lastInput.Limit != 2 || uc.lastInput.Offset != 2 {
+		t.Fatalf("unexpected input: %+v", uc.lastInput)
+	}
+
+	var body httpadapter.MetricsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.NextOffset != 4 || len(body.Groups) != 2 {
+		t.Fatalf("unexpected response: %+v", body)
+	}
+
+	for _, q := range []string{"limit=ten", "offset=x"} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics?event_name=product_view&from=100&to=200&group_by=channel&"+q, nil))
+		if err != nil {
+			t.Fatalf("app.Test error: %v", err)
+		}
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d", q, resp.StatusCode)
+		}
+	}
+}
+
+func TestGetMetrics_GroupOrder(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{EventName: in.EventName}, nil
+		},
+	}
+	app := setupApp(t, uc)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics?event_name=purchase&from=100&to=200&group_by=channel&order_by=total_count&order=desc", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if uc.lastInput.OrderBy != "total_count" || uc.lastInput.Order != "desc" {
+		t.Fatalf("unexpected input: %+v", uc.lastInput)
+	}
+}
+
+func TestGetMetrics_Tags(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{EventName: in.EventName}, nil
+		},
+	}
+
+	app := setupApp(t, uc)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics?event_name=purchase&from=100&to=200&tags=promo,beta&tag_match=any", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if len(uc.lastInput.Tags) != 2 || uc.lastInput.Tags[1] != "beta" || uc.lastInput.TagMatch != "any" {
+		t.Fatalf("unexpected input: %+v", uc.lastInput)
+	}
+}
+
+// ------------------------------------------------------------
+// USECASE-LEVEL VALIDATION ERRORS -> 400
+// ------------------------------------------------------------
+
+func TestGetMetrics_UsecaseValidationErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		ucError error
+	}{
+		{"invalid_query", usecase.ErrInvalidMetricsQuery},
+		{"invalid_time_range", usecase.ErrInvalidTimeRange},
+		{"invalid_group_by", usecase.ErrInvalidGroupBy},
+		{"invalid_interval", usecase.ErrInvalidInterval},
+		{"invalid_mode", usecase.ErrInvalidMode},
+		{"invalid_histogram", usecase.ErrInvalidHistogram},
+		{"invalid_metadata", usecase.ErrInvalidMetadata},
+		{"invalid_tags", usecase.ErrInvalidTags},
+		{"invalid_aggregate", usecase.ErrInvalidAggregate},
+		{"invalid_top", usecase.ErrInvalidTop},
+		{"invalid_compare", usecase.ErrInvalidCompare},
+		{"invalid_page", usecase.ErrInvalidPage},
+		{"invalid_order", usecase.ErrInvalidOrder},
+		{"invalid_stream", usecase.ErrInvalidStream},
+		{"watermark_disabled", usecase.ErrWatermarkDisabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &fakeGetMetricsUseCase{
+				ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+					return nil, tt.ucError
+				},
+			}
+
+			app := setupApp(t, uc)
+
+			params := url.Values{}
+			params.Set("event_name", "product_view")
+			params.Set("from", "100")
+			params.Set("to", "200")
+
+			req := httptest.NewRequest(http.MethodGet, "/metrics?"+params.Encode(), nil)
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test error: %v", err)
+			}
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d", resp.StatusCode)
+			}
+		})
+	}
+}
+
+// ------------------------------------------------------------
+// USECASE OTHER ERROR -> 500
+// ------------------------------------------------------------
+
+func TestGetMetrics_InternalError(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			return nil, context.DeadlineExceeded // herhangi bir 5xx kabul edilebilir hata
+		},
+	}
+
+	app := setupApp(t, uc)
+
+	params := url.Values{}
+	params.Set("event_name", "product_view")
+	params.Set("from", "100")
+	params.Set("to", "200")
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics?"+params.Encode(), nil)
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", resp.StatusCode)
+	}
+}
+
+// ------------------------------------------------------------
+// AS OF
+// ------------------------------------------------------------
+
+func TestGetMetrics_AsOf(t *testing.T) {
+	asOf := time.Date(2025, 12, 7, 10, 0, 0, 123000000, time.UTC)
+
+	tests := []struct {
+		name       string
+		value      string
+		wantStatus int
+		wantLatest bool
+		wantAsOf   *time.Time
+	}{
+		{"explicit", "2025-12-07T10:00:00.123Z", http.StatusOK, false, &asOf},
+		{"latest", "latest", http.StatusOK, true, nil},
+		{"invalid", "yesterday", http.StatusBadRequest, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &fakeGetMetricsUseCase{
+				ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+					return &domain.AggregatedMetrics{EventName: in.EventName, AsOf: &asOf}, nil
+				},
+			}
+
+			app := setupApp(t, uc)
+
+			params := url.Values{}
+			params.Set("event_name", "product_view")
+			params.Set("from", "100")
+			params.Set("to", "200")
+			params.Set("as_of", tt.value)
+
+			req := httptest.NewRequest(http.MethodGet, "/metrics?"+params.Encode(), nil)
+
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test error: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, resp.StatusCode)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if uc.called {
+					t.Fatalf("usecase must not be called for invalid as_of")
+				}
+				return
+			}
+			if uc.lastInput.AsOfLatest != tt.wantLatest {
+				t.Fatalf("expected AsOfLatest=%v, got %v", tt.wantLatest, uc.lastInput.AsOfLatest)
+			}
+			if (tt.wantAsOf == nil) != (uc.lastInput.AsOf == nil) ||
+				(tt.wantAsOf != nil && !uc.lastInput.AsOf.Equal(*tt.wantAsOf)) {
+				t.Fatalf("expected AsOf=%v, got %v", tt.wantAsOf, uc.lastInput.AsOf)
+			}
+
+			var body httpadapter.MetricsResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("decode error: %v", err)
+			}
+			if body.AsOf == nil || !body.AsOf.Equal(asOf) {
+				t.Fatalf("expected as_of in response, got %v", body.AsOf)
+			}
+		})
+	}
+}
+
+// ------------------------------------------------------------
+// TOP
+// ------------------------------------------------------------
+
+func TestGetTopValues_Success(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{
+				EventName:   in.EventName,
+				GroupBy:     in.GroupBy,
+				TotalCount:  500,
+				UniqueUsers: 120,
+				Groups: []domain.MetricsGroup{
+					{Key: "c2", TotalCount: 90, UniqueUsers: 30},
+					{Key: "c1", TotalCount: 40, UniqueUsers: 12},
+				},
+			}, nil
+		},
+	}
+
+	app := setupApp(t, uc)
+
+	params := url.Values{}
+	params.Set("event_name", "purchase")
+	params.Set("from", "100")
+	params.Set("to", "200")
+	params.Set("dimension", "campaign_id")
+	params.Set("n", "2")
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics/top?"+params.Encode(), nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if uc.lastInput.GroupBy != "campaign_id" || uc.lastInput.Top != 2 || uc.lastInput.Mode != "" {
+		t.Fatalf("unexpected input: %+v", uc.lastInput)
+	}
+
+	var body httpadapter.MetricsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.TotalCount != 500 || len(body.Groups) != 2 || body.Groups[0].Key != "c2" {
+		t.Fatalf("unexpected response: %+v", body)
+	}
+}
+
+func TestGetMetrics_Compare(t *testing.T) {
+	pct := func(v float64) *float64 { return &v }
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			change := domain.CompareFigures(domain.MetricsGroup{TotalCount: 40, UniqueUsers: 10}, domain.MetricsGroup{TotalCount: 50, UniqueUsers: 10})
+			return &domain.AggregatedMetrics{
+				EventName:   in.EventName,
+				GroupBy:     "channel",
+				TotalCount:  50,
+				UniqueUsers: 10,
+				Groups:      []domain.MetricsGroup{{Key: "web", TotalCount: 50, UniqueUsers: 10, Change: &change}},
+				Comparison: &domain.Comparison{
+					Compare: in.Compare,
+					Previous: &domain.AggregatedMetrics{
+						From: 0, To: 99, TotalCount: 40, UniqueUsers: 10,
+						Groups: []domain.MetricsGroup{{Key: "web", TotalCount: 40, UniqueUsers: 10}},
+					},
+					Change: domain.Changes{
+						TotalCount:  domain.Change{Delta: 10, Percent: pct(25)},
+						UniqueUsers: domain.Change{Percent: pct(0)},
+					},
+				},
+			}, nil
+		},
+	}
+
+	app := setupApp(t, uc)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics?event_name=purchase&from=100&to=199&group_by=channel&compare=previous_period", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if uc.lastInput.Compare != "previous_period" {
+		t.Fatalf("unexpected input: %+v", uc.lastInput)
+	}
+
+	var body httpadapter.MetricsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	cmp := body.Comparison
+	if cmp == nil || cmp.Compare != "previous_period" || cmp.To != 99 || cmp.TotalCount != 40 || len(cmp.Groups) != 1 {
+		t.Fatalf("unexpected comparison: %+v", cmp)
+	}
+	if cmp.Change.TotalCount.Delta != 10 || *cmp.Change.TotalCount.Percent != 25 {
+		t.Fatalf("unexpected change: %+v", cmp.Change)
+	}
+	if c := body.Groups[0].Change; c == nil || *c.TotalCount.Percent != 25 {
+		t.Fatalf("unexpected group change: %+v", c)
+	}
+}
+
+func TestGetTopValues_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"missing dimension", "event_name=purchase&from=100&to=200"},
+		{"zero n", "event_name=purchase&from=100&to=200&dimension=user_id&n=0"},
+		{"non-numeric n", "event_name=purchase&from=100&to=200&dimension=user_id&n=ten"},
+		{"missing event_name", "from=100&to=200&dimension=user_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &fakeGetMetricsUseCase{}
+			app := setupApp(t, uc)
+
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics/top?"+tt.query, nil))
+			if err != nil {
+				t.Fatalf("app.Test error: %v", err)
+			}
+			if resp.StatusCode != http.StatusBadRequest {
+				t.Fatalf("expected status 400, got %d", resp.StatusCode)
+			}
+			if uc.called {
+				t.Fatalf("usecase should not be called")
+			}
+		})
+	}
+}
+
+func TestGetTopValues_DefaultN(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{}, nil
+		},
+	}
+	app := setupApp(t, uc)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics/top?event_name=purchase&from=100&to=200&dimension=user_id", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || uc.lastInput.Top != 10 {
+		t.Fatalf("expected 200 with n=10, got %d (%+v)", resp.StatusCode, uc.lastInput)
+	}
+}
+
+// ------------------------------------------------------------
+// BATCH
+// ------------------------------------------------------------
+
+func TestGetMetricsBatch_Success(t *testing.T) {
+	asOf := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+
+	uc := &fakeGetMetricsUseCase{
+		ExecuteBatchFn: func(ctx context.Context, in usecase.BatchGetMetricsInput) (*usecase.BatchMetricsResult, error) {
+			if in.AsOf != nil {
+				t.Fatalf("expected no explicit as_of, got %v", in.AsOf)
+			}
+			if len(in.Queries) != 2 {
+				t.Fatalf("expected 2 queries, got %d", len(in.Queries))
+			}
+			q := in.Queries[1]
+			if q.Channel == nil || *q.Channel != "web" || q.Histogram == nil || q.Histogram.Count != 4 {
+				t.Fatalf("unexpected second query: %+v", q)
+			}
+
+			res := &usecase.BatchMetricsResult{AsOf: asOf}
+			for _, q := range in.Queries {
+				res.Results = append(res.Results, &domain.AggregatedMetrics{EventName: q.EventName, AsOf: &asOf, TotalCount: 7})
+			}
+			return res, nil
+		},
+	}
+
+	app := setupApp(t, uc)
+
+	body := `{"queries":[
+		{"event_name":"product_view","from":100,"to":200,"group_by":"channel"},
+		{"event_name":"purchase","from":100,"to":200,"channel":"web","mode":"histogram","bucket_min":0,"bucket_max":100,"bucket_count":4}
+	]}`
+	req := httptest.NewRequest(http.MethodPost, "/metrics/batch", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	var out httpadapter.BatchMetricsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if !out.AsOf.Equal(asOf) || len(out.Results) != 2 {
+		t.Fatalf("unexpected response: %+v", out)
+	}
+	if out.Results[1].EventName != "purchase" || out.Results[1].TotalCount != 7 {
+		t.Fatalf("unexpected second result: %+v", out.Results[1])
+	}
+}
+
+func TestGetMetricsBatch_InvalidBatch(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{
+		ExecuteBatchFn: func(ctx context.Context, in usecase.BatchGetMetricsInput) (*usecase.BatchMetricsResult, error) {
+			return nil, usecase.ErrInvalidBatch
+		},
+	}
+
+	app := setupApp(t, uc)
+
+	req := httptest.NewRequest(http.MethodPost, "/metrics/batch", strings.NewReader(`{"queries":[]}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", resp.StatusCode)
+	}
+}
+
+// ------------------------------------------------------------
+// STREAMING
+// ------------------------------------------------------------
+
+func TestGetMetrics_StreamNDJSON(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{
+		StreamFn: func(ctx context.Context, in usecase.GetMetricsInput, head func(*domain.AggregatedMetrics) error, emit func([]domain.MetricsGroup) error) error {
+			if err := head(&domain.AggregatedMetrics{EventName: in.EventName, GroupBy: in.GroupBy, TotalCount: 30, UniqueUsers: 4}); err != nil {
+				return err
+			}
+			if err := emit([]domain.MetricsGroup{{Key: "android", TotalCount: 10}, {Key: "ios", TotalCount: 5}}); err != nil {
+				return err
+			}
+			return emit([]domain.MetricsGroup{{Key: "web", TotalCount: 15}})
+		},
+	}
+	app := setupApp(t, uc)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics?event_name=purchase&from=100&to=200&group_by=channel", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+
+	sc := bufio.NewScanner(resp.Body)
+	if !sc.Scan() {
+		t.Fatalf("expected a head line")
+	}
+	var head httpadapter.MetricsResponse
+	if err := json.Unmarshal(sc.Bytes(), &head); err != nil {
+		t.Fatalf("invalid head line %q: %v", sc.Text(), err)
+	}
+	if head.TotalCount != 30 || head.GroupBy != "channel" || len(head.Groups) != 0 {
+		t.Fatalf("unexpected head: %+v", head)
+	}
+	var keys []string
+	for sc.Scan() {
+		var g httpadapter.MetricsGroupResponse
+		if err := json.Unmarshal(sc.Bytes(), &g); err != nil {
+			t.Fatalf("invalid group line %q: %v", sc.Text(), err)
+		}
+		keys = append(keys, g.Key)
+	}
+	if strings.Join(keys, ",") != "android,ios,web" {
+		t.Fatalf("unexpected groups: %v", keys)
+	}
+}
+
+func TestGetMetrics_StreamFailureEndsWithError(t *testing.T) {
+	for streamErr, want := range map[error]string{
+		errors.New("connection reset"):                      "stream_failed",
+		fmt.Errorf("%w after 30s", usecase.ErrQueryTimeout): "query_timeout",
+	} {
+		uc := &fakeGetMetricsUseCase{
+			StreamFn: func(ctx context.Context, in usecase.GetMetricsInput, head func(*domain.AggregatedMetrics) error, emit func([]domain.MetricsGroup) error) error {
+				if err := head(&domain.AggregatedMetrics{EventName: in.EventName}); err != nil {
+					return err
+				}
+				return streamErr
+			},
+		}
+		app := setupApp(t, uc)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/metrics?event_name=purchase&from=100&to=200&group_by=channel&format=ndjson", nil))
+		if err != nil {
+			t.Fatalf("app.Test error: %v", err)
+		}
+
+		var last string
+		sc := bufio.NewScanner(resp.Body)
+		for sc.Scan() {
+			last = sc.Text()
+		}
+		var e httpadapter.ErrorResponse
+		if err := json.Unmarshal([]byte(last), &e); err != nil || e.Error != want {
+			t.Fatalf("%v: expected a %s line last, got %q", streamErr, want, last)
+		}
+	}
+}
+
+func TestGetMetrics_StreamBadRequests(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{ValidateErr: usecase.ErrInvalidStream}
+	app := setupApp(t, uc)
+
+	for _, target := range []string{
+		"/metrics?event_name=purchase&from=100&to=200&group_by=channel&format=ndjson&limit=10",
+		"/metrics?event_name=purchase&from=100&to=200&group_by=channel&format=xml",
+	} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, target, nil))
+		if err != nil {
+			t.Fatalf("app.Test error: %v", err)
+		}
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d", target, resp.StatusCode)
+		}
+	}
+	if uc.called {
+		t.Fatalf("usecase should not run for rejected streams")
+	}
+}
+
+// ------------------------------------------------------------
+// TENANT: the request's user context reaches the reader
+// ------------------------------------------------------------
+
+type tenantKey struct{}
+
+type recordingReader struct {
+	tenants []*string
+}
+
+func (r *recordingReader) QueryMetrics(_ context.Context, f ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+	r.tenants = append(r.tenants, f.Tenant)
+	return &domain.AggregatedMetrics{EventName: f.EventName}, nil
+}
+
+func TestMetricsHandlers_TenantReachesReader(t *testing.T) {
+	reader := &recordingReader{}
+	uc := usecase.NewGetMetricsUseCase(reader, usecase.WithTenantResolver(func(ctx context.Context) string {
+		tenant, _ := ctx.Value(tenantKey{}).(string)
+		return tenant
+	}))
+
+	app := fiber.New()
+	// Like authHttp.RequireTenant, the tenant of the key only lives in the
+	// user context.
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(context.WithValue(c.UserContext(), tenantKey{}, c.Get("X-Tenant")))
+		return c.Next()
+	})
+	h := httpadapter.NewMetricsHandler(uc)
+	app.Get("/metrics", h.GetMetrics)
+	app.Get("/metrics/top", h.GetTopValues)
+	app.Post("/metrics/batch", h.GetMetricsBatch)
+	app.Post("/metrics/query", httpadapter.NewQueryHandler(usecase.NewQueryMetricsUseCase(uc)).QueryMetrics)
+
+	for _, tc := range []struct {
+		method, target, body string
+	}{
+		{http.MethodGet, "/metrics?event_name=purchase&from=100&to=200", ""},
+		{http.MethodGet, "/metrics/top?event_name=purchase&from=100&to=200&dimension=channel", ""},
+		{http.MethodPost, "/metrics/batch", `{"as_of":"2025-12-07T10:00:00Z","queries":[{"event_name":"purchase","from":100,"to":200}]}`},
+		{http.MethodPost, "/metrics/query", `{"event_names":["purchase"],"from":100,"to":200}`},
+	} {
+		reader.tenants = nil
+		req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Tenant", "acme")
+
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("%s: app.Test error: %v", tc.target, err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d", tc.target, resp.StatusCode)
+		}
+		if len(reader.tenants) == 0 {
+			t.Fatalf("%s: expected the reader to be called", tc.target)
+		}
+		for _, tenant := range reader.tenants {
+			if tenant == nil || *tenant != "acme" {
+				t.Fatalf("%s: expected tenant acme, got %v", tc.target, tenant)
+			}
+		}
 	}
 }
@@ -0,0 +1,67 @@
+package fiber
+
+import (
+	"context"
+	"net/http"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type QueryMetricsUseCase interface {
+	Execute(ctx context.Context, in usecase.QueryMetricsInput) (*domain.AggregatedMetrics, error)
+}
+
+type QueryHandler struct {
+	uc QueryMetricsUseCase
+}
+
+func NewQueryHandler(uc QueryMetricsUseCase) *QueryHandler {
+	return &QueryHandler{uc: uc}
+}
+
+// QueryMetrics godoc
+// @Summary Structured metrics query
+// @Description Runs a metrics query described by a JSON body instead of query parameters: filters, a list of group_by dimensions
+// @Description (interval on the time one) and up to 10 aggregations. The response is that of GET /metrics; aggregations holds
+// @Description every aggregation and each group their values in the same order, the first is also aggregate, field and value.
+// @Description With several aggregations order_by value is refused, and comparisons cover the first aggregation only.
+// @Tags Metrics
+// @Accept json
+// @Produce json
+// @Param request body StructuredQueryRequest true "Query"
+// @Param Authorization header string false "Bearer JWT from OIDC_ISSUER, required when it is set"
+// @Success 200 {object} MetricsResponse
+// @Failure 400 {object} ErrorResponse "invalid query, query_range_too_large: the range or its time buckets exceed the configured maximum, or query_too_expensive: the planner estimates a grouped query above METRICS_MAX_QUERY_COST or METRICS_MAX_QUERY_ROWS"
+// @Failure 401 {object} ErrorResponse "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read"
+// @Failure 429 {object} ErrorResponse "query_concurrency_limited: too many concurrent queries for this API key"
+// @Failure 500 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse "query_timeout: the query ran longer than METRICS_QUERY_TIMEOUT"
+// @Router /metrics/query [post]
+func (h *QueryHandler) QueryMetrics(c *fiber.Ctx) error {
+	var req StructuredQueryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error:   "invalid_body",
+			Message: "Request body could not be parsed",
+		})
+	}
+
+	in, errMsg := req.toInput()
+	if errMsg != "" {
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error:   "invalid_body",
+			Message: errMsg,
+		})
+	}
+
+	res, err := h.uc.Execute(c.UserContext(), in)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.Status(http.StatusOK).JSON(toMetricsResponse(res))
+}
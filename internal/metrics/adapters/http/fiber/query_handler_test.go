@@ -0,0 +1,133 @@
+package fiber_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpadapter "event-metrics-service/internal/metrics/adapters/http/fiber"
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeQueryMetricsUseCase struct {
+	ExecuteFn func(in usecase.QueryMetricsInput) (*domain.AggregatedMetrics, error)
+	lastInput usecase.QueryMetricsInput
+	called    bool
+}
+
+func (f *fakeQueryMetricsUseCase) Execute(_ context.Context, in usecase.QueryMetricsInput) (*domain.AggregatedMetrics, error) {
+	f.called = true
+	f.lastInput = in
+	return f.ExecuteFn(in)
+}
+
+func postQuery(t *testing.T, uc httpadapter.QueryMetricsUseCase, body string) *http.Response {
+	t.Helper()
+	app := fiber.New()
+	app.Post("/metrics/query", httpadapter.NewQueryHandler(uc).QueryMetrics)
+
+	req := httptest.NewRequest(http.MethodPost, "/metrics/query", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	return resp
+}
+
+func TestQueryMetrics_Success(t *testing.T) {
+	sum, p95 := 120.0, 30.0
+	uc := &fakeQueryMetricsUseCase{
+		ExecuteFn: func(in usecase.QueryMetricsInput) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{
+				EventName: in.EventName, TotalCount: 3, GroupBy: in.GroupBy,
+				Aggregate: domain.AggregateSum, Field: "metadata.amount", Value: &sum,
+				Aggregations: []domain.Aggregation{
+					{Aggregate: domain.AggregateSum, Field: "metadata.amount", Value: &sum},
+					{Aggregate: domain.AggregateP95, Field: "metadata.amount", Value: &p95},
+				},
+				Groups: []domain.MetricsGroup{
+					{Key: "web|2025-12-07T10:00:00Z", Keys: []string{"web", "2025-12-07T10:00:00Z"}, TotalCount: 3, Value: &sum, Values: []*float64{&sum, nil}},
+				},
+			}, nil
+		},
+	}
+
+	resp := postQuery(t, uc, `{
+		"event_names": ["purchase", "refund"],
+		"from": 100, "to": 200,
+		"filters": {"channel": "web", "campaign_id": "", "metadata": {"plan": "pro"}, "tags": ["vip"], "tag_match": "any"},
+		"group_by": [{"dimension": "channel"}, {"dimension": "time", "interval": "hour"}],
+		"aggregations": [{"function": "sum", "field": "metadata.amount"}, {"function": "p95", "field": "metadata.amount"}],
+		"order_by": "total_count", "limit": 50,
+		"options": {"counts_only": true, "as_of": "latest"}
+	}`)
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, body)
+	}
+
+	in := uc.lastInput
+	if in.EventName != "purchase,refund" || in.From != 100 || in.To != 200 || in.GroupBy != "channel,time" || in.Interval != "hour" {
+		t.Fatalf("unexpected input: %+v", in)
+	}
+	if *in.Channel != "web" || *in.CampaignID != "" || in.Metadata["plan"] != "pro" || in.TagMatch != "any" || len(in.Tags) != 1 {
+		t.Fatalf("unexpected filters: %+v", in)
+	}
+	if len(in.Aggregations) != 2 || in.Aggregations[1] != (usecase.AggregationSpec{Aggregate: "p95", Field: "metadata.amount"}) {
+		t.Fatalf("unexpected aggregations: %+v", in.Aggregations)
+	}
+	if in.OrderBy != "total_count" || in.Limit != 50 || !in.CountsOnly || !in.AsOfLatest {
+		t.Fatalf("unexpected options: %+v", in)
+	}
+
+	var out httpadapter.MetricsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if len(out.Aggregations) != 2 || out.Aggregations[1].Function != "p95" || *out.Aggregations[1].Value != 30 {
+		t.Fatalf("unexpected aggregations: %+v", out.Aggregations)
+	}
+	if g := out.Groups[0]; len(g.Values) != 2 || *g.Values[0] != 120 || g.Values[1] != nil || g.Keys["time"] != "2025-12-07T10:00:00Z" {
+		t.Fatalf("unexpected group: %+v", g)
+	}
+}
+
+func TestQueryMetrics_InvalidBody(t *testing.T) {
+	cases := map[string]string{
+		`{"from":`:             "could not be parsed",
+		`{"from": 1, "to": 2}`: "event_names is required",
+		`{"event_names": ["a"], "group_by": [{"dimension": "channel", "interval": "hour"}]}`: "interval is only for the time dimension",
+		`{"event_names": ["a"], "options": {"as_of": "yesterday"}}`:                          "invalid 'as_of' option",
+	}
+	for body, want := range cases {
+		uc := &fakeQueryMetricsUseCase{}
+		resp := postQuery(t, uc, body)
+		out, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != http.StatusBadRequest || !strings.Contains(string(out), want) {
+			t.Fatalf("%s: expected 400 %q, got %d %s", body, want, resp.StatusCode, out)
+		}
+		if uc.called {
+			t.Fatalf("%s: usecase should not be called", body)
+		}
+	}
+}
+
+func TestQueryMetrics_UsecaseValidationError(t *testing.T) {
+	uc := &fakeQueryMetricsUseCase{
+		ExecuteFn: func(in usecase.QueryMetricsInput) (*domain.AggregatedMetrics, error) {
+			return nil, usecase.ErrInvalidOrder
+		},
+	}
+	resp := postQuery(t, uc, `{"event_names": ["a"], "from": 1, "to": 2, "order_by": "value"}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+}
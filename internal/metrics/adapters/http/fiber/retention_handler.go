@@ -0,0 +1,101 @@
+package fiber
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type GetRetentionUseCase interface {
+	Execute(ctx context.Context, in usecase.GetRetentionInput) (*domain.Retention, error)
+}
+
+type RetentionHandler struct {
+	uc GetRetentionUseCase
+}
+
+func NewRetentionHandler(uc GetRetentionUseCase) *RetentionHandler {
+	return &RetentionHandler{uc: uc}
+}
+
+// GetRetention godoc
+// @Summary Cohort retention
+// @Description Cohorts users by the day or week of their first start_event in the range and reports, for each later bucket, how many of them did return_event in it.
+// @Description Buckets starting after to are left out, so later cohorts have fewer periods (a retention triangle).
+// @Tags Metrics
+// @Produce json
+// @Param start_event query string true "Event that puts a user in a cohort"
+// @Param return_event query string true "Event that counts as coming back"
+// @Param from query int true "From timestamp"
+// @Param to query int true "To timestamp"
+// @Param interval query string false "Bucket: day | week (default week, starting Monday)"
+// @Param periods query int false "Buckets to report after the cohort bucket, 1 to 90 (default 8)"
+// @Param Authorization header string false "Bearer JWT from OIDC_ISSUER, required when it is set"
+// @Success 200 {object} RetentionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read"
+// @Failure 429 {object} ErrorResponse "query_concurrency_limited: too many concurrent queries for this API key"
+// @Failure 500 {object} ErrorResponse
+// @Router /metrics/retention [get]
+func (h *RetentionHandler) GetRetention(c *fiber.Ctx) error {
+	from, err := strconv.ParseInt(c.Query("from", ""), 10, 64)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid 'from' parameter",
+		})
+	}
+	to, err := strconv.ParseInt(c.Query("to", ""), 10, 64)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid 'to' parameter",
+		})
+	}
+
+	in := usecase.GetRetentionInput{
+		StartEvent:  c.Query("start_event", ""),
+		ReturnEvent: c.Query("return_event", ""),
+		From:        from,
+		To:          to,
+		Interval:    c.Query("interval", domain.RetentionWeek),
+	}
+	if periods := c.Query("periods", ""); periods != "" {
+		n, err := strconv.Atoi(periods)
+		if err != nil || n < 1 {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid 'periods' parameter",
+			})
+		}
+		in.Periods = n
+	}
+
+	res, err := h.uc.Execute(c.UserContext(), in)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	resp := RetentionResponse{
+		StartEvent:  res.StartEvent,
+		ReturnEvent: res.ReturnEvent,
+		From:        res.From,
+		To:          res.To,
+		Interval:    res.Interval,
+		Periods:     res.Periods,
+		Cohorts:     make([]RetentionCohortResponse, 0, len(res.Cohorts)),
+	}
+	for _, co := range res.Cohorts {
+		resp.Cohorts = append(resp.Cohorts, RetentionCohortResponse{
+			Start:    co.Start,
+			Users:    co.Users,
+			Returned: co.Returned,
+			Rates:    co.Rates(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
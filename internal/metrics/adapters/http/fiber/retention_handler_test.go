@@ -0,0 +1,89 @@
+package fiber_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpadapter "event-metrics-service/internal/metrics/adapters/http/fiber"
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeGetRetentionUseCase struct {
+	ExecuteFn func(in usecase.GetRetentionInput) (*domain.Retention, error)
+	lastInput usecase.GetRetentionInput
+	called    bool
+}
+
+func (f *fakeGetRetentionUseCase) Execute(_ context.Context, in usecase.GetRetentionInput) (*domain.Retention, error) {
+	f.called = true
+	f.lastInput = in
+	return f.ExecuteFn(in)
+}
+
+func getRetention(t *testing.T, uc httpadapter.GetRetentionUseCase, path string) *http.Response {
+	t.Helper()
+	app := fiber.New()
+	app.Get("/metrics/retention", httpadapter.NewRetentionHandler(uc).GetRetention)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	return resp
+}
+
+func TestGetRetention_Success(t *testing.T) {
+	start := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	uc := &fakeGetRetentionUseCase{
+		ExecuteFn: func(in usecase.GetRetentionInput) (*domain.Retention, error) {
+			return &domain.Retention{
+				StartEvent:  in.StartEvent,
+				ReturnEvent: in.ReturnEvent,
+				Interval:    in.Interval,
+				Periods:     in.Periods,
+				Cohorts:     []domain.RetentionCohort{{Start: start, Users: 4, Returned: []int64{3, 1}}},
+			}, nil
+		},
+	}
+
+	resp := getRetention(t, uc, "/metrics/retention?start_event=signup&return_event=login&from=100&to=200&periods=2")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	// Weekly cohorts unless asked otherwise.
+	if uc.lastInput.Interval != "week" || uc.lastInput.Periods != 2 || uc.lastInput.From != 100 {
+		t.Fatalf("unexpected input: %+v", uc.lastInput)
+	}
+
+	var body httpadapter.RetentionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(body.Cohorts) != 1 || body.Cohorts[0].Users != 4 || body.Cohorts[0].Rates[0] != 0.75 || body.Cohorts[0].Rates[1] != 0.25 {
+		t.Fatalf("unexpected response: %+v", body)
+	}
+}
+
+func TestGetRetention_BadRequest(t *testing.T) {
+	for name, path := range map[string]string{
+		"missing from":  "/metrics/retention?start_event=signup&return_event=login&to=200",
+		"zero periods":  "/metrics/retention?start_event=signup&return_event=login&from=100&to=200&periods=0",
+		"usecase error": "/metrics/retention?return_event=login&from=100&to=200",
+	} {
+		uc := &fakeGetRetentionUseCase{
+			ExecuteFn: func(in usecase.GetRetentionInput) (*domain.Retention, error) {
+				return nil, usecase.ErrInvalidRetention
+			},
+		}
+		if resp := getRetention(t, uc, path); resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", name, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package fiber
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// DefaultMaxStreams is the number of metrics streams open at once
+	// without WithMaxStreams.
+	DefaultMaxStreams = 10
+
+	defaultStreamEvery = 10 * time.Second
+	maxStreamEvery     = time.Minute
+	defaultStreamRange = time.Hour
+)
+
+type StreamMetricsUseCase interface {
+	Execute(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error)
+}
+
+// StreamHandler pushes the result of a metrics query over a rolling window
+// as Server-Sent Events, querying again every few seconds.
+type StreamHandler struct {
+	uc         StreamMetricsUseCase
+	maxStreams int
+	now        func() time.Time
+
+	mu      sync.Mutex
+	streams int
+	closed  bool
+	done    chan struct{}
+}
+
+type StreamOption func(*StreamHandler)
+
+// WithMaxStreams allows up to n streams at once; n <= 0 means
+// DefaultMaxStreams.
+func WithMaxStreams(n int) StreamOption {
+	return func(h *StreamHandler) {
+		if n > 0 {
+			h.maxStreams = n
+		}
+	}
+}
+
+func NewStreamHandler(uc StreamMetricsUseCase, opts ...StreamOption) *StreamHandler {
+	h := &StreamHandler{
+		uc:         uc,
+		maxStreams: DefaultMaxStreams,
+		now:        time.Now,
+		done:       make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// StreamMetrics godoc
+// @Summary Live metrics stream
+// @Description Streams the result of a metrics query over the last range as Server-Sent Events, for wallboards that
+// @Description would otherwise poll GET /metrics. A "metrics" event carrying a MetricsResponse is sent at once and then
+// @Description every `every` seconds, with from and to moved to the last range; the range starts on a whole minute. A
+// @Description query that fails midway sends an "error" event with an ErrorResponse and is retried at the next tick.
+// @Description Filters and grouping are those of GET /metrics; paging, format, mode and as_of are not supported.
+// @Tags Metrics
+// @Produce text/event-stream
+// @Param event_name query string true "Event name; several comma-separated or repeated names are counted together"
+// @Param range query string false "Rolling window ending now, as a Go duration (default 1h)"
+// @Param every query int false "Seconds between pushes, 1 to 60 (default 10)"
+// @Param group_by query string false "Group by: channel | campaign_id | user_id | time | hour_of_day | day_of_week | metadata.<key>, or a comma-separated combination"
+// @Param interval query string false "Interval: minute | hour | day | week (starting Monday) | month"
+// @Param aggregate query string false "Aggregate of field per group: sum | avg | min | max | p50 | p90 | p95 | p99"
+// @Param field query string false "Numeric metadata field to aggregate: metadata.<key>"
+// @Param channel query string false "Only events of this channel"
+// @Param campaign_id query string false "Only events of this campaign; empty (campaign_id=) for events without a campaign"
+// @Param user_id query string false "Only the events of this user, including anonymous events stitched to them"
+// @Param tags query string false "Comma separated tags; only events carrying all of them (or any, with tag_match=any)"
+// @Param tag_match query string false "How tags match: all (default) | any"
+// @Param compare query string false "Also query the comparison window and return changes: previous_period | previous_year"
+// @Param order_by query string false "Order groups by: key (default) | total_count | unique_users | value (with aggregate)"
+// @Param order query string false "Order: asc | desc (default asc by key, desc otherwise)"
+// @Param limit query int false "Groups sent, 1 to 10000 (default 10000)"
+// @Param counts_only query bool false "Skip unique users (reported as 0)"
+// @Param Authorization header string false "Bearer JWT from OIDC_ISSUER, required when it is set"
+// @Success 200 {string} string "event stream"
+// @Failure 400 {object} ErrorResponse "invalid query, or query_range_too_large: the range or its time buckets exceed the configured maximum"
+// @Failure 401 {object} ErrorResponse "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read"
+// @Failure 429 {object} ErrorResponse "query_concurrency_limited: too many concurrent queries for this API key"
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse "too_many_streams: the maximum number of metrics streams is open"
+// @Router /metrics/stream [get]
+func (h *StreamHandler) StreamMetrics(c *fiber.Ctx) error {
+	eventName := parseEventNames(c)
+	if eventName == "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "event_name is required",
+		})
+	}
+	for _, p := range []string{"from", "to", "offset", "format", "mode", "as_of"} {
+		if c.Context().QueryArgs().Has(p) {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+				"error": "'" + p + "' is not supported by streams",
+			})
+		}
+	}
+
+	window := defaultStreamRange
+	if v := c.Query("range", ""); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < time.Minute {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid 'range' parameter",
+			})
+		}
+		window = d
+	}
+	every := defaultStreamEvery
+	if v := c.Query("every", ""); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || time.Duration(n)*time.Second > maxStreamEvery {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid 'every' parameter",
+			})
+		}
+		every = time.Duration(n) * time.Second
+	}
+
+	in, errMsg := parseMetricsFilters(c, eventName)
+	if errMsg != "" {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": errMsg,
+		})
+	}
+	in.GroupBy = c.Query("group_by", "")
+	in.Interval = c.Query("interval", "")
+	in.OrderBy = c.Query("order_by", "")
+	in.Order = c.Query("order", "")
+	if v := c.Query("limit", ""); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+				"error": "invalid 'limit' parameter",
+			})
+		}
+		in.Limit = n
+	}
+
+	if !h.open() {
+		return c.Status(http.StatusServiceUnavailable).JSON(ErrorResponse{
+			Error:   "too_many_streams",
+			Message: "the maximum number of metrics streams is open; retry later",
+		})
+	}
+
+	// The first query runs before the status line, so a bad one is refused
+	// like on GET /metrics.
+	ctx := c.UserContext()
+	query := func() (*domain.AggregatedMetrics, error) {
+		return h.uc.Execute(ctx, h.window(in, window))
+	}
+	res, err := query()
+	if err != nil {
+		h.release()
+		return writeError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	// Stops nginx from buffering the stream.
+	c.Set("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer h.release()
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+
+		writeSSE(w, "metrics", toMetricsResponse(res))
+		for {
+			// A failed flush means the client is gone.
+			if err := w.Flush(); err != nil {
+				return
+			}
+			select {
+			case <-h.done:
+				return
+			case <-ticker.C:
+			}
+			res, err := query()
+			if err != nil {
+				writeSSE(w, "error", ErrorResponse{
+					Error:   "query_failed",
+					Message: "the query failed; it is retried at the next tick",
+				})
+				continue
+			}
+			writeSSE(w, "metrics", toMetricsResponse(res))
+		}
+	})
+	return nil
+}
+
+// window sets the time range of in to the last d, from the start of its
+// first minute to now.
+func (h *StreamHandler) window(in usecase.GetMetricsInput, d time.Duration) usecase.GetMetricsInput {
+	now := h.now()
+	in.From = now.Add(-d).Truncate(time.Minute).Unix()
+	in.To = now.Unix()
+	return in
+}
+
+func (h *StreamHandler) open() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed || h.streams >= h.maxStreams {
+		return false
+	}
+	h.streams++
+	return true
+}
+
+func (h *StreamHandler) release() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.streams--
+}
+
+// Close ends every stream and refuses new ones, e.g. on shutdown.
+func (h *StreamHandler) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.closed {
+		h.closed = true
+		close(h.done)
+	}
+}
+
+func writeSSE(w *bufio.Writer, event string, v any) {
+	data, _ := json.Marshal(v)
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+}
@@ -0,0 +1,114 @@
+package fiber_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpadapter "event-metrics-service/internal/metrics/adapters/http/fiber"
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func getStream(t *testing.T, h *httpadapter.StreamHandler, path string) (*http.Response, string) {
+	t.Helper()
+	app := fiber.New()
+	app.Get("/metrics/stream", h.StreamMetrics)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil), -1)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp, string(body)
+}
+
+func TestStreamMetrics_PushesTheRollingWindow(t *testing.T) {
+	var h *httpadapter.StreamHandler
+	var got usecase.GetMetricsInput
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(_ context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			got = in
+			// Ends the stream after its first push.
+			h.Close()
+			return &domain.AggregatedMetrics{EventName: in.EventName, From: in.From, To: in.To, TotalCount: 42}, nil
+		},
+	}
+	h = httpadapter.NewStreamHandler(uc)
+
+	before := time.Now().Unix()
+	resp, body := getStream(t, h, "/metrics/stream?event_name=signup&range=15m&every=5&group_by=channel&counts_only=true")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, body)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	if got.GroupBy != "channel" || !got.CountsOnly || got.To < before || got.To-got.From < 15*60 || got.To-got.From >= 16*60 || got.From%60 != 0 {
+		t.Fatalf("unexpected input: %+v", got)
+	}
+	if n := strings.Count(body, "event: metrics\n"); n != 1 || !strings.Contains(body, `"total_count":42`) {
+		t.Fatalf("expected one metrics event, got %q", body)
+	}
+}
+
+func TestStreamMetrics_InvalidParams(t *testing.T) {
+	cases := map[string]string{
+		"/metrics/stream":                             "event_name is required",
+		"/metrics/stream?event_name=a&from=1&to=2":    "'from' is not supported by streams",
+		"/metrics/stream?event_name=a&range=tomorrow": "invalid 'range' parameter",
+		"/metrics/stream?event_name=a&range=30s":      "invalid 'range' parameter",
+		"/metrics/stream?event_name=a&every=0":        "invalid 'every' parameter",
+		"/metrics/stream?event_name=a&every=61":       "invalid 'every' parameter",
+		"/metrics/stream?event_name=a&limit=ten":      "invalid 'limit' parameter",
+		"/metrics/stream?event_name=a&format=ndjson":  "'format' is not supported by streams",
+		"/metrics/stream?event_name=a&as_of=latest":   "'as_of' is not supported by streams",
+	}
+	for path, want := range cases {
+		uc := &fakeGetMetricsUseCase{}
+		resp, body := getStream(t, httpadapter.NewStreamHandler(uc), path)
+		if resp.StatusCode != http.StatusBadRequest || !strings.Contains(body, want) {
+			t.Fatalf("%s: expected 400 %q, got %d %s", path, want, resp.StatusCode, body)
+		}
+		if uc.called {
+			t.Fatalf("%s: usecase should not be called", path)
+		}
+	}
+}
+
+func TestStreamMetrics_RefusesABadQuery(t *testing.T) {
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(context.Context, usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			return nil, usecase.ErrInvalidGroupBy
+		},
+	}
+	h := httpadapter.NewStreamHandler(uc, httpadapter.WithMaxStreams(1))
+
+	// The refused stream gives its slot back.
+	for i := 0; i < 2; i++ {
+		resp, body := getStream(t, h, "/metrics/stream?event_name=a&group_by=nope")
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", resp.StatusCode, body)
+		}
+	}
+}
+
+func TestStreamMetrics_Closed(t *testing.T) {
+	h := httpadapter.NewStreamHandler(&fakeGetMetricsUseCase{ExecuteFn: func(context.Context, usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+		return nil, errors.New("unexpected query")
+	}})
+	h.Close()
+
+	resp, body := getStream(t, h, "/metrics/stream?event_name=a")
+	if resp.StatusCode != http.StatusServiceUnavailable || !strings.Contains(body, "too_many_streams") {
+		t.Fatalf("expected 503, got %d: %s", resp.StatusCode, body)
+	}
+}
@@ -0,0 +1,127 @@
+package live
+
+import (
+	"sync"
+	"time"
+)
+
+// Counters counts the events this instance stored in the last minutes, per
+// minute of event time, tenant, event name and channel. Events older than
+// the window are not counted.
+type Counters struct {
+	window  time.Duration
+	now     func() time.Time
+	started time.Time
+
+	mu      sync.Mutex
+	minutes map[time.Time]map[counterKey]float64
+}
+
+type counterKey struct {
+	tenant    string
+	eventName string
+	channel   string
+}
+
+type CountersOption func(*Counters)
+
+// WithClock replaces time.Now, for tests.
+func WithClock(now func() time.Time) CountersOption {
+	return func(c *Counters) {
+		c.now = now
+	}
+}
+
+// NewCounters keeps the minutes of the last window, rounded up to whole
+// minutes.
+func NewCounters(window time.Duration, opts ...CountersOption) *Counters {
+	c := &Counters{
+		window:  ((window + time.Minute - 1) / time.Minute) * time.Minute,
+		now:     time.Now,
+		minutes: map[time.Time]map[counterKey]float64{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.started = c.now().UTC()
+	return c
+}
+
+// Add counts weight events at time at.
+func (c *Counters) Add(tenant, eventName, channel string, at time.Time, weight float64) {
+	minute := at.UTC().Truncate(time.Minute)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cut := c.cut()
+	c.prune(cut)
+	if minute.Before(cut) {
+		return
+	}
+	counts, ok := c.minutes[minute]
+	if !ok {
+		counts = map[counterKey]float64{}
+		c.minutes[minute] = counts
+	}
+	counts[counterKey{tenant, eventName, channel}] += weight
+}
+
+// Since returns the first minute the counters hold every event of: the
+// start of the window, or the first whole minute after they were created.
+func (c *Counters) Since() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.since()
+}
+
+func (c *Counters) since() time.Time {
+	cut := c.cut()
+	first := c.started.Truncate(time.Minute)
+	if first.Before(c.started) {
+		first = first.Add(time.Minute)
+	}
+	if first.After(cut) {
+		return first
+	}
+	return cut
+}
+
+// count is one counter of a snapshot.
+type count struct {
+	minute    time.Time
+	tenant    string
+	eventName string
+	channel   string
+	weight    float64
+}
+
+// snapshot returns the counters of the minutes in [from, until).
+func (c *Counters) snapshot(from, until time.Time) []count {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.prune(c.cut())
+
+	var out []count
+	for minute, counts := range c.minutes {
+		if minute.Before(from) || !minute.Before(until) {
+			continue
+		}
+		for k, w := range counts {
+			out = append(out, count{minute: minute, tenant: k.tenant, eventName: k.eventName, channel: k.channel, weight: w})
+		}
+	}
+	return out
+}
+
+// cut is the first minute of the window.
+func (c *Counters) cut() time.Time {
+	return c.now().UTC().Truncate(time.Minute).Add(-c.window)
+}
+
+func (c *Counters) prune(cut time.Time) {
+	for minute := range c.minutes {
+		if minute.Before(cut) {
+			delete(c.minutes, minute)
+		}
+	}
+}
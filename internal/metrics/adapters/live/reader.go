@@ -0,0 +1,171 @@
+package live
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/ports"
+)
+
+// MetricsReader answers the minutes of eligible queries within the window
+// of the counters from them, and the rest of the window from next. Results
+// of next may lag behind ingestion (cache, ClickHouse copy); the counters
+// do not.
+type MetricsReader struct {
+	next     ports.MetricsReaderPort
+	counters *Counters
+}
+
+var _ ports.MetricsReaderPort = (*MetricsReader)(nil)
+
+func NewMetricsReader(next ports.MetricsReaderPort, counters *Counters) *MetricsReader {
+	return &MetricsReader{next: next, counters: counters}
+}
+
+// eligible reports whether the counters answer f: a counts-only query of
+// the first page of groups in key order, filtered and grouped only by name,
+// tenant, channel and time, like the event counters.
+func eligible(f ports.MetricsFilter) bool {
+	if !f.CountsOnly || f.Mode != domain.ModeCount || f.Aggregate != "" || f.CampaignID != nil || f.UserID != "" ||
+		len(f.Metadata) > 0 || len(f.Tags) > 0 || f.AsOf != nil || f.Top > 0 || f.Offset > 0 {
+		return false
+	}
+	if f.OrderBy != "" && f.OrderBy != domain.OrderByKey {
+		return false
+	}
+	for _, dim := range domain.GroupByDimensions(f.GroupBy) {
+		if dim != domain.DimensionChannel && dim != "time" {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *MetricsReader) QueryMetrics(ctx context.Context, f ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+	if !eligible(f) {
+		return r.next.QueryMetrics(ctx, f)
+	}
+
+	// Minutes are counted whole: the live part has to start on a minute and
+	// end after the last second of one, or reach the present.
+	liveFrom := time.Unix(f.From, 0).UTC()
+	if since := r.counters.Since(); liveFrom.Before(since) {
+		liveFrom = since
+	}
+	to := time.Unix(f.To, 0).UTC()
+	if to.Before(liveFrom) || !liveFrom.Truncate(time.Minute).Equal(liveFrom) ||
+		((f.To+1)%60 != 0 && to.Before(r.counters.now())) {
+		return r.next.QueryMetrics(ctx, f)
+	}
+
+	res := &domain.AggregatedMetrics{EventName: f.EventName, GroupBy: f.GroupBy}
+	if f.From < liveFrom.Unix() {
+		before := f
+		before.To = liveFrom.Unix() - 1
+		earlier, err := r.next.QueryMetrics(ctx, before)
+		if err != nil {
+			return nil, err
+		}
+		// Live groups may belong to later pages.
+		if earlier.NextOffset != 0 {
+			return r.next.QueryMetrics(ctx, f)
+		}
+		copied := *earlier
+		copied.Groups = slices.Clone(earlier.Groups)
+		res = &copied
+	}
+	res.From, res.To = f.From, f.To
+
+	merge(res, f, r.counters.snapshot(liveFrom, to.Truncate(time.Minute).Add(time.Minute)))
+	return res, nil
+}
+
+// merge adds the matching counts to the totals and groups of res, and
+// orders and pages the groups again.
+func merge(res *domain.AggregatedMetrics, f ports.MetricsFilter, counts []count) {
+	names := domain.EventNames(f.EventName)
+	dims := domain.GroupByDimensions(f.GroupBy)
+
+	var total float64
+	byKey := map[string]float64{}
+	keys := map[string][]string{}
+	for _, c := range counts {
+		if !slices.Contains(names, c.eventName) ||
+			(f.Channel != nil && *f.Channel != c.channel) ||
+			(f.Tenant != nil && *f.Tenant != c.tenant) {
+			continue
+		}
+		total += c.weight
+		if len(dims) == 0 {
+			continue
+		}
+		parts := make([]string, len(dims))
+		for i, dim := range dims {
+			if dim == domain.DimensionChannel {
+				parts[i] = c.channel
+			} else {
+				parts[i] = bucketStart(c.minute, f.Interval).Format(time.RFC3339)
+			}
+		}
+		key := strings.Join(parts, domain.CompositeKeySeparator)
+		byKey[key] += c.weight
+		keys[key] = parts
+	}
+	res.TotalCount += int64(math.Round(total))
+	if len(byKey) == 0 {
+		return
+	}
+
+	index := make(map[string]int, len(res.Groups))
+	for i, g := range res.Groups {
+		index[g.Key] = i
+	}
+	for key, w := range byKey {
+		n := int64(math.Round(w))
+		if i, ok := index[key]; ok {
+			res.Groups[i].TotalCount += n
+			continue
+		}
+		g := domain.MetricsGroup{Key: key, TotalCount: n}
+		if len(dims) > 1 {
+			g.Keys = keys[key]
+		}
+		res.Groups = append(res.Groups, g)
+	}
+
+	slices.SortFunc(res.Groups, func(a, b domain.MetricsGroup) int {
+		c := strings.Compare(a.Key, b.Key)
+		if len(dims) > 1 {
+			c = slices.Compare(a.Keys, b.Keys)
+		}
+		if f.Order == domain.OrderDesc {
+			return -c
+		}
+		return c
+	})
+	if f.Limit > 0 && len(res.Groups) > f.Limit {
+		res.Groups = res.Groups[:f.Limit]
+		res.NextOffset = f.Limit
+	}
+}
+
+// bucketStart truncates a minute to the start of its time group, in UTC
+// like the time groups of queries; weeks start on Monday.
+func bucketStart(t time.Time, interval string) time.Time {
+	switch interval {
+	case "hour":
+		return t.Truncate(time.Hour)
+	case "day":
+		return t.Truncate(24 * time.Hour)
+	case "week":
+		day := t.Truncate(24 * time.Hour)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return t
+}
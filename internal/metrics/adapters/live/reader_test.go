@@ -0,0 +1,197 @@
+package live
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/ports"
+)
+
+type fakeReader struct {
+	QueryFn func(f ports.MetricsFilter) *domain.AggregatedMetrics
+	filters []ports.MetricsFilter
+}
+
+func (r *fakeReader) QueryMetrics(_ context.Context, f ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+	r.filters = append(r.filters, f)
+	return r.QueryFn(f), nil
+}
+
+// clock starts at 10:00 on December 8th 2025; advance moves it.
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newClock() *clock {
+	return &clock{t: time.Date(2025, 12, 8, 10, 0, 0, 0, time.UTC)}
+}
+
+func TestCounters_KeepTheWindow(t *testing.T) {
+	c := newClock()
+	counters := NewCounters(150*time.Second, WithClock(c.now))
+
+	// Rounded up to 3 minutes, and nothing before the counters started.
+	if got := counters.Since(); !got.Equal(c.t) {
+		t.Fatalf("expected since %s, got %s", c.t, got)
+	}
+	c.advance(10 * time.Minute)
+	if got := counters.Since(); !got.Equal(c.t.Add(-3 * time.Minute)) {
+		t.Fatalf("expected since the window start, got %s", got)
+	}
+
+	counters.Add("", "signup", "web", c.t.Add(-4*time.Minute), 1) // before the window
+	counters.Add("", "signup", "web", c.t.Add(-2*time.Minute), 1)
+	c.advance(time.Minute)
+	counters.Add("", "signup", "web", c.t, 2)
+
+	got := counters.snapshot(time.Unix(0, 0), c.t.Add(time.Hour))
+	if len(got) != 2 {
+		t.Fatalf("expected two minutes, got %+v", got)
+	}
+	c.advance(time.Minute)
+	if got := counters.snapshot(time.Unix(0, 0), c.t.Add(time.Hour)); len(got) != 1 || got[0].weight != 2 {
+		t.Fatalf("expected the oldest minute pruned, got %+v", got)
+	}
+}
+
+func TestMetricsReader_MergesTheLiveMinutes(t *testing.T) {
+	c := newClock()
+	counters := NewCounters(5*time.Minute, WithClock(c.now))
+	c.advance(time.Hour + 30*time.Second) // 11:00:30
+
+	for _, e := range []struct {
+		tenant, name, channel string
+		at                    time.Time
+		weight                float64
+	}{
+		{"acme", "signup", "web", c.t.Add(-3 * time.Minute), 1}, // 10:57:30
+		{"acme", "signup", "ios", c.t.Add(-time.Minute), 2},     // 10:59:30
+		{"acme", "signup", "web", c.t, 1},                       // 11:00:30
+		{"acme", "login", "web", c.t, 1},
+		{"other", "signup", "web", c.t, 1},
+	} {
+		counters.Add(e.tenant, e.name, e.channel, e.at, e.weight)
+	}
+
+	next := &fakeReader{QueryFn: func(f ports.MetricsFilter) *domain.AggregatedMetrics {
+		return &domain.AggregatedMetrics{
+			EventName: f.EventName, From: f.From, To: f.To, TotalCount: 100,
+			Groups: []domain.MetricsGroup{
+				{Key: "2025-12-08T09:00:00Z", TotalCount: 60},
+				{Key: "2025-12-08T10:00:00Z", TotalCount: 40},
+			},
+		}
+	}}
+	tenant := "acme"
+	from := time.Date(2025, 12, 8, 9, 0, 0, 0, time.UTC)
+	res, err := NewMetricsReader(next, counters).QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName:  "signup",
+		From:       from.Unix(),
+		To:         c.t.Add(time.Hour).Unix(),
+		GroupBy:    "time",
+		Interval:   "hour",
+		CountsOnly: true,
+		Tenant:     &tenant,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cut := time.Date(2025, 12, 8, 10, 55, 0, 0, time.UTC)
+	if len(next.filters) != 1 || next.filters[0].To != cut.Unix()-1 || next.filters[0].From != from.Unix() {
+		t.Fatalf("expected events before %s from next, got %+v", cut, next.filters)
+	}
+	if res.TotalCount != 104 || res.To != c.t.Add(time.Hour).Unix() {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	want := []domain.MetricsGroup{
+		{Key: "2025-12-08T09:00:00Z", TotalCount: 60},
+		{Key: "2025-12-08T10:00:00Z", TotalCount: 43},
+		{Key: "2025-12-08T11:00:00Z", TotalCount: 1},
+	}
+	if len(res.Groups) != len(want) {
+		t.Fatalf("unexpected groups: %+v", res.Groups)
+	}
+	for i, g := range want {
+		if res.Groups[i].Key != g.Key || res.Groups[i].TotalCount != g.TotalCount {
+			t.Fatalf("group %d: expected %+v, got %+v", i, g, res.Groups[i])
+		}
+	}
+}
+
+func TestMetricsReader_ChannelsWithinTheWindow(t *testing.T) {
+	c := newClock()
+	counters := NewCounters(5*time.Minute, WithClock(c.now))
+	c.advance(10 * time.Minute)
+	counters.Add("", "signup", "web", c.t, 1)
+	counters.Add("", "signup", "ios", c.t, 1)
+	counters.Add("", "signup", "android", c.t, 1)
+
+	next := &fakeReader{}
+	res, err := NewMetricsReader(next, counters).QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName:  "signup",
+		From:       c.t.Add(-time.Minute).Unix(),
+		To:         c.t.Add(time.Minute - time.Second).Unix(),
+		GroupBy:    "channel",
+		Order:      domain.OrderDesc,
+		Limit:      2,
+		CountsOnly: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(next.filters) != 0 {
+		t.Fatalf("expected the counters alone, got %+v", next.filters)
+	}
+	if res.TotalCount != 3 || len(res.Groups) != 2 || res.Groups[0].Key != "web" || res.Groups[1].Key != "ios" || res.NextOffset != 2 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestMetricsReader_PassesThrough(t *testing.T) {
+	c := newClock()
+	counters := NewCounters(5*time.Minute, WithClock(c.now))
+	c.advance(10 * time.Minute)
+	counters.Add("", "signup", "web", c.t, 1)
+
+	from := c.t.Add(-time.Hour).Unix()
+	to := c.t.Add(time.Hour).Unix()
+	for name, f := range map[string]ports.MetricsFilter{
+		"unique users":   {EventName: "signup", From: from, To: to},
+		"metadata":       {EventName: "signup", From: from, To: to, CountsOnly: true, Metadata: map[string]string{"plan": "pro"}},
+		"ordered counts": {EventName: "signup", From: from, To: to, CountsOnly: true, GroupBy: "channel", OrderBy: domain.OrderByTotalCount},
+		"past window":    {EventName: "signup", From: from, To: c.t.Add(-10 * time.Minute).Unix(), CountsOnly: true},
+		"partial minute": {EventName: "signup", From: from, To: c.t.Add(-30 * time.Second).Unix(), CountsOnly: true},
+	} {
+		next := &fakeReader{QueryFn: func(f ports.MetricsFilter) *domain.AggregatedMetrics {
+			return &domain.AggregatedMetrics{TotalCount: 7}
+		}}
+		res, err := NewMetricsReader(next, counters).QueryMetrics(context.Background(), f)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if len(next.filters) != 1 || next.filters[0].To != f.To || res.TotalCount != 7 {
+			t.Fatalf("%s: expected the query passed through, got %+v %+v", name, next.filters, res)
+		}
+	}
+}
+
+func TestMetricsReader_MoreGroupsThanAPage(t *testing.T) {
+	c := newClock()
+	counters := NewCounters(5*time.Minute, WithClock(c.now))
+	c.advance(10 * time.Minute)
+
+	next := &fakeReader{QueryFn: func(f ports.MetricsFilter) *domain.AggregatedMetrics {
+		return &domain.AggregatedMetrics{TotalCount: 7, NextOffset: 1, Groups: []domain.MetricsGroup{{Key: "web"}}}
+	}}
+	f := ports.MetricsFilter{EventName: "signup", From: c.t.Add(-time.Hour).Unix(), To: c.t.Unix(), GroupBy: "channel", Limit: 1, CountsOnly: true}
+	if _, err := NewMetricsReader(next, counters).QueryMetrics(context.Background(), f); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(next.filters) != 2 || next.filters[1].To != f.To {
+		t.Fatalf("expected the whole query from next, got %+v", next.filters)
+	}
+}
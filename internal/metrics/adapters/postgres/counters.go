@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/ports"
+)
+
+// countersSource reads event_counters (migration 021) with the columns of
+// events that eligible queries use. Counters stand at the start of their
+// hour and weigh the events they count, like rollup rows.
+const countersSource = `(
+    SELECT event_name, hour AS event_time, tenant_id, channel, events AS weight
+    FROM event_counters
+) events`
+
+// countersEligible reports whether f is answered exactly by the counters:
+// a counts-only query of whole hours, from the start of one to the last
+// second of another, filtered and grouped only by name, tenant, channel and
+// time.
+func countersEligible(f ports.MetricsFilter) bool {
+	if !f.CountsOnly || f.Mode != domain.ModeCount || f.Aggregate != "" || f.CampaignID != nil || f.UserID != "" ||
+		len(f.Metadata) > 0 || len(f.Tags) > 0 || f.AsOf != nil {
+		return false
+	}
+	if f.From%3600 != 0 || (f.To+1)%3600 != 0 {
+		return false
+	}
+	for _, dim := range domain.GroupByDimensions(f.GroupBy) {
+		switch dim {
+		case domain.DimensionChannel:
+		case "time":
+			if f.Interval == "minute" {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
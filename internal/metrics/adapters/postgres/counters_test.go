@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/ports"
+)
+
+func TestMetricsRepository_RoutesToCounters(t *testing.T) {
+	var queries []string
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			queries = append(queries, query)
+			if rows, ok := overallRows(query, 200, 0); ok {
+				return rows, nil
+			}
+			return &fakeRowScanner{rows: []fakeRow{{values: []any{"web", int64(200), int64(0)}}}}, nil
+		},
+	}
+	repo := NewMetricsRepository(db, WithCounters(), WithRollups(rolledUp))
+
+	res, err := repo.QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName:  "purchase",
+		From:       time.Date(2025, 12, 3, 10, 0, 0, 0, time.UTC).Unix(),
+		To:         time.Date(2025, 12, 3, 17, 59, 59, 0, time.UTC).Unix(),
+		GroupBy:    "channel",
+		CountsOnly: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.TotalCount != 200 || len(res.Groups) != 1 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	for _, q := range queries {
+		for _, want := range []string{"FROM event_counters\n) events", "ROUND(SUM(weight))::bigint", "COUNT(DISTINCT NULL)"} {
+			if !strings.Contains(q, want) {
+				t.Fatalf("query missing %q: %s", want, q)
+			}
+		}
+		if strings.Contains(q, "event_rollups") {
+			t.Fatalf("expected counters rather than rollups: %s", q)
+		}
+	}
+
+	// Unique users need the events.
+	if _, err := repo.QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "purchase",
+		From:      time.Date(2025, 12, 3, 10, 0, 0, 0, time.UTC).Unix(),
+		To:        time.Date(2025, 12, 3, 17, 59, 59, 0, time.UTC).Unix(),
+		GroupBy:   "channel",
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(db.lastQuery, "event_counters") || strings.Contains(db.lastQuery, "DISTINCT NULL") {
+		t.Fatalf("expected unique users from the events: %s", db.lastQuery)
+	}
+}
+
+func TestMetricsRepository_CountersOnlyForEligibleQueries(t *testing.T) {
+	from := time.Date(2025, 12, 3, 0, 0, 0, 0, time.UTC).Unix()
+	to := time.Date(2025, 12, 4, 0, 0, 0, 0, time.UTC).Unix() - 1
+	campaign := ""
+
+	tests := []struct {
+		name   string
+		filter ports.MetricsFilter
+		gate   ColumnGate
+		want   bool
+	}{
+		{"no group", ports.MetricsFilter{}, nil, true},
+		{"channel and daily time", ports.MetricsFilter{GroupBy: "channel,time", Interval: "day"}, nil, true},
+		{"partial hour", ports.MetricsFilter{From: from + 60}, nil, false},
+		{"hour end", ports.MetricsFilter{To: to + 1}, nil, false},
+		{"minutely time", ports.MetricsFilter{GroupBy: "time", Interval: "minute"}, nil, false},
+		{"campaign group", ports.MetricsFilter{GroupBy: "campaign_id"}, nil, false},
+		{"campaign filter", ports.MetricsFilter{CampaignID: &campaign}, nil, false},
+		{"user filter", ports.MetricsFilter{UserID: "u1"}, nil, false},
+		{"tags", ports.MetricsFilter{Tags: []string{"vip"}}, nil, false},
+		{"gated", ports.MetricsFilter{}, fakeColumnGate{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDB{
+				QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+					return &fakeRowScanner{}, nil
+				},
+			}
+			opts := []RepositoryOption{WithCounters()}
+			if tt.gate != nil {
+				opts = append(opts, WithColumnGate(tt.gate))
+			}
+
+			f := tt.filter
+			f.EventName = "purchase"
+			f.CountsOnly = true
+			if f.From == 0 {
+				f.From = from
+			}
+			if f.To == 0 {
+				f.To = to
+			}
+			if _, err := NewMetricsRepository(db, opts...).QueryMetrics(context.Background(), f); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := strings.Contains(db.lastQuery, "event_counters"); got != tt.want {
+				t.Fatalf("expected counters=%v: %s", tt.want, db.lastQuery)
+			}
+			if !strings.Contains(db.lastQuery, "COUNT(DISTINCT NULL)") {
+				t.Fatalf("expected unique users to be skipped: %s", db.lastQuery)
+			}
+		})
+	}
+}
@@ -2,11 +2,19 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"event-metrics-service/internal/metrics/core/domain"
 	"event-metrics-service/internal/metrics/core/ports"
+
+	"github.com/lib/pq"
 )
 
 type RowScanner interface {
@@ -18,23 +26,412 @@ type RowScanner interface {
 
 type DB interface {
 	QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error)
+	BeginTx(ctx context.Context) (Tx, error)
+}
+
+// Tx is a transaction, which server-side cursors live in.
+type Tx interface {
+	QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error)
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	Commit() error
+	Rollback() error
 }
 
+// DefaultStreamFetchSize is the number of groups fetched from the cursor
+// of a stream at a time.
+const DefaultStreamFetchSize = 1000
+
 type MetricsRepository struct {
-	db DB
+	db        DB
+	promoted  func(key string) bool
+	gate      ColumnGate
+	fetchSize int
+	rollups   func(granularity string) (domain.RollupCoverage, bool)
+	views     map[string]string // by granularity; nil = event_rollups
+	sketches  bool              // event_rollup_sketches instead of event_rollups
+	counters  bool
+	maxCost   float64 // 0 = no limit
+	maxRows   float64 // 0 = no limit
+
+	// rolled is set on the copy answering a query from rollups, counted on
+	// the one answering it from event_counters. countsOnly skips unique
+	// users.
+	rolled     *rollupSource
+	counted    bool
+	countsOnly bool
+}
+
+// Gated columns, named "table.column". They were added by migrations that
+// may not have reached the database yet during a rolling deploy.
+const (
+	ColumnAnonymousID   = "events.anonymous_id"
+	ColumnIdentityLinks = "identity_links.stitch_from"
+	ColumnLinkTenantID  = "identity_links.tenant_id"
+	ColumnSampleRate    = "events.sample_rate"
+	ColumnTenantID      = "events.tenant_id"
+	ColumnRollups       = "event_rollups.unique_user"
+	ColumnCounters      = "event_counters.events"
+)
+
+// GatedColumns lists every column this adapter consults the gate for.
+var GatedColumns = []string{ColumnAnonymousID, ColumnIdentityLinks, ColumnLinkTenantID, ColumnSampleRate, ColumnTenantID, ColumnRollups, ColumnCounters}
+
+// errTenantColumnGated fails tenant-scoped reads while events.tenant_id is
+// gated off, rather than answering them with every tenant's events.
+var errTenantColumnGated = errors.New("tenant-scoped query while " + ColumnTenantID + " is not readable")
+
+// ColumnGate reports whether queries may rely on a gated column, and
+// whether the rollups may be written.
+type ColumnGate interface {
+	Reads(column string) bool
+	Writes(column string) bool
+}
+
+type RepositoryOption func(*MetricsRepository)
+
+// WithPromotedMetadata makes metadata filters and group-bys read the
+// promoted meta_<key> column for keys reported ready, instead of extracting
+// the key from the JSONB metadata.
+func WithPromotedMetadata(ready func(key string) bool) RepositoryOption {
+	return func(r *MetricsRepository) {
+		r.promoted = ready
+	}
+}
+
+// WithColumnGate falls back to older query shapes while gated columns are
+// not readable, so the same release can run against the schema before and
+// after their migration.
+func WithColumnGate(g ColumnGate) RepositoryOption {
+	return func(r *MetricsRepository) {
+		r.gate = g
+	}
+}
+
+// WithRollups answers eligible metrics queries from the rollups within the
+// coverage reported for each granularity (see RollUp).
+func WithRollups(coverage func(granularity string) (domain.RollupCoverage, bool)) RepositoryOption {
+	return func(r *MetricsRepository) {
+		r.rollups = coverage
+	}
+}
+
+// WithRollupViews reads rollups from a view per granularity instead of
+// event_rollups, e.g. TimescaleDB continuous aggregates. A view has one row
+// per bucket, tenant_id, event_name, channel, campaign_id (empty without a
+// campaign), user_id and anonymous_id, with the events it stands for as
+// weight. Unique users are resolved from it as from events, but stitching
+// applies to whole buckets: anonymous events count as the user from the
+// first bucket starting at or after stitch_from. Use it with WithRollups.
+func WithRollupViews(views map[string]string) RepositoryOption {
+	return func(r *MetricsRepository) {
+		r.views = views
+	}
+}
+
+// WithRollupSketches keeps rollups as one HyperLogLog sketch of unique
+// users per bucket and dimensions in event_rollup_sketches (migrations/hll),
+// rather than one row per user in event_rollups, and estimates the unique
+// users of queries read from them. Set it on the repository rolling up and
+// the one reading alike; rollup views ignore it.
+func WithRollupSketches() RepositoryOption {
+	return func(r *MetricsRepository) {
+		r.sketches = true
+	}
+}
+
+// WithCounters answers eligible counts-only queries from event_counters
+// (see countersEligible).
+func WithCounters() RepositoryOption {
+	return func(r *MetricsRepository) {
+		r.counters = true
+	}
 }
 
-func NewMetricsRepository(db DB) *MetricsRepository {
-	return &MetricsRepository{db: db}
+// WithStreamFetchSize sets the number of groups StreamMetrics fetches at a
+// time (default DefaultStreamFetchSize).
+func WithStreamFetchSize(n int) RepositoryOption {
+	return func(r *MetricsRepository) {
+		if n > 0 {
+			r.fetchSize = n
+		}
+	}
+}
+
+// WithCostLimit runs EXPLAIN before every grouped query and refuses it with
+// domain.ErrQueryTooExpensive when the planner estimates its total cost
+// above maxCost, or a step of its plan reading more than maxRows rows. A
+// limit of 0 is not checked.
+func WithCostLimit(maxCost, maxRows float64) RepositoryOption {
+	return func(r *MetricsRepository) {
+		r.maxCost = max(maxCost, 0)
+		r.maxRows = max(maxRows, 0)
+	}
+}
+
+func NewMetricsRepository(db DB, opts ...RepositoryOption) *MetricsRepository {
+	r := &MetricsRepository{db: db, fetchSize: DefaultStreamFetchSize}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// uniqueUserExpr resolves the user an event counts towards. Anonymous events
+// count as the user their tenant identified them as when they fall inside
+// the link's stitching window, and as their own anonymous visitor otherwise.
+const uniqueUserExpr = `CASE
+        WHEN user_id <> '' THEN user_id
+        ELSE COALESCE(
+            (SELECT l.user_id FROM identity_links l
+             WHERE l.tenant_id = events.tenant_id AND l.anonymous_id = events.anonymous_id
+               AND events.event_time >= l.stitch_from),
+            'anon:' || anonymous_id)
+    END`
+
+// Fallbacks while identity_links, its tenant_id or anonymous_id are gated
+// off: links that may belong to another tenant are not followed.
+const (
+	anonymousUniqueUserExpr = `CASE WHEN user_id <> '' THEN user_id ELSE 'anon:' || anonymous_id END`
+	legacyUniqueUserExpr    = `user_id`
+)
+
+// uniqueUserCount counts unique users: none for counts-only queries, as
+// COUNT(DISTINCT NULL) is 0, and an estimate merged from HyperLogLog
+// sketches for queries read from rollup sketches.
+func (r *MetricsRepository) uniqueUserCount() string {
+	if r.countsOnly {
+		return "COUNT(DISTINCT NULL)"
+	}
+	if r.rolled != nil && r.sketched() {
+		return sketchUniqueUsersExpr
+	}
+	return "COUNT(DISTINCT " + r.uniqueUsers() + ")"
+}
+
+func (r *MetricsRepository) uniqueUsers() string {
+	if r.rolled != nil {
+		return "unique_user"
+	}
+	if r.gate == nil {
+		return uniqueUserExpr
+	}
+	switch {
+	case !r.gate.Reads(ColumnAnonymousID):
+		return legacyUniqueUserExpr
+	case !r.gate.Reads(ColumnIdentityLinks) || !r.gate.Reads(ColumnLinkTenantID):
+		return anonymousUniqueUserExpr
+	default:
+		return uniqueUserExpr
+	}
+}
+
+// eventCountExpr counts each sampled event as the 1 / sample_rate events it
+// stands for. Unique users are not scaled: dropped events may belong to
+// users already counted.
+const eventCountExpr = `ROUND(SUM(1 / sample_rate))::bigint`
+
+// sampleRateColumn passes sample_rate through subqueries for eventCount.
+func (r *MetricsRepository) sampleRateColumn() string {
+	if r.gate != nil && !r.gate.Reads(ColumnSampleRate) {
+		return ""
+	}
+	return ", sample_rate"
+}
+
+func (r *MetricsRepository) reads(column string) bool {
+	return r.gate == nil || r.gate.Reads(column)
+}
+
+func (r *MetricsRepository) eventCount() string {
+	if r.rolled != nil || r.counted {
+		return rolledEventCountExpr
+	}
+	if r.gate != nil && !r.gate.Reads(ColumnSampleRate) {
+		return "COUNT(*)"
+	}
+	return eventCountExpr
+}
+
+// metadataExpr returns the SQL expression for metadata[key], appending the
+// key to args when it has to be extracted from JSONB.
+func (r *MetricsRepository) metadataExpr(key string, args []any) (string, []any) {
+	if r.promoted != nil && r.promoted(key) {
+		return "meta_" + key, args
+	}
+	args = append(args, key)
+	return fmt.Sprintf("metadata->>$%d", len(args)), args
+}
+
+// metadataContains matches metadata[key] = value by JSONB containment, which
+// the GIN index on metadata serves. A value that reads as a JSON number or
+// boolean also matches that number or boolean, as metadata->>key would.
+func metadataContains(key, value string, args []any) (string, []any) {
+	doc := func(v any) string {
+		b, _ := json.Marshal(map[string]any{key: v})
+		return string(b)
+	}
+
+	args = append(args, doc(value))
+	cond := fmt.Sprintf("metadata @> $%d::jsonb", len(args))
+
+	dec := json.NewDecoder(strings.NewReader(value))
+	dec.UseNumber()
+	var scalar any
+	if err := dec.Decode(&scalar); err == nil && !dec.More() {
+		switch scalar.(type) {
+		case json.Number, bool:
+			args = append(args, doc(scalar))
+			cond = fmt.Sprintf("(%s OR metadata @> $%d::jsonb)", cond, len(args))
+		}
+	}
+	return cond, args
 }
 
 func (r *MetricsRepository) QueryMetrics(ctx context.Context, f ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
-	fromTime := time.Unix(f.From, 0).UTC()
-	toTime := time.Unix(f.To, 0).UTC()
+	r = r.routed(f)
+	where, args, err := r.filterWhere(f)
+	if err != nil {
+		return nil, err
+	}
+	result := newResult(f)
+
+	if f.Mode == domain.ModeHistogram {
+		return r.queryHistogram(ctx, where, args, result, *f.Histogram)
+	}
+	if f.Mode == domain.ModeLag {
+		return r.queryLag(ctx, where, args, result, f)
+	}
+
+	var value string
+	value, args = r.aggregateExpr(f, args)
+
+	if f.GroupBy == "" {
+		return r.queryNoGroup(ctx, where, args, result, value)
+	}
+
+	// A user active in several groups counts once overall, and averages,
+	// minimums and maximums do not add up either, so the overall figures
+	// cannot be derived from the groups.
+	q, err := r.groupQuery(where, args, f, value)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.checkCost(ctx, q); err != nil {
+		return nil, err
+	}
+	total, unique, overall, err := r.queryOverall(ctx, where, args, value)
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.queryGroups(ctx, q, result, f)
+	if err != nil {
+		return nil, err
+	}
+	// Top-N groups and pages leave events out, so their sum is not the
+	// total.
+	if f.Top > 0 || f.Offset > 0 || res.NextOffset > 0 {
+		res.TotalCount = total
+	}
+	res.UniqueUsers = unique
+	res.Value = overall
+	return res, nil
+}
+
+var _ ports.MetricsStreamerPort = (*MetricsRepository)(nil)
+
+// StreamMetrics reads the groups through a server-side cursor, so a query
+// with millions of groups holds one fetch of them in memory at a time.
+func (r *MetricsRepository) StreamMetrics(
+	ctx context.Context,
+	f ports.MetricsFilter,
+	head func(*domain.AggregatedMetrics) error,
+	emit func([]domain.MetricsGroup) error,
+) error {
+	r = r.routed(f)
+	where, args, err := r.filterWhere(f)
+	if err != nil {
+		return err
+	}
+	var value string
+	value, args = r.aggregateExpr(f, args)
+
+	res := newResult(f)
+	res.TotalCount, res.UniqueUsers, res.Value, err = r.queryOverall(ctx, where, args, value)
+	if err != nil {
+		return err
+	}
+	if err := head(res); err != nil {
+		return err
+	}
+
+	f.Limit, f.Offset = 0, 0
+	q, err := r.groupQuery(where, args, f, value)
+	if err != nil {
+		return err
+	}
+
+	// Cursors only live until the end of their transaction. Nothing is
+	// written, so it is rolled back rather than committed.
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, "DECLARE metrics_groups NO SCROLL CURSOR FOR "+q.sql, q.args...); err != nil {
+		return err
+	}
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM metrics_groups", r.fetchSize)
+	for {
+		groups, err := r.fetchGroups(ctx, tx, fetch, q)
+		if err != nil {
+			return err
+		}
+		if len(groups) > 0 {
+			if err := emit(groups); err != nil {
+				return err
+			}
+		}
+		if len(groups) < r.fetchSize {
+			return nil
+		}
+	}
+}
+
+func (r *MetricsRepository) fetchGroups(ctx context.Context, tx Tx, fetch string, q groupQuery) ([]domain.MetricsGroup, error) {
+	rows, err := tx.QueryContext(ctx, fetch)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return q.groups(rows)
+}
+
+// timeRange returns the start and the exclusive end of the range from..to
+// of unix seconds. Events carry milliseconds, so the range ends at the
+// second after to rather than at to itself.
+func timeRange(from, to int64) (time.Time, time.Time) {
+	return time.Unix(from, 0).UTC(), time.Unix(to+1, 0).UTC()
+}
 
-	where := "event_name = $1 AND event_time BETWEEN $2 AND $3"
-	args := []any{f.EventName, fromTime, toTime}
-	argIndex := 4
+// filterWhere returns the WHERE condition of f and its arguments.
+func (r *MetricsRepository) filterWhere(f ports.MetricsFilter) (string, []any, error) {
+	fromTime, untilTime := timeRange(f.From, f.To)
+
+	where := "event_name = $1"
+	args := []any{f.EventName}
+	if names := domain.EventNames(f.EventName); len(names) > 1 {
+		args = args[:0]
+		placeholders := make([]string, len(names))
+		for i, name := range names {
+			args = append(args, name)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		where = "event_name IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+	args = append(args, fromTime, untilTime)
+	where += fmt.Sprintf(" AND event_time >= $%d AND event_time < $%d", len(args)-1, len(args))
+	argIndex := len(args) + 1
 
 	if f.Channel != nil {
 		where += fmt.Sprintf(" AND channel = $%d", argIndex)
@@ -42,37 +439,654 @@ func (r *MetricsRepository) QueryMetrics(ctx context.Context, f ports.MetricsFil
 		argIndex++
 	}
 
-	result := &domain.AggregatedMetrics{
+	if f.CampaignID != nil {
+		// campaign_id is NULL or '' for events without a campaign.
+		args = append(args, *f.CampaignID)
+		where += fmt.Sprintf(" AND COALESCE(campaign_id, '') = $%d", len(args))
+	}
+
+	if f.UserID != "" {
+		// The user unique users count the event towards, so anonymous events
+		// stitched to the user are theirs too.
+		args = append(args, f.UserID)
+		where += fmt.Sprintf(" AND (%s) = $%d", r.uniqueUsers(), len(args))
+	}
+
+	keys := make([]string, 0, len(f.Metadata))
+	for k := range f.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if r.promoted != nil && r.promoted(k) {
+			args = append(args, f.Metadata[k])
+			where += fmt.Sprintf(" AND meta_%s = $%d", k, len(args))
+			continue
+		}
+		var cond string
+		cond, args = metadataContains(k, f.Metadata[k], args)
+		where += " AND " + cond
+	}
+
+	if len(f.Tags) > 0 {
+		// @> needs every tag, && any of them.
+		op := "@>"
+		if f.TagMatch == domain.TagMatchAny {
+			op = "&&"
+		}
+		args = append(args, pq.Array(f.Tags))
+		where += fmt.Sprintf(" AND tags %s $%d::text[]", op, len(args))
+	}
+
+	if f.AsOf != nil {
+		args = append(args, f.AsOf.UTC())
+		where += fmt.Sprintf(" AND received_at <= $%d", len(args))
+	}
+
+	if f.Tenant != nil {
+		if !r.reads(ColumnTenantID) {
+			return "", nil, errTenantColumnGated
+		}
+		args = append(args, *f.Tenant)
+		where += fmt.Sprintf(" AND tenant_id = $%d", len(args))
+	}
+
+	return where, args, nil
+}
+
+func newResult(f ports.MetricsFilter) *domain.AggregatedMetrics {
+	return &domain.AggregatedMetrics{
 		EventName: f.EventName,
 		From:      f.From,
 		To:        f.To,
 		GroupBy:   f.GroupBy,
+		Aggregate: f.Aggregate,
+		Field:     f.Field,
+		Mode:      f.Mode,
+		AsOf:      f.AsOf,
+	}
+}
+
+func (r *MetricsRepository) queryGroups(
+	ctx context.Context,
+	q groupQuery,
+	res *domain.AggregatedMetrics,
+	f ports.MetricsFilter,
+) (*domain.AggregatedMetrics, error) {
+	rows, err := r.db.QueryContext(ctx, q.sql, q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups, err := q.groups(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	var totalSum int64
+	for _, g := range groups {
+		totalSum += g.TotalCount
+	}
+	res.Groups, res.NextOffset = pageOf(f).trim(groups)
+	res.TotalCount = totalSum
+
+	return res, nil
+}
+
+// queryPlan is the part of a node of EXPLAIN (FORMAT JSON) output the cost
+// limit reads.
+type queryPlan struct {
+	TotalCost float64     `json:"Total Cost"`
+	PlanRows  float64     `json:"Plan Rows"`
+	Plans     []queryPlan `json:"Plans"`
+}
+
+// maxPlanRows returns the most rows any step of p is estimated to return.
+func (p queryPlan) maxPlanRows() float64 {
+	rows := p.PlanRows
+	for _, child := range p.Plans {
+		rows = max(rows, child.maxPlanRows())
+	}
+	return rows
+}
+
+// checkCost refuses q when the planner's estimates exceed the cost limit
+// (see WithCostLimit). Estimates are only as good as the table statistics.
+func (r *MetricsRepository) checkCost(ctx context.Context, q groupQuery) error {
+	if r.maxCost == 0 && r.maxRows == 0 {
+		return nil
+	}
+	rows, err := r.db.QueryContext(ctx, "EXPLAIN (FORMAT JSON) "+q.sql, q.args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return errors.New("explain returned no plan")
+	}
+	var raw string
+	if err := rows.Scan(&raw); err != nil {
+		return err
+	}
+	var plans []struct {
+		Plan queryPlan `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(raw), &plans); err != nil {
+		return fmt.Errorf("parse query plan: %w", err)
+	}
+	if len(plans) == 0 {
+		return errors.New("explain returned no plan")
+	}
+
+	plan := plans[0].Plan
+	if r.maxCost > 0 && plan.TotalCost > r.maxCost {
+		return fmt.Errorf("%w: estimated cost %.0f exceeds %.0f", domain.ErrQueryTooExpensive, plan.TotalCost, r.maxCost)
+	}
+	if n := plan.maxPlanRows(); r.maxRows > 0 && n > r.maxRows {
+		return fmt.Errorf("%w: estimated %.0f rows exceed %.0f", domain.ErrQueryTooExpensive, n, r.maxRows)
+	}
+	return nil
+}
+
+// groupQuery returns the query grouping the events of where by f.GroupBy.
+func (r *MetricsRepository) groupQuery(where string, args []any, f ports.MetricsFilter, value string) (groupQuery, error) {
+	if dims := domain.GroupByDimensions(f.GroupBy); len(dims) > 1 {
+		return r.groupByDimensionsQuery(where, args, dims, f.Interval, value, pageOf(f))
+	}
+
+	if key, ok := domain.MetadataGroupKey(f.GroupBy); ok {
+		var expr string
+		expr, args = r.metadataExpr(key, args)
+		// Events without the key are grouped under "".
+		return r.groupByExprQuery(where, args, "COALESCE("+expr+", '')", value, f.Top, pageOf(f)), nil
 	}
 
 	switch f.GroupBy {
-	case "":
-		return r.queryNoGroup(ctx, where, args, result)
-	case "channel":
-		return r.queryGroupByChannel(ctx, where, args, result)
+	case domain.DimensionChannel:
+		return r.groupByExprQuery(where, args, "channel", value, f.Top, pageOf(f)), nil
+	case domain.DimensionCampaign:
+		return r.groupByExprQuery(where, args, "COALESCE(campaign_id, '')", value, f.Top, pageOf(f)), nil
+	case domain.DimensionUser:
+		return r.groupByExprQuery(where, args, "user_id", value, f.Top, pageOf(f)), nil
 	case "time":
-		return r.queryGroupByTime(ctx, where, args, result, f.Interval)
+		return r.groupByTimeQuery(where, args, f.Interval, value, pageOf(f)), nil
+	case domain.DimensionHourOfDay, domain.DimensionDayOfWeek:
+		return r.groupByExprQuery(where, args, calendarKeyExprs[f.GroupBy], value, f.Top, pageOf(f)), nil
 	default:
 		// Aslında buraya gelmemeli; usecase validasyonu zaten yapıyor.
-		return nil, fmt.Errorf("unsupported group_by: %s", f.GroupBy)
+		return groupQuery{}, fmt.Errorf("unsupported group_by: %s", f.GroupBy)
 	}
 }
 
+// groupQuery is a grouped query and how to scan one of its rows.
+type groupQuery struct {
+	sql  string
+	args []any
+	scan func(rows RowScanner) (domain.MetricsGroup, error)
+}
+
+// groups scans the remaining rows of rows.
+func (q groupQuery) groups(rows RowScanner) ([]domain.MetricsGroup, error) {
+	var groups []domain.MetricsGroup
+	for rows.Next() {
+		g, err := q.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// queryOverall returns the total count, unique users and aggregate value
+// over all groups.
+func (r *MetricsRepository) queryOverall(ctx context.Context, where string, args []any, value string) (int64, int64, *float64, error) {
+	rows, err := r.db.QueryContext(ctx, `
+SELECT `+r.uniqueUserCount()+` AS unique_users,
+    `+r.eventCount()+` AS total_count`+valueColumn(value)+`
+FROM `+r.source()+`
+WHERE `+where, args...)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	defer rows.Close()
+
+	var unique, total int64
+	var v *float64
+	if rows.Next() {
+		if err := rows.Scan(withValue([]any{&unique, &total}, value, &v)...); err != nil {
+			return 0, 0, nil, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, 0, nil, err
+	}
+	return total, unique, v, nil
+}
+
+// numericPattern matches the text of a JSON number.
+const numericPattern = `^-?[0-9]+(\.[0-9]+)?([eE][-+]?[0-9]+)?$`
+
+// numericExpr casts a metadata text expression to float8, or NULL when it
+// is not a number.
+func numericExpr(expr string) string {
+	return fmt.Sprintf(`CASE WHEN %[1]s ~ '%[2]s' THEN (%[1]s)::float8 END`, expr, numericPattern)
+}
+
+// aggregateExpr returns the SQL aggregate of f.Field, appending to args like
+// metadataExpr, or "" without an aggregate. Values that are not numbers are
+// skipped instead of failing the cast. Sums are scaled by sample rate like
+// event counts; the other aggregates need no scaling, as sampling keeps the
+// distribution of values as is. Percentiles are continuous, like lag ones.
+func (r *MetricsRepository) aggregateExpr(f ports.MetricsFilter, args []any) (string, []any) {
+	key, ok := domain.MetadataGroupKey(f.Field)
+	if f.Aggregate == "" || !ok {
+		return "", args
+	}
+	expr, args := r.metadataExpr(key, args)
+	v := numericExpr(expr)
+
+	if p, ok := domain.AggregatePercentiles[f.Aggregate]; ok {
+		return fmt.Sprintf("percentile_cont(%g) WITHIN GROUP (ORDER BY %s)", p, v), args
+	}
+
+	switch f.Aggregate {
+	case domain.AggregateSum:
+		if r.reads(ColumnSampleRate) {
+			return "SUM(" + v + " / sample_rate)", args
+		}
+		return "SUM(" + v + ")", args
+	case domain.AggregateAvg:
+		return "AVG(" + v + ")", args
+	case domain.AggregateMin:
+		return "MIN(" + v + ")", args
+	default:
+		return "MAX(" + v + ")", args
+	}
+}
+
+// valueColumn selects the aggregate value, when there is one.
+func valueColumn(value string) string {
+	if value == "" {
+		return ""
+	}
+	return ",\n    " + value + " AS value"
+}
+
+// withValue appends the scan destination of valueColumn.
+func withValue(dest []any, value string, v **float64) []any {
+	if value == "" {
+		return dest
+	}
+	return append(dest, v)
+}
+
+// groupPage is one page of ordered groups. Group queries ask for one group
+// more than the limit to tell whether another page follows.
+type groupPage struct {
+	orderBy string // "" = key
+	desc    bool
+	limit   int // 0 = every group
+	offset  int
+}
+
+func pageOf(f ports.MetricsFilter) groupPage {
+	return groupPage{orderBy: f.OrderBy, desc: f.Order == domain.OrderDesc, limit: f.Limit, offset: f.Offset}
+}
+
+// order returns the ORDER BY list of a query grouped by keys. Ties are
+// broken by key, so pages do not overlap.
+func (p groupPage) order(keys ...string) string {
+	dir := " ASC"
+	if p.desc {
+		dir = " DESC"
+	}
+	switch p.orderBy {
+	case domain.OrderByTotalCount, domain.OrderByUniqueUsers:
+		return p.orderBy + dir + ", " + strings.Join(keys, ", ")
+	case domain.OrderByValue:
+		return "value" + dir + " NULLS LAST, " + strings.Join(keys, ", ")
+	}
+	if !p.desc {
+		return strings.Join(keys, ", ")
+	}
+	return strings.Join(keys, " DESC, ") + " DESC"
+}
+
+// clause returns the LIMIT/OFFSET of the page, appending to args.
+func (p groupPage) clause(args []any) (string, []any) {
+	if p.limit <= 0 {
+		return "", args
+	}
+	args = append(args, p.limit+1, p.offset)
+	return fmt.Sprintf("\nLIMIT $%d OFFSET $%d", len(args)-1, len(args)), args
+}
+
+// trim drops the extra group, returning the offset of the next page, or 0
+// on the last one.
+func (p groupPage) trim(groups []domain.MetricsGroup) ([]domain.MetricsGroup, int) {
+	if p.limit <= 0 || len(groups) <= p.limit {
+		return groups, 0
+	}
+	return groups[:p.limit], p.offset + p.limit
+}
+
+var _ ports.RetentionReaderPort = (*MetricsRepository)(nil)
+
+// retentionSQL returns one row per cohort with its size as period 0, and one
+// per later bucket with returning users. Users are resolved like unique users.
+const retentionSQL = `
+WITH cohorts AS (
+    SELECT date_trunc('%[1]s', MIN(event_time)) AS cohort, u
+    FROM (
+        SELECT event_time, %[2]s AS u
+        FROM events
+        WHERE event_name = $1 AND %[3]s
+    ) s
+    GROUP BY u
+),
+returns AS (
+    SELECT DISTINCT date_trunc('%[1]s', event_time) AS bucket, %[2]s AS u
+    FROM events
+    WHERE event_name = $2 AND %[3]s
+)
+SELECT cohort, 0 AS period, COUNT(*)
+FROM cohorts
+GROUP BY 1
+UNION ALL
+SELECT c.cohort, (EXTRACT(EPOCH FROM r.bucket - c.cohort)::bigint / %[4]d)::int AS period, COUNT(*)
+FROM cohorts c
+JOIN returns r ON r.u = c.u AND r.bucket > c.cohort AND r.bucket <= c.cohort + $5::int * interval '1 %[1]s'
+GROUP BY 1, 2
+ORDER BY 1, 2`
+
+func (r *MetricsRepository) QueryRetention(ctx context.Context, f ports.RetentionFilter) (*domain.Retention, error) {
+	from, until := timeRange(f.From, f.To)
+	where := "event_time >= $3 AND event_time < $4"
+	args := []any{f.StartEvent, f.ReturnEvent, from, until, f.Periods}
+	if f.Tenant != nil {
+		if !r.reads(ColumnTenantID) {
+			return nil, errTenantColumnGated
+		}
+		args = append(args, *f.Tenant)
+		where += fmt.Sprintf(" AND tenant_id = $%d", len(args))
+	}
+
+	step := int64(domain.RetentionIntervals[f.Interval] / time.Second)
+	query := fmt.Sprintf(retentionSQL, f.Interval, r.uniqueUsers(), where, step)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := &domain.Retention{
+		StartEvent:  f.StartEvent,
+		ReturnEvent: f.ReturnEvent,
+		From:        f.From,
+		To:          f.To,
+		Interval:    f.Interval,
+		Periods:     f.Periods,
+	}
+	for rows.Next() {
+		var cohort time.Time
+		var period, users int64
+		if err := rows.Scan(&cohort, &period, &users); err != nil {
+			return nil, err
+		}
+
+		// Rows come ordered by cohort, its size first.
+		if period == 0 {
+			res.Cohorts = append(res.Cohorts, domain.RetentionCohort{
+				Start:    cohort.UTC(),
+				Users:    users,
+				Returned: make([]int64, f.Periods),
+			})
+			continue
+		}
+		if n := len(res.Cohorts); n > 0 && period <= int64(f.Periods) {
+			res.Cohorts[n-1].Returned[period-1] = users
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+var _ ports.EventNamesReaderPort = (*MetricsRepository)(nil)
+
+func (r *MetricsRepository) ListEventNames(ctx context.Context, f ports.CatalogFilter) ([]domain.EventName, error) {
+	from, until := timeRange(f.From, f.To)
+	where := "event_time >= $1 AND event_time < $2"
+	args := []any{from, until, f.Limit}
+	if f.Tenant != nil {
+		if !r.reads(ColumnTenantID) {
+			return nil, errTenantColumnGated
+		}
+		args = append(args, *f.Tenant)
+		where += fmt.Sprintf(" AND tenant_id = $%d", len(args))
+	}
+
+	query := fmt.Sprintf(`
+SELECT event_name, %s, MIN(event_time), MAX(event_time)
+FROM events
+WHERE %s
+GROUP BY event_name
+ORDER BY event_name
+LIMIT $3`, r.eventCount(), where)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.EventName
+	for rows.Next() {
+		var n domain.EventName
+		if err := rows.Scan(&n.Name, &n.Count, &n.FirstSeen, &n.LastSeen); err != nil {
+			return nil, err
+		}
+		n.FirstSeen, n.LastSeen = n.FirstSeen.UTC(), n.LastSeen.UTC()
+		out = append(out, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+var _ ports.ChannelsReaderPort = (*MetricsRepository)(nil)
+
+func (r *MetricsRepository) ListChannels(ctx context.Context, f ports.CatalogFilter) ([]domain.DimensionValue, error) {
+	from, until := timeRange(f.From, f.To)
+	where := "event_time >= $1 AND event_time < $2 AND channel <> ''"
+	args := []any{from, until, f.Limit}
+	if f.Tenant != nil {
+		if !r.reads(ColumnTenantID) {
+			return nil, errTenantColumnGated
+		}
+		args = append(args, *f.Tenant)
+		where += fmt.Sprintf(" AND tenant_id = $%d", len(args))
+	}
+
+	query := fmt.Sprintf(`
+SELECT channel, %s AS cnt
+FROM events
+WHERE %s
+GROUP BY channel
+ORDER BY cnt DESC, channel
+LIMIT $3`, r.eventCount(), where)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.DimensionValue
+	for rows.Next() {
+		var v domain.DimensionValue
+		if err := rows.Scan(&v.Value, &v.Count); err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+const currentWatermarkSQL = `SELECT COALESCE(MAX(received_at), now()) FROM events`
+
+var _ ports.DimensionValuesReaderPort = (*MetricsRepository)(nil)
+
+// dimensionExpr returns the SQL expression of a dimension, appending to args
+// like metadataExpr.
+func (r *MetricsRepository) dimensionExpr(dimension string, args []any) (string, []any, error) {
+	switch dimension {
+	case domain.DimensionChannel:
+		return "channel", args, nil
+	case domain.DimensionCampaign:
+		return "campaign_id", args, nil
+	}
+	key, ok := domain.MetadataGroupKey(dimension)
+	if !ok {
+		return "", nil, fmt.Errorf("unsupported dimension %q", dimension)
+	}
+	expr, args := r.metadataExpr(key, args)
+	return expr, args, nil
+}
+
+func (r *MetricsRepository) TopDimensionValues(ctx context.Context, dimension string, since time.Time, limit int) ([]domain.DimensionValue, error) {
+	expr, args, err := r.dimensionExpr(dimension, []any{since, limit})
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+SELECT %[1]s AS value, %[2]s AS cnt
+FROM events
+WHERE event_time >= $1 AND %[1]s <> ''
+GROUP BY 1
+ORDER BY cnt DESC, value
+LIMIT $2`, expr, r.eventCount())
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.DimensionValue
+	for rows.Next() {
+		var v domain.DimensionValue
+		if err := rows.Scan(&v.Value, &v.Count); err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// TopDimensionValuesByTenant ranks values within each tenant, so a large
+// tenant cannot push a small one's values out of its top list.
+func (r *MetricsRepository) TopDimensionValuesByTenant(ctx context.Context, dimension string, since time.Time, limit int) (map[string][]domain.DimensionValue, error) {
+	if !r.reads(ColumnTenantID) {
+		return nil, errTenantColumnGated
+	}
+	expr, args, err := r.dimensionExpr(dimension, []any{since, limit})
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+SELECT tenant_id, value, cnt
+FROM (
+    SELECT
+        tenant_id,
+        %[1]s AS value,
+        %[2]s AS cnt,
+        row_number() OVER (PARTITION BY tenant_id ORDER BY %[2]s DESC, %[1]s) AS rank
+    FROM events
+    WHERE event_time >= $1 AND %[1]s <> ''
+    GROUP BY tenant_id, %[1]s
+) ranked
+WHERE rank <= $2
+ORDER BY tenant_id, rank`, expr, r.eventCount())
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := map[string][]domain.DimensionValue{}
+	for rows.Next() {
+		var tenant string
+		var v domain.DimensionValue
+		if err := rows.Scan(&tenant, &v.Value, &v.Count); err != nil {
+			return nil, err
+		}
+		out[tenant] = append(out[tenant], v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// CurrentWatermark returns the newest received_at, or now() while no events
+// exist yet.
+func (r *MetricsRepository) CurrentWatermark(ctx context.Context) (time.Time, error) {
+	rows, err := r.db.QueryContext(ctx, currentWatermarkSQL)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer rows.Close()
+
+	var wm time.Time
+	if rows.Next() {
+		if err := rows.Scan(&wm); err != nil {
+			return time.Time{}, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return time.Time{}, err
+	}
+
+	return wm.UTC(), nil
+}
+
 func (r *MetricsRepository) queryNoGroup(
 	ctx context.Context,
 	where string,
 	args []any,
 	res *domain.AggregatedMetrics,
+	value string,
 ) (*domain.AggregatedMetrics, error) {
 	query := `
 SELECT
-    COUNT(*) AS total_count,
-    COUNT(DISTINCT user_id) AS unique_users
-FROM events
+    ` + r.eventCount() + ` AS total_count,
+    ` + r.uniqueUserCount() + ` AS unique_users` + valueColumn(value) + `
+FROM ` + r.source() + `
 WHERE ` + where
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
@@ -83,11 +1097,13 @@ WHERE ` + where
 
 	if rows.Next() {
 		var total, unique int64
-		if err := rows.Scan(&total, &unique); err != nil {
+		var v *float64
+		if err := rows.Scan(withValue([]any{&total, &unique}, value, &v)...); err != nil {
 			return nil, err
 		}
 		res.TotalCount = total
 		res.UniqueUsers = unique
+		res.Value = v
 	}
 
 	if err := rows.Err(); err != nil {
@@ -97,112 +1113,324 @@ WHERE ` + where
 	return res, nil
 }
 
-func (r *MetricsRepository) queryGroupByChannel(
+// groupByExprQuery groups by a text expression (channel, campaign, user or a
+// metadata key). With top > 0 only the top groups by count, or by value when
+// aggregating, are returned, skipping the empty key.
+func (r *MetricsRepository) groupByExprQuery(
+	where string,
+	args []any,
+	expr string,
+	value string,
+	top int,
+	page groupPage,
+) groupQuery {
+	order := page.order(expr)
+	if top > 0 {
+		rank := "total_count"
+		if value != "" {
+			rank = "value"
+		}
+		where += " AND " + expr + " <> ''"
+		args = append(args, top)
+		order = fmt.Sprintf("%s DESC NULLS LAST, %s\nLIMIT $%d", rank, expr, len(args))
+	} else {
+		var limit string
+		limit, args = page.clause(args)
+		order += limit
+	}
+
+	query := fmt.Sprintf(`
+SELECT
+    %[1]s,
+    %[4]s AS total_count,
+    %[3]s AS unique_users%[5]s
+FROM %[7]s
+WHERE %[2]s
+GROUP BY %[1]s
+ORDER BY %[6]s`, expr, where, r.uniqueUserCount(), r.eventCount(), valueColumn(value), order, r.source())
+
+	return groupQuery{sql: query, args: args, scan: func(rows RowScanner) (domain.MetricsGroup, error) {
+		var g domain.MetricsGroup
+		err := rows.Scan(withValue([]any{&g.Key, &g.TotalCount, &g.UniqueUsers}, value, &g.Value)...)
+		return g, err
+	}}
+}
+
+func (r *MetricsRepository) groupByTimeQuery(
+	where string,
+	args []any,
+	interval string,
+	value string,
+	page groupPage,
+) groupQuery {
+	limit, args := page.clause(args)
+	query := fmt.Sprintf(`
+SELECT
+    date_trunc('%s', event_time) AS bucket,
+    %s AS total_count,
+    %s AS unique_users%s
+FROM %s
+WHERE %s
+GROUP BY bucket
+ORDER BY %s%s
+`, interval, r.eventCount(), r.uniqueUserCount(), valueColumn(value), r.source(), where, page.order("bucket"), limit)
+
+	return groupQuery{sql: query, args: args, scan: func(rows RowScanner) (domain.MetricsGroup, error) {
+		var g domain.MetricsGroup
+		var ts time.Time
+		err := rows.Scan(withValue([]any{&ts, &g.TotalCount, &g.UniqueUsers}, value, &g.Value)...)
+		g.Key = ts.UTC().Format(time.RFC3339)
+		return g, err
+	}}
+}
+
+// timeKeyExpr formats time buckets as text, with the same keys as
+// groupByTimeQuery.
+func timeKeyExpr(interval string) string {
+	return fmt.Sprintf(`to_char(date_trunc('%s', event_time) AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"')`, interval)
+}
+
+// calendarKeyExprs are the keys of the calendar dimensions, in UTC; both
+// sort in calendar order as text.
+var calendarKeyExprs = map[string]string{
+	domain.DimensionHourOfDay: `to_char(event_time AT TIME ZONE 'UTC', 'HH24')`,
+	domain.DimensionDayOfWeek: `to_char(event_time AT TIME ZONE 'UTC', 'ID')`,
+}
+
+// groupByDimensionsQuery groups by every combination of several dimensions,
+// e.g. channel and time. Combinations without events are not returned.
+func (r *MetricsRepository) groupByDimensionsQuery(
+	where string,
+	args []any,
+	dims []string,
+	interval string,
+	value string,
+	page groupPage,
+) (groupQuery, error) {
+	exprs := make([]string, len(dims))
+	positions := make([]string, len(dims))
+	for i, dim := range dims {
+		switch dim {
+		case domain.DimensionChannel:
+			exprs[i] = "channel"
+		case domain.DimensionCampaign:
+			exprs[i] = "COALESCE(campaign_id, '')"
+		case domain.DimensionUser:
+			exprs[i] = "user_id"
+		case "time":
+			exprs[i] = timeKeyExpr(interval)
+		case domain.DimensionHourOfDay, domain.DimensionDayOfWeek:
+			exprs[i] = calendarKeyExprs[dim]
+		default:
+			key, ok := domain.MetadataGroupKey(dim)
+			if !ok {
+				return groupQuery{}, fmt.Errorf("unsupported group_by: %s", dim)
+			}
+			var expr string
+			expr, args = r.metadataExpr(key, args)
+			exprs[i] = "COALESCE(" + expr + ", '')"
+		}
+		positions[i] = strconv.Itoa(i + 1)
+	}
+
+	limit, args := page.clause(args)
+	query := fmt.Sprintf(`
+SELECT
+    %[1]s,
+    %[4]s AS total_count,
+    %[3]s AS unique_users%[6]s
+FROM %[9]s
+WHERE %[2]s
+GROUP BY %[5]s
+ORDER BY %[7]s%[8]s`, strings.Join(exprs, ",\n    "), where, r.uniqueUserCount(), r.eventCount(), strings.Join(positions, ", "), valueColumn(value), page.order(positions...), limit, r.source())
+
+	return groupQuery{sql: query, args: args, scan: func(rows RowScanner) (domain.MetricsGroup, error) {
+		g := domain.MetricsGroup{Keys: make([]string, len(dims))}
+		dest := make([]any, 0, len(dims)+3)
+		for i := range g.Keys {
+			dest = append(dest, &g.Keys[i])
+		}
+		err := rows.Scan(withValue(append(dest, &g.TotalCount, &g.UniqueUsers), value, &g.Value)...)
+		g.Key = strings.Join(g.Keys, domain.CompositeKeySeparator)
+		return g, err
+	}}, nil
+}
+
+func (r *MetricsRepository) queryHistogram(
 	ctx context.Context,
 	where string,
 	args []any,
 	res *domain.AggregatedMetrics,
+	spec domain.HistogramSpec,
 ) (*domain.AggregatedMetrics, error) {
-	query := `
+	value := "value"
+	if key, ok := domain.MetadataGroupKey(spec.Field); ok {
+		var expr string
+		expr, args = r.metadataExpr(key, args)
+		value = numericExpr(expr)
+		res.Field = spec.Field
+	}
+	where += " AND " + value + " IS NOT NULL"
+
+	// Totals only cover events that actually carry a value.
+	if _, err := r.queryNoGroup(ctx, where, args, res, ""); err != nil {
+		return nil, err
+	}
+
+	n := len(args)
+	bucket := fmt.Sprintf("width_bucket(%s, $%d, $%d, $%d)", value, n+1, n+2, n+3)
+	hargs := append(append([]any{}, args...), spec.Min, spec.Max, spec.Count)
+	if len(spec.Bounds) > 0 {
+		bucket = fmt.Sprintf("width_bucket(%s, $%d::float8[])", value, n+1)
+		hargs = append(append([]any{}, args...), pq.Array(spec.Bounds))
+	}
+	query := fmt.Sprintf(`
 SELECT
-    channel,
-    COUNT(*) AS total_count,
-    COUNT(DISTINCT user_id) AS unique_users
+    %s AS bucket,
+    %s AS total_count
 FROM events
-WHERE ` + where + `
-GROUP BY channel
-ORDER BY channel`
+WHERE %s
+GROUP BY bucket
+ORDER BY bucket
+`, bucket, r.eventCount(), where)
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.db.QueryContext(ctx, query, hargs...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var groups []domain.MetricsGroup
-	var totalSum int64
-	var uniqueSum int64
-
+	counts := map[int]int64{}
 	for rows.Next() {
-		var ch string
-		var total, unique int64
-
-		if err := rows.Scan(&ch, &total, &unique); err != nil {
+		var bucket, count int64
+		if err := rows.Scan(&bucket, &count); err != nil {
 			return nil, err
 		}
-
-		groups = append(groups, domain.MetricsGroup{
-			Key:         ch,
-			TotalCount:  total,
-			UniqueUsers: unique,
-		})
-		totalSum += total
-		uniqueSum += unique // not: cross-channel unique tam olarak doğru değil, ama basit çözüm
+		counts[int(bucket)] = count
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	res.Groups = groups
-	res.TotalCount = totalSum
-	res.UniqueUsers = uniqueSum
+	// Regular buckets are always present (zero-filled); under/overflow
+	// buckets only when they contain events.
+	buckets := spec.Buckets()
+	for i := 0; i <= buckets+1; i++ {
+		c, ok := counts[i]
+		if !ok && (i == 0 || i == buckets+1) {
+			continue
+		}
+		lower, upper := spec.BucketBounds(i)
+		res.Histogram = append(res.Histogram, domain.HistogramBucket{
+			Bucket: i,
+			Lower:  lower,
+			Upper:  upper,
+			Count:  c,
+		})
+	}
 
 	return res, nil
 }
 
-func (r *MetricsRepository) queryGroupByTime(
+// lagQuery aggregates received_at - event_time per group key. Percentiles
+// are continuous, so they may fall between two observed lags; they are not
+// weighted by sample rate, sampling keeps the lag distribution as is.
+const lagQuery = `
+SELECT
+    key,
+    %s AS total_count,
+    AVG(lag),
+    percentile_cont(0.5) WITHIN GROUP (ORDER BY lag),
+    percentile_cont(0.95) WITHIN GROUP (ORDER BY lag),
+    percentile_cont(0.99) WITHIN GROUP (ORDER BY lag),
+    MAX(lag)
+FROM (
+    SELECT %s AS key, EXTRACT(EPOCH FROM received_at - event_time)::float8 AS lag%s
+    FROM events
+    WHERE %s
+) l
+GROUP BY key
+ORDER BY key%s`
+
+func (r *MetricsRepository) queryLag(
 	ctx context.Context,
 	where string,
 	args []any,
 	res *domain.AggregatedMetrics,
-	interval string,
+	f ports.MetricsFilter,
 ) (*domain.AggregatedMetrics, error) {
-	query := fmt.Sprintf(`
-SELECT
-    date_trunc('%s', event_time) AS bucket,
-    COUNT(*) AS total_count,
-    COUNT(DISTINCT user_id) AS unique_users
-FROM events
-WHERE %s
-GROUP BY bucket
-ORDER BY bucket
-`, interval, where)
+	// Percentiles do not add up across groups, so the overall figures come
+	// from their own query.
+	overall, err := r.lagStats(ctx, "''", where, args, groupPage{})
+	if err != nil {
+		return nil, err
+	}
+	if len(overall) == 1 {
+		res.TotalCount = overall[0].TotalCount
+		res.Lag = overall[0].Lag
+	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	var keyExpr string
+	switch f.GroupBy {
+	case "":
+		return res, nil
+	case domain.DimensionChannel:
+		keyExpr = "channel"
+	case domain.DimensionCampaign:
+		keyExpr = "COALESCE(campaign_id, '')"
+	case domain.DimensionUser:
+		keyExpr = "user_id"
+	case "time":
+		keyExpr = timeKeyExpr(f.Interval)
+	case domain.DimensionHourOfDay, domain.DimensionDayOfWeek:
+		keyExpr = calendarKeyExprs[f.GroupBy]
+	default:
+		key, ok := domain.MetadataGroupKey(f.GroupBy)
+		if !ok {
+			return nil, fmt.Errorf("unsupported group_by: %s", f.GroupBy)
+		}
+		var expr string
+		expr, args = r.metadataExpr(key, args)
+		keyExpr = "COALESCE(" + expr + ", '')"
+	}
+
+	page := pageOf(f)
+	groups, err := r.lagStats(ctx, keyExpr, where, args, page)
+	if err != nil {
+		return nil, err
+	}
+	res.Groups, res.NextOffset = page.trim(groups)
+	return res, nil
+}
+
+func (r *MetricsRepository) lagStats(ctx context.Context, keyExpr, where string, args []any, page groupPage) ([]domain.MetricsGroup, error) {
+	limit, args := page.clause(args)
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(lagQuery, r.eventCount(), keyExpr, r.sampleRateColumn(), where, limit), args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	var groups []domain.MetricsGroup
-	var totalSum int64
-	var uniqueSum int64
-
 	for rows.Next() {
-		var ts time.Time
-		var total, unique int64
-
-		if err := rows.Scan(&ts, &total, &unique); err != nil {
+		g := domain.MetricsGroup{Lag: &domain.LagStats{}}
+		if err := rows.Scan(
+			&g.Key,
+			&g.TotalCount,
+			&g.Lag.AvgSeconds,
+			&g.Lag.P50Seconds,
+			&g.Lag.P95Seconds,
+			&g.Lag.P99Seconds,
+			&g.Lag.MaxSeconds,
+		); err != nil {
 			return nil, err
 		}
-
-		groups = append(groups, domain.MetricsGroup{
-			Key:         ts.UTC().Format(time.RFC3339),
-			TotalCount:  total,
-			UniqueUsers: unique,
-		})
-		totalSum += total
-		uniqueSum += unique
+		groups = append(groups, g)
 	}
-
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	res.Groups = groups
-	res.TotalCount = totalSum
-	res.UniqueUsers = uniqueSum
-
-	return res, nil
+	return groups, nil
 }
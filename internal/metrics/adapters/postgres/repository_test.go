@@ -2,11 +2,15 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
 
+	"event-metrics-service/internal/metrics/core/domain"
 	"event-metrics-service/internal/metrics/core/ports"
 )
 
@@ -53,6 +57,28 @@ func (f *fakeRowScanner) Scan(dest ...any) error {
 				return errors.New("type assertion to time.Time failed")
 			}
 			*d = v
+		case *bool:
+			v, ok := row.values[i].(bool)
+			if !ok {
+				return errors.New("type assertion to bool failed")
+			}
+			*d = v
+		case *float64:
+			v, ok := row.values[i].(float64)
+			if !ok {
+				return errors.New("type assertion to float64 failed")
+			}
+			*d = v
+		case **float64:
+			if row.values[i] == nil {
+				*d = nil
+				continue
+			}
+			v, ok := row.values[i].(float64)
+			if !ok {
+				return errors.New("type assertion to float64 failed")
+			}
+			*d = &v
 		default:
 			return errors.New("unsupported dest type")
 		}
@@ -75,6 +101,7 @@ type fakeDB struct {
 	lastQuery string
 	lastArgs  []any
 	called    bool
+	tx        *fakeTx
 }
 
 func (f *fakeDB) QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error) {
@@ -87,6 +114,46 @@ func (f *fakeDB) QueryContext(ctx context.Context, query string, args ...any) (R
 	return nil, nil
 }
 
+func (f *fakeDB) BeginTx(ctx context.Context) (Tx, error) {
+	f.tx = &fakeTx{db: f}
+	return f.tx, nil
+}
+
+// fakeTx runs queries through its fakeDB and records statements.
+type fakeTx struct {
+	db         *fakeDB
+	execs      []string
+	execArgs   []any
+	rolledBack bool
+}
+
+func (t *fakeTx) QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error) {
+	return t.db.QueryContext(ctx, query, args...)
+}
+
+func (t *fakeTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	t.execs = append(t.execs, query)
+	t.execArgs = args
+	return nil, nil
+}
+
+func (t *fakeTx) Commit() error { return nil }
+
+func (t *fakeTx) Rollback() error {
+	t.rolledBack = true
+	return nil
+}
+
+// overallRows answers the overall query that grouped metrics run besides
+// the group query.
+func overallRows(query string, total, unique int64) (RowScanner, bool) {
+	q := strings.TrimSpace(query)
+	if !strings.HasPrefix(q, "SELECT COUNT(DISTINCT") && !strings.HasPrefix(q, "SELECT "+sketchUniqueUsersExpr) {
+		return nil, false
+	}
+	return &fakeRowScanner{rows: []fakeRow{{values: []any{unique, total}}}}, true
+}
+
 // ------------------------------------------------------------
 // NO GROUP BY
 // ------------------------------------------------------------
@@ -129,6 +196,158 @@ func TestMetricsRepository_NoGroupBy(t *testing.T) {
 	}
 }
 
+func TestMetricsRepository_SubSecondEventAtTo(t *testing.T) {
+	to := time.Unix(200, 0)
+	// The first three are in the range: it ends after the last millisecond
+	// of to.
+	events := []time.Time{
+		time.Unix(100, 0),
+		to,
+		to.Add(500 * time.Millisecond),
+		time.Unix(99, 999*int64(time.Millisecond)),
+		to.Add(time.Second),
+	}
+
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if !strings.Contains(query, "event_time >= $2 AND event_time < $3") {
+				t.Fatalf("unexpected where clause: %s", query)
+			}
+			start, until := args[1].(time.Time), args[2].(time.Time)
+			var total int64
+			for _, e := range events {
+				if !e.Before(start) && e.Before(until) {
+					total++
+				}
+			}
+			return &fakeRowScanner{rows: []fakeRow{{values: []any{total, int64(1)}}}}, nil
+		},
+	}
+
+	res, err := NewMetricsRepository(db).QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "purchase",
+		From:      100,
+		To:        to.Unix(),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.TotalCount != 3 {
+		t.Fatalf("expected the events from from to the last millisecond of to, got %d", res.TotalCount)
+	}
+}
+
+func TestMetricsRepository_SeveralEventNames(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if !strings.Contains(query, "event_name IN ($1, $2) AND event_time >= $3 AND event_time < $4 AND channel = $5") {
+				t.Fatalf("unexpected where clause: %s", query)
+			}
+			if args[0] != "signup_web" || args[1] != "signup_mobile" || args[4] != "web" {
+				t.Fatalf("unexpected args: %v", args)
+			}
+			return &fakeRowScanner{rows: []fakeRow{{values: []any{int64(30), int64(12)}}}}, nil
+		},
+	}
+
+	channel := "web"
+	res, err := NewMetricsRepository(db).QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "signup_web,signup_mobile",
+		From:      100,
+		To:        200,
+		Channel:   &channel,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.TotalCount != 30 || res.EventName != "signup_web,signup_mobile" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestMetricsRepository_CampaignFilter(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return &fakeRowScanner{rows: []fakeRow{{values: []any{int64(3), int64(2)}}}}, nil
+		},
+	}
+
+	noCampaign := ""
+	_, err := NewMetricsRepository(db).QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName:  "purchase",
+		From:       100,
+		To:         200,
+		CampaignID: &noCampaign,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(db.lastQuery, "AND COALESCE(campaign_id, '') = $4") || db.lastArgs[3] != "" {
+		t.Fatalf("unexpected query %s with args %v", db.lastQuery, db.lastArgs)
+	}
+}
+
+func TestMetricsRepository_UserFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		gate ColumnGate
+		want string
+	}{
+		{"stitched", nil, "AND (" + uniqueUserExpr + ") = $4"},
+		{"anonymous_id gated off", fakeColumnGate{}, "AND (user_id) = $4"},
+	}
+
+	for _, tt := range tests {
+		db := &fakeDB{
+			QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+				return &fakeRowScanner{rows: []fakeRow{{values: []any{int64(3), int64(1)}}}}, nil
+			},
+		}
+
+		_, err := NewMetricsRepository(db, WithColumnGate(tt.gate)).QueryMetrics(context.Background(), ports.MetricsFilter{
+			EventName: "purchase",
+			From:      100,
+			To:        200,
+			UserID:    "u1",
+		})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if !strings.Contains(db.lastQuery, tt.want) || db.lastArgs[3] != "u1" {
+			t.Fatalf("%s: unexpected query %s with args %v", tt.name, db.lastQuery, db.lastArgs)
+		}
+	}
+}
+
+func TestMetricsRepository_Tags(t *testing.T) {
+	for match, op := range map[string]string{"": "@>", "all": "@>", "any": "&&"} {
+		db := &fakeDB{
+			QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+				if !strings.Contains(query, "AND tags "+op+" $4::text[]") {
+					t.Fatalf("%q: unexpected where clause: %s", match, query)
+				}
+				if tags, ok := args[3].(driver.Valuer); !ok {
+					t.Fatalf("%q: expected a Postgres array argument, got %T", match, args[3])
+				} else if v, _ := tags.Value(); v != `{"promo","beta"}` {
+					t.Fatalf("%q: unexpected tags argument %v", match, v)
+				}
+				return &fakeRowScanner{rows: []fakeRow{{values: []any{int64(3), int64(2)}}}}, nil
+			},
+		}
+
+		_, err := NewMetricsRepository(db).QueryMetrics(context.Background(), ports.MetricsFilter{
+			EventName: "purchase",
+			From:      100,
+			To:        200,
+			Tags:      []string{"promo", "beta"},
+			TagMatch:  match,
+		})
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", match, err)
+		}
+	}
+}
+
 // ------------------------------------------------------------
 // GROUP BY CHANNEL
 // ------------------------------------------------------------
@@ -136,6 +355,9 @@ func TestMetricsRepository_NoGroupBy(t *testing.T) {
 func TestMetricsRepository_GroupByChannel(t *testing.T) {
 	db := &fakeDB{
 		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if rows, ok := overallRows(query, 200, 70); ok {
+				return rows, nil
+			}
 			if !strings.Contains(query, "GROUP BY channel") {
 				t.Fatalf("expected GROUP BY channel in query, got: %s", query)
 			}
@@ -173,8 +395,315 @@ func TestMetricsRepository_GroupByChannel(t *testing.T) {
 	if res.TotalCount != 200 {
 		t.Fatalf("expected total_count=200, got %d", res.TotalCount)
 	}
-	if res.UniqueUsers != 80 {
-		t.Fatalf("expected unique_users=80, got %d", res.UniqueUsers)
+	// u1 is active on both channels, so 70 rather than 50+30.
+	if res.UniqueUsers != 70 {
+		t.Fatalf("expected unique_users=70, got %d", res.UniqueUsers)
+	}
+}
+
+// ------------------------------------------------------------
+// METADATA FILTER / GROUP BY
+// ------------------------------------------------------------
+
+func TestMetricsRepository_MetadataFromJSONB(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if rows, ok := overallRows(query, 13, 5); ok {
+				// Postgres rejects parameters the query does not use.
+				if len(args) != 4 {
+					t.Fatalf("expected the group key not to be passed to the unique users query, got %v", args)
+				}
+				return rows, nil
+			}
+			return &fakeRowScanner{rows: []fakeRow{
+				{values: []any{"", int64(3), int64(2)}},
+				{values: []any{"p1", int64(10), int64(4)}},
+			}}, nil
+		},
+	}
+
+	repo := NewMetricsRepository(db)
+
+	res, err := repo.QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "product_view",
+		From:      100,
+		To:        200,
+		GroupBy:   "metadata.product_id",
+		Metadata:  map[string]string{"category": "shoes"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{"AND metadata @> $4::jsonb", "GROUP BY COALESCE(metadata->>$5, '')"} {
+		if !strings.Contains(db.lastQuery, want) {
+			t.Fatalf("expected query to contain %q, got: %s", want, db.lastQuery)
+		}
+	}
+	if db.lastArgs[3] != `{"category":"shoes"}` || db.lastArgs[4] != "product_id" {
+		t.Fatalf("unexpected args: %v", db.lastArgs)
+	}
+	if len(res.Groups) != 2 || res.TotalCount != 13 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestMetricsRepository_MetadataUsesPromotedColumn(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if rows, ok := overallRows(query, 10, 1); ok {
+				return rows, nil
+			}
+			return &fakeRowScanner{rows: []fakeRow{{values: []any{"p1", int64(10), int64(4)}}}}, nil
+		},
+	}
+
+	ready := func(key string) bool { return key == "product_id" }
+	repo := NewMetricsRepository(db, WithPromotedMetadata(ready))
+
+	_, err := repo.QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "product_view",
+		From:      100,
+		To:        200,
+		GroupBy:   "metadata.product_id",
+		Metadata:  map[string]string{"product_id": "p1", "category": "shoes"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"AND metadata @> $4::jsonb", // category is not promoted
+		"AND meta_product_id = $5",  // product_id is
+		"GROUP BY COALESCE(meta_product_id, '')",
+	} {
+		if !strings.Contains(db.lastQuery, want) {
+			t.Fatalf("expected query to contain %q, got: %s", want, db.lastQuery)
+		}
+	}
+	if len(db.lastArgs) != 5 {
+		t.Fatalf("expected 5 args, got %v", db.lastArgs)
+	}
+}
+
+func TestMetricsRepository_MetadataFilterMatchesNumbers(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return &fakeRowScanner{rows: []fakeRow{{values: []any{int64(4), int64(2)}}}}, nil
+		},
+	}
+
+	_, err := NewMetricsRepository(db).QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "product_view",
+		From:      100,
+		To:        200,
+		Metadata:  map[string]string{"product_id": "12345678901234567890", "in_stock": "true", "sku": "12 34"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"AND (metadata @> $4::jsonb OR metadata @> $5::jsonb)",
+		"AND (metadata @> $6::jsonb OR metadata @> $7::jsonb)",
+		"AND metadata @> $8::jsonb",
+	} {
+		if !strings.Contains(db.lastQuery, want) {
+			t.Fatalf("expected query to contain %q, got: %s", want, db.lastQuery)
+		}
+	}
+	want := []any{`{"in_stock":"true"}`, `{"in_stock":true}`, `{"product_id":"12345678901234567890"}`, `{"product_id":12345678901234567890}`, `{"sku":"12 34"}`}
+	for i, w := range want {
+		if db.lastArgs[3+i] != w {
+			t.Fatalf("arg %d: expected %v, got %v", 3+i, w, db.lastArgs[3+i])
+		}
+	}
+}
+
+func TestMetricsRepository_CostLimit(t *testing.T) {
+	const plan = `[{"Plan": {"Node Type": "Aggregate", "Total Cost": 1200.5, "Plan Rows": 2,
+		"Plans": [{"Node Type": "Seq Scan", "Total Cost": 900, "Plan Rows": 50000}]}}]`
+
+	tests := []struct {
+		name             string
+		maxCost, maxRows float64
+		refused          bool
+	}{
+		{"under both limits", 2000, 100000, false},
+		{"cost above the limit", 1000, 0, true},
+		{"scan above the row limit", 0, 10000, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var explained, grouped bool
+			db := &fakeDB{
+				QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+					if strings.HasPrefix(query, "EXPLAIN (FORMAT JSON) ") {
+						if !strings.Contains(query, "GROUP BY channel") || args[0] != "product_view" {
+							t.Fatalf("expected the group query to be explained, got: %s %v", query, args)
+						}
+						explained = true
+						return &fakeRowScanner{rows: []fakeRow{{values: []any{plan}}}}, nil
+					}
+					if rows, ok := overallRows(query, 200, 70); ok {
+						return rows, nil
+					}
+					grouped = true
+					return &fakeRowScanner{rows: []fakeRow{{values: []any{"web", int64(200), int64(70)}}}}, nil
+				},
+			}
+
+			_, err := NewMetricsRepository(db, WithCostLimit(tt.maxCost, tt.maxRows)).QueryMetrics(context.Background(), ports.MetricsFilter{
+				EventName: "product_view",
+				From:      100,
+				To:        200,
+				GroupBy:   "channel",
+			})
+			if !explained {
+				t.Fatalf("expected the query to be explained")
+			}
+			if tt.refused {
+				if !errors.Is(err, domain.ErrQueryTooExpensive) {
+					t.Fatalf("expected ErrQueryTooExpensive, got %v", err)
+				}
+				if grouped {
+					t.Fatalf("expected a refused query not to run")
+				}
+				return
+			}
+			if err != nil || !grouped {
+				t.Fatalf("expected the query to run, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMetricsRepository_CostLimitSkipsUngroupedQueries(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if strings.Contains(query, "EXPLAIN") {
+				t.Fatalf("unexpected explain: %s", query)
+			}
+			return &fakeRowScanner{rows: []fakeRow{{values: []any{int64(3), int64(2)}}}}, nil
+		},
+	}
+	_, err := NewMetricsRepository(db, WithCostLimit(1, 1)).QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "product_view",
+		From:      100,
+		To:        200,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMetricsRepository_GroupPage(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if rows, ok := overallRows(query, 500, 90); ok {
+				return rows, nil
+			}
+			if !strings.Contains(query, "ORDER BY COALESCE(metadata->>$4, '')\nLIMIT $5 OFFSET $6") {
+				t.Fatalf("unexpected query: %s", query)
+			}
+			if args[4] != 3 || args[5] != 2 {
+				t.Fatalf("expected one group more than the limit, got %v", args)
+			}
+			return &fakeRowScanner{rows: []fakeRow{
+				{values: []any{"p3", int64(10), int64(4)}},
+				{values: []any{"p4", int64(20), int64(5)}},
+				{values: []any{"p5", int64(30), int64(6)}},
+			}}, nil
+		},
+	}
+
+	res, err := NewMetricsRepository(db).QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "product_view",
+		From:      100,
+		To:        200,
+		GroupBy:   "metadata.product_id",
+		Limit:     2,
+		Offset:    2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Groups) != 2 || res.Groups[1].Key != "p4" || res.NextOffset != 4 {
+		t.Fatalf("unexpected page: %+v", res)
+	}
+	if res.TotalCount != 500 || res.UniqueUsers != 90 {
+		t.Fatalf("expected the overall totals on a page, got %+v", res)
+	}
+}
+
+func TestMetricsRepository_GroupOrder(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter ports.MetricsFilter
+		want   string
+	}{
+		{
+			"biggest channels first",
+			ports.MetricsFilter{GroupBy: "channel", OrderBy: "total_count", Order: "desc"},
+			"ORDER BY total_count DESC, channel",
+		},
+		{
+			"latest buckets first",
+			ports.MetricsFilter{GroupBy: "time", Interval: "hour", OrderBy: "key", Order: "desc"},
+			"ORDER BY bucket DESC",
+		},
+		{
+			"lowest revenue first",
+			ports.MetricsFilter{GroupBy: "channel,campaign_id", Aggregate: "sum", Field: "metadata.revenue", OrderBy: "value", Order: "asc"},
+			"ORDER BY value ASC NULLS LAST, 1, 2",
+		},
+	}
+
+	for _, tt := range tests {
+		db := &fakeDB{
+			QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+				return &fakeRowScanner{}, nil
+			},
+		}
+		f := tt.filter
+		f.EventName, f.From, f.To = "purchase", 100, 200
+		if _, err := NewMetricsRepository(db).QueryMetrics(context.Background(), f); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if !strings.Contains(db.lastQuery, tt.want) {
+			t.Fatalf("%s: expected query to contain %q, got: %s", tt.name, tt.want, db.lastQuery)
+		}
+	}
+}
+
+func TestMetricsRepository_LastGroupPage(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if rows, ok := overallRows(query, 30, 6); ok {
+				return rows, nil
+			}
+			if !strings.Contains(query, "ORDER BY bucket\nLIMIT $4 OFFSET $5") {
+				t.Fatalf("unexpected query: %s", query)
+			}
+			return &fakeRowScanner{rows: []fakeRow{
+				{values: []any{time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC), int64(30), int64(6)}},
+			}}, nil
+		},
+	}
+
+	res, err := NewMetricsRepository(db).QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "product_view",
+		From:      100,
+		To:        200,
+		GroupBy:   "time",
+		Interval:  "hour",
+		Limit:     10,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Groups) != 1 || res.NextOffset != 0 || res.TotalCount != 30 {
+		t.Fatalf("unexpected page: %+v", res)
 	}
 }
 
@@ -185,6 +714,9 @@ func TestMetricsRepository_GroupByChannel(t *testing.T) {
 func TestMetricsRepository_GroupByTime(t *testing.T) {
 	db := &fakeDB{
 		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if rows, ok := overallRows(query, 300, 90); ok {
+				return rows, nil
+			}
 			if !strings.Contains(query, "date_trunc('hour'") {
 				t.Fatalf("expected date_trunc('hour', ...) in query, got: %s", query)
 			}
@@ -225,8 +757,8 @@ func TestMetricsRepository_GroupByTime(t *testing.T) {
 	if res.TotalCount != 300 {
 		t.Fatalf("expected total_count=300, got %d", res.TotalCount)
 	}
-	if res.UniqueUsers != 100 {
-		t.Fatalf("expected unique_users=100, got %d", res.UniqueUsers)
+	if res.UniqueUsers != 90 {
+		t.Fatalf("expected unique_users=90, got %d", res.UniqueUsers)
 	}
 
 	// key format RFC3339 mi?
@@ -237,33 +769,963 @@ func TestMetricsRepository_GroupByTime(t *testing.T) {
 	}
 }
 
-// ------------------------------------------------------------
-// DB ERROR
-// ------------------------------------------------------------
-
-func TestMetricsRepository_DBError(t *testing.T) {
+func TestMetricsRepository_GroupByChannelAndTime(t *testing.T) {
 	db := &fakeDB{
 		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
-			return nil, errors.New("db failure")
+			if rows, ok := overallRows(query, 12, 6); ok {
+				return rows, nil
+			}
+			return &fakeRowScanner{
+				rows: []fakeRow{
+					{values: []any{"mobile", "2025-12-07T10:00:00Z", "", int64(5), int64(3)}},
+					{values: []any{"web", "2025-12-07T10:00:00Z", "p1", int64(7), int64(4)}},
+				},
+			}, nil
 		},
 	}
 
 	repo := NewMetricsRepository(db)
 
-	filter := ports.MetricsFilter{
+	res, err := repo.QueryMetrics(context.Background(), ports.MetricsFilter{
 		EventName: "product_view",
 		From:      100,
 		To:        200,
+		GroupBy:   "channel,time,metadata.product_id",
+		Interval:  "hour",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
 
-	res, err := repo.QueryMetrics(context.Background(), filter)
-	if err == nil {
-		t.Fatalf("expected error, got nil")
+	for _, want := range []string{"to_char(date_trunc('hour', event_time)", "COALESCE(metadata->>$4, '')", "GROUP BY 1, 2, 3", "ORDER BY 1, 2, 3"} {
+		if !strings.Contains(db.lastQuery, want) {
+			t.Fatalf("expected query to contain %q, got: %s", want, db.lastQuery)
+		}
 	}
-	if err.Error() != "db failure" {
-		t.Fatalf("expected db failure, got %v", err)
+	if db.lastArgs[3] != "product_id" {
+		t.Fatalf("unexpected args: %v", db.lastArgs)
 	}
-	if res != nil {
-		t.Fatalf("expected nil result on error")
+	if len(res.Groups) != 2 || res.TotalCount != 12 || res.UniqueUsers != 6 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	g := res.Groups[1]
+	if g.Key != "web|2025-12-07T10:00:00Z|p1" || len(g.Keys) != 3 || g.Keys[0] != "web" || g.Keys[2] != "p1" {
+		t.Fatalf("unexpected group: %+v", g)
+	}
+}
+
+func TestMetricsRepository_GroupByCalendar(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if rows, ok := overallRows(query, 12, 6); ok {
+				return rows, nil
+			}
+			return &fakeRowScanner{
+				rows: []fakeRow{
+					{values: []any{"1", "09", int64(5), int64(3)}},
+					{values: []any{"7", "23", int64(7), int64(4)}},
+				},
+			}, nil
+		},
+	}
+
+	repo := NewMetricsRepository(db)
+
+	res, err := repo.QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "product_view",
+		From:      100,
+		To:        200,
+		GroupBy:   "day_of_week,hour_of_day",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{"to_char(event_time AT TIME ZONE 'UTC', 'ID')", "to_char(event_time AT TIME ZONE 'UTC', 'HH24')", "GROUP BY 1, 2"} {
+		if !strings.Contains(db.lastQuery, want) {
+			t.Fatalf("expected query to contain %q, got: %s", want, db.lastQuery)
+		}
+	}
+	if g := res.Groups[1]; g.Key != "7|23" || len(g.Keys) != 2 {
+		t.Fatalf("unexpected group: %+v", g)
+	}
+
+	db.QueryFn = func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+		return &fakeRowScanner{}, nil
+	}
+	if _, err := repo.QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "product_view",
+		From:      100,
+		To:        200,
+		GroupBy:   "hour_of_day",
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(db.lastQuery, "to_char(event_time AT TIME ZONE 'UTC', 'HH24')") {
+		t.Fatalf("unexpected query: %s", db.lastQuery)
+	}
+}
+
+func TestMetricsRepository_AggregateNoGroup(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return &fakeRowScanner{rows: []fakeRow{{values: []any{int64(10), int64(4), 1234.5}}}}, nil
+		},
+	}
+
+	repo := NewMetricsRepository(db)
+
+	res, err := repo.QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "purchase",
+		From:      100,
+		To:        200,
+		Aggregate: domain.AggregateSum,
+		Field:     "metadata.revenue",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{"SUM(CASE WHEN metadata->>$4 ~ ", "THEN (metadata->>$4)::float8 END / sample_rate) AS value"} {
+		if !strings.Contains(db.lastQuery, want) {
+			t.Fatalf("expected query to contain %q, got: %s", want, db.lastQuery)
+		}
+	}
+	if len(db.lastArgs) != 4 || db.lastArgs[3] != "revenue" {
+		t.Fatalf("unexpected args: %v", db.lastArgs)
+	}
+	if res.Value == nil || *res.Value != 1234.5 || res.Aggregate != "sum" || res.Field != "metadata.revenue" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestMetricsRepository_AggregatePerGroup(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if strings.HasPrefix(strings.TrimSpace(query), "SELECT COUNT(DISTINCT") {
+				if !strings.Contains(query, "AVG(CASE WHEN meta_duration ~ ") {
+					t.Fatalf("expected the overall query to average, got: %s", query)
+				}
+				return &fakeRowScanner{rows: []fakeRow{{values: []any{int64(5), int64(6), 2.5}}}}, nil
+			}
+			return &fakeRowScanner{rows: []fakeRow{
+				{values: []any{"ios", int64(2), int64(2), nil}},
+				{values: []any{"web", int64(4), int64(3), 2.5}},
+			}}, nil
+		},
+	}
+
+	ready := func(key string) bool { return key == "duration" }
+	repo := NewMetricsRepository(db, WithPromotedMetadata(ready))
+
+	res, err := repo.QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "video_watched",
+		From:      100,
+		To:        200,
+		GroupBy:   "channel",
+		Aggregate: domain.AggregateAvg,
+		Field:     "metadata.duration",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(db.lastQuery, "GROUP BY channel") || !strings.Contains(db.lastQuery, "AS value") {
+		t.Fatalf("unexpected group query: %s", db.lastQuery)
+	}
+	if res.Value == nil || *res.Value != 2.5 || res.UniqueUsers != 5 {
+		t.Fatalf("unexpected overall figures: %+v", res)
+	}
+	// ios has no numeric durations.
+	if res.Groups[0].Value != nil || res.Groups[1].Value == nil || *res.Groups[1].Value != 2.5 {
+		t.Fatalf("unexpected groups: %+v", res.Groups)
+	}
+}
+
+func TestMetricsRepository_TopValues(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if strings.HasPrefix(strings.TrimSpace(query), "SELECT COUNT(DISTINCT") {
+				return &fakeRowScanner{rows: []fakeRow{{values: []any{int64(120), int64(500), 2100.0}}}}, nil
+			}
+			return &fakeRowScanner{rows: []fakeRow{
+				{values: []any{"c2", int64(40), int64(12), 900.0}},
+				{values: []any{"c1", int64(90), int64(30), 450.0}},
+			}}, nil
+		},
+	}
+
+	repo := NewMetricsRepository(db)
+
+	res, err := repo.QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "purchase",
+		From:      100,
+		To:        200,
+		GroupBy:   domain.DimensionCampaign,
+		Aggregate: domain.AggregateSum,
+		Field:     "metadata.revenue",
+		Top:       2,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"COALESCE(campaign_id, '') <> ''",
+		"ORDER BY value DESC NULLS LAST, COALESCE(campaign_id, '')\nLIMIT $5",
+	} {
+		if !strings.Contains(db.lastQuery, want) {
+			t.Fatalf("expected query to contain %q, got: %s", want, db.lastQuery)
+		}
+	}
+	if len(db.lastArgs) != 5 || db.lastArgs[4] != 2 {
+		t.Fatalf("unexpected args: %v", db.lastArgs)
+	}
+	// The total covers every campaign, not only the top ones.
+	if res.TotalCount != 500 || res.UniqueUsers != 120 || len(res.Groups) != 2 || res.Groups[0].Key != "c2" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestMetricsRepository_AggregatePercentile(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return &fakeRowScanner{rows: []fakeRow{{values: []any{int64(10), int64(4), 812.0}}}}, nil
+		},
+	}
+
+	repo := NewMetricsRepository(db)
+
+	res, err := repo.QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "checkout",
+		From:      100,
+		To:        200,
+		Aggregate: domain.AggregateP95,
+		Field:     "metadata.latency_ms",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "percentile_cont(0.95) WITHIN GROUP (ORDER BY CASE WHEN metadata->>$4 ~ "
+	if !strings.Contains(db.lastQuery, want) || strings.Contains(db.lastQuery, "/ sample_rate) AS value") {
+		t.Fatalf("expected an unweighted percentile, got: %s", db.lastQuery)
+	}
+	if res.Value == nil || *res.Value != 812 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+// ------------------------------------------------------------
+// RETENTION
+// ------------------------------------------------------------
+
+func TestMetricsRepository_QueryRetention(t *testing.T) {
+	w1 := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	w2 := w1.AddDate(0, 0, 7)
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return &fakeRowScanner{rows: []fakeRow{
+				{values: []any{w1, int64(0), int64(10)}},
+				{values: []any{w1, int64(1), int64(6)}},
+				{values: []any{w1, int64(3), int64(2)}},
+				{values: []any{w2, int64(0), int64(5)}},
+				{values: []any{w2, int64(2), int64(1)}},
+			}}, nil
+		},
+	}
+
+	repo := NewMetricsRepository(db)
+	tenant := "acme"
+
+	res, err := repo.QueryRetention(context.Background(), ports.RetentionFilter{
+		StartEvent:  "signup",
+		ReturnEvent: "login",
+		From:        100,
+		To:          200,
+		Interval:    domain.RetentionWeek,
+		Periods:     3,
+		Tenant:      &tenant,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"date_trunc('week', MIN(event_time))",
+		"EXTRACT(EPOCH FROM r.bucket - c.cohort)::bigint / 604800",
+		"$5::int * interval '1 week'",
+		"tenant_id = $6",
+	} {
+		if !strings.Contains(db.lastQuery, want) {
+			t.Fatalf("expected query to contain %q, got: %s", want, db.lastQuery)
+		}
+	}
+	if len(db.lastArgs) != 6 || db.lastArgs[0] != "signup" || db.lastArgs[1] != "login" || db.lastArgs[4] != 3 {
+		t.Fatalf("unexpected args: %v", db.lastArgs)
+	}
+
+	if len(res.Cohorts) != 2 {
+		t.Fatalf("expected 2 cohorts, got %+v", res.Cohorts)
+	}
+	got := res.Cohorts[0]
+	if !got.Start.Equal(w1) || got.Users != 10 || fmt.Sprint(got.Returned) != "[6 0 2]" {
+		t.Fatalf("unexpected first cohort: %+v", got)
+	}
+	if got := res.Cohorts[1]; got.Users != 5 || fmt.Sprint(got.Returned) != "[0 1 0]" {
+		t.Fatalf("unexpected second cohort: %+v", got)
+	}
+}
+
+// ------------------------------------------------------------
+// HISTOGRAM
+// ------------------------------------------------------------
+
+func TestMetricsRepository_Histogram(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if !strings.Contains(query, "value IS NOT NULL") {
+				t.Fatalf("expected value IS NOT NULL filter, got: %s", query)
+			}
+			if strings.Contains(query, "width_bucket(value, $4, $5, $6)") {
+				if len(args) != 6 || args[3] != 0.0 || args[4] != 100.0 || args[5] != 4 {
+					t.Fatalf("unexpected histogram args: %v", args)
+				}
+				return &fakeRowScanner{
+					rows: []fakeRow{
+						{values: []any{int64(0), int64(1)}},
+						{values: []any{int64(2), int64(3)}},
+					},
+				}, nil
+			}
+			return &fakeRowScanner{
+				rows: []fakeRow{
+					{values: []any{int64(4), int64(2)}},
+				},
+			}, nil
+		},
+	}
+
+	repo := NewMetricsRepository(db)
+
+	filter := ports.MetricsFilter{
+		EventName: "order_placed",
+		From:      100,
+		To:        200,
+		Mode:      domain.ModeHistogram,
+		Histogram: &domain.HistogramSpec{Min: 0, Max: 100, Count: 4},
+	}
+
+	res, err := repo.QueryMetrics(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.TotalCount != 4 || res.UniqueUsers != 2 {
+		t.Fatalf("unexpected totals: %+v", res)
+	}
+	// underflow + 4 regular buckets (overflow empty -> omitted)
+	if len(res.Histogram) != 5 {
+		t.Fatalf("expected 5 buckets, got %d", len(res.Histogram))
+	}
+	if res.Histogram[0].Bucket != 0 || res.Histogram[0].Lower != nil || *res.Histogram[0].Upper != 0 {
+		t.Fatalf("unexpected underflow bucket: %+v", res.Histogram[0])
+	}
+	b2 := res.Histogram[2]
+	if b2.Bucket != 2 || b2.Count != 3 || *b2.Lower != 25 || *b2.Upper != 50 {
+		t.Fatalf("unexpected bucket 2: %+v", b2)
+	}
+	if res.Histogram[1].Count != 0 {
+		t.Fatalf("expected zero-filled bucket 1, got %+v", res.Histogram[1])
+	}
+}
+
+func TestMetricsRepository_HistogramBoundsOnMetadata(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if !strings.Contains(query, "THEN (metadata->>$4)::float8 END IS NOT NULL") || args[3] != "amount" {
+				t.Fatalf("expected a numeric metadata filter, got: %s %v", query, args)
+			}
+			if strings.Contains(query, "width_bucket(") {
+				if !strings.Contains(query, "::float8 END, $5::float8[])") || len(args) != 5 {
+					t.Fatalf("unexpected histogram query: %s %v", query, args)
+				}
+				return &fakeRowScanner{
+					rows: []fakeRow{
+						{values: []any{int64(2), int64(3)}},
+						{values: []any{int64(4), int64(1)}},
+					},
+				}, nil
+			}
+			return &fakeRowScanner{
+				rows: []fakeRow{
+					{values: []any{int64(4), int64(2)}},
+				},
+			}, nil
+		},
+	}
+
+	repo := NewMetricsRepository(db)
+
+	res, err := repo.QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "purchase",
+		From:      100,
+		To:        200,
+		Mode:      domain.ModeHistogram,
+		Histogram: &domain.HistogramSpec{Bounds: []float64{0, 10, 50, 100}, Field: "metadata.amount"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Field != "metadata.amount" {
+		t.Fatalf("expected the bucketed field, got %q", res.Field)
+	}
+	// 3 regular buckets and the non-empty overflow one
+	if len(res.Histogram) != 4 {
+		t.Fatalf("expected 4 buckets, got %+v", res.Histogram)
+	}
+	b2 := res.Histogram[1]
+	if b2.Bucket != 2 || b2.Count != 3 || *b2.Lower != 10 || *b2.Upper != 50 {
+		t.Fatalf("unexpected bucket 2: %+v", b2)
+	}
+	if over := res.Histogram[3]; over.Bucket != 4 || *over.Lower != 100 || over.Upper != nil || over.Count != 1 {
+		t.Fatalf("unexpected overflow bucket: %+v", over)
+	}
+}
+
+// ------------------------------------------------------------
+// INGESTION LAG
+// ------------------------------------------------------------
+
+func TestMetricsRepository_LagGroupedByChannel(t *testing.T) {
+	var queries []string
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			queries = append(queries, query)
+			if !strings.Contains(query, "received_at - event_time") {
+				t.Fatalf("expected lag expression, got: %s", query)
+			}
+			if strings.Contains(query, "SELECT channel AS key") {
+				return &fakeRowScanner{
+					rows: []fakeRow{
+						{values: []any{"ios", int64(2), 30.0, 30.0, 55.0, 59.0, 60.0}},
+						{values: []any{"web", int64(8), 1.5, 1.0, 3.0, 4.0, 4.0}},
+					},
+				}, nil
+			}
+			return &fakeRowScanner{
+				rows: []fakeRow{{values: []any{"", int64(10), 7.2, 1.0, 50.0, 58.0, 60.0}}},
+			}, nil
+		},
+	}
+
+	repo := NewMetricsRepository(db)
+
+	res, err := repo.QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "order_placed",
+		From:      100,
+		To:        200,
+		GroupBy:   "channel",
+		Mode:      domain.ModeLag,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(queries) != 2 {
+		t.Fatalf("expected overall and grouped queries, got %d", len(queries))
+	}
+	if res.TotalCount != 10 || res.Lag == nil || res.Lag.P95Seconds != 50 || res.Lag.MaxSeconds != 60 {
+		t.Fatalf("unexpected overall lag: %+v %+v", res, res.Lag)
+	}
+	if len(res.Groups) != 2 || res.Groups[0].Key != "ios" || res.Groups[0].Lag.P99Seconds != 59 {
+		t.Fatalf("unexpected groups: %+v", res.Groups)
+	}
+}
+
+func TestMetricsRepository_LagNoEvents(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return &fakeRowScanner{}, nil
+		},
+	}
+
+	repo := NewMetricsRepository(db)
+
+	res, err := repo.QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "order_placed",
+		From:      100,
+		To:        200,
+		Mode:      domain.ModeLag,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.TotalCount != 0 || res.Lag != nil {
+		t.Fatalf("expected no lag without events, got %+v", res)
+	}
+}
+
+// ------------------------------------------------------------
+// AS OF WATERMARK
+// ------------------------------------------------------------
+
+func TestMetricsRepository_AsOfPinsReceivedAt(t *testing.T) {
+	asOf := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return &fakeRowScanner{
+				rows: []fakeRow{{values: []any{int64(5), int64(2)}}},
+			}, nil
+		},
+	}
+
+	repo := NewMetricsRepository(db)
+
+	res, err := repo.QueryMetrics(context.Background(), ports.MetricsFilter{
+		EventName: "product_view",
+		From:      100,
+		To:        200,
+		AsOf:      &asOf,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(db.lastQuery, "received_at <= $4") {
+		t.Fatalf("expected received_at predicate, got: %s", db.lastQuery)
+	}
+	if got := db.lastArgs[3]; got != asOf {
+		t.Fatalf("expected as_of arg %v, got %v", asOf, got)
+	}
+	if res.AsOf == nil || !res.AsOf.Equal(asOf) {
+		t.Fatalf("expected result as_of %v, got %v", asOf, res.AsOf)
+	}
+}
+
+func TestMetricsRepository_CurrentWatermark(t *testing.T) {
+	want := time.Date(2025, 12, 7, 10, 0, 0, 123000000, time.UTC)
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if !strings.Contains(query, "MAX(received_at)") {
+				t.Fatalf("unexpected query: %s", query)
+			}
+			return &fakeRowScanner{
+				rows: []fakeRow{{values: []any{want}}},
+			}, nil
+		},
+	}
+
+	got, err := NewMetricsRepository(db).CurrentWatermark(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+// ------------------------------------------------------------
+// DB ERROR
+// ------------------------------------------------------------
+
+func TestMetricsRepository_DBError(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return nil, errors.New("db failure")
+		},
+	}
+
+	repo := NewMetricsRepository(db)
+
+	filter := ports.MetricsFilter{
+		EventName: "product_view",
+		From:      100,
+		To:        200,
+	}
+
+	res, err := repo.QueryMetrics(context.Background(), filter)
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+	if err.Error() != "db failure" {
+		t.Fatalf("expected db failure, got %v", err)
+	}
+	if res != nil {
+		t.Fatalf("expected nil result on error")
+	}
+}
+
+// ------------------------------------------------------------
+// ANONYMOUS STITCHING
+// ------------------------------------------------------------
+
+func TestMetricsRepository_UniqueUsersResolveIdentityLinks(t *testing.T) {
+	for _, groupBy := range []string{"", "channel", "time"} {
+		db := &fakeDB{
+			QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+				if strings.Contains(query, "COUNT(DISTINCT user_id)") ||
+					!strings.Contains(query, "identity_links") ||
+					!strings.Contains(query, "event_time >= l.stitch_from") {
+					t.Fatalf("group_by=%q: unique users must resolve identity links: %s", groupBy, query)
+				}
+				return &fakeRowScanner{}, nil
+			},
+		}
+
+		filter := ports.MetricsFilter{EventName: "product_view", From: 100, To: 200, GroupBy: groupBy}
+		if groupBy == "time" {
+			filter.Interval = "hour"
+		}
+		if _, err := NewMetricsRepository(db).QueryMetrics(context.Background(), filter); err != nil {
+			t.Fatalf("group_by=%q: unexpected error: %v", groupBy, err)
+		}
+	}
+}
+
+type fakeColumnGate map[string]bool
+
+func (g fakeColumnGate) Reads(column string) bool {
+	return g[column]
+}
+
+func (g fakeColumnGate) Writes(column string) bool {
+	return g[column]
+}
+
+func TestMetricsRepository_UniqueUsersFollowColumnGate(t *testing.T) {
+	tests := []struct {
+		name    string
+		gate    fakeColumnGate
+		want    string
+		notWant string
+	}{
+		{"before migration", fakeColumnGate{}, "COUNT(DISTINCT user_id)", "anonymous_id"},
+		{"links not readable", fakeColumnGate{ColumnAnonymousID: true}, "'anon:' || anonymous_id", "identity_links"},
+		{"link tenants not readable", fakeColumnGate{ColumnAnonymousID: true, ColumnIdentityLinks: true}, "'anon:' || anonymous_id", "identity_links"},
+		{"all readable", fakeColumnGate{ColumnAnonymousID: true, ColumnIdentityLinks: true, ColumnLinkTenantID: true}, "l.tenant_id = events.tenant_id", "COUNT(DISTINCT user_id)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDB{
+				QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+					return &fakeRowScanner{}, nil
+				},
+			}
+			repo := NewMetricsRepository(db, WithColumnGate(tt.gate))
+
+			_, err := repo.QueryMetrics(context.Background(), ports.MetricsFilter{EventName: "product_view", From: 100, To: 200})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !strings.Contains(db.lastQuery, tt.want) || strings.Contains(db.lastQuery, tt.notWant) {
+				t.Fatalf("expected %q without %q, got: %s", tt.want, tt.notWant, db.lastQuery)
+			}
+		})
+	}
+}
+
+func TestMetricsRepository_EventCountsScaleBySampleRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		gate    fakeColumnGate
+		want    string
+		notWant string
+	}{
+		{"before migration", fakeColumnGate{}, "COUNT(*) AS total_count", "sample_rate"},
+		{"readable", fakeColumnGate{ColumnSampleRate: true}, "ROUND(SUM(1 / sample_rate))::bigint AS total_count", "COUNT(*)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var queries []string
+			db := &fakeDB{
+				QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+					queries = append(queries, query)
+					return &fakeRowScanner{}, nil
+				},
+			}
+			repo := NewMetricsRepository(db, WithColumnGate(tt.gate))
+
+			for _, f := range []ports.MetricsFilter{
+				{EventName: "heartbeat", From: 100, To: 200},
+				{EventName: "heartbeat", From: 100, To: 200, GroupBy: "channel", Mode: domain.ModeLag},
+			} {
+				if _, err := repo.QueryMetrics(context.Background(), f); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			for _, q := range queries {
+				if !strings.Contains(q, tt.want) || strings.Contains(q, tt.notWant) {
+					t.Fatalf("expected %q without %q, got: %s", tt.want, tt.notWant, q)
+				}
+			}
+		})
+	}
+}
+
+// ------------------------------------------------------------
+// DIMENSION VALUES
+// ------------------------------------------------------------
+
+func TestMetricsRepository_ListEventNames(t *testing.T) {
+	first := time.Date(2025, 12, 1, 8, 0, 0, 0, time.UTC)
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return &fakeRowScanner{rows: []fakeRow{
+				{values: []any{"login", int64(30), first, first.Add(2 * time.Hour)}},
+				{values: []any{"signup", int64(4), first.Add(time.Hour), first.Add(time.Hour)}},
+			}}, nil
+		},
+	}
+	tenant := "acme"
+
+	got, err := NewMetricsRepository(db).ListEventNames(context.Background(), ports.CatalogFilter{
+		From: 100, To: 200, Limit: 50, Tenant: &tenant,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{"GROUP BY event_name", "ORDER BY event_name", "LIMIT $3", "tenant_id = $4", eventCountExpr} {
+		if !strings.Contains(db.lastQuery, want) {
+			t.Fatalf("expected query to contain %q, got: %s", want, db.lastQuery)
+		}
+	}
+	if len(db.lastArgs) != 4 || db.lastArgs[2] != 50 || db.lastArgs[3] != "acme" {
+		t.Fatalf("unexpected args: %v", db.lastArgs)
+	}
+	if len(got) != 2 || got[0].Name != "login" || got[0].Count != 30 || !got[0].LastSeen.Equal(first.Add(2*time.Hour)) {
+		t.Fatalf("unexpected names: %+v", got)
+	}
+}
+
+func TestMetricsRepository_ListChannels(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return &fakeRowScanner{rows: []fakeRow{
+				{values: []any{"web", int64(30)}},
+				{values: []any{"ios", int64(12)}},
+			}}, nil
+		},
+	}
+
+	got, err := NewMetricsRepository(db).ListChannels(context.Background(), ports.CatalogFilter{From: 100, To: 200, Limit: 50})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{"channel <> ''", "GROUP BY channel", "ORDER BY cnt DESC, channel", "LIMIT $3"} {
+		if !strings.Contains(db.lastQuery, want) {
+			t.Fatalf("expected query to contain %q, got: %s", want, db.lastQuery)
+		}
+	}
+	if strings.Contains(db.lastQuery, "tenant_id") || len(db.lastArgs) != 3 {
+		t.Fatalf("expected no tenant filter, got: %s %v", db.lastQuery, db.lastArgs)
+	}
+	if len(got) != 2 || got[0].Value != "web" || got[0].Count != 30 {
+		t.Fatalf("unexpected channels: %+v", got)
+	}
+}
+
+func TestMetricsRepository_TopDimensionValues(t *testing.T) {
+	since := time.Date(2025, 11, 7, 0, 0, 0, 0, time.UTC)
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if !strings.Contains(query, "metadata->>$3") || !strings.Contains(query, "ORDER BY cnt DESC") {
+				t.Fatalf("unexpected query: %s", query)
+			}
+			if len(args) != 3 || args[0] != since || args[1] != 10 || args[2] != "plan" {
+				t.Fatalf("unexpected args: %v", args)
+			}
+			return &fakeRowScanner{
+				rows: []fakeRow{
+					{values: []any{"pro", int64(30)}},
+					{values: []any{"free", int64(12)}},
+				},
+			}, nil
+		},
+	}
+
+	got, err := NewMetricsRepository(db).TopDimensionValues(context.Background(), "metadata.plan", since, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0].Value != "pro" || got[0].Count != 30 {
+		t.Fatalf("unexpected values: %+v", got)
+	}
+}
+
+func TestMetricsRepository_TopDimensionValuesChannel(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if !strings.Contains(query, "SELECT channel AS value") || len(args) != 2 {
+				t.Fatalf("unexpected query: %s %v", query, args)
+			}
+			return &fakeRowScanner{}, nil
+		},
+	}
+
+	if _, err := NewMetricsRepository(db).TopDimensionValues(context.Background(), "channel", time.Now(), 10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := NewMetricsRepository(db).TopDimensionValues(context.Background(), "user_id", time.Now(), 10); err == nil {
+		t.Fatalf("expected error for unsupported dimension")
+	}
+}
+
+// ------------------------------------------------------------
+// TENANTS
+// ------------------------------------------------------------
+
+func TestMetricsRepository_TenantFilter(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return &fakeRowScanner{rows: []fakeRow{{values: []any{int64(5), int64(2)}}}}, nil
+		},
+	}
+	tenant := "team-a"
+	flt := ports.MetricsFilter{EventName: "product_view", From: 100, To: 200, Tenant: &tenant}
+
+	repo := NewMetricsRepository(db, WithColumnGate(fakeColumnGate{ColumnTenantID: true}))
+	if _, err := repo.QueryMetrics(context.Background(), flt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(db.lastQuery, "tenant_id = $4") || db.lastArgs[3] != "team-a" {
+		t.Fatalf("expected tenant predicate, got: %s %v", db.lastQuery, db.lastArgs)
+	}
+
+	// while the column is gated, tenant-scoped queries fail rather than
+	// counting every tenant's events
+	db.called = false
+	repo = NewMetricsRepository(db, WithColumnGate(fakeColumnGate{}))
+	if _, err := repo.QueryMetrics(context.Background(), flt); err == nil {
+		t.Fatalf("expected error while tenant column is gated")
+	}
+	if db.called {
+		t.Fatalf("expected no query while tenant column is gated")
+	}
+}
+
+func TestMetricsRepository_TopDimensionValuesByTenant(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if !strings.Contains(query, "PARTITION BY tenant_id") || !strings.Contains(query, "rank <= $2") {
+				t.Fatalf("unexpected query: %s", query)
+			}
+			return &fakeRowScanner{
+				rows: []fakeRow{
+					{values: []any{"team-a", "web", int64(3)}},
+					{values: []any{"team-b", "ios", int64(5)}},
+					{values: []any{"team-b", "web", int64(1)}},
+				},
+			}, nil
+		},
+	}
+
+	repo := NewMetricsRepository(db, WithColumnGate(fakeColumnGate{ColumnTenantID: true}))
+	got, err := repo.TopDimensionValuesByTenant(context.Background(), "channel", time.Now(), 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got["team-a"]) != 1 || len(got["team-b"]) != 2 || got["team-b"][0].Value != "ios" {
+		t.Fatalf("unexpected values: %+v", got)
+	}
+
+	if _, err := NewMetricsRepository(db, WithColumnGate(fakeColumnGate{})).TopDimensionValuesByTenant(context.Background(), "channel", time.Now(), 10); err == nil {
+		t.Fatalf("expected error while tenant column is gated")
+	}
+}
+
+// ------------------------------------------------------------
+// STREAMING
+// ------------------------------------------------------------
+
+func TestMetricsRepository_StreamMetrics(t *testing.T) {
+	var fetches int
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if rows, ok := overallRows(query, 50, 7); ok {
+				return rows, nil
+			}
+			if query != "FETCH FORWARD 2 FROM metrics_groups" {
+				return nil, fmt.Errorf("unexpected query: %s", query)
+			}
+			fetches++
+			rows := []fakeRow{
+				{values: []any{fmt.Sprintf("c%d", 2*fetches-1), int64(10), int64(2)}},
+				{values: []any{fmt.Sprintf("c%d", 2*fetches), int64(10), int64(2)}},
+			}
+			if fetches == 3 {
+				rows = rows[:1]
+			}
+			return &fakeRowScanner{rows: rows}, nil
+		},
+	}
+	repo := NewMetricsRepository(db, WithStreamFetchSize(2))
+
+	var head *domain.AggregatedMetrics
+	var keys []string
+	err := repo.StreamMetrics(context.Background(),
+		ports.MetricsFilter{EventName: "purchase", From: 100, To: 200, GroupBy: "channel", Limit: 10000},
+		func(m *domain.AggregatedMetrics) error {
+			head = m
+			return nil
+		},
+		func(groups []domain.MetricsGroup) error {
+			if len(groups) > 2 {
+				t.Fatalf("expected batches of at most 2 groups, got %d", len(groups))
+			}
+			for _, g := range groups {
+				keys = append(keys, g.Key)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if head == nil || head.TotalCount != 50 || head.UniqueUsers != 7 || head.GroupBy != "channel" {
+		t.Fatalf("unexpected head: %+v", head)
+	}
+	if strings.Join(keys, ",") != "c1,c2,c3,c4,c5" {
+		t.Fatalf("unexpected groups: %v", keys)
+	}
+	if len(db.tx.execs) != 1 || !strings.HasPrefix(db.tx.execs[0], "DECLARE metrics_groups NO SCROLL CURSOR FOR") {
+		t.Fatalf("expected a cursor declaration, got: %v", db.tx.execs)
+	}
+	if strings.Contains(db.tx.execs[0], "LIMIT") || len(db.tx.execArgs) != 3 {
+		t.Fatalf("expected the cursor to read every group, got: %s %v", db.tx.execs[0], db.tx.execArgs)
+	}
+	if !db.tx.rolledBack {
+		t.Fatalf("expected the read-only transaction to end")
+	}
+}
+
+func TestMetricsRepository_StreamMetricsStopsOnEmitError(t *testing.T) {
+	var fetches int
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if rows, ok := overallRows(query, 50, 7); ok {
+				return rows, nil
+			}
+			fetches++
+			return &fakeRowScanner{rows: []fakeRow{{values: []any{"web", int64(10), int64(2)}}}}, nil
+		},
+	}
+
+	gone := errors.New("client gone")
+	err := NewMetricsRepository(db, WithStreamFetchSize(1)).StreamMetrics(context.Background(),
+		ports.MetricsFilter{EventName: "purchase", From: 100, To: 200, GroupBy: "channel"},
+		func(*domain.AggregatedMetrics) error { return nil },
+		func([]domain.MetricsGroup) error { return gone },
+	)
+	if !errors.Is(err, gone) {
+		t.Fatalf("expected the emit error, got %v", err)
+	}
+	if fetches != 1 || !db.tx.rolledBack {
+		t.Fatalf("expected one fetch and a rollback, got %d fetches", fetches)
 	}
 }
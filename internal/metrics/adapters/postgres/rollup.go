@@ -0,0 +1,401 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/ports"
+)
+
+var _ ports.RollupStorePort = (*MetricsRepository)(nil)
+
+// errRollupsGated skips rolling up while event_rollups may not be written.
+var errRollupsGated = errors.New("rollups while " + ColumnRollups + " is not writable")
+
+// errRollupLinksGated skips rolling up while unique users cannot be stitched
+// within their tenant, as the rollups would keep the users counted without.
+var errRollupLinksGated = errors.New("rollups while " + ColumnLinkTenantID + " is not readable")
+
+// rollUpSQL recomputes the rollups of granularity $1 for the buckets in
+// [$2, $3) in one statement, which needs no transaction for its advisory
+// lock. Rows are upserted and the ones left over deleted, rather than all
+// deleted and inserted again, as the data-modifying CTEs of a statement see
+// the same snapshot. Buckets are truncated in UTC, like coverage ranges.
+const rollUpSQL = `
+WITH locked AS (
+    SELECT pg_try_advisory_xact_lock(hashtext('event_rollups')) AS ok
+), fresh AS (
+    SELECT
+        date_trunc($1, event_time AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS bucket,
+        tenant_id,
+        event_name,
+        channel,
+        COALESCE(campaign_id, '') AS campaign_id,
+        ` + uniqueUserExpr + ` AS unique_user,
+        SUM(1 / sample_rate) AS weight
+    FROM events
+    WHERE event_time >= $2 AND event_time < $3 AND (SELECT ok FROM locked)
+    GROUP BY 1, 2, 3, 4, 5, 6
+), upserted AS (
+    INSERT INTO event_rollups (granularity, bucket, tenant_id, event_name, channel, campaign_id, unique_user, weight)
+    SELECT $1, bucket, tenant_id, event_name, channel, campaign_id, unique_user, weight FROM fresh
+    ON CONFLICT (granularity, tenant_id, event_name, bucket, channel, campaign_id, unique_user)
+    DO UPDATE SET weight = EXCLUDED.weight
+), cleared AS (
+    DELETE FROM event_rollups o
+    WHERE o.granularity = $1 AND o.bucket >= $2 AND o.bucket < $3 AND (SELECT ok FROM locked)
+      AND NOT EXISTS (
+        SELECT 1 FROM fresh n
+        WHERE n.bucket = o.bucket AND n.tenant_id = o.tenant_id AND n.event_name = o.event_name
+          AND n.channel = o.channel AND n.campaign_id = o.campaign_id AND n.unique_user = o.unique_user
+    )
+), progress AS (
+    INSERT INTO rollup_progress (granularity, rolled_from, rolled_until)
+    SELECT $1, $2, $3 WHERE (SELECT ok FROM locked)
+    ON CONFLICT (granularity) DO UPDATE
+    SET rolled_from = LEAST(rollup_progress.rolled_from, EXCLUDED.rolled_from),
+        rolled_until = GREATEST(rollup_progress.rolled_until, EXCLUDED.rolled_until)
+)
+SELECT ok FROM locked`
+
+// rollUpSketchesSQL is rollUpSQL for event_rollup_sketches: one row per
+// bucket and dimensions, with the users it counts as a HyperLogLog sketch.
+const rollUpSketchesSQL = `
+WITH locked AS (
+    SELECT pg_try_advisory_xact_lock(hashtext('event_rollup_sketches')) AS ok
+), fresh AS (
+    SELECT
+        date_trunc($1, event_time AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS bucket,
+        tenant_id,
+        event_name,
+        channel,
+        COALESCE(campaign_id, '') AS campaign_id,
+        hll_add_agg(hll_hash_text(` + uniqueUserExpr + `)) AS users,
+        SUM(1 / sample_rate) AS weight
+    FROM events
+    WHERE event_time >= $2 AND event_time < $3 AND (SELECT ok FROM locked)
+    GROUP BY 1, 2, 3, 4, 5
+), upserted AS (
+    INSERT INTO event_rollup_sketches (granularity, bucket, tenant_id, event_name, channel, campaign_id, users, weight)
+    SELECT $1, bucket, tenant_id, event_name, channel, campaign_id, users, weight FROM fresh
+    ON CONFLICT (granularity, tenant_id, event_name, bucket, channel, campaign_id)
+    DO UPDATE SET users = EXCLUDED.users, weight = EXCLUDED.weight
+), cleared AS (
+    DELETE FROM event_rollup_sketches o
+    WHERE o.granularity = $1 AND o.bucket >= $2 AND o.bucket < $3 AND (SELECT ok FROM locked)
+      AND NOT EXISTS (
+        SELECT 1 FROM fresh n
+        WHERE n.bucket = o.bucket AND n.tenant_id = o.tenant_id AND n.event_name = o.event_name
+          AND n.channel = o.channel AND n.campaign_id = o.campaign_id
+    )
+), progress AS (
+    INSERT INTO rollup_sketch_progress (granularity, rolled_from, rolled_until)
+    SELECT $1, $2, $3 WHERE (SELECT ok FROM locked)
+    ON CONFLICT (granularity) DO UPDATE
+    SET rolled_from = LEAST(rollup_sketch_progress.rolled_from, EXCLUDED.rolled_from),
+        rolled_until = GREATEST(rollup_sketch_progress.rolled_until, EXCLUDED.rolled_until)
+)
+SELECT ok FROM locked`
+
+const rollupCoverageSQL = `
+SELECT granularity, rolled_from, rolled_until FROM rollup_progress ORDER BY granularity`
+
+const sketchCoverageSQL = `
+SELECT granularity, rolled_from, rolled_until FROM rollup_sketch_progress ORDER BY granularity`
+
+// sketchUniqueUsersExpr estimates unique users by merging the sketches of
+// the rows counted.
+const sketchUniqueUsersExpr = `COALESCE(ROUND(hll_cardinality(hll_union_agg(users))), 0)::bigint`
+
+// sketched reports whether rollups are kept as sketches.
+func (r *MetricsRepository) sketched() bool {
+	return r.sketches && r.views == nil
+}
+
+func (r *MetricsRepository) RollUp(ctx context.Context, granularity string, from, until time.Time) (bool, error) {
+	query := rollUpSketchesSQL
+	if !r.sketched() {
+		if r.gate != nil && !r.gate.Writes(ColumnRollups) {
+			return false, errRollupsGated
+		}
+		query = rollUpSQL
+	}
+	if r.gate != nil && !r.gate.Reads(ColumnLinkTenantID) {
+		return false, errRollupLinksGated
+	}
+	rows, err := r.db.QueryContext(ctx, query, granularity, from.UTC(), until.UTC())
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	var locked bool
+	if rows.Next() {
+		if err := rows.Scan(&locked); err != nil {
+			return false, err
+		}
+	}
+	return locked, rows.Err()
+}
+
+func (r *MetricsRepository) RollupCoverage(ctx context.Context) ([]domain.RollupCoverage, error) {
+	query := rollupCoverageSQL
+	if r.sketched() {
+		query = sketchCoverageSQL
+	}
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.RollupCoverage
+	for rows.Next() {
+		var c domain.RollupCoverage
+		if err := rows.Scan(&c.Granularity, &c.From, &c.Until); err != nil {
+			return nil, err
+		}
+		c.From, c.Until = c.From.UTC(), c.Until.UTC()
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// rolledEventCountExpr counts events from rollups, whose weights already
+// stand for 1 / sample_rate events each.
+const rolledEventCountExpr = `ROUND(SUM(weight))::bigint`
+
+// rollupSource is the part of a query window answered from rollups: whole
+// buckets of each segment, which together cover [from, until). The events
+// outside it are read as they are.
+type rollupSource struct {
+	segments    []rollupSegment
+	from, until time.Time
+}
+
+type rollupSegment struct {
+	granularity string
+	from, until time.Time
+}
+
+// routed returns the repository to answer f with: a copy reading counters
+// or rollups where f allows it and rollups cover at least one bucket of its
+// window, or r.
+func (r *MetricsRepository) routed(f ports.MetricsFilter) *MetricsRepository {
+	if f.CountsOnly {
+		rr := *r
+		rr.countsOnly = true
+		if r.counters && r.reads(ColumnCounters) && countersEligible(f) {
+			rr.counted = true
+			return &rr
+		}
+		r = &rr
+	}
+	if r.rollups == nil || !r.rollupsReadable() || !rollupEligible(f) {
+		return r
+	}
+	// Sketches cannot tell the events of one user.
+	if r.sketched() && f.UserID != "" {
+		return r
+	}
+	src := r.planRollups(f)
+	if src == nil {
+		return r
+	}
+	rr := *r
+	rr.rolled = src
+	return &rr
+}
+
+// rollupsReadable requires the columns unique users and event counts of the
+// rollups were computed with, so both sources count alike. Sketches are
+// checked for once, at startup (see SketchesAvailable).
+func (r *MetricsRepository) rollupsReadable() bool {
+	columns := []string{ColumnAnonymousID, ColumnIdentityLinks, ColumnLinkTenantID, ColumnSampleRate}
+	if r.views == nil && !r.sketches {
+		columns = append(columns, ColumnRollups)
+	}
+	for _, c := range columns {
+		if !r.reads(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// rollupEligible reports whether f only needs what rollups keep: event
+// counts and unique users by name, time, tenant, channel and campaign.
+func rollupEligible(f ports.MetricsFilter) bool {
+	if f.Mode != domain.ModeCount || f.Aggregate != "" || len(f.Metadata) > 0 || len(f.Tags) > 0 || f.AsOf != nil {
+		return false
+	}
+	if f.GroupBy == "" {
+		return true
+	}
+	for _, dim := range domain.GroupByDimensions(f.GroupBy) {
+		switch dim {
+		case domain.DimensionChannel, domain.DimensionCampaign:
+		case "time":
+			if f.Interval == "minute" {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// planRollups covers the window of f with hourly buckets and, where whole
+// days fit in them, daily ones. Without hourly rollups, daily ones are used
+// alone. It returns nil if no bucket fits.
+func (r *MetricsRepository) planRollups(f ports.MetricsFilter) *rollupSource {
+	from, until := timeRange(f.From, f.To)
+	days := !groupsByTime(f) || f.Interval != domain.RollupHour
+
+	if hour, ok := r.rollupSpan(domain.RollupHour, from, until); ok {
+		src := &rollupSource{from: hour.from, until: hour.until}
+		day, ok := r.rollupSpan(domain.RollupDay, hour.from, hour.until)
+		if !days || !ok {
+			src.segments = []rollupSegment{hour}
+			return src
+		}
+		for _, s := range []rollupSegment{
+			{domain.RollupHour, hour.from, day.from},
+			day,
+			{domain.RollupHour, day.until, hour.until},
+		} {
+			if s.from.Before(s.until) {
+				src.segments = append(src.segments, s)
+			}
+		}
+		return src
+	}
+
+	if day, ok := r.rollupSpan(domain.RollupDay, from, until); ok && days {
+		return &rollupSource{segments: []rollupSegment{day}, from: day.from, until: day.until}
+	}
+	return nil
+}
+
+// groupsByTime reports whether f groups by time.
+func groupsByTime(f ports.MetricsFilter) bool {
+	for _, dim := range domain.GroupByDimensions(f.GroupBy) {
+		if dim == "time" {
+			return true
+		}
+	}
+	return false
+}
+
+// rollupSpan returns the covered buckets of granularity lying wholly within
+// [from, until).
+func (r *MetricsRepository) rollupSpan(granularity string, from, until time.Time) (rollupSegment, bool) {
+	c, ok := r.rollups(granularity)
+	if !ok {
+		return rollupSegment{}, false
+	}
+	length := domain.RollupLengths[granularity]
+	start := from.Truncate(length)
+	if start.Before(from) {
+		start = start.Add(length)
+	}
+	end := until.Truncate(length)
+	if start.Before(c.From) {
+		start = c.From
+	}
+	if end.After(c.Until) {
+		end = c.Until
+	}
+	return rollupSegment{granularity, start, end}, start.Before(end)
+}
+
+// source is what queries read events from: the events table, or rollup rows
+// within the planned segments and the events outside them, with the columns
+// the filters, groupings and the rolled-up expressions use. Rollup rows
+// stand at the start of their bucket. With sketches, every event outside
+// carries a sketch of its one user.
+func (r *MetricsRepository) source() string {
+	if r.counted {
+		return countersSource
+	}
+	if r.rolled == nil {
+		return "events"
+	}
+	users := uniqueUserExpr + ` AS unique_user`
+	if r.sketched() {
+		users = `hll_add(hll_empty(), hll_hash_text(` + uniqueUserExpr + `)) AS users`
+	}
+	return `(` + r.rolledRows() + `
+    UNION ALL
+    SELECT event_name, event_time, tenant_id, channel, campaign_id, ` + users + `, 1 / sample_rate AS weight
+    FROM events
+    WHERE event_time < ` + timestampLiteral(r.rolled.from) + ` OR event_time >= ` + timestampLiteral(r.rolled.until) + `
+) events`
+}
+
+// rolledRows selects the rollup rows of the planned segments, from
+// event_rollups, event_rollup_sketches or the rollup views.
+func (r *MetricsRepository) rolledRows() string {
+	if r.views != nil {
+		branches := make([]string, len(r.rolled.segments))
+		for i, s := range r.rolled.segments {
+			branches[i] = `
+    SELECT event_name, event_time, tenant_id, channel, campaign_id, ` + uniqueUserExpr + ` AS unique_user, weight
+    FROM (
+        SELECT bucket AS event_time, tenant_id, event_name, channel, campaign_id, user_id, anonymous_id, weight
+        FROM ` + r.views[s.granularity] + `
+    ) events
+    WHERE event_time >= ` + timestampLiteral(s.from) + ` AND event_time < ` + timestampLiteral(s.until)
+		}
+		return strings.Join(branches, "\n    UNION ALL")
+	}
+
+	conds := make([]string, len(r.rolled.segments))
+	for i, s := range r.rolled.segments {
+		conds[i] = "(granularity = '" + s.granularity + "' AND bucket >= " + timestampLiteral(s.from) + " AND bucket < " + timestampLiteral(s.until) + ")"
+	}
+	if r.sketched() {
+		return `
+    SELECT event_name, bucket AS event_time, tenant_id, channel, campaign_id, users, weight
+    FROM event_rollup_sketches
+    WHERE ` + strings.Join(conds, "\n       OR ")
+	}
+	return `
+    SELECT event_name, bucket AS event_time, tenant_id, channel, campaign_id, unique_user, weight
+    FROM event_rollups
+    WHERE ` + strings.Join(conds, "\n       OR ")
+}
+
+func timestampLiteral(t time.Time) string {
+	return "timestamptz '" + t.UTC().Format(time.RFC3339) + "'"
+}
+
+const sketchesAvailableSQL = `
+SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'hll')
+   AND to_regclass('event_rollup_sketches') IS NOT NULL
+   AND to_regclass('rollup_sketch_progress') IS NOT NULL`
+
+// SketchesAvailable reports whether the hll extension is installed and the
+// tables of migrations/hll exist.
+func SketchesAvailable(ctx context.Context, db DB) (bool, error) {
+	rows, err := db.QueryContext(ctx, sketchesAvailableSQL)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	var ok bool
+	if rows.Next() {
+		if err := rows.Scan(&ok); err != nil {
+			return false, err
+		}
+	}
+	return ok, rows.Err()
+}
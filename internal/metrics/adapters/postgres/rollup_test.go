@@ -0,0 +1,316 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/ports"
+)
+
+func TestMetricsRepository_RollUp(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return &fakeRowScanner{rows: []fakeRow{{values: []any{true}}}}, nil
+		},
+	}
+	from := time.Date(2025, 12, 5, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 12, 7, 0, 0, 0, 0, time.UTC)
+
+	locked, err := NewMetricsRepository(db).RollUp(context.Background(), domain.RollupHour, from, until)
+	if err != nil || !locked {
+		t.Fatalf("expected to roll up, got locked=%v err=%v", locked, err)
+	}
+	for _, want := range []string{
+		"pg_try_advisory_xact_lock(hashtext('event_rollups'))",
+		"date_trunc($1, event_time AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS bucket",
+		"identity_links",
+		"SUM(1 / sample_rate) AS weight",
+		"WHERE event_time >= $2 AND event_time < $3",
+		"DO UPDATE SET weight = EXCLUDED.weight",
+		"DELETE FROM event_rollups",
+		"rolled_until = GREATEST(rollup_progress.rolled_until, EXCLUDED.rolled_until)",
+	} {
+		if !strings.Contains(db.lastQuery, want) {
+			t.Fatalf("query missing %q: %s", want, db.lastQuery)
+		}
+	}
+	if db.lastArgs[0] != "hour" || db.lastArgs[1] != from || db.lastArgs[2] != until {
+		t.Fatalf("unexpected args: %v", db.lastArgs)
+	}
+}
+
+func TestMetricsRepository_RollUpFollowsColumnGate(t *testing.T) {
+	db := &fakeDB{}
+	repo := NewMetricsRepository(db, WithColumnGate(fakeColumnGate{}))
+
+	_, err := repo.RollUp(context.Background(), domain.RollupHour, time.Unix(0, 0), time.Unix(3600, 0))
+	if !errors.Is(err, errRollupsGated) {
+		t.Fatalf("expected errRollupsGated, got %v", err)
+	}
+	if db.called {
+		t.Fatalf("expected no query while the rollups are gated")
+	}
+}
+
+func TestMetricsRepository_RollUpWaitsForLinkTenants(t *testing.T) {
+	db := &fakeDB{}
+	repo := NewMetricsRepository(db, WithColumnGate(fakeColumnGate{ColumnRollups: true}))
+
+	_, err := repo.RollUp(context.Background(), domain.RollupHour, time.Unix(0, 0), time.Unix(3600, 0))
+	if !errors.Is(err, errRollupLinksGated) {
+		t.Fatalf("expected errRollupLinksGated, got %v", err)
+	}
+	if db.called {
+		t.Fatalf("expected no query while identity links have no tenant")
+	}
+}
+
+func TestMetricsRepository_RollUpSketches(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if strings.Contains(query, "SELECT granularity, rolled_from, rolled_until") {
+				return &fakeRowScanner{}, nil
+			}
+			return &fakeRowScanner{rows: []fakeRow{{values: []any{true}}}}, nil
+		},
+	}
+	// Sketches have no column gate of their own: they are checked for at
+	// startup.
+	repo := NewMetricsRepository(db, WithColumnGate(fakeColumnGate{ColumnLinkTenantID: true}), WithRollupSketches())
+
+	locked, err := repo.RollUp(context.Background(), domain.RollupDay, time.Unix(0, 0), time.Unix(86400, 0))
+	if err != nil || !locked {
+		t.Fatalf("expected to roll up, got locked=%v err=%v", locked, err)
+	}
+	for _, want := range []string{
+		"hll_add_agg(hll_hash_text(CASE",
+		"GROUP BY 1, 2, 3, 4, 5\n",
+		"INSERT INTO event_rollup_sketches",
+		"DO UPDATE SET users = EXCLUDED.users, weight = EXCLUDED.weight",
+		"INSERT INTO rollup_sketch_progress",
+	} {
+		if !strings.Contains(db.lastQuery, want) {
+			t.Fatalf("query missing %q: %s", want, db.lastQuery)
+		}
+	}
+
+	if _, err := repo.RollupCoverage(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(db.lastQuery, "FROM rollup_sketch_progress") {
+		t.Fatalf("expected sketch coverage, got: %s", db.lastQuery)
+	}
+}
+
+func TestMetricsRepository_RollupCoverage(t *testing.T) {
+	from := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 12, 7, 0, 0, 0, 0, time.UTC)
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return &fakeRowScanner{rows: []fakeRow{
+				{values: []any{"day", from, until}},
+				{values: []any{"hour", from, until}},
+			}}, nil
+		},
+	}
+
+	got, err := NewMetricsRepository(db).RollupCoverage(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[1] != (domain.RollupCoverage{Granularity: "hour", From: from, Until: until}) {
+		t.Fatalf("unexpected coverage: %+v", got)
+	}
+}
+
+// rolledUp reports hourly and daily rollups for December 1st to 7th 2025.
+func rolledUp(granularity string) (domain.RollupCoverage, bool) {
+	return domain.RollupCoverage{
+		Granularity: granularity,
+		From:        time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+		Until:       time.Date(2025, 12, 7, 0, 0, 0, 0, time.UTC),
+	}, true
+}
+
+func TestMetricsRepository_RoutesToRollups(t *testing.T) {
+	var queries []string
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			queries = append(queries, query)
+			if rows, ok := overallRows(query, 200, 70); ok {
+				return rows, nil
+			}
+			return &fakeRowScanner{rows: []fakeRow{{values: []any{"web", int64(200), int64(70)}}}}, nil
+		},
+	}
+	repo := NewMetricsRepository(db, WithRollups(rolledUp))
+
+	filter := ports.MetricsFilter{
+		EventName: "purchase",
+		From:      time.Date(2025, 12, 3, 10, 30, 0, 0, time.UTC).Unix(),
+		To:        time.Date(2025, 12, 6, 15, 15, 0, 0, time.UTC).Unix(),
+		GroupBy:   "channel",
+	}
+	res, err := repo.QueryMetrics(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.TotalCount != 200 || res.UniqueUsers != 70 || len(res.Groups) != 1 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	// Whole hours at the edges, whole days in between, and the events
+	// outside the whole hours.
+	for _, q := range queries {
+		for _, want := range []string{
+			"(granularity = 'hour' AND bucket >= timestamptz '2025-12-03T11:00:00Z' AND bucket < timestamptz '2025-12-04T00:00:00Z')",
+			"(granularity = 'day' AND bucket >= timestamptz '2025-12-04T00:00:00Z' AND bucket < timestamptz '2025-12-06T00:00:00Z')",
+			"(granularity = 'hour' AND bucket >= timestamptz '2025-12-06T00:00:00Z' AND bucket < timestamptz '2025-12-06T15:00:00Z')",
+			"WHERE event_time < timestamptz '2025-12-03T11:00:00Z' OR event_time >= timestamptz '2025-12-06T15:00:00Z'\n) events",
+			"ROUND(SUM(weight))::bigint",
+			"COUNT(DISTINCT unique_user)",
+		} {
+			if !strings.Contains(q, want) {
+				t.Fatalf("query missing %q: %s", want, q)
+			}
+		}
+	}
+}
+
+func TestMetricsRepository_RoutesToRollupSketches(t *testing.T) {
+	var queries []string
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			queries = append(queries, query)
+			if rows, ok := overallRows(query, 200, 70); ok {
+				return rows, nil
+			}
+			return &fakeRowScanner{rows: []fakeRow{{values: []any{"web", int64(200), int64(70)}}}}, nil
+		},
+	}
+	repo := NewMetricsRepository(db, WithRollups(rolledUp), WithRollupSketches())
+
+	filter := ports.MetricsFilter{
+		EventName: "purchase",
+		From:      time.Date(2025, 12, 2, 0, 0, 0, 0, time.UTC).Unix(),
+		To:        time.Date(2025, 12, 5, 0, 0, 0, 0, time.UTC).Unix(),
+		GroupBy:   "channel",
+	}
+	res, err := repo.QueryMetrics(context.Background(), filter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.UniqueUsers != 70 || len(res.Groups) != 1 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	for _, q := range queries {
+		for _, want := range []string{
+			"FROM event_rollup_sketches",
+			"hll_add(hll_empty(), hll_hash_text(CASE",
+			sketchUniqueUsersExpr + " AS unique_users",
+		} {
+			if !strings.Contains(q, want) {
+				t.Fatalf("query missing %q: %s", want, q)
+			}
+		}
+		if strings.Contains(q, "COUNT(DISTINCT") {
+			t.Fatalf("expected no exact unique users: %s", q)
+		}
+	}
+
+	// The events of one user cannot be told apart in a sketch.
+	queries = nil
+	filter.UserID = "u1"
+	if _, err := repo.QueryMetrics(context.Background(), filter); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, q := range queries {
+		if strings.Contains(q, "event_rollup_sketches") {
+			t.Fatalf("expected a user's query to read events: %s", q)
+		}
+	}
+}
+
+func TestSketchesAvailable(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return &fakeRowScanner{rows: []fakeRow{{values: []any{true}}}}, nil
+		},
+	}
+	ok, err := SketchesAvailable(context.Background(), db)
+	if err != nil || !ok {
+		t.Fatalf("expected sketches available, got ok=%v err=%v", ok, err)
+	}
+	if !strings.Contains(db.lastQuery, "extname = 'hll'") || !strings.Contains(db.lastQuery, "to_regclass('event_rollup_sketches')") {
+		t.Fatalf("unexpected query: %s", db.lastQuery)
+	}
+}
+
+func TestMetricsRepository_RollupsOnlyForEligibleQueries(t *testing.T) {
+	asOf := time.Date(2025, 12, 6, 0, 0, 0, 0, time.UTC)
+	from := time.Date(2025, 12, 2, 0, 0, 0, 0, time.UTC).Unix()
+	to := time.Date(2025, 12, 5, 0, 0, 0, 0, time.UTC).Unix()
+
+	tests := []struct {
+		name   string
+		filter ports.MetricsFilter
+		gate   ColumnGate
+		want   string // "" = events only
+	}{
+		{"no group", ports.MetricsFilter{}, nil, "granularity = 'day'"},
+		{"campaign and daily time", ports.MetricsFilter{GroupBy: "campaign_id,time", Interval: "day"}, nil, "granularity = 'day'"},
+		{"hourly time", ports.MetricsFilter{GroupBy: "time", Interval: "hour"}, nil, "(granularity = 'hour' AND bucket >= timestamptz '2025-12-02T00:00:00Z' AND bucket < timestamptz '2025-12-05T00:00:00Z')\n"},
+		{"user filter", ports.MetricsFilter{UserID: "u1"}, nil, "(unique_user) = $4"},
+		{"minutely time", ports.MetricsFilter{GroupBy: "time", Interval: "minute"}, nil, ""},
+		{"user_id group", ports.MetricsFilter{GroupBy: "user_id"}, nil, ""},
+		{"metadata", ports.MetricsFilter{Metadata: map[string]string{"plan": "pro"}}, nil, ""},
+		{"tags", ports.MetricsFilter{Tags: []string{"vip"}}, nil, ""},
+		{"aggregate", ports.MetricsFilter{Aggregate: "sum", Field: "metadata.amount"}, nil, ""},
+		{"as of", ports.MetricsFilter{AsOf: &asOf}, nil, ""},
+		{"gated", ports.MetricsFilter{}, fakeColumnGate{ColumnAnonymousID: true, ColumnIdentityLinks: true, ColumnLinkTenantID: true, ColumnSampleRate: true}, ""},
+		{"outside the coverage", ports.MetricsFilter{From: time.Date(2025, 12, 8, 0, 0, 0, 0, time.UTC).Unix()}, nil, ""},
+		{"less than an hour", ports.MetricsFilter{To: from + 1799}, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeDB{
+				QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+					return &fakeRowScanner{}, nil
+				},
+			}
+			opts := []RepositoryOption{WithRollups(rolledUp)}
+			if tt.gate != nil {
+				opts = append(opts, WithColumnGate(tt.gate))
+			}
+
+			f := tt.filter
+			f.EventName = "purchase"
+			if f.From == 0 {
+				f.From = from
+			}
+			if f.To == 0 {
+				f.To = to
+			}
+			if f.From > f.To {
+				f.To = f.From + 86400
+			}
+			if _, err := NewMetricsRepository(db, opts...).QueryMetrics(context.Background(), f); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.want == "" {
+				if strings.Contains(db.lastQuery, "event_rollups") {
+					t.Fatalf("expected events only: %s", db.lastQuery)
+				}
+				return
+			}
+			if !strings.Contains(db.lastQuery, tt.want) {
+				t.Fatalf("query missing %q: %s", tt.want, db.lastQuery)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package slo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+)
+
+// Operation names reported to the SLO tracker.
+const (
+	OperationQueryMetrics      = "query_metrics"
+	OperationQueryMetricsBatch = "query_metrics_batch"
+)
+
+// Outcome labels understood by the SLO tracker.
+const (
+	outcomeOK       = "ok"
+	outcomeRejected = "rejected"
+	outcomeError    = "error"
+)
+
+// Recorder is satisfied by the SLO tracker.
+type Recorder interface {
+	Observe(ctx context.Context, operation, outcome string, d time.Duration)
+}
+
+type GetMetricsUseCase interface {
+	Execute(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error)
+	ExecuteBatch(ctx context.Context, in usecase.BatchGetMetricsInput) (*usecase.BatchMetricsResult, error)
+	ValidateStream(in usecase.GetMetricsInput) error
+	ExecuteStream(
+		ctx context.Context,
+		in usecase.GetMetricsInput,
+		head func(*domain.AggregatedMetrics) error,
+		emit func([]domain.MetricsGroup) error,
+	) error
+}
+
+// GetMetrics decorates the metrics use case with SLI measurement.
+type GetMetrics struct {
+	next     GetMetricsUseCase
+	recorder Recorder
+	now      func() time.Time
+}
+
+func NewGetMetrics(next GetMetricsUseCase, recorder Recorder) *GetMetrics {
+	return &GetMetrics{next: next, recorder: recorder, now: time.Now}
+}
+
+func (g *GetMetrics) Execute(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+	start := g.now()
+	res, err := g.next.Execute(ctx, in)
+	g.recorder.Observe(ctx, OperationQueryMetrics, outcome(err), g.now().Sub(start))
+	return res, err
+}
+
+func (g *GetMetrics) ExecuteBatch(ctx context.Context, in usecase.BatchGetMetricsInput) (*usecase.BatchMetricsResult, error) {
+	start := g.now()
+	res, err := g.next.ExecuteBatch(ctx, in)
+	g.recorder.Observe(ctx, OperationQueryMetricsBatch, outcome(err), g.now().Sub(start))
+	return res, err
+}
+
+func (g *GetMetrics) ValidateStream(in usecase.GetMetricsInput) error {
+	return g.next.ValidateStream(in)
+}
+
+// ExecuteStream is not measured: a stream lasts as long as the client takes
+// to read it, so its duration says little about the service.
+func (g *GetMetrics) ExecuteStream(
+	ctx context.Context,
+	in usecase.GetMetricsInput,
+	head func(*domain.AggregatedMetrics) error,
+	emit func([]domain.MetricsGroup) error,
+) error {
+	return g.next.ExecuteStream(ctx, in, head, emit)
+}
+
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return outcomeOK
+	case errors.Is(err, usecase.ErrInvalidMetricsQuery),
+		errors.Is(err, usecase.ErrInvalidTimeRange),
+		errors.Is(err, usecase.ErrInvalidGroupBy),
+		errors.Is(err, usecase.ErrInvalidInterval),
+		errors.Is(err, usecase.ErrInvalidMode),
+		errors.Is(err, usecase.ErrInvalidHistogram),
+		errors.Is(err, usecase.ErrInvalidMetadata),
+		errors.Is(err, usecase.ErrInvalidTags),
+		errors.Is(err, usecase.ErrInvalidAggregate),
+		errors.Is(err, usecase.ErrInvalidTop),
+		errors.Is(err, usecase.ErrInvalidCompare),
+		errors.Is(err, usecase.ErrInvalidPage),
+		errors.Is(err, usecase.ErrInvalidOrder),
+		errors.Is(err, usecase.ErrQueryRangeTooLarge),
+		errors.Is(err, domain.ErrQueryTooExpensive),
+		errors.Is(err, usecase.ErrInvalidBatch),
+		errors.Is(err, usecase.ErrInvalidStream),
+		errors.Is(err, usecase.ErrWatermarkDisabled),
+		errors.Is(err, usecase.ErrStreamDisabled):
+		return outcomeRejected
+	default:
+		return outcomeError
+	}
+}
@@ -0,0 +1,62 @@
+package slo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+)
+
+type fakeRecorder struct {
+	operations []string
+	outcomes   []string
+}
+
+func (f *fakeRecorder) Observe(ctx context.Context, operation, outcome string, d time.Duration) {
+	f.operations = append(f.operations, operation)
+	f.outcomes = append(f.outcomes, outcome)
+}
+
+type fakeGetMetricsUseCase struct {
+	err error
+}
+
+func (f *fakeGetMetricsUseCase) Execute(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+	return &domain.AggregatedMetrics{}, f.err
+}
+
+func (f *fakeGetMetricsUseCase) ExecuteBatch(ctx context.Context, in usecase.BatchGetMetricsInput) (*usecase.BatchMetricsResult, error) {
+	return &usecase.BatchMetricsResult{}, f.err
+}
+
+func (f *fakeGetMetricsUseCase) ValidateStream(in usecase.GetMetricsInput) error {
+	return f.err
+}
+
+func (f *fakeGetMetricsUseCase) ExecuteStream(
+	ctx context.Context,
+	in usecase.GetMetricsInput,
+	head func(*domain.AggregatedMetrics) error,
+	emit func([]domain.MetricsGroup) error,
+) error {
+	return f.err
+}
+
+func TestGetMetrics_RecordsOutcomes(t *testing.T) {
+	rec := &fakeRecorder{}
+
+	_, _ = NewGetMetrics(&fakeGetMetricsUseCase{}, rec).Execute(context.Background(), usecase.GetMetricsInput{})
+	_, _ = NewGetMetrics(&fakeGetMetricsUseCase{err: usecase.ErrInvalidGroupBy}, rec).Execute(context.Background(), usecase.GetMetricsInput{})
+	_, _ = NewGetMetrics(&fakeGetMetricsUseCase{err: errors.New("timeout")}, rec).ExecuteBatch(context.Background(), usecase.BatchGetMetricsInput{})
+
+	wantOps := []string{OperationQueryMetrics, OperationQueryMetrics, OperationQueryMetricsBatch}
+	wantOutcomes := []string{outcomeOK, outcomeRejected, outcomeError}
+	for i := range wantOps {
+		if rec.operations[i] != wantOps[i] || rec.outcomes[i] != wantOutcomes[i] {
+			t.Fatalf("observation %d: got %s/%s", i, rec.operations[i], rec.outcomes[i])
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package timescale
+
+import (
+	"context"
+	"time"
+
+	"event-metrics-service/internal/metrics/adapters/postgres"
+	"event-metrics-service/internal/metrics/core/domain"
+
+	"github.com/lib/pq"
+)
+
+// Continuous aggregates of migrations/timescale, by rollup granularity.
+var Views = map[string]string{
+	domain.RollupHour: "event_counts_hourly",
+	domain.RollupDay:  "event_counts_daily",
+}
+
+const extensionSQL = `SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'timescaledb')`
+
+// Views without real-time aggregation miss the buckets not materialized yet.
+const viewsSQL = `
+SELECT count(*) FROM timescaledb_information.continuous_aggregates
+WHERE view_name = ANY($1) AND NOT materialized_only`
+
+// Available reports whether the timescaledb extension is installed and every
+// view exists as a real-time continuous aggregate.
+func Available(ctx context.Context, db postgres.DB) (bool, error) {
+	var installed bool
+	if err := scanOne(ctx, db, &installed, extensionSQL); err != nil || !installed {
+		return false, err
+	}
+
+	names := make([]string, 0, len(Views))
+	for _, v := range Views {
+		names = append(names, v)
+	}
+	var n int64
+	if err := scanOne(ctx, db, &n, viewsSQL, pq.Array(names)); err != nil {
+		return false, err
+	}
+	return n == int64(len(Views)), nil
+}
+
+// coverage covers every bucket: real-time aggregation answers the buckets
+// the refresh policy has not materialized yet from the events.
+func coverage(granularity string) (domain.RollupCoverage, bool) {
+	if _, ok := Views[granularity]; !ok {
+		return domain.RollupCoverage{}, false
+	}
+	return domain.RollupCoverage{
+		Granularity: granularity,
+		From:        time.Unix(0, 0).UTC(),
+		Until:       time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, true
+}
+
+// NewMetricsRepository returns the Postgres metrics repository answering the
+// whole hours and days of eligible queries (see postgres.WithRollups) from
+// the continuous aggregates. Everything else reads events as usual.
+func NewMetricsRepository(db postgres.DB, opts ...postgres.RepositoryOption) *postgres.MetricsRepository {
+	opts = append(opts, postgres.WithRollups(coverage), postgres.WithRollupViews(Views))
+	return postgres.NewMetricsRepository(db, opts...)
+}
+
+// scanOne scans the single column of the first row of query into dest.
+func scanOne(ctx context.Context, db postgres.DB, dest any, query string, args ...any) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(dest); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,128 @@
+package timescale
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/metrics/adapters/postgres"
+	"event-metrics-service/internal/metrics/core/ports"
+)
+
+type fakeRows struct {
+	values []any
+	done   bool
+}
+
+func (r *fakeRows) Next() bool {
+	if r.done || r.values == nil {
+		return false
+	}
+	r.done = true
+	return true
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	for i, d := range dest {
+		switch d := d.(type) {
+		case *bool:
+			*d = r.values[i].(bool)
+		case *int64:
+			*d = r.values[i].(int64)
+		default:
+			return errors.New("unsupported dest type")
+		}
+	}
+	return nil
+}
+
+func (r *fakeRows) Err() error   { return nil }
+func (r *fakeRows) Close() error { return nil }
+
+type fakeDB struct {
+	installed bool
+	views     int64
+	queries   []string
+}
+
+func (f *fakeDB) QueryContext(ctx context.Context, query string, args ...any) (postgres.RowScanner, error) {
+	f.queries = append(f.queries, query)
+	switch {
+	case strings.Contains(query, "pg_extension"):
+		return &fakeRows{values: []any{f.installed}}, nil
+	case strings.Contains(query, "timescaledb_information"):
+		if !f.installed {
+			return nil, errors.New(`schema "timescaledb_information" does not exist`)
+		}
+		return &fakeRows{values: []any{f.views}}, nil
+	}
+	return &fakeRows{}, nil
+}
+
+func (f *fakeDB) BeginTx(ctx context.Context) (postgres.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestAvailable(t *testing.T) {
+	tests := []struct {
+		name string
+		db   *fakeDB
+		want bool
+	}{
+		{"no extension", &fakeDB{}, false},
+		{"missing view", &fakeDB{installed: true, views: 1}, false},
+		{"both views", &fakeDB{installed: true, views: 2}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Available(context.Background(), tt.db)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMetricsRepository_ReadsContinuousAggregates(t *testing.T) {
+	db := &fakeDB{}
+	repo := NewMetricsRepository(db)
+
+	filter := ports.MetricsFilter{
+		EventName: "purchase",
+		From:      time.Date(2025, 12, 3, 10, 30, 0, 0, time.UTC).Unix(),
+		To:        time.Date(2025, 12, 6, 15, 15, 0, 0, time.UTC).Unix(),
+	}
+	if _, err := repo.QueryMetrics(context.Background(), filter); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	query := db.queries[len(db.queries)-1]
+	for _, want := range []string{
+		"FROM event_counts_hourly\n    ) events\n    WHERE event_time >= timestamptz '2025-12-03T11:00:00Z' AND event_time < timestamptz '2025-12-04T00:00:00Z'",
+		"FROM event_counts_daily\n    ) events\n    WHERE event_time >= timestamptz '2025-12-04T00:00:00Z' AND event_time < timestamptz '2025-12-06T00:00:00Z'",
+		"FROM event_counts_hourly\n    ) events\n    WHERE event_time >= timestamptz '2025-12-06T00:00:00Z' AND event_time < timestamptz '2025-12-06T15:00:00Z'",
+		"identity_links",
+		"ROUND(SUM(weight))::bigint",
+	} {
+		if !strings.Contains(query, want) {
+			t.Fatalf("query missing %q: %s", want, query)
+		}
+	}
+	if strings.Contains(query, "event_rollups") {
+		t.Fatalf("expected the rollup table not to be read: %s", query)
+	}
+
+	// Metadata filters are not in the aggregates.
+	filter.Metadata = map[string]string{"plan": "pro"}
+	if _, err := repo.QueryMetrics(context.Background(), filter); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query := db.queries[len(db.queries)-1]; strings.Contains(query, "event_counts") {
+		t.Fatalf("expected events only: %s", query)
+	}
+}
@@ -0,0 +1,103 @@
+package warmup
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+)
+
+// DefaultRange is the lookback of a saved query without range=.
+const DefaultRange = 24 * time.Hour
+
+type GetMetricsUseCase interface {
+	Execute(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error)
+}
+
+// SavedQuery is a GET /metrics query string with a relative time range
+// instead of from/to, e.g.
+//
+//	event_name=purchase&group_by=channel&range=24h
+//
+// Supported keys: event_name (required), range, channel, group_by, interval,
+// aggregate, field, mode (counts or lag) and metadata.<key>.
+type SavedQuery struct {
+	Raw   string
+	Range time.Duration
+	Input usecase.GetMetricsInput
+}
+
+func ParseSavedQuery(raw string) (SavedQuery, error) {
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return SavedQuery{}, fmt.Errorf("saved query %q: %w", raw, err)
+	}
+
+	q := SavedQuery{Raw: raw, Range: DefaultRange}
+	for key, vs := range values {
+		v := vs[len(vs)-1]
+		switch {
+		case key == "event_name":
+			q.Input.EventName = v
+		case key == "range":
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				return SavedQuery{}, fmt.Errorf("saved query %q: invalid range %q", raw, v)
+			}
+			q.Range = d
+		case key == "channel":
+			channel := v
+			q.Input.Channel = &channel
+		case key == "group_by":
+			q.Input.GroupBy = v
+		case key == "interval":
+			q.Input.Interval = v
+		case key == "aggregate":
+			q.Input.Aggregate = v
+		case key == "field":
+			q.Input.Field = v
+		case key == "mode":
+			if v != domain.ModeCount && v != domain.ModeLag {
+				return SavedQuery{}, fmt.Errorf("saved query %q: unsupported mode %q", raw, v)
+			}
+			q.Input.Mode = v
+		case strings.HasPrefix(key, domain.MetadataGroupPrefix):
+			if q.Input.Metadata == nil {
+				q.Input.Metadata = map[string]string{}
+			}
+			q.Input.Metadata[strings.TrimPrefix(key, domain.MetadataGroupPrefix)] = v
+		default:
+			return SavedQuery{}, fmt.Errorf("saved query %q: unknown parameter %q", raw, key)
+		}
+	}
+	if q.Input.EventName == "" {
+		return SavedQuery{}, fmt.Errorf("saved query %q: event_name is required", raw)
+	}
+	return q, nil
+}
+
+// QueryWarmer executes a saved query so its plan and the pages it touches are
+// cached before real traffic arrives. The result is discarded.
+type QueryWarmer struct {
+	uc    GetMetricsUseCase
+	query SavedQuery
+	now   func() time.Time
+}
+
+func NewQueryWarmer(uc GetMetricsUseCase, query SavedQuery) *QueryWarmer {
+	return &QueryWarmer{uc: uc, query: query, now: time.Now}
+}
+
+func (w *QueryWarmer) Warm(ctx context.Context) error {
+	in := w.query.Input
+	to := w.now()
+	in.From = to.Add(-w.query.Range).Unix()
+	in.To = to.Unix()
+
+	_, err := w.uc.Execute(ctx, in)
+	return err
+}
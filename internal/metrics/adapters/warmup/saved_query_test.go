@@ -0,0 +1,83 @@
+package warmup
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+)
+
+type fakeGetMetricsUseCase struct {
+	ExecuteFn func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error)
+}
+
+func (f *fakeGetMetricsUseCase) Execute(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+	return f.ExecuteFn(ctx, in)
+}
+
+func TestParseSavedQuery(t *testing.T) {
+	q, err := ParseSavedQuery("event_name=purchase&group_by=channel&range=1h&channel=web&metadata.plan=pro")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if q.Range != time.Hour || q.Input.EventName != "purchase" || q.Input.GroupBy != "channel" {
+		t.Fatalf("unexpected query: %+v", q)
+	}
+	if q.Input.Channel == nil || *q.Input.Channel != "web" || q.Input.Metadata["plan"] != "pro" {
+		t.Fatalf("unexpected filters: %+v", q.Input)
+	}
+
+	q, err = ParseSavedQuery("event_name=purchase&aggregate=sum&field=metadata.revenue")
+	if err != nil || q.Input.Aggregate != "sum" || q.Input.Field != "metadata.revenue" {
+		t.Fatalf("unexpected aggregate: %+v (%v)", q.Input, err)
+	}
+
+	q, err = ParseSavedQuery("event_name=signup")
+	if err != nil || q.Range != DefaultRange {
+		t.Fatalf("expected default range, got %v (%v)", q.Range, err)
+	}
+}
+
+func TestParseSavedQuery_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"group_by=channel":                    "event_name is required",
+		"event_name=purchase&range=-1h":       "invalid range",
+		"event_name=purchase&mode=histogram":  "unsupported mode",
+		"event_name=purchase&from=1733572800": "unknown parameter",
+		"event_name=purchase&range=yesterday": "invalid range",
+	}
+
+	for raw, want := range tests {
+		if _, err := ParseSavedQuery(raw); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%s: expected %q, got %v", raw, want, err)
+		}
+	}
+}
+
+func TestQueryWarmer_ResolvesRangeAtWarmTime(t *testing.T) {
+	now := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	q, err := ParseSavedQuery("event_name=purchase&range=2h")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got usecase.GetMetricsInput
+	uc := &fakeGetMetricsUseCase{
+		ExecuteFn: func(ctx context.Context, in usecase.GetMetricsInput) (*domain.AggregatedMetrics, error) {
+			got = in
+			return &domain.AggregatedMetrics{}, nil
+		},
+	}
+	w := NewQueryWarmer(uc, q)
+	w.now = func() time.Time { return now }
+
+	if err := w.Warm(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.EventName != "purchase" || got.From != now.Add(-2*time.Hour).Unix() || got.To != now.Unix() {
+		t.Fatalf("unexpected input: %+v", got)
+	}
+}
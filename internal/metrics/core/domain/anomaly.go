@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// AnomalySeason is the cycle the baseline of a bucket is taken from: the
+// same hour or day of the previous weeks.
+const AnomalySeason = 7 * 24 * time.Hour
+
+// Anomaly directions.
+const (
+	AnomalySpike = "spike"
+	AnomalyDrop  = "drop"
+)
+
+// AnomalyPoint is one time bucket of an event and its seasonal baseline.
+type AnomalyPoint struct {
+	Bucket    time.Time
+	Count     int64
+	Expected  float64 // mean count of the same bucket in the previous seasons
+	StdDev    float64 // of those counts, at least √Expected
+	ZScore    float64 // (Count - Expected) / StdDev
+	Direction string  // "spike" / "drop" when |ZScore| reaches the threshold, else ""
+}
+
+type AnomalyReport struct {
+	EventName string
+	From      int64
+	To        int64
+	Interval  string // "hour" / "day"
+	Seasons   int
+	Threshold float64
+	Points    []AnomalyPoint // every bucket from From to To, empty ones included
+}
+
+// Anomalies returns the flagged points.
+func (r AnomalyReport) Anomalies() []AnomalyPoint {
+	var out []AnomalyPoint
+	for _, p := range r.Points {
+		if p.Direction != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// ScoreAnomaly compares count with the counts of the same bucket in earlier
+// seasons. The deviation is floored at the Poisson noise √mean (and 1), so a
+// series that was flat so far is not flagged for a handful of events.
+func ScoreAnomaly(count int64, baseline []int64, threshold float64) AnomalyPoint {
+	p := AnomalyPoint{Count: count}
+	if len(baseline) == 0 {
+		return p
+	}
+
+	var sum float64
+	for _, b := range baseline {
+		sum += float64(b)
+	}
+	p.Expected = sum / float64(len(baseline))
+
+	var variance float64
+	if len(baseline) > 1 {
+		for _, b := range baseline {
+			d := float64(b) - p.Expected
+			variance += d * d
+		}
+		variance /= float64(len(baseline) - 1)
+	}
+	p.StdDev = math.Max(math.Sqrt(variance), math.Sqrt(math.Max(p.Expected, 1)))
+	p.ZScore = (float64(count) - p.Expected) / p.StdDev
+
+	switch {
+	case p.ZScore >= threshold:
+		p.Direction = AnomalySpike
+	case p.ZScore <= -threshold:
+		p.Direction = AnomalyDrop
+	}
+	return p
+}
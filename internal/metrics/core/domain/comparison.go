@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// Comparison windows.
+const (
+	ComparePreviousPeriod = "previous_period"
+	ComparePreviousYear   = "previous_year"
+)
+
+// Comparison is the same query run over an earlier window.
+type Comparison struct {
+	Compare  string
+	Previous *AggregatedMetrics
+	Change   Changes // current figures against Previous
+}
+
+// Changes compares the figures of a result or group with the previous ones.
+type Changes struct {
+	TotalCount  Change
+	UniqueUsers Change
+	Value       *Change // when both sides have an aggregate value
+}
+
+// Change is the difference to a previous figure.
+type Change struct {
+	Delta   float64
+	Percent *float64 // nil when the previous figure is 0
+}
+
+// NewChange returns the change from previous to current.
+func NewChange(previous, current float64) Change {
+	c := Change{Delta: current - previous}
+	if previous != 0 {
+		p := c.Delta / previous * 100
+		c.Percent = &p
+	}
+	return c
+}
+
+// CompareFigures compares current with previous; previous may be the zero
+// group when the previous window had no such group.
+func CompareFigures(previous, current MetricsGroup) Changes {
+	c := Changes{
+		TotalCount:  NewChange(float64(previous.TotalCount), float64(current.TotalCount)),
+		UniqueUsers: NewChange(float64(previous.UniqueUsers), float64(current.UniqueUsers)),
+	}
+	if previous.Value != nil && current.Value != nil {
+		v := NewChange(*previous.Value, *current.Value)
+		c.Value = &v
+	}
+	return c
+}
+
+// ComparisonWindow returns the window compare refers to for [from, to]. The
+// previous period is as long as the range and ends right before it.
+func ComparisonWindow(compare string, from, to int64) (int64, int64) {
+	if compare == ComparePreviousYear {
+		return time.Unix(from, 0).UTC().AddDate(-1, 0, 0).Unix(), time.Unix(to, 0).UTC().AddDate(-1, 0, 0).Unix()
+	}
+	return from - (to - from) - 1, from - 1
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// Groupable dimensions besides "metadata.<key>" and "time".
+const (
+	DimensionChannel  = "channel"
+	DimensionCampaign = "campaign_id"
+	DimensionUser     = "user_id"
+
+	// Calendar dimensions group events by when in the day or week they
+	// happened, whatever the date, in UTC: hours "00" to "23" and ISO days
+	// "1" (Monday) to "7" (Sunday).
+	DimensionHourOfDay = "hour_of_day"
+	DimensionDayOfWeek = "day_of_week"
+)
+
+// DimensionValue is one distinct value of a dimension and how many events
+// carried it in the lookback window.
+type DimensionValue struct {
+	Value string
+	Count int64
+}
+
+// DimensionValues is the cached top-N list of a dimension.
+type DimensionValues struct {
+	Dimension   string
+	Values      []DimensionValue // most frequent first
+	RefreshedAt time.Time
+}
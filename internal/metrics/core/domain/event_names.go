@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// EventName is one event name seen in a time range: how many events carried
+// it and when the first and last of them happened.
+type EventName struct {
+	Name      string
+	Count     int64
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
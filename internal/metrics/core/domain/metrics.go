@@ -1,5 +1,16 @@
 package domain
 
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ErrQueryTooExpensive is returned by readers refusing a query the
+// database estimates too costly to run.
+var ErrQueryTooExpensive = errors.New("query too expensive, narrow your range")
+
 type AggregatedMetrics struct {
 	EventName   string
 	From        int64 // unix second
@@ -7,12 +18,230 @@ type AggregatedMetrics struct {
 	TotalCount  int64
 	UniqueUsers int64
 
-	GroupBy string         // "", "channel", "time"
-	Groups  []MetricsGroup // grup bazlı breakdown
+	GroupBy    string         // "", "channel", "time", or several joined by ","
+	Groups     []MetricsGroup // grup bazlı breakdown
+	NextOffset int            // offset of the next page of groups (0 = last page)
+
+	Aggregate string   // "", "sum", "avg", "min", "max", "p50", "p90", "p95" or "p99"
+	Field     string   // "metadata.<key>" the aggregate is computed over
+	Value     *float64 // aggregate over all groups (nil = no numeric values)
+
+	// Aggregations holds every aggregate of a structured query; Aggregate,
+	// Field and Value are its first.
+	Aggregations []Aggregation
+
+	Mode       string            // "" (counts), "histogram", "lag" or "conversion"
+	Histogram  []HistogramBucket // mode=histogram ise dolu
+	Lag        *LagStats         // mode=lag ise dolu
+	Conversion *Conversion       // mode=conversion only
+
+	AsOf *time.Time // snapshot watermark (nil = not pinned)
+
+	Comparison *Comparison // compare=... only
 }
 
 type MetricsGroup struct {
-	Key         string // örn: "web" veya "2025-12-07T10:00:00Z"
+	Key         string   // örn: "web" veya "2025-12-07T10:00:00Z"
+	Keys        []string // one value per group_by dimension, when there are several
 	TotalCount  int64
 	UniqueUsers int64
+	Value       *float64    // aggregate=... only
+	Values      []*float64  // structured queries only, one per aggregation
+	Lag         *LagStats   // mode=lag only
+	Conversion  *Conversion // mode=conversion only, against the matching denominator group
+	Change      *Changes    // compare=... only, against the matching previous group
+}
+
+// Aggregation is one aggregate of a structured query and its value over
+// all groups.
+type Aggregation struct {
+	Aggregate string
+	Field     string
+	Value     *float64 // nil = no numeric values
+}
+
+// Aggregates over a numeric metadata field. Events whose field is missing
+// or not a number are left out of the aggregate but still counted.
+const (
+	AggregateSum = "sum"
+	AggregateAvg = "avg"
+	AggregateMin = "min"
+	AggregateMax = "max"
+	AggregateP50 = "p50"
+	AggregateP90 = "p90"
+	AggregateP95 = "p95"
+	AggregateP99 = "p99"
+)
+
+// AggregatePercentiles maps the percentile aggregates to their fraction.
+var AggregatePercentiles = map[string]float64{
+	AggregateP50: 0.5,
+	AggregateP90: 0.9,
+	AggregateP95: 0.95,
+	AggregateP99: 0.99,
+}
+
+const (
+	ModeCount      = ""
+	ModeHistogram  = "histogram"
+	ModeLag        = "lag"
+	ModeConversion = "conversion"
+)
+
+// Group orderings. Groups are ordered by key unless asked otherwise; ties
+// are broken by key.
+const (
+	OrderByKey         = "key"
+	OrderByTotalCount  = "total_count"
+	OrderByUniqueUsers = "unique_users"
+	OrderByValue       = "value"
+
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// How a tags filter matches: events carrying every tag, or any of them.
+const (
+	TagMatchAll = "all"
+	TagMatchAny = "any"
+)
+
+// Conversion relates the counted events of a result or group, the
+// numerator, to the denominator events matching the same filter, e.g.
+// purchases to product views.
+type Conversion struct {
+	Denominator string // denominator event names; set on the result only
+	TotalCount  int64  // denominator events
+	UniqueUsers int64  // denominator users
+
+	Rate     *float64 // numerator / denominator events, nil without denominator events
+	UserRate *float64 // numerator / denominator users, nil without denominator users
+}
+
+// NewConversion returns the conversion of numerator to denominator.
+func NewConversion(numerator, denominator MetricsGroup) *Conversion {
+	c := &Conversion{TotalCount: denominator.TotalCount, UniqueUsers: denominator.UniqueUsers}
+	if denominator.TotalCount != 0 {
+		r := float64(numerator.TotalCount) / float64(denominator.TotalCount)
+		c.Rate = &r
+	}
+	if denominator.UniqueUsers != 0 {
+		r := float64(numerator.UniqueUsers) / float64(denominator.UniqueUsers)
+		c.UserRate = &r
+	}
+	return c
+}
+
+// LagStats summarises ingestion lag, i.e. received_at - event_time, in
+// seconds. Negative values mean the client clock is ahead of the server.
+type LagStats struct {
+	AvgSeconds float64
+	P50Seconds float64
+	P95Seconds float64
+	P99Seconds float64
+	MaxSeconds float64
+}
+
+// MetadataGroupPrefix marks group_by values that group by a metadata key,
+// e.g. "metadata.product_id".
+const MetadataGroupPrefix = "metadata."
+
+// MetadataGroupKey returns the metadata key of a "metadata.<key>" group_by.
+func MetadataGroupKey(groupBy string) (string, bool) {
+	key, ok := strings.CutPrefix(groupBy, MetadataGroupPrefix)
+	return key, ok && key != ""
+}
+
+// GroupBySeparator joins several group_by dimensions, e.g. "channel,time".
+const GroupBySeparator = ","
+
+// CompositeKeySeparator joins the Keys of a multi-dimensional group into its
+// Key, e.g. "web|2025-12-07T10:00:00Z".
+const CompositeKeySeparator = "|"
+
+// GroupByDimensions splits a group_by into its dimensions; "" has none.
+func GroupByDimensions(groupBy string) []string {
+	if groupBy == "" {
+		return nil
+	}
+	return strings.Split(groupBy, GroupBySeparator)
+}
+
+// EventNameSeparator joins several event names counted together, e.g.
+// "signup_web,signup_mobile".
+const EventNameSeparator = ","
+
+// EventNames splits an event name list, trimming spaces and dropping empty
+// and repeated names.
+func EventNames(eventName string) []string {
+	var names []string
+	for _, n := range strings.Split(eventName, EventNameSeparator) {
+		if n = strings.TrimSpace(n); n != "" && !slices.Contains(names, n) {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// HistogramSpec describes equal-width buckets over [Min, Max), or the
+// buckets between ascending Bounds when they are set. Values below the
+// first bound fall into bucket 0 and values at or above the last into
+// bucket Buckets()+1, mirroring Postgres width_bucket.
+type HistogramSpec struct {
+	Min   float64
+	Max   float64
+	Count int
+
+	Bounds []float64 // explicit bucket bounds, instead of Min, Max and Count
+	Field  string    // "metadata.<key>" to bucket; "" buckets the event value
+}
+
+// Buckets returns the number of buckets between the first and last bound.
+func (s HistogramSpec) Buckets() int {
+	if len(s.Bounds) > 0 {
+		return len(s.Bounds) - 1
+	}
+	return s.Count
+}
+
+// BucketBounds returns the bounds of bucket i. A nil bound means unbounded.
+func (s HistogramSpec) BucketBounds(i int) (lower, upper *float64) {
+	if n := len(s.Bounds); n > 0 {
+		switch {
+		case i <= 0:
+			u := s.Bounds[0]
+			return nil, &u
+		case i >= n:
+			l := s.Bounds[n-1]
+			return &l, nil
+		default:
+			l, u := s.Bounds[i-1], s.Bounds[i]
+			return &l, &u
+		}
+	}
+
+	width := (s.Max - s.Min) / float64(s.Count)
+
+	switch {
+	case i <= 0:
+		u := s.Min
+		return nil, &u
+	case i > s.Count:
+		l := s.Max
+		return &l, nil
+	default:
+		l := s.Min + float64(i-1)*width
+		u := s.Min + float64(i)*width
+		if i == s.Count {
+			u = s.Max
+		}
+		return &l, &u
+	}
+}
+
+type HistogramBucket struct {
+	Bucket int      // width_bucket index (0 = underflow, Count+1 = overflow)
+	Lower  *float64 // nil = -inf
+	Upper  *float64 // nil = +inf
+	Count  int64
 }
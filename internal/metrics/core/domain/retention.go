@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+// Retention buckets.
+const (
+	RetentionDay  = "day"
+	RetentionWeek = "week"
+)
+
+// RetentionIntervals maps each retention bucket to its length.
+var RetentionIntervals = map[string]time.Duration{
+	RetentionDay:  24 * time.Hour,
+	RetentionWeek: 7 * 24 * time.Hour,
+}
+
+// RetentionBucket returns the start of the UTC bucket holding t. Weeks start
+// on Monday, as with date_trunc('week', ...).
+func RetentionBucket(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if interval == RetentionWeek {
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	}
+	return day
+}
+
+// RetentionCohort is the users whose first start event fell in the bucket
+// starting at Start, and how many of them came back in later buckets.
+type RetentionCohort struct {
+	Start    time.Time
+	Users    int64
+	Returned []int64 // Returned[k-1] users did the return event k buckets after Start
+}
+
+// Rates returns Returned as fractions of Users.
+func (c RetentionCohort) Rates() []float64 {
+	rates := make([]float64, len(c.Returned))
+	if c.Users == 0 {
+		return rates
+	}
+	for i, n := range c.Returned {
+		rates[i] = float64(n) / float64(c.Users)
+	}
+	return rates
+}
+
+// Retention is a retention triangle: one cohort per bucket with a start
+// event, oldest first.
+type Retention struct {
+	StartEvent  string
+	ReturnEvent string
+	From        int64
+	To          int64
+	Interval    string
+	Periods     int
+	Cohorts     []RetentionCohort
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// Rollup granularities, named after the Postgres date_trunc fields.
+const (
+	RollupHour = "hour"
+	RollupDay  = "day"
+)
+
+// RollupGranularities are the granularities events are rolled up at, finest
+// first.
+var RollupGranularities = []string{RollupHour, RollupDay}
+
+// RollupLengths maps each rollup granularity to its bucket length. Buckets
+// are aligned in UTC.
+var RollupLengths = map[string]time.Duration{
+	RollupHour: time.Hour,
+	RollupDay:  24 * time.Hour,
+}
+
+// RollupCoverage is the range of buckets of a granularity that have been
+// rolled up, [From, Until).
+type RollupCoverage struct {
+	Granularity string
+	From        time.Time
+	Until       time.Time
+}
@@ -2,19 +2,139 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"event-metrics-service/internal/metrics/core/domain"
 )
 
 type MetricsFilter struct {
-	EventName string
-	From      int64
-	To        int64
-	Channel   *string // optional
-	GroupBy   string  // "", "channel", "time"
-	Interval  string  // "hour" / "day" (GroupBy = "time" required)
+	EventName  string // one event name or several joined by ","
+	From       int64
+	To         int64
+	Channel    *string // optional
+	CampaignID *string // optional; "" = events without a campaign
+	UserID     string  // only the events counted towards this user ("" = all users)
+	GroupBy    string  // "", "channel", "time", "metadata.<key>" or several joined by ","
+	Interval   string  // "minute" / "hour" / "day" / "week" / "month" (GroupBy = "time" required)
+
+	Metadata map[string]string // metadata key -> exact value
+
+	Tags     []string // only events carrying the tags (nil = no tag filter)
+	TagMatch string   // "all" or "any" of Tags ("" = "all")
+
+	Aggregate string // "", "sum", "avg", "min", "max", "p50", "p90", "p95" or "p99"
+	Field     string // "metadata.<key>" (Aggregate != "" required)
+
+	// OrderBy orders the groups by "key" (default), "total_count",
+	// "unique_users" or "value", in Order "asc" or "desc". Top queries have
+	// their own order.
+	OrderBy string
+	Order   string
+
+	// Limit and Offset page the ordered groups (Limit 0 = every group).
+	// Top queries are not paged.
+	Limit  int
+	Offset int
+
+	// Top keeps only the Top groups with the highest count, or aggregate
+	// when set, skipping empty values (0 = every group, in key order).
+	Top int
+
+	Mode      string                // "", "histogram" or "lag"
+	Histogram *domain.HistogramSpec // Mode = "histogram" required
+
+	AsOf *time.Time // only events received at or before AsOf (nil = all)
+
+	// CountsOnly leaves unique users out (reported as 0), so counts can be
+	// answered from incremental counters.
+	CountsOnly bool
+
+	Tenant *string // only events of this tenant (nil = all tenants)
 }
 
 type MetricsReaderPort interface {
 	QueryMetrics(ctx context.Context, f MetricsFilter) (*domain.AggregatedMetrics, error)
 }
+
+type MetricsStreamerPort interface {
+	// StreamMetrics answers a grouped query like QueryMetrics, but with
+	// every group rather than a page of them. It passes the result without
+	// groups to head first, then the groups to emit in batches, in order.
+	// Limit and Offset are ignored. It stops at the first error of head or
+	// emit.
+	StreamMetrics(
+		ctx context.Context,
+		f MetricsFilter,
+		head func(*domain.AggregatedMetrics) error,
+		emit func([]domain.MetricsGroup) error,
+	) error
+}
+
+type WatermarkPort interface {
+	// CurrentWatermark returns the latest received_at, i.e. the point in
+	// time up to which ingested events are visible.
+	CurrentWatermark(ctx context.Context) (time.Time, error)
+}
+
+type DimensionValuesReaderPort interface {
+	// TopDimensionValues returns up to limit non-empty values of dimension
+	// ("channel", "campaign_id" or "metadata.<key>") seen in events since
+	// the given time, most frequent first.
+	TopDimensionValues(ctx context.Context, dimension string, since time.Time, limit int) ([]domain.DimensionValue, error)
+
+	// TopDimensionValuesByTenant is TopDimensionValues computed separately
+	// for every tenant with events since the given time, keyed by tenant.
+	TopDimensionValuesByTenant(ctx context.Context, dimension string, since time.Time, limit int) (map[string][]domain.DimensionValue, error)
+}
+
+type RetentionFilter struct {
+	StartEvent  string
+	ReturnEvent string
+	From        int64
+	To          int64
+	Interval    string // "day" or "week"
+	Periods     int    // buckets after the cohort bucket to report
+
+	Tenant *string // only events of this tenant (nil = all tenants)
+}
+
+type RetentionReaderPort interface {
+	// QueryRetention cohorts users by the bucket of their first start event
+	// in [From, To] and counts, for each of the next Periods buckets, the
+	// cohort users with a return event in it. Every cohort has Periods
+	// Returned entries.
+	QueryRetention(ctx context.Context, f RetentionFilter) (*domain.Retention, error)
+}
+
+type CatalogFilter struct {
+	From  int64
+	To    int64
+	Limit int // names to return at most
+
+	Tenant *string // only events of this tenant (nil = all tenants)
+}
+
+type EventNamesReaderPort interface {
+	// ListEventNames returns the event names with events in [From, To] in
+	// name order, each with its count and first and last event time in the
+	// range.
+	ListEventNames(ctx context.Context, f CatalogFilter) ([]domain.EventName, error)
+}
+
+type ChannelsReaderPort interface {
+	// ListChannels returns the non-empty channels with events in [From, To],
+	// most frequent first.
+	ListChannels(ctx context.Context, f CatalogFilter) ([]domain.DimensionValue, error)
+}
+
+type RollupStorePort interface {
+	// RollUp recomputes the rollups of granularity for the buckets in
+	// [from, until) from the events, and extends the coverage of the
+	// granularity to them. It returns false without rolling up while
+	// another instance is rolling up.
+	RollUp(ctx context.Context, granularity string, from, until time.Time) (bool, error)
+
+	// RollupCoverage returns the coverage of every granularity rolled up so
+	// far.
+	RollupCoverage(ctx context.Context) ([]domain.RollupCoverage, error)
+}
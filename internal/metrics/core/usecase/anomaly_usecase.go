@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+)
+
+var ErrInvalidAnomaly = errors.New("invalid anomaly query")
+
+const (
+	DefaultAnomalySeasons   = 4
+	MaxAnomalySeasons       = 12
+	DefaultAnomalyThreshold = 3.0
+)
+
+// anomalyIntervals are the buckets anomalies are scored in; both repeat
+// within an AnomalySeason.
+var anomalyIntervals = map[string]time.Duration{
+	"hour": time.Hour,
+	"day":  24 * time.Hour,
+}
+
+type DetectAnomaliesInput struct {
+	EventName string // one event name or several joined by ","
+	From      int64
+	To        int64
+	Channel   *string
+	Metadata  map[string]string
+	Interval  string  // "hour" / "day"
+	Seasons   int     // previous weeks in the baseline (0 = DefaultAnomalySeasons)
+	Threshold float64 // |z-score| flagged (0 = DefaultAnomalyThreshold)
+}
+
+// DetectAnomaliesUseCase scores the event counts of each time bucket against
+// the same bucket in the previous weeks. Counts are read with one time
+// grouped metrics query, so tenants, caching and counters apply as usual.
+type DetectAnomaliesUseCase struct {
+	metrics Metrics
+}
+
+func NewDetectAnomaliesUseCase(metrics Metrics) *DetectAnomaliesUseCase {
+	return &DetectAnomaliesUseCase{metrics: metrics}
+}
+
+// Execute scores every bucket from the one holding From to the one holding
+// To. A bucket still in progress at To is scored on its partial count.
+func (uc *DetectAnomaliesUseCase) Execute(ctx context.Context, in DetectAnomaliesInput) (*domain.AnomalyReport, error) {
+	if in.EventName == "" {
+		return nil, ErrInvalidMetricsQuery
+	}
+	if in.From <= 0 || in.To <= 0 || in.From > in.To {
+		return nil, ErrInvalidTimeRange
+	}
+	step, ok := anomalyIntervals[in.Interval]
+	if !ok {
+		return nil, fmt.Errorf("%w: interval must be hour or day", ErrInvalidAnomaly)
+	}
+	if in.Seasons == 0 {
+		in.Seasons = DefaultAnomalySeasons
+	}
+	if in.Seasons < 1 || in.Seasons > MaxAnomalySeasons {
+		return nil, fmt.Errorf("%w: seasons must be between 1 and %d", ErrInvalidAnomaly, MaxAnomalySeasons)
+	}
+	if in.Threshold == 0 {
+		in.Threshold = DefaultAnomalyThreshold
+	}
+	if in.Threshold < 0 {
+		return nil, fmt.Errorf("%w: threshold must be positive", ErrInvalidAnomaly)
+	}
+
+	first := time.Unix(in.From, 0).UTC().Truncate(step)
+	last := time.Unix(in.To, 0).UTC().Truncate(step)
+	history := first.Add(-time.Duration(in.Seasons) * domain.AnomalySeason)
+
+	res, err := uc.metrics.Execute(ctx, GetMetricsInput{
+		EventName:  in.EventName,
+		From:       history.Unix(),
+		To:         in.To,
+		Channel:    in.Channel,
+		Metadata:   in.Metadata,
+		GroupBy:    "time",
+		Interval:   in.Interval,
+		Limit:      MaxGroups,
+		CountsOnly: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[time.Time]int64, len(res.Groups))
+	for _, g := range res.Groups {
+		bucket, err := time.Parse(time.RFC3339, g.Key)
+		if err != nil {
+			return nil, fmt.Errorf("time bucket %q: %w", g.Key, err)
+		}
+		counts[bucket.UTC()] = g.TotalCount
+	}
+
+	report := &domain.AnomalyReport{
+		EventName: in.EventName,
+		From:      in.From,
+		To:        in.To,
+		Interval:  in.Interval,
+		Seasons:   in.Seasons,
+		Threshold: in.Threshold,
+	}
+	baseline := make([]int64, in.Seasons)
+	for t := first; !t.After(last); t = t.Add(step) {
+		for k := range baseline {
+			baseline[k] = counts[t.Add(-time.Duration(k+1)*domain.AnomalySeason)]
+		}
+		p := domain.ScoreAnomaly(counts[t], baseline, in.Threshold)
+		p.Bucket = t
+		report.Points = append(report.Points, p)
+	}
+	return report, nil
+}
@@ -0,0 +1,91 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/ports"
+	"event-metrics-service/internal/metrics/core/usecase"
+)
+
+func TestDetectAnomalies_SeasonalBaseline(t *testing.T) {
+	// Monday 10:00 to 11:59; each hour is compared with the same hour of the
+	// four previous Mondays.
+	day := time.Date(2025, 12, 8, 10, 0, 0, 0, time.UTC)
+	week := 7 * 24 * time.Hour
+	counts := map[time.Time]int64{
+		day: 12, day.Add(time.Hour): 150,
+		day.Add(-week): 140, day.Add(-2 * week): 150, day.Add(-3 * week): 130, day.Add(-4 * week): 140,
+		day.Add(time.Hour - week): 140, day.Add(time.Hour - 2*week): 150,
+		day.Add(time.Hour - 3*week): 130, day.Add(time.Hour - 4*week): 140,
+	}
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, f ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			res := &domain.AggregatedMetrics{EventName: f.EventName, GroupBy: f.GroupBy}
+			for bucket, n := range counts {
+				res.Groups = append(res.Groups, domain.MetricsGroup{Key: bucket.Format(time.RFC3339), TotalCount: n})
+			}
+			return res, nil
+		},
+	}
+	uc := usecase.NewDetectAnomaliesUseCase(usecase.NewGetMetricsUseCase(reader))
+
+	res, err := uc.Execute(context.Background(), usecase.DetectAnomaliesInput{
+		EventName: "signup",
+		From:      day.Add(5 * time.Minute).Unix(),
+		To:        day.Add(2*time.Hour - time.Second).Unix(),
+		Interval:  "hour",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	f := reader.lastFilter
+	if f.GroupBy != "time" || f.Interval != "hour" || !f.CountsOnly || f.From != day.Add(-4*week).Unix() {
+		t.Fatalf("unexpected filter: %+v", f)
+	}
+	if len(res.Points) != 2 || res.Seasons != 4 || res.Threshold != 3 {
+		t.Fatalf("unexpected report: %+v", res)
+	}
+	drop, normal := res.Points[0], res.Points[1]
+	if !drop.Bucket.Equal(day) || drop.Expected != 140 || drop.Direction != domain.AnomalyDrop {
+		t.Fatalf("expected a drop at 10:00, got %+v", drop)
+	}
+	if normal.Direction != "" || normal.ZScore <= 0 {
+		t.Fatalf("expected 11:00 within range, got %+v", normal)
+	}
+	if len(res.Anomalies()) != 1 {
+		t.Fatalf("expected one anomaly, got %+v", res.Anomalies())
+	}
+}
+
+func TestDetectAnomalies_QuietSeriesIsNotFlagged(t *testing.T) {
+	// Nothing in the previous weeks and 2 events now: the deviation is
+	// floored at 1, so z = 2 stays under the threshold.
+	p := domain.ScoreAnomaly(2, []int64{0, 0, 0, 0}, 3)
+	if p.Direction != "" || p.StdDev != 1 || p.ZScore != 2 {
+		t.Fatalf("unexpected point: %+v", p)
+	}
+	if p := domain.ScoreAnomaly(9, []int64{0, 0, 0, 0}, 3); p.Direction != domain.AnomalySpike {
+		t.Fatalf("expected a spike, got %+v", p)
+	}
+}
+
+func TestDetectAnomalies_Invalid(t *testing.T) {
+	uc := usecase.NewDetectAnomaliesUseCase(usecase.NewGetMetricsUseCase(&fakeMetricsReader{}))
+	for _, in := range []usecase.DetectAnomaliesInput{
+		{EventName: "signup", From: 100, To: 200, Interval: "week"},
+		{EventName: "signup", From: 100, To: 200, Interval: "hour", Seasons: 13},
+		{EventName: "signup", From: 100, To: 200, Interval: "day", Threshold: -1},
+	} {
+		if _, err := uc.Execute(context.Background(), in); !errors.Is(err, usecase.ErrInvalidAnomaly) {
+			t.Fatalf("%+v: expected ErrInvalidAnomaly, got %v", in, err)
+		}
+	}
+	if _, err := uc.Execute(context.Background(), usecase.DetectAnomaliesInput{EventName: "signup", From: 200, To: 100, Interval: "hour"}); !errors.Is(err, usecase.ErrInvalidTimeRange) {
+		t.Fatalf("expected ErrInvalidTimeRange, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/ports"
+)
+
+type ListChannelsInput struct {
+	From int64
+	To   int64
+}
+
+// ListChannelsUseCase lists the channels seen in a time range for filter
+// dropdowns. Unlike the cached dimension values, it reads the range asked
+// for.
+type ListChannelsUseCase struct {
+	reader   ports.ChannelsReaderPort
+	tenantOf func(ctx context.Context) string
+}
+
+type ChannelsOption func(*ListChannelsUseCase)
+
+// WithChannelsTenantResolver restricts the channels to the events of the
+// tenant of the request context, like WithTenantResolver.
+func WithChannelsTenantResolver(fn func(ctx context.Context) string) ChannelsOption {
+	return func(uc *ListChannelsUseCase) {
+		uc.tenantOf = fn
+	}
+}
+
+func NewListChannelsUseCase(reader ports.ChannelsReaderPort, opts ...ChannelsOption) *ListChannelsUseCase {
+	uc := &ListChannelsUseCase{reader: reader}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// Execute returns up to MaxCatalogNames channels, most frequent first.
+func (uc *ListChannelsUseCase) Execute(ctx context.Context, in ListChannelsInput) ([]domain.DimensionValue, error) {
+	if in.From <= 0 || in.To <= 0 || in.From > in.To {
+		return nil, ErrInvalidTimeRange
+	}
+
+	f := ports.CatalogFilter{
+		From:  in.From,
+		To:    in.To,
+		Limit: MaxCatalogNames,
+	}
+	if uc.tenantOf != nil {
+		t := uc.tenantOf(ctx)
+		f.Tenant = &t
+	}
+	return uc.reader.ListChannels(ctx, f)
+}
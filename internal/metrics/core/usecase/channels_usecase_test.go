@@ -0,0 +1,51 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/ports"
+	"event-metrics-service/internal/metrics/core/usecase"
+)
+
+type fakeChannelsReader struct {
+	lastFilter ports.CatalogFilter
+	called     bool
+}
+
+func (f *fakeChannelsReader) ListChannels(ctx context.Context, flt ports.CatalogFilter) ([]domain.DimensionValue, error) {
+	f.called = true
+	f.lastFilter = flt
+	return []domain.DimensionValue{{Value: "web", Count: 30}, {Value: "ios", Count: 12}}, nil
+}
+
+func TestListChannels_TenantAndCap(t *testing.T) {
+	reader := &fakeChannelsReader{}
+	uc := usecase.NewListChannelsUseCase(reader, usecase.WithChannelsTenantResolver(func(context.Context) string { return "acme" }))
+
+	channels, err := uc.Execute(context.Background(), usecase.ListChannelsInput{From: 100, To: 200})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(channels) != 2 || channels[0].Value != "web" {
+		t.Fatalf("unexpected channels: %+v", channels)
+	}
+	f := reader.lastFilter
+	if f.Tenant == nil || *f.Tenant != "acme" || f.Limit != usecase.MaxCatalogNames || f.From != 100 || f.To != 200 {
+		t.Fatalf("unexpected filter: %+v", f)
+	}
+}
+
+func TestListChannels_InvalidRange(t *testing.T) {
+	reader := &fakeChannelsReader{}
+	uc := usecase.NewListChannelsUseCase(reader)
+
+	if _, err := uc.Execute(context.Background(), usecase.ListChannelsInput{From: 0, To: 100}); !errors.Is(err, usecase.ErrInvalidTimeRange) {
+		t.Fatalf("expected ErrInvalidTimeRange, got %v", err)
+	}
+	if reader.called {
+		t.Fatal("reader should not be called")
+	}
+}
@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/ports"
+)
+
+var (
+	ErrUnknownDimension  = errors.New("unknown dimension")
+	ErrDimensionNotReady = errors.New("dimension values are not cached yet")
+)
+
+const (
+	DefaultDimensionTopN     = 100
+	DefaultDimensionLookback = 30 * 24 * time.Hour
+)
+
+// DimensionValuesUseCase keeps the top values of every configured dimension
+// in memory so filter dropdowns never trigger a DISTINCT scan per request.
+type DimensionValuesUseCase struct {
+	reader     ports.DimensionValuesReaderPort
+	dimensions []string
+	topN       int
+	lookback   time.Duration
+	tenantOf   func(ctx context.Context) string
+	now        func() time.Time
+
+	mu    sync.RWMutex
+	cache map[string]dimensionCache // by dimension
+}
+
+// dimensionCache holds the values of one dimension by tenant; without
+// per-tenant values everything is kept under "".
+type dimensionCache struct {
+	byTenant    map[string][]domain.DimensionValue
+	refreshedAt time.Time
+}
+
+type DimensionOption func(*DimensionValuesUseCase)
+
+// WithTopN sets how many values are kept per dimension.
+func WithTopN(n int) DimensionOption {
+	return func(uc *DimensionValuesUseCase) {
+		uc.topN = n
+	}
+}
+
+// WithLookback limits the values to events from the last d.
+func WithLookback(d time.Duration) DimensionOption {
+	return func(uc *DimensionValuesUseCase) {
+		uc.lookback = d
+	}
+}
+
+// WithValuesPerTenant keeps the values of every tenant apart and serves each
+// request those of the tenant of its context.
+func WithValuesPerTenant(tenantOf func(ctx context.Context) string) DimensionOption {
+	return func(uc *DimensionValuesUseCase) {
+		uc.tenantOf = tenantOf
+	}
+}
+
+// NewDimensionValuesUseCase caches channel, campaign_id and the given
+// metadata keys (exposed as "metadata.<key>").
+func NewDimensionValuesUseCase(reader ports.DimensionValuesReaderPort, metadataKeys []string, opts ...DimensionOption) *DimensionValuesUseCase {
+	dims := []string{domain.DimensionChannel, domain.DimensionCampaign}
+	for _, k := range metadataKeys {
+		dims = append(dims, domain.MetadataGroupPrefix+k)
+	}
+
+	uc := &DimensionValuesUseCase{
+		reader:     reader,
+		dimensions: dims,
+		topN:       DefaultDimensionTopN,
+		lookback:   DefaultDimensionLookback,
+		now:        time.Now,
+		cache:      map[string]dimensionCache{},
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// Refresh recomputes every dimension. A failing dimension keeps its previous
+// values; the others are still refreshed.
+func (uc *DimensionValuesUseCase) Refresh(ctx context.Context) error {
+	var errs []error
+	for _, dim := range uc.dimensions {
+		now := uc.now().UTC()
+		byTenant, err := uc.topValues(ctx, dim, now.Add(-uc.lookback))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("dimension %s: %w", dim, err))
+			continue
+		}
+
+		uc.mu.Lock()
+		uc.cache[dim] = dimensionCache{byTenant: byTenant, refreshedAt: now}
+		uc.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// Run refreshes immediately and then on every tick until ctx is cancelled.
+func (uc *DimensionValuesUseCase) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := uc.Refresh(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (uc *DimensionValuesUseCase) topValues(ctx context.Context, dim string, since time.Time) (map[string][]domain.DimensionValue, error) {
+	if uc.tenantOf != nil {
+		return uc.reader.TopDimensionValuesByTenant(ctx, dim, since, uc.topN)
+	}
+	values, err := uc.reader.TopDimensionValues(ctx, dim, since, uc.topN)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]domain.DimensionValue{"": values}, nil
+}
+
+// Values returns the cached values of dimension, optionally narrowed to
+// values starting with prefix (case-insensitive). With values per tenant,
+// only those of the tenant of ctx are returned.
+func (uc *DimensionValuesUseCase) Values(ctx context.Context, dimension, prefix string) (domain.DimensionValues, error) {
+	if !uc.known(dimension) {
+		return domain.DimensionValues{}, fmt.Errorf("%w: %s", ErrUnknownDimension, dimension)
+	}
+
+	var tenant string
+	if uc.tenantOf != nil {
+		tenant = uc.tenantOf(ctx)
+	}
+
+	uc.mu.RLock()
+	entry, ok := uc.cache[dimension]
+	uc.mu.RUnlock()
+	if !ok {
+		return domain.DimensionValues{}, ErrDimensionNotReady
+	}
+	// A tenant without events in the window has no values, not missing ones.
+	cached := domain.DimensionValues{Dimension: dimension, Values: entry.byTenant[tenant], RefreshedAt: entry.refreshedAt}
+
+	if prefix == "" {
+		return cached, nil
+	}
+
+	prefix = strings.ToLower(prefix)
+	filtered := cached
+	filtered.Values = nil
+	for _, v := range cached.Values {
+		if strings.HasPrefix(strings.ToLower(v.Value), prefix) {
+			filtered.Values = append(filtered.Values, v)
+		}
+	}
+	return filtered, nil
+}
+
+func (uc *DimensionValuesUseCase) known(dimension string) bool {
+	for _, d := range uc.dimensions {
+		if d == dimension {
+			return true
+		}
+	}
+	return false
+}
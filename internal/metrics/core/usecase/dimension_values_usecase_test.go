@@ -0,0 +1,128 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+)
+
+type fakeDimensionReader struct {
+	TopFn      func(ctx context.Context, dimension string, since time.Time, limit int) ([]domain.DimensionValue, error)
+	ByTenantFn func(ctx context.Context, dimension string, since time.Time, limit int) (map[string][]domain.DimensionValue, error)
+	calls      []string
+}
+
+func (f *fakeDimensionReader) TopDimensionValues(ctx context.Context, dimension string, since time.Time, limit int) ([]domain.DimensionValue, error) {
+	f.calls = append(f.calls, dimension)
+	return f.TopFn(ctx, dimension, since, limit)
+}
+
+func (f *fakeDimensionReader) TopDimensionValuesByTenant(ctx context.Context, dimension string, since time.Time, limit int) (map[string][]domain.DimensionValue, error) {
+	f.calls = append(f.calls, dimension)
+	return f.ByTenantFn(ctx, dimension, since, limit)
+}
+
+func TestDimensionValues_RefreshAndServe(t *testing.T) {
+	reader := &fakeDimensionReader{
+		TopFn: func(ctx context.Context, dimension string, since time.Time, limit int) ([]domain.DimensionValue, error) {
+			if limit != 5 || time.Since(since) < 6*24*time.Hour {
+				t.Fatalf("unexpected limit/since: %d %s", limit, since)
+			}
+			if dimension == "channel" {
+				return []domain.DimensionValue{{Value: "web", Count: 10}, {Value: "Whatsapp", Count: 4}, {Value: "ios", Count: 2}}, nil
+			}
+			return nil, nil
+		},
+	}
+	uc := usecase.NewDimensionValuesUseCase(reader, []string{"plan"},
+		usecase.WithTopN(5),
+		usecase.WithLookback(7*24*time.Hour),
+	)
+
+	if _, err := uc.Values(context.Background(), "channel", ""); !errors.Is(err, usecase.ErrDimensionNotReady) {
+		t.Fatalf("expected ErrDimensionNotReady before the first refresh, got %v", err)
+	}
+
+	if err := uc.Refresh(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reader.calls) != 3 || reader.calls[2] != "metadata.plan" {
+		t.Fatalf("expected channel, campaign_id and metadata.plan, got %v", reader.calls)
+	}
+
+	got, err := uc.Values(context.Background(), "channel", "w")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Values) != 2 || got.Values[1].Value != "Whatsapp" || got.RefreshedAt.IsZero() {
+		t.Fatalf("unexpected values: %+v", got)
+	}
+
+	if _, err := uc.Values(context.Background(), "metadata.unknown", ""); !errors.Is(err, usecase.ErrUnknownDimension) {
+		t.Fatalf("expected ErrUnknownDimension, got %v", err)
+	}
+}
+
+func TestDimensionValues_FailedRefreshKeepsPreviousValues(t *testing.T) {
+	fail := false
+	reader := &fakeDimensionReader{
+		TopFn: func(ctx context.Context, dimension string, since time.Time, limit int) ([]domain.DimensionValue, error) {
+			if fail && dimension == "channel" {
+				return nil, errors.New("db failure")
+			}
+			return []domain.DimensionValue{{Value: "web", Count: 1}}, nil
+		},
+	}
+	uc := usecase.NewDimensionValuesUseCase(reader, nil)
+
+	if err := uc.Refresh(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fail = true
+	if err := uc.Refresh(context.Background()); err == nil {
+		t.Fatalf("expected refresh error")
+	}
+
+	got, err := uc.Values(context.Background(), "channel", "")
+	if err != nil || len(got.Values) != 1 {
+		t.Fatalf("expected previous values to be kept, got %+v, %v", got, err)
+	}
+}
+
+type tenantKey struct{}
+
+func TestDimensionValues_PerTenant(t *testing.T) {
+	reader := &fakeDimensionReader{
+		ByTenantFn: func(ctx context.Context, dimension string, since time.Time, limit int) (map[string][]domain.DimensionValue, error) {
+			return map[string][]domain.DimensionValue{
+				"team-a": {{Value: "web", Count: 3}},
+				"team-b": {{Value: "ios", Count: 5}, {Value: "web", Count: 1}},
+			}, nil
+		},
+	}
+	uc := usecase.NewDimensionValuesUseCase(reader, nil,
+		usecase.WithValuesPerTenant(func(ctx context.Context) string {
+			tenant, _ := ctx.Value(tenantKey{}).(string)
+			return tenant
+		}),
+	)
+	if err := uc.Refresh(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := uc.Values(context.WithValue(context.Background(), tenantKey{}, "team-b"), "channel", "")
+	if err != nil || len(got.Values) != 2 || got.Values[0].Value != "ios" {
+		t.Fatalf("unexpected team-b values: %+v, %v", got, err)
+	}
+
+	// a tenant without events sees no values rather than another tenant's
+	got, err = uc.Values(context.WithValue(context.Background(), tenantKey{}, "team-c"), "channel", "")
+	if err != nil || len(got.Values) != 0 {
+		t.Fatalf("expected no values for team-c, got %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/ports"
+)
+
+// MaxCatalogNames caps the event name and channel catalogs, like MaxGroups
+// caps a metrics query.
+const MaxCatalogNames = 10000
+
+type ListEventNamesInput struct {
+	From int64
+	To   int64
+}
+
+// ListEventNamesUseCase lists the event names seen in a time range, so
+// dashboards can offer them without access to the database.
+type ListEventNamesUseCase struct {
+	reader   ports.EventNamesReaderPort
+	tenantOf func(ctx context.Context) string
+}
+
+type EventNamesOption func(*ListEventNamesUseCase)
+
+// WithEventNamesTenantResolver restricts the catalog to the events of the
+// tenant of the request context, like WithTenantResolver.
+func WithEventNamesTenantResolver(fn func(ctx context.Context) string) EventNamesOption {
+	return func(uc *ListEventNamesUseCase) {
+		uc.tenantOf = fn
+	}
+}
+
+func NewListEventNamesUseCase(reader ports.EventNamesReaderPort, opts ...EventNamesOption) *ListEventNamesUseCase {
+	uc := &ListEventNamesUseCase{reader: reader}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// Execute returns up to MaxCatalogNames names in name order.
+func (uc *ListEventNamesUseCase) Execute(ctx context.Context, in ListEventNamesInput) ([]domain.EventName, error) {
+	if in.From <= 0 || in.To <= 0 || in.From > in.To {
+		return nil, ErrInvalidTimeRange
+	}
+
+	f := ports.CatalogFilter{
+		From:  in.From,
+		To:    in.To,
+		Limit: MaxCatalogNames,
+	}
+	if uc.tenantOf != nil {
+		t := uc.tenantOf(ctx)
+		f.Tenant = &t
+	}
+	return uc.reader.ListEventNames(ctx, f)
+}
@@ -0,0 +1,51 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/ports"
+	"event-metrics-service/internal/metrics/core/usecase"
+)
+
+type fakeEventNamesReader struct {
+	lastFilter ports.CatalogFilter
+	called     bool
+}
+
+func (f *fakeEventNamesReader) ListEventNames(ctx context.Context, flt ports.CatalogFilter) ([]domain.EventName, error) {
+	f.called = true
+	f.lastFilter = flt
+	return []domain.EventName{{Name: "login", Count: 3}, {Name: "signup", Count: 1}}, nil
+}
+
+func TestListEventNames_TenantAndCap(t *testing.T) {
+	reader := &fakeEventNamesReader{}
+	uc := usecase.NewListEventNamesUseCase(reader, usecase.WithEventNamesTenantResolver(func(context.Context) string { return "acme" }))
+
+	names, err := uc.Execute(context.Background(), usecase.ListEventNamesInput{From: 100, To: 200})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(names) != 2 {
+		t.Fatalf("unexpected names: %+v", names)
+	}
+	f := reader.lastFilter
+	if f.Tenant == nil || *f.Tenant != "acme" || f.Limit != usecase.MaxCatalogNames || f.From != 100 || f.To != 200 {
+		t.Fatalf("unexpected filter: %+v", f)
+	}
+}
+
+func TestListEventNames_InvalidRange(t *testing.T) {
+	reader := &fakeEventNamesReader{}
+	uc := usecase.NewListEventNamesUseCase(reader)
+
+	if _, err := uc.Execute(context.Background(), usecase.ListEventNamesInput{From: 200, To: 100}); !errors.Is(err, usecase.ErrInvalidTimeRange) {
+		t.Fatalf("expected ErrInvalidTimeRange, got %v", err)
+	}
+	if reader.called {
+		t.Fatal("reader should not be called")
+	}
+}
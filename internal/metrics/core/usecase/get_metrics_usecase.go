@@ -3,9 +3,16 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"event-metrics-service/internal/metrics/core/domain"
 	"event-metrics-service/internal/metrics/core/ports"
+	"event-metrics-service/internal/tag"
 )
 
 var (
@@ -13,64 +20,818 @@ var (
 	ErrInvalidTimeRange    = errors.New("invalid time range")
 	ErrInvalidGroupBy      = errors.New("invalid group_by value")
 	ErrInvalidInterval     = errors.New("invalid interval for time grouping")
+	ErrInvalidMode         = errors.New("invalid metrics mode")
+	ErrInvalidHistogram    = errors.New("invalid histogram bucket specification")
+	ErrInvalidMetadata     = errors.New("invalid metadata filter")
+	ErrInvalidTags         = errors.New("invalid tags filter")
+	ErrInvalidAggregate    = errors.New("invalid aggregate")
+	ErrInvalidTop          = errors.New("invalid top-N query")
+	ErrInvalidCompare      = errors.New("invalid comparison")
+	ErrInvalidPage         = errors.New("invalid group page")
+	ErrInvalidOrder        = errors.New("invalid group order")
+	ErrInvalidBatch        = errors.New("invalid metrics batch")
+	ErrInvalidStream       = errors.New("invalid metrics stream")
+	ErrWatermarkDisabled   = errors.New("as_of snapshots are not available")
+	ErrStreamDisabled      = errors.New("metrics streams are not available")
+	ErrQueryRangeTooLarge  = errors.New("query range too large")
+	ErrQueryTimeout        = errors.New("metrics query timed out")
 )
 
+// MaxHistogramBuckets caps the number of histogram buckets per request.
+const MaxHistogramBuckets = 1000
+
+const (
+	MaxMetadataFilters   = 10
+	MaxMetadataKeyLength = 64
+)
+
+// MaxTagFilters caps the tags of one tags filter.
+const MaxTagFilters = 20
+
+// TimeIntervals are the buckets of group_by=time, named after the Postgres
+// date_trunc units. Weeks start on Monday; all buckets are in UTC.
+var TimeIntervals = []string{"minute", "hour", "day", "week", "month"}
+
+// MaxTimeBuckets caps the time buckets one query may span by default,
+// about a week of minutes. Longer ranges need a coarser interval.
+const MaxTimeBuckets = 10000
+
+// intervalSeconds is the shortest length of each interval, so the bucket
+// count derived from it is an upper bound.
+var intervalSeconds = map[string]int64{
+	"minute": 60,
+	"hour":   60 * 60,
+	"day":    24 * 60 * 60,
+	"week":   7 * 24 * 60 * 60,
+	"month":  28 * 24 * 60 * 60,
+}
+
+// MaxGroupByDimensions caps the dimensions of one group_by, e.g.
+// "channel,time" has two.
+const MaxGroupByDimensions = 3
+
+// MaxTop caps the groups of one top-N query.
+const MaxTop = 1000
+
+// MaxGroups caps the groups of one response; more are paged with Limit and
+// Offset.
+const MaxGroups = 10000
+
+// MaxEventNames caps the event names counted together in one query.
+const MaxEventNames = 20
+
+// MaxBatchQueries caps the number of sub-queries in one batch request.
+const MaxBatchQueries = 20
+
 type GetMetricsInput struct {
-	EventName string
+	EventName string // one event name or several joined by ","
 	From      int64
 	To        int64
 
-	Channel  *string
-	GroupBy  string // "", "channel", "time"
-	Interval string // "hour" / "day" (group_by=time ise zorunlu)
+	Channel    *string
+	CampaignID *string // "" = events without a campaign
+	UserID     string  // one user's events, anonymous ones stitched to them included
+	GroupBy    string  // "", "channel", "time", "hour_of_day", "metadata.<key>" or several joined by ","
+	Interval   string  // "minute" / "hour" / "day" / "week" / "month" (group_by=time ise zorunlu)
+
+	Metadata map[string]string // metadata.<key>=<value> filtreleri
+
+	Tags     []string // events carrying all (or any) of the tags
+	TagMatch string   // "" / "all" / "any"
+
+	Aggregate string // "" / "sum" / "avg" / "min" / "max" / "p50" / "p90" / "p95" / "p99"
+	Field     string // "metadata.<key>", aggregate ise zorunlu
+
+	Top int // only the top N groups by count or aggregate (0 = all)
+
+	OrderBy string // "" / "key" / "total_count" / "unique_users" / "value"
+	Order   string // "" / "asc" / "desc"; "" is asc by key and desc otherwise
+
+	Limit  int // groups per page, at most MaxGroups (0 = MaxGroups)
+	Offset int // groups skipped
+
+	Compare string // "" / "previous_period" / "previous_year"
+
+	Mode        string                // "" (counts) / "histogram" / "lag" / "conversion"
+	Histogram   *domain.HistogramSpec // mode=histogram ise zorunlu
+	Denominator string                // mode=conversion: the event names EventName is divided by
+
+	// AsOf pins the query to events received at or before the watermark.
+	// AsOfLatest resolves the current watermark first (as_of=latest).
+	AsOf       *time.Time
+	AsOfLatest bool
+
+	CountsOnly bool // skip unique users (reported as 0)
 }
 
 type GetMetricsUseCase struct {
-	reader ports.MetricsReaderPort
+	reader    ports.MetricsReaderPort
+	streamer  ports.MetricsStreamerPort
+	watermark ports.WatermarkPort
+	tenantOf  func(ctx context.Context) string
+
+	maxRange       time.Duration // 0 = unlimited
+	maxTimeBuckets int64
+	queryTimeout   time.Duration // 0 = none
 }
 
-func NewGetMetricsUseCase(reader ports.MetricsReaderPort) *GetMetricsUseCase {
-	return &GetMetricsUseCase{reader: reader}
+type Option func(*GetMetricsUseCase)
+
+// WithWatermark enables as_of snapshots backed by w.
+func WithWatermark(w ports.WatermarkPort) Option {
+	return func(uc *GetMetricsUseCase) {
+		uc.watermark = w
+	}
+}
+
+// WithStreamer enables ExecuteStream backed by s.
+func WithStreamer(s ports.MetricsStreamerPort) Option {
+	return func(uc *GetMetricsUseCase) {
+		uc.streamer = s
+	}
+}
+
+// WithTenantResolver restricts every query to the events of the tenant of
+// the request context. A context without a tenant only sees events stored
+// without one.
+func WithTenantResolver(fn func(ctx context.Context) string) Option {
+	return func(uc *GetMetricsUseCase) {
+		uc.tenantOf = fn
+	}
+}
+
+// WithMaxRange rejects queries spanning more than d with
+// ErrQueryRangeTooLarge.
+func WithMaxRange(d time.Duration) Option {
+	return func(uc *GetMetricsUseCase) {
+		uc.maxRange = d
+	}
+}
+
+// WithMaxTimeBuckets replaces MaxTimeBuckets as the cap on the time buckets
+// of one query.
+func WithMaxTimeBuckets(n int) Option {
+	return func(uc *GetMetricsUseCase) {
+		uc.maxTimeBuckets = int64(n)
+	}
+}
+
+// WithQueryTimeout cancels a query still running after d, failing it with
+// ErrQueryTimeout; the reader's database call is cancelled with it. Every
+// query of a batch gets its own d. Streams and exports get d for all of
+// their groups, including the time the caller takes to write them.
+func WithQueryTimeout(d time.Duration) Option {
+	return func(uc *GetMetricsUseCase) {
+		uc.queryTimeout = d
+	}
+}
+
+func NewGetMetricsUseCase(reader ports.MetricsReaderPort, opts ...Option) *GetMetricsUseCase {
+	uc := &GetMetricsUseCase{reader: reader, maxTimeBuckets: MaxTimeBuckets}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
 }
 
 // Execute, input'u doğrular, filter'a çevirir ve MetricsReaderPort'u çağırır.
 func (uc *GetMetricsUseCase) Execute(ctx context.Context, in GetMetricsInput) (*domain.AggregatedMetrics, error) {
+	filter, err := uc.buildFilter(in)
+	if err != nil {
+		return nil, err
+	}
 
-	if in.EventName == "" {
-		return nil, ErrInvalidMetricsQuery
+	if in.AsOfLatest {
+		asOf, err := uc.currentWatermark(ctx)
+		if err != nil {
+			return nil, err
+		}
+		filter.AsOf = &asOf
 	}
+	filter.Tenant = uc.tenant(ctx)
 
-	if in.From <= 0 || in.To <= 0 || in.From > in.To {
-		return nil, ErrInvalidTimeRange
+	return uc.run(ctx, filter, in)
+}
+
+// ValidateStream reports the error ExecuteStream would refuse in with, so
+// callers can reject it before they start writing a response.
+func (uc *GetMetricsUseCase) ValidateStream(in GetMetricsInput) error {
+	_, err := uc.streamFilter(in)
+	return err
+}
+
+// ExecuteStream answers a grouped query with every group instead of a page
+// of them: the result without groups goes to head, then the groups go to
+// emit in batches. Top-N, comparisons and histogram or lag modes are not
+// streamed.
+func (uc *GetMetricsUseCase) ExecuteStream(
+	ctx context.Context,
+	in GetMetricsInput,
+	head func(*domain.AggregatedMetrics) error,
+	emit func([]domain.MetricsGroup) error,
+) error {
+	filter, err := uc.streamFilter(in)
+	if err != nil {
+		return err
 	}
 
-	switch in.GroupBy {
-	case "":
-		// no group
-	case "channel":
-		// valid
-	case "time":
-		// interval required and only "hour" / "day"
-		if in.Interval != "hour" && in.Interval != "day" {
-			return nil, ErrInvalidInterval
+	if in.AsOfLatest {
+		asOf, err := uc.currentWatermark(ctx)
+		if err != nil {
+			return err
 		}
-	default:
-		return nil, ErrInvalidGroupBy
+		filter.AsOf = &asOf
 	}
+	filter.Tenant = uc.tenant(ctx)
+
+	ctx, cancel := uc.withQueryTimeout(ctx)
+	defer cancel()
+	return uc.timedOut(ctx, uc.streamer.StreamMetrics(ctx, filter, head, emit))
+}
 
-	filter := ports.MetricsFilter{
-		EventName: in.EventName,
-		From:      in.From,
-		To:        in.To,
-		Channel:   in.Channel,
-		GroupBy:   in.GroupBy,
-		Interval:  in.Interval,
+func (uc *GetMetricsUseCase) streamFilter(in GetMetricsInput) (ports.MetricsFilter, error) {
+	if uc.streamer == nil {
+		return ports.MetricsFilter{}, ErrStreamDisabled
+	}
+	if in.AsOfLatest && uc.watermark == nil {
+		return ports.MetricsFilter{}, ErrWatermarkDisabled
+	}
+	f, err := uc.buildFilter(in)
+	if err != nil {
+		return ports.MetricsFilter{}, err
+	}
+	switch {
+	case in.GroupBy == "":
+		return ports.MetricsFilter{}, fmt.Errorf("%w: group_by is required", ErrInvalidStream)
+	case in.Mode != domain.ModeCount, in.Top > 0, in.Compare != "":
+		return ports.MetricsFilter{}, fmt.Errorf("%w: top, compare and mode are not streamed", ErrInvalidStream)
+	case in.Limit > 0, in.Offset > 0:
+		return ports.MetricsFilter{}, fmt.Errorf("%w: streams return every group, without limit or offset", ErrInvalidStream)
+	}
+	f.Limit = 0
+	return f, nil
+}
+
+// run answers f, the filter built from in, within the query timeout.
+func (uc *GetMetricsUseCase) run(ctx context.Context, f ports.MetricsFilter, in GetMetricsInput) (*domain.AggregatedMetrics, error) {
+	ctx, cancel := uc.withQueryTimeout(ctx)
+	defer cancel()
+
+	var res *domain.AggregatedMetrics
+	var err error
+	if in.Mode == domain.ModeConversion {
+		res, err = uc.conversion(ctx, f, in.Denominator)
+	} else {
+		res, err = uc.query(ctx, f, in.Compare)
+	}
+	if err = uc.timedOut(ctx, err); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// withQueryTimeout bounds ctx by the query timeout, if any.
+func (uc *GetMetricsUseCase) withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if uc.queryTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeoutCause(ctx, uc.queryTimeout, ErrQueryTimeout)
+}
+
+// timedOut replaces err with ErrQueryTimeout when ctx ran out of the query
+// timeout. Readers fail in their own words once cancelled; the cause tells
+// our deadline from the caller's.
+func (uc *GetMetricsUseCase) timedOut(ctx context.Context, err error) error {
+	if err != nil && errors.Is(context.Cause(ctx), ErrQueryTimeout) {
+		return fmt.Errorf("%w after %s", ErrQueryTimeout, uc.queryTimeout)
+	}
+	return err
+}
+
+// conversion counts f and the same query over the denominator events, and
+// divides the counts of the result and of every group by the matching
+// denominator ones. Groups with denominator events only are added with a
+// count of 0, so every time bucket of either side has a rate.
+func (uc *GetMetricsUseCase) conversion(ctx context.Context, f ports.MetricsFilter, denominator string) (*domain.AggregatedMetrics, error) {
+	result, err := uc.reader.QueryMetrics(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	df := f
+	df.EventName = strings.Join(domain.EventNames(denominator), domain.EventNameSeparator)
+	den, err := uc.reader.QueryMetrics(ctx, df)
+	if err != nil {
+		return nil, err
+	}
+	if result.NextOffset != 0 || den.NextOffset != 0 {
+		return nil, fmt.Errorf("%w: conversions cover at most %d groups", ErrInvalidMode, MaxGroups)
+	}
+
+	result.Mode = domain.ModeConversion
+	result.Conversion = domain.NewConversion(totals(result), totals(den))
+	result.Conversion.Denominator = df.EventName
+
+	byKey := make(map[string]domain.MetricsGroup, len(den.Groups))
+	for _, g := range den.Groups {
+		byKey[g.Key] = g
+	}
+	for i, g := range result.Groups {
+		result.Groups[i].Conversion = domain.NewConversion(g, byKey[g.Key])
+		delete(byKey, g.Key)
+	}
+	if len(byKey) == 0 {
+		return result, nil
+	}
+	for _, g := range den.Groups {
+		if _, ok := byKey[g.Key]; ok {
+			result.Groups = append(result.Groups, domain.MetricsGroup{
+				Key:        g.Key,
+				Keys:       g.Keys,
+				Conversion: domain.NewConversion(domain.MetricsGroup{}, g),
+			})
+		}
+	}
+	slices.SortStableFunc(result.Groups, func(a, b domain.MetricsGroup) int {
+		if len(a.Keys) > 0 && len(b.Keys) > 0 {
+			return slices.Compare(a.Keys, b.Keys)
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+	return result, nil
+}
+
+// query runs f and, with compare, the same query over the comparison
+// window.
+func (uc *GetMetricsUseCase) query(ctx context.Context, f ports.MetricsFilter, compare string) (*domain.AggregatedMetrics, error) {
+	result, err := uc.reader.QueryMetrics(ctx, f)
+	if err != nil || compare == "" {
+		return result, err
 	}
 
-	result, err := uc.reader.QueryMetrics(ctx, filter)
+	pf := f
+	pf.From, pf.To = domain.ComparisonWindow(compare, f.From, f.To)
+	previous, err := uc.reader.QueryMetrics(ctx, pf)
 	if err != nil {
 		return nil, err
 	}
 
+	result.Comparison = &domain.Comparison{
+		Compare:  compare,
+		Previous: previous,
+		Change:   domain.CompareFigures(totals(previous), totals(result)),
+	}
+
+	// Groups are matched by key, time buckets by their position in the
+	// window, so the first hour is compared with the first hour.
+	dims := domain.GroupByDimensions(f.GroupBy)
+	byKey := make(map[string]domain.MetricsGroup, len(previous.Groups))
+	for _, g := range previous.Groups {
+		byKey[matchKey(g, dims, pf.From, f.Interval)] = g
+	}
+	for i, g := range result.Groups {
+		c := domain.CompareFigures(byKey[matchKey(g, dims, f.From, f.Interval)], g)
+		result.Groups[i].Change = &c
+	}
 	return result, nil
 }
+
+func totals(m *domain.AggregatedMetrics) domain.MetricsGroup {
+	return domain.MetricsGroup{TotalCount: m.TotalCount, UniqueUsers: m.UniqueUsers, Value: m.Value}
+}
+
+// matchKey is the key of g with time buckets replaced by their position
+// from the start of the window.
+func matchKey(g domain.MetricsGroup, dims []string, from int64, interval string) string {
+	keys := g.Keys
+	if len(dims) == 1 {
+		keys = []string{g.Key}
+	}
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k
+		if i >= len(dims) || dims[i] != "time" {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339, k); err == nil {
+			parts[i] = strconv.Itoa(bucketOrdinal(t, time.Unix(from, 0).UTC(), interval))
+		}
+	}
+	return strings.Join(parts, domain.CompositeKeySeparator)
+}
+
+// bucketOrdinal returns how many interval buckets bucket starts after the
+// bucket holding from.
+func bucketOrdinal(bucket, from time.Time, interval string) int {
+	switch interval {
+	case "month":
+		return (bucket.Year()-from.Year())*12 + int(bucket.Month()-from.Month())
+	case "week":
+		day := from.Truncate(24 * time.Hour)
+		from = day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	default:
+		from = from.Truncate(time.Duration(intervalSeconds[interval]) * time.Second)
+	}
+	return int(bucket.Sub(from) / (time.Duration(intervalSeconds[interval]) * time.Second))
+}
+
+type BatchGetMetricsInput struct {
+	AsOf    *time.Time // nil = current watermark
+	Queries []GetMetricsInput
+}
+
+type BatchMetricsResult struct {
+	AsOf    time.Time
+	Results []*domain.AggregatedMetrics
+}
+
+// ExecuteBatch runs every query against the same as_of watermark, so panels
+// of one dashboard never disagree because events arrived in between. All
+// queries are validated before any of them runs; per-query AsOf is ignored.
+func (uc *GetMetricsUseCase) ExecuteBatch(ctx context.Context, in BatchGetMetricsInput) (*BatchMetricsResult, error) {
+	if len(in.Queries) == 0 || len(in.Queries) > MaxBatchQueries {
+		return nil, fmt.Errorf("%w: between 1 and %d queries are required", ErrInvalidBatch, MaxBatchQueries)
+	}
+
+	filters := make([]ports.MetricsFilter, len(in.Queries))
+	for i, q := range in.Queries {
+		f, err := uc.buildFilter(q)
+		if err != nil {
+			return nil, fmt.Errorf("query at index %d: %w", i, err)
+		}
+		filters[i] = f
+	}
+
+	var asOf time.Time
+	if in.AsOf != nil {
+		asOf = *in.AsOf
+	} else {
+		wm, err := uc.currentWatermark(ctx)
+		if err != nil {
+			return nil, err
+		}
+		asOf = wm
+	}
+
+	tenant := uc.tenant(ctx)
+	res := &BatchMetricsResult{AsOf: asOf, Results: make([]*domain.AggregatedMetrics, 0, len(filters))}
+	for i, f := range filters {
+		f.AsOf = &asOf
+		f.Tenant = tenant
+		r, err := uc.run(ctx, f, in.Queries[i])
+		if err != nil {
+			return nil, err
+		}
+		res.Results = append(res.Results, r)
+	}
+
+	return res, nil
+}
+
+func (uc *GetMetricsUseCase) tenant(ctx context.Context) *string {
+	if uc.tenantOf == nil {
+		return nil
+	}
+	t := uc.tenantOf(ctx)
+	return &t
+}
+
+func (uc *GetMetricsUseCase) currentWatermark(ctx context.Context) (time.Time, error) {
+	if uc.watermark == nil {
+		return time.Time{}, ErrWatermarkDisabled
+	}
+	return uc.watermark.CurrentWatermark(ctx)
+}
+
+// buildFilter validates the input and converts it into a reader filter.
+func (uc *GetMetricsUseCase) buildFilter(in GetMetricsInput) (ports.MetricsFilter, error) {
+
+	names := domain.EventNames(in.EventName)
+	if len(names) == 0 {
+		return ports.MetricsFilter{}, ErrInvalidMetricsQuery
+	}
+	if len(names) > MaxEventNames {
+		return ports.MetricsFilter{}, fmt.Errorf("%w: at most %d event names", ErrInvalidMetricsQuery, MaxEventNames)
+	}
+
+	if in.From <= 0 || in.To <= 0 || in.From > in.To {
+		return ports.MetricsFilter{}, ErrInvalidTimeRange
+	}
+	if span := time.Duration(in.To-in.From) * time.Second; uc.maxRange > 0 && span > uc.maxRange {
+		return ports.MetricsFilter{}, fmt.Errorf("%w: %s exceeds the maximum of %s", ErrQueryRangeTooLarge, span, uc.maxRange)
+	}
+
+	if err := validateGroupBy(in, uc.maxTimeBuckets); err != nil {
+		return ports.MetricsFilter{}, err
+	}
+
+	if err := validateMetadataFilters(in.Metadata); err != nil {
+		return ports.MetricsFilter{}, err
+	}
+	tags, err := tagFilters(in)
+	if err != nil {
+		return ports.MetricsFilter{}, err
+	}
+
+	switch in.Mode {
+	case domain.ModeCount:
+		// default
+	case domain.ModeHistogram:
+		if err := validateHistogram(in); err != nil {
+			return ports.MetricsFilter{}, err
+		}
+	case domain.ModeLag:
+		if len(domain.GroupByDimensions(in.GroupBy)) > 1 {
+			return ports.MetricsFilter{}, fmt.Errorf("%w: lag mode groups by one dimension only", ErrInvalidGroupBy)
+		}
+	case domain.ModeConversion:
+		if err := validateConversion(in); err != nil {
+			return ports.MetricsFilter{}, err
+		}
+	default:
+		return ports.MetricsFilter{}, ErrInvalidMode
+	}
+
+	if err := validateAggregate(in); err != nil {
+		return ports.MetricsFilter{}, err
+	}
+	if err := validateTop(in); err != nil {
+		return ports.MetricsFilter{}, err
+	}
+	if err := validatePage(in); err != nil {
+		return ports.MetricsFilter{}, err
+	}
+	if err := validateOrder(in); err != nil {
+		return ports.MetricsFilter{}, err
+	}
+	if err := validateCompare(in); err != nil {
+		return ports.MetricsFilter{}, err
+	}
+
+	f := ports.MetricsFilter{
+		EventName:  strings.Join(names, domain.EventNameSeparator),
+		From:       in.From,
+		To:         in.To,
+		Channel:    in.Channel,
+		CampaignID: in.CampaignID,
+		UserID:     in.UserID,
+		GroupBy:    in.GroupBy,
+		Interval:   in.Interval,
+		Metadata:   in.Metadata,
+		Tags:       tags,
+		TagMatch:   in.TagMatch,
+		Aggregate:  in.Aggregate,
+		Field:      in.Field,
+		Top:        in.Top,
+		Mode:       in.Mode,
+		Histogram:  in.Histogram,
+		AsOf:       in.AsOf,
+		CountsOnly: in.CountsOnly,
+	}
+	if in.Mode == domain.ModeConversion {
+		// Both sides are plain counts; the use case divides them.
+		f.Mode = domain.ModeCount
+	}
+	if in.GroupBy != "" && in.Top == 0 {
+		f.Limit, f.Offset = in.Limit, in.Offset
+		if f.Limit == 0 {
+			f.Limit = MaxGroups
+		}
+		f.OrderBy, f.Order = in.OrderBy, in.Order
+		if f.OrderBy == "" {
+			f.OrderBy = domain.OrderByKey
+		}
+		if f.Order == "" {
+			f.Order = domain.OrderDesc
+			if f.OrderBy == domain.OrderByKey {
+				f.Order = domain.OrderAsc
+			}
+		}
+	}
+	return f, nil
+}
+
+// validateGroupBy checks every dimension of the group_by; a dimension may
+// appear only once, and time at most maxBuckets buckets.
+func validateGroupBy(in GetMetricsInput, maxBuckets int64) error {
+	dims := domain.GroupByDimensions(in.GroupBy)
+	if len(dims) > MaxGroupByDimensions {
+		return fmt.Errorf("%w: at most %d dimensions are allowed", ErrInvalidGroupBy, MaxGroupByDimensions)
+	}
+	for i, dim := range dims {
+		if slices.Contains(dims[:i], dim) {
+			return fmt.Errorf("%w: %q is repeated", ErrInvalidGroupBy, dim)
+		}
+		switch dim {
+		case domain.DimensionChannel, domain.DimensionCampaign, domain.DimensionUser,
+			domain.DimensionHourOfDay, domain.DimensionDayOfWeek:
+			// valid
+		case "time":
+			if !slices.Contains(TimeIntervals, in.Interval) {
+				return ErrInvalidInterval
+			}
+			if buckets := (in.To-in.From)/intervalSeconds[in.Interval] + 1; buckets > maxBuckets {
+				return fmt.Errorf("%w: %w: %d %s buckets exceed the maximum of %d, use a coarser interval",
+					ErrQueryRangeTooLarge, ErrInvalidInterval, buckets, in.Interval, maxBuckets)
+			}
+		default:
+			key, ok := domain.MetadataGroupKey(dim)
+			if !ok || len(key) > MaxMetadataKeyLength {
+				return ErrInvalidGroupBy
+			}
+		}
+	}
+	return nil
+}
+
+func validateHistogram(in GetMetricsInput) error {
+	if in.GroupBy != "" {
+		return fmt.Errorf("%w: group_by is not supported in histogram mode", ErrInvalidHistogram)
+	}
+
+	h := in.Histogram
+	if h == nil {
+		return ErrInvalidHistogram
+	}
+	if h.Field != "" {
+		key, ok := domain.MetadataGroupKey(h.Field)
+		if !ok || len(key) > MaxMetadataKeyLength {
+			return fmt.Errorf("%w: field must be metadata.<key>", ErrInvalidHistogram)
+		}
+	}
+
+	if len(h.Bounds) > 0 {
+		if h.Count != 0 {
+			return fmt.Errorf("%w: buckets and bucket_count are exclusive", ErrInvalidHistogram)
+		}
+		if len(h.Bounds) < 2 || len(h.Bounds) > MaxHistogramBuckets+1 {
+			return fmt.Errorf("%w: buckets must list 2 to %d bounds", ErrInvalidHistogram, MaxHistogramBuckets+1)
+		}
+		for i, b := range h.Bounds {
+			if math.IsInf(b, 0) || math.IsNaN(b) || (i > 0 && !(b > h.Bounds[i-1])) {
+				return fmt.Errorf("%w: buckets must be ascending finite numbers", ErrInvalidHistogram)
+			}
+		}
+		return nil
+	}
+	if h.Count < 1 || h.Count > MaxHistogramBuckets {
+		return fmt.Errorf("%w: bucket_count must be between 1 and %d", ErrInvalidHistogram, MaxHistogramBuckets)
+	}
+	if !(h.Max > h.Min) {
+		return fmt.Errorf("%w: bucket_max must be greater than bucket_min", ErrInvalidHistogram)
+	}
+
+	return nil
+}
+
+// Aggregates lists the supported aggregate values.
+var Aggregates = []string{
+	domain.AggregateSum, domain.AggregateAvg, domain.AggregateMin, domain.AggregateMax,
+	domain.AggregateP50, domain.AggregateP90, domain.AggregateP95, domain.AggregateP99,
+}
+
+func validateAggregate(in GetMetricsInput) error {
+	if in.Aggregate == "" {
+		if in.Field != "" {
+			return fmt.Errorf("%w: field requires aggregate", ErrInvalidAggregate)
+		}
+		return nil
+	}
+	if !slices.Contains(Aggregates, in.Aggregate) {
+		return fmt.Errorf("%w: aggregate must be sum, avg, min, max, p50, p90, p95 or p99", ErrInvalidAggregate)
+	}
+	key, ok := domain.MetadataGroupKey(in.Field)
+	if !ok || len(key) > MaxMetadataKeyLength {
+		return fmt.Errorf("%w: field must be metadata.<key>", ErrInvalidAggregate)
+	}
+	if in.Mode != domain.ModeCount {
+		return fmt.Errorf("%w: aggregate is only supported when counting events", ErrInvalidAggregate)
+	}
+	return nil
+}
+
+// validateConversion checks the denominator of a conversion. Its groups
+// are matched by key, so they are neither paged nor reordered.
+func validateConversion(in GetMetricsInput) error {
+	names := domain.EventNames(in.Denominator)
+	if len(names) == 0 || len(names) > MaxEventNames {
+		return fmt.Errorf("%w: conversion needs 1 to %d denominator event names", ErrInvalidMode, MaxEventNames)
+	}
+	if in.Limit > 0 || in.Offset > 0 {
+		return fmt.Errorf("%w: conversions are not paged", ErrInvalidPage)
+	}
+	return nil
+}
+
+// validateTop allows top-N over one dimension other than time, when
+// counting events.
+func validateTop(in GetMetricsInput) error {
+	if in.Top == 0 {
+		return nil
+	}
+	if in.Top < 0 || in.Top > MaxTop {
+		return fmt.Errorf("%w: n must be between 1 and %d", ErrInvalidTop, MaxTop)
+	}
+	dims := domain.GroupByDimensions(in.GroupBy)
+	if len(dims) != 1 || dims[0] == "time" {
+		return fmt.Errorf("%w: a single dimension other than time is required", ErrInvalidTop)
+	}
+	if in.Mode != domain.ModeCount {
+		return fmt.Errorf("%w: only supported when counting events", ErrInvalidTop)
+	}
+	return nil
+}
+
+// validatePage allows pages of grouped queries other than top-N ones.
+func validatePage(in GetMetricsInput) error {
+	if in.Limit < 0 || in.Limit > MaxGroups || in.Offset < 0 {
+		return fmt.Errorf("%w: limit must be between 1 and %d and offset not negative", ErrInvalidPage, MaxGroups)
+	}
+	if in.Limit == 0 && in.Offset == 0 {
+		return nil
+	}
+	if in.GroupBy == "" {
+		return fmt.Errorf("%w: limit and offset page groups and need a group_by", ErrInvalidPage)
+	}
+	if in.Top > 0 {
+		return fmt.Errorf("%w: top-N queries are not paged", ErrInvalidPage)
+	}
+	return nil
+}
+
+// validateOrder allows ordering the groups of grouped count queries other
+// than top-N ones; value needs an aggregate.
+func validateOrder(in GetMetricsInput) error {
+	if in.OrderBy == "" && in.Order == "" {
+		return nil
+	}
+	switch in.OrderBy {
+	case "", domain.OrderByKey, domain.OrderByTotalCount, domain.OrderByUniqueUsers:
+	case domain.OrderByValue:
+		if in.Aggregate == "" {
+			return fmt.Errorf("%w: ordering by value requires aggregate", ErrInvalidOrder)
+		}
+	default:
+		return fmt.Errorf("%w: order_by must be key, total_count, unique_users or value", ErrInvalidOrder)
+	}
+	if in.Order != "" && in.Order != domain.OrderAsc && in.Order != domain.OrderDesc {
+		return fmt.Errorf("%w: order must be asc or desc", ErrInvalidOrder)
+	}
+	if in.GroupBy == "" || in.Top > 0 || in.Mode != domain.ModeCount {
+		return fmt.Errorf("%w: only grouped counts other than top-N can be ordered", ErrInvalidOrder)
+	}
+	return nil
+}
+
+// validateCompare allows comparisons of event counts whose comparison window
+// starts after the epoch.
+func validateCompare(in GetMetricsInput) error {
+	switch in.Compare {
+	case "":
+		return nil
+	case domain.ComparePreviousPeriod, domain.ComparePreviousYear:
+	default:
+		return fmt.Errorf("%w: compare must be previous_period or previous_year", ErrInvalidCompare)
+	}
+	if in.Mode != domain.ModeCount {
+		return fmt.Errorf("%w: only supported when counting events", ErrInvalidCompare)
+	}
+	if from, _ := domain.ComparisonWindow(in.Compare, in.From, in.To); from <= 0 {
+		return fmt.Errorf("%w: the comparison window starts before 1970", ErrInvalidCompare)
+	}
+	return nil
+}
+
+// tagFilters validates the tags of in and returns them normalized like the
+// tags events are stored with.
+func tagFilters(in GetMetricsInput) ([]string, error) {
+	switch in.TagMatch {
+	case "", domain.TagMatchAll, domain.TagMatchAny:
+	default:
+		return nil, fmt.Errorf("%w: tag_match must be all or any", ErrInvalidTags)
+	}
+	if len(in.Tags) == 0 {
+		return nil, nil
+	}
+	for _, t := range in.Tags {
+		if strings.TrimSpace(t) == "" {
+			return nil, fmt.Errorf("%w: tags must not be empty", ErrInvalidTags)
+		}
+	}
+	tags := tag.Normalize(in.Tags)
+	if len(tags) > MaxTagFilters {
+		return nil, fmt.Errorf("%w: at most %d tags are allowed", ErrInvalidTags, MaxTagFilters)
+	}
+	return tags, nil
+}
+
+func validateMetadataFilters(filters map[string]string) error {
+	if len(filters) > MaxMetadataFilters {
+		return fmt.Errorf("%w: at most %d metadata filters are allowed", ErrInvalidMetadata, MaxMetadataFilters)
+	}
+	for k := range filters {
+		if k == "" || len(k) > MaxMetadataKeyLength {
+			return fmt.Errorf("%w: metadata key must be 1-%d characters", ErrInvalidMetadata, MaxMetadataKeyLength)
+		}
+	}
+	return nil
+}
@@ -3,7 +3,11 @@ package usecase_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"reflect"
 	"testing"
+	"time"
 
 	"event-metrics-service/internal/metrics/core/domain"
 	"event-metrics-service/internal/metrics/core/ports"
@@ -26,6 +30,41 @@ func (f *fakeMetricsReader) QueryMetrics(ctx context.Context, flt ports.MetricsF
 	return nil, nil
 }
 
+type fakeWatermark struct {
+	at    time.Time
+	err   error
+	calls int
+}
+
+func (f *fakeWatermark) CurrentWatermark(ctx context.Context) (time.Time, error) {
+	f.calls++
+	return f.at, f.err
+}
+
+type fakeStreamer struct {
+	lastFilter ports.MetricsFilter
+	called     bool
+	stall      bool // wait for ctx to end after head, like a slow cursor
+}
+
+func (f *fakeStreamer) StreamMetrics(
+	ctx context.Context,
+	flt ports.MetricsFilter,
+	head func(*domain.AggregatedMetrics) error,
+	emit func([]domain.MetricsGroup) error,
+) error {
+	f.called = true
+	f.lastFilter = flt
+	if err := head(&domain.AggregatedMetrics{EventName: flt.EventName, TotalCount: 3}); err != nil {
+		return err
+	}
+	if f.stall {
+		<-ctx.Done()
+		return errors.New("pq: canceling statement due to user request")
+	}
+	return emit([]domain.MetricsGroup{{Key: "web", TotalCount: 3}})
+}
+
 // ------------------------------------------------------------
 // SUCCESS (no group_by)
 // ------------------------------------------------------------
@@ -268,6 +307,123 @@ func TestGetMetrics_InvalidIntervalForTimeGroup(t *testing.T) {
 	}
 }
 
+func TestGetMetrics_WeekAndMonthIntervals(t *testing.T) {
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{}, nil
+		},
+	}
+	uc := usecase.NewGetMetricsUseCase(reader)
+
+	for _, interval := range []string{"week", "month"} {
+		in := usecase.GetMetricsInput{EventName: "product_view", From: 100, To: 200, GroupBy: "time", Interval: interval}
+		if _, err := uc.Execute(context.Background(), in); err != nil {
+			t.Fatalf("interval=%s: unexpected error: %v", interval, err)
+		}
+		if reader.lastFilter.Interval != interval {
+			t.Fatalf("expected interval=%s, got %s", interval, reader.lastFilter.Interval)
+		}
+	}
+
+	in := usecase.GetMetricsInput{EventName: "product_view", From: 100, To: 200, GroupBy: "time", Interval: "year"}
+	if _, err := uc.Execute(context.Background(), in); !errors.Is(err, usecase.ErrInvalidInterval) {
+		t.Fatalf("expected ErrInvalidInterval, got %v", err)
+	}
+}
+
+func TestGetMetrics_MinuteIntervalCapsBuckets(t *testing.T) {
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{}, nil
+		},
+	}
+	uc := usecase.NewGetMetricsUseCase(reader)
+
+	day := int64(24 * 60 * 60)
+	in := usecase.GetMetricsInput{EventName: "product_view", From: 1_765_000_000, To: 1_765_000_000 + day, GroupBy: "time", Interval: "minute"}
+	if _, err := uc.Execute(context.Background(), in); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reader.lastFilter.Interval != "minute" {
+		t.Fatalf("expected interval=minute, got %s", reader.lastFilter.Interval)
+	}
+
+	reader.called = false
+	in.To = in.From + 7*day
+	if _, err := uc.Execute(context.Background(), in); !errors.Is(err, usecase.ErrInvalidInterval) {
+		t.Fatalf("expected ErrInvalidInterval for 10081 buckets, got %v", err)
+	}
+	if reader.called {
+		t.Fatalf("repository should not be called over the bucket cap")
+	}
+
+	// The same range is fine hourly.
+	in.Interval = "hour"
+	if _, err := uc.Execute(context.Background(), in); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGetMetrics_ConfiguredRangeLimits(t *testing.T) {
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{}, nil
+		},
+	}
+	uc := usecase.NewGetMetricsUseCase(reader,
+		usecase.WithMaxRange(90*24*time.Hour),
+		usecase.WithMaxTimeBuckets(1000),
+	)
+
+	day := int64(24 * 60 * 60)
+	in := usecase.GetMetricsInput{EventName: "product_view", From: 1_765_000_000, To: 1_765_000_000 + 90*day}
+	if _, err := uc.Execute(context.Background(), in); err != nil {
+		t.Fatalf("unexpected error at the maximum range: %v", err)
+	}
+
+	in.To = in.From + 365*day
+	if _, err := uc.Execute(context.Background(), in); !errors.Is(err, usecase.ErrQueryRangeTooLarge) {
+		t.Fatalf("expected ErrQueryRangeTooLarge for a year, got %v", err)
+	}
+
+	// 60 days of hours is 1441 buckets, over the configured 1000.
+	in.To = in.From + 60*day
+	in.GroupBy, in.Interval = "time", "hour"
+	_, err := uc.Execute(context.Background(), in)
+	if !errors.Is(err, usecase.ErrQueryRangeTooLarge) || !errors.Is(err, usecase.ErrInvalidInterval) {
+		t.Fatalf("expected ErrQueryRangeTooLarge for 1441 buckets, got %v", err)
+	}
+	in.Interval = "day"
+	if _, err := uc.Execute(context.Background(), in); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestGetMetrics_QueryTimeout(t *testing.T) {
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			if _, ok := ctx.Deadline(); !ok {
+				t.Fatalf("expected a deadline on the query")
+			}
+			<-ctx.Done()
+			return nil, errors.New("pq: canceling statement due to user request")
+		},
+	}
+	uc := usecase.NewGetMetricsUseCase(reader, usecase.WithQueryTimeout(10*time.Millisecond))
+
+	in := usecase.GetMetricsInput{EventName: "product_view", From: 100, To: 200}
+	if _, err := uc.Execute(context.Background(), in); !errors.Is(err, usecase.ErrQueryTimeout) {
+		t.Fatalf("expected ErrQueryTimeout, got %v", err)
+	}
+
+	// A caller giving up is not a timeout of ours.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := uc.Execute(ctx, in); err == nil || errors.Is(err, usecase.ErrQueryTimeout) {
+		t.Fatalf("expected the reader error, got %v", err)
+	}
+}
+
 // ------------------------------------------------------------
 // VALIDATION: group_by bilinmeyen değer
 // ------------------------------------------------------------
@@ -298,6 +454,61 @@ func TestGetMetrics_InvalidGroupBy(t *testing.T) {
 	}
 }
 
+func TestGetMetrics_MultiDimensionGroupBy(t *testing.T) {
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{}, nil
+		},
+	}
+	uc := usecase.NewGetMetricsUseCase(reader)
+
+	base := usecase.GetMetricsInput{EventName: "product_view", From: 100, To: 200, Interval: "day"}
+
+	in := base
+	in.GroupBy = "channel,time"
+	if _, err := uc.Execute(context.Background(), in); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reader.lastFilter.GroupBy != "channel,time" || reader.lastFilter.Interval != "day" {
+		t.Fatalf("unexpected filter: %+v", reader.lastFilter)
+	}
+
+	in = base
+	in.GroupBy = "day_of_week,hour_of_day"
+	in.Interval = ""
+	if _, err := uc.Execute(context.Background(), in); err != nil {
+		t.Fatalf("calendar dimensions need no interval, got %v", err)
+	}
+
+	for groupBy, want := range map[string]error{
+		"channel,channel":                    usecase.ErrInvalidGroupBy,
+		"channel,":                           usecase.ErrInvalidGroupBy,
+		"channel,time,metadata.a,metadata.b": usecase.ErrInvalidGroupBy,
+		"channel,metadata.":                  usecase.ErrInvalidGroupBy,
+		"channel,something_else":             usecase.ErrInvalidGroupBy,
+	} {
+		in := base
+		in.GroupBy = groupBy
+		if _, err := uc.Execute(context.Background(), in); !errors.Is(err, want) {
+			t.Fatalf("group_by=%q: expected %v, got %v", groupBy, want, err)
+		}
+	}
+
+	in = base
+	in.GroupBy = "channel,time"
+	in.Interval = ""
+	if _, err := uc.Execute(context.Background(), in); !errors.Is(err, usecase.ErrInvalidInterval) {
+		t.Fatalf("expected ErrInvalidInterval, got %v", err)
+	}
+
+	in = base
+	in.GroupBy = "channel,time"
+	in.Mode = domain.ModeLag
+	if _, err := uc.Execute(context.Background(), in); !errors.Is(err, usecase.ErrInvalidGroupBy) {
+		t.Fatalf("expected ErrInvalidGroupBy in lag mode, got %v", err)
+	}
+}
+
 // ------------------------------------------------------------
 // REPOSITORY ERROR PROPAGATION
 // ------------------------------------------------------------
@@ -328,3 +539,859 @@ func TestGetMetrics_RepositoryError(t *testing.T) {
 		t.Fatalf("expected nil result on error")
 	}
 }
+
+// ------------------------------------------------------------
+// HISTOGRAM MODE
+// ------------------------------------------------------------
+
+func TestGetMetrics_Success_Histogram(t *testing.T) {
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			if flt.Mode != domain.ModeHistogram {
+				t.Fatalf("expected mode=histogram, got %s", flt.Mode)
+			}
+			if flt.Histogram == nil || flt.Histogram.Count != 10 {
+				t.Fatalf("expected histogram spec to be passed, got %+v", flt.Histogram)
+			}
+			return &domain.AggregatedMetrics{EventName: flt.EventName, Mode: flt.Mode}, nil
+		},
+	}
+
+	uc := usecase.NewGetMetricsUseCase(reader)
+
+	in := usecase.GetMetricsInput{
+		EventName: "order_placed",
+		From:      100,
+		To:        200,
+		Mode:      domain.ModeHistogram,
+		Histogram: &domain.HistogramSpec{Min: 0, Max: 500, Count: 10},
+	}
+
+	if _, err := uc.Execute(context.Background(), in); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reader.called {
+		t.Fatalf("expected QueryMetrics to be called")
+	}
+}
+
+func TestGetMetrics_InvalidHistogram(t *testing.T) {
+	tests := []struct {
+		name string
+		in   usecase.GetMetricsInput
+	}{
+		{"missing spec", usecase.GetMetricsInput{Mode: domain.ModeHistogram}},
+		{"zero buckets", usecase.GetMetricsInput{Mode: domain.ModeHistogram, Histogram: &domain.HistogramSpec{Min: 0, Max: 10, Count: 0}}},
+		{"too many buckets", usecase.GetMetricsInput{Mode: domain.ModeHistogram, Histogram: &domain.HistogramSpec{Min: 0, Max: 10, Count: usecase.MaxHistogramBuckets + 1}}},
+		{"max <= min", usecase.GetMetricsInput{Mode: domain.ModeHistogram, Histogram: &domain.HistogramSpec{Min: 10, Max: 10, Count: 5}}},
+		{"with group_by", usecase.GetMetricsInput{Mode: domain.ModeHistogram, GroupBy: "channel", Histogram: &domain.HistogramSpec{Min: 0, Max: 10, Count: 5}}},
+		{"one bound", usecase.GetMetricsInput{Mode: domain.ModeHistogram, Histogram: &domain.HistogramSpec{Bounds: []float64{10}}}},
+		{"bounds not ascending", usecase.GetMetricsInput{Mode: domain.ModeHistogram, Histogram: &domain.HistogramSpec{Bounds: []float64{0, 50, 10}}}},
+		{"infinite bound", usecase.GetMetricsInput{Mode: domain.ModeHistogram, Histogram: &domain.HistogramSpec{Bounds: []float64{0, math.Inf(1)}}}},
+		{"bounds and count", usecase.GetMetricsInput{Mode: domain.ModeHistogram, Histogram: &domain.HistogramSpec{Bounds: []float64{0, 10}, Count: 5}}},
+		{"field not metadata", usecase.GetMetricsInput{Mode: domain.ModeHistogram, Histogram: &domain.HistogramSpec{Bounds: []float64{0, 10}, Field: "amount"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &fakeMetricsReader{}
+			uc := usecase.NewGetMetricsUseCase(reader)
+
+			in := tt.in
+			in.EventName = "order_placed"
+			in.From = 100
+			in.To = 200
+
+			_, err := uc.Execute(context.Background(), in)
+			if !errors.Is(err, usecase.ErrInvalidHistogram) {
+				t.Fatalf("expected ErrInvalidHistogram, got %v", err)
+			}
+			if reader.called {
+				t.Fatalf("repository should not be called on invalid histogram")
+			}
+		})
+	}
+}
+
+// ------------------------------------------------------------
+// LAG MODE
+// ------------------------------------------------------------
+
+func TestGetMetrics_Success_LagGroupedByChannel(t *testing.T) {
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			if flt.Mode != domain.ModeLag || flt.GroupBy != "channel" {
+				t.Fatalf("expected mode=lag grouped by channel, got %+v", flt)
+			}
+			return &domain.AggregatedMetrics{EventName: flt.EventName, Mode: flt.Mode, Lag: &domain.LagStats{P95Seconds: 4}}, nil
+		},
+	}
+
+	uc := usecase.NewGetMetricsUseCase(reader)
+
+	res, err := uc.Execute(context.Background(), usecase.GetMetricsInput{
+		EventName: "order_placed",
+		From:      100,
+		To:        200,
+		GroupBy:   "channel",
+		Mode:      domain.ModeLag,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Lag == nil || res.Lag.P95Seconds != 4 {
+		t.Fatalf("expected lag stats to be returned, got %+v", res.Lag)
+	}
+}
+
+func TestGetMetrics_InvalidMode(t *testing.T) {
+	reader := &fakeMetricsReader{}
+	uc := usecase.NewGetMetricsUseCase(reader)
+
+	_, err := uc.Execute(context.Background(), usecase.GetMetricsInput{
+		EventName: "product_view",
+		From:      100,
+		To:        200,
+		Mode:      "pie",
+	})
+	if !errors.Is(err, usecase.ErrInvalidMode) {
+		t.Fatalf("expected ErrInvalidMode, got %v", err)
+	}
+}
+
+// ------------------------------------------------------------
+// METADATA FILTERS / GROUP BY
+// ------------------------------------------------------------
+
+func TestGetMetrics_MetadataGroupAndFilters(t *testing.T) {
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{}, nil
+		},
+	}
+	uc := usecase.NewGetMetricsUseCase(reader)
+
+	_, err := uc.Execute(context.Background(), usecase.GetMetricsInput{
+		EventName: "product_view",
+		From:      100,
+		To:        200,
+		GroupBy:   "metadata.product_id",
+		Metadata:  map[string]string{"category": "shoes"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reader.lastFilter.GroupBy != "metadata.product_id" || reader.lastFilter.Metadata["category"] != "shoes" {
+		t.Fatalf("unexpected filter: %+v", reader.lastFilter)
+	}
+}
+
+func TestGetMetrics_InvalidMetadata(t *testing.T) {
+	uc := usecase.NewGetMetricsUseCase(&fakeMetricsReader{})
+
+	base := usecase.GetMetricsInput{EventName: "product_view", From: 100, To: 200}
+
+	in := base
+	in.GroupBy = "metadata."
+	if _, err := uc.Execute(context.Background(), in); !errors.Is(err, usecase.ErrInvalidGroupBy) {
+		t.Fatalf("expected ErrInvalidGroupBy, got %v", err)
+	}
+
+	in = base
+	in.Metadata = map[string]string{"": "x"}
+	if _, err := uc.Execute(context.Background(), in); !errors.Is(err, usecase.ErrInvalidMetadata) {
+		t.Fatalf("expected ErrInvalidMetadata, got %v", err)
+	}
+}
+
+func TestGetMetrics_SeveralEventNames(t *testing.T) {
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{EventName: flt.EventName}, nil
+		},
+	}
+	uc := usecase.NewGetMetricsUseCase(reader)
+
+	res, err := uc.Execute(context.Background(), usecase.GetMetricsInput{EventName: "signup_web, signup_mobile,,signup_web", From: 100, To: 200})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reader.lastFilter.EventName != "signup_web,signup_mobile" || res.EventName != "signup_web,signup_mobile" {
+		t.Fatalf("unexpected event names: %q", reader.lastFilter.EventName)
+	}
+
+	names := "e0"
+	for i := 1; i <= usecase.MaxEventNames; i++ {
+		names += fmt.Sprintf(",e%d", i)
+	}
+	for _, name := range []string{" , ", names} {
+		if _, err := uc.Execute(context.Background(), usecase.GetMetricsInput{EventName: name, From: 100, To: 200}); !errors.Is(err, usecase.ErrInvalidMetricsQuery) {
+			t.Fatalf("%q: expected ErrInvalidMetricsQuery, got %v", name, err)
+		}
+	}
+}
+
+func TestGetMetrics_Tags(t *testing.T) {
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{}, nil
+		},
+	}
+	uc := usecase.NewGetMetricsUseCase(reader)
+
+	base := usecase.GetMetricsInput{EventName: "purchase", From: 100, To: 200, Tags: []string{"promo", "beta"}, TagMatch: domain.TagMatchAny}
+	if _, err := uc.Execute(context.Background(), base); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reader.lastFilter.Tags) != 2 || reader.lastFilter.TagMatch != "any" {
+		t.Fatalf("unexpected filter: %+v", reader.lastFilter)
+	}
+
+	// Tags are matched the way ingest stores them: trimmed and lowercased.
+	mixed := base
+	mixed.Tags = []string{"Promo", " Beta", "PROMO "}
+	if _, err := uc.Execute(context.Background(), mixed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(reader.lastFilter.Tags, []string{"promo", "beta"}) {
+		t.Fatalf("expected normalized tags, got %q", reader.lastFilter.Tags)
+	}
+
+	tooMany := make([]string, usecase.MaxTagFilters+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("t%d", i)
+	}
+	for name, mutate := range map[string]func(*usecase.GetMetricsInput){
+		"unknown match": func(in *usecase.GetMetricsInput) { in.TagMatch = "some" },
+		"empty tag":     func(in *usecase.GetMetricsInput) { in.Tags = []string{"promo", ""} },
+		"blank tag":     func(in *usecase.GetMetricsInput) { in.Tags = []string{"promo", "  "} },
+		"too many tags": func(in *usecase.GetMetricsInput) { in.Tags = tooMany },
+	} {
+		in := base
+		mutate(&in)
+		if _, err := uc.Execute(context.Background(), in); !errors.Is(err, usecase.ErrInvalidTags) {
+			t.Fatalf("%s: expected ErrInvalidTags, got %v", name, err)
+		}
+	}
+}
+
+func TestGetMetrics_Aggregate(t *testing.T) {
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{}, nil
+		},
+	}
+	uc := usecase.NewGetMetricsUseCase(reader)
+
+	base := usecase.GetMetricsInput{EventName: "purchase", From: 100, To: 200, GroupBy: "channel"}
+
+	in := base
+	in.Aggregate, in.Field = domain.AggregateSum, "metadata.revenue"
+	if _, err := uc.Execute(context.Background(), in); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reader.lastFilter.Aggregate != "sum" || reader.lastFilter.Field != "metadata.revenue" {
+		t.Fatalf("unexpected filter: %+v", reader.lastFilter)
+	}
+
+	in.Aggregate = domain.AggregateP99
+	if _, err := uc.Execute(context.Background(), in); err != nil || reader.lastFilter.Aggregate != "p99" {
+		t.Fatalf("expected p99 to be accepted, got %v (%+v)", err, reader.lastFilter)
+	}
+
+	for name, mutate := range map[string]func(*usecase.GetMetricsInput){
+		"unknown aggregate":  func(in *usecase.GetMetricsInput) { in.Aggregate, in.Field = "median", "metadata.revenue" },
+		"missing field":      func(in *usecase.GetMetricsInput) { in.Aggregate = domain.AggregateAvg },
+		"non-metadata field": func(in *usecase.GetMetricsInput) { in.Aggregate, in.Field = domain.AggregateMax, "value" },
+		"field without agg":  func(in *usecase.GetMetricsInput) { in.Field = "metadata.revenue" },
+		"aggregate with lag": func(in *usecase.GetMetricsInput) {
+			in.Aggregate, in.Field, in.Mode = domain.AggregateMin, "metadata.revenue", domain.ModeLag
+		},
+	} {
+		in := base
+		mutate(&in)
+		if _, err := uc.Execute(context.Background(), in); !errors.Is(err, usecase.ErrInvalidAggregate) {
+			t.Fatalf("%s: expected ErrInvalidAggregate, got %v", name, err)
+		}
+	}
+}
+
+func TestGetMetrics_Top(t *testing.T) {
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{}, nil
+		},
+	}
+	uc := usecase.NewGetMetricsUseCase(reader)
+
+	base := usecase.GetMetricsInput{EventName: "purchase", From: 100, To: 200, GroupBy: domain.DimensionUser, Top: 5}
+
+	if _, err := uc.Execute(context.Background(), base); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reader.lastFilter.Top != 5 || reader.lastFilter.GroupBy != "user_id" {
+		t.Fatalf("unexpected filter: %+v", reader.lastFilter)
+	}
+
+	for name, mutate := range map[string]func(*usecase.GetMetricsInput){
+		"negative n":        func(in *usecase.GetMetricsInput) { in.Top = -1 },
+		"n above max":       func(in *usecase.GetMetricsInput) { in.Top = usecase.MaxTop + 1 },
+		"time dimension":    func(in *usecase.GetMetricsInput) { in.GroupBy, in.Interval = "time", "hour" },
+		"no dimension":      func(in *usecase.GetMetricsInput) { in.GroupBy = "" },
+		"two dimensions":    func(in *usecase.GetMetricsInput) { in.GroupBy = "channel,campaign_id" },
+		"top with lag mode": func(in *usecase.GetMetricsInput) { in.Mode = domain.ModeLag },
+	} {
+		in := base
+		mutate(&in)
+		if _, err := uc.Execute(context.Background(), in); !errors.Is(err, usecase.ErrInvalidTop) {
+			t.Fatalf("%s: expected ErrInvalidTop, got %v", name, err)
+		}
+	}
+}
+
+func TestGetMetrics_ComparePreviousPeriod(t *testing.T) {
+	from := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC).Unix()
+	to := from + 2*3600 - 1
+
+	var filters []ports.MetricsFilter
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			filters = append(filters, flt)
+			if flt.From == from {
+				return &domain.AggregatedMetrics{From: flt.From, To: flt.To, TotalCount: 30, UniqueUsers: 6, Groups: []domain.MetricsGroup{
+					{Key: "2025-12-07T10:00:00Z", TotalCount: 10, UniqueUsers: 2},
+					{Key: "2025-12-07T11:00:00Z", TotalCount: 20, UniqueUsers: 4},
+				}}, nil
+			}
+			return &domain.AggregatedMetrics{From: flt.From, To: flt.To, TotalCount: 20, UniqueUsers: 6, Groups: []domain.MetricsGroup{
+				{Key: "2025-12-07T08:00:00Z", TotalCount: 5, UniqueUsers: 2},
+			}}, nil
+		},
+	}
+	uc := usecase.NewGetMetricsUseCase(reader)
+
+	res, err := uc.Execute(context.Background(), usecase.GetMetricsInput{
+		EventName: "purchase", From: from, To: to, GroupBy: "time", Interval: "hour",
+		Compare: domain.ComparePreviousPeriod,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(filters) != 2 || filters[1].From != from-7200 || filters[1].To != from-1 {
+		t.Fatalf("unexpected comparison window: %+v", filters)
+	}
+	cmp := res.Comparison
+	if cmp == nil || cmp.Compare != domain.ComparePreviousPeriod || cmp.Previous.TotalCount != 20 {
+		t.Fatalf("unexpected comparison: %+v", cmp)
+	}
+	if cmp.Change.TotalCount.Delta != 10 || *cmp.Change.TotalCount.Percent != 50 || *cmp.Change.UniqueUsers.Percent != 0 {
+		t.Fatalf("unexpected change: %+v", cmp.Change)
+	}
+
+	// 10:00 is compared with 08:00, the first hour of the previous period;
+	// 11:00 has no counterpart.
+	first, second := res.Groups[0].Change, res.Groups[1].Change
+	if first == nil || first.TotalCount.Delta != 5 || *first.TotalCount.Percent != 100 {
+		t.Fatalf("unexpected first bucket change: %+v", first)
+	}
+	if second == nil || second.TotalCount.Delta != 20 || second.TotalCount.Percent != nil {
+		t.Fatalf("unexpected second bucket change: %+v", second)
+	}
+}
+
+func TestGetMetrics_ComparePreviousYear(t *testing.T) {
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC).Unix()
+	to := time.Date(2025, 3, 31, 23, 59, 59, 0, time.UTC).Unix()
+
+	value := func(v float64) *float64 { return &v }
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			if flt.From == from {
+				return &domain.AggregatedMetrics{Value: value(150), Groups: []domain.MetricsGroup{
+					{Key: "web", Value: value(100)},
+				}}, nil
+			}
+			return &domain.AggregatedMetrics{Value: value(100), Groups: []domain.MetricsGroup{
+				{Key: "ios", Value: value(80)},
+				{Key: "web", Value: value(80)},
+			}}, nil
+		},
+	}
+	uc := usecase.NewGetMetricsUseCase(reader)
+
+	res, err := uc.Execute(context.Background(), usecase.GetMetricsInput{
+		EventName: "purchase", From: from, To: to, GroupBy: "channel",
+		Aggregate: "sum", Field: "metadata.revenue", Compare: domain.ComparePreviousYear,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantFrom := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC).Unix()
+	wantTo := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC).Unix()
+	if reader.lastFilter.From != wantFrom || reader.lastFilter.To != wantTo {
+		t.Fatalf("unexpected comparison window: %d-%d", reader.lastFilter.From, reader.lastFilter.To)
+	}
+	if v := res.Comparison.Change.Value; v == nil || v.Delta != 50 || *v.Percent != 50 {
+		t.Fatalf("unexpected value change: %+v", v)
+	}
+	if v := res.Groups[0].Change.Value; v == nil || v.Delta != 20 || *v.Percent != 25 {
+		t.Fatalf("unexpected web change: %+v", v)
+	}
+}
+
+func TestGetMetrics_InvalidCompare(t *testing.T) {
+	uc := usecase.NewGetMetricsUseCase(&fakeMetricsReader{})
+
+	base := usecase.GetMetricsInput{EventName: "purchase", From: 1000, To: 2000, Compare: domain.ComparePreviousPeriod}
+	for name, mutate := range map[string]func(*usecase.GetMetricsInput){
+		"unknown compare":       func(in *usecase.GetMetricsInput) { in.Compare = "last_week" },
+		"lag mode":              func(in *usecase.GetMetricsInput) { in.Mode = domain.ModeLag },
+		"window before epoch":   func(in *usecase.GetMetricsInput) { in.From, in.To = 100, 300 },
+		"year before the epoch": func(in *usecase.GetMetricsInput) { in.Compare = domain.ComparePreviousYear },
+	} {
+		in := base
+		mutate(&in)
+		if _, err := uc.Execute(context.Background(), in); !errors.Is(err, usecase.ErrInvalidCompare) {
+			t.Fatalf("%s: expected ErrInvalidCompare, got %v", name, err)
+		}
+	}
+}
+
+func TestGetMetrics_Conversion(t *testing.T) {
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, f ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			if f.Mode != domain.ModeCount {
+				t.Fatalf("expected plain counts, got mode %q", f.Mode)
+			}
+			if f.EventName == "purchase" {
+				return &domain.AggregatedMetrics{EventName: f.EventName, TotalCount: 6, UniqueUsers: 5, Groups: []domain.MetricsGroup{
+					{Key: "2025-12-07T10:00:00Z", TotalCount: 2, UniqueUsers: 2},
+					{Key: "2025-12-07T12:00:00Z", TotalCount: 4, UniqueUsers: 3},
+				}}, nil
+			}
+			return &domain.AggregatedMetrics{EventName: f.EventName, TotalCount: 60, UniqueUsers: 20, Groups: []domain.MetricsGroup{
+				{Key: "2025-12-07T10:00:00Z", TotalCount: 40, UniqueUsers: 10},
+				{Key: "2025-12-07T11:00:00Z", TotalCount: 20, UniqueUsers: 10},
+			}}, nil
+		},
+	}
+	uc := usecase.NewGetMetricsUseCase(reader)
+
+	res, err := uc.Execute(context.Background(), usecase.GetMetricsInput{
+		EventName:   "purchase",
+		Denominator: "product_view",
+		From:        1000,
+		To:          2000,
+		GroupBy:     "time",
+		Interval:    "hour",
+		Mode:        domain.ModeConversion,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reader.lastFilter.EventName != "product_view" {
+		t.Fatalf("expected the denominator query last, got %+v", reader.lastFilter)
+	}
+
+	c := res.Conversion
+	if res.Mode != domain.ModeConversion || c == nil || c.Denominator != "product_view" || *c.Rate != 0.1 || *c.UserRate != 0.25 {
+		t.Fatalf("unexpected conversion: %+v", c)
+	}
+	if len(res.Groups) != 3 {
+		t.Fatalf("expected the buckets of both sides, got %+v", res.Groups)
+	}
+	for i, want := range []struct {
+		key     string
+		rate    float64
+		hasRate bool // no rate without denominator events
+	}{
+		{"2025-12-07T10:00:00Z", 0.05, true},
+		{"2025-12-07T11:00:00Z", 0, true},
+		{"2025-12-07T12:00:00Z", 0, false},
+	} {
+		g := res.Groups[i]
+		if g.Key != want.key || (g.Conversion.Rate != nil) != want.hasRate ||
+			(want.hasRate && *g.Conversion.Rate != want.rate) {
+			t.Fatalf("group %d: expected %s at %v, got %+v", i, want.key, want.rate, g.Conversion)
+		}
+	}
+}
+
+func TestGetMetrics_InvalidConversion(t *testing.T) {
+	reader := &fakeMetricsReader{}
+	uc := usecase.NewGetMetricsUseCase(reader)
+
+	base := usecase.GetMetricsInput{EventName: "purchase", Denominator: "product_view", From: 1000, To: 2000, Mode: domain.ModeConversion}
+	for name, tc := range map[string]struct {
+		mutate func(*usecase.GetMetricsInput)
+		want   error
+	}{
+		"no denominator": {func(in *usecase.GetMetricsInput) { in.Denominator = " , " }, usecase.ErrInvalidMode},
+		"paged":          {func(in *usecase.GetMetricsInput) { in.GroupBy, in.Limit = "channel", 10 }, usecase.ErrInvalidPage},
+		"compare":        {func(in *usecase.GetMetricsInput) { in.Compare = domain.ComparePreviousPeriod }, usecase.ErrInvalidCompare},
+		"aggregate": {func(in *usecase.GetMetricsInput) {
+			in.Aggregate, in.Field = domain.AggregateSum, "metadata.amount"
+		}, usecase.ErrInvalidAggregate},
+	} {
+		in := base
+		tc.mutate(&in)
+		if _, err := uc.Execute(context.Background(), in); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", name, tc.want, err)
+		}
+	}
+	if reader.called {
+		t.Fatalf("reader should not be called on invalid conversions")
+	}
+}
+
+func TestGetMetrics_GroupPages(t *testing.T) {
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{}, nil
+		},
+	}
+	uc := usecase.NewGetMetricsUseCase(reader)
+
+	base := usecase.GetMetricsInput{EventName: "product_view", From: 100, To: 200, GroupBy: "metadata.product_id"}
+
+	if _, err := uc.Execute(context.Background(), base); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reader.lastFilter.Limit != usecase.MaxGroups || reader.lastFilter.Offset != 0 {
+		t.Fatalf("expected the groups to be capped at MaxGroups, got %+v", reader.lastFilter)
+	}
+
+	in := base
+	in.Limit, in.Offset = 100, 300
+	if _, err := uc.Execute(context.Background(), in); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reader.lastFilter.Limit != 100 || reader.lastFilter.Offset != 300 {
+		t.Fatalf("unexpected page: %+v", reader.lastFilter)
+	}
+
+	in = base
+	in.GroupBy, in.Top = "user_id", 5
+	if _, err := uc.Execute(context.Background(), in); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reader.lastFilter.Limit != 0 {
+		t.Fatalf("expected top-N queries not to be paged, got %+v", reader.lastFilter)
+	}
+
+	for name, mutate := range map[string]func(*usecase.GetMetricsInput){
+		"negative limit":    func(in *usecase.GetMetricsInput) { in.Limit = -1 },
+		"limit above max":   func(in *usecase.GetMetricsInput) { in.Limit = usecase.MaxGroups + 1 },
+		"negative offset":   func(in *usecase.GetMetricsInput) { in.Offset = -1 },
+		"without group_by":  func(in *usecase.GetMetricsInput) { in.GroupBy, in.Limit = "", 10 },
+		"top-N with offset": func(in *usecase.GetMetricsInput) { in.GroupBy, in.Top, in.Offset = "user_id", 5, 5 },
+	} {
+		in := base
+		mutate(&in)
+		if _, err := uc.Execute(context.Background(), in); !errors.Is(err, usecase.ErrInvalidPage) {
+			t.Fatalf("%s: expected ErrInvalidPage, got %v", name, err)
+		}
+	}
+}
+
+func TestGetMetrics_GroupOrder(t *testing.T) {
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{}, nil
+		},
+	}
+	uc := usecase.NewGetMetricsUseCase(reader)
+
+	base := usecase.GetMetricsInput{EventName: "purchase", From: 100, To: 200, GroupBy: "channel"}
+
+	for _, tt := range []struct {
+		orderBy, order         string
+		wantOrderBy, wantOrder string
+	}{
+		{"", "", "key", "asc"},
+		{"", "desc", "key", "desc"},
+		{"total_count", "", "total_count", "desc"},
+		{"unique_users", "asc", "unique_users", "asc"},
+	} {
+		in := base
+		in.OrderBy, in.Order = tt.orderBy, tt.order
+		if _, err := uc.Execute(context.Background(), in); err != nil {
+			t.Fatalf("%s %s: unexpected error: %v", tt.orderBy, tt.order, err)
+		}
+		if reader.lastFilter.OrderBy != tt.wantOrderBy || reader.lastFilter.Order != tt.wantOrder {
+			t.Fatalf("%s %s: unexpected order %q %q", tt.orderBy, tt.order, reader.lastFilter.OrderBy, reader.lastFilter.Order)
+		}
+	}
+
+	for name, mutate := range map[string]func(*usecase.GetMetricsInput){
+		"unknown order_by":        func(in *usecase.GetMetricsInput) { in.OrderBy = "revenue" },
+		"unknown order":           func(in *usecase.GetMetricsInput) { in.Order = "up" },
+		"value without aggregate": func(in *usecase.GetMetricsInput) { in.OrderBy = "value" },
+		"without group_by":        func(in *usecase.GetMetricsInput) { in.GroupBy, in.OrderBy = "", "total_count" },
+		"top-N":                   func(in *usecase.GetMetricsInput) { in.Top, in.OrderBy = 5, "total_count" },
+		"lag mode":                func(in *usecase.GetMetricsInput) { in.Mode, in.OrderBy = domain.ModeLag, "total_count" },
+	} {
+		in := base
+		mutate(&in)
+		if _, err := uc.Execute(context.Background(), in); !errors.Is(err, usecase.ErrInvalidOrder) {
+			t.Fatalf("%s: expected ErrInvalidOrder, got %v", name, err)
+		}
+	}
+}
+
+// ------------------------------------------------------------
+// AS OF / BATCH
+// ------------------------------------------------------------
+
+func TestGetMetrics_AsOfLatestResolvesWatermark(t *testing.T) {
+	wm := &fakeWatermark{at: time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)}
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{AsOf: flt.AsOf}, nil
+		},
+	}
+	uc := usecase.NewGetMetricsUseCase(reader, usecase.WithWatermark(wm))
+
+	_, err := uc.Execute(context.Background(), usecase.GetMetricsInput{
+		EventName:  "product_view",
+		From:       100,
+		To:         200,
+		AsOfLatest: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reader.lastFilter.AsOf == nil || !reader.lastFilter.AsOf.Equal(wm.at) {
+		t.Fatalf("expected filter pinned to %v, got %v", wm.at, reader.lastFilter.AsOf)
+	}
+}
+
+func TestGetMetrics_AsOfLatestWithoutWatermark(t *testing.T) {
+	uc := usecase.NewGetMetricsUseCase(&fakeMetricsReader{})
+
+	_, err := uc.Execute(context.Background(), usecase.GetMetricsInput{
+		EventName:  "product_view",
+		From:       100,
+		To:         200,
+		AsOfLatest: true,
+	})
+	if !errors.Is(err, usecase.ErrWatermarkDisabled) {
+		t.Fatalf("expected ErrWatermarkDisabled, got %v", err)
+	}
+}
+
+func TestGetMetricsBatch_PinsAllQueriesToOneWatermark(t *testing.T) {
+	wm := &fakeWatermark{at: time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)}
+
+	var seen []*time.Time
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			seen = append(seen, flt.AsOf)
+			return &domain.AggregatedMetrics{EventName: flt.EventName, AsOf: flt.AsOf}, nil
+		},
+	}
+	uc := usecase.NewGetMetricsUseCase(reader, usecase.WithWatermark(wm))
+
+	// A per-query as_of must not break the snapshot.
+	other := wm.at.Add(-time.Hour)
+	res, err := uc.ExecuteBatch(context.Background(), usecase.BatchGetMetricsInput{
+		Queries: []usecase.GetMetricsInput{
+			{EventName: "product_view", From: 100, To: 200},
+			{EventName: "purchase", From: 100, To: 200, GroupBy: "channel", AsOf: &other},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if wm.calls != 1 {
+		t.Fatalf("expected watermark to be resolved once, got %d", wm.calls)
+	}
+	if !res.AsOf.Equal(wm.at) || len(res.Results) != 2 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	for i, asOf := range seen {
+		if asOf == nil || !asOf.Equal(wm.at) {
+			t.Fatalf("query %d not pinned to watermark: %v", i, asOf)
+		}
+	}
+}
+
+func TestGetMetricsBatch_ExplicitAsOf(t *testing.T) {
+	wm := &fakeWatermark{}
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{}, nil
+		},
+	}
+	uc := usecase.NewGetMetricsUseCase(reader, usecase.WithWatermark(wm))
+
+	asOf := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	res, err := uc.ExecuteBatch(context.Background(), usecase.BatchGetMetricsInput{
+		AsOf:    &asOf,
+		Queries: []usecase.GetMetricsInput{{EventName: "product_view", From: 100, To: 200}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if wm.calls != 0 {
+		t.Fatalf("explicit as_of must not resolve the watermark")
+	}
+	if !res.AsOf.Equal(asOf) || !reader.lastFilter.AsOf.Equal(asOf) {
+		t.Fatalf("expected as_of %v, got %v / %v", asOf, res.AsOf, reader.lastFilter.AsOf)
+	}
+}
+
+func TestGetMetricsBatch_ValidatesBeforeQuerying(t *testing.T) {
+	reader := &fakeMetricsReader{}
+	uc := usecase.NewGetMetricsUseCase(reader, usecase.WithWatermark(&fakeWatermark{}))
+
+	_, err := uc.ExecuteBatch(context.Background(), usecase.BatchGetMetricsInput{
+		Queries: []usecase.GetMetricsInput{
+			{EventName: "product_view", From: 100, To: 200},
+			{EventName: "purchase", From: 200, To: 100},
+		},
+	})
+	if !errors.Is(err, usecase.ErrInvalidTimeRange) {
+		t.Fatalf("expected ErrInvalidTimeRange, got %v", err)
+	}
+	if reader.called {
+		t.Fatalf("reader must not be called when a query is invalid")
+	}
+
+	if _, err := uc.ExecuteBatch(context.Background(), usecase.BatchGetMetricsInput{}); !errors.Is(err, usecase.ErrInvalidBatch) {
+		t.Fatalf("expected ErrInvalidBatch for empty batch, got %v", err)
+	}
+
+	tooMany := make([]usecase.GetMetricsInput, usecase.MaxBatchQueries+1)
+	if _, err := uc.ExecuteBatch(context.Background(), usecase.BatchGetMetricsInput{Queries: tooMany}); !errors.Is(err, usecase.ErrInvalidBatch) {
+		t.Fatalf("expected ErrInvalidBatch for oversized batch, got %v", err)
+	}
+}
+
+func TestGetMetrics_ScopesQueriesToTenant(t *testing.T) {
+	var seen []*string
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, flt ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			seen = append(seen, flt.Tenant)
+			return &domain.AggregatedMetrics{EventName: flt.EventName}, nil
+		},
+	}
+	uc := usecase.NewGetMetricsUseCase(reader,
+		usecase.WithWatermark(&fakeWatermark{at: time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)}),
+		usecase.WithTenantResolver(func(ctx context.Context) string {
+			return "team-a"
+		}),
+	)
+
+	if _, err := uc.Execute(context.Background(), usecase.GetMetricsInput{EventName: "product_view", From: 100, To: 200}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := uc.ExecuteBatch(context.Background(), usecase.BatchGetMetricsInput{
+		Queries: []usecase.GetMetricsInput{{EventName: "purchase", From: 100, To: 200}},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(seen) != 2 {
+		t.Fatalf("expected 2 queries, got %d", len(seen))
+	}
+	for i, tenant := range seen {
+		if tenant == nil || *tenant != "team-a" {
+			t.Fatalf("query %d not scoped to tenant: %v", i, tenant)
+		}
+	}
+}
+
+// ------------------------------------------------------------
+// STREAMING
+// ------------------------------------------------------------
+
+func TestExecuteStream(t *testing.T) {
+	streamer := &fakeStreamer{}
+	uc := usecase.NewGetMetricsUseCase(&fakeMetricsReader{}, usecase.WithStreamer(streamer), usecase.WithTenantResolver(func(ctx context.Context) string { return "acme" }))
+
+	var heads, groups int
+	err := uc.ExecuteStream(context.Background(),
+		usecase.GetMetricsInput{EventName: "purchase", From: 100, To: 200, GroupBy: "channel", OrderBy: "total_count"},
+		func(*domain.AggregatedMetrics) error {
+			heads++
+			return nil
+		},
+		func(g []domain.MetricsGroup) error {
+			groups += len(g)
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if heads != 1 || groups != 1 {
+		t.Fatalf("unexpected stream: %d heads, %d groups", heads, groups)
+	}
+
+	f := streamer.lastFilter
+	if f.Limit != 0 || f.Offset != 0 {
+		t.Fatalf("expected every group, got limit=%d offset=%d", f.Limit, f.Offset)
+	}
+	if f.OrderBy != "total_count" || f.Order != "desc" {
+		t.Fatalf("expected the group order to apply, got %q %q", f.OrderBy, f.Order)
+	}
+	if f.Tenant == nil || *f.Tenant != "acme" {
+		t.Fatalf("expected the tenant to apply, got %v", f.Tenant)
+	}
+}
+
+func TestExecuteStream_QueryTimeout(t *testing.T) {
+	streamer := &fakeStreamer{stall: true}
+	uc := usecase.NewGetMetricsUseCase(&fakeMetricsReader{}, usecase.WithStreamer(streamer), usecase.WithQueryTimeout(10*time.Millisecond))
+
+	in := usecase.GetMetricsInput{EventName: "purchase", From: 100, To: 200, GroupBy: "channel"}
+	noop := func(*domain.AggregatedMetrics) error { return nil }
+	emit := func([]domain.MetricsGroup) error { return nil }
+	if err := uc.ExecuteStream(context.Background(), in, noop, emit); !errors.Is(err, usecase.ErrQueryTimeout) {
+		t.Fatalf("expected ErrQueryTimeout, got %v", err)
+	}
+
+	// A caller giving up is not a timeout of ours.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := uc.ExecuteStream(ctx, in, noop, emit); err == nil || errors.Is(err, usecase.ErrQueryTimeout) {
+		t.Fatalf("expected the streamer error, got %v", err)
+	}
+}
+
+func TestValidateStream(t *testing.T) {
+	base := usecase.GetMetricsInput{EventName: "purchase", From: 1733529600, To: 1733616000, GroupBy: "channel"}
+
+	if err := usecase.NewGetMetricsUseCase(&fakeMetricsReader{}).ValidateStream(base); !errors.Is(err, usecase.ErrStreamDisabled) {
+		t.Fatalf("expected ErrStreamDisabled without a streamer, got %v", err)
+	}
+
+	uc := usecase.NewGetMetricsUseCase(&fakeMetricsReader{}, usecase.WithStreamer(&fakeStreamer{}))
+	if err := uc.ValidateStream(base); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for name, mutate := range map[string]func(*usecase.GetMetricsInput){
+		"without group_by": func(in *usecase.GetMetricsInput) { in.GroupBy = "" },
+		"top-N":            func(in *usecase.GetMetricsInput) { in.Top = 5 },
+		"compare":          func(in *usecase.GetMetricsInput) { in.Compare = domain.ComparePreviousPeriod },
+		"lag mode":         func(in *usecase.GetMetricsInput) { in.Mode = domain.ModeLag },
+		"limit":            func(in *usecase.GetMetricsInput) { in.Limit = 100 },
+		"offset":           func(in *usecase.GetMetricsInput) { in.Offset = 100 },
+	} {
+		in := base
+		mutate(&in)
+		if err := uc.ValidateStream(in); !errors.Is(err, usecase.ErrInvalidStream) {
+			t.Fatalf("%s: expected ErrInvalidStream, got %v", name, err)
+		}
+	}
+
+	in := base
+	in.Interval = "fortnight"
+	in.GroupBy = "time"
+	if err := uc.ValidateStream(in); !errors.Is(err, usecase.ErrInvalidInterval) {
+		t.Fatalf("expected the query to be validated too, got %v", err)
+	}
+}
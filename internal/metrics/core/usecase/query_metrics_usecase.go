@@ -0,0 +1,117 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"event-metrics-service/internal/metrics/core/domain"
+)
+
+// MaxAggregations is the number of aggregations of one structured query.
+const MaxAggregations = 10
+
+// AggregationSpec is one aggregate of a numeric metadata field.
+type AggregationSpec struct {
+	Aggregate string // "sum" / "avg" / "min" / "max" / "p50" / "p90" / "p95" / "p99"
+	Field     string // "metadata.<key>"
+}
+
+// QueryMetricsInput is a structured metrics query: a GetMetricsInput asking
+// for any number of aggregations instead of its Aggregate and Field.
+type QueryMetricsInput struct {
+	GetMetricsInput
+	Aggregations []AggregationSpec
+}
+
+// QueryMetricsUseCase answers structured queries. Each aggregation is one
+// metrics query over the same groups, so tenants, caching and counters
+// apply as usual; their values are merged into the result of the first.
+type QueryMetricsUseCase struct {
+	metrics Metrics
+}
+
+// Metrics runs metrics queries: a GetMetricsUseCase, or a decorator of it
+// such as the query audit log.
+type Metrics interface {
+	Execute(ctx context.Context, in GetMetricsInput) (*domain.AggregatedMetrics, error)
+	ExecuteBatch(ctx context.Context, in BatchGetMetricsInput) (*BatchMetricsResult, error)
+}
+
+func NewQueryMetricsUseCase(metrics Metrics) *QueryMetricsUseCase {
+	return &QueryMetricsUseCase{metrics: metrics}
+}
+
+func (uc *QueryMetricsUseCase) Execute(ctx context.Context, in QueryMetricsInput) (*domain.AggregatedMetrics, error) {
+	if len(in.Aggregations) > MaxAggregations {
+		return nil, fmt.Errorf("%w: at most %d aggregations", ErrInvalidAggregate, MaxAggregations)
+	}
+	// Ordered by value, each query would page its own groups.
+	if len(in.Aggregations) > 1 && in.OrderBy == domain.OrderByValue {
+		return nil, fmt.Errorf("%w: order_by value needs a single aggregation", ErrInvalidOrder)
+	}
+
+	queries := []GetMetricsInput{in.GetMetricsInput}
+	if len(in.Aggregations) > 0 {
+		queries = make([]GetMetricsInput, len(in.Aggregations))
+		for i, a := range in.Aggregations {
+			q := in.GetMetricsInput
+			q.Aggregate, q.Field = a.Aggregate, a.Field
+			queries[i] = q
+		}
+	}
+
+	results, err := uc.run(ctx, queries)
+	if err != nil {
+		return nil, err
+	}
+	first := *results[0]
+	if len(in.Aggregations) == 0 {
+		return &first, nil
+	}
+
+	res := &first
+	res.Groups = slices.Clone(first.Groups)
+	for i := range res.Groups {
+		res.Groups[i].Values = make([]*float64, len(results))
+	}
+	index := make(map[string]int, len(res.Groups))
+	for i, g := range res.Groups {
+		index[g.Key] = i
+	}
+	for n, r := range results {
+		res.Aggregations = append(res.Aggregations, domain.Aggregation{Aggregate: r.Aggregate, Field: r.Field, Value: r.Value})
+		for _, g := range r.Groups {
+			if i, ok := index[g.Key]; ok {
+				res.Groups[i].Values[n] = g.Value
+			}
+		}
+	}
+	return res, nil
+}
+
+// run executes the queries, at one watermark when they ask for the latest.
+func (uc *QueryMetricsUseCase) run(ctx context.Context, queries []GetMetricsInput) ([]*domain.AggregatedMetrics, error) {
+	if len(queries) > 1 && queries[0].AsOfLatest {
+		batch := BatchGetMetricsInput{Queries: make([]GetMetricsInput, len(queries))}
+		for i, q := range queries {
+			q.AsOfLatest = false
+			batch.Queries[i] = q
+		}
+		res, err := uc.metrics.ExecuteBatch(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		return res.Results, nil
+	}
+
+	results := make([]*domain.AggregatedMetrics, 0, len(queries))
+	for _, q := range queries {
+		r, err := uc.metrics.Execute(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
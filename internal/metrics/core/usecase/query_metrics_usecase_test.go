@@ -0,0 +1,102 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/ports"
+	"event-metrics-service/internal/metrics/core/usecase"
+)
+
+func floatPtr(v float64) *float64 { return &v }
+
+func TestQueryMetrics_MergesAggregations(t *testing.T) {
+	wm := &fakeWatermark{at: time.Date(2025, 12, 8, 10, 0, 0, 0, time.UTC)}
+	var filters []ports.MetricsFilter
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, f ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			filters = append(filters, f)
+			// The p95 query does not reach the web group.
+			res := &domain.AggregatedMetrics{EventName: f.EventName, TotalCount: 30, Aggregate: f.Aggregate, Field: f.Field, Value: floatPtr(100)}
+			res.Groups = append(res.Groups, domain.MetricsGroup{Key: "ios", TotalCount: 10, Value: floatPtr(40)})
+			if f.Aggregate == domain.AggregateSum {
+				res.Groups = append(res.Groups, domain.MetricsGroup{Key: "web", TotalCount: 20, Value: floatPtr(60)})
+			}
+			return res, nil
+		},
+	}
+	uc := usecase.NewQueryMetricsUseCase(usecase.NewGetMetricsUseCase(reader, usecase.WithWatermark(wm)))
+
+	res, err := uc.Execute(context.Background(), usecase.QueryMetricsInput{
+		GetMetricsInput: usecase.GetMetricsInput{EventName: "purchase", From: 100, To: 200, GroupBy: "channel", AsOfLatest: true},
+		Aggregations: []usecase.AggregationSpec{
+			{Aggregate: domain.AggregateSum, Field: "metadata.amount"},
+			{Aggregate: domain.AggregateP95, Field: "metadata.amount"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Both queries at the one watermark.
+	if len(filters) != 2 || wm.calls != 1 || !filters[0].AsOf.Equal(wm.at) || !filters[1].AsOf.Equal(wm.at) {
+		t.Fatalf("expected two queries at one watermark, got %+v (%d watermark reads)", filters, wm.calls)
+	}
+	if filters[0].Aggregate != domain.AggregateSum || filters[1].Aggregate != domain.AggregateP95 {
+		t.Fatalf("unexpected aggregates: %+v", filters)
+	}
+	if res.Aggregate != domain.AggregateSum || len(res.Aggregations) != 2 || res.Aggregations[1].Aggregate != domain.AggregateP95 {
+		t.Fatalf("unexpected aggregations: %+v", res)
+	}
+	if len(res.Groups) != 2 || len(res.Groups[1].Values) != 2 || *res.Groups[1].Values[0] != 60 || res.Groups[1].Values[1] != nil {
+		t.Fatalf("unexpected groups: %+v", res.Groups)
+	}
+	if v := res.Groups[0].Values; *v[0] != 40 || *v[1] != 40 {
+		t.Fatalf("unexpected ios values: %v", v)
+	}
+}
+
+func TestQueryMetrics_WithoutAggregations(t *testing.T) {
+	reader := &fakeMetricsReader{
+		QueryFn: func(ctx context.Context, f ports.MetricsFilter) (*domain.AggregatedMetrics, error) {
+			return &domain.AggregatedMetrics{TotalCount: 7, Groups: []domain.MetricsGroup{{Key: "web", TotalCount: 7}}}, nil
+		},
+	}
+	uc := usecase.NewQueryMetricsUseCase(usecase.NewGetMetricsUseCase(reader))
+
+	res, err := uc.Execute(context.Background(), usecase.QueryMetricsInput{
+		GetMetricsInput: usecase.GetMetricsInput{EventName: "purchase", From: 100, To: 200, GroupBy: "channel"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.TotalCount != 7 || res.Aggregations != nil || res.Groups[0].Values != nil {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestQueryMetrics_Invalid(t *testing.T) {
+	reader := &fakeMetricsReader{}
+	uc := usecase.NewQueryMetricsUseCase(usecase.NewGetMetricsUseCase(reader))
+	two := []usecase.AggregationSpec{
+		{Aggregate: domain.AggregateSum, Field: "metadata.amount"},
+		{Aggregate: domain.AggregateMax, Field: "metadata.amount"},
+	}
+	base := usecase.GetMetricsInput{EventName: "purchase", From: 100, To: 200, GroupBy: "channel"}
+
+	byValue := base
+	byValue.OrderBy = domain.OrderByValue
+	if _, err := uc.Execute(context.Background(), usecase.QueryMetricsInput{GetMetricsInput: byValue, Aggregations: two}); !errors.Is(err, usecase.ErrInvalidOrder) {
+		t.Fatalf("expected ErrInvalidOrder, got %v", err)
+	}
+	many := make([]usecase.AggregationSpec, usecase.MaxAggregations+1)
+	if _, err := uc.Execute(context.Background(), usecase.QueryMetricsInput{GetMetricsInput: base, Aggregations: many}); !errors.Is(err, usecase.ErrInvalidAggregate) {
+		t.Fatalf("expected ErrInvalidAggregate, got %v", err)
+	}
+	if reader.called {
+		t.Fatal("reader should not be called")
+	}
+}
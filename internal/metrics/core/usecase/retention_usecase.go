@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/ports"
+)
+
+var ErrInvalidRetention = errors.New("invalid retention query")
+
+const (
+	DefaultRetentionPeriods = 8
+	MaxRetentionPeriods     = 90
+)
+
+type GetRetentionInput struct {
+	StartEvent  string
+	ReturnEvent string
+	From        int64
+	To          int64
+	Interval    string // "day" / "week"
+	Periods     int    // 0 = DefaultRetentionPeriods
+}
+
+type GetRetentionUseCase struct {
+	reader   ports.RetentionReaderPort
+	tenantOf func(ctx context.Context) string
+}
+
+type RetentionOption func(*GetRetentionUseCase)
+
+// WithRetentionTenantResolver restricts retention to the events of the
+// tenant of the request context, like WithTenantResolver.
+func WithRetentionTenantResolver(fn func(ctx context.Context) string) RetentionOption {
+	return func(uc *GetRetentionUseCase) {
+		uc.tenantOf = fn
+	}
+}
+
+func NewGetRetentionUseCase(reader ports.RetentionReaderPort, opts ...RetentionOption) *GetRetentionUseCase {
+	uc := &GetRetentionUseCase{reader: reader}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// Execute computes the retention triangle of the input range. Periods whose
+// bucket starts after To are cut off, so later cohorts have fewer of them.
+func (uc *GetRetentionUseCase) Execute(ctx context.Context, in GetRetentionInput) (*domain.Retention, error) {
+	if in.StartEvent == "" || in.ReturnEvent == "" {
+		return nil, fmt.Errorf("%w: start_event and return_event are required", ErrInvalidRetention)
+	}
+	if in.From <= 0 || in.To <= 0 || in.From > in.To {
+		return nil, ErrInvalidTimeRange
+	}
+	step, ok := domain.RetentionIntervals[in.Interval]
+	if !ok {
+		return nil, fmt.Errorf("%w: interval must be day or week", ErrInvalidRetention)
+	}
+	if in.Periods == 0 {
+		in.Periods = DefaultRetentionPeriods
+	}
+	if in.Periods < 0 || in.Periods > MaxRetentionPeriods {
+		return nil, fmt.Errorf("%w: periods must be between 1 and %d", ErrInvalidRetention, MaxRetentionPeriods)
+	}
+
+	f := ports.RetentionFilter{
+		StartEvent:  in.StartEvent,
+		ReturnEvent: in.ReturnEvent,
+		From:        in.From,
+		To:          in.To,
+		Interval:    in.Interval,
+		Periods:     in.Periods,
+	}
+	if uc.tenantOf != nil {
+		t := uc.tenantOf(ctx)
+		f.Tenant = &t
+	}
+
+	res, err := uc.reader.QueryRetention(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	last := domain.RetentionBucket(time.Unix(in.To, 0), in.Interval)
+	for i, c := range res.Cohorts {
+		n := int(last.Sub(c.Start) / step)
+		if n < len(c.Returned) {
+			res.Cohorts[i].Returned = c.Returned[:max(n, 0)]
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,104 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/ports"
+	"event-metrics-service/internal/metrics/core/usecase"
+)
+
+type fakeRetentionReader struct {
+	QueryFn    func(ctx context.Context, f ports.RetentionFilter) (*domain.Retention, error)
+	lastFilter ports.RetentionFilter
+	called     bool
+}
+
+func (f *fakeRetentionReader) QueryRetention(ctx context.Context, flt ports.RetentionFilter) (*domain.Retention, error) {
+	f.called = true
+	f.lastFilter = flt
+	return f.QueryFn(ctx, flt)
+}
+
+func TestGetRetention_CutsPeriodsAfterTo(t *testing.T) {
+	week := func(day int) time.Time { return time.Date(2025, 12, day, 0, 0, 0, 0, time.UTC) }
+	reader := &fakeRetentionReader{
+		QueryFn: func(ctx context.Context, f ports.RetentionFilter) (*domain.Retention, error) {
+			return &domain.Retention{Periods: f.Periods, Cohorts: []domain.RetentionCohort{
+				{Start: week(1), Users: 10, Returned: []int64{6, 4, 0, 0}},
+				{Start: week(8), Users: 5, Returned: []int64{2, 0, 0, 0}},
+				{Start: week(15), Users: 4, Returned: []int64{0, 0, 0, 0}},
+			}}, nil
+		},
+	}
+	uc := usecase.NewGetRetentionUseCase(reader, usecase.WithRetentionTenantResolver(func(context.Context) string { return "acme" }))
+
+	// Wednesday of the third week.
+	to := time.Date(2025, 12, 17, 12, 0, 0, 0, time.UTC)
+	res, err := uc.Execute(context.Background(), usecase.GetRetentionInput{
+		StartEvent: "signup", ReturnEvent: "login", From: week(1).Unix(), To: to.Unix(), Interval: domain.RetentionWeek, Periods: 4,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if reader.lastFilter.Tenant == nil || *reader.lastFilter.Tenant != "acme" || reader.lastFilter.Periods != 4 {
+		t.Fatalf("unexpected filter: %+v", reader.lastFilter)
+	}
+	for i, want := range []int{2, 1, 0} {
+		if got := len(res.Cohorts[i].Returned); got != want {
+			t.Fatalf("cohort %d: expected %d periods, got %d", i, want, got)
+		}
+	}
+	if rates := res.Cohorts[0].Rates(); rates[0] != 0.6 || rates[1] != 0.4 {
+		t.Fatalf("unexpected rates: %v", rates)
+	}
+}
+
+func TestGetRetention_DefaultPeriods(t *testing.T) {
+	reader := &fakeRetentionReader{
+		QueryFn: func(ctx context.Context, f ports.RetentionFilter) (*domain.Retention, error) {
+			return &domain.Retention{}, nil
+		},
+	}
+	uc := usecase.NewGetRetentionUseCase(reader)
+
+	if _, err := uc.Execute(context.Background(), usecase.GetRetentionInput{
+		StartEvent: "signup", ReturnEvent: "login", From: 100, To: 200, Interval: domain.RetentionDay,
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reader.lastFilter.Periods != usecase.DefaultRetentionPeriods || reader.lastFilter.Tenant != nil {
+		t.Fatalf("unexpected filter: %+v", reader.lastFilter)
+	}
+}
+
+func TestGetRetention_Validation(t *testing.T) {
+	base := usecase.GetRetentionInput{StartEvent: "signup", ReturnEvent: "login", From: 100, To: 200, Interval: domain.RetentionWeek}
+
+	for name, tc := range map[string]struct {
+		mutate func(*usecase.GetRetentionInput)
+		want   error
+	}{
+		"missing start event":  {func(in *usecase.GetRetentionInput) { in.StartEvent = "" }, usecase.ErrInvalidRetention},
+		"missing return event": {func(in *usecase.GetRetentionInput) { in.ReturnEvent = "" }, usecase.ErrInvalidRetention},
+		"hourly buckets":       {func(in *usecase.GetRetentionInput) { in.Interval = "hour" }, usecase.ErrInvalidRetention},
+		"too many periods":     {func(in *usecase.GetRetentionInput) { in.Periods = usecase.MaxRetentionPeriods + 1 }, usecase.ErrInvalidRetention},
+		"reversed range":       {func(in *usecase.GetRetentionInput) { in.From, in.To = 200, 100 }, usecase.ErrInvalidTimeRange},
+	} {
+		reader := &fakeRetentionReader{}
+		uc := usecase.NewGetRetentionUseCase(reader)
+
+		in := base
+		tc.mutate(&in)
+		if _, err := uc.Execute(context.Background(), in); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", name, tc.want, err)
+		}
+		if reader.called {
+			t.Fatalf("%s: reader should not be called", name)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/ports"
+)
+
+const (
+	DefaultRollupLookback     = 48 * time.Hour
+	DefaultRollupHistory      = 90 * 24 * time.Hour
+	DefaultRollupBackfillStep = 7 * 24 * time.Hour
+)
+
+// RollupUseCase keeps the hourly and daily rollups of events up to date, so
+// metrics over long windows read pre-aggregated buckets instead of every
+// event.
+//
+// Every run recomputes the closed buckets of the last lookback, which picks
+// up late events, and extends the rollups one backfill step further into the
+// past until they reach back history. Changes to older events (deletes,
+// amendments, the retention purge) are not rolled up again.
+type RollupUseCase struct {
+	store    ports.RollupStorePort
+	lookback time.Duration
+	history  time.Duration
+	step     time.Duration
+	now      func() time.Time
+
+	mu       sync.RWMutex
+	coverage map[string]domain.RollupCoverage
+}
+
+type RollupOption func(*RollupUseCase)
+
+// WithRollupLookback sets how far back closed buckets are recomputed on
+// every run.
+func WithRollupLookback(d time.Duration) RollupOption {
+	return func(uc *RollupUseCase) {
+		uc.lookback = d
+	}
+}
+
+// WithRollupHistory sets how far back the rollups are backfilled.
+func WithRollupHistory(d time.Duration) RollupOption {
+	return func(uc *RollupUseCase) {
+		uc.history = d
+	}
+}
+
+// WithRollupBackfillStep sets how much older history one run backfills.
+func WithRollupBackfillStep(d time.Duration) RollupOption {
+	return func(uc *RollupUseCase) {
+		uc.step = d
+	}
+}
+
+func NewRollupUseCase(store ports.RollupStorePort, opts ...RollupOption) *RollupUseCase {
+	uc := &RollupUseCase{
+		store:    store,
+		lookback: DefaultRollupLookback,
+		history:  DefaultRollupHistory,
+		step:     DefaultRollupBackfillStep,
+		now:      time.Now,
+		coverage: map[string]domain.RollupCoverage{},
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// Execute rolls up every granularity and reloads their coverage. Only one
+// instance rolls up at a time; the others just reload the coverage.
+func (uc *RollupUseCase) Execute(ctx context.Context) error {
+	coverage, err := uc.store.RollupCoverage(ctx)
+	if err != nil {
+		return err
+	}
+	byGranularity := map[string]domain.RollupCoverage{}
+	for _, c := range coverage {
+		byGranularity[c.Granularity] = c
+	}
+
+	var errs []error
+	for _, g := range domain.RollupGranularities {
+		c, ok := byGranularity[g]
+		if err := uc.rollUp(ctx, g, c, ok); err != nil {
+			errs = append(errs, fmt.Errorf("rollup %s: %w", g, err))
+		}
+	}
+
+	if err := uc.reload(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// rollUp recomputes the recent buckets of granularity g, including any gap
+// since the rollups last ran, then backfills one step of older ones.
+func (uc *RollupUseCase) rollUp(ctx context.Context, g string, c domain.RollupCoverage, ok bool) error {
+	length := domain.RollupLengths[g]
+	now := uc.now().UTC()
+	closed := now.Truncate(length)
+
+	from := closed.Add(-uc.lookback).Truncate(length)
+	if ok && c.Until.Before(from) {
+		from = c.Until
+	}
+	rolled, err := uc.store.RollUp(ctx, g, from, closed)
+	if err != nil || !rolled {
+		return err
+	}
+
+	oldest := now.Add(-uc.history).Truncate(length)
+	if ok && c.From.Before(from) {
+		from = c.From
+	}
+	if !from.After(oldest) {
+		return nil
+	}
+	_, err = uc.store.RollUp(ctx, g, maxTime(from.Add(-uc.step).Truncate(length), oldest), from)
+	return err
+}
+
+func (uc *RollupUseCase) reload(ctx context.Context) error {
+	coverage, err := uc.store.RollupCoverage(ctx)
+	if err != nil {
+		return err
+	}
+	byGranularity := make(map[string]domain.RollupCoverage, len(coverage))
+	for _, c := range coverage {
+		byGranularity[c.Granularity] = c
+	}
+
+	uc.mu.Lock()
+	uc.coverage = byGranularity
+	uc.mu.Unlock()
+	return nil
+}
+
+// Run rolls up immediately and then on every tick until ctx is cancelled.
+func (uc *RollupUseCase) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := uc.Execute(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Coverage returns the rolled-up buckets of granularity as of the last run.
+// Metrics only read rollups within them.
+func (uc *RollupUseCase) Coverage(granularity string) (domain.RollupCoverage, bool) {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	c, ok := uc.coverage[granularity]
+	return c, ok
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,160 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/metrics/core/domain"
+	"event-metrics-service/internal/metrics/core/usecase"
+)
+
+type rollUpCall struct {
+	granularity string
+	from, until time.Time
+}
+
+// fakeRollupStore extends the coverage like the repository does.
+type fakeRollupStore struct {
+	coverage map[string]domain.RollupCoverage
+	calls    []rollUpCall
+	locked   bool
+	err      map[string]error
+}
+
+func newFakeRollupStore() *fakeRollupStore {
+	return &fakeRollupStore{coverage: map[string]domain.RollupCoverage{}, locked: true}
+}
+
+func (f *fakeRollupStore) RollUp(ctx context.Context, granularity string, from, until time.Time) (bool, error) {
+	f.calls = append(f.calls, rollUpCall{granularity, from, until})
+	if err := f.err[granularity]; err != nil {
+		return false, err
+	}
+	if !f.locked {
+		return false, nil
+	}
+	c, ok := f.coverage[granularity]
+	if !ok || from.Before(c.From) {
+		c.From = from
+	}
+	if !ok || until.After(c.Until) {
+		c.Until = until
+	}
+	c.Granularity = granularity
+	f.coverage[granularity] = c
+	return true, nil
+}
+
+func (f *fakeRollupStore) RollupCoverage(ctx context.Context) ([]domain.RollupCoverage, error) {
+	var out []domain.RollupCoverage
+	for _, c := range f.coverage {
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+func (f *fakeRollupStore) callsOf(granularity string) []rollUpCall {
+	var out []rollUpCall
+	for _, c := range f.calls {
+		if c.granularity == granularity {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func TestRollup_FirstRunRollsUpLookbackAndBackfills(t *testing.T) {
+	store := newFakeRollupStore()
+	uc := usecase.NewRollupUseCase(store, usecase.WithRollupLookback(48*time.Hour), usecase.WithRollupBackfillStep(72*time.Hour))
+
+	if _, ok := uc.Coverage(domain.RollupHour); ok {
+		t.Fatalf("expected no coverage before the first run")
+	}
+	if err := uc.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, g := range domain.RollupGranularities {
+		length := domain.RollupLengths[g]
+		calls := store.callsOf(g)
+		if len(calls) != 2 {
+			t.Fatalf("%s: expected a recent and a backfill run, got %+v", g, calls)
+		}
+		recent, backfill := calls[0], calls[1]
+		if !recent.until.Equal(recent.until.Truncate(length)) || time.Since(recent.until) > length {
+			t.Fatalf("%s: expected the recent run to end at the last closed bucket, got %s", g, recent.until)
+		}
+		if recent.until.Sub(recent.from) != 48*time.Hour {
+			t.Fatalf("%s: expected the lookback to be rolled up, got %+v", g, recent)
+		}
+		if !backfill.until.Equal(recent.from) || backfill.until.Sub(backfill.from) != 72*time.Hour {
+			t.Fatalf("%s: expected one backfill step before the lookback, got %+v", g, backfill)
+		}
+
+		c, ok := uc.Coverage(g)
+		if !ok || !c.From.Equal(backfill.from) || !c.Until.Equal(recent.until) {
+			t.Fatalf("%s: expected the coverage to be reloaded, got %+v", g, c)
+		}
+	}
+}
+
+func TestRollup_CatchesUpAndStopsAtHistory(t *testing.T) {
+	store := newFakeRollupStore()
+	until := time.Now().UTC().Truncate(time.Hour).Add(-5 * 24 * time.Hour)
+	store.coverage[domain.RollupHour] = domain.RollupCoverage{
+		Granularity: domain.RollupHour,
+		From:        until.Add(-30 * 24 * time.Hour),
+		Until:       until,
+	}
+	uc := usecase.NewRollupUseCase(store, usecase.WithRollupHistory(7*24*time.Hour))
+
+	if err := uc.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	calls := store.callsOf(domain.RollupHour)
+	if len(calls) != 1 {
+		t.Fatalf("expected no backfill past the history, got %+v", calls)
+	}
+	if !calls[0].from.Equal(until) {
+		t.Fatalf("expected the gap since the last run to be rolled up, got %+v", calls[0])
+	}
+}
+
+func TestRollup_SkipsWhileAnotherInstanceRollsUp(t *testing.T) {
+	store := newFakeRollupStore()
+	store.locked = false
+	store.coverage[domain.RollupDay] = domain.RollupCoverage{
+		Granularity: domain.RollupDay,
+		From:        time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+		Until:       time.Date(2025, 12, 7, 0, 0, 0, 0, time.UTC),
+	}
+	uc := usecase.NewRollupUseCase(store)
+
+	if err := uc.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(store.calls) != 2 {
+		t.Fatalf("expected one attempt per granularity, got %+v", store.calls)
+	}
+	if c, ok := uc.Coverage(domain.RollupDay); !ok || c.Until.Day() != 7 {
+		t.Fatalf("expected the coverage of the other instance, got %+v", c)
+	}
+}
+
+func TestRollup_FailingGranularityDoesNotStopOthers(t *testing.T) {
+	store := newFakeRollupStore()
+	store.err = map[string]error{domain.RollupHour: errors.New("statement timeout")}
+	uc := usecase.NewRollupUseCase(store)
+
+	err := uc.Execute(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "rollup hour: statement timeout") {
+		t.Fatalf("expected the hourly error, got %v", err)
+	}
+	if _, ok := uc.Coverage(domain.RollupDay); !ok {
+		t.Fatalf("expected the daily rollups to go ahead")
+	}
+}
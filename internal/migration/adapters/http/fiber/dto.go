@@ -0,0 +1,14 @@
+package fiber
+
+// ColumnStateResponse is the effective state of one gated column.
+type ColumnStateResponse struct {
+	Column   string `json:"column" example:"events.anonymous_id"`
+	Mode     string `json:"mode" example:"write" enums:"off,write,on"`
+	Exists   bool   `json:"exists"`
+	Writable bool   `json:"writable"`
+	Readable bool   `json:"readable"`
+}
+
+type SchemaCompatResponse struct {
+	Columns []ColumnStateResponse `json:"columns"`
+}
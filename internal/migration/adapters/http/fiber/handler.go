@@ -0,0 +1,47 @@
+package fiber
+
+import (
+	"net/http"
+
+	"event-metrics-service/internal/migration/core/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CompatUseCase interface {
+	States() []domain.ColumnState
+}
+
+type CompatHandler struct {
+	uc CompatUseCase
+}
+
+func NewCompatHandler(uc CompatUseCase) *CompatHandler {
+	return &CompatHandler{uc: uc}
+}
+
+// GetSchemaCompat godoc
+// @Summary Show gated schema columns
+// @Description Lists columns added by recent migrations with their SCHEMA_COLUMN_MODES flag, whether this
+// @Description instance found them in the database and whether it currently writes and reads them.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} SchemaCompatResponse
+// @Router /admin/schema-compat [get]
+func (h *CompatHandler) GetSchemaCompat(c *fiber.Ctx) error {
+	states := h.uc.States()
+
+	resp := SchemaCompatResponse{Columns: make([]ColumnStateResponse, 0, len(states))}
+	for _, s := range states {
+		resp.Columns = append(resp.Columns, ColumnStateResponse{
+			Column:   s.Column,
+			Mode:     string(s.Mode),
+			Exists:   s.Exists,
+			Writable: s.Writable(),
+			Readable: s.Readable(),
+		})
+	}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
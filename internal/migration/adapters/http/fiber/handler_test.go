@@ -0,0 +1,53 @@
+package fiber_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpadapter "event-metrics-service/internal/migration/adapters/http/fiber"
+	"event-metrics-service/internal/migration/core/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeCompatUseCase struct {
+	states []domain.ColumnState
+}
+
+func (f *fakeCompatUseCase) States() []domain.ColumnState {
+	return f.states
+}
+
+func TestGetSchemaCompat(t *testing.T) {
+	uc := &fakeCompatUseCase{states: []domain.ColumnState{
+		{Column: "events.anonymous_id", Mode: domain.ModeWrite, Exists: true},
+		{Column: "identity_links.stitch_from", Mode: domain.ModeOn, Exists: false},
+	}}
+
+	app := fiber.New()
+	app.Get("/admin/schema-compat", httpadapter.NewCompatHandler(uc).GetSchemaCompat)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/admin/schema-compat", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body httpadapter.SchemaCompatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if len(body.Columns) != 2 {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+	if c := body.Columns[0]; !c.Writable || c.Readable || c.Mode != "write" {
+		t.Fatalf("unexpected dual-write column: %+v", c)
+	}
+	if c := body.Columns[1]; c.Writable || c.Readable {
+		t.Fatalf("missing column must not be used: %+v", c)
+	}
+}
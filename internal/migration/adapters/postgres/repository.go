@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+
+	"event-metrics-service/internal/migration/core/ports"
+
+	"github.com/lib/pq"
+)
+
+type RowScanner interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+type DB interface {
+	QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+type ColumnProber struct {
+	db DB
+}
+
+func NewColumnProber(db DB) *ColumnProber {
+	return &ColumnProber{db: db}
+}
+
+var _ ports.ColumnProberPort = (*ColumnProber)(nil)
+
+// Only the schema on the search path counts, so a newer schema in another
+// namespace (e.g. a migration rehearsal) does not enable anything.
+const existingColumnsSQL = `
+SELECT table_name || '.' || column_name
+FROM information_schema.columns
+WHERE table_schema = current_schema()
+  AND table_name || '.' || column_name = ANY($1)`
+
+func (p *ColumnProber) ExistingColumns(ctx context.Context, columns []string) (map[string]bool, error) {
+	rows, err := p.db.QueryContext(ctx, existingColumnsSQL, pq.Array(columns))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	found := make(map[string]bool, len(columns))
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, err
+		}
+		found[column] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fakeRowScanner implements RowScanner for tests.
+type fakeRowScanner struct {
+	rows []string
+	i    int
+}
+
+func (f *fakeRowScanner) Next() bool {
+	return f.i < len(f.rows)
+}
+
+func (f *fakeRowScanner) Scan(dest ...any) error {
+	d, ok := dest[0].(*string)
+	if !ok || len(dest) != 1 {
+		return errors.New("unsupported dest")
+	}
+	*d = f.rows[f.i]
+	f.i++
+	return nil
+}
+
+func (f *fakeRowScanner) Err() error   { return nil }
+func (f *fakeRowScanner) Close() error { return nil }
+
+type fakeDB struct {
+	QueryFn func(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+func (f *fakeDB) QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error) {
+	return f.QueryFn(ctx, query, args...)
+}
+
+func TestColumnProber_ExistingColumns(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if !strings.Contains(query, "information_schema.columns") || !strings.Contains(query, "current_schema()") {
+				t.Fatalf("unexpected query: %s", query)
+			}
+			return &fakeRowScanner{rows: []string{"events.anonymous_id"}}, nil
+		},
+	}
+
+	found, err := NewColumnProber(db).ExistingColumns(context.Background(), []string{"events.anonymous_id", "identity_links.stitch_from"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !found["events.anonymous_id"] || found["identity_links.stitch_from"] {
+		t.Fatalf("unexpected result: %v", found)
+	}
+}
+
+func TestColumnProber_DBError(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return nil, errors.New("db down")
+		},
+	}
+
+	if _, err := NewColumnProber(db).ExistingColumns(context.Background(), []string{"events.anonymous_id"}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
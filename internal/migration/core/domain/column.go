@@ -0,0 +1,36 @@
+package domain
+
+// Mode is the operator flag of a gated column, i.e. a column added by a
+// migration that may not have reached every database yet.
+type Mode string
+
+const (
+	// ModeOff ignores the column, as releases older than the migration do.
+	ModeOff Mode = "off"
+	// ModeWrite writes the column next to the data older releases read
+	// (dual-write) but keeps reading the legacy representation.
+	ModeWrite Mode = "write"
+	// ModeOn writes and reads the column.
+	ModeOn Mode = "on"
+)
+
+func (m Mode) Valid() bool {
+	return m == ModeOff || m == ModeWrite || m == ModeOn
+}
+
+// ColumnState combines the configured mode of a gated column ("table.column")
+// with whether the database has it. A flag never forces a column that does
+// not exist yet.
+type ColumnState struct {
+	Column string
+	Mode   Mode
+	Exists bool
+}
+
+func (s ColumnState) Writable() bool {
+	return s.Exists && s.Mode != ModeOff
+}
+
+func (s ColumnState) Readable() bool {
+	return s.Exists && s.Mode == ModeOn
+}
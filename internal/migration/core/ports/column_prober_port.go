@@ -0,0 +1,9 @@
+package ports
+
+import "context"
+
+type ColumnProberPort interface {
+	// ExistingColumns reports which of the given "table.column" names exist
+	// in the current schema.
+	ExistingColumns(ctx context.Context, columns []string) (map[string]bool, error)
+}
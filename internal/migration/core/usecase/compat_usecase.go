@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"event-metrics-service/internal/migration/core/domain"
+	"event-metrics-service/internal/migration/core/ports"
+)
+
+var ErrInvalidColumnMode = errors.New("invalid column mode")
+
+// ValidateColumnModes checks operator flags: every key must be a gated
+// column and every value a known mode.
+func ValidateColumnModes(modes map[string]string, columns []string) error {
+	known := make(map[string]bool, len(columns))
+	for _, c := range columns {
+		known[c] = true
+	}
+	for c, m := range modes {
+		if !known[c] {
+			return fmt.Errorf("%w: %q is not a gated column", ErrInvalidColumnMode, c)
+		}
+		if !domain.Mode(m).Valid() {
+			return fmt.Errorf("%w: %s=%q (want off, write or on)", ErrInvalidColumnMode, c, m)
+		}
+	}
+	return nil
+}
+
+// CompatUseCase lets one release run against the schema before and after a
+// migration, so blue and green deployments can share a database. Columns
+// added by recent migrations are gated: they are only used once they exist,
+// and operator flags can hold them back (off) or limit them to dual-writing
+// (write) until every instance writes them and the backfill is done.
+type CompatUseCase struct {
+	prober  ports.ColumnProberPort
+	columns []string
+	modes   map[string]domain.Mode
+
+	mu     sync.RWMutex
+	exists map[string]bool
+}
+
+// NewCompatUseCase gates the given "table.column" names. Columns without a
+// flag in modes default to on. modes must pass ValidateColumnModes.
+func NewCompatUseCase(prober ports.ColumnProberPort, columns []string, modes map[string]string) *CompatUseCase {
+	uc := &CompatUseCase{
+		prober:  prober,
+		columns: columns,
+		modes:   make(map[string]domain.Mode, len(columns)),
+		exists:  map[string]bool{},
+	}
+	for _, c := range columns {
+		uc.modes[c] = domain.ModeOn
+		if m, ok := modes[c]; ok {
+			uc.modes[c] = domain.Mode(m)
+		}
+	}
+	return uc
+}
+
+// Refresh probes the database and returns the columns whose effective state
+// changed, e.g. because a migration was applied mid-deploy. On error the
+// previous state is kept.
+func (uc *CompatUseCase) Refresh(ctx context.Context) ([]domain.ColumnState, error) {
+	found, err := uc.prober.ExistingColumns(ctx, uc.columns)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	var changed []domain.ColumnState
+	for _, c := range uc.columns {
+		if found[c] != uc.exists[c] {
+			changed = append(changed, domain.ColumnState{Column: c, Mode: uc.modes[c], Exists: found[c]})
+		}
+	}
+	uc.exists = found
+
+	return changed, nil
+}
+
+// Run refreshes on every tick until ctx is cancelled.
+func (uc *CompatUseCase) Run(ctx context.Context, interval time.Duration, onChange func(domain.ColumnState), onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		changed, err := uc.Refresh(ctx)
+		if err != nil {
+			if onError != nil {
+				onError(err)
+			}
+			continue
+		}
+		if onChange != nil {
+			for _, s := range changed {
+				onChange(s)
+			}
+		}
+	}
+}
+
+// Writes reports whether column may be written. Columns that are not gated
+// are always usable.
+func (uc *CompatUseCase) Writes(column string) bool {
+	s, ok := uc.state(column)
+	return !ok || s.Writable()
+}
+
+// Reads reports whether queries may rely on column.
+func (uc *CompatUseCase) Reads(column string) bool {
+	s, ok := uc.state(column)
+	return !ok || s.Readable()
+}
+
+// States returns every gated column in registration order.
+func (uc *CompatUseCase) States() []domain.ColumnState {
+	out := make([]domain.ColumnState, 0, len(uc.columns))
+	for _, c := range uc.columns {
+		s, _ := uc.state(c)
+		out = append(out, s)
+	}
+	return out
+}
+
+func (uc *CompatUseCase) state(column string) (domain.ColumnState, bool) {
+	mode, ok := uc.modes[column]
+	if !ok {
+		return domain.ColumnState{}, false
+	}
+
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	return domain.ColumnState{Column: column, Mode: mode, Exists: uc.exists[column]}, true
+}
@@ -0,0 +1,134 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"event-metrics-service/internal/migration/core/domain"
+	"event-metrics-service/internal/migration/core/usecase"
+)
+
+type fakeColumnProber struct {
+	ExistingFn func(ctx context.Context, columns []string) (map[string]bool, error)
+}
+
+func (f *fakeColumnProber) ExistingColumns(ctx context.Context, columns []string) (map[string]bool, error) {
+	return f.ExistingFn(ctx, columns)
+}
+
+const (
+	anonymousID   = "events.anonymous_id"
+	identityLinks = "identity_links.stitch_from"
+)
+
+// ------------------------------------------------------------
+// GATING
+// ------------------------------------------------------------
+
+func TestCompat_ColumnsWaitForMigration(t *testing.T) {
+	existing := map[string]bool{}
+	prober := &fakeColumnProber{
+		ExistingFn: func(ctx context.Context, columns []string) (map[string]bool, error) {
+			return existing, nil
+		},
+	}
+	uc := usecase.NewCompatUseCase(prober, []string{anonymousID, identityLinks}, nil)
+
+	if _, err := uc.Refresh(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if uc.Writes(anonymousID) || uc.Reads(anonymousID) {
+		t.Fatalf("missing column must not be used")
+	}
+
+	// The migration lands while the release is running.
+	existing = map[string]bool{anonymousID: true}
+	changed, err := uc.Refresh(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changed) != 1 || changed[0].Column != anonymousID || !changed[0].Exists {
+		t.Fatalf("expected anonymous_id to be reported, got %+v", changed)
+	}
+	if !uc.Writes(anonymousID) || !uc.Reads(anonymousID) {
+		t.Fatalf("expected anonymous_id to be used once it exists")
+	}
+	if uc.Reads(identityLinks) {
+		t.Fatalf("identity_links is still missing")
+	}
+}
+
+func TestCompat_ModesLimitUsage(t *testing.T) {
+	prober := &fakeColumnProber{
+		ExistingFn: func(ctx context.Context, columns []string) (map[string]bool, error) {
+			return map[string]bool{anonymousID: true, identityLinks: true}, nil
+		},
+	}
+	uc := usecase.NewCompatUseCase(prober, []string{anonymousID, identityLinks}, map[string]string{
+		anonymousID:   "write",
+		identityLinks: "off",
+	})
+	if _, err := uc.Refresh(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !uc.Writes(anonymousID) || uc.Reads(anonymousID) {
+		t.Fatalf("write mode must dual-write without reading")
+	}
+	if uc.Writes(identityLinks) || uc.Reads(identityLinks) {
+		t.Fatalf("off mode must ignore the column")
+	}
+	if !uc.Writes("events.channel") || !uc.Reads("events.channel") {
+		t.Fatalf("columns that are not gated are always usable")
+	}
+
+	states := uc.States()
+	if len(states) != 2 || states[0].Mode != domain.ModeWrite || states[1].Mode != domain.ModeOff {
+		t.Fatalf("unexpected states: %+v", states)
+	}
+}
+
+func TestCompat_RefreshErrorKeepsState(t *testing.T) {
+	fail := false
+	prober := &fakeColumnProber{
+		ExistingFn: func(ctx context.Context, columns []string) (map[string]bool, error) {
+			if fail {
+				return nil, errors.New("db down")
+			}
+			return map[string]bool{anonymousID: true}, nil
+		},
+	}
+	uc := usecase.NewCompatUseCase(prober, []string{anonymousID}, nil)
+	if _, err := uc.Refresh(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fail = true
+	if _, err := uc.Refresh(context.Background()); err == nil {
+		t.Fatalf("expected probe error")
+	}
+	if !uc.Writes(anonymousID) {
+		t.Fatalf("expected previous state to be kept")
+	}
+}
+
+// ------------------------------------------------------------
+// VALIDATION
+// ------------------------------------------------------------
+
+func TestValidateColumnModes(t *testing.T) {
+	columns := []string{anonymousID}
+
+	if err := usecase.ValidateColumnModes(map[string]string{anonymousID: "write"}, columns); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, modes := range []map[string]string{
+		{anonymousID: "auto"},
+		{"events.unknown": "on"},
+	} {
+		if err := usecase.ValidateColumnModes(modes, columns); !errors.Is(err, usecase.ErrInvalidColumnMode) {
+			t.Fatalf("%v: expected ErrInvalidColumnMode, got %v", modes, err)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Package parquet writes and reads flat Parquet files readable by common
+// query engines (DuckDB, Spark, Athena, pandas), on top of parquet-go with
+// gzip compressed, PLAIN encoded columns. Files are written one row group
+// per Flush, so a large extract streams without being held in memory.
+//
+// Read takes any flat file with columns of the types below; nested and
+// repeated columns are not supported.
+package parquet
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+// ErrUnsupportedFile is returned by Read for files it cannot decode.
+var ErrUnsupportedFile = errors.New("unsupported parquet file")
+
+// Type is the type of a column, with the Go type of its values.
+type Type int
+
+const (
+	String    Type = iota // string, BYTE_ARRAY annotated UTF8
+	JSON                  // []byte holding JSON, BYTE_ARRAY annotated JSON
+	Int64                 // int64
+	Double                // float64
+	Timestamp             // time.Time, INT64 annotated TIMESTAMP_MICROS
+)
+
+// Column describes one column of a file. Optional columns take nil values,
+// which are read back as nil.
+type Column struct {
+	Name     string
+	Type     Type
+	Optional bool
+}
+
+// CreatedBy is written to the footer of every file.
+const CreatedBy = "event-metrics-service"
+
+// node returns the parquet-go node of t.
+func (t Type) node() parquet.Node {
+	switch t {
+	case String:
+		return parquet.String()
+	case JSON:
+		return parquet.JSON()
+	case Timestamp:
+		return parquet.Timestamp(parquet.Microsecond)
+	case Double:
+		return parquet.Leaf(parquet.DoubleType)
+	}
+	return parquet.Leaf(parquet.Int64Type)
+}
+
+// typeOf is the inverse of node. Byte arrays without annotation are read as
+// strings, and timestamps of any unit as time.Time.
+func typeOf(t parquet.Type) (Type, error) {
+	var logical format.LogicalTypeValue
+	if lt := t.LogicalType(); lt != nil {
+		logical = lt.Value
+	}
+	_, isJSON := logical.(*format.JsonType)
+	_, isTimestamp := logical.(*format.TimestampType)
+	switch {
+	case t.Kind() == parquet.ByteArray && isJSON:
+		return JSON, nil
+	case t.Kind() == parquet.ByteArray:
+		return String, nil
+	case t.Kind() == parquet.Int64 && isTimestamp:
+		return Timestamp, nil
+	case t.Kind() == parquet.Int64:
+		return Int64, nil
+	case t.Kind() == parquet.Double:
+		return Double, nil
+	}
+	return 0, fmt.Errorf("%w: type %s", ErrUnsupportedFile, t)
+}
+
+// timeUnit returns the unit of the values of a Timestamp column.
+func timeUnit(t parquet.Type) time.Duration {
+	if ts, ok := t.LogicalType().Value.(*format.TimestampType); ok && ts.Unit.Value != nil {
+		return ts.Unit.Value.Duration()
+	}
+	return time.Microsecond
+}
+
+// newSchema returns the schema of a file with columns, in their order.
+func newSchema(columns []Column) *parquet.Schema {
+	group := make(parquet.Group, len(columns))
+	order := make(map[string]int, len(columns))
+	for i, col := range columns {
+		node := col.Type.node()
+		if col.Optional {
+			node = parquet.Optional(node)
+		}
+		group[col.Name] = node
+		order[col.Name] = i
+	}
+	return parquet.NewSchema("schema", orderedGroup{Group: group, order: order})
+}
+
+// orderedGroup lists its fields in the order of the columns rather than by
+// name, as parquet.Group does.
+type orderedGroup struct {
+	parquet.Group
+	order map[string]int
+}
+
+func (g orderedGroup) Fields() []parquet.Field {
+	fields := g.Group.Fields()
+	ordered := make([]parquet.Field, len(fields))
+	for _, f := range fields {
+		ordered[g.order[f.Name()]] = f
+	}
+	return ordered
+}
@@ -0,0 +1,204 @@
+package parquet
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+)
+
+var testColumns = []Column{
+	{Name: "channel", Type: String},
+	{Name: "bucket", Type: Timestamp},
+	{Name: "total_count", Type: Int64},
+	{Name: "value", Type: Double, Optional: true},
+	{Name: "metadata", Type: JSON},
+}
+
+func testRow(i int) []any {
+	var value any
+	if i%3 != 0 {
+		value = float64(i) / 2
+	}
+	return []any{
+		"web",
+		time.Date(2025, 9, 1, i%24, 0, 0, 0, time.UTC),
+		int64(i),
+		value,
+		[]byte(`{"plan":"pro"}`),
+	}
+}
+
+func TestWriter_RoundTripRowGroups(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, testColumns)
+
+	var want [][]any
+	for i := 0; i < 300; i++ {
+		row := testRow(i)
+		if err := w.Write(row); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want = append(want, row)
+		if i%100 == 99 {
+			if err := w.Flush(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	columns, rows, err := Read(buf.Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(columns, testColumns) {
+		t.Fatalf("unexpected columns: %+v", columns)
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("round trip mismatch:\nwant %v\ngot  %v", want[:3], rows[:3])
+	}
+
+	if groups := openFile(t, buf.Bytes()).RowGroups(); len(groups) != 3 {
+		t.Fatalf("expected a row group per flush, got %d", len(groups))
+	}
+}
+
+func TestWriter_Footer(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, testColumns)
+	for i := 1; i <= 2; i++ {
+		if err := w.Write(testRow(i)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data := buf.Bytes()
+	if !bytes.HasPrefix(data, []byte("PAR1")) || !bytes.HasSuffix(data, []byte("PAR1")) {
+		t.Fatalf("missing magic bytes")
+	}
+
+	f := openFile(t, data)
+	if rows := f.NumRows(); rows != 2 {
+		t.Fatalf("expected 2 rows, got %d", rows)
+	}
+	fields := f.Schema().Fields()
+	if len(fields) != len(testColumns) {
+		t.Fatalf("expected %d columns, got %d", len(testColumns), len(fields))
+	}
+	bucket := fields[1]
+	if bucket.Name() != "bucket" {
+		t.Fatalf("unexpected column %s", bucket.Name())
+	}
+	if lt := bucket.Type().LogicalType().String(); lt != "TIMESTAMP(isAdjustedToUTC=true,unit=MICROS)" {
+		t.Fatalf("expected microsecond timestamps, got %s", lt)
+	}
+	if createdBy := f.Metadata().CreatedBy; createdBy != CreatedBy {
+		t.Fatalf("unexpected created_by %q", createdBy)
+	}
+	for _, chunk := range f.Metadata().RowGroups[0].Columns {
+		if codec := chunk.MetaData.Codec; codec != format.Gzip {
+			t.Fatalf("expected gzip columns, got %s", codec)
+		}
+	}
+}
+
+func TestWriter_EmptyFile(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewWriter(&buf, testColumns).Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	columns, rows, err := Read(buf.Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(columns) != len(testColumns) || len(rows) != 0 {
+		t.Fatalf("expected the schema and no rows, got %+v %v", columns, rows)
+	}
+}
+
+func TestWriter_RejectsMismatchedValues(t *testing.T) {
+	w := NewWriter(&bytes.Buffer{}, testColumns)
+	for _, row := range [][]any{
+		{"web", time.Now(), int64(1), nil},
+		{"web", time.Now(), 1, nil, []byte("{}")},
+		{nil, time.Now(), int64(1), nil, []byte("{}")},
+		{"web", time.Now(), int64(1), "1.5", []byte("{}")},
+	} {
+		if err := w.Write(row); err == nil {
+			t.Fatalf("%v: expected an error", row)
+		}
+	}
+}
+
+func TestRead_RejectsInvalidFiles(t *testing.T) {
+	for _, data := range [][]byte{
+		nil,
+		[]byte("PAR1PAR1"),
+		[]byte("not a parquet file at all"),
+		append([]byte("PAR1\xff\xff\xff\x7f"), []byte("\x10\x00\x00\x00PAR1")...),
+	} {
+		if _, _, err := Read(data); err == nil {
+			t.Fatalf("%q: expected an error", data)
+		}
+	}
+}
+
+func TestRead_OtherWriters(t *testing.T) {
+	// Dictionary encoded, snappy compressed and millisecond timestamps, as
+	// other tools write them.
+	type row struct {
+		Channel string    `parquet:"channel,dict,snappy"`
+		At      time.Time `parquet:"at,timestamp(millisecond)"`
+		Count   *int64    `parquet:"count,optional"`
+	}
+	at := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	n := int64(7)
+	var buf bytes.Buffer
+	if err := parquet.Write(&buf, []row{{"web", at, &n}, {"web", at, nil}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	columns, rows, err := Read(buf.Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantColumns := []Column{{Name: "channel", Type: String}, {Name: "at", Type: Timestamp}, {Name: "count", Type: Int64, Optional: true}}
+	if !reflect.DeepEqual(columns, wantColumns) {
+		t.Fatalf("unexpected columns: %+v", columns)
+	}
+	want := [][]any{{"web", at, int64(7)}, {"web", at, nil}}
+	if !reflect.DeepEqual(rows, want) {
+		t.Fatalf("expected %v, got %v", want, rows)
+	}
+}
+
+func TestRead_RejectsNestedColumns(t *testing.T) {
+	type row struct {
+		Tags []string `parquet:"tags,list"`
+	}
+	var buf bytes.Buffer
+	if err := parquet.Write(&buf, []row{{Tags: []string{"a"}}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := Read(buf.Bytes()); !errors.Is(err, ErrUnsupportedFile) {
+		t.Fatalf("expected ErrUnsupportedFile, got %v", err)
+	}
+}
+
+func openFile(t *testing.T, data []byte) *parquet.File {
+	t.Helper()
+	f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return f
+}
@@ -0,0 +1,82 @@
+package parquet
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// Read decodes a file into its columns and rows, with values of the Go
+// types Writer takes.
+func Read(data []byte) ([]Column, [][]any, error) {
+	f, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrUnsupportedFile, err)
+	}
+
+	fields := f.Schema().Fields()
+	columns := make([]Column, len(fields))
+	units := make([]time.Duration, len(fields))
+	for i, field := range fields {
+		if !field.Leaf() || field.Repeated() {
+			return nil, nil, fmt.Errorf("%w: nested column %s", ErrUnsupportedFile, field.Name())
+		}
+		t, err := typeOf(field.Type())
+		if err != nil {
+			return nil, nil, fmt.Errorf("column %s: %w", field.Name(), err)
+		}
+		columns[i] = Column{Name: field.Name(), Type: t, Optional: field.Optional()}
+		if t == Timestamp {
+			units[i] = timeUnit(field.Type())
+		}
+	}
+
+	r := parquet.NewReader(f)
+	defer r.Close()
+	var out [][]any
+	buf := make([]parquet.Row, 128)
+	for {
+		n, err := r.ReadRows(buf)
+		for _, values := range buf[:n] {
+			row := make([]any, len(columns))
+			for _, v := range values {
+				i := v.Column()
+				if v.IsNull() {
+					continue
+				}
+				row[i] = fromValue(columns[i].Type, units[i], v)
+			}
+			out = append(out, row)
+		}
+		if errors.Is(err, io.EOF) {
+			return columns, out, nil
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrUnsupportedFile, err)
+		}
+	}
+}
+
+func fromValue(t Type, unit time.Duration, v parquet.Value) any {
+	switch t {
+	case String:
+		return string(v.ByteArray())
+	case JSON:
+		return bytes.Clone(v.ByteArray())
+	case Timestamp:
+		switch unit {
+		case time.Millisecond:
+			return time.UnixMilli(v.Int64()).UTC()
+		case time.Nanosecond:
+			return time.Unix(0, v.Int64()).UTC()
+		}
+		return time.UnixMicro(v.Int64()).UTC()
+	case Double:
+		return v.Double()
+	}
+	return v.Int64()
+}
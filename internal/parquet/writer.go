@@ -0,0 +1,91 @@
+package parquet
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// Writer writes a Parquet file to an io.Writer. Rows are buffered until
+// Flush writes them as a row group; Close writes the footer, without which
+// the file is unreadable.
+type Writer struct {
+	columns []Column
+	w       *parquet.Writer
+}
+
+func NewWriter(w io.Writer, columns []Column) *Writer {
+	return &Writer{
+		columns: columns,
+		w: parquet.NewWriter(w, newSchema(columns), &parquet.WriterConfig{
+			CreatedBy:   CreatedBy,
+			Compression: &parquet.Gzip,
+		}),
+	}
+}
+
+// Write buffers one row with a value per column, of the Go type of its
+// Type or nil in an optional column.
+func (w *Writer) Write(row []any) error {
+	if len(row) != len(w.columns) {
+		return fmt.Errorf("parquet: row has %d values for %d columns", len(row), len(w.columns))
+	}
+	values := make(parquet.Row, len(row))
+	for i, col := range w.columns {
+		v, err := toValue(col, row[i])
+		if err != nil {
+			return err
+		}
+		def := 0
+		if col.Optional && row[i] != nil {
+			def = 1
+		}
+		values[i] = v.Level(0, def, i)
+	}
+	_, err := w.w.WriteRows([]parquet.Row{values})
+	return err
+}
+
+func toValue(col Column, v any) (parquet.Value, error) {
+	switch v := v.(type) {
+	case nil:
+		if col.Optional {
+			return parquet.NullValue(), nil
+		}
+	case string:
+		if col.Type == String {
+			return parquet.ByteArrayValue([]byte(v)), nil
+		}
+	case []byte:
+		if col.Type == JSON {
+			return parquet.ByteArrayValue(v), nil
+		}
+	case int64:
+		if col.Type == Int64 {
+			return parquet.Int64Value(v), nil
+		}
+	case float64:
+		if col.Type == Double {
+			return parquet.DoubleValue(v), nil
+		}
+	case time.Time:
+		if col.Type == Timestamp {
+			return parquet.Int64Value(v.UnixMicro()), nil
+		}
+	}
+	return parquet.Value{}, fmt.Errorf("parquet: column %s cannot hold %T", col.Name, v)
+}
+
+// Flush writes the buffered rows as a row group. It does nothing without
+// rows.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// Close flushes the buffered rows and writes the footer. It does not close
+// the underlying writer.
+func (w *Writer) Close() error {
+	return w.w.Close()
+}
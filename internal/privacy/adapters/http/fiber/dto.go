@@ -0,0 +1,19 @@
+package fiber
+
+import "time"
+
+// DeletionReceiptResponse confirms an erasure request. Keep receipt_id for
+// compliance records; the service itself keeps nothing about the user.
+type DeletionReceiptResponse struct {
+	ReceiptID            string    `json:"receipt_id" example:"5d0c1c2e-8f0a-4a57-b7c4-0f9f3b0d6a11"`
+	UserID               string    `json:"user_id" example:"u1"`
+	ErasedAt             time.Time `json:"erased_at"`
+	EventsDeleted        int64     `json:"events_deleted" example:"42"`
+	IdentityLinksDeleted int64     `json:"identity_links_deleted" example:"1"`
+	DedupeKeysDeleted    int64     `json:"dedupe_keys_deleted" example:"3"`
+}
+
+type ErrorResponse struct {
+	Error   string `json:"error" example:"invalid_user_id"`
+	Message string `json:"message" example:"user_id is required"`
+}
@@ -0,0 +1,66 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"event-metrics-service/internal/privacy/core/domain"
+	"event-metrics-service/internal/privacy/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type EraseUserUseCase interface {
+	Execute(ctx context.Context, userID string) (domain.DeletionReceipt, error)
+}
+
+type PrivacyHandler struct {
+	uc EraseUserUseCase
+}
+
+func NewPrivacyHandler(uc EraseUserUseCase) *PrivacyHandler {
+	return &PrivacyHandler{uc: uc}
+}
+
+// EraseUser godoc
+// @Summary Erase a user's events
+// @Description Deletes every event of the user, anonymous events stitched to it via /identify, its identity links
+// @Description and the dedupe claims of the deleted events, in one atomic statement. Repeating the call is safe.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param user_id path string true "User ID (URL-escaped)"
+// @Success 200 {object} DeletionReceiptResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/{user_id}/events [delete]
+func (h *PrivacyHandler) EraseUser(c *fiber.Ctx) error {
+	userID, err := url.PathUnescape(c.Params("user_id"))
+	if err != nil {
+		userID = ""
+	}
+
+	receipt, err := h.uc.Execute(c.UserContext(), userID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidUserID) {
+			return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+				Error:   "invalid_user_id",
+				Message: "user_id is required and at most 100 characters",
+			})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error: "internal_server_error",
+		})
+	}
+
+	return c.JSON(DeletionReceiptResponse{
+		ReceiptID:            receipt.ReceiptID,
+		UserID:               receipt.UserID,
+		ErasedAt:             receipt.ErasedAt,
+		EventsDeleted:        receipt.EventsDeleted,
+		IdentityLinksDeleted: receipt.IdentityLinksDeleted,
+		DedupeKeysDeleted:    receipt.DedupeKeysDeleted,
+	})
+}
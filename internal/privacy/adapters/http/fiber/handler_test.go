@@ -0,0 +1,86 @@
+package fiber_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpadapter "event-metrics-service/internal/privacy/adapters/http/fiber"
+	"event-metrics-service/internal/privacy/core/domain"
+	"event-metrics-service/internal/privacy/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeEraseUserUseCase struct {
+	ExecuteFn func(ctx context.Context, userID string) (domain.DeletionReceipt, error)
+}
+
+func (f *fakeEraseUserUseCase) Execute(ctx context.Context, userID string) (domain.DeletionReceipt, error) {
+	return f.ExecuteFn(ctx, userID)
+}
+
+func setupApp(uc httpadapter.EraseUserUseCase) *fiber.App {
+	app := fiber.New()
+	h := httpadapter.NewPrivacyHandler(uc)
+	app.Delete("/users/:user_id/events", h.EraseUser)
+	return app
+}
+
+func TestEraseUser_Success(t *testing.T) {
+	erasedAt := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	uc := &fakeEraseUserUseCase{
+		ExecuteFn: func(ctx context.Context, userID string) (domain.DeletionReceipt, error) {
+			if userID != "user@example.com" {
+				t.Fatalf("expected unescaped user id, got %q", userID)
+			}
+			return domain.DeletionReceipt{ReceiptID: "r1", UserID: userID, ErasedAt: erasedAt, EventsDeleted: 3}, nil
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/users/user%40example.com/events", nil)
+	resp, err := setupApp(uc).Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body httpadapter.DeletionReceiptResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if body.ReceiptID != "r1" || body.EventsDeleted != 3 || !body.ErasedAt.Equal(erasedAt) {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+}
+
+func TestEraseUser_Errors(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantStatus int
+	}{
+		{usecase.ErrInvalidUserID, http.StatusBadRequest},
+		{errors.New("db failure"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		uc := &fakeEraseUserUseCase{
+			ExecuteFn: func(ctx context.Context, userID string) (domain.DeletionReceipt, error) {
+				return domain.DeletionReceipt{}, tt.err
+			},
+		}
+		resp, err := setupApp(uc).Test(httptest.NewRequest(http.MethodDelete, "/users/u1/events", nil))
+		if err != nil {
+			t.Fatalf("app.Test error: %v", err)
+		}
+		if resp.StatusCode != tt.wantStatus {
+			t.Fatalf("%v: expected %d, got %d", tt.err, tt.wantStatus, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"event-metrics-service/internal/privacy/core/ports"
+)
+
+type RowScanner interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+type DB interface {
+	QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+// Gated columns (see the migration module) the erasure depends on.
+const (
+	ColumnAnonymousID   = "events.anonymous_id"
+	ColumnIdentityLinks = "identity_links.stitch_from"
+	ColumnRehydrated    = "events_rehydrated.user_id"
+	ColumnRollups       = "event_rollups.unique_user"
+	ColumnCounters      = "event_counters.events"
+	ColumnSampleRate    = "events.sample_rate"
+	ColumnTenantID      = "events.tenant_id"
+)
+
+var GatedColumns = []string{
+	ColumnAnonymousID, ColumnIdentityLinks, ColumnRehydrated, ColumnRollups,
+	ColumnCounters, ColumnSampleRate, ColumnTenantID,
+}
+
+// ColumnGate reports whether a gated column may be written.
+type ColumnGate interface {
+	Writes(column string) bool
+}
+
+type UserEraser struct {
+	db       DB
+	gate     ColumnGate
+	counters bool
+}
+
+type EraserOption func(*UserEraser)
+
+// WithColumnGate skips identity links, stitched anonymous events,
+// rehydrated archive rows and rollups while their columns are gated off or
+// not migrated yet.
+func WithColumnGate(g ColumnGate) EraserOption {
+	return func(e *UserEraser) {
+		e.gate = g
+	}
+}
+
+// WithCounters takes erased events off their hourly counters in
+// event_counters (migration 021), in the same statement. Use it when ingest
+// increments them (EVENT_COUNTERS_ENABLED).
+func WithCounters() EraserOption {
+	return func(e *UserEraser) {
+		e.counters = true
+	}
+}
+
+func NewUserEraser(db DB, opts ...EraserOption) *UserEraser {
+	e := &UserEraser{db: db}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+var _ ports.UserEraserPort = (*UserEraser)(nil)
+
+// A single statement keeps the erasure atomic without a transaction:
+// data-modifying CTEs all run against the same snapshot.
+const eraseUserSQL = `
+WITH links AS (
+    DELETE FROM identity_links WHERE user_id = $1
+    RETURNING anonymous_id
+), deleted AS (
+    DELETE FROM events
+    WHERE user_id = $1 OR anonymous_id IN (SELECT anonymous_id FROM links)
+    RETURNING dedupe_key
+), claims AS (
+    DELETE FROM event_dedupe WHERE dedupe_key IN (SELECT dedupe_key FROM deleted)
+    RETURNING 1
+)
+SELECT (SELECT count(*) FROM deleted), (SELECT count(*) FROM links), (SELECT count(*) FROM claims)`
+
+// legacyEraseUserSQL is used before migration 009.
+const legacyEraseUserSQL = `
+WITH deleted AS (
+    DELETE FROM events WHERE user_id = $1
+    RETURNING dedupe_key
+), claims AS (
+    DELETE FROM event_dedupe WHERE dedupe_key IN (SELECT dedupe_key FROM deleted)
+    RETURNING 1
+)
+SELECT (SELECT count(*) FROM deleted), 0, (SELECT count(*) FROM claims)`
+
+// withRehydrated extends an erase statement to events restored from the
+// archive (migration 013); they count as erased events.
+func withRehydrated(query string, stitched bool) string {
+	cond := "user_id = $1"
+	if stitched {
+		cond += " OR anonymous_id IN (SELECT anonymous_id FROM links)"
+	}
+	cte := "), rehydrated AS (\n    DELETE FROM events_rehydrated WHERE " + cond + "\n    RETURNING 1\n)\n"
+	query = strings.Replace(query, ")\nSELECT (SELECT count(*) FROM deleted)", cte+"SELECT (SELECT count(*) FROM deleted) + (SELECT count(*) FROM rehydrated)", 1)
+	return query
+}
+
+// withRollups extends an erase statement to the rollup rows of the user
+// (migration 020), including those of their anonymous visitors counted
+// before stitching. Rollup rows are not events, so they are not counted.
+func withRollups(query string, stitched bool) string {
+	cond := "unique_user = $1"
+	if stitched {
+		cond += " OR unique_user IN (SELECT 'anon:' || anonymous_id FROM links)"
+	}
+	cte := "), rollups AS (\n    DELETE FROM event_rollups WHERE " + cond + "\n)\n"
+	return strings.Replace(query, ")\nSELECT (SELECT count(*) FROM deleted)", cte+"SELECT (SELECT count(*) FROM deleted)", 1)
+}
+
+// withCounters extends an erase statement to decrement the counters of the
+// erased events by their weight, keyed as the insert keyed them. Rehydrated
+// events were never counted.
+func withCounters(query, tenant, weight string) string {
+	returning := "RETURNING dedupe_key, " + tenant + ", event_name, channel, event_time, " + weight + " AS weight\n), claims AS ("
+	query = strings.Replace(query, "RETURNING dedupe_key\n), claims AS (", returning, 1)
+	cte := `), uncounted AS (
+    UPDATE event_counters c
+    SET events = c.events - d.events
+    FROM (
+        SELECT tenant_id, event_name, channel, date_trunc('hour', event_time) AS hour, SUM(weight) AS events
+        FROM deleted
+        GROUP BY 1, 2, 3, 4
+    ) d
+    WHERE c.tenant_id = d.tenant_id
+      AND c.event_name = d.event_name
+      AND c.hour = d.hour
+      AND c.channel = d.channel
+)
+`
+	return strings.Replace(query, ")\nSELECT (SELECT count(*) FROM deleted)", cte+"SELECT (SELECT count(*) FROM deleted)", 1)
+}
+
+func (e *UserEraser) EraseUser(ctx context.Context, userID string) (ports.ErasureCounts, error) {
+	allows := func(column string) bool { return e.gate == nil || e.gate.Writes(column) }
+
+	stitched := allows(ColumnAnonymousID) && allows(ColumnIdentityLinks)
+	query := eraseUserSQL
+	if !stitched {
+		query = legacyEraseUserSQL
+	}
+	if allows(ColumnRehydrated) {
+		query = withRehydrated(query, stitched)
+	}
+	if allows(ColumnRollups) {
+		query = withRollups(query, stitched)
+	}
+	if e.counters && allows(ColumnCounters) {
+		tenant, weight := "''::text AS tenant_id", "1::double precision"
+		if allows(ColumnTenantID) {
+			tenant = "tenant_id"
+		}
+		if allows(ColumnSampleRate) {
+			weight = "1 / sample_rate"
+		}
+		query = withCounters(query, tenant, weight)
+	}
+
+	rows, err := e.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return ports.ErasureCounts{}, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return ports.ErasureCounts{}, err
+		}
+		return ports.ErasureCounts{}, errors.New("erase user: no row returned")
+	}
+
+	var out ports.ErasureCounts
+	if err := rows.Scan(&out.Events, &out.IdentityLinks, &out.DedupeKeys); err != nil {
+		return ports.ErasureCounts{}, err
+	}
+	return out, rows.Err()
+}
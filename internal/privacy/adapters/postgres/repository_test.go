@@ -0,0 +1,225 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"event-metrics-service/internal/privacy/core/ports"
+)
+
+// fakeRowScanner implements RowScanner for tests.
+type fakeRowScanner struct {
+	rows [][]int64
+	i    int
+}
+
+func (f *fakeRowScanner) Next() bool {
+	return f.i < len(f.rows)
+}
+
+func (f *fakeRowScanner) Scan(dest ...any) error {
+	row := f.rows[f.i]
+	if len(dest) != len(row) {
+		return errors.New("dest length mismatch")
+	}
+	for i := range dest {
+		d, ok := dest[i].(*int64)
+		if !ok {
+			return errors.New("unsupported dest type")
+		}
+		*d = row[i]
+	}
+	f.i++
+	return nil
+}
+
+func (f *fakeRowScanner) Err() error   { return nil }
+func (f *fakeRowScanner) Close() error { return nil }
+
+type fakeDB struct {
+	QueryFn func(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+func (f *fakeDB) QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error) {
+	return f.QueryFn(ctx, query, args...)
+}
+
+type fakeGate map[string]bool
+
+func (g fakeGate) Writes(column string) bool { return g[column] }
+
+func TestUserEraser_DeletesStitchedEventsAndClaims(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			for _, want := range []string{
+				"DELETE FROM identity_links WHERE user_id = $1",
+				"anonymous_id IN (SELECT anonymous_id FROM links)",
+				"DELETE FROM event_dedupe",
+			} {
+				if !strings.Contains(query, want) {
+					t.Fatalf("query missing %q: %s", want, query)
+				}
+			}
+			if len(args) != 1 || args[0] != "u1" {
+				t.Fatalf("unexpected args: %v", args)
+			}
+			return &fakeRowScanner{rows: [][]int64{{5, 1, 4}}}, nil
+		},
+	}
+
+	got, err := NewUserEraser(db).EraseUser(context.Background(), "u1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != (ports.ErasureCounts{Events: 5, IdentityLinks: 1, DedupeKeys: 4}) {
+		t.Fatalf("unexpected counts: %+v", got)
+	}
+}
+
+func TestUserEraser_GatedIdentityColumns(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if strings.Contains(query, "identity_links") || strings.Contains(query, "anonymous_id") {
+				t.Fatalf("gated columns must not be referenced: %s", query)
+			}
+			return &fakeRowScanner{rows: [][]int64{{2, 0, 2}}}, nil
+		},
+	}
+
+	gate := fakeGate{ColumnAnonymousID: true, ColumnIdentityLinks: false}
+	got, err := NewUserEraser(db, WithColumnGate(gate)).EraseUser(context.Background(), "u1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Events != 2 || got.IdentityLinks != 0 {
+		t.Fatalf("unexpected counts: %+v", got)
+	}
+}
+
+func TestUserEraser_RehydratedEvents(t *testing.T) {
+	var query string
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, q string, args ...any) (RowScanner, error) {
+			query = q
+			return &fakeRowScanner{rows: [][]int64{{3, 0, 1}}}, nil
+		},
+	}
+
+	if _, err := NewUserEraser(db).EraseUser(context.Background(), "u1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"DELETE FROM events_rehydrated WHERE user_id = $1 OR anonymous_id IN (SELECT anonymous_id FROM links)",
+		"SELECT (SELECT count(*) FROM deleted) + (SELECT count(*) FROM rehydrated)",
+	} {
+		if !strings.Contains(query, want) {
+			t.Fatalf("query missing %q: %s", want, query)
+		}
+	}
+
+	// Before migration 009 only user_id is matched.
+	gate := fakeGate{ColumnRehydrated: true}
+	if _, err := NewUserEraser(db, WithColumnGate(gate)).EraseUser(context.Background(), "u1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(query, "DELETE FROM events_rehydrated WHERE user_id = $1\n") || strings.Contains(query, "links") {
+		t.Fatalf("unexpected legacy query: %s", query)
+	}
+
+	// Before migration 013 the table is not referenced.
+	gate = fakeGate{ColumnAnonymousID: true, ColumnIdentityLinks: true}
+	if _, err := NewUserEraser(db, WithColumnGate(gate)).EraseUser(context.Background(), "u1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(query, "events_rehydrated") {
+		t.Fatalf("expected events_rehydrated to be left out: %s", query)
+	}
+}
+
+func TestUserEraser_DBError(t *testing.T) {
+	boom := errors.New("db failure")
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return nil, boom
+		},
+	}
+
+	if _, err := NewUserEraser(db).EraseUser(context.Background(), "u1"); !errors.Is(err, boom) {
+		t.Fatalf("expected db error, got %v", err)
+	}
+}
+
+func TestUserEraser_Rollups(t *testing.T) {
+	var query string
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, q string, args ...any) (RowScanner, error) {
+			query = q
+			return &fakeRowScanner{rows: [][]int64{{3, 1, 3}}}, nil
+		},
+	}
+
+	got, err := NewUserEraser(db).EraseUser(context.Background(), "u1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(query, "DELETE FROM event_rollups WHERE unique_user = $1 OR unique_user IN (SELECT 'anon:' || anonymous_id FROM links)") {
+		t.Fatalf("query missing the rollups: %s", query)
+	}
+	if got.Events != 3 {
+		t.Fatalf("expected rollup rows not to count as events, got %+v", got)
+	}
+
+	// Before migration 020 the table is not referenced.
+	gate := fakeGate{ColumnAnonymousID: true, ColumnIdentityLinks: true, ColumnRehydrated: true}
+	if _, err := NewUserEraser(db, WithColumnGate(gate)).EraseUser(context.Background(), "u1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(query, "event_rollups") {
+		t.Fatalf("expected event_rollups to be left out: %s", query)
+	}
+}
+
+func TestUserEraser_Counters(t *testing.T) {
+	var query string
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, q string, args ...any) (RowScanner, error) {
+			query = q
+			return &fakeRowScanner{rows: [][]int64{{3, 1, 3}}}, nil
+		},
+	}
+
+	if _, err := NewUserEraser(db, WithCounters()).EraseUser(context.Background(), "u1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"RETURNING dedupe_key, tenant_id, event_name, channel, event_time, 1 / sample_rate AS weight",
+		"UPDATE event_counters c\n    SET events = c.events - d.events",
+		"FROM deleted\n",
+	} {
+		if !strings.Contains(query, want) {
+			t.Fatalf("query missing %q: %s", want, query)
+		}
+	}
+
+	// Without the option, or before migration 021, counters are left alone;
+	// without the tenant and sample rate columns, events count once in ''.
+	gate := fakeGate{ColumnAnonymousID: true, ColumnIdentityLinks: true, ColumnCounters: true}
+	for _, eraser := range []*UserEraser{
+		NewUserEraser(db),
+		NewUserEraser(db, WithCounters(), WithColumnGate(fakeGate{})),
+		NewUserEraser(db, WithCounters(), WithColumnGate(gate)),
+	} {
+		if _, err := eraser.EraseUser(context.Background(), "u1"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		uncounted := strings.Contains(query, "event_counters")
+		if want := eraser.counters && eraser.gate.Writes(ColumnCounters); uncounted != want {
+			t.Fatalf("expected counters to be decremented: %v, got query %s", want, query)
+		}
+	}
+	if !strings.Contains(query, "RETURNING dedupe_key, ''::text AS tenant_id, event_name, channel, event_time, 1::double precision AS weight") {
+		t.Fatalf("expected the default tenant and weight: %s", query)
+	}
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// DeletionReceipt records an erasure request for one user. It is returned to
+// the caller as proof of deletion; nothing about the user is kept.
+type DeletionReceipt struct {
+	ReceiptID string
+	UserID    string
+	ErasedAt  time.Time
+
+	EventsDeleted        int64
+	IdentityLinksDeleted int64
+	DedupeKeysDeleted    int64
+}
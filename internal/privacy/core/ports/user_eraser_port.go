@@ -0,0 +1,17 @@
+package ports
+
+import "context"
+
+// ErasureCounts is what an erasure removed.
+type ErasureCounts struct {
+	Events        int64
+	IdentityLinks int64
+	DedupeKeys    int64
+}
+
+type UserEraserPort interface {
+	// EraseUser atomically deletes every event of userID, the anonymous
+	// events and identity links stitched to it, and the dedupe claims of the
+	// deleted events, which embed the user ID.
+	EraseUser(ctx context.Context, userID string) (ErasureCounts, error)
+}
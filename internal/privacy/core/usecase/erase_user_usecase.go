@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"event-metrics-service/internal/privacy/core/domain"
+	"event-metrics-service/internal/privacy/core/ports"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidUserID = errors.New("invalid user_id")
+
+// MaxUserIDLength matches the events.user_id column.
+const MaxUserIDLength = 100
+
+// EraseUserUseCase serves right-to-erasure requests. Rows are deleted rather
+// than anonymized, so aggregate metrics over the erased period drop the user.
+type EraseUserUseCase struct {
+	eraser ports.UserEraserPort
+	now    func() time.Time
+}
+
+func NewEraseUserUseCase(eraser ports.UserEraserPort) *EraseUserUseCase {
+	return &EraseUserUseCase{eraser: eraser, now: time.Now}
+}
+
+// Execute is idempotent: erasing a user with no data left succeeds with zero
+// counts.
+func (uc *EraseUserUseCase) Execute(ctx context.Context, userID string) (domain.DeletionReceipt, error) {
+	if userID == "" || len(userID) > MaxUserIDLength {
+		return domain.DeletionReceipt{}, ErrInvalidUserID
+	}
+
+	counts, err := uc.eraser.EraseUser(ctx, userID)
+	if err != nil {
+		return domain.DeletionReceipt{}, err
+	}
+
+	return domain.DeletionReceipt{
+		ReceiptID:            uuid.NewString(),
+		UserID:               userID,
+		ErasedAt:             uc.now().UTC(),
+		EventsDeleted:        counts.Events,
+		IdentityLinksDeleted: counts.IdentityLinks,
+		DedupeKeysDeleted:    counts.DedupeKeys,
+	}, nil
+}
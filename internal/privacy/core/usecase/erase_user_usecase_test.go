@@ -0,0 +1,69 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"event-metrics-service/internal/privacy/core/ports"
+	"event-metrics-service/internal/privacy/core/usecase"
+)
+
+type fakeUserEraser struct {
+	EraseFn func(ctx context.Context, userID string) (ports.ErasureCounts, error)
+}
+
+func (f *fakeUserEraser) EraseUser(ctx context.Context, userID string) (ports.ErasureCounts, error) {
+	return f.EraseFn(ctx, userID)
+}
+
+func TestEraseUser_ReturnsReceipt(t *testing.T) {
+	eraser := &fakeUserEraser{
+		EraseFn: func(ctx context.Context, userID string) (ports.ErasureCounts, error) {
+			if userID != "u1" {
+				t.Fatalf("unexpected user: %s", userID)
+			}
+			return ports.ErasureCounts{Events: 3, IdentityLinks: 1, DedupeKeys: 2}, nil
+		},
+	}
+	uc := usecase.NewEraseUserUseCase(eraser)
+
+	receipt, err := uc.Execute(context.Background(), "u1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if receipt.ReceiptID == "" || receipt.ErasedAt.IsZero() || receipt.UserID != "u1" ||
+		receipt.EventsDeleted != 3 || receipt.IdentityLinksDeleted != 1 || receipt.DedupeKeysDeleted != 2 {
+		t.Fatalf("unexpected receipt: %+v", receipt)
+	}
+}
+
+func TestEraseUser_InvalidUserID(t *testing.T) {
+	eraser := &fakeUserEraser{
+		EraseFn: func(ctx context.Context, userID string) (ports.ErasureCounts, error) {
+			t.Fatalf("eraser must not be called")
+			return ports.ErasureCounts{}, nil
+		},
+	}
+	uc := usecase.NewEraseUserUseCase(eraser)
+
+	for _, id := range []string{"", strings.Repeat("x", usecase.MaxUserIDLength+1)} {
+		if _, err := uc.Execute(context.Background(), id); !errors.Is(err, usecase.ErrInvalidUserID) {
+			t.Fatalf("%q: expected ErrInvalidUserID, got %v", id, err)
+		}
+	}
+}
+
+func TestEraseUser_RepositoryError(t *testing.T) {
+	boom := errors.New("db failure")
+	eraser := &fakeUserEraser{
+		EraseFn: func(ctx context.Context, userID string) (ports.ErasureCounts, error) {
+			return ports.ErasureCounts{}, boom
+		},
+	}
+
+	if _, err := usecase.NewEraseUserUseCase(eraser).Execute(context.Background(), "u1"); !errors.Is(err, boom) {
+		t.Fatalf("expected repository error, got %v", err)
+	}
+}
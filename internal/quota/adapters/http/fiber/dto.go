@@ -0,0 +1,19 @@
+package fiber
+
+import "time"
+
+type QuotaStatusResponse struct {
+	APIKey    string    `json:"api_key" example:"key-a"`
+	Unlimited bool      `json:"unlimited"`
+	Limit     int64     `json:"limit,omitempty" example:"100000"`
+	Used      int64     `json:"used" example:"85000"`
+	Remaining int64     `json:"remaining,omitempty" example:"15000"`
+	ResetAt   time.Time `json:"reset_at"`
+	Warning   bool      `json:"warning"`
+	Exceeded  bool      `json:"exceeded"`
+}
+
+type ErrorResponse struct {
+	Error   string `json:"error" example:"internal_server_error"`
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,58 @@
+package fiber
+
+import (
+	"context"
+	"net/http"
+
+	"event-metrics-service/internal/quota/core/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type QuotaStatusUseCase interface {
+	Status(ctx context.Context, apiKey string) (domain.Status, error)
+}
+
+type QuotaHandler struct {
+	uc    QuotaStatusUseCase
+	keyFn func(c *fiber.Ctx) string
+}
+
+// NewQuotaHandler reports the quota of the key returned by keyFn.
+func NewQuotaHandler(uc QuotaStatusUseCase, keyFn func(c *fiber.Ctx) string) *QuotaHandler {
+	return &QuotaHandler{uc: uc, keyFn: keyFn}
+}
+
+// GetQuota godoc
+// @Summary Daily ingest quota of the calling API key
+// @Description Quotas are soft: ingest keeps working past the limit, but responses carry X-Quota-Warning.
+// @Tags Quota
+// @Produce json
+// @Success 200 {object} QuotaStatusResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /quota [get]
+func (h *QuotaHandler) GetQuota(c *fiber.Ctx) error {
+	s, err := h.uc.Status(c.UserContext(), h.keyFn(c))
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error: "internal_server_error",
+		})
+	}
+
+	setQuotaHeaders(c, s)
+
+	resp := QuotaStatusResponse{
+		APIKey:    s.APIKey,
+		Unlimited: s.Unlimited(),
+		Used:      s.Used,
+		ResetAt:   s.ResetAt,
+		Warning:   s.Warning,
+		Exceeded:  s.Exceeded(),
+	}
+	if !s.Unlimited() {
+		resp.Limit = s.Limit
+		resp.Remaining = s.Remaining()
+	}
+
+	return c.JSON(resp)
+}
@@ -0,0 +1,61 @@
+package fiber
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"event-metrics-service/internal/quota/core/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	HeaderQuotaLimit     = "X-Quota-Limit"
+	HeaderQuotaRemaining = "X-Quota-Remaining"
+	HeaderQuotaReset     = "X-Quota-Reset"
+	HeaderQuotaWarning   = "X-Quota-Warning"
+)
+
+type QuotaConsumer interface {
+	Consume(ctx context.Context, apiKey string, n int64) (domain.Status, error)
+}
+
+// SoftQuota counts each successfully handled request against the caller's
+// daily quota and reports the result in X-Quota-* response headers. It never
+// rejects a request, and a failing usage store only drops the headers.
+func SoftQuota(uc QuotaConsumer, keyFn func(c *fiber.Ctx) string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+		if c.Response().StatusCode() >= http.StatusBadRequest {
+			return nil
+		}
+
+		s, err := uc.Consume(c.UserContext(), keyFn(c), 1)
+		if err != nil {
+			return nil
+		}
+		setQuotaHeaders(c, s)
+		return nil
+	}
+}
+
+func setQuotaHeaders(c *fiber.Ctx, s domain.Status) {
+	if s.Unlimited() {
+		return
+	}
+
+	c.Set(HeaderQuotaLimit, strconv.FormatInt(s.Limit, 10))
+	c.Set(HeaderQuotaRemaining, strconv.FormatInt(s.Remaining(), 10))
+	c.Set(HeaderQuotaReset, strconv.FormatInt(s.ResetAt.Unix(), 10))
+
+	switch {
+	case s.Exceeded():
+		c.Set(HeaderQuotaWarning, fmt.Sprintf("daily quota exceeded (%d/%d)", s.Used, s.Limit))
+	case s.Warning:
+		c.Set(HeaderQuotaWarning, fmt.Sprintf("%d%% of daily quota used", s.Used*100/s.Limit))
+	}
+}
@@ -0,0 +1,155 @@
+package fiber
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/quota/core/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeQuota struct {
+	status   domain.Status
+	err      error
+	consumed int64
+	lastKey  string
+}
+
+func (f *fakeQuota) Consume(ctx context.Context, apiKey string, n int64) (domain.Status, error) {
+	f.consumed += n
+	f.lastKey = apiKey
+	return f.status, f.err
+}
+
+func (f *fakeQuota) Status(ctx context.Context, apiKey string) (domain.Status, error) {
+	f.lastKey = apiKey
+	return f.status, f.err
+}
+
+func keyFromHeader(c *fiber.Ctx) string { return c.Get("X-API-Key") }
+
+func newQuotaApp(q *fakeQuota, status int) *fiber.App {
+	app := fiber.New()
+	app.Post("/events", SoftQuota(q, keyFromHeader), func(c *fiber.Ctx) error {
+		return c.SendStatus(status)
+	})
+	app.Get("/quota", NewQuotaHandler(q, keyFromHeader).GetQuota)
+	return app
+}
+
+func TestSoftQuota_Headers(t *testing.T) {
+	reset := time.Date(2025, 12, 8, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		status        domain.Status
+		wantRemaining string
+		wantWarning   string
+	}{
+		{"below threshold", domain.Status{Limit: 100, Used: 10, ResetAt: reset}, "90", ""},
+		{"warning", domain.Status{Limit: 100, Used: 85, ResetAt: reset, Warning: true}, "15", "85% of daily quota used"},
+		{"exceeded", domain.Status{Limit: 100, Used: 120, ResetAt: reset, Warning: true}, "0", "daily quota exceeded (120/100)"},
+		{"unlimited", domain.Status{Used: 5}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &fakeQuota{status: tt.status}
+			app := newQuotaApp(q, http.StatusCreated)
+
+			req := httptest.NewRequest(http.MethodPost, "/events", nil)
+			req.Header.Set("X-API-Key", "key-a")
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("app.Test error: %v", err)
+			}
+			if resp.StatusCode != http.StatusCreated {
+				t.Fatalf("soft quota must not change the status, got %d", resp.StatusCode)
+			}
+			if q.consumed != 1 || q.lastKey != "key-a" {
+				t.Fatalf("expected one unit consumed for key-a, got %d for %q", q.consumed, q.lastKey)
+			}
+			if got := resp.Header.Get(HeaderQuotaRemaining); got != tt.wantRemaining {
+				t.Fatalf("expected remaining %q, got %q", tt.wantRemaining, got)
+			}
+			if got := resp.Header.Get(HeaderQuotaWarning); got != tt.wantWarning {
+				t.Fatalf("expected warning %q, got %q", tt.wantWarning, got)
+			}
+		})
+	}
+}
+
+func TestSoftQuota_SkipsFailedRequests(t *testing.T) {
+	q := &fakeQuota{status: domain.Status{Limit: 100}}
+	app := newQuotaApp(q, http.StatusBadRequest)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/events", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if q.consumed != 0 || resp.Header.Get(HeaderQuotaRemaining) != "" {
+		t.Fatalf("rejected requests must not count against the quota")
+	}
+}
+
+func TestSoftQuota_StoreErrorDoesNotFailIngest(t *testing.T) {
+	q := &fakeQuota{err: errors.New("db down")}
+	app := newQuotaApp(q, http.StatusCreated)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/events", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+}
+
+// ------------------------------------------------------------
+// STATUS ENDPOINT
+// ------------------------------------------------------------
+
+func TestGetQuota(t *testing.T) {
+	q := &fakeQuota{status: domain.Status{APIKey: "key-a", Limit: 100, Used: 85, Warning: true}}
+	app := newQuotaApp(q, http.StatusCreated)
+
+	req := httptest.NewRequest(http.MethodGet, "/quota", nil)
+	req.Header.Set("X-API-Key", "key-a")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if q.consumed != 0 {
+		t.Fatalf("status endpoint must not consume quota")
+	}
+
+	var body QuotaStatusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if body.APIKey != "key-a" || body.Remaining != 15 || !body.Warning || body.Exceeded {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+}
+
+func TestGetQuota_Error(t *testing.T) {
+	q := &fakeQuota{err: errors.New("db down")}
+	app := newQuotaApp(q, http.StatusCreated)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/quota", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"event-metrics-service/internal/quota/core/ports"
+)
+
+type RowScanner interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+type DB interface {
+	QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+type UsageRepository struct {
+	db DB
+}
+
+func NewUsageRepository(db DB) *UsageRepository {
+	return &UsageRepository{db: db}
+}
+
+var _ ports.UsageCounterPort = (*UsageRepository)(nil)
+
+const addUsageSQL = `
+INSERT INTO quota_usage (api_key, day, used)
+VALUES ($1, $2, $3)
+ON CONFLICT (api_key, day) DO UPDATE
+SET used = quota_usage.used + EXCLUDED.used
+RETURNING used`
+
+const usageSQL = `
+SELECT used FROM quota_usage WHERE api_key = $1 AND day = $2`
+
+func (r *UsageRepository) AddUsage(ctx context.Context, apiKey string, day time.Time, n int64) (int64, error) {
+	return r.queryUsage(ctx, addUsageSQL, apiKey, day, n)
+}
+
+func (r *UsageRepository) Usage(ctx context.Context, apiKey string, day time.Time) (int64, error) {
+	return r.queryUsage(ctx, usageSQL, apiKey, day)
+}
+
+func (r *UsageRepository) queryUsage(ctx context.Context, query string, args ...any) (int64, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var used int64
+	if rows.Next() {
+		if err := rows.Scan(&used); err != nil {
+			return 0, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	return used, nil
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeRowScanner struct {
+	rows [][]any
+	i    int
+}
+
+func (f *fakeRowScanner) Next() bool {
+	return f.i < len(f.rows)
+}
+
+func (f *fakeRowScanner) Scan(dest ...any) error {
+	row := f.rows[f.i]
+	if len(dest) != len(row) {
+		return errors.New("dest length mismatch")
+	}
+	for i := range dest {
+		d, ok := dest[i].(*int64)
+		if !ok {
+			return errors.New("unsupported dest type")
+		}
+		*d = row[i].(int64)
+	}
+	f.i++
+	return nil
+}
+
+func (f *fakeRowScanner) Err() error   { return nil }
+func (f *fakeRowScanner) Close() error { return nil }
+
+type fakeDB struct {
+	QueryFn func(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+func (f *fakeDB) QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error) {
+	return f.QueryFn(ctx, query, args...)
+}
+
+func TestUsageRepository_AddUsage(t *testing.T) {
+	day := time.Date(2025, 12, 7, 0, 0, 0, 0, time.UTC)
+	var gotArgs []any
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if !strings.Contains(query, "ON CONFLICT (api_key, day)") || !strings.Contains(query, "RETURNING used") {
+				t.Fatalf("expected upsert returning usage, got %s", query)
+			}
+			gotArgs = args
+			return &fakeRowScanner{rows: [][]any{{int64(42)}}}, nil
+		},
+	}
+
+	used, err := NewUsageRepository(db).AddUsage(context.Background(), "key-a", day, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if used != 42 {
+		t.Fatalf("expected 42, got %d", used)
+	}
+	if gotArgs[0] != "key-a" || gotArgs[1] != day || gotArgs[2] != int64(2) {
+		t.Fatalf("unexpected args: %v", gotArgs)
+	}
+}
+
+func TestUsageRepository_UsageWithoutRow(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return &fakeRowScanner{}, nil
+		},
+	}
+
+	used, err := NewUsageRepository(db).Usage(context.Background(), "key-a", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if used != 0 {
+		t.Fatalf("expected 0 for a key without usage, got %d", used)
+	}
+}
+
+func TestUsageRepository_DBError(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return nil, errors.New("db failure")
+		},
+	}
+
+	if _, err := NewUsageRepository(db).AddUsage(context.Background(), "key-a", time.Now(), 1); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// Status is an API key's usage of its daily ingest quota. Quotas are soft:
+// exceeding them is reported, not rejected.
+type Status struct {
+	APIKey  string
+	Limit   int64 // 0 = unlimited
+	Used    int64
+	ResetAt time.Time // start of the next UTC day
+
+	// Warning is set once usage reaches the warning threshold.
+	Warning bool
+}
+
+// Unlimited reports whether the key has no quota.
+func (s Status) Unlimited() bool {
+	return s.Limit <= 0
+}
+
+// Remaining never goes below zero; it is only meaningful with a limit.
+func (s Status) Remaining() int64 {
+	if s.Used >= s.Limit {
+		return 0
+	}
+	return s.Limit - s.Used
+}
+
+// Exceeded reports whether usage is past the limit.
+func (s Status) Exceeded() bool {
+	return !s.Unlimited() && s.Used > s.Limit
+}
+
+// Day truncates t to the start of its UTC day, the quota period.
+func Day(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
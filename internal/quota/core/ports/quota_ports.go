@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+type UsageCounterPort interface {
+	// AddUsage adds n to the key's usage for day and returns the new total.
+	AddUsage(ctx context.Context, apiKey string, day time.Time, n int64) (int64, error)
+	// Usage returns the key's usage for day (0 when nothing was recorded).
+	Usage(ctx context.Context, apiKey string, day time.Time) (int64, error)
+}
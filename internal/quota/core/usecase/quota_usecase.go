@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"event-metrics-service/internal/quota/core/domain"
+	"event-metrics-service/internal/quota/core/ports"
+)
+
+// DefaultWarnPercent is the share of the daily quota after which responses
+// carry a warning.
+const DefaultWarnPercent = 80
+
+type QuotaUseCase struct {
+	counter      ports.UsageCounterPort
+	defaultLimit int64
+	overrides    map[string]int64
+	warnPercent  int64
+	now          func() time.Time
+}
+
+type Option func(*QuotaUseCase)
+
+// WithOverrides sets per API key daily limits (0 = unlimited).
+func WithOverrides(limits map[string]int) Option {
+	return func(uc *QuotaUseCase) {
+		for k, v := range limits {
+			uc.overrides[k] = int64(v)
+		}
+	}
+}
+
+// WithWarnPercent sets the usage percentage that triggers warnings.
+func WithWarnPercent(p int) Option {
+	return func(uc *QuotaUseCase) {
+		if p > 0 {
+			uc.warnPercent = int64(p)
+		}
+	}
+}
+
+// NewQuotaUseCase tracks daily usage per API key against defaultLimit
+// (0 = unlimited) unless a key has an override.
+func NewQuotaUseCase(counter ports.UsageCounterPort, defaultLimit int, opts ...Option) *QuotaUseCase {
+	uc := &QuotaUseCase{
+		counter:      counter,
+		defaultLimit: int64(defaultLimit),
+		overrides:    map[string]int64{},
+		warnPercent:  DefaultWarnPercent,
+		now:          time.Now,
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// Consume records n units of usage for apiKey and returns the updated status.
+func (uc *QuotaUseCase) Consume(ctx context.Context, apiKey string, n int64) (domain.Status, error) {
+	day := domain.Day(uc.now())
+	used, err := uc.counter.AddUsage(ctx, apiKey, day, n)
+	if err != nil {
+		return domain.Status{}, err
+	}
+	return uc.status(apiKey, day, used), nil
+}
+
+// Status returns apiKey's usage for the current day without consuming any.
+func (uc *QuotaUseCase) Status(ctx context.Context, apiKey string) (domain.Status, error) {
+	day := domain.Day(uc.now())
+	used, err := uc.counter.Usage(ctx, apiKey, day)
+	if err != nil {
+		return domain.Status{}, err
+	}
+	return uc.status(apiKey, day, used), nil
+}
+
+func (uc *QuotaUseCase) limitFor(apiKey string) int64 {
+	if l, ok := uc.overrides[apiKey]; ok {
+		return l
+	}
+	return uc.defaultLimit
+}
+
+func (uc *QuotaUseCase) status(apiKey string, day time.Time, used int64) domain.Status {
+	s := domain.Status{
+		APIKey:  apiKey,
+		Limit:   uc.limitFor(apiKey),
+		Used:    used,
+		ResetAt: day.AddDate(0, 0, 1),
+	}
+	s.Warning = !s.Unlimited() && used*100 >= s.Limit*uc.warnPercent
+	return s
+}
@@ -0,0 +1,127 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/quota/core/usecase"
+)
+
+// fakeCounter keeps usage in memory, keyed by API key and day.
+type fakeCounter struct {
+	used    map[string]int64
+	lastDay time.Time
+	err     error
+}
+
+func newFakeCounter() *fakeCounter {
+	return &fakeCounter{used: map[string]int64{}}
+}
+
+func (f *fakeCounter) AddUsage(ctx context.Context, apiKey string, day time.Time, n int64) (int64, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	f.lastDay = day
+	f.used[apiKey] += n
+	return f.used[apiKey], nil
+}
+
+func (f *fakeCounter) Usage(ctx context.Context, apiKey string, day time.Time) (int64, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	f.lastDay = day
+	return f.used[apiKey], nil
+}
+
+// ------------------------------------------------------------
+// CONSUME
+// ------------------------------------------------------------
+
+func TestQuota_ConsumeWarnsNearLimit(t *testing.T) {
+	counter := newFakeCounter()
+	uc := usecase.NewQuotaUseCase(counter, 10)
+
+	s, err := uc.Consume(context.Background(), "key-a", 7)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Used != 7 || s.Remaining() != 3 || s.Warning {
+		t.Fatalf("unexpected status below threshold: %+v", s)
+	}
+
+	s, _ = uc.Consume(context.Background(), "key-a", 1)
+	if !s.Warning || s.Remaining() != 2 || s.Exceeded() {
+		t.Fatalf("expected warning at 80%%: %+v", s)
+	}
+
+	s, _ = uc.Consume(context.Background(), "key-a", 5)
+	if !s.Exceeded() || s.Remaining() != 0 {
+		t.Fatalf("expected exceeded quota with 0 remaining: %+v", s)
+	}
+
+	day := counter.lastDay
+	if day.Hour() != 0 || day.Minute() != 0 || day.Location() != time.UTC {
+		t.Fatalf("expected usage to be bucketed by UTC day, got %v", day)
+	}
+	if !s.ResetAt.Equal(day.Add(24 * time.Hour)) {
+		t.Fatalf("expected reset at next UTC midnight, got %v", s.ResetAt)
+	}
+}
+
+func TestQuota_OverridesAndWarnPercent(t *testing.T) {
+	uc := usecase.NewQuotaUseCase(newFakeCounter(), 100,
+		usecase.WithOverrides(map[string]int{"big": 1000, "free": 0}),
+		usecase.WithWarnPercent(50),
+	)
+
+	s, _ := uc.Consume(context.Background(), "big", 500)
+	if s.Limit != 1000 || !s.Warning {
+		t.Fatalf("expected override limit with warning at 50%%: %+v", s)
+	}
+
+	s, _ = uc.Consume(context.Background(), "free", 1_000_000)
+	if !s.Unlimited() || s.Warning || s.Exceeded() {
+		t.Fatalf("expected unlimited key to never warn: %+v", s)
+	}
+
+	s, _ = uc.Consume(context.Background(), "other", 10)
+	if s.Limit != 100 || s.Warning {
+		t.Fatalf("expected default limit: %+v", s)
+	}
+}
+
+// ------------------------------------------------------------
+// STATUS
+// ------------------------------------------------------------
+
+func TestQuota_StatusDoesNotConsume(t *testing.T) {
+	counter := newFakeCounter()
+	uc := usecase.NewQuotaUseCase(counter, 10)
+
+	_, _ = uc.Consume(context.Background(), "key-a", 3)
+
+	s, err := uc.Status(context.Background(), "key-a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Used != 3 || counter.used["key-a"] != 3 {
+		t.Fatalf("expected status to report usage without consuming: %+v", s)
+	}
+}
+
+func TestQuota_CounterError(t *testing.T) {
+	counter := newFakeCounter()
+	counter.err = errors.New("db down")
+	uc := usecase.NewQuotaUseCase(counter, 10)
+
+	if _, err := uc.Consume(context.Background(), "key-a", 1); err == nil {
+		t.Fatalf("expected error from Consume")
+	}
+	if _, err := uc.Status(context.Background(), "key-a"); err == nil {
+		t.Fatalf("expected error from Status")
+	}
+}
@@ -0,0 +1,27 @@
+package fiber
+
+import "time"
+
+// ReportRequest creates or replaces a report.
+type ReportRequest struct {
+	Name        string `json:"name,omitempty" example:"weekly-purchases"` // create only; PUT takes it from the path
+	Description string `json:"description,omitempty" example:"Purchases per channel over the last week"`
+	Query       string `json:"query" example:"event_name=purchase&group_by=channel&range=168h"`
+}
+
+type ReportResponse struct {
+	Name        string    `json:"name" example:"weekly-purchases"`
+	Description string    `json:"description"`
+	Query       string    `json:"query" example:"event_name=purchase&group_by=channel&range=168h"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type ReportListResponse struct {
+	Reports []ReportResponse `json:"reports"`
+}
+
+type ErrorResponse struct {
+	Error   string `json:"error" example:"invalid_report"`
+	Message string `json:"message" example:"invalid report: query must have an event_name"`
+}
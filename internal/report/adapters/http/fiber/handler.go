@@ -0,0 +1,229 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"event-metrics-service/internal/report/core/domain"
+	"event-metrics-service/internal/report/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReportUseCase interface {
+	Create(ctx context.Context, name string, in usecase.ReportInput) (domain.Report, error)
+	Update(ctx context.Context, name string, in usecase.ReportInput) (domain.Report, error)
+	Delete(ctx context.Context, name string) error
+	Get(ctx context.Context, name string) (domain.Report, error)
+	List(ctx context.Context) ([]domain.Report, error)
+	Run(ctx context.Context, name string, params url.Values) (url.Values, error)
+}
+
+type ReportHandler struct {
+	uc      ReportUseCase
+	metrics fiber.Handler
+}
+
+// NewReportHandler runs reports through metrics, the GET /metrics handler,
+// so a run answers exactly like the query it was saved from.
+func NewReportHandler(uc ReportUseCase, metrics fiber.Handler) *ReportHandler {
+	return &ReportHandler{uc: uc, metrics: metrics}
+}
+
+// CreateReport godoc
+// @Summary Save a metrics query as a named report
+// @Description query is a GET /metrics query string; a pasted URL is cut at "?". range= (e.g. 168h) stands in for from/to and ends at the time of each run; without either a report covers the last 24h.
+// @Description The query is only checked for event_name and its time range here; GET /metrics validates the rest on each run.
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Param request body ReportRequest true "Report"
+// @Param Authorization header string false "Bearer JWT from OIDC_ISSUER, required when it is set"
+// @Success 201 {object} ReportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /reports [post]
+func (h *ReportHandler) CreateReport(c *fiber.Ctx) error {
+	var req ReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid_json",
+		})
+	}
+
+	r, err := h.uc.Create(c.UserContext(), req.Name, toReportInput(req))
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(toReportResponse(r))
+}
+
+// UpdateReport godoc
+// @Summary Replace the description and query of a report
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Param name path string true "Report name"
+// @Param request body ReportRequest true "Report"
+// @Param Authorization header string false "Bearer JWT from OIDC_ISSUER, required when it is set"
+// @Success 200 {object} ReportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /reports/{name} [put]
+func (h *ReportHandler) UpdateReport(c *fiber.Ctx) error {
+	var req ReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid_json",
+		})
+	}
+
+	r, err := h.uc.Update(c.UserContext(), c.Params("name"), toReportInput(req))
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.JSON(toReportResponse(r))
+}
+
+// GetReport godoc
+// @Summary Get a report
+// @Tags Reports
+// @Produce json
+// @Param name path string true "Report name"
+// @Param Authorization header string false "Bearer JWT from OIDC_ISSUER, required when it is set"
+// @Success 200 {object} ReportResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /reports/{name} [get]
+func (h *ReportHandler) GetReport(c *fiber.Ctx) error {
+	r, err := h.uc.Get(c.UserContext(), c.Params("name"))
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.JSON(toReportResponse(r))
+}
+
+// ListReports godoc
+// @Summary List reports by name
+// @Tags Reports
+// @Produce json
+// @Param Authorization header string false "Bearer JWT from OIDC_ISSUER, required when it is set"
+// @Success 200 {object} ReportListResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /reports [get]
+func (h *ReportHandler) ListReports(c *fiber.Ctx) error {
+	reports, err := h.uc.List(c.UserContext())
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	resp := ReportListResponse{Reports: make([]ReportResponse, 0, len(reports))}
+	for _, r := range reports {
+		resp.Reports = append(resp.Reports, toReportResponse(r))
+	}
+
+	return c.JSON(resp)
+}
+
+// DeleteReport godoc
+// @Summary Delete a report
+// @Tags Reports
+// @Param name path string true "Report name"
+// @Param Authorization header string false "Bearer JWT from OIDC_ISSUER, required when it is set"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /reports/{name} [delete]
+func (h *ReportHandler) DeleteReport(c *fiber.Ctx) error {
+	if err := h.uc.Delete(c.UserContext(), c.Params("name")); err != nil {
+		return writeError(c, err)
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+// RunReport godoc
+// @Summary Run a report
+// @Description Answers like GET /metrics with the saved query. Query parameters override the saved ones, e.g. format=ndjson or offset for the next page; range=, or from and to, replace the saved time range.
+// @Tags Reports
+// @Produce json
+// @Param name path string true "Report name"
+// @Param range query string false "Relative time range ending now, e.g. 168h"
+// @Param from query int false "Start (unix seconds), with to"
+// @Param to query int false "End (unix seconds), with from"
+// @Param Authorization header string false "Bearer JWT from OIDC_ISSUER, required when it is set"
+// @Success 200 {object} object "the GET /metrics response"
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse "query_concurrency_limited: too many concurrent queries for this API key"
+// @Failure 500 {object} ErrorResponse
+// @Router /reports/{name}/run [get]
+func (h *ReportHandler) RunReport(c *fiber.Ctx) error {
+	params, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error:   "invalid_query",
+			Message: err.Error(),
+		})
+	}
+
+	q, err := h.uc.Run(c.UserContext(), c.Params("name"), params)
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	c.Request().URI().SetQueryString(q.Encode())
+	return h.metrics(c)
+}
+
+func writeError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidReport):
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error:   "invalid_report",
+			Message: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrInvalidRun):
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error:   "invalid_query",
+			Message: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrReportNotFound):
+		return c.Status(http.StatusNotFound).JSON(ErrorResponse{
+			Error:   "report_not_found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrReportExists):
+		return c.Status(http.StatusConflict).JSON(ErrorResponse{
+			Error:   "report_exists",
+			Message: err.Error(),
+		})
+	}
+	return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+		Error: "internal_server_error",
+	})
+}
+
+func toReportInput(req ReportRequest) usecase.ReportInput {
+	return usecase.ReportInput{
+		Description: req.Description,
+		Query:       req.Query,
+	}
+}
+
+func toReportResponse(r domain.Report) ReportResponse {
+	return ReportResponse{
+		Name:        r.Name,
+		Description: r.Description,
+		Query:       r.Query,
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
@@ -0,0 +1,169 @@
+package fiber_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	httpadapter "event-metrics-service/internal/report/adapters/http/fiber"
+	"event-metrics-service/internal/report/core/domain"
+	"event-metrics-service/internal/report/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeReportUseCase struct {
+	CreateFn func(ctx context.Context, name string, in usecase.ReportInput) (domain.Report, error)
+	UpdateFn func(ctx context.Context, name string, in usecase.ReportInput) (domain.Report, error)
+	DeleteFn func(ctx context.Context, name string) error
+	GetFn    func(ctx context.Context, name string) (domain.Report, error)
+	ListFn   func(ctx context.Context) ([]domain.Report, error)
+	RunFn    func(ctx context.Context, name string, params url.Values) (url.Values, error)
+}
+
+func (f *fakeReportUseCase) Create(ctx context.Context, name string, in usecase.ReportInput) (domain.Report, error) {
+	return f.CreateFn(ctx, name, in)
+}
+
+func (f *fakeReportUseCase) Update(ctx context.Context, name string, in usecase.ReportInput) (domain.Report, error) {
+	return f.UpdateFn(ctx, name, in)
+}
+
+func (f *fakeReportUseCase) Delete(ctx context.Context, name string) error {
+	return f.DeleteFn(ctx, name)
+}
+
+func (f *fakeReportUseCase) Get(ctx context.Context, name string) (domain.Report, error) {
+	return f.GetFn(ctx, name)
+}
+
+func (f *fakeReportUseCase) List(ctx context.Context) ([]domain.Report, error) {
+	return f.ListFn(ctx)
+}
+
+func (f *fakeReportUseCase) Run(ctx context.Context, name string, params url.Values) (url.Values, error) {
+	return f.RunFn(ctx, name, params)
+}
+
+// echoMetrics stands in for GET /metrics and answers with its query string.
+func echoMetrics(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"query": string(c.Request().URI().QueryString()), "group_by": c.Query("group_by")})
+}
+
+func setupApp(uc httpadapter.ReportUseCase) *fiber.App {
+	app := fiber.New()
+	h := httpadapter.NewReportHandler(uc, echoMetrics)
+	app.Get("/reports", h.ListReports)
+	app.Post("/reports", h.CreateReport)
+	app.Get("/reports/:name", h.GetReport)
+	app.Put("/reports/:name", h.UpdateReport)
+	app.Delete("/reports/:name", h.DeleteReport)
+	app.Get("/reports/:name/run", h.RunReport)
+	return app
+}
+
+func doRequest(t *testing.T, app *fiber.App, method, path, body string) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	return resp
+}
+
+func TestCreateReport(t *testing.T) {
+	var gotName string
+	var got usecase.ReportInput
+	uc := &fakeReportUseCase{
+		CreateFn: func(ctx context.Context, name string, in usecase.ReportInput) (domain.Report, error) {
+			gotName, got = name, in
+			return domain.Report{Name: name, Query: in.Query}, nil
+		},
+	}
+
+	resp := doRequest(t, setupApp(uc), http.MethodPost, "/reports", `{"name":"weekly","query":"event_name=purchase&range=168h"}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	if gotName != "weekly" || got.Query != "event_name=purchase&range=168h" {
+		t.Fatalf("unexpected input: %s %+v", gotName, got)
+	}
+}
+
+func TestReport_Errors(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantStatus int
+		wantError  string
+	}{
+		{fmt.Errorf("%w: query must have an event_name", usecase.ErrInvalidReport), http.StatusBadRequest, "invalid_report"},
+		{fmt.Errorf("%w: weekly", usecase.ErrReportExists), http.StatusConflict, "report_exists"},
+		{usecase.ErrReportNotFound, http.StatusNotFound, "report_not_found"},
+	}
+	for _, tt := range tests {
+		uc := &fakeReportUseCase{
+			CreateFn: func(ctx context.Context, name string, in usecase.ReportInput) (domain.Report, error) {
+				return domain.Report{}, tt.err
+			},
+		}
+
+		resp := doRequest(t, setupApp(uc), http.MethodPost, "/reports", `{"name":"weekly","query":"group_by=channel"}`)
+		if resp.StatusCode != tt.wantStatus {
+			t.Fatalf("%v: expected %d, got %d", tt.err, tt.wantStatus, resp.StatusCode)
+		}
+		var body httpadapter.ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("invalid json response: %v", err)
+		}
+		if body.Error != tt.wantError {
+			t.Fatalf("unexpected body: %+v", body)
+		}
+	}
+}
+
+func TestRunReport_AnswersWithMetrics(t *testing.T) {
+	var gotName string
+	var gotParams url.Values
+	uc := &fakeReportUseCase{
+		RunFn: func(ctx context.Context, name string, params url.Values) (url.Values, error) {
+			gotName, gotParams = name, params
+			return url.Values{"event_name": {"purchase"}, "group_by": {"channel"}, "from": {"1"}, "to": {"2"}, "offset": params["offset"]}, nil
+		},
+	}
+
+	resp := doRequest(t, setupApp(uc), http.MethodGet, "/reports/weekly/run?offset=100", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if gotName != "weekly" || gotParams.Get("offset") != "100" {
+		t.Fatalf("unexpected run: %s %v", gotName, gotParams)
+	}
+
+	var body map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if body["group_by"] != "channel" || !strings.Contains(body["query"], "offset=100") {
+		t.Fatalf("expected the metrics handler to see the saved query, got %v", body)
+	}
+}
+
+func TestRunReport_Invalid(t *testing.T) {
+	uc := &fakeReportUseCase{
+		RunFn: func(ctx context.Context, name string, params url.Values) (url.Values, error) {
+			return nil, fmt.Errorf("%w: from and to must be given together", usecase.ErrInvalidRun)
+		},
+	}
+
+	resp := doRequest(t, setupApp(uc), http.MethodGet, "/reports/weekly/run?from=1", "")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+}
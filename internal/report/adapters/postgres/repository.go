@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"event-metrics-service/internal/report/core/domain"
+	"event-metrics-service/internal/report/core/ports"
+)
+
+type RowScanner interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+type DB interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+type ReportRepository struct {
+	db DB
+}
+
+func NewReportRepository(db DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+var _ ports.ReportRepositoryPort = (*ReportRepository)(nil)
+
+const insertReportSQL = `
+INSERT INTO reports (tenant_id, name, description, query, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (tenant_id, name) DO NOTHING`
+
+const updateReportSQL = `
+UPDATE reports
+SET description = $3, query = $4, updated_at = $5
+WHERE tenant_id = $1 AND name = $2`
+
+const deleteReportSQL = `
+DELETE FROM reports WHERE tenant_id = $1 AND name = $2`
+
+const selectReportsSQL = `
+SELECT tenant_id, name, description, query, created_at, updated_at
+FROM reports
+WHERE tenant_id = $1`
+
+func (r *ReportRepository) CreateReport(ctx context.Context, rep domain.Report) (bool, error) {
+	res, err := r.db.ExecContext(ctx, insertReportSQL,
+		rep.TenantID, rep.Name, rep.Description, rep.Query, rep.CreatedAt, rep.UpdatedAt)
+	return affected(res, err)
+}
+
+func (r *ReportRepository) UpdateReport(ctx context.Context, rep domain.Report) (bool, error) {
+	res, err := r.db.ExecContext(ctx, updateReportSQL,
+		rep.TenantID, rep.Name, rep.Description, rep.Query, rep.UpdatedAt)
+	return affected(res, err)
+}
+
+func (r *ReportRepository) DeleteReport(ctx context.Context, tenantID, name string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, deleteReportSQL, tenantID, name)
+	return affected(res, err)
+}
+
+func (r *ReportRepository) GetReport(ctx context.Context, tenantID, name string) (domain.Report, bool, error) {
+	reps, err := r.queryReports(ctx, selectReportsSQL+" AND name = $2", tenantID, name)
+	if err != nil || len(reps) == 0 {
+		return domain.Report{}, false, err
+	}
+	return reps[0], true, nil
+}
+
+func (r *ReportRepository) ListReports(ctx context.Context, tenantID string) ([]domain.Report, error) {
+	return r.queryReports(ctx, selectReportsSQL+"\nORDER BY name", tenantID)
+}
+
+func (r *ReportRepository) queryReports(ctx context.Context, query string, args ...any) ([]domain.Report, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Report
+	for rows.Next() {
+		var rep domain.Report
+		if err := rows.Scan(&rep.TenantID, &rep.Name, &rep.Description, &rep.Query, &rep.CreatedAt, &rep.UpdatedAt); err != nil {
+			return nil, err
+		}
+		rep.CreatedAt, rep.UpdatedAt = rep.CreatedAt.UTC(), rep.UpdatedAt.UTC()
+		out = append(out, rep)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// affected reports whether a statement changed a row.
+func affected(res sql.Result, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/report/core/domain"
+)
+
+type fakeRowScanner struct {
+	rows [][]any
+	i    int
+}
+
+func (f *fakeRowScanner) Next() bool {
+	return f.i < len(f.rows)
+}
+
+func (f *fakeRowScanner) Scan(dest ...any) error {
+	row := f.rows[f.i]
+	if len(dest) != len(row) {
+		return errors.New("dest length mismatch")
+	}
+	for i := range dest {
+		switch d := dest[i].(type) {
+		case *string:
+			*d = row[i].(string)
+		case *time.Time:
+			*d = row[i].(time.Time)
+		default:
+			return errors.New("unsupported dest type")
+		}
+	}
+	f.i++
+	return nil
+}
+
+func (f *fakeRowScanner) Err() error   { return nil }
+func (f *fakeRowScanner) Close() error { return nil }
+
+type fakeResult struct{ n int64 }
+
+func (r fakeResult) LastInsertId() (int64, error) { return 0, nil }
+func (r fakeResult) RowsAffected() (int64, error) { return r.n, nil }
+
+type fakeDB struct {
+	ExecFn  func(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryFn func(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+func (f *fakeDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return f.ExecFn(ctx, query, args...)
+}
+
+func (f *fakeDB) QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error) {
+	return f.QueryFn(ctx, query, args...)
+}
+
+func TestReportRepository_CreateTaken(t *testing.T) {
+	db := &fakeDB{
+		ExecFn: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+			if !strings.Contains(query, "ON CONFLICT (tenant_id, name) DO NOTHING") {
+				t.Fatalf("unexpected query: %s", query)
+			}
+			return fakeResult{n: 0}, nil
+		},
+	}
+
+	created, err := NewReportRepository(db).CreateReport(context.Background(), domain.Report{Name: "weekly"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created {
+		t.Fatalf("expected created=false")
+	}
+}
+
+func TestReportRepository_Get(t *testing.T) {
+	created := time.Date(2025, 12, 7, 10, 0, 0, 0, time.FixedZone("TRT", 3*3600))
+	var gotQuery string
+	var gotArgs []any
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			gotQuery, gotArgs = query, args
+			return &fakeRowScanner{rows: [][]any{
+				{"acme", "weekly", "", "event_name=purchase&range=168h", created, created},
+			}}, nil
+		},
+	}
+
+	r, found, err := NewReportRepository(db).GetReport(context.Background(), "acme", "weekly")
+	if err != nil || !found {
+		t.Fatalf("expected report, got found=%v err=%v", found, err)
+	}
+	if !strings.Contains(gotQuery, "WHERE tenant_id = $1 AND name = $2") || len(gotArgs) != 2 || gotArgs[0] != "acme" {
+		t.Fatalf("unexpected query: %s %v", gotQuery, gotArgs)
+	}
+	if r.Query != "event_name=purchase&range=168h" || r.CreatedAt.Location() != time.UTC {
+		t.Fatalf("unexpected report: %+v", r)
+	}
+}
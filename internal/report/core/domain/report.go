@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// DefaultRange is the lookback of a report saved without range= or from/to.
+const DefaultRange = 24 * time.Hour
+
+// Report is a metrics query saved under a name, so it can be run without
+// pasting its query string around.
+type Report struct {
+	TenantID    string // "" without tenant isolation
+	Name        string
+	Description string
+	// Query is a GET /metrics query string. A relative range= (e.g. 168h)
+	// stands in for from/to and is resolved against the time of each run.
+	Query     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+
+	"event-metrics-service/internal/report/core/domain"
+)
+
+// ReportRepositoryPort keeps reports by tenant and name.
+type ReportRepositoryPort interface {
+	// CreateReport returns false when the tenant already has a report of
+	// that name.
+	CreateReport(ctx context.Context, r domain.Report) (created bool, err error)
+	// UpdateReport replaces the description and query of a report; found is
+	// false when there is none.
+	UpdateReport(ctx context.Context, r domain.Report) (found bool, err error)
+	DeleteReport(ctx context.Context, tenantID, name string) (found bool, err error)
+	GetReport(ctx context.Context, tenantID, name string) (r domain.Report, found bool, err error)
+	ListReports(ctx context.Context, tenantID string) ([]domain.Report, error)
+}
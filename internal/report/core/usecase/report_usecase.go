@@ -0,0 +1,253 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"event-metrics-service/internal/report/core/domain"
+	"event-metrics-service/internal/report/core/ports"
+)
+
+var (
+	ErrInvalidReport  = errors.New("invalid report")
+	ErrReportNotFound = errors.New("report not found")
+	ErrReportExists   = errors.New("report already exists")
+	ErrInvalidRun     = errors.New("invalid report run")
+)
+
+const (
+	MaxNameLength        = 100
+	MaxDescriptionLength = 1000
+	MaxQueryLength       = 8 << 10
+)
+
+// Parameters a report resolves itself rather than passing on to GET /metrics.
+const (
+	paramRange = "range"
+	paramFrom  = "from"
+	paramTo    = "to"
+)
+
+type ReportUseCase struct {
+	repo     ports.ReportRepositoryPort
+	now      func() time.Time
+	tenantOf func(ctx context.Context) string
+}
+
+type ReportOption func(*ReportUseCase)
+
+// WithTenantResolver keeps the reports of each tenant apart, so names only
+// need to be unique within a tenant.
+func WithTenantResolver(fn func(ctx context.Context) string) ReportOption {
+	return func(uc *ReportUseCase) {
+		uc.tenantOf = fn
+	}
+}
+
+// WithReportClock replaces time.Now, which relative ranges end at.
+func WithReportClock(now func() time.Time) ReportOption {
+	return func(uc *ReportUseCase) {
+		uc.now = now
+	}
+}
+
+func NewReportUseCase(repo ports.ReportRepositoryPort, opts ...ReportOption) *ReportUseCase {
+	uc := &ReportUseCase{repo: repo, now: time.Now}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+type ReportInput struct {
+	Description string
+	Query       string // a GET /metrics query string; a pasted URL is cut at "?"
+}
+
+func (uc *ReportUseCase) Create(ctx context.Context, name string, in ReportInput) (domain.Report, error) {
+	r, err := buildReport(name, in)
+	if err != nil {
+		return domain.Report{}, err
+	}
+
+	now := uc.now().UTC()
+	r.TenantID = uc.tenant(ctx)
+	r.CreatedAt, r.UpdatedAt = now, now
+	created, err := uc.repo.CreateReport(ctx, r)
+	if err != nil {
+		return domain.Report{}, err
+	}
+	if !created {
+		return domain.Report{}, fmt.Errorf("%w: %s", ErrReportExists, name)
+	}
+	return r, nil
+}
+
+// Update replaces the description and query of a report.
+func (uc *ReportUseCase) Update(ctx context.Context, name string, in ReportInput) (domain.Report, error) {
+	r, err := buildReport(name, in)
+	if err != nil {
+		return domain.Report{}, err
+	}
+
+	current, err := uc.Get(ctx, name)
+	if err != nil {
+		return domain.Report{}, err
+	}
+	r.TenantID = current.TenantID
+	r.CreatedAt = current.CreatedAt
+	r.UpdatedAt = uc.now().UTC()
+
+	found, err := uc.repo.UpdateReport(ctx, r)
+	if err != nil {
+		return domain.Report{}, err
+	}
+	if !found {
+		return domain.Report{}, ErrReportNotFound
+	}
+	return r, nil
+}
+
+func (uc *ReportUseCase) Delete(ctx context.Context, name string) error {
+	found, err := uc.repo.DeleteReport(ctx, uc.tenant(ctx), name)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrReportNotFound
+	}
+	return nil
+}
+
+func (uc *ReportUseCase) Get(ctx context.Context, name string) (domain.Report, error) {
+	r, found, err := uc.repo.GetReport(ctx, uc.tenant(ctx), name)
+	if err != nil {
+		return domain.Report{}, err
+	}
+	if !found {
+		return domain.Report{}, ErrReportNotFound
+	}
+	return r, nil
+}
+
+func (uc *ReportUseCase) List(ctx context.Context) ([]domain.Report, error) {
+	return uc.repo.ListReports(ctx, uc.tenant(ctx))
+}
+
+// Run returns the GET /metrics parameters of a report run. params override
+// the saved ones; a range=, from or to among them replaces the saved time
+// range. A relative range ends now.
+func (uc *ReportUseCase) Run(ctx context.Context, name string, params url.Values) (url.Values, error) {
+	r, err := uc.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	q, err := url.ParseQuery(r.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Has(paramRange) || params.Has(paramFrom) || params.Has(paramTo) {
+		q.Del(paramRange)
+		q.Del(paramFrom)
+		q.Del(paramTo)
+	}
+	for k, vs := range params {
+		q[k] = vs
+	}
+
+	if err := checkTimeRange(q); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRun, err)
+	}
+	if q.Has(paramFrom) {
+		return q, nil
+	}
+	d := domain.DefaultRange
+	if q.Has(paramRange) {
+		d, _ = time.ParseDuration(q.Get(paramRange))
+		q.Del(paramRange)
+	}
+	to := uc.now()
+	q.Set(paramFrom, strconv.FormatInt(to.Add(-d).Unix(), 10))
+	q.Set(paramTo, strconv.FormatInt(to.Unix(), 10))
+	return q, nil
+}
+
+func (uc *ReportUseCase) tenant(ctx context.Context) string {
+	if uc.tenantOf == nil {
+		return ""
+	}
+	return uc.tenantOf(ctx)
+}
+
+func buildReport(name string, in ReportInput) (domain.Report, error) {
+	if !validName(name) {
+		return domain.Report{}, fmt.Errorf("%w: name must be 1-%d letters, digits, '.', '-' or '_'", ErrInvalidReport, MaxNameLength)
+	}
+	description := strings.TrimSpace(in.Description)
+	if len(description) > MaxDescriptionLength {
+		return domain.Report{}, fmt.Errorf("%w: description must be at most %d characters", ErrInvalidReport, MaxDescriptionLength)
+	}
+
+	query := strings.TrimSpace(in.Query)
+	if _, after, ok := strings.Cut(query, "?"); ok {
+		query = after
+	}
+	if query == "" || len(query) > MaxQueryLength {
+		return domain.Report{}, fmt.Errorf("%w: query must be 1-%d characters", ErrInvalidReport, MaxQueryLength)
+	}
+	q, err := url.ParseQuery(query)
+	if err != nil {
+		return domain.Report{}, fmt.Errorf("%w: query: %s", ErrInvalidReport, err)
+	}
+	if q.Get("event_name") == "" {
+		return domain.Report{}, fmt.Errorf("%w: query must have an event_name", ErrInvalidReport)
+	}
+	if err := checkTimeRange(q); err != nil {
+		return domain.Report{}, fmt.Errorf("%w: query: %s", ErrInvalidReport, err)
+	}
+
+	return domain.Report{
+		Name:        name,
+		Description: description,
+		Query:       query,
+	}, nil
+}
+
+// checkTimeRange accepts a positive range= or both from and to, or neither
+// (DefaultRange). from and to themselves are checked by GET /metrics.
+func checkTimeRange(q url.Values) error {
+	if q.Has(paramRange) {
+		if q.Has(paramFrom) || q.Has(paramTo) {
+			return errors.New("range cannot be combined with from and to")
+		}
+		d, err := time.ParseDuration(q.Get(paramRange))
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid range %q, want a positive duration such as 24h", q.Get(paramRange))
+		}
+		return nil
+	}
+	if q.Has(paramFrom) != q.Has(paramTo) {
+		return errors.New("from and to must be given together")
+	}
+	return nil
+}
+
+func validName(name string) bool {
+	if name == "" || len(name) > MaxNameLength {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '.', c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,171 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/report/core/domain"
+	"event-metrics-service/internal/report/core/usecase"
+)
+
+// memReports is an in-memory ReportRepositoryPort.
+type memReports struct {
+	reports map[string]domain.Report // by tenant + "/" + name
+}
+
+func newMemReports(reports ...domain.Report) *memReports {
+	m := &memReports{reports: map[string]domain.Report{}}
+	for _, r := range reports {
+		m.reports[r.TenantID+"/"+r.Name] = r
+	}
+	return m
+}
+
+func (m *memReports) CreateReport(ctx context.Context, r domain.Report) (bool, error) {
+	if _, ok := m.reports[r.TenantID+"/"+r.Name]; ok {
+		return false, nil
+	}
+	m.reports[r.TenantID+"/"+r.Name] = r
+	return true, nil
+}
+
+func (m *memReports) UpdateReport(ctx context.Context, r domain.Report) (bool, error) {
+	if _, ok := m.reports[r.TenantID+"/"+r.Name]; !ok {
+		return false, nil
+	}
+	m.reports[r.TenantID+"/"+r.Name] = r
+	return true, nil
+}
+
+func (m *memReports) DeleteReport(ctx context.Context, tenantID, name string) (bool, error) {
+	_, ok := m.reports[tenantID+"/"+name]
+	delete(m.reports, tenantID+"/"+name)
+	return ok, nil
+}
+
+func (m *memReports) GetReport(ctx context.Context, tenantID, name string) (domain.Report, bool, error) {
+	r, ok := m.reports[tenantID+"/"+name]
+	return r, ok, nil
+}
+
+func (m *memReports) ListReports(ctx context.Context, tenantID string) ([]domain.Report, error) {
+	var out []domain.Report
+	for _, r := range m.reports {
+		if r.TenantID == tenantID {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+var runAt = time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+
+func TestReport_CreateCutsPastedURL(t *testing.T) {
+	repo := newMemReports()
+	uc := usecase.NewReportUseCase(repo, usecase.WithTenantResolver(func(ctx context.Context) string { return "acme" }))
+
+	r, err := uc.Create(context.Background(), "weekly-purchases", usecase.ReportInput{
+		Query: " https://metrics.example.com/metrics?event_name=purchase&group_by=channel&range=168h ",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if r.Query != "event_name=purchase&group_by=channel&range=168h" || r.TenantID != "acme" {
+		t.Fatalf("unexpected report: %+v", r)
+	}
+
+	_, err = uc.Create(context.Background(), "weekly-purchases", usecase.ReportInput{Query: "event_name=signup"})
+	if !errors.Is(err, usecase.ErrReportExists) {
+		t.Fatalf("expected ErrReportExists, got %v", err)
+	}
+}
+
+func TestReport_CreateInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"has space", "event_name=purchase"},
+		{"weekly", ""},
+		{"weekly", "group_by=channel"},
+		{"weekly", "event_name=purchase&range=1w"},
+		{"weekly", "event_name=purchase&range=-1h"},
+		{"weekly", "event_name=purchase&range=24h&from=1"},
+		{"weekly", "event_name=purchase&from=1700000000"},
+		{"weekly", "event_name=purchase&metadata.plan=%zz"},
+	}
+	for _, tt := range tests {
+		uc := usecase.NewReportUseCase(newMemReports())
+		_, err := uc.Create(context.Background(), tt.name, usecase.ReportInput{Query: tt.query})
+		if !errors.Is(err, usecase.ErrInvalidReport) {
+			t.Fatalf("%s %q: expected ErrInvalidReport, got %v", tt.name, tt.query, err)
+		}
+	}
+}
+
+func TestReport_TenantsAreApart(t *testing.T) {
+	repo := newMemReports(domain.Report{TenantID: "acme", Name: "weekly", Query: "event_name=purchase"})
+	uc := usecase.NewReportUseCase(repo, usecase.WithTenantResolver(func(ctx context.Context) string { return "globex" }))
+
+	if _, err := uc.Get(context.Background(), "weekly"); !errors.Is(err, usecase.ErrReportNotFound) {
+		t.Fatalf("expected ErrReportNotFound, got %v", err)
+	}
+	if err := uc.Delete(context.Background(), "weekly"); !errors.Is(err, usecase.ErrReportNotFound) {
+		t.Fatalf("expected ErrReportNotFound, got %v", err)
+	}
+	if _, err := uc.Update(context.Background(), "weekly", usecase.ReportInput{Query: "event_name=signup"}); !errors.Is(err, usecase.ErrReportNotFound) {
+		t.Fatalf("expected ErrReportNotFound, got %v", err)
+	}
+}
+
+func TestReport_Run(t *testing.T) {
+	repo := newMemReports(
+		domain.Report{Name: "weekly", Query: "event_name=purchase&group_by=channel&range=168h"},
+		domain.Report{Name: "daily", Query: "event_name=signup"},
+		domain.Report{Name: "q3", Query: "event_name=purchase&from=1751328000&to=1759276800"},
+	)
+	uc := usecase.NewReportUseCase(repo, usecase.WithReportClock(func() time.Time { return runAt }))
+	now := runAt.Unix()
+
+	tests := []struct {
+		report   string
+		params   string
+		from, to int64
+		groupBy  string
+	}{
+		{"weekly", "", now - 7*24*3600, now, "channel"},
+		{"daily", "", now - 24*3600, now, ""},
+		{"q3", "", 1751328000, 1759276800, ""},
+		{"q3", "range=1h", now - 3600, now, ""},
+		{"weekly", "from=1700000000&to=1700003600&group_by=time", 1700000000, 1700003600, "time"},
+	}
+	for _, tt := range tests {
+		params, _ := url.ParseQuery(tt.params)
+		q, err := uc.Run(context.Background(), tt.report, params)
+		if err != nil {
+			t.Fatalf("%s?%s: unexpected error: %v", tt.report, tt.params, err)
+		}
+		if q.Has("range") || q.Get("from") != strconv.FormatInt(tt.from, 10) ||
+			q.Get("to") != strconv.FormatInt(tt.to, 10) || q.Get("group_by") != tt.groupBy {
+			t.Fatalf("%s?%s: unexpected query %v", tt.report, tt.params, q)
+		}
+	}
+}
+
+func TestReport_RunInvalid(t *testing.T) {
+	repo := newMemReports(domain.Report{Name: "weekly", Query: "event_name=purchase&range=168h"})
+	uc := usecase.NewReportUseCase(repo)
+
+	_, err := uc.Run(context.Background(), "weekly", url.Values{"from": {"1700000000"}})
+	if !errors.Is(err, usecase.ErrInvalidRun) {
+		t.Fatalf("expected ErrInvalidRun, got %v", err)
+	}
+	_, err = uc.Run(context.Background(), "monthly", nil)
+	if !errors.Is(err, usecase.ErrReportNotFound) {
+		t.Fatalf("expected ErrReportNotFound, got %v", err)
+	}
+}
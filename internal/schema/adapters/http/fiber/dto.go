@@ -0,0 +1,25 @@
+package fiber
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type PutSchemaRequest struct {
+	Schema json.RawMessage `json:"schema" swaggertype:"object"`
+}
+
+type EventSchemaResponse struct {
+	EventName string          `json:"event_name" example:"purchase"`
+	Schema    json.RawMessage `json:"schema" swaggertype:"object"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+type EventSchemaListResponse struct {
+	Schemas []EventSchemaResponse `json:"schemas"`
+}
+
+type ErrorResponse struct {
+	Error   string `json:"error" example:"invalid_schema"`
+	Message string `json:"message" example:"invalid schema: jsonschema validation failed with 'https://json-schema.org/draft/2020-12/schema#'"`
+}
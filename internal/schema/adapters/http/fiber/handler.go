@@ -0,0 +1,123 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"event-metrics-service/internal/schema/core/domain"
+	"event-metrics-service/internal/schema/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SchemaRegistryUseCase interface {
+	Put(ctx context.Context, eventName string, raw []byte) (domain.EventSchema, error)
+	Delete(ctx context.Context, eventName string) error
+	List(ctx context.Context) ([]domain.EventSchema, error)
+}
+
+type SchemaHandler struct {
+	uc SchemaRegistryUseCase
+}
+
+func NewSchemaHandler(uc SchemaRegistryUseCase) *SchemaHandler {
+	return &SchemaHandler{uc: uc}
+}
+
+// PutSchema godoc
+// @Summary Register the metadata JSON Schema for an event name
+// @Description Events with this event_name whose metadata does not match are rejected with 400 schema_violation.
+// @Description Supported keywords: type, enum, const, properties, required, additionalProperties, items,
+// @Description minItems, maxItems, minLength, maxLength, pattern, minimum, maximum, exclusiveMinimum, exclusiveMaximum.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param event_name path string true "Event name"
+// @Param request body PutSchemaRequest true "Schema"
+// @Success 200 {object} EventSchemaResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/schemas/{event_name} [put]
+func (h *SchemaHandler) PutSchema(c *fiber.Ctx) error {
+	var req PutSchemaRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid_json",
+		})
+	}
+
+	s, err := h.uc.Put(c.UserContext(), c.Params("event_name"), req.Schema)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidSchema) {
+			return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+				Error:   "invalid_schema",
+				Message: err.Error(),
+			})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error: "internal_server_error",
+		})
+	}
+
+	return c.JSON(toEventSchemaResponse(s))
+}
+
+// ListSchemas godoc
+// @Summary List registered metadata schemas
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} EventSchemaListResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/schemas [get]
+func (h *SchemaHandler) ListSchemas(c *fiber.Ctx) error {
+	schemas, err := h.uc.List(c.UserContext())
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error: "internal_server_error",
+		})
+	}
+
+	resp := EventSchemaListResponse{Schemas: make([]EventSchemaResponse, 0, len(schemas))}
+	for _, s := range schemas {
+		resp.Schemas = append(resp.Schemas, toEventSchemaResponse(s))
+	}
+
+	return c.JSON(resp)
+}
+
+// DeleteSchema godoc
+// @Summary Stop validating metadata for an event name
+// @Tags Admin
+// @Param X-Admin-Token header string true "Admin token"
+// @Param event_name path string true "Event name"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/schemas/{event_name} [delete]
+func (h *SchemaHandler) DeleteSchema(c *fiber.Ctx) error {
+	err := h.uc.Delete(c.UserContext(), c.Params("event_name"))
+	if err != nil {
+		if errors.Is(err, usecase.ErrSchemaNotFound) {
+			return c.Status(http.StatusNotFound).JSON(ErrorResponse{
+				Error:   "schema_not_found",
+				Message: err.Error(),
+			})
+		}
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error: "internal_server_error",
+		})
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+func toEventSchemaResponse(s domain.EventSchema) EventSchemaResponse {
+	return EventSchemaResponse{
+		EventName: s.EventName,
+		Schema:    s.Schema,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
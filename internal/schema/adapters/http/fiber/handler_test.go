@@ -0,0 +1,147 @@
+package fiber_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpadapter "event-metrics-service/internal/schema/adapters/http/fiber"
+	"event-metrics-service/internal/schema/core/domain"
+	"event-metrics-service/internal/schema/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeSchemaRegistryUseCase struct {
+	PutFn    func(ctx context.Context, eventName string, raw []byte) (domain.EventSchema, error)
+	DeleteFn func(ctx context.Context, eventName string) error
+	ListFn   func(ctx context.Context) ([]domain.EventSchema, error)
+}
+
+func (f *fakeSchemaRegistryUseCase) Put(ctx context.Context, eventName string, raw []byte) (domain.EventSchema, error) {
+	return f.PutFn(ctx, eventName, raw)
+}
+
+func (f *fakeSchemaRegistryUseCase) Delete(ctx context.Context, eventName string) error {
+	return f.DeleteFn(ctx, eventName)
+}
+
+func (f *fakeSchemaRegistryUseCase) List(ctx context.Context) ([]domain.EventSchema, error) {
+	return f.ListFn(ctx)
+}
+
+func setupApp(uc httpadapter.SchemaRegistryUseCase) *fiber.App {
+	app := fiber.New()
+	h := httpadapter.NewSchemaHandler(uc)
+	app.Get("/admin/schemas", h.ListSchemas)
+	app.Put("/admin/schemas/:event_name", h.PutSchema)
+	app.Delete("/admin/schemas/:event_name", h.DeleteSchema)
+	return app
+}
+
+func doRequest(t *testing.T, app *fiber.App, method, path, body string) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	return resp
+}
+
+func TestPutSchema_Success(t *testing.T) {
+	now := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	uc := &fakeSchemaRegistryUseCase{
+		PutFn: func(ctx context.Context, eventName string, raw []byte) (domain.EventSchema, error) {
+			if eventName != "purchase" || string(raw) != `{"required":["order_id"]}` {
+				t.Fatalf("unexpected input: %s %s", eventName, raw)
+			}
+			return domain.EventSchema{EventName: eventName, Schema: raw, UpdatedAt: now}, nil
+		},
+	}
+
+	resp := doRequest(t, setupApp(uc), http.MethodPut, "/admin/schemas/purchase", `{"schema":{"required":["order_id"]}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body httpadapter.EventSchemaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if body.EventName != "purchase" || string(body.Schema) != `{"required":["order_id"]}` {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+}
+
+func TestPutSchema_Invalid(t *testing.T) {
+	uc := &fakeSchemaRegistryUseCase{
+		PutFn: func(ctx context.Context, eventName string, raw []byte) (domain.EventSchema, error) {
+			return domain.EventSchema{}, fmt.Errorf("%w: unsupported keyword #/oneOf", usecase.ErrInvalidSchema)
+		},
+	}
+
+	resp := doRequest(t, setupApp(uc), http.MethodPut, "/admin/schemas/purchase", `{"schema":{"oneOf":[]}}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+
+	var body httpadapter.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if body.Error != "invalid_schema" || !strings.Contains(body.Message, "oneOf") {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+}
+
+func TestListSchemas(t *testing.T) {
+	uc := &fakeSchemaRegistryUseCase{
+		ListFn: func(ctx context.Context) ([]domain.EventSchema, error) {
+			return []domain.EventSchema{{EventName: "purchase", Schema: []byte(`{}`)}}, nil
+		},
+	}
+
+	resp := doRequest(t, setupApp(uc), http.MethodGet, "/admin/schemas", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body httpadapter.EventSchemaListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if len(body.Schemas) != 1 || body.Schemas[0].EventName != "purchase" {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+}
+
+func TestDeleteSchema(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantStatus int
+	}{
+		{nil, http.StatusNoContent},
+		{usecase.ErrSchemaNotFound, http.StatusNotFound},
+		{errors.New("db failure"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		uc := &fakeSchemaRegistryUseCase{
+			DeleteFn: func(ctx context.Context, eventName string) error {
+				return tt.err
+			},
+		}
+		resp := doRequest(t, setupApp(uc), http.MethodDelete, "/admin/schemas/purchase", "")
+		if resp.StatusCode != tt.wantStatus {
+			t.Fatalf("%v: expected %d, got %d", tt.err, tt.wantStatus, resp.StatusCode)
+		}
+	}
+}
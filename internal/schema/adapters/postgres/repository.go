@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"event-metrics-service/internal/schema/core/domain"
+	"event-metrics-service/internal/schema/core/ports"
+)
+
+type RowScanner interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+type DB interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+type SchemaRepository struct {
+	db DB
+}
+
+func NewSchemaRepository(db DB) *SchemaRepository {
+	return &SchemaRepository{db: db}
+}
+
+var _ ports.SchemaRepositoryPort = (*SchemaRepository)(nil)
+
+const upsertSchemaSQL = `
+INSERT INTO event_schemas (event_name, schema, updated_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (event_name) DO UPDATE
+SET schema = EXCLUDED.schema, updated_at = EXCLUDED.updated_at`
+
+const deleteSchemaSQL = `
+DELETE FROM event_schemas WHERE event_name = $1`
+
+const listSchemasSQL = `
+SELECT event_name, schema, updated_at
+FROM event_schemas
+ORDER BY event_name`
+
+func (r *SchemaRepository) UpsertSchema(ctx context.Context, s domain.EventSchema) error {
+	_, err := r.db.ExecContext(ctx, upsertSchemaSQL, s.EventName, s.Schema, s.UpdatedAt)
+	return err
+}
+
+func (r *SchemaRepository) DeleteSchema(ctx context.Context, eventName string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, deleteSchemaSQL, eventName)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *SchemaRepository) ListSchemas(ctx context.Context) ([]domain.EventSchema, error) {
+	rows, err := r.db.QueryContext(ctx, listSchemasSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.EventSchema
+	for rows.Next() {
+		var s domain.EventSchema
+		if err := rows.Scan(&s.EventName, &s.Schema, &s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		s.UpdatedAt = s.UpdatedAt.UTC()
+		out = append(out, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/schema/core/domain"
+)
+
+type fakeRowScanner struct {
+	rows [][]any
+	i    int
+}
+
+func (f *fakeRowScanner) Next() bool {
+	return f.i < len(f.rows)
+}
+
+func (f *fakeRowScanner) Scan(dest ...any) error {
+	row := f.rows[f.i]
+	if len(dest) != len(row) {
+		return errors.New("dest length mismatch")
+	}
+	for i := range dest {
+		switch d := dest[i].(type) {
+		case *[]byte:
+			*d = row[i].([]byte)
+		case *string:
+			*d = row[i].(string)
+		case *time.Time:
+			*d = row[i].(time.Time)
+		default:
+			return errors.New("unsupported dest type")
+		}
+	}
+	f.i++
+	return nil
+}
+
+func (f *fakeRowScanner) Err() error   { return nil }
+func (f *fakeRowScanner) Close() error { return nil }
+
+type fakeResult struct{ n int64 }
+
+func (r fakeResult) LastInsertId() (int64, error) { return 0, nil }
+func (r fakeResult) RowsAffected() (int64, error) { return r.n, nil }
+
+type fakeDB struct {
+	ExecFn  func(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryFn func(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+func (f *fakeDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return f.ExecFn(ctx, query, args...)
+}
+
+func (f *fakeDB) QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error) {
+	return f.QueryFn(ctx, query, args...)
+}
+
+func TestSchemaRepository_Upsert(t *testing.T) {
+	var gotArgs []any
+	db := &fakeDB{
+		ExecFn: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+			if !strings.Contains(query, "ON CONFLICT (event_name)") {
+				t.Fatalf("expected upsert query, got %s", query)
+			}
+			gotArgs = args
+			return fakeResult{n: 1}, nil
+		},
+	}
+
+	now := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	err := NewSchemaRepository(db).UpsertSchema(context.Background(), domain.EventSchema{
+		EventName: "purchase",
+		Schema:    []byte(`{"type":"object"}`),
+		UpdatedAt: now,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(gotArgs) != 3 || gotArgs[0] != "purchase" || string(gotArgs[1].([]byte)) != `{"type":"object"}` {
+		t.Fatalf("unexpected args: %v", gotArgs)
+	}
+}
+
+func TestSchemaRepository_DeleteMissing(t *testing.T) {
+	db := &fakeDB{
+		ExecFn: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+			return fakeResult{n: 0}, nil
+		},
+	}
+
+	deleted, err := NewSchemaRepository(db).DeleteSchema(context.Background(), "purchase")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if deleted {
+		t.Fatalf("expected deleted=false")
+	}
+}
+
+func TestSchemaRepository_List(t *testing.T) {
+	updated := time.Date(2025, 12, 7, 10, 0, 0, 0, time.FixedZone("TRT", 3*3600))
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return &fakeRowScanner{rows: [][]any{
+				{"purchase", []byte(`{"type":"object"}`), updated},
+			}}, nil
+		},
+	}
+
+	got, err := NewSchemaRepository(db).ListSchemas(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0].EventName != "purchase" || got[0].UpdatedAt.Location() != time.UTC {
+		t.Fatalf("unexpected schemas: %+v", got)
+	}
+}
+
+func TestSchemaRepository_ListError(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return nil, errors.New("db failure")
+		},
+	}
+
+	if _, err := NewSchemaRepository(db).ListSchemas(context.Background()); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+var ErrInvalidSchema = errors.New("invalid schema")
+
+// schemaURL is the location the registered document is compiled under.
+// Relative $refs resolve against it, so they can only point inside the
+// document itself.
+const schemaURL = "urn:event-metrics:schema"
+
+var printer = message.NewPrinter(language.English)
+
+// Schema is a compiled JSON Schema. Compilation and validation are done by
+// santhosh-tekuri/jsonschema, so every keyword of the declared draft
+// (2020-12 when $schema is absent) is enforced, including format. Remote
+// and file $refs are rejected at compile time.
+type Schema struct {
+	compiled *jsonschema.Schema
+}
+
+// Compile parses a JSON Schema document.
+func Compile(raw []byte) (*Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+
+	c := jsonschema.NewCompiler()
+	c.UseLoader(jsonschema.SchemeURLLoader{})
+	c.AssertFormat()
+	if err := c.AddResource(schemaURL, doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	compiled, err := c.Compile(schemaURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	return &Schema{compiled: compiled}, nil
+}
+
+// Validate checks v against the schema and returns every violation, sorted
+// by path. root prefixes each path (e.g. "metadata").
+func (s *Schema) Validate(v any, root string) []Violation {
+	err := s.compiled.Validate(v)
+	if err == nil {
+		return nil
+	}
+
+	var ve *jsonschema.ValidationError
+	if !errors.As(err, &ve) {
+		return []Violation{{Path: root, Message: err.Error()}}
+	}
+
+	var out []Violation
+	collect(ve, v, root, &out)
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Path != out[j].Path {
+			return out[i].Path < out[j].Path
+		}
+		return out[i].Message < out[j].Message
+	})
+	return out
+}
+
+// collect flattens the error tree into its leaves. Missing and unexpected
+// properties are reported on the property itself rather than its parent.
+func collect(ve *jsonschema.ValidationError, doc any, root string, out *[]Violation) {
+	if len(ve.Causes) > 0 {
+		for _, cause := range ve.Causes {
+			collect(cause, doc, root, out)
+		}
+		return
+	}
+
+	path := instancePath(doc, root, ve.InstanceLocation)
+	switch k := ve.ErrorKind.(type) {
+	case *kind.Required:
+		for _, name := range k.Missing {
+			*out = append(*out, Violation{Path: childPath(path, name), Message: "is required"})
+		}
+	case *kind.AdditionalProperties:
+		for _, name := range k.Properties {
+			*out = append(*out, Violation{Path: childPath(path, name), Message: "is not allowed"})
+		}
+	default:
+		*out = append(*out, Violation{Path: path, Message: ve.ErrorKind.LocalizedString(printer)})
+	}
+}
+
+// instancePath renders a JSON pointer as "metadata.items[0].sku", walking
+// doc to tell array indices from object keys that look like numbers.
+func instancePath(doc any, root string, loc []string) string {
+	path := root
+	cur := doc
+	for _, tok := range loc {
+		switch node := cur.(type) {
+		case []any:
+			if i, err := strconv.Atoi(tok); err == nil && i >= 0 && i < len(node) {
+				path += "[" + tok + "]"
+				cur = node[i]
+				continue
+			}
+		case map[string]any:
+			cur = node[tok]
+		default:
+			cur = nil
+		}
+		path = childPath(path, tok)
+	}
+	return path
+}
+
+func childPath(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const orderSchema = `{
+  "type": "object",
+  "required": ["order_id", "amount"],
+  "additionalProperties": false,
+  "properties": {
+    "order_id": { "type": "string", "pattern": "^ord_[0-9]+$" },
+    "amount":   { "type": "number", "exclusiveMinimum": 0 },
+    "currency": { "enum": ["TRY", "EUR", "USD"] },
+    "items": {
+      "type": "array",
+      "maxItems": 2,
+      "items": {
+        "type": "object",
+        "required": ["sku"],
+        "properties": { "sku": { "type": "string", "minLength": 3 }, "qty": { "type": "integer", "minimum": 1 } }
+      }
+    }
+  }
+}`
+
+func TestSchema_Validate(t *testing.T) {
+	s, err := Compile([]byte(orderSchema))
+	if err != nil {
+		t.Fatalf("unexpected compile error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		doc  map[string]any
+		want []Violation
+	}{
+		{
+			name: "valid",
+			doc: map[string]any{
+				"order_id": "ord_1",
+				"amount":   json.Number("12.5"),
+				"currency": "EUR",
+				"items":    []any{map[string]any{"sku": "abc", "qty": float64(2)}},
+			},
+		},
+		{
+			name: "missing required and unknown key",
+			doc:  map[string]any{"order_id": "ord_1", "coupon": "X"},
+			want: []Violation{
+				{Path: "metadata.amount", Message: "is required"},
+				{Path: "metadata.coupon", Message: "is not allowed"},
+			},
+		},
+		{
+			name: "nested violations",
+			doc: map[string]any{
+				"order_id": "order-1",
+				"amount":   float64(0),
+				"currency": "GBP",
+				"items": []any{
+					map[string]any{"sku": "ab", "qty": json.Number("1.5")},
+					"not-an-object",
+				},
+			},
+			want: []Violation{
+				{Path: "metadata.amount", Message: "exclusiveMinimum: got 0, want 0"},
+				{Path: "metadata.currency", Message: "value must be one of 'TRY', 'EUR', 'USD'"},
+				{Path: "metadata.items[0].qty", Message: "got number, want integer"},
+				{Path: "metadata.items[0].sku", Message: "minLength: got 2, want 3"},
+				{Path: "metadata.items[1]", Message: "got string, want object"},
+				{Path: "metadata.order_id", Message: "'order-1' does not match pattern '^ord_[0-9]+$'"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.Validate(tt.doc, "metadata")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSchema_EnumComparesNumbersByValue(t *testing.T) {
+	s, err := Compile([]byte(`{"properties": {"tier": {"enum": [1, 2]}}}`))
+	if err != nil {
+		t.Fatalf("unexpected compile error: %v", err)
+	}
+
+	for _, v := range []any{float64(2), json.Number("2"), 2} {
+		if got := s.Validate(map[string]any{"tier": v}, "metadata"); len(got) != 0 {
+			t.Fatalf("%T(%v): unexpected violations %+v", v, v, got)
+		}
+	}
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	tests := []string{
+		`not json`,
+		`[]`,
+		`{"type": "decimal"}`,
+		`{"minLength": -1}`,
+		`{"pattern": "("}`,
+		`{"enum": 1}`,
+		`{"$ref": "#/$defs/missing"}`,
+		`{"$ref": "file:///etc/passwd"}`,
+		`{"$ref": "https://example.com/schema.json"}`,
+		`{"properties": {"a": {"oneOf": []}}}`,
+		`{} {}`,
+	}
+
+	for _, raw := range tests {
+		if _, err := Compile([]byte(raw)); !errors.Is(err, ErrInvalidSchema) {
+			t.Fatalf("%s: expected ErrInvalidSchema, got %v", raw, err)
+		}
+	}
+}
+
+func TestCompile_AnnotationsAllowed(t *testing.T) {
+	raw := `{"$schema": "https://json-schema.org/draft/2020-12/schema", "title": "Purchase", "description": "d",
+	         "properties": {"email": {"type": "string", "format": "email"}}}`
+	if _, err := Compile([]byte(raw)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSchema_CompositionAndFormat(t *testing.T) {
+	raw := `{
+	  "$defs": {"email": {"type": "string", "format": "email"}},
+	  "properties": {
+	    "contact": {"$ref": "#/$defs/email"},
+	    "channel": {"oneOf": [{"const": "web"}, {"const": "ios"}]}
+	  }
+	}`
+	s, err := Compile([]byte(raw))
+	if err != nil {
+		t.Fatalf("unexpected compile error: %v", err)
+	}
+
+	if got := s.Validate(map[string]any{"contact": "a@b.co", "channel": "web"}, "metadata"); len(got) != 0 {
+		t.Fatalf("unexpected violations %+v", got)
+	}
+
+	got := s.Validate(map[string]any{"contact": "nope", "channel": "tv"}, "metadata")
+	paths := make([]string, 0, len(got))
+	for _, v := range got {
+		paths = append(paths, v.Path)
+	}
+	want := []string{"metadata.channel", "metadata.channel", "metadata.contact"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("expected paths %v, got %+v", want, got)
+	}
+}
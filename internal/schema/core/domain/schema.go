@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// EventSchema is the metadata contract registered for one event_name.
+type EventSchema struct {
+	EventName string
+	Schema    []byte // JSON Schema document as registered
+	UpdatedAt time.Time
+}
+
+// Violation is a single place where a document does not match its schema.
+type Violation struct {
+	Path    string // e.g. "metadata.items[0].sku"
+	Message string
+}
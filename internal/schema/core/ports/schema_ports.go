@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"event-metrics-service/internal/schema/core/domain"
+)
+
+type SchemaRepositoryPort interface {
+	// UpsertSchema registers or replaces the schema for s.EventName.
+	UpsertSchema(ctx context.Context, s domain.EventSchema) error
+	// DeleteSchema returns false when no schema was registered.
+	DeleteSchema(ctx context.Context, eventName string) (bool, error)
+	ListSchemas(ctx context.Context) ([]domain.EventSchema, error)
+}
@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"event-metrics-service/internal/schema/core/domain"
+	"event-metrics-service/internal/schema/core/ports"
+)
+
+var (
+	ErrInvalidSchema  = domain.ErrInvalidSchema
+	ErrSchemaNotFound = errors.New("schema not found")
+)
+
+const (
+	// MaxSchemaBytes caps the size of a single registered schema document.
+	MaxSchemaBytes = 64 * 1024
+	// MaxEventNameLength matches the events.event_name column.
+	MaxEventNameLength = 100
+)
+
+// SchemaRegistryUseCase manages metadata schemas per event_name and keeps a
+// compiled copy in memory so ingest never has to hit the database.
+type SchemaRegistryUseCase struct {
+	repo ports.SchemaRepositoryPort
+	now  func() time.Time
+
+	mu       sync.RWMutex
+	compiled map[string]*domain.Schema
+}
+
+func NewSchemaRegistryUseCase(repo ports.SchemaRepositoryPort) *SchemaRegistryUseCase {
+	return &SchemaRegistryUseCase{
+		repo:     repo,
+		now:      time.Now,
+		compiled: map[string]*domain.Schema{},
+	}
+}
+
+// Put validates and stores the schema for eventName. It is enforced on this
+// instance immediately and on other instances after their next Refresh.
+func (uc *SchemaRegistryUseCase) Put(ctx context.Context, eventName string, raw []byte) (domain.EventSchema, error) {
+	if eventName == "" || len(eventName) > MaxEventNameLength {
+		return domain.EventSchema{}, fmt.Errorf("%w: event_name must be 1-%d characters", ErrInvalidSchema, MaxEventNameLength)
+	}
+	if len(raw) > MaxSchemaBytes {
+		return domain.EventSchema{}, fmt.Errorf("%w: schema exceeds %d bytes", ErrInvalidSchema, MaxSchemaBytes)
+	}
+
+	compiled, err := domain.Compile(raw)
+	if err != nil {
+		return domain.EventSchema{}, err
+	}
+
+	s := domain.EventSchema{
+		EventName: eventName,
+		Schema:    raw,
+		UpdatedAt: uc.now().UTC(),
+	}
+	if err := uc.repo.UpsertSchema(ctx, s); err != nil {
+		return domain.EventSchema{}, err
+	}
+
+	uc.mu.Lock()
+	uc.compiled[eventName] = compiled
+	uc.mu.Unlock()
+
+	return s, nil
+}
+
+func (uc *SchemaRegistryUseCase) Delete(ctx context.Context, eventName string) error {
+	deleted, err := uc.repo.DeleteSchema(ctx, eventName)
+	if err != nil {
+		return err
+	}
+
+	uc.mu.Lock()
+	delete(uc.compiled, eventName)
+	uc.mu.Unlock()
+
+	if !deleted {
+		return ErrSchemaNotFound
+	}
+	return nil
+}
+
+func (uc *SchemaRegistryUseCase) List(ctx context.Context) ([]domain.EventSchema, error) {
+	return uc.repo.ListSchemas(ctx)
+}
+
+// Refresh reloads every schema from the repository. Stored schemas that no
+// longer compile are skipped and reported, the rest are still applied.
+func (uc *SchemaRegistryUseCase) Refresh(ctx context.Context) error {
+	schemas, err := uc.repo.ListSchemas(ctx)
+	if err != nil {
+		return err
+	}
+
+	compiled := make(map[string]*domain.Schema, len(schemas))
+	var errs []error
+	for _, s := range schemas {
+		c, err := domain.Compile(s.Schema)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("schema for %s: %w", s.EventName, err))
+			continue
+		}
+		compiled[s.EventName] = c
+	}
+
+	uc.mu.Lock()
+	uc.compiled = compiled
+	uc.mu.Unlock()
+
+	return errors.Join(errs...)
+}
+
+// Run calls Refresh on every tick until ctx is cancelled.
+func (uc *SchemaRegistryUseCase) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := uc.Refresh(ctx); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// Validate checks metadata against the schema registered for eventName.
+// Events without a schema always pass.
+func (uc *SchemaRegistryUseCase) Validate(eventName string, metadata map[string]any) []domain.Violation {
+	uc.mu.RLock()
+	s := uc.compiled[eventName]
+	uc.mu.RUnlock()
+
+	if s == nil {
+		return nil
+	}
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	return s.Validate(metadata, "metadata")
+}
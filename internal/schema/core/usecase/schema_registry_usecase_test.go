@@ -0,0 +1,135 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"event-metrics-service/internal/schema/core/domain"
+	"event-metrics-service/internal/schema/core/usecase"
+)
+
+type fakeSchemaRepo struct {
+	UpsertFn func(ctx context.Context, s domain.EventSchema) error
+	DeleteFn func(ctx context.Context, eventName string) (bool, error)
+	ListFn   func(ctx context.Context) ([]domain.EventSchema, error)
+}
+
+func (f *fakeSchemaRepo) UpsertSchema(ctx context.Context, s domain.EventSchema) error {
+	if f.UpsertFn != nil {
+		return f.UpsertFn(ctx, s)
+	}
+	return nil
+}
+
+func (f *fakeSchemaRepo) DeleteSchema(ctx context.Context, eventName string) (bool, error) {
+	if f.DeleteFn != nil {
+		return f.DeleteFn(ctx, eventName)
+	}
+	return true, nil
+}
+
+func (f *fakeSchemaRepo) ListSchemas(ctx context.Context) ([]domain.EventSchema, error) {
+	if f.ListFn != nil {
+		return f.ListFn(ctx)
+	}
+	return nil, nil
+}
+
+const purchaseSchema = `{"type": "object", "required": ["order_id"]}`
+
+// ------------------------------------------------------------
+// PUT / DELETE
+// ------------------------------------------------------------
+
+func TestSchemaRegistry_PutEnforcesImmediately(t *testing.T) {
+	var stored domain.EventSchema
+	repo := &fakeSchemaRepo{
+		UpsertFn: func(ctx context.Context, s domain.EventSchema) error {
+			stored = s
+			return nil
+		},
+	}
+	uc := usecase.NewSchemaRegistryUseCase(repo)
+
+	if got := uc.Validate("purchase", map[string]any{}); len(got) != 0 {
+		t.Fatalf("expected no violations without a schema, got %+v", got)
+	}
+
+	if _, err := uc.Put(context.Background(), "purchase", []byte(purchaseSchema)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.EventName != "purchase" || string(stored.Schema) != purchaseSchema || stored.UpdatedAt.IsZero() {
+		t.Fatalf("unexpected stored schema: %+v", stored)
+	}
+
+	got := uc.Validate("purchase", nil)
+	if len(got) != 1 || got[0].Path != "metadata.order_id" {
+		t.Fatalf("expected missing order_id, got %+v", got)
+	}
+}
+
+func TestSchemaRegistry_PutRejectsInvalidSchema(t *testing.T) {
+	repo := &fakeSchemaRepo{
+		UpsertFn: func(ctx context.Context, s domain.EventSchema) error {
+			t.Fatalf("invalid schema must not be stored")
+			return nil
+		},
+	}
+	uc := usecase.NewSchemaRegistryUseCase(repo)
+
+	for _, tc := range []struct{ eventName, raw string }{
+		{"purchase", `{"type": 1}`},
+		{"", purchaseSchema},
+	} {
+		if _, err := uc.Put(context.Background(), tc.eventName, []byte(tc.raw)); !errors.Is(err, usecase.ErrInvalidSchema) {
+			t.Fatalf("%q: expected ErrInvalidSchema, got %v", tc.raw, err)
+		}
+	}
+}
+
+func TestSchemaRegistry_DeleteNotFound(t *testing.T) {
+	repo := &fakeSchemaRepo{
+		DeleteFn: func(ctx context.Context, eventName string) (bool, error) {
+			return false, nil
+		},
+	}
+	uc := usecase.NewSchemaRegistryUseCase(repo)
+
+	if err := uc.Delete(context.Background(), "purchase"); !errors.Is(err, usecase.ErrSchemaNotFound) {
+		t.Fatalf("expected ErrSchemaNotFound, got %v", err)
+	}
+}
+
+// ------------------------------------------------------------
+// REFRESH
+// ------------------------------------------------------------
+
+func TestSchemaRegistry_RefreshReplacesCache(t *testing.T) {
+	schemas := []domain.EventSchema{
+		{EventName: "purchase", Schema: []byte(purchaseSchema)},
+		{EventName: "broken", Schema: []byte(`{"type": "decimal"}`)},
+	}
+	repo := &fakeSchemaRepo{
+		ListFn: func(ctx context.Context) ([]domain.EventSchema, error) {
+			return schemas, nil
+		},
+	}
+	uc := usecase.NewSchemaRegistryUseCase(repo)
+
+	if err := uc.Refresh(context.Background()); !errors.Is(err, usecase.ErrInvalidSchema) {
+		t.Fatalf("expected the broken schema to be reported, got %v", err)
+	}
+	if got := uc.Validate("purchase", map[string]any{}); len(got) != 1 {
+		t.Fatalf("expected valid schemas to be applied, got %+v", got)
+	}
+
+	// Schemas deleted on another instance disappear on the next refresh.
+	schemas = nil
+	if err := uc.Refresh(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := uc.Validate("purchase", map[string]any{}); len(got) != 0 {
+		t.Fatalf("expected no schema after refresh, got %+v", got)
+	}
+}
@@ -0,0 +1,17 @@
+package fiber
+
+type SessionStatsResponse struct {
+	From                  int64   `json:"from" example:"1765065600"`
+	To                    int64   `json:"to" example:"1765152000"`
+	GapSeconds            int64   `json:"gap_seconds" example:"1800"`
+	Sessions              int64   `json:"sessions" example:"5120"`
+	Users                 int64   `json:"users" example:"2048"`
+	AvgDurationSeconds    float64 `json:"avg_duration_seconds" example:"312.5"`
+	MedianDurationSeconds float64 `json:"median_duration_seconds" example:"95"`
+	AvgEventsPerSession   float64 `json:"avg_events_per_session" example:"6.4"`
+}
+
+type ErrorResponse struct {
+	Error   string `json:"error" example:"invalid_session_query"`
+	Message string `json:"message" example:"invalid session query: gap must be between 1m0s and 24h0m0s"`
+}
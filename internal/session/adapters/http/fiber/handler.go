@@ -0,0 +1,91 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"event-metrics-service/internal/session/core/domain"
+	"event-metrics-service/internal/session/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SessionStatsUseCase interface {
+	Execute(ctx context.Context, in usecase.SessionStatsInput) (*domain.SessionStats, error)
+}
+
+type SessionHandler struct {
+	uc SessionStatsUseCase
+}
+
+func NewSessionHandler(uc SessionStatsUseCase) *SessionHandler {
+	return &SessionHandler{uc: uc}
+}
+
+// GetSessionStats godoc
+// @Summary Session metrics
+// @Description Groups every user's events in the range into sessions, split where the user was inactive for longer than gap,
+// @Description and returns the session count, session duration and events per session. Sessions are computed at query time.
+// @Tags Metrics
+// @Produce json
+// @Param from query int true "From timestamp"
+// @Param to query int true "To timestamp (at most 31 days after from)"
+// @Param gap query string false "Inactivity gap as a Go duration, 1m to 24h (default SESSION_GAP)"
+// @Param Authorization header string false "Bearer JWT from OIDC_ISSUER, required when it is set"
+// @Success 200 {object} SessionStatsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse "missing or invalid bearer token (when OIDC_ISSUER is set), or an API key of no tenant (when TENANT_ISOLATION is on)"
+// @Failure 403 {object} ErrorResponse "insufficient token scope (when OIDC_ISSUER is set), or missing scope metrics:read"
+// @Failure 429 {object} ErrorResponse "query_concurrency_limited: too many concurrent queries for this API key"
+// @Failure 500 {object} ErrorResponse
+// @Router /metrics/sessions [get]
+func (h *SessionHandler) GetSessionStats(c *fiber.Ctx) error {
+	from, err := strconv.ParseInt(c.Query("from"), 10, 64)
+	if err != nil {
+		return badRequest(c, "invalid 'from' parameter")
+	}
+	to, err := strconv.ParseInt(c.Query("to"), 10, 64)
+	if err != nil {
+		return badRequest(c, "invalid 'to' parameter")
+	}
+
+	in := usecase.SessionStatsInput{From: from, To: to}
+	if gap := c.Query("gap"); gap != "" {
+		d, err := time.ParseDuration(gap)
+		if err != nil || d <= 0 {
+			return badRequest(c, "invalid 'gap' parameter")
+		}
+		in.Gap = d
+	}
+
+	res, err := h.uc.Execute(c.UserContext(), in)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidSessionQuery) {
+			return badRequest(c, err.Error())
+		}
+		return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+			Error: "internal_server_error",
+		})
+	}
+
+	return c.JSON(SessionStatsResponse{
+		From:                  res.From,
+		To:                    res.To,
+		GapSeconds:            int64(res.Gap / time.Second),
+		Sessions:              res.Sessions,
+		Users:                 res.Users,
+		AvgDurationSeconds:    res.AvgDurationSeconds,
+		MedianDurationSeconds: res.MedianDurationSeconds,
+		AvgEventsPerSession:   res.AvgEventsPerSession,
+	})
+}
+
+func badRequest(c *fiber.Ctx, msg string) error {
+	return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+		Error:   "invalid_session_query",
+		Message: msg,
+	})
+}
@@ -0,0 +1,98 @@
+package fiber_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpadapter "event-metrics-service/internal/session/adapters/http/fiber"
+	"event-metrics-service/internal/session/core/domain"
+	"event-metrics-service/internal/session/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeSessionStatsUseCase struct {
+	ExecuteFn func(in usecase.SessionStatsInput) (*domain.SessionStats, error)
+	lastInput usecase.SessionStatsInput
+	called    bool
+}
+
+func (f *fakeSessionStatsUseCase) Execute(_ context.Context, in usecase.SessionStatsInput) (*domain.SessionStats, error) {
+	f.called = true
+	f.lastInput = in
+	return f.ExecuteFn(in)
+}
+
+func getSessionStats(t *testing.T, uc httpadapter.SessionStatsUseCase, path string) *http.Response {
+	t.Helper()
+	app := fiber.New()
+	app.Get("/metrics/sessions", httpadapter.NewSessionHandler(uc).GetSessionStats)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	return resp
+}
+
+func TestGetSessionStats_Success(t *testing.T) {
+	uc := &fakeSessionStatsUseCase{
+		ExecuteFn: func(in usecase.SessionStatsInput) (*domain.SessionStats, error) {
+			return &domain.SessionStats{
+				From: in.From, To: in.To, Gap: in.Gap,
+				Sessions: 12, Users: 5, AvgDurationSeconds: 240.5, MedianDurationSeconds: 60, AvgEventsPerSession: 4.25,
+			}, nil
+		},
+	}
+
+	resp := getSessionStats(t, uc, "/metrics/sessions?from=100&to=200&gap=45m")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if uc.lastInput.Gap != 45*time.Minute {
+		t.Fatalf("unexpected input: %+v", uc.lastInput)
+	}
+
+	var body httpadapter.SessionStatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.GapSeconds != 2700 || body.Sessions != 12 || body.AvgEventsPerSession != 4.25 {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+}
+
+func TestGetSessionStats_BadRequest(t *testing.T) {
+	for name, path := range map[string]string{
+		"missing to":   "/metrics/sessions?from=100",
+		"invalid gap":  "/metrics/sessions?from=100&to=200&gap=soon",
+		"negative gap": "/metrics/sessions?from=100&to=200&gap=-5m",
+	} {
+		uc := &fakeSessionStatsUseCase{}
+		if resp := getSessionStats(t, uc, path); resp.StatusCode != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", name, resp.StatusCode)
+		}
+		if uc.called {
+			t.Fatalf("%s: usecase should not be called", name)
+		}
+	}
+}
+
+func TestGetSessionStats_UsecaseErrors(t *testing.T) {
+	for err, want := range map[error]int{
+		fmt.Errorf("%w: range too long", usecase.ErrInvalidSessionQuery): http.StatusBadRequest,
+		fmt.Errorf("db down"): http.StatusInternalServerError,
+	} {
+		uc := &fakeSessionStatsUseCase{
+			ExecuteFn: func(in usecase.SessionStatsInput) (*domain.SessionStats, error) { return nil, err },
+		}
+		if resp := getSessionStats(t, uc, "/metrics/sessions?from=100&to=200"); resp.StatusCode != want {
+			t.Fatalf("%v: expected %d, got %d", err, want, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"event-metrics-service/internal/session/core/domain"
+	"event-metrics-service/internal/session/core/ports"
+)
+
+type RowScanner interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+type DB interface {
+	QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+// Gated columns (see the migration module) sessions depend on.
+const (
+	ColumnAnonymousID = "events.anonymous_id"
+	ColumnTenantID    = "events.tenant_id"
+)
+
+var GatedColumns = []string{ColumnAnonymousID, ColumnTenantID}
+
+// errTenantColumnGated fails tenant-scoped reads while events.tenant_id is
+// gated off, rather than answering them with every tenant's events.
+var errTenantColumnGated = errors.New("tenant-scoped query while " + ColumnTenantID + " is not readable")
+
+// ColumnGate reports whether queries may rely on a gated column.
+type ColumnGate interface {
+	Reads(column string) bool
+}
+
+type SessionRepository struct {
+	db   DB
+	gate ColumnGate
+}
+
+type RepositoryOption func(*SessionRepository)
+
+// WithColumnGate keys sessions by user_id alone while anonymous_id is not
+// readable; anonymous events are then left out.
+func WithColumnGate(g ColumnGate) RepositoryOption {
+	return func(r *SessionRepository) {
+		r.gate = g
+	}
+}
+
+func NewSessionRepository(db DB, opts ...RepositoryOption) *SessionRepository {
+	r := &SessionRepository{db: db}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+var _ ports.SessionReaderPort = (*SessionRepository)(nil)
+
+func (r *SessionRepository) reads(column string) bool {
+	return r.gate == nil || r.gate.Reads(column)
+}
+
+// sessionsSQL numbers each user's sessions with a running count of session
+// starts, i.e. events more than the gap after the previous one.
+const sessionsSQL = `
+WITH marked AS (
+    SELECT u, event_time,
+        CASE WHEN EXTRACT(EPOCH FROM event_time - LAG(event_time) OVER w) <= $3::float8 THEN 0 ELSE 1 END AS starts
+    FROM (
+        SELECT %s AS u, event_time
+        FROM events
+        WHERE %s
+    ) e
+    WINDOW w AS (PARTITION BY u ORDER BY event_time)
+),
+numbered AS (
+    SELECT u, event_time,
+        SUM(starts) OVER (PARTITION BY u ORDER BY event_time ROWS UNBOUNDED PRECEDING) AS session
+    FROM marked
+),
+sessions AS (
+    SELECT u, EXTRACT(EPOCH FROM MAX(event_time) - MIN(event_time))::float8 AS duration, COUNT(*) AS events
+    FROM numbered
+    GROUP BY u, session
+)
+SELECT
+    COUNT(*),
+    COUNT(DISTINCT u),
+    COALESCE(AVG(duration), 0),
+    COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY duration), 0),
+    COALESCE(AVG(events), 0)::float8
+FROM sessions`
+
+func (r *SessionRepository) QuerySessions(ctx context.Context, f ports.SessionFilter) (*domain.SessionStats, error) {
+	// Up to the second after to, as events carry milliseconds.
+	where := "event_time >= $1 AND event_time < $2"
+	args := []any{time.Unix(f.From, 0).UTC(), time.Unix(f.To+1, 0).UTC(), f.Gap.Seconds()}
+
+	// Anonymous visitors have sessions of their own, as with unique users.
+	user := `CASE WHEN user_id <> '' THEN user_id ELSE 'anon:' || anonymous_id END`
+	if r.reads(ColumnAnonymousID) {
+		where += " AND (user_id <> '' OR anonymous_id <> '')"
+	} else {
+		user = "user_id"
+		where += " AND user_id <> ''"
+	}
+
+	if f.Tenant != nil {
+		if !r.reads(ColumnTenantID) {
+			return nil, errTenantColumnGated
+		}
+		args = append(args, *f.Tenant)
+		where += fmt.Sprintf(" AND tenant_id = $%d", len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(sessionsSQL, user, where), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := &domain.SessionStats{From: f.From, To: f.To, Gap: f.Gap}
+	if rows.Next() {
+		if err := rows.Scan(
+			&res.Sessions,
+			&res.Users,
+			&res.AvgDurationSeconds,
+			&res.MedianDurationSeconds,
+			&res.AvgEventsPerSession,
+		); err != nil {
+			return nil, err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/session/core/domain"
+	"event-metrics-service/internal/session/core/ports"
+)
+
+// fakeRowScanner implements RowScanner for tests.
+type fakeRowScanner struct {
+	rows [][]any
+	i    int
+}
+
+func (f *fakeRowScanner) Next() bool {
+	return f.i < len(f.rows)
+}
+
+func (f *fakeRowScanner) Scan(dest ...any) error {
+	row := f.rows[f.i]
+	if len(dest) != len(row) {
+		return errors.New("dest length mismatch")
+	}
+	for i := range dest {
+		switch d := dest[i].(type) {
+		case *int64:
+			*d = row[i].(int64)
+		case *float64:
+			*d = row[i].(float64)
+		default:
+			return errors.New("unsupported dest type")
+		}
+	}
+	f.i++
+	return nil
+}
+
+func (f *fakeRowScanner) Err() error   { return nil }
+func (f *fakeRowScanner) Close() error { return nil }
+
+type fakeDB struct {
+	QueryFn   func(ctx context.Context, query string, args ...any) (RowScanner, error)
+	lastQuery string
+	lastArgs  []any
+}
+
+func (f *fakeDB) QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error) {
+	f.lastQuery = query
+	f.lastArgs = args
+	return f.QueryFn(ctx, query, args...)
+}
+
+type fakeGate map[string]bool
+
+func (g fakeGate) Reads(column string) bool { return g[column] }
+
+func statsRows(context.Context, string, ...any) (RowScanner, error) {
+	return &fakeRowScanner{rows: [][]any{{int64(12), int64(5), 240.5, 60.0, 4.25}}}, nil
+}
+
+func TestSessionRepository_QuerySessions(t *testing.T) {
+	db := &fakeDB{QueryFn: statsRows}
+	tenant := "acme"
+
+	got, err := NewSessionRepository(db).QuerySessions(context.Background(), ports.SessionFilter{
+		From:   100,
+		To:     200,
+		Gap:    30 * time.Minute,
+		Tenant: &tenant,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"LAG(event_time) OVER w) <= $3::float8",
+		"'anon:' || anonymous_id",
+		"tenant_id = $4",
+		"GROUP BY u, session",
+	} {
+		if !strings.Contains(db.lastQuery, want) {
+			t.Fatalf("query missing %q: %s", want, db.lastQuery)
+		}
+	}
+	if len(db.lastArgs) != 4 || db.lastArgs[2] != 1800.0 || db.lastArgs[3] != "acme" {
+		t.Fatalf("unexpected args: %v", db.lastArgs)
+	}
+
+	want := domain.SessionStats{
+		From: 100, To: 200, Gap: 30 * time.Minute,
+		Sessions: 12, Users: 5, AvgDurationSeconds: 240.5, MedianDurationSeconds: 60, AvgEventsPerSession: 4.25,
+	}
+	if *got != want {
+		t.Fatalf("unexpected stats: %+v", got)
+	}
+}
+
+func TestSessionRepository_GatedColumns(t *testing.T) {
+	db := &fakeDB{QueryFn: statsRows}
+	repo := NewSessionRepository(db, WithColumnGate(fakeGate{}))
+
+	if _, err := repo.QuerySessions(context.Background(), ports.SessionFilter{From: 100, To: 200, Gap: time.Hour}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(db.lastQuery, "anonymous_id") || !strings.Contains(db.lastQuery, "user_id <> ''") {
+		t.Fatalf("expected sessions by user_id only, got: %s", db.lastQuery)
+	}
+
+	tenant := "acme"
+	_, err := repo.QuerySessions(context.Background(), ports.SessionFilter{From: 100, To: 200, Gap: time.Hour, Tenant: &tenant})
+	if !errors.Is(err, errTenantColumnGated) {
+		t.Fatalf("expected errTenantColumnGated, got %v", err)
+	}
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// SessionStats summarises the sessions in a time range. A session is a run
+// of one user's events with no gap longer than Gap between them.
+type SessionStats struct {
+	From int64
+	To   int64
+	Gap  time.Duration
+
+	Sessions int64
+	Users    int64
+
+	AvgDurationSeconds    float64 // first to last event; 0 for single-event sessions
+	MedianDurationSeconds float64
+	AvgEventsPerSession   float64
+}
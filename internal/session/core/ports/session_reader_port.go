@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"event-metrics-service/internal/session/core/domain"
+)
+
+type SessionFilter struct {
+	From int64
+	To   int64
+	Gap  time.Duration
+
+	Tenant *string // only events of this tenant (nil = all tenants)
+}
+
+type SessionReaderPort interface {
+	// QuerySessions splits every user's events in [From, To] into sessions
+	// at gaps longer than Gap and summarises them.
+	QuerySessions(ctx context.Context, f SessionFilter) (*domain.SessionStats, error)
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"event-metrics-service/internal/session/core/domain"
+	"event-metrics-service/internal/session/core/ports"
+)
+
+var ErrInvalidSessionQuery = errors.New("invalid session query")
+
+const (
+	DefaultGap = 30 * time.Minute
+	MinGap     = time.Minute
+	MaxGap     = 24 * time.Hour
+
+	// MaxRange caps one query: sessions are computed from the raw events of
+	// the whole range.
+	MaxRange = 31 * 24 * time.Hour
+)
+
+type SessionStatsInput struct {
+	From int64
+	To   int64
+	Gap  time.Duration // 0 = the configured default
+}
+
+// SessionStatsUseCase sessionizes events at query time, so changing the gap
+// needs no backfill.
+type SessionStatsUseCase struct {
+	reader   ports.SessionReaderPort
+	gap      time.Duration
+	tenantOf func(ctx context.Context) string
+}
+
+type Option func(*SessionStatsUseCase)
+
+// WithDefaultGap sets the inactivity gap used when a query names none.
+func WithDefaultGap(gap time.Duration) Option {
+	return func(uc *SessionStatsUseCase) {
+		uc.gap = gap
+	}
+}
+
+// WithTenantResolver restricts sessions to the events of the tenant of the
+// request context.
+func WithTenantResolver(fn func(ctx context.Context) string) Option {
+	return func(uc *SessionStatsUseCase) {
+		uc.tenantOf = fn
+	}
+}
+
+func NewSessionStatsUseCase(reader ports.SessionReaderPort, opts ...Option) *SessionStatsUseCase {
+	uc := &SessionStatsUseCase{reader: reader, gap: DefaultGap}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+func (uc *SessionStatsUseCase) Execute(ctx context.Context, in SessionStatsInput) (*domain.SessionStats, error) {
+	if in.From <= 0 || in.To <= 0 || in.From > in.To {
+		return nil, fmt.Errorf("%w: from and to must be a valid range", ErrInvalidSessionQuery)
+	}
+	if time.Duration(in.To-in.From)*time.Second > MaxRange {
+		return nil, fmt.Errorf("%w: range must not exceed %s", ErrInvalidSessionQuery, MaxRange)
+	}
+
+	gap := in.Gap
+	if gap == 0 {
+		gap = uc.gap
+	}
+	if gap < MinGap || gap > MaxGap {
+		return nil, fmt.Errorf("%w: gap must be between %s and %s", ErrInvalidSessionQuery, MinGap, MaxGap)
+	}
+
+	f := ports.SessionFilter{From: in.From, To: in.To, Gap: gap}
+	if uc.tenantOf != nil {
+		t := uc.tenantOf(ctx)
+		f.Tenant = &t
+	}
+	return uc.reader.QuerySessions(ctx, f)
+}
@@ -0,0 +1,74 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/session/core/domain"
+	"event-metrics-service/internal/session/core/ports"
+	"event-metrics-service/internal/session/core/usecase"
+)
+
+type fakeSessionReader struct {
+	lastFilter ports.SessionFilter
+	called     bool
+}
+
+func (f *fakeSessionReader) QuerySessions(ctx context.Context, flt ports.SessionFilter) (*domain.SessionStats, error) {
+	f.called = true
+	f.lastFilter = flt
+	return &domain.SessionStats{From: flt.From, To: flt.To, Gap: flt.Gap}, nil
+}
+
+func TestSessionStats_DefaultAndRequestedGap(t *testing.T) {
+	reader := &fakeSessionReader{}
+	uc := usecase.NewSessionStatsUseCase(reader, usecase.WithDefaultGap(15*time.Minute))
+
+	if _, err := uc.Execute(context.Background(), usecase.SessionStatsInput{From: 100, To: 200}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reader.lastFilter.Gap != 15*time.Minute || reader.lastFilter.Tenant != nil {
+		t.Fatalf("unexpected filter: %+v", reader.lastFilter)
+	}
+
+	if _, err := uc.Execute(context.Background(), usecase.SessionStatsInput{From: 100, To: 200, Gap: time.Hour}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reader.lastFilter.Gap != time.Hour {
+		t.Fatalf("expected the requested gap, got %s", reader.lastFilter.Gap)
+	}
+}
+
+func TestSessionStats_ScopesToTenant(t *testing.T) {
+	reader := &fakeSessionReader{}
+	uc := usecase.NewSessionStatsUseCase(reader, usecase.WithTenantResolver(func(context.Context) string { return "acme" }))
+
+	if _, err := uc.Execute(context.Background(), usecase.SessionStatsInput{From: 100, To: 200}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reader.lastFilter.Tenant == nil || *reader.lastFilter.Tenant != "acme" {
+		t.Fatalf("expected tenant acme, got %+v", reader.lastFilter)
+	}
+}
+
+func TestSessionStats_Validation(t *testing.T) {
+	day := int64(24 * 60 * 60)
+	for name, in := range map[string]usecase.SessionStatsInput{
+		"reversed range": {From: 200, To: 100},
+		"missing from":   {To: 100},
+		"range too long": {From: 100, To: 100 + 32*day},
+		"gap too short":  {From: 100, To: 200, Gap: time.Second},
+		"gap too long":   {From: 100, To: 200, Gap: 25 * time.Hour},
+	} {
+		reader := &fakeSessionReader{}
+		uc := usecase.NewSessionStatsUseCase(reader)
+		if _, err := uc.Execute(context.Background(), in); !errors.Is(err, usecase.ErrInvalidSessionQuery) {
+			t.Fatalf("%s: expected ErrInvalidSessionQuery, got %v", name, err)
+		}
+		if reader.called {
+			t.Fatalf("%s: reader should not be called", name)
+		}
+	}
+}
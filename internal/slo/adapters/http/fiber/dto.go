@@ -0,0 +1,26 @@
+package fiber
+
+// SLOWindowResponse is one look-back window of an operation's SLO.
+type SLOWindowResponse struct {
+	Window          string  `json:"window" example:"1h0m0s"`
+	Requests        int64   `json:"requests" example:"12000"`
+	Errors          int64   `json:"errors" example:"3"`
+	Rejected        int64   `json:"rejected" example:"41"`
+	Slow            int64   `json:"slow" example:"90"`
+	P99Ms           float64 `json:"p99_ms" example:"100"`
+	ErrorBurnRate   float64 `json:"error_burn_rate" example:"0.25"`
+	LatencyBurnRate float64 `json:"latency_burn_rate" example:"0.75"`
+}
+
+type SLOSummaryResponse struct {
+	Operation             string              `json:"operation" example:"store_event"`
+	Tenant                string              `json:"tenant" example:"acme"`
+	LatencyTargetMs       float64             `json:"latency_target_ms" example:"100"`
+	LatencyObjective      float64             `json:"latency_objective" example:"0.99"`
+	AvailabilityObjective float64             `json:"availability_objective" example:"0.999"`
+	Windows               []SLOWindowResponse `json:"windows"`
+}
+
+type SLOResponse struct {
+	Summaries []SLOSummaryResponse `json:"summaries"`
+}
@@ -0,0 +1,67 @@
+package fiber
+
+import (
+	"net/http"
+	"time"
+
+	"event-metrics-service/internal/slo/core/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SLOUseCase interface {
+	Summaries() []domain.Summary
+}
+
+type SLOHandler struct {
+	uc SLOUseCase
+}
+
+func NewSLOHandler(uc SLOUseCase) *SLOHandler {
+	return &SLOHandler{uc: uc}
+}
+
+// GetSLO godoc
+// @Summary Summarize SLO burn rates
+// @Description Error and latency burn rates of business operations (store_event, bulk_store_events, query_metrics,
+// @Description query_metrics_batch) per tenant over the last 5m, 1h and 6h, as seen by this instance. A burn rate
+// @Description of 1 spends the error budget exactly over the SLO period; rejected (invalid) requests do not count.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} SLOResponse
+// @Router /admin/slo [get]
+func (h *SLOHandler) GetSLO(c *fiber.Ctx) error {
+	summaries := h.uc.Summaries()
+
+	resp := SLOResponse{Summaries: make([]SLOSummaryResponse, 0, len(summaries))}
+	for _, s := range summaries {
+		item := SLOSummaryResponse{
+			Operation:             s.Objective.Operation,
+			Tenant:                s.Tenant,
+			LatencyTargetMs:       ms(s.Objective.LatencyTarget),
+			LatencyObjective:      s.Objective.LatencyObjective,
+			AvailabilityObjective: s.Objective.AvailabilityObjective,
+			Windows:               make([]SLOWindowResponse, 0, len(s.Windows)),
+		}
+		for _, w := range s.Windows {
+			item.Windows = append(item.Windows, SLOWindowResponse{
+				Window:          w.Window.String(),
+				Requests:        w.Requests,
+				Errors:          w.Errors,
+				Rejected:        w.Rejected,
+				Slow:            w.Slow,
+				P99Ms:           ms(w.P99),
+				ErrorBurnRate:   w.ErrorBurnRate,
+				LatencyBurnRate: w.LatencyBurnRate,
+			})
+		}
+		resp.Summaries = append(resp.Summaries, item)
+	}
+
+	return c.Status(http.StatusOK).JSON(resp)
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,67 @@
+package fiber_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpadapter "event-metrics-service/internal/slo/adapters/http/fiber"
+	"event-metrics-service/internal/slo/core/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeSLOUseCase struct {
+	summaries []domain.Summary
+}
+
+func (f *fakeSLOUseCase) Summaries() []domain.Summary {
+	return f.summaries
+}
+
+func TestGetSLO(t *testing.T) {
+	uc := &fakeSLOUseCase{summaries: []domain.Summary{{
+		Objective: domain.Objective{
+			Operation:             "store_event",
+			LatencyTarget:         100 * time.Millisecond,
+			LatencyObjective:      0.99,
+			AvailabilityObjective: 0.999,
+		},
+		Tenant: "acme",
+		Windows: []domain.WindowStats{{
+			Window:        time.Hour,
+			Requests:      1000,
+			Errors:        2,
+			P99:           250 * time.Millisecond,
+			ErrorBurnRate: 2,
+		}},
+	}}}
+
+	app := fiber.New()
+	app.Get("/admin/slo", httpadapter.NewSLOHandler(uc).GetSLO)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/admin/slo", nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body httpadapter.SLOResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if len(body.Summaries) != 1 {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+	s := body.Summaries[0]
+	if s.Operation != "store_event" || s.Tenant != "acme" || s.LatencyTargetMs != 100 || len(s.Windows) != 1 {
+		t.Fatalf("unexpected summary: %+v", s)
+	}
+	if w := s.Windows[0]; w.Window != "1h0m0s" || w.P99Ms != 250 || w.ErrorBurnRate != 2 {
+		t.Fatalf("unexpected window: %+v", w)
+	}
+}
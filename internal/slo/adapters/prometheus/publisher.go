@@ -0,0 +1,51 @@
+package prometheus
+
+import (
+	"time"
+
+	"event-metrics-service/internal/slo/core/domain"
+	"event-metrics-service/internal/slo/core/ports"
+	"event-metrics-service/internal/telemetry"
+)
+
+const (
+	metricDuration     = "ems_sli_duration_seconds"
+	metricSlowRequests = "ems_sli_slow_requests_total"
+	metricObjective    = "ems_slo_objective_ratio"
+	metricLatencyGoal  = "ems_slo_latency_target_seconds"
+)
+
+// SLIPublisher exports SLIs so SLOs and burn-rate alerts can be defined in
+// Prometheus, e.g.
+//
+//	histogram_quantile(0.99, sum by (le) (rate(ems_sli_duration_seconds_bucket{operation="store_event"}[5m])))
+type SLIPublisher struct {
+	reg *telemetry.Registry
+}
+
+// NewSLIPublisher also publishes the objectives, so alert rules can divide
+// by the budget without hard-coding it.
+func NewSLIPublisher(reg *telemetry.Registry, objectives []domain.Objective) *SLIPublisher {
+	for _, o := range objectives {
+		reg.SetGauge(metricObjective, "Target share of good requests, by operation and sli (availability or latency).",
+			telemetry.Labels{"operation": o.Operation, "sli": "availability"}, o.AvailabilityObjective)
+		reg.SetGauge(metricObjective, "Target share of good requests, by operation and sli (availability or latency).",
+			telemetry.Labels{"operation": o.Operation, "sli": "latency"}, o.LatencyObjective)
+		reg.SetGauge(metricLatencyGoal, "Latency a request must stay within to count as good.",
+			telemetry.Labels{"operation": o.Operation}, o.LatencyTarget.Seconds())
+	}
+	return &SLIPublisher{reg: reg}
+}
+
+var _ ports.SLIPublisherPort = (*SLIPublisher)(nil)
+
+func (p *SLIPublisher) Observe(operation, tenant, outcome string, d time.Duration, slow bool) {
+	p.reg.ObserveHistogram(metricDuration, "Duration of business operations, by operation, tenant and outcome.",
+		telemetry.Labels{"operation": operation, "tenant": tenant, "outcome": outcome},
+		domain.LatencyBuckets, d.Seconds())
+
+	if slow {
+		p.reg.AddCounter(metricSlowRequests, "Operations slower than their latency target.",
+			telemetry.Labels{"operation": operation, "tenant": tenant}, 1)
+	}
+}
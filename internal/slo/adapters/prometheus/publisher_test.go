@@ -0,0 +1,42 @@
+package prometheus
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/slo/core/domain"
+	"event-metrics-service/internal/telemetry"
+)
+
+func TestSLIPublisher_ExportsHistogramsAndObjectives(t *testing.T) {
+	reg := telemetry.NewRegistry()
+	p := NewSLIPublisher(reg, []domain.Objective{{
+		Operation:             "store_event",
+		LatencyTarget:         100 * time.Millisecond,
+		LatencyObjective:      0.99,
+		AvailabilityObjective: 0.999,
+	}})
+
+	p.Observe("store_event", "acme", domain.OutcomeOK, 20*time.Millisecond, false)
+	p.Observe("store_event", "acme", domain.OutcomeOK, 300*time.Millisecond, true)
+
+	var buf bytes.Buffer
+	if err := reg.WriteText(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		`ems_sli_duration_seconds_bucket{operation="store_event",outcome="ok",tenant="acme",le="0.025"} 1`,
+		`ems_sli_duration_seconds_count{operation="store_event",outcome="ok",tenant="acme"} 2`,
+		`ems_sli_slow_requests_total{operation="store_event",tenant="acme"} 1`,
+		`ems_slo_objective_ratio{operation="store_event",sli="availability"} 0.999`,
+		`ems_slo_latency_target_seconds{operation="store_event"} 0.1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// Outcomes of a measured operation. Rejected requests failed validation;
+// they are the caller's fault and do not burn the error budget.
+const (
+	OutcomeOK       = "ok"
+	OutcomeRejected = "rejected"
+	OutcomeError    = "error"
+)
+
+// LatencyBuckets are the upper bounds, in seconds, used to estimate latency
+// percentiles both in-process and in the exported histograms.
+var LatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Objective is the SLO of one business operation, e.g. "99% of store_event
+// calls finish within 100ms and 99.9% succeed".
+type Objective struct {
+	Operation             string
+	LatencyTarget         time.Duration
+	LatencyObjective      float64 // share of requests within LatencyTarget, e.g. 0.99
+	AvailabilityObjective float64 // share of non-rejected requests that succeed, e.g. 0.999
+}
+
+// WindowStats summarises one look-back window. Requests excludes rejected
+// ones; Slow counts requests over the latency target.
+type WindowStats struct {
+	Window   time.Duration
+	Requests int64
+	Errors   int64
+	Rejected int64
+	Slow     int64
+	P99      time.Duration // upper bound of the bucket holding the 99th percentile
+
+	// Burn rates: how fast the error budget is being spent, where 1 means
+	// exactly at the objective and e.g. 14.4 over 1h exhausts a 30-day
+	// budget in about two days.
+	ErrorBurnRate   float64
+	LatencyBurnRate float64
+}
+
+// Summary is the SLO state of one operation for one tenant.
+type Summary struct {
+	Objective Objective
+	Tenant    string
+	Windows   []WindowStats
+}
+
+// BurnRate returns bad/total relative to the budget 1-objective.
+func BurnRate(bad, total int64, objective float64) float64 {
+	if total == 0 || objective >= 1 {
+		return 0
+	}
+	return (float64(bad) / float64(total)) / (1 - objective)
+}
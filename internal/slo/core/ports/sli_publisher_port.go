@@ -0,0 +1,8 @@
+package ports
+
+import "time"
+
+type SLIPublisherPort interface {
+	// Observe exports one measured operation.
+	Observe(operation, tenant, outcome string, d time.Duration, slow bool)
+}
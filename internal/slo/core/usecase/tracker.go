@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"event-metrics-service/internal/slo/core/domain"
+	"event-metrics-service/internal/slo/core/ports"
+)
+
+// DefaultWindows are the look-back windows burn rates are reported for,
+// short enough to page on and long enough to ticket on.
+var DefaultWindows = []time.Duration{5 * time.Minute, time.Hour, 6 * time.Hour}
+
+// DefaultTenant labels requests when no tenant resolver is configured.
+const DefaultTenant = "default"
+
+// Tracker measures business operations against their objectives. It keeps
+// per-minute counters for the longest window in memory, per operation and
+// tenant, so /admin/slo reflects this instance only; the exported
+// histograms are the fleet-wide source for alerting.
+type Tracker struct {
+	objectives map[string]domain.Objective
+	order      []string
+	publisher  ports.SLIPublisherPort
+	tenantOf   func(ctx context.Context) string
+	windows    []time.Duration
+	now        func() time.Time
+
+	mu     sync.Mutex
+	series map[seriesKey]*series
+}
+
+type seriesKey struct {
+	operation string
+	tenant    string
+}
+
+type Option func(*Tracker)
+
+// WithTenantResolver labels each observation with the tenant of the request
+// context. The resolver must map to a bounded set of values.
+func WithTenantResolver(fn func(ctx context.Context) string) Option {
+	return func(t *Tracker) {
+		t.tenantOf = fn
+	}
+}
+
+func WithWindows(windows ...time.Duration) Option {
+	return func(t *Tracker) {
+		t.windows = windows
+	}
+}
+
+func NewTracker(objectives []domain.Objective, publisher ports.SLIPublisherPort, opts ...Option) *Tracker {
+	t := &Tracker{
+		objectives: make(map[string]domain.Objective, len(objectives)),
+		publisher:  publisher,
+		tenantOf:   func(context.Context) string { return DefaultTenant },
+		windows:    DefaultWindows,
+		now:        time.Now,
+		series:     map[seriesKey]*series{},
+	}
+	for _, o := range objectives {
+		t.objectives[o.Operation] = o
+		t.order = append(t.order, o.Operation)
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Observe records one call of operation. Operations without an objective
+// are ignored.
+func (t *Tracker) Observe(ctx context.Context, operation, outcome string, d time.Duration) {
+	o, ok := t.objectives[operation]
+	if !ok {
+		return
+	}
+
+	tenant := t.tenantOf(ctx)
+	slow := outcome != domain.OutcomeRejected && d > o.LatencyTarget
+
+	t.mu.Lock()
+	key := seriesKey{operation: operation, tenant: tenant}
+	s := t.series[key]
+	if s == nil {
+		s = newSeries(t.retention())
+		t.series[key] = s
+	}
+	s.add(t.now().Unix()/60, outcome, d, slow)
+	t.mu.Unlock()
+
+	if t.publisher != nil {
+		t.publisher.Observe(operation, tenant, outcome, d, slow)
+	}
+}
+
+// Summaries returns the state of every operation and tenant seen so far,
+// in objective order, then by tenant.
+func (t *Tracker) Summaries() []domain.Summary {
+	minute := t.now().Unix() / 60
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	keys := make([]seriesKey, 0, len(t.series))
+	for k := range t.series {
+		keys = append(keys, k)
+	}
+	rank := make(map[string]int, len(t.order))
+	for i, op := range t.order {
+		rank[op] = i
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].operation != keys[j].operation {
+			return rank[keys[i].operation] < rank[keys[j].operation]
+		}
+		return keys[i].tenant < keys[j].tenant
+	})
+
+	out := make([]domain.Summary, 0, len(keys))
+	for _, k := range keys {
+		o := t.objectives[k.operation]
+		sum := domain.Summary{Objective: o, Tenant: k.tenant}
+		for _, w := range t.windows {
+			sum.Windows = append(sum.Windows, t.series[k].stats(minute, w, o))
+		}
+		out = append(out, sum)
+	}
+	return out
+}
+
+func (t *Tracker) retention() int {
+	longest := time.Minute
+	for _, w := range t.windows {
+		if w > longest {
+			longest = w
+		}
+	}
+	return int(longest / time.Minute)
+}
+
+// series is a ring of per-minute buckets.
+type series struct {
+	buckets []bucket
+}
+
+type bucket struct {
+	minute   int64
+	requests int64
+	errors   int64
+	rejected int64
+	slow     int64
+	latency  []int64 // per domain.LatencyBuckets, plus +Inf
+}
+
+func newSeries(minutes int) *series {
+	return &series{buckets: make([]bucket, minutes)}
+}
+
+func (s *series) add(minute int64, outcome string, d time.Duration, slow bool) {
+	b := &s.buckets[minute%int64(len(s.buckets))]
+	if b.minute != minute || b.latency == nil {
+		*b = bucket{minute: minute, latency: make([]int64, len(domain.LatencyBuckets)+1)}
+	}
+
+	if outcome == domain.OutcomeRejected {
+		b.rejected++
+		return
+	}
+	b.requests++
+	if outcome == domain.OutcomeError {
+		b.errors++
+	}
+	if slow {
+		b.slow++
+	}
+	b.latency[sort.SearchFloat64s(domain.LatencyBuckets, d.Seconds())]++
+}
+
+func (s *series) stats(minute int64, window time.Duration, o domain.Objective) domain.WindowStats {
+	out := domain.WindowStats{Window: window}
+	latency := make([]int64, len(domain.LatencyBuckets)+1)
+
+	oldest := minute - int64(window/time.Minute) + 1
+	for _, b := range s.buckets {
+		if b.latency == nil || b.minute < oldest || b.minute > minute {
+			continue
+		}
+		out.Requests += b.requests
+		out.Errors += b.errors
+		out.Rejected += b.rejected
+		out.Slow += b.slow
+		for i, n := range b.latency {
+			latency[i] += n
+		}
+	}
+
+	out.P99 = percentile(latency, out.Requests, 0.99)
+	out.ErrorBurnRate = domain.BurnRate(out.Errors, out.Requests, o.AvailabilityObjective)
+	out.LatencyBurnRate = domain.BurnRate(out.Slow, out.Requests, o.LatencyObjective)
+	return out
+}
+
+// percentile returns the upper bound of the bucket holding quantile q. The
+// overflow bucket reports the largest finite bound.
+func percentile(counts []int64, total int64, q float64) time.Duration {
+	if total == 0 {
+		return 0
+	}
+
+	rank := int64(math.Ceil(float64(total) * q))
+	var seen int64
+	for i, n := range counts {
+		seen += n
+		if seen >= rank {
+			if i >= len(domain.LatencyBuckets) {
+				i = len(domain.LatencyBuckets) - 1
+			}
+			return time.Duration(domain.LatencyBuckets[i] * float64(time.Second))
+		}
+	}
+	return 0
+}
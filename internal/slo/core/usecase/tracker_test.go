@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/slo/core/domain"
+)
+
+type fakePublisher struct {
+	calls []string
+}
+
+func (f *fakePublisher) Observe(operation, tenant, outcome string, d time.Duration, slow bool) {
+	f.calls = append(f.calls, operation+"/"+tenant+"/"+outcome)
+}
+
+var storeObjective = domain.Objective{
+	Operation:             "store_event",
+	LatencyTarget:         100 * time.Millisecond,
+	LatencyObjective:      0.99,
+	AvailabilityObjective: 0.999,
+}
+
+func TestTracker_BurnRatesPerWindow(t *testing.T) {
+	now := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	pub := &fakePublisher{}
+	tr := NewTracker([]domain.Objective{storeObjective}, pub)
+	tr.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Half an hour ago: 100 fast successes.
+	now = now.Add(-30 * time.Minute)
+	for i := 0; i < 100; i++ {
+		tr.Observe(ctx, "store_event", domain.OutcomeOK, 10*time.Millisecond)
+	}
+
+	// Now: one error, one slow success and a rejected call.
+	now = now.Add(30 * time.Minute)
+	tr.Observe(ctx, "store_event", domain.OutcomeError, 10*time.Millisecond)
+	tr.Observe(ctx, "store_event", domain.OutcomeOK, 300*time.Millisecond)
+	tr.Observe(ctx, "store_event", domain.OutcomeRejected, time.Millisecond)
+
+	got := tr.Summaries()
+	if len(got) != 1 || got[0].Tenant != DefaultTenant || len(got[0].Windows) != 3 {
+		t.Fatalf("unexpected summaries: %+v", got)
+	}
+
+	short, hour := got[0].Windows[0], got[0].Windows[1]
+	if short.Requests != 2 || short.Errors != 1 || short.Slow != 1 || short.Rejected != 1 {
+		t.Fatalf("unexpected 5m window: %+v", short)
+	}
+	if short.ErrorBurnRate < 499 || short.ErrorBurnRate > 501 || short.LatencyBurnRate < 49 || short.LatencyBurnRate > 51 {
+		t.Fatalf("unexpected 5m burn rates: %+v", short)
+	}
+	if short.P99 != 500*time.Millisecond {
+		t.Fatalf("expected p99 bucket 500ms, got %s", short.P99)
+	}
+
+	if hour.Requests != 102 || hour.Errors != 1 || hour.P99 != 10*time.Millisecond {
+		t.Fatalf("unexpected 1h window: %+v", hour)
+	}
+
+	if len(pub.calls) != 103 || pub.calls[100] != "store_event/default/error" {
+		t.Fatalf("unexpected publisher calls: %d", len(pub.calls))
+	}
+}
+
+type tenantKey struct{}
+
+func withTenant(tenant string) context.Context {
+	return context.WithValue(context.Background(), tenantKey{}, tenant)
+}
+
+func TestTracker_TenantsAndUnknownOperations(t *testing.T) {
+	tr := NewTracker([]domain.Objective{storeObjective}, nil,
+		WithTenantResolver(func(ctx context.Context) string { return ctx.Value(tenantKey{}).(string) }),
+		WithWindows(time.Minute),
+	)
+
+	tr.Observe(withTenant("globex"), "store_event", domain.OutcomeOK, time.Millisecond)
+	tr.Observe(withTenant("acme"), "store_event", domain.OutcomeOK, time.Millisecond)
+	tr.Observe(withTenant("acme"), "unknown_op", domain.OutcomeOK, time.Millisecond)
+
+	got := tr.Summaries()
+	if len(got) != 2 || got[0].Tenant != "acme" || got[1].Tenant != "globex" {
+		t.Fatalf("unexpected summaries: %+v", got)
+	}
+	if got[0].Windows[0].Requests != 1 {
+		t.Fatalf("unexpected window: %+v", got[0].Windows[0])
+	}
+}
+
+func TestTracker_OldBucketsExpire(t *testing.T) {
+	now := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	tr := NewTracker([]domain.Objective{storeObjective}, nil, WithWindows(5*time.Minute))
+	tr.now = func() time.Time { return now }
+
+	tr.Observe(context.Background(), "store_event", domain.OutcomeError, time.Millisecond)
+
+	now = now.Add(5 * time.Minute)
+	if w := tr.Summaries()[0].Windows[0]; w.Requests != 0 || w.ErrorBurnRate != 0 {
+		t.Fatalf("expected expired bucket to be ignored, got %+v", w)
+	}
+}
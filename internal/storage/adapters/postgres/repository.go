@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+
+	"event-metrics-service/internal/storage/core/domain"
+	"event-metrics-service/internal/storage/core/ports"
+)
+
+type RowScanner interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+type DB interface {
+	QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+type StorageStatsRepository struct {
+	db DB
+}
+
+func NewStorageStatsRepository(db DB) *StorageStatsRepository {
+	return &StorageStatsRepository{db: db}
+}
+
+var _ ports.StorageStatsReaderPort = (*StorageStatsRepository)(nil)
+
+// Parent tables and their partitions (if any). Partition rows are reported
+// under their own name so growth can be tracked per partition.
+const tableStatsSQL = `
+SELECT
+    s.relname,
+    pg_total_relation_size(s.relid) AS total_bytes,
+    pg_relation_size(s.relid)       AS table_bytes,
+    pg_indexes_size(s.relid)        AS index_bytes,
+    s.n_live_tup,
+    s.n_dead_tup,
+    (SELECT COUNT(*) FROM pg_inherits i WHERE i.inhparent = s.relid) AS partition_count
+FROM pg_stat_user_tables s
+WHERE s.relname = $1
+   OR s.relid IN (
+       SELECT i.inhrelid FROM pg_inherits i
+       JOIN pg_class p ON p.oid = i.inhparent
+       WHERE p.relname = $1
+   )
+ORDER BY s.relname`
+
+const indexStatsSQL = `
+SELECT
+    s.indexrelname,
+    s.relname,
+    pg_relation_size(s.indexrelid) AS index_bytes
+FROM pg_stat_user_indexes s
+WHERE s.relname = $1
+   OR s.relid IN (
+       SELECT i.inhrelid FROM pg_inherits i
+       JOIN pg_class p ON p.oid = i.inhparent
+       WHERE p.relname = $1
+   )
+ORDER BY s.indexrelname`
+
+func (r *StorageStatsRepository) ReadStorageStats(ctx context.Context, tables []string) (*domain.StorageStats, error) {
+	res := &domain.StorageStats{}
+
+	for _, table := range tables {
+		ts, err := r.queryTables(ctx, table)
+		if err != nil {
+			return nil, err
+		}
+		res.Tables = append(res.Tables, ts...)
+
+		is, err := r.queryIndexes(ctx, table)
+		if err != nil {
+			return nil, err
+		}
+		res.Indexes = append(res.Indexes, is...)
+	}
+
+	return res, nil
+}
+
+func (r *StorageStatsRepository) queryTables(ctx context.Context, table string) ([]domain.TableStats, error) {
+	rows, err := r.db.QueryContext(ctx, tableStatsSQL, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.TableStats
+	for rows.Next() {
+		var t domain.TableStats
+		if err := rows.Scan(
+			&t.Name,
+			&t.TotalBytes,
+			&t.TableBytes,
+			&t.IndexBytes,
+			&t.LiveTuples,
+			&t.DeadTuples,
+			&t.PartitionCount,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (r *StorageStatsRepository) queryIndexes(ctx context.Context, table string) ([]domain.IndexStats, error) {
+	rows, err := r.db.QueryContext(ctx, indexStatsSQL, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.IndexStats
+	for rows.Next() {
+		var i domain.IndexStats
+		if err := rows.Scan(&i.Name, &i.Table, &i.Bytes); err != nil {
+			return nil, err
+		}
+		out = append(out, i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
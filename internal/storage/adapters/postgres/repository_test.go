@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// fakeRowScanner implements RowScanner for tests.
+type fakeRowScanner struct {
+	rows [][]any
+	i    int
+	err  error
+}
+
+func (f *fakeRowScanner) Next() bool {
+	return f.i < len(f.rows)
+}
+
+func (f *fakeRowScanner) Scan(dest ...any) error {
+	row := f.rows[f.i]
+	if len(dest) != len(row) {
+		return errors.New("dest length mismatch")
+	}
+	for i := range dest {
+		switch d := dest[i].(type) {
+		case *int64:
+			*d = row[i].(int64)
+		case *string:
+			*d = row[i].(string)
+		default:
+			return errors.New("unsupported dest type")
+		}
+	}
+	f.i++
+	return nil
+}
+
+func (f *fakeRowScanner) Err() error   { return f.err }
+func (f *fakeRowScanner) Close() error { return nil }
+
+type fakeDB struct {
+	QueryFn func(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+func (f *fakeDB) QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error) {
+	return f.QueryFn(ctx, query, args...)
+}
+
+func TestStorageStatsRepository_ReadStorageStats(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if args[0] != "events" {
+				t.Fatalf("expected table arg 'events', got %v", args[0])
+			}
+			if strings.Contains(query, "pg_stat_user_indexes") {
+				return &fakeRowScanner{rows: [][]any{
+					{"ux_events_dedupe", "events", int64(512)},
+					{"idx_events_eventname_time", "events", int64(256)},
+				}}, nil
+			}
+			return &fakeRowScanner{rows: [][]any{
+				{"events", int64(4096), int64(2048), int64(768), int64(100), int64(7), int64(0)},
+			}}, nil
+		},
+	}
+
+	repo := NewStorageStatsRepository(db)
+
+	res, err := repo.ReadStorageStats(context.Background(), []string{"events"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Tables) != 1 {
+		t.Fatalf("expected 1 table, got %d", len(res.Tables))
+	}
+	tbl := res.Tables[0]
+	if tbl.TotalBytes != 4096 || tbl.DeadTuples != 7 || tbl.IndexBytes != 768 {
+		t.Fatalf("unexpected table stats: %+v", tbl)
+	}
+	if len(res.Indexes) != 2 {
+		t.Fatalf("expected 2 indexes, got %d", len(res.Indexes))
+	}
+}
+
+func TestStorageStatsRepository_DBError(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return nil, errors.New("db failure")
+		},
+	}
+
+	repo := NewStorageStatsRepository(db)
+
+	res, err := repo.ReadStorageStats(context.Background(), []string{"events"})
+	if err == nil || err.Error() != "db failure" {
+		t.Fatalf("expected db failure, got %v", err)
+	}
+	if res != nil {
+		t.Fatalf("expected nil result on error")
+	}
+}
@@ -0,0 +1,54 @@
+package prometheus
+
+import (
+	"event-metrics-service/internal/storage/core/domain"
+	"event-metrics-service/internal/storage/core/ports"
+	"event-metrics-service/internal/telemetry"
+)
+
+const (
+	metricTableTotalBytes = "ems_storage_table_total_bytes"
+	metricTableHeapBytes  = "ems_storage_table_heap_bytes"
+	metricTableIndexBytes = "ems_storage_table_index_bytes"
+	metricLiveTuples      = "ems_storage_table_live_tuples"
+	metricDeadTuples      = "ems_storage_table_dead_tuples"
+	metricPartitions      = "ems_storage_table_partitions"
+	metricIndexBytes      = "ems_storage_index_bytes"
+	metricCollectedAt     = "ems_storage_stats_collected_at_seconds"
+)
+
+type StorageStatsPublisher struct {
+	reg *telemetry.Registry
+}
+
+func NewStorageStatsPublisher(reg *telemetry.Registry) *StorageStatsPublisher {
+	return &StorageStatsPublisher{reg: reg}
+}
+
+var _ ports.StorageStatsPublisherPort = (*StorageStatsPublisher)(nil)
+
+func (p *StorageStatsPublisher) PublishStorageStats(s *domain.StorageStats) {
+	for _, name := range []string{
+		metricTableTotalBytes, metricTableHeapBytes, metricTableIndexBytes,
+		metricLiveTuples, metricDeadTuples, metricPartitions, metricIndexBytes,
+	} {
+		p.reg.Reset(name)
+	}
+
+	for _, t := range s.Tables {
+		l := telemetry.Labels{"table": t.Name}
+		p.reg.SetGauge(metricTableTotalBytes, "Total on-disk size of the table including indexes and TOAST.", l, float64(t.TotalBytes))
+		p.reg.SetGauge(metricTableHeapBytes, "On-disk size of the table heap.", l, float64(t.TableBytes))
+		p.reg.SetGauge(metricTableIndexBytes, "Combined on-disk size of the table's indexes.", l, float64(t.IndexBytes))
+		p.reg.SetGauge(metricLiveTuples, "Estimated live tuples.", l, float64(t.LiveTuples))
+		p.reg.SetGauge(metricDeadTuples, "Estimated dead tuples awaiting vacuum.", l, float64(t.DeadTuples))
+		p.reg.SetGauge(metricPartitions, "Number of partitions attached to the table.", l, float64(t.PartitionCount))
+	}
+
+	for _, i := range s.Indexes {
+		l := telemetry.Labels{"index": i.Name, "table": i.Table}
+		p.reg.SetGauge(metricIndexBytes, "On-disk size of the index.", l, float64(i.Bytes))
+	}
+
+	p.reg.SetGauge(metricCollectedAt, "Unix time of the last successful storage stats collection.", nil, float64(s.CollectedAt.Unix()))
+}
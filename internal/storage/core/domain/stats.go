@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// StorageStats is a point-in-time snapshot of how much space the
+// monitored tables occupy and how bloated they are.
+type StorageStats struct {
+	CollectedAt time.Time
+	Tables      []TableStats
+	Indexes     []IndexStats
+}
+
+type TableStats struct {
+	Name           string
+	TotalBytes     int64 // heap + indexes + toast
+	TableBytes     int64 // heap only
+	IndexBytes     int64
+	LiveTuples     int64
+	DeadTuples     int64
+	PartitionCount int64 // 0 for non-partitioned tables
+}
+
+type IndexStats struct {
+	Name  string
+	Table string
+	Bytes int64
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"event-metrics-service/internal/storage/core/domain"
+)
+
+type StorageStatsReaderPort interface {
+	// ReadStorageStats returns size/bloat figures for the given tables
+	// and, for partitioned tables, their partitions.
+	ReadStorageStats(ctx context.Context, tables []string) (*domain.StorageStats, error)
+}
+
+type StorageStatsPublisherPort interface {
+	PublishStorageStats(s *domain.StorageStats)
+}
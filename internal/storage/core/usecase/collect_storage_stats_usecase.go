@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"event-metrics-service/internal/storage/core/ports"
+)
+
+var ErrNoTablesConfigured = errors.New("no tables configured for storage stats")
+
+type CollectStorageStatsUseCase struct {
+	reader    ports.StorageStatsReaderPort
+	publisher ports.StorageStatsPublisherPort
+	tables    []string
+	now       func() time.Time
+}
+
+func NewCollectStorageStatsUseCase(
+	reader ports.StorageStatsReaderPort,
+	publisher ports.StorageStatsPublisherPort,
+	tables []string,
+) *CollectStorageStatsUseCase {
+	return &CollectStorageStatsUseCase{
+		reader:    reader,
+		publisher: publisher,
+		tables:    tables,
+		now:       time.Now,
+	}
+}
+
+// Execute reads a fresh snapshot and hands it to the publisher.
+func (uc *CollectStorageStatsUseCase) Execute(ctx context.Context) error {
+	if len(uc.tables) == 0 {
+		return ErrNoTablesConfigured
+	}
+
+	stats, err := uc.reader.ReadStorageStats(ctx, uc.tables)
+	if err != nil {
+		return err
+	}
+
+	stats.CollectedAt = uc.now().UTC()
+	uc.publisher.PublishStorageStats(stats)
+
+	return nil
+}
+
+// Run collects on every tick until ctx is cancelled. Errors are passed to
+// onError (if set) and never stop the loop.
+func (uc *CollectStorageStatsUseCase) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := uc.Execute(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
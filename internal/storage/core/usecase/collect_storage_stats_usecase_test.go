@@ -0,0 +1,91 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"event-metrics-service/internal/storage/core/domain"
+	"event-metrics-service/internal/storage/core/usecase"
+)
+
+type fakeStatsReader struct {
+	ReadFn     func(ctx context.Context, tables []string) (*domain.StorageStats, error)
+	lastTables []string
+	called     bool
+}
+
+func (f *fakeStatsReader) ReadStorageStats(ctx context.Context, tables []string) (*domain.StorageStats, error) {
+	f.called = true
+	f.lastTables = tables
+	if f.ReadFn != nil {
+		return f.ReadFn(ctx, tables)
+	}
+	return &domain.StorageStats{}, nil
+}
+
+type fakeStatsPublisher struct {
+	published []*domain.StorageStats
+}
+
+func (f *fakeStatsPublisher) PublishStorageStats(s *domain.StorageStats) {
+	f.published = append(f.published, s)
+}
+
+func TestCollectStorageStats_Success(t *testing.T) {
+	reader := &fakeStatsReader{
+		ReadFn: func(ctx context.Context, tables []string) (*domain.StorageStats, error) {
+			return &domain.StorageStats{
+				Tables: []domain.TableStats{{Name: "events", TotalBytes: 4096, DeadTuples: 12}},
+			}, nil
+		},
+	}
+	pub := &fakeStatsPublisher{}
+
+	uc := usecase.NewCollectStorageStatsUseCase(reader, pub, []string{"events"})
+
+	if err := uc.Execute(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(reader.lastTables) != 1 || reader.lastTables[0] != "events" {
+		t.Fatalf("expected tables=[events], got %v", reader.lastTables)
+	}
+	if len(pub.published) != 1 {
+		t.Fatalf("expected 1 publish, got %d", len(pub.published))
+	}
+	if pub.published[0].CollectedAt.IsZero() {
+		t.Fatalf("expected CollectedAt to be set")
+	}
+}
+
+func TestCollectStorageStats_NoTables(t *testing.T) {
+	reader := &fakeStatsReader{}
+	uc := usecase.NewCollectStorageStatsUseCase(reader, &fakeStatsPublisher{}, nil)
+
+	err := uc.Execute(context.Background())
+	if !errors.Is(err, usecase.ErrNoTablesConfigured) {
+		t.Fatalf("expected ErrNoTablesConfigured, got %v", err)
+	}
+	if reader.called {
+		t.Fatalf("reader should not be called without tables")
+	}
+}
+
+func TestCollectStorageStats_ReaderError(t *testing.T) {
+	reader := &fakeStatsReader{
+		ReadFn: func(ctx context.Context, tables []string) (*domain.StorageStats, error) {
+			return nil, errors.New("db failure")
+		},
+	}
+	pub := &fakeStatsPublisher{}
+
+	uc := usecase.NewCollectStorageStatsUseCase(reader, pub, []string{"events"})
+
+	err := uc.Execute(context.Background())
+	if err == nil || err.Error() != "db failure" {
+		t.Fatalf("expected db failure, got %v", err)
+	}
+	if len(pub.published) != 0 {
+		t.Fatalf("expected nothing published on error")
+	}
+}
@@ -0,0 +1,26 @@
+// Package tag holds the normalization events are stored with, so that the
+// tags of queries match the stored ones.
+package tag
+
+import "strings"
+
+// Normalize trims and lowercases every tag, drops empty ones and removes
+// duplicates while keeping the first-seen order.
+func Normalize(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" {
+			continue
+		}
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		out = append(out, t)
+	}
+
+	return out
+}
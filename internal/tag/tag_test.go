@@ -0,0 +1,22 @@
+package tag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	got := Normalize([]string{" Electronics ", "electronics", "SALE", "", "  ", "sale", "New"})
+	want := []string{"electronics", "sale", "new"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestNormalize_Nil(t *testing.T) {
+	got := Normalize(nil)
+	if got == nil || len(got) != 0 {
+		t.Fatalf("expected empty non-nil slice, got %#v", got)
+	}
+}
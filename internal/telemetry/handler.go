@@ -0,0 +1,29 @@
+package telemetry
+
+import (
+	"bytes"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler exposes the registry for Prometheus scraping.
+func (r *Registry) Handler(c *fiber.Ctx) error {
+	var buf bytes.Buffer
+	if err := r.WriteText(&buf); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString(err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, string(textFormat))
+	return c.Send(buf.Bytes())
+}
+
+// OpenMetricsHandler exposes the registry in the OpenMetrics format.
+func (r *Registry) OpenMetricsHandler(c *fiber.Ctx) error {
+	var buf bytes.Buffer
+	if err := r.WriteOpenMetrics(&buf); err != nil {
+		return c.Status(fiber.StatusInternalServerError).SendString(err.Error())
+	}
+
+	c.Set(fiber.HeaderContentType, string(openMetricsFormat))
+	return c.Send(buf.Bytes())
+}
@@ -0,0 +1,163 @@
+package telemetry
+
+import (
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+)
+
+const (
+	KindGauge     = "gauge"
+	KindCounter   = "counter"
+	KindHistogram = "histogram"
+)
+
+// Labels is a set of label name/value pairs attached to a sample.
+type Labels map[string]string
+
+// Registry wraps a Prometheus registry so adapters can publish samples by
+// name without declaring collectors up front. It supports gauges, counters
+// and fixed-bucket histograms.
+//
+// A metric is registered on its first sample: the label names of that sample
+// (and, for histograms, its bucket bounds) apply to the whole metric, and
+// later samples with other label names are dropped.
+type Registry struct {
+	reg *prometheus.Registry
+
+	mu         sync.Mutex
+	gauges     map[string]*prometheus.GaugeVec
+	counters   map[string]*prometheus.CounterVec
+	histograms map[string]*prometheus.HistogramVec
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		reg:        prometheus.NewRegistry(),
+		gauges:     map[string]*prometheus.GaugeVec{},
+		counters:   map[string]*prometheus.CounterVec{},
+		histograms: map[string]*prometheus.HistogramVec{},
+	}
+}
+
+// SetGauge sets the current value of a gauge sample.
+func (r *Registry) SetGauge(name, help string, labels Labels, value float64) {
+	r.mu.Lock()
+	v, ok := r.gauges[name]
+	if !ok {
+		v = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help}, labelNames(labels))
+		v = register(r, v, r.gauges, name)
+	}
+	r.mu.Unlock()
+
+	if g, err := v.GetMetricWith(prometheus.Labels(labels)); err == nil {
+		g.Set(value)
+	}
+}
+
+// AddCounter increments a counter sample by delta.
+func (r *Registry) AddCounter(name, help string, labels Labels, delta float64) {
+	r.mu.Lock()
+	v, ok := r.counters[name]
+	if !ok {
+		v = prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help}, labelNames(labels))
+		v = register(r, v, r.counters, name)
+	}
+	r.mu.Unlock()
+
+	if c, err := v.GetMetricWith(prometheus.Labels(labels)); err == nil && delta >= 0 {
+		c.Add(delta)
+	}
+}
+
+// ObserveHistogram records value in a histogram sample with the given
+// upper bucket bounds (ascending, +Inf is implicit). The bounds of the
+// first observation of the metric are kept.
+func (r *Registry) ObserveHistogram(name, help string, labels Labels, bounds []float64, value float64) {
+	r.mu.Lock()
+	v, ok := r.histograms[name]
+	if !ok {
+		v = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help, Buckets: bounds}, labelNames(labels))
+		v = register(r, v, r.histograms, name)
+	}
+	r.mu.Unlock()
+
+	if h, err := v.GetMetricWith(prometheus.Labels(labels)); err == nil {
+		h.Observe(value)
+	}
+}
+
+// Reset drops every sample of the given metric. Collectors use it before
+// republishing a snapshot so that vanished label sets (e.g. dropped
+// partitions) do not linger.
+func (r *Registry) Reset(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if v, ok := r.gauges[name]; ok {
+		v.Reset()
+	}
+	if v, ok := r.counters[name]; ok {
+		v.Reset()
+	}
+	if v, ok := r.histograms[name]; ok {
+		v.Reset()
+	}
+}
+
+// register adds a new metric vector to the registry and remembers it under
+// name. A vector the registry refuses (e.g. the name is already used by
+// another kind) is still remembered, so its samples are dropped silently
+// instead of being retried on every call.
+func register[V prometheus.Collector](r *Registry, v V, known map[string]V, name string) V {
+	_ = r.reg.Register(v)
+	known[name] = v
+	return v
+}
+
+func labelNames(labels Labels) []string {
+	names := make([]string, 0, len(labels))
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var (
+	textFormat        = expfmt.NewFormat(expfmt.TypeTextPlain)
+	openMetricsFormat = expfmt.NewFormat(expfmt.TypeOpenMetrics)
+)
+
+// WriteText writes all metrics in the Prometheus text format (version 0.0.4).
+func (r *Registry) WriteText(w io.Writer) error {
+	return r.write(w, textFormat)
+}
+
+// WriteOpenMetrics writes all metrics in the OpenMetrics text format (1.0.0):
+// counter families drop the _total suffix, counter samples carry it, and
+// the exposition ends with # EOF.
+func (r *Registry) WriteOpenMetrics(w io.Writer) error {
+	return r.write(w, openMetricsFormat)
+}
+
+func (r *Registry) write(w io.Writer, format expfmt.Format) error {
+	families, err := r.reg.Gather()
+	if err != nil {
+		return err
+	}
+
+	enc := expfmt.NewEncoder(w, format)
+	for _, f := range families {
+		if err := enc.Encode(f); err != nil {
+			return err
+		}
+	}
+	if c, ok := enc.(expfmt.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package telemetry
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRegistry_WriteText_GaugesAndCounters(t *testing.T) {
+	r := NewRegistry()
+
+	r.SetGauge("ems_table_bytes", "Table size", Labels{"table": "events"}, 1024)
+	r.SetGauge("ems_table_bytes", "Table size", Labels{"table": "events"}, 2048)
+	r.AddCounter("ems_events_total", "Events", nil, 1)
+	r.AddCounter("ems_events_total", "Events", nil, 2)
+
+	var buf bytes.Buffer
+	if err := r.WriteText(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+
+	if !strings.Contains(out, "# TYPE ems_table_bytes gauge") {
+		t.Fatalf("expected gauge TYPE line, got:\n%s", out)
+	}
+	if !strings.Contains(out, `ems_table_bytes{table="events"} 2048`) {
+		t.Fatalf("expected overwritten gauge value, got:\n%s", out)
+	}
+	if !strings.Contains(out, "ems_events_total 3") {
+		t.Fatalf("expected accumulated counter value, got:\n%s", out)
+	}
+}
+
+func TestRegistry_Reset(t *testing.T) {
+	r := NewRegistry()
+
+	r.SetGauge("ems_partitions", "Partitions", Labels{"table": "old"}, 1)
+	r.Reset("ems_partitions")
+	r.SetGauge("ems_partitions", "Partitions", Labels{"table": "new"}, 2)
+
+	var buf bytes.Buffer
+	_ = r.WriteText(&buf)
+	out := buf.String()
+
+	if strings.Contains(out, `table="old"`) {
+		t.Fatalf("expected stale sample to be dropped, got:\n%s", out)
+	}
+	if !strings.Contains(out, `ems_partitions{table="new"} 2`) {
+		t.Fatalf("expected new sample, got:\n%s", out)
+	}
+}
+
+func TestRegistry_EscapesLabelValues(t *testing.T) {
+	r := NewRegistry()
+	r.SetGauge("g", "h", Labels{"k": "a\"b\\c"}, 1)
+
+	var buf bytes.Buffer
+	_ = r.WriteText(&buf)
+
+	if !strings.Contains(buf.String(), `g{k="a\"b\\c"} 1`) {
+		t.Fatalf("unexpected escaping: %s", buf.String())
+	}
+}
+
+func TestRegistry_WriteOpenMetrics(t *testing.T) {
+	r := NewRegistry()
+
+	r.AddCounter("ems_events_total", "Events", Labels{"event_name": "order_placed"}, 5)
+	r.SetGauge("ems_up", "Up", nil, 1)
+
+	var buf bytes.Buffer
+	if err := r.WriteOpenMetrics(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"# TYPE ems_events counter\n",
+		`ems_events_total{event_name="order_placed"} 5.0`,
+		"# TYPE ems_up gauge\nems_up 1.0\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Fatalf("expected output to end with # EOF, got:\n%s", out)
+	}
+}
+
+func TestRegistry_Histogram(t *testing.T) {
+	r := NewRegistry()
+
+	bounds := []float64{0.1, 0.5}
+	for _, v := range []float64{0.05, 0.1, 0.3, 2} {
+		r.ObserveHistogram("ems_latency_seconds", "Latency", Labels{"op": "store"}, bounds, v)
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteText(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"# TYPE ems_latency_seconds histogram\n",
+		`ems_latency_seconds_bucket{op="store",le="0.1"} 2`,
+		`ems_latency_seconds_bucket{op="store",le="0.5"} 3`,
+		`ems_latency_seconds_bucket{op="store",le="+Inf"} 4`,
+		`ems_latency_seconds_sum{op="store"} 2.45`,
+		`ems_latency_seconds_count{op="store"} 4`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestRegistry_DropsSamplesWithOtherLabelNames(t *testing.T) {
+	r := NewRegistry()
+
+	r.SetGauge("ems_up", "Up", Labels{"producer": "a"}, 1)
+	r.SetGauge("ems_up", "Up", Labels{"service": "b"}, 1)
+
+	var buf bytes.Buffer
+	if err := r.WriteText(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(buf.String(), `service="b"`) || !strings.Contains(buf.String(), `ems_up{producer="a"} 1`) {
+		t.Fatalf("expected only the first label names to be kept, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,57 @@
+package fiber
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type OnboardTenantRequest struct {
+	ID            string           `json:"id" example:"acme"`
+	Name          string           `json:"name,omitempty" example:"Acme Corp"`
+	RetentionDays int              `json:"retention_days,omitempty" example:"90"`
+	APIKeys       []string         `json:"api_keys,omitempty" example:"web,backend"`
+	Channels      []ChannelRequest `json:"channels,omitempty"`
+	Schemas       []SchemaRequest  `json:"schemas,omitempty"`
+}
+
+type ChannelRequest struct {
+	Name        string `json:"name" example:"web"`
+	Description string `json:"description,omitempty" example:"Marketing website"`
+}
+
+type SchemaRequest struct {
+	EventName string          `json:"event_name" example:"purchase"`
+	Schema    json.RawMessage `json:"schema" swaggertype:"object"`
+}
+
+type OnboardTenantResponse struct {
+	Tenant   TenantResponse    `json:"tenant"`
+	APIKeys  []APIKeyResponse  `json:"api_keys"`
+	Channels []ChannelResponse `json:"channels"`
+	Schemas  []string          `json:"schemas"`
+}
+
+type TenantResponse struct {
+	ID            string    `json:"id" example:"acme"`
+	Name          string    `json:"name" example:"Acme Corp"`
+	RetentionDays int       `json:"retention_days" example:"90"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// APIKeyResponse includes the plaintext secret. It is only returned here and
+// cannot be retrieved later.
+type APIKeyResponse struct {
+	ID     string `json:"id" example:"key_3f2a9c1b7d4e5f60"`
+	Name   string `json:"name" example:"web"`
+	Secret string `json:"secret" example:"ems_5c1e..."`
+}
+
+type ChannelResponse struct {
+	Name        string `json:"name" example:"web"`
+	Description string `json:"description,omitempty" example:"Marketing website"`
+}
+
+type ErrorResponse struct {
+	Error   string `json:"error" example:"invalid_tenant"`
+	Message string `json:"message,omitempty" example:"invalid tenant: duplicate channel \"web\""`
+}
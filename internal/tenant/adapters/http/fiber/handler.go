@@ -0,0 +1,105 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"event-metrics-service/internal/tenant/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type OnboardTenantUseCase interface {
+	Execute(ctx context.Context, in usecase.OnboardTenantInput) (*usecase.OnboardTenantResult, error)
+}
+
+type TenantHandler struct {
+	uc OnboardTenantUseCase
+}
+
+func NewTenantHandler(uc OnboardTenantUseCase) *TenantHandler {
+	return &TenantHandler{uc: uc}
+}
+
+// OnboardTenant godoc
+// @Summary Provision a new tenant
+// @Description Creates the tenant with its API keys, retention policy, channel registry entries and optional
+// @Description metadata schemas in one atomic step. API key secrets are only returned in this response.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param request body OnboardTenantRequest true "Tenant"
+// @Success 201 {object} OnboardTenantResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/tenants [post]
+func (h *TenantHandler) OnboardTenant(c *fiber.Ctx) error {
+	var req OnboardTenantRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error:   "invalid_body",
+			Message: "request body could not be parsed",
+		})
+	}
+
+	in := usecase.OnboardTenantInput{
+		ID:            req.ID,
+		Name:          req.Name,
+		RetentionDays: req.RetentionDays,
+		APIKeyNames:   req.APIKeys,
+	}
+	for _, ch := range req.Channels {
+		in.Channels = append(in.Channels, usecase.ChannelInput{Name: ch.Name, Description: ch.Description})
+	}
+	for _, s := range req.Schemas {
+		in.Schemas = append(in.Schemas, usecase.SchemaInput{EventName: s.EventName, Schema: s.Schema})
+	}
+
+	res, err := h.uc.Execute(c.UserContext(), in)
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrInvalidTenant):
+			return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+				Error:   "invalid_tenant",
+				Message: err.Error(),
+			})
+		case errors.Is(err, usecase.ErrTenantExists):
+			return c.Status(http.StatusConflict).JSON(ErrorResponse{
+				Error:   "tenant_exists",
+				Message: err.Error(),
+			})
+		default:
+			return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+				Error: "internal_server_error",
+			})
+		}
+	}
+
+	resp := OnboardTenantResponse{
+		Tenant: TenantResponse{
+			ID:            res.Tenant.ID,
+			Name:          res.Tenant.Name,
+			RetentionDays: res.Tenant.RetentionDays,
+			CreatedAt:     res.Tenant.CreatedAt,
+		},
+		APIKeys:  make([]APIKeyResponse, 0, len(res.APIKeys)),
+		Channels: make([]ChannelResponse, 0, len(res.Channels)),
+		Schemas:  make([]string, 0, len(res.Schemas)),
+	}
+	for _, k := range res.APIKeys {
+		resp.APIKeys = append(resp.APIKeys, APIKeyResponse{ID: k.ID, Name: k.Name, Secret: k.Secret})
+	}
+	for _, ch := range res.Channels {
+		resp.Channels = append(resp.Channels, ChannelResponse{Name: ch.Name, Description: ch.Description})
+	}
+	for _, s := range res.Schemas {
+		resp.Schemas = append(resp.Schemas, s.EventName)
+	}
+
+	// Secrets must not end up in shared caches.
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(http.StatusCreated).JSON(resp)
+}
@@ -0,0 +1,129 @@
+package fiber_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpadapter "event-metrics-service/internal/tenant/adapters/http/fiber"
+	"event-metrics-service/internal/tenant/core/domain"
+	"event-metrics-service/internal/tenant/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeOnboardUseCase struct {
+	ExecuteFn func(ctx context.Context, in usecase.OnboardTenantInput) (*usecase.OnboardTenantResult, error)
+	lastInput usecase.OnboardTenantInput
+}
+
+func (f *fakeOnboardUseCase) Execute(ctx context.Context, in usecase.OnboardTenantInput) (*usecase.OnboardTenantResult, error) {
+	f.lastInput = in
+	return f.ExecuteFn(ctx, in)
+}
+
+func setupApp(uc httpadapter.OnboardTenantUseCase) *fiber.App {
+	app := fiber.New()
+	app.Post("/admin/tenants", httpadapter.NewTenantHandler(uc).OnboardTenant)
+	return app
+}
+
+func doRequest(t *testing.T, app *fiber.App, body string) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/admin/tenants", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	return resp
+}
+
+func TestOnboardTenant_Created(t *testing.T) {
+	uc := &fakeOnboardUseCase{
+		ExecuteFn: func(ctx context.Context, in usecase.OnboardTenantInput) (*usecase.OnboardTenantResult, error) {
+			return &usecase.OnboardTenantResult{
+				Tenant: domain.Tenant{ID: in.ID, Name: in.Name, RetentionDays: 30},
+				APIKeys: []usecase.IssuedAPIKey{
+					{APIKey: domain.APIKey{ID: "key_1", Name: "web", SecretHash: "hash"}, Secret: "ems_secret"},
+				},
+				Channels: []domain.Channel{{Name: "web"}},
+				Schemas:  []domain.EventSchema{{EventName: "purchase"}},
+			}, nil
+		},
+	}
+	app := setupApp(uc)
+
+	resp := doRequest(t, app, `{
+		"id": "acme", "name": "Acme", "retention_days": 30, "api_keys": ["web"],
+		"channels": [{"name": "web"}],
+		"schemas": [{"event_name": "purchase", "schema": {"type": "object"}}]
+	}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get(fiber.HeaderCacheControl) != "no-store" {
+		t.Fatalf("expected Cache-Control: no-store")
+	}
+
+	if len(uc.lastInput.Schemas) != 1 || string(uc.lastInput.Schemas[0].Schema) != `{"type": "object"}` {
+		t.Fatalf("expected schema to be passed through, got %+v", uc.lastInput.Schemas)
+	}
+
+	var body httpadapter.OnboardTenantResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if body.Tenant.ID != "acme" || len(body.APIKeys) != 1 || body.APIKeys[0].Secret != "ems_secret" {
+		t.Fatalf("unexpected response: %+v", body)
+	}
+	if len(body.Schemas) != 1 || body.Schemas[0] != "purchase" {
+		t.Fatalf("unexpected schemas: %+v", body.Schemas)
+	}
+}
+
+func TestOnboardTenant_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantError  string
+	}{
+		{"invalid", usecase.ErrInvalidTenant, http.StatusBadRequest, "invalid_tenant"},
+		{"exists", usecase.ErrTenantExists, http.StatusConflict, "tenant_exists"},
+		{"internal", context.DeadlineExceeded, http.StatusInternalServerError, "internal_server_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := &fakeOnboardUseCase{
+				ExecuteFn: func(ctx context.Context, in usecase.OnboardTenantInput) (*usecase.OnboardTenantResult, error) {
+					return nil, tt.err
+				},
+			}
+
+			resp := doRequest(t, setupApp(uc), `{"id":"acme"}`)
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, resp.StatusCode)
+			}
+			var body httpadapter.ErrorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("invalid json response: %v", err)
+			}
+			if body.Error != tt.wantError {
+				t.Fatalf("expected %s, got %s", tt.wantError, body.Error)
+			}
+		})
+	}
+}
+
+func TestOnboardTenant_InvalidBody(t *testing.T) {
+	uc := &fakeOnboardUseCase{}
+	resp := doRequest(t, setupApp(uc), `{not json`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+
+	"event-metrics-service/internal/tenant/core/domain"
+	"event-metrics-service/internal/tenant/core/ports"
+)
+
+type TenantRepository struct {
+	db DB
+}
+
+func NewTenantRepository(db DB) *TenantRepository {
+	return &TenantRepository{db: db}
+}
+
+var (
+	_ ports.TenantProvisionerPort = (*TenantRepository)(nil)
+	_ ports.APIKeyTenantPort      = (*TenantRepository)(nil)
+)
+
+const insertTenantSQL = `
+INSERT INTO tenants (id, name, retention_days, created_at)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (id) DO NOTHING`
+
+const insertAPIKeySQL = `
+INSERT INTO tenant_api_keys (id, tenant_id, name, secret_hash, created_at)
+VALUES ($1, $2, $3, $4, $5)`
+
+const insertChannelSQL = `
+INSERT INTO tenant_channels (tenant_id, name, description)
+VALUES ($1, $2, $3)`
+
+const insertEventSchemaSQL = `
+INSERT INTO tenant_event_schemas (tenant_id, event_name, schema)
+VALUES ($1, $2, $3::jsonb)`
+
+const selectTenantBySecretHashSQL = `
+SELECT tenant_id FROM tenant_api_keys WHERE secret_hash = $1`
+
+func (r *TenantRepository) TenantIDForSecretHash(ctx context.Context, secretHash string) (string, bool, error) {
+	rows, err := r.db.QueryContext(ctx, selectTenantBySecretHashSQL, secretHash)
+	if err != nil {
+		return "", false, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return "", false, rows.Err()
+	}
+	var tenantID string
+	if err := rows.Scan(&tenantID); err != nil {
+		return "", false, err
+	}
+	return tenantID, true, nil
+}
+
+func (r *TenantRepository) ProvisionTenant(ctx context.Context, b domain.Bootstrap) (bool, error) {
+	tx, err := r.db.BeginTx(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	created, err := provision(ctx, tx, b)
+	if err != nil || !created {
+		_ = tx.Rollback()
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func provision(ctx context.Context, tx Tx, b domain.Bootstrap) (bool, error) {
+	t := b.Tenant
+	res, err := tx.ExecContext(ctx, insertTenantSQL, t.ID, t.Name, t.RetentionDays, t.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+
+	for _, k := range b.APIKeys {
+		if _, err := tx.ExecContext(ctx, insertAPIKeySQL, k.ID, k.TenantID, k.Name, k.SecretHash, k.CreatedAt); err != nil {
+			return false, err
+		}
+	}
+
+	for _, ch := range b.Channels {
+		if _, err := tx.ExecContext(ctx, insertChannelSQL, ch.TenantID, ch.Name, ch.Description); err != nil {
+			return false, err
+		}
+	}
+
+	for _, s := range b.Schemas {
+		if _, err := tx.ExecContext(ctx, insertEventSchemaSQL, s.TenantID, s.EventName, string(s.Schema)); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
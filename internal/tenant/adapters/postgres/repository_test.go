@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/tenant/core/domain"
+)
+
+type fakeResult struct{ n int64 }
+
+func (r fakeResult) LastInsertId() (int64, error) { return 0, nil }
+func (r fakeResult) RowsAffected() (int64, error) { return r.n, nil }
+
+type fakeTx struct {
+	ExecFn     func(query string, args ...any) (sql.Result, error)
+	queries    []string
+	committed  bool
+	rolledBack bool
+}
+
+func (t *fakeTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	t.queries = append(t.queries, query)
+	if t.ExecFn != nil {
+		return t.ExecFn(query, args...)
+	}
+	return fakeResult{n: 1}, nil
+}
+
+func (t *fakeTx) Commit() error {
+	t.committed = true
+	return nil
+}
+
+func (t *fakeTx) Rollback() error {
+	t.rolledBack = true
+	return nil
+}
+
+type fakeRows struct {
+	values []string
+	i      int
+}
+
+func (r *fakeRows) Next() bool { return r.i < len(r.values) }
+
+func (r *fakeRows) Scan(dest ...any) error {
+	*dest[0].(*string) = r.values[r.i]
+	r.i++
+	return nil
+}
+
+func (r *fakeRows) Err() error   { return nil }
+func (r *fakeRows) Close() error { return nil }
+
+type fakeDB struct {
+	tx   *fakeTx
+	rows map[string][]string // by first arg
+}
+
+func (f *fakeDB) QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error) {
+	return &fakeRows{values: f.rows[args[0].(string)]}, nil
+}
+
+func (f *fakeDB) BeginTx(ctx context.Context) (Tx, error) {
+	return f.tx, nil
+}
+
+func testBootstrap() domain.Bootstrap {
+	now := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	return domain.Bootstrap{
+		Tenant:   domain.Tenant{ID: "acme", Name: "Acme", RetentionDays: 90, CreatedAt: now},
+		APIKeys:  []domain.APIKey{{ID: "key_1", TenantID: "acme", Name: "default", SecretHash: "abc", CreatedAt: now}},
+		Channels: []domain.Channel{{TenantID: "acme", Name: "web"}, {TenantID: "acme", Name: "ios"}},
+		Schemas:  []domain.EventSchema{{TenantID: "acme", EventName: "purchase", Schema: json.RawMessage(`{}`)}},
+	}
+}
+
+func TestTenantRepository_Provision(t *testing.T) {
+	tx := &fakeTx{}
+	repo := NewTenantRepository(&fakeDB{tx: tx})
+
+	created, err := repo.ProvisionTenant(context.Background(), testBootstrap())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !created || !tx.committed || tx.rolledBack {
+		t.Fatalf("expected committed provisioning, created=%v committed=%v", created, tx.committed)
+	}
+
+	// tenant + 1 key + 2 channels + 1 schema
+	if len(tx.queries) != 5 {
+		t.Fatalf("expected 5 statements, got %d", len(tx.queries))
+	}
+	if !strings.Contains(tx.queries[0], "INSERT INTO tenants") {
+		t.Fatalf("expected tenant to be inserted first, got %s", tx.queries[0])
+	}
+}
+
+func TestTenantRepository_ExistingTenant(t *testing.T) {
+	tx := &fakeTx{
+		ExecFn: func(query string, args ...any) (sql.Result, error) {
+			return fakeResult{n: 0}, nil
+		},
+	}
+	repo := NewTenantRepository(&fakeDB{tx: tx})
+
+	created, err := repo.ProvisionTenant(context.Background(), testBootstrap())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created || !tx.rolledBack || len(tx.queries) != 1 {
+		t.Fatalf("expected rollback after tenant conflict, created=%v queries=%d", created, len(tx.queries))
+	}
+}
+
+func TestTenantRepository_RollsBackOnError(t *testing.T) {
+	tx := &fakeTx{
+		ExecFn: func(query string, args ...any) (sql.Result, error) {
+			if strings.Contains(query, "tenant_channels") {
+				return nil, errors.New("db failure")
+			}
+			return fakeResult{n: 1}, nil
+		},
+	}
+	repo := NewTenantRepository(&fakeDB{tx: tx})
+
+	if _, err := repo.ProvisionTenant(context.Background(), testBootstrap()); err == nil {
+		t.Fatalf("expected error")
+	}
+	if tx.committed || !tx.rolledBack {
+		t.Fatalf("expected rollback, committed=%v", tx.committed)
+	}
+}
+
+func TestTenantRepository_TenantIDForSecretHash(t *testing.T) {
+	repo := NewTenantRepository(&fakeDB{rows: map[string][]string{"abc": {"acme"}}})
+
+	tenantID, found, err := repo.TenantIDForSecretHash(context.Background(), "abc")
+	if err != nil || !found || tenantID != "acme" {
+		t.Fatalf("expected acme, got %q found=%v err=%v", tenantID, found, err)
+	}
+
+	_, found, err = repo.TenantIDForSecretHash(context.Background(), "unknown")
+	if err != nil || found {
+		t.Fatalf("expected unknown hash not to be found, found=%v err=%v", found, err)
+	}
+}
@@ -0,0 +1,26 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+)
+
+type sqlDB struct {
+	db *sql.DB
+}
+
+func NewSQLDB(db *sql.DB) DB {
+	return &sqlDB{db: db}
+}
+
+func (s *sqlDB) QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (s *sqlDB) BeginTx(ctx context.Context) (Tx, error) {
+	return s.db.BeginTx(ctx, nil)
+}
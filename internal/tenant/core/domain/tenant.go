@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+type Tenant struct {
+	ID            string
+	Name          string
+	RetentionDays int // events older than this are eligible for purging
+	CreatedAt     time.Time
+}
+
+// APIKey is a stored credential. Only the SHA-256 hash of the secret is
+// kept; the secret itself is shown once, at creation.
+type APIKey struct {
+	ID         string // public identifier, safe to log
+	TenantID   string
+	Name       string
+	SecretHash string
+	CreatedAt  time.Time
+}
+
+// Channel is a channel registered for a tenant.
+type Channel struct {
+	TenantID    string
+	Name        string
+	Description string
+}
+
+// EventSchema is a JSON Schema for the metadata of one event_name.
+type EventSchema struct {
+	TenantID  string
+	EventName string
+	Schema    json.RawMessage
+}
+
+// Bootstrap is everything provisioned for a new tenant in one step.
+type Bootstrap struct {
+	Tenant   Tenant
+	APIKeys  []APIKey
+	Channels []Channel
+	Schemas  []EventSchema
+}
+
+// HashAPIKeySecret returns the hex encoded SHA-256 of secret.
+func HashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+
+	"event-metrics-service/internal/tenant/core/domain"
+)
+
+type TenantProvisionerPort interface {
+	// ProvisionTenant stores the tenant with all of its keys, channels and
+	// schemas atomically. created is false (and nothing is written) when a
+	// tenant with the same ID already exists.
+	ProvisionTenant(ctx context.Context, b domain.Bootstrap) (created bool, err error)
+}
+
+type APIKeyTenantPort interface {
+	// TenantIDForSecretHash returns the tenant owning the API key whose
+	// secret hashes to secretHash. found is false for unknown keys.
+	TenantIDForSecretHash(ctx context.Context, secretHash string) (tenantID string, found bool, err error)
+}
@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"event-metrics-service/internal/tenant/core/domain"
+	"event-metrics-service/internal/tenant/core/ports"
+)
+
+var (
+	ErrInvalidTenant = errors.New("invalid tenant")
+	ErrTenantExists  = errors.New("tenant already exists")
+)
+
+const (
+	DefaultRetentionDays = 90
+	DefaultAPIKeyName    = "default"
+
+	MaxAPIKeysPerTenant   = 10
+	MaxChannelsPerTenant  = 100
+	MaxSchemasPerTenant   = 100
+	MaxChannelLength      = 50  // events.channel
+	MaxEventNameLength    = 100 // events.event_name
+	APIKeySecretPrefix    = "ems_"
+	apiKeySecretByteCount = 32
+	apiKeyIDByteCount     = 8
+)
+
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,62}$`)
+
+type OnboardTenantUseCase struct {
+	repo                 ports.TenantProvisionerPort
+	defaultRetentionDays int
+	random               io.Reader
+	now                  func() time.Time
+}
+
+type Option func(*OnboardTenantUseCase)
+
+// WithDefaultRetentionDays sets the retention used when a request omits it.
+func WithDefaultRetentionDays(days int) Option {
+	return func(uc *OnboardTenantUseCase) {
+		if days > 0 {
+			uc.defaultRetentionDays = days
+		}
+	}
+}
+
+func NewOnboardTenantUseCase(repo ports.TenantProvisionerPort, opts ...Option) *OnboardTenantUseCase {
+	uc := &OnboardTenantUseCase{
+		repo:                 repo,
+		defaultRetentionDays: DefaultRetentionDays,
+		random:               rand.Reader,
+		now:                  time.Now,
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+type ChannelInput struct {
+	Name        string
+	Description string
+}
+
+type SchemaInput struct {
+	EventName string
+	Schema    json.RawMessage
+}
+
+type OnboardTenantInput struct {
+	ID            string
+	Name          string // defaults to ID
+	RetentionDays int    // 0 = default retention
+	APIKeyNames   []string
+	Channels      []ChannelInput
+	Schemas       []SchemaInput // optional
+}
+
+// IssuedAPIKey carries the plaintext secret, which is not stored and cannot
+// be retrieved again.
+type IssuedAPIKey struct {
+	domain.APIKey
+	Secret string
+}
+
+type OnboardTenantResult struct {
+	Tenant   domain.Tenant
+	APIKeys  []IssuedAPIKey
+	Channels []domain.Channel
+	Schemas  []domain.EventSchema
+}
+
+// Execute validates the request, issues API keys and provisions the tenant
+// with its keys, channels and schemas in a single atomic step.
+func (uc *OnboardTenantUseCase) Execute(ctx context.Context, in OnboardTenantInput) (*OnboardTenantResult, error) {
+	tenant, err := uc.buildTenant(in)
+	if err != nil {
+		return nil, err
+	}
+
+	channels, err := buildChannels(tenant.ID, in.Channels)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas, err := buildSchemas(tenant.ID, in.Schemas)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := uc.issueAPIKeys(tenant, in.APIKeyNames)
+	if err != nil {
+		return nil, err
+	}
+
+	b := domain.Bootstrap{
+		Tenant:   tenant,
+		Channels: channels,
+		Schemas:  schemas,
+	}
+	for _, k := range keys {
+		b.APIKeys = append(b.APIKeys, k.APIKey)
+	}
+
+	created, err := uc.repo.ProvisionTenant(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		return nil, ErrTenantExists
+	}
+
+	return &OnboardTenantResult{
+		Tenant:   tenant,
+		APIKeys:  keys,
+		Channels: channels,
+		Schemas:  schemas,
+	}, nil
+}
+
+func (uc *OnboardTenantUseCase) buildTenant(in OnboardTenantInput) (domain.Tenant, error) {
+	id := strings.TrimSpace(in.ID)
+	if !tenantIDPattern.MatchString(id) {
+		return domain.Tenant{}, fmt.Errorf("%w: id must match %s", ErrInvalidTenant, tenantIDPattern)
+	}
+
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		name = id
+	}
+
+	retention := in.RetentionDays
+	switch {
+	case retention < 0:
+		return domain.Tenant{}, fmt.Errorf("%w: retention_days must not be negative", ErrInvalidTenant)
+	case retention == 0:
+		retention = uc.defaultRetentionDays
+	}
+
+	return domain.Tenant{
+		ID:            id,
+		Name:          name,
+		RetentionDays: retention,
+		CreatedAt:     uc.now().UTC(),
+	}, nil
+}
+
+// buildChannels normalizes channel names the way events are matched
+// (trimmed, lowercased) and rejects duplicates.
+func buildChannels(tenantID string, in []ChannelInput) ([]domain.Channel, error) {
+	if len(in) > MaxChannelsPerTenant {
+		return nil, fmt.Errorf("%w: at most %d channels allowed", ErrInvalidTenant, MaxChannelsPerTenant)
+	}
+
+	out := make([]domain.Channel, 0, len(in))
+	seen := make(map[string]struct{}, len(in))
+	for i, ch := range in {
+		name := strings.ToLower(strings.TrimSpace(ch.Name))
+		if name == "" || len(name) > MaxChannelLength {
+			return nil, fmt.Errorf("%w: channels[%d].name must be 1-%d characters", ErrInvalidTenant, i, MaxChannelLength)
+		}
+		if _, ok := seen[name]; ok {
+			return nil, fmt.Errorf("%w: duplicate channel %q", ErrInvalidTenant, name)
+		}
+		seen[name] = struct{}{}
+
+		out = append(out, domain.Channel{
+			TenantID:    tenantID,
+			Name:        name,
+			Description: strings.TrimSpace(ch.Description),
+		})
+	}
+	return out, nil
+}
+
+func buildSchemas(tenantID string, in []SchemaInput) ([]domain.EventSchema, error) {
+	if len(in) > MaxSchemasPerTenant {
+		return nil, fmt.Errorf("%w: at most %d schemas allowed", ErrInvalidTenant, MaxSchemasPerTenant)
+	}
+
+	out := make([]domain.EventSchema, 0, len(in))
+	seen := make(map[string]struct{}, len(in))
+	for i, s := range in {
+		if s.EventName == "" || len(s.EventName) > MaxEventNameLength {
+			return nil, fmt.Errorf("%w: schemas[%d].event_name must be 1-%d characters", ErrInvalidTenant, i, MaxEventNameLength)
+		}
+		if _, ok := seen[s.EventName]; ok {
+			return nil, fmt.Errorf("%w: duplicate schema for %q", ErrInvalidTenant, s.EventName)
+		}
+		seen[s.EventName] = struct{}{}
+
+		var obj map[string]any
+		if err := json.Unmarshal(s.Schema, &obj); err != nil || obj == nil {
+			return nil, fmt.Errorf("%w: schemas[%d].schema must be a JSON object", ErrInvalidTenant, i)
+		}
+
+		out = append(out, domain.EventSchema{
+			TenantID:  tenantID,
+			EventName: s.EventName,
+			Schema:    s.Schema,
+		})
+	}
+	return out, nil
+}
+
+func (uc *OnboardTenantUseCase) issueAPIKeys(tenant domain.Tenant, names []string) ([]IssuedAPIKey, error) {
+	if len(names) == 0 {
+		names = []string{DefaultAPIKeyName}
+	}
+	if len(names) > MaxAPIKeysPerTenant {
+		return nil, fmt.Errorf("%w: at most %d api keys allowed", ErrInvalidTenant, MaxAPIKeysPerTenant)
+	}
+
+	out := make([]IssuedAPIKey, 0, len(names))
+	seen := make(map[string]struct{}, len(names))
+	for i, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: api_keys[%d] must not be empty", ErrInvalidTenant, i)
+		}
+		if _, ok := seen[name]; ok {
+			return nil, fmt.Errorf("%w: duplicate api key name %q", ErrInvalidTenant, name)
+		}
+		seen[name] = struct{}{}
+
+		id, err := uc.randomHex(apiKeyIDByteCount)
+		if err != nil {
+			return nil, err
+		}
+		secret, err := uc.randomHex(apiKeySecretByteCount)
+		if err != nil {
+			return nil, err
+		}
+		secret = APIKeySecretPrefix + secret
+
+		out = append(out, IssuedAPIKey{
+			APIKey: domain.APIKey{
+				ID:         "key_" + id,
+				TenantID:   tenant.ID,
+				Name:       name,
+				SecretHash: domain.HashAPIKeySecret(secret),
+				CreatedAt:  tenant.CreatedAt,
+			},
+			Secret: secret,
+		})
+	}
+	return out, nil
+}
+
+func (uc *OnboardTenantUseCase) randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(uc.random, b); err != nil {
+		return "", fmt.Errorf("generate api key: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
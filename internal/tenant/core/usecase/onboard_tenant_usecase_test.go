@@ -0,0 +1,159 @@
+package usecase_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"event-metrics-service/internal/tenant/core/domain"
+	"event-metrics-service/internal/tenant/core/usecase"
+)
+
+type fakeProvisioner struct {
+	ProvisionFn func(ctx context.Context, b domain.Bootstrap) (bool, error)
+	last        domain.Bootstrap
+	called      bool
+}
+
+func (f *fakeProvisioner) ProvisionTenant(ctx context.Context, b domain.Bootstrap) (bool, error) {
+	f.called = true
+	f.last = b
+	if f.ProvisionFn != nil {
+		return f.ProvisionFn(ctx, b)
+	}
+	return true, nil
+}
+
+// ------------------------------------------------------------
+// SUCCESS
+// ------------------------------------------------------------
+
+func TestOnboardTenant_Success(t *testing.T) {
+	repo := &fakeProvisioner{}
+	uc := usecase.NewOnboardTenantUseCase(repo, usecase.WithDefaultRetentionDays(30))
+
+	res, err := uc.Execute(context.Background(), usecase.OnboardTenantInput{
+		ID:          "acme",
+		Name:        "Acme Corp",
+		APIKeyNames: []string{"web", "backend"},
+		Channels: []usecase.ChannelInput{
+			{Name: " Web ", Description: "website"},
+			{Name: "ios"},
+		},
+		Schemas: []usecase.SchemaInput{
+			{EventName: "purchase", Schema: json.RawMessage(`{"type":"object"}`)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if res.Tenant.ID != "acme" || res.Tenant.Name != "Acme Corp" || res.Tenant.RetentionDays != 30 {
+		t.Fatalf("unexpected tenant: %+v", res.Tenant)
+	}
+	if len(res.APIKeys) != 2 {
+		t.Fatalf("expected 2 api keys, got %d", len(res.APIKeys))
+	}
+	for _, k := range res.APIKeys {
+		if !strings.HasPrefix(k.Secret, usecase.APIKeySecretPrefix) || !strings.HasPrefix(k.ID, "key_") {
+			t.Fatalf("unexpected key format: %+v", k)
+		}
+		if k.SecretHash != domain.HashAPIKeySecret(k.Secret) {
+			t.Fatalf("stored hash does not match the issued secret")
+		}
+	}
+	if res.APIKeys[0].Secret == res.APIKeys[1].Secret {
+		t.Fatalf("expected distinct secrets")
+	}
+	if res.Channels[0].Name != "web" || res.Channels[0].Description != "website" {
+		t.Fatalf("expected normalized channel, got %+v", res.Channels[0])
+	}
+
+	// The repository only ever sees hashes.
+	stored, _ := json.Marshal(repo.last)
+	for _, k := range res.APIKeys {
+		if strings.Contains(string(stored), k.Secret) {
+			t.Fatalf("plaintext secret must not be passed to the repository")
+		}
+	}
+	if len(repo.last.Channels) != 2 || len(repo.last.Schemas) != 1 || repo.last.Schemas[0].TenantID != "acme" {
+		t.Fatalf("unexpected bootstrap: %+v", repo.last)
+	}
+}
+
+func TestOnboardTenant_Defaults(t *testing.T) {
+	uc := usecase.NewOnboardTenantUseCase(&fakeProvisioner{})
+
+	res, err := uc.Execute(context.Background(), usecase.OnboardTenantInput{ID: "team-a"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Tenant.Name != "team-a" || res.Tenant.RetentionDays != usecase.DefaultRetentionDays {
+		t.Fatalf("unexpected defaults: %+v", res.Tenant)
+	}
+	if len(res.APIKeys) != 1 || res.APIKeys[0].Name != usecase.DefaultAPIKeyName {
+		t.Fatalf("expected one default api key, got %+v", res.APIKeys)
+	}
+}
+
+// ------------------------------------------------------------
+// ERRORS
+// ------------------------------------------------------------
+
+func TestOnboardTenant_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		in   usecase.OnboardTenantInput
+	}{
+		{"bad id", usecase.OnboardTenantInput{ID: "Acme Corp"}},
+		{"negative retention", usecase.OnboardTenantInput{ID: "acme", RetentionDays: -1}},
+		{"duplicate channel", usecase.OnboardTenantInput{ID: "acme", Channels: []usecase.ChannelInput{{Name: "web"}, {Name: "WEB"}}}},
+		{"empty channel", usecase.OnboardTenantInput{ID: "acme", Channels: []usecase.ChannelInput{{Name: " "}}}},
+		{"duplicate key name", usecase.OnboardTenantInput{ID: "acme", APIKeyNames: []string{"a", "a"}}},
+		{"schema not an object", usecase.OnboardTenantInput{ID: "acme", Schemas: []usecase.SchemaInput{{EventName: "x", Schema: json.RawMessage(`[1]`)}}}},
+		{"schema without event", usecase.OnboardTenantInput{ID: "acme", Schemas: []usecase.SchemaInput{{Schema: json.RawMessage(`{}`)}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeProvisioner{}
+			uc := usecase.NewOnboardTenantUseCase(repo)
+
+			_, err := uc.Execute(context.Background(), tt.in)
+			if !errors.Is(err, usecase.ErrInvalidTenant) {
+				t.Fatalf("expected ErrInvalidTenant, got %v", err)
+			}
+			if repo.called {
+				t.Fatalf("repository must not be called for invalid input")
+			}
+		})
+	}
+}
+
+func TestOnboardTenant_AlreadyExists(t *testing.T) {
+	repo := &fakeProvisioner{
+		ProvisionFn: func(ctx context.Context, b domain.Bootstrap) (bool, error) {
+			return false, nil
+		},
+	}
+	uc := usecase.NewOnboardTenantUseCase(repo)
+
+	if _, err := uc.Execute(context.Background(), usecase.OnboardTenantInput{ID: "acme"}); !errors.Is(err, usecase.ErrTenantExists) {
+		t.Fatalf("expected ErrTenantExists, got %v", err)
+	}
+}
+
+func TestOnboardTenant_RepositoryError(t *testing.T) {
+	repo := &fakeProvisioner{
+		ProvisionFn: func(ctx context.Context, b domain.Bootstrap) (bool, error) {
+			return false, errors.New("db down")
+		},
+	}
+	uc := usecase.NewOnboardTenantUseCase(repo)
+
+	if _, err := uc.Execute(context.Background(), usecase.OnboardTenantInput{ID: "acme"}); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"event-metrics-service/internal/tenant/core/domain"
+	"event-metrics-service/internal/tenant/core/ports"
+)
+
+const (
+	DefaultTenantKeyCacheTTL = time.Minute
+
+	// maxCachedTenantKeys bounds the cache; unknown keys are cached too, so
+	// without a bound every guessed key would stay in memory.
+	maxCachedTenantKeys = 10000
+)
+
+// ResolveTenantUseCase maps an API key to its tenant. Lookups are cached
+// for ttl, so a revoked key keeps resolving for up to that long.
+type ResolveTenantUseCase struct {
+	keys ports.APIKeyTenantPort
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedTenant // by secret hash
+}
+
+type cachedTenant struct {
+	tenantID  string // "" for unknown keys
+	expiresAt time.Time
+}
+
+type ResolveTenantOption func(*ResolveTenantUseCase)
+
+func WithResolveTenantClock(now func() time.Time) ResolveTenantOption {
+	return func(uc *ResolveTenantUseCase) {
+		uc.now = now
+	}
+}
+
+// NewResolveTenantUseCase creates a resolver caching lookups for ttl; a ttl
+// <= 0 means DefaultTenantKeyCacheTTL.
+func NewResolveTenantUseCase(keys ports.APIKeyTenantPort, ttl time.Duration, opts ...ResolveTenantOption) *ResolveTenantUseCase {
+	if ttl <= 0 {
+		ttl = DefaultTenantKeyCacheTTL
+	}
+	uc := &ResolveTenantUseCase{
+		keys:  keys,
+		ttl:   ttl,
+		now:   time.Now,
+		cache: map[string]cachedTenant{},
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// Execute returns the tenant of apiKey, or "" when no tenant has that key.
+// Only the hash of the key is looked up and kept.
+func (uc *ResolveTenantUseCase) Execute(ctx context.Context, apiKey string) (string, error) {
+	if apiKey == "" {
+		return "", nil
+	}
+	hash := domain.HashAPIKeySecret(apiKey)
+	now := uc.now()
+
+	uc.mu.Lock()
+	entry, ok := uc.cache[hash]
+	uc.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.tenantID, nil
+	}
+
+	tenantID, found, err := uc.keys.TenantIDForSecretHash(ctx, hash)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		tenantID = ""
+	}
+
+	uc.mu.Lock()
+	if len(uc.cache) >= maxCachedTenantKeys {
+		uc.evictExpired(now)
+	}
+	if len(uc.cache) >= maxCachedTenantKeys {
+		clear(uc.cache)
+	}
+	uc.cache[hash] = cachedTenant{tenantID: tenantID, expiresAt: now.Add(uc.ttl)}
+	uc.mu.Unlock()
+
+	return tenantID, nil
+}
+
+func (uc *ResolveTenantUseCase) evictExpired(now time.Time) {
+	for hash, entry := range uc.cache {
+		if !now.Before(entry.expiresAt) {
+			delete(uc.cache, hash)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/tenant/core/domain"
+	"event-metrics-service/internal/tenant/core/usecase"
+)
+
+type fakeKeyLookup struct {
+	tenants map[string]string // by secret hash
+	err     error
+	calls   int
+}
+
+func (f *fakeKeyLookup) TenantIDForSecretHash(ctx context.Context, secretHash string) (string, bool, error) {
+	f.calls++
+	if f.err != nil {
+		return "", false, f.err
+	}
+	tenantID, ok := f.tenants[secretHash]
+	return tenantID, ok, nil
+}
+
+func TestResolveTenant_CachesLookups(t *testing.T) {
+	now := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	keys := &fakeKeyLookup{tenants: map[string]string{domain.HashAPIKeySecret("ems_secret"): "acme"}}
+	uc := usecase.NewResolveTenantUseCase(keys, time.Minute, usecase.WithResolveTenantClock(func() time.Time { return now }))
+
+	for i := 0; i < 2; i++ {
+		tenantID, err := uc.Execute(context.Background(), "ems_secret")
+		if err != nil || tenantID != "acme" {
+			t.Fatalf("expected acme, got %q, %v", tenantID, err)
+		}
+	}
+	// unknown keys are cached as well
+	for i := 0; i < 2; i++ {
+		tenantID, err := uc.Execute(context.Background(), "ems_guess")
+		if err != nil || tenantID != "" {
+			t.Fatalf("expected no tenant, got %q, %v", tenantID, err)
+		}
+	}
+	if keys.calls != 2 {
+		t.Fatalf("expected 2 lookups, got %d", keys.calls)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := uc.Execute(context.Background(), "ems_secret"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keys.calls != 3 {
+		t.Fatalf("expected an expired entry to be looked up again, got %d lookups", keys.calls)
+	}
+}
+
+func TestResolveTenant_Errors(t *testing.T) {
+	keys := &fakeKeyLookup{err: errors.New("db failure")}
+	uc := usecase.NewResolveTenantUseCase(keys, 0)
+
+	if _, err := uc.Execute(context.Background(), "ems_secret"); err == nil {
+		t.Fatalf("expected error")
+	}
+	// failures are not cached
+	keys.err = nil
+	if _, err := uc.Execute(context.Background(), "ems_secret"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keys.calls != 2 {
+		t.Fatalf("expected 2 lookups, got %d", keys.calls)
+	}
+
+	// no key, no lookup
+	if tenantID, err := uc.Execute(context.Background(), ""); err != nil || tenantID != "" || keys.calls != 2 {
+		t.Fatalf("expected empty key to resolve to no tenant without a lookup")
+	}
+}
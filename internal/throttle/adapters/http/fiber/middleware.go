@@ -0,0 +1,51 @@
+package fiber
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"event-metrics-service/internal/throttle/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ErrorResponse struct {
+	Error   string `json:"error" example:"query_concurrency_limited"`
+	Message string `json:"message" example:"too many concurrent queries for this API key"`
+}
+
+// ConcurrencyLimit rejects a request with 429 when the caller (as returned
+// by keyFn) already has the maximum number of requests in flight.
+func ConcurrencyLimit(limiter *usecase.ConcurrencyLimiter, keyFn func(c *fiber.Ctx) string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		release, ok := limiter.TryAcquire(keyFn(c))
+		if !ok {
+			c.Set(fiber.HeaderRetryAfter, "1")
+			return c.Status(http.StatusTooManyRequests).JSON(ErrorResponse{
+				Error:   "query_concurrency_limited",
+				Message: "too many concurrent queries for this API key",
+			})
+		}
+		defer release()
+
+		return c.Next()
+	}
+}
+
+// RateLimit rejects a request with 429 when the caller (as returned by
+// keyFn) has used up its request rate. Retry-After is in whole seconds.
+func RateLimit(limiter *usecase.RateLimiter, keyFn func(c *fiber.Ctx) string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		retryAfter, ok := limiter.Allow(keyFn(c))
+		if !ok {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			return c.Status(http.StatusTooManyRequests).JSON(ErrorResponse{
+				Error:   "rate_limited",
+				Message: "request rate limit exceeded for this API key",
+			})
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,98 @@
+package fiber
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"event-metrics-service/internal/throttle/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestConcurrencyLimit(t *testing.T) {
+	limiter := usecase.NewConcurrencyLimiter(1, nil)
+
+	app := fiber.New()
+	app.Use(ConcurrencyLimit(limiter, func(c *fiber.Ctx) string { return c.Get("X-API-Key") }))
+	app.Get("/metrics", func(c *fiber.Ctx) error { return c.SendStatus(http.StatusOK) })
+
+	// Simulate a query already running for key "a".
+	release, _ := limiter.TryAcquire("a")
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("X-API-Key", "a")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", resp.StatusCode)
+	}
+	var body ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if body.Error != "query_concurrency_limited" {
+		t.Fatalf("expected query_concurrency_limited, got %s", body.Error)
+	}
+
+	// A different key is unaffected.
+	req = httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("X-API-Key", "b")
+	if resp, _ = app.Test(req); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 for other key, got %d", resp.StatusCode)
+	}
+
+	// After release the slot is free again, and the handler releases its own slot.
+	release()
+	for i := 0; i < 2; i++ {
+		req = httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set("X-API-Key", "a")
+		if resp, _ = app.Test(req); resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200 after release, got %d", resp.StatusCode)
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	limiter := usecase.NewRateLimiter(0.5, 1, nil)
+
+	app := fiber.New()
+	app.Use(RateLimit(limiter, func(c *fiber.Ctx) string { return c.Get("X-API-Key") }))
+	app.Post("/events", func(c *fiber.Ctx) error { return c.SendStatus(http.StatusAccepted) })
+
+	send := func(key string) *http.Response {
+		req := httptest.NewRequest(http.MethodPost, "/events", nil)
+		req.Header.Set("X-API-Key", key)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app.Test error: %v", err)
+		}
+		return resp
+	}
+
+	if resp := send("a"); resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", resp.StatusCode)
+	}
+	resp := send("a")
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get(fiber.HeaderRetryAfter); got != "2" {
+		t.Fatalf("expected Retry-After 2, got %q", got)
+	}
+	var body ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if body.Error != "rate_limited" {
+		t.Fatalf("expected rate_limited, got %s", body.Error)
+	}
+
+	// A different key is unaffected.
+	if resp := send("b"); resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected 202 for other key, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import "sync"
+
+// ConcurrencyLimiter caps the number of in-flight operations per key.
+type ConcurrencyLimiter struct {
+	mu         sync.Mutex
+	defaultMax int
+	overrides  map[string]int
+	inflight   map[string]int
+}
+
+// NewConcurrencyLimiter creates a limiter allowing defaultMax concurrent
+// operations per key, with optional per-key overrides. A limit <= 0 means
+// unlimited.
+func NewConcurrencyLimiter(defaultMax int, overrides map[string]int) *ConcurrencyLimiter {
+	if overrides == nil {
+		overrides = map[string]int{}
+	}
+	return &ConcurrencyLimiter{
+		defaultMax: defaultMax,
+		overrides:  overrides,
+		inflight:   map[string]int{},
+	}
+}
+
+// TryAcquire reserves a slot for key. When ok is true the caller must call
+// release exactly once.
+func (l *ConcurrencyLimiter) TryAcquire(key string) (release func(), ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit := l.limitFor(key)
+	if limit > 0 && l.inflight[key] >= limit {
+		return nil, false
+	}
+	l.inflight[key]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+
+			l.inflight[key]--
+			if l.inflight[key] <= 0 {
+				delete(l.inflight, key)
+			}
+		})
+	}, true
+}
+
+// InFlight returns the number of operations currently held for key.
+func (l *ConcurrencyLimiter) InFlight(key string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inflight[key]
+}
+
+func (l *ConcurrencyLimiter) limitFor(key string) int {
+	if v, ok := l.overrides[key]; ok {
+		return v
+	}
+	return l.defaultMax
+}
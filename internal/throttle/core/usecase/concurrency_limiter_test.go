@@ -0,0 +1,56 @@
+package usecase_test
+
+import (
+	"testing"
+
+	"event-metrics-service/internal/throttle/core/usecase"
+)
+
+func TestConcurrencyLimiter_PerKeyCap(t *testing.T) {
+	l := usecase.NewConcurrencyLimiter(2, nil)
+
+	r1, ok := l.TryAcquire("a")
+	if !ok {
+		t.Fatalf("expected first acquire to succeed")
+	}
+	if _, ok := l.TryAcquire("a"); !ok {
+		t.Fatalf("expected second acquire to succeed")
+	}
+	if _, ok := l.TryAcquire("a"); ok {
+		t.Fatalf("expected third acquire to be throttled")
+	}
+
+	// other keys are not affected
+	if _, ok := l.TryAcquire("b"); !ok {
+		t.Fatalf("expected acquire for another key to succeed")
+	}
+
+	r1()
+	r1() // release is idempotent
+	if got := l.InFlight("a"); got != 1 {
+		t.Fatalf("expected 1 in flight after release, got %d", got)
+	}
+	if _, ok := l.TryAcquire("a"); !ok {
+		t.Fatalf("expected acquire after release to succeed")
+	}
+}
+
+func TestConcurrencyLimiter_Overrides(t *testing.T) {
+	l := usecase.NewConcurrencyLimiter(1, map[string]int{"vip": 3, "free": 0})
+
+	for i := 0; i < 3; i++ {
+		if _, ok := l.TryAcquire("vip"); !ok {
+			t.Fatalf("expected vip acquire %d to succeed", i)
+		}
+	}
+	if _, ok := l.TryAcquire("vip"); ok {
+		t.Fatalf("expected vip to be capped at 3")
+	}
+
+	// 0 = unlimited
+	for i := 0; i < 10; i++ {
+		if _, ok := l.TryAcquire("free"); !ok {
+			t.Fatalf("expected unlimited key to succeed")
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often idle buckets are dropped. A bucket that has
+// refilled completely behaves like a new one, so removing it is safe.
+const sweepInterval = time.Minute
+
+// RateLimiter is a token bucket per key: each key earns rate tokens per
+// second up to its burst, and every allowed operation spends one.
+type RateLimiter struct {
+	mu          sync.Mutex
+	defaultRate float64
+	burst       int
+	overrides   map[string]float64
+	buckets     map[string]*bucket
+	lastSweep   time.Time
+	now         func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type RateLimiterOption func(*RateLimiter)
+
+func WithRateLimiterClock(now func() time.Time) RateLimiterOption {
+	return func(l *RateLimiter) {
+		l.now = now
+	}
+}
+
+// NewRateLimiter creates a limiter allowing defaultRate operations per second
+// per key, with optional per-key overrides. A rate <= 0 means unlimited. A
+// key may burst up to burst operations, or its rate rounded up if that is
+// larger, so a key is never held below one second's worth.
+func NewRateLimiter(defaultRate float64, burst int, overrides map[string]float64, opts ...RateLimiterOption) *RateLimiter {
+	if overrides == nil {
+		overrides = map[string]float64{}
+	}
+	l := &RateLimiter{
+		defaultRate: defaultRate,
+		burst:       burst,
+		overrides:   overrides,
+		buckets:     map[string]*bucket{},
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	l.lastSweep = l.now()
+	return l
+}
+
+// Allow spends a token for key. When it returns false, retryAfter is how
+// long until the next token is available.
+func (l *RateLimiter) Allow(key string) (retryAfter time.Duration, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rate := l.rateFor(key)
+	if rate <= 0 {
+		return 0, true
+	}
+	capacity := l.capacityFor(rate)
+	now := l.now()
+	l.sweep(now)
+
+	b, found := l.buckets[key]
+	if !found {
+		b = &bucket{tokens: capacity, last: now}
+		l.buckets[key] = b
+	}
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+elapsed*rate)
+		b.last = now
+	}
+
+	if b.tokens < 1 {
+		wait := (1 - b.tokens) / rate
+		return time.Duration(math.Ceil(wait * float64(time.Second))), false
+	}
+	b.tokens--
+	return 0, true
+}
+
+// Tracked returns the number of keys with a bucket in memory.
+func (l *RateLimiter) Tracked() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
+}
+
+func (l *RateLimiter) rateFor(key string) float64 {
+	if v, ok := l.overrides[key]; ok {
+		return v
+	}
+	return l.defaultRate
+}
+
+func (l *RateLimiter) capacityFor(rate float64) float64 {
+	return math.Max(float64(l.burst), math.Ceil(rate))
+}
+
+// sweep drops the buckets that are full again, keeping memory bounded by
+// the keys active within the last refill period.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		rate := l.rateFor(key)
+		if rate <= 0 || b.tokens+now.Sub(b.last).Seconds()*rate >= l.capacityFor(rate) {
+			delete(l.buckets, key)
+		}
+	}
+}
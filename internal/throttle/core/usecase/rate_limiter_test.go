@@ -0,0 +1,89 @@
+package usecase_test
+
+import (
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/throttle/core/usecase"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func TestRateLimiter_BurstThenRefill(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)}
+	l := usecase.NewRateLimiter(2, 3, nil, usecase.WithRateLimiterClock(clock.now))
+
+	for i := 0; i < 3; i++ {
+		if _, ok := l.Allow("a"); !ok {
+			t.Fatalf("expected request %d within burst to be allowed", i)
+		}
+	}
+	retryAfter, ok := l.Allow("a")
+	if ok {
+		t.Fatalf("expected request over burst to be limited")
+	}
+	if retryAfter != 500*time.Millisecond {
+		t.Fatalf("expected retry after 500ms, got %s", retryAfter)
+	}
+
+	// other keys have their own bucket
+	if _, ok := l.Allow("b"); !ok {
+		t.Fatalf("expected another key to be allowed")
+	}
+
+	clock.advance(500 * time.Millisecond)
+	if _, ok := l.Allow("a"); !ok {
+		t.Fatalf("expected a refilled token to be allowed")
+	}
+	if _, ok := l.Allow("a"); ok {
+		t.Fatalf("expected only one token to have refilled")
+	}
+}
+
+func TestRateLimiter_Overrides(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)}
+	l := usecase.NewRateLimiter(1, 1, map[string]float64{"vip": 5, "free": 0}, usecase.WithRateLimiterClock(clock.now))
+
+	// burst is raised to one second's worth of the override
+	for i := 0; i < 5; i++ {
+		if _, ok := l.Allow("vip"); !ok {
+			t.Fatalf("expected vip request %d to be allowed", i)
+		}
+	}
+	if _, ok := l.Allow("vip"); ok {
+		t.Fatalf("expected vip to be limited after 5 requests")
+	}
+
+	// 0 = unlimited
+	for i := 0; i < 100; i++ {
+		if _, ok := l.Allow("free"); !ok {
+			t.Fatalf("expected unlimited key to be allowed")
+		}
+	}
+	if n := l.Tracked(); n != 1 {
+		t.Fatalf("expected only vip to be tracked, got %d", n)
+	}
+}
+
+func TestRateLimiter_SweepsIdleBuckets(t *testing.T) {
+	clock := &fakeClock{t: time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)}
+	l := usecase.NewRateLimiter(1, 2, nil, usecase.WithRateLimiterClock(clock.now))
+
+	l.Allow("a")
+	l.Allow("b")
+	if n := l.Tracked(); n != 2 {
+		t.Fatalf("expected 2 buckets, got %d", n)
+	}
+
+	clock.advance(2 * time.Minute)
+	l.Allow("c")
+	if n := l.Tracked(); n != 1 {
+		t.Fatalf("expected idle buckets to be dropped, got %d", n)
+	}
+}
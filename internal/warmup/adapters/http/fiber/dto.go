@@ -0,0 +1,21 @@
+package fiber
+
+import "time"
+
+type WarmUpTaskResponse struct {
+	Name       string  `json:"name" example:"metrics:purchase"`
+	DurationMs float64 `json:"duration_ms" example:"182.4"`
+	Error      string  `json:"error,omitempty"`
+}
+
+type WarmUpStatusResponse struct {
+	State      string               `json:"state" example:"ready" enums:"pending,warming,ready"`
+	StartedAt  *time.Time           `json:"started_at,omitempty"`
+	FinishedAt *time.Time           `json:"finished_at,omitempty"`
+	Tasks      []WarmUpTaskResponse `json:"tasks"`
+}
+
+type ErrorResponse struct {
+	Error   string `json:"error" example:"warmup_running"`
+	Message string `json:"message" example:"warm-up already running"`
+}
@@ -0,0 +1,97 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"event-metrics-service/internal/warmup/core/domain"
+	"event-metrics-service/internal/warmup/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type WarmUpUseCase interface {
+	Execute(ctx context.Context) (domain.Status, error)
+	Ready() bool
+	Status() domain.Status
+}
+
+type WarmUpHandler struct {
+	uc WarmUpUseCase
+}
+
+func NewWarmUpHandler(uc WarmUpUseCase) *WarmUpHandler {
+	return &WarmUpHandler{uc: uc}
+}
+
+// Ready godoc
+// @Summary Readiness probe
+// @Description 200 once the startup warm-up (saved queries, dimension values, dedupe claims) has finished,
+// @Description 503 before that. Point load balancer and orchestrator readiness checks here.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} WarmUpStatusResponse
+// @Failure 503 {object} WarmUpStatusResponse
+// @Router /readyz [get]
+func (h *WarmUpHandler) Ready(c *fiber.Ctx) error {
+	status := http.StatusOK
+	if !h.uc.Ready() {
+		status = http.StatusServiceUnavailable
+	}
+	return c.Status(status).JSON(toStatusResponse(h.uc.Status()))
+}
+
+// GetWarmUp godoc
+// @Summary Show the last warm-up run
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} WarmUpStatusResponse
+// @Router /admin/warmup [get]
+func (h *WarmUpHandler) GetWarmUp(c *fiber.Ctx) error {
+	return c.Status(http.StatusOK).JSON(toStatusResponse(h.uc.Status()))
+}
+
+// TriggerWarmUp godoc
+// @Summary Run the warm-up again
+// @Description Re-primes caches, e.g. after a database failover. Waits for the run to finish. The instance stays
+// @Description ready while it runs.
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} WarmUpStatusResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /admin/warmup [post]
+func (h *WarmUpHandler) TriggerWarmUp(c *fiber.Ctx) error {
+	status, err := h.uc.Execute(c.Context())
+	if errors.Is(err, usecase.ErrWarmUpRunning) {
+		return c.Status(http.StatusConflict).JSON(ErrorResponse{
+			Error:   "warmup_running",
+			Message: err.Error(),
+		})
+	}
+	return c.Status(http.StatusOK).JSON(toStatusResponse(status))
+}
+
+func toStatusResponse(s domain.Status) WarmUpStatusResponse {
+	resp := WarmUpStatusResponse{
+		State: string(s.State),
+		Tasks: make([]WarmUpTaskResponse, 0, len(s.Results)),
+	}
+	if !s.StartedAt.IsZero() {
+		resp.StartedAt = &s.StartedAt
+	}
+	if !s.FinishedAt.IsZero() {
+		resp.FinishedAt = &s.FinishedAt
+	}
+	for _, r := range s.Results {
+		t := WarmUpTaskResponse{Name: r.Name, DurationMs: float64(r.Duration) / float64(time.Millisecond)}
+		if r.Err != nil {
+			t.Error = r.Err.Error()
+		}
+		resp.Tasks = append(resp.Tasks, t)
+	}
+	return resp
+}
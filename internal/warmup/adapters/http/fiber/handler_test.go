@@ -0,0 +1,117 @@
+package fiber_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpadapter "event-metrics-service/internal/warmup/adapters/http/fiber"
+	"event-metrics-service/internal/warmup/core/domain"
+	"event-metrics-service/internal/warmup/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeWarmUpUseCase struct {
+	ExecuteFn func(ctx context.Context) (domain.Status, error)
+	status    domain.Status
+}
+
+func (f *fakeWarmUpUseCase) Execute(ctx context.Context) (domain.Status, error) {
+	return f.ExecuteFn(ctx)
+}
+
+func (f *fakeWarmUpUseCase) Ready() bool {
+	return f.status.State == domain.StateReady
+}
+
+func (f *fakeWarmUpUseCase) Status() domain.Status {
+	return f.status
+}
+
+func setupApp(uc httpadapter.WarmUpUseCase) *fiber.App {
+	app := fiber.New()
+	h := httpadapter.NewWarmUpHandler(uc)
+	app.Get("/readyz", h.Ready)
+	app.Get("/admin/warmup", h.GetWarmUp)
+	app.Post("/admin/warmup", h.TriggerWarmUp)
+	return app
+}
+
+func doRequest(t *testing.T, app *fiber.App, method, path string) *http.Response {
+	t.Helper()
+	resp, err := app.Test(httptest.NewRequest(method, path, nil))
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	return resp
+}
+
+func TestReady(t *testing.T) {
+	tests := []struct {
+		state      domain.State
+		wantStatus int
+	}{
+		{domain.StatePending, http.StatusServiceUnavailable},
+		{domain.StateWarming, http.StatusServiceUnavailable},
+		{domain.StateReady, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		uc := &fakeWarmUpUseCase{status: domain.Status{State: tt.state}}
+		resp := doRequest(t, setupApp(uc), http.MethodGet, "/readyz")
+		if resp.StatusCode != tt.wantStatus {
+			t.Fatalf("%s: expected %d, got %d", tt.state, tt.wantStatus, resp.StatusCode)
+		}
+	}
+}
+
+func TestTriggerWarmUp(t *testing.T) {
+	started := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	uc := &fakeWarmUpUseCase{
+		ExecuteFn: func(ctx context.Context) (domain.Status, error) {
+			return domain.Status{
+				State:      domain.StateReady,
+				StartedAt:  started,
+				FinishedAt: started.Add(time.Second),
+				Results: []domain.TaskResult{
+					{Name: "dedupe", Duration: 250 * time.Millisecond},
+					{Name: "metrics:purchase", Err: errors.New("statement timeout")},
+				},
+			}, nil
+		},
+	}
+
+	resp := doRequest(t, setupApp(uc), http.MethodPost, "/admin/warmup")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body httpadapter.WarmUpStatusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if body.State != "ready" || len(body.Tasks) != 2 || body.Tasks[0].DurationMs != 250 {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+	if body.Tasks[1].Error != "statement timeout" {
+		t.Fatalf("expected the task error to be reported, got %+v", body.Tasks[1])
+	}
+}
+
+func TestTriggerWarmUp_AlreadyRunning(t *testing.T) {
+	uc := &fakeWarmUpUseCase{
+		ExecuteFn: func(ctx context.Context) (domain.Status, error) {
+			return domain.Status{State: domain.StateWarming}, usecase.ErrWarmUpRunning
+		},
+	}
+
+	resp := doRequest(t, setupApp(uc), http.MethodPost, "/admin/warmup")
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", resp.StatusCode)
+	}
+}
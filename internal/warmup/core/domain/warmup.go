@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+type State string
+
+const (
+	// StatePending: warm-up has not started; the instance is not ready.
+	StatePending State = "pending"
+	// StateWarming: a warm-up run is in progress.
+	StateWarming State = "warming"
+	// StateReady: the first run finished. Later runs (admin triggered) do
+	// not take the instance out of rotation.
+	StateReady State = "ready"
+)
+
+// TaskResult is the outcome of one warm-up task. Failures are reported but
+// do not block readiness; a cold cache is better than no instance.
+type TaskResult struct {
+	Name     string
+	Duration time.Duration
+	Err      error
+}
+
+type Status struct {
+	State      State
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Results    []TaskResult
+}
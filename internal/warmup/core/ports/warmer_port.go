@@ -0,0 +1,15 @@
+package ports
+
+import "context"
+
+type WarmerPort interface {
+	// Warm runs one priming step, e.g. a saved query or a cache refresh.
+	Warm(ctx context.Context) error
+}
+
+// WarmerFunc adapts a function, e.g. a cache's Refresh method, to WarmerPort.
+type WarmerFunc func(ctx context.Context) error
+
+func (f WarmerFunc) Warm(ctx context.Context) error {
+	return f(ctx)
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"event-metrics-service/internal/warmup/core/domain"
+	"event-metrics-service/internal/warmup/core/ports"
+)
+
+var ErrWarmUpRunning = errors.New("warm-up already running")
+
+// DefaultTimeout bounds a whole warm-up run, so a slow database delays
+// readiness by at most this much.
+const DefaultTimeout = time.Minute
+
+// Task is a named warm-up step.
+type Task struct {
+	Name   string
+	Warmer ports.WarmerPort
+}
+
+// WarmUpUseCase primes caches and database buffers before the instance
+// reports ready, and on demand afterwards.
+type WarmUpUseCase struct {
+	tasks   []Task
+	timeout time.Duration
+	now     func() time.Time
+
+	run    sync.Mutex // held for the duration of a run
+	mu     sync.RWMutex
+	status domain.Status
+}
+
+func NewWarmUpUseCase(tasks []Task, timeout time.Duration) *WarmUpUseCase {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &WarmUpUseCase{
+		tasks:   tasks,
+		timeout: timeout,
+		now:     time.Now,
+		status:  domain.Status{State: domain.StatePending},
+	}
+}
+
+// Execute runs every task in order and returns the final status. Tasks
+// still pending when the timeout expires fail with the context error.
+func (uc *WarmUpUseCase) Execute(ctx context.Context) (domain.Status, error) {
+	if !uc.run.TryLock() {
+		return uc.Status(), ErrWarmUpRunning
+	}
+	defer uc.run.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, uc.timeout)
+	defer cancel()
+
+	uc.mu.Lock()
+	wasReady := uc.status.State == domain.StateReady
+	if !wasReady {
+		uc.status.State = domain.StateWarming
+	}
+	uc.status.StartedAt = uc.now().UTC()
+	uc.status.FinishedAt = time.Time{}
+	uc.status.Results = nil
+	uc.mu.Unlock()
+
+	results := make([]domain.TaskResult, 0, len(uc.tasks))
+	for _, t := range uc.tasks {
+		start := uc.now()
+		err := ctx.Err()
+		if err == nil {
+			err = t.Warmer.Warm(ctx)
+		}
+		results = append(results, domain.TaskResult{Name: t.Name, Duration: uc.now().Sub(start), Err: err})
+	}
+
+	uc.mu.Lock()
+	uc.status.State = domain.StateReady
+	uc.status.FinishedAt = uc.now().UTC()
+	uc.status.Results = results
+	status := uc.status
+	uc.mu.Unlock()
+
+	return status, nil
+}
+
+// Ready reports whether the first warm-up run has finished.
+func (uc *WarmUpUseCase) Ready() bool {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	return uc.status.State == domain.StateReady
+}
+
+func (uc *WarmUpUseCase) Status() domain.Status {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	s := uc.status
+	s.Results = append([]domain.TaskResult(nil), uc.status.Results...)
+	return s
+}
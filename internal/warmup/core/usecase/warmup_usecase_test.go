@@ -0,0 +1,96 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/warmup/core/domain"
+	"event-metrics-service/internal/warmup/core/ports"
+	"event-metrics-service/internal/warmup/core/usecase"
+)
+
+func TestWarmUp_ReadyAfterFirstRun(t *testing.T) {
+	var order []string
+	task := func(name string, err error) usecase.Task {
+		return usecase.Task{Name: name, Warmer: ports.WarmerFunc(func(ctx context.Context) error {
+			order = append(order, name)
+			return err
+		})}
+	}
+	uc := usecase.NewWarmUpUseCase([]usecase.Task{
+		task("dedupe", nil),
+		task("metrics:purchase", errors.New("statement timeout")),
+		task("dimensions", nil),
+	}, time.Second)
+
+	if uc.Ready() || uc.Status().State != domain.StatePending {
+		t.Fatalf("expected pending before the first run, got %+v", uc.Status())
+	}
+
+	status, err := uc.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A failing task is reported but does not keep the instance out of rotation.
+	if !uc.Ready() || status.State != domain.StateReady {
+		t.Fatalf("expected ready, got %+v", status)
+	}
+	if len(order) != 3 || order[0] != "dedupe" || order[2] != "dimensions" {
+		t.Fatalf("expected tasks to run in order, got %v", order)
+	}
+	if status.Results[0].Err != nil || status.Results[1].Err == nil {
+		t.Fatalf("unexpected results: %+v", status.Results)
+	}
+}
+
+func TestWarmUp_TimeoutSkipsRemainingTasks(t *testing.T) {
+	calls := 0
+	slow := ports.WarmerFunc(func(ctx context.Context) error {
+		calls++
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	uc := usecase.NewWarmUpUseCase([]usecase.Task{{Name: "a", Warmer: slow}, {Name: "b", Warmer: slow}}, 10*time.Millisecond)
+
+	status, err := uc.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 || !errors.Is(status.Results[1].Err, context.DeadlineExceeded) {
+		t.Fatalf("expected the second task to be skipped, got calls=%d results=%+v", calls, status.Results)
+	}
+	if !uc.Ready() {
+		t.Fatalf("expected ready after a timed out run")
+	}
+}
+
+func TestWarmUp_RejectsConcurrentRuns(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	uc := usecase.NewWarmUpUseCase([]usecase.Task{{Name: "a", Warmer: ports.WarmerFunc(func(ctx context.Context) error {
+		close(started)
+		<-release
+		return nil
+	})}}, time.Second)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = uc.Execute(context.Background())
+	}()
+	<-started
+
+	status, err := uc.Execute(context.Background())
+	if !errors.Is(err, usecase.ErrWarmUpRunning) {
+		t.Fatalf("expected ErrWarmUpRunning, got %v", err)
+	}
+	if status.State != domain.StateWarming {
+		t.Fatalf("expected warming, got %s", status.State)
+	}
+
+	close(release)
+	<-done
+}
@@ -0,0 +1,34 @@
+package fiber
+
+import "time"
+
+// SubscriptionRequest creates or replaces a webhook subscription.
+type SubscriptionRequest struct {
+	URL        string   `json:"url" example:"https://hooks.example.com/events"`
+	Secret     string   `json:"secret,omitempty"` // generated on create, kept on update when omitted
+	EventNames []string `json:"event_names" example:"purchase"`
+	Channels   []string `json:"channels" example:"web"`
+	Active     *bool    `json:"active,omitempty" example:"true"` // defaults to true
+}
+
+// SubscriptionResponse is a subscription; Secret is only included when it
+// was just set.
+type SubscriptionResponse struct {
+	ID         string    `json:"id" example:"5f1c2d3e-0a9b-4c8d-b7e6-1f2a3b4c5d6e"`
+	URL        string    `json:"url" example:"https://hooks.example.com/events"`
+	Secret     string    `json:"secret,omitempty"`
+	EventNames []string  `json:"event_names"`
+	Channels   []string  `json:"channels"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type SubscriptionListResponse struct {
+	Subscriptions []SubscriptionResponse `json:"subscriptions"`
+}
+
+type ErrorResponse struct {
+	Error   string `json:"error" example:"invalid_subscription"`
+	Message string `json:"message" example:"invalid subscription: url must be an absolute http or https URL"`
+}
@@ -0,0 +1,199 @@
+package fiber
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"event-metrics-service/internal/webhook/core/domain"
+	"event-metrics-service/internal/webhook/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SubscriptionUseCase interface {
+	Create(ctx context.Context, in usecase.SubscriptionInput) (domain.Subscription, error)
+	Update(ctx context.Context, id string, in usecase.SubscriptionInput) (domain.Subscription, error)
+	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id string) (domain.Subscription, error)
+	List(ctx context.Context) ([]domain.Subscription, error)
+}
+
+type WebhookHandler struct {
+	uc SubscriptionUseCase
+}
+
+func NewWebhookHandler(uc SubscriptionUseCase) *WebhookHandler {
+	return &WebhookHandler{uc: uc}
+}
+
+// CreateSubscription godoc
+// @Summary Subscribe a URL to stored events
+// @Description Matching events are POSTed to url, signed with the secret in X-Webhook-Signature.
+// @Description Empty event_names or channels match every value. The secret is only returned here and when it is replaced.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param request body SubscriptionRequest true "Subscription"
+// @Success 201 {object} SubscriptionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/webhooks [post]
+func (h *WebhookHandler) CreateSubscription(c *fiber.Ctx) error {
+	var req SubscriptionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid_json",
+		})
+	}
+
+	s, err := h.uc.Create(c.UserContext(), toSubscriptionInput(req))
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(toSubscriptionResponse(s, true))
+}
+
+// UpdateSubscription godoc
+// @Summary Replace a webhook subscription
+// @Description Omitting secret keeps the current one. Deliveries already queued are sent to the new url.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param id path string true "Subscription ID"
+// @Param request body SubscriptionRequest true "Subscription"
+// @Success 200 {object} SubscriptionResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateSubscription(c *fiber.Ctx) error {
+	var req SubscriptionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{
+			"error": "invalid_json",
+		})
+	}
+
+	s, err := h.uc.Update(c.UserContext(), c.Params("id"), toSubscriptionInput(req))
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.JSON(toSubscriptionResponse(s, req.Secret != ""))
+}
+
+// GetSubscription godoc
+// @Summary Get a webhook subscription
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} SubscriptionResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/webhooks/{id} [get]
+func (h *WebhookHandler) GetSubscription(c *fiber.Ctx) error {
+	s, err := h.uc.Get(c.UserContext(), c.Params("id"))
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	return c.JSON(toSubscriptionResponse(s, false))
+}
+
+// ListSubscriptions godoc
+// @Summary List webhook subscriptions
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Success 200 {object} SubscriptionListResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/webhooks [get]
+func (h *WebhookHandler) ListSubscriptions(c *fiber.Ctx) error {
+	subs, err := h.uc.List(c.UserContext())
+	if err != nil {
+		return writeError(c, err)
+	}
+
+	resp := SubscriptionListResponse{Subscriptions: make([]SubscriptionResponse, 0, len(subs))}
+	for _, s := range subs {
+		resp.Subscriptions = append(resp.Subscriptions, toSubscriptionResponse(s, false))
+	}
+
+	return c.JSON(resp)
+}
+
+// DeleteSubscription godoc
+// @Summary Delete a webhook subscription and its pending deliveries
+// @Tags Admin
+// @Param X-Admin-Token header string true "Admin token"
+// @Param id path string true "Subscription ID"
+// @Success 204
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteSubscription(c *fiber.Ctx) error {
+	if err := h.uc.Delete(c.UserContext(), c.Params("id")); err != nil {
+		return writeError(c, err)
+	}
+
+	return c.SendStatus(http.StatusNoContent)
+}
+
+func writeError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidSubscription):
+		return c.Status(http.StatusBadRequest).JSON(ErrorResponse{
+			Error:   "invalid_subscription",
+			Message: err.Error(),
+		})
+	case errors.Is(err, usecase.ErrSubscriptionNotFound):
+		return c.Status(http.StatusNotFound).JSON(ErrorResponse{
+			Error:   "subscription_not_found",
+			Message: err.Error(),
+		})
+	}
+	return c.Status(http.StatusInternalServerError).JSON(ErrorResponse{
+		Error: "internal_server_error",
+	})
+}
+
+func toSubscriptionInput(req SubscriptionRequest) usecase.SubscriptionInput {
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+	return usecase.SubscriptionInput{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventNames: req.EventNames,
+		Channels:   req.Channels,
+		Active:     active,
+	}
+}
+
+func toSubscriptionResponse(s domain.Subscription, withSecret bool) SubscriptionResponse {
+	resp := SubscriptionResponse{
+		ID:         s.ID,
+		URL:        s.URL,
+		EventNames: s.EventNames,
+		Channels:   s.Channels,
+		Active:     s.Active,
+		CreatedAt:  s.CreatedAt,
+		UpdatedAt:  s.UpdatedAt,
+	}
+	if resp.EventNames == nil {
+		resp.EventNames = []string{}
+	}
+	if resp.Channels == nil {
+		resp.Channels = []string{}
+	}
+	if withSecret {
+		resp.Secret = s.Secret
+	}
+	return resp
+}
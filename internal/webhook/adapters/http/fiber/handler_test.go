@@ -0,0 +1,231 @@
+package fiber_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpadapter "event-metrics-service/internal/webhook/adapters/http/fiber"
+	"event-metrics-service/internal/webhook/core/domain"
+	"event-metrics-service/internal/webhook/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeSubscriptionUseCase struct {
+	CreateFn func(ctx context.Context, in usecase.SubscriptionInput) (domain.Subscription, error)
+	UpdateFn func(ctx context.Context, id string, in usecase.SubscriptionInput) (domain.Subscription, error)
+	DeleteFn func(ctx context.Context, id string) error
+	GetFn    func(ctx context.Context, id string) (domain.Subscription, error)
+	ListFn   func(ctx context.Context) ([]domain.Subscription, error)
+}
+
+func (f *fakeSubscriptionUseCase) Create(ctx context.Context, in usecase.SubscriptionInput) (domain.Subscription, error) {
+	return f.CreateFn(ctx, in)
+}
+
+func (f *fakeSubscriptionUseCase) Update(ctx context.Context, id string, in usecase.SubscriptionInput) (domain.Subscription, error) {
+	return f.UpdateFn(ctx, id, in)
+}
+
+func (f *fakeSubscriptionUseCase) Delete(ctx context.Context, id string) error {
+	return f.DeleteFn(ctx, id)
+}
+
+func (f *fakeSubscriptionUseCase) Get(ctx context.Context, id string) (domain.Subscription, error) {
+	return f.GetFn(ctx, id)
+}
+
+func (f *fakeSubscriptionUseCase) List(ctx context.Context) ([]domain.Subscription, error) {
+	return f.ListFn(ctx)
+}
+
+func setupApp(uc httpadapter.SubscriptionUseCase) *fiber.App {
+	app := fiber.New()
+	h := httpadapter.NewWebhookHandler(uc)
+	app.Get("/admin/webhooks", h.ListSubscriptions)
+	app.Post("/admin/webhooks", h.CreateSubscription)
+	app.Get("/admin/webhooks/:id", h.GetSubscription)
+	app.Put("/admin/webhooks/:id", h.UpdateSubscription)
+	app.Delete("/admin/webhooks/:id", h.DeleteSubscription)
+	return app
+}
+
+func doRequest(t *testing.T, app *fiber.App, method, path, body string) *http.Response {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test error: %v", err)
+	}
+	return resp
+}
+
+func subscription(in usecase.SubscriptionInput) domain.Subscription {
+	now := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	secret := in.Secret
+	if secret == "" {
+		secret = "generated-secret-0123456789"
+	}
+	return domain.Subscription{
+		ID:         "5f1c2d3e-0a9b-4c8d-b7e6-1f2a3b4c5d6e",
+		URL:        in.URL,
+		Secret:     secret,
+		EventNames: in.EventNames,
+		Channels:   in.Channels,
+		Active:     in.Active,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+func TestCreateSubscription_ReturnsSecret(t *testing.T) {
+	var got usecase.SubscriptionInput
+	uc := &fakeSubscriptionUseCase{
+		CreateFn: func(ctx context.Context, in usecase.SubscriptionInput) (domain.Subscription, error) {
+			got = in
+			return subscription(in), nil
+		},
+	}
+
+	resp := doRequest(t, setupApp(uc), http.MethodPost, "/admin/webhooks", `{"url":"https://example.com/hook","event_names":["purchase"]}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	if !got.Active || got.URL != "https://example.com/hook" || len(got.EventNames) != 1 {
+		t.Fatalf("unexpected input: %+v", got)
+	}
+
+	var body httpadapter.SubscriptionResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if body.Secret != "generated-secret-0123456789" || body.Channels == nil {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+}
+
+func TestCreateSubscription_Invalid(t *testing.T) {
+	uc := &fakeSubscriptionUseCase{
+		CreateFn: func(ctx context.Context, in usecase.SubscriptionInput) (domain.Subscription, error) {
+			return domain.Subscription{}, fmt.Errorf("%w: url must be an absolute http or https URL", usecase.ErrInvalidSubscription)
+		},
+	}
+
+	resp := doRequest(t, setupApp(uc), http.MethodPost, "/admin/webhooks", `{"url":"ftp://example.com"}`)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+
+	var body httpadapter.ErrorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if body.Error != "invalid_subscription" || !strings.Contains(body.Message, "url") {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+}
+
+func TestUpdateSubscription_SecretOnlyWhenRotated(t *testing.T) {
+	tests := []struct {
+		body       string
+		wantSecret string
+		wantActive bool
+	}{
+		{`{"url":"https://example.com/hook","active":false}`, "", false},
+		{`{"url":"https://example.com/hook","secret":"rotated-secret-0123456789"}`, "rotated-secret-0123456789", true},
+	}
+
+	for _, tt := range tests {
+		var got usecase.SubscriptionInput
+		uc := &fakeSubscriptionUseCase{
+			UpdateFn: func(ctx context.Context, id string, in usecase.SubscriptionInput) (domain.Subscription, error) {
+				got = in
+				return subscription(in), nil
+			},
+		}
+
+		resp := doRequest(t, setupApp(uc), http.MethodPut, "/admin/webhooks/5f1c2d3e-0a9b-4c8d-b7e6-1f2a3b4c5d6e", tt.body)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", tt.body, resp.StatusCode)
+		}
+		var body httpadapter.SubscriptionResponse
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("invalid json response: %v", err)
+		}
+		if body.Secret != tt.wantSecret || got.Active != tt.wantActive {
+			t.Fatalf("%s: unexpected secret %q active %v", tt.body, body.Secret, got.Active)
+		}
+	}
+}
+
+func TestGetSubscription_HidesSecret(t *testing.T) {
+	uc := &fakeSubscriptionUseCase{
+		GetFn: func(ctx context.Context, id string) (domain.Subscription, error) {
+			return subscription(usecase.SubscriptionInput{URL: "https://example.com/hook", Secret: "stored-secret-0123456789"}), nil
+		},
+	}
+
+	resp := doRequest(t, setupApp(uc), http.MethodGet, "/admin/webhooks/5f1c2d3e-0a9b-4c8d-b7e6-1f2a3b4c5d6e", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var body map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if _, ok := body["secret"]; ok {
+		t.Fatalf("expected no secret, got %v", body)
+	}
+}
+
+func TestListSubscriptions(t *testing.T) {
+	uc := &fakeSubscriptionUseCase{
+		ListFn: func(ctx context.Context) ([]domain.Subscription, error) {
+			return []domain.Subscription{subscription(usecase.SubscriptionInput{URL: "https://example.com/hook"})}, nil
+		},
+	}
+
+	resp := doRequest(t, setupApp(uc), http.MethodGet, "/admin/webhooks", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body httpadapter.SubscriptionListResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("invalid json response: %v", err)
+	}
+	if len(body.Subscriptions) != 1 || body.Subscriptions[0].Secret != "" {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+}
+
+func TestDeleteSubscription(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantStatus int
+	}{
+		{nil, http.StatusNoContent},
+		{usecase.ErrSubscriptionNotFound, http.StatusNotFound},
+		{errors.New("db failure"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		uc := &fakeSubscriptionUseCase{
+			DeleteFn: func(ctx context.Context, id string) error {
+				return tt.err
+			},
+		}
+		resp := doRequest(t, setupApp(uc), http.MethodDelete, "/admin/webhooks/5f1c2d3e-0a9b-4c8d-b7e6-1f2a3b4c5d6e", "")
+		if resp.StatusCode != tt.wantStatus {
+			t.Fatalf("%v: expected %d, got %d", tt.err, tt.wantStatus, resp.StatusCode)
+		}
+	}
+}
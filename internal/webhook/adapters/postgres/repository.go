@@ -0,0 +1,203 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"event-metrics-service/internal/webhook/core/domain"
+	"event-metrics-service/internal/webhook/core/ports"
+
+	"github.com/lib/pq"
+)
+
+type RowScanner interface {
+	Next() bool
+	Scan(dest ...any) error
+	Err() error
+	Close() error
+}
+
+type DB interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+type WebhookRepository struct {
+	db DB
+}
+
+func NewWebhookRepository(db DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+var (
+	_ ports.SubscriptionRepositoryPort = (*WebhookRepository)(nil)
+	_ ports.DeliveryQueuePort          = (*WebhookRepository)(nil)
+)
+
+// ------------------------------------------------------------
+// SUBSCRIPTIONS
+// ------------------------------------------------------------
+
+const insertSubscriptionSQL = `
+INSERT INTO webhook_subscriptions (id, url, secret, event_names, channels, active, created_at, updated_at)
+VALUES ($1, $2, $3, $4::text[], $5::text[], $6, $7, $8)`
+
+const updateSubscriptionSQL = `
+UPDATE webhook_subscriptions
+SET url = $2, secret = $3, event_names = $4::text[], channels = $5::text[], active = $6, updated_at = $7
+WHERE id = $1`
+
+const deleteSubscriptionSQL = `
+DELETE FROM webhook_subscriptions WHERE id = $1`
+
+const selectSubscriptionsSQL = `
+SELECT id, url, secret, event_names, channels, active, created_at, updated_at
+FROM webhook_subscriptions`
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, s domain.Subscription) error {
+	_, err := r.db.ExecContext(ctx, insertSubscriptionSQL,
+		s.ID, s.URL, s.Secret, pq.Array(s.EventNames), pq.Array(s.Channels), s.Active, s.CreatedAt, s.UpdatedAt)
+	return err
+}
+
+func (r *WebhookRepository) UpdateSubscription(ctx context.Context, s domain.Subscription) (bool, error) {
+	res, err := r.db.ExecContext(ctx, updateSubscriptionSQL,
+		s.ID, s.URL, s.Secret, pq.Array(s.EventNames), pq.Array(s.Channels), s.Active, s.UpdatedAt)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, deleteSubscriptionSQL, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+func (r *WebhookRepository) GetSubscription(ctx context.Context, id string) (domain.Subscription, bool, error) {
+	subs, err := r.querySubscriptions(ctx, selectSubscriptionsSQL+"\nWHERE id = $1", id)
+	if err != nil || len(subs) == 0 {
+		return domain.Subscription{}, false, err
+	}
+	return subs[0], true, nil
+}
+
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context) ([]domain.Subscription, error) {
+	return r.querySubscriptions(ctx, selectSubscriptionsSQL+"\nORDER BY created_at, id")
+}
+
+func (r *WebhookRepository) querySubscriptions(ctx context.Context, query string, args ...any) ([]domain.Subscription, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Subscription
+	for rows.Next() {
+		var (
+			s                    domain.Subscription
+			eventNames, channels pq.StringArray
+		)
+		if err := rows.Scan(&s.ID, &s.URL, &s.Secret, &eventNames, &channels, &s.Active, &s.CreatedAt, &s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		s.EventNames, s.Channels = []string(eventNames), []string(channels)
+		s.CreatedAt, s.UpdatedAt = s.CreatedAt.UTC(), s.UpdatedAt.UTC()
+		out = append(out, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ------------------------------------------------------------
+// DELIVERIES
+// ------------------------------------------------------------
+
+const enqueueDeliveriesSQL = `
+INSERT INTO webhook_deliveries (subscription_id, event_row_id, payload)
+SELECT s, e, p::jsonb
+FROM unnest($1::uuid[], $2::bigint[], $3::text[]) AS t(s, e, p)
+ON CONFLICT (subscription_id, event_row_id) DO NOTHING`
+
+// The claim pushes next_attempt_at past the lease, so deliveries of a worker
+// that dies mid-batch become due again. SKIP LOCKED lets the workers of all
+// instances claim side by side.
+const claimDeliveriesSQL = `
+UPDATE webhook_deliveries d
+SET attempts = d.attempts + 1,
+    next_attempt_at = now() + make_interval(secs => $2)
+FROM webhook_subscriptions s
+WHERE s.id = d.subscription_id
+  AND d.id IN (
+    SELECT q.id FROM webhook_deliveries q
+    JOIN webhook_subscriptions qs ON qs.id = q.subscription_id
+    WHERE qs.active AND q.next_attempt_at <= now()
+    ORDER BY q.next_attempt_at, q.id
+    LIMIT $1
+    FOR UPDATE OF q SKIP LOCKED
+  )
+RETURNING d.id, d.subscription_id, d.event_row_id, d.payload, d.attempts, s.url, s.secret`
+
+const removeDeliverySQL = `
+DELETE FROM webhook_deliveries WHERE id = $1`
+
+const retryDeliverySQL = `
+UPDATE webhook_deliveries SET next_attempt_at = $2, last_error = $3 WHERE id = $1`
+
+func (r *WebhookRepository) EnqueueDeliveries(ctx context.Context, ds []domain.Delivery) error {
+	subs := make([]string, len(ds))
+	events := make([]int64, len(ds))
+	payloads := make([]string, len(ds))
+	for i, d := range ds {
+		subs[i], events[i], payloads[i] = d.SubscriptionID, d.EventRowID, string(d.Payload)
+	}
+	_, err := r.db.ExecContext(ctx, enqueueDeliveriesSQL, pq.Array(subs), pq.Array(events), pq.Array(payloads))
+	return err
+}
+
+func (r *WebhookRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.Delivery, error) {
+	rows, err := r.db.QueryContext(ctx, claimDeliveriesSQL, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Delivery
+	for rows.Next() {
+		var d domain.Delivery
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventRowID, &d.Payload, &d.Attempts, &d.URL, &d.Secret); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *WebhookRepository) RemoveDelivery(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx, removeDeliverySQL, id)
+	return err
+}
+
+func (r *WebhookRepository) RetryDelivery(ctx context.Context, id int64, at time.Time, lastError string) error {
+	_, err := r.db.ExecContext(ctx, retryDeliverySQL, id, at, lastError)
+	return err
+}
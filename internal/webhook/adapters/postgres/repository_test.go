@@ -0,0 +1,206 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/webhook/core/domain"
+
+	"github.com/lib/pq"
+)
+
+type fakeRowScanner struct {
+	rows [][]any
+	i    int
+}
+
+func (f *fakeRowScanner) Next() bool {
+	return f.i < len(f.rows)
+}
+
+func (f *fakeRowScanner) Scan(dest ...any) error {
+	row := f.rows[f.i]
+	if len(dest) != len(row) {
+		return errors.New("dest length mismatch")
+	}
+	for i := range dest {
+		switch d := dest[i].(type) {
+		case *[]byte:
+			*d = row[i].([]byte)
+		case *string:
+			*d = row[i].(string)
+		case *bool:
+			*d = row[i].(bool)
+		case *int:
+			*d = row[i].(int)
+		case *int64:
+			*d = row[i].(int64)
+		case *pq.StringArray:
+			*d = row[i].(pq.StringArray)
+		case *time.Time:
+			*d = row[i].(time.Time)
+		default:
+			return errors.New("unsupported dest type")
+		}
+	}
+	f.i++
+	return nil
+}
+
+func (f *fakeRowScanner) Err() error   { return nil }
+func (f *fakeRowScanner) Close() error { return nil }
+
+type fakeResult struct{ n int64 }
+
+func (r fakeResult) LastInsertId() (int64, error) { return 0, nil }
+func (r fakeResult) RowsAffected() (int64, error) { return r.n, nil }
+
+type fakeDB struct {
+	ExecFn  func(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryFn func(ctx context.Context, query string, args ...any) (RowScanner, error)
+}
+
+func (f *fakeDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return f.ExecFn(ctx, query, args...)
+}
+
+func (f *fakeDB) QueryContext(ctx context.Context, query string, args ...any) (RowScanner, error) {
+	return f.QueryFn(ctx, query, args...)
+}
+
+// ------------------------------------------------------------
+// SUBSCRIPTIONS
+// ------------------------------------------------------------
+
+func TestWebhookRepository_UpdateMissing(t *testing.T) {
+	db := &fakeDB{
+		ExecFn: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+			if !strings.Contains(query, "UPDATE webhook_subscriptions") {
+				t.Fatalf("unexpected query: %s", query)
+			}
+			return fakeResult{n: 0}, nil
+		},
+	}
+
+	found, err := NewWebhookRepository(db).UpdateSubscription(context.Background(), domain.Subscription{ID: "s1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if found {
+		t.Fatalf("expected found=false")
+	}
+}
+
+func TestWebhookRepository_Get(t *testing.T) {
+	created := time.Date(2025, 12, 7, 10, 0, 0, 0, time.FixedZone("TRT", 3*3600))
+	var gotQuery string
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			gotQuery = query
+			return &fakeRowScanner{rows: [][]any{
+				{"s1", "https://example.com/hook", "secret", pq.StringArray{"purchase"}, pq.StringArray{}, true, created, created},
+			}}, nil
+		},
+	}
+
+	s, found, err := NewWebhookRepository(db).GetSubscription(context.Background(), "s1")
+	if err != nil || !found {
+		t.Fatalf("expected subscription, got found=%v err=%v", found, err)
+	}
+	if !strings.Contains(gotQuery, "WHERE id = $1") {
+		t.Fatalf("unexpected query: %s", gotQuery)
+	}
+	if s.URL != "https://example.com/hook" || len(s.EventNames) != 1 || len(s.Channels) != 0 || !s.Active {
+		t.Fatalf("unexpected subscription: %+v", s)
+	}
+	if s.CreatedAt.Location() != time.UTC {
+		t.Fatalf("expected UTC timestamps")
+	}
+}
+
+func TestWebhookRepository_GetMissing(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return &fakeRowScanner{}, nil
+		},
+	}
+
+	_, found, err := NewWebhookRepository(db).GetSubscription(context.Background(), "s1")
+	if err != nil || found {
+		t.Fatalf("expected not found, got found=%v err=%v", found, err)
+	}
+}
+
+// ------------------------------------------------------------
+// DELIVERIES
+// ------------------------------------------------------------
+
+func TestWebhookRepository_EnqueueDeliveries(t *testing.T) {
+	var gotArgs []any
+	db := &fakeDB{
+		ExecFn: func(ctx context.Context, query string, args ...any) (sql.Result, error) {
+			if !strings.Contains(query, "ON CONFLICT (subscription_id, event_row_id) DO NOTHING") {
+				t.Fatalf("expected idempotent insert, got %s", query)
+			}
+			gotArgs = args
+			return fakeResult{n: 2}, nil
+		},
+	}
+
+	err := NewWebhookRepository(db).EnqueueDeliveries(context.Background(), []domain.Delivery{
+		{SubscriptionID: "s1", EventRowID: 1, Payload: []byte(`{"id":1}`)},
+		{SubscriptionID: "s2", EventRowID: 1, Payload: []byte(`{"id":1}`)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(gotArgs) != 3 {
+		t.Fatalf("expected 3 array args, got %d", len(gotArgs))
+	}
+	if v, _ := gotArgs[0].(driver.Valuer).Value(); v != `{"s1","s2"}` {
+		t.Fatalf("unexpected subscription ids %v", v)
+	}
+}
+
+func TestWebhookRepository_ClaimDeliveries(t *testing.T) {
+	var gotArgs []any
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			if !strings.Contains(query, "SKIP LOCKED") || !strings.Contains(query, "qs.active") {
+				t.Fatalf("unexpected claim query: %s", query)
+			}
+			gotArgs = args
+			return &fakeRowScanner{rows: [][]any{
+				{int64(7), "s1", int64(42), []byte(`{"id":42}`), 2, "https://example.com/hook", "secret"},
+			}}, nil
+		},
+	}
+
+	ds, err := NewWebhookRepository(db).ClaimDeliveries(context.Background(), 10, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotArgs[0] != 10 || gotArgs[1] != 300.0 {
+		t.Fatalf("unexpected args: %v", gotArgs)
+	}
+	if len(ds) != 1 || ds[0].ID != 7 || ds[0].EventRowID != 42 || ds[0].Attempts != 2 || ds[0].Secret != "secret" {
+		t.Fatalf("unexpected deliveries: %+v", ds)
+	}
+}
+
+func TestWebhookRepository_ClaimError(t *testing.T) {
+	db := &fakeDB{
+		QueryFn: func(ctx context.Context, query string, args ...any) (RowScanner, error) {
+			return nil, errors.New("db failure")
+		},
+	}
+
+	if _, err := NewWebhookRepository(db).ClaimDeliveries(context.Background(), 10, time.Minute); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,66 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"event-metrics-service/internal/webhook/core/ports"
+)
+
+const DefaultTimeout = 10 * time.Second
+
+// HTTPSender POSTs webhook payloads as JSON. Redirects are not followed, so
+// a subscriber moving its endpoint has to update the subscription.
+type HTTPSender struct {
+	http *http.Client
+}
+
+type Option func(*HTTPSender)
+
+func WithTimeout(d time.Duration) Option {
+	return func(s *HTTPSender) {
+		s.http.Timeout = d
+	}
+}
+
+func NewHTTPSender(opts ...Option) *HTTPSender {
+	s := &HTTPSender{http: &http.Client{
+		Timeout: DefaultTimeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+var _ ports.SenderPort = (*HTTPSender)(nil)
+
+func (s *HTTPSender) Send(ctx context.Context, url string, headers map[string]string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Drain a little of the body so the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("webhook %s: %s", url, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package sender
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPSender_Send(t *testing.T) {
+	var gotBody, gotType, gotSig string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST, got %s", r.Method)
+		}
+		b, _ := io.ReadAll(r.Body)
+		gotBody, gotType, gotSig = string(b), r.Header.Get("Content-Type"), r.Header.Get("X-Webhook-Signature")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	err := NewHTTPSender().Send(context.Background(), srv.URL, map[string]string{"X-Webhook-Signature": "sha256=ab"}, []byte(`{"id":1}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotBody != `{"id":1}` || gotType != "application/json" || gotSig != "sha256=ab" {
+		t.Fatalf("unexpected request: body=%s type=%s sig=%s", gotBody, gotType, gotSig)
+	}
+}
+
+func TestHTTPSender_NonSuccessStatus(t *testing.T) {
+	for _, status := range []int{http.StatusFound, http.StatusGone, http.StatusServiceUnavailable} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Location", "/elsewhere")
+			w.WriteHeader(status)
+		}))
+
+		err := NewHTTPSender().Send(context.Background(), srv.URL, nil, []byte(`{}`))
+		if err == nil || !strings.Contains(err.Error(), http.StatusText(status)) {
+			t.Fatalf("status %d: expected error, got %v", status, err)
+		}
+		srv.Close()
+	}
+}
+
+func TestHTTPSender_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	if err := NewHTTPSender(WithTimeout(50*time.Millisecond)).Send(context.Background(), srv.URL, nil, []byte(`{}`)); err == nil {
+		t.Fatalf("expected timeout error")
+	}
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Subscription receives the stored events matching its filter at URL.
+type Subscription struct {
+	ID         string
+	URL        string
+	Secret     string   // HMAC key of the delivery signature
+	EventNames []string // empty matches every event name
+	Channels   []string // empty matches every channel
+	Active     bool     // inactive subscriptions keep their queue but get no deliveries
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Matches reports whether an event is delivered to the subscription.
+func (s Subscription) Matches(e Event) bool {
+	return s.Active &&
+		(len(s.EventNames) == 0 || slices.Contains(s.EventNames, e.EventName)) &&
+		(len(s.Channels) == 0 || slices.Contains(s.Channels, e.Channel))
+}
+
+// Event is a stored event as delivered to subscribers.
+type Event struct {
+	ID          int64 // events.id
+	EventID     string
+	EventName   string
+	Channel     string
+	CampaignID  string
+	UserID      string
+	AnonymousID string
+	EventTime   time.Time
+	ReceivedAt  time.Time
+	Value       *float64
+	Tags        []string
+	Metadata    map[string]any
+	DedupeKey   string
+	TenantID    string
+}
+
+// Delivery is one event queued for one subscription. URL and Secret are
+// those of the subscription when the delivery was claimed.
+type Delivery struct {
+	ID             int64
+	SubscriptionID string
+	EventRowID     int64
+	Payload        []byte
+	Attempts       int // including the one in progress
+	URL            string
+	Secret         string
+}
+
+// DedupeID is the X-Webhook-Id value. It names the subscription and event
+// rather than the queue entry, so it stays the same if the event is queued
+// again after a relay retry.
+func (d Delivery) DedupeID() string {
+	return d.SubscriptionID + "." + strconv.FormatInt(d.EventRowID, 10)
+}
+
+// Delivery request headers.
+const (
+	HeaderDeliveryID = "X-Webhook-Id"
+	HeaderTimestamp  = "X-Webhook-Timestamp"
+	HeaderSignature  = "X-Webhook-Signature"
+)
+
+// Sign returns the X-Webhook-Signature value for a payload sent at ts:
+// "sha256=" and the hex HMAC-SHA256 of "<unix seconds>.<payload>" keyed
+// with the subscription secret. Receivers should reject old timestamps to
+// stop replays.
+func Sign(secret string, ts time.Time, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(ts.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
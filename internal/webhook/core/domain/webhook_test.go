@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSubscription_Matches(t *testing.T) {
+	purchaseWeb := Event{EventName: "purchase", Channel: "web"}
+
+	tests := []struct {
+		name string
+		sub  Subscription
+		want bool
+	}{
+		{name: "no filter", sub: Subscription{Active: true}, want: true},
+		{name: "event name listed", sub: Subscription{Active: true, EventNames: []string{"signup", "purchase"}}, want: true},
+		{name: "event name not listed", sub: Subscription{Active: true, EventNames: []string{"signup"}}, want: false},
+		{name: "both filters match", sub: Subscription{Active: true, EventNames: []string{"purchase"}, Channels: []string{"web"}}, want: true},
+		{name: "channel not listed", sub: Subscription{Active: true, EventNames: []string{"purchase"}, Channels: []string{"ios"}}, want: false},
+		{name: "inactive", sub: Subscription{}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sub.Matches(purchaseWeb); got != tt.want {
+				t.Fatalf("Matches = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	ts := time.Unix(1733572800, 0)
+
+	// printf '1733572800.{"id":1}' | openssl dgst -sha256 -hmac secret
+	want := "sha256=f5770c4d0c9d15246bfb9b058ba31f25e29e798d60e5df8d84838d212bf62dca"
+	got := Sign("secret", ts, []byte(`{"id":1}`))
+	if got != want {
+		t.Fatalf("Sign = %q, want %q", got, want)
+	}
+	if Sign("other", ts, []byte(`{"id":1}`)) == got || Sign("secret", ts.Add(time.Second), []byte(`{"id":1}`)) == got {
+		t.Fatalf("expected the secret and timestamp to change the signature")
+	}
+}
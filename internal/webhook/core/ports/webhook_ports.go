@@ -0,0 +1,40 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"event-metrics-service/internal/webhook/core/domain"
+)
+
+type SubscriptionRepositoryPort interface {
+	CreateSubscription(ctx context.Context, s domain.Subscription) error
+	// UpdateSubscription replaces the subscription with s.ID; found is false
+	// when there is none.
+	UpdateSubscription(ctx context.Context, s domain.Subscription) (found bool, err error)
+	// DeleteSubscription also drops its queued deliveries. It returns false
+	// when no subscription had the id.
+	DeleteSubscription(ctx context.Context, id string) (bool, error)
+	GetSubscription(ctx context.Context, id string) (s domain.Subscription, found bool, err error)
+	ListSubscriptions(ctx context.Context) ([]domain.Subscription, error)
+}
+
+// DeliveryQueuePort keeps deliveries until they succeed or are given up.
+type DeliveryQueuePort interface {
+	// EnqueueDeliveries queues deliveries; one already queued for the same
+	// subscription and event is skipped, so enqueuing again is harmless.
+	EnqueueDeliveries(ctx context.Context, ds []domain.Delivery) error
+	// ClaimDeliveries returns up to limit due deliveries of active
+	// subscriptions, counts the attempt and hides them from other claims for
+	// lease, so a crashed worker's deliveries are retried afterwards.
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.Delivery, error)
+	// RemoveDelivery drops a delivery that succeeded or was given up.
+	RemoveDelivery(ctx context.Context, id int64) error
+	// RetryDelivery schedules the next attempt.
+	RetryDelivery(ctx context.Context, id int64, at time.Time, lastError string) error
+}
+
+// SenderPort POSTs a payload. A non-2xx response is an error.
+type SenderPort interface {
+	Send(ctx context.Context, url string, headers map[string]string, payload []byte) error
+}
@@ -0,0 +1,258 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"event-metrics-service/internal/webhook/core/domain"
+	"event-metrics-service/internal/webhook/core/ports"
+)
+
+var ErrDeliveryGivenUp = errors.New("webhook delivery given up")
+
+const (
+	DefaultDeliveryBatchSize   = 100
+	DefaultDeliveryConcurrency = 8
+	DefaultMaxAttempts         = 8
+	DefaultRetryBackoff        = 10 * time.Second
+	MaxRetryBackoff            = time.Hour
+
+	// deliveryLease hides claimed deliveries from other workers. It must
+	// outlast a batch: batch size / concurrency * sender timeout.
+	deliveryLease = 5 * time.Minute
+)
+
+// DispatchUseCase fans stored events out to matching subscriptions and
+// delivers them with retries. Deliveries are queued before they are sent, so
+// they survive restarts; a receiver may see one twice and should deduplicate
+// by the X-Webhook-Id header.
+type DispatchUseCase struct {
+	subs        ports.SubscriptionRepositoryPort
+	queue       ports.DeliveryQueuePort
+	sender      ports.SenderPort
+	batchSize   int
+	concurrency int
+	maxAttempts int
+	backoff     time.Duration
+	now         func() time.Time
+}
+
+type DispatchOption func(*DispatchUseCase)
+
+// WithRetries gives up on a delivery after maxAttempts; attempt n+1 waits
+// backoff * 2^(n-1), at most MaxRetryBackoff.
+func WithRetries(maxAttempts int, backoff time.Duration) DispatchOption {
+	return func(uc *DispatchUseCase) {
+		if maxAttempts > 0 {
+			uc.maxAttempts = maxAttempts
+		}
+		if backoff > 0 {
+			uc.backoff = backoff
+		}
+	}
+}
+
+// WithConcurrency caps the requests in flight per batch.
+func WithConcurrency(n int) DispatchOption {
+	return func(uc *DispatchUseCase) {
+		if n > 0 {
+			uc.concurrency = n
+		}
+	}
+}
+
+func NewDispatchUseCase(subs ports.SubscriptionRepositoryPort, queue ports.DeliveryQueuePort, sender ports.SenderPort, opts ...DispatchOption) *DispatchUseCase {
+	uc := &DispatchUseCase{
+		subs:        subs,
+		queue:       queue,
+		sender:      sender,
+		batchSize:   DefaultDeliveryBatchSize,
+		concurrency: DefaultDeliveryConcurrency,
+		maxAttempts: DefaultMaxAttempts,
+		backoff:     DefaultRetryBackoff,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// eventPayload is the delivery body, in the shape of GET /events.
+type eventPayload struct {
+	ID          int64          `json:"id"`
+	EventID     string         `json:"event_id,omitempty"`
+	EventName   string         `json:"event_name"`
+	Channel     string         `json:"channel"`
+	CampaignID  string         `json:"campaign_id,omitempty"`
+	UserID      string         `json:"user_id"`
+	AnonymousID string         `json:"anonymous_id,omitempty"`
+	EventTime   time.Time      `json:"event_time"`
+	ReceivedAt  time.Time      `json:"received_at"`
+	Value       *float64       `json:"value,omitempty"`
+	Tags        []string       `json:"tags"`
+	Metadata    map[string]any `json:"metadata"`
+	DedupeKey   string         `json:"dedupe_key"`
+	TenantID    string         `json:"tenant_id,omitempty"`
+}
+
+func encodePayload(e domain.Event) ([]byte, error) {
+	tags := e.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	return json.Marshal(eventPayload{
+		ID:          e.ID,
+		EventID:     e.EventID,
+		EventName:   e.EventName,
+		Channel:     e.Channel,
+		CampaignID:  e.CampaignID,
+		UserID:      e.UserID,
+		AnonymousID: e.AnonymousID,
+		EventTime:   e.EventTime,
+		ReceivedAt:  e.ReceivedAt,
+		Value:       e.Value,
+		Tags:        tags,
+		Metadata:    e.Metadata,
+		DedupeKey:   e.DedupeKey,
+		TenantID:    e.TenantID,
+	})
+}
+
+// Enqueue queues a delivery of every event to each active subscription it
+// matches.
+func (uc *DispatchUseCase) Enqueue(ctx context.Context, events []domain.Event) error {
+	subs, err := uc.subs.ListSubscriptions(ctx)
+	if err != nil {
+		return fmt.Errorf("list subscriptions: %w", err)
+	}
+
+	var ds []domain.Delivery
+	for _, e := range events {
+		var payload []byte
+		for _, s := range subs {
+			if !s.Matches(e) {
+				continue
+			}
+			if payload == nil {
+				if payload, err = encodePayload(e); err != nil {
+					return fmt.Errorf("encode event %d: %w", e.ID, err)
+				}
+			}
+			ds = append(ds, domain.Delivery{SubscriptionID: s.ID, EventRowID: e.ID, Payload: payload})
+		}
+	}
+	if len(ds) == 0 {
+		return nil
+	}
+	return uc.queue.EnqueueDeliveries(ctx, ds)
+}
+
+// DeliveryReport counts the outcome of a Deliver run.
+type DeliveryReport struct {
+	Delivered int
+	Retried   int // failed, scheduled again
+	GivenUp   int // failed for the last time and dropped
+}
+
+// Deliver sends due deliveries batch by batch until a short batch signals
+// none are left. Given up deliveries are reported in the returned error as
+// ErrDeliveryGivenUp.
+func (uc *DispatchUseCase) Deliver(ctx context.Context) (DeliveryReport, error) {
+	var (
+		report DeliveryReport
+		errs   []error
+	)
+	for {
+		ds, err := uc.queue.ClaimDeliveries(ctx, uc.batchSize, deliveryLease)
+		if err != nil {
+			return report, errors.Join(append(errs, err)...)
+		}
+
+		var (
+			mu  sync.Mutex
+			wg  sync.WaitGroup
+			sem = make(chan struct{}, uc.concurrency)
+		)
+		for _, d := range ds {
+			sem <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer func() { <-sem; wg.Done() }()
+				sent, givenUp, err := uc.deliver(ctx, d)
+
+				mu.Lock()
+				defer mu.Unlock()
+				switch {
+				case sent:
+					report.Delivered++
+				case givenUp:
+					report.GivenUp++
+				default:
+					report.Retried++
+				}
+				if err != nil {
+					errs = append(errs, err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if len(ds) < uc.batchSize || ctx.Err() != nil {
+			return report, errors.Join(errs...)
+		}
+	}
+}
+
+func (uc *DispatchUseCase) deliver(ctx context.Context, d domain.Delivery) (sent, givenUp bool, err error) {
+	now := uc.now()
+	headers := map[string]string{
+		domain.HeaderDeliveryID: d.DedupeID(),
+		domain.HeaderTimestamp:  strconv.FormatInt(now.Unix(), 10),
+		domain.HeaderSignature:  domain.Sign(d.Secret, now, d.Payload),
+	}
+
+	sendErr := uc.sender.Send(ctx, d.URL, headers, d.Payload)
+	if sendErr == nil {
+		return true, false, uc.queue.RemoveDelivery(ctx, d.ID)
+	}
+
+	if d.Attempts >= uc.maxAttempts {
+		err := fmt.Errorf("%w: delivery %d of event %d to subscription %s after %d attempts: %v",
+			ErrDeliveryGivenUp, d.ID, d.EventRowID, d.SubscriptionID, d.Attempts, sendErr)
+		return false, true, errors.Join(err, uc.queue.RemoveDelivery(ctx, d.ID))
+	}
+	return false, false, uc.queue.RetryDelivery(ctx, d.ID, now.Add(uc.retryDelay(d.Attempts)), sendErr.Error())
+}
+
+// retryDelay is the wait after the given number of failed attempts.
+func (uc *DispatchUseCase) retryDelay(attempts int) time.Duration {
+	d := uc.backoff
+	for i := 1; i < attempts && d < MaxRetryBackoff; i++ {
+		d *= 2
+	}
+	return min(d, MaxRetryBackoff)
+}
+
+// Run delivers on every tick until ctx is cancelled.
+func (uc *DispatchUseCase) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := uc.Deliver(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
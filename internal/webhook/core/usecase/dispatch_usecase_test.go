@@ -0,0 +1,235 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"event-metrics-service/internal/webhook/core/domain"
+	"event-metrics-service/internal/webhook/core/usecase"
+)
+
+// memQueue is an in-memory DeliveryQueuePort. Claims return every delivery
+// due at now, up to limit.
+type memQueue struct {
+	mu      sync.Mutex
+	now     time.Time
+	due     map[int64]time.Time
+	ds      map[int64]domain.Delivery
+	nextID  int64
+	errors  map[int64]string
+	removed []int64
+}
+
+func newMemQueue(now time.Time) *memQueue {
+	return &memQueue{now: now, due: map[int64]time.Time{}, ds: map[int64]domain.Delivery{}, errors: map[int64]string{}}
+}
+
+func (q *memQueue) EnqueueDeliveries(ctx context.Context, ds []domain.Delivery) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, d := range ds {
+		q.nextID++
+		d.ID = q.nextID
+		q.ds[d.ID] = d
+		q.due[d.ID] = q.now
+	}
+	return nil
+}
+
+func (q *memQueue) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.Delivery, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var out []domain.Delivery
+	for id := int64(1); id <= q.nextID && len(out) < limit; id++ {
+		d, ok := q.ds[id]
+		if !ok || q.due[id].After(q.now) {
+			continue
+		}
+		d.Attempts++
+		q.ds[id] = d
+		q.due[id] = q.now.Add(lease)
+		d.URL, d.Secret = "https://hooks.example.com/"+d.SubscriptionID, "secret-"+d.SubscriptionID
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+func (q *memQueue) RemoveDelivery(ctx context.Context, id int64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.ds, id)
+	q.removed = append(q.removed, id)
+	return nil
+}
+
+func (q *memQueue) RetryDelivery(ctx context.Context, id int64, at time.Time, lastError string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.due[id] = at
+	q.errors[id] = lastError
+	return nil
+}
+
+type sentRequest struct {
+	url     string
+	headers map[string]string
+	payload string
+}
+
+type fakeSender struct {
+	mu     sync.Mutex
+	SendFn func(url string) error
+	sent   []sentRequest
+}
+
+func (f *fakeSender) Send(ctx context.Context, url string, headers map[string]string, payload []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = append(f.sent, sentRequest{url: url, headers: headers, payload: string(payload)})
+	if f.SendFn != nil {
+		return f.SendFn(url)
+	}
+	return nil
+}
+
+func webhookEvent(id int64, name, channel string) domain.Event {
+	return domain.Event{
+		ID:        id,
+		EventName: name,
+		Channel:   channel,
+		UserID:    "u1",
+		EventTime: time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC),
+		DedupeKey: "dk",
+	}
+}
+
+// ------------------------------------------------------------
+// ENQUEUE
+// ------------------------------------------------------------
+
+func TestDispatch_EnqueueMatchesFilters(t *testing.T) {
+	subs := newMemSubscriptions(
+		domain.Subscription{ID: "all", Active: true},
+		domain.Subscription{ID: "purchases", Active: true, EventNames: []string{"purchase"}},
+		domain.Subscription{ID: "paused", Active: false},
+	)
+	queue := newMemQueue(time.Now())
+	uc := usecase.NewDispatchUseCase(subs, queue, &fakeSender{})
+
+	err := uc.Enqueue(context.Background(), []domain.Event{
+		webhookEvent(1, "purchase", "web"),
+		webhookEvent(2, "page_view", "web"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := map[string][]int64{}
+	for _, d := range queue.ds {
+		got[d.SubscriptionID] = append(got[d.SubscriptionID], d.EventRowID)
+		if !strings.Contains(string(d.Payload), `"tags":[]`) || !strings.Contains(string(d.Payload), `"dedupe_key":"dk"`) {
+			t.Fatalf("unexpected payload %s", d.Payload)
+		}
+	}
+	if len(got["all"]) != 2 || len(got["purchases"]) != 1 || got["purchases"][0] != 1 || len(got["paused"]) != 0 {
+		t.Fatalf("unexpected deliveries %v", got)
+	}
+}
+
+// ------------------------------------------------------------
+// DELIVER
+// ------------------------------------------------------------
+
+func TestDispatch_DeliverSignsAndRemoves(t *testing.T) {
+	now := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	queue := newMemQueue(now)
+	_ = queue.EnqueueDeliveries(context.Background(), []domain.Delivery{{SubscriptionID: "s1", EventRowID: 7, Payload: []byte(`{"id":7}`)}})
+	sender := &fakeSender{}
+	uc := usecase.NewDispatchUseCase(newMemSubscriptions(), queue, sender)
+
+	report, err := uc.Deliver(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Delivered != 1 || len(queue.ds) != 0 {
+		t.Fatalf("expected the delivery to be sent and removed, got %+v", report)
+	}
+
+	req := sender.sent[0]
+	if req.url != "https://hooks.example.com/s1" || req.payload != `{"id":7}` {
+		t.Fatalf("unexpected request %+v", req)
+	}
+	if req.headers[domain.HeaderDeliveryID] != "s1.7" {
+		t.Fatalf("unexpected headers %v", req.headers)
+	}
+	if want := domain.Sign("secret-s1", sentAt(t, req), []byte(`{"id":7}`)); req.headers[domain.HeaderSignature] != want {
+		t.Fatalf("signature %q, want %q", req.headers[domain.HeaderSignature], want)
+	}
+}
+
+func sentAt(t *testing.T, req sentRequest) time.Time {
+	t.Helper()
+	sec, err := strconv.ParseInt(req.headers[domain.HeaderTimestamp], 10, 64)
+	if err != nil {
+		t.Fatalf("invalid timestamp header: %v", err)
+	}
+	return time.Unix(sec, 0)
+}
+
+func TestDispatch_DeliverRetriesWithBackoffThenGivesUp(t *testing.T) {
+	now := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	queue := newMemQueue(now)
+	_ = queue.EnqueueDeliveries(context.Background(), []domain.Delivery{{SubscriptionID: "s1", EventRowID: 7}})
+	sender := &fakeSender{SendFn: func(string) error { return errors.New("503 Service Unavailable") }}
+	uc := usecase.NewDispatchUseCase(newMemSubscriptions(), queue, sender, usecase.WithRetries(3, time.Minute))
+
+	var waits []time.Duration
+	for attempt := 1; attempt <= 3; attempt++ {
+		report, err := uc.Deliver(context.Background())
+		if attempt < 3 {
+			if err != nil || report.Retried != 1 {
+				t.Fatalf("attempt %d: expected a retry, got %+v %v", attempt, report, err)
+			}
+			// The header has whole seconds.
+			waits = append(waits, queue.due[1].Sub(sentAt(t, sender.sent[attempt-1])).Truncate(time.Second))
+			if queue.errors[1] != "503 Service Unavailable" {
+				t.Fatalf("expected the last error to be kept, got %q", queue.errors[1])
+			}
+			queue.now = queue.due[1] // time passes until the retry is due
+			continue
+		}
+		if !errors.Is(err, usecase.ErrDeliveryGivenUp) || report.GivenUp != 1 {
+			t.Fatalf("expected the delivery to be given up, got %+v %v", report, err)
+		}
+	}
+
+	if len(waits) != 2 || waits[0] != time.Minute || waits[1] != 2*time.Minute {
+		t.Fatalf("expected exponential backoff, got %v", waits)
+	}
+	if len(queue.ds) != 0 {
+		t.Fatalf("expected the given up delivery to be removed")
+	}
+}
+
+func TestDispatch_DeliverDrainsFullBatches(t *testing.T) {
+	queue := newMemQueue(time.Now())
+	var ds []domain.Delivery
+	for i := range usecase.DefaultDeliveryBatchSize + 5 {
+		ds = append(ds, domain.Delivery{SubscriptionID: "s1", EventRowID: int64(i)})
+	}
+	_ = queue.EnqueueDeliveries(context.Background(), ds)
+	uc := usecase.NewDispatchUseCase(newMemSubscriptions(), queue, &fakeSender{}, usecase.WithConcurrency(3))
+
+	report, err := uc.Deliver(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Delivered != usecase.DefaultDeliveryBatchSize+5 {
+		t.Fatalf("expected every delivery to be sent, got %+v", report)
+	}
+}
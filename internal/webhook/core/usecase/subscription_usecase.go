@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"event-metrics-service/internal/webhook/core/domain"
+	"event-metrics-service/internal/webhook/core/ports"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidSubscription  = errors.New("invalid subscription")
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+)
+
+const (
+	MaxFilterValues    = 100
+	MinSecretLength    = 16
+	secretByteCount    = 32
+	MaxEventNameLength = 100 // events.event_name
+	MaxChannelLength   = 50  // events.channel
+)
+
+type SubscriptionUseCase struct {
+	repo   ports.SubscriptionRepositoryPort
+	random io.Reader
+	now    func() time.Time
+}
+
+func NewSubscriptionUseCase(repo ports.SubscriptionRepositoryPort) *SubscriptionUseCase {
+	return &SubscriptionUseCase{repo: repo, random: rand.Reader, now: time.Now}
+}
+
+type SubscriptionInput struct {
+	URL        string
+	Secret     string // generated on create when empty, kept on update when empty
+	EventNames []string
+	Channels   []string
+	Active     bool
+}
+
+// Create stores a subscription, generating its secret unless one is given.
+func (uc *SubscriptionUseCase) Create(ctx context.Context, in SubscriptionInput) (domain.Subscription, error) {
+	s, err := buildSubscription(in)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+	if s.Secret == "" {
+		if s.Secret, err = uc.newSecret(); err != nil {
+			return domain.Subscription{}, err
+		}
+	}
+
+	now := uc.now().UTC()
+	s.ID = uuid.NewString()
+	s.CreatedAt, s.UpdatedAt = now, now
+	if err := uc.repo.CreateSubscription(ctx, s); err != nil {
+		return domain.Subscription{}, err
+	}
+	return s, nil
+}
+
+// Update replaces the URL, filters and state of a subscription, and its
+// secret when one is given.
+func (uc *SubscriptionUseCase) Update(ctx context.Context, id string, in SubscriptionInput) (domain.Subscription, error) {
+	s, err := buildSubscription(in)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+
+	current, err := uc.Get(ctx, id)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+	if s.Secret == "" {
+		s.Secret = current.Secret
+	}
+	s.ID = current.ID
+	s.CreatedAt = current.CreatedAt
+	s.UpdatedAt = uc.now().UTC()
+
+	found, err := uc.repo.UpdateSubscription(ctx, s)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+	if !found {
+		return domain.Subscription{}, ErrSubscriptionNotFound
+	}
+	return s, nil
+}
+
+func (uc *SubscriptionUseCase) Delete(ctx context.Context, id string) error {
+	if uuid.Validate(id) != nil {
+		return ErrSubscriptionNotFound
+	}
+	found, err := uc.repo.DeleteSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+func (uc *SubscriptionUseCase) Get(ctx context.Context, id string) (domain.Subscription, error) {
+	if uuid.Validate(id) != nil {
+		return domain.Subscription{}, ErrSubscriptionNotFound
+	}
+	s, found, err := uc.repo.GetSubscription(ctx, id)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+	if !found {
+		return domain.Subscription{}, ErrSubscriptionNotFound
+	}
+	return s, nil
+}
+
+func (uc *SubscriptionUseCase) List(ctx context.Context) ([]domain.Subscription, error) {
+	return uc.repo.ListSubscriptions(ctx)
+}
+
+func buildSubscription(in SubscriptionInput) (domain.Subscription, error) {
+	u, err := url.Parse(strings.TrimSpace(in.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return domain.Subscription{}, fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidSubscription)
+	}
+	if in.Secret != "" && len(in.Secret) < MinSecretLength {
+		return domain.Subscription{}, fmt.Errorf("%w: secret must be at least %d characters", ErrInvalidSubscription, MinSecretLength)
+	}
+
+	eventNames, err := normalizeFilter("event_names", in.EventNames, MaxEventNameLength)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+	channels, err := normalizeFilter("channels", in.Channels, MaxChannelLength)
+	if err != nil {
+		return domain.Subscription{}, err
+	}
+
+	return domain.Subscription{
+		URL:        u.String(),
+		Secret:     in.Secret,
+		EventNames: eventNames,
+		Channels:   channels,
+		Active:     in.Active,
+	}, nil
+}
+
+// normalizeFilter trims values and drops duplicates.
+func normalizeFilter(field string, values []string, maxLen int) ([]string, error) {
+	if len(values) > MaxFilterValues {
+		return nil, fmt.Errorf("%w: at most %d %s allowed", ErrInvalidSubscription, MaxFilterValues, field)
+	}
+
+	out := make([]string, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+	for i, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || len(v) > maxLen {
+			return nil, fmt.Errorf("%w: %s[%d] must be 1-%d characters", ErrInvalidSubscription, field, i, maxLen)
+		}
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func (uc *SubscriptionUseCase) newSecret() (string, error) {
+	b := make([]byte, secretByteCount)
+	if _, err := io.ReadFull(uc.random, b); err != nil {
+		return "", fmt.Errorf("generate secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
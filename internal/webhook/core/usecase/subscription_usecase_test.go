@@ -0,0 +1,153 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"event-metrics-service/internal/webhook/core/domain"
+	"event-metrics-service/internal/webhook/core/usecase"
+)
+
+// memSubscriptions is an in-memory SubscriptionRepositoryPort.
+type memSubscriptions struct {
+	subs map[string]domain.Subscription
+	err  error
+}
+
+func newMemSubscriptions(subs ...domain.Subscription) *memSubscriptions {
+	m := &memSubscriptions{subs: map[string]domain.Subscription{}}
+	for _, s := range subs {
+		m.subs[s.ID] = s
+	}
+	return m
+}
+
+func (m *memSubscriptions) CreateSubscription(ctx context.Context, s domain.Subscription) error {
+	m.subs[s.ID] = s
+	return m.err
+}
+
+func (m *memSubscriptions) UpdateSubscription(ctx context.Context, s domain.Subscription) (bool, error) {
+	if _, ok := m.subs[s.ID]; !ok {
+		return false, m.err
+	}
+	m.subs[s.ID] = s
+	return true, m.err
+}
+
+func (m *memSubscriptions) DeleteSubscription(ctx context.Context, id string) (bool, error) {
+	_, ok := m.subs[id]
+	delete(m.subs, id)
+	return ok, m.err
+}
+
+func (m *memSubscriptions) GetSubscription(ctx context.Context, id string) (domain.Subscription, bool, error) {
+	s, ok := m.subs[id]
+	return s, ok, m.err
+}
+
+func (m *memSubscriptions) ListSubscriptions(ctx context.Context) ([]domain.Subscription, error) {
+	var out []domain.Subscription
+	for _, s := range m.subs {
+		out = append(out, s)
+	}
+	return out, m.err
+}
+
+// ------------------------------------------------------------
+// CREATE / UPDATE
+// ------------------------------------------------------------
+
+func TestSubscription_CreateGeneratesSecret(t *testing.T) {
+	repo := newMemSubscriptions()
+	uc := usecase.NewSubscriptionUseCase(repo)
+
+	s, err := uc.Create(context.Background(), usecase.SubscriptionInput{
+		URL:        " https://hooks.example.com/events ",
+		EventNames: []string{"purchase", " purchase", "signup"},
+		Active:     true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.ID == "" || len(s.Secret) != 64 {
+		t.Fatalf("expected an id and a generated secret, got %+v", s)
+	}
+	if s.URL != "https://hooks.example.com/events" {
+		t.Fatalf("expected a trimmed url, got %q", s.URL)
+	}
+	if len(s.EventNames) != 2 || s.EventNames[0] != "purchase" || s.EventNames[1] != "signup" {
+		t.Fatalf("expected deduplicated event names, got %v", s.EventNames)
+	}
+	if s.CreatedAt.IsZero() || !s.CreatedAt.Equal(s.UpdatedAt) {
+		t.Fatalf("expected timestamps, got %v %v", s.CreatedAt, s.UpdatedAt)
+	}
+	if _, ok := repo.subs[s.ID]; !ok {
+		t.Fatalf("expected the subscription to be stored")
+	}
+}
+
+func TestSubscription_CreateInvalid(t *testing.T) {
+	uc := usecase.NewSubscriptionUseCase(newMemSubscriptions())
+
+	for name, in := range map[string]usecase.SubscriptionInput{
+		"relative url":   {URL: "/hooks"},
+		"ftp url":        {URL: "ftp://example.com/hooks"},
+		"short secret":   {URL: "https://example.com", Secret: "short"},
+		"empty channel":  {URL: "https://example.com", Channels: []string{" "}},
+		"long eventname": {URL: "https://example.com", EventNames: []string{string(make([]byte, 101))}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := uc.Create(context.Background(), in); !errors.Is(err, usecase.ErrInvalidSubscription) {
+				t.Fatalf("expected ErrInvalidSubscription, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSubscription_UpdateKeepsSecretUnlessGiven(t *testing.T) {
+	repo := newMemSubscriptions()
+	uc := usecase.NewSubscriptionUseCase(repo)
+	created, _ := uc.Create(context.Background(), usecase.SubscriptionInput{URL: "https://a.example.com", Active: true})
+
+	s, err := uc.Update(context.Background(), created.ID, usecase.SubscriptionInput{URL: "https://b.example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Secret != created.Secret || s.URL != "https://b.example.com" || s.Active {
+		t.Fatalf("unexpected update %+v", s)
+	}
+	if !s.CreatedAt.Equal(created.CreatedAt) {
+		t.Fatalf("expected created_at to be kept")
+	}
+
+	s, err = uc.Update(context.Background(), created.ID, usecase.SubscriptionInput{
+		URL:    "https://b.example.com",
+		Secret: "0123456789abcdef-rotated",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.subs[created.ID].Secret != "0123456789abcdef-rotated" || s.Secret != "0123456789abcdef-rotated" {
+		t.Fatalf("expected the secret to be rotated")
+	}
+}
+
+func TestSubscription_NotFound(t *testing.T) {
+	uc := usecase.NewSubscriptionUseCase(newMemSubscriptions())
+	id := "5f1c2d3e-0a9b-4c8d-b7e6-1f2a3b4c5d6e"
+
+	if _, err := uc.Get(context.Background(), id); !errors.Is(err, usecase.ErrSubscriptionNotFound) {
+		t.Fatalf("expected ErrSubscriptionNotFound from Get, got %v", err)
+	}
+	if _, err := uc.Get(context.Background(), "not-a-uuid"); !errors.Is(err, usecase.ErrSubscriptionNotFound) {
+		t.Fatalf("expected ErrSubscriptionNotFound for a malformed id, got %v", err)
+	}
+	if _, err := uc.Update(context.Background(), id, usecase.SubscriptionInput{URL: "https://example.com"}); !errors.Is(err, usecase.ErrSubscriptionNotFound) {
+		t.Fatalf("expected ErrSubscriptionNotFound from Update, got %v", err)
+	}
+	if err := uc.Delete(context.Background(), id); !errors.Is(err, usecase.ErrSubscriptionNotFound) {
+		t.Fatalf("expected ErrSubscriptionNotFound from Delete, got %v", err)
+	}
+}
@@ -0,0 +1,8 @@
+-- Client-supplied idempotency key (UUID). When present it replaces the
+-- synthetic dedupe key, so ux_events_dedupe keeps enforcing uniqueness.
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS event_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_events_event_id
+    ON events (event_id)
+    WHERE event_id IS NOT NULL;
@@ -0,0 +1,4 @@
+-- Optional numeric measurement carried by the event (e.g. order size),
+-- used by histogram metrics.
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS value DOUBLE PRECISION;
@@ -0,0 +1,22 @@
+-- Time-bounded deduplication: dedupe keys are claimed in a small table with
+-- an expiry instead of a unique index on events that grows forever.
+CREATE TABLE IF NOT EXISTS event_dedupe (
+    dedupe_key  TEXT        PRIMARY KEY,
+    expires_at  TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_dedupe_expires_at
+    ON event_dedupe (expires_at);
+
+-- Keep protecting recently ingested events during the switch-over.
+INSERT INTO event_dedupe (dedupe_key, expires_at)
+SELECT dedupe_key, now() + interval '24 hours'
+FROM events
+WHERE event_time > now() - interval '24 hours'
+ON CONFLICT (dedupe_key) DO NOTHING;
+
+DROP INDEX IF EXISTS ux_events_dedupe;
+
+-- Lookup only (no longer unique)
+CREATE INDEX IF NOT EXISTS idx_events_dedupe_key
+    ON events (dedupe_key);
@@ -0,0 +1,9 @@
+-- Producers register heartbeat events they promise to send at a fixed interval.
+-- Heartbeats are ordinary events: event_name = heartbeats.event_name, user_id = producer.
+CREATE TABLE IF NOT EXISTS heartbeats (
+    producer         VARCHAR(100) NOT NULL,
+    event_name       VARCHAR(100) NOT NULL,
+    interval_seconds INTEGER      NOT NULL CHECK (interval_seconds > 0),
+    registered_at    TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    PRIMARY KEY (producer, event_name)
+);
@@ -0,0 +1,8 @@
+-- Ingestion time of each event. Metrics snapshots (as_of) only count events
+-- received at or before their watermark, so panels queried moments apart
+-- agree with each other.
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS received_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp();
+
+CREATE INDEX IF NOT EXISTS idx_events_received_at
+    ON events (received_at);
@@ -0,0 +1,7 @@
+-- Daily ingest usage per API key for soft quotas.
+CREATE TABLE IF NOT EXISTS quota_usage (
+    api_key  TEXT   NOT NULL,
+    day      DATE   NOT NULL,
+    used     BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (api_key, day)
+);
@@ -0,0 +1,31 @@
+-- Tenants provisioned through POST /admin/tenants.
+CREATE TABLE IF NOT EXISTS tenants (
+    id              TEXT        PRIMARY KEY,
+    name            TEXT        NOT NULL,
+    retention_days  INTEGER     NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL
+);
+
+-- Only the SHA-256 of each secret is stored.
+CREATE TABLE IF NOT EXISTS tenant_api_keys (
+    id           TEXT        PRIMARY KEY,
+    tenant_id    TEXT        NOT NULL REFERENCES tenants (id) ON DELETE CASCADE,
+    name         TEXT        NOT NULL,
+    secret_hash  TEXT        NOT NULL UNIQUE,
+    created_at   TIMESTAMPTZ NOT NULL,
+    UNIQUE (tenant_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS tenant_channels (
+    tenant_id    TEXT        NOT NULL REFERENCES tenants (id) ON DELETE CASCADE,
+    name         VARCHAR(50) NOT NULL,
+    description  TEXT        NOT NULL DEFAULT '',
+    PRIMARY KEY (tenant_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS tenant_event_schemas (
+    tenant_id   TEXT         NOT NULL REFERENCES tenants (id) ON DELETE CASCADE,
+    event_name  VARCHAR(100) NOT NULL,
+    schema      JSONB        NOT NULL,
+    PRIMARY KEY (tenant_id, event_name)
+);
@@ -0,0 +1,15 @@
+-- Anonymous events: user_id stays empty until the visitor is identified.
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS anonymous_id VARCHAR(100);
+
+CREATE INDEX IF NOT EXISTS idx_events_anonymous_id
+    ON events (anonymous_id) WHERE anonymous_id IS NOT NULL;
+
+-- Identify calls. Anonymous events at or after stitch_from count as user_id
+-- in unique-user metrics.
+CREATE TABLE IF NOT EXISTS identity_links (
+    anonymous_id   VARCHAR(100) PRIMARY KEY,
+    user_id        VARCHAR(100) NOT NULL,
+    identified_at  TIMESTAMPTZ  NOT NULL,
+    stitch_from    TIMESTAMPTZ  NOT NULL
+);
@@ -0,0 +1,6 @@
+-- Metadata JSON Schemas enforced at ingest, one per event_name.
+CREATE TABLE IF NOT EXISTS event_schemas (
+    event_name  VARCHAR(100) PRIMARY KEY,
+    schema      JSONB        NOT NULL,
+    updated_at  TIMESTAMPTZ  NOT NULL
+);
@@ -0,0 +1,17 @@
+-- Rejected ingest requests, kept briefly for support (JOURNAL_ENABLED).
+CREATE TABLE IF NOT EXISTS rejected_requests (
+    request_id    VARCHAR(128) PRIMARY KEY,
+    api_key_hash  TEXT         NOT NULL,
+    method        VARCHAR(10)  NOT NULL,
+    path          TEXT         NOT NULL,
+    status        INTEGER      NOT NULL,
+    body          BYTEA        NOT NULL,
+    body_sha256   TEXT         NOT NULL,
+    body_size     INTEGER      NOT NULL,
+    response      BYTEA        NOT NULL,
+    received_at   TIMESTAMPTZ  NOT NULL,
+    expires_at    TIMESTAMPTZ  NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rejected_requests_expires_at
+    ON rejected_requests (expires_at);
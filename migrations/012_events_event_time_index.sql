@@ -0,0 +1,4 @@
+-- Retention purges (EVENTS_RETENTION_DAYS) delete the oldest events by
+-- event_time in batches; without this index every batch scans the table.
+CREATE INDEX IF NOT EXISTS idx_events_event_time
+    ON events (event_time);
@@ -0,0 +1,26 @@
+-- Archived events loaded back from object storage (POST /admin/archive/rehydrate).
+-- Kept apart from events so the retention purge does not delete, and archive,
+-- them again. archive_key names the file a row was restored from.
+CREATE TABLE IF NOT EXISTS events_rehydrated (
+    id            BIGINT       PRIMARY KEY,
+    event_id      UUID,
+    event_name    VARCHAR(100) NOT NULL,
+    channel       VARCHAR(50)  NOT NULL,
+    campaign_id   VARCHAR(100),
+    user_id       VARCHAR(100) NOT NULL,
+    anonymous_id  VARCHAR(100),
+    event_time    TIMESTAMPTZ  NOT NULL,
+    received_at   TIMESTAMPTZ  NOT NULL,
+    value         DOUBLE PRECISION,
+    tags          TEXT[]       NOT NULL DEFAULT '{}',
+    metadata      JSONB        NOT NULL DEFAULT '{}'::jsonb,
+    dedupe_key    TEXT         NOT NULL,
+    archive_key   TEXT         NOT NULL,
+    rehydrated_at TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_events_rehydrated_eventname_time
+    ON events_rehydrated (event_name, event_time);
+
+CREATE INDEX IF NOT EXISTS idx_events_rehydrated_user_id
+    ON events_rehydrated (user_id);
@@ -0,0 +1,9 @@
+-- Events waiting to be published to Kafka (KAFKA_BROKERS). A row is written
+-- by the same statement that stores the event and deleted once the relay
+-- worker has published it. Only the events.id is kept, so events purged or
+-- erased before they are relayed are never published.
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id           BIGSERIAL   PRIMARY KEY,
+    event_row_id BIGINT      NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
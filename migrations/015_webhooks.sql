@@ -0,0 +1,28 @@
+-- Outbound webhooks (WEBHOOKS_ENABLED). Deliveries are queued per
+-- subscription and event from the event outbox (migration 014) and removed
+-- once sent or given up; a subscription's queue goes with it.
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id          UUID         PRIMARY KEY,
+    url         TEXT         NOT NULL,
+    secret      TEXT         NOT NULL,
+    event_names TEXT[]       NOT NULL DEFAULT '{}',
+    channels    TEXT[]       NOT NULL DEFAULT '{}',
+    active      BOOLEAN      NOT NULL DEFAULT true,
+    created_at  TIMESTAMPTZ  NOT NULL,
+    updated_at  TIMESTAMPTZ  NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id              BIGSERIAL    PRIMARY KEY,
+    subscription_id UUID         NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_row_id    BIGINT       NOT NULL,
+    payload         JSONB        NOT NULL,
+    attempts        INTEGER      NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    last_error      TEXT         NOT NULL DEFAULT '',
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    UNIQUE (subscription_id, event_row_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_next_attempt_at
+    ON webhook_deliveries (next_attempt_at);
@@ -0,0 +1,5 @@
+-- Share of events with this name kept by ingest sampling (SAMPLING_RATES).
+-- Unsampled events keep the default of 1; metrics count each event as
+-- 1 / sample_rate events. A constant default needs no table rewrite.
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS sample_rate DOUBLE PRECISION NOT NULL DEFAULT 1;
@@ -0,0 +1,9 @@
+-- Tenant of the API key that sent each event (TENANT_ISOLATION). Events
+-- stored before, or without isolation, keep the empty default and are not
+-- visible to any tenant. A constant default needs no table rewrite.
+ALTER TABLE events
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT '';
+
+-- Every metrics query of a tenant filters on tenant_id first.
+CREATE INDEX IF NOT EXISTS idx_events_tenant_name_time
+    ON events (tenant_id, event_name, event_time);
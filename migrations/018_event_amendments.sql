@@ -0,0 +1,14 @@
+-- Audit trail of PATCH /events/{id}: one row per correction of a stored
+-- event, with the fields it changed ([{"field","before","after"}]). The
+-- trail goes with its event, e.g. on erasure or retention.
+CREATE TABLE IF NOT EXISTS event_amendments (
+    id           BIGSERIAL    PRIMARY KEY,
+    event_row_id BIGINT       NOT NULL REFERENCES events (id) ON DELETE CASCADE,
+    changes      JSONB        NOT NULL,
+    reason       TEXT         NOT NULL,
+    amended_by   TEXT         NOT NULL DEFAULT '',
+    amended_at   TIMESTAMPTZ  NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_amendments_event_row_id
+    ON event_amendments (event_row_id);
@@ -0,0 +1,5 @@
+-- Metrics metadata filters (metadata.<key>=<value>) match by JSONB
+-- containment (metadata @> '{"key": "value"}'), which this index serves.
+-- jsonb_path_ops only supports @>, and is smaller and faster for it.
+CREATE INDEX IF NOT EXISTS idx_events_metadata
+    ON events USING GIN (metadata jsonb_path_ops);
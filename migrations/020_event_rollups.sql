@@ -0,0 +1,28 @@
+-- Hourly and daily pre-aggregates of events (ROLLUP_ENABLED), kept up to
+-- date by the rollup worker. One row per bucket, dimensions and the user the
+-- events count towards (as unique users resolve it, with identity
+-- stitching), so unique users stay exact. weight is the number of events the
+-- row stands for, i.e. SUM(1 / sample_rate).
+CREATE TABLE IF NOT EXISTS event_rollups (
+    granularity TEXT             NOT NULL,
+    bucket      TIMESTAMPTZ      NOT NULL,
+    tenant_id   TEXT             NOT NULL DEFAULT '',
+    event_name  VARCHAR(100)     NOT NULL,
+    channel     VARCHAR(50)      NOT NULL,
+    campaign_id VARCHAR(100)     NOT NULL DEFAULT '',
+    unique_user TEXT             NOT NULL,
+    weight      DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (granularity, tenant_id, event_name, bucket, channel, campaign_id, unique_user)
+);
+
+-- Erasure deletes the rows of a user.
+CREATE INDEX IF NOT EXISTS idx_event_rollups_unique_user
+    ON event_rollups (unique_user);
+
+-- The buckets of each granularity the worker has rolled up,
+-- [rolled_from, rolled_until). Metrics only read rollups within them.
+CREATE TABLE IF NOT EXISTS rollup_progress (
+    granularity  TEXT        PRIMARY KEY,
+    rolled_from  TIMESTAMPTZ NOT NULL,
+    rolled_until TIMESTAMPTZ NOT NULL
+);
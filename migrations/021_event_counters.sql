@@ -0,0 +1,13 @@
+-- Event counts per tenant, name, channel and hour (EVENT_COUNTERS_ENABLED),
+-- incremented by the same statement that stores each event. events is the
+-- number of events the row stands for, i.e. SUM(1 / sample_rate). Deletes,
+-- channel corrections and erasure adjust them in the same statement or
+-- transaction; retention does not, so counts outlive purged events.
+CREATE TABLE IF NOT EXISTS event_counters (
+    tenant_id  TEXT             NOT NULL DEFAULT '',
+    event_name VARCHAR(100)     NOT NULL,
+    channel    VARCHAR(50)      NOT NULL,
+    hour       TIMESTAMPTZ      NOT NULL,
+    events     DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (tenant_id, event_name, hour, channel)
+);
@@ -0,0 +1,12 @@
+-- Saved metrics queries run by name (GET /reports/{name}/run). query is a
+-- GET /metrics query string, with range= standing in for from/to. Names
+-- are unique per tenant; tenant_id is '' without TENANT_ISOLATION.
+CREATE TABLE IF NOT EXISTS reports (
+    tenant_id   TEXT         NOT NULL DEFAULT '',
+    name        VARCHAR(100) NOT NULL,
+    description TEXT         NOT NULL DEFAULT '',
+    query       TEXT         NOT NULL,
+    created_at  TIMESTAMPTZ  NOT NULL,
+    updated_at  TIMESTAMPTZ  NOT NULL,
+    PRIMARY KEY (tenant_id, name)
+);
@@ -0,0 +1,22 @@
+-- Threshold alerts (ALERTS_ENABLED). Each rule keeps the state of its last
+-- evaluation; status only changes through a compare-and-set on the old
+-- status, so one instance notifies each change.
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id           UUID             PRIMARY KEY,
+    name         VARCHAR(100)     NOT NULL,
+    tenant_id    TEXT             NOT NULL DEFAULT '',
+    query        TEXT             NOT NULL,
+    measure      VARCHAR(20)      NOT NULL,
+    operator     VARCHAR(2)       NOT NULL,
+    threshold    DOUBLE PRECISION NOT NULL,
+    notify_url   TEXT             NOT NULL,
+    active       BOOLEAN          NOT NULL DEFAULT true,
+    created_at   TIMESTAMPTZ      NOT NULL,
+    updated_at   TIMESTAMPTZ      NOT NULL,
+
+    status       VARCHAR(10)      NOT NULL DEFAULT 'ok',
+    status_since TIMESTAMPTZ,
+    value        DOUBLE PRECISION,
+    evaluated_at TIMESTAMPTZ,
+    last_error   TEXT             NOT NULL DEFAULT ''
+);
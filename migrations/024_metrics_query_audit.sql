@@ -0,0 +1,24 @@
+-- Metrics queries and who ran them (QUERY_AUDIT_ENABLED), kept for
+-- QUERY_AUDIT_RETENTION.
+CREATE TABLE IF NOT EXISTS metrics_query_audit (
+    id            BIGSERIAL        PRIMARY KEY,
+    api_key_hash  TEXT             NOT NULL,
+    subject       TEXT             NOT NULL DEFAULT '',
+    tenant_id     TEXT             NOT NULL DEFAULT '',
+    operation     VARCHAR(10)      NOT NULL,
+    event_name    TEXT             NOT NULL,
+    group_by      TEXT             NOT NULL DEFAULT '',
+    from_time     TIMESTAMPTZ      NOT NULL,
+    to_time       TIMESTAMPTZ      NOT NULL,
+    filter        JSONB            NOT NULL,
+    duration_ms   DOUBLE PRECISION NOT NULL,
+    rows_returned INTEGER          NOT NULL,
+    error         TEXT             NOT NULL DEFAULT '',
+    started_at    TIMESTAMPTZ      NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_metrics_query_audit_started_at
+    ON metrics_query_audit (started_at);
+
+CREATE INDEX IF NOT EXISTS idx_metrics_query_audit_api_key_hash
+    ON metrics_query_audit (api_key_hash, started_at);
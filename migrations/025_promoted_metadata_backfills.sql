@@ -0,0 +1,13 @@
+-- Backfill progress of the PROMOTED_METADATA_KEYS columns, shared by every
+-- instance: last_id is the events.id the keyset backfill has reached, and
+-- completed_at is set once the column is indexed and filled, from which on
+-- readers use the column instead of the JSONB metadata.
+--
+-- The meta_<key> columns themselves are added by a migration per key, e.g.
+--   ALTER TABLE events ADD COLUMN IF NOT EXISTS meta_product_id TEXT;
+CREATE TABLE IF NOT EXISTS promoted_metadata_backfills (
+    key          TEXT        PRIMARY KEY,
+    last_id      BIGINT      NOT NULL DEFAULT 0,
+    completed_at TIMESTAMPTZ,
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
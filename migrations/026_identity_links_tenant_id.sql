@@ -0,0 +1,9 @@
+-- Identity links belong to the tenant whose API key sent the identify call
+-- (TENANT_ISOLATION); '' without isolation. Anonymous ids are only unique
+-- within a tenant, so the key becomes (tenant_id, anonymous_id). Releases
+-- before this one fail POST /identify once it is applied.
+ALTER TABLE identity_links
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE identity_links DROP CONSTRAINT IF EXISTS identity_links_pkey;
+ALTER TABLE identity_links ADD PRIMARY KEY (tenant_id, anonymous_id);
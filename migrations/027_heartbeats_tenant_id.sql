@@ -0,0 +1,9 @@
+-- Heartbeats belong to the tenant whose API key registered them
+-- (TENANT_ISOLATION); '' without isolation. Their events are looked up in
+-- the same tenant, and producers are only unique within a tenant. Releases
+-- before this one fail PUT /heartbeats once it is applied.
+ALTER TABLE heartbeats
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE heartbeats DROP CONSTRAINT IF EXISTS heartbeats_pkey;
+ALTER TABLE heartbeats ADD PRIMARY KEY (tenant_id, producer, event_name);
@@ -0,0 +1,7 @@
+-- Rehydrated events keep the tenant and sample rate they were archived with,
+-- so restoring and replaying an archive file neither moves events out of
+-- their tenant nor counts a sampled event as one. Rows restored before this
+-- migration keep the defaults.
+ALTER TABLE events_rehydrated
+    ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS sample_rate DOUBLE PRECISION NOT NULL DEFAULT 1;
@@ -0,0 +1,31 @@
+-- ClickHouse copy of events read with METRICS_READER=clickhouse. Not applied
+-- with the numbered migrations: it lives in ClickHouse, and filling it is up
+-- to the deployment, e.g. by copying new rows from Postgres on a schedule:
+--
+-- INSERT INTO events
+-- SELECT id, event_name, channel, ifNull(campaign_id, ''), user_id, anonymous_id,
+--        event_time, received_at, value, tags, metadata, sample_rate, tenant_id
+-- FROM postgresql('postgres:5432', 'events_db', 'events', 'reader', '...')
+-- WHERE id > 123456; -- the last id copied
+--
+-- Rows inserted again for the same event replace the earlier one; queries
+-- read with FINAL. Deletes and user erasure in Postgres are not mirrored.
+CREATE TABLE IF NOT EXISTS events
+(
+    id           UInt64,
+    event_name   LowCardinality(String),
+    channel      LowCardinality(String),
+    campaign_id  String DEFAULT '',
+    user_id      String,
+    anonymous_id String DEFAULT '',
+    event_time   DateTime64(3, 'UTC'),
+    received_at  DateTime64(3, 'UTC'),
+    value        Nullable(Float64),
+    tags         Array(String),
+    metadata     String DEFAULT '{}',
+    sample_rate  Float64 DEFAULT 1,
+    tenant_id    LowCardinality(String) DEFAULT ''
+)
+ENGINE = ReplacingMergeTree
+PARTITION BY toYYYYMM(event_time)
+ORDER BY (tenant_id, event_name, event_time, id);
@@ -0,0 +1,30 @@
+-- Rollups kept as HyperLogLog sketches (ROLLUP_SKETCHES). Not applied with
+-- the numbered migrations: they need the hll extension
+-- (https://github.com/citusdata/postgresql-hll).
+--
+-- One row per bucket and dimensions, like event_rollups without the user:
+-- users is a sketch of the users the events count towards (with identity
+-- stitching), so unique users merge across buckets and dimensions as an
+-- estimate (about 2% error with the default parameters). weight is the
+-- number of events the row stands for, i.e. SUM(1 / sample_rate).
+CREATE EXTENSION IF NOT EXISTS hll;
+
+CREATE TABLE IF NOT EXISTS event_rollup_sketches (
+    granularity TEXT             NOT NULL,
+    bucket      TIMESTAMPTZ      NOT NULL,
+    tenant_id   TEXT             NOT NULL DEFAULT '',
+    event_name  VARCHAR(100)     NOT NULL,
+    channel     VARCHAR(50)      NOT NULL,
+    campaign_id VARCHAR(100)     NOT NULL DEFAULT '',
+    users       hll              NOT NULL,
+    weight      DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (granularity, tenant_id, event_name, bucket, channel, campaign_id)
+);
+
+-- The buckets of each granularity rolled up into sketches, like
+-- rollup_progress.
+CREATE TABLE IF NOT EXISTS rollup_sketch_progress (
+    granularity  TEXT        PRIMARY KEY,
+    rolled_from  TIMESTAMPTZ NOT NULL,
+    rolled_until TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,52 @@
+-- TimescaleDB continuous aggregates read with METRICS_READER=timescale.
+-- Not applied with the numbered migrations: they need the timescaledb
+-- extension, and events to be a hypertable on event_time.
+--
+-- Rows are grouped by user and anonymous visitor, so unique users can be
+-- resolved from them as from events. Real-time aggregation
+-- (materialized_only = false) keeps buckets not materialized yet complete;
+-- the service does not read the views without it.
+CREATE MATERIALIZED VIEW IF NOT EXISTS event_counts_hourly
+WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+SELECT
+    time_bucket(INTERVAL '1 hour', event_time) AS bucket,
+    tenant_id,
+    event_name,
+    channel,
+    COALESCE(campaign_id, '') AS campaign_id,
+    user_id,
+    anonymous_id,
+    SUM(1 / sample_rate) AS weight
+FROM events
+GROUP BY 1, 2, 3, 4, 5, 6, 7
+WITH NO DATA;
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS event_counts_daily
+WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+SELECT
+    time_bucket(INTERVAL '1 day', event_time) AS bucket,
+    tenant_id,
+    event_name,
+    channel,
+    COALESCE(campaign_id, '') AS campaign_id,
+    user_id,
+    anonymous_id,
+    SUM(1 / sample_rate) AS weight
+FROM events
+GROUP BY 1, 2, 3, 4, 5, 6, 7
+WITH NO DATA;
+
+-- Changes to events older than start_offset (late events, deletes,
+-- erasure) are only materialized by refreshing the range by hand, e.g.
+-- CALL refresh_continuous_aggregate('event_counts_daily', '2025-01-01', '2025-02-01');
+SELECT add_continuous_aggregate_policy('event_counts_hourly',
+    start_offset      => INTERVAL '3 days',
+    end_offset        => INTERVAL '1 hour',
+    schedule_interval => INTERVAL '15 minutes',
+    if_not_exists     => true);
+
+SELECT add_continuous_aggregate_policy('event_counts_daily',
+    start_offset      => INTERVAL '7 days',
+    end_offset        => INTERVAL '1 day',
+    schedule_interval => INTERVAL '1 hour',
+    if_not_exists     => true);
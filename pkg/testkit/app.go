@@ -0,0 +1,107 @@
+package testkit
+
+import (
+	"net/http"
+	"testing"
+
+	authHttp "event-metrics-service/internal/auth/adapters/http/fiber"
+	eventsHttp "event-metrics-service/internal/events/adapters/http/fiber"
+	eventsUsecase "event-metrics-service/internal/events/core/usecase"
+	metricsHttp "event-metrics-service/internal/metrics/adapters/http/fiber"
+	metricsUsecase "event-metrics-service/internal/metrics/core/usecase"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+)
+
+// DefaultAdminToken is the X-Admin-Token the App accepts unless
+// WithAdminToken overrides it.
+const DefaultAdminToken = "testkit-admin"
+
+// App is the service's HTTP surface backed by in-memory fakes. It mounts
+//
+//	POST /events, POST /events/bulk
+//	GET  /events, GET /events/:id   (X-Admin-Token required)
+//	GET  /metrics, GET /metrics/top, POST /metrics/batch
+//
+// with the production handlers and use cases, so status codes, error bodies
+// and validation match the real service.
+type App struct {
+	Fiber   *fiber.App
+	Events  *EventStore
+	Metrics *MetricsReader
+
+	adminToken string
+}
+
+type AppOption func(*App)
+
+// WithStore backs the App with an existing store, e.g. one seeded upfront
+// or built with WithClock.
+func WithStore(s *EventStore) AppOption {
+	return func(a *App) {
+		a.Events = s
+	}
+}
+
+func WithAdminToken(token string) AppOption {
+	return func(a *App) {
+		a.adminToken = token
+	}
+}
+
+func NewApp(opts ...AppOption) *App {
+	a := &App{adminToken: DefaultAdminToken}
+	for _, opt := range opts {
+		opt(a)
+	}
+	if a.Events == nil {
+		a.Events = NewEventStore()
+	}
+	a.Metrics = NewMetricsReader(a.Events)
+
+	storeEventUC := eventsUsecase.NewStoreEventUseCase(a.Events)
+	listEventsUC := eventsUsecase.NewListEventsUseCase(a.Events)
+	getEventUC := eventsUsecase.NewGetEventUseCase(a.Events)
+	exportEventsUC := eventsUsecase.NewExportEventsUseCase(a.Events, eventsUsecase.DefaultExportBatchSize)
+	getMetricsUC := metricsUsecase.NewGetMetricsUseCase(
+		a.Metrics,
+		metricsUsecase.WithWatermark(a.Metrics),
+		metricsUsecase.WithStreamer(a.Metrics),
+	)
+
+	a.Fiber = fiber.New()
+	a.Fiber.Use(requestid.New())
+
+	eventsHandler := eventsHttp.NewEventHandler(storeEventUC)
+	a.Fiber.Post("/events", eventsHandler.CreateEvent)
+	a.Fiber.Post("/events/bulk", eventsHandler.BulkCreateEvents)
+
+	eventQueryHandler := eventsHttp.NewEventQueryHandler(listEventsUC, getEventUC, exportEventsUC)
+	a.Fiber.Get("/events", authHttp.RequireAdminToken(a.adminToken), eventQueryHandler.ListEvents)
+	a.Fiber.Get("/events/export", authHttp.RequireAdminToken(a.adminToken), eventQueryHandler.ExportEvents)
+	a.Fiber.Get("/events/:id", authHttp.RequireAdminToken(a.adminToken), eventQueryHandler.GetEvent)
+
+	metricsHandler := metricsHttp.NewMetricsHandler(getMetricsUC)
+	a.Fiber.Get("/metrics", metricsHandler.GetMetrics)
+	a.Fiber.Get("/metrics/top", metricsHandler.GetTopValues)
+	a.Fiber.Post("/metrics/batch", metricsHandler.GetMetricsBatch)
+
+	return a
+}
+
+// Do serves req and fails the test if the app cannot handle it. Requests to
+// the admin-only read endpoints get the admin token unless they already
+// carry one.
+func (a *App) Do(t testing.TB, req *http.Request) *http.Response {
+	t.Helper()
+	if req.Method == http.MethodGet && req.Header.Get(authHttp.HeaderAdminToken) == "" {
+		req.Header.Set(authHttp.HeaderAdminToken, a.adminToken)
+	}
+
+	resp, err := a.Fiber.Test(req, -1)
+	if err != nil {
+		t.Fatalf("testkit: %s %s: %v", req.Method, req.URL, err)
+	}
+	return resp
+}
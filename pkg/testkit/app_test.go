@@ -0,0 +1,264 @@
+package testkit_test
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"event-metrics-service/pkg/testkit"
+)
+
+var at = time.Date(2025, 12, 7, 10, 15, 0, 0, time.UTC)
+
+func TestApp_IngestAndQueryMetrics(t *testing.T) {
+	app := testkit.NewApp()
+
+	for _, req := range []*testkit.EventRequest{
+		testkit.NewEvent("purchase").At(at).User("u1").Value(10),
+		testkit.NewEvent("purchase").At(at).User("u2").Channel("ios"),
+		testkit.NewEvent("purchase").At(at.Add(time.Hour)).Anonymous("anon-1").Meta("plan", "pro"),
+	} {
+		if resp := app.Do(t, req.Request()); resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected 201, got %d", resp.StatusCode)
+		}
+	}
+
+	// Resending the same payload is a duplicate, as in production.
+	dup := testkit.NewEvent("purchase").At(at).User("u1").Value(10)
+	if resp := app.Do(t, dup.Request()); resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 for duplicate, got %d", resp.StatusCode)
+	}
+
+	q := testkit.NewMetricsQuery("purchase", at.Add(-time.Hour), at.Add(2*time.Hour)).GroupBy("channel")
+	testkit.AssertGoldenResponse(t, "testdata/metrics_by_channel.golden", app.Do(t, q.Request()), testkit.DefaultMask...)
+
+	q = testkit.NewMetricsQuery("purchase", at.Add(-time.Hour), at.Add(2*time.Hour)).GroupBy("time").Interval("hour")
+	testkit.AssertGoldenResponse(t, "testdata/metrics_by_hour.golden", app.Do(t, q.Request()), testkit.DefaultMask...)
+
+	q = testkit.NewMetricsQuery("purchase", at.Add(-time.Hour), at.Add(2*time.Hour)).GroupBy("channel,time").Interval("hour")
+	testkit.AssertGoldenResponse(t, "testdata/metrics_by_channel_and_hour.golden", app.Do(t, q.Request()), testkit.DefaultMask...)
+}
+
+func TestApp_WeeklyBucketsStartOnMonday(t *testing.T) {
+	app := testkit.NewApp()
+	// at is a Sunday; the next day starts a new week.
+	app.Do(t, testkit.NewEvent("purchase").At(at).User("u1").Request())
+	app.Do(t, testkit.NewEvent("purchase").At(at.Add(24*time.Hour)).User("u1").Request())
+
+	q := testkit.NewMetricsQuery("purchase", at.Add(-time.Hour), at.Add(48*time.Hour)).GroupBy("time").Interval("week")
+	var body struct {
+		UniqueUsers int64 `json:"unique_users"`
+		Groups      []struct {
+			Key string `json:"key"`
+		} `json:"groups"`
+	}
+	if err := json.NewDecoder(app.Do(t, q.Request()).Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.Groups) != 2 || body.Groups[0].Key != "2025-12-01T00:00:00Z" || body.Groups[1].Key != "2025-12-08T00:00:00Z" {
+		t.Fatalf("unexpected groups: %+v", body.Groups)
+	}
+	// u1 is active in both weeks but counted once overall.
+	if body.UniqueUsers != 1 {
+		t.Fatalf("expected 1 unique user, got %d", body.UniqueUsers)
+	}
+}
+
+func TestApp_AggregatesNumericMetadata(t *testing.T) {
+	app := testkit.NewApp()
+	app.Do(t, testkit.NewEvent("purchase").At(at).User("u1").Meta("revenue", 10.5).Request())
+	app.Do(t, testkit.NewEvent("purchase").At(at).User("u2").Meta("revenue", 4).Request())
+	app.Do(t, testkit.NewEvent("purchase").At(at).User("u3").Channel("ios").Meta("revenue", "n/a").Request())
+
+	q := testkit.NewMetricsQuery("purchase", at.Add(-time.Hour), at.Add(time.Hour)).GroupBy("channel").Aggregate("sum", "metadata.revenue")
+	testkit.AssertGoldenResponse(t, "testdata/metrics_revenue_by_channel.golden", app.Do(t, q.Request()), testkit.DefaultMask...)
+
+	// Continuous percentile: 4 + 0.9 * (10.5 - 4).
+	q = testkit.NewMetricsQuery("purchase", at.Add(-time.Hour), at.Add(time.Hour)).Aggregate("p90", "metadata.revenue")
+	var body struct {
+		Value float64 `json:"value"`
+	}
+	if err := json.NewDecoder(app.Do(t, q.Request()).Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if math.Abs(body.Value-9.85) > 1e-9 {
+		t.Fatalf("expected p90 9.85, got %v", body.Value)
+	}
+}
+
+func TestApp_TopValues(t *testing.T) {
+	app := testkit.NewApp()
+	app.Do(t, testkit.NewEvent("purchase").At(at).User("u1").Campaign("spring").Meta("revenue", 5).Request())
+	app.Do(t, testkit.NewEvent("purchase").At(at.Add(time.Minute)).User("u1").Campaign("spring").Meta("revenue", 5).Request())
+	app.Do(t, testkit.NewEvent("purchase").At(at).User("u2").Campaign("summer").Meta("revenue", 30).Request())
+	app.Do(t, testkit.NewEvent("purchase").At(at).Anonymous("anon-1").Meta("revenue", 1).Request())
+
+	q := testkit.NewMetricsQuery("purchase", at.Add(-time.Hour), at.Add(time.Hour)).Top("user_id", 1)
+	testkit.AssertGoldenResponse(t, "testdata/metrics_top_users.golden", app.Do(t, q.Request()), testkit.DefaultMask...)
+
+	q = testkit.NewMetricsQuery("purchase", at.Add(-time.Hour), at.Add(time.Hour)).Top("campaign_id", 5).Aggregate("sum", "metadata.revenue")
+	testkit.AssertGoldenResponse(t, "testdata/metrics_top_campaigns_by_revenue.golden", app.Do(t, q.Request()), testkit.DefaultMask...)
+}
+
+func TestApp_MetricsComparedWithPreviousPeriod(t *testing.T) {
+	app := testkit.NewApp()
+	app.Do(t, testkit.NewEvent("purchase").At(at.Add(-90*time.Minute)).User("u1").Channel("web").Request())
+	app.Do(t, testkit.NewEvent("purchase").At(at).User("u1").Channel("web").Request())
+	app.Do(t, testkit.NewEvent("purchase").At(at.Add(time.Minute)).User("u2").Channel("web").Request())
+	app.Do(t, testkit.NewEvent("purchase").At(at).User("u3").Channel("ios").Request())
+
+	q := testkit.NewMetricsQuery("purchase", at.Add(-time.Hour), at.Add(time.Hour)).GroupBy("channel").Compare("previous_period")
+	testkit.AssertGoldenResponse(t, "testdata/metrics_compared_with_previous_period.golden", app.Do(t, q.Request()), testkit.DefaultMask...)
+}
+
+func TestApp_MetricsByTags(t *testing.T) {
+	app := testkit.NewApp()
+	app.Do(t, testkit.NewEvent("purchase").At(at).User("u1").Tag("promo", "beta").Request())
+	app.Do(t, testkit.NewEvent("purchase").At(at).User("u2").Tag("promo").Request())
+	app.Do(t, testkit.NewEvent("purchase").At(at).User("u3").Request())
+
+	for match, want := range map[string]int64{"all": 1, "any": 2} {
+		q := testkit.NewMetricsQuery("purchase", at.Add(-time.Hour), at.Add(time.Hour)).Tags(match, "promo", "beta")
+		var body struct {
+			TotalCount int64 `json:"total_count"`
+		}
+		if err := json.NewDecoder(app.Do(t, q.Request()).Body).Decode(&body); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if body.TotalCount != want {
+			t.Fatalf("%s: expected %d events, got %d", match, want, body.TotalCount)
+		}
+	}
+}
+
+func TestApp_MetricsByCampaign(t *testing.T) {
+	app := testkit.NewApp()
+	app.Do(t, testkit.NewEvent("purchase").At(at).User("u1").Campaign("spring").Request())
+	app.Do(t, testkit.NewEvent("purchase").At(at).User("u2").Campaign("summer").Request())
+	app.Do(t, testkit.NewEvent("purchase").At(at).User("u3").Request())
+
+	for campaign, want := range map[string]int64{"spring": 1, "": 1, "autumn": 0} {
+		q := testkit.NewMetricsQuery("purchase", at.Add(-time.Hour), at.Add(time.Hour)).Campaign(campaign)
+		var body struct {
+			TotalCount int64 `json:"total_count"`
+		}
+		if err := json.NewDecoder(app.Do(t, q.Request()).Body).Decode(&body); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if body.TotalCount != want {
+			t.Fatalf("campaign %q: expected %d events, got %d", campaign, want, body.TotalCount)
+		}
+	}
+}
+
+func TestApp_MetricsOfOneUser(t *testing.T) {
+	app := testkit.NewApp()
+	app.Do(t, testkit.NewEvent("page_view").At(at).User("u1").Request())
+	app.Do(t, testkit.NewEvent("page_view").At(at.Add(time.Minute)).User("u1").Request())
+	app.Do(t, testkit.NewEvent("page_view").At(at.Add(2*time.Hour)).User("u1").Request())
+	app.Do(t, testkit.NewEvent("page_view").At(at).User("u2").Request())
+
+	q := testkit.NewMetricsQuery("page_view", at.Add(-time.Hour), at.Add(3*time.Hour)).User("u1").GroupBy("time").Interval("hour")
+	testkit.AssertGoldenResponse(t, "testdata/metrics_user_activity.golden", app.Do(t, q.Request()), testkit.DefaultMask...)
+}
+
+func TestApp_MetricsGroupPage(t *testing.T) {
+	app := testkit.NewApp()
+	for i, product := range []string{"p1", "p2", "p3", "p4"} {
+		app.Do(t, testkit.NewEvent("product_view").At(at.Add(time.Duration(i)*time.Minute)).User("u1").Meta("product_id", product).Request())
+	}
+
+	q := testkit.NewMetricsQuery("product_view", at.Add(-time.Hour), at.Add(time.Hour)).GroupBy("metadata.product_id").Page(2, 1)
+	testkit.AssertGoldenResponse(t, "testdata/metrics_group_page.golden", app.Do(t, q.Request()), testkit.DefaultMask...)
+}
+
+func TestApp_MetricsBiggestGroupsFirst(t *testing.T) {
+	app := testkit.NewApp()
+	app.Do(t, testkit.NewEvent("purchase").At(at).User("u1").Channel("android").Request())
+	app.Do(t, testkit.NewEvent("purchase").At(at).User("u1").Channel("web").Request())
+	app.Do(t, testkit.NewEvent("purchase").At(at.Add(time.Minute)).User("u2").Channel("web").Request())
+	app.Do(t, testkit.NewEvent("purchase").At(at).User("u3").Channel("ios").Request())
+
+	q := testkit.NewMetricsQuery("purchase", at.Add(-time.Hour), at.Add(time.Hour)).GroupBy("channel").OrderBy("total_count", "desc")
+	testkit.AssertGoldenResponse(t, "testdata/metrics_biggest_channels_first.golden", app.Do(t, q.Request()), testkit.DefaultMask...)
+}
+
+func TestApp_StreamMetricsGroups(t *testing.T) {
+	app := testkit.NewApp()
+	app.Do(t, testkit.NewEvent("purchase").At(at).User("u1").Channel("android").Request())
+	app.Do(t, testkit.NewEvent("purchase").At(at).User("u1").Channel("web").Request())
+	app.Do(t, testkit.NewEvent("purchase").At(at.Add(time.Minute)).User("u2").Channel("web").Request())
+	app.Do(t, testkit.NewEvent("purchase").At(at).User("u3").Channel("ios").Request())
+
+	q := testkit.NewMetricsQuery("purchase", at.Add(-time.Hour), at.Add(time.Hour)).GroupBy("channel").OrderBy("total_count", "desc").Stream()
+	resp := app.Do(t, q.Request())
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	type line struct {
+		Key         string `json:"key"`
+		TotalCount  int64  `json:"total_count"`
+		UniqueUsers int64  `json:"unique_users"`
+	}
+	var lines []line
+	dec := json.NewDecoder(resp.Body)
+	for dec.More() {
+		var l line
+		if err := dec.Decode(&l); err != nil {
+			t.Fatalf("decode line %d: %v", len(lines)+1, err)
+		}
+		lines = append(lines, l)
+	}
+	if len(lines) != 4 || lines[0].Key != "" || lines[0].TotalCount != 4 || lines[0].UniqueUsers != 3 {
+		t.Fatalf("expected the totals first, got %+v", lines)
+	}
+	if lines[1].Key != "web" || lines[1].TotalCount != 2 || lines[2].Key != "android" || lines[3].Key != "ios" {
+		t.Fatalf("unexpected groups: %+v", lines[1:])
+	}
+}
+
+func TestApp_BulkAndListEvents(t *testing.T) {
+	app := testkit.NewApp()
+
+	resp := app.Do(t, testkit.Bulk(
+		testkit.NewEvent("signup").At(at).Tag("beta"),
+		testkit.NewEvent("signup").At(at.Add(time.Minute)).User("u2"),
+		testkit.NewEvent("").At(at),
+	))
+	testkit.AssertGoldenResponse(t, "testdata/bulk.golden", resp, testkit.DefaultMask...)
+
+	resp = app.Do(t, httptest.NewRequest(http.MethodGet, "/events?event_name=signup", nil))
+	testkit.AssertGoldenResponse(t, "testdata/list_events.golden", resp, testkit.DefaultMask...)
+}
+
+func TestApp_GetEventRequiresAdminToken(t *testing.T) {
+	app := testkit.NewApp(testkit.WithAdminToken("secret"))
+	app.Do(t, testkit.NewEvent("signup").At(at).Request())
+
+	req := httptest.NewRequest(http.MethodGet, "/events/1", nil)
+	req.Header.Set("X-Admin-Token", "wrong")
+	if resp := app.Do(t, req); resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", resp.StatusCode)
+	}
+
+	resp := app.Do(t, httptest.NewRequest(http.MethodGet, "/events/1", nil))
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var body struct {
+		EventName string `json:"event_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.EventName != "signup" {
+		t.Fatalf("unexpected body: %+v %v", body, err)
+	}
+}
+
+func TestAssertGolden_MasksNestedFields(t *testing.T) {
+	testkit.AssertGolden(t, "testdata/masked.golden",
+		[]byte(`{"items":[{"id":7,"name":"a"}],"received_at":"2025-12-07T10:00:00Z"}`),
+		"id", "received_at")
+}
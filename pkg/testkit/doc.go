@@ -0,0 +1,36 @@
+// Package testkit helps code that embeds the event metrics service write
+// realistic tests without a database or copies of the internal fakes.
+//
+// It provides
+//
+//   - EventStore and MetricsReader, in-memory implementations of the
+//     repository ports that behave like the Postgres adapters (dedupe
+//     windows, transactions, newest-first paging, grouping),
+//   - NewApp, the real HTTP handlers and use cases wired to those fakes,
+//   - request builders for the public endpoints, and
+//   - golden-response helpers.
+//
+// The service's domain types live in internal packages; the aliases below
+// make them nameable from other modules.
+package testkit
+
+import (
+	eventsDomain "event-metrics-service/internal/events/core/domain"
+	eventsPorts "event-metrics-service/internal/events/core/ports"
+	metricsDomain "event-metrics-service/internal/metrics/core/domain"
+	metricsPorts "event-metrics-service/internal/metrics/core/ports"
+)
+
+type (
+	Event       = eventsDomain.Event
+	StoredEvent = eventsDomain.StoredEvent
+	EventQuery  = eventsPorts.EventQuery
+	EventRef    = eventsPorts.EventRef
+	EventCursor = eventsPorts.EventCursor
+
+	EventRepository = eventsPorts.EventRepositoryPort
+
+	AggregatedMetrics = metricsDomain.AggregatedMetrics
+	MetricsGroup      = metricsDomain.MetricsGroup
+	MetricsFilter     = metricsPorts.MetricsFilter
+)
@@ -0,0 +1,110 @@
+package testkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// UpdateGoldenEnv names the environment variable that makes AssertGolden
+// rewrite golden files instead of comparing against them:
+//
+//	UPDATE_GOLDEN=1 go test ./...
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// Masked replaces the values of masked fields in golden output.
+const Masked = "<masked>"
+
+// DefaultMask lists the server-assigned fields that differ between runs.
+var DefaultMask = []string{"id", "event_id", "received_at", "as_of", "dedupe_key"}
+
+// AssertGolden compares the JSON document got with the golden file at path
+// (conventionally testdata/<name>.golden). Both sides are indented and the
+// values of any object key listed in mask are replaced with Masked at every
+// depth, so the file stays stable and readable in review.
+func AssertGolden(t testing.TB, path string, got []byte, mask ...string) {
+	t.Helper()
+
+	normalized, err := normalizeJSON(got, mask)
+	if err != nil {
+		t.Fatalf("testkit: %s: response is not JSON: %v\n%s", path, err, got)
+	}
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("testkit: %v", err)
+		}
+		if err := os.WriteFile(path, normalized, 0o644); err != nil {
+			t.Fatalf("testkit: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("testkit: %v (run with %s=1 to create it)", err, UpdateGoldenEnv)
+	}
+	if !bytes.Equal(want, normalized) {
+		t.Fatalf("testkit: %s mismatch (run with %s=1 to update)\n--- want\n%s\n--- got\n%s",
+			path, UpdateGoldenEnv, want, normalized)
+	}
+}
+
+// AssertGoldenResponse reads and closes resp.Body, then calls AssertGolden.
+func AssertGoldenResponse(t testing.TB, path string, resp *http.Response, mask ...string) {
+	t.Helper()
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("testkit: reading response: %v", err)
+	}
+	AssertGolden(t, path, body, mask...)
+}
+
+func normalizeJSON(raw []byte, mask []string) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	masked := make(map[string]bool, len(mask))
+	for _, k := range mask {
+		masked[k] = true
+	}
+	doc = maskFields(doc, masked)
+
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func maskFields(v any, masked map[string]bool) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, item := range val {
+			if masked[k] {
+				val[k] = Masked
+			} else {
+				val[k] = maskFields(item, masked)
+			}
+		}
+	case []any:
+		for i, item := range val {
+			val[i] = maskFields(item, masked)
+		}
+	}
+	return v
+}
@@ -0,0 +1,382 @@
+package testkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metricsDomain "event-metrics-service/internal/metrics/core/domain"
+	metricsPorts "event-metrics-service/internal/metrics/core/ports"
+)
+
+var ErrUnsupportedMode = errors.New("testkit: metrics mode not supported")
+
+// MetricsReader aggregates the events of an EventStore the way the Postgres
+// metrics reader does for count queries. Unique users are counted by
+// user_id, or "anon:"+anonymous_id for anonymous events; identity stitching
+// is not simulated, nor is sampling. Histogram and lag modes return
+// ErrUnsupportedMode.
+type MetricsReader struct {
+	store *EventStore
+}
+
+func NewMetricsReader(store *EventStore) *MetricsReader {
+	return &MetricsReader{store: store}
+}
+
+var (
+	_ metricsPorts.MetricsReaderPort   = (*MetricsReader)(nil)
+	_ metricsPorts.MetricsStreamerPort = (*MetricsReader)(nil)
+	_ metricsPorts.WatermarkPort       = (*MetricsReader)(nil)
+)
+
+func (r *MetricsReader) QueryMetrics(ctx context.Context, f MetricsFilter) (*AggregatedMetrics, error) {
+	if f.Mode != metricsDomain.ModeCount {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMode, f.Mode)
+	}
+
+	events, err := r.store.ListEvents(ctx, EventQuery{
+		From: time.Unix(f.From, 0),
+		To:   time.Unix(f.To, 0),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &AggregatedMetrics{
+		EventName: f.EventName,
+		From:      f.From,
+		To:        f.To,
+		GroupBy:   f.GroupBy,
+		Aggregate: f.Aggregate,
+		Field:     f.Field,
+		Mode:      f.Mode,
+		AsOf:      f.AsOf,
+	}
+	field, _ := metricsDomain.MetadataGroupKey(f.Field)
+
+	type bucket struct {
+		keys   []string
+		total  int64
+		users  map[string]bool
+		values []float64
+	}
+	buckets := map[string]*bucket{}
+	all := &bucket{users: map[string]bool{}}
+	dims := metricsDomain.GroupByDimensions(f.GroupBy)
+
+	for _, e := range events {
+		if !matchesFilter(e, f) {
+			continue
+		}
+
+		keys := make([]string, len(dims))
+		for i, dim := range dims {
+			keys[i] = groupKey(e, dim, f.Interval)
+		}
+		key := strings.Join(keys, metricsDomain.CompositeKeySeparator)
+		b := buckets[key]
+		if b == nil {
+			b = &bucket{keys: keys, users: map[string]bool{}}
+			buckets[key] = b
+		}
+		user := uniqueUser(e)
+		b.total++
+		b.users[user] = true
+		all.total++
+		all.users[user] = true
+		if v, ok := numericMetadata(e, field); ok && f.Aggregate != "" {
+			b.values = append(b.values, v)
+			all.values = append(all.values, v)
+		}
+	}
+
+	res.Value = aggregate(f.Aggregate, all.values)
+	if f.GroupBy == "" {
+		res.TotalCount = all.total
+		res.UniqueUsers = int64(len(all.users))
+		return res, nil
+	}
+
+	keys := make([]string, 0, len(buckets))
+	for k := range buckets {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	// Like the Postgres reader, the grouped total count is the sum over the
+	// groups, while unique users are counted once across all groups.
+	for _, k := range keys {
+		b := buckets[k]
+		g := MetricsGroup{Key: k, TotalCount: b.total, UniqueUsers: int64(len(b.users)), Value: aggregate(f.Aggregate, b.values)}
+		if len(dims) > 1 {
+			g.Keys = b.keys
+		}
+		res.Groups = append(res.Groups, g)
+		res.TotalCount += b.total
+	}
+	res.UniqueUsers = int64(len(all.users))
+	if f.Top > 0 {
+		res.Groups = topGroups(res.Groups, f.Top, f.Aggregate != "")
+		res.TotalCount = all.total
+	}
+	if f.Top == 0 {
+		orderGroups(res.Groups, f.OrderBy, f.Order == metricsDomain.OrderDesc)
+	}
+	if f.Limit > 0 {
+		res.Groups = res.Groups[min(f.Offset, len(res.Groups)):]
+		if len(res.Groups) > f.Limit {
+			res.Groups = res.Groups[:f.Limit]
+			res.NextOffset = f.Offset + f.Limit
+		}
+		if f.Offset > 0 || res.NextOffset > 0 {
+			res.TotalCount = all.total
+		}
+	}
+	return res, nil
+}
+
+// orderGroups orders groups sorted by key like the Postgres reader does for
+// orderBy, ties in key order.
+// StreamMetrics answers f like QueryMetrics without paging and emits the
+// groups in one batch; there is no cursor to read them through.
+func (r *MetricsReader) StreamMetrics(
+	ctx context.Context,
+	f MetricsFilter,
+	head func(*AggregatedMetrics) error,
+	emit func([]MetricsGroup) error,
+) error {
+	f.Limit, f.Offset = 0, 0
+	res, err := r.QueryMetrics(ctx, f)
+	if err != nil {
+		return err
+	}
+	groups := res.Groups
+	res.Groups = nil
+	if err := head(res); err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+	return emit(groups)
+}
+
+func orderGroups(groups []MetricsGroup, orderBy string, desc bool) {
+	if orderBy == "" || orderBy == metricsDomain.OrderByKey {
+		if desc {
+			slices.Reverse(groups)
+		}
+		return
+	}
+	figure := func(g MetricsGroup) (float64, bool) {
+		switch orderBy {
+		case metricsDomain.OrderByTotalCount:
+			return float64(g.TotalCount), true
+		case metricsDomain.OrderByUniqueUsers:
+			return float64(g.UniqueUsers), true
+		}
+		if g.Value == nil {
+			return 0, false
+		}
+		return *g.Value, true
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		fi, oki := figure(groups[i])
+		fj, okj := figure(groups[j])
+		if oki != okj {
+			return oki
+		}
+		if desc {
+			return fi > fj
+		}
+		return fi < fj
+	})
+}
+
+// topGroups keeps the n non-empty groups with the highest count, or value
+// when byValue, ties and missing values last in key order.
+func topGroups(groups []MetricsGroup, n int, byValue bool) []MetricsGroup {
+	groups = slices.DeleteFunc(groups, func(g MetricsGroup) bool { return g.Key == "" })
+	rank := func(g MetricsGroup) (float64, bool) {
+		if !byValue {
+			return float64(g.TotalCount), true
+		}
+		if g.Value == nil {
+			return 0, false
+		}
+		return *g.Value, true
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		ri, oki := rank(groups[i])
+		rj, okj := rank(groups[j])
+		if oki != okj {
+			return oki
+		}
+		return ri > rj
+	})
+	if len(groups) > n {
+		groups = groups[:n]
+	}
+	return groups
+}
+
+// CurrentWatermark returns the newest ReceivedAt, or the store's clock while
+// it is empty.
+func (r *MetricsReader) CurrentWatermark(ctx context.Context) (time.Time, error) {
+	var wm time.Time
+	for _, e := range r.store.Events() {
+		if e.ReceivedAt.After(wm) {
+			wm = e.ReceivedAt
+		}
+	}
+	if wm.IsZero() {
+		wm = r.store.now()
+	}
+	return wm.UTC(), nil
+}
+
+func matchesFilter(e StoredEvent, f MetricsFilter) bool {
+	if !slices.Contains(metricsDomain.EventNames(f.EventName), e.EventName) {
+		return false
+	}
+	if f.Channel != nil && e.Channel != *f.Channel {
+		return false
+	}
+	if f.CampaignID != nil && e.CampaignID != *f.CampaignID {
+		return false
+	}
+	if f.UserID != "" && uniqueUser(e) != f.UserID {
+		return false
+	}
+	if f.AsOf != nil && e.ReceivedAt.After(*f.AsOf) {
+		return false
+	}
+	for k, v := range f.Metadata {
+		if metadataString(e, k) != v {
+			return false
+		}
+	}
+	if len(f.Tags) > 0 {
+		carries := func(tag string) bool { return slices.Contains(e.Tags, tag) }
+		if f.TagMatch == metricsDomain.TagMatchAny {
+			return slices.ContainsFunc(f.Tags, carries)
+		}
+		for _, tag := range f.Tags {
+			if !carries(tag) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// groupKey returns the value of one group_by dimension for e.
+func groupKey(e StoredEvent, dim, interval string) string {
+	if key, ok := metricsDomain.MetadataGroupKey(dim); ok {
+		return metadataString(e, key)
+	}
+	switch dim {
+	case metricsDomain.DimensionChannel:
+		return e.Channel
+	case metricsDomain.DimensionCampaign:
+		return e.CampaignID
+	case metricsDomain.DimensionUser:
+		return e.UserID
+	case metricsDomain.DimensionHourOfDay:
+		return fmt.Sprintf("%02d", e.EventTime.UTC().Hour())
+	case metricsDomain.DimensionDayOfWeek:
+		// ISO days, Monday is 1 and Sunday 7.
+		return strconv.Itoa((int(e.EventTime.UTC().Weekday())+6)%7 + 1)
+	case "time":
+		t := e.EventTime.UTC()
+		switch interval {
+		case "minute":
+			t = t.Truncate(time.Minute)
+		case "day":
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		case "week":
+			// Weeks start on Monday, as with date_trunc('week', ...).
+			t = time.Date(t.Year(), t.Month(), t.Day()-(int(t.Weekday())+6)%7, 0, 0, 0, 0, time.UTC)
+		case "month":
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		default:
+			t = t.Truncate(time.Hour)
+		}
+		return t.Format(time.RFC3339)
+	}
+	return ""
+}
+
+// metadataString mirrors metadata->>'key' for scalar values.
+func metadataString(e StoredEvent, key string) string {
+	v, ok := e.Metadata[key]
+	if !ok || v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// numericPattern is the JSON number syntax the Postgres reader accepts for
+// aggregates.
+var numericPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+
+func numericMetadata(e StoredEvent, key string) (float64, bool) {
+	s := metadataString(e, key)
+	if !numericPattern.MatchString(s) {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	return v, err == nil
+}
+
+// aggregate returns nil without values, like the SQL aggregates.
+func aggregate(kind string, values []float64) *float64 {
+	if kind == "" || len(values) == 0 {
+		return nil
+	}
+	if p, ok := metricsDomain.AggregatePercentiles[kind]; ok {
+		v := percentileCont(values, p)
+		return &v
+	}
+	var v float64
+	switch kind {
+	case metricsDomain.AggregateMin:
+		v = slices.Min(values)
+	case metricsDomain.AggregateMax:
+		v = slices.Max(values)
+	default:
+		for _, x := range values {
+			v += x
+		}
+		if kind == metricsDomain.AggregateAvg {
+			v /= float64(len(values))
+		}
+	}
+	return &v
+}
+
+// percentileCont interpolates linearly between the closest values, like
+// Postgres percentile_cont.
+func percentileCont(values []float64, p float64) float64 {
+	sorted := slices.Sorted(slices.Values(values))
+	pos := p * float64(len(sorted)-1)
+	lower := int(pos)
+	if lower+1 >= len(sorted) {
+		return sorted[lower]
+	}
+	return sorted[lower] + (pos-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+func uniqueUser(e StoredEvent) string {
+	if e.UserID != "" {
+		return e.UserID
+	}
+	return "anon:" + e.AnonymousID
+}
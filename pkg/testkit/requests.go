@@ -0,0 +1,186 @@
+package testkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EventRequest builds a POST /events body. Start from NewEvent, which
+// fills the required fields.
+type EventRequest struct {
+	body eventBody
+}
+
+type eventBody struct {
+	EventID     string         `json:"event_id,omitempty"`
+	EventName   string         `json:"event_name"`
+	Channel     string         `json:"channel"`
+	CampaignID  string         `json:"campaign_id,omitempty"`
+	UserID      string         `json:"user_id,omitempty"`
+	AnonymousID string         `json:"anonymous_id,omitempty"`
+	Timestamp   int64          `json:"timestamp"`
+	Value       *float64       `json:"value,omitempty"`
+	Tags        []string       `json:"tags,omitempty"`
+	Metadata    map[string]any `json:"metadata,omitempty"`
+}
+
+// NewEvent returns a request for eventName on the "web" channel by "u1",
+// timestamped a minute ago.
+func NewEvent(eventName string) *EventRequest {
+	return &EventRequest{body: eventBody{
+		EventName: eventName,
+		Channel:   "web",
+		UserID:    "u1",
+		Timestamp: time.Now().Add(-time.Minute).Unix(),
+	}}
+}
+
+func (r *EventRequest) ID(eventID string) *EventRequest { r.body.EventID = eventID; return r }
+
+func (r *EventRequest) Channel(channel string) *EventRequest { r.body.Channel = channel; return r }
+
+func (r *EventRequest) Campaign(campaignID string) *EventRequest {
+	r.body.CampaignID = campaignID
+	return r
+}
+
+func (r *EventRequest) User(userID string) *EventRequest { r.body.UserID = userID; return r }
+
+// Anonymous sends the event for an anonymous visitor; the user ID is cleared.
+func (r *EventRequest) Anonymous(anonymousID string) *EventRequest {
+	r.body.UserID, r.body.AnonymousID = "", anonymousID
+	return r
+}
+
+func (r *EventRequest) At(t time.Time) *EventRequest { r.body.Timestamp = t.Unix(); return r }
+
+func (r *EventRequest) Value(v float64) *EventRequest { r.body.Value = &v; return r }
+
+func (r *EventRequest) Tag(tags ...string) *EventRequest {
+	r.body.Tags = append(r.body.Tags, tags...)
+	return r
+}
+
+func (r *EventRequest) Meta(key string, value any) *EventRequest {
+	if r.body.Metadata == nil {
+		r.body.Metadata = map[string]any{}
+	}
+	r.body.Metadata[key] = value
+	return r
+}
+
+func (r *EventRequest) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.body)
+}
+
+// Request returns the POST /events request.
+func (r *EventRequest) Request() *http.Request {
+	return jsonRequest(http.MethodPost, "/events", r)
+}
+
+// Bulk returns a POST /events/bulk request carrying events in order.
+func Bulk(events ...*EventRequest) *http.Request {
+	return jsonRequest(http.MethodPost, "/events/bulk", map[string]any{"events": events})
+}
+
+// MetricsQuery builds a GET /metrics request, or GET /metrics/top after
+// Top.
+type MetricsQuery struct {
+	path   string
+	values url.Values
+}
+
+func NewMetricsQuery(eventName string, from, to time.Time) *MetricsQuery {
+	q := &MetricsQuery{path: "/metrics", values: url.Values{}}
+	q.values.Set("event_name", eventName)
+	q.values.Set("from", strconv.FormatInt(from.Unix(), 10))
+	q.values.Set("to", strconv.FormatInt(to.Unix(), 10))
+	return q
+}
+
+func (q *MetricsQuery) Channel(channel string) *MetricsQuery { return q.set("channel", channel) }
+
+// Campaign keeps the events of one campaign; "" keeps events without one.
+func (q *MetricsQuery) Campaign(campaignID string) *MetricsQuery {
+	return q.set("campaign_id", campaignID)
+}
+
+// User keeps the events of one user.
+func (q *MetricsQuery) User(userID string) *MetricsQuery { return q.set("user_id", userID) }
+
+// GroupBy takes "channel", "campaign_id", "user_id", "hour_of_day",
+// "day_of_week", "metadata.<key>" or "time", or several of them joined by
+// ","; grouping by time also needs Interval.
+func (q *MetricsQuery) GroupBy(groupBy string) *MetricsQuery { return q.set("group_by", groupBy) }
+
+func (q *MetricsQuery) Interval(interval string) *MetricsQuery { return q.set("interval", interval) }
+
+func (q *MetricsQuery) Meta(key, value string) *MetricsQuery { return q.set("metadata."+key, value) }
+
+// Tags keeps events carrying every tag, or any of them with match "any".
+func (q *MetricsQuery) Tags(match string, tags ...string) *MetricsQuery {
+	return q.set("tags", strings.Join(tags, ",")).set("tag_match", match)
+}
+
+// Aggregate computes sum, avg, min or max of a numeric "metadata.<key>"
+// field.
+func (q *MetricsQuery) Aggregate(aggregate, field string) *MetricsQuery {
+	return q.set("aggregate", aggregate).set("field", field)
+}
+
+// Top asks for the n values of dimension with the most events, or the
+// highest Aggregate.
+func (q *MetricsQuery) Top(dimension string, n int) *MetricsQuery {
+	q.path = "/metrics/top"
+	return q.set("dimension", dimension).set("n", strconv.Itoa(n))
+}
+
+// Compare also queries "previous_period" or "previous_year" and returns the
+// changes from it.
+func (q *MetricsQuery) Compare(compare string) *MetricsQuery { return q.set("compare", compare) }
+
+// OrderBy orders the groups by "key", "total_count", "unique_users" or
+// "value", order being "asc" or "desc".
+func (q *MetricsQuery) OrderBy(orderBy, order string) *MetricsQuery {
+	return q.set("order_by", orderBy).set("order", order)
+}
+
+// Page asks for limit groups after skipping offset, in key order or that
+// of OrderBy.
+func (q *MetricsQuery) Page(limit, offset int) *MetricsQuery {
+	return q.set("limit", strconv.Itoa(limit)).set("offset", strconv.Itoa(offset))
+}
+
+// Stream asks for every group as NDJSON: the totals on the first line, then
+// one group per line.
+func (q *MetricsQuery) Stream() *MetricsQuery { return q.set("format", "ndjson") }
+
+func (q *MetricsQuery) AsOf(t time.Time) *MetricsQuery {
+	return q.set("as_of", t.UTC().Format(time.RFC3339Nano))
+}
+
+func (q *MetricsQuery) set(key, value string) *MetricsQuery {
+	q.values.Set(key, value)
+	return q
+}
+
+func (q *MetricsQuery) Request() *http.Request {
+	return httptest.NewRequest(http.MethodGet, q.path+"?"+q.values.Encode(), nil)
+}
+
+func jsonRequest(method, path string, body any) *http.Request {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		// Builders only hold JSON-safe values; this is a programming error.
+		panic("testkit: " + err.Error())
+	}
+	req := httptest.NewRequest(method, path, bytes.NewReader(raw))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
@@ -0,0 +1,272 @@
+package testkit
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	eventsPorts "event-metrics-service/internal/events/core/ports"
+)
+
+// EventStore is an in-memory events repository. Like the Postgres adapter
+// it claims dedupe keys until they expire, assigns increasing IDs and stamps
+// ReceivedAt with the store's clock. It is safe for concurrent use.
+type EventStore struct {
+	mu     sync.Mutex
+	now    func() time.Time
+	err    error
+	nextID int64
+	events []StoredEvent
+	dedupe map[string]time.Time // dedupe key -> expiry (zero = never)
+}
+
+type StoreOption func(*EventStore)
+
+// WithClock replaces time.Now for ReceivedAt and dedupe expiry.
+func WithClock(now func() time.Time) StoreOption {
+	return func(s *EventStore) {
+		s.now = now
+	}
+}
+
+func NewEventStore(opts ...StoreOption) *EventStore {
+	s := &EventStore{now: time.Now, dedupe: map[string]time.Time{}}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+var (
+	_ eventsPorts.EventRepositoryPort = (*EventStore)(nil)
+	_ eventsPorts.EventReaderPort     = (*EventStore)(nil)
+	_ eventsPorts.DedupeJanitorPort   = (*EventStore)(nil)
+	_ eventsPorts.RetentionPort       = (*EventStore)(nil)
+)
+
+// FailWith makes every following call return err, simulating an outage.
+// FailWith(nil) heals the store.
+func (s *EventStore) FailWith(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func (s *EventStore) InsertEvent(ctx context.Context, e *Event) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return false, s.err
+	}
+	return s.insert(e), nil
+}
+
+func (s *EventStore) insert(e *Event) bool {
+	now := s.now().UTC()
+	if exp, claimed := s.dedupe[e.DedupeKey]; claimed && (exp.IsZero() || exp.After(now)) {
+		// Like the Postgres repository, report the original's event_id.
+		for i := len(s.events) - 1; i >= 0; i-- {
+			if s.events[i].DedupeKey == e.DedupeKey {
+				e.EventID = s.events[i].EventID
+				break
+			}
+		}
+		return false
+	}
+	s.dedupe[e.DedupeKey] = e.DedupeExpiresAt
+
+	s.nextID++
+	stored := StoredEvent{Event: *e, ID: s.nextID, ReceivedAt: now}
+	stored.Tags = append([]string(nil), e.Tags...)
+	s.events = append(s.events, stored)
+	return true
+}
+
+// WithinTransaction applies fn's inserts only when fn succeeds.
+// Transactions are serialized.
+func (s *EventStore) WithinTransaction(ctx context.Context, fn func(repo EventRepository) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+
+	tx := &EventStore{
+		now:    s.now,
+		nextID: s.nextID,
+		events: append([]StoredEvent(nil), s.events...),
+		dedupe: make(map[string]time.Time, len(s.dedupe)),
+	}
+	for k, v := range s.dedupe {
+		tx.dedupe[k] = v
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	s.nextID, s.events, s.dedupe = tx.nextID, tx.events, tx.dedupe
+	return nil
+}
+
+func (s *EventStore) PurgeExpiredDedupeKeys(ctx context.Context, limit int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	now := s.now()
+	var n int64
+	for k, exp := range s.dedupe {
+		if n >= int64(limit) {
+			break
+		}
+		if !exp.IsZero() && !exp.After(now) {
+			delete(s.dedupe, k)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// PurgeEventsBefore deletes the oldest events first, like the Postgres
+// adapter.
+func (s *EventStore) PurgeEventsBefore(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	var expired []StoredEvent
+	for _, e := range s.events {
+		if e.EventTime.Before(cutoff) {
+			expired = append(expired, e)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool { return expired[i].EventTime.Before(expired[j].EventTime) })
+	if len(expired) > limit {
+		expired = expired[:limit]
+	}
+
+	drop := make(map[int64]bool, len(expired))
+	for _, e := range expired {
+		drop[e.ID] = true
+	}
+	kept := s.events[:0]
+	for _, e := range s.events {
+		if !drop[e.ID] {
+			kept = append(kept, e)
+		}
+	}
+	s.events = kept
+	return int64(len(expired)), nil
+}
+
+func (s *EventStore) DeleteEventsByID(ctx context.Context, ids []int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	drop := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		drop[id] = true
+	}
+	kept := s.events[:0]
+	for _, e := range s.events {
+		if !drop[e.ID] {
+			kept = append(kept, e)
+		}
+	}
+	n := int64(len(s.events) - len(kept))
+	s.events = kept
+	return n, nil
+}
+
+// ListEvents matches the Postgres reader: newest event_time first, ties
+// broken by ID, continuing strictly after q.After.
+func (s *EventStore) ListEvents(ctx context.Context, q EventQuery) ([]StoredEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	var out []StoredEvent
+	for _, e := range s.events {
+		if matchesQuery(e, q) {
+			out = append(out, e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].EventTime.Equal(out[j].EventTime) {
+			return out[i].EventTime.After(out[j].EventTime)
+		}
+		return out[i].ID > out[j].ID
+	})
+	if q.Limit > 0 && len(out) > q.Limit {
+		out = out[:q.Limit]
+	}
+	return out, nil
+}
+
+func matchesQuery(e StoredEvent, q EventQuery) bool {
+	switch {
+	case q.EventName != "" && e.EventName != q.EventName,
+		q.Channel != "" && e.Channel != q.Channel,
+		q.UserID != "" && e.UserID != q.UserID,
+		q.CampaignID != "" && e.CampaignID != q.CampaignID,
+		!q.From.IsZero() && e.EventTime.Before(q.From),
+		!q.To.IsZero() && e.EventTime.After(q.To):
+		return false
+	}
+	if q.After != nil {
+		if e.EventTime.After(q.After.EventTime) ||
+			(e.EventTime.Equal(q.After.EventTime) && e.ID >= q.After.ID) {
+			return false
+		}
+	}
+	for _, want := range q.Tags {
+		if !contains(e.Tags, want) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *EventStore) GetEvent(ctx context.Context, ref EventRef) (StoredEvent, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return StoredEvent{}, false, s.err
+	}
+
+	// Newest first, as in the Postgres adapter.
+	for i := len(s.events) - 1; i >= 0; i-- {
+		e := s.events[i]
+		if (ref.EventID != "" && e.EventID == ref.EventID) || (ref.ID != 0 && e.ID == ref.ID) {
+			return e, true, nil
+		}
+	}
+	return StoredEvent{}, false, nil
+}
+
+// Events returns every stored event in insertion order.
+func (s *EventStore) Events() []StoredEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]StoredEvent(nil), s.events...)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package testkit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"event-metrics-service/pkg/testkit"
+)
+
+func newEvent(name, dedupeKey string, at time.Time) *testkit.Event {
+	return &testkit.Event{
+		EventName:       name,
+		Channel:         "web",
+		UserID:          "u1",
+		EventTime:       at,
+		DedupeKey:       dedupeKey,
+		DedupeExpiresAt: at.Add(time.Hour),
+	}
+}
+
+func TestEventStore_InsertDedupesUntilExpiry(t *testing.T) {
+	now := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	store := testkit.NewEventStore(testkit.WithClock(func() time.Time { return now }))
+	ctx := context.Background()
+
+	if created, err := store.InsertEvent(ctx, newEvent("purchase", "k1", now)); err != nil || !created {
+		t.Fatalf("expected first insert to be created, got %v %v", created, err)
+	}
+	if created, _ := store.InsertEvent(ctx, newEvent("purchase", "k1", now)); created {
+		t.Fatalf("expected duplicate within the dedupe window")
+	}
+
+	now = now.Add(2 * time.Hour)
+	if n, _ := store.PurgeExpiredDedupeKeys(ctx, 10); n != 1 {
+		t.Fatalf("expected one expired key purged, got %d", n)
+	}
+	if created, _ := store.InsertEvent(ctx, newEvent("purchase", "k1", now)); !created {
+		t.Fatalf("expected insert after expiry to be created")
+	}
+
+	events := store.Events()
+	if len(events) != 2 || events[0].ID != 1 || events[1].ID != 2 || !events[1].ReceivedAt.Equal(now) {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+}
+
+func TestEventStore_TransactionRollsBack(t *testing.T) {
+	store := testkit.NewEventStore()
+	ctx := context.Background()
+	boom := errors.New("boom")
+
+	err := store.WithinTransaction(ctx, func(repo testkit.EventRepository) error {
+		if _, err := repo.InsertEvent(ctx, newEvent("purchase", "k1", time.Now())); err != nil {
+			return err
+		}
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected boom, got %v", err)
+	}
+	if got := store.Events(); len(got) != 0 {
+		t.Fatalf("expected rollback, got %+v", got)
+	}
+}
+
+func TestEventStore_ListEventsPagesNewestFirst(t *testing.T) {
+	store := testkit.NewEventStore()
+	ctx := context.Background()
+	base := time.Date(2025, 12, 7, 10, 0, 0, 0, time.UTC)
+	for i, key := range []string{"a", "b", "c"} {
+		store.InsertEvent(ctx, newEvent("purchase", key, base.Add(time.Duration(i)*time.Minute)))
+	}
+
+	page, _ := store.ListEvents(ctx, testkit.EventQuery{Limit: 2})
+	if len(page) != 2 || page[0].DedupeKey != "c" || page[1].DedupeKey != "b" {
+		t.Fatalf("unexpected first page: %+v", page)
+	}
+
+	last := page[1]
+	page, _ = store.ListEvents(ctx, testkit.EventQuery{
+		Limit: 2,
+		After: &testkit.EventCursor{EventTime: last.EventTime, ID: last.ID},
+	})
+	if len(page) != 1 || page[0].DedupeKey != "a" {
+		t.Fatalf("unexpected second page: %+v", page)
+	}
+}
+
+func TestEventStore_FailWith(t *testing.T) {
+	store := testkit.NewEventStore()
+	boom := errors.New("db down")
+	store.FailWith(boom)
+
+	if _, err := store.InsertEvent(context.Background(), newEvent("purchase", "k1", time.Now())); !errors.Is(err, boom) {
+		t.Fatalf("expected injected error, got %v", err)
+	}
+	if _, err := testkit.NewMetricsReader(store).QueryMetrics(context.Background(), testkit.MetricsFilter{}); !errors.Is(err, boom) {
+		t.Fatalf("expected injected error from metrics, got %v", err)
+	}
+}